@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	cliDefaultTimeout = 30 * time.Second
+	outputTable       = "table"
+	outputJSON        = "json"
+)
+
+// errUnhealthy makes `kc-agent health` exit with status 2 without printing an error
+var errUnhealthy = errors.New("one or more clusters are unhealthy")
+
+// cliCommand is a headless subcommand reusing pkg/k8s, e.g. `kc-agent clusters -o json`
+type cliCommand struct {
+	summary string
+	run     func(ctx context.Context, opts *cliOptions) error
+}
+
+var cliCommands = map[string]cliCommand{
+	"clusters":  {"List kubeconfig contexts", runClustersCommand},
+	"health":    {"Check cluster health (exit status 2 if any cluster is unhealthy)", runHealthCommand},
+	"gpu-nodes": {"List nodes with GPUs and other accelerators", runGPUNodesCommand},
+	"issues":    {"List pods with problems (crash loops, OOM kills, pending, ...)", runIssuesCommand},
+}
+
+// cliOptions are the flags shared by all subcommands
+type cliOptions struct {
+	kubeconfig string
+	cluster    string
+	namespace  string
+	output     string
+	timeout    time.Duration
+	verbose    bool
+
+	client *k8s.MultiClusterClient
+	out    io.Writer
+	errOut io.Writer
+}
+
+// printSubcommandUsage lists the subcommands below the server flags in -h output
+func printSubcommandUsage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "\nCommands (run `kc-agent <command> -h` for flags):\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  %-17s %s\n", "serve", "Start the agent server (default)")
+	names := make([]string, 0, len(cliCommands))
+	for name := range cliCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-17s %s\n", name, cliCommands[name].summary)
+	}
+	names = names[:0]
+	for name := range serviceCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-17s %s\n", name, serviceCommands[name].summary)
+	}
+}
+
+// runCLICommand parses the subcommand's flags and runs it, returning the process exit code
+func runCLICommand(name string, args []string) int {
+	return runCLI(name, args, os.Stdout, os.Stderr, k8s.NewMultiClusterClient)
+}
+
+// runCLI is runCLICommand with the output streams and client constructor passed
+// in, so subcommands can run against fake clusters
+func runCLI(name string, args []string, out, errOut io.Writer, newClient func(kubeconfig string) (*k8s.MultiClusterClient, error)) int {
+	cmd := cliCommands[name]
+	opts := &cliOptions{out: out, errOut: errOut}
+
+	fs := flag.NewFlagSet("kc-agent "+name, flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to kubeconfig file")
+	fs.StringVar(&opts.cluster, "cluster", "", "Only query this kubeconfig context (default: all)")
+	fs.StringVar(&opts.output, "o", outputTable, "Output format: table or json")
+	fs.DurationVar(&opts.timeout, "timeout", cliDefaultTimeout, "Overall timeout")
+	fs.BoolVar(&opts.verbose, "v", false, "Log client diagnostics to stderr")
+	if name == "issues" {
+		fs.StringVar(&opts.namespace, "namespace", "", "Only check this namespace (default: all)")
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if opts.output != outputTable && opts.output != outputJSON {
+		fmt.Fprintf(errOut, "Error: unsupported output format %q (use table or json)\n", opts.output)
+		return 1
+	}
+
+	// pkg/k8s logs cache and watcher activity; keep stdout/stderr clean for scripts
+	if !opts.verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	client, err := newClient(opts.kubeconfig)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	opts.client = client
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	if err := cmd.run(ctx, opts); err != nil {
+		if errors.Is(err, errUnhealthy) {
+			return 2
+		}
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// targetClusters returns --cluster, or every context in the kubeconfig
+func (o *cliOptions) targetClusters(ctx context.Context) ([]string, error) {
+	if o.cluster != "" {
+		return []string{o.cluster}, nil
+	}
+	clusters, err := o.client.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+// forEachCluster runs fn against the target clusters in parallel. Failing clusters are
+// reported on stderr so one unreachable cluster doesn't hide the others.
+func (o *cliOptions) forEachCluster(ctx context.Context, fn func(ctx context.Context, cluster string) error) error {
+	names, err := o.targetClusters(ctx)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			errs[i] = fn(ctx, name)
+		}(i, name)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			fmt.Fprintf(o.errOut, "Warning: %s: %v\n", names[i], err)
+		}
+	}
+	if failed > 0 && failed == len(names) {
+		return fmt.Errorf("all %d cluster(s) failed", failed)
+	}
+	return nil
+}
+
+// render writes v as JSON, or calls table with a tabwriter
+func (o *cliOptions) render(v interface{}, table func(w io.Writer)) error {
+	if o.output == outputJSON {
+		enc := json.NewEncoder(o.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(o.out, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+func runClustersCommand(ctx context.Context, opts *cliOptions) error {
+	clusters, err := opts.client.ListClusters(ctx)
+	if err != nil {
+		return err
+	}
+	if opts.cluster != "" {
+		filtered := clusters[:0]
+		for _, c := range clusters {
+			if c.Name == opts.cluster {
+				filtered = append(filtered, c)
+			}
+		}
+		clusters = filtered
+	}
+	if clusters == nil {
+		clusters = []k8s.ClusterInfo{}
+	}
+
+	return opts.render(clusters, func(w io.Writer) {
+		fmt.Fprintln(w, "CURRENT\tNAME\tSERVER\tAUTH\tSOURCE")
+		for _, c := range clusters {
+			current := ""
+			if c.IsCurrent {
+				current = "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, c.Name, c.Server, c.AuthMethod, c.Source)
+		}
+	})
+}
+
+func runHealthCommand(ctx context.Context, opts *cliOptions) error {
+	var mu sync.Mutex
+	results := []k8s.ClusterHealth{}
+	err := opts.forEachCluster(ctx, func(ctx context.Context, cluster string) error {
+		health, err := opts.client.GetClusterHealth(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		results = append(results, *health)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Cluster < results[j].Cluster })
+
+	if err := opts.render(results, func(w io.Writer) {
+		fmt.Fprintln(w, "CLUSTER\tHEALTHY\tREACHABLE\tNODES\tPODS\tISSUES")
+		for _, h := range results {
+			issues := strings.Join(h.Issues, "; ")
+			if h.ErrorMessage != "" {
+				issues = h.ErrorType + ": " + h.ErrorMessage
+			}
+			fmt.Fprintf(w, "%s\t%t\t%t\t%d/%d\t%d\t%s\n", h.Cluster, h.Healthy, h.Reachable, h.ReadyNodes, h.NodeCount, h.PodCount, issues)
+		}
+	}); err != nil {
+		return err
+	}
+
+	for _, h := range results {
+		if !h.Healthy {
+			return errUnhealthy
+		}
+	}
+	return nil
+}
+
+func runGPUNodesCommand(ctx context.Context, opts *cliOptions) error {
+	var mu sync.Mutex
+	nodes := []k8s.GPUNode{}
+	err := opts.forEachCluster(ctx, func(ctx context.Context, cluster string) error {
+		clusterNodes, err := opts.client.GetGPUNodes(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		nodes = append(nodes, clusterNodes...)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Cluster != nodes[j].Cluster {
+			return nodes[i].Cluster < nodes[j].Cluster
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return opts.render(nodes, func(w io.Writer) {
+		fmt.Fprintln(w, "CLUSTER\tNODE\tTYPE\tKIND\tALLOCATED")
+		for _, n := range nodes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\n", n.Cluster, n.Name, n.GPUType, n.AcceleratorType, n.GPUAllocated, n.GPUCount)
+		}
+	})
+}
+
+func runIssuesCommand(ctx context.Context, opts *cliOptions) error {
+	var mu sync.Mutex
+	issues := []k8s.PodIssue{}
+	err := opts.forEachCluster(ctx, func(ctx context.Context, cluster string) error {
+		clusterIssues, err := opts.client.FindPodIssues(ctx, cluster, opts.namespace)
+		if err != nil {
+			return err
+		}
+		for i := range clusterIssues {
+			clusterIssues[i].Cluster = cluster
+		}
+		mu.Lock()
+		issues = append(issues, clusterIssues...)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return opts.render(issues, func(w io.Writer) {
+		fmt.Fprintln(w, "CLUSTER\tNAMESPACE\tPOD\tSTATUS\tRESTARTS\tISSUES")
+		for _, p := range issues {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", p.Cluster, p.Namespace, p.Name, p.Status, p.Restarts, strings.Join(p.Issues, "; "))
+		}
+	})
+}
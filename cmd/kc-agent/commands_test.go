@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// fakeCLIClient returns a client constructor serving the given fake clusters,
+// in kubeconfig order, with the first one as the current context
+func fakeCLIClient(clusters map[string]*k8sfake.Clientset) func(string) (*k8s.MultiClusterClient, error) {
+	return func(string) (*k8s.MultiClusterClient, error) {
+		m, err := k8s.NewMultiClusterClient("")
+		if err != nil {
+			return nil, err
+		}
+		cfg := &api.Config{Clusters: map[string]*api.Cluster{}, Contexts: map[string]*api.Context{}, AuthInfos: map[string]*api.AuthInfo{}}
+		for name, cs := range clusters {
+			cfg.Clusters[name] = &api.Cluster{Server: "https://" + name + ":6443"}
+			cfg.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name}
+			cfg.AuthInfos[name] = &api.AuthInfo{Token: "t"}
+			m.InjectClient(name, cs)
+		}
+		cfg.CurrentContext = "alpha"
+		m.SetRawConfig(cfg)
+		return m, nil
+	}
+}
+
+func readyNode(name string, gpus int64) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			Capacity:    corev1.ResourceList{},
+			Allocatable: corev1.ResourceList{},
+		},
+	}
+	if gpus > 0 {
+		node.Labels = map[string]string{"nvidia.com/gpu.product": "A100"}
+		node.Status.Capacity["nvidia.com/gpu"] = *resource.NewQuantity(gpus, resource.DecimalSI)
+		node.Status.Allocatable["nvidia.com/gpu"] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+	return node
+}
+
+func crashingPod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "app",
+			RestartCount: 9,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	}
+}
+
+// failList makes every list of resource on cs fail
+func failList(cs *k8sfake.Clientset, resource string) *k8sfake.Clientset {
+	cs.PrependReactor("list", resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	return cs
+}
+
+func TestRunCLI(t *testing.T) {
+	tests := []struct {
+		name     string
+		clusters map[string]*k8sfake.Clientset
+		args     []string
+		wantCode int
+		// want is matched against stdout; for json output it is also decoded
+		want       []string
+		notWant    []string
+		wantErr    []string
+		wantJSONOf int // number of items expected in the JSON array, -1 for table output
+	}{
+		{
+			name: "clusters table",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": k8sfake.NewSimpleClientset(),
+				"beta":  k8sfake.NewSimpleClientset(),
+			},
+			args:       []string{"clusters"},
+			want:       []string{"CURRENT", "NAME", "*", "alpha", "https://alpha:6443", "beta"},
+			wantJSONOf: -1,
+		},
+		{
+			name: "clusters json filtered by --cluster",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": k8sfake.NewSimpleClientset(),
+				"beta":  k8sfake.NewSimpleClientset(),
+			},
+			args:       []string{"clusters", "-o", "json", "--cluster", "beta"},
+			want:       []string{`"name": "beta"`},
+			notWant:    []string{"alpha"},
+			wantJSONOf: 1,
+		},
+		{
+			name:       "unsupported output format",
+			clusters:   map[string]*k8sfake.Clientset{"alpha": k8sfake.NewSimpleClientset()},
+			args:       []string{"clusters", "-o", "yaml"},
+			wantCode:   1,
+			wantErr:    []string{`unsupported output format "yaml"`},
+			wantJSONOf: -1,
+		},
+		{
+			name: "health of healthy clusters",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": k8sfake.NewSimpleClientset(readyNode("n1", 0)),
+				"beta":  k8sfake.NewSimpleClientset(readyNode("n2", 0)),
+			},
+			args:       []string{"health"},
+			want:       []string{"CLUSTER", "alpha", "beta", "1/1"},
+			wantJSONOf: -1,
+		},
+		{
+			name: "health exits 2 when a cluster is unhealthy",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": k8sfake.NewSimpleClientset(readyNode("n1", 0)),
+				"beta":  failList(k8sfake.NewSimpleClientset(), "nodes"),
+			},
+			args:       []string{"health", "-o", "json"},
+			wantCode:   2,
+			want:       []string{`"cluster": "alpha"`, `"cluster": "beta"`, `"healthy": false`},
+			wantJSONOf: 2,
+		},
+		{
+			name: "gpu-nodes json",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": k8sfake.NewSimpleClientset(readyNode("gpu-1", 4), readyNode("cpu-1", 0)),
+			},
+			args:       []string{"gpu-nodes", "-o", "json"},
+			want:       []string{`"name": "gpu-1"`, `"gpuCount": 4`},
+			notWant:    []string{"cpu-1"},
+			wantJSONOf: 1,
+		},
+		{
+			name: "issues keep working clusters when one fails",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": k8sfake.NewSimpleClientset(crashingPod("shop", "api")),
+				"beta":  failList(k8sfake.NewSimpleClientset(), "pods"),
+			},
+			args:       []string{"issues"},
+			want:       []string{"alpha", "shop", "api", "CrashLoopBackOff"},
+			wantErr:    []string{"Warning: beta: connection refused"},
+			wantJSONOf: -1,
+		},
+		{
+			name: "issues fail when every cluster fails",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": failList(k8sfake.NewSimpleClientset(), "pods"),
+				"beta":  failList(k8sfake.NewSimpleClientset(), "pods"),
+			},
+			args:       []string{"issues", "-o", "json"},
+			wantCode:   1,
+			wantErr:    []string{"Warning: alpha", "Warning: beta", "all 2 cluster(s) failed"},
+			wantJSONOf: -1,
+		},
+		{
+			name: "issues filtered by namespace",
+			clusters: map[string]*k8sfake.Clientset{
+				"alpha": k8sfake.NewSimpleClientset(crashingPod("shop", "api"), crashingPod("ml", "trainer")),
+			},
+			args:       []string{"issues", "-o", "json", "--namespace", "ml"},
+			want:       []string{`"name": "trainer"`, `"cluster": "alpha"`},
+			notWant:    []string{"api"},
+			wantJSONOf: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := runCLI(tt.args[0], tt.args[1:], &out, &errOut, fakeCLIClient(tt.clusters))
+			if code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (stderr: %s)", code, tt.wantCode, errOut.String())
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(out.String(), w) {
+					t.Errorf("stdout lacks %q:\n%s", w, out.String())
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(out.String(), w) {
+					t.Errorf("stdout should not contain %q:\n%s", w, out.String())
+				}
+			}
+			for _, w := range tt.wantErr {
+				if !strings.Contains(errOut.String(), w) {
+					t.Errorf("stderr lacks %q:\n%s", w, errOut.String())
+				}
+			}
+			if tt.wantJSONOf >= 0 {
+				var items []json.RawMessage
+				if err := json.Unmarshal(out.Bytes(), &items); err != nil {
+					t.Fatalf("stdout is not a JSON array: %v\n%s", err, out.String())
+				}
+				if len(items) != tt.wantJSONOf {
+					t.Errorf("got %d JSON items, want %d:\n%s", len(items), tt.wantJSONOf, out.String())
+				}
+			}
+		})
+	}
+}
@@ -13,10 +13,35 @@ import (
 )
 
 func main() {
+	// Headless subcommands (`kc-agent clusters`, `kc-agent health`, ...); anything else,
+	// including `kc-agent serve`, starts the agent server
+	if len(os.Args) > 1 {
+		if _, ok := cliCommands[os.Args[1]]; ok {
+			os.Exit(runCLICommand(os.Args[1], os.Args[2:]))
+		}
+		if _, ok := serviceCommands[os.Args[1]]; ok {
+			os.Exit(runServiceCommand(os.Args[1], os.Args[2:]))
+		}
+		if os.Args[1] == "serve" {
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+		}
+	}
+
 	port := flag.Int("port", 8585, "Port to listen on")
 	kubeconfig := flag.String("kubeconfig", "", "Path to kubeconfig file")
 	allowedOrigins := flag.String("allowed-origins", "", "Comma-separated list of additional allowed WebSocket origins")
+	leaderElect := flag.Bool("leader-elect", false, "Run background subsystems on one replica only (in-cluster, uses a coordination.k8s.io Lease)")
+	leaderElectionNamespace := flag.String("leader-election-namespace", "", "Namespace for the leader election Lease (default: the pod's namespace)")
+	grpcPort := flag.Int("grpc-port", 0, "Port for the gRPC API (0 disables it)")
+	mdns := flag.Bool("mdns", false, "Advertise the agent on the local network via mDNS (requires an agent token) and discover teammates' agents")
+	configPath := flag.String("config", agent.AgentFilePath(), "Agent config file (env: "+agent.AgentFileEnv+"); flags override it")
+	profile := flag.String("profile", "", "Config profile from the agent config file to start with (switch at runtime via /profiles/active)")
 	version := flag.Bool("version", false, "Print version and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: kc-agent [serve] [flags]\n       kc-agent <command> [flags]\n\nServer flags:\n")
+		flag.PrintDefaults()
+		printSubcommandUsage()
+	}
 	flag.Parse()
 
 	if *version {
@@ -44,10 +69,40 @@ KubeStellar Console - Local Agent v%s
 		}
 	}
 
+	// Settings from the config file apply unless the matching flag was given
+	agentFile, err := agent.LoadAgentFile(*configPath)
+	if err != nil {
+		log.Fatalf("Invalid agent config: %v", err)
+	}
+	setFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	if !setFlags["port"] && agentFile.Port > 0 {
+		*port = agentFile.Port
+	}
+	if !setFlags["kubeconfig"] && os.Getenv("KUBECONFIG") == "" && agentFile.Kubeconfig != "" {
+		*kubeconfig = agentFile.Kubeconfig
+	}
+	if !setFlags["grpc-port"] && agentFile.GRPCPort > 0 {
+		*grpcPort = agentFile.GRPCPort
+	}
+	if !setFlags["mdns"] {
+		*mdns = agentFile.Discovery.MDNS
+	}
+
 	server, err := agent.NewServer(agent.Config{
 		Port:           *port,
 		Kubeconfig:     *kubeconfig,
 		AllowedOrigins: origins,
+
+		LeaderElect:             *leaderElect,
+		LeaderElectionNamespace: *leaderElectionNamespace,
+
+		GRPCPort: *grpcPort,
+		MDNS:     *mdns,
+
+		AgentFile:     agentFile,
+		AgentFilePath: *configPath,
+		Profile:       *profile,
 	})
 	if err != nil {
 		log.Fatalf("Failed to create server: %v", err)
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	serviceLabel    = "io.kubestellar.kc-agent" // launchd label
+	serviceUnitName = "kc-agent.service"        // systemd user unit
+	serviceEnvFile  = "kc-agent.env"            // systemd EnvironmentFile= holding secrets, under ~/.kc
+	serviceFileMode = 0600                      // the plist and env file may hold the agent token
+	serviceDirMode  = 0755
+)
+
+// serviceCommand installs or removes kc-agent as a per-user OS service
+type serviceCommand struct {
+	summary string
+	run     func(args []string) error
+}
+
+var serviceCommands = map[string]serviceCommand{
+	"install-service":   {"Run the agent at login as a launchd agent (macOS) or systemd user unit (Linux)", runInstallService},
+	"uninstall-service": {"Stop and remove the service installed by install-service", runUninstallService},
+}
+
+// serviceSpec is what the generated plist or unit runs
+type serviceSpec struct {
+	Binary  string
+	Args    []string
+	Env     map[string]string
+	Secrets map[string]string // environment kept out of argv, the unit and systemctl show
+	EnvFile string            // where systemd reads Secrets from
+	LogPath string
+}
+
+// envFlag collects repeated --env KEY=VALUE flags
+type envFlag map[string]string
+
+func (e envFlag) String() string { return "" }
+
+func (e envFlag) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", v)
+	}
+	e[key] = value
+	return nil
+}
+
+// runServiceCommand runs install-service or uninstall-service, returning the exit code
+func runServiceCommand(name string, args []string) int {
+	if err := serviceCommands[name].run(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runInstallService(args []string) error {
+	env := envFlag{}
+	fs := flag.NewFlagSet("kc-agent install-service", flag.ContinueOnError)
+	port := fs.Int("port", 0, "Port the service listens on (default: agent config or 8585)")
+	kubeconfig := fs.String("kubeconfig", os.Getenv("KUBECONFIG"), "Kubeconfig the service uses")
+	configPath := fs.String("config", "", "Agent config file (default: ~/.kc/agent.yaml)")
+	tokenFile := fs.String("token-file", "", "Read the bearer token to require from this file, or - for stdin (default: $KC_AGENT_TOKEN)")
+	logPath := fs.String("log", "", "Log file (default: ~/.kc/logs/kc-agent.log)")
+	fs.Var(env, "env", "Extra environment variable, KEY=VALUE (repeatable)")
+	dryRun := fs.Bool("dry-run", false, "Print the service file instead of installing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the kc-agent binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		binary = resolved
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	spec := serviceSpec{
+		Binary:  binary,
+		Args:    []string{"serve"},
+		Env:     map[string]string{},
+		Secrets: map[string]string{},
+		EnvFile: filepath.Join(home, ".kc", serviceEnvFile),
+		LogPath: *logPath,
+	}
+	if spec.LogPath == "" {
+		spec.LogPath = filepath.Join(home, ".kc", "logs", "kc-agent.log")
+	}
+	if *port > 0 {
+		spec.Args = append(spec.Args, "--port", strconv.Itoa(*port))
+	}
+	if *configPath != "" {
+		abs, err := filepath.Abs(*configPath)
+		if err != nil {
+			return err
+		}
+		spec.Args = append(spec.Args, "--config", abs)
+	}
+	// Services start with a minimal environment; keep the PATH that finds
+	// kubectl, cloud auth plugins and AI CLIs
+	if path := os.Getenv("PATH"); path != "" {
+		spec.Env["PATH"] = path
+	}
+	if *kubeconfig != "" {
+		spec.Env["KUBECONFIG"] = *kubeconfig
+	}
+	// The token never goes on a command line, where ps would show it
+	token, err := readServiceToken(*tokenFile)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		spec.Secrets["KC_AGENT_TOKEN"] = token
+	}
+	// --env values may hold credentials too, so they get the same treatment
+	for k, v := range env {
+		spec.Secrets[k] = v
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return installLaunchdService(home, spec, *dryRun)
+	case "linux":
+		return installSystemdService(home, spec, *dryRun)
+	default:
+		return fmt.Errorf("install-service supports macOS (launchd) and Linux (systemd), not %s", runtime.GOOS)
+	}
+}
+
+func runUninstallService(args []string) error {
+	fs := flag.NewFlagSet("kc-agent uninstall-service", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		path := launchdPlistPath(home)
+		// Not loaded is fine; the plist is still removed
+		runServiceTool("launchctl", "bootout", fmt.Sprintf("gui/%d/%s", os.Getuid(), serviceLabel)) //nolint:errcheck
+		return removeServiceFile(path)
+	case "linux":
+		path := systemdUnitPath(home)
+		runServiceTool("systemctl", "--user", "disable", "--now", serviceUnitName) //nolint:errcheck
+		if err := removeServiceFile(path); err != nil {
+			return err
+		}
+		if err := os.Remove(filepath.Join(home, ".kc", serviceEnvFile)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return runServiceTool("systemctl", "--user", "daemon-reload")
+	default:
+		return fmt.Errorf("uninstall-service supports macOS (launchd) and Linux (systemd), not %s", runtime.GOOS)
+	}
+}
+
+func launchdPlistPath(home string) string {
+	return filepath.Join(home, "Library", "LaunchAgents", serviceLabel+".plist")
+}
+
+func systemdUnitPath(home string) string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "systemd", "user", serviceUnitName)
+}
+
+func installLaunchdService(home string, spec serviceSpec, dryRun bool) error {
+	plist := renderLaunchdPlist(spec)
+	if dryRun {
+		fmt.Print(plist)
+		return nil
+	}
+	path := launchdPlistPath(home)
+	if err := writeServiceFile(path, plist, spec.LogPath); err != nil {
+		return err
+	}
+
+	domain := fmt.Sprintf("gui/%d", os.Getuid())
+	// Reinstalling replaces a loaded agent
+	runServiceTool("launchctl", "bootout", domain+"/"+serviceLabel) //nolint:errcheck
+	if err := runServiceTool("launchctl", "bootstrap", domain, path); err != nil {
+		return err
+	}
+	fmt.Printf("Installed %s\nLogs: %s\nRemove with: kc-agent uninstall-service\n", path, spec.LogPath)
+	return nil
+}
+
+// readServiceToken reads the agent token from path ("-" for stdin), or from
+// KC_AGENT_TOKEN when no path is given
+func readServiceToken(path string) (string, error) {
+	var raw []byte
+	var err error
+	switch path {
+	case "":
+		return os.Getenv("KC_AGENT_TOKEN"), nil
+	case "-":
+		raw, err = io.ReadAll(os.Stdin)
+	default:
+		raw, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read token: %w", err)
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+func installSystemdService(home string, spec serviceSpec, dryRun bool) error {
+	unit, err := renderSystemdUnit(spec)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Print(unit)
+		return nil
+	}
+	path := systemdUnitPath(home)
+	if err := syncServiceEnvFile(spec); err != nil {
+		return err
+	}
+	if err := writeServiceFile(path, unit, spec.LogPath); err != nil {
+		return err
+	}
+
+	if err := runServiceTool("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	if err := runServiceTool("systemctl", "--user", "enable", "--now", serviceUnitName); err != nil {
+		return err
+	}
+	fmt.Printf("Installed %s\nLogs: %s\nRemove with: kc-agent uninstall-service\n", path, spec.LogPath)
+	fmt.Println("To keep the agent running while logged out: loginctl enable-linger " + os.Getenv("USER"))
+	return nil
+}
+
+// syncServiceEnvFile writes spec.Secrets to spec.EnvFile, or removes the file
+// when there are none so a reinstall without a token drops the previous one
+func syncServiceEnvFile(spec serviceSpec) error {
+	if len(spec.Secrets) == 0 {
+		if err := os.Remove(spec.EnvFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return writeServiceFile(spec.EnvFile, renderEnvFile(spec.Secrets), spec.LogPath)
+}
+
+// renderLaunchdPlist generates a launchd agent that starts at login and is
+// restarted if it exits. launchd has no environment file, so secrets go in the
+// plist, which is written with mode 0600.
+func renderLaunchdPlist(spec serviceSpec) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(serviceLabel))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{spec.Binary}, spec.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	env := make(map[string]string, len(spec.Env)+len(spec.Secrets))
+	for k, v := range spec.Env {
+		env[k] = v
+	}
+	for k, v := range spec.Secrets {
+		env[k] = v
+	}
+	if len(env) > 0 {
+		b.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, k := range sortedKeys(env) {
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", xmlEscape(k), xmlEscape(env[k]))
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n\t<key>KeepAlive</key>\n\t<true/>\n")
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(spec.LogPath))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(spec.LogPath))
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// renderSystemdUnit generates a systemd user unit that starts with the user
+// session and is restarted if it exits (auto-update exits to restart). Secrets
+// are read from spec.EnvFile rather than written into the unit, where
+// systemctl show would print them. Control characters are rejected, since a
+// line break would start a new directive.
+func renderSystemdUnit(spec serviceSpec) (string, error) {
+	if err := checkSystemdValues(spec); err != nil {
+		return "", err
+	}
+	logPath, err := systemdPath(spec.LogPath)
+	if err != nil {
+		return "", fmt.Errorf("log file: %w", err)
+	}
+	var b strings.Builder
+	b.WriteString("[Unit]\nDescription=KubeStellar Console local agent (kc-agent)\nAfter=network-online.target\n\n[Service]\n")
+	args := make([]string, 0, len(spec.Args)+1)
+	for _, arg := range append([]string{spec.Binary}, spec.Args...) {
+		args = append(args, systemdQuote(arg, true))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+	for _, k := range sortedKeys(spec.Env) {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(k+"="+spec.Env[k], false))
+	}
+	if len(spec.Secrets) > 0 {
+		envFile, err := systemdPath(spec.EnvFile)
+		if err != nil {
+			return "", fmt.Errorf("environment file: %w", err)
+		}
+		fmt.Fprintf(&b, "EnvironmentFile=%s\n", envFile)
+	}
+	b.WriteString("Restart=always\nRestartSec=5\n")
+	fmt.Fprintf(&b, "StandardOutput=append:%s\nStandardError=append:%s\n", logPath, logPath)
+	b.WriteString("\n[Install]\nWantedBy=default.target\n")
+	return b.String(), nil
+}
+
+// systemdPath escapes % specifiers in a path for settings that take it
+// unquoted, like StandardOutput=append: and EnvironmentFile=. Those cannot
+// quote whitespace, so such paths, and those with control characters, are rejected.
+func systemdPath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("%q is not an absolute path", path)
+	}
+	if strings.IndexFunc(path, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 || strings.ContainsAny(path, "\\\"'") {
+		return "", fmt.Errorf("%q contains whitespace, control characters, quotes or backslashes, which systemd cannot take here", path)
+	}
+	return strings.ReplaceAll(path, "%", "%%"), nil
+}
+
+// checkSystemdValues rejects control characters in anything written to the
+// unit or the environment file
+func checkSystemdValues(spec serviceSpec) error {
+	words := append([]string{spec.Binary}, spec.Args...)
+	for _, env := range []map[string]string{spec.Env, spec.Secrets} {
+		for k, v := range env {
+			if strings.IndexFunc(k+v, unicode.IsControl) >= 0 {
+				return fmt.Errorf("environment variable %q contains control characters", k)
+			}
+		}
+	}
+	for _, w := range words {
+		if strings.IndexFunc(w, unicode.IsControl) >= 0 {
+			return fmt.Errorf("argument %q contains control characters", w)
+		}
+	}
+	return nil
+}
+
+// renderEnvFile generates a systemd EnvironmentFile; values are double-quoted
+// so they may hold spaces
+func renderEnvFile(env map[string]string) string {
+	var b strings.Builder
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	for _, k := range sortedKeys(env) {
+		fmt.Fprintf(&b, "%s=\"%s\"\n", k, quote.Replace(env[k]))
+	}
+	return b.String()
+}
+
+// systemdQuote double-quotes a word for ExecStart= or Environment=. Both
+// expand % specifiers; only ExecStart= expands $ variables.
+func systemdQuote(s string, exec bool) string {
+	pairs := []string{`\`, `\\`, `"`, `\"`, "%", "%%"}
+	if exec {
+		pairs = append(pairs, "$", "$$")
+	}
+	return `"` + strings.NewReplacer(pairs...).Replace(s) + `"`
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s)) //nolint:errcheck
+	return buf.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeServiceFile(path, content, logPath string) error {
+	if err := os.MkdirAll(filepath.Dir(path), serviceDirMode); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), serviceDirMode); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), serviceFileMode); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(path, serviceFileMode)
+}
+
+func removeServiceFile(path string) error {
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no service installed at %s", path)
+		}
+		return err
+	}
+	fmt.Printf("Removed %s\n", path)
+	return nil
+}
+
+// runServiceTool runs launchctl or systemctl, including its output in errors
+func runServiceTool(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		exec bool
+		want string
+	}{
+		{"/usr/local/bin/kc-agent", true, `"/usr/local/bin/kc-agent"`},
+		{"/Users/Jane Doe/bin/kc-agent", true, `"/Users/Jane Doe/bin/kc-agent"`},
+		{`say "hi"\now`, true, `"say \"hi\"\\now"`},
+		{"100%", true, `"100%%"`},
+		{"$HOME", true, `"$$HOME"`},
+		{"PATH=$HOME/bin:%h", false, `"PATH=$HOME/bin:%%h"`},
+	}
+	for _, tt := range tests {
+		if got := systemdQuote(tt.in, tt.exec); got != tt.want {
+			t.Errorf("systemdQuote(%q, %v) = %s, want %s", tt.in, tt.exec, got, tt.want)
+		}
+	}
+}
+
+func TestRenderSystemdUnit(t *testing.T) {
+	base := serviceSpec{
+		Binary:  "/opt/kc/kc-agent",
+		Args:    []string{"serve", "--port", "8585"},
+		Env:     map[string]string{"KUBECONFIG": "/home/jane/.kube/config"},
+		EnvFile: "/home/jane/.kc/kc-agent.env",
+		LogPath: "/home/jane/.kc/logs/kc-agent.log",
+	}
+	tests := []struct {
+		name    string
+		mutate  func(*serviceSpec)
+		want    []string
+		notWant []string
+		wantErr bool
+	}{
+		{
+			name: "plain",
+			want: []string{
+				`ExecStart="/opt/kc/kc-agent" "serve" "--port" "8585"`,
+				`Environment="KUBECONFIG=/home/jane/.kube/config"`,
+				"StandardOutput=append:/home/jane/.kc/logs/kc-agent.log\n",
+				"StandardError=append:/home/jane/.kc/logs/kc-agent.log\n",
+			},
+			notWant: []string{"EnvironmentFile="},
+		},
+		{
+			name:    "secrets go to the environment file",
+			mutate:  func(s *serviceSpec) { s.Secrets = map[string]string{"KC_AGENT_TOKEN": "s3cret"} },
+			want:    []string{"EnvironmentFile=/home/jane/.kc/kc-agent.env\n"},
+			notWant: []string{"s3cret", "KC_AGENT_TOKEN"},
+		},
+		{
+			name:   "specifiers in the log path are escaped",
+			mutate: func(s *serviceSpec) { s.LogPath = "/home/jane/100%h/kc-agent.log" },
+			want:   []string{"StandardOutput=append:/home/jane/100%%h/kc-agent.log\n"},
+		},
+		{
+			name:    "whitespace in the log path is rejected",
+			mutate:  func(s *serviceSpec) { s.LogPath = "/home/Jane Doe/.kc/logs/kc-agent.log" },
+			wantErr: true,
+		},
+		{
+			name:    "relative log path is rejected",
+			mutate:  func(s *serviceSpec) { s.LogPath = "kc-agent.log" },
+			wantErr: true,
+		},
+		{
+			name: "whitespace in the environment file is rejected",
+			mutate: func(s *serviceSpec) {
+				s.Secrets = map[string]string{"KC_AGENT_TOKEN": "s3cret"}
+				s.EnvFile = "/home/Jane Doe/.kc/kc-agent.env"
+			},
+			wantErr: true,
+		},
+		{
+			name:    "a line break in an environment value is rejected",
+			mutate:  func(s *serviceSpec) { s.Env = map[string]string{"A": "1\nExecStartPre=/bin/evil"} },
+			wantErr: true,
+		},
+		{
+			name:    "a line break in a secret is rejected",
+			mutate:  func(s *serviceSpec) { s.Secrets = map[string]string{"KC_AGENT_TOKEN": "s3cret\nEVIL=1"} },
+			wantErr: true,
+		},
+		{
+			name:    "a control character in an argument is rejected",
+			mutate:  func(s *serviceSpec) { s.Args = []string{"serve", "--config", "/tmp/a\rb.yaml"} },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := base
+			if tt.mutate != nil {
+				tt.mutate(&spec)
+			}
+			unit, err := renderSystemdUnit(spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got unit:\n%s", unit)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(unit, w) {
+					t.Errorf("unit lacks %q:\n%s", w, unit)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(unit, w) {
+					t.Errorf("unit should not contain %q:\n%s", w, unit)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderEnvFile(t *testing.T) {
+	got := renderEnvFile(map[string]string{"KC_AGENT_TOKEN": `a "b" \c`, "A": "1"})
+	want := "A=\"1\"\nKC_AGENT_TOKEN=\"a \\\"b\\\" \\\\c\"\n"
+	if got != want {
+		t.Errorf("renderEnvFile = %q, want %q", got, want)
+	}
+}
+
+func TestSyncServiceEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	spec := serviceSpec{
+		Secrets: map[string]string{"KC_AGENT_TOKEN": "s3cret", "API_KEY": "k"},
+		EnvFile: filepath.Join(dir, serviceEnvFile),
+		LogPath: filepath.Join(dir, "logs", "kc-agent.log"),
+	}
+	if err := syncServiceEnvFile(spec); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(spec.EnvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(raw), "API_KEY=\"k\"\nKC_AGENT_TOKEN=\"s3cret\"\n"; got != want {
+		t.Errorf("env file = %q, want %q", got, want)
+	}
+
+	// Reinstalling without secrets removes the previous token
+	spec.Secrets = map[string]string{}
+	if err := syncServiceEnvFile(spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(spec.EnvFile); !os.IsNotExist(err) {
+		t.Errorf("expected the env file to be removed, got %v", err)
+	}
+	if err := syncServiceEnvFile(spec); err != nil {
+		t.Errorf("removing a missing env file should succeed, got %v", err)
+	}
+}
+
+func TestRenderLaunchdPlist(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serviceSpec
+		want []string
+	}{
+		{
+			name: "arguments and paths are XML-escaped",
+			spec: serviceSpec{
+				Binary:  "/Users/Jane Doe/bin/kc-agent",
+				Args:    []string{"serve", "--config", "/Users/Jane Doe/a&b.yaml"},
+				LogPath: "/Users/Jane Doe/.kc/logs/<kc>.log",
+			},
+			want: []string{
+				"<string>/Users/Jane Doe/bin/kc-agent</string>",
+				"<string>/Users/Jane Doe/a&amp;b.yaml</string>",
+				"<key>StandardOutPath</key>\n\t<string>/Users/Jane Doe/.kc/logs/&lt;kc&gt;.log</string>",
+				"<key>StandardErrorPath</key>\n\t<string>/Users/Jane Doe/.kc/logs/&lt;kc&gt;.log</string>",
+			},
+		},
+		{
+			name: "environment and secrets are merged in key order",
+			spec: serviceSpec{
+				Binary:  "/opt/kc/kc-agent",
+				Env:     map[string]string{"PATH": "/usr/bin"},
+				Secrets: map[string]string{"KC_AGENT_TOKEN": "s3cret"},
+				LogPath: "/tmp/kc.log",
+			},
+			want: []string{
+				"<key>EnvironmentVariables</key>",
+				"<key>KC_AGENT_TOKEN</key>\n\t\t<string>s3cret</string>\n\t\t<key>PATH</key>\n\t\t<string>/usr/bin</string>",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plist := renderLaunchdPlist(tt.spec)
+			for _, w := range tt.want {
+				if !strings.Contains(plist, w) {
+					t.Errorf("plist lacks %q:\n%s", w, plist)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	agentFileName = "agent.yaml"
+	// AgentFileEnv overrides the location of the agent config file (default ~/.kc/agent.yaml)
+	AgentFileEnv = "KC_AGENT_CONFIG"
+
+	agentFilePollInterval = 5 * time.Second
+)
+
+// AgentFile is the optional ~/.kc/agent.yaml. Every setting can be overridden by the
+// matching flag or env var. Origins, token, provider defaults, cluster filters and
+// profiles are reloaded live; port, kubeconfig, timeouts, retries, API client
+// tuning, namespace scope, features, the API proxy and discovery apply at startup.
+type AgentFile struct {
+	Port           int                          `yaml:"port,omitempty"`
+	GRPCPort       int                          `yaml:"grpcPort,omitempty"`
+	Kubeconfig     string                       `yaml:"kubeconfig,omitempty"`
+	AllowedOrigins []string                     `yaml:"allowedOrigins,omitempty"`
+	Token          string                       `yaml:"token,omitempty"`
+	Timeouts       AgentFileTimeouts            `yaml:"timeouts,omitempty"`
+	Retries        AgentFileRetries             `yaml:"retries,omitempty"`
+	APIClient      AgentFileAPIClient           `yaml:"apiClient,omitempty"`
+	NamespaceScope AgentFileNamespaceScope      `yaml:"namespaceScope,omitempty"`
+	Features       map[string]bool              `yaml:"features,omitempty"`
+	DefaultAgent   string                       `yaml:"defaultAgent,omitempty"`
+	Providers      map[string]AgentFileProvider `yaml:"providers,omitempty"`
+	Clusters       AgentFileClusters            `yaml:"clusters,omitempty"`
+	Proxy          AgentFileProxy               `yaml:"proxy,omitempty"`
+	Discovery      AgentFileDiscovery           `yaml:"discovery,omitempty"`
+	Backend        AgentFileBackend             `yaml:"backend,omitempty"`
+	Profiles       map[string]AgentFileProfile  `yaml:"profiles,omitempty"`
+	Profile        string                       `yaml:"profile,omitempty"` // active at startup; --profile overrides
+}
+
+// AgentFileTimeouts overrides the agent's request timeouts, e.g. "45s"
+type AgentFileTimeouts struct {
+	Default  time.Duration `yaml:"default,omitempty"`  // most cluster queries
+	Extended time.Duration `yaml:"extended,omitempty"` // slow operations (upgrades, large lists)
+	Command  time.Duration `yaml:"command,omitempty"`  // single-cluster HTTP queries
+}
+
+// AgentFileRetries tunes retries of Kubernetes API reads after throttling or
+// transient errors. KC_K8S_RETRY_ATTEMPTS takes precedence over maxAttempts.
+type AgentFileRetries struct {
+	MaxAttempts int           `yaml:"maxAttempts,omitempty"` // 1 disables retries
+	BaseDelay   time.Duration `yaml:"baseDelay,omitempty"`
+	MaxDelay    time.Duration `yaml:"maxDelay,omitempty"`
+}
+
+// AgentFileAPIClient tunes the rate limit and connections of the Kubernetes API
+// clients. KC_K8S_QPS and KC_K8S_BURST take precedence over qps and burst.
+type AgentFileAPIClient struct {
+	QPS       float32                         `yaml:"qps,omitempty"`
+	Burst     int                             `yaml:"burst,omitempty"`
+	KeepAlive time.Duration                   `yaml:"keepAlive,omitempty"` // TCP keep-alive probe interval
+	Clusters  map[string]AgentFileClusterRate `yaml:"clusters,omitempty"`  // per-context qps/burst
+}
+
+// AgentFileClusterRate overrides the API client rate limit of one context
+type AgentFileClusterRate struct {
+	QPS   float32 `yaml:"qps,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
+}
+
+// isZero reports whether no API client setting is present
+func (c AgentFileAPIClient) isZero() bool {
+	return c.QPS == 0 && c.Burst == 0 && c.KeepAlive == 0 && len(c.Clusters) == 0
+}
+
+// AgentFileNamespaceScope restricts every namespaced query, issue finder and AI
+// tool to a set of namespaces, for developers without cluster-wide read access.
+// KC_NAMESPACE_SCOPE takes precedence.
+type AgentFileNamespaceScope struct {
+	Namespaces []string `yaml:"namespaces,omitempty"` // fixed list used on every cluster
+	Discover   bool     `yaml:"discover,omitempty"`   // otherwise, namespaces where pods can be listed
+}
+
+// Enabled reports whether the agent runs namespace-scoped
+func (s AgentFileNamespaceScope) Enabled() bool {
+	return len(s.Namespaces) > 0 || s.Discover
+}
+
+func (s AgentFileNamespaceScope) scope() k8s.NamespaceScope {
+	return k8s.NamespaceScope{Namespaces: s.Namespaces, Discover: s.Discover}
+}
+
+// AgentFileProvider holds defaults for an AI provider. Env vars and keys saved
+// from the UI (~/.kc/config.yaml) take precedence.
+type AgentFileProvider struct {
+	APIKey string `yaml:"apiKey,omitempty"`
+	Model  string `yaml:"model,omitempty"`
+}
+
+// AgentFileClusters limits which kubeconfig contexts the agent exposes. Entries are
+// regular expressions matched against the whole context name.
+type AgentFileClusters struct {
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// AgentFilePath returns $KC_AGENT_CONFIG or ~/.kc/agent.yaml
+func AgentFilePath() string {
+	if p := os.Getenv(AgentFileEnv); p != "" {
+		return p
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, configDirName, agentFileName)
+}
+
+// LoadAgentFile reads an agent config file. A missing file yields an empty config.
+func LoadAgentFile(path string) (*AgentFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &AgentFile{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var f AgentFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if _, err := k8s.NewClusterFilter(f.Clusters.Include, f.Clusters.Exclude); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := f.Proxy.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// FeatureEnabled reports whether a feature is on; features are on unless set to false
+func (f *AgentFile) FeatureEnabled(name string) bool {
+	if f == nil {
+		return true
+	}
+	enabled, ok := f.Features[name]
+	return !ok || enabled
+}
+
+// applyTimeouts overrides the package timeouts; called once before serving
+func (f *AgentFile) applyTimeouts() {
+	if f.Timeouts.Default > 0 {
+		agentDefaultTimeout = f.Timeouts.Default
+	}
+	if f.Timeouts.Extended > 0 {
+		agentExtendedTimeout = f.Timeouts.Extended
+	}
+	if f.Timeouts.Command > 0 {
+		agentCommandTimeout = f.Timeouts.Command
+	}
+}
+
+// retryPolicy applies the agent.yaml retry overrides to policy
+func (f *AgentFile) retryPolicy(policy k8s.RetryPolicy) k8s.RetryPolicy {
+	if f.Retries.MaxAttempts > 0 && os.Getenv(k8s.RetryAttemptsEnv) == "" {
+		policy.MaxAttempts = f.Retries.MaxAttempts
+	}
+	if f.Retries.BaseDelay > 0 {
+		policy.BaseDelay = f.Retries.BaseDelay
+	}
+	if f.Retries.MaxDelay > 0 {
+		policy.MaxDelay = f.Retries.MaxDelay
+	}
+	return policy
+}
+
+// clientTuning applies the agent.yaml API client overrides to tuning
+func (f *AgentFile) clientTuning(tuning k8s.ClientTuning) k8s.ClientTuning {
+	if f.APIClient.QPS > 0 && os.Getenv(k8s.QPSEnv) == "" {
+		tuning.QPS = f.APIClient.QPS
+	}
+	if f.APIClient.Burst > 0 && os.Getenv(k8s.BurstEnv) == "" {
+		tuning.Burst = f.APIClient.Burst
+	}
+	if f.APIClient.KeepAlive > 0 {
+		tuning.KeepAlive = f.APIClient.KeepAlive
+	}
+	if len(f.APIClient.Clusters) > 0 {
+		tuning.Clusters = make(map[string]k8s.ClusterRateLimit, len(f.APIClient.Clusters))
+		for name, rate := range f.APIClient.Clusters {
+			tuning.Clusters[name] = k8s.ClusterRateLimit{QPS: rate.QPS, Burst: rate.Burst}
+		}
+	}
+	return tuning
+}
+
+// buildAllowedOrigins combines the built-in origins with agent.yaml, KC_ALLOWED_ORIGINS
+// and --allowed-origins
+func buildAllowedOrigins(file *AgentFile, flagOrigins []string) []string {
+	allowedOrigins := append([]string{}, defaultAllowedOrigins...)
+	add := func(origins []string) {
+		for _, origin := range origins {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				allowedOrigins = append(allowedOrigins, origin)
+			}
+		}
+	}
+	if file != nil {
+		add(file.AllowedOrigins)
+	}
+	if extraOrigins := os.Getenv("KC_ALLOWED_ORIGINS"); extraOrigins != "" {
+		add(strings.Split(extraOrigins, ","))
+	}
+	add(flagOrigins)
+	return allowedOrigins
+}
+
+// agentTokenFrom returns KC_AGENT_TOKEN, falling back to agent.yaml
+func agentTokenFrom(file *AgentFile) string {
+	if token := os.Getenv("KC_AGENT_TOKEN"); token != "" {
+		return token
+	}
+	if file != nil {
+		return file.Token
+	}
+	return ""
+}
+
+// applyAgentFile applies the live-reloadable settings of an agent config file
+func (s *Server) applyAgentFile(file *AgentFile) {
+	origins := buildAllowedOrigins(file, s.config.AllowedOrigins)
+	token := agentTokenFrom(file)
+
+	s.authMu.Lock()
+	s.allowedOrigins = origins
+	s.agentToken = token
+	s.authMu.Unlock()
+
+	if s.k8sClient != nil {
+		filter, err := k8s.NewClusterFilter(file.Clusters.Include, file.Clusters.Exclude)
+		if err != nil {
+			log.Printf("Warning: ignoring cluster filter: %v", err)
+		} else {
+			s.k8sClient.SetClusterFilter(filter)
+		}
+	}
+
+	GetConfigManager().SetFileDefaults(file.DefaultAgent, file.Providers)
+}
+
+// watchAgentFile polls the agent config file and applies changes to the live settings
+func (s *Server) watchAgentFile(path string, current *AgentFile) {
+	stat := func() (time.Time, int64) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return info.ModTime(), info.Size()
+	}
+	lastMod, lastSize := stat()
+
+	ticker := time.NewTicker(agentFilePollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		mod, size := stat()
+		if mod.Equal(lastMod) && size == lastSize {
+			continue
+		}
+		lastMod, lastSize = mod, size
+
+		file, err := LoadAgentFile(path)
+		if err != nil {
+			log.Printf("[AgentConfig] Keeping previous settings: %v", err)
+			continue
+		}
+		s.reloadProfiles(file)
+		log.Printf("[AgentConfig] Reloaded %s", path)
+
+		if file.Port != current.Port || file.GRPCPort != current.GRPCPort || file.Kubeconfig != current.Kubeconfig ||
+			file.Timeouts != current.Timeouts || !reflect.DeepEqual(file.Features, current.Features) ||
+			!reflect.DeepEqual(file.Proxy, current.Proxy) || file.Discovery != current.Discovery ||
+			!reflect.DeepEqual(file.Backend, current.Backend) {
+			log.Printf("[AgentConfig] Port, kubeconfig, timeout, feature, proxy, discovery and backend changes take effect after a restart")
+		}
+		current = file
+	}
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const testAgentFile = `port: 9595
+allowedOrigins:
+  - https://console.example.com
+token: file-token
+timeouts:
+  command: 90s
+features:
+  deviceTracker: false
+providers:
+  openai:
+    model: gpt-test
+clusters:
+  include: ["prod-.*"]
+  exclude: ["prod-legacy"]
+`
+
+func TestLoadAgentFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	if err := os.WriteFile(path, []byte(testAgentFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadAgentFile(path)
+	if err != nil {
+		t.Fatalf("LoadAgentFile failed: %v", err)
+	}
+	if f.Port != 9595 || f.Token != "file-token" || f.Timeouts.Command != 90*time.Second {
+		t.Errorf("Unexpected config: %+v", f)
+	}
+	if f.FeatureEnabled(FeatureDeviceTracker) || !f.FeatureEnabled(FeaturePredictions) {
+		t.Error("Expected only deviceTracker to be disabled")
+	}
+	if !(*AgentFile)(nil).FeatureEnabled(FeaturePredictions) {
+		t.Error("Expected features to default to enabled without a config file")
+	}
+
+	if f, err := LoadAgentFile(filepath.Join(t.TempDir(), "missing.yaml")); err != nil || f.Port != 0 {
+		t.Errorf("Expected empty config for a missing file, got %+v, %v", f, err)
+	}
+
+	if err := os.WriteFile(path, []byte("clusters:\n  include: [\"prod-(\"]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAgentFile(path); err == nil {
+		t.Error("Expected an invalid cluster pattern to be rejected")
+	}
+}
+
+func TestAgentFileOverrides(t *testing.T) {
+	f := &AgentFile{Token: "file-token", AllowedOrigins: []string{"https://file.example.com"}}
+
+	t.Setenv("KC_AGENT_TOKEN", "")
+	t.Setenv("KC_ALLOWED_ORIGINS", "https://env.example.com")
+	if token := agentTokenFrom(f); token != "file-token" {
+		t.Errorf("Expected token from file, got %q", token)
+	}
+	t.Setenv("KC_AGENT_TOKEN", "env-token")
+	if token := agentTokenFrom(f); token != "env-token" {
+		t.Errorf("Expected env token to override the file, got %q", token)
+	}
+
+	origins := buildAllowedOrigins(f, []string{"https://flag.example.com"})
+	extra := origins[len(defaultAllowedOrigins):]
+	want := []string{"https://file.example.com", "https://env.example.com", "https://flag.example.com"}
+	if len(extra) != len(want) {
+		t.Fatalf("Expected origins %v, got %v", want, extra)
+	}
+	for i := range want {
+		if extra[i] != want[i] {
+			t.Errorf("Expected origins %v, got %v", want, extra)
+		}
+	}
+}
+
+func TestApplyAgentFileReload(t *testing.T) {
+	t.Setenv("KC_AGENT_TOKEN", "")
+	t.Setenv("KC_ALLOWED_ORIGINS", "")
+	defer GetConfigManager().SetFileDefaults("", nil)
+
+	m, _ := k8s.NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{
+		"prod-east": {Cluster: "pe"}, "prod-legacy": {Cluster: "pl"}, "kind-dev": {Cluster: "kd"},
+	}})
+	s := &Server{k8sClient: m}
+
+	f, err := LoadAgentFile(writeTempAgentFile(t, testAgentFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.applyAgentFile(f)
+
+	req := httptest.NewRequest("GET", "/clusters", nil)
+	if s.validateToken(req) {
+		t.Error("Expected requests without the new token to be rejected")
+	}
+	req.Header.Set("Authorization", "Bearer file-token")
+	if !s.validateToken(req) {
+		t.Error("Expected the reloaded token to be accepted")
+	}
+	if !s.isAllowedOrigin("https://console.example.com") {
+		t.Error("Expected the reloaded origin to be allowed")
+	}
+
+	clusters, _ := m.ListClusters(context.Background())
+	if len(clusters) != 1 || clusters[0].Name != "prod-east" {
+		t.Errorf("Expected cluster filter to leave prod-east only, got %+v", clusters)
+	}
+
+	if model := GetConfigManager().GetModel("openai", "default"); os.Getenv("OPENAI_MODEL") == "" && model != "gpt-test" {
+		t.Errorf("Expected provider model default from agent.yaml, got %q", model)
+	}
+}
+
+func writeTempAgentFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
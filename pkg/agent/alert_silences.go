@@ -0,0 +1,286 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+const (
+	maxSilenceDuration = 30 * 24 * time.Hour
+
+	// Alert families used in AlertSilence.AlertType
+	alertTypeDevice     = "device"
+	alertTypePrediction = "prediction"
+)
+
+// AlertSilencer matches alerts against the silences stored in settings
+type AlertSilencer struct {
+	mu   sync.Mutex
+	load func() []settings.AlertSilence
+	save func([]settings.AlertSilence) error
+	now  func() time.Time
+}
+
+// NewAlertSilencer creates a silencer backed by the given load/save functions
+func NewAlertSilencer(load func() []settings.AlertSilence, save func([]settings.AlertSilence) error) *AlertSilencer {
+	return &AlertSilencer{load: load, save: save, now: time.Now}
+}
+
+// settingsAlertSilences reads silences from the persisted settings
+func settingsAlertSilences() []settings.AlertSilence {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil || all == nil {
+		return nil
+	}
+	return all.AlertSilences
+}
+
+// saveSettingsAlertSilences replaces the silences in the persisted settings
+func saveSettingsAlertSilences(silences []settings.AlertSilence) error {
+	mgr := settings.GetSettingsManager()
+	all, err := mgr.GetAll()
+	if err != nil {
+		return err
+	}
+	if silences == nil {
+		silences = []settings.AlertSilence{}
+	}
+	all.AlertSilences = silences
+	return mgr.SaveAll(all)
+}
+
+// Active returns silences that have not expired
+func (a *AlertSilencer) Active() []settings.AlertSilence {
+	now := a.now()
+	active := []settings.AlertSilence{}
+	for _, s := range a.load() {
+		if silenceActive(s, now) {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+// Match returns the first active silence covering an alert, or nil
+func (a *AlertSilencer) Match(alertType, cluster, node string) *settings.AlertSilence {
+	if a == nil {
+		return nil
+	}
+	for _, s := range a.Active() {
+		if silenceMatches(s, alertType, cluster, node) {
+			return &s
+		}
+	}
+	return nil
+}
+
+// Add stores a new silence lasting duration and drops any expired ones
+func (a *AlertSilencer) Add(s settings.AlertSilence, duration time.Duration) (settings.AlertSilence, error) {
+	if duration <= 0 || duration > maxSilenceDuration {
+		return s, fmt.Errorf("duration must be positive and at most %s", maxSilenceDuration)
+	}
+	if strings.TrimSpace(s.Reason) == "" {
+		return s, fmt.Errorf("reason is required")
+	}
+	if s.Cluster == "" && s.Node == "" && s.AlertType == "" {
+		return s, fmt.Errorf("at least one of cluster, node or alertType is required")
+	}
+	if s.AlertType != "" {
+		switch family, _, _ := strings.Cut(s.AlertType, ":"); family {
+		case alertTypeDevice, alertTypePrediction:
+		default:
+			return s, fmt.Errorf("unknown alert type %q", s.AlertType)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	s.ID = uuid.New().String()
+	s.CreatedAt = now.Format(time.RFC3339)
+	s.ExpiresAt = now.Add(duration).Format(time.RFC3339)
+
+	silences := append(a.activeLocked(now), s)
+	if err := a.save(silences); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// Remove deletes a silence by ID, reporting whether it existed
+func (a *AlertSilencer) Remove(id string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	found := false
+	kept := []settings.AlertSilence{}
+	for _, s := range a.activeLocked(a.now()) {
+		if s.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, s)
+	}
+	if !found {
+		return false, nil
+	}
+	return true, a.save(kept)
+}
+
+func (a *AlertSilencer) activeLocked(now time.Time) []settings.AlertSilence {
+	active := []settings.AlertSilence{}
+	for _, s := range a.load() {
+		if silenceActive(s, now) {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+func silenceActive(s settings.AlertSilence, now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, s.ExpiresAt)
+	return err == nil && now.Before(expires)
+}
+
+// silenceMatches reports whether every non-empty field of s matches the alert.
+// An AlertType without a ":" covers the whole family.
+func silenceMatches(s settings.AlertSilence, alertType, cluster, node string) bool {
+	if s.Cluster != "" && s.Cluster != cluster {
+		return false
+	}
+	if s.Node != "" && s.Node != node {
+		return false
+	}
+	if s.AlertType != "" && s.AlertType != alertType && !strings.HasPrefix(alertType, s.AlertType+":") {
+		return false
+	}
+	return true
+}
+
+// applyDeviceSilences marks silenced device alerts and returns how many are still active
+func (s *Server) applyDeviceSilences(resp *DeviceAlertsResponse) int {
+	unsilenced := 0
+	for i := range resp.Alerts {
+		alert := &resp.Alerts[i]
+		alert.Silenced = s.alertSilences.Match(alertTypeDevice+":"+alert.DeviceType, alert.Cluster, alert.NodeName) != nil
+		if !alert.Silenced {
+			unsilenced++
+		}
+	}
+	return unsilenced
+}
+
+// unsilencedDeviceAlerts returns the alerts that are not covered by a silence
+func unsilencedDeviceAlerts(alerts []DeviceAlert) []DeviceAlert {
+	out := make([]DeviceAlert, 0, len(alerts))
+	for _, a := range alerts {
+		if !a.Silenced {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// predictionSilenced reports whether a prediction is covered by a silence
+func (s *Server) predictionSilenced(p AIPrediction) bool {
+	return s.alertSilences.Match(alertTypePrediction+":"+p.Category, p.Cluster, p.Name) != nil
+}
+
+// SilenceRequest is the body for creating a silence
+type SilenceRequest struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Node      string `json:"node,omitempty"`
+	AlertType string `json:"alertType,omitempty"`
+	Reason    string `json:"reason"`
+	Duration  string `json:"duration"` // Go duration, e.g. "2h"
+}
+
+// handleAlertSilences lists (GET), creates (POST) or removes (DELETE ?id=) alert silences
+func (s *Server) handleAlertSilences(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.alertSilences == nil {
+		http.Error(w, "Alert silencing not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		json.NewEncoder(w).Encode(map[string]interface{}{"silences": s.alertSilences.Active()})
+
+	case "POST":
+		var req SilenceRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		duration, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid duration"})
+			return
+		}
+		silence, err := s.alertSilences.Add(settings.AlertSilence{
+			Cluster:   req.Cluster,
+			Node:      req.Node,
+			AlertType: req.AlertType,
+			Reason:    req.Reason,
+		}, duration)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		log.Printf("[Silences] Added silence %s (cluster=%q node=%q type=%q) until %s: %s",
+			silence.ID, silence.Cluster, silence.Node, silence.AlertType, silence.ExpiresAt, silence.Reason)
+		s.BroadcastToClients("alert_silences_updated", s.alertSilences.Active())
+		json.NewEncoder(w).Encode(silence)
+
+	case "DELETE":
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "id is required"})
+			return
+		}
+		removed, err := s.alertSilences.Remove(id)
+		if err != nil {
+			log.Printf("[Silences] failed to remove silence %s: %v", id, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to remove silence"})
+			return
+		}
+		if !removed {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "silence not found"})
+			return
+		}
+		s.BroadcastToClients("alert_silences_updated", s.alertSilences.Active())
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
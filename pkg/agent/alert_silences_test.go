@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+func newTestSilencer() (*AlertSilencer, *[]settings.AlertSilence) {
+	stored := []settings.AlertSilence{}
+	a := NewAlertSilencer(
+		func() []settings.AlertSilence { return stored },
+		func(s []settings.AlertSilence) error { stored = s; return nil },
+	)
+	return a, &stored
+}
+
+func TestAlertSilencer_Match(t *testing.T) {
+	a, _ := newTestSilencer()
+
+	if _, err := a.Add(settings.AlertSilence{Cluster: "prod", AlertType: "device", Reason: "GPU maintenance"}, time.Hour); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := a.Add(settings.AlertSilence{Node: "node-7", AlertType: "prediction:pod-crash", Reason: "known flake"}, time.Hour); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	tests := []struct {
+		alertType, cluster, node string
+		want                     bool
+	}{
+		{"device:gpu", "prod", "node-1", true},
+		{"device", "prod", "", true},
+		{"device:gpu", "staging", "node-1", false},
+		{"devices:gpu", "prod", "node-1", false},
+		{"prediction:pod-crash", "staging", "node-7", true},
+		{"prediction:capacity-risk", "staging", "node-7", false},
+		{"cluster-health", "prod", "", false},
+	}
+	for _, tt := range tests {
+		if got := a.Match(tt.alertType, tt.cluster, tt.node) != nil; got != tt.want {
+			t.Errorf("Match(%q, %q, %q) = %v, want %v", tt.alertType, tt.cluster, tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestAlertSilencer_ExpiryAndRemove(t *testing.T) {
+	a, stored := newTestSilencer()
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	short, _ := a.Add(settings.AlertSilence{Cluster: "prod", Reason: "upgrade"}, 10*time.Minute)
+	long, _ := a.Add(settings.AlertSilence{Cluster: "dev", Reason: "rebuild"}, 2*time.Hour)
+
+	now = now.Add(30 * time.Minute)
+	if a.Match("device:gpu", "prod", "n1") != nil {
+		t.Error("Expected expired silence not to match")
+	}
+	if active := a.Active(); len(active) != 1 || active[0].ID != long.ID {
+		t.Errorf("Expected only the long silence to be active, got %+v", active)
+	}
+
+	if removed, _ := a.Remove(short.ID); removed {
+		t.Error("Expected expired silence to be gone")
+	}
+	if removed, err := a.Remove(long.ID); !removed || err != nil {
+		t.Errorf("Expected long silence to be removed, got %v, %v", removed, err)
+	}
+	if len(*stored) != 0 {
+		t.Errorf("Expected no stored silences, got %+v", *stored)
+	}
+}
+
+func TestAlertSilencer_Validation(t *testing.T) {
+	a, _ := newTestSilencer()
+
+	if _, err := a.Add(settings.AlertSilence{Cluster: "prod"}, time.Hour); err == nil {
+		t.Error("Expected missing reason to be rejected")
+	}
+	if _, err := a.Add(settings.AlertSilence{Reason: "everything"}, time.Hour); err == nil {
+		t.Error("Expected silence without scope to be rejected")
+	}
+	if _, err := a.Add(settings.AlertSilence{AlertType: "pager:foo", Reason: "x"}, time.Hour); err == nil {
+		t.Error("Expected unknown alert type to be rejected")
+	}
+	if _, err := a.Add(settings.AlertSilence{AlertType: "cluster-health", Reason: "x"}, time.Hour); err == nil {
+		t.Error("Expected cluster-health, which no alert path checks, to be rejected")
+	}
+	if _, err := a.Add(settings.AlertSilence{Cluster: "prod", Reason: "x"}, 0); err == nil {
+		t.Error("Expected zero duration to be rejected")
+	}
+	if _, err := a.Add(settings.AlertSilence{Cluster: "prod", Reason: "x"}, 90*24*time.Hour); err == nil {
+		t.Error("Expected overly long duration to be rejected")
+	}
+}
+
+func TestApplyDeviceSilences(t *testing.T) {
+	a, _ := newTestSilencer()
+	a.Add(settings.AlertSilence{Cluster: "prod", Node: "gpu-1", Reason: "swap card"}, time.Hour)
+	s := &Server{alertSilences: a}
+
+	resp := DeviceAlertsResponse{Alerts: []DeviceAlert{
+		{NodeName: "gpu-1", Cluster: "prod", DeviceType: "gpu"},
+		{NodeName: "gpu-2", Cluster: "prod", DeviceType: "gpu"},
+	}}
+	if n := s.applyDeviceSilences(&resp); n != 1 {
+		t.Errorf("Expected 1 unsilenced alert, got %d", n)
+	}
+	if !resp.Alerts[0].Silenced || resp.Alerts[1].Silenced {
+		t.Errorf("Unexpected silenced flags: %+v", resp.Alerts)
+	}
+	if active := unsilencedDeviceAlerts(resp.Alerts); len(active) != 1 || active[0].NodeName != "gpu-2" {
+		t.Errorf("Expected gpu-2 to remain, got %+v", active)
+	}
+
+	// A server without silences treats everything as active
+	empty := &Server{}
+	if n := empty.applyDeviceSilences(&resp); n != 2 {
+		t.Errorf("Expected 2 unsilenced alerts without silencer, got %d", n)
+	}
+}
@@ -0,0 +1,209 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+)
+
+// Minimum absolute change from the baseline before a deviation is reported,
+// so tiny fluctuations on very flat series don't raise alerts
+const (
+	anomalyMinRestartDelta  = 3
+	anomalyMinNotReadyDelta = 1
+	anomalyMinGPUDelta      = 10 // percentage points
+)
+
+// anomalyMetric describes one per-cluster series extracted from metrics history
+type anomalyMetric struct {
+	key      string
+	label    string
+	minDelta float64
+	upOnly   bool // only increases are anomalous
+	extract  func(s MetricsSnapshot, cluster string) (float64, bool)
+}
+
+var anomalyMetrics = []anomalyMetric{
+	{
+		key:      "restarts",
+		label:    "Pod restarts",
+		minDelta: anomalyMinRestartDelta,
+		upOnly:   true,
+		extract: func(s MetricsSnapshot, cluster string) (float64, bool) {
+			total := 0
+			for _, p := range s.PodIssues {
+				if p.Cluster == cluster {
+					total += p.Restarts
+				}
+			}
+			return float64(total), true
+		},
+	},
+	{
+		key:      "not-ready-nodes",
+		label:    "NotReady nodes",
+		minDelta: anomalyMinNotReadyDelta,
+		upOnly:   true,
+		extract: func(s MetricsSnapshot, cluster string) (float64, bool) {
+			for _, c := range s.Clusters {
+				if c.Name == cluster {
+					return float64(c.NodeCount - c.HealthyNodes), true
+				}
+			}
+			return 0, false
+		},
+	},
+	{
+		key:      "gpu-allocation",
+		label:    "GPU allocation",
+		minDelta: anomalyMinGPUDelta,
+		extract: func(s MetricsSnapshot, cluster string) (float64, bool) {
+			allocated, total := 0, 0
+			for _, g := range s.GPUNodes {
+				if g.Cluster == cluster {
+					allocated += g.GPUAllocated
+					total += g.GPUTotal
+				}
+			}
+			if total == 0 {
+				return 0, false
+			}
+			return float64(allocated) / float64(total) * 100, true
+		},
+	},
+}
+
+// anomalyResult describes how far the latest sample is from its EWMA baseline
+type anomalyResult struct {
+	Latest   float64
+	Baseline float64
+	StdDev   float64
+	ZScore   float64
+}
+
+// detectAnomaly compares the last value against an exponentially weighted mean
+// and variance of the earlier values. It returns nil when there is not enough
+// history or the deviation is within zThreshold standard deviations.
+func detectAnomaly(values []float64, alpha, zThreshold float64, minSamples int, minDelta float64, upOnly bool) *anomalyResult {
+	if len(values) < minSamples+1 || len(values) < 2 {
+		return nil
+	}
+	baseline, latest := values[:len(values)-1], values[len(values)-1]
+
+	mean, variance := baseline[0], 0.0
+	for _, v := range baseline[1:] {
+		diff := v - mean
+		incr := alpha * diff
+		mean += incr
+		variance = (1 - alpha) * (variance + diff*incr)
+	}
+	std := math.Sqrt(variance)
+
+	dev := latest - mean
+	if upOnly && dev <= 0 {
+		return nil
+	}
+	if math.Abs(dev) < minDelta {
+		return nil
+	}
+
+	z := math.Inf(1)
+	if dev < 0 {
+		z = math.Inf(-1)
+	}
+	if std > 0 {
+		z = dev / std
+	}
+	if math.Abs(z) < zThreshold {
+		return nil
+	}
+	return &anomalyResult{Latest: latest, Baseline: mean, StdDev: std, ZScore: z}
+}
+
+// metricsAnomalyAnalyzer detects sudden changes in the metrics history using
+// rolling z-scores. It needs no AI provider.
+type metricsAnomalyAnalyzer struct {
+	history *MetricsHistory
+}
+
+// NewMetricsAnomalyAnalyzer creates an analyzer that reads from history
+func NewMetricsAnomalyAnalyzer(history *MetricsHistory) PredictionAnalyzer {
+	return &metricsAnomalyAnalyzer{history: history}
+}
+
+func (a *metricsAnomalyAnalyzer) Name() string { return "metrics-anomaly" }
+
+func (a *metricsAnomalyAnalyzer) Description() string {
+	return "Flags sudden changes in pod restarts, node readiness and GPU allocation compared to recent history"
+}
+
+func (a *metricsAnomalyAnalyzer) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		"zScore":     3,
+		"minSamples": 6,
+		"smoothing":  0.3, // EWMA alpha
+	}
+}
+
+func (a *metricsAnomalyAnalyzer) Analyze(cluster string, _ *ClusterAnalysisData, th map[string]float64) []AIPrediction {
+	if a.history == nil {
+		return nil
+	}
+	alpha := th["smoothing"]
+	if alpha <= 0 || alpha > 1 {
+		alpha = a.DefaultThresholds()["smoothing"]
+	}
+	zThreshold := th["zScore"]
+	minSamples := int(th["minSamples"])
+
+	snapshots := a.history.GetRecentSnapshots(0)
+
+	var out []AIPrediction
+	for _, m := range anomalyMetrics {
+		values := make([]float64, 0, len(snapshots))
+		for _, s := range snapshots {
+			if v, ok := m.extract(s, cluster); ok {
+				values = append(values, v)
+			}
+		}
+		// Restart totals are cumulative, so look at the increase per interval
+		if m.key == "restarts" {
+			values = positiveDeltas(values)
+		}
+
+		res := detectAnomaly(values, alpha, zThreshold, minSamples, m.minDelta, m.upOnly)
+		if res == nil {
+			continue
+		}
+
+		severity := severityWarning
+		if math.Abs(res.ZScore) >= 2*zThreshold {
+			severity = severityCritical
+		}
+		direction := "spiked"
+		if res.Latest < res.Baseline {
+			direction = "dropped"
+		}
+		pred := newHeuristicPrediction(a.Name(), "anomaly", severity, cluster, cluster,
+			fmt.Sprintf("%s %s to %.0f (baseline %.1f)", m.label, direction, res.Latest, res.Baseline),
+			fmt.Sprintf("%s on %s %s to %.1f against a recent baseline of %.1f (std dev %.2f, z-score %.1f). This is unusual compared to the last %d snapshots.",
+				m.label, cluster, direction, res.Latest, res.Baseline, res.StdDev, res.ZScore, len(values)-1))
+		pred.Trend = "worsening"
+		if m.key == "gpu-allocation" {
+			pred.Trend = ""
+		}
+		out = append(out, pred)
+	}
+	return out
+}
+
+// positiveDeltas turns a cumulative series into per-step increases
+func positiveDeltas(values []float64) []float64 {
+	if len(values) < 2 {
+		return nil
+	}
+	out := make([]float64, 0, len(values)-1)
+	for i := 1; i < len(values); i++ {
+		out = append(out, math.Max(0, values[i]-values[i-1]))
+	}
+	return out
+}
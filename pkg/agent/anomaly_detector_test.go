@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectAnomaly(t *testing.T) {
+	steady := []float64{2, 3, 2, 3, 2, 3, 2, 3}
+
+	tests := []struct {
+		name   string
+		values []float64
+		upOnly bool
+		want   bool
+	}{
+		{"not enough samples", []float64{1, 2, 50}, false, false},
+		{"steady series", append(append([]float64{}, steady...), 3), false, false},
+		{"spike", append(append([]float64{}, steady...), 30), false, true},
+		{"drop ignored when upOnly", append(append([]float64{}, steady...), -30), true, false},
+		{"drop reported", append(append([]float64{}, steady...), -30), false, true},
+		{"flat baseline below min delta", []float64{0, 0, 0, 0, 0, 0, 0.5}, false, false},
+		{"flat baseline above min delta", []float64{0, 0, 0, 0, 0, 0, 2}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := detectAnomaly(tt.values, 0.3, 3, 6, 1, tt.upOnly)
+			if (res != nil) != tt.want {
+				t.Errorf("detectAnomaly(%v) = %+v, want anomaly=%v", tt.values, res, tt.want)
+			}
+		})
+	}
+
+	if res := detectAnomaly([]float64{0, 0, 0, 0, 0, 0, 2}, 0.3, 3, 6, 1, true); res == nil || !math.IsInf(res.ZScore, 1) {
+		t.Errorf("Expected infinite z-score on flat baseline, got %+v", res)
+	}
+}
+
+func TestMetricsAnomalyAnalyzer(t *testing.T) {
+	mh := NewMetricsHistory(nil, t.TempDir())
+
+	start := time.Now().Add(-2 * time.Hour)
+	for i := 0; i < 10; i++ {
+		snap := MetricsSnapshot{
+			Timestamp: start.Add(time.Duration(i) * 10 * time.Minute).Format(time.RFC3339),
+			Clusters:  []ClusterMetricSnapshot{{Name: "prod", NodeCount: 5, HealthyNodes: 5}},
+			PodIssues: []PodIssueSnapshot{{Name: "api", Cluster: "prod", Restarts: i}},
+			GPUNodes:  []GPUNodeMetricSnapshot{{Name: "gpu-1", Cluster: "prod", GPUAllocated: 4, GPUTotal: 8}},
+		}
+		if i == 9 {
+			snap.Clusters[0].HealthyNodes = 2
+			snap.PodIssues[0].Restarts = 40
+		}
+		mh.snapshots = append(mh.snapshots, snap)
+	}
+
+	a := NewMetricsAnomalyAnalyzer(mh)
+	preds := a.Analyze("prod", nil, a.DefaultThresholds())
+
+	if len(preds) != 2 {
+		t.Fatalf("Expected restart and node readiness anomalies, got %d: %v", len(preds), preds)
+	}
+	for i, prefix := range []string{"Pod restarts spiked", "NotReady nodes spiked"} {
+		if preds[i].Category != "anomaly" || !strings.HasPrefix(preds[i].Reason, prefix) {
+			t.Errorf("Expected %q anomaly, got %+v", prefix, preds[i])
+		}
+	}
+
+	if preds := a.Analyze("staging", nil, a.DefaultThresholds()); len(preds) != 0 {
+		t.Errorf("Expected no anomalies for unknown cluster, got %d", len(preds))
+	}
+
+	// The analyzer is reachable through the worker like any other
+	w := NewPredictionWorker(nil, nil, nil, nil)
+	w.RegisterAnalyzer(a)
+	found := false
+	for _, an := range w.Analyzers() {
+		if an.Name() == "metrics-anomaly" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected metrics-anomaly to be registered, got %d analyzers", len(w.Analyzers()))
+	}
+}
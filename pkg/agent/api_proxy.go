@@ -0,0 +1,261 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const apiProxyTimeout = 30 * time.Second
+
+// defaultAPIProxyMethods are the methods forwarded when agent.yaml doesn't list any
+var defaultAPIProxyMethods = []string{http.MethodGet, http.MethodHead}
+
+// defaultAPIProxyPaths are the read-only API paths forwarded when agent.yaml
+// doesn't list any, as path.Match patterns
+var defaultAPIProxyPaths = []string{
+	"/version",
+	"/api/v1/namespaces",
+	"/api/v1/namespaces/*",
+	"/api/v1/nodes",
+	"/api/v1/nodes/*",
+	"/api/v1/pods",
+	"/api/v1/namespaces/*/pods",
+	"/api/v1/namespaces/*/pods/*",
+	"/api/v1/namespaces/*/pods/*/log",
+	"/api/v1/events",
+	"/api/v1/namespaces/*/events",
+	"/api/v1/services",
+	"/api/v1/namespaces/*/services",
+	"/api/v1/namespaces/*/services/*",
+	"/apis/apps/v1/*",
+	"/apis/apps/v1/namespaces/*/*",
+	"/apis/apps/v1/namespaces/*/*/*",
+	"/apis/batch/v1/*",
+	"/apis/batch/v1/namespaces/*/*",
+	"/apis/batch/v1/namespaces/*/*/*",
+}
+
+// apiProxyDeniedResources are never forwarded, whatever the path allowlist says
+var apiProxyDeniedResources = map[string]bool{
+	"secrets": true,
+}
+
+// apiProxyDeniedSubresources open interactive or unfiltered channels into the
+// cluster and are never forwarded
+var apiProxyDeniedSubresources = map[string]bool{
+	"exec":        true,
+	"attach":      true,
+	"portforward": true,
+	"proxy":       true,
+}
+
+// apiProxyQueryParams are the query parameters passed through; anything else
+// (watch, dryRun, ...) is dropped
+var apiProxyQueryParams = []string{
+	"labelSelector", "fieldSelector", "limit", "continue", "resourceVersion",
+	"container", "tailLines", "sinceSeconds", "previous", "timestamps",
+}
+
+// AgentFileProxy enables /proxy/{cluster}/..., which forwards read-only
+// Kubernetes API requests from the browser with the agent's credentials.
+// Paths are path.Match patterns and replace the defaults; methods may only
+// narrow GET and HEAD. Applies at startup.
+type AgentFileProxy struct {
+	Enabled bool     `yaml:"enabled,omitempty"`
+	Methods []string `yaml:"methods,omitempty"`
+	Paths   []string `yaml:"paths,omitempty"`
+}
+
+// validate rejects methods that could change cluster state and malformed patterns
+func (p AgentFileProxy) validate() error {
+	for _, m := range p.Methods {
+		if m = strings.ToUpper(m); m != http.MethodGet && m != http.MethodHead {
+			return fmt.Errorf("proxy: method %q is not read-only", m)
+		}
+	}
+	for _, pattern := range p.Paths {
+		if !strings.HasPrefix(pattern, "/") {
+			return fmt.Errorf("proxy: path %q must start with /", pattern)
+		}
+		if _, err := path.Match(pattern, "/"); err != nil {
+			return fmt.Errorf("proxy: invalid path %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// allows reports whether a method and API path may be forwarded
+func (p AgentFileProxy) allows(method, apiPath string) bool {
+	if !p.Enabled || apiProxyDenied(apiPath) {
+		return false
+	}
+	methods := p.Methods
+	if len(methods) == 0 {
+		methods = defaultAPIProxyMethods
+	}
+	methodAllowed := false
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			methodAllowed = true
+			break
+		}
+	}
+	if !methodAllowed {
+		return false
+	}
+	paths := p.Paths
+	if len(paths) == 0 {
+		paths = defaultAPIProxyPaths
+	}
+	for _, pattern := range paths {
+		if ok, _ := path.Match(pattern, apiPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// apiProxyDenied reports whether apiPath addresses secrets or a denied
+// subresource, e.g. /api/v1/namespaces/ns/pods/p/exec
+func apiProxyDenied(apiPath string) bool {
+	parts := strings.Split(strings.Trim(apiPath, "/"), "/")
+	var rest []string
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		rest = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		rest = parts[3:]
+	default:
+		return false
+	}
+	// namespaces/{ns}/{resource}/... addresses a namespaced resource
+	if len(rest) >= 3 && rest[0] == "namespaces" {
+		rest = rest[2:]
+	}
+	if len(rest) > 0 && apiProxyDeniedResources[rest[0]] {
+		return true
+	}
+	return len(rest) > 2 && apiProxyDeniedSubresources[rest[2]]
+}
+
+// splitAPIProxyPath splits /proxy/{cluster}/{apiPath} into the cluster and the
+// API path, rejecting paths that aren't in canonical form
+func splitAPIProxyPath(u *url.URL) (string, string, error) {
+	escaped := strings.TrimPrefix(u.EscapedPath(), "/proxy/")
+	escapedCluster, escapedPath, ok := strings.Cut(escaped, "/")
+	if !ok || escapedCluster == "" || escapedPath == "" {
+		return "", "", fmt.Errorf("expected /proxy/{cluster}/{path}")
+	}
+	cluster, err := url.PathUnescape(escapedCluster)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid cluster: %w", err)
+	}
+	apiPath, err := url.PathUnescape("/" + escapedPath)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid path: %w", err)
+	}
+	if path.Clean(apiPath) != apiPath {
+		return "", "", fmt.Errorf("path must be in canonical form")
+	}
+	return cluster, apiPath, nil
+}
+
+// handleAPIProxy forwards allowlisted read-only requests under
+// /proxy/{cluster}/... to the cluster's API server, authenticated with the
+// agent's kubeconfig credentials (client certificates, tokens or exec plugins)
+// and any impersonated identity of the request.
+func (s *Server) handleAPIProxy(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var policy AgentFileProxy
+	if s.config.AgentFile != nil {
+		policy = s.config.AgentFile.Proxy
+	}
+	if !policy.Enabled {
+		http.Error(w, `{"error":"API proxy is disabled"}`, http.StatusForbidden)
+		return
+	}
+
+	if s.k8sClient == nil {
+		http.Error(w, `{"error":"k8s client not initialized"}`, http.StatusServiceUnavailable)
+		return
+	}
+
+	cluster, apiPath, err := splitAPIProxyPath(r.URL)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+	if !policy.allows(r.Method, apiPath) {
+		http.Error(w, `{"error":"request not allowed by the proxy allowlist"}`, http.StatusForbidden)
+		return
+	}
+
+	config, err := s.k8sClient.GetRestConfig(cluster)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, "failed to get cluster config: "+err.Error()), http.StatusBadGateway)
+		return
+	}
+	// The cluster's shared client keeps one pooled connection per cluster
+	httpClient, err := s.k8sClient.GetHTTPClient(cluster)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, "failed to get cluster client: "+err.Error()), http.StatusBadGateway)
+		return
+	}
+	transport := httpClient.Transport
+
+	query := url.Values{}
+	incoming := r.URL.Query()
+	for _, key := range apiProxyQueryParams {
+		if v, ok := incoming[key]; ok {
+			query[key] = v
+		}
+	}
+	target := strings.TrimRight(config.Host, "/") + (&url.URL{Path: apiPath}).EscapedPath()
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), apiProxyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, r.Method, target, nil)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+	if accept := r.Header.Get("Accept"); accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, "API request failed: "+err.Error()), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, copyErr := io.Copy(w, resp.Body); copyErr != nil {
+		log.Printf("[APIProxy] failed to stream response for %s%s: %v", cluster, apiPath, copyErr)
+	}
+}
@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestAPIProxyAllows(t *testing.T) {
+	policy := AgentFileProxy{Enabled: true}
+	tests := []struct {
+		method string
+		path   string
+		want   bool
+	}{
+		{"GET", "/version", true},
+		{"GET", "/api/v1/namespaces/team-a/pods", true},
+		{"GET", "/api/v1/namespaces/team-a/pods/api-0/log", true},
+		{"HEAD", "/apis/apps/v1/namespaces/team-a/deployments/api", true},
+		{"POST", "/api/v1/namespaces/team-a/pods", false},
+		{"DELETE", "/api/v1/namespaces/team-a/pods/api-0", false},
+		{"GET", "/api/v1/namespaces/team-a/secrets", false},
+		{"GET", "/api/v1/secrets", false},
+		{"GET", "/api/v1/namespaces/team-a/pods/api-0/exec", false},
+		{"GET", "/api/v1/namespaces/team-a/services/web/proxy", false},
+		{"GET", "/apis/rbac.authorization.k8s.io/v1/clusterroles", false},
+	}
+	for _, tt := range tests {
+		if got := policy.allows(tt.method, tt.path); got != tt.want {
+			t.Errorf("allows(%s %s) = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+
+	if (AgentFileProxy{}).allows("GET", "/version") {
+		t.Error("Expected a disabled proxy to allow nothing")
+	}
+
+	custom := AgentFileProxy{Enabled: true, Methods: []string{"GET"}, Paths: []string{"/api/v1/*", "/api/v1/namespaces/*/*"}}
+	if custom.allows("HEAD", "/api/v1/nodes") || !custom.allows("GET", "/api/v1/nodes") {
+		t.Error("Expected configured methods to replace the defaults")
+	}
+	if custom.allows("GET", "/api/v1/namespaces/team-a/secrets") {
+		t.Error("Expected secrets to be denied even when a pattern matches")
+	}
+}
+
+func TestAgentFileProxyValidate(t *testing.T) {
+	if err := (AgentFileProxy{Methods: []string{"get", "HEAD"}, Paths: []string{"/api/v1/*"}}).validate(); err != nil {
+		t.Errorf("Expected read-only config to be valid, got %v", err)
+	}
+	if err := (AgentFileProxy{Methods: []string{"DELETE"}}).validate(); err == nil {
+		t.Error("Expected a mutating method to be rejected")
+	}
+	if err := (AgentFileProxy{Paths: []string{"api/v1/*"}}).validate(); err == nil {
+		t.Error("Expected a relative path to be rejected")
+	}
+	if err := (AgentFileProxy{Paths: []string{"/api/v1/["}}).validate(); err == nil {
+		t.Error("Expected a malformed pattern to be rejected")
+	}
+}
+
+func TestSplitAPIProxyPath(t *testing.T) {
+	u, _ := url.Parse("/proxy/arn%3Aaws%3Aeks%2Fprod/api/v1/pods")
+	cluster, apiPath, err := splitAPIProxyPath(u)
+	if err != nil || cluster != "arn:aws:eks/prod" || apiPath != "/api/v1/pods" {
+		t.Errorf("Unexpected split: %q %q %v", cluster, apiPath, err)
+	}
+	for _, raw := range []string{"/proxy/c1", "/proxy//api/v1/pods", "/proxy/c1/api/v1/../../secrets", "/proxy/c1/api/v1/pods/"} {
+		u, _ := url.Parse(raw)
+		if _, _, err := splitAPIProxyPath(u); err == nil {
+			t.Errorf("Expected %s to be rejected", raw)
+		}
+	}
+}
+
+func TestHandleAPIProxy(t *testing.T) {
+	t.Setenv("KC_AGENT_TOKEN", "")
+
+	var gotPath, gotQuery string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"kind":"PodList","items":[]}`)
+	}))
+	defer apiServer.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(api.Config{
+		Clusters:  map[string]*api.Cluster{"c1": {Server: apiServer.URL}},
+		AuthInfos: map[string]*api.AuthInfo{"u1": {Token: "cluster-token"}},
+		Contexts:  map[string]*api.Context{"c1": {Cluster: "c1", AuthInfo: "u1"}},
+	}, kubeconfig); err != nil {
+		t.Fatal(err)
+	}
+	m, err := k8s.NewMultiClusterClient(kubeconfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{k8sClient: m, config: Config{AgentFile: &AgentFile{Proxy: AgentFileProxy{Enabled: true}}}}
+
+	rec := httptest.NewRecorder()
+	s.handleAPIProxy(rec, httptest.NewRequest("GET", "/proxy/c1/api/v1/namespaces/team-a/pods?labelSelector=app%3Dapi&watch=true", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"kind":"PodList","items":[]}` {
+		t.Fatalf("Expected the API response to be forwarded, got %d %s", rec.Code, rec.Body.String())
+	}
+	if gotPath != "/api/v1/namespaces/team-a/pods" || gotQuery != "labelSelector=app%3Dapi" {
+		t.Errorf("Unexpected upstream request %s?%s", gotPath, gotQuery)
+	}
+
+	gotPath = ""
+	rec = httptest.NewRecorder()
+	s.handleAPIProxy(rec, httptest.NewRequest("GET", "/proxy/c1/api/v1/namespaces/team-a/secrets", nil))
+	if rec.Code != http.StatusForbidden || gotPath != "" {
+		t.Errorf("Expected secrets to be refused without reaching the cluster, got %d", rec.Code)
+	}
+
+	s.config.AgentFile = &AgentFile{}
+	rec = httptest.NewRecorder()
+	s.handleAPIProxy(rec, httptest.NewRequest("GET", "/proxy/c1/version", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected the proxy to be off by default, got %d", rec.Code)
+	}
+}
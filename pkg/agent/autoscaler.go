@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// handleAutoscaler reports cluster-autoscaler / Karpenter activity: node group state,
+// unschedulable pods and the autoscaler's decision for each, and recent scale events
+func (s *Server) handleAutoscaler(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"clusters": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+
+	cluster := r.URL.Query().Get("cluster")
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+
+	all := []*k8s.AutoscalerStatus{}
+
+	if cluster != "" {
+		status, err := s.k8sClient.GetAutoscalerStatus(ctx, cluster)
+		if err != nil {
+			log.Printf("error fetching autoscaler status: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"clusters": []interface{}{}, "error": "internal server error"})
+			return
+		}
+		all = append(all, status)
+	} else {
+		clusters, err := s.k8sClient.ListClusters(ctx)
+		if err != nil {
+			log.Printf("error fetching autoscaler status: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"clusters": []interface{}{}, "error": "internal server error"})
+			return
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+
+		for _, cl := range clusters {
+			wg.Add(1)
+			go func(clusterName string) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[Autoscaler] recovered from panic for cluster %s: %v", clusterName, r)
+					}
+				}()
+				clusterCtx, clusterCancel := context.WithTimeout(ctx, s.requestTimeout(r, clusterName, k8s.OpList, agentDefaultTimeout))
+				defer clusterCancel()
+				status, err := s.k8sClient.GetAutoscalerStatus(clusterCtx, clusterName)
+				if err == nil {
+					mu.Lock()
+					all = append(all, status)
+					mu.Unlock()
+				}
+			}(cl.Name)
+		}
+		wg.Wait()
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"clusters": all, "source": "agent"})
+}
@@ -0,0 +1,178 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBackendPort = 8080
+	// BackendBinaryEnv and BackendPortEnv take precedence over agent.yaml's backend section
+	BackendBinaryEnv = "KC_BACKEND_BINARY"
+	BackendPortEnv   = "KC_BACKEND_PORT"
+
+	portProbeTimeout   = 300 * time.Millisecond
+	portReleaseTimeout = 5 * time.Second
+	portReleasePoll    = 100 * time.Millisecond
+)
+
+// AgentFileBackend is the console backend kc-agent manages for restart-from-UI
+// and auto-update. Without a binary, kc-agent runs the console binary next to
+// itself or on PATH, then falls back to `go run ./cmd/console` in a checkout.
+// Applies at startup.
+type AgentFileBackend struct {
+	Binary string   `yaml:"binary,omitempty"`
+	Args   []string `yaml:"args,omitempty"`
+	Port   int      `yaml:"port,omitempty"` // default 8080
+}
+
+// withEnv applies KC_BACKEND_BINARY and KC_BACKEND_PORT
+func (b AgentFileBackend) withEnv() AgentFileBackend {
+	if bin := os.Getenv(BackendBinaryEnv); bin != "" {
+		b.Binary = bin
+	}
+	if v := os.Getenv(BackendPortEnv); v != "" {
+		if port, err := strconv.Atoi(v); err == nil && port > 0 && port < 65536 {
+			b.Port = port
+		} else {
+			log.Printf("Warning: ignoring invalid %s=%q", BackendPortEnv, v)
+		}
+	}
+	return b
+}
+
+func (b AgentFileBackend) port() int {
+	if b.Port > 0 {
+		return b.Port
+	}
+	return defaultBackendPort
+}
+
+func (b AgentFileBackend) healthURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d/health", b.port())
+}
+
+// command builds the command that starts the backend
+func (b AgentFileBackend) command() (*exec.Cmd, error) {
+	binary := b.Binary
+	if binary == "" {
+		binary = findConsoleBinary()
+	}
+	if binary != "" {
+		cmd := exec.Command(binary, b.Args...)
+		cmd.Env = os.Environ()
+		if b.Port > 0 {
+			cmd.Env = append(cmd.Env, "PORT="+strconv.Itoa(b.Port))
+		}
+		return cmd, nil
+	}
+
+	// Dev checkout without a built binary
+	if _, err := os.Stat("go.mod"); err != nil {
+		return nil, fmt.Errorf("no console binary found; set backend.binary in agent.yaml or %s", BackendBinaryEnv)
+	}
+	cmd := exec.Command("go", append([]string{"run", "./cmd/console"}, b.Args...)...)
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	if b.Port > 0 {
+		cmd.Env = append(cmd.Env, "PORT="+strconv.Itoa(b.Port))
+	}
+	return cmd, nil
+}
+
+// findConsoleBinary looks for the console binary next to kc-agent, then on PATH
+func findConsoleBinary() string {
+	name := "console"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	if self, err := os.Executable(); err == nil {
+		candidate := filepath.Join(filepath.Dir(self), name)
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate
+		}
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	return ""
+}
+
+// portListening reports whether something accepts connections on the local port
+func portListening(port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), portProbeTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// waitPortReleased waits for the port to stop accepting connections
+func waitPortReleased(port int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for portListening(port) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(portReleasePoll)
+	}
+	return true
+}
+
+// parseProcNetTCP returns the socket inodes listening on port in the
+// contents of /proc/net/tcp or /proc/net/tcp6
+func parseProcNetTCP(data []byte, port int) map[string]bool {
+	const listenState = "0A"
+	inodes := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != listenState {
+			continue
+		}
+		i := strings.LastIndex(fields[1], ":")
+		if i < 0 {
+			continue
+		}
+		p, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil || int(p) != port || fields[9] == "0" {
+			continue
+		}
+		inodes[fields[9]] = true
+	}
+	return inodes
+}
+
+// parseNetstatListeners returns the PIDs listening on port in `netstat -ano` output
+func parseNetstatListeners(out []byte, port int) []int {
+	suffix := ":" + strconv.Itoa(port)
+	seen := map[int]bool{}
+	var pids []int
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// Proto  Local Address  Foreign Address  State  PID
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 || !strings.EqualFold(fields[0], "TCP") || fields[3] != "LISTENING" ||
+			!strings.HasSuffix(fields[1], suffix) {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[4])
+		if err != nil || pid <= 0 || seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		pids = append(pids, pid)
+	}
+	return pids
+}
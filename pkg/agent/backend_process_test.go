@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseProcNetTCP(t *testing.T) {
+	data := []byte(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41234 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:D2F0 01 00000000:00000000 00:00000000 00000000  1000        0 41299 1 0000000000000000 20 4 30 10 -1
+   2: 00000000:2161 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 51234 1 0000000000000000 100 0 0 10 0
+`)
+	got := parseProcNetTCP(data, 8080)
+	if !reflect.DeepEqual(got, map[string]bool{"41234": true}) {
+		t.Errorf("Expected only the listening socket on 8080, got %v", got)
+	}
+}
+
+func TestParseNetstatListeners(t *testing.T) {
+	out := []byte(`
+Active Connections
+
+  Proto  Local Address          Foreign Address        State           PID
+  TCP    0.0.0.0:135            0.0.0.0:0              LISTENING       1024
+  TCP    0.0.0.0:8080           0.0.0.0:0              LISTENING       4312
+  TCP    127.0.0.1:8080         127.0.0.1:52011        ESTABLISHED     4312
+  TCP    127.0.0.1:52011        127.0.0.1:8080         ESTABLISHED     9001
+  TCP    [::]:8080              [::]:0                 LISTENING       4312
+  TCP    0.0.0.0:18080          0.0.0.0:0              LISTENING       77
+`)
+	got := parseNetstatListeners(out, 8080)
+	if !reflect.DeepEqual(got, []int{4312}) {
+		t.Errorf("Expected the listener pid only, got %v", got)
+	}
+}
+
+func TestAgentFileBackendCommand(t *testing.T) {
+	t.Setenv(BackendBinaryEnv, "")
+	t.Setenv(BackendPortEnv, "")
+
+	b := AgentFileBackend{Binary: "/opt/kc/console", Args: []string{"--dev"}, Port: 9090}.withEnv()
+	cmd, err := b.command()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cmd.Args, []string{"/opt/kc/console", "--dev"}) {
+		t.Errorf("Unexpected args %v", cmd.Args)
+	}
+	if cmd.Env[len(cmd.Env)-1] != "PORT=9090" {
+		t.Errorf("Expected the port passed as PORT, got %v", cmd.Env[len(cmd.Env)-1])
+	}
+	if b.healthURL() != "http://127.0.0.1:9090/health" {
+		t.Errorf("Unexpected health URL %s", b.healthURL())
+	}
+
+	bin := filepath.Join(t.TempDir(), "console-custom")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(BackendBinaryEnv, bin)
+	t.Setenv(BackendPortEnv, "not-a-port")
+	b = b.withEnv()
+	if b.Binary != bin || b.port() != 9090 {
+		t.Errorf("Expected env binary and the configured port, got %+v", b)
+	}
+	if (AgentFileBackend{}).port() != defaultBackendPort {
+		t.Error("Expected the default backend port")
+	}
+}
+
+func TestPortProbing(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	if !portListening(port) {
+		t.Fatal("Expected the port to be listening")
+	}
+	if waitPortReleased(port, 2*portReleasePoll) {
+		t.Error("Expected the port to still be held")
+	}
+
+	pids, err := listeningPIDs(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, pid := range pids {
+		found = found || pid == os.Getpid()
+	}
+	if !found {
+		t.Errorf("Expected the test process among the listeners, got %v", pids)
+	}
+
+	ln.Close()
+	if !waitPortReleased(port, portReleaseTimeout) {
+		t.Error("Expected the port to be released")
+	}
+}
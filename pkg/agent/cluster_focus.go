@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// ClusterFocusRequest tells the agent which clusters the UI currently shows
+type ClusterFocusRequest struct {
+	Clusters []string `json:"clusters"`
+}
+
+// ClusterFocusResponse is the current focus hint and how long it lasts
+type ClusterFocusResponse struct {
+	Clusters   []string `json:"clusters"`
+	TTLSeconds int      `json:"ttlSeconds"`
+}
+
+// handleClusterFocus reads or replaces the focus hint. Focused clusters are
+// probed first and their health kept fresh; the others are polled less often
+// until the hint expires, so the UI re-sends it while clusters stay visible.
+func (s *Server) handleClusterFocus(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.k8sClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+	case "POST", "PUT":
+		var req ClusterFocusRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		s.k8sClient.SetFocusedClusters(req.Clusters)
+		go s.probeFocusedClusters()
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	focused := s.k8sClient.FocusedClusters()
+	if focused == nil {
+		focused = []string{}
+	}
+	json.NewEncoder(w).Encode(ClusterFocusResponse{Clusters: focused, TTLSeconds: int(k8s.ProbeFocusTTL.Seconds())})
+}
+
+// probeFocusedClusters refreshes the health of newly focused clusters whose
+// cached health is stale, so the UI finds it warm on its next poll
+func (s *Server) probeFocusedClusters() {
+	ctx, cancel := context.WithTimeout(context.Background(), agentDefaultTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, name := range s.k8sClient.FocusedClusters() {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			s.k8sClient.GetClusterHealth(ctx, name)
+		}(name)
+	}
+	wg.Wait()
+}
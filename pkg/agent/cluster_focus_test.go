@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+func TestHandleClusterFocus(t *testing.T) {
+	k8sClient, _ := k8s.NewMultiClusterClient(filepath.Join(t.TempDir(), "config"))
+	s := &Server{k8sClient: k8sClient}
+
+	rec := httptest.NewRecorder()
+	s.handleClusterFocus(rec, httptest.NewRequest("POST", "/clusters/focus", strings.NewReader(`{"clusters":["prod","dev"]}`)))
+	var resp ClusterFocusResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if rec.Code != http.StatusOK || len(resp.Clusters) != 2 || resp.Clusters[0] != "dev" || resp.TTLSeconds == 0 {
+		t.Fatalf("Expected the focus hint to be stored, got %d %+v", rec.Code, resp)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleClusterFocus(rec, httptest.NewRequest("PUT", "/clusters/focus", strings.NewReader(`{"clusters":[]}`)))
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if len(resp.Clusters) != 0 || len(k8sClient.FocusedClusters()) != 0 {
+		t.Errorf("Expected an empty list to clear the hint, got %+v", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleClusterFocus(rec, httptest.NewRequest("DELETE", "/clusters/focus", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,232 @@
+package agent
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+// ClusterFiltersRequest replaces the cluster filters and groups stored in settings
+type ClusterFiltersRequest struct {
+	Filters settings.ClusterFilters `json:"filters"`
+	Groups  []settings.ClusterGroup `json:"groups"`
+}
+
+// ClusterGroupStatus is a group with the clusters it currently matches
+type ClusterGroupStatus struct {
+	settings.ClusterGroup
+	Members []string `json:"members"`
+}
+
+// SettingsClusterPolicy reads the cluster filters and groups from persisted settings.
+// The console backend installs it too so both processes list the same clusters.
+func SettingsClusterPolicy() k8s.ClusterPolicy {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil || all == nil {
+		return k8s.ClusterPolicy{}
+	}
+	policy := clusterPolicyFromSettings(all.ClusterFilters, all.ClusterGroups)
+	policy.Preferences = clusterPreferencesFromSettings(all.ClusterPreferences)
+	policy.Timeouts = clusterTimeoutsFromSettings(all.ClusterTimeouts)
+	return policy
+}
+
+func clusterPolicyFromSettings(filters *settings.ClusterFilters, groups []settings.ClusterGroup) k8s.ClusterPolicy {
+	var policy k8s.ClusterPolicy
+	if filters != nil {
+		policy.Include = filters.Include
+		policy.Exclude = filters.Exclude
+	}
+	for _, g := range groups {
+		policy.Groups = append(policy.Groups, k8s.ClusterGroup{Name: g.Name, Patterns: g.Clusters})
+	}
+	return policy
+}
+
+// saveSettingsClusterFilters replaces the cluster filters and groups in the persisted settings
+func saveSettingsClusterFilters(filters settings.ClusterFilters, groups []settings.ClusterGroup) error {
+	mgr := settings.GetSettingsManager()
+	all, err := mgr.GetAll()
+	if err != nil {
+		return err
+	}
+	if groups == nil {
+		groups = []settings.ClusterGroup{}
+	}
+	all.ClusterFilters = &filters
+	all.ClusterGroups = groups
+	return mgr.SaveAll(all)
+}
+
+// validateClusterPatterns rejects filters or groups with invalid regular expressions
+func validateClusterPatterns(req ClusterFiltersRequest) string {
+	if _, err := k8s.NewClusterFilter(req.Filters.Include, req.Filters.Exclude); err != nil {
+		return err.Error()
+	}
+	seen := map[string]bool{}
+	for _, g := range req.Groups {
+		if g.Name == "" {
+			return "group name is required"
+		}
+		if seen[g.Name] {
+			return "duplicate group " + g.Name
+		}
+		seen[g.Name] = true
+		for _, p := range g.Clusters {
+			if _, err := regexp.Compile("^(?:" + p + ")$"); err != nil {
+				return "invalid pattern in group " + g.Name + ": " + err.Error()
+			}
+		}
+	}
+	return ""
+}
+
+// listContexts lists kubeconfig contexts, honoring cluster filters and setting groups
+func (s *Server) listContexts() ([]protocol.ClusterInfo, string) {
+	clusters, current := s.kubectl.ListContexts()
+	if s.k8sClient == nil {
+		return clusters, current
+	}
+	names := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	visibility := s.k8sClient.ClusterVisibility(names)
+
+	visible := clusters[:0]
+	for _, c := range clusters {
+		info, ok := visibility[c.Name]
+		if !ok {
+			continue
+		}
+		c.Groups = info.Groups
+		c.DisplayName = info.DisplayName
+		c.Color = info.Color
+		c.Pinned = info.Pinned
+		visible = append(visible, c)
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return visible[i].Pinned && !visible[j].Pinned
+	})
+	return visible, current
+}
+
+// clustersInGroup returns the clusters tagged with group
+func clustersInGroup(clusters []protocol.ClusterInfo, group string) []protocol.ClusterInfo {
+	out := []protocol.ClusterInfo{}
+	for _, c := range clusters {
+		for _, g := range c.Groups {
+			if g == group {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// k8sClustersInGroup returns the clusters tagged with group
+func k8sClustersInGroup(clusters []k8s.ClusterInfo, group string) []k8s.ClusterInfo {
+	out := []k8s.ClusterInfo{}
+	for _, c := range clusters {
+		for _, g := range c.Groups {
+			if g == group {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// handleClusterFilters gets or replaces the cluster include/exclude filters and groups
+func (s *Server) handleClusterFilters(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		all, err := settings.GetSettingsManager().GetAll()
+		if err != nil {
+			log.Printf("[ClusterFilters] failed to read settings: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to read settings"})
+			return
+		}
+		filters := settings.ClusterFilters{}
+		if all.ClusterFilters != nil {
+			filters = *all.ClusterFilters
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"filters": filters,
+			"groups":  s.clusterGroupStatus(all.ClusterGroups),
+		})
+
+	case "PUT":
+		var req ClusterFiltersRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		if msg := validateClusterPatterns(req); msg != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": msg})
+			return
+		}
+		if err := saveSettingsClusterFilters(req.Filters, req.Groups); err != nil {
+			log.Printf("[ClusterFilters] failed to save settings: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to save cluster filters"})
+			return
+		}
+		log.Printf("[ClusterFilters] Updated filters (include=%v exclude=%v) and %d group(s)",
+			req.Filters.Include, req.Filters.Exclude, len(req.Groups))
+
+		clusters, current := s.listContexts()
+		s.BroadcastToClients("clusters_updated", protocol.ClustersPayload{Clusters: clusters, Current: current})
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"filters": req.Filters,
+			"groups":  s.clusterGroupStatus(req.Groups),
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// clusterGroupStatus resolves each group's members among the visible clusters
+func (s *Server) clusterGroupStatus(groups []settings.ClusterGroup) []ClusterGroupStatus {
+	clusters, _ := s.listContexts()
+	out := make([]ClusterGroupStatus, 0, len(groups))
+	for _, g := range groups {
+		status := ClusterGroupStatus{ClusterGroup: g, Members: []string{}}
+		for _, c := range clusters {
+			for _, name := range c.Groups {
+				if name == g.Name {
+					status.Members = append(status.Members, c.Name)
+					break
+				}
+			}
+		}
+		out = append(out, status)
+	}
+	return out
+}
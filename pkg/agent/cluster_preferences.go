@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+// maxDisplayNameLength bounds user-assigned cluster display names
+const maxDisplayNameLength = 64
+
+var clusterColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ClusterPreferenceRequest sets the display name, color and pin of one cluster.
+// Clearing every field removes the cluster's preferences.
+type ClusterPreferenceRequest struct {
+	Cluster string `json:"cluster"`
+	settings.ClusterPreference
+}
+
+func clusterPreferencesFromSettings(prefs map[string]settings.ClusterPreference) map[string]k8s.ClusterPreference {
+	if len(prefs) == 0 {
+		return nil
+	}
+	out := make(map[string]k8s.ClusterPreference, len(prefs))
+	for name, p := range prefs {
+		out[name] = k8s.ClusterPreference{DisplayName: p.DisplayName, Color: p.Color, Pinned: p.Pinned}
+	}
+	return out
+}
+
+// validateClusterPreference returns an error message for an invalid request, or ""
+func validateClusterPreference(req ClusterPreferenceRequest) string {
+	if req.Cluster == "" {
+		return "cluster is required"
+	}
+	if len(req.DisplayName) > maxDisplayNameLength {
+		return "displayName is too long"
+	}
+	if req.Color != "" && !clusterColorPattern.MatchString(req.Color) {
+		return "color must be #rgb or #rrggbb"
+	}
+	return ""
+}
+
+// saveSettingsClusterPreference stores or clears one cluster's preferences
+func saveSettingsClusterPreference(cluster string, pref settings.ClusterPreference) (map[string]settings.ClusterPreference, error) {
+	mgr := settings.GetSettingsManager()
+	all, err := mgr.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(map[string]settings.ClusterPreference, len(all.ClusterPreferences)+1)
+	for name, p := range all.ClusterPreferences {
+		prefs[name] = p
+	}
+	if pref == (settings.ClusterPreference{}) {
+		delete(prefs, cluster)
+	} else {
+		prefs[cluster] = pref
+	}
+	all.ClusterPreferences = prefs
+	return prefs, mgr.SaveAll(all)
+}
+
+// handleClusterPreferences lists or updates per-cluster display names, colors and pins
+func (s *Server) handleClusterPreferences(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		all, err := settings.GetSettingsManager().GetAll()
+		if err != nil {
+			log.Printf("[ClusterPreferences] failed to read settings: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to read settings"})
+			return
+		}
+		prefs := all.ClusterPreferences
+		if prefs == nil {
+			prefs = map[string]settings.ClusterPreference{}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"preferences": prefs})
+
+	case "PUT":
+		var req ClusterPreferenceRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		if msg := validateClusterPreference(req); msg != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": msg})
+			return
+		}
+		prefs, err := saveSettingsClusterPreference(req.Cluster, req.ClusterPreference)
+		if err != nil {
+			log.Printf("[ClusterPreferences] failed to save settings: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to save cluster preferences"})
+			return
+		}
+
+		clusters, current := s.listContexts()
+		s.BroadcastToClients("clusters_updated", protocol.ClustersPayload{Clusters: clusters, Current: current})
+		json.NewEncoder(w).Encode(map[string]interface{}{"preferences": prefs})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
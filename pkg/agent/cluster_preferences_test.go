@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+func TestValidateClusterPreference(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ClusterPreferenceRequest
+		wantErr bool
+	}{
+		{"valid", ClusterPreferenceRequest{Cluster: "prod", ClusterPreference: settings.ClusterPreference{DisplayName: "Prod", Color: "#1a2b3c", Pinned: true}}, false},
+		{"short color", ClusterPreferenceRequest{Cluster: "prod", ClusterPreference: settings.ClusterPreference{Color: "#abc"}}, false},
+		{"clear", ClusterPreferenceRequest{Cluster: "prod"}, false},
+		{"missing cluster", ClusterPreferenceRequest{ClusterPreference: settings.ClusterPreference{Pinned: true}}, true},
+		{"named color", ClusterPreferenceRequest{Cluster: "prod", ClusterPreference: settings.ClusterPreference{Color: "red"}}, true},
+		{"css injection", ClusterPreferenceRequest{Cluster: "prod", ClusterPreference: settings.ClusterPreference{Color: "#fff;background:url(x)"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if msg := validateClusterPreference(tt.req); (msg != "") != tt.wantErr {
+				t.Errorf("validateClusterPreference() = %q, wantErr %v", msg, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,641 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/audit"
+)
+
+const (
+	commandApprovalTimeout = 2 * time.Minute
+	commandAuditFile       = "command_audit.jsonl"
+	maxCommandAuditEntries = 500
+	commandAuditFileMode   = 0600
+	commandAuditDirMode    = 0700
+)
+
+// CommandRisk classifies how dangerous an AI-proposed command is
+type CommandRisk string
+
+const (
+	// CommandRiskSafe commands are read-only and run without review
+	CommandRiskSafe CommandRisk = "safe"
+	// CommandRiskMutating commands change cluster state and need approval
+	CommandRiskMutating CommandRisk = "mutating"
+	// CommandRiskDestructive commands can cause data loss or outages and need explicit confirmation
+	CommandRiskDestructive CommandRisk = "destructive"
+)
+
+// Command decisions recorded in the audit log
+const (
+	CommandDecisionAutoApproved = "auto_approved"
+	CommandDecisionApproved     = "approved"
+	CommandDecisionRejected     = "rejected"
+	CommandDecisionTimedOut     = "timed_out"
+	CommandDecisionCancelled    = "cancelled"
+)
+
+// Read-only verbs that are allowed to run without user review
+var safeKubectlVerbs = map[string]bool{
+	"get":           true,
+	"describe":      true,
+	"logs":          true,
+	"top":           true,
+	"explain":       true,
+	"api-resources": true,
+	"api-versions":  true,
+	"version":       true,
+	"cluster-info":  true,
+	"events":        true,
+	"diff":          true,
+}
+
+// Read-only kubectl sub-commands keyed by verb (e.g. "auth can-i", "config view")
+var safeKubectlSubcommands = map[string]map[string]bool{
+	"auth":    {"can-i": true, "whoami": true},
+	"config":  {"view": true, "get-contexts": true, "current-context": true, "get-clusters": true},
+	"rollout": {"status": true, "history": true},
+}
+
+var safeHelmVerbs = map[string]bool{
+	"list":     true,
+	"ls":       true,
+	"status":   true,
+	"get":      true,
+	"history":  true,
+	"show":     true,
+	"search":   true,
+	"template": true,
+	"version":  true,
+	"env":      true,
+}
+
+// kubectl flags that consume the following argument as their value
+var kubectlValueFlags = map[string]bool{
+	"-n": true, "--namespace": true,
+	"--context": true, "--kubeconfig": true, "--cluster": true, "--user": true,
+	"-l": true, "--selector": true, "-o": true, "--output": true,
+	"-c": true, "--container": true, "-f": true, "--filename": true,
+}
+
+// ClassifiedCommand is a proposed command with its risk assessment
+type ClassifiedCommand struct {
+	Command string      `json:"command"`
+	Risk    CommandRisk `json:"risk"`
+	Reason  string      `json:"reason"`
+}
+
+// CommandApprovalRequest is sent to the client when commands need review
+type CommandApprovalRequest struct {
+	RequestID string              `json:"requestId"`
+	SessionID string              `json:"sessionId"`
+	Agent     string              `json:"agent"`
+	Commands  []ClassifiedCommand `json:"commands"`
+	ExpiresAt string              `json:"expiresAt"`
+}
+
+// CommandApprovalResponse is the client's decision on an approval request.
+// Destructive commands only run when ConfirmDestructive is also set.
+type CommandApprovalResponse struct {
+	RequestID          string `json:"requestId"`
+	Approved           bool   `json:"approved"`
+	ConfirmDestructive bool   `json:"confirmDestructive,omitempty"`
+}
+
+// CommandAuditEntry records the outcome of a single AI-proposed command
+type CommandAuditEntry struct {
+	Timestamp string      `json:"timestamp"`
+	RequestID string      `json:"requestId,omitempty"`
+	SessionID string      `json:"sessionId,omitempty"`
+	Agent     string      `json:"agent,omitempty"`
+	Command   string      `json:"command"`
+	Risk      CommandRisk `json:"risk"`
+	Decision  string      `json:"decision"`
+}
+
+// CommandApprovalManager gates AI-executed commands behind user approval
+// and keeps an audit trail of every decision
+type CommandApprovalManager struct {
+	mu      sync.Mutex
+	pending map[string]chan CommandApprovalResponse
+	audit   []CommandAuditEntry
+	dataDir string
+	timeout time.Duration
+	// forwarder sends entries to an external collector when configured
+	forwarder *audit.Forwarder
+}
+
+// NewCommandApprovalManager creates a manager that persists its audit log to dataDir
+func NewCommandApprovalManager(dataDir string) *CommandApprovalManager {
+	if dataDir == "" {
+		homeDir, _ := os.UserHomeDir()
+		dataDir = filepath.Join(homeDir, ".kc")
+	}
+	return &CommandApprovalManager{
+		pending: make(map[string]chan CommandApprovalResponse),
+		audit:   []CommandAuditEntry{},
+		dataDir: dataDir,
+		timeout: commandApprovalTimeout,
+	}
+}
+
+// ClassifyCommand determines the risk of a shell command proposed by an AI agent.
+// Only well-known read-only kubectl/helm verbs are considered safe.
+func ClassifyCommand(command string) ClassifiedCommand {
+	cc := ClassifiedCommand{Command: command}
+	trimmed := strings.TrimSpace(command)
+	if trimmed == "" {
+		cc.Risk = CommandRiskMutating
+		cc.Reason = "empty command"
+		return cc
+	}
+
+	// Shell composition can hide arbitrary commands behind a safe-looking prefix;
+	// a line break or a lone & starts a second command just like ; does
+	for _, meta := range []string{"|", ";", "&", ">", "<", "`", "$(", "\n", "\r"} {
+		if strings.Contains(trimmed, meta) {
+			cc.Risk = CommandRiskMutating
+			cc.Reason = fmt.Sprintf("contains shell operator %q", meta)
+			return cc
+		}
+	}
+
+	fields := strings.Fields(trimmed)
+	tool := filepath.Base(fields[0])
+	args := positionalArgs(fields[1:])
+
+	switch tool {
+	case "kubectl", "oc", "k":
+		return classifyKubectl(cc, fields[1:], args)
+	case "helm":
+		if len(args) == 0 {
+			cc.Risk = CommandRiskSafe
+			cc.Reason = "helm without a verb"
+			return cc
+		}
+		verb := args[0]
+		switch {
+		case hasHelmPostRenderer(fields[1:]):
+			// --post-renderer runs an arbitrary binary, even for helm template
+			cc.Risk = CommandRiskMutating
+			cc.Reason = "helm --post-renderer runs an external program"
+		case safeHelmVerbs[verb]:
+			cc.Risk = CommandRiskSafe
+			cc.Reason = "read-only helm verb " + verb
+		case verb == "repo" && len(args) > 1 && args[1] == "list":
+			cc.Risk = CommandRiskSafe
+			cc.Reason = "read-only helm verb repo list"
+		case verb == "uninstall" || verb == "delete" || verb == "rollback":
+			cc.Risk = CommandRiskDestructive
+			cc.Reason = "helm " + verb + " removes or reverts a release"
+		default:
+			cc.Risk = CommandRiskMutating
+			cc.Reason = "helm " + verb + " modifies cluster state"
+		}
+		return cc
+	default:
+		cc.Risk = CommandRiskMutating
+		cc.Reason = "unrecognized tool " + tool
+		return cc
+	}
+}
+
+func classifyKubectl(cc ClassifiedCommand, rawArgs, args []string) ClassifiedCommand {
+	if len(args) == 0 {
+		cc.Risk = CommandRiskSafe
+		cc.Reason = "kubectl without a verb"
+		return cc
+	}
+	verb := args[0]
+
+	// A kubeconfig can name an exec credential plugin, so pointing kubectl at
+	// an arbitrary file runs an arbitrary binary even for a read-only verb
+	if flagPresent(rawArgs, "--kubeconfig") {
+		cc.Risk = CommandRiskMutating
+		cc.Reason = "kubectl --kubeconfig can run an exec credential plugin"
+		return cc
+	}
+	// An unlisted flag written as "--flag value" may swallow the next word,
+	// so the word read as the verb may not be the one kubectl runs
+	if flag := unknownFlagBefore(rawArgs, 1); flag != "" {
+		cc.Risk = CommandRiskMutating
+		cc.Reason = "unrecognized flag " + flag + " before the verb"
+		return cc
+	}
+
+	if safeKubectlVerbs[verb] {
+		cc.Risk = CommandRiskSafe
+		cc.Reason = "read-only verb " + verb
+		return cc
+	}
+	if subs, ok := safeKubectlSubcommands[verb]; ok && len(args) > 1 && subs[args[1]] {
+		if flag := unknownFlagBefore(rawArgs, 2); flag != "" {
+			cc.Risk = CommandRiskMutating
+			cc.Reason = "unrecognized flag " + flag + " before the sub-command"
+			return cc
+		}
+		if verb == "config" && flagPresent(rawArgs, "--raw") {
+			// config view --raw prints client keys and tokens
+			cc.Risk = CommandRiskMutating
+			cc.Reason = "kubectl config view --raw exposes credentials"
+			return cc
+		}
+		cc.Risk = CommandRiskSafe
+		cc.Reason = "read-only verb " + verb + " " + args[1]
+		return cc
+	}
+
+	switch verb {
+	case "delete", "drain", "replace":
+		cc.Risk = CommandRiskDestructive
+		cc.Reason = "kubectl " + verb + " can remove workloads"
+		return cc
+	case "scale":
+		if scaleReplicas(rawArgs) == "0" {
+			cc.Risk = CommandRiskDestructive
+			cc.Reason = "scaling to 0 replicas stops the workload"
+			return cc
+		}
+	}
+
+	cc.Risk = CommandRiskMutating
+	cc.Reason = "kubectl " + verb + " modifies cluster state"
+	return cc
+}
+
+func hasHelmPostRenderer(args []string) bool {
+	for _, a := range args {
+		if a == "--post-renderer" || strings.HasPrefix(a, "--post-renderer=") || strings.HasPrefix(a, "--post-renderer-args") {
+			return true
+		}
+	}
+	return false
+}
+
+// positionalArgs strips flags (and the values of flags that take one) from args
+func positionalArgs(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if strings.HasPrefix(a, "-") {
+			if !strings.Contains(a, "=") && kubectlValueFlags[a] {
+				i++
+			}
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// unknownFlagBefore returns the first flag outside kubectlValueFlags written
+// without "=" that appears before the n-th positional argument, or "" if none
+func unknownFlagBefore(args []string, n int) string {
+	seen := 0
+	for i := 0; i < len(args) && seen < n; i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") {
+			seen++
+			continue
+		}
+		if strings.Contains(a, "=") {
+			continue
+		}
+		if kubectlValueFlags[a] {
+			i++
+			continue
+		}
+		return a
+	}
+	return ""
+}
+
+// flagPresent reports whether flag appears in args, alone or as flag=value
+func flagPresent(args []string, flag string) bool {
+	for _, a := range args {
+		if a == flag || strings.HasPrefix(a, flag+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// scaleReplicas returns the value of --replicas in a kubectl scale command
+func scaleReplicas(args []string) string {
+	for i, a := range args {
+		if strings.HasPrefix(a, "--replicas=") {
+			return strings.TrimPrefix(a, "--replicas=")
+		}
+		if a == "--replicas" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// RequestApproval blocks until the client responds, the request times out,
+// or ctx is cancelled. The send callback delivers the request to the client.
+func (m *CommandApprovalManager) RequestApproval(ctx context.Context, req CommandApprovalRequest, send func(CommandApprovalRequest)) (CommandApprovalResponse, string) {
+	ch := make(chan CommandApprovalResponse, 1)
+
+	m.mu.Lock()
+	if req.RequestID == "" {
+		req.RequestID = uuid.New().String()
+	}
+	timeout := m.timeout
+	m.pending[req.RequestID] = ch
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		delete(m.pending, req.RequestID)
+		m.mu.Unlock()
+	}()
+
+	req.ExpiresAt = time.Now().Add(timeout).UTC().Format(time.RFC3339)
+	send(req)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-ch:
+		return resp, ""
+	case <-timer.C:
+		return CommandApprovalResponse{RequestID: req.RequestID}, CommandDecisionTimedOut
+	case <-ctx.Done():
+		return CommandApprovalResponse{RequestID: req.RequestID}, CommandDecisionCancelled
+	}
+}
+
+// Resolve delivers a client decision to the waiting approval request.
+// Returns false if no request with that ID is pending.
+func (m *CommandApprovalManager) Resolve(resp CommandApprovalResponse) bool {
+	m.mu.Lock()
+	ch, ok := m.pending[resp.RequestID]
+	if ok {
+		delete(m.pending, resp.RequestID)
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	ch <- resp
+	return true
+}
+
+// PendingCount returns the number of approval requests awaiting a decision
+func (m *CommandApprovalManager) PendingCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
+}
+
+// SetForwarder forwards every recorded entry to an external collector
+func (m *CommandApprovalManager) SetForwarder(f *audit.Forwarder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forwarder = f
+}
+
+// Record appends an entry to the audit trail and persists it
+func (m *CommandApprovalManager) Record(entry CommandAuditEntry) {
+	if entry.Timestamp == "" {
+		entry.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	log.Printf("[CommandAudit] session=%s risk=%s decision=%s command=%q", entry.SessionID, entry.Risk, entry.Decision, entry.Command)
+
+	m.mu.Lock()
+	m.audit = append(m.audit, entry)
+	if len(m.audit) > maxCommandAuditEntries {
+		m.audit = m.audit[len(m.audit)-maxCommandAuditEntries:]
+	}
+	dataDir := m.dataDir
+	forwarder := m.forwarder
+	m.mu.Unlock()
+
+	outcome := audit.OutcomeSuccess
+	if entry.Decision != CommandDecisionAutoApproved && entry.Decision != CommandDecisionApproved {
+		outcome = audit.OutcomeFailure
+	}
+	forwarder.Record(audit.Entry{
+		Category: audit.CategoryCommand,
+		Action:   "command_" + entry.Decision,
+		Outcome:  outcome,
+		Target:   entry.Command,
+		Details: map[string]string{
+			"risk":      string(entry.Risk),
+			"sessionId": entry.SessionID,
+			"requestId": entry.RequestID,
+			"agent":     entry.Agent,
+		},
+	})
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("[CommandAudit] Error marshaling entry: %v", err)
+		return
+	}
+	if err := os.MkdirAll(dataDir, commandAuditDirMode); err != nil {
+		log.Printf("[CommandAudit] Error creating data dir: %v", err)
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(dataDir, commandAuditFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, commandAuditFileMode)
+	if err != nil {
+		log.Printf("[CommandAudit] Error opening audit file: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		log.Printf("[CommandAudit] Error writing audit file: %v", err)
+	}
+}
+
+// GetAudit returns the most recent audit entries, newest last
+func (m *CommandApprovalManager) GetAudit(limit int) []CommandAuditEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	start := 0
+	if limit > 0 && len(m.audit) > limit {
+		start = len(m.audit) - limit
+	}
+	out := make([]CommandAuditEntry, len(m.audit)-start)
+	copy(out, m.audit[start:])
+	return out
+}
+
+// filterApprovedCommands applies a client decision to classified commands.
+// Safe commands always run; mutating ones need approval; destructive ones
+// additionally need ConfirmDestructive. Every command is audited.
+func (m *CommandApprovalManager) filterApprovedCommands(classified []ClassifiedCommand, resp CommandApprovalResponse, failure string, base CommandAuditEntry) (allowed []string, rejected []ClassifiedCommand) {
+	for _, cc := range classified {
+		entry := base
+		entry.Command = cc.Command
+		entry.Risk = cc.Risk
+
+		switch {
+		case cc.Risk == CommandRiskSafe:
+			entry.Decision = CommandDecisionAutoApproved
+		case failure != "":
+			entry.Decision = failure
+		case !resp.Approved:
+			entry.Decision = CommandDecisionRejected
+		case cc.Risk == CommandRiskDestructive && !resp.ConfirmDestructive:
+			entry.Decision = CommandDecisionRejected
+		default:
+			entry.Decision = CommandDecisionApproved
+		}
+
+		m.Record(entry)
+		if entry.Decision == CommandDecisionAutoApproved || entry.Decision == CommandDecisionApproved {
+			allowed = append(allowed, cc.Command)
+		} else {
+			rejected = append(rejected, cc)
+		}
+	}
+	return allowed, rejected
+}
+
+// gateCommands classifies proposed commands, asks the client to approve any
+// that are not read-only, and returns the commands cleared to run
+func (s *Server) gateCommands(ctx context.Context, requestID, sessionID, agentName string, commands []string, send func(CommandApprovalRequest)) (allowed []string, rejected []ClassifiedCommand) {
+	classified := make([]ClassifiedCommand, 0, len(commands))
+	needsReview := false
+	for _, c := range commands {
+		cc := ClassifyCommand(c)
+		if cc.Risk == CommandRiskDestructive {
+			s.annotateDrain(ctx, &cc)
+		}
+		classified = append(classified, cc)
+		if cc.Risk != CommandRiskSafe {
+			needsReview = true
+		}
+	}
+
+	base := CommandAuditEntry{SessionID: sessionID, Agent: agentName}
+	if !needsReview {
+		return s.commandApprovals.filterApprovedCommands(classified, CommandApprovalResponse{}, "", base)
+	}
+
+	resp, failure := s.commandApprovals.RequestApproval(ctx, CommandApprovalRequest{
+		RequestID: requestID,
+		SessionID: sessionID,
+		Agent:     agentName,
+		Commands:  classified,
+	}, send)
+	base.RequestID = resp.RequestID
+	return s.commandApprovals.filterApprovedCommands(classified, resp, failure, base)
+}
+
+// handleCommandApprovalMessage resolves a pending approval request from a WebSocket client
+func (s *Server) handleCommandApprovalMessage(msg protocol.Message) protocol.Message {
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return s.errorResponse(msg.ID, "invalid_payload", "Failed to parse command approval")
+	}
+	var resp CommandApprovalResponse
+	if err := json.Unmarshal(payloadBytes, &resp); err != nil || resp.RequestID == "" {
+		return s.errorResponse(msg.ID, "invalid_payload", "requestId is required")
+	}
+	if s.commandApprovals == nil || !s.commandApprovals.Resolve(resp) {
+		return s.errorResponse(msg.ID, "not_found", "No pending approval request with that ID")
+	}
+	return protocol.Message{
+		ID:   msg.ID,
+		Type: protocol.TypeResult,
+		Payload: map[string]interface{}{
+			"requestId": resp.RequestID,
+			"resolved":  true,
+		},
+	}
+}
+
+// handleCommandApprovalHTTP is the HTTP fallback for answering approval requests
+func (s *Server) handleCommandApprovalHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if s.isAllowedOrigin(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	w.Header().Set("Access-Control-Allow-Private-Network", "true")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.commandApprovals == nil {
+		http.Error(w, "Command approvals not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var resp CommandApprovalResponse
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&resp); err != nil || resp.RequestID == "" {
+		http.Error(w, `{"error":"requestId is required"}`, http.StatusBadRequest)
+		return
+	}
+
+	resolved := s.commandApprovals.Resolve(resp)
+	if !resolved {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"requestId": resp.RequestID,
+		"resolved":  resolved,
+	})
+}
+
+// handleCommandAudit returns the audit trail of AI-proposed commands
+func (s *Server) handleCommandAudit(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.commandApprovals == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"entries": []CommandAuditEntry{}})
+		return
+	}
+
+	limit := 100
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if n, err := strconv.Atoi(l); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if limit > maxQueryLimit {
+		limit = maxQueryLimit
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entries": s.commandApprovals.GetAudit(limit),
+		"pending": s.commandApprovals.PendingCount(),
+	})
+}
@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClassifyCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    CommandRisk
+	}{
+		{"kubectl get pods -A", CommandRiskSafe},
+		{"kubectl -n kube-system describe pod coredns", CommandRiskSafe},
+		{"kubectl --context prod logs my-pod -c app", CommandRiskSafe},
+		{"kubectl auth can-i list pods", CommandRiskSafe},
+		{"kubectl rollout status deployment/web", CommandRiskSafe},
+		{"helm list -A", CommandRiskSafe},
+		{"kubectl apply -f deploy.yaml", CommandRiskMutating},
+		{"kubectl rollout restart deployment/web", CommandRiskMutating},
+		{"kubectl scale deployment web --replicas=3", CommandRiskMutating},
+		{"kubectl cordon node-1", CommandRiskMutating},
+		{"helm upgrade web ./chart", CommandRiskMutating},
+		{"kubectl delete pod my-pod", CommandRiskDestructive},
+		{"kubectl -n delete get pods", CommandRiskSafe},
+		{"kubectl drain node-1 --ignore-daemonsets", CommandRiskDestructive},
+		{"kubectl scale deployment web --replicas=0", CommandRiskDestructive},
+		{"kubectl scale deployment web --replicas 0", CommandRiskDestructive},
+		{"helm uninstall web", CommandRiskDestructive},
+		{"kubectl get pods | xargs kubectl delete pod", CommandRiskMutating},
+		{"kubectl get pods; rm -rf /", CommandRiskMutating},
+		{"kubectl get pods & rm -rf ~", CommandRiskMutating},
+		{"kubectl get pods&rm -rf ~", CommandRiskMutating},
+		{"kubectl get pods && rm -rf ~", CommandRiskMutating},
+		{"kubectl get pods\nrm -rf ~", CommandRiskMutating},
+		{"kubectl get pods\rrm -rf ~", CommandRiskMutating},
+		{"helm template web ./chart", CommandRiskSafe},
+		{"helm template web ./chart --post-renderer ./evil.sh", CommandRiskMutating},
+		{"helm template web ./chart --post-renderer=./evil.sh", CommandRiskMutating},
+		{"kubectl --cache-dir get delete ns prod", CommandRiskMutating},
+		{"kubectl --as get delete ns prod", CommandRiskMutating},
+		{"kubectl -s get delete ns prod", CommandRiskMutating},
+		{"kubectl --request-timeout get delete ns prod", CommandRiskMutating},
+		{"kubectl --request-timeout=5s get pods", CommandRiskSafe},
+		{"kubectl get -A pods", CommandRiskSafe},
+		{"kubectl auth --token can-i rollout undo", CommandRiskMutating},
+		{"kubectl config view", CommandRiskSafe},
+		{"kubectl config view --raw", CommandRiskMutating},
+		{"kubectl config view --raw=true", CommandRiskMutating},
+		{"kubectl --kubeconfig /tmp/evil get pods", CommandRiskMutating},
+		{"kubectl get pods --kubeconfig=/tmp/evil", CommandRiskMutating},
+		{"rm -rf /tmp/foo", CommandRiskMutating},
+		{"", CommandRiskMutating},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := ClassifyCommand(tt.command)
+			if got.Risk != tt.want {
+				t.Errorf("ClassifyCommand(%q) = %s (%s), want %s", tt.command, got.Risk, got.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestGateCommands_SafeCommandsSkipApproval(t *testing.T) {
+	s := &Server{commandApprovals: NewCommandApprovalManager(t.TempDir())}
+
+	sent := false
+	allowed, rejected := s.gateCommands(context.Background(), "", "sess-1", "claude-code",
+		[]string{"kubectl get pods", "kubectl top nodes"},
+		func(CommandApprovalRequest) { sent = true })
+
+	if sent {
+		t.Error("Expected no approval request for read-only commands")
+	}
+	if len(allowed) != 2 || len(rejected) != 0 {
+		t.Errorf("Expected 2 allowed, 0 rejected; got %d allowed, %d rejected", len(allowed), len(rejected))
+	}
+
+	audit := s.commandApprovals.GetAudit(0)
+	if len(audit) != 2 || audit[0].Decision != CommandDecisionAutoApproved {
+		t.Errorf("Expected 2 auto_approved audit entries, got %+v", audit)
+	}
+}
+
+func TestGateCommands_ApprovalFlow(t *testing.T) {
+	commands := []string{"kubectl get pods", "kubectl apply -f x.yaml", "kubectl delete ns prod"}
+
+	tests := []struct {
+		name        string
+		resp        CommandApprovalResponse
+		wantAllowed []string
+	}{
+		{
+			name:        "rejected",
+			resp:        CommandApprovalResponse{Approved: false},
+			wantAllowed: []string{"kubectl get pods"},
+		},
+		{
+			name:        "approved without destructive confirmation",
+			resp:        CommandApprovalResponse{Approved: true},
+			wantAllowed: []string{"kubectl get pods", "kubectl apply -f x.yaml"},
+		},
+		{
+			name:        "approved with destructive confirmation",
+			resp:        CommandApprovalResponse{Approved: true, ConfirmDestructive: true},
+			wantAllowed: commands,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{commandApprovals: NewCommandApprovalManager(t.TempDir())}
+
+			allowed, _ := s.gateCommands(context.Background(), "req-1", "sess-1", "claude-code", commands,
+				func(req CommandApprovalRequest) {
+					if len(req.Commands) != len(commands) {
+						t.Errorf("Expected %d commands in approval request, got %d", len(commands), len(req.Commands))
+					}
+					resp := tt.resp
+					resp.RequestID = req.RequestID
+					go s.commandApprovals.Resolve(resp)
+				})
+
+			if len(allowed) != len(tt.wantAllowed) {
+				t.Fatalf("Expected allowed %v, got %v", tt.wantAllowed, allowed)
+			}
+			for i := range allowed {
+				if allowed[i] != tt.wantAllowed[i] {
+					t.Errorf("allowed[%d] = %q, want %q", i, allowed[i], tt.wantAllowed[i])
+				}
+			}
+			if s.commandApprovals.PendingCount() != 0 {
+				t.Error("Expected no pending approvals after resolution")
+			}
+		})
+	}
+}
+
+func TestGateCommands_TimeoutAndCancel(t *testing.T) {
+	s := &Server{commandApprovals: NewCommandApprovalManager(t.TempDir())}
+	s.commandApprovals.timeout = 10 * time.Millisecond
+
+	allowed, rejected := s.gateCommands(context.Background(), "", "sess-1", "claude-code",
+		[]string{"kubectl delete pod x"}, func(CommandApprovalRequest) {})
+	if len(allowed) != 0 || len(rejected) != 1 {
+		t.Fatalf("Expected command to be rejected on timeout, got allowed=%v", allowed)
+	}
+	if audit := s.commandApprovals.GetAudit(1); audit[0].Decision != CommandDecisionTimedOut {
+		t.Errorf("Expected timed_out decision, got %s", audit[0].Decision)
+	}
+
+	s.commandApprovals.timeout = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.gateCommands(ctx, "", "sess-1", "claude-code", []string{"kubectl apply -f x.yaml"}, func(CommandApprovalRequest) {})
+	if audit := s.commandApprovals.GetAudit(1); audit[0].Decision != CommandDecisionCancelled {
+		t.Errorf("Expected cancelled decision, got %s", audit[0].Decision)
+	}
+}
+
+func TestCommandApprovalManager_AuditPersisted(t *testing.T) {
+	dir := t.TempDir()
+	m := NewCommandApprovalManager(dir)
+
+	m.Record(CommandAuditEntry{Command: "kubectl get pods", Risk: CommandRiskSafe, Decision: CommandDecisionAutoApproved})
+	m.Record(CommandAuditEntry{Command: "kubectl delete pod x", Risk: CommandRiskDestructive, Decision: CommandDecisionRejected})
+
+	f, err := os.Open(filepath.Join(dir, commandAuditFile))
+	if err != nil {
+		t.Fatalf("Expected audit file to exist: %v", err)
+	}
+	defer f.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("Expected 2 audit lines, got %d", lines)
+	}
+
+	if m.Resolve(CommandApprovalResponse{RequestID: "missing"}) {
+		t.Error("Expected Resolve to fail for unknown request")
+	}
+}
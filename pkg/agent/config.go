@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sync"
@@ -20,6 +22,8 @@ const (
 type AgentConfig struct {
 	Agents       map[string]AgentKeyConfig `yaml:"agents"`
 	DefaultAgent string                    `yaml:"default_agent,omitempty"`
+	// KeyStorage is "file" (default) or "keychain"; see KeyStorageEnv
+	KeyStorage string `yaml:"key_storage,omitempty"`
 }
 
 // AgentKeyConfig holds API key configuration for a provider
@@ -35,6 +39,17 @@ type ConfigManager struct {
 	config      *AgentConfig
 	keyValidity map[string]bool // Cache of key validity (true=valid, false=invalid)
 	validityMu  sync.RWMutex    // Separate mutex for validity cache
+
+	// Lowest-precedence defaults from ~/.kc/agent.yaml
+	fileDefaultAgent string
+	fileProviders    map[string]AgentFileProvider
+
+	// OS keychain used when key_storage is "keychain", opened on first use;
+	// secrets caches lookups so the keychain isn't queried on every request
+	secretsMu   sync.Mutex
+	secrets     map[string]string
+	secretStore secretStore
+	storeOpened bool
 }
 
 var (
@@ -69,8 +84,17 @@ func GetConfigManager() *ConfigManager {
 	return globalConfigManager
 }
 
-// Load reads the config from disk
+// Load reads the config from disk, moving plaintext keys to the OS keychain
+// when key_storage is "keychain"
 func (cm *ConfigManager) Load() error {
+	if err := cm.load(); err != nil {
+		return err
+	}
+	cm.migrateKeysToKeychain()
+	return nil
+}
+
+func (cm *ConfigManager) load() error {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
@@ -96,6 +120,95 @@ func (cm *ConfigManager) Load() error {
 	return nil
 }
 
+// migrateKeysToKeychain moves plaintext keys out of config.yaml once
+// key_storage is "keychain"
+func (cm *ConfigManager) migrateKeysToKeychain() {
+	store := cm.keychain()
+	if store == nil {
+		return
+	}
+
+	cm.mu.Lock()
+	var migrated []string
+	for provider, agentConfig := range cm.config.Agents {
+		if agentConfig.APIKey == "" {
+			continue
+		}
+		if err := store.Set(provider, agentConfig.APIKey); err != nil {
+			log.Printf("Warning: could not move the %s API key to the %s: %v", provider, store.Name(), err)
+			continue
+		}
+		cm.cacheSecret(provider, agentConfig.APIKey)
+		agentConfig.APIKey = ""
+		cm.config.Agents[provider] = agentConfig
+		migrated = append(migrated, provider)
+	}
+	cm.mu.Unlock()
+
+	if len(migrated) == 0 {
+		return
+	}
+	if err := cm.Save(); err != nil {
+		log.Printf("Warning: moved API keys to the %s but could not rewrite config: %v", store.Name(), err)
+		return
+	}
+	log.Printf("Moved %d API key(s) from %s to the %s", len(migrated), cm.configPath, store.Name())
+}
+
+// keychain returns the OS keychain when API keys are stored there, nil otherwise
+func (cm *ConfigManager) keychain() secretStore {
+	cm.mu.RLock()
+	storage := cm.config.keyStorage()
+	cm.mu.RUnlock()
+	if storage != KeyStorageKeychain {
+		return nil
+	}
+
+	cm.secretsMu.Lock()
+	defer cm.secretsMu.Unlock()
+	if !cm.storeOpened {
+		cm.storeOpened = true
+		store, err := newKeychainStore()
+		if err != nil {
+			log.Printf("Warning: key_storage is keychain but %v; keeping API keys in %s", err, cm.configPath)
+		}
+		cm.secretStore = store
+	}
+	return cm.secretStore
+}
+
+// keychainSecret returns the provider's key from the OS keychain, if used
+func (cm *ConfigManager) keychainSecret(provider string) string {
+	store := cm.keychain()
+	if store == nil {
+		return ""
+	}
+	cm.secretsMu.Lock()
+	defer cm.secretsMu.Unlock()
+	if secret, ok := cm.secrets[provider]; ok {
+		return secret
+	}
+	secret, err := store.Get(provider)
+	if err != nil && !errors.Is(err, errSecretNotFound) {
+		log.Printf("Warning: could not read the %s API key from the %s: %v", provider, store.Name(), err)
+		return ""
+	}
+	if cm.secrets == nil {
+		cm.secrets = make(map[string]string)
+	}
+	cm.secrets[provider] = secret
+	return secret
+}
+
+func (cm *ConfigManager) cacheSecret(provider, secret string) {
+	cm.secretsMu.Lock()
+	defer cm.secretsMu.Unlock()
+	if cm.secrets == nil {
+		cm.secrets = make(map[string]string)
+	}
+	cm.secrets[provider] = secret
+}
+
 // Save writes the config to disk with secure permissions
 func (cm *ConfigManager) Save() error {
 	cm.mu.Lock()
@@ -128,16 +241,21 @@ func (cm *ConfigManager) GetAPIKey(provider string) string {
 		return envVal
 	}
 
-	// Fall back to config file
+	// Fall back to config file, then the OS keychain
 	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-
 	if cm.config != nil {
-		if agentConfig, ok := cm.config.Agents[provider]; ok {
+		if agentConfig, ok := cm.config.Agents[provider]; ok && agentConfig.APIKey != "" {
+			cm.mu.RUnlock()
 			return agentConfig.APIKey
 		}
 	}
-	return ""
+	fileKey := cm.fileProviders[provider].APIKey
+	cm.mu.RUnlock()
+
+	if secret := cm.keychainSecret(provider); secret != "" {
+		return secret
+	}
+	return fileKey
 }
 
 // GetModel returns the model for a provider (env var takes precedence)
@@ -157,14 +275,26 @@ func (cm *ConfigManager) GetModel(provider, defaultModel string) string {
 			return agentConfig.Model
 		}
 	}
+	if model := cm.fileProviders[provider].Model; model != "" {
+		return model
+	}
 	return defaultModel
 }
 
-// SetAPIKey stores an API key for a provider
+// SetAPIKey stores an API key for a provider, in the OS keychain when configured
 func (cm *ConfigManager) SetAPIKey(provider, apiKey string) error {
+	stored := apiKey
+	if store := cm.keychain(); store != nil {
+		if err := store.Set(provider, apiKey); err != nil {
+			return fmt.Errorf("failed to store key in %s: %w", store.Name(), err)
+		}
+		cm.cacheSecret(provider, apiKey)
+		stored = ""
+	}
+
 	cm.mu.Lock()
 	agentConfig := cm.config.Agents[provider]
-	agentConfig.APIKey = apiKey
+	agentConfig.APIKey = stored
 	cm.config.Agents[provider] = agentConfig
 	cm.mu.Unlock()
 
@@ -184,6 +314,13 @@ func (cm *ConfigManager) SetModel(provider, model string) error {
 
 // RemoveAPIKey removes the API key for a provider
 func (cm *ConfigManager) RemoveAPIKey(provider string) error {
+	if store := cm.keychain(); store != nil {
+		if err := store.Delete(provider); err != nil {
+			return fmt.Errorf("failed to delete key from %s: %w", store.Name(), err)
+		}
+		cm.cacheSecret(provider, "")
+	}
+
 	cm.mu.Lock()
 	delete(cm.config.Agents, provider)
 	cm.mu.Unlock()
@@ -196,6 +333,28 @@ func (cm *ConfigManager) HasAPIKey(provider string) bool {
 	return cm.GetAPIKey(provider) != ""
 }
 
+// KeySource returns where the provider's key comes from: env, keychain or config
+func (cm *ConfigManager) KeySource(provider string) string {
+	if cm.IsFromEnv(provider) {
+		return "env"
+	}
+	cm.mu.RLock()
+	inConfig := cm.config.Agents[provider].APIKey != ""
+	cm.mu.RUnlock()
+	if !inConfig && cm.keychainSecret(provider) != "" {
+		return KeyStorageKeychain
+	}
+	return "config"
+}
+
+// KeyStorage returns the storage new keys are saved to, KeyStorageFile or KeyStorageKeychain
+func (cm *ConfigManager) KeyStorage() string {
+	if cm.keychain() != nil {
+		return KeyStorageKeychain
+	}
+	return KeyStorageFile
+}
+
 // IsFromEnv checks if the API key is from environment variable
 func (cm *ConfigManager) IsFromEnv(provider string) bool {
 	envKey := getEnvKeyForProvider(provider)
@@ -243,7 +402,19 @@ func (cm *ConfigManager) IsKeyAvailable(provider string) bool {
 func (cm *ConfigManager) GetDefaultAgent() string {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
-	return cm.config.DefaultAgent
+	if cm.config.DefaultAgent != "" {
+		return cm.config.DefaultAgent
+	}
+	return cm.fileDefaultAgent
+}
+
+// SetFileDefaults sets the provider defaults from agent.yaml, used when neither an
+// env var nor ~/.kc/config.yaml configures a provider
+func (cm *ConfigManager) SetFileDefaults(defaultAgent string, providers map[string]AgentFileProvider) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.fileDefaultAgent = defaultAgent
+	cm.fileProviders = providers
 }
 
 // SetDefaultAgent sets the default agent
@@ -0,0 +1,293 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	contextHealthFile = "context-health.json"
+	// defaultStaleContextDays is how long a context must be unreachable before
+	// it is suggested for removal
+	defaultStaleContextDays = 7
+)
+
+// ContextHealthRecord tracks when a kubeconfig context was last reachable
+type ContextHealthRecord struct {
+	Context          string `json:"context"`
+	FirstSeen        string `json:"firstSeen"` // RFC3339
+	LastChecked      string `json:"lastChecked"`
+	LastReachable    string `json:"lastReachable,omitempty"`
+	UnreachableSince string `json:"unreachableSince,omitempty"`
+	LastError        string `json:"lastError,omitempty"`
+}
+
+// StaleContext is a context that has been unreachable long enough to suggest removing it
+type StaleContext struct {
+	ContextHealthRecord
+	Server          string `json:"server,omitempty"`
+	DaysUnreachable int    `json:"daysUnreachable"`
+	IsCurrent       bool   `json:"isCurrent,omitempty"`
+}
+
+// ContextHealthHistory persists per-context reachability across restarts so
+// contexts that have been dead for days can be told apart from a brief outage
+type ContextHealthHistory struct {
+	mu      sync.Mutex
+	records map[string]*ContextHealthRecord
+	path    string
+	now     func() time.Time
+}
+
+// NewContextHealthHistory loads the reachability history stored in dataDir
+func NewContextHealthHistory(dataDir string) *ContextHealthHistory {
+	h := &ContextHealthHistory{
+		records: make(map[string]*ContextHealthRecord),
+		path:    filepath.Join(dataDir, contextHealthFile),
+		now:     time.Now,
+	}
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[ContextHealth] Error reading %s: %v", h.path, err)
+		}
+		return h
+	}
+	var records []*ContextHealthRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		log.Printf("[ContextHealth] Error parsing %s: %v", h.path, err)
+		return h
+	}
+	for _, r := range records {
+		h.records[r.Context] = r
+	}
+	return h
+}
+
+// Record updates the history from a round of cluster health checks
+func (h *ContextHealthHistory) Record(health []k8s.ClusterHealth) {
+	if len(health) == 0 {
+		return
+	}
+	now := h.now().UTC().Format(time.RFC3339)
+
+	h.mu.Lock()
+	for _, c := range health {
+		r, ok := h.records[c.Cluster]
+		if !ok {
+			r = &ContextHealthRecord{Context: c.Cluster, FirstSeen: now}
+			h.records[c.Cluster] = r
+		}
+		r.LastChecked = now
+		if c.Reachable {
+			r.LastReachable, r.UnreachableSince, r.LastError = now, "", ""
+			continue
+		}
+		if r.UnreachableSince == "" {
+			r.UnreachableSince = now
+		}
+		r.LastError = c.ErrorMessage
+	}
+	h.mu.Unlock()
+
+	h.save()
+}
+
+// Stale returns the contexts in the kubeconfig that have been unreachable for at
+// least minAge, longest unreachable first
+func (h *ContextHealthHistory) Stale(minAge time.Duration, contexts map[string]string, current string) []StaleContext {
+	now := h.now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stale := []StaleContext{}
+	for name, server := range contexts {
+		r, ok := h.records[name]
+		if !ok || r.UnreachableSince == "" {
+			continue
+		}
+		since, err := time.Parse(time.RFC3339, r.UnreachableSince)
+		if err != nil || now.Sub(since) < minAge {
+			continue
+		}
+		stale = append(stale, StaleContext{
+			ContextHealthRecord: *r,
+			Server:              server,
+			DaysUnreachable:     int(now.Sub(since).Hours() / 24),
+			IsCurrent:           name == current,
+		})
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].UnreachableSince != stale[j].UnreachableSince {
+			return stale[i].UnreachableSince < stale[j].UnreachableSince
+		}
+		return stale[i].Context < stale[j].Context
+	})
+	return stale
+}
+
+// Forget drops the history of removed contexts
+func (h *ContextHealthHistory) Forget(contexts []string) {
+	h.mu.Lock()
+	for _, name := range contexts {
+		delete(h.records, name)
+	}
+	h.mu.Unlock()
+	h.save()
+}
+
+func (h *ContextHealthHistory) save() {
+	h.mu.Lock()
+	records := make([]*ContextHealthRecord, 0, len(h.records))
+	for _, r := range h.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Context < records[j].Context })
+	data, err := json.Marshal(records)
+	h.mu.Unlock()
+	if err != nil {
+		log.Printf("[ContextHealth] Error marshaling history: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), metricsDirMode); err != nil {
+		log.Printf("[ContextHealth] Error creating data dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(h.path, data, metricsFileMode); err != nil {
+		log.Printf("[ContextHealth] Error writing history file: %v", err)
+	}
+}
+
+// staleContextAge parses the days query parameter
+func staleContextAge(r *http.Request) (time.Duration, error) {
+	days := defaultStaleContextDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("days must be a positive integer")
+		}
+		days = n
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// staleContexts lists stale contexts currently in the kubeconfig
+func (s *Server) staleContexts(minAge time.Duration) []StaleContext {
+	clusters, current := s.kubectl.ListContexts()
+	contexts := make(map[string]string, len(clusters))
+	for _, c := range clusters {
+		contexts[c.Name] = c.Server
+	}
+	return s.contextHealth.Stale(minAge, contexts, current)
+}
+
+// staleContextsRemoveRequest selects stale contexts to remove
+type staleContextsRemoveRequest struct {
+	Contexts []string `json:"contexts"`
+	// Confirm must be true; it guards against accidental bulk removal
+	Confirm bool `json:"confirm"`
+}
+
+// handleStaleContexts lists contexts unreachable for at least ?days= days (GET)
+// or removes selected stale contexts after backing up the kubeconfig (POST).
+// Only contexts that are still stale are removed, and never the current context.
+func (s *Server) handleStaleContexts(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.kubectl == nil || s.contextHealth == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "kubeconfig not available"})
+		return
+	}
+
+	minAge, err := staleContextAge(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"contexts": s.staleContexts(minAge),
+			"days":     int(minAge.Hours() / 24),
+		})
+
+	case "POST":
+		var req staleContextsRemoveRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		if !req.Confirm || len(req.Contexts) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "contexts and confirm=true are required"})
+			return
+		}
+
+		stale := make(map[string]StaleContext)
+		for _, c := range s.staleContexts(minAge) {
+			stale[c.Context] = c
+		}
+		var remove []string
+		skipped := make(map[string]string)
+		for _, name := range req.Contexts {
+			c, ok := stale[name]
+			switch {
+			case !ok:
+				skipped[name] = "not stale"
+			case c.IsCurrent:
+				skipped[name] = "current context"
+			default:
+				remove = append(remove, name)
+			}
+		}
+
+		removed, backup, err := s.kubectl.RemoveContexts(remove)
+		if err != nil {
+			log.Printf("[ContextHealth] failed to remove stale contexts: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to update kubeconfig"})
+			return
+		}
+		s.contextHealth.Forget(removed)
+		if len(removed) > 0 {
+			log.Printf("[ContextHealth] removed %d stale contexts (backup: %s)", len(removed), backup)
+		}
+		if removed == nil {
+			removed = []string{}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"removed": removed,
+			"skipped": skipped,
+			"backup":  backup,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+func TestContextHealthHistory(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	h := NewContextHealthHistory(dir)
+	h.now = func() time.Time { return now }
+
+	h.Record([]k8s.ClusterHealth{
+		{Cluster: "dead", Reachable: false, ErrorMessage: "dial tcp: no route to host"},
+		{Cluster: "flaky", Reachable: false},
+		{Cluster: "live", Reachable: true},
+	})
+	now = now.Add(10 * 24 * time.Hour)
+	h.Record([]k8s.ClusterHealth{
+		{Cluster: "dead", Reachable: false},
+		{Cluster: "flaky", Reachable: true},
+		{Cluster: "live", Reachable: true},
+	})
+
+	// History survives a restart
+	h = NewContextHealthHistory(dir)
+	h.now = func() time.Time { return now }
+
+	contexts := map[string]string{"dead": "https://dead:6443", "flaky": "", "live": ""}
+	stale := h.Stale(7*24*time.Hour, contexts, "")
+	if len(stale) != 1 || stale[0].Context != "dead" || stale[0].DaysUnreachable != 10 || stale[0].Server != "https://dead:6443" {
+		t.Fatalf("Expected only dead to be stale for 10 days, got %+v", stale)
+	}
+	if stale[0].FirstSeen != "2026-03-01T00:00:00Z" {
+		t.Errorf("Expected the unreachable streak to start at the first failure, got %+v", stale[0])
+	}
+	if got := h.Stale(30*24*time.Hour, contexts, ""); len(got) != 0 {
+		t.Errorf("Expected no contexts stale for 30 days, got %+v", got)
+	}
+	if got := h.Stale(7*24*time.Hour, map[string]string{"live": ""}, ""); len(got) != 0 {
+		t.Errorf("Expected contexts no longer in the kubeconfig to be ignored, got %+v", got)
+	}
+}
+
+func TestHandleStaleContextsRemove(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfigPath := filepath.Join(dir, "config")
+	config := `apiVersion: v1
+kind: Config
+clusters:
+- cluster: {server: "https://dead:6443"}
+  name: dead
+- cluster: {server: "https://old:6443"}
+  name: old
+- cluster: {server: "https://live:6443"}
+  name: live
+contexts:
+- context: {cluster: dead, user: dead}
+  name: dead
+- context: {cluster: old, user: shared}
+  name: old
+- context: {cluster: live, user: shared}
+  name: live
+users:
+- name: dead
+  user: {token: a}
+- name: shared
+  user: {token: b}
+current-context: old
+`
+	if err := os.WriteFile(kubeconfigPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := NewKubectlProxy(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("NewKubectlProxy failed: %v", err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	history := NewContextHealthHistory(dir)
+	history.now = func() time.Time { return now }
+	history.Record([]k8s.ClusterHealth{{Cluster: "dead"}, {Cluster: "old"}, {Cluster: "live", Reachable: true}})
+	now = now.Add(8 * 24 * time.Hour)
+
+	s := &Server{kubectl: proxy, contextHealth: history, allowedOrigins: []string{"*"}}
+	body, _ := json.Marshal(staleContextsRemoveRequest{Contexts: []string{"dead", "old", "live"}, Confirm: true})
+	w := httptest.NewRecorder()
+	s.handleStaleContexts(w, httptest.NewRequest("POST", "/contexts/stale", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Removed []string          `json:"removed"`
+		Skipped map[string]string `json:"skipped"`
+		Backup  string            `json:"backup"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Removed) != 1 || resp.Removed[0] != "dead" {
+		t.Errorf("Expected only dead to be removed, got %v", resp.Removed)
+	}
+	if resp.Skipped["old"] != "current context" || resp.Skipped["live"] != "not stale" {
+		t.Errorf("Expected the current and live contexts to be skipped, got %v", resp.Skipped)
+	}
+	if _, err := os.Stat(resp.Backup); err != nil {
+		t.Errorf("Expected a kubeconfig backup, got %q: %v", resp.Backup, err)
+	}
+
+	if _, ok := proxy.config.Contexts["dead"]; ok {
+		t.Error("dead context still present")
+	}
+	if _, ok := proxy.config.Clusters["dead"]; ok {
+		t.Error("dead cluster should be removed with its only context")
+	}
+	if _, ok := proxy.config.AuthInfos["shared"]; !ok {
+		t.Error("users still referenced by other contexts must be kept")
+	}
+
+	// Without confirmation nothing is removed
+	body, _ = json.Marshal(staleContextsRemoveRequest{Contexts: []string{"old"}})
+	w = httptest.NewRecorder()
+	s.handleStaleContexts(w, httptest.NewRequest("POST", "/contexts/stale", bytes.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without confirm, got %d", w.Code)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
 )
 
 const (
@@ -50,6 +51,8 @@ type DeviceAlert struct {
 	FirstSeen    time.Time    `json:"firstSeen"`
 	LastSeen     time.Time    `json:"lastSeen"`
 	Severity     string       `json:"severity"` // "warning", "critical"
+	Silenced     bool         `json:"silenced,omitempty"` // covered by an active alert silence
+	Threshold    bool         `json:"threshold,omitempty"` // below a configured expected count (PreviousCount holds the expected value)
 }
 
 // DeviceAlertsResponse is the HTTP response format
@@ -69,6 +72,13 @@ type DeviceTracker struct {
 	maxCounts map[string]DeviceCounts
 	// Current alerts
 	alerts    map[string]*DeviceAlert
+	// Change-point history per node for trend views (persisted when dataDir is set)
+	trends    map[string]*NodeDeviceTrend
+	dataDir   string
+	saveMu    sync.Mutex // serializes writes of the trends file
+
+	// Expected device counts per node pool
+	thresholds func() []settings.DeviceThreshold
 
 	mu        sync.RWMutex
 	stopCh    chan struct{}
@@ -85,6 +95,7 @@ func NewDeviceTracker(k8sClient *k8s.MultiClusterClient, broadcast func(string,
 		history:   make(map[string][]DeviceSnapshot),
 		maxCounts: make(map[string]DeviceCounts),
 		alerts:    make(map[string]*DeviceAlert),
+		trends:    make(map[string]*NodeDeviceTrend),
 		stopCh:    make(chan struct{}),
 		broadcast: broadcast,
 	}
@@ -118,7 +129,8 @@ func (t *DeviceTracker) runLoop() {
 }
 
 func (t *DeviceTracker) scanDevices() {
-	ctx, cancel := context.WithTimeout(context.Background(), deviceTrackerTimeout)
+	// Alerts compare consecutive scans, so don't reuse a node listing made for another query
+	ctx, cancel := context.WithTimeout(k8s.WithFreshSnapshot(context.Background()), deviceTrackerTimeout)
 	defer cancel()
 
 	// Use ListClusters to get ALL cluster contexts - deduplication happens in frontend
@@ -133,6 +145,14 @@ func (t *DeviceTracker) scanDevices() {
 	}
 
 	newAlerts := false
+	trendsChanged := false
+
+	t.mu.RLock()
+	var rules []settings.DeviceThreshold
+	if t.thresholds != nil {
+		rules = t.thresholds()
+	}
+	t.mu.RUnlock()
 
 	for _, cluster := range clusters {
 		nodes, err := t.k8sClient.GetNodes(ctx, cluster.Context)
@@ -277,10 +297,23 @@ func (t *DeviceTracker) scanDevices() {
 				newAlerts = true
 			}
 
+			// Check configured per-pool expectations
+			if t.checkThresholdsLocked(rules, key, node.Name, cluster.Name, node.Labels, counts) {
+				newAlerts = true
+			}
+
+			if t.recordTrendLocked(key, cluster.Name, node.Name, counts, snapshot.Timestamp) {
+				trendsChanged = true
+			}
+
 			t.mu.Unlock()
 		}
 	}
 
+	if trendsChanged {
+		go t.saveTrends()
+	}
+
 	// Broadcast if new alerts
 	if newAlerts && t.broadcast != nil {
 		t.broadcast("device_alerts_updated", t.GetAlerts())
@@ -3,8 +3,10 @@ package agent
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -95,3 +97,102 @@ func TestDeviceTracker(t *testing.T) {
 		t.Errorf("Expected at least 2 snapshots in history, got %d", len(history))
 	}
 }
+
+func TestDeviceTracker_ThresholdsAndTrends(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{
+		Contexts: map[string]*api.Context{"c1": {Cluster: "cl1"}},
+		Clusters: map[string]*api.Cluster{"cl1": {Server: "s1"}},
+	})
+
+	ibNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ib-1",
+			Labels: map[string]string{
+				"pool":             "hpc",
+				"pci-15b3.present": "true",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	cpuNode := ibNode.DeepCopy()
+	cpuNode.Name = "cpu-1"
+	cpuNode.Labels = map[string]string{"pool": "general"}
+	m.InjectClient("c1", fake.NewSimpleClientset(ibNode, cpuNode))
+
+	dir := t.TempDir()
+	broadcasts := 0
+	dt := NewDeviceTracker(m, func(string, interface{}) { broadcasts++ })
+	dt.SetDataDir(dir)
+	dt.SetThresholdSource(func() []settings.DeviceThreshold {
+		return []settings.DeviceThreshold{
+			{NodePool: "pool=hpc", DeviceType: "infiniband", MinCount: 2, Severity: "critical"},
+			{NodePool: "pool=hpc", DeviceType: "infiniband", MinCount: 1},
+		}
+	})
+
+	dt.scanDevices()
+
+	alerts := dt.GetAlerts().Alerts
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 threshold alert, got %+v", alerts)
+	}
+	a := alerts[0]
+	if !a.Threshold || a.NodeName != "ib-1" || a.PreviousCount != 2 || a.CurrentCount != 1 || a.Severity != "critical" {
+		t.Errorf("Unexpected threshold alert: %+v", a)
+	}
+
+	// Unchanged counts don't add trend points within the heartbeat or re-announce the alert
+	dt.scanDevices()
+	if broadcasts != 1 {
+		t.Errorf("Expected a single alert broadcast, got %d", broadcasts)
+	}
+	trends := dt.GetTrends("c1", "ib-1", 24)
+	if len(trends.Nodes) != 1 || len(trends.Nodes[0].Points) != 1 {
+		t.Fatalf("Expected a single trend point for ib-1, got %+v", trends.Nodes)
+	}
+	if got := dt.GetTrends("c1", "", 24); len(got.Nodes) != 2 {
+		t.Errorf("Expected trends for both nodes, got %d", len(got.Nodes))
+	}
+
+	// Trends survive a restart
+	dt.saveTrends()
+	dt2 := NewDeviceTracker(m, nil)
+	dt2.SetDataDir(dir)
+	if got := dt2.GetTrends("c1", "ib-1", 24); len(got.Nodes) != 1 || got.Nodes[0].Points[0].Counts.InfiniBandCount != 1 {
+		t.Errorf("Expected persisted trends, got %+v", got.Nodes)
+	}
+}
+
+func TestDeviceTracker_TrendsKeepClusterNamesWithSlashes(t *testing.T) {
+	dt := NewDeviceTracker(nil, nil)
+	cluster := "arn:aws:eks:us-east-1:123456789012:cluster/prod"
+	dt.mu.Lock()
+	dt.recordTrendLocked(cluster+"/gpu-1", cluster, "gpu-1", DeviceCounts{GPUCount: 8}, time.Now())
+	dt.mu.Unlock()
+
+	got := dt.GetTrends(cluster, "gpu-1", 24)
+	if len(got.Nodes) != 1 || got.Nodes[0].Cluster != cluster || got.Nodes[0].NodeName != "gpu-1" {
+		t.Errorf("Expected the trend for %s/gpu-1, got %+v", cluster, got.Nodes)
+	}
+}
+
+func TestValidateDeviceThreshold(t *testing.T) {
+	tests := []struct {
+		rule    settings.DeviceThreshold
+		wantErr bool
+	}{
+		{settings.DeviceThreshold{DeviceType: "infiniband", MinCount: 4, NodePool: "pool in (hpc,gpu)"}, false},
+		{settings.DeviceThreshold{DeviceType: "tpu", MinCount: 1}, true},
+		{settings.DeviceThreshold{DeviceType: "nic", MinCount: 0}, true},
+		{settings.DeviceThreshold{DeviceType: "nic", MinCount: 1, Severity: "page"}, true},
+		{settings.DeviceThreshold{DeviceType: "nic", MinCount: 1, NodePool: "pool in (hpc"}, true},
+	}
+	for _, tt := range tests {
+		if err := validateDeviceThreshold(tt.rule); (err != nil) != tt.wantErr {
+			t.Errorf("validateDeviceThreshold(%+v) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+		}
+	}
+}
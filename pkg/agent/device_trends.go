@@ -0,0 +1,392 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/settings"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	deviceTrendsFile         = "device_trends.json"
+	deviceTrendRetention     = 7 * 24 * time.Hour
+	deviceTrendHeartbeat     = time.Hour // record a point at least this often even without changes
+	maxDeviceTrendPoints     = 1000      // per node
+	defaultDeviceTrendWindow = 24        // hours
+	maxDeviceTrendWindow     = 24 * 7    // hours
+)
+
+// countedDeviceTypes are the device types that have counts and can have thresholds
+var countedDeviceTypes = []string{"gpu", "nic", "nvme", "infiniband"}
+
+// DeviceTrendPoint is a device count sample for a node
+type DeviceTrendPoint struct {
+	Timestamp time.Time    `json:"timestamp"`
+	Counts    DeviceCounts `json:"counts"`
+}
+
+// NodeDeviceTrend holds the count history for one node
+type NodeDeviceTrend struct {
+	Cluster  string             `json:"cluster"`
+	NodeName string             `json:"nodeName"`
+	Points   []DeviceTrendPoint `json:"points"`
+}
+
+// DeviceTrendsResponse is the HTTP response for device trends
+type DeviceTrendsResponse struct {
+	Nodes     []NodeDeviceTrend `json:"nodes"`
+	Hours     int               `json:"hours"`
+	Timestamp string            `json:"timestamp"`
+}
+
+// deviceCount returns the count for a counted device type
+func deviceCount(c DeviceCounts, deviceType string) int {
+	switch deviceType {
+	case "gpu":
+		return c.GPUCount
+	case "nic":
+		return c.NICCount
+	case "nvme":
+		return c.NVMECount
+	case "infiniband":
+		return c.InfiniBandCount
+	}
+	return 0
+}
+
+// SetDataDir enables persistence of device trends in dir and loads any saved history
+func (t *DeviceTracker) SetDataDir(dir string) {
+	t.mu.Lock()
+	t.dataDir = dir
+	t.mu.Unlock()
+	t.loadTrends()
+}
+
+// SetThresholdSource sets the function used to read device thresholds on each scan
+func (t *DeviceTracker) SetThresholdSource(thresholds func() []settings.DeviceThreshold) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.thresholds = thresholds
+}
+
+// settingsDeviceThresholds reads device thresholds from the persisted settings
+func settingsDeviceThresholds() []settings.DeviceThreshold {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil || all == nil {
+		return nil
+	}
+	return all.DeviceThresholds
+}
+
+// recordTrendLocked appends a trend point when counts changed or the heartbeat
+// elapsed, and reports whether anything was recorded. Must be called with lock held.
+func (t *DeviceTracker) recordTrendLocked(key, cluster, nodeName string, counts DeviceCounts, now time.Time) bool {
+	trend := t.trends[key]
+	if trend == nil {
+		trend = &NodeDeviceTrend{Cluster: cluster, NodeName: nodeName}
+		t.trends[key] = trend
+	}
+	points := trend.Points
+	if n := len(points); n > 0 && points[n-1].Counts == counts && now.Sub(points[n-1].Timestamp) < deviceTrendHeartbeat {
+		return false
+	}
+
+	points = append(points, DeviceTrendPoint{Timestamp: now, Counts: counts})
+	cutoff := now.Add(-deviceTrendRetention)
+	start := 0
+	for start < len(points) && points[start].Timestamp.Before(cutoff) {
+		start++
+	}
+	if len(points)-start > maxDeviceTrendPoints {
+		start = len(points) - maxDeviceTrendPoints
+	}
+	trend.Points = points[start:]
+	return true
+}
+
+// checkThresholdsLocked raises or clears threshold alerts for a node and reports
+// whether a new alert was raised. Must be called with lock held.
+func (t *DeviceTracker) checkThresholdsLocked(rules []settings.DeviceThreshold, key, nodeName, cluster string, nodeLabels map[string]string, counts DeviceCounts) bool {
+	// Use the strictest matching rule per device type
+	expected := make(map[string]settings.DeviceThreshold)
+	for _, rule := range rules {
+		if rule.Cluster != "" && rule.Cluster != cluster {
+			continue
+		}
+		if rule.NodePool != "" {
+			selector, err := labels.Parse(rule.NodePool)
+			if err != nil || !selector.Matches(labels.Set(nodeLabels)) {
+				continue
+			}
+		}
+		if cur, ok := expected[rule.DeviceType]; !ok || rule.MinCount > cur.MinCount {
+			expected[rule.DeviceType] = rule
+		}
+	}
+
+	raised := false
+	now := time.Now()
+	for _, deviceType := range countedDeviceTypes {
+		alertKey := key + "/" + deviceType + "/threshold"
+		rule, ok := expected[deviceType]
+		current := deviceCount(counts, deviceType)
+		if !ok || current >= rule.MinCount {
+			delete(t.alerts, alertKey)
+			continue
+		}
+
+		severity := rule.Severity
+		if severity == "" {
+			severity = "warning"
+		}
+		if existing, ok := t.alerts[alertKey]; ok {
+			existing.PreviousCount = rule.MinCount
+			existing.CurrentCount = current
+			existing.DroppedCount = rule.MinCount - current
+			existing.LastSeen = now
+			existing.Severity = severity
+			continue
+		}
+		t.alerts[alertKey] = &DeviceAlert{
+			ID:            alertKey,
+			NodeName:      nodeName,
+			Cluster:       cluster,
+			DeviceType:    deviceType,
+			PreviousCount: rule.MinCount,
+			CurrentCount:  current,
+			DroppedCount:  rule.MinCount - current,
+			FirstSeen:     now,
+			LastSeen:      now,
+			Severity:      severity,
+			Threshold:     true,
+		}
+		raised = true
+		log.Printf("[DeviceTracker] ALERT: %s on %s/%s below expected count (%d < %d)",
+			deviceType, cluster, nodeName, current, rule.MinCount)
+	}
+	return raised
+}
+
+// GetTrends returns device count history for nodes matching cluster and node
+// (empty matches all) over the last hours
+func (t *DeviceTracker) GetTrends(cluster, nodeName string, hours int) DeviceTrendsResponse {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	cutoff := time.Now().Add(-time.Duration(hours) * time.Hour)
+	nodes := []NodeDeviceTrend{}
+	for _, stored := range t.trends {
+		if (cluster != "" && stored.Cluster != cluster) || (nodeName != "" && stored.NodeName != nodeName) {
+			continue
+		}
+		trend := NodeDeviceTrend{Cluster: stored.Cluster, NodeName: stored.NodeName, Points: []DeviceTrendPoint{}}
+		for _, p := range stored.Points {
+			if !p.Timestamp.Before(cutoff) {
+				trend.Points = append(trend.Points, p)
+			}
+		}
+		nodes = append(nodes, trend)
+	}
+
+	return DeviceTrendsResponse{
+		Nodes:     nodes,
+		Hours:     hours,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// saveTrends writes the trends file. Saves run in the background after scans,
+// so they are serialized and written via rename to never leave a partial file.
+func (t *DeviceTracker) saveTrends() {
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+
+	t.mu.RLock()
+	dir := t.dataDir
+	data, err := json.Marshal(t.trends)
+	t.mu.RUnlock()
+	if dir == "" {
+		return
+	}
+	if err != nil {
+		log.Printf("[DeviceTracker] Error marshaling trends: %v", err)
+		return
+	}
+	if err := os.MkdirAll(dir, metricsDirMode); err != nil {
+		log.Printf("[DeviceTracker] Error creating data dir: %v", err)
+		return
+	}
+	path := filepath.Join(dir, deviceTrendsFile)
+	if err := os.WriteFile(path+".tmp", data, agentFileMode); err != nil {
+		log.Printf("[DeviceTracker] Error writing trends: %v", err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		log.Printf("[DeviceTracker] Error writing trends: %v", err)
+	}
+}
+
+func (t *DeviceTracker) loadTrends() {
+	t.mu.RLock()
+	dir := t.dataDir
+	t.mu.RUnlock()
+	if dir == "" {
+		return
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, deviceTrendsFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[DeviceTracker] Error reading trends: %v", err)
+		}
+		return
+	}
+	var trends map[string]*NodeDeviceTrend
+	if err := json.Unmarshal(data, &trends); err != nil {
+		log.Printf("[DeviceTracker] Error parsing trends: %v", err)
+		return
+	}
+
+	t.mu.Lock()
+	for key, trend := range trends {
+		if trend != nil {
+			t.trends[key] = trend
+		}
+	}
+	t.mu.Unlock()
+}
+
+// validateDeviceThreshold checks a threshold rule from the API
+func validateDeviceThreshold(rule settings.DeviceThreshold) error {
+	known := false
+	for _, dt := range countedDeviceTypes {
+		if rule.DeviceType == dt {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown deviceType %q (expected one of %s)", rule.DeviceType, strings.Join(countedDeviceTypes, ", "))
+	}
+	if rule.MinCount < 1 {
+		return fmt.Errorf("minCount must be at least 1")
+	}
+	if rule.Severity != "" && rule.Severity != "warning" && rule.Severity != "critical" {
+		return fmt.Errorf("severity must be warning or critical")
+	}
+	if rule.NodePool != "" {
+		if _, err := labels.Parse(rule.NodePool); err != nil {
+			return fmt.Errorf("invalid nodePool selector: %v", err)
+		}
+	}
+	return nil
+}
+
+// handleDeviceTrends returns device count trends per node
+func (s *Server) handleDeviceTrends(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	hours := defaultDeviceTrendWindow
+	if h, err := strconv.Atoi(r.URL.Query().Get("hours")); err == nil && h > 0 {
+		hours = h
+	}
+	if hours > maxDeviceTrendWindow {
+		hours = maxDeviceTrendWindow
+	}
+
+	if s.deviceTracker == nil {
+		json.NewEncoder(w).Encode(DeviceTrendsResponse{
+			Nodes:     []NodeDeviceTrend{},
+			Hours:     hours,
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	q := r.URL.Query()
+	json.NewEncoder(w).Encode(s.deviceTracker.GetTrends(q.Get("cluster"), q.Get("node"), hours))
+}
+
+// handleDeviceThresholds returns (GET) or replaces (PUT) the device thresholds
+func (s *Server) handleDeviceThresholds(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		thresholds := settingsDeviceThresholds()
+		if thresholds == nil {
+			thresholds = []settings.DeviceThreshold{}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"thresholds": thresholds})
+
+	case "PUT":
+		var thresholds []settings.DeviceThreshold
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&thresholds); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		for i, rule := range thresholds {
+			if err := validateDeviceThreshold(rule); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("threshold %d: %v", i, err)})
+				return
+			}
+		}
+		if thresholds == nil {
+			thresholds = []settings.DeviceThreshold{}
+		}
+
+		mgr := settings.GetSettingsManager()
+		all, err := mgr.GetAll()
+		if err == nil {
+			all.DeviceThresholds = thresholds
+			err = mgr.SaveAll(all)
+		}
+		if err != nil {
+			log.Printf("[DeviceTracker] failed to save thresholds: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to save thresholds"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
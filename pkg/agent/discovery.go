@@ -0,0 +1,486 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// mdnsServiceType is the DNS-SD service type kc-agents advertise
+	mdnsServiceType = "_kc-agent._tcp.local."
+	mdnsTTL         = 120 * time.Second
+	// mdnsBrowseInterval re-queries the LAN so peers are refreshed before their TTL runs out
+	mdnsBrowseInterval = 60 * time.Second
+	mdnsMaxPacket      = 9000
+	// maxInstanceLabel is the DNS limit for one label of the instance name
+	maxInstanceLabel = 63
+)
+
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// errMDNSNeedsToken is returned by Start when --mdns is set without an agent token
+var errMDNSNeedsToken = errors.New("--mdns serves the agent on the local network and requires an agent token (KC_AGENT_TOKEN or token in agent.yaml)")
+
+// AgentFileDiscovery advertises the agent on the local network over mDNS
+// (DNS-SD type _kc-agent._tcp) and browses for teammates' agents, which are
+// listed by /discovery. The --mdns flag overrides enabled. Applies at startup.
+// Advertising also serves the agent on the advertised addresses, so it needs an
+// agent token.
+type AgentFileDiscovery struct {
+	MDNS bool   `yaml:"mdns,omitempty"`
+	Name string `yaml:"name,omitempty"` // instance name, default kc-agent-{hostname}-{port}
+}
+
+// DiscoveredAgent is a kc-agent advertised on the local network
+type DiscoveredAgent struct {
+	Instance     string    `json:"instance"`
+	Host         string    `json:"host"`
+	Addresses    []string  `json:"addresses"`
+	Port         int       `json:"port"`
+	Version      string    `json:"version,omitempty"`
+	AuthRequired bool      `json:"authRequired"`
+	LastSeen     time.Time `json:"lastSeen"`
+	expires      time.Time
+}
+
+// mdnsDiscovery answers mDNS queries for this agent and collects the
+// announcements of other agents
+type mdnsDiscovery struct {
+	self         DiscoveredAgent
+	authRequired func() bool
+
+	conn   *net.UDPConn
+	mu     sync.Mutex
+	peers  map[string]*DiscoveredAgent
+	stopCh chan struct{}
+}
+
+// newMDNSDiscovery describes this agent, reachable at addresses; Start joins
+// the multicast group
+func newMDNSDiscovery(name string, port int, addresses []string, authRequired func() bool) *mdnsDiscovery {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+	hostname, _, _ = strings.Cut(hostname, ".")
+	if name == "" {
+		name = fmt.Sprintf("kc-agent-%s-%d", hostname, port)
+	}
+	name = strings.ToLower(strings.ReplaceAll(name, ".", "-"))
+	if len(name) > maxInstanceLabel {
+		name = name[:maxInstanceLabel]
+	}
+	return &mdnsDiscovery{
+		self: DiscoveredAgent{
+			Instance:  name,
+			Host:      hostname + ".local.",
+			Addresses: addresses,
+			Port:      port,
+			Version:   Version,
+		},
+		authRequired: authRequired,
+		peers:        make(map[string]*DiscoveredAgent),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Start announces the agent and answers queries until Stop
+func (d *mdnsDiscovery) Start() error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroup)
+	if err != nil {
+		return fmt.Errorf("failed to join mDNS group: %w", err)
+	}
+	d.conn = conn
+
+	go d.receive()
+	go func() {
+		d.announce(mdnsTTL)
+		d.query()
+		ticker := time.NewTicker(mdnsBrowseInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stopCh:
+				return
+			case <-ticker.C:
+				d.query()
+				d.expirePeers(time.Now())
+			}
+		}
+	}()
+	log.Printf("[Discovery] Advertising %s on %s", d.self.Instance, mdnsServiceType)
+	return nil
+}
+
+// Stop sends a goodbye so peers drop the agent at once, and leaves the group
+func (d *mdnsDiscovery) Stop() {
+	if d.conn == nil {
+		return
+	}
+	close(d.stopCh)
+	d.announce(0)
+	d.conn.Close()
+}
+
+func (d *mdnsDiscovery) instanceName() string {
+	return d.self.Instance + "." + mdnsServiceType
+}
+
+// announce sends the agent's records; ttl 0 is a goodbye. Nothing is
+// advertised while no agent token is configured, since the LAN listeners refuse
+// every request then.
+func (d *mdnsDiscovery) announce(ttl time.Duration) {
+	if ttl > 0 && (d.authRequired == nil || !d.authRequired()) {
+		return
+	}
+	msg, err := d.buildAnnouncement(ttl)
+	if err != nil {
+		log.Printf("[Discovery] failed to build announcement: %v", err)
+		return
+	}
+	if _, err := d.conn.WriteToUDP(msg, mdnsGroup); err != nil {
+		log.Printf("[Discovery] failed to announce: %v", err)
+	}
+}
+
+func (d *mdnsDiscovery) query() {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	b.StartQuestions()
+	b.Question(dnsmessage.Question{Name: dnsmessage.MustNewName(mdnsServiceType), Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET})
+	msg, err := b.Finish()
+	if err != nil {
+		return
+	}
+	if _, err := d.conn.WriteToUDP(msg, mdnsGroup); err != nil {
+		log.Printf("[Discovery] failed to query: %v", err)
+	}
+}
+
+// buildAnnouncement encodes the PTR, SRV, TXT and A records of this agent.
+// A ttl of 0 is a goodbye.
+func (d *mdnsDiscovery) buildAnnouncement(ttl time.Duration) ([]byte, error) {
+	instance, err := dnsmessage.NewName(d.instanceName())
+	if err != nil {
+		return nil, err
+	}
+	host, err := dnsmessage.NewName(d.self.Host)
+	if err != nil {
+		return nil, err
+	}
+	auth := "none"
+	if d.authRequired != nil && d.authRequired() {
+		auth = "token"
+	}
+	hdr := func(name dnsmessage.Name) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: uint32(ttl / time.Second)}
+	}
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, Authoritative: true})
+	b.EnableCompression()
+	if err := b.StartAnswers(); err != nil {
+		return nil, err
+	}
+	if err := b.PTRResource(hdr(dnsmessage.MustNewName(mdnsServiceType)), dnsmessage.PTRResource{PTR: instance}); err != nil {
+		return nil, err
+	}
+	if err := b.SRVResource(hdr(instance), dnsmessage.SRVResource{Port: uint16(d.self.Port), Target: host}); err != nil {
+		return nil, err
+	}
+	txt := []string{"txtvers=1", "version=" + d.self.Version, "auth=" + auth}
+	if err := b.TXTResource(hdr(instance), dnsmessage.TXTResource{TXT: txt}); err != nil {
+		return nil, err
+	}
+	for _, addr := range d.self.Addresses {
+		ip := net.ParseIP(addr).To4()
+		if ip == nil {
+			continue
+		}
+		if err := b.AResource(hdr(host), dnsmessage.AResource{A: [4]byte(ip)}); err != nil {
+			return nil, err
+		}
+	}
+	return b.Finish()
+}
+
+func (d *mdnsDiscovery) receive() {
+	buf := make([]byte, mdnsMaxPacket)
+	for {
+		n, src, err := d.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-d.stopCh:
+				return
+			default:
+			}
+			log.Printf("[Discovery] read failed: %v", err)
+			continue
+		}
+		d.handlePacket(buf[:n], src, time.Now())
+	}
+}
+
+// handlePacket answers queries for the service type and records the agents
+// announced in responses
+func (d *mdnsDiscovery) handlePacket(msg []byte, src *net.UDPAddr, now time.Time) {
+	var p dnsmessage.Parser
+	h, err := p.Start(msg)
+	if err != nil {
+		return
+	}
+	if !h.Response {
+		questions, err := p.AllQuestions()
+		if err != nil {
+			return
+		}
+		for _, q := range questions {
+			if (q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL) && strings.EqualFold(q.Name.String(), mdnsServiceType) {
+				d.announce(mdnsTTL)
+				return
+			}
+		}
+		return
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return
+	}
+	for _, peer := range parseAnnouncement(&p, src, now) {
+		if peer.Instance == d.self.Instance {
+			continue
+		}
+		d.mu.Lock()
+		if peer.expires.After(now) {
+			d.peers[peer.Instance] = peer
+		} else {
+			delete(d.peers, peer.Instance)
+		}
+		d.mu.Unlock()
+	}
+}
+
+// parseAnnouncement collects the kc-agent instances described by the answer
+// and additional records of a response. Addresses fall back to the sender.
+func parseAnnouncement(p *dnsmessage.Parser, src *net.UDPAddr, now time.Time) []*DiscoveredAgent {
+	instances := map[string]*DiscoveredAgent{}
+	hostAddrs := map[string][]string{}
+	get := func(name string) *DiscoveredAgent {
+		if a, ok := instances[name]; ok {
+			return a
+		}
+		a := &DiscoveredAgent{Instance: strings.TrimSuffix(name, "."+mdnsServiceType), LastSeen: now}
+		instances[name] = a
+		return a
+	}
+
+	next := p.AnswerHeader
+	skip := p.SkipAnswer
+	for section := 0; section < 3; section++ {
+		for {
+			rh, err := next()
+			if err != nil {
+				break
+			}
+			name := strings.ToLower(rh.Name.String())
+			ttl := time.Duration(rh.TTL) * time.Second
+			switch rh.Type {
+			case dnsmessage.TypePTR:
+				r, err := p.PTRResource()
+				if err != nil || name != mdnsServiceType {
+					continue
+				}
+				get(strings.ToLower(r.PTR.String())).expires = now.Add(ttl)
+			case dnsmessage.TypeSRV:
+				r, err := p.SRVResource()
+				if err != nil || !strings.HasSuffix(name, "."+mdnsServiceType) {
+					continue
+				}
+				a := get(name)
+				a.Host = r.Target.String()
+				a.Port = int(r.Port)
+				a.expires = now.Add(ttl)
+			case dnsmessage.TypeTXT:
+				r, err := p.TXTResource()
+				if err != nil || !strings.HasSuffix(name, "."+mdnsServiceType) {
+					continue
+				}
+				a := get(name)
+				for _, kv := range r.TXT {
+					key, value, _ := strings.Cut(kv, "=")
+					switch key {
+					case "version":
+						a.Version = value
+					case "auth":
+						a.AuthRequired = value != "none"
+					}
+				}
+			case dnsmessage.TypeA:
+				r, err := p.AResource()
+				if err != nil {
+					continue
+				}
+				hostAddrs[name] = append(hostAddrs[name], net.IP(r.A[:]).String())
+			default:
+				if err := skip(); err != nil {
+					return nil
+				}
+			}
+		}
+		// Answers are followed by authority and additional records
+		switch section {
+		case 0:
+			next, skip = p.AuthorityHeader, p.SkipAuthority
+		case 1:
+			next, skip = p.AdditionalHeader, p.SkipAdditional
+		}
+	}
+
+	agents := make([]*DiscoveredAgent, 0, len(instances))
+	for _, a := range instances {
+		if a.Port == 0 && a.expires.After(now) {
+			continue // PTR without SRV: nothing to connect to yet
+		}
+		a.Addresses = hostAddrs[strings.ToLower(a.Host)]
+		if len(a.Addresses) == 0 && src != nil {
+			a.Addresses = []string{src.IP.String()}
+		}
+		agents = append(agents, a)
+	}
+	return agents
+}
+
+func (d *mdnsDiscovery) expirePeers(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name, peer := range d.peers {
+		if !peer.expires.After(now) {
+			delete(d.peers, name)
+		}
+	}
+}
+
+// Peers returns the agents seen on the network whose announcements haven't expired
+func (d *mdnsDiscovery) Peers(now time.Time) []DiscoveredAgent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	peers := make([]DiscoveredAgent, 0, len(d.peers))
+	for _, peer := range d.peers {
+		if peer.expires.After(now) {
+			peers = append(peers, *peer)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Instance < peers[j].Instance })
+	return peers
+}
+
+// localIPv4Addresses returns the non-loopback IPv4 addresses of the up interfaces
+func localIPv4Addresses() []string {
+	var addrs []string
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ifAddrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range ifAddrs {
+			if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				addrs = append(addrs, ipNet.IP.String())
+			}
+		}
+	}
+	return addrs
+}
+
+// listenLAN serves handler on each local IPv4 address at the agent port, so
+// the advertised addresses are reachable, and returns those it listens on
+func (s *Server) listenLAN(handler http.Handler) []string {
+	lan := s.lanHandler(handler)
+	var addrs []string
+	for _, addr := range localIPv4Addresses() {
+		ln, err := net.Listen("tcp", net.JoinHostPort(addr, strconv.Itoa(s.config.Port)))
+		if err != nil {
+			log.Printf("[Discovery] not listening on %s: %v", addr, err)
+			continue
+		}
+		go func() {
+			if err := http.Serve(ln, lan); err != nil {
+				log.Printf("[Discovery] listener on %s stopped: %v", ln.Addr(), err)
+			}
+		}()
+		log.Printf("[Discovery] Listening on %s for LAN peers", ln.Addr())
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// lanHandler requires the agent token on every request but /health, and
+// refuses them all while no token is configured
+func (s *Server) lanHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" && (s.token() == "" || !s.validateToken(r)) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// handleDiscovery returns this agent's advertisement and the agents found on
+// the local network over mDNS
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, `{"error":"method not allowed"}`, http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	resp := map[string]interface{}{
+		"enabled":     s.discovery != nil,
+		"serviceType": mdnsServiceType,
+		"peers":       []DiscoveredAgent{},
+	}
+	if s.discovery != nil {
+		self := s.discovery.self
+		self.AuthRequired = s.token() != ""
+		self.LastSeen = time.Now()
+		resp["self"] = self
+		resp["peers"] = s.discovery.Peers(time.Now())
+	} else {
+		resp["self"] = DiscoveredAgent{
+			Instance:     "kc-agent",
+			Host:         "127.0.0.1",
+			Addresses:    []string{"127.0.0.1"},
+			Port:         s.config.Port,
+			Version:      Version,
+			AuthRequired: s.token() != "",
+			LastSeen:     time.Now(),
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
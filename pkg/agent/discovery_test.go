@@ -0,0 +1,112 @@
+package agent
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMDNSAnnouncementRoundTrip(t *testing.T) {
+	now := time.Now()
+	peer := newMDNSDiscovery("Alice.Laptop", 8585, []string{"192.168.1.20"}, func() bool { return true })
+	peer.self.Host = "alice.local."
+	peer.self.Addresses = []string{"192.168.1.20"}
+	if peer.self.Instance != "alice-laptop" {
+		t.Errorf("Expected a sanitized instance name, got %q", peer.self.Instance)
+	}
+
+	msg, err := peer.buildAnnouncement(mdnsTTL)
+	if err != nil {
+		t.Fatalf("buildAnnouncement failed: %v", err)
+	}
+
+	local := newMDNSDiscovery("bob", 8585, nil, nil)
+	src := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 20), Port: 5353}
+	local.handlePacket(msg, src, now)
+
+	peers := local.Peers(now)
+	if len(peers) != 1 {
+		t.Fatalf("Expected 1 peer, got %+v", peers)
+	}
+	got := peers[0]
+	if got.Instance != "alice-laptop" || got.Host != "alice.local." || got.Port != 8585 ||
+		!got.AuthRequired || got.Version != Version || len(got.Addresses) != 1 || got.Addresses[0] != "192.168.1.20" {
+		t.Errorf("Unexpected peer: %+v", got)
+	}
+
+	// Our own announcements echo back from the multicast group
+	own, _ := local.buildAnnouncement(mdnsTTL)
+	local.handlePacket(own, src, now)
+	if len(local.Peers(now)) != 1 {
+		t.Error("Expected the agent not to list itself")
+	}
+
+	if len(local.Peers(now.Add(mdnsTTL+time.Second))) != 0 {
+		t.Error("Expected the peer to expire after its TTL")
+	}
+
+	goodbye, _ := peer.buildAnnouncement(0)
+	local.handlePacket(goodbye, src, now)
+	if len(local.Peers(now)) != 0 {
+		t.Error("Expected a goodbye to remove the peer")
+	}
+}
+
+func TestHandleDiscoveryDisabled(t *testing.T) {
+	t.Setenv("KC_AGENT_TOKEN", "")
+	s := &Server{config: Config{Port: 8585}}
+
+	rec := httptest.NewRecorder()
+	s.handleDiscovery(rec, httptest.NewRequest("GET", "/discovery", nil))
+	var resp struct {
+		Enabled bool              `json:"enabled"`
+		Self    DiscoveredAgent   `json:"self"`
+		Peers   []DiscoveredAgent `json:"peers"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid response %s: %v", rec.Body.String(), err)
+	}
+	if resp.Enabled || resp.Self.Port != 8585 || resp.Peers == nil {
+		t.Errorf("Unexpected response: %s", rec.Body.String())
+	}
+}
+
+func TestLANHandlerRequiresToken(t *testing.T) {
+	s := &Server{agentToken: "secret"}
+	h := s.lanHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(path, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		path, token string
+		want        int
+	}{
+		{"/health", "", http.StatusOK},
+		{"/clusters", "", http.StatusUnauthorized},
+		{"/metrics", "wrong", http.StatusUnauthorized},
+		{"/clusters", "secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := serve(tt.path, tt.token); got != tt.want {
+			t.Errorf("%s with token %q: got %d, want %d", tt.path, tt.token, got, tt.want)
+		}
+	}
+
+	// Clearing the token at runtime closes the LAN rather than opening it
+	s.agentToken = ""
+	if got := serve("/clusters", ""); got != http.StatusUnauthorized {
+		t.Errorf("Expected LAN requests to be refused without a token, got %d", got)
+	}
+}
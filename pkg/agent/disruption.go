@@ -0,0 +1,178 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// drainCheckTimeout bounds the PDB lookup done while an AI-proposed drain awaits approval
+const drainCheckTimeout = 10 * time.Second
+
+// handlePDBsHTTP returns PodDisruptionBudgets for a cluster/namespace
+func (s *Server) handlePDBsHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"pdbs": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+	cluster := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	if cluster == "" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"pdbs": []interface{}{}, "error": "cluster parameter required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+	pdbs, err := s.k8sClient.GetPDBs(ctx, cluster, namespace)
+	if err != nil {
+		log.Printf("error fetching pdbs: %v", err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"pdbs": []interface{}{}, "error": "internal server error"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"pdbs": pdbs, "source": "agent"})
+}
+
+// handleDisruptionRisksHTTP returns deployments whose PDB allows no disruptions
+// and deployments without any PDB
+func (s *Server) handleDisruptionRisksHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"risks": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+	cluster := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	if cluster == "" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"risks": []interface{}{}, "error": "cluster parameter required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+	risks, err := s.k8sClient.FindDisruptionRisks(ctx, cluster, namespace)
+	if err != nil {
+		log.Printf("error fetching disruption risks: %v", err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"risks": []interface{}{}, "error": "internal server error"})
+		return
+	}
+	if risks == nil {
+		risks = []k8s.DisruptionRisk{}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"risks": risks, "source": "agent"})
+}
+
+// handleDrainCheck reports whether draining a node would be blocked by PDBs
+func (s *Server) handleDrainCheck(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.k8sClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+	cluster := r.URL.Query().Get("cluster")
+	node := r.URL.Query().Get("node")
+	if cluster == "" || node == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "cluster and node parameters required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+	check, err := s.k8sClient.CheckDrain(ctx, cluster, node)
+	if err != nil {
+		log.Printf("error checking drain of %s/%s: %v", cluster, node, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+		return
+	}
+	json.NewEncoder(w).Encode(check)
+}
+
+// annotateDrain adds the PDBs that would block a proposed "kubectl drain" to
+// its approval reason, so the user sees the hang before approving it
+func (s *Server) annotateDrain(ctx context.Context, cc *ClassifiedCommand) {
+	if s.k8sClient == nil {
+		return
+	}
+	node, cluster := drainTarget(cc.Command)
+	if node == "" {
+		return
+	}
+	if cluster == "" {
+		clusters, err := s.k8sClient.ListClusters(ctx)
+		if err != nil {
+			return
+		}
+		for _, c := range clusters {
+			if c.IsCurrent {
+				cluster = c.Context
+			}
+		}
+		if cluster == "" {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, drainCheckTimeout)
+	defer cancel()
+	check, err := s.k8sClient.CheckDrain(ctx, cluster, node)
+	if err != nil {
+		log.Printf("[CommandApproval] drain check for %s/%s failed: %v", cluster, node, err)
+		return
+	}
+	for _, b := range check.Blockers {
+		cc.Reason += fmt.Sprintf("; PDB %s/%s allows %d disruption(s) but %d of its pods are on %s, so the drain will block",
+			b.Namespace, b.PDB, b.DisruptionsAllowed, len(b.Pods), node)
+	}
+}
+
+// drainTarget returns the node and --context of a kubectl drain command, or "" for other commands
+func drainTarget(command string) (node, cluster string) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	switch filepath.Base(fields[0]) {
+	case "kubectl", "oc", "k":
+	default:
+		return "", ""
+	}
+	args := positionalArgs(fields[1:])
+	if len(args) < 2 || args[0] != "drain" {
+		return "", ""
+	}
+	for i, a := range fields {
+		if strings.HasPrefix(a, "--context=") {
+			cluster = strings.TrimPrefix(a, "--context=")
+		} else if a == "--context" && i+1 < len(fields) {
+			cluster = fields[i+1]
+		}
+	}
+	return args[1], cluster
+}
@@ -0,0 +1,21 @@
+package agent
+
+import "testing"
+
+func TestDrainTarget(t *testing.T) {
+	tests := []struct {
+		command, node, cluster string
+	}{
+		{"kubectl drain node-1 --ignore-daemonsets", "node-1", ""},
+		{"kubectl --context prod drain node-1", "node-1", "prod"},
+		{"oc drain node-2 --context=stage --delete-emptydir-data", "node-2", "stage"},
+		{"kubectl delete pod api", "", ""},
+		{"helm uninstall drain", "", ""},
+	}
+	for _, tt := range tests {
+		node, cluster := drainTarget(tt.command)
+		if node != tt.node || cluster != tt.cluster {
+			t.Errorf("drainTarget(%q) = %q, %q; expected %q, %q", tt.command, node, cluster, tt.node, tt.cluster)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// handleDoctor validates every kubeconfig context and suggests fixes. API
+// servers are probed for reachability unless probe=false.
+func (s *Server) handleDoctor(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	probe := r.URL.Query().Get("probe") != "false"
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, "", k8s.OpHealth, agentDefaultTimeout))
+	defer cancel()
+
+	report, err := s.k8sClient.RunKubeconfigDoctor(ctx, probe)
+	if err != nil {
+		log.Printf("[Doctor] %v", err)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
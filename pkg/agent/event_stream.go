@@ -0,0 +1,290 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fallback transports for the broadcast stream. Corporate proxies often block
+// WebSockets to localhost-forwarded agents, so the same events are offered over
+// Server-Sent Events (/stream) and long-polling (/stream/poll).
+const (
+	eventSubscriberBuffer = 64  // events buffered per subscriber before dropping
+	eventLogSize          = 256 // recent events kept for SSE resume and long-poll
+
+	sseKeepaliveInterval   = 15 * time.Second
+	longPollDefaultTimeout = 25 * time.Second
+	longPollMaxTimeout     = 55 * time.Second
+)
+
+// agentEvent is a broadcast message delivered to non-WebSocket subscribers
+// (gRPC WatchEvents, SSE and long-poll)
+type agentEvent struct {
+	Seq       uint64
+	Type      string
+	Payload   []byte
+	Timestamp time.Time
+}
+
+// PolledEvent is a broadcast event as returned by /stream/poll
+type PolledEvent struct {
+	ID        uint64          `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	Timestamp string          `json:"timestamp"`
+}
+
+// StreamPollResponse is the /stream/poll response. Next is the cursor for the
+// following request; Reset means events were missed and the client should refetch.
+type StreamPollResponse struct {
+	Events []PolledEvent `json:"events"`
+	Next   uint64        `json:"next"`
+	Reset  bool          `json:"reset,omitempty"`
+}
+
+// subscribeEvents registers a channel receiving every broadcast; call the returned func to unsubscribe
+func (s *Server) subscribeEvents() (<-chan agentEvent, func()) {
+	ch := make(chan agentEvent, eventSubscriberBuffer)
+	s.eventSubsMu.Lock()
+	if s.eventSubs == nil {
+		s.eventSubs = make(map[chan agentEvent]struct{})
+	}
+	s.eventSubs[ch] = struct{}{}
+	s.eventSubsMu.Unlock()
+
+	return ch, func() {
+		s.eventSubsMu.Lock()
+		delete(s.eventSubs, ch)
+		s.eventSubsMu.Unlock()
+	}
+}
+
+// publishEvent records a broadcast and hands it to subscribers; slow subscribers
+// miss events rather than blocking WebSocket broadcasts
+func (s *Server) publishEvent(msgType string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	s.eventSubsMu.Lock()
+	defer s.eventSubsMu.Unlock()
+	s.eventSeq++
+	ev := agentEvent{Seq: s.eventSeq, Type: msgType, Payload: data, Timestamp: time.Now()}
+	s.eventLog = append(s.eventLog, ev)
+	if len(s.eventLog) > eventLogSize {
+		s.eventLog = s.eventLog[len(s.eventLog)-eventLogSize:]
+	}
+	for ch := range s.eventSubs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// eventsSince returns the logged events after seq, the latest sequence number,
+// and whether events after seq have already been dropped from the log
+func (s *Server) eventsSince(seq uint64) ([]agentEvent, uint64, bool) {
+	s.eventSubsMu.Lock()
+	defer s.eventSubsMu.Unlock()
+	if seq > s.eventSeq {
+		// Cursor from before an agent restart
+		return nil, s.eventSeq, true
+	}
+	var out []agentEvent
+	for _, ev := range s.eventLog {
+		if ev.Seq > seq {
+			out = append(out, ev)
+		}
+	}
+	missed := len(s.eventLog) > 0 && s.eventLog[0].Seq > seq+1
+	return out, s.eventSeq, missed
+}
+
+// parseEventTypes parses the optional ?types=a,b filter
+func parseEventTypes(r *http.Request) map[string]bool {
+	raw := r.URL.Query().Get("types")
+	if raw == "" {
+		return nil
+	}
+	wanted := map[string]bool{}
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			wanted[t] = true
+		}
+	}
+	return wanted
+}
+
+func filterEvents(events []agentEvent, wanted map[string]bool) []agentEvent {
+	if len(wanted) == 0 {
+		return events
+	}
+	out := events[:0:0]
+	for _, ev := range events {
+		if wanted[ev.Type] {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// writeSSEEvent writes one event in the WebSocket message shape ({"type","payload"})
+func writeSSEEvent(w http.ResponseWriter, ev agentEvent) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: {\"type\":%q,\"payload\":%s}\n\n", ev.Seq, ev.Type, ev.Type, ev.Payload)
+	return err
+}
+
+// handleEventStreamSSE mirrors the WebSocket broadcast stream as Server-Sent Events.
+// Reconnecting clients resume from Last-Event-ID while the events are still logged.
+func (s *Server) handleEventStreamSSE(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// EventSource cannot set headers, so the token usually arrives as ?token=
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	wanted := parseEventTypes(r)
+	events, unsubscribe := s.subscribeEvents()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Replay what the client missed; later events arrive on the subscription
+	var lastSent uint64
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	if seq, err := strconv.ParseUint(lastID, 10, 64); err == nil {
+		missed, _, _ := s.eventsSince(seq)
+		for _, ev := range filterEvents(missed, wanted) {
+			if writeSSEEvent(w, ev) != nil {
+				return
+			}
+			lastSent = ev.Seq
+		}
+	}
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepalive := time.NewTicker(sseKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case ev := <-events:
+			if ev.Seq <= lastSent || (len(wanted) > 0 && !wanted[ev.Type]) {
+				continue
+			}
+			if writeSSEEvent(w, ev) != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// handleEventStreamPoll is the long-poll fallback: it returns the events after
+// ?since= as soon as there are any, or an empty list after ?timeout=. Without
+// ?since= it returns the current cursor immediately.
+func (s *Server) handleEventStreamPoll(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	if q.Get("since") == "" {
+		_, latest, _ := s.eventsSince(0)
+		json.NewEncoder(w).Encode(StreamPollResponse{Events: []PolledEvent{}, Next: latest})
+		return
+	}
+	since, err := strconv.ParseUint(q.Get("since"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid since"})
+		return
+	}
+	timeout := longPollDefaultTimeout
+	if t, err := time.ParseDuration(q.Get("timeout")); err == nil && t > 0 {
+		timeout = min(t, longPollMaxTimeout)
+	}
+	wanted := parseEventTypes(r)
+
+	// Subscribe before reading the log so nothing published in between is lost
+	events, unsubscribe := s.subscribeEvents()
+	defer unsubscribe()
+
+	logged, latest, reset := s.eventsSince(since)
+	found := filterEvents(logged, wanted)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for len(found) == 0 && !reset {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+			json.NewEncoder(w).Encode(StreamPollResponse{Events: []PolledEvent{}, Next: latest})
+			return
+		case ev := <-events:
+			if ev.Seq <= since {
+				continue
+			}
+			latest = max(latest, ev.Seq)
+			if len(wanted) == 0 || wanted[ev.Type] {
+				found = append(found, ev)
+			}
+		}
+	}
+
+	resp := StreamPollResponse{Events: make([]PolledEvent, 0, len(found)), Next: latest, Reset: reset}
+	for _, ev := range found {
+		resp.Events = append(resp.Events, PolledEvent{
+			ID:        ev.Seq,
+			Type:      ev.Type,
+			Payload:   ev.Payload,
+			Timestamp: ev.Timestamp.Format(time.RFC3339),
+		})
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventStreamPoll(t *testing.T) {
+	s := &Server{}
+	s.BroadcastToClients("clusters_updated", map[string]string{"current": "c1"})
+
+	poll := func(query string) StreamPollResponse {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.handleEventStreamPoll(rec, httptest.NewRequest("GET", "/stream/poll"+query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp StreamPollResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// Without a cursor the poll returns immediately with the latest one
+	cursor := poll("").Next
+	if cursor != 1 {
+		t.Fatalf("Expected cursor 1, got %d", cursor)
+	}
+	// Logged events after the cursor are returned without waiting
+	if resp := poll("?since=0"); len(resp.Events) != 1 || resp.Events[0].Type != "clusters_updated" {
+		t.Errorf("Expected the logged event, got %+v", resp)
+	}
+
+	// A waiting poll returns on the next matching broadcast
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.BroadcastToClients("prediction_update", map[string]int{"n": 1})
+		s.BroadcastToClients("device_alert", map[string]string{"node": "n1"})
+	}()
+	resp := poll("?since=1&types=device_alert&timeout=5s")
+	if len(resp.Events) != 1 || string(resp.Events[0].Payload) != `{"node":"n1"}` || resp.Next != 3 {
+		t.Errorf("Expected the filtered device_alert with cursor 3, got %+v", resp)
+	}
+
+	if resp := poll("?since=3&timeout=10ms"); len(resp.Events) != 0 || resp.Next != 3 {
+		t.Errorf("Expected an empty poll after the timeout, got %+v", resp)
+	}
+	// A cursor from before an agent restart tells the client to refetch
+	if resp := poll("?since=99&timeout=10ms"); !resp.Reset {
+		t.Errorf("Expected reset for an unknown cursor, got %+v", resp)
+	}
+}
+
+func TestEventStreamSSE(t *testing.T) {
+	s := &Server{}
+	s.BroadcastToClients("clusters_updated", map[string]string{"current": "c1"})
+	srv := httptest.NewServer(http.HandlerFunc(s.handleEventStreamSSE))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/stream", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("SSE request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected text/event-stream, got %q", ct)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.BroadcastToClients("prediction_update", map[string]int{"n": 1})
+	}()
+
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for len(data) < 2 && scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+			data = append(data, strings.TrimPrefix(line, "data: "))
+		}
+	}
+	want := []string{
+		`{"type":"clusters_updated","payload":{"current":"c1"}}`, // replayed from Last-Event-ID
+		`{"type":"prediction_update","payload":{"n":1}}`,
+	}
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d events, got %v (%v)", len(want), data, scanner.Err())
+	}
+	for i := range want {
+		if data[i] != want[i] {
+			t.Errorf("Expected %s, got %s", want[i], data[i])
+		}
+	}
+}
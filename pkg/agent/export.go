@@ -0,0 +1,281 @@
+package agent
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// exportColumn is one CSV column of an export
+type exportColumn[T any] struct {
+	header string
+	value  func(T) string
+}
+
+// exportWriter streams rows as a CSV or JSON file download. Headers are sent
+// with the first row, so an export that fails before producing anything can
+// still answer with an error status.
+type exportWriter[T any] struct {
+	w       http.ResponseWriter
+	format  string // csv or json
+	name    string
+	columns []exportColumn[T]
+	csv     *csv.Writer
+	started bool
+	rows    int
+}
+
+// exportFormat returns the requested export format, csv by default
+func exportFormat(r *http.Request) (string, bool) {
+	switch format := strings.ToLower(r.URL.Query().Get("format")); format {
+	case "", "csv":
+		return "csv", true
+	case "json":
+		return "json", true
+	}
+	return "", false
+}
+
+func (e *exportWriter[T]) start() {
+	if e.started {
+		return
+	}
+	e.started = true
+	filename := fmt.Sprintf("%s-%s.%s", e.name, time.Now().UTC().Format("20060102-150405"), e.format)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if e.format == "json" {
+		e.w.Header().Set("Content-Type", "application/json")
+		e.w.Write([]byte("[\n"))
+		return
+	}
+	e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	e.csv = csv.NewWriter(e.w)
+	headers := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		headers[i] = c.header
+	}
+	e.csv.Write(headers)
+}
+
+func (e *exportWriter[T]) write(rows []T) error {
+	e.start()
+	for _, row := range rows {
+		if e.format == "json" {
+			data, err := json.Marshal(row)
+			if err != nil {
+				return err
+			}
+			if e.rows > 0 {
+				e.w.Write([]byte(",\n"))
+			}
+			if _, err := e.w.Write(data); err != nil {
+				return err
+			}
+		} else {
+			record := make([]string, len(e.columns))
+			for i, c := range e.columns {
+				record[i] = csvSafe(c.value(row))
+			}
+			if err := e.csv.Write(record); err != nil {
+				return err
+			}
+		}
+		e.rows++
+	}
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (e *exportWriter[T]) finish() {
+	e.start()
+	if e.format == "json" {
+		e.w.Write([]byte("\n]\n"))
+		return
+	}
+	e.csv.Flush()
+}
+
+// csvSafe keeps spreadsheets from evaluating cells as formulas
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// joinLabels renders labels as sorted k=v pairs separated by semicolons
+func joinLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
+
+var podExportColumns = []exportColumn[k8s.PodInfo]{
+	{"cluster", func(p k8s.PodInfo) string { return p.Cluster }},
+	{"namespace", func(p k8s.PodInfo) string { return p.Namespace }},
+	{"name", func(p k8s.PodInfo) string { return p.Name }},
+	{"status", func(p k8s.PodInfo) string { return p.Status }},
+	{"ready", func(p k8s.PodInfo) string { return p.Ready }},
+	{"restarts", func(p k8s.PodInfo) string { return strconv.Itoa(p.Restarts) }},
+	{"age", func(p k8s.PodInfo) string { return p.Age }},
+	{"node", func(p k8s.PodInfo) string { return p.Node }},
+	{"images", func(p k8s.PodInfo) string {
+		images := make([]string, len(p.Containers))
+		for i, c := range p.Containers {
+			images[i] = c.Image
+		}
+		return strings.Join(images, ";")
+	}},
+	{"labels", func(p k8s.PodInfo) string { return joinLabels(p.Labels) }},
+}
+
+var gpuNodeExportColumns = []exportColumn[k8s.GPUNode]{
+	{"cluster", func(n k8s.GPUNode) string { return n.Cluster }},
+	{"name", func(n k8s.GPUNode) string { return n.Name }},
+	{"manufacturer", func(n k8s.GPUNode) string { return n.Manufacturer }},
+	{"gpuType", func(n k8s.GPUNode) string { return n.GPUType }},
+	{"acceleratorType", func(n k8s.GPUNode) string { return string(n.AcceleratorType) }},
+	{"gpuCount", func(n k8s.GPUNode) string { return strconv.Itoa(n.GPUCount) }},
+	{"gpuAllocated", func(n k8s.GPUNode) string { return strconv.Itoa(n.GPUAllocated) }},
+	{"gpuMemoryMB", func(n k8s.GPUNode) string { return strconv.Itoa(n.GPUMemoryMB) }},
+	{"gpuFamily", func(n k8s.GPUNode) string { return n.GPUFamily }},
+	{"cudaDriverVersion", func(n k8s.GPUNode) string { return n.CUDADriverVersion }},
+	{"migCapable", func(n k8s.GPUNode) string { return strconv.FormatBool(n.MIGCapable) }},
+}
+
+// exportClusters returns the requested cluster, or every cluster when none is given
+func (s *Server) exportClusters(ctx context.Context, cluster string) ([]string, error) {
+	if cluster != "" {
+		return []string{cluster}, nil
+	}
+	clusters, err := s.k8sClient.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+// beginExport runs the checks shared by export handlers and returns the format,
+// or "" when a response has already been written
+func (s *Server) beginExport(w http.ResponseWriter, r *http.Request) string {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return ""
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return ""
+	}
+	if s.k8sClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return ""
+	}
+	format, ok := exportFormat(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "format must be csv or json"})
+		return ""
+	}
+	return format
+}
+
+// handlePodsExport streams every pod of a cluster, or of all clusters, as a
+// CSV or JSON download. Pods are listed in pages so large clusters are exported
+// completely without buffering.
+func (s *Server) handlePodsExport(w http.ResponseWriter, r *http.Request) {
+	format := s.beginExport(w, r)
+	if format == "" {
+		return
+	}
+	cluster := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	clusters, err := s.exportClusters(r.Context(), cluster)
+	if err != nil {
+		log.Printf("error listing clusters for pod export: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+		return
+	}
+
+	ew := &exportWriter[k8s.PodInfo]{w: w, format: format, name: "pods", columns: podExportColumns}
+	for _, c := range clusters {
+		ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, c, k8s.OpList, agentCommandTimeout))
+		err := s.k8sClient.EachPodPage(ctx, c, namespace, 0, ew.write)
+		cancel()
+		if err == nil {
+			continue
+		}
+		log.Printf("error exporting pods from %s: %v", c, err)
+		if cluster != "" && !ew.started {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+			return
+		}
+		// Unreachable clusters are left out of all-cluster exports
+	}
+	ew.finish()
+}
+
+// handleGPUNodesExport exports GPU nodes of a cluster, or of all clusters, as CSV or JSON
+func (s *Server) handleGPUNodesExport(w http.ResponseWriter, r *http.Request) {
+	format := s.beginExport(w, r)
+	if format == "" {
+		return
+	}
+	cluster := r.URL.Query().Get("cluster")
+	clusters, err := s.exportClusters(r.Context(), cluster)
+	if err != nil {
+		log.Printf("error listing clusters for gpu node export: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+		return
+	}
+
+	ew := &exportWriter[k8s.GPUNode]{w: w, format: format, name: "gpu-nodes", columns: gpuNodeExportColumns}
+	for _, c := range clusters {
+		ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, c, k8s.OpList, agentDefaultTimeout))
+		nodes, err := s.k8sClient.GetGPUNodes(ctx, c)
+		cancel()
+		if err != nil {
+			log.Printf("error exporting gpu nodes from %s: %v", c, err)
+			if cluster != "" {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+				return
+			}
+			continue
+		}
+		if err := ew.write(nodes); err != nil {
+			log.Printf("error writing gpu node export: %v", err)
+			return
+		}
+	}
+	ew.finish()
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandlePodsExport(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Labels: map[string]string{"app": "api", "tier": "web"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "api:1.0"}, {Name: "proxy", Image: "envoy:1.30"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "=cmd", Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	))
+	s := &Server{k8sClient: m, allowedOrigins: []string{"*"}}
+
+	w := httptest.NewRecorder()
+	s.handlePodsExport(w, httptest.NewRequest("GET", "/pods/export?cluster=c1&format=csv", nil))
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("Expected a CSV download, got %q: %s", ct, w.Body.String())
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, `attachment; filename="pods-`) || !strings.HasSuffix(cd, `.csv"`) {
+		t.Errorf("Unexpected Content-Disposition %q", cd)
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("Expected a header and 2 rows, got %v, %v", records, err)
+	}
+	rows := map[string][]string{}
+	for _, r := range records[1:] {
+		rows[r[2]] = r
+	}
+	if api := rows["api"]; api == nil || api[8] != "api:1.0;envoy:1.30" || api[9] != "app=api;tier=web" {
+		t.Errorf("Unexpected api row %v", api)
+	}
+	if rows["'=cmd"] == nil {
+		t.Errorf("Expected formula-like cells to be escaped, got %v", rows)
+	}
+
+	w = httptest.NewRecorder()
+	s.handlePodsExport(w, httptest.NewRequest("GET", "/pods/export?cluster=c1&format=json", nil))
+	var pods []k8s.PodInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &pods); err != nil || len(pods) != 2 {
+		t.Fatalf("Expected a JSON array of 2 pods, got %v: %s", err, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	s.handlePodsExport(w, httptest.NewRequest("GET", "/pods/export?cluster=c1&format=xlsx", nil))
+	if w.Code != 400 {
+		t.Errorf("Expected 400 for an unknown format, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	s.handlePodsExport(w, httptest.NewRequest("GET", "/pods/export?cluster=missing", nil))
+	if w.Code != 500 {
+		t.Errorf("Expected 500 for an unknown cluster, got %d", w.Code)
+	}
+}
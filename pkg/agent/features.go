@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Features that can be switched off in agent.yaml or KC_FEATURES
+const (
+	FeaturePredictions    = "predictions"
+	FeatureMetricsHistory = "metricsHistory"
+	FeatureDeviceTracker  = "deviceTracker"
+	FeatureReports        = "reports"
+	FeatureAIChat         = "aiChat"
+	FeatureLocalClusters  = "localClusters"
+	FeatureHealthRefresh  = "healthRefresh"
+
+	// FeaturesEnv overrides agent.yaml's features, e.g. "aiChat=false,localClusters=false"
+	FeaturesEnv = "KC_FEATURES"
+)
+
+// featureRegistry lists the known features and what switching them off disables
+var featureRegistry = map[string]string{
+	FeaturePredictions:    "failure prediction worker and /predictions endpoints",
+	FeatureMetricsHistory: "metrics history snapshots and /metrics/history",
+	FeatureDeviceTracker:  "hardware device tracker and /devices endpoints",
+	FeatureReports:        "scheduled reports and /reports endpoints",
+	FeatureAIChat:         "AI chat over the WebSocket and /cancel-chat",
+	FeatureLocalClusters:  "local cluster management (kind, k3d, minikube) and /local-cluster endpoints",
+	FeatureHealthRefresh:  "background re-probing of cluster health before the cached health expires",
+}
+
+// FeatureFlags maps each registered feature to whether it is on
+type FeatureFlags map[string]bool
+
+// newFeatureFlags resolves the feature flags: everything is on unless switched
+// off in agent.yaml, and KC_FEATURES takes precedence over the file
+func newFeatureFlags(file *AgentFile) FeatureFlags {
+	flags := make(FeatureFlags, len(featureRegistry))
+	for name := range featureRegistry {
+		flags[name] = file.FeatureEnabled(name)
+	}
+	if file != nil {
+		for name := range file.Features {
+			if _, ok := featureRegistry[name]; !ok {
+				log.Printf("Warning: ignoring unknown feature %q in agent config", name)
+			}
+		}
+	}
+
+	if env := os.Getenv(FeaturesEnv); env != "" {
+		overrides, err := parseFeatureOverrides(env)
+		if err != nil {
+			log.Printf("Warning: ignoring %s: %v", FeaturesEnv, err)
+		}
+		for name, on := range overrides {
+			flags[name] = on
+		}
+	}
+
+	var disabled []string
+	for name, on := range flags {
+		if !on {
+			disabled = append(disabled, name)
+		}
+	}
+	if len(disabled) > 0 {
+		sort.Strings(disabled)
+		log.Printf("Disabled features: %s", strings.Join(disabled, ", "))
+	}
+	return flags
+}
+
+// parseFeatureOverrides parses KC_FEATURES: comma-separated name=true|false
+// entries. Valid entries are returned even when others are invalid.
+func parseFeatureOverrides(s string) (map[string]bool, error) {
+	overrides := map[string]bool{}
+	var bad []string
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		on, err := strconv.ParseBool(strings.TrimSpace(value))
+		if _, known := featureRegistry[name]; !ok || err != nil || !known {
+			bad = append(bad, entry)
+			continue
+		}
+		overrides[name] = on
+	}
+	if len(bad) > 0 {
+		return overrides, fmt.Errorf("invalid entries %q (use name=true|false with a known feature)", bad)
+	}
+	return overrides, nil
+}
+
+// Enabled reports whether a feature is on; unknown features are on
+func (f FeatureFlags) Enabled(name string) bool {
+	on, ok := f[name]
+	return !ok || on
+}
+
+// requireFeature returns h when the feature is on, otherwise a handler that
+// answers 404 so the frontend can tell a disabled feature from a failure
+func (s *Server) requireFeature(feature string, h http.HandlerFunc) http.HandlerFunc {
+	if s.features.Enabled(feature) {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.setCORSHeaders(w, r)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error":   fmt.Sprintf("feature %s is disabled", feature),
+			"feature": feature,
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewFeatureFlags(t *testing.T) {
+	t.Setenv(FeaturesEnv, "")
+	file := &AgentFile{Features: map[string]bool{FeatureDeviceTracker: false, FeatureAIChat: false, "unknown": false}}
+
+	flags := newFeatureFlags(file)
+	if flags.Enabled(FeatureDeviceTracker) || flags.Enabled(FeatureAIChat) || !flags.Enabled(FeaturePredictions) {
+		t.Errorf("Expected agent.yaml to switch features off, got %v", flags)
+	}
+	if _, ok := flags["unknown"]; ok {
+		t.Error("Expected unknown features to be dropped")
+	}
+	if len(newFeatureFlags(nil)) != len(featureRegistry) {
+		t.Error("Expected every registered feature to be reported")
+	}
+
+	t.Setenv(FeaturesEnv, "aiChat=true, localClusters=false, bogus=false, reports")
+	flags = newFeatureFlags(file)
+	if !flags.Enabled(FeatureAIChat) || flags.Enabled(FeatureLocalClusters) || !flags.Enabled(FeatureReports) {
+		t.Errorf("Expected %s to take precedence over agent.yaml, got %v", FeaturesEnv, flags)
+	}
+	if flags.Enabled(FeatureDeviceTracker) {
+		t.Error("Expected features not named in the env to keep the agent.yaml setting")
+	}
+}
+
+func TestRequireFeature(t *testing.T) {
+	s := &Server{features: FeatureFlags{FeatureReports: false, FeaturePredictions: true}}
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) }
+
+	rec := httptest.NewRecorder()
+	s.requireFeature(FeaturePredictions, ok)(rec, httptest.NewRequest("GET", "/predictions/stats", nil))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("Expected an enabled feature to reach the handler, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.requireFeature(FeatureReports, ok)(rec, httptest.NewRequest("GET", "/reports/schedules", nil))
+	var body map[string]string
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusNotFound || body["feature"] != FeatureReports {
+		t.Errorf("Expected a disabled feature to answer 404, got %d %v", rec.Code, body)
+	}
+}
@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	fleetEventsDefaultBacklog = 50  // recent warnings replayed when a stream opens
+	fleetEventsBuffer         = 256 // warnings buffered per stream before dropping
+)
+
+// handleFleetEventStream serves /events/stream: one Server-Sent Events tail of
+// the warning events of every healthy cluster. ?filter= narrows it with the
+// k8s.EventFilter language (e.g. "cluster=prod-* reason=BackOff kind=Pod") and
+// ?backlog= sets how many recent warnings are replayed first, oldest first.
+// Warnings a slow client cannot keep up with are dropped and counted in a
+// "dropped" event.
+func (s *Server) handleFleetEventStream(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// EventSource cannot set headers, so the token usually arrives as ?token=
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.k8sClient == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	q := r.URL.Query()
+	filter, err := k8s.ParseEventFilter(q.Get("filter"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	backlog := fleetEventsDefaultBacklog
+	if raw := q.Get("backlog"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			backlog = min(n, maxQueryLimit)
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ctx := r.Context()
+	if backlog > 0 {
+		listCtx, cancel := context.WithTimeout(ctx, agentDefaultTimeout)
+		recent, err := s.k8sClient.RecentFleetWarnings(listCtx, filter, backlog)
+		cancel()
+		if err != nil {
+			log.Printf("[FleetEvents] backlog failed: %v", err)
+		}
+		for _, e := range recent {
+			if writeSSEData(w, "warning", e) != nil {
+				return
+			}
+		}
+	}
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	warnings := make(chan k8s.Event, fleetEventsBuffer)
+	var dropped atomic.Int64
+	go s.k8sClient.StreamFleetWarnings(ctx, filter, func(e k8s.Event) {
+		select {
+		case warnings <- e:
+		default:
+			dropped.Add(1)
+		}
+	})
+
+	keepalive := time.NewTicker(sseKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e := <-warnings:
+			if n := dropped.Swap(0); n > 0 {
+				if writeSSEData(w, "dropped", map[string]int64{"count": n}) != nil {
+					return
+				}
+			}
+			if writeSSEData(w, "warning", e) != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEData writes one named Server-Sent Event with a JSON payload
+func writeSSEData(w http.ResponseWriter, event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
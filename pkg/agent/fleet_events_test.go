@@ -0,0 +1,31 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+func TestHandleFleetEventStream(t *testing.T) {
+	k8sClient, _ := k8s.NewMultiClusterClient("")
+	s := &Server{k8sClient: k8sClient, allowedOrigins: []string{"*"}}
+
+	rec := httptest.NewRecorder()
+	s.handleFleetEventStream(rec, httptest.NewRequest("GET", "/events/stream?filter=owner%3Dapi", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "unknown filter field") {
+		t.Errorf("Expected an invalid filter to be rejected, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	rec = httptest.NewRecorder()
+	s.handleFleetEventStream(rec, httptest.NewRequest("GET", "/events/stream?filter=reason%3DBackOff", nil).WithContext(ctx))
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" || !strings.Contains(rec.Body.String(), ": connected") {
+		t.Errorf("Expected an open event stream, got %q %q", ct, rec.Body.String())
+	}
+}
@@ -0,0 +1,181 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// maxSummaryIssues caps the pod issues returned by /summary
+const maxSummaryIssues = 10
+
+// FleetSummary is a fleet-wide rollup computed from cached health checks and the
+// latest metrics snapshot, so it never fans out to the clusters itself
+type FleetSummary struct {
+	Clusters            int                `json:"clusters"`
+	HealthyClusters     int                `json:"healthyClusters"`
+	UnreachableClusters int                `json:"unreachableClusters"`
+	UncheckedClusters   int                `json:"uncheckedClusters"` // no cached health check yet
+	Nodes               int                `json:"nodes"`
+	ReadyNodes          int                `json:"readyNodes"`
+	Pods                int                `json:"pods"`
+	CPU                 ResourceRollup     `json:"cpu"`    // cores
+	Memory              ResourceRollup     `json:"memory"` // GB
+	GPUs                ResourceRollup     `json:"gpus"`
+	Unhealthy           []UnhealthyCluster `json:"unhealthy"`
+	TopIssues           []PodIssueSnapshot `json:"topIssues"`
+	// AsOf is the oldest data point used; GeneratedAt is when the rollup was computed
+	AsOf        string `json:"asOf,omitempty"`
+	GeneratedAt string `json:"generatedAt"`
+}
+
+// ResourceRollup sums capacity and allocation across the fleet
+type ResourceRollup struct {
+	Capacity          float64 `json:"capacity"`
+	Allocated         float64 `json:"allocated"`
+	AllocationPercent float64 `json:"allocationPercent"`
+}
+
+// UnhealthyCluster explains why a cluster is counted as unhealthy
+type UnhealthyCluster struct {
+	Name         string   `json:"name"`
+	Reachable    bool     `json:"reachable"`
+	ErrorType    string   `json:"errorType,omitempty"`
+	ErrorMessage string   `json:"errorMessage,omitempty"`
+	Issues       []string `json:"issues,omitempty"`
+}
+
+func (r *ResourceRollup) add(capacity, allocated float64) {
+	r.Capacity += capacity
+	r.Allocated += allocated
+	if r.Capacity > 0 {
+		r.AllocationPercent = r.Allocated / r.Capacity * 100
+	}
+}
+
+// buildFleetSummary rolls up the cached health of clusters and the latest metrics
+// snapshot (nil when none was captured yet)
+func buildFleetSummary(clusters []k8s.ClusterInfo, health map[string]*k8s.ClusterHealth, snapshot *MetricsSnapshot, now time.Time) FleetSummary {
+	summary := FleetSummary{
+		Clusters:    len(clusters),
+		Unhealthy:   []UnhealthyCluster{},
+		TopIssues:   []PodIssueSnapshot{},
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+	}
+
+	var oldest time.Time
+	observe := func(ts string) {
+		if t, err := time.Parse(time.RFC3339, ts); err == nil && (oldest.IsZero() || t.Before(oldest)) {
+			oldest = t
+		}
+	}
+
+	visible := make(map[string]bool, len(clusters))
+	for _, c := range clusters {
+		visible[c.Name] = true
+		h, ok := health[c.Context]
+		if !ok || h == nil {
+			summary.UncheckedClusters++
+			continue
+		}
+		observe(h.CheckedAt)
+
+		if h.Healthy {
+			summary.HealthyClusters++
+		} else {
+			summary.Unhealthy = append(summary.Unhealthy, UnhealthyCluster{
+				Name:         c.Name,
+				Reachable:    h.Reachable,
+				ErrorType:    h.ErrorType,
+				ErrorMessage: h.ErrorMessage,
+				Issues:       h.Issues,
+			})
+		}
+		if !h.Reachable {
+			summary.UnreachableClusters++
+		}
+		summary.Nodes += h.NodeCount
+		summary.ReadyNodes += h.ReadyNodes
+		summary.Pods += h.PodCount
+		summary.CPU.add(float64(h.CpuCores), h.CpuRequestsCores)
+		summary.Memory.add(h.MemoryGB, h.MemoryRequestsGB)
+	}
+
+	if snapshot != nil {
+		observe(snapshot.Timestamp)
+		for _, g := range snapshot.GPUNodes {
+			if visible[g.Cluster] {
+				summary.GPUs.add(float64(g.GPUTotal), float64(g.GPUAllocated))
+			}
+		}
+		for _, issue := range snapshot.PodIssues {
+			if visible[issue.Cluster] {
+				summary.TopIssues = append(summary.TopIssues, issue)
+			}
+		}
+		sort.SliceStable(summary.TopIssues, func(i, j int) bool {
+			return summary.TopIssues[i].Restarts > summary.TopIssues[j].Restarts
+		})
+		if len(summary.TopIssues) > maxSummaryIssues {
+			summary.TopIssues = summary.TopIssues[:maxSummaryIssues]
+		}
+	}
+
+	sort.Slice(summary.Unhealthy, func(i, j int) bool {
+		return summary.Unhealthy[i].Name < summary.Unhealthy[j].Name
+	})
+	if !oldest.IsZero() {
+		summary.AsOf = oldest.UTC().Format(time.RFC3339)
+	}
+	return summary
+}
+
+// handleSummaryHTTP returns fleet-level rollups from cached data. The cache holds
+// the agent's own view, so impersonated requests are refused rather than shown it.
+func (s *Server) handleSummaryHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if _, impersonated := k8s.ImpersonationFrom(r.Context()); impersonated {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "/summary is built from the agent's cached view and is not available to impersonated requests"})
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "k8s client not initialized"})
+		return
+	}
+
+	clusters, err := s.k8sClient.ListClusters(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "no kubeconfig available"})
+		return
+	}
+	if group := r.URL.Query().Get("group"); group != "" {
+		clusters = k8sClustersInGroup(clusters, group)
+	}
+
+	var snapshot *MetricsSnapshot
+	if s.metricsHistory != nil {
+		if recent := s.metricsHistory.GetRecentSnapshots(1); len(recent) > 0 {
+			snapshot = &recent[len(recent)-1]
+		}
+	}
+
+	json.NewEncoder(w).Encode(buildFleetSummary(clusters, s.k8sClient.GetCachedHealth(), snapshot, time.Now()))
+}
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+func TestBuildFleetSummary(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	clusters := []k8s.ClusterInfo{
+		{Name: "prod", Context: "prod-ctx"},
+		{Name: "edge", Context: "edge-ctx"},
+		{Name: "new", Context: "new-ctx"},
+	}
+	health := map[string]*k8s.ClusterHealth{
+		"prod-ctx": {Healthy: true, Reachable: true, NodeCount: 3, ReadyNodes: 3, PodCount: 40,
+			CpuCores: 12, CpuRequestsCores: 6, MemoryGB: 48, MemoryRequestsGB: 12, CheckedAt: "2026-01-02T14:59:00Z"},
+		"edge-ctx": {Reachable: false, ErrorType: "timeout", CheckedAt: "2026-01-02T14:58:00Z"},
+		// Filtered out of the visible clusters: must not be counted
+		"hidden-ctx": {Healthy: true, NodeCount: 100},
+	}
+	snapshot := &MetricsSnapshot{
+		Timestamp: "2026-01-02T14:50:00Z",
+		GPUNodes: []GPUNodeMetricSnapshot{
+			{Name: "gpu-1", Cluster: "prod", GPUTotal: 8, GPUAllocated: 6},
+			{Name: "gpu-2", Cluster: "hidden", GPUTotal: 8},
+		},
+		PodIssues: []PodIssueSnapshot{
+			{Name: "a", Cluster: "prod", Restarts: 2},
+			{Name: "b", Cluster: "prod", Restarts: 9},
+			{Name: "c", Cluster: "hidden", Restarts: 50},
+		},
+	}
+
+	s := buildFleetSummary(clusters, health, snapshot, now)
+
+	if s.Clusters != 3 || s.HealthyClusters != 1 || s.UnreachableClusters != 1 || s.UncheckedClusters != 1 {
+		t.Errorf("Unexpected cluster counts: %+v", s)
+	}
+	if s.Nodes != 3 || s.Pods != 40 {
+		t.Errorf("Expected 3 nodes and 40 pods, got %d and %d", s.Nodes, s.Pods)
+	}
+	if s.CPU.AllocationPercent != 50 || s.Memory.AllocationPercent != 25 {
+		t.Errorf("Unexpected allocation: cpu %+v memory %+v", s.CPU, s.Memory)
+	}
+	if s.GPUs.Capacity != 8 || s.GPUs.AllocationPercent != 75 {
+		t.Errorf("Expected 6/8 GPUs allocated, got %+v", s.GPUs)
+	}
+	if len(s.Unhealthy) != 1 || s.Unhealthy[0].Name != "edge" || s.Unhealthy[0].ErrorType != "timeout" {
+		t.Errorf("Expected edge to be reported unhealthy, got %+v", s.Unhealthy)
+	}
+	if len(s.TopIssues) != 2 || s.TopIssues[0].Name != "b" {
+		t.Errorf("Expected visible issues ordered by restarts, got %+v", s.TopIssues)
+	}
+	if s.AsOf != "2026-01-02T14:50:00Z" || s.GeneratedAt != "2026-01-02T15:00:00Z" {
+		t.Errorf("Unexpected freshness: asOf %s generatedAt %s", s.AsOf, s.GeneratedAt)
+	}
+}
+
+func TestBuildFleetSummary_Empty(t *testing.T) {
+	s := buildFleetSummary(nil, nil, nil, time.Now())
+	if s.Unhealthy == nil || s.TopIssues == nil || s.AsOf != "" {
+		t.Errorf("Expected empty lists and no asOf without data, got %+v", s)
+	}
+}
+
+func TestSummaryRefusesImpersonation(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+	s := &Server{k8sClient: m}
+	handler := withImpersonation(http.HandlerFunc(s.handleSummaryHTTP))
+
+	r := httptest.NewRequest("GET", "/summary", nil)
+	r.Header.Set(ImpersonateUserHeader, "jane")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an impersonated /summary to be refused, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,26 @@
+package agent
+
+import (
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+// settingsGPUOperatorChecks reads per-stack GPU operator pod checks from the persisted settings
+func settingsGPUOperatorChecks() map[string][]k8s.GPUOperatorPodCheck {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil || all == nil || len(all.GPUOperatorChecks) == 0 {
+		return nil
+	}
+	sets := make(map[string][]k8s.GPUOperatorPodCheck, len(all.GPUOperatorChecks))
+	for stack, checks := range all.GPUOperatorChecks {
+		for _, c := range checks {
+			sets[stack] = append(sets[stack], k8s.GPUOperatorPodCheck{
+				Name:       c.Name,
+				PodNames:   c.PodNames,
+				Namespaces: c.Namespaces,
+				Exclude:    c.Exclude,
+			})
+		}
+	}
+	return sets
+}
@@ -0,0 +1,342 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/kubestellar/console/pkg/agent/rpc/consolev1"
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	grpcWatchDefaultInterval = 30 * time.Second
+	grpcWatchMinInterval     = 5 * time.Second
+)
+
+// grpcService implements consolev1.ConsoleServiceServer on top of the agent's k8s client
+type grpcService struct {
+	consolev1.UnimplementedConsoleServiceServer
+	s *Server
+}
+
+// newGRPCServer creates a gRPC server exposing the ConsoleService, guarded by the agent token
+func (s *Server) newGRPCServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if !s.validateGRPCToken(ctx) {
+				return nil, status.Error(codes.Unauthenticated, "invalid or missing agent token")
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if !s.validateGRPCToken(ss.Context()) {
+				return status.Error(codes.Unauthenticated, "invalid or missing agent token")
+			}
+			return handler(srv, ss)
+		}),
+	)
+	consolev1.RegisterConsoleServiceServer(srv, &grpcService{s: s})
+	return srv
+}
+
+// serveGRPC listens on addr and serves the gRPC API until the process exits
+func (s *Server) serveGRPC(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Printf("Warning: gRPC API disabled, cannot listen on %s: %v", addr, err)
+		return
+	}
+	log.Printf("gRPC: %s", addr)
+	if err := s.newGRPCServer().Serve(lis); err != nil {
+		log.Printf("gRPC server stopped: %v", err)
+	}
+}
+
+// validateGRPCToken checks the "authorization: Bearer <token>" metadata, mirroring validateToken
+func (s *Server) validateGRPCToken(ctx context.Context) bool {
+	agentToken := s.token()
+	if agentToken == "" {
+		return true
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, v := range md.Get("authorization") {
+		if strings.HasPrefix(v, "Bearer ") && strings.TrimPrefix(v, "Bearer ") == agentToken {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *grpcService) k8sClient() (*k8s.MultiClusterClient, error) {
+	if g.s.k8sClient == nil {
+		return nil, status.Error(codes.Unavailable, "k8s client not initialized")
+	}
+	return g.s.k8sClient, nil
+}
+
+// ListClusters returns the kubeconfig contexts known to the agent
+func (g *grpcService) ListClusters(ctx context.Context, _ *consolev1.ListClustersRequest) (*consolev1.ListClustersResponse, error) {
+	client, err := g.k8sClient()
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := client.ListClusters(ctx)
+	if err != nil {
+		log.Printf("[gRPC] error listing clusters: %v", err)
+		return nil, status.Error(codes.Internal, "failed to list clusters")
+	}
+	resp := &consolev1.ListClustersResponse{Clusters: make([]*consolev1.ClusterInfo, 0, len(clusters))}
+	for _, c := range clusters {
+		resp.Clusters = append(resp.Clusters, clusterInfoToProto(c))
+	}
+	return resp, nil
+}
+
+// GetClusterHealth checks a single cluster
+func (g *grpcService) GetClusterHealth(ctx context.Context, req *consolev1.GetClusterHealthRequest) (*consolev1.ClusterHealth, error) {
+	client, err := g.k8sClient()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetCluster() == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster is required")
+	}
+	ctx, cancel := context.WithTimeout(ctx, client.OperationTimeout(req.GetCluster(), k8s.OpHealth, agentCommandTimeout))
+	defer cancel()
+
+	health, err := client.GetClusterHealth(ctx, req.GetCluster())
+	if err != nil {
+		log.Printf("[gRPC] error checking health of %s: %v", req.GetCluster(), err)
+		return nil, status.Error(codes.Internal, "failed to check cluster health")
+	}
+	return clusterHealthToProto(health), nil
+}
+
+// ListPods lists pods in a cluster
+func (g *grpcService) ListPods(ctx context.Context, req *consolev1.ListPodsRequest) (*consolev1.ListPodsResponse, error) {
+	client, err := g.k8sClient()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetCluster() == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster is required")
+	}
+	ctx, cancel := context.WithTimeout(ctx, client.OperationTimeout(req.GetCluster(), k8s.OpList, agentCommandTimeout))
+	defer cancel()
+
+	pods, err := client.GetPods(ctx, req.GetCluster(), req.GetNamespace())
+	if err != nil {
+		log.Printf("[gRPC] error fetching pods: %v", err)
+		return nil, status.Error(codes.Internal, "failed to list pods")
+	}
+	resp := &consolev1.ListPodsResponse{Pods: make([]*consolev1.PodInfo, 0, len(pods))}
+	for _, p := range pods {
+		resp.Pods = append(resp.Pods, podInfoToProto(p))
+	}
+	return resp, nil
+}
+
+// WatchClusterHealth sends each cluster's health, then again whenever it changes
+func (g *grpcService) WatchClusterHealth(req *consolev1.WatchClusterHealthRequest, stream consolev1.ConsoleService_WatchClusterHealthServer) error {
+	client, err := g.k8sClient()
+	if err != nil {
+		return err
+	}
+	interval := grpcWatchDefaultInterval
+	if req.GetIntervalSeconds() > 0 {
+		interval = time.Duration(req.GetIntervalSeconds()) * time.Second
+	}
+	if interval < grpcWatchMinInterval {
+		interval = grpcWatchMinInterval
+	}
+
+	ctx := stream.Context()
+	last := make(map[string]*consolev1.ClusterHealth)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, h := range g.checkHealth(ctx, client, req.GetCluster()) {
+			if prev, ok := last[h.Cluster]; ok && sameHealth(prev, h) {
+				continue
+			}
+			last[h.Cluster] = h
+			if err := stream.Send(h); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHealth checks one cluster, or every cluster in parallel when cluster is empty
+func (g *grpcService) checkHealth(ctx context.Context, client *k8s.MultiClusterClient, cluster string) []*consolev1.ClusterHealth {
+	names := []string{cluster}
+	if cluster == "" {
+		clusters, err := client.ListClusters(ctx)
+		if err != nil {
+			log.Printf("[gRPC] error listing clusters: %v", err)
+			return nil
+		}
+		names = names[:0]
+		for _, c := range clusters {
+			names = append(names, c.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*consolev1.ClusterHealth, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("[gRPC] recovered from panic checking %s: %v", name, r)
+				}
+			}()
+			checkCtx, cancel := context.WithTimeout(ctx, client.OperationTimeout(name, k8s.OpHealth, agentDefaultTimeout))
+			defer cancel()
+			health, err := client.GetClusterHealth(checkCtx, name)
+			if err != nil {
+				results[i] = &consolev1.ClusterHealth{Cluster: name, ErrorType: "unknown", ErrorMessage: err.Error()}
+				return
+			}
+			results[i] = clusterHealthToProto(health)
+		}(i, name)
+	}
+	wg.Wait()
+
+	out := results[:0]
+	for _, h := range results {
+		if h != nil {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// sameHealth compares two health results, ignoring the check timestamps
+func sameHealth(a, b *consolev1.ClusterHealth) bool {
+	a, b = proto.Clone(a).(*consolev1.ClusterHealth), proto.Clone(b).(*consolev1.ClusterHealth)
+	a.CheckedAt, b.CheckedAt = "", ""
+	a.LastSeen, b.LastSeen = "", ""
+	return proto.Equal(a, b)
+}
+
+// WatchEvents streams broadcast events, optionally limited to the requested types
+func (g *grpcService) WatchEvents(req *consolev1.WatchEventsRequest, stream consolev1.ConsoleService_WatchEventsServer) error {
+	wanted := make(map[string]bool, len(req.GetTypes()))
+	for _, t := range req.GetTypes() {
+		wanted[t] = true
+	}
+
+	events, unsubscribe := g.s.subscribeEvents()
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-events:
+			if len(wanted) > 0 && !wanted[ev.Type] {
+				continue
+			}
+			if err := stream.Send(&consolev1.Event{
+				Type:        ev.Type,
+				PayloadJson: ev.Payload,
+				Timestamp:   ev.Timestamp.Format(time.RFC3339),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func clusterInfoToProto(c k8s.ClusterInfo) *consolev1.ClusterInfo {
+	return &consolev1.ClusterInfo{
+		Name:        c.Name,
+		Context:     c.Context,
+		Server:      c.Server,
+		User:        c.User,
+		Namespace:   c.Namespace,
+		AuthMethod:  c.AuthMethod,
+		Healthy:     c.Healthy,
+		Source:      c.Source,
+		NodeCount:   int32(c.NodeCount),
+		PodCount:    int32(c.PodCount),
+		IsCurrent:   c.IsCurrent,
+		Groups:      c.Groups,
+		DisplayName: c.DisplayName,
+		Color:       c.Color,
+		Pinned:      c.Pinned,
+	}
+}
+
+func clusterHealthToProto(h *k8s.ClusterHealth) *consolev1.ClusterHealth {
+	return &consolev1.ClusterHealth{
+		Cluster:               h.Cluster,
+		Healthy:               h.Healthy,
+		Reachable:             h.Reachable,
+		LastSeen:              h.LastSeen,
+		ErrorType:             h.ErrorType,
+		ErrorMessage:          h.ErrorMessage,
+		ApiServer:             h.APIServer,
+		NodeCount:             int32(h.NodeCount),
+		ReadyNodes:            int32(h.ReadyNodes),
+		PodCount:              int32(h.PodCount),
+		CpuCores:              int32(h.CpuCores),
+		MemoryBytes:           h.MemoryBytes,
+		StorageBytes:          h.StorageBytes,
+		CpuRequestsMillicores: h.CpuRequestsMillicores,
+		MemoryRequestsBytes:   h.MemoryRequestsBytes,
+		PvcCount:              int32(h.PVCCount),
+		PvcBoundCount:         int32(h.PVCBoundCount),
+		Issues:                h.Issues,
+		CheckedAt:             h.CheckedAt,
+	}
+}
+
+func podInfoToProto(p k8s.PodInfo) *consolev1.PodInfo {
+	pod := &consolev1.PodInfo{
+		Name:        p.Name,
+		Namespace:   p.Namespace,
+		Cluster:     p.Cluster,
+		Status:      p.Status,
+		Ready:       p.Ready,
+		Restarts:    int32(p.Restarts),
+		Age:         p.Age,
+		Node:        p.Node,
+		Labels:      p.Labels,
+		Annotations: p.Annotations,
+	}
+	for _, c := range p.Containers {
+		pod.Containers = append(pod.Containers, &consolev1.ContainerInfo{
+			Name:         c.Name,
+			Image:        c.Image,
+			Ready:        c.Ready,
+			State:        c.State,
+			Reason:       c.Reason,
+			Message:      c.Message,
+			GpuRequested: int32(c.GPURequested),
+		})
+	}
+	return pod
+}
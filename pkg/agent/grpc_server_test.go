@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/kubestellar/console/pkg/agent/rpc/consolev1"
+	"github.com/kubestellar/console/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// dialTestGRPC serves s over an in-memory listener and returns a connected client
+func dialTestGRPC(t *testing.T, s *Server) consolev1.ConsoleServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := s.newGRPCServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return consolev1.NewConsoleServiceClient(conn)
+}
+
+func TestGRPCListClustersAndPods(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{
+		Contexts: map[string]*api.Context{"c1": {Cluster: "cl1", AuthInfo: "u1"}},
+		Clusters: map[string]*api.Cluster{"cl1": {Server: "https://c1.example.com"}},
+	})
+	m.InjectClient("c1", fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "prod", Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "nginx"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}))
+
+	client := dialTestGRPC(t, &Server{k8sClient: m, agentToken: "secret"})
+
+	if _, err := client.ListClusters(context.Background(), &consolev1.ListClustersRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Expected Unauthenticated without token, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	clusters, err := client.ListClusters(ctx, &consolev1.ListClustersRequest{})
+	if err != nil {
+		t.Fatalf("ListClusters failed: %v", err)
+	}
+	if len(clusters.Clusters) != 1 || clusters.Clusters[0].Name != "c1" || clusters.Clusters[0].Server != "https://c1.example.com" {
+		t.Errorf("Unexpected clusters: %v", clusters.Clusters)
+	}
+
+	pods, err := client.ListPods(ctx, &consolev1.ListPodsRequest{Cluster: "c1", Namespace: "prod"})
+	if err != nil {
+		t.Fatalf("ListPods failed: %v", err)
+	}
+	if len(pods.Pods) != 1 || pods.Pods[0].Labels["app"] != "web" || len(pods.Pods[0].Containers) != 1 {
+		t.Errorf("Unexpected pods: %v", pods.Pods)
+	}
+
+	if _, err := client.ListPods(ctx, &consolev1.ListPodsRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without cluster, got %v", err)
+	}
+}
+
+func TestGRPCWithoutK8sClient(t *testing.T) {
+	client := dialTestGRPC(t, &Server{})
+	if _, err := client.ListClusters(context.Background(), &consolev1.ListClustersRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable without k8s client, got %v", err)
+	}
+}
+
+func TestGRPCWatchEvents(t *testing.T) {
+	s := &Server{}
+	client := dialTestGRPC(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchEvents(ctx, &consolev1.WatchEventsRequest{Types: []string{"clusters_updated"}})
+	if err != nil {
+		t.Fatalf("WatchEvents failed: %v", err)
+	}
+
+	// Broadcast until the stream has subscribed; the filtered type must never arrive
+	go func() {
+		for ctx.Err() == nil {
+			s.BroadcastToClients("prediction_update", map[string]int{"n": 1})
+			s.BroadcastToClients("clusters_updated", map[string]string{"current": "c1"})
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if ev.Type != "clusters_updated" || string(ev.PayloadJson) != `{"current":"c1"}` {
+		t.Errorf("Unexpected event: %v", ev)
+	}
+}
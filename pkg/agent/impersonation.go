@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// Request headers selecting the identity the agent acts as for one request.
+// Groups may be repeated or comma-separated; a service account is "namespace/name".
+const (
+	ImpersonateUserHeader           = "X-Impersonate-User"
+	ImpersonateGroupHeader          = "X-Impersonate-Group"
+	ImpersonateServiceAccountHeader = "X-Impersonate-Service-Account"
+)
+
+// impersonationFromRequest reads the impersonation headers; the zero value means none
+func impersonationFromRequest(r *http.Request) (k8s.Impersonation, error) {
+	var imp k8s.Impersonation
+	if sa := strings.TrimSpace(r.Header.Get(ImpersonateServiceAccountHeader)); sa != "" {
+		namespace, name, ok := strings.Cut(sa, "/")
+		if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+			return k8s.Impersonation{}, fmt.Errorf("%s must be namespace/name", ImpersonateServiceAccountHeader)
+		}
+		if r.Header.Get(ImpersonateUserHeader) != "" {
+			return k8s.Impersonation{}, fmt.Errorf("%s and %s are mutually exclusive", ImpersonateUserHeader, ImpersonateServiceAccountHeader)
+		}
+		imp = k8s.ServiceAccountImpersonation(namespace, name)
+	} else {
+		imp.User = strings.TrimSpace(r.Header.Get(ImpersonateUserHeader))
+	}
+
+	for _, value := range r.Header.Values(ImpersonateGroupHeader) {
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				imp.Groups = append(imp.Groups, group)
+			}
+		}
+	}
+	if imp.User == "" && len(imp.Groups) > 0 {
+		// The API server only impersonates groups together with a user
+		return k8s.Impersonation{}, fmt.Errorf("%s requires %s", ImpersonateGroupHeader, ImpersonateUserHeader)
+	}
+	return imp, nil
+}
+
+// withImpersonation runs requests carrying impersonation headers as that
+// identity. Every impersonated request is logged for auditing.
+func withImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imp, err := impersonationFromRequest(r)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if imp.IsZero() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		log.Printf("[Impersonation] %s %s as user=%q groups=%v", r.Method, r.URL.Path, imp.User, imp.Groups)
+		next.ServeHTTP(w, r.WithContext(k8s.WithImpersonation(r.Context(), imp)))
+	})
+}
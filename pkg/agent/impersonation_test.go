@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+func TestImpersonationFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string][]string
+		want    k8s.Impersonation
+		wantErr bool
+	}{
+		{"none", nil, k8s.Impersonation{}, false},
+		{"user and groups", map[string][]string{
+			ImpersonateUserHeader:  {"alice"},
+			ImpersonateGroupHeader: {"dev, qa", "oncall"},
+		}, k8s.Impersonation{User: "alice", Groups: []string{"dev", "qa", "oncall"}}, false},
+		{"service account", map[string][]string{
+			ImpersonateServiceAccountHeader: {"team-a/deployer"},
+		}, k8s.ServiceAccountImpersonation("team-a", "deployer"), false},
+		{"bad service account", map[string][]string{ImpersonateServiceAccountHeader: {"deployer"}}, k8s.Impersonation{}, true},
+		{"user and service account", map[string][]string{
+			ImpersonateUserHeader:           {"alice"},
+			ImpersonateServiceAccountHeader: {"team-a/deployer"},
+		}, k8s.Impersonation{}, true},
+		{"groups without user", map[string][]string{ImpersonateGroupHeader: {"dev"}}, k8s.Impersonation{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/pods", nil)
+			for k, v := range tt.headers {
+				r.Header[http.CanonicalHeaderKey(k)] = v
+			}
+			got, err := impersonationFromRequest(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if got.String() != tt.want.String() {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWithImpersonation(t *testing.T) {
+	var got k8s.Impersonation
+	var ok bool
+	handler := withImpersonation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok = k8s.ImpersonationFrom(r.Context())
+	}))
+
+	r := httptest.NewRequest("GET", "/pods", nil)
+	r.Header.Set(ImpersonateUserHeader, "alice")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if !ok || got.User != "alice" {
+		t.Errorf("Expected the request to run as alice, got %+v", got)
+	}
+
+	r = httptest.NewRequest("GET", "/pods", nil)
+	r.Header.Set(ImpersonateServiceAccountHeader, "nope")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed header, got %d", w.Code)
+	}
+}
@@ -0,0 +1,398 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Inference serving engines
+const (
+	inferenceEngineVLLM   = "vllm"
+	inferenceEngineTGI    = "tgi"
+	inferenceEngineKServe = "kserve"
+
+	// kserveOwnerLabel marks deployments created for an InferenceService predictor
+	kserveOwnerLabel = "serving.kserve.io/inferenceservice"
+)
+
+// inferenceGPUResources are the accelerator resources counted as GPU usage
+var inferenceGPUResources = []corev1.ResourceName{"nvidia.com/gpu", "amd.com/gpu", "habana.ai/gaudi", "intel.com/gaudi"}
+
+// inferenceQueueQueries are the per-engine Prometheus queries for queue depth and in-flight requests
+var inferenceQueueQueries = map[string]struct{ waiting, running string }{
+	inferenceEngineVLLM: {
+		waiting: "sum by (namespace, pod) (vllm:num_requests_waiting)",
+		running: "sum by (namespace, pod) (vllm:num_requests_running)",
+	},
+	inferenceEngineTGI: {
+		waiting: "sum by (namespace, pod) (tgi_queue_size)",
+		running: "sum by (namespace, pod) (tgi_batch_current_size)",
+	},
+}
+
+// InferenceWorkload is a model-serving workload discovered in a cluster
+type InferenceWorkload struct {
+	Name            string   `json:"name"`
+	Namespace       string   `json:"namespace"`
+	Cluster         string   `json:"cluster"`
+	Kind            string   `json:"kind"`   // Deployment or InferenceService
+	Engine          string   `json:"engine"` // vllm, tgi, kserve
+	Model           string   `json:"model,omitempty"`
+	Replicas        int32    `json:"replicas"`
+	ReadyReplicas   int32    `json:"readyReplicas"`
+	GPUsPerReplica  int64    `json:"gpusPerReplica"`
+	GPUs            int64    `json:"gpus"`
+	Status          string   `json:"status"`
+	URL             string   `json:"url,omitempty"`
+	QueueDepth      *float64 `json:"queueDepth,omitempty"`      // requests waiting (from Prometheus)
+	RunningRequests *float64 `json:"runningRequests,omitempty"` // requests in flight (from Prometheus)
+
+	podPrefix string // pod name prefix used to match metrics
+}
+
+// detectInferenceEngine identifies the serving engine of a pod template, or ""
+func detectInferenceEngine(labels map[string]string, spec corev1.PodSpec) string {
+	switch strings.ToLower(labels["app.kubernetes.io/name"]) {
+	case "vllm":
+		return inferenceEngineVLLM
+	case "tgi", "text-generation-inference":
+		return inferenceEngineTGI
+	}
+	for _, c := range spec.Containers {
+		image := strings.ToLower(c.Image)
+		switch {
+		case strings.Contains(image, "vllm"):
+			return inferenceEngineVLLM
+		case strings.Contains(image, "text-generation-inference"):
+			return inferenceEngineTGI
+		}
+		for _, arg := range append(append([]string{}, c.Command...), c.Args...) {
+			if arg == "vllm" || strings.Contains(arg, "vllm.entrypoints") {
+				return inferenceEngineVLLM
+			}
+			if arg == "text-generation-launcher" {
+				return inferenceEngineTGI
+			}
+		}
+	}
+	return ""
+}
+
+// inferenceModelName extracts the served model from a container's args or env
+func inferenceModelName(engine string, spec corev1.PodSpec) string {
+	flags := []string{"--served-model-name", "--model"}
+	envs := []string{"MODEL_NAME", "MODEL"}
+	if engine == inferenceEngineTGI {
+		flags = []string{"--model-id"}
+		envs = []string{"MODEL_ID"}
+	}
+
+	for _, c := range spec.Containers {
+		args := append(append([]string{}, c.Command...), c.Args...)
+		// Commands are often wrapped in a shell: split "sh -c 'vllm serve ...'"
+		var words []string
+		for _, a := range args {
+			words = append(words, strings.Fields(a)...)
+		}
+		for _, flag := range flags {
+			for i, w := range words {
+				if strings.HasPrefix(w, flag+"=") {
+					return strings.Trim(strings.TrimPrefix(w, flag+"="), `"'`)
+				}
+				if w == flag && i+1 < len(words) {
+					return strings.Trim(words[i+1], `"'`)
+				}
+			}
+		}
+		// vllm serve <model>
+		if engine == inferenceEngineVLLM {
+			for i, w := range words {
+				if w == "serve" && i+1 < len(words) && !strings.HasPrefix(words[i+1], "-") {
+					return strings.Trim(words[i+1], `"'`)
+				}
+			}
+		}
+		for _, name := range envs {
+			for _, e := range c.Env {
+				if e.Name == name && e.Value != "" {
+					return e.Value
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// podGPUs returns the accelerators requested by a pod template
+func podGPUs(spec corev1.PodSpec) int64 {
+	var total int64
+	for _, c := range spec.Containers {
+		for _, name := range inferenceGPUResources {
+			if q, ok := c.Resources.Limits[name]; ok {
+				total += q.Value()
+			} else if q, ok := c.Resources.Requests[name]; ok {
+				total += q.Value()
+			}
+		}
+	}
+	return total
+}
+
+// inferenceFromDeployment returns the workload for a serving deployment, or nil
+func inferenceFromDeployment(cluster string, d *appsv1.Deployment) *InferenceWorkload {
+	if _, ok := d.Labels[kserveOwnerLabel]; ok {
+		return nil // reported through its InferenceService
+	}
+	engine := detectInferenceEngine(d.Labels, d.Spec.Template.Spec)
+	if engine == "" {
+		engine = detectInferenceEngine(d.Spec.Template.Labels, d.Spec.Template.Spec)
+	}
+	if engine == "" {
+		return nil
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	w := &InferenceWorkload{
+		Name:           d.Name,
+		Namespace:      d.Namespace,
+		Cluster:        cluster,
+		Kind:           "Deployment",
+		Engine:         engine,
+		Model:          inferenceModelName(engine, d.Spec.Template.Spec),
+		Replicas:       replicas,
+		ReadyReplicas:  d.Status.ReadyReplicas,
+		GPUsPerReplica: podGPUs(d.Spec.Template.Spec),
+		podPrefix:      d.Name + "-",
+	}
+	w.GPUs = w.GPUsPerReplica * int64(replicas)
+	switch {
+	case replicas == 0:
+		w.Status = "ScaledToZero"
+	case w.ReadyReplicas >= replicas:
+		w.Status = "Ready"
+	case w.ReadyReplicas == 0:
+		w.Status = "NotReady"
+	default:
+		w.Status = "Degraded"
+	}
+	return w
+}
+
+// inferenceFromKServe converts a KServe InferenceService
+func inferenceFromKServe(cluster string, item *unstructured.Unstructured) InferenceWorkload {
+	obj := item.Object
+	w := InferenceWorkload{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   cluster,
+		Kind:      "InferenceService",
+		Engine:    inferenceEngineKServe,
+		URL:       nestedString(obj, "status", "url"),
+		Replicas:  1,
+		podPrefix: item.GetName() + "-predictor-",
+	}
+
+	w.Model = nestedString(obj, "spec", "predictor", "model", "storageUri")
+	if w.Model == "" {
+		w.Model = nestedString(obj, "spec", "predictor", "model", "modelFormat", "name")
+	}
+	if minReplicas, found, _ := unstructured.NestedInt64(obj, "spec", "predictor", "minReplicas"); found {
+		w.Replicas = int32(minReplicas)
+	}
+
+	limits, _, _ := unstructured.NestedStringMap(obj, "spec", "predictor", "model", "resources", "limits")
+	for _, name := range inferenceGPUResources {
+		if v, ok := limits[string(name)]; ok {
+			if q, err := resource.ParseQuantity(v); err == nil {
+				w.GPUsPerReplica += q.Value()
+			}
+		}
+	}
+	w.GPUs = w.GPUsPerReplica * int64(w.Replicas)
+
+	w.Status = "Unknown"
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if !ok || cm["type"] != "Ready" {
+			continue
+		}
+		if cm["status"] == "True" {
+			w.Status = "Ready"
+			w.ReadyReplicas = w.Replicas
+		} else if reason, _ := cm["reason"].(string); reason != "" {
+			w.Status = reason
+		} else {
+			w.Status = "NotReady"
+		}
+	}
+	return w
+}
+
+// listInferenceWorkloads discovers serving workloads in one cluster
+func (s *Server) listInferenceWorkloads(ctx context.Context, cluster, namespace string) ([]InferenceWorkload, error) {
+	deployments, err := s.k8sClient.ListDeploymentObjects(ctx, cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := []InferenceWorkload{}
+	for i := range deployments {
+		if w := inferenceFromDeployment(cluster, &deployments[i]); w != nil {
+			workloads = append(workloads, *w)
+		}
+	}
+
+	// KServe is optional; a missing CRD is not an error
+	if services, err := s.k8sClient.ListInferenceServices(ctx, cluster, namespace); err == nil {
+		for i := range services {
+			workloads = append(workloads, inferenceFromKServe(cluster, &services[i]))
+		}
+	}
+	return workloads, nil
+}
+
+// applyInferenceQueueMetrics sums per-pod samples into the workloads they belong to
+func applyInferenceQueueMetrics(workloads []InferenceWorkload, waiting, running []prometheusSample) {
+	assign := func(samples []prometheusSample, set func(w *InferenceWorkload, v float64)) {
+		for i := range workloads {
+			w := &workloads[i]
+			var total float64
+			matched := false
+			for _, sample := range samples {
+				if sample.Labels["namespace"] == w.Namespace && strings.HasPrefix(sample.Labels["pod"], w.podPrefix) {
+					total += sample.Value
+					matched = true
+				}
+			}
+			if matched {
+				set(w, total)
+			}
+		}
+	}
+	assign(waiting, func(w *InferenceWorkload, v float64) { w.QueueDepth = &v })
+	assign(running, func(w *InferenceWorkload, v float64) { w.RunningRequests = &v })
+}
+
+// enrichInferenceMetrics fills queue depth from the cluster's Prometheus. KServe
+// predictors typically run vLLM or TGI, so all engine queries are tried.
+func (s *Server) enrichInferenceMetrics(ctx context.Context, cluster, namespace, service string, workloads []InferenceWorkload) {
+	if len(workloads) == 0 {
+		return
+	}
+	var waiting, running []prometheusSample
+	for engine, q := range inferenceQueueQueries {
+		if samples, err := s.queryPrometheusVector(ctx, cluster, namespace, service, q.waiting); err == nil {
+			waiting = append(waiting, samples...)
+		} else {
+			log.Printf("[Inference] %s queue query failed on %s: %v", engine, cluster, err)
+		}
+		if samples, err := s.queryPrometheusVector(ctx, cluster, namespace, service, q.running); err == nil {
+			running = append(running, samples...)
+		}
+	}
+	applyInferenceQueueMetrics(workloads, waiting, running)
+}
+
+// handleInference returns model-serving workloads (vLLM, TGI, KServe) for a cluster or all clusters.
+// Queue depth is included when ?prometheus=<namespace> points at a Prometheus service.
+func (s *Server) handleInference(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"workloads": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+
+	cluster := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	promNamespace := r.URL.Query().Get("prometheus")
+	promService := r.URL.Query().Get("prometheusService")
+	if promService == "" {
+		promService = prometheusServiceName
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+
+	collect := func(ctx context.Context, clusterName string) ([]InferenceWorkload, error) {
+		workloads, err := s.listInferenceWorkloads(ctx, clusterName, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if promNamespace != "" {
+			s.enrichInferenceMetrics(ctx, clusterName, promNamespace, promService, workloads)
+		}
+		return workloads, nil
+	}
+
+	all := []InferenceWorkload{}
+
+	if cluster != "" {
+		workloads, err := collect(ctx, cluster)
+		if err != nil {
+			log.Printf("error fetching inference workloads: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"workloads": []interface{}{}, "error": "internal server error"})
+			return
+		}
+		all = workloads
+	} else {
+		clusters, err := s.k8sClient.ListClusters(ctx)
+		if err != nil {
+			log.Printf("error fetching inference workloads: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"workloads": []interface{}{}, "error": "internal server error"})
+			return
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+
+		for _, cl := range clusters {
+			wg.Add(1)
+			go func(clusterName string) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[Inference] recovered from panic for cluster %s: %v", clusterName, r)
+					}
+				}()
+				clusterCtx, clusterCancel := context.WithTimeout(ctx, s.requestTimeout(r, clusterName, k8s.OpList, agentDefaultTimeout))
+				defer clusterCancel()
+				workloads, err := collect(clusterCtx, clusterName)
+				if err == nil && len(workloads) > 0 {
+					mu.Lock()
+					all = append(all, workloads...)
+					mu.Unlock()
+				}
+			}(cl.Name)
+		}
+		wg.Wait()
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"workloads": all, "source": "agent"})
+}
@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestInferenceModelName(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine string
+		c      corev1.Container
+		want   string
+	}{
+		{"vllm serve positional", inferenceEngineVLLM, corev1.Container{Command: []string{"vllm", "serve", "meta-llama/Llama-3-8B"}}, "meta-llama/Llama-3-8B"},
+		{"vllm --model=", inferenceEngineVLLM, corev1.Container{Args: []string{"--model=mistralai/Mistral-7B", "--port=8000"}}, "mistralai/Mistral-7B"},
+		{"served name wins", inferenceEngineVLLM, corev1.Container{Args: []string{"--model", "/models/x", "--served-model-name", "llama"}}, "llama"},
+		{"shell wrapped", inferenceEngineVLLM, corev1.Container{Command: []string{"sh", "-c", "vllm serve Qwen/Qwen2-7B --tensor-parallel-size 2"}}, "Qwen/Qwen2-7B"},
+		{"tgi model-id", inferenceEngineTGI, corev1.Container{Args: []string{"--model-id", "bigscience/bloom"}}, "bigscience/bloom"},
+		{"tgi env", inferenceEngineTGI, corev1.Container{Env: []corev1.EnvVar{{Name: "MODEL_ID", Value: "gpt2"}}}, "gpt2"},
+		{"unknown", inferenceEngineVLLM, corev1.Container{Args: []string{"--port", "8000"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferenceModelName(tt.engine, corev1.PodSpec{Containers: []corev1.Container{tt.c}}); got != tt.want {
+				t.Errorf("inferenceModelName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrometheusVectorAndQueueMetrics(t *testing.T) {
+	body := `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"namespace":"llm","pod":"llama-7c9f-abc"},"value":[1700000000,"3"]},
+		{"metric":{"namespace":"llm","pod":"llama-7c9f-def"},"value":[1700000000,"2"]},
+		{"metric":{"namespace":"other","pod":"llama-1"},"value":[1700000000,"9"]}
+	]}}`
+	samples, err := parsePrometheusVector(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parsePrometheusVector failed: %v", err)
+	}
+	if len(samples) != 3 || samples[0].Value != 3 {
+		t.Fatalf("Unexpected samples: %+v", samples)
+	}
+
+	if _, err := parsePrometheusVector(strings.NewReader(`{"status":"error","error":"bad query"}`)); err == nil {
+		t.Error("Expected error status to be reported")
+	}
+
+	workloads := []InferenceWorkload{
+		{Name: "llama", Namespace: "llm", podPrefix: "llama-"},
+		{Name: "idle", Namespace: "llm", podPrefix: "idle-"},
+	}
+	applyInferenceQueueMetrics(workloads, samples, nil)
+	if workloads[0].QueueDepth == nil || *workloads[0].QueueDepth != 5 {
+		t.Errorf("Expected queue depth 5, got %v", workloads[0].QueueDepth)
+	}
+	if workloads[0].RunningRequests != nil || workloads[1].QueueDepth != nil {
+		t.Errorf("Expected metrics only where samples matched: %+v", workloads)
+	}
+}
+
+func TestHandleInference(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1"}}})
+
+	replicas := int32(2)
+	vllm := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "llm"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Image: "vllm/vllm-openai:v0.6.0",
+				Args:  []string{"--model", "meta-llama/Llama-3-8B"},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("2")},
+				},
+			}}}},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	web := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Image: "nginx"}},
+		}}},
+	}
+	kservePredictor := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "sklearn-predictor", Namespace: "llm", Labels: map[string]string{kserveOwnerLabel: "sklearn"}},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Image: "kserve/vllmserver"}},
+		}}},
+	}
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(vllm, web, kservePredictor))
+
+	isvc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serving.kserve.io/v1beta1",
+		"kind":       "InferenceService",
+		"metadata":   map[string]interface{}{"name": "sklearn", "namespace": "llm"},
+		"spec": map[string]interface{}{
+			"predictor": map[string]interface{}{
+				"minReplicas": int64(3),
+				"model": map[string]interface{}{
+					"modelFormat": map[string]interface{}{"name": "sklearn"},
+					"storageUri":  "gs://models/iris",
+					"resources": map[string]interface{}{
+						"limits": map[string]interface{}{"nvidia.com/gpu": "1"},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"url": "http://sklearn.llm.example.com",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+	m.InjectDynamicClient("c1", fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{k8s.KServeInferenceServiceGVR: "InferenceServiceList"}, isvc))
+
+	s := &Server{k8sClient: m, allowedOrigins: []string{"*"}}
+	w := httptest.NewRecorder()
+	s.handleInference(w, httptest.NewRequest("GET", "/inference?cluster=c1", nil))
+
+	var resp struct {
+		Workloads []InferenceWorkload `json:"workloads"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Workloads) != 2 {
+		t.Fatalf("Expected vLLM deployment and InferenceService, got %+v", resp.Workloads)
+	}
+
+	got := map[string]InferenceWorkload{}
+	for _, wl := range resp.Workloads {
+		got[wl.Name] = wl
+	}
+	if l := got["llama"]; l.Engine != inferenceEngineVLLM || l.Model != "meta-llama/Llama-3-8B" || l.GPUs != 4 || l.Status != "Degraded" {
+		t.Errorf("Unexpected vLLM workload: %+v", l)
+	}
+	if k := got["sklearn"]; k.Engine != inferenceEngineKServe || k.Model != "gs://models/iris" || k.GPUs != 3 || k.Status != "Ready" || k.URL == "" {
+		t.Errorf("Unexpected KServe workload: %+v", k)
+	}
+}
@@ -465,14 +465,12 @@ func (s *Server) handleKagentiSummary(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Count tools
-	if toolList, err := dynClient.Resource(kagentiToolGVR).List(ctx, metav1.ListOptions{}); err == nil {
-		toolCount = len(toolList.Items)
+	// Count tools and cards (metadata only)
+	if n, err := s.k8sClient.CountObjects(ctx, cluster, kagentiToolGVR, ""); err == nil {
+		toolCount = n
 	}
-
-	// Count cards
-	if cardList, err := dynClient.Resource(kagentiCardGVR).List(ctx, metav1.ListOptions{}); err == nil {
-		cardCount = len(cardList.Items)
+	if n, err := s.k8sClient.CountObjects(ctx, cluster, kagentiCardGVR, ""); err == nil {
+		cardCount = n
 	}
 
 	json.NewEncoder(w).Encode(map[string]any{
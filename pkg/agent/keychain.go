@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"errors"
+	"os"
+)
+
+const (
+	// KeyStorageEnv selects where API keys are stored; it takes precedence
+	// over key_storage in ~/.kc/config.yaml
+	KeyStorageEnv = "KC_KEY_STORAGE"
+	// KeyStorageFile keeps API keys in ~/.kc/config.yaml (default)
+	KeyStorageFile = "file"
+	// KeyStorageKeychain keeps API keys in the macOS Keychain, the Linux
+	// Secret Service or the Windows Credential Manager
+	KeyStorageKeychain = "keychain"
+
+	// keychainService is the service (macOS, Linux) or target prefix (Windows)
+	// the agent's secrets are stored under
+	keychainService = "kc-agent"
+)
+
+var (
+	errSecretNotFound      = errors.New("secret not found")
+	errKeychainUnsupported = errors.New("no OS keychain available")
+)
+
+// secretStore keeps provider API keys outside config.yaml
+type secretStore interface {
+	// Name describes the backend, e.g. "macOS Keychain"
+	Name() string
+	// Get returns errSecretNotFound when the provider has no secret
+	Get(provider string) (string, error)
+	Set(provider, secret string) error
+	// Delete succeeds when the provider has no secret
+	Delete(provider string) error
+}
+
+// keyStorage returns the configured key storage, KeyStorageFile or KeyStorageKeychain
+func (c *AgentConfig) keyStorage() string {
+	if v := os.Getenv(KeyStorageEnv); v != "" {
+		return v
+	}
+	if c != nil && c.KeyStorage != "" {
+		return c.KeyStorage
+	}
+	return KeyStorageFile
+}
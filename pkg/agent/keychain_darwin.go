@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// securityItemNotFound is the exit status of security(1) for a missing item
+const securityItemNotFound = 44
+
+// macKeychain stores secrets as generic passwords in the login keychain via security(1)
+type macKeychain struct{}
+
+func newKeychainStore() (secretStore, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil, errKeychainUnsupported
+	}
+	return macKeychain{}, nil
+}
+
+func (macKeychain) Name() string { return "macOS Keychain" }
+
+func (macKeychain) Get(provider string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", provider, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+			return "", errSecretNotFound
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (macKeychain) Set(provider, secret string) error {
+	if strings.ContainsAny(secret, "\r\n") {
+		return errors.New("keychain secrets cannot contain line breaks")
+	}
+	// A trailing -w without a value makes security(1) prompt for the password
+	// (and again to confirm) instead of taking it on argv, where ps shows it.
+	// Without a controlling terminal the prompt reads stdin. -U updates an
+	// existing item.
+	cmd := exec.Command("security", "add-generic-password", "-U", "-s", keychainService, "-a", provider,
+		"-l", keychainService+" "+provider, "-w")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	cmd.Stdin = strings.NewReader(secret + "\n" + secret + "\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("security add-generic-password: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (macKeychain) Delete(provider string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", provider).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceKeychain stores secrets in the Secret Service (GNOME Keyring,
+// KWallet) via secret-tool(1); the secret is passed on stdin
+type secretServiceKeychain struct{}
+
+func newKeychainStore() (secretStore, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, errKeychainUnsupported
+	}
+	return secretServiceKeychain{}, nil
+}
+
+func (secretServiceKeychain) Name() string { return "Secret Service" }
+
+func (secretServiceKeychain) Get(provider string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", keychainService, "account", provider)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// lookup exits 1 without output when nothing matches
+		if len(out) == 0 && stderr.Len() == 0 {
+			return "", errSecretNotFound
+		}
+		return "", fmt.Errorf("secret-tool lookup: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+func (secretServiceKeychain) Set(provider, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label="+keychainService+" "+provider,
+		"service", keychainService, "account", provider)
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret-tool store: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (secretServiceKeychain) Delete(provider string) error {
+	// clear succeeds when nothing matches
+	return exec.Command("secret-tool", "clear", "service", keychainService, "account", provider).Run()
+}
@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !windows
+
+package agent
+
+func newKeychainStore() (secretStore, error) {
+	return nil, errKeychainUnsupported
+}
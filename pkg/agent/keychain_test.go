@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// memorySecretStore is an in-memory OS keychain
+type memorySecretStore map[string]string
+
+func (m memorySecretStore) Name() string { return "test keychain" }
+
+func (m memorySecretStore) Get(provider string) (string, error) {
+	secret, ok := m[provider]
+	if !ok {
+		return "", errSecretNotFound
+	}
+	return secret, nil
+}
+
+func (m memorySecretStore) Set(provider, secret string) error {
+	m[provider] = secret
+	return nil
+}
+
+func (m memorySecretStore) Delete(provider string) error {
+	delete(m, provider)
+	return nil
+}
+
+func TestConfigManagerKeychainMigration(t *testing.T) {
+	t.Setenv(KeyStorageEnv, "")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "key_storage: keychain\nagents:\n  claude:\n    api_key: sk-ant-plain\n    model: sonnet\n"
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store := memorySecretStore{}
+	cm := &ConfigManager{configPath: path, keyValidity: map[string]bool{}, secretStore: store, storeOpened: true}
+	if err := cm.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	if store["claude"] != "sk-ant-plain" {
+		t.Errorf("Expected the key to move to the keychain, got %v", store)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "sk-ant-plain") || !strings.Contains(string(data), "sonnet") {
+		t.Errorf("Expected config.yaml without the key but with the model, got:\n%s", data)
+	}
+	if got := cm.GetAPIKey("claude"); got != "sk-ant-plain" {
+		t.Errorf("Expected the key to be read from the keychain, got %q", got)
+	}
+	if cm.KeySource("claude") != KeyStorageKeychain || cm.KeyStorage() != KeyStorageKeychain {
+		t.Errorf("Unexpected key source %q / storage %q", cm.KeySource("claude"), cm.KeyStorage())
+	}
+
+	if err := cm.SetAPIKey("openai", "sk-new"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if store["openai"] != "sk-new" || strings.Contains(string(data), "sk-new") {
+		t.Errorf("Expected new keys to be stored in the keychain only")
+	}
+
+	if err := cm.RemoveAPIKey("claude"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store["claude"]; ok || cm.HasAPIKey("claude") {
+		t.Error("Expected the key to be removed from the keychain")
+	}
+
+	// File storage ignores the keychain
+	t.Setenv(KeyStorageEnv, KeyStorageFile)
+	if err := cm.SetAPIKey("gemini", "plain"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store["gemini"]; ok || cm.KeySource("gemini") != "config" {
+		t.Error("Expected file storage to keep the key in config.yaml")
+	}
+}
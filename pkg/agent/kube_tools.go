@@ -0,0 +1,390 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	kubeToolTimeout        = 30 * time.Second
+	maxKubeToolIterations  = 8     // max model↔tool round trips per chat
+	maxKubeToolOutputChars = 12000 // truncate tool output sent back to the model
+	defaultToolLogTail     = 100
+	maxToolLogTail         = 1000
+	maxToolEventsPerObject = 10
+)
+
+// KubeToolDefinition describes a read-only cluster tool exposed to API providers
+type KubeToolDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"` // JSON schema for the tool input
+}
+
+// KubeToolCall is a tool invocation requested by a model
+type KubeToolCall struct {
+	ID    string                 `json:"id"`
+	Name  string                 `json:"name"`
+	Input map[string]interface{} `json:"input"`
+}
+
+// KubeToolProvider is an optional interface for API providers that support
+// native function calling with the built-in Kubernetes tools
+type KubeToolProvider interface {
+	AIProvider
+	// ChatWithKubeTools runs a chat loop where the model may call the given tools.
+	// execute is invoked for each tool call and its output is fed back to the model.
+	ChatWithKubeTools(ctx context.Context, req *ChatRequest, tools []KubeToolDefinition, execute func(ctx context.Context, call KubeToolCall) string) (*ChatResponse, error)
+}
+
+// kubeToolSystemPrompt is appended to the system prompt when tools are enabled
+const kubeToolSystemPrompt = `
+
+You have read-only tools for inspecting the user's Kubernetes clusters: list_pods, get_logs, describe_resource and top_nodes.
+Call them whenever the question depends on live cluster state instead of asking the user to run kubectl.
+The "cluster" argument is a kubeconfig context name; call list_pods without arguments to discover clusters if unsure.`
+
+// kubeToolDefinitions are the tools offered to API providers
+var kubeToolDefinitions = []KubeToolDefinition{
+	{
+		Name:        "list_pods",
+		Description: "List pods in a cluster with status, readiness, restarts and node. Omit cluster to list available clusters.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"cluster":   map[string]interface{}{"type": "string", "description": "Kubeconfig context name"},
+				"namespace": map[string]interface{}{"type": "string", "description": "Namespace (empty for all namespaces)"},
+			},
+		},
+	},
+	{
+		Name:        "get_logs",
+		Description: "Fetch recent log lines from a pod container, optionally from the previous crashed instance or filtered by a regular expression.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"cluster":   map[string]interface{}{"type": "string", "description": "Kubeconfig context name"},
+				"namespace": map[string]interface{}{"type": "string", "description": "Pod namespace"},
+				"pod":       map[string]interface{}{"type": "string", "description": "Pod name"},
+				"container": map[string]interface{}{"type": "string", "description": "Container name (optional)"},
+				"tailLines": map[string]interface{}{"type": "integer", "description": "Number of lines from the end (default 100)"},
+				"previous":  map[string]interface{}{"type": "boolean", "description": "Fetch logs of the previous, crashed container instance"},
+				"grep":      map[string]interface{}{"type": "string", "description": "Regular expression; only matching lines are returned (optional)"},
+			},
+			"required": []string{"cluster", "namespace", "pod"},
+		},
+	},
+	{
+		Name:        "describe_resource",
+		Description: "Show the spec, status and recent events of a resource. Supported kinds: pod, deployment, statefulset, daemonset, replicaset, job, service, node, pvc, configmap.",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"cluster":   map[string]interface{}{"type": "string", "description": "Kubeconfig context name"},
+				"kind":      map[string]interface{}{"type": "string", "description": "Resource kind"},
+				"namespace": map[string]interface{}{"type": "string", "description": "Namespace (ignored for nodes)"},
+				"name":      map[string]interface{}{"type": "string", "description": "Resource name"},
+			},
+			"required": []string{"cluster", "kind", "name"},
+		},
+	},
+	{
+		Name:        "top_nodes",
+		Description: "Show CPU and memory usage per node (from metrics-server when available, otherwise pod requests).",
+		Parameters: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"cluster": map[string]interface{}{"type": "string", "description": "Kubeconfig context name"},
+			},
+			"required": []string{"cluster"},
+		},
+	},
+}
+
+// describableKinds maps accepted kind names to their GroupVersionResource
+var describableKinds = map[string]schema.GroupVersionResource{
+	"pod":         {Version: "v1", Resource: "pods"},
+	"service":     {Version: "v1", Resource: "services"},
+	"node":        {Version: "v1", Resource: "nodes"},
+	"pvc":         {Version: "v1", Resource: "persistentvolumeclaims"},
+	"configmap":   {Version: "v1", Resource: "configmaps"},
+	"deployment":  {Group: "apps", Version: "v1", Resource: "deployments"},
+	"statefulset": {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"daemonset":   {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"replicaset":  {Group: "apps", Version: "v1", Resource: "replicasets"},
+	"job":         {Group: "batch", Version: "v1", Resource: "jobs"},
+}
+
+var nodeMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+
+// KubeToolExecutor runs the built-in read-only tools against MultiClusterClient
+type KubeToolExecutor struct {
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewKubeToolExecutor creates a tool executor backed by the given client
+func NewKubeToolExecutor(k8sClient *k8s.MultiClusterClient) *KubeToolExecutor {
+	return &KubeToolExecutor{k8sClient: k8sClient}
+}
+
+// Execute runs a tool call and returns its textual output. Errors are
+// returned as text so the model can react to them.
+func (e *KubeToolExecutor) Execute(ctx context.Context, call KubeToolCall) string {
+	if e.k8sClient == nil {
+		return "error: kubernetes client not initialized"
+	}
+	ctx, cancel := context.WithTimeout(ctx, kubeToolTimeout)
+	defer cancel()
+
+	var out string
+	var err error
+	switch call.Name {
+	case "list_pods":
+		out, err = e.listPods(ctx, call.Input)
+	case "get_logs":
+		out, err = e.getLogs(ctx, call.Input)
+	case "describe_resource":
+		out, err = e.describeResource(ctx, call.Input)
+	case "top_nodes":
+		out, err = e.topNodes(ctx, call.Input)
+	default:
+		err = fmt.Errorf("unknown tool %q", call.Name)
+	}
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return truncateToolOutput(out)
+}
+
+func (e *KubeToolExecutor) listPods(ctx context.Context, input map[string]interface{}) (string, error) {
+	cluster := toolStringArg(input, "cluster")
+	if cluster == "" {
+		clusters, err := e.k8sClient.ListClusters(ctx)
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		sb.WriteString("Available clusters (pass one as \"cluster\"):\n")
+		for _, c := range clusters {
+			sb.WriteString("- " + c.Name + "\n")
+		}
+		return sb.String(), nil
+	}
+
+	pods, err := e.k8sClient.GetPods(ctx, cluster, toolStringArg(input, "namespace"))
+	if err != nil {
+		return "", err
+	}
+	if len(pods) == 0 {
+		return "No pods found", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("NAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE\tNODE\n")
+	for _, p := range pods {
+		fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", p.Namespace, p.Name, p.Ready, p.Status, p.Restarts, p.Age, p.Node)
+	}
+	return sb.String(), nil
+}
+
+func (e *KubeToolExecutor) getLogs(ctx context.Context, input map[string]interface{}) (string, error) {
+	cluster := toolStringArg(input, "cluster")
+	namespace := toolStringArg(input, "namespace")
+	pod := toolStringArg(input, "pod")
+	if cluster == "" || namespace == "" || pod == "" {
+		return "", fmt.Errorf("cluster, namespace and pod are required")
+	}
+
+	grep := toolStringArg(input, "grep")
+	tail := int64(defaultToolLogTail)
+	if grep != "" {
+		// Search the whole log unless a tail is requested; the match limit bounds the output
+		tail = 0
+	}
+	if v, ok := input["tailLines"].(float64); ok && v > 0 {
+		tail = int64(v)
+	}
+	if tail > maxToolLogTail {
+		tail = maxToolLogTail
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.k8sClient.OperationTimeout(cluster, k8s.OpLogs, agentCommandTimeout))
+	defer cancel()
+
+	previous, _ := input["previous"].(bool)
+	result, err := e.k8sClient.GetPodLogsWithOptions(ctx, cluster, namespace, pod, k8s.PodLogOptions{
+		Container: toolStringArg(input, "container"),
+		TailLines: tail,
+		Previous:  previous,
+		Grep:      grep,
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.Logs == "" {
+		return "(no log output)", nil
+	}
+	if result.Truncated {
+		return result.Logs + fmt.Sprintf("(stopped after %d matching lines)\n", result.Matches), nil
+	}
+	return result.Logs, nil
+}
+
+func (e *KubeToolExecutor) describeResource(ctx context.Context, input map[string]interface{}) (string, error) {
+	cluster := toolStringArg(input, "cluster")
+	kind := strings.ToLower(strings.TrimSuffix(toolStringArg(input, "kind"), "s"))
+	name := toolStringArg(input, "name")
+	namespace := toolStringArg(input, "namespace")
+	if cluster == "" || kind == "" || name == "" {
+		return "", fmt.Errorf("cluster, kind and name are required")
+	}
+
+	gvr, ok := describableKinds[kind]
+	if !ok {
+		return "", fmt.Errorf("unsupported kind %q", kind)
+	}
+
+	dynClient, err := e.k8sClient.GetDynamicClient(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	var obj map[string]interface{}
+	if kind == "node" {
+		u, getErr := dynClient.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			return "", getErr
+		}
+		obj = u.Object
+		namespace = ""
+	} else {
+		if namespace == "" {
+			namespace = "default"
+		}
+		u, getErr := dynClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			return "", getErr
+		}
+		obj = u.Object
+	}
+
+	// managedFields is noise for the model and wastes tokens
+	if md, ok := obj["metadata"].(map[string]interface{}); ok {
+		delete(md, "managedFields")
+		if ann, ok := md["annotations"].(map[string]interface{}); ok {
+			delete(ann, "kubectl.kubernetes.io/last-applied-configuration")
+		}
+	}
+
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.Write(data)
+
+	// Append recent events for the object
+	if client, clientErr := e.k8sClient.GetClient(cluster); clientErr == nil {
+		events, evErr := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: "involvedObject.name=" + name,
+		})
+		if evErr == nil && len(events.Items) > 0 {
+			items := events.Items
+			sort.Slice(items, func(i, j int) bool {
+				return items[i].LastTimestamp.After(items[j].LastTimestamp.Time)
+			})
+			if len(items) > maxToolEventsPerObject {
+				items = items[:maxToolEventsPerObject]
+			}
+			sb.WriteString("\n\nEvents:\n")
+			for _, ev := range items {
+				fmt.Fprintf(&sb, "%s\t%s\t%s (x%d)\n", ev.Type, ev.Reason, ev.Message, ev.Count)
+			}
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func (e *KubeToolExecutor) topNodes(ctx context.Context, input map[string]interface{}) (string, error) {
+	cluster := toolStringArg(input, "cluster")
+	if cluster == "" {
+		return "", fmt.Errorf("cluster is required")
+	}
+
+	// Prefer live usage from metrics-server
+	if dynClient, err := e.k8sClient.GetDynamicClient(cluster); err == nil {
+		list, listErr := dynClient.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
+		if listErr == nil && len(list.Items) > 0 {
+			var sb strings.Builder
+			sb.WriteString("NAME\tCPU\tMEMORY\t(source: metrics-server)\n")
+			for _, item := range list.Items {
+				usage, _ := item.Object["usage"].(map[string]interface{})
+				fmt.Fprintf(&sb, "%s\t%v\t%v\n", item.GetName(), usage["cpu"], usage["memory"])
+			}
+			return sb.String(), nil
+		}
+	}
+
+	// Fall back to allocatable capacity vs. pod requests
+	health, err := e.k8sClient.GetClusterHealth(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := e.k8sClient.GetNodes(ctx, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("metrics-server not available; showing capacity and cluster-wide requests\n")
+	fmt.Fprintf(&sb, "Cluster: %d nodes, CPU requested %.1f of %d cores, memory requested %.1f of %.1f GB\n",
+		health.NodeCount, health.CpuRequestsCores, health.CpuCores, health.MemoryRequestsGB, health.MemoryGB)
+	sb.WriteString("NAME\tSTATUS\tCPU CAPACITY\tMEMORY CAPACITY\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\n", n.Name, n.Status, n.CPUCapacity, n.MemoryCapacity)
+	}
+	return sb.String(), nil
+}
+
+// toolStringArg reads a string argument from a tool input map
+func toolStringArg(input map[string]interface{}, key string) string {
+	if input == nil {
+		return ""
+	}
+	v, _ := input[key].(string)
+	return strings.TrimSpace(v)
+}
+
+// truncateToolOutput caps output so large lists don't blow the context window
+func truncateToolOutput(out string) string {
+	if len(out) <= maxKubeToolOutputChars {
+		return out
+	}
+	return out[:maxKubeToolOutputChars] + fmt.Sprintf("\n... (truncated, %d more characters)", len(out)-maxKubeToolOutputChars)
+}
+
+// kubeToolsSystemPrompt returns the system prompt to use for tool-enabled chats
+func kubeToolsSystemPrompt(req *ChatRequest) string {
+	base := req.SystemPrompt
+	if base == "" {
+		base = DefaultSystemPrompt
+	}
+	return base + kubeToolSystemPrompt
+}
+
+// addUsage accumulates token usage across tool-calling round trips
+func addUsage(total *ProviderTokenUsage, in, out int) {
+	total.InputTokens += in
+	total.OutputTokens += out
+	total.TotalTokens = total.InputTokens + total.OutputTokens
+}
@@ -0,0 +1,234 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func newKubeToolTestClient(t *testing.T) *k8s.MultiClusterClient {
+	t.Helper()
+	m, _ := k8s.NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{
+		Contexts: map[string]*api.Context{"c1": {Cluster: "cl1"}},
+		Clusters: map[string]*api.Cluster{"cl1": {Server: "https://c1"}},
+	})
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "app"}}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(pod))
+
+	podObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":          "web-1",
+			"namespace":     "default",
+			"managedFields": []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"spec": map[string]interface{}{"nodeName": "node-1"},
+	}}
+	nodeMetrics := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "NodeMetrics",
+		"metadata":   map[string]interface{}{"name": "node-1"},
+		"usage":      map[string]interface{}{"cpu": "250m", "memory": "1Gi"},
+	}}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Version: "v1", Resource: "pods"}: "PodList",
+			nodeMetricsGVR:                    "NodeMetricsList",
+		}, podObj)
+	// NodeMetrics doesn't map to "nodes" by kind guessing, so create it explicitly
+	if _, err := dyn.Resource(nodeMetricsGVR).Create(context.Background(), nodeMetrics, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("creating node metrics: %v", err)
+	}
+	m.InjectDynamicClient("c1", dyn)
+	return m
+}
+
+func TestKubeToolExecutor(t *testing.T) {
+	e := NewKubeToolExecutor(newKubeToolTestClient(t))
+	ctx := context.Background()
+
+	t.Run("list_pods", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "list_pods", Input: map[string]interface{}{"cluster": "c1"}})
+		if !strings.Contains(out, "web-1") || !strings.Contains(out, "node-1") {
+			t.Errorf("Expected pod listing, got %q", out)
+		}
+	})
+
+	t.Run("list_pods without cluster lists clusters", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "list_pods"})
+		if !strings.Contains(out, "- c1") {
+			t.Errorf("Expected cluster list, got %q", out)
+		}
+	})
+
+	t.Run("get_logs requires pod", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "get_logs", Input: map[string]interface{}{"cluster": "c1"}})
+		if !strings.HasPrefix(out, "error:") {
+			t.Errorf("Expected error, got %q", out)
+		}
+	})
+
+	t.Run("get_logs", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "get_logs", Input: map[string]interface{}{
+			"cluster": "c1", "namespace": "default", "pod": "web-1", "tailLines": float64(5000),
+		}})
+		if strings.HasPrefix(out, "error:") {
+			t.Errorf("Unexpected error: %q", out)
+		}
+	})
+
+	t.Run("describe_resource strips managedFields", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "describe_resource", Input: map[string]interface{}{
+			"cluster": "c1", "kind": "Pods", "namespace": "default", "name": "web-1",
+		}})
+		if !strings.Contains(out, "\"nodeName\": \"node-1\"") {
+			t.Errorf("Expected pod spec in output, got %q", out)
+		}
+		if strings.Contains(out, "managedFields") {
+			t.Error("Expected managedFields to be stripped")
+		}
+	})
+
+	t.Run("describe_resource rejects secrets", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "describe_resource", Input: map[string]interface{}{
+			"cluster": "c1", "kind": "secret", "namespace": "default", "name": "creds",
+		}})
+		if !strings.Contains(out, "unsupported kind") {
+			t.Errorf("Expected unsupported kind error, got %q", out)
+		}
+	})
+
+	t.Run("top_nodes uses metrics-server", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "top_nodes", Input: map[string]interface{}{"cluster": "c1"}})
+		if !strings.Contains(out, "250m") || !strings.Contains(out, "metrics-server") {
+			t.Errorf("Expected node metrics, got %q", out)
+		}
+	})
+
+	t.Run("unknown tool", func(t *testing.T) {
+		out := e.Execute(ctx, KubeToolCall{Name: "delete_everything"})
+		if !strings.Contains(out, "unknown tool") {
+			t.Errorf("Expected unknown tool error, got %q", out)
+		}
+	})
+}
+
+func TestTruncateToolOutput(t *testing.T) {
+	long := strings.Repeat("x", maxKubeToolOutputChars+10)
+	out := truncateToolOutput(long)
+	if !strings.Contains(out, "truncated, 10 more characters") {
+		t.Errorf("Expected truncation marker, got suffix %q", out[len(out)-40:])
+	}
+	if truncateToolOutput("short") != "short" {
+		t.Error("Expected short output to be unchanged")
+	}
+}
+
+func TestClaudeProvider_ChatWithKubeTools(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if _, ok := body["tools"]; !ok {
+			t.Error("Expected tools in request")
+		}
+		calls++
+
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Write([]byte(`{"stop_reason":"tool_use","content":[{"type":"text","text":"Checking pods"},{"type":"tool_use","id":"tu_1","name":"list_pods","input":{"cluster":"c1"}}],"usage":{"input_tokens":10,"output_tokens":5}}`))
+			return
+		}
+		// Second round must carry the tool result back to the model
+		msgs, _ := body["messages"].([]interface{})
+		last, _ := msgs[len(msgs)-1].(map[string]interface{})
+		if last["role"] != "user" {
+			t.Errorf("Expected tool_result message from user, got %v", last["role"])
+		}
+		w.Write([]byte(`{"stop_reason":"end_turn","content":[{"type":"text","text":"All pods are running"}],"usage":{"input_tokens":20,"output_tokens":7}}`))
+	}))
+	defer server.Close()
+
+	oldURL := claudeAPIURL
+	claudeAPIURL = server.URL
+	defer func() { claudeAPIURL = oldURL }()
+
+	os.Setenv("ANTHROPIC_API_KEY", "test-key")
+	defer os.Unsetenv("ANTHROPIC_API_KEY")
+
+	var executed []string
+	p := NewClaudeProvider()
+	resp, err := p.ChatWithKubeTools(context.Background(), &ChatRequest{Prompt: "are my pods ok?"}, kubeToolDefinitions,
+		func(ctx context.Context, call KubeToolCall) string {
+			executed = append(executed, call.Name+":"+toolStringArg(call.Input, "cluster"))
+			return "web-1 Running"
+		})
+	if err != nil {
+		t.Fatalf("ChatWithKubeTools failed: %v", err)
+	}
+	if resp.Content != "All pods are running" {
+		t.Errorf("Unexpected content %q", resp.Content)
+	}
+	if len(executed) != 1 || executed[0] != "list_pods:c1" {
+		t.Errorf("Expected list_pods on c1 to be executed, got %v", executed)
+	}
+	if resp.TokenUsage.TotalTokens != 42 {
+		t.Errorf("Expected usage summed across rounds (42), got %d", resp.TokenUsage.TotalTokens)
+	}
+}
+
+func TestOpenAIProvider_ChatWithKubeTools(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Write([]byte(`{"choices":[{"message":{"content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"top_nodes","arguments":"{\"cluster\":\"c1\"}"}}]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":10,"completion_tokens":5}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"Nodes look fine"},"finish_reason":"stop"}],"usage":{"prompt_tokens":12,"completion_tokens":3}}`))
+	}))
+	defer server.Close()
+
+	oldURL := openAIAPIURL
+	openAIAPIURL = server.URL
+	defer func() { openAIAPIURL = oldURL }()
+
+	os.Setenv("OPENAI_API_KEY", "test-key")
+	defer os.Unsetenv("OPENAI_API_KEY")
+
+	var executed []string
+	p := NewOpenAIProvider()
+	resp, err := p.ChatWithKubeTools(context.Background(), &ChatRequest{Prompt: "top nodes"}, kubeToolDefinitions,
+		func(ctx context.Context, call KubeToolCall) string {
+			executed = append(executed, call.Name)
+			return "node-1 250m"
+		})
+	if err != nil {
+		t.Fatalf("ChatWithKubeTools failed: %v", err)
+	}
+	if resp.Content != "Nodes look fine" || len(executed) != 1 || executed[0] != "top_nodes" {
+		t.Errorf("Unexpected result %q, executed %v", resp.Content, executed)
+	}
+}
@@ -0,0 +1,191 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	kubeconfigBackupDirName = "kubeconfig-backups"
+	// maxKubeconfigBackups is how many kubeconfig backups are kept; older ones are pruned
+	maxKubeconfigBackups = 20
+	// kubeconfigBackupTimeFormat sorts lexically in time order
+	kubeconfigBackupTimeFormat = "20060102T150405.000000000Z"
+	kubeconfigBackupDirMode    = 0700
+	kubeconfigFileMode         = 0600
+)
+
+// KubeconfigBackup describes one saved copy of the kubeconfig
+type KubeconfigBackup struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"` // RFC3339
+	Size      int64  `json:"size"`
+}
+
+// SetBackupDir sets where kubeconfig backups are written
+func (k *KubectlProxy) SetBackupDir(dir string) {
+	k.backupDir = dir
+}
+
+func (k *KubectlProxy) kubeconfigBackupDir() string {
+	if k.backupDir != "" {
+		return k.backupDir
+	}
+	return filepath.Dir(k.kubeconfig)
+}
+
+func (k *KubectlProxy) kubeconfigBackupPrefix() string {
+	return filepath.Base(k.kubeconfig) + ".bak-"
+}
+
+// backupKubeconfig saves a timestamped copy of the kubeconfig before it is
+// modified and prunes old copies. Returns the backup path, or "" when there is
+// no kubeconfig file yet.
+func (k *KubectlProxy) backupKubeconfig() (string, error) {
+	data, err := os.ReadFile(k.kubeconfig)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read kubeconfig for backup: %w", err)
+	}
+	dir := k.kubeconfigBackupDir()
+	if err := os.MkdirAll(dir, kubeconfigBackupDirMode); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	backupPath := filepath.Join(dir, k.kubeconfigBackupPrefix()+time.Now().UTC().Format(kubeconfigBackupTimeFormat))
+	if err := os.WriteFile(backupPath, data, kubeconfigFileMode); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	k.pruneKubeconfigBackups()
+	return backupPath, nil
+}
+
+// kubeconfigBackupPaths returns the backup files, oldest first
+func (k *KubectlProxy) kubeconfigBackupPaths() []string {
+	paths, _ := filepath.Glob(filepath.Join(k.kubeconfigBackupDir(), k.kubeconfigBackupPrefix()+"*"))
+	sort.Strings(paths)
+	return paths
+}
+
+func (k *KubectlProxy) pruneKubeconfigBackups() {
+	paths := k.kubeconfigBackupPaths()
+	for len(paths) > maxKubeconfigBackups {
+		if err := os.Remove(paths[0]); err != nil && !os.IsNotExist(err) {
+			log.Printf("[KubeconfigBackup] failed to prune %s: %v", paths[0], err)
+		}
+		paths = paths[1:]
+	}
+}
+
+// ListKubeconfigBackups returns the saved kubeconfig backups, newest first
+func (k *KubectlProxy) ListKubeconfigBackups() []KubeconfigBackup {
+	paths := k.kubeconfigBackupPaths()
+	backups := make([]KubeconfigBackup, 0, len(paths))
+	for i := len(paths) - 1; i >= 0; i-- {
+		info, err := os.Stat(paths[i])
+		if err != nil {
+			continue
+		}
+		b := KubeconfigBackup{Name: filepath.Base(paths[i]), Size: info.Size()}
+		stamp := strings.TrimPrefix(b.Name, k.kubeconfigBackupPrefix())
+		if t, err := time.Parse(kubeconfigBackupTimeFormat, stamp); err == nil {
+			b.CreatedAt = t.Format(time.RFC3339)
+		} else {
+			b.CreatedAt = info.ModTime().UTC().Format(time.RFC3339)
+		}
+		backups = append(backups, b)
+	}
+	return backups
+}
+
+// UndoLastChange restores the kubeconfig from the newest backup and removes
+// that backup, so repeated calls step further back. Returns the restored
+// backup name, or "" when there is nothing to undo.
+func (k *KubectlProxy) UndoLastChange() (string, error) {
+	paths := k.kubeconfigBackupPaths()
+	if len(paths) == 0 {
+		return "", nil
+	}
+	latest := paths[len(paths)-1]
+	data, err := os.ReadFile(latest)
+	if err != nil {
+		return "", fmt.Errorf("failed to read backup: %w", err)
+	}
+	if err := os.WriteFile(k.kubeconfig, data, kubeconfigFileMode); err != nil {
+		return "", fmt.Errorf("failed to restore kubeconfig: %w", err)
+	}
+	if err := os.Remove(latest); err != nil {
+		log.Printf("[KubeconfigBackup] failed to remove restored backup %s: %v", latest, err)
+	}
+	k.Reload()
+	return filepath.Base(latest), nil
+}
+
+// handleUndo lists kubeconfig backups (GET) or restores the kubeconfig from the
+// newest backup, undoing the last rename, import, add or removal (POST)
+func (s *Server) handleUndo(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.kubectl == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "kubeconfig not available"})
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		json.NewEncoder(w).Encode(map[string]interface{}{"backups": s.kubectl.ListKubeconfigBackups()})
+
+	case "POST":
+		restored, err := s.kubectl.UndoLastChange()
+		if err != nil {
+			log.Printf("[KubeconfigBackup] undo failed: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to restore kubeconfig"})
+			return
+		}
+		if restored == "" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "no kubeconfig backups to restore"})
+			return
+		}
+		// The file watcher picks this up too; reload now so the next request sees it
+		if s.k8sClient != nil {
+			if err := s.k8sClient.Reload(); err != nil {
+				log.Printf("[KubeconfigBackup] reload after undo failed: %v", err)
+			}
+		}
+		s.responseCache.clear()
+		log.Printf("[KubeconfigBackup] restored kubeconfig from %s", restored)
+		clusters, current := s.listContexts()
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"restored":  restored,
+			"remaining": len(s.kubectl.ListKubeconfigBackups()),
+			"clusters":  clusters,
+			"current":   current,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
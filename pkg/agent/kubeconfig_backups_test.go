@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKubectlProxy_BackupRetention(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfigPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeconfigPath, []byte(sampleKubeconfig("ctx1", "c1", "u1", "https://c1.example.com")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := NewKubectlProxy(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("NewKubectlProxy failed: %v", err)
+	}
+	backupDir := filepath.Join(dir, kubeconfigBackupDirName)
+	proxy.SetBackupDir(backupDir)
+
+	for i := 0; i < maxKubeconfigBackups+5; i++ {
+		if _, err := proxy.backupKubeconfig(); err != nil {
+			t.Fatalf("backupKubeconfig failed: %v", err)
+		}
+	}
+	backups := proxy.ListKubeconfigBackups()
+	if len(backups) != maxKubeconfigBackups {
+		t.Fatalf("Expected %d backups after pruning, got %d", maxKubeconfigBackups, len(backups))
+	}
+	if backups[0].Name <= backups[len(backups)-1].Name {
+		t.Errorf("Expected newest backup first, got %s before %s", backups[0].Name, backups[len(backups)-1].Name)
+	}
+	if info, err := os.Stat(backupDir); err != nil || info.Mode().Perm() != kubeconfigBackupDirMode {
+		t.Errorf("Expected backup dir with mode %o, got %v", kubeconfigBackupDirMode, err)
+	}
+}
+
+func TestHandleUndo(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfigPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeconfigPath, []byte(sampleKubeconfig("ctx1", "c1", "u1", "https://c1.example.com")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := NewKubectlProxy(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("NewKubectlProxy failed: %v", err)
+	}
+	proxy.SetBackupDir(filepath.Join(dir, kubeconfigBackupDirName))
+	s := &Server{kubectl: proxy, allowedOrigins: []string{"*"}}
+
+	// Nothing to undo yet
+	w := httptest.NewRecorder()
+	s.handleUndo(w, httptest.NewRequest("POST", "/undo", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 with no backups, got %d", w.Code)
+	}
+
+	if _, _, err := proxy.ImportKubeconfig(sampleKubeconfig("ctx2", "c2", "u2", "https://c2.example.com")); err != nil {
+		t.Fatalf("ImportKubeconfig failed: %v", err)
+	}
+	if _, ok := proxy.config.Contexts["ctx2"]; !ok {
+		t.Fatal("ctx2 not imported")
+	}
+
+	w = httptest.NewRecorder()
+	s.handleUndo(w, httptest.NewRequest("GET", "/undo", nil))
+	var list struct {
+		Backups []KubeconfigBackup `json:"backups"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Backups) != 1 || list.Backups[0].CreatedAt == "" {
+		t.Fatalf("Expected one backup, got %+v", list.Backups)
+	}
+
+	w = httptest.NewRecorder()
+	s.handleUndo(w, httptest.NewRequest("POST", "/undo", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Restored  string `json:"restored"`
+		Remaining int    `json:"remaining"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Restored != list.Backups[0].Name || resp.Remaining != 0 {
+		t.Errorf("Unexpected undo response: %+v", resp)
+	}
+	if _, ok := proxy.config.Contexts["ctx2"]; ok {
+		t.Error("ctx2 should be gone after undo")
+	}
+	if _, ok := proxy.config.Contexts["ctx1"]; !ok {
+		t.Error("ctx1 should be restored after undo")
+	}
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"os"
@@ -23,16 +24,16 @@ var execCommand = exec.Command
 type KubectlProxy struct {
 	kubeconfig string
 	config     *api.Config
+	// backupDir holds kubeconfig backups taken before each mutation; empty
+	// keeps them next to the kubeconfig
+	backupDir string
+	// command builds the kubectl process; nil uses execCommand. Tests set it
+	// per proxy so handlers still running can't race a swapped package var.
+	command func(name string, arg ...string) *exec.Cmd
 }
 
 func NewKubectlProxy(kubeconfig string) (*KubectlProxy, error) {
-	if kubeconfig == "" {
-		kubeconfig = os.Getenv("KUBECONFIG")
-	}
-	if kubeconfig == "" {
-		home, _ := os.UserHomeDir()
-		kubeconfig = filepath.Join(home, ".kube", "config")
-	}
+	kubeconfig = resolveKubeconfigPath(kubeconfig)
 
 	config, err := clientcmd.LoadFromFile(kubeconfig)
 	if err != nil {
@@ -62,13 +63,18 @@ func (k *KubectlProxy) ListContexts() ([]protocol.ClusterInfo, string) {
 	return clusters, current
 }
 
-func (k *KubectlProxy) Execute(context, namespace string, args []string) protocol.KubectlResponse {
+func (k *KubectlProxy) Execute(kubeContext, namespace string, args []string) protocol.KubectlResponse {
+	return k.ExecuteContext(context.Background(), kubeContext, namespace, args)
+}
+
+// ExecuteContext runs kubectl, killing it when ctx is cancelled
+func (k *KubectlProxy) ExecuteContext(ctx context.Context, kubeContext, namespace string, args []string) protocol.KubectlResponse {
 	cmdArgs := []string{}
 	if k.kubeconfig != "" {
 		cmdArgs = append(cmdArgs, "--kubeconfig", k.kubeconfig)
 	}
-	if context != "" {
-		cmdArgs = append(cmdArgs, "--context", context)
+	if kubeContext != "" {
+		cmdArgs = append(cmdArgs, "--context", kubeContext)
 	}
 	if namespace != "" {
 		cmdArgs = append(cmdArgs, "-n", namespace)
@@ -79,12 +85,27 @@ func (k *KubectlProxy) Execute(context, namespace string, args []string) protoco
 		return protocol.KubectlResponse{ExitCode: 1, Error: "Disallowed kubectl command"}
 	}
 
-	cmd := execCommand("kubectl", cmdArgs...)
+	if ctx.Err() != nil {
+		return protocol.KubectlResponse{ExitCode: 1, Error: "cancelled"}
+	}
+	newCmd := k.command
+	if newCmd == nil {
+		newCmd = execCommand
+	}
+	cmd := newCmd("kubectl", cmdArgs...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := cmd.Start()
+	if err == nil {
+		stop := context.AfterFunc(ctx, func() { cmd.Process.Kill() })
+		err = cmd.Wait()
+		stop()
+	}
+	if ctx.Err() != nil {
+		return protocol.KubectlResponse{ExitCode: 1, Error: "cancelled"}
+	}
 	exitCode := 0
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -256,6 +277,18 @@ func (k *KubectlProxy) GetCurrentContext() string { return k.config.CurrentConte
 // GetKubeconfigPath returns the path to the kubeconfig file
 func (k *KubectlProxy) GetKubeconfigPath() string { return k.kubeconfig }
 
+// resolveKubeconfigPath returns path, defaulting to $KUBECONFIG or ~/.kube/config
+func resolveKubeconfigPath(path string) string {
+	if path == "" {
+		path = os.Getenv("KUBECONFIG")
+	}
+	if path == "" {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, ".kube", "config")
+	}
+	return path
+}
+
 // Reload reloads the kubeconfig from disk
 func (k *KubectlProxy) Reload() {
 	config, err := clientcmd.LoadFromFile(k.kubeconfig)
@@ -264,8 +297,23 @@ func (k *KubectlProxy) Reload() {
 	}
 }
 
+// SetKubeconfig switches kubectl commands to another kubeconfig file
+func (k *KubectlProxy) SetKubeconfig(path string) error {
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return err
+	}
+	k.kubeconfig = path
+	k.config = config
+	return nil
+}
+
 // RenameContext renames a kubeconfig context
 func (k *KubectlProxy) RenameContext(oldName, newName string) error {
+	if _, err := k.backupKubeconfig(); err != nil {
+		return err
+	}
+
 	cmdArgs := []string{"config", "rename-context", oldName, newName}
 	if k.kubeconfig != "" {
 		cmdArgs = append([]string{"--kubeconfig", k.kubeconfig}, cmdArgs...)
@@ -340,15 +388,8 @@ func (k *KubectlProxy) ImportKubeconfig(yamlContent string) (added []string, ski
 	}
 
 	// Backup existing kubeconfig if the file exists
-	if _, statErr := os.Stat(k.kubeconfig); statErr == nil {
-		backupPath := fmt.Sprintf("%s.bak-%d", k.kubeconfig, time.Now().Unix())
-		data, readErr := os.ReadFile(k.kubeconfig)
-		if readErr != nil {
-			return nil, nil, fmt.Errorf("failed to read kubeconfig for backup: %w", readErr)
-		}
-		if writeErr := os.WriteFile(backupPath, data, 0600); writeErr != nil {
-			return nil, nil, fmt.Errorf("failed to write backup: %w", writeErr)
-		}
+	if _, err := k.backupKubeconfig(); err != nil {
+		return nil, nil, err
 	}
 
 	// Initialise maps if they are nil (empty starting config)
@@ -395,6 +436,58 @@ func (k *KubectlProxy) ImportKubeconfig(yamlContent string) (added []string, ski
 	return added, skipped, nil
 }
 
+// RemoveContexts deletes contexts from the kubeconfig after backing it up, along
+// with their clusters and users when no remaining context references them. Unknown names are
+// ignored. Returns the removed contexts and the backup path.
+func (k *KubectlProxy) RemoveContexts(names []string) (removed []string, backupPath string, err error) {
+	for _, name := range names {
+		if _, ok := k.config.Contexts[name]; ok {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, "", nil
+	}
+
+	backupPath, err = k.backupKubeconfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var clusters, users []string
+	for _, name := range removed {
+		clusters = append(clusters, k.config.Contexts[name].Cluster)
+		users = append(users, k.config.Contexts[name].AuthInfo)
+		delete(k.config.Contexts, name)
+		if k.config.CurrentContext == name {
+			k.config.CurrentContext = ""
+		}
+	}
+	usedClusters := make(map[string]bool)
+	usedUsers := make(map[string]bool)
+	for _, ctx := range k.config.Contexts {
+		usedClusters[ctx.Cluster] = true
+		usedUsers[ctx.AuthInfo] = true
+	}
+	for _, name := range clusters {
+		if !usedClusters[name] {
+			delete(k.config.Clusters, name)
+		}
+	}
+	for _, name := range users {
+		if !usedUsers[name] {
+			delete(k.config.AuthInfos, name)
+		}
+	}
+
+	if writeErr := clientcmd.WriteToFile(*k.config, k.kubeconfig); writeErr != nil {
+		return nil, "", fmt.Errorf("failed to write kubeconfig: %w", writeErr)
+	}
+	k.Reload()
+
+	return removed, backupPath, nil
+}
+
 // AddClusterRequest describes the form fields for adding a cluster.
 type AddClusterRequest struct {
 	ContextName   string `json:"contextName"`
@@ -495,15 +588,8 @@ func (k *KubectlProxy) AddCluster(req AddClusterRequest) error {
 	}
 
 	// Backup existing kubeconfig if the file exists
-	if _, statErr := os.Stat(k.kubeconfig); statErr == nil {
-		backupPath := fmt.Sprintf("%s.bak-%d", k.kubeconfig, time.Now().Unix())
-		data, readErr := os.ReadFile(k.kubeconfig)
-		if readErr != nil {
-			return fmt.Errorf("failed to read kubeconfig for backup: %w", readErr)
-		}
-		if writeErr := os.WriteFile(backupPath, data, 0600); writeErr != nil {
-			return fmt.Errorf("failed to write backup: %w", writeErr)
-		}
+	if _, err := k.backupKubeconfig(); err != nil {
+		return err
 	}
 
 	// Initialise maps if nil
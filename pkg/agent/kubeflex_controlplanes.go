@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	// controlPlaneRefreshInterval is how often hosted KubeFlex control planes are rediscovered
+	controlPlaneRefreshInterval = 5 * time.Minute
+	// controlPlaneRefreshTimeout bounds one discovery pass across all clusters
+	controlPlaneRefreshTimeout = 30 * time.Second
+)
+
+// refreshControlPlanes rediscovers KubeFlex control planes and tells clients
+// when the set of hosted control plane contexts changed
+func (s *Server) refreshControlPlanes(ctx context.Context, previous map[string]bool) (map[string]bool, error) {
+	planes, err := s.k8sClient.RefreshControlPlanes(ctx)
+	if err != nil {
+		return previous, err
+	}
+	contexts := make(map[string]bool)
+	for _, cp := range planes {
+		if cp.Context != "" {
+			contexts[cp.Context] = true
+		}
+	}
+	if !sameContextSet(previous, contexts) {
+		s.responseCache.clear()
+		s.BroadcastToClients("controlplanes_updated", map[string]interface{}{
+			"controlPlanes": planes,
+		})
+	}
+	return contexts, nil
+}
+
+func sameContextSet(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name := range a {
+		if !b[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// watchControlPlanes periodically maps KubeFlex control planes into clusters
+func (s *Server) watchControlPlanes() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[ControlPlanes] recovered from panic: %v", r)
+		}
+	}()
+
+	var known map[string]bool
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), controlPlaneRefreshTimeout)
+		defer cancel()
+		var err error
+		if known, err = s.refreshControlPlanes(ctx, known); err != nil {
+			log.Printf("[ControlPlanes] discovery failed: %v", err)
+		}
+	}
+	refresh()
+
+	ticker := time.NewTicker(controlPlaneRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		refresh()
+	}
+}
+
+// handleControlPlanes lists the KubeFlex control planes of every cluster with
+// their type, hosting namespace, readiness and the context they are mapped to
+func (s *Server) handleControlPlanes(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"controlPlanes": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, "", k8s.OpList, controlPlaneRefreshTimeout))
+	defer cancel()
+
+	planes, err := s.k8sClient.RefreshControlPlanes(ctx)
+	if err != nil {
+		log.Printf("error listing control planes: %v", err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"controlPlanes": []interface{}{}, "error": "internal server error"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"controlPlanes": planes,
+		"count":         len(planes),
+	})
+}
@@ -0,0 +1,504 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// defaultKubeStellarVersion is the KubeStellar release installed when none is requested
+	defaultKubeStellarVersion = "0.28.0"
+	kubestellarCoreChart      = "oci://ghcr.io/kubestellar/kubestellar/core-chart"
+	kubestellarCoreRelease    = "ks-core"
+	// kubestellarBootstrapTimeout bounds the whole workflow, including local cluster creation
+	kubestellarBootstrapTimeout = 30 * time.Minute
+	kubestellarHelmTimeout      = "15m"
+)
+
+// bootstrapPollInterval is how often control planes are checked for readiness
+var bootstrapPollInterval = 5 * time.Second
+
+var errBootstrapRunning = errors.New("a KubeStellar bootstrap is already running")
+
+// Bootstrap step and run states
+const (
+	bootstrapPending   = "pending"
+	bootstrapRunning   = "running"
+	bootstrapDone      = "done"
+	bootstrapSkipped   = "skipped"
+	bootstrapFailed    = "failed"
+	bootstrapSucceeded = "succeeded"
+)
+
+// KubeStellarBootstrapRequest selects where and how KubeStellar is installed
+type KubeStellarBootstrapRequest struct {
+	// Cluster is the kubeconfig context of the hosting cluster; ignored when
+	// LocalCluster is set
+	Cluster string `json:"cluster,omitempty"`
+	// LocalCluster creates a fresh hosting cluster from the kubestellar-dev template
+	LocalCluster *struct {
+		Tool string `json:"tool"`
+		Name string `json:"name"`
+	} `json:"localCluster,omitempty"`
+	Version string   `json:"version,omitempty"`
+	ITSes   []string `json:"itses,omitempty"` // inventory and transport spaces, default its1
+	WDSes   []string `json:"wdses,omitempty"` // workload description spaces, default wds1
+}
+
+// BootstrapStep is one stage of the bootstrap workflow
+type BootstrapStep struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // pending, running, done, skipped, failed
+	Message string `json:"message,omitempty"`
+}
+
+// BootstrapCheck is a post-install verification result
+type BootstrapCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// KubeStellarBootstrapRun is the state of a bootstrap workflow, broadcast as
+// kubestellar_bootstrap_progress on every change
+type KubeStellarBootstrapRun struct {
+	ID         string           `json:"id"`
+	Cluster    string           `json:"cluster"`
+	Version    string           `json:"version"`
+	Status     string           `json:"status"` // running, succeeded, failed
+	Steps      []BootstrapStep  `json:"steps"`
+	Checks     []BootstrapCheck `json:"checks,omitempty"`
+	Contexts   []string         `json:"contexts,omitempty"` // kubeconfig contexts added for the spaces
+	Error      string           `json:"error,omitempty"`
+	StartedAt  string           `json:"startedAt"`
+	FinishedAt string           `json:"finishedAt,omitempty"`
+}
+
+// kubestellarBootstrap holds the latest bootstrap run; only one runs at a time
+type kubestellarBootstrap struct {
+	mu  sync.Mutex
+	run *KubeStellarBootstrapRun
+}
+
+// bootstrapStepNames are the workflow stages in order
+var bootstrapStepNames = []string{"preflight", "hosting-cluster", "install-core", "control-planes", "contexts", "verify"}
+
+// bootstrapWorkflow runs one bootstrap, publishing a snapshot after each change
+type bootstrapWorkflow struct {
+	s   *Server
+	req KubeStellarBootstrapRequest
+	mu  *sync.Mutex
+	run *KubeStellarBootstrapRun
+}
+
+func (b *bootstrapWorkflow) update(fn func(run *KubeStellarBootstrapRun)) {
+	b.mu.Lock()
+	fn(b.run)
+	snapshot := b.run.snapshot()
+	b.mu.Unlock()
+	b.s.BroadcastToClients("kubestellar_bootstrap_progress", snapshot)
+}
+
+// snapshot copies the run so it can be encoded while the workflow continues
+func (r *KubeStellarBootstrapRun) snapshot() *KubeStellarBootstrapRun {
+	c := *r
+	c.Steps = append([]BootstrapStep(nil), r.Steps...)
+	c.Checks = append([]BootstrapCheck(nil), r.Checks...)
+	c.Contexts = append([]string(nil), r.Contexts...)
+	return &c
+}
+
+func (b *bootstrapWorkflow) setStep(name, status, message string) {
+	b.update(func(run *KubeStellarBootstrapRun) {
+		for i := range run.Steps {
+			if run.Steps[i].Name == name {
+				run.Steps[i].Status, run.Steps[i].Message = status, message
+			}
+		}
+	})
+}
+
+// execute runs the steps in order, stopping at the first failure
+func (b *bootstrapWorkflow) execute(ctx context.Context) {
+	steps := map[string]func(context.Context) (string, error){
+		"preflight":       b.preflight,
+		"hosting-cluster": b.hostingCluster,
+		"install-core":    b.installCore,
+		"control-planes":  b.waitForControlPlanes,
+		"contexts":        b.importContexts,
+		"verify":          b.verify,
+	}
+	for _, name := range bootstrapStepNames {
+		b.setStep(name, bootstrapRunning, "")
+		message, err := steps[name](ctx)
+		if err != nil {
+			log.Printf("[KubeStellarBootstrap] %s failed: %v", name, err)
+			b.setStep(name, bootstrapFailed, err.Error())
+			b.update(func(run *KubeStellarBootstrapRun) {
+				run.Status, run.Error = bootstrapFailed, fmt.Sprintf("%s: %v", name, err)
+				run.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+			})
+			return
+		}
+		status := bootstrapDone
+		if message == bootstrapSkipped {
+			status, message = bootstrapSkipped, ""
+		}
+		b.setStep(name, status, message)
+	}
+	b.update(func(run *KubeStellarBootstrapRun) {
+		run.Status = bootstrapSucceeded
+		for _, c := range run.Checks {
+			if !c.Passed {
+				run.Status = bootstrapFailed
+				run.Error = "verification failed: " + c.Name
+				break
+			}
+		}
+		run.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	})
+}
+
+func (b *bootstrapWorkflow) preflight(ctx context.Context) (string, error) {
+	for _, tool := range []string{"helm", "kubectl"} {
+		if _, err := lookPath(tool); err != nil {
+			return "", fmt.Errorf("%s is not installed", tool)
+		}
+	}
+	if b.req.LocalCluster != nil {
+		return "hosting cluster will be created locally", nil
+	}
+	client, err := b.s.k8sClient.GetClient(b.run.Cluster)
+	if err != nil {
+		return "", err
+	}
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("cluster %s is unreachable: %w", b.run.Cluster, err)
+	}
+	return "hosting cluster runs Kubernetes " + info.GitVersion, nil
+}
+
+func (b *bootstrapWorkflow) hostingCluster(ctx context.Context) (string, error) {
+	lc := b.req.LocalCluster
+	if lc == nil {
+		return bootstrapSkipped, nil
+	}
+	tmpl, _ := findLocalClusterTemplate("kubestellar-dev")
+	if err := b.s.localClusters.CreateClusterFromTemplate(lc.Tool, lc.Name, tmpl, LocalClusterOptions{}); err != nil {
+		return "", err
+	}
+	// Pick up the context the tool just added to the kubeconfig
+	if err := b.s.k8sClient.Reload(); err != nil {
+		return "", fmt.Errorf("failed to reload kubeconfig: %w", err)
+	}
+	b.s.kubectl.Reload()
+	return fmt.Sprintf("created %s cluster %s", lc.Tool, lc.Name), nil
+}
+
+func (b *bootstrapWorkflow) installCore(ctx context.Context) (string, error) {
+	spaces := func(names []string) string {
+		list := make([]map[string]string, 0, len(names))
+		for _, n := range names {
+			list = append(list, map[string]string{"name": n})
+		}
+		data, _ := json.Marshal(list)
+		return string(data)
+	}
+	args := []string{"upgrade", "--install", kubestellarCoreRelease, kubestellarCoreChart,
+		"--version", b.run.Version,
+		"--kube-context", b.run.Cluster,
+		"--set-json", "ITSes=" + spaces(b.req.ITSes),
+		"--set-json", "WDSes=" + spaces(b.req.WDSes),
+		"--wait", "--timeout", kubestellarHelmTimeout,
+	}
+	// The core chart bundles the KubeFlex operator; don't fight an existing install
+	if _, err := b.s.k8sClient.ListControlPlanes(ctx, b.run.Cluster); err == nil {
+		args = append(args, "--set", "kubeflex-operator.install=false")
+	}
+	if err := runAddonCommand("helm", args); err != nil {
+		return "", err
+	}
+	return "installed KubeStellar " + b.run.Version, nil
+}
+
+// spaceNames returns the ITS and WDS names requested
+func (b *bootstrapWorkflow) spaceNames() []string {
+	return append(append([]string{}, b.req.ITSes...), b.req.WDSes...)
+}
+
+func (b *bootstrapWorkflow) waitForControlPlanes(ctx context.Context) (string, error) {
+	ticker := time.NewTicker(bootstrapPollInterval)
+	defer ticker.Stop()
+	for {
+		planes, err := b.s.k8sClient.ListControlPlanes(ctx, b.run.Cluster)
+		if err == nil {
+			ready := make(map[string]bool, len(planes))
+			for _, cp := range planes {
+				ready[cp.Name] = cp.Ready
+			}
+			var waiting []string
+			for _, name := range b.spaceNames() {
+				if !ready[name] {
+					waiting = append(waiting, name)
+				}
+			}
+			if len(waiting) == 0 {
+				return fmt.Sprintf("%d control planes ready", len(b.spaceNames())), nil
+			}
+			b.setStep("control-planes", bootstrapRunning, fmt.Sprintf("waiting for %v", waiting))
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("control planes not ready: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *bootstrapWorkflow) importContexts(ctx context.Context) (string, error) {
+	planes, err := b.s.k8sClient.ListControlPlanes(ctx, b.run.Cluster)
+	if err != nil {
+		return "", err
+	}
+	byName := make(map[string]k8s.KubeFlexControlPlane, len(planes))
+	for _, cp := range planes {
+		byName[cp.Name] = cp
+	}
+	var added []string
+	for _, name := range b.spaceNames() {
+		data, err := b.s.k8sClient.GetControlPlaneKubeconfig(ctx, b.run.Cluster, byName[name])
+		if err != nil {
+			return "", err
+		}
+		kubeconfig, err := renameKubeconfig(data, name)
+		if err != nil {
+			return "", fmt.Errorf("kubeconfig of %s: %w", name, err)
+		}
+		a, _, err := b.s.kubectl.ImportKubeconfig(kubeconfig)
+		if err != nil {
+			return "", err
+		}
+		added = append(added, a...)
+	}
+	b.update(func(run *KubeStellarBootstrapRun) { run.Contexts = b.spaceNames() })
+	return fmt.Sprintf("added %d kubeconfig contexts", len(added)), nil
+}
+
+// renameKubeconfig rewrites a single-context kubeconfig so its context, cluster
+// and user are all called name, avoiding clashes with existing entries
+func renameKubeconfig(data []byte, name string) (string, error) {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return "", err
+	}
+	current := config.CurrentContext
+	if _, ok := config.Contexts[current]; !ok {
+		for n := range config.Contexts {
+			current = n
+			break
+		}
+	}
+	c, ok := config.Contexts[current]
+	if !ok {
+		return "", fmt.Errorf("no contexts")
+	}
+	cluster, ok := config.Clusters[c.Cluster]
+	if !ok {
+		return "", fmt.Errorf("cluster %q not found", c.Cluster)
+	}
+	out := api.NewConfig()
+	out.Clusters[name] = cluster
+	if user, ok := config.AuthInfos[c.AuthInfo]; ok {
+		out.AuthInfos[name] = user
+	}
+	out.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name, Namespace: c.Namespace}
+	out.CurrentContext = name
+	yaml, err := clientcmd.Write(*out)
+	return string(yaml), err
+}
+
+// verify checks that every space is ready and the KubeStellar controllers of
+// each WDS are available
+func (b *bootstrapWorkflow) verify(ctx context.Context) (string, error) {
+	var checks []BootstrapCheck
+	planes, err := b.s.k8sClient.ListControlPlanes(ctx, b.run.Cluster)
+	if err != nil {
+		return "", err
+	}
+	byName := make(map[string]k8s.KubeFlexControlPlane, len(planes))
+	for _, cp := range planes {
+		byName[cp.Name] = cp
+	}
+	for _, name := range b.spaceNames() {
+		cp, ok := byName[name]
+		check := BootstrapCheck{Name: "control plane " + name, Passed: ok && cp.Ready}
+		if !check.Passed {
+			check.Message = "not ready"
+			if ok && cp.Message != "" {
+				check.Message = cp.Message
+			}
+		}
+		checks = append(checks, check)
+	}
+
+	client, err := b.s.k8sClient.GetClient(b.run.Cluster)
+	if err != nil {
+		return "", err
+	}
+	for _, wds := range b.req.WDSes {
+		for _, deployment := range []string{"kubestellar-controller-manager", "transport-controller"} {
+			check := BootstrapCheck{Name: fmt.Sprintf("%s %s", wds, deployment)}
+			d, err := client.AppsV1().Deployments(wds+"-system").Get(ctx, deployment, metav1.GetOptions{})
+			switch {
+			case err != nil:
+				check.Message = "not found"
+			case d.Status.AvailableReplicas < 1:
+				check.Message = "not available"
+			default:
+				check.Passed = true
+			}
+			checks = append(checks, check)
+		}
+	}
+
+	passed := 0
+	for _, c := range checks {
+		if c.Passed {
+			passed++
+		}
+	}
+	b.update(func(run *KubeStellarBootstrapRun) { run.Checks = checks })
+	return fmt.Sprintf("%d/%d checks passed", passed, len(checks)), nil
+}
+
+// startBootstrap validates the request and starts the workflow in the background
+func (s *Server) startBootstrap(req KubeStellarBootstrapRequest) (*KubeStellarBootstrapRun, error) {
+	if req.Version == "" {
+		req.Version = defaultKubeStellarVersion
+	}
+	if len(req.ITSes) == 0 {
+		req.ITSes = []string{"its1"}
+	}
+	if len(req.WDSes) == 0 {
+		req.WDSes = []string{"wds1"}
+	}
+	for _, name := range append(append([]string{}, req.ITSes...), req.WDSes...) {
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid space name %q: %s", name, errs[0])
+		}
+	}
+	cluster := req.Cluster
+	if req.LocalCluster != nil {
+		if req.LocalCluster.Tool == "" || req.LocalCluster.Name == "" {
+			return nil, fmt.Errorf("localCluster requires tool and name")
+		}
+		cluster = localClusterContext(req.LocalCluster.Tool, req.LocalCluster.Name)
+	} else if cluster == "" {
+		return nil, fmt.Errorf("cluster or localCluster is required")
+	}
+
+	run := &KubeStellarBootstrapRun{
+		ID:        uuid.New().String(),
+		Cluster:   cluster,
+		Version:   req.Version,
+		Status:    bootstrapRunning,
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, name := range bootstrapStepNames {
+		run.Steps = append(run.Steps, BootstrapStep{Name: name, Status: bootstrapPending})
+	}
+
+	s.kubestellarBootstrap.mu.Lock()
+	if current := s.kubestellarBootstrap.run; current != nil && current.Status == bootstrapRunning {
+		s.kubestellarBootstrap.mu.Unlock()
+		return nil, errBootstrapRunning
+	}
+	s.kubestellarBootstrap.run = run
+	snapshot := run.snapshot()
+	s.kubestellarBootstrap.mu.Unlock()
+
+	workflow := &bootstrapWorkflow{s: s, req: req, mu: &s.kubestellarBootstrap.mu, run: run}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[KubeStellarBootstrap] recovered from panic: %v", r)
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), kubestellarBootstrapTimeout)
+		defer cancel()
+		workflow.execute(ctx)
+	}()
+	return snapshot, nil
+}
+
+// handleKubeStellarBootstrap returns the latest bootstrap run (GET) or starts
+// installing KubeStellar on a cluster (POST)
+func (s *Server) handleKubeStellarBootstrap(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil || s.kubectl == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		s.kubestellarBootstrap.mu.Lock()
+		var run *KubeStellarBootstrapRun
+		if s.kubestellarBootstrap.run != nil {
+			run = s.kubestellarBootstrap.run.snapshot()
+		}
+		s.kubestellarBootstrap.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"run": run})
+
+	case "POST":
+		var req KubeStellarBootstrapRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		run, err := s.startBootstrap(req)
+		if err != nil {
+			status := http.StatusBadRequest
+			if err == errBootstrapRunning {
+				status = http.StatusConflict
+			}
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{"run": run})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
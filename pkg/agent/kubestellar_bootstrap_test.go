@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func testControlPlane(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tenancy.kflex.kubestellar.org/v1alpha1",
+		"kind":       "ControlPlane",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       map[string]interface{}{"type": "k8s"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+			"secretRef": map[string]interface{}{
+				"name": "admin-kubeconfig", "namespace": name + "-system", "key": "kubeconfig", "inClusterKey": "kubeconfig-incluster",
+			},
+		},
+	}}
+}
+
+func TestKubeStellarBootstrap(t *testing.T) {
+	oldLookPath, oldExecCommand, oldPoll := lookPath, execCommand, bootstrapPollInterval
+	defer func() {
+		lookPath, execCommand, bootstrapPollInterval = oldLookPath, oldExecCommand, oldPoll
+	}()
+	lookPath = func(file string) (string, error) { return "/usr/local/bin/" + file, nil }
+	var helmArgs []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		if name == "helm" {
+			helmArgs = arg
+		}
+		return exec.Command("true")
+	}
+	bootstrapPollInterval = 10 * time.Millisecond
+
+	dir := t.TempDir()
+	kubeconfigPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeconfigPath, []byte(sampleKubeconfig("host", "host", "host", "https://host:6443")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	proxy, err := NewKubectlProxy(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("NewKubectlProxy failed: %v", err)
+	}
+
+	var objects []runtime.Object
+	for _, space := range []string{"its1", "wds1"} {
+		objects = append(objects, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-kubeconfig", Namespace: space + "-system"},
+			Data:       map[string][]byte{"kubeconfig": []byte(sampleKubeconfig("default", "kflex", "admin", "https://"+space+".localtest.me:9443"))},
+		})
+	}
+	for _, d := range []string{"kubestellar-controller-manager", "transport-controller"} {
+		objects = append(objects, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: d, Namespace: "wds1-system"},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+		})
+	}
+	client, _ := k8s.NewMultiClusterClient("")
+	client.InjectClient("host", k8sfake.NewSimpleClientset(objects...))
+	client.InjectDynamicClient("host", dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "tenancy.kflex.kubestellar.org", Version: "v1alpha1", Resource: "controlplanes"}: "ControlPlaneList",
+		}, testControlPlane("its1"), testControlPlane("wds1")))
+
+	s := &Server{k8sClient: client, kubectl: proxy}
+	if _, err := s.startBootstrap(KubeStellarBootstrapRequest{}); err == nil {
+		t.Error("Expected a missing cluster to be rejected")
+	}
+	if _, err := s.startBootstrap(KubeStellarBootstrapRequest{Cluster: "host", WDSes: []string{"Bad_Name"}}); err == nil {
+		t.Error("Expected an invalid space name to be rejected")
+	}
+
+	if _, err := s.startBootstrap(KubeStellarBootstrapRequest{Cluster: "host"}); err != nil {
+		t.Fatalf("startBootstrap failed: %v", err)
+	}
+	var run *KubeStellarBootstrapRun
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.kubestellarBootstrap.mu.Lock()
+		run = s.kubestellarBootstrap.run.snapshot()
+		s.kubestellarBootstrap.mu.Unlock()
+		if run.Status != bootstrapRunning {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if run.Status != bootstrapSucceeded {
+		t.Fatalf("Expected bootstrap to succeed, got %s: %s (%+v)", run.Status, run.Error, run.Steps)
+	}
+	if run.Steps[1].Status != bootstrapSkipped {
+		t.Errorf("Expected hosting-cluster to be skipped, got %+v", run.Steps[1])
+	}
+	if len(run.Checks) != 4 {
+		t.Errorf("Expected 4 verification checks, got %+v", run.Checks)
+	}
+
+	joined := strings.Join(helmArgs, " ")
+	for _, want := range []string{"--kube-context host", `ITSes=[{"name":"its1"}]`, `WDSes=[{"name":"wds1"}]`, "kubeflex-operator.install=false"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("helm args missing %q: %s", want, joined)
+		}
+	}
+	for _, space := range []string{"its1", "wds1"} {
+		ctx, ok := proxy.config.Contexts[space]
+		if !ok {
+			t.Errorf("Expected context %s to be imported", space)
+			continue
+		}
+		if server := proxy.config.Clusters[ctx.Cluster].Server; server != "https://"+space+".localtest.me:9443" {
+			t.Errorf("Context %s points at %s", space, server)
+		}
+	}
+
+	// Only one bootstrap runs at a time
+	s.kubestellarBootstrap.mu.Lock()
+	s.kubestellarBootstrap.run.Status = bootstrapRunning
+	s.kubestellarBootstrap.mu.Unlock()
+	if _, err := s.startBootstrap(KubeStellarBootstrapRequest{Cluster: "host"}); err != errBootstrapRunning {
+		t.Errorf("Expected concurrent bootstrap to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Leader election for in-cluster deployments with more than one replica. HTTP and
+// WebSocket serving run on every replica; the background subsystems (prediction
+// worker, metrics history, device tracker) run only on the replica holding the Lease.
+const (
+	leaderElectionLeaseName = "kc-agent-leader"
+	leaderLeaseDuration     = 15 * time.Second
+	leaderRenewDeadline     = 10 * time.Second
+	leaderRetryPeriod       = 2 * time.Second
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// leaderState tracks whether this replica currently runs the background subsystems
+type leaderState struct {
+	enabled  bool
+	isLeader atomic.Bool
+}
+
+// leaderElectionIdentity returns a unique identity for this replica
+func leaderElectionIdentity() string {
+	if name := os.Getenv("POD_NAME"); name != "" {
+		return name
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return fmt.Sprintf("kc-agent-%d", os.Getpid())
+}
+
+// leaderElectionNamespace returns the namespace for the Lease: the configured one,
+// then POD_NAMESPACE, then the ServiceAccount namespace, then "default"
+func leaderElectionNamespace(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
+
+// startBackgroundSubsystems starts the periodic workers that must not run duplicated
+func (s *Server) startBackgroundSubsystems() {
+	if s.predictionWorker != nil && s.features.Enabled(FeaturePredictions) {
+		s.predictionWorker.Start()
+		log.Println("Prediction worker started")
+	}
+	if s.metricsHistory != nil && s.features.Enabled(FeatureMetricsHistory) {
+		s.metricsHistory.Start(metricsHistoryTick)
+		log.Println("Metrics history started")
+	}
+	if s.deviceTracker != nil && s.features.Enabled(FeatureDeviceTracker) {
+		s.deviceTracker.Start()
+		log.Println("Device tracker started")
+	}
+	if s.reportScheduler != nil && s.features.Enabled(FeatureReports) {
+		s.reportScheduler.Start()
+		log.Println("Report scheduler started")
+	}
+}
+
+// stopBackgroundSubsystems stops the workers started by startBackgroundSubsystems
+func (s *Server) stopBackgroundSubsystems() {
+	if s.predictionWorker != nil {
+		s.predictionWorker.Stop()
+	}
+	if s.metricsHistory != nil {
+		s.metricsHistory.Stop()
+	}
+	if s.deviceTracker != nil {
+		s.deviceTracker.Stop()
+	}
+	if s.reportScheduler != nil {
+		s.reportScheduler.Stop()
+	}
+}
+
+// runBackgroundSubsystems starts the background subsystems directly, or behind a
+// Lease when leader election is enabled and the agent runs in-cluster
+func (s *Server) runBackgroundSubsystems() {
+	if !s.config.LeaderElect {
+		s.startBackgroundSubsystems()
+		return
+	}
+	if s.k8sClient == nil || !s.k8sClient.IsInCluster() {
+		log.Println("Warning: leader election requires running in-cluster; starting background subsystems on this replica")
+		s.startBackgroundSubsystems()
+		return
+	}
+	client, err := s.k8sClient.GetClient("in-cluster")
+	if err != nil {
+		log.Printf("Warning: leader election client unavailable (%v); starting background subsystems on this replica", err)
+		s.startBackgroundSubsystems()
+		return
+	}
+
+	s.leader.enabled = true
+	go s.runLeaderElection(context.Background(), client, leaderElectionNamespace(s.config.LeaderElectionNamespace), leaderElectionIdentity(),
+		s.startBackgroundSubsystems,
+		func() {
+			// The workers can't be restarted in-process; exit so the pod restarts as a follower
+			s.stopBackgroundSubsystems()
+			log.Println("[LeaderElection] Lost leadership, exiting so the replica restarts as a follower")
+			os.Exit(1)
+		})
+}
+
+// runLeaderElection blocks while campaigning for the Lease, calling onStarted when
+// this replica becomes leader and onStopped when it loses the Lease
+func (s *Server) runLeaderElection(ctx context.Context, client kubernetes.Interface, namespace, identity string, onStarted, onStopped func()) {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Name: leaderElectionLeaseName, Namespace: namespace},
+		Client:     client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	log.Printf("[LeaderElection] Campaigning for lease %s/%s as %s", namespace, leaderElectionLeaseName, identity)
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaderLeaseDuration,
+		RenewDeadline:   leaderRenewDeadline,
+		RetryPeriod:     leaderRetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            leaderElectionLeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				log.Printf("[LeaderElection] %s became leader, starting background subsystems", identity)
+				s.leader.isLeader.Store(true)
+				onStarted()
+			},
+			OnStoppedLeading: func() {
+				s.leader.isLeader.Store(false)
+				if ctx.Err() != nil {
+					return // shutting down
+				}
+				onStopped()
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					log.Printf("[LeaderElection] Current leader is %s; background subsystems run there", current)
+				}
+			},
+		},
+	})
+}
+
+// IsLeader reports whether this replica runs the background subsystems. It is
+// always true when leader election is disabled.
+func (s *Server) IsLeader() bool {
+	return !s.leader.enabled || s.leader.isLeader.Load()
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLeaderElectionSingleLeader(t *testing.T) {
+	client := k8sfake.NewSimpleClientset()
+
+	a := &Server{}
+	b := &Server{}
+	a.leader.enabled = true
+	b.leader.enabled = true
+
+	started := make(chan string, 2)
+	ctxA, cancelA := context.WithCancel(context.Background())
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	go a.runLeaderElection(ctxA, client, "kc", "replica-a", func() { started <- "replica-a" }, func() {})
+	select {
+	case id := <-started:
+		if id != "replica-a" {
+			t.Fatalf("Expected replica-a to lead, got %s", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("replica-a never became leader")
+	}
+
+	go b.runLeaderElection(ctxB, client, "kc", "replica-b", func() { started <- "replica-b" }, func() {})
+	time.Sleep(500 * time.Millisecond)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("Expected only replica-a to lead (a=%v b=%v)", a.IsLeader(), b.IsLeader())
+	}
+
+	// Shutting down the leader releases the Lease to the follower
+	cancelA()
+	select {
+	case id := <-started:
+		if id != "replica-b" {
+			t.Fatalf("Expected replica-b to take over, got %s", id)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("replica-b never took over the lease")
+	}
+	if a.IsLeader() {
+		t.Error("Expected replica-a to report follower after releasing the lease")
+	}
+}
+
+func TestLeaderElectionNamespace(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "kubestellar")
+	if ns := leaderElectionNamespace("custom"); ns != "custom" {
+		t.Errorf("Expected configured namespace, got %s", ns)
+	}
+	if ns := leaderElectionNamespace(""); ns != "kubestellar" {
+		t.Errorf("Expected POD_NAMESPACE, got %s", ns)
+	}
+}
+
+func TestIsLeaderWithoutElection(t *testing.T) {
+	if !(&Server{}).IsLeader() {
+		t.Error("Expected every replica to lead when leader election is disabled")
+	}
+}
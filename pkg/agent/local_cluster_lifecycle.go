@@ -0,0 +1,323 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Local cluster lifecycle actions
+const (
+	localClusterStart   = "start"
+	localClusterStop    = "stop"
+	localClusterPause   = "pause"
+	localClusterUnpause = "unpause"
+)
+
+// LocalClusterUsage is the host CPU and memory consumed by a local cluster's containers
+type LocalClusterUsage struct {
+	Name             string  `json:"name"`
+	Tool             string  `json:"tool"`
+	Status           string  `json:"status"`
+	Containers       int     `json:"containers"`
+	CPUPercent       float64 `json:"cpuPercent"` // of one host core, summed across containers
+	MemoryBytes      int64   `json:"memoryBytes"`
+	MemoryLimitBytes int64   `json:"memoryLimitBytes,omitempty"`
+}
+
+// localClusterLabel returns the docker label selecting a local cluster's node containers
+func localClusterLabel(tool, name string) string {
+	switch tool {
+	case "kind":
+		return "io.x-k8s.kind.cluster=" + name
+	case "k3d":
+		return "k3d.cluster=" + name
+	case "minikube":
+		return "name.minikube.sigs.k8s.io=" + name
+	}
+	return ""
+}
+
+// clusterContainers returns the IDs and states of a local cluster's docker containers
+func clusterContainers(tool, name string) (ids, states []string, err error) {
+	label := localClusterLabel(tool, name)
+	if label == "" {
+		return nil, nil, fmt.Errorf("unsupported tool: %s", tool)
+	}
+	cmd := execCommand("docker", "ps", "-a", "--filter", "label="+label, "--format", "{{.ID}} {{.State}}")
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, nil, fmt.Errorf("docker ps failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			ids = append(ids, fields[0])
+			states = append(states, fields[1])
+		}
+	}
+	return ids, states, nil
+}
+
+// localClusterStatus summarizes container states as running, paused or stopped,
+// or "" when they are mixed or unknown
+func localClusterStatus(states []string) string {
+	counts := make(map[string]int)
+	for _, s := range states {
+		counts[s]++
+	}
+	switch {
+	case len(states) == 0:
+		return ""
+	case counts["running"] == len(states):
+		return "running"
+	case counts["paused"] == len(states):
+		return "paused"
+	case counts["exited"]+counts["created"] == len(states):
+		return "stopped"
+	}
+	return ""
+}
+
+// containerStatus returns the status of a cluster from its node containers,
+// or fallback when docker cannot tell
+func containerStatus(tool, name, fallback string) string {
+	_, states, err := clusterContainers(tool, name)
+	if err != nil {
+		return fallback
+	}
+	if status := localClusterStatus(states); status != "" {
+		return status
+	}
+	return fallback
+}
+
+// SetClusterState starts, stops, pauses or unpauses a local cluster. Stopping
+// frees its memory but keeps its state on disk; pausing freezes it in memory
+// for a near-instant resume.
+func (m *LocalClusterManager) SetClusterState(tool, name, action string) error {
+	switch action {
+	case localClusterStart, localClusterStop, localClusterPause, localClusterUnpause:
+	default:
+		return fmt.Errorf("unsupported action: %s", action)
+	}
+	m.broadcastProgress(tool, name, action, fmt.Sprintf("Running %s on %s cluster '%s'...", action, tool, name), progressCreating)
+
+	var args []string
+	switch {
+	case tool == "k3d" && (action == localClusterStart || action == localClusterStop):
+		args = []string{"k3d", "cluster", action, name}
+	case tool == "minikube":
+		args = []string{"minikube", action, "--profile", name}
+	case tool == "kind" || tool == "k3d":
+		// kind has no lifecycle commands, and k3d cannot pause; drive the node containers directly
+		if err := m.checkDockerRunning(); err != nil {
+			return err
+		}
+		ids, _, err := clusterContainers(tool, name)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("no containers found for %s cluster '%s'", tool, name)
+		}
+		args = append([]string{"docker", action}, ids...)
+	default:
+		return fmt.Errorf("unsupported tool: %s", tool)
+	}
+
+	cmd := execCommand(args[0], args[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s failed: %s", args[0], action, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// dockerStatsLine is one container from docker stats --format '{{json .}}'
+type dockerStatsLine struct {
+	ID       string `json:"ID"`
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"` // "1.2GiB / 7.6GiB"
+}
+
+// ClusterUsage reports the host resources used by each local cluster. Clusters
+// that do not run in docker (for example minikube with a VM driver) report
+// zero usage.
+func (m *LocalClusterManager) ClusterUsage() []LocalClusterUsage {
+	usage := []LocalClusterUsage{}
+	for _, c := range m.ListClusters() {
+		u := LocalClusterUsage{Name: c.Name, Tool: c.Tool, Status: c.Status}
+		ids, states, err := clusterContainers(c.Tool, c.Name)
+		if err != nil {
+			log.Printf("[LocalClusters] usage for %s/%s: %v", c.Tool, c.Name, err)
+			usage = append(usage, u)
+			continue
+		}
+		u.Containers = len(ids)
+		if status := localClusterStatus(states); status != "" {
+			u.Status = status
+		}
+		if len(ids) > 0 {
+			if err := addDockerStats(&u, ids); err != nil {
+				log.Printf("[LocalClusters] docker stats for %s/%s: %v", c.Tool, c.Name, err)
+			}
+		}
+		usage = append(usage, u)
+	}
+	return usage
+}
+
+// addDockerStats sums docker stats for the given containers into u
+func addDockerStats(u *LocalClusterUsage, ids []string) error {
+	cmd := execCommand("docker", append([]string{"stats", "--no-stream", "--format", "{{json .}}"}, ids...)...)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker stats failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var line dockerStatsLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if cpu, err := strconv.ParseFloat(strings.TrimSuffix(line.CPUPerc, "%"), 64); err == nil {
+			u.CPUPercent += cpu
+		}
+		used, limit, _ := strings.Cut(line.MemUsage, "/")
+		u.MemoryBytes += parseDockerSize(used)
+		// Containers share the host memory limit, so don't sum it
+		if l := parseDockerSize(limit); l > u.MemoryLimitBytes {
+			u.MemoryLimitBytes = l
+		}
+	}
+	return scanner.Err()
+}
+
+// parseDockerSize parses docker's human-readable sizes like "512KiB" or "1.5GB"
+func parseDockerSize(s string) int64 {
+	s = strings.TrimSpace(s)
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+		{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+		{"B", 1},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			if err != nil {
+				return 0
+			}
+			return int64(v * u.factor)
+		}
+	}
+	return 0
+}
+
+// handleLocalClusterLifecycle starts, stops, pauses or unpauses a local cluster
+// in the background, streaming local_cluster_progress events
+func (s *Server) handleLocalClusterLifecycle(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Tool   string `json:"tool"`
+		Name   string `json:"name"`
+		Action string `json:"action"` // start, stop, pause, unpause
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Tool == "" || req.Name == "" {
+		http.Error(w, "tool and name are required", http.StatusBadRequest)
+		return
+	}
+	switch req.Action {
+	case localClusterStart, localClusterStop, localClusterPause, localClusterUnpause:
+	default:
+		http.Error(w, "action must be start, stop, pause or unpause", http.StatusBadRequest)
+		return
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[LocalClusters] recovered from panic during %s of cluster %s: %v", req.Action, req.Name, r)
+			}
+		}()
+		if err := s.localClusters.SetClusterState(req.Tool, req.Name, req.Action); err != nil {
+			log.Printf("[LocalClusters] Failed to %s cluster %s: %v", req.Action, req.Name, err)
+			s.BroadcastToClients("local_cluster_progress", map[string]interface{}{
+				"tool":     req.Tool,
+				"name":     req.Name,
+				"status":   "failed",
+				"message":  "operation failed",
+				"progress": progressFailed,
+			})
+			return
+		}
+		log.Printf("[LocalClusters] %s cluster %s: %s done", req.Tool, req.Name, req.Action)
+		s.BroadcastToClients("local_cluster_progress", map[string]interface{}{
+			"tool":     req.Tool,
+			"name":     req.Name,
+			"status":   "done",
+			"message":  fmt.Sprintf("Cluster '%s' %s complete", req.Name, req.Action),
+			"progress": progressDone,
+		})
+	}()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": req.Action,
+		"tool":   req.Tool,
+		"name":   req.Name,
+	})
+}
+
+// handleLocalClusterUsage reports host CPU and memory used by each local cluster
+func (s *Server) handleLocalClusterUsage(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"clusters": s.localClusters.ClusterUsage(),
+	})
+}
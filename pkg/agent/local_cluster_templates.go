@@ -0,0 +1,274 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Progress range for addon installation after a templated cluster is created
+const (
+	progressAddonsStart = 50
+	progressAddonsEnd   = 95
+)
+
+// addonInstallTimeout bounds how long helm waits for one addon to become ready
+const addonInstallTimeout = "10m"
+
+// LocalClusterChart is a Helm chart installed as a template addon
+type LocalClusterChart struct {
+	Repo    string   `json:"repo,omitempty"` // empty for oci:// charts
+	Chart   string   `json:"chart"`
+	Version string   `json:"version,omitempty"`
+	Release string   `json:"release"`
+	Set     []string `json:"set,omitempty"` // --set values
+}
+
+// LocalClusterAddon is installed into a cluster after it is created from a template
+type LocalClusterAddon struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// NodeLabels are applied to every node before the addon is installed
+	NodeLabels map[string]string  `json:"nodeLabels,omitempty"`
+	Manifests  []string           `json:"manifests,omitempty"` // applied with kubectl apply -f
+	Chart      *LocalClusterChart `json:"chart,omitempty"`
+}
+
+// LocalClusterTemplate is a named local cluster preset with bundled addons
+type LocalClusterTemplate struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Options     LocalClusterOptions `json:"options"`
+	Addons      []LocalClusterAddon `json:"addons"`
+}
+
+// metricsServerAddon installs metrics-server; local kubelets use self-signed certificates
+var metricsServerAddon = LocalClusterAddon{
+	Name:      "metrics-server",
+	Namespace: "kube-system",
+	Chart: &LocalClusterChart{
+		Repo:    "https://kubernetes-sigs.github.io/metrics-server/",
+		Chart:   "metrics-server",
+		Release: "metrics-server",
+		Set:     []string{"args={--kubelet-insecure-tls}"},
+	},
+}
+
+// localClusterTemplates are the built-in templates offered for local cluster creation
+var localClusterTemplates = []LocalClusterTemplate{
+	{
+		Name:        "kubestellar-dev",
+		Description: "Hosting cluster for KubeStellar development: ingress-nginx with SSL passthrough on port 9443 and the KubeFlex operator",
+		Options: LocalClusterOptions{
+			Ports: []LocalClusterPort{{HostPort: 9443, ContainerPort: 443}},
+		},
+		Addons: []LocalClusterAddon{
+			metricsServerAddon,
+			{
+				Name:      "ingress-nginx",
+				Namespace: "ingress-nginx",
+				Chart: &LocalClusterChart{
+					Repo:    "https://kubernetes.github.io/ingress-nginx",
+					Chart:   "ingress-nginx",
+					Release: "ingress-nginx",
+					Set: []string{
+						"controller.extraArgs.enable-ssl-passthrough=true",
+						"controller.hostPort.enabled=true",
+						"controller.service.type=NodePort",
+					},
+				},
+			},
+			{
+				Name:      "kubeflex",
+				Namespace: "kubeflex-system",
+				Chart: &LocalClusterChart{
+					Chart:   "oci://ghcr.io/kubestellar/kubeflex/chart/kubeflex-operator",
+					Release: "kubeflex-operator",
+				},
+			},
+		},
+	},
+	{
+		Name:        "gpu-sim",
+		Description: "Two-node cluster advertising simulated NVIDIA GPUs through the fake GPU operator, for testing GPU scheduling without hardware",
+		Options:     LocalClusterOptions{Nodes: 2},
+		Addons: []LocalClusterAddon{
+			metricsServerAddon,
+			{
+				Name:       "fake-gpu-operator",
+				Namespace:  "gpu-operator",
+				NodeLabels: map[string]string{"run.ai/simulated-gpu-node-pool": "default"},
+				Chart: &LocalClusterChart{
+					Repo:    "https://fake-gpu-operator.storage.googleapis.com",
+					Chart:   "fake-gpu-operator",
+					Release: "fake-gpu-operator",
+					Set:     []string{"topology.nodePools.default.gpuCount=4"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "observability",
+		Description: "Cluster with metrics-server and the Prometheus stack (Prometheus, Alertmanager, Grafana) for dashboard development",
+		Addons: []LocalClusterAddon{
+			metricsServerAddon,
+			{
+				Name:      "kube-prometheus-stack",
+				Namespace: "monitoring",
+				Chart: &LocalClusterChart{
+					Repo:    "https://prometheus-community.github.io/helm-charts",
+					Chart:   "kube-prometheus-stack",
+					Release: "kube-prometheus-stack",
+				},
+			},
+		},
+	},
+}
+
+// findLocalClusterTemplate looks up a built-in template by name
+func findLocalClusterTemplate(name string) (LocalClusterTemplate, bool) {
+	for _, t := range localClusterTemplates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return LocalClusterTemplate{}, false
+}
+
+// withOverrides returns the template options with any options set in o taking precedence
+func (t LocalClusterTemplate) withOverrides(o LocalClusterOptions) LocalClusterOptions {
+	opts := t.Options
+	if o.Nodes != 0 {
+		opts.Nodes = o.Nodes
+	}
+	if o.KubernetesVersion != "" {
+		opts.KubernetesVersion = o.KubernetesVersion
+	}
+	opts.GPU = opts.GPU || o.GPU
+	if len(o.Ports) > 0 {
+		opts.Ports = o.Ports
+	}
+	if o.RegistryMirror != "" {
+		opts.RegistryMirror = o.RegistryMirror
+	}
+	return opts
+}
+
+// localClusterContext returns the kubeconfig context each tool creates for a cluster
+func localClusterContext(tool, name string) string {
+	switch tool {
+	case "kind":
+		return "kind-" + name
+	case "k3d":
+		return "k3d-" + name
+	}
+	return name
+}
+
+// CreateClusterFromTemplate creates a local cluster with the template's options,
+// overridden by opts, then installs the template's addons
+func (m *LocalClusterManager) CreateClusterFromTemplate(tool, name string, tmpl LocalClusterTemplate, opts LocalClusterOptions) error {
+	if err := m.CreateCluster(tool, name, tmpl.withOverrides(opts)); err != nil {
+		return err
+	}
+	return m.installAddons(tool, name, tmpl.Addons)
+}
+
+// installAddons installs addons in order, broadcasting progress for each
+func (m *LocalClusterManager) installAddons(tool, name string, addons []LocalClusterAddon) error {
+	for _, a := range addons {
+		if a.Chart != nil {
+			if _, err := lookPath("helm"); err != nil {
+				return fmt.Errorf("helm is required to install addon %s", a.Name)
+			}
+			break
+		}
+	}
+
+	kubeContext := localClusterContext(tool, name)
+	for i, a := range addons {
+		progress := progressAddonsStart + (progressAddonsEnd-progressAddonsStart)*i/len(addons)
+		m.broadcastProgress(tool, name, "installing", fmt.Sprintf("Installing %s (%d/%d)...", a.Name, i+1, len(addons)), progress)
+		if err := installAddon(kubeContext, a); err != nil {
+			return fmt.Errorf("addon %s: %w", a.Name, err)
+		}
+	}
+	return nil
+}
+
+func installAddon(kubeContext string, a LocalClusterAddon) error {
+	if len(a.NodeLabels) > 0 {
+		args := []string{"--context", kubeContext, "label", "nodes", "--all", "--overwrite"}
+		labels := make([]string, 0, len(a.NodeLabels))
+		for k, v := range a.NodeLabels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		args = append(args, labels...)
+		if err := runAddonCommand("kubectl", args); err != nil {
+			return err
+		}
+	}
+	for _, manifest := range a.Manifests {
+		if err := runAddonCommand("kubectl", []string{"--context", kubeContext, "apply", "-f", manifest}); err != nil {
+			return err
+		}
+	}
+	if a.Chart != nil {
+		return runAddonCommand("helm", helmAddonArgs(kubeContext, a))
+	}
+	return nil
+}
+
+// helmAddonArgs builds an idempotent helm install for an addon chart
+func helmAddonArgs(kubeContext string, a LocalClusterAddon) []string {
+	c := a.Chart
+	args := []string{"upgrade", "--install", c.Release, c.Chart,
+		"--kube-context", kubeContext,
+		"--namespace", a.Namespace, "--create-namespace",
+		"--wait", "--timeout", addonInstallTimeout,
+	}
+	if c.Repo != "" {
+		args = append(args, "--repo", c.Repo)
+	}
+	if c.Version != "" {
+		args = append(args, "--version", c.Version)
+	}
+	for _, v := range c.Set {
+		args = append(args, "--set", v)
+	}
+	return args
+}
+
+func runAddonCommand(name string, args []string) error {
+	cmd := execCommand(name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// handleLocalClusterTemplates lists the built-in local cluster templates
+func (s *Server) handleLocalClusterTemplates(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"templates": localClusterTemplates,
+	})
+}
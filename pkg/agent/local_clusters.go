@@ -3,8 +3,11 @@ package agent
 import (
 	"bytes"
 	"fmt"
+	"net/url"
+	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -17,9 +20,18 @@ const (
 	progressFailed     = 0   // Operation failed
 )
 
+// Limits on local cluster create options
+const (
+	maxLocalClusterNodes = 10
+	maxPortNumber        = 65535
+)
+
 var (
 	// execCommand is already declared in kubectl.go
 	lookPath = exec.LookPath
+
+	// kubernetesVersionPattern matches versions like "1.30", "v1.30.2" or "v1.30.2-k3s1"
+	kubernetesVersionPattern = regexp.MustCompile(`^v?\d+\.\d+(\.\d+)?(-k3s\d+)?$`)
 )
 
 // LocalClusterTool represents a detected local cluster tool
@@ -37,6 +49,53 @@ type LocalCluster struct {
 	Status string `json:"status"` // "running", "stopped", "unknown"
 }
 
+// LocalClusterPort maps a host port to a port inside the cluster
+type LocalClusterPort struct {
+	HostPort      int    `json:"hostPort"`
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol,omitempty"` // tcp (default) or udp
+}
+
+// LocalClusterOptions are optional settings for creating a local cluster.
+// Zero values keep each tool's defaults.
+type LocalClusterOptions struct {
+	Nodes             int                `json:"nodes,omitempty"` // total nodes, including the control plane
+	KubernetesVersion string             `json:"kubernetesVersion,omitempty"`
+	GPU               bool               `json:"gpu,omitempty"` // pass host GPUs through to the nodes
+	Ports             []LocalClusterPort `json:"ports,omitempty"`
+	RegistryMirror    string             `json:"registryMirror,omitempty"` // mirror for docker.io pulls
+}
+
+// Validate checks the options before they are passed to a cluster tool
+func (o LocalClusterOptions) Validate() error {
+	if o.Nodes < 0 || o.Nodes > maxLocalClusterNodes {
+		return fmt.Errorf("nodes must be between 1 and %d", maxLocalClusterNodes)
+	}
+	if o.KubernetesVersion != "" && !kubernetesVersionPattern.MatchString(o.KubernetesVersion) {
+		return fmt.Errorf("invalid kubernetesVersion %q", o.KubernetesVersion)
+	}
+	for _, p := range o.Ports {
+		if p.HostPort < 1 || p.HostPort > maxPortNumber || p.ContainerPort < 1 || p.ContainerPort > maxPortNumber {
+			return fmt.Errorf("ports must be between 1 and %d", maxPortNumber)
+		}
+		if p.Protocol != "" && p.Protocol != "tcp" && p.Protocol != "udp" {
+			return fmt.Errorf("invalid port protocol %q", p.Protocol)
+		}
+	}
+	if o.RegistryMirror != "" {
+		u, err := url.Parse(o.RegistryMirror)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("registryMirror must be an http(s) URL")
+		}
+	}
+	return nil
+}
+
+// versionTag returns the Kubernetes version with a leading "v"
+func (o LocalClusterOptions) versionTag() string {
+	return "v" + strings.TrimPrefix(o.KubernetesVersion, "v")
+}
+
 // LocalClusterManager handles local cluster operations
 type LocalClusterManager struct {
 	broadcast func(msgType string, payload interface{})
@@ -207,7 +266,7 @@ func (m *LocalClusterManager) listKindClusters() []LocalCluster {
 			clusters = append(clusters, LocalCluster{
 				Name:   name,
 				Tool:   "kind",
-				Status: "running", // kind clusters are always running if listed
+				Status: containerStatus("kind", name, "running"),
 			})
 		}
 	}
@@ -234,7 +293,7 @@ func (m *LocalClusterManager) listK3dClusters() []LocalCluster {
 			clusters = append(clusters, LocalCluster{
 				Name:   fields[0],
 				Tool:   "k3d",
-				Status: "running",
+				Status: containerStatus("k3d", fields[0], "running"),
 			})
 		}
 	}
@@ -273,10 +332,14 @@ func (m *LocalClusterManager) listMinikubeClusters() []LocalCluster {
 }
 
 // CreateCluster creates a new local cluster with phased progress broadcasting
-func (m *LocalClusterManager) CreateCluster(tool, name string) error {
+func (m *LocalClusterManager) CreateCluster(tool, name string, opts LocalClusterOptions) error {
 	// Phase 1: Validating prerequisites
 	m.broadcastProgress(tool, name, "validating", "Checking prerequisites...", progressValidating)
 
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
 	// Docker pre-flight check for tools that require it
 	if tool == "kind" || tool == "k3d" {
 		if err := m.checkDockerRunning(); err != nil {
@@ -289,18 +352,22 @@ func (m *LocalClusterManager) CreateCluster(tool, name string) error {
 
 	switch tool {
 	case "kind":
-		return m.createKindCluster(name)
+		return m.createKindCluster(name, opts)
 	case "k3d":
-		return m.createK3dCluster(name)
+		return m.createK3dCluster(name, opts)
 	case "minikube":
-		return m.createMinikubeCluster(name)
+		return m.createMinikubeCluster(name, opts)
 	default:
 		return fmt.Errorf("unsupported tool: %s", tool)
 	}
 }
 
-func (m *LocalClusterManager) createKindCluster(name string) error {
-	cmd := execCommand("kind", "create", "cluster", "--name", name)
+func (m *LocalClusterManager) createKindCluster(name string, opts LocalClusterOptions) error {
+	args, config := kindCreateArgs(name, opts)
+	cmd := execCommand("kind", args...)
+	if config != "" {
+		cmd.Stdin = strings.NewReader(config)
+	}
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -309,8 +376,72 @@ func (m *LocalClusterManager) createKindCluster(name string) error {
 	return nil
 }
 
-func (m *LocalClusterManager) createK3dCluster(name string) error {
-	cmd := execCommand("k3d", "cluster", "create", name)
+// kindCreateArgs builds the kind command line and, when options need one, a
+// cluster config to pass on stdin
+func kindCreateArgs(name string, opts LocalClusterOptions) ([]string, string) {
+	args := []string{"create", "cluster", "--name", name}
+	if opts.KubernetesVersion != "" {
+		args = append(args, "--image", "kindest/node:"+opts.versionTag())
+	}
+	if opts.Nodes <= 1 && !opts.GPU && len(opts.Ports) == 0 && opts.RegistryMirror == "" {
+		return args, ""
+	}
+
+	var b strings.Builder
+	b.WriteString("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\n")
+	if opts.RegistryMirror != "" {
+		b.WriteString("containerdConfigPatches:\n- |-\n")
+		b.WriteString("  [plugins.\"io.containerd.grpc.v1.cri\".registry.mirrors.\"docker.io\"]\n")
+		fmt.Fprintf(&b, "    endpoint = [%q]\n", opts.RegistryMirror)
+	}
+	b.WriteString("nodes:\n")
+	nodes := opts.Nodes
+	if nodes < 1 {
+		nodes = 1
+	}
+	for i := 0; i < nodes; i++ {
+		role := "worker"
+		if i == 0 {
+			role = "control-plane"
+		}
+		fmt.Fprintf(&b, "- role: %s\n", role)
+		if opts.GPU {
+			// Exposes all GPUs through the NVIDIA container runtime, which must be
+			// the Docker default runtime with accept-nvidia-visible-devices-as-volume-mounts
+			b.WriteString("  extraMounts:\n  - hostPath: /dev/null\n    containerPath: /var/run/nvidia-container-devices/all\n")
+		}
+		if i == 0 && len(opts.Ports) > 0 {
+			b.WriteString("  extraPortMappings:\n")
+			for _, p := range opts.Ports {
+				fmt.Fprintf(&b, "  - containerPort: %d\n    hostPort: %d\n", p.ContainerPort, p.HostPort)
+				if p.Protocol != "" {
+					fmt.Fprintf(&b, "    protocol: %s\n", strings.ToUpper(p.Protocol))
+				}
+			}
+		}
+	}
+	return append(args, "--config", "-"), b.String()
+}
+
+func (m *LocalClusterManager) createK3dCluster(name string, opts LocalClusterOptions) error {
+	registryConfig := ""
+	if opts.RegistryMirror != "" {
+		f, err := os.CreateTemp("", "k3d-registries-*.yaml")
+		if err != nil {
+			return fmt.Errorf("failed to write registry config: %w", err)
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(k3dRegistryConfig(opts.RegistryMirror))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write registry config: %w", err)
+		}
+		registryConfig = f.Name()
+	}
+
+	cmd := execCommand("k3d", k3dCreateArgs(name, opts, registryConfig)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -319,8 +450,43 @@ func (m *LocalClusterManager) createK3dCluster(name string) error {
 	return nil
 }
 
-func (m *LocalClusterManager) createMinikubeCluster(name string) error {
-	cmd := execCommand("minikube", "start", "--profile", name)
+// k3dCreateArgs builds the k3d command line; registryConfig is the path of a
+// registries.yaml holding the mirror, if any
+func k3dCreateArgs(name string, opts LocalClusterOptions, registryConfig string) []string {
+	args := []string{"cluster", "create", name}
+	if opts.Nodes > 1 {
+		args = append(args, "--agents", strconv.Itoa(opts.Nodes-1))
+	}
+	if opts.KubernetesVersion != "" {
+		tag := opts.versionTag()
+		if !strings.Contains(tag, "-k3s") {
+			tag += "-k3s1"
+		}
+		args = append(args, "--image", "rancher/k3s:"+tag)
+	}
+	if opts.GPU {
+		args = append(args, "--gpus", "all")
+	}
+	for _, p := range opts.Ports {
+		mapping := fmt.Sprintf("%d:%d", p.HostPort, p.ContainerPort)
+		if p.Protocol != "" {
+			mapping += "/" + p.Protocol
+		}
+		args = append(args, "--port", mapping+"@loadbalancer")
+	}
+	if registryConfig != "" {
+		args = append(args, "--registry-config", registryConfig)
+	}
+	return args
+}
+
+// k3dRegistryConfig returns a k3s registries.yaml mirroring docker.io
+func k3dRegistryConfig(mirror string) string {
+	return fmt.Sprintf("mirrors:\n  docker.io:\n    endpoint:\n      - %q\n", mirror)
+}
+
+func (m *LocalClusterManager) createMinikubeCluster(name string, opts LocalClusterOptions) error {
+	cmd := execCommand("minikube", minikubeStartArgs(name, opts)...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -329,6 +495,32 @@ func (m *LocalClusterManager) createMinikubeCluster(name string) error {
 	return nil
 }
 
+// minikubeStartArgs builds the minikube command line. GPUs and port mappings
+// need the docker driver.
+func minikubeStartArgs(name string, opts LocalClusterOptions) []string {
+	args := []string{"start", "--profile", name}
+	if opts.Nodes > 1 {
+		args = append(args, "--nodes", strconv.Itoa(opts.Nodes))
+	}
+	if opts.KubernetesVersion != "" {
+		args = append(args, "--kubernetes-version", opts.versionTag())
+	}
+	if opts.GPU {
+		args = append(args, "--driver", "docker", "--container-runtime", "docker", "--gpus", "all")
+	}
+	for _, p := range opts.Ports {
+		mapping := fmt.Sprintf("%d:%d", p.HostPort, p.ContainerPort)
+		if p.Protocol != "" {
+			mapping += "/" + p.Protocol
+		}
+		args = append(args, "--ports", mapping)
+	}
+	if opts.RegistryMirror != "" {
+		args = append(args, "--registry-mirror", opts.RegistryMirror)
+	}
+	return args
+}
+
 // DeleteCluster deletes a local cluster with phased progress broadcasting
 func (m *LocalClusterManager) DeleteCluster(tool, name string) error {
 	// Phase 1: Validating
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"os/exec"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -55,7 +57,7 @@ func TestLocalClusterManager(t *testing.T) {
 	}
 
 	// 4. Test Create/Delete Cluster
-	err := m.CreateCluster("kind", "test-kind")
+	err := m.CreateCluster("kind", "test-kind", LocalClusterOptions{})
 	if err != nil {
 		t.Errorf("Create kind cluster failed: %v", err)
 	}
@@ -65,3 +67,186 @@ func TestLocalClusterManager(t *testing.T) {
 		t.Errorf("Delete k3d cluster failed: %v", err)
 	}
 }
+
+func TestLocalClusterCreateArgs(t *testing.T) {
+	opts := LocalClusterOptions{
+		Nodes:             3,
+		KubernetesVersion: "1.30.2",
+		GPU:               true,
+		Ports:             []LocalClusterPort{{HostPort: 8080, ContainerPort: 80}, {HostPort: 5353, ContainerPort: 53, Protocol: "udp"}},
+		RegistryMirror:    "https://mirror.example.com",
+	}
+
+	args, config := kindCreateArgs("dev", opts)
+	wantKind := []string{"create", "cluster", "--name", "dev", "--image", "kindest/node:v1.30.2", "--config", "-"}
+	if !reflect.DeepEqual(args, wantKind) {
+		t.Errorf("kind args = %v, want %v", args, wantKind)
+	}
+	for _, want := range []string{"- role: control-plane", "hostPort: 8080", "protocol: UDP", "nvidia-container-devices", `endpoint = ["https://mirror.example.com"]`} {
+		if !strings.Contains(config, want) {
+			t.Errorf("kind config missing %q:\n%s", want, config)
+		}
+	}
+	if strings.Count(config, "- role: worker") != 2 {
+		t.Errorf("expected 2 kind workers:\n%s", config)
+	}
+	if _, config := kindCreateArgs("dev", LocalClusterOptions{}); config != "" {
+		t.Errorf("expected no kind config for defaults, got %q", config)
+	}
+
+	wantK3d := []string{"cluster", "create", "dev", "--agents", "2", "--image", "rancher/k3s:v1.30.2-k3s1", "--gpus", "all",
+		"--port", "8080:80@loadbalancer", "--port", "5353:53/udp@loadbalancer", "--registry-config", "/tmp/registries.yaml"}
+	if args := k3dCreateArgs("dev", opts, "/tmp/registries.yaml"); !reflect.DeepEqual(args, wantK3d) {
+		t.Errorf("k3d args = %v, want %v", args, wantK3d)
+	}
+
+	wantMinikube := []string{"start", "--profile", "dev", "--nodes", "3", "--kubernetes-version", "v1.30.2",
+		"--driver", "docker", "--container-runtime", "docker", "--gpus", "all",
+		"--ports", "8080:80", "--ports", "5353:53/udp", "--registry-mirror", "https://mirror.example.com"}
+	if args := minikubeStartArgs("dev", opts); !reflect.DeepEqual(args, wantMinikube) {
+		t.Errorf("minikube args = %v, want %v", args, wantMinikube)
+	}
+}
+
+func TestLocalClusterOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    LocalClusterOptions
+		wantErr bool
+	}{
+		{"defaults", LocalClusterOptions{}, false},
+		{"k3s version", LocalClusterOptions{KubernetesVersion: "v1.29.4-k3s1"}, false},
+		{"too many nodes", LocalClusterOptions{Nodes: maxLocalClusterNodes + 1}, true},
+		{"bad version", LocalClusterOptions{KubernetesVersion: "latest; rm -rf /"}, true},
+		{"bad port", LocalClusterOptions{Ports: []LocalClusterPort{{HostPort: 0, ContainerPort: 80}}}, true},
+		{"bad protocol", LocalClusterOptions{Ports: []LocalClusterPort{{HostPort: 80, ContainerPort: 80, Protocol: "sctp"}}}, true},
+		{"bad mirror", LocalClusterOptions{RegistryMirror: "mirror.example.com"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCreateClusterFromTemplate(t *testing.T) {
+	oldLookPath := lookPath
+	oldExecCommand := execCommand
+	defer func() {
+		lookPath = oldLookPath
+		execCommand = oldExecCommand
+	}()
+	lookPath = func(file string) (string, error) {
+		return "/usr/local/bin/" + file, nil
+	}
+	var calls []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		calls = append(calls, name+" "+strings.Join(arg, " "))
+		return exec.Command("true")
+	}
+
+	var statuses []string
+	m := NewLocalClusterManager(func(_ string, payload interface{}) {
+		statuses = append(statuses, payload.(map[string]interface{})["status"].(string))
+	})
+	tmpl, ok := findLocalClusterTemplate("gpu-sim")
+	if !ok {
+		t.Fatal("gpu-sim template not found")
+	}
+	if err := m.CreateClusterFromTemplate("k3d", "sim", tmpl, LocalClusterOptions{KubernetesVersion: "1.30.2"}); err != nil {
+		t.Fatalf("CreateClusterFromTemplate failed: %v", err)
+	}
+
+	wantPrefixes := []string{
+		"docker info",
+		"k3d cluster create sim --agents 1 --image rancher/k3s:v1.30.2-k3s1",
+		"helm upgrade --install metrics-server metrics-server --kube-context k3d-sim",
+		"kubectl --context k3d-sim label nodes --all --overwrite run.ai/simulated-gpu-node-pool=default",
+		"helm upgrade --install fake-gpu-operator fake-gpu-operator --kube-context k3d-sim --namespace gpu-operator",
+	}
+	if len(calls) != len(wantPrefixes) {
+		t.Fatalf("Expected %d commands, got %d: %v", len(wantPrefixes), len(calls), calls)
+	}
+	for i, want := range wantPrefixes {
+		if !strings.HasPrefix(calls[i], want) {
+			t.Errorf("command %d = %q, want prefix %q", i, calls[i], want)
+		}
+	}
+	if got := strings.Count(strings.Join(statuses, ","), "installing"); got != len(tmpl.Addons) {
+		t.Errorf("Expected %d installing progress events, got %d (%v)", len(tmpl.Addons), got, statuses)
+	}
+
+	if _, ok := findLocalClusterTemplate("missing"); ok {
+		t.Error("Expected unknown template lookup to fail")
+	}
+}
+
+func TestLocalClusterLifecycleAndUsage(t *testing.T) {
+	oldLookPath := lookPath
+	oldExecCommand := execCommand
+	defer func() {
+		lookPath = oldLookPath
+		execCommand = oldExecCommand
+	}()
+	lookPath = func(file string) (string, error) {
+		if file != "kind" {
+			return "", exec.ErrNotFound
+		}
+		return "/usr/local/bin/kind", nil
+	}
+	var calls []string
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		call := name + " " + strings.Join(arg, " ")
+		calls = append(calls, call)
+		switch {
+		case call == "kind get clusters":
+			return exec.Command("echo", "dev")
+		case strings.HasPrefix(call, "docker ps -a --filter label=io.x-k8s.kind.cluster=dev"):
+			return exec.Command("printf", "a1 exited\nb2 exited\n")
+		case strings.HasPrefix(call, "docker stats"):
+			return exec.Command("printf", `{"ID":"a1","CPUPerc":"1.50%%","MemUsage":"512MiB / 8GiB"}`+"\n"+`{"ID":"b2","CPUPerc":"0.25%%","MemUsage":"1GiB / 8GiB"}`+"\n")
+		case name == "kind" || name == "k3d" || name == "minikube":
+			return exec.Command("false")
+		}
+		return exec.Command("true")
+	}
+
+	m := NewLocalClusterManager(nil)
+	if err := m.SetClusterState("kind", "dev", localClusterStart); err != nil {
+		t.Fatalf("SetClusterState failed: %v", err)
+	}
+	if last := calls[len(calls)-1]; last != "docker start a1 b2" {
+		t.Errorf("Expected docker start of both nodes, got %q", last)
+	}
+	if err := m.SetClusterState("kind", "dev", "destroy"); err == nil {
+		t.Error("Expected unsupported action to fail")
+	}
+
+	usage := m.ClusterUsage()
+	if len(usage) != 1 {
+		t.Fatalf("Expected 1 cluster, got %+v", usage)
+	}
+	u := usage[0]
+	if u.Status != "stopped" || u.Containers != 2 || u.CPUPercent != 1.75 {
+		t.Errorf("Unexpected usage: %+v", u)
+	}
+	if u.MemoryBytes != 1536<<20 || u.MemoryLimitBytes != 8<<30 {
+		t.Errorf("Unexpected memory: %d / %d", u.MemoryBytes, u.MemoryLimitBytes)
+	}
+}
+
+func TestParseDockerSize(t *testing.T) {
+	tests := map[string]int64{
+		"512KiB":  512 << 10,
+		" 1.5GiB": 3 << 29,
+		"100MB":   100e6,
+		"12B":     12,
+		"":        0,
+		"n/a":     0,
+	}
+	for in, want := range tests {
+		if got := parseDockerSize(in); got != want {
+			t.Errorf("parseDockerSize(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
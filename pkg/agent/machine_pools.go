@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// MachinePoolScaleRequest adjusts the replica count of a Cluster API node pool
+type MachinePoolScaleRequest struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind,omitempty"` // MachineDeployment (default) or MachinePool
+	Replicas  *int32 `json:"replicas"`
+}
+
+// handleMachinePools lists Cluster API node pools (GET) or scales one (POST), so
+// capacity can be added in response to GPU shortage alerts
+func (s *Server) handleMachinePools(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"pools": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		s.listMachinePools(w, r)
+	case "POST":
+		s.scaleMachinePool(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) listMachinePools(w http.ResponseWriter, r *http.Request) {
+	cluster := r.URL.Query().Get("cluster")
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+
+	all := []k8s.MachinePool{}
+
+	if cluster != "" {
+		pools, err := s.k8sClient.ListMachinePools(ctx, cluster)
+		if err != nil {
+			log.Printf("error fetching machine pools: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"pools": []interface{}{}, "error": "internal server error"})
+			return
+		}
+		all = pools
+	} else {
+		clusters, err := s.k8sClient.ListClusters(ctx)
+		if err != nil {
+			log.Printf("error fetching machine pools: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"pools": []interface{}{}, "error": "internal server error"})
+			return
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+
+		for _, cl := range clusters {
+			wg.Add(1)
+			go func(clusterName string) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[MachinePools] recovered from panic for cluster %s: %v", clusterName, r)
+					}
+				}()
+				clusterCtx, clusterCancel := context.WithTimeout(ctx, s.requestTimeout(r, clusterName, k8s.OpList, agentDefaultTimeout))
+				defer clusterCancel()
+				pools, err := s.k8sClient.ListMachinePools(clusterCtx, clusterName)
+				if err == nil && len(pools) > 0 {
+					mu.Lock()
+					all = append(all, pools...)
+					mu.Unlock()
+				}
+			}(cl.Name)
+		}
+		wg.Wait()
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"pools": all, "source": "agent"})
+}
+
+func (s *Server) scaleMachinePool(w http.ResponseWriter, r *http.Request) {
+	var req MachinePoolScaleRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+		return
+	}
+	if req.Cluster == "" || req.Namespace == "" || req.Name == "" || req.Replicas == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "cluster, namespace, name and replicas are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	defer cancel()
+
+	pool, err := s.k8sClient.ScaleMachinePool(ctx, req.Cluster, req.Kind, req.Namespace, req.Name, *req.Replicas)
+	if err != nil {
+		log.Printf("[MachinePools] failed to scale %s/%s on %s: %v", req.Namespace, req.Name, req.Cluster, err)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	log.Printf("[MachinePools] Scaled %s %s/%s on %s to %d replicas", pool.Kind, pool.Namespace, pool.Name, pool.Cluster, pool.Replicas)
+	s.BroadcastToClients("machine_pool_scaled", pool)
+	json.NewEncoder(w).Encode(pool)
+}
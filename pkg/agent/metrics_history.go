@@ -17,6 +17,7 @@ const (
 	maxSnapshots          = 144 // 24 hours at 10-min intervals
 	metricsHistoryFile    = "metrics_history.json"
 	snapshotRetentionHrs  = 24
+	metricsSharedCacheKey = "metrics:history"
 	metricsHistoryTimeout = 30 * time.Second
 	metricsFileMode       = 0600
 	metricsDirMode        = 0700
@@ -69,6 +70,8 @@ type MetricsHistory struct {
 	stopCh             chan struct{}
 	dataDir            string
 	loggedClusterError bool // suppress repeated "no kubeconfig" errors
+	// onHealth receives each round of cluster health checks, e.g. to track stale contexts
+	onHealth func([]k8s.ClusterHealth)
 }
 
 // NewMetricsHistory creates a new metrics history manager
@@ -99,6 +102,13 @@ func (mh *MetricsHistory) SetDataDir(dir string) {
 	mh.dataDir = dir
 }
 
+// SetHealthRecorder registers a callback for the cluster health gathered with each snapshot
+func (mh *MetricsHistory) SetHealthRecorder(fn func([]k8s.ClusterHealth)) {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+	mh.onHealth = fn
+}
+
 // Start begins the metrics collection loop
 func (mh *MetricsHistory) Start(interval time.Duration) {
 	go mh.runLoop(interval)
@@ -109,8 +119,33 @@ func (mh *MetricsHistory) Stop() {
 	close(mh.stopCh)
 }
 
+// syncFromSharedCache adopts the history captured by another replica when it is
+// newer than ours (followers under leader election never capture themselves)
+func (mh *MetricsHistory) syncFromSharedCache() {
+	if mh.k8sClient == nil {
+		return
+	}
+	var shared []MetricsSnapshot
+	if !k8s.SharedGetJSON(mh.k8sClient.SharedCache(), metricsSharedCacheKey, &shared) || len(shared) == 0 {
+		return
+	}
+	latestShared, err := time.Parse(time.RFC3339, shared[len(shared)-1].Timestamp)
+	if err != nil {
+		return
+	}
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+	if len(mh.snapshots) > 0 {
+		if latest, err := time.Parse(time.RFC3339, mh.snapshots[len(mh.snapshots)-1].Timestamp); err == nil && !latestShared.After(latest) {
+			return
+		}
+	}
+	mh.snapshots = shared
+}
+
 // GetSnapshots returns all snapshots
 func (mh *MetricsHistory) GetSnapshots() MetricsHistoryResponse {
+	mh.syncFromSharedCache()
 	mh.mu.RLock()
 	defer mh.mu.RUnlock()
 
@@ -122,6 +157,7 @@ func (mh *MetricsHistory) GetSnapshots() MetricsHistoryResponse {
 
 // GetRecentSnapshots returns the last N snapshots
 func (mh *MetricsHistory) GetRecentSnapshots(n int) []MetricsSnapshot {
+	mh.syncFromSharedCache()
 	mh.mu.RLock()
 	defer mh.mu.RUnlock()
 
@@ -181,6 +217,12 @@ func (mh *MetricsHistory) captureSnapshot() error {
 			log.Printf("[MetricsHistory] Cluster data unavailable (will retry silently): %v", err)
 		}
 	} else {
+		mh.mu.RLock()
+		onHealth := mh.onHealth
+		mh.mu.RUnlock()
+		if onHealth != nil {
+			onHealth(healthList)
+		}
 		for _, h := range healthList {
 			cpuPercent := 0.0
 			if h.CpuCores > 0 && h.CpuRequestsCores > 0 {
@@ -257,8 +299,9 @@ func (mh *MetricsHistory) captureSnapshot() error {
 	mh.snapshots = trimmed
 	mh.mu.Unlock()
 
-	// Persist to disk
+	// Persist to disk and share with other replicas
 	go mh.saveToDisk()
+	k8s.SharedSetJSON(mh.k8sClient.SharedCache(), metricsSharedCacheKey, trimmed, time.Duration(snapshotRetentionHrs)*time.Hour)
 
 	log.Printf("[MetricsHistory] Captured snapshot: %d clusters, %d pod issues, %d GPU nodes",
 		len(snapshot.Clusters), len(snapshot.PodIssues), len(snapshot.GPUNodes))
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	// ndjsonFlushEvery items are buffered between flushes to the client
+	ndjsonFlushEvery = 100
+)
+
+// wantsNDJSON reports whether the client asked for newline-delimited JSON,
+// via "Accept: application/x-ndjson" or ?format=ndjson
+func wantsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ndjsonContentType) || r.URL.Query().Get("format") == "ndjson"
+}
+
+// ndjsonWriter writes one JSON object per line, flushing periodically so the
+// client sees the first items before the listing finishes. A failure after
+// streaming started is reported as a final {"error": ...} line.
+type ndjsonWriter struct {
+	enc     *json.Encoder
+	flusher http.Flusher
+	pending int
+}
+
+func newNDJSONWriter(w http.ResponseWriter) *ndjsonWriter {
+	w.Header().Set("Content-Type", ndjsonContentType)
+	flusher, _ := w.(http.Flusher)
+	return &ndjsonWriter{enc: json.NewEncoder(w), flusher: flusher}
+}
+
+// item writes v as one line; an error means the client went away
+func (n *ndjsonWriter) item(v interface{}) error {
+	if err := n.enc.Encode(v); err != nil {
+		return err
+	}
+	n.pending++
+	if n.pending >= ndjsonFlushEvery {
+		n.flush()
+	}
+	return nil
+}
+
+// fail ends the stream with an error line
+func (n *ndjsonWriter) fail(message string) {
+	n.enc.Encode(map[string]string{"error": message}) //nolint:errcheck
+	n.flush()
+}
+
+func (n *ndjsonWriter) flush() {
+	n.pending = 0
+	if n.flusher != nil {
+		n.flusher.Flush()
+	}
+}
+
+// writeNDJSON streams an already listed slice one item per line
+func writeNDJSON[T any](w http.ResponseWriter, items []T) {
+	out := newNDJSONWriter(w)
+	for _, item := range items {
+		if err := out.item(item); err != nil {
+			return
+		}
+	}
+	out.flush()
+}
+
+// streamPods writes a cluster's pods as NDJSON while they are listed, a page
+// at a time, instead of building and encoding the whole list
+func (s *Server) streamPods(ctx context.Context, w http.ResponseWriter, cluster, namespace string) {
+	out := newNDJSONWriter(w)
+	err := s.k8sClient.StreamPods(ctx, cluster, namespace, func(pod k8s.PodInfo) error {
+		return out.item(pod)
+	})
+	if err != nil {
+		log.Printf("error streaming pods: %v", err)
+		out.fail("internal server error")
+		return
+	}
+	out.flush()
+}
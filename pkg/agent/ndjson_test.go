@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakek8s "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestHandlePodsNDJSON(t *testing.T) {
+	const podCount = 250
+	var objects []runtime.Object
+	for i := 0; i < podCount; i++ {
+		objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%03d", i), Namespace: "default"}})
+	}
+	k8sClient, _ := k8s.NewMultiClusterClient("")
+	k8sClient.SetClient("ctx-1", fakek8s.NewSimpleClientset(objects...))
+	s := &Server{k8sClient: k8sClient, allowedOrigins: []string{"*"}}
+
+	req := httptest.NewRequest("GET", "/pods?cluster=ctx-1", nil)
+	req.Header.Set("Accept", ndjsonContentType)
+	rec := httptest.NewRecorder()
+	s.cachedList(s.handlePodsHTTP)(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != ndjsonContentType {
+		t.Errorf("Expected %s, got %q", ndjsonContentType, ct)
+	}
+	if rec.Header().Get("X-Cache") != "" {
+		t.Error("Expected NDJSON responses to bypass the response cache")
+	}
+	lines := 0
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var pod k8s.PodInfo
+		if err := json.Unmarshal(scanner.Bytes(), &pod); err != nil || pod.Name == "" {
+			t.Fatalf("Expected one pod per line, got %q (%v)", scanner.Text(), err)
+		}
+		lines++
+	}
+	if lines != podCount {
+		t.Errorf("Expected %d lines, got %d", podCount, lines)
+	}
+
+	// Without the Accept header the usual JSON object is returned
+	rec = httptest.NewRecorder()
+	s.handlePodsHTTP(rec, httptest.NewRequest("GET", "/pods?cluster=ctx-1", nil))
+	var body struct {
+		Pods []k8s.PodInfo `json:"pods"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || len(body.Pods) != podCount {
+		t.Errorf("Expected a JSON object with %d pods, got %d (%v)", podCount, len(body.Pods), err)
+	}
+}
@@ -0,0 +1,539 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+const (
+	// heuristicProvider is reported as the Provider of analyzer-generated predictions
+	heuristicProvider = "heuristic"
+
+	heuristicWarningConfidence  = 75
+	heuristicCriticalConfidence = 90
+
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+// PredictionAnalyzer is a rule-based analyzer that runs alongside the AI
+// providers on every analysis pass. Analyzers receive data for a single
+// cluster and the effective thresholds for that cluster.
+type PredictionAnalyzer interface {
+	Name() string
+	Description() string
+	DefaultThresholds() map[string]float64
+	Analyze(cluster string, data *ClusterAnalysisData, thresholds map[string]float64) []AIPrediction
+}
+
+// defaultPredictionAnalyzers returns the built-in analyzers
+func defaultPredictionAnalyzers() []PredictionAnalyzer {
+	return []PredictionAnalyzer{
+		&nodePressureAnalyzer{},
+		&crashloopAnalyzer{lastRestarts: make(map[string]int)},
+		&gpuDegradationAnalyzer{},
+		&pdbCoverageAnalyzer{},
+		&cronMissedAnalyzer{},
+	}
+}
+
+// newHeuristicPrediction fills in the fields common to all analyzer predictions
+func newHeuristicPrediction(analyzer, category, severity, cluster, name, reason, detail string) AIPrediction {
+	confidence := heuristicWarningConfidence
+	if severity == severityCritical {
+		confidence = heuristicCriticalConfidence
+	}
+	return AIPrediction{
+		ID:             uuid.New().String(),
+		Category:       category,
+		Severity:       severity,
+		Name:           name,
+		Cluster:        cluster,
+		Reason:         reason,
+		ReasonDetailed: detail,
+		Confidence:     confidence,
+		GeneratedAt:    time.Now().Format(time.RFC3339),
+		Provider:       heuristicProvider + ":" + analyzer,
+	}
+}
+
+// severityFor returns critical/warning when value crosses the given thresholds, or ""
+func severityFor(value, warning, critical float64) string {
+	switch {
+	case critical > 0 && value >= critical:
+		return severityCritical
+	case warning > 0 && value >= warning:
+		return severityWarning
+	}
+	return ""
+}
+
+// nodePressureAnalyzer flags clusters whose CPU or memory requests approach capacity
+type nodePressureAnalyzer struct{}
+
+func (a *nodePressureAnalyzer) Name() string { return "node-pressure" }
+
+func (a *nodePressureAnalyzer) Description() string {
+	return "Flags clusters whose CPU or memory requests approach allocatable capacity"
+}
+
+func (a *nodePressureAnalyzer) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		"cpuWarning":     80,
+		"cpuCritical":    95,
+		"memoryWarning":  85,
+		"memoryCritical": 95,
+	}
+}
+
+func (a *nodePressureAnalyzer) Analyze(cluster string, data *ClusterAnalysisData, th map[string]float64) []AIPrediction {
+	var out []AIPrediction
+	for _, c := range data.Clusters {
+		if sev := severityFor(c.CPUPercent, th["cpuWarning"], th["cpuCritical"]); sev != "" {
+			out = append(out, newHeuristicPrediction(a.Name(), "capacity-risk", sev, cluster, c.Name,
+				fmt.Sprintf("CPU requests at %.0f%% of capacity", c.CPUPercent),
+				fmt.Sprintf("Pod CPU requests on %s are at %.0f%% of allocatable CPU across %d nodes. New workloads may fail to schedule; consider adding nodes or reducing requests.",
+					c.Name, c.CPUPercent, c.NodeCount)))
+		}
+		if sev := severityFor(c.MemPercent, th["memoryWarning"], th["memoryCritical"]); sev != "" {
+			out = append(out, newHeuristicPrediction(a.Name(), "capacity-risk", sev, cluster, c.Name,
+				fmt.Sprintf("Memory requests at %.0f%% of capacity", c.MemPercent),
+				fmt.Sprintf("Pod memory requests on %s are at %.0f%% of allocatable memory across %d nodes. Pods may be evicted or fail to schedule; consider adding nodes or reducing requests.",
+					c.Name, c.MemPercent, c.NodeCount)))
+		}
+	}
+	return out
+}
+
+// crashloopAnalyzer flags restarting pods and tracks whether restarts are increasing
+type crashloopAnalyzer struct {
+	mu           sync.Mutex
+	lastRestarts map[string]int // cluster/namespace/pod → restarts seen on previous run
+}
+
+func (a *crashloopAnalyzer) Name() string { return "crashloop-trend" }
+
+func (a *crashloopAnalyzer) Description() string {
+	return "Flags pods with repeated restarts and reports whether restarts are increasing"
+}
+
+func (a *crashloopAnalyzer) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		"restartsWarning":  3,
+		"restartsCritical": 10,
+	}
+}
+
+func (a *crashloopAnalyzer) Analyze(cluster string, data *ClusterAnalysisData, th map[string]float64) []AIPrediction {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var out []AIPrediction
+	for _, p := range data.PodIssues {
+		key := p.Cluster + "/" + p.Namespace + "/" + p.Name
+		prev, seen := a.lastRestarts[key]
+		a.lastRestarts[key] = p.Restarts
+
+		sev := severityFor(float64(p.Restarts), th["restartsWarning"], th["restartsCritical"])
+		if sev == "" && p.Status == "CrashLoopBackOff" {
+			sev = severityWarning
+		}
+		if sev == "" {
+			continue
+		}
+
+		trend := "stable"
+		if seen && p.Restarts > prev {
+			trend = "worsening"
+		} else if seen && p.Restarts < prev {
+			trend = "improving"
+		}
+
+		pred := newHeuristicPrediction(a.Name(), "pod-crash", sev, cluster, p.Name,
+			fmt.Sprintf("%d restarts (%s)", p.Restarts, p.Status),
+			fmt.Sprintf("Pod %s/%s has restarted %d times and is in state %s. Check container logs and recent events for the cause.",
+				p.Namespace, p.Name, p.Restarts, p.Status))
+		pred.Namespace = p.Namespace
+		pred.Trend = trend
+		out = append(out, pred)
+	}
+	return out
+}
+
+// gpuDegradationAnalyzer flags GPU nodes that are saturated or not ready
+type gpuDegradationAnalyzer struct{}
+
+func (a *gpuDegradationAnalyzer) Name() string { return "gpu-degradation" }
+
+func (a *gpuDegradationAnalyzer) Description() string {
+	return "Flags GPU nodes that are fully allocated or not ready"
+}
+
+func (a *gpuDegradationAnalyzer) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		"allocationWarning":  90,
+		"allocationCritical": 100,
+	}
+}
+
+func (a *gpuDegradationAnalyzer) Analyze(cluster string, data *ClusterAnalysisData, th map[string]float64) []AIPrediction {
+	offline := make(map[string]string, len(data.OfflineNodes))
+	for _, n := range data.OfflineNodes {
+		offline[n.Name] = n.Status
+	}
+
+	var out []AIPrediction
+	for _, g := range data.GPUNodes {
+		if g.Total <= 0 {
+			continue
+		}
+		if status, ok := offline[g.Name]; ok {
+			out = append(out, newHeuristicPrediction(a.Name(), "anomaly", severityCritical, cluster, g.Name,
+				fmt.Sprintf("GPU node is %s", status),
+				fmt.Sprintf("Node %s with %d GPUs is %s. Its %d allocated GPUs are at risk and no new GPU workloads can be scheduled on it.",
+					g.Name, g.Total, status, g.Allocated)))
+			continue
+		}
+		pct := float64(g.Allocated) / float64(g.Total) * 100
+		if sev := severityFor(pct, th["allocationWarning"], th["allocationCritical"]); sev != "" {
+			out = append(out, newHeuristicPrediction(a.Name(), "capacity-risk", sev, cluster, g.Name,
+				fmt.Sprintf("%d/%d GPUs allocated", g.Allocated, g.Total),
+				fmt.Sprintf("Node %s has %d of %d GPUs allocated, leaving no headroom for failover or new GPU workloads.",
+					g.Name, g.Allocated, g.Total)))
+		}
+	}
+	return out
+}
+
+// pdbCoverageAnalyzer is a reliability check: it flags deployments whose PDB
+// allows no disruptions (node drains hang on them) and replicated deployments
+// that no PDB protects (a drain may take every replica down at once)
+type pdbCoverageAnalyzer struct{}
+
+// maxListedDeployments caps the deployment names quoted in one prediction
+const maxListedDeployments = 5
+
+func (a *pdbCoverageAnalyzer) Name() string { return "pdb-coverage" }
+
+func (a *pdbCoverageAnalyzer) Description() string {
+	return "Flags deployments with zero-disruption PDBs and replicated deployments without a PDB"
+}
+
+func (a *pdbCoverageAnalyzer) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		// Deployments with fewer replicas are not expected to have a PDB
+		"minReplicas": 2,
+	}
+}
+
+func (a *pdbCoverageAnalyzer) Analyze(cluster string, data *ClusterAnalysisData, th map[string]float64) []AIPrediction {
+	var out []AIPrediction
+	var unprotected []string
+	for _, r := range data.DisruptionRisks {
+		switch r.Reason {
+		case k8s.DisruptionBlocked:
+			pred := newHeuristicPrediction(a.Name(), "reliability-risk", severityWarning, cluster, r.Name,
+				fmt.Sprintf("PDB %s allows 0 disruptions", r.PDB),
+				fmt.Sprintf("Deployment %s/%s is covered by PodDisruptionBudget %s, which currently allows no disruptions. Node drains and cluster upgrades will block until more replicas are healthy or the budget is relaxed.",
+					r.Namespace, r.Name, r.PDB))
+			pred.Namespace = r.Namespace
+			out = append(out, pred)
+		case k8s.DisruptionUnprotected:
+			if float64(r.Replicas) >= th["minReplicas"] {
+				unprotected = append(unprotected, r.Namespace+"/"+r.Name)
+			}
+		}
+	}
+	if len(unprotected) > 0 {
+		sort.Strings(unprotected)
+		listed := unprotected
+		if len(listed) > maxListedDeployments {
+			listed = listed[:maxListedDeployments]
+		}
+		detail := fmt.Sprintf("%d replicated deployments on %s have no PodDisruptionBudget (%s", len(unprotected), cluster, strings.Join(listed, ", "))
+		if len(unprotected) > len(listed) {
+			detail += fmt.Sprintf(" and %d more", len(unprotected)-len(listed))
+		}
+		detail += "). A node drain may evict all of their replicas at once; add a PDB with minAvailable or maxUnavailable."
+		out = append(out, newHeuristicPrediction(a.Name(), "reliability-risk", severityWarning, cluster, cluster,
+			fmt.Sprintf("%d deployments without a PDB", len(unprotected)), detail))
+	}
+	return out
+}
+
+// cronMissedAnalyzer flags CronJobs whose last due run never started, e.g.
+// because the controller was down, startingDeadlineSeconds passed or a
+// Forbid concurrency policy skipped it
+type cronMissedAnalyzer struct{}
+
+func (a *cronMissedAnalyzer) Name() string { return "cronjob-missed" }
+
+func (a *cronMissedAnalyzer) Description() string {
+	return "Flags CronJobs that have not fired when their schedule says they should have"
+}
+
+func (a *cronMissedAnalyzer) DefaultThresholds() map[string]float64 {
+	return map[string]float64{
+		"lateMinutesWarning":  5,
+		"lateMinutesCritical": 60,
+	}
+}
+
+func (a *cronMissedAnalyzer) Analyze(cluster string, data *ClusterAnalysisData, th map[string]float64) []AIPrediction {
+	var out []AIPrediction
+	now := time.Now()
+	for _, cj := range data.MissedCronJobs {
+		due, err := time.Parse(time.RFC3339, cj.MissedSince)
+		if err != nil {
+			continue
+		}
+		late := now.Sub(due).Minutes()
+		sev := severityFor(late, th["lateMinutesWarning"], th["lateMinutesCritical"])
+		if sev == "" {
+			continue
+		}
+		last := "never"
+		if cj.LastScheduleTime != "" {
+			last = cj.LastScheduleTime
+		}
+		pred := newHeuristicPrediction(a.Name(), "reliability-risk", sev, cluster, cj.Name,
+			fmt.Sprintf("Missed run due %.0f minutes ago", late),
+			fmt.Sprintf("CronJob %s/%s (%q) was due at %s but has not started; last scheduled %s. Check the CronJob controller, startingDeadlineSeconds and the concurrency policy.",
+				cj.Namespace, cj.Name, cj.Schedule, cj.MissedSince, last))
+		pred.Namespace = cj.Namespace
+		out = append(out, pred)
+	}
+	return out
+}
+
+// analyzerSettings is the effective configuration of an analyzer for one cluster
+type analyzerSettings struct {
+	Enabled    bool
+	Interval   time.Duration
+	Thresholds map[string]float64
+}
+
+// resolveAnalyzerSettings merges defaults, the analyzer config and the cluster override
+func resolveAnalyzerSettings(a PredictionAnalyzer, cfg *settings.PredictionAnalyzerConfig, cluster string) analyzerSettings {
+	eff := analyzerSettings{Enabled: true, Thresholds: a.DefaultThresholds()}
+	if cfg == nil {
+		return eff
+	}
+	eff.Enabled = cfg.Enabled
+	eff.Interval = time.Duration(cfg.Interval) * time.Minute
+	for k, v := range cfg.Thresholds {
+		eff.Thresholds[k] = v
+	}
+	if override, ok := cfg.Clusters[cluster]; ok {
+		if override.Enabled != nil {
+			eff.Enabled = *override.Enabled
+		}
+		if override.Interval != nil {
+			eff.Interval = time.Duration(*override.Interval) * time.Minute
+		}
+		for k, v := range override.Thresholds {
+			eff.Thresholds[k] = v
+		}
+	}
+	return eff
+}
+
+// clusterSubset returns the parts of data that belong to cluster
+func clusterSubset(data *ClusterAnalysisData, cluster string) *ClusterAnalysisData {
+	sub := &ClusterAnalysisData{Timestamp: data.Timestamp}
+	for _, c := range data.Clusters {
+		if c.Name == cluster {
+			sub.Clusters = append(sub.Clusters, c)
+		}
+	}
+	for _, p := range data.PodIssues {
+		if p.Cluster == cluster {
+			sub.PodIssues = append(sub.PodIssues, p)
+		}
+	}
+	for _, g := range data.GPUNodes {
+		if g.Cluster == cluster {
+			sub.GPUNodes = append(sub.GPUNodes, g)
+		}
+	}
+	for _, n := range data.OfflineNodes {
+		if n.Cluster == cluster {
+			sub.OfflineNodes = append(sub.OfflineNodes, n)
+		}
+	}
+	for _, r := range data.DisruptionRisks {
+		if r.Cluster == cluster {
+			sub.DisruptionRisks = append(sub.DisruptionRisks, r)
+		}
+	}
+	for _, cj := range data.MissedCronJobs {
+		if cj.Cluster == cluster {
+			sub.MissedCronJobs = append(sub.MissedCronJobs, cj)
+		}
+	}
+	return sub
+}
+
+// settingsPredictionAnalyzers reads analyzer configuration from the persisted settings
+func settingsPredictionAnalyzers() map[string]settings.PredictionAnalyzerConfig {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil || all == nil {
+		return nil
+	}
+	return all.PredictionAnalyzers
+}
+
+// sortPredictions orders predictions critical first, then by confidence
+func sortPredictions(predictions []AIPrediction) {
+	sort.SliceStable(predictions, func(i, j int) bool {
+		if predictions[i].Severity != predictions[j].Severity {
+			return predictions[i].Severity == severityCritical
+		}
+		return predictions[i].Confidence > predictions[j].Confidence
+	})
+}
+
+// PredictionAnalyzerInfo describes an analyzer and its stored configuration
+type PredictionAnalyzerInfo struct {
+	Name              string                                         `json:"name"`
+	Description       string                                         `json:"description"`
+	Enabled           bool                                           `json:"enabled"`
+	Interval          int                                            `json:"interval"`
+	Thresholds        map[string]float64                             `json:"thresholds"`
+	DefaultThresholds map[string]float64                             `json:"defaultThresholds"`
+	Clusters          map[string]settings.PredictionAnalyzerOverride `json:"clusters,omitempty"`
+}
+
+// validateAnalyzerConfig checks a config against the analyzer's known thresholds
+func validateAnalyzerConfig(a PredictionAnalyzer, cfg settings.PredictionAnalyzerConfig) error {
+	known := a.DefaultThresholds()
+	checkThresholds := func(th map[string]float64) error {
+		for k, v := range th {
+			if _, ok := known[k]; !ok {
+				return fmt.Errorf("unknown threshold %q for analyzer %s", k, a.Name())
+			}
+			if v < 0 {
+				return fmt.Errorf("threshold %q for analyzer %s must not be negative", k, a.Name())
+			}
+		}
+		return nil
+	}
+	if cfg.Interval < 0 {
+		return fmt.Errorf("interval for analyzer %s must not be negative", a.Name())
+	}
+	if err := checkThresholds(cfg.Thresholds); err != nil {
+		return err
+	}
+	for cluster, override := range cfg.Clusters {
+		if override.Interval != nil && *override.Interval < 0 {
+			return fmt.Errorf("interval for analyzer %s on %s must not be negative", a.Name(), cluster)
+		}
+		if err := checkThresholds(override.Thresholds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handlePredictionAnalyzers lists analyzers (GET) or replaces their configuration (PUT)
+func (s *Server) handlePredictionAnalyzers(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.predictionWorker == nil {
+		http.Error(w, "Prediction worker not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		configs := settingsPredictionAnalyzers()
+		infos := []PredictionAnalyzerInfo{}
+		for _, a := range s.predictionWorker.Analyzers() {
+			var cfg *settings.PredictionAnalyzerConfig
+			if c, ok := configs[a.Name()]; ok {
+				cfg = &c
+			}
+			eff := resolveAnalyzerSettings(a, cfg, "")
+			info := PredictionAnalyzerInfo{
+				Name:              a.Name(),
+				Description:       a.Description(),
+				Enabled:           eff.Enabled,
+				Interval:          int(eff.Interval / time.Minute),
+				Thresholds:        eff.Thresholds,
+				DefaultThresholds: a.DefaultThresholds(),
+			}
+			if cfg != nil {
+				info.Clusters = cfg.Clusters
+			}
+			infos = append(infos, info)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"analyzers": infos})
+
+	case "PUT":
+		var configs map[string]settings.PredictionAnalyzerConfig
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&configs); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		analyzers := make(map[string]PredictionAnalyzer)
+		for _, a := range s.predictionWorker.Analyzers() {
+			analyzers[a.Name()] = a
+		}
+		for name, cfg := range configs {
+			a, ok := analyzers[name]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("unknown analyzer %q", name)})
+				return
+			}
+			if err := validateAnalyzerConfig(a, cfg); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		if configs == nil {
+			configs = map[string]settings.PredictionAnalyzerConfig{}
+		}
+
+		mgr := settings.GetSettingsManager()
+		all, err := mgr.GetAll()
+		if err == nil {
+			all.PredictionAnalyzers = configs
+			err = mgr.SaveAll(all)
+		}
+		if err != nil {
+			log.Printf("[PredictionWorker] failed to save analyzer config: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to save analyzer config"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
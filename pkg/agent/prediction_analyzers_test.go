@@ -0,0 +1,193 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+func analyzerTestData() *ClusterAnalysisData {
+	return &ClusterAnalysisData{
+		Clusters: []ClusterSummary{
+			{Name: "prod", CPUPercent: 97, MemPercent: 50, NodeCount: 3, Healthy: true},
+			{Name: "dev", CPUPercent: 82, MemPercent: 88, NodeCount: 1, Healthy: true},
+			{Name: "down", CPUPercent: 99, Healthy: false},
+		},
+		PodIssues: []PodIssueSummary{
+			{Name: "api", Namespace: "default", Cluster: "prod", Restarts: 4, Status: "CrashLoopBackOff"},
+			{Name: "web", Namespace: "default", Cluster: "dev", Restarts: 1, Status: "Running"},
+		},
+		GPUNodes: []GPUNodeSummary{
+			{Name: "gpu-1", Cluster: "prod", Allocated: 8, Total: 8},
+			{Name: "gpu-2", Cluster: "prod", Allocated: 2, Total: 8},
+		},
+		OfflineNodes: []NodeSummary{
+			{Name: "gpu-2", Cluster: "prod", Status: "NotReady"},
+		},
+	}
+}
+
+func countBy(preds []AIPrediction, provider, cluster string) int {
+	n := 0
+	for _, p := range preds {
+		if p.Provider == heuristicProvider+":"+provider && p.Cluster == cluster {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPredictionAnalyzers_Defaults(t *testing.T) {
+	w := NewPredictionWorker(nil, nil, nil, nil)
+	preds := w.runAnalyzers(analyzerTestData(), false)
+
+	if got := countBy(preds, "node-pressure", "prod"); got != 1 {
+		t.Errorf("Expected 1 node-pressure prediction for prod, got %d", got)
+	}
+	if got := countBy(preds, "node-pressure", "dev"); got != 2 {
+		t.Errorf("Expected CPU and memory predictions for dev, got %d", got)
+	}
+	if got := countBy(preds, "node-pressure", "down"); got != 0 {
+		t.Errorf("Expected unhealthy clusters to be skipped, got %d", got)
+	}
+	if got := countBy(preds, "crashloop-trend", "prod"); got != 1 {
+		t.Errorf("Expected 1 crashloop prediction, got %d", got)
+	}
+	if got := countBy(preds, "gpu-degradation", "prod"); got != 2 {
+		t.Errorf("Expected saturated and NotReady GPU predictions, got %d", got)
+	}
+	for _, p := range preds {
+		if p.Provider == heuristicProvider+":gpu-degradation" && p.Name == "gpu-2" && p.Severity != severityCritical {
+			t.Errorf("Expected NotReady GPU node to be critical, got %s", p.Severity)
+		}
+	}
+}
+
+func TestPredictionAnalyzers_ConfigAndOverrides(t *testing.T) {
+	disabled := false
+	interval := 60
+	configs := map[string]settings.PredictionAnalyzerConfig{
+		"node-pressure": {
+			Enabled:    true,
+			Thresholds: map[string]float64{"cpuWarning": 90},
+			Clusters: map[string]settings.PredictionAnalyzerOverride{
+				"dev": {Thresholds: map[string]float64{"memoryWarning": 95}},
+			},
+		},
+		"gpu-degradation": {Enabled: false},
+		"crashloop-trend": {
+			Enabled:  true,
+			Clusters: map[string]settings.PredictionAnalyzerOverride{"prod": {Enabled: &disabled, Interval: &interval}},
+		},
+	}
+	w := NewPredictionWorker(nil, nil, nil, nil)
+	w.SetAnalyzerConfigSource(func() map[string]settings.PredictionAnalyzerConfig { return configs })
+
+	preds := w.runAnalyzers(analyzerTestData(), false)
+	if got := countBy(preds, "node-pressure", "dev"); got != 0 {
+		t.Errorf("Expected dev to be below raised thresholds, got %d", got)
+	}
+	if got := countBy(preds, "node-pressure", "prod"); got != 1 {
+		t.Errorf("Expected prod CPU to exceed default critical, got %d", got)
+	}
+	if got := countBy(preds, "gpu-degradation", "prod"); got != 0 {
+		t.Errorf("Expected disabled analyzer not to run, got %d", got)
+	}
+	if got := countBy(preds, "crashloop-trend", "prod"); got != 0 {
+		t.Errorf("Expected per-cluster disable to apply, got %d", got)
+	}
+	if !w.hasEnabledAnalyzers() {
+		t.Error("Expected analyzers to be reported as enabled")
+	}
+}
+
+func TestPredictionAnalyzers_IntervalAndTrend(t *testing.T) {
+	configs := map[string]settings.PredictionAnalyzerConfig{
+		"crashloop-trend": {Enabled: true, Interval: 30},
+	}
+	w := NewPredictionWorker(nil, nil, nil, nil)
+	w.SetAnalyzerConfigSource(func() map[string]settings.PredictionAnalyzerConfig { return configs })
+
+	data := analyzerTestData()
+	first := w.runAnalyzers(data, false)
+
+	// Within the interval the cached result is returned even though restarts changed
+	data.PodIssues[0].Restarts = 12
+	cached := w.runAnalyzers(data, false)
+	if countBy(cached, "crashloop-trend", "prod") != countBy(first, "crashloop-trend", "prod") {
+		t.Fatal("Expected cached crashloop results within interval")
+	}
+
+	configs["crashloop-trend"] = settings.PredictionAnalyzerConfig{Enabled: true}
+	fresh := w.runAnalyzers(data, false)
+	for _, p := range fresh {
+		if p.Provider == heuristicProvider+":crashloop-trend" {
+			if p.Trend != "worsening" || p.Severity != severityCritical {
+				t.Errorf("Expected worsening critical prediction, got trend=%s severity=%s", p.Trend, p.Severity)
+			}
+		}
+	}
+}
+
+func TestValidateAnalyzerConfig(t *testing.T) {
+	a := &nodePressureAnalyzer{}
+	if err := validateAnalyzerConfig(a, settings.PredictionAnalyzerConfig{Thresholds: map[string]float64{"cpuWarning": 70}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := validateAnalyzerConfig(a, settings.PredictionAnalyzerConfig{Thresholds: map[string]float64{"bogus": 1}}); err == nil {
+		t.Error("Expected unknown threshold to be rejected")
+	}
+	negative := -5
+	cfg := settings.PredictionAnalyzerConfig{Clusters: map[string]settings.PredictionAnalyzerOverride{"prod": {Interval: &negative}}}
+	if err := validateAnalyzerConfig(a, cfg); err == nil {
+		t.Error("Expected negative interval to be rejected")
+	}
+}
+
+func TestPDBCoverageAnalyzer(t *testing.T) {
+	a := &pdbCoverageAnalyzer{}
+	data := &ClusterAnalysisData{DisruptionRisks: []k8s.DisruptionRisk{
+		{Name: "api", Namespace: "default", Replicas: 3, Reason: k8s.DisruptionBlocked, PDB: "api-pdb"},
+		{Name: "web", Namespace: "default", Replicas: 3, Reason: k8s.DisruptionUnprotected},
+		{Name: "cron", Namespace: "default", Replicas: 1, Reason: k8s.DisruptionUnprotected},
+	}}
+
+	preds := a.Analyze("prod", data, a.DefaultThresholds())
+	if len(preds) != 2 {
+		t.Fatalf("Expected a blocked and an unprotected prediction, got %d", len(preds))
+	}
+	if preds[0].Name != "api" || preds[0].Category != "reliability-risk" {
+		t.Errorf("Expected the blocked deployment first, got %+v", preds[0])
+	}
+	if preds[1].Reason != "1 deployments without a PDB" {
+		t.Errorf("Expected single-replica deployments to be ignored, got %q", preds[1].Reason)
+	}
+
+	preds = a.Analyze("prod", data, map[string]float64{"minReplicas": 1})
+	if len(preds) != 2 || preds[1].Reason != "2 deployments without a PDB" {
+		t.Errorf("Expected the lower threshold to include cron, got %+v", preds)
+	}
+}
+
+func TestCronMissedAnalyzer(t *testing.T) {
+	a := &cronMissedAnalyzer{}
+	now := time.Now()
+	data := &ClusterAnalysisData{MissedCronJobs: []k8s.CronJob{
+		{Name: "backup", Namespace: "ops", Schedule: "0 * * * *", MissedSince: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+		{Name: "report", Namespace: "ops", Schedule: "*/5 * * * *", MissedSince: now.Add(-10 * time.Minute).Format(time.RFC3339)},
+		{Name: "cleanup", Namespace: "ops", Schedule: "* * * * *", MissedSince: now.Add(-3 * time.Minute).Format(time.RFC3339)},
+	}}
+
+	preds := a.Analyze("prod", data, a.DefaultThresholds())
+	if len(preds) != 2 {
+		t.Fatalf("Expected 2 predictions within the grace threshold, got %d", len(preds))
+	}
+	if preds[0].Name != "backup" || preds[0].Severity != severityCritical || preds[0].Namespace != "ops" {
+		t.Errorf("Expected backup to be critical, got %+v", preds[0])
+	}
+	if preds[1].Name != "report" || preds[1].Severity != severityWarning {
+		t.Errorf("Expected report to be a warning, got %+v", preds[1])
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
 )
 
 const (
@@ -42,7 +43,7 @@ func DefaultPredictionSettings() PredictionSettings {
 // AIPrediction represents an AI-generated prediction
 type AIPrediction struct {
 	ID             string `json:"id"`
-	Category       string `json:"category"`       // pod-crash, resource-trend, capacity-risk, anomaly
+	Category       string `json:"category"`       // pod-crash, resource-trend, capacity-risk, reliability-risk, anomaly
 	Severity       string `json:"severity"`       // warning, critical
 	Name           string `json:"name"`           // affected resource name
 	Cluster        string `json:"cluster"`        // cluster name
@@ -86,6 +87,19 @@ type PredictionWorker struct {
 	// Token tracking callback
 	trackTokens        func(usage *ProviderTokenUsage)
 	loggedClusterError bool // suppress repeated "no kubeconfig" errors
+
+	// Optional per-provider budget hooks
+	checkBudget  func(provider string) error
+	recordBudget func(provider string, usage *ProviderTokenUsage)
+
+	// Rule-based analyzers and their configuration source
+	analyzers       []PredictionAnalyzer
+	analyzerConfigs func() map[string]settings.PredictionAnalyzerConfig
+	analyzerLastRun map[string]time.Time     // analyzer|cluster → last run
+	analyzerResults map[string][]AIPrediction // analyzer|cluster → predictions from last run
+
+	// Optional filter that drops predictions covered by an alert silence
+	silenced func(p AIPrediction) bool
 }
 
 // NewPredictionWorker creates a new prediction worker
@@ -99,7 +113,54 @@ func NewPredictionWorker(k8sClient *k8s.MultiClusterClient, registry *Registry,
 		stopCh:      make(chan struct{}),
 		broadcast:   broadcast,
 		trackTokens: trackTokens,
+
+		analyzers:       defaultPredictionAnalyzers(),
+		analyzerLastRun: make(map[string]time.Time),
+		analyzerResults: make(map[string][]AIPrediction),
+	}
+}
+
+// RegisterAnalyzer adds an analyzer, replacing any existing analyzer with the same name
+func (w *PredictionWorker) RegisterAnalyzer(a PredictionAnalyzer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, existing := range w.analyzers {
+		if existing.Name() == a.Name() {
+			w.analyzers[i] = a
+			return
+		}
 	}
+	w.analyzers = append(w.analyzers, a)
+}
+
+// Analyzers returns the registered analyzers
+func (w *PredictionWorker) Analyzers() []PredictionAnalyzer {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return append([]PredictionAnalyzer(nil), w.analyzers...)
+}
+
+// SetSilenceFilter sets a function that hides predictions matching an alert silence
+func (w *PredictionWorker) SetSilenceFilter(silenced func(p AIPrediction) bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.silenced = silenced
+}
+
+// SetAnalyzerConfigSource sets the function used to read analyzer configuration.
+// It is called on every analysis pass so changes apply without a restart.
+func (w *PredictionWorker) SetAnalyzerConfigSource(configs func() map[string]settings.PredictionAnalyzerConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.analyzerConfigs = configs
+}
+
+// SetBudgetHooks wires per-provider token budget enforcement into analysis runs
+func (w *PredictionWorker) SetBudgetHooks(check func(provider string) error, record func(provider string, usage *ProviderTokenUsage)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checkBudget = check
+	w.recordBudget = record
 }
 
 // Start begins the background analysis loop
@@ -190,21 +251,7 @@ func (w *PredictionWorker) runLoop() {
 	time.Sleep(predictionInitialDelay)
 
 	for {
-		w.mu.RLock()
-		settings := w.settings
-		w.mu.RUnlock()
-
-		if settings.AIEnabled {
-			w.mu.Lock()
-			if !w.running {
-				w.running = true
-				w.mu.Unlock()
-				w.runAnalysis(nil)
-				w.mu.Lock()
-				w.running = false
-			}
-			w.mu.Unlock()
-		}
+		settings := w.runScheduled()
 
 		// Wait for next interval or stop signal
 		interval := time.Duration(settings.Interval) * time.Minute
@@ -218,8 +265,36 @@ func (w *PredictionWorker) runLoop() {
 	}
 }
 
+// runScheduled runs one scheduled analysis: with AI when it is enabled,
+// otherwise only the analyzers explicitly enabled in their config. It returns
+// the settings it ran with.
+func (w *PredictionWorker) runScheduled() PredictionSettings {
+	w.mu.RLock()
+	settings := w.settings
+	w.mu.RUnlock()
+
+	if settings.AIEnabled || w.hasEnabledAnalyzers() {
+		w.mu.Lock()
+		if !w.running {
+			w.running = true
+			w.mu.Unlock()
+			w.analyze(nil, settings.AIEnabled)
+			w.mu.Lock()
+			w.running = false
+		}
+		w.mu.Unlock()
+	}
+	return settings
+}
+
 // runAnalysis performs the AI analysis
 func (w *PredictionWorker) runAnalysis(specificProviders []string) {
+	w.analyze(specificProviders, true)
+}
+
+// analyze gathers cluster data, runs the enabled analyzers and, if useAI is set,
+// the AI providers, then publishes the combined predictions
+func (w *PredictionWorker) analyze(specificProviders []string, useAI bool) {
 	log.Println("[PredictionWorker] Starting AI prediction analysis")
 
 	// Gather cluster data
@@ -235,20 +310,32 @@ func (w *PredictionWorker) runAnalysis(specificProviders []string) {
 		return
 	}
 
-	// Build prompt
-	prompt := w.buildAnalysisPrompt(clusterData)
+	// Without AI only analyzers the user turned on run, so a default install
+	// with AI disabled stays quiet
+	heuristics := w.runAnalyzers(clusterData, !useAI)
 
 	// Get providers to use
-	providers := specificProviders
-	if len(providers) == 0 {
-		providers = w.getAvailableProviders()
+	var providers []string
+	if useAI {
+		providers = specificProviders
+		if len(providers) == 0 {
+			providers = w.getAvailableProviders()
+		}
+		if len(providers) == 0 {
+			log.Println("[PredictionWorker] No AI providers available")
+		}
 	}
 
-	if len(providers) == 0 {
-		log.Println("[PredictionWorker] No AI providers available")
+	if len(providers) == 0 && !w.hasEnabledAnalyzers() {
 		return
 	}
 
+	// Build prompt
+	prompt := ""
+	if len(providers) > 0 {
+		prompt = w.buildAnalysisPrompt(clusterData)
+	}
+
 	// Run analysis on each provider
 	allPredictions := make(map[string][]AIPrediction)
 	usedProviders := []string{}
@@ -257,6 +344,7 @@ func (w *PredictionWorker) runAnalysis(specificProviders []string) {
 	consensusMode := w.settings.ConsensusMode
 	minConfidence := w.settings.MinConfidence
 	maxPredictions := w.settings.MaxPredictions
+	silenced := w.silenced
 	w.mu.RUnlock()
 
 	for _, providerName := range providers {
@@ -280,12 +368,27 @@ func (w *PredictionWorker) runAnalysis(specificProviders []string) {
 		}
 	}
 
-	// Merge predictions
-	merged := w.mergePredictions(allPredictions, consensusMode)
+	// Merge predictions; analyzer findings take precedence over AI duplicates
+	merged := append([]AIPrediction{}, heuristics...)
+	seen := make(map[string]bool, len(heuristics))
+	for _, p := range heuristics {
+		seen[fmt.Sprintf("%s-%s-%s", p.Category, p.Name, p.Cluster)] = true
+	}
+	for _, p := range w.mergePredictions(allPredictions, consensusMode) {
+		if !seen[fmt.Sprintf("%s-%s-%s", p.Category, p.Name, p.Cluster)] {
+			merged = append(merged, p)
+		}
+	}
+	if len(heuristics) > 0 {
+		sortPredictions(merged)
+	}
 
 	// Filter by confidence and limit
 	filtered := []AIPrediction{}
 	for _, p := range merged {
+		if silenced != nil && silenced(p) {
+			continue
+		}
 		if p.Confidence >= minConfidence {
 			filtered = append(filtered, p)
 		}
@@ -313,12 +416,101 @@ func (w *PredictionWorker) runAnalysis(specificProviders []string) {
 	}
 }
 
+// hasEnabledAnalyzers reports whether any analyzer is explicitly enabled
+// globally or for some cluster. Analyzers without a config don't count.
+func (w *PredictionWorker) hasEnabledAnalyzers() bool {
+	w.mu.RLock()
+	analyzers, source := w.analyzers, w.analyzerConfigs
+	w.mu.RUnlock()
+
+	var configs map[string]settings.PredictionAnalyzerConfig
+	if source != nil {
+		configs = source()
+	}
+	for _, a := range analyzers {
+		cfg, ok := configs[a.Name()]
+		if !ok {
+			continue
+		}
+		if cfg.Enabled {
+			return true
+		}
+		for _, override := range cfg.Clusters {
+			if override.Enabled != nil && *override.Enabled {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runAnalyzers runs each enabled analyzer against each healthy cluster. Analyzers
+// whose interval has not elapsed for a cluster reuse their previous results.
+// With configuredOnly, analyzers without a config are skipped rather than run
+// with their defaults.
+func (w *PredictionWorker) runAnalyzers(data *ClusterAnalysisData, configuredOnly bool) []AIPrediction {
+	w.mu.RLock()
+	analyzers, source := w.analyzers, w.analyzerConfigs
+	w.mu.RUnlock()
+
+	var configs map[string]settings.PredictionAnalyzerConfig
+	if source != nil {
+		configs = source()
+	}
+
+	now := time.Now()
+	var out []AIPrediction
+	for _, a := range analyzers {
+		var cfg *settings.PredictionAnalyzerConfig
+		if c, ok := configs[a.Name()]; ok {
+			cfg = &c
+		} else if configuredOnly {
+			continue
+		}
+		for _, c := range data.Clusters {
+			if !c.Healthy {
+				continue
+			}
+			key := a.Name() + "|" + c.Name
+			eff := resolveAnalyzerSettings(a, cfg, c.Name)
+			if !eff.Enabled {
+				w.mu.Lock()
+				delete(w.analyzerResults, key)
+				delete(w.analyzerLastRun, key)
+				w.mu.Unlock()
+				continue
+			}
+
+			w.mu.RLock()
+			last, ran := w.analyzerLastRun[key]
+			cached := w.analyzerResults[key]
+			w.mu.RUnlock()
+			if ran && eff.Interval > 0 && now.Sub(last) < eff.Interval {
+				out = append(out, cached...)
+				continue
+			}
+
+			results := a.Analyze(c.Name, clusterSubset(data, c.Name), eff.Thresholds)
+			w.mu.Lock()
+			w.analyzerLastRun[key] = now
+			w.analyzerResults[key] = results
+			w.mu.Unlock()
+			out = append(out, results...)
+		}
+	}
+	return out
+}
+
 // ClusterAnalysisData holds data for AI analysis
 type ClusterAnalysisData struct {
 	Clusters     []ClusterSummary `json:"clusters"`
 	PodIssues    []PodIssueSummary `json:"podIssues"`
 	GPUNodes     []GPUNodeSummary `json:"gpuNodes"`
 	OfflineNodes []NodeSummary    `json:"offlineNodes"`
+	// DisruptionRisks feeds the PDB coverage check; it is not sent to AI providers
+	DisruptionRisks []k8s.DisruptionRisk `json:"disruptionRisks,omitempty"`
+	// MissedCronJobs feeds the missed schedule check; it is not sent to AI providers
+	MissedCronJobs []k8s.CronJob `json:"missedCronJobs,omitempty"`
 	Timestamp    string           `json:"timestamp"`
 }
 
@@ -473,6 +665,40 @@ func (w *PredictionWorker) gatherClusterData(ctx context.Context) (*ClusterAnaly
 		}
 	}
 
+	// Get deployments at risk during node drains from healthy clusters only
+	for _, cluster := range clusters {
+		if !healthyClusterSet[cluster.Name] {
+			continue
+		}
+		risks, err := w.k8sClient.FindDisruptionRisks(ctx, cluster.Context, "")
+		if err != nil {
+			log.Printf("[PredictionWorker] Error getting disruption risks for %s: %v", cluster.Name, err)
+			continue
+		}
+		for _, r := range risks {
+			r.Cluster = cluster.Name
+			data.DisruptionRisks = append(data.DisruptionRisks, r)
+		}
+	}
+
+	// Get CronJobs that missed a scheduled run from healthy clusters only
+	for _, cluster := range clusters {
+		if !healthyClusterSet[cluster.Name] {
+			continue
+		}
+		cronJobs, err := w.k8sClient.GetCronJobs(ctx, cluster.Context, "")
+		if err != nil {
+			log.Printf("[PredictionWorker] Error getting cronjobs for %s: %v", cluster.Name, err)
+			continue
+		}
+		for _, cj := range cronJobs {
+			if cj.Missed {
+				cj.Cluster = cluster.Name
+				data.MissedCronJobs = append(data.MissedCronJobs, cj)
+			}
+		}
+	}
+
 	return data, nil
 }
 
@@ -540,6 +766,16 @@ func (w *PredictionWorker) getAvailableProviders() []string {
 }
 
 func (w *PredictionWorker) analyzeWithProvider(ctx context.Context, provider AIProvider, prompt string) ([]AIPrediction, error) {
+	w.mu.RLock()
+	checkBudget, recordBudget := w.checkBudget, w.recordBudget
+	w.mu.RUnlock()
+
+	if checkBudget != nil {
+		if err := checkBudget(provider.Name()); err != nil {
+			return nil, err
+		}
+	}
+
 	// Use the provider's chat interface
 	req := &ChatRequest{
 		SessionID: fmt.Sprintf("prediction-%d", time.Now().Unix()),
@@ -558,6 +794,9 @@ func (w *PredictionWorker) analyzeWithProvider(ctx context.Context, provider AIP
 	if w.trackTokens != nil && resp.TokenUsage != nil {
 		w.trackTokens(resp.TokenUsage)
 	}
+	if recordBudget != nil && resp.TokenUsage != nil {
+		recordBudget(provider.Name(), resp.TokenUsage)
+	}
 
 	// Parse response
 	return w.parseAIPredictions(resp.Content, provider.Name())
@@ -679,6 +918,7 @@ func (s *Server) BroadcastToClients(msgType string, payload interface{}) {
 		log.Printf("[Server] Error marshaling broadcast message: %v", err)
 		return
 	}
+	s.publishEvent(msgType, payload)
 
 	s.wsMux.Lock()
 	defer s.wsMux.Unlock()
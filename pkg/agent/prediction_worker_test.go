@@ -121,3 +121,32 @@ func TestPredictionWorker(t *testing.T) {
 		t.Error("Worker still running analysis")
 	}
 }
+
+func TestPredictionWorker_AIDisabledWithoutAnalyzerConfig(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{
+		Contexts: map[string]*api.Context{"c1": {Cluster: "cl1"}},
+		Clusters: map[string]*api.Cluster{"cl1": {Server: "s1"}},
+	})
+	m.InjectClient("c1", k8sfake.NewSimpleClientset())
+
+	broadcasts := 0
+	worker := NewPredictionWorker(m, nil, func(string, interface{}) { broadcasts++ }, nil)
+	settings := DefaultPredictionSettings()
+	settings.AIEnabled = false
+	worker.UpdateSettings(settings)
+
+	if worker.hasEnabledAnalyzers() {
+		t.Error("Expected analyzers without a config not to count as enabled")
+	}
+	worker.runScheduled()
+	if broadcasts != 0 {
+		t.Errorf("Expected no broadcast with AI disabled and no analyzer configured, got %d", broadcasts)
+	}
+	worker.mu.RLock()
+	ran := !worker.lastRun.IsZero()
+	worker.mu.RUnlock()
+	if ran {
+		t.Error("Expected no analysis with AI disabled and no analyzer configured")
+	}
+}
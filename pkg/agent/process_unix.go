@@ -0,0 +1,102 @@
+//go:build !windows
+
+package agent
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group, so it outlives the
+// agent and killProcessTree can reach its children (e.g. go run's binary)
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessTree kills pid's process group, or pid alone when it leads none
+func killProcessTree(pid int) error {
+	if err := syscall.Kill(-pid, syscall.SIGKILL); err == nil {
+		return nil
+	}
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
+
+// listeningPIDs returns the processes listening on the local TCP port. Linux
+// reads /proc; other Unixes ask lsof for LISTEN sockets only, so connected
+// clients (browsers, proxies) are never matched.
+func listeningPIDs(port int) ([]int, error) {
+	if runtime.GOOS == "linux" {
+		return procListeningPIDs(port)
+	}
+	out, err := exec.Command("lsof", "-ti", ":"+strconv.Itoa(port), "-sTCP:LISTEN").Output()
+	if err != nil {
+		// lsof exits 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(out)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// procListeningPIDs maps the port's listening socket inodes to the processes
+// holding them. Processes of other users are skipped (their fds are unreadable).
+func procListeningPIDs(port int) ([]int, error) {
+	inodes := map[string]bool{}
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(table)
+		if err != nil {
+			continue
+		}
+		for inode := range parseProcNetTCP(data, port) {
+			inodes[inode] = true
+		}
+	}
+	if len(inodes) == 0 {
+		return nil, nil
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, p := range procs {
+		pid, err := strconv.Atoi(p.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", p.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			if inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")] {
+				pids = append(pids, pid)
+				break
+			}
+		}
+	}
+	return pids, nil
+}
+
+// execSelf replaces the running process with binary
+func execSelf(binary string, args, env []string) error {
+	return syscall.Exec(binary, args, env)
+}
@@ -0,0 +1,49 @@
+//go:build windows
+
+package agent
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in a new process group so console signals sent
+// to the agent do not reach it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killProcessTree kills pid and its children
+func killProcessTree(pid int) error {
+	return exec.Command("taskkill", "/PID", strconv.Itoa(pid), "/T", "/F").Run()
+}
+
+// listeningPIDs returns the processes listening on the local TCP port
+func listeningPIDs(port int) ([]int, error) {
+	out, err := exec.Command("netstat", "-ano", "-p", "TCP").Output()
+	if err != nil {
+		return nil, err
+	}
+	pids := parseNetstatListeners(out, port)
+	// netstat -p TCP omits IPv6 listeners
+	if out6, err := exec.Command("netstat", "-ano", "-p", "TCPv6").Output(); err == nil {
+		pids = append(pids, parseNetstatListeners(out6, port)...)
+	}
+	return pids, nil
+}
+
+// execSelf starts binary as a new process and exits; Windows has no exec
+func execSelf(binary string, args, env []string) error {
+	cmd := exec.Command(binary, args[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
@@ -0,0 +1,257 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+)
+
+// AgentFileProfile bundles the settings of one environment (a customer, a fleet,
+// the home lab) under agent.yaml's profiles. The active profile's kubeconfig,
+// cluster filter and default agent replace the top-level ones, its providers
+// replace the same-named top-level providers and its origins are added to the
+// top-level origins. Profiles switch at runtime via POST /profiles/active.
+type AgentFileProfile struct {
+	Kubeconfig     string                       `yaml:"kubeconfig,omitempty"`
+	Clusters       AgentFileClusters            `yaml:"clusters,omitempty"`
+	Providers      map[string]AgentFileProvider `yaml:"providers,omitempty"`
+	DefaultAgent   string                       `yaml:"defaultAgent,omitempty"`
+	AllowedOrigins []string                     `yaml:"allowedOrigins,omitempty"`
+}
+
+// withProfile returns the file with the named profile applied; "" returns f
+func (f *AgentFile) withProfile(name string) (*AgentFile, error) {
+	if name == "" {
+		return f, nil
+	}
+	p, ok := f.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+
+	merged := *f
+	if p.Kubeconfig != "" {
+		merged.Kubeconfig = p.Kubeconfig
+	}
+	if len(p.Clusters.Include) > 0 || len(p.Clusters.Exclude) > 0 {
+		merged.Clusters = p.Clusters
+	}
+	if p.DefaultAgent != "" {
+		merged.DefaultAgent = p.DefaultAgent
+	}
+	if len(p.Providers) > 0 {
+		merged.Providers = make(map[string]AgentFileProvider, len(f.Providers)+len(p.Providers))
+		for name, provider := range f.Providers {
+			merged.Providers[name] = provider
+		}
+		for name, provider := range p.Providers {
+			merged.Providers[name] = provider
+		}
+	}
+	merged.AllowedOrigins = append(append([]string{}, f.AllowedOrigins...), p.AllowedOrigins...)
+	return &merged, nil
+}
+
+// profileKubeconfig returns the kubeconfig of the named profile, or base when
+// the profile doesn't set one
+func profileKubeconfig(f *AgentFile, name, base string) string {
+	if p, ok := f.Profiles[name]; ok && name != "" && p.Kubeconfig != "" {
+		return p.Kubeconfig
+	}
+	return base
+}
+
+// activateProfile switches to the named profile ("" returns to the top-level
+// settings). Nothing changes if the profile's kubeconfig can't be loaded.
+func (s *Server) activateProfile(name string) error {
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	effective, err := s.agentFile.withProfile(name)
+	if err != nil {
+		return err
+	}
+	previous, err := s.agentFile.withProfile(s.activeProfile)
+	if err != nil {
+		previous = s.agentFile
+	}
+
+	s.applyAgentFile(effective)
+	changed, err := s.useKubeconfig(profileKubeconfig(s.agentFile, name, s.baseKubeconfig))
+	if err != nil {
+		s.applyAgentFile(previous)
+		return err
+	}
+	if !changed {
+		// Same kubeconfig, but the cluster filter may differ
+		s.kubeconfigReloaded()
+	}
+
+	s.activeProfile = name
+	if name == "" {
+		log.Printf("[Profiles] Deactivated profile, using the top-level settings")
+	} else {
+		log.Printf("[Profiles] Activated profile %q", name)
+	}
+	s.BroadcastToClients("profile_changed", map[string]string{"profile": name})
+	return nil
+}
+
+// reloadProfiles applies a reloaded agent.yaml with the active profile. A
+// profile that was removed from the file is deactivated.
+func (s *Server) reloadProfiles(file *AgentFile) {
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	s.agentFile = file
+	effective, err := file.withProfile(s.activeProfile)
+	if err != nil {
+		log.Printf("[Profiles] Profile %q is no longer configured, using the top-level settings", s.activeProfile)
+		s.activeProfile = ""
+		effective = file
+	}
+	s.applyAgentFile(effective)
+	if _, err := s.useKubeconfig(profileKubeconfig(file, s.activeProfile, s.baseKubeconfig)); err != nil {
+		log.Printf("[Profiles] Keeping kubeconfig %s: %v", s.kubectl.GetKubeconfigPath(), err)
+	}
+}
+
+// useKubeconfig points kubectl and the cluster client at path, reporting
+// whether it differed from the current kubeconfig
+func (s *Server) useKubeconfig(path string) (bool, error) {
+	previous := s.kubectl.GetKubeconfigPath()
+	if path == previous {
+		return false, nil
+	}
+	if err := s.kubectl.SetKubeconfig(path); err != nil {
+		return false, fmt.Errorf("failed to load kubeconfig %s: %w", path, err)
+	}
+	if s.k8sClient == nil {
+		s.kubeconfigReloaded()
+		return true, nil
+	}
+	// SetKubeconfig notifies kubeconfigReloaded
+	if err := s.k8sClient.SetKubeconfig(path); err != nil {
+		s.kubectl.SetKubeconfig(previous) //nolint:errcheck
+		return false, err
+	}
+	return true, nil
+}
+
+// ProfileInfo describes a configured profile. Provider API keys are not included.
+type ProfileInfo struct {
+	Name           string            `json:"name"`
+	Kubeconfig     string            `json:"kubeconfig,omitempty"`
+	Clusters       AgentFileClusters `json:"clusters"`
+	Providers      []string          `json:"providers,omitempty"`
+	DefaultAgent   string            `json:"defaultAgent,omitempty"`
+	AllowedOrigins []string          `json:"allowedOrigins,omitempty"`
+	Active         bool              `json:"active"`
+}
+
+// ProfilesResponse is returned by GET /profiles
+type ProfilesResponse struct {
+	Active     string        `json:"active"` // "" when no profile is active
+	Kubeconfig string        `json:"kubeconfig"`
+	Profiles   []ProfileInfo `json:"profiles"`
+}
+
+func (s *Server) profiles() ProfilesResponse {
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	resp := ProfilesResponse{
+		Active:     s.activeProfile,
+		Kubeconfig: s.kubectl.GetKubeconfigPath(),
+		Profiles:   []ProfileInfo{},
+	}
+	for name, p := range s.agentFile.Profiles {
+		info := ProfileInfo{
+			Name:           name,
+			Kubeconfig:     p.Kubeconfig,
+			Clusters:       p.Clusters,
+			DefaultAgent:   p.DefaultAgent,
+			AllowedOrigins: p.AllowedOrigins,
+			Active:         name == s.activeProfile,
+		}
+		for provider := range p.Providers {
+			info.Providers = append(info.Providers, provider)
+		}
+		sort.Strings(info.Providers)
+		resp.Profiles = append(resp.Profiles, info)
+	}
+	sort.Slice(resp.Profiles, func(i, j int) bool { return resp.Profiles[i].Name < resp.Profiles[j].Name })
+	return resp
+}
+
+// handleProfiles lists the profiles configured in agent.yaml and the active one
+func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	json.NewEncoder(w).Encode(s.profiles())
+}
+
+// handleActiveProfile switches the active profile: POST {"name": "acme"}, or
+// {"name": ""} to return to the top-level settings
+func (s *Server) handleActiveProfile(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method == "GET" {
+		json.NewEncoder(w).Encode(s.profiles())
+		return
+	}
+	if r.Method != "POST" && r.Method != "PUT" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	s.profileMu.Lock()
+	_, known := s.agentFile.Profiles[req.Name]
+	s.profileMu.Unlock()
+	if req.Name != "" && !known {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("unknown profile %q", req.Name)})
+		return
+	}
+	if err := s.activateProfile(req.Name); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(s.profiles())
+}
@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const testProfilesAgentFile = `allowedOrigins:
+  - https://console.example.com
+defaultAgent: claude
+providers:
+  claude:
+    model: base-model
+  openai:
+    model: gpt-base
+clusters:
+  exclude: ["kind-.*"]
+profiles:
+  acme:
+    kubeconfig: %s
+    allowedOrigins:
+      - https://console.acme.example.com
+    providers:
+      claude:
+        apiKey: acme-key
+    clusters:
+      include: ["acme-.*"]
+  home:
+    defaultAgent: openai
+`
+
+func writeTestKubeconfig(t *testing.T, path string, contexts ...string) {
+	t.Helper()
+	config := api.NewConfig()
+	for _, name := range contexts {
+		config.Clusters[name] = &api.Cluster{Server: "https://" + name + ".example.com:6443"}
+		config.AuthInfos[name] = &api.AuthInfo{Token: "token"}
+		config.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name}
+		config.CurrentContext = name
+	}
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAgentFileWithProfile(t *testing.T) {
+	f, err := LoadAgentFile(writeTempAgentFile(t, strings.Replace(testProfilesAgentFile, "%s", "/tmp/acme.yaml", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acme, err := f.withProfile("acme")
+	if err != nil {
+		t.Fatalf("withProfile failed: %v", err)
+	}
+	if acme.Kubeconfig != "/tmp/acme.yaml" || acme.DefaultAgent != "claude" {
+		t.Errorf("Unexpected kubeconfig or default agent: %+v", acme)
+	}
+	if len(acme.AllowedOrigins) != 2 || acme.AllowedOrigins[1] != "https://console.acme.example.com" {
+		t.Errorf("Expected the profile origins to be added, got %v", acme.AllowedOrigins)
+	}
+	if acme.Providers["claude"].APIKey != "acme-key" || acme.Providers["openai"].Model != "gpt-base" {
+		t.Errorf("Expected the profile providers over the top-level ones, got %+v", acme.Providers)
+	}
+	if len(acme.Clusters.Include) != 1 || len(acme.Clusters.Exclude) != 0 {
+		t.Errorf("Expected the profile cluster filter, got %+v", acme.Clusters)
+	}
+	if f.Providers["claude"].APIKey != "" || len(f.AllowedOrigins) != 1 {
+		t.Error("Expected withProfile to leave the file unchanged")
+	}
+
+	home, _ := f.withProfile("home")
+	if home.DefaultAgent != "openai" || len(home.Clusters.Exclude) != 1 {
+		t.Errorf("Expected home to keep the top-level clusters, got %+v", home)
+	}
+	if _, err := f.withProfile("missing"); err == nil {
+		t.Error("Expected an unknown profile to be rejected")
+	}
+}
+
+func TestActivateProfile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "config")
+	acmeKubeconfig := filepath.Join(dir, "acme.yaml")
+	writeTestKubeconfig(t, base, "kind-dev")
+	writeTestKubeconfig(t, acmeKubeconfig, "acme-prod")
+
+	f, err := LoadAgentFile(writeTempAgentFile(t, strings.Replace(testProfilesAgentFile, "%s", acmeKubeconfig, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubectl, _ := NewKubectlProxy(base)
+	s := &Server{kubectl: kubectl, agentFile: f, baseKubeconfig: base}
+	s.applyAgentFile(f)
+
+	if err := s.activateProfile("acme"); err != nil {
+		t.Fatalf("activateProfile failed: %v", err)
+	}
+	if got := s.kubectl.GetKubeconfigPath(); got != acmeKubeconfig || s.kubectl.GetCurrentContext() != "acme-prod" {
+		t.Errorf("Expected the acme kubeconfig, got %s (%s)", got, s.kubectl.GetCurrentContext())
+	}
+	if !s.isAllowedOrigin("https://console.acme.example.com") {
+		t.Error("Expected the acme origin to be allowed")
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleActiveProfile(rec, httptest.NewRequest("POST", "/profiles/active", strings.NewReader(`{"name":"missing"}`)))
+	if rec.Code != http.StatusNotFound || s.activeProfile != "acme" {
+		t.Errorf("Expected an unknown profile to be rejected, got %d (active %q)", rec.Code, s.activeProfile)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleActiveProfile(rec, httptest.NewRequest("POST", "/profiles/active", strings.NewReader(`{"name":""}`)))
+	if rec.Code != http.StatusOK || s.activeProfile != "" || s.kubectl.GetKubeconfigPath() != base {
+		t.Errorf("Expected to return to the base kubeconfig, got %d (%s)", rec.Code, s.kubectl.GetKubeconfigPath())
+	}
+	if s.isAllowedOrigin("https://console.acme.example.com") {
+		t.Error("Expected the acme origin to be dropped")
+	}
+
+	resp := s.profiles()
+	if len(resp.Profiles) != 2 || resp.Profiles[0].Name != "acme" || resp.Profiles[0].Providers[0] != "claude" {
+		t.Errorf("Unexpected profiles: %+v", resp)
+	}
+
+	// A profile whose kubeconfig can't be loaded leaves everything as it was
+	f.Profiles["broken"] = AgentFileProfile{Kubeconfig: filepath.Join(dir, "missing"), AllowedOrigins: []string{"https://broken.example.com"}}
+	if err := s.activateProfile("broken"); err == nil {
+		t.Error("Expected a missing kubeconfig to fail")
+	}
+	if s.activeProfile != "" || s.isAllowedOrigin("https://broken.example.com") || s.kubectl.GetKubeconfigPath() != base {
+		t.Error("Expected a failed switch to keep the previous settings")
+	}
+}
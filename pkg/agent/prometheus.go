@@ -1,12 +1,14 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"k8s.io/client-go/rest"
@@ -67,33 +69,26 @@ func (s *Server) handlePrometheusQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build the K8s API server proxy URL to reach Prometheus
-	proxyPath := fmt.Sprintf("/api/v1/namespaces/%s/services/%s:%s/proxy/api/v1/query",
-		url.PathEscape(namespace),
-		url.PathEscape(serviceName),
-		prometheusServicePort,
-	)
-
 	params := url.Values{}
 	params.Set("query", query)
 	if queryTime != "" {
 		params.Set("time", queryTime)
 	}
 
-	fullURL := fmt.Sprintf("%s%s?%s", config.Host, proxyPath, params.Encode())
+	fullURL := prometheusProxyURL(config, namespace, serviceName, params)
 
-	// Create an HTTP client with the cluster's TLS/auth config
-	transport, err := rest.TransportFor(config)
+	// Reuse the cluster's shared transport, with its TLS/auth config and pooled connection
+	httpClient, err := s.k8sClient.GetHTTPClient(cluster)
 	if err != nil {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status": "error",
-			"error":  fmt.Sprintf("failed to create transport: %v", err),
+			"error":  fmt.Sprintf("failed to get cluster client: %v", err),
 		})
 		return
 	}
 
 	client := &http.Client{
-		Transport: transport,
+		Transport: httpClient.Transport,
 		Timeout:   prometheusQueryTimeout,
 	}
 
@@ -113,3 +108,90 @@ func (s *Server) handlePrometheusQuery(w http.ResponseWriter, r *http.Request) {
 		log.Printf("failed to stream Prometheus response: %v", copyErr)
 	}
 }
+
+// prometheusProxyURL builds the K8s API server service proxy URL for a Prometheus instant query
+func prometheusProxyURL(config *rest.Config, namespace, serviceName string, params url.Values) string {
+	proxyPath := fmt.Sprintf("/api/v1/namespaces/%s/services/%s:%s/proxy/api/v1/query",
+		url.PathEscape(namespace),
+		url.PathEscape(serviceName),
+		prometheusServicePort,
+	)
+	return fmt.Sprintf("%s%s?%s", config.Host, proxyPath, params.Encode())
+}
+
+// prometheusSample is one series of an instant vector result
+type prometheusSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// queryPrometheusVector runs an instant query against a cluster's Prometheus and
+// returns the vector result. Used by modules that enrich data with metrics.
+func (s *Server) queryPrometheusVector(ctx context.Context, cluster, namespace, serviceName, query string) ([]prometheusSample, error) {
+	config, err := s.k8sClient.GetRestConfig(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster config: %w", err)
+	}
+	httpClient, err := s.k8sClient.GetHTTPClient(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster client: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("query", query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, prometheusProxyURL(config, namespace, serviceName, params), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: httpClient.Transport, Timeout: prometheusQueryTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prometheus returned %s", resp.Status)
+	}
+	return parsePrometheusVector(resp.Body)
+}
+
+// parsePrometheusVector decodes a Prometheus /api/v1/query vector response
+func parsePrometheusVector(body io.Reader) ([]prometheusSample, error) {
+	var result struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric map[string]string `json:"metric"`
+				Value  []interface{}     `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid prometheus response: %w", err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus query error: %s", result.Error)
+	}
+	if result.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected prometheus result type %q", result.Data.ResultType)
+	}
+
+	samples := make([]prometheusSample, 0, len(result.Data.Result))
+	for _, r := range result.Data.Result {
+		if len(r.Value) != 2 {
+			continue
+		}
+		str, ok := r.Value[1].(string)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, prometheusSample{Labels: r.Metric, Value: v})
+	}
+	return samples, nil
+}
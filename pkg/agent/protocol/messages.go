@@ -14,6 +14,8 @@ const (
 	TypeSelectAgent   MessageType = "select_agent"   // Select an AI agent
 	TypeCancelChat    MessageType = "cancel_chat"    // Cancel in-progress chat
 	TypeRenameContext MessageType = "rename_context"
+	TypeCommandApproval MessageType = "command_approval" // Approve/reject AI-proposed commands
+	TypeCancel        MessageType = "cancel"         // Cancel an in-flight request by its message ID
 
 	// Response types
 	TypeResult        MessageType = "result"
@@ -22,6 +24,7 @@ const (
 	TypeProgress      MessageType = "progress"       // Tool activity/progress events
 	TypeAgentSelected MessageType = "agent_selected" // Agent selection confirmed
 	TypeAgentsList    MessageType = "agents_list"    // List of available agents
+	TypeCommandApprovalRequest MessageType = "command_approval_request" // AI-proposed commands awaiting review
 )
 
 // Message is the base message structure for WebSocket communication
@@ -40,6 +43,9 @@ type HealthPayload struct {
 	Claude             *ClaudeInfo       `json:"claude,omitempty"`
 	InstallMethod      string            `json:"install_method,omitempty"`
 	AvailableProviders []ProviderSummary `json:"availableProviders,omitempty"`
+	LeaderElection     string            `json:"leaderElection,omitempty"` // leader or follower, when enabled
+	NamespaceScope     string            `json:"namespaceScope,omitempty"` // namespaces or discover, when queries are namespace-scoped
+	Features           map[string]bool   `json:"features,omitempty"`       // feature flags, so the UI can hide disabled subsystems
 }
 
 // ProviderSummary is a lightweight view of a detected AI provider for telemetry
@@ -78,13 +84,17 @@ type ClustersPayload struct {
 
 // ClusterInfo represents a kubeconfig context
 type ClusterInfo struct {
-	Name       string `json:"name"`
-	Context    string `json:"context"`
-	Server     string `json:"server"`
-	User       string `json:"user,omitempty"`
-	Namespace  string `json:"namespace,omitempty"`
-	AuthMethod string `json:"authMethod,omitempty"` // exec, token, certificate, auth-provider, unknown
-	IsCurrent  bool   `json:"isCurrent"`
+	Name        string   `json:"name"`
+	Context     string   `json:"context"`
+	Server      string   `json:"server"`
+	User        string   `json:"user,omitempty"`
+	Namespace   string   `json:"namespace,omitempty"`
+	AuthMethod  string   `json:"authMethod,omitempty"` // exec, token, certificate, auth-provider, unknown
+	IsCurrent   bool     `json:"isCurrent"`
+	Groups      []string `json:"groups,omitempty"`      // user-defined cluster groups
+	DisplayName string   `json:"displayName,omitempty"` // user-assigned name shown instead of the context
+	Color       string   `json:"color,omitempty"`
+	Pinned      bool     `json:"pinned,omitempty"`
 }
 
 // KubectlRequest is the payload for kubectl commands
@@ -120,6 +130,11 @@ type ErrorPayload struct {
 	Message string `json:"message"`
 }
 
+// CancelRequest is the payload for cancelling an in-flight request
+type CancelRequest struct {
+	RequestID string `json:"requestId"` // ID of the chat or kubectl message to cancel
+}
+
 // RenameContextRequest is the payload for renaming a kubeconfig context
 type RenameContextRequest struct {
 	OldName string `json:"oldName"`
@@ -275,3 +275,135 @@ type claudeStreamEvent struct {
 		} `json:"usage,omitempty"`
 	} `json:"message,omitempty"`
 }
+
+// ChatWithKubeTools runs a tool-use loop using Claude's native tool calling,
+// executing the built-in Kubernetes tools until the model produces a final answer
+func (c *ClaudeProvider) ChatWithKubeTools(ctx context.Context, req *ChatRequest, tools []KubeToolDefinition, execute func(ctx context.Context, call KubeToolCall) string) (*ChatResponse, error) {
+	if !c.IsAvailable() {
+		return nil, fmt.Errorf("Claude provider not configured - ANTHROPIC_API_KEY not set")
+	}
+
+	messages := make([]interface{}, 0)
+	for _, m := range c.buildMessages(req) {
+		messages = append(messages, m)
+	}
+
+	toolDefs := make([]map[string]interface{}, 0, len(tools))
+	for _, t := range tools {
+		toolDefs = append(toolDefs, map[string]interface{}{
+			"name":         t.Name,
+			"description":  t.Description,
+			"input_schema": t.Parameters,
+		})
+	}
+
+	var usage ProviderTokenUsage
+	for i := 0; i < maxKubeToolIterations; i++ {
+		body := map[string]interface{}{
+			"model":      c.model,
+			"max_tokens": 4096,
+			"messages":   messages,
+			"system":     kubeToolsSystemPrompt(req),
+			"tools":      toolDefs,
+		}
+
+		result, err := c.doToolRequest(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		addUsage(&usage, result.Usage.InputTokens, result.Usage.OutputTokens)
+
+		var text strings.Builder
+		var toolResults []map[string]interface{}
+		var assistantBlocks []claudeContentBlock
+		for _, block := range result.Content {
+			switch block.Type {
+			case "text":
+				if block.Text == "" {
+					continue
+				}
+				text.WriteString(block.Text)
+			case "tool_use":
+				var input map[string]interface{}
+				if len(block.Input) > 0 {
+					json.Unmarshal(block.Input, &input)
+				}
+				output := execute(ctx, KubeToolCall{ID: block.ID, Name: block.Name, Input: input})
+				toolResults = append(toolResults, map[string]interface{}{
+					"type":        "tool_result",
+					"tool_use_id": block.ID,
+					"content":     output,
+				})
+			}
+			assistantBlocks = append(assistantBlocks, block)
+		}
+
+		if len(toolResults) == 0 || result.StopReason != "tool_use" {
+			return &ChatResponse{
+				Content:    text.String(),
+				Agent:      c.Name(),
+				TokenUsage: &usage,
+				Done:       true,
+			}, nil
+		}
+
+		messages = append(messages,
+			map[string]interface{}{"role": "assistant", "content": assistantBlocks},
+			map[string]interface{}{"role": "user", "content": toolResults},
+		)
+	}
+
+	return nil, fmt.Errorf("tool loop exceeded %d iterations", maxKubeToolIterations)
+}
+
+func (c *ClaudeProvider) doToolRequest(ctx context.Context, body map[string]interface{}) (*claudeToolResponse, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", claudeAPIURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			body = []byte("(failed to read response body)")
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result claudeToolResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// claudeContentBlock is a text or tool_use block in a Claude message
+type claudeContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text,omitempty"`
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+type claudeToolResponse struct {
+	Content    []claudeContentBlock `json:"content"`
+	StopReason string               `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
@@ -292,3 +292,143 @@ type geminiStreamEvent struct {
 		TotalTokenCount      int `json:"totalTokenCount"`
 	} `json:"usageMetadata,omitempty"`
 }
+
+// ChatWithKubeTools runs a function-calling loop using Gemini's native
+// function declarations, executing the built-in Kubernetes tools
+func (g *GeminiProvider) ChatWithKubeTools(ctx context.Context, req *ChatRequest, tools []KubeToolDefinition, execute func(ctx context.Context, call KubeToolCall) string) (*ChatResponse, error) {
+	if !g.IsAvailable() {
+		return nil, fmt.Errorf("Gemini provider not configured - GOOGLE_API_KEY not set")
+	}
+
+	contents := make([]interface{}, 0)
+	for _, c := range g.buildContents(req) {
+		contents = append(contents, c)
+	}
+
+	declarations := make([]map[string]interface{}, 0, len(tools))
+	for _, t := range tools {
+		declarations = append(declarations, map[string]interface{}{
+			"name":        t.Name,
+			"description": t.Description,
+			"parameters":  t.Parameters,
+		})
+	}
+
+	var usage ProviderTokenUsage
+	for i := 0; i < maxKubeToolIterations; i++ {
+		body := map[string]interface{}{
+			"contents": contents,
+			"tools": []map[string]interface{}{
+				{"functionDeclarations": declarations},
+			},
+			"systemInstruction": map[string]interface{}{
+				"parts": []map[string]string{
+					{"text": kubeToolsSystemPrompt(req)},
+				},
+			},
+			"generationConfig": map[string]interface{}{
+				"maxOutputTokens": 4096,
+			},
+		}
+
+		result, err := g.doToolRequest(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		if result.UsageMetadata != nil {
+			addUsage(&usage, result.UsageMetadata.PromptTokenCount, result.UsageMetadata.CandidatesTokenCount)
+		}
+		if len(result.Candidates) == 0 {
+			return nil, fmt.Errorf("empty response from Gemini")
+		}
+
+		parts := result.Candidates[0].Content.Parts
+		var text strings.Builder
+		var responses []map[string]interface{}
+		for _, p := range parts {
+			if p.FunctionCall != nil {
+				output := execute(ctx, KubeToolCall{Name: p.FunctionCall.Name, Input: p.FunctionCall.Args})
+				responses = append(responses, map[string]interface{}{
+					"functionResponse": map[string]interface{}{
+						"name":     p.FunctionCall.Name,
+						"response": map[string]interface{}{"content": output},
+					},
+				})
+				continue
+			}
+			text.WriteString(p.Text)
+		}
+
+		if len(responses) == 0 {
+			return &ChatResponse{
+				Content:    text.String(),
+				Agent:      g.Name(),
+				TokenUsage: &usage,
+				Done:       true,
+			}, nil
+		}
+
+		contents = append(contents,
+			map[string]interface{}{"role": "model", "parts": parts},
+			map[string]interface{}{"role": "user", "parts": responses},
+		)
+	}
+
+	return nil, fmt.Errorf("tool loop exceeded %d iterations", maxKubeToolIterations)
+}
+
+func (g *GeminiProvider) doToolRequest(ctx context.Context, body map[string]interface{}) (*geminiToolResponse, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s:generateContent?key=%s", geminiAPIBaseURL, g.model, GetConfigManager().GetAPIKey("gemini"))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			body = []byte("(failed to read response body)")
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result geminiToolResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// geminiPart is a text or functionCall part of a Gemini message
+type geminiPart struct {
+	Text         string `json:"text,omitempty"`
+	FunctionCall *struct {
+		Name string                 `json:"name"`
+		Args map[string]interface{} `json:"args,omitempty"`
+	} `json:"functionCall,omitempty"`
+}
+
+type geminiToolResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []geminiPart `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata,omitempty"`
+}
@@ -268,3 +268,138 @@ type openAIStreamEvent struct {
 		TotalTokens      int `json:"total_tokens"`
 	} `json:"usage,omitempty"`
 }
+
+// ChatWithKubeTools runs a function-calling loop using OpenAI's native tool
+// support, executing the built-in Kubernetes tools until a final answer
+func (o *OpenAIProvider) ChatWithKubeTools(ctx context.Context, req *ChatRequest, tools []KubeToolDefinition, execute func(ctx context.Context, call KubeToolCall) string) (*ChatResponse, error) {
+	if !o.IsAvailable() {
+		return nil, fmt.Errorf("OpenAI provider not configured - OPENAI_API_KEY not set")
+	}
+
+	toolReq := *req
+	toolReq.SystemPrompt = kubeToolsSystemPrompt(req)
+	messages := make([]interface{}, 0)
+	for _, m := range o.buildMessages(&toolReq) {
+		messages = append(messages, m)
+	}
+
+	toolDefs := make([]map[string]interface{}, 0, len(tools))
+	for _, t := range tools {
+		toolDefs = append(toolDefs, map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        t.Name,
+				"description": t.Description,
+				"parameters":  t.Parameters,
+			},
+		})
+	}
+
+	var usage ProviderTokenUsage
+	for i := 0; i < maxKubeToolIterations; i++ {
+		body := map[string]interface{}{
+			"model":      o.model,
+			"messages":   messages,
+			"max_tokens": 4096,
+			"tools":      toolDefs,
+		}
+
+		result, err := o.doToolRequest(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		addUsage(&usage, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+
+		if len(result.Choices) == 0 {
+			return nil, fmt.Errorf("empty response from OpenAI")
+		}
+		msg := result.Choices[0].Message
+		if len(msg.ToolCalls) == 0 {
+			return &ChatResponse{
+				Content:    msg.Content,
+				Agent:      o.Name(),
+				TokenUsage: &usage,
+				Done:       true,
+			}, nil
+		}
+
+		messages = append(messages, map[string]interface{}{
+			"role":       "assistant",
+			"content":    msg.Content,
+			"tool_calls": msg.ToolCalls,
+		})
+		for _, tc := range msg.ToolCalls {
+			var input map[string]interface{}
+			if tc.Function.Arguments != "" {
+				json.Unmarshal([]byte(tc.Function.Arguments), &input)
+			}
+			output := execute(ctx, KubeToolCall{ID: tc.ID, Name: tc.Function.Name, Input: input})
+			messages = append(messages, map[string]interface{}{
+				"role":         "tool",
+				"tool_call_id": tc.ID,
+				"content":      output,
+			})
+		}
+	}
+
+	return nil, fmt.Errorf("tool loop exceeded %d iterations", maxKubeToolIterations)
+}
+
+func (o *OpenAIProvider) doToolRequest(ctx context.Context, body map[string]interface{}) (*openAIToolResponse, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", openAIAPIURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	o.setHeaders(httpReq)
+
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			body = []byte("(failed to read response body)")
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result openAIToolResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// openAIToolCall is a function call requested by the model
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openAIToolResponse struct {
+	Choices []struct {
+		Message struct {
+			Content   string           `json:"content"`
+			ToolCalls []openAIToolCall `json:"tool_calls,omitempty"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
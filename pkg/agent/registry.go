@@ -257,8 +257,12 @@ func InitializeProviders() error {
 	// Register HTTP API-based agents
 	registry.Register(NewOpenWebUIProvider())
 
-	// Set default agent based on environment or availability
-	if defaultAgent := os.Getenv("DEFAULT_AGENT"); defaultAgent != "" {
+	// Set default agent based on environment, agent.yaml or availability
+	defaultAgent := os.Getenv("DEFAULT_AGENT")
+	if defaultAgent == "" {
+		defaultAgent = GetConfigManager().GetDefaultAgent()
+	}
+	if defaultAgent != "" {
 		if err := registry.SetDefault(defaultAgent); err != nil {
 			// Log warning but don't fail - will use first available
 			fmt.Printf("Warning: Could not set default agent %s: %v\n", defaultAgent, err)
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// Render tools
+const (
+	renderToolHelm      = "helm"
+	renderToolKustomize = "kustomize"
+)
+
+// defaultRenderRelease names the release of a rendered chart when none is given
+const defaultRenderRelease = "preview"
+
+// renderRequest asks for a Helm chart or kustomize overlay to be expanded into manifests
+type renderRequest struct {
+	Tool      string `json:"tool"` // helm, kustomize
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	// Helm
+	Release string                 `json:"release,omitempty"`
+	Chart   string                 `json:"chart,omitempty"` // chart reference, path or OCI URL
+	Repo    string                 `json:"repo,omitempty"`
+	Version string                 `json:"version,omitempty"`
+	Values  map[string]interface{} `json:"values,omitempty"`
+
+	// Kustomize
+	Path string `json:"path,omitempty"` // overlay directory or remote URL
+}
+
+func (r *renderRequest) validate() error {
+	switch r.Tool {
+	case renderToolHelm:
+		if r.Chart == "" {
+			return fmt.Errorf("chart is required")
+		}
+	case renderToolKustomize:
+		if r.Path == "" {
+			return fmt.Errorf("path is required")
+		}
+	default:
+		return fmt.Errorf("tool must be helm or kustomize")
+	}
+	// Positional arguments must not be mistaken for flags
+	for field, v := range map[string]string{
+		"release": r.Release, "chart": r.Chart, "repo": r.Repo,
+		"version": r.Version, "path": r.Path, "namespace": r.Namespace,
+	} {
+		if strings.HasPrefix(v, "-") {
+			return fmt.Errorf("%s must not start with '-'", field)
+		}
+	}
+	return nil
+}
+
+// helmTemplateArgs builds the helm template command line; valuesFile may be empty
+func (r *renderRequest) helmTemplateArgs(valuesFile string) []string {
+	release := r.Release
+	if release == "" {
+		release = defaultRenderRelease
+	}
+	args := []string{"template", release, r.Chart}
+	if r.Namespace != "" {
+		args = append(args, "--namespace", r.Namespace)
+	}
+	if r.Repo != "" {
+		args = append(args, "--repo", r.Repo)
+	}
+	if r.Version != "" {
+		args = append(args, "--version", r.Version)
+	}
+	if valuesFile != "" {
+		args = append(args, "--values", valuesFile)
+	}
+	return args
+}
+
+// renderManifests runs helm template or kubectl kustomize and returns the expanded manifests
+func renderManifests(req *renderRequest) (string, error) {
+	var name string
+	var args []string
+	switch req.Tool {
+	case renderToolHelm:
+		valuesFile := ""
+		if len(req.Values) > 0 {
+			// JSON is valid YAML, so helm reads it as a values file
+			data, err := json.Marshal(req.Values)
+			if err != nil {
+				return "", fmt.Errorf("invalid values: %w", err)
+			}
+			f, err := os.CreateTemp("", "kc-render-values-*.json")
+			if err != nil {
+				return "", err
+			}
+			defer os.Remove(f.Name())
+			if _, err := f.Write(data); err != nil {
+				f.Close()
+				return "", err
+			}
+			f.Close()
+			valuesFile = f.Name()
+		}
+		name, args = "helm", req.helmTemplateArgs(valuesFile)
+	case renderToolKustomize:
+		name, args = "kubectl", []string{"kustomize", req.Path}
+	}
+
+	cmd := execCommand(name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// handleRender expands a Helm chart or kustomize overlay into manifests and,
+// when a cluster is given, validates them there as /validate does
+func (s *Server) handleRender(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req renderRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manifests, err := renderManifests(&req)
+	if err != nil {
+		log.Printf("[Render] %v", err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := map[string]interface{}{"manifests": manifests}
+	if req.Cluster != "" && s.k8sClient != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, req.Cluster, k8s.OpList, agentDefaultTimeout))
+		defer cancel()
+		validation, err := s.k8sClient.ValidateManifest(ctx, req.Cluster, req.Namespace, []byte(manifests))
+		if err != nil {
+			log.Printf("[Render] validation on %s: %v", req.Cluster, err)
+			resp["validationError"] = "cluster unavailable"
+		} else {
+			resp["validation"] = validation
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
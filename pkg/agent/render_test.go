@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     renderRequest
+		wantErr bool
+	}{
+		{"helm chart", renderRequest{Tool: "helm", Chart: "podinfo", Repo: "https://stefanprodan.github.io/podinfo"}, false},
+		{"kustomize path", renderRequest{Tool: "kustomize", Path: "./overlays/prod"}, false},
+		{"unknown tool", renderRequest{Tool: "jsonnet", Path: "x"}, true},
+		{"helm without chart", renderRequest{Tool: "helm"}, true},
+		{"kustomize without path", renderRequest{Tool: "kustomize"}, true},
+		{"flag injection", renderRequest{Tool: "helm", Chart: "--post-renderer=/bin/sh"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHelmTemplateArgs(t *testing.T) {
+	req := renderRequest{Tool: "helm", Chart: "podinfo", Repo: "https://example.com/charts", Version: "6.5.0", Namespace: "apps"}
+	got := req.helmTemplateArgs("/tmp/values.json")
+	want := []string{"template", "preview", "podinfo", "--namespace", "apps",
+		"--repo", "https://example.com/charts", "--version", "6.5.0", "--values", "/tmp/values.json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("helmTemplateArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestHandleRender(t *testing.T) {
+	defer func() { execCommand = exec.Command }()
+	execCommand = fakeExecCommand
+	mockStdout = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: rendered\n"
+	mockStderr = ""
+	mockExitCode = 0
+
+	s := &Server{allowedOrigins: []string{"*"}}
+	w := httptest.NewRecorder()
+	s.handleRender(w, httptest.NewRequest("POST", "/render", strings.NewReader(`{"tool":"kustomize","path":"./overlay"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["manifests"] != mockStdout {
+		t.Errorf("Unexpected manifests: %v", resp["manifests"])
+	}
+
+	mockStdout, mockStderr, mockExitCode = "", "Error: chart not found", 1
+	w = httptest.NewRecorder()
+	s.handleRender(w, httptest.NewRequest("POST", "/render", strings.NewReader(`{"tool":"helm","chart":"missing"}`)))
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), "chart not found") {
+		t.Errorf("Expected 422 with helm's error, got %d: %s", w.Code, w.Body.String())
+	}
+	mockStderr, mockExitCode = "", 0
+}
@@ -0,0 +1,773 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+const (
+	// reportSchedulerPoll is how often schedules are checked for a due report
+	reportSchedulerPoll = time.Minute
+	// reportCatchUpWindow is how late a report may still be sent, e.g. after the
+	// agent was offline at the scheduled time; older runs are skipped
+	reportCatchUpWindow = time.Hour
+	// reportClusterTimeout bounds data collection for one cluster
+	reportClusterTimeout = 30 * time.Second
+	// maxReportIssues is how many pod issues a report lists
+	maxReportIssues = 10
+
+	// Default on-demand prices for cost estimates, in USD per hour
+	defaultCPUCoreHourUSD  = 0.031
+	defaultMemoryGBHourUSD = 0.004
+	defaultGPUHourUSD      = 2.50
+)
+
+// Report sections
+const (
+	reportSectionHealth = "health"
+	reportSectionGPU    = "gpu"
+	reportSectionIssues = "issues"
+	reportSectionCost   = "cost"
+)
+
+var reportSections = []string{reportSectionHealth, reportSectionGPU, reportSectionIssues, reportSectionCost}
+
+// ReportScheduler sends the report schedules stored in settings when they come due
+type ReportScheduler struct {
+	k8sClient *k8s.MultiClusterClient
+	load      func() []settings.ReportSchedule
+	markSent  func(id string, at time.Time) error
+	now       func() time.Time
+	stopCh    chan struct{}
+}
+
+// NewReportScheduler creates a report scheduler reading schedules from load
+func NewReportScheduler(k8sClient *k8s.MultiClusterClient, load func() []settings.ReportSchedule, markSent func(string, time.Time) error) *ReportScheduler {
+	return &ReportScheduler{
+		k8sClient: k8sClient,
+		load:      load,
+		markSent:  markSent,
+		now:       time.Now,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// settingsReportSchedules reads report schedules from the persisted settings
+func settingsReportSchedules() []settings.ReportSchedule {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil || all == nil {
+		return nil
+	}
+	return all.ReportSchedules
+}
+
+// saveReportSent records when a schedule's report was last delivered
+func saveReportSent(id string, at time.Time) error {
+	mgr := settings.GetSettingsManager()
+	all, err := mgr.GetAll()
+	if err != nil {
+		return err
+	}
+	for i := range all.ReportSchedules {
+		if all.ReportSchedules[i].ID == id {
+			all.ReportSchedules[i].LastSentAt = at.UTC().Format(time.RFC3339)
+			return mgr.SaveAll(all)
+		}
+	}
+	return nil
+}
+
+// Start begins checking schedules every minute
+func (r *ReportScheduler) Start() {
+	go r.runLoop()
+}
+
+// Stop stops the report scheduler
+func (r *ReportScheduler) Stop() {
+	close(r.stopCh)
+}
+
+func (r *ReportScheduler) runLoop() {
+	ticker := time.NewTicker(reportSchedulerPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.sendDue()
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// sendDue delivers every enabled schedule whose run time has passed
+func (r *ReportScheduler) sendDue() {
+	now := r.now()
+	for _, schedule := range r.load() {
+		if !reportDue(schedule, now) {
+			continue
+		}
+		if err := r.send(schedule); err != nil {
+			log.Printf("[Reports] failed to send %q: %v", schedule.Name, err)
+		}
+		// Failed deliveries are not retried every minute; the next run tries again
+		if err := r.markSent(schedule.ID, now); err != nil {
+			log.Printf("[Reports] failed to record delivery of %q: %v", schedule.Name, err)
+		}
+	}
+}
+
+// send builds a schedule's report and delivers it now
+func (r *ReportScheduler) send(schedule settings.ReportSchedule) error {
+	report, err := r.build(context.Background(), schedule)
+	if err != nil {
+		return err
+	}
+	return deliverReport(schedule, report)
+}
+
+// reportDue reports whether the latest scheduled run of an enabled schedule
+// has not been sent yet and is recent enough to still send
+func reportDue(schedule settings.ReportSchedule, now time.Time) bool {
+	if !schedule.Enabled {
+		return false
+	}
+	run, err := lastScheduledRun(schedule, now)
+	if err != nil || now.Sub(run) > reportCatchUpWindow {
+		return false
+	}
+	if schedule.LastSentAt != "" {
+		if sent, err := time.Parse(time.RFC3339, schedule.LastSentAt); err == nil && !sent.Before(run) {
+			return false
+		}
+	}
+	return true
+}
+
+// lastScheduledRun returns the most recent run time at or before now, computed
+// in the schedule's time zone so runs stay at the same wall-clock time across DST
+func lastScheduledRun(schedule settings.ReportSchedule, now time.Time) (time.Time, error) {
+	loc, err := reportLocation(schedule.TimeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	hour, minute, err := parseReportTime(schedule.Time)
+	if err != nil {
+		return time.Time{}, err
+	}
+	local := now.In(loc)
+	run := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+
+	switch schedule.Frequency {
+	case "daily":
+		if run.After(now) {
+			run = time.Date(local.Year(), local.Month(), local.Day()-1, hour, minute, 0, 0, loc)
+		}
+	case "weekly":
+		weekday, err := parseWeekday(schedule.Weekday)
+		if err != nil {
+			return time.Time{}, err
+		}
+		back := (int(local.Weekday()) - int(weekday) + 7) % 7
+		run = time.Date(local.Year(), local.Month(), local.Day()-back, hour, minute, 0, 0, loc)
+		if run.After(now) {
+			run = time.Date(local.Year(), local.Month(), local.Day()-back-7, hour, minute, 0, 0, loc)
+		}
+	default:
+		return time.Time{}, fmt.Errorf("frequency must be daily or weekly")
+	}
+	return run, nil
+}
+
+func reportLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// parseReportTime parses an HH:MM time of day
+func parseReportTime(s string) (int, int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("time must be HH:MM")
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("weekday must be a day name such as monday")
+}
+
+// validateReportSchedule checks a schedule before it is saved
+func validateReportSchedule(schedule settings.ReportSchedule) error {
+	if strings.TrimSpace(schedule.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := lastScheduledRun(schedule, time.Now()); err != nil {
+		return err
+	}
+	switch schedule.Format {
+	case "", "html", "markdown":
+	default:
+		return fmt.Errorf("format must be html or markdown")
+	}
+	switch schedule.Channel {
+	case string(notifications.NotificationTypeEmail), string(notifications.NotificationTypeSlack):
+	default:
+		return fmt.Errorf("channel must be email or slack")
+	}
+	for _, section := range schedule.Sections {
+		known := false
+		for _, s := range reportSections {
+			known = known || section == s
+		}
+		if !known {
+			return fmt.Errorf("unknown section %q", section)
+		}
+	}
+	if r := schedule.CostRates; r != nil && (r.CPUCoreHour < 0 || r.MemoryGBHour < 0 || r.GPUHour < 0) {
+		return fmt.Errorf("cost rates must not be negative")
+	}
+	return nil
+}
+
+// reportData is what report templates render
+type reportData struct {
+	Title       string
+	Period      string // daily or weekly
+	GeneratedAt string
+	Sections    map[string]bool
+	Clusters    []reportCluster
+	GPUs        int
+	GPUsUsed    int
+	Issues      []k8s.PodIssue
+	Cost        reportCost
+}
+
+// reportCluster summarizes one cluster
+type reportCluster struct {
+	Name        string
+	Reachable   bool
+	Healthy     bool
+	Nodes       int
+	ReadyNodes  int
+	Pods        int
+	CPURequests float64
+	CPUCores    int
+	MemRequests float64
+	MemoryGB    float64
+	GPUs        int
+	GPUsUsed    int
+	Issues      []string
+	cost        float64
+}
+
+// reportCost estimates spend over the report period from current resource requests
+type reportCost struct {
+	Hours    int
+	CPU      float64
+	Memory   float64
+	GPU      float64
+	Total    float64
+	Clusters []reportClusterCost
+}
+
+type reportClusterCost struct {
+	Name  string
+	Total float64
+}
+
+// build collects cluster data and renders a schedule's report
+func (r *ReportScheduler) build(ctx context.Context, schedule settings.ReportSchedule) (notifications.Report, error) {
+	if r.k8sClient == nil {
+		return notifications.Report{}, fmt.Errorf("k8s client not initialized")
+	}
+	clusters := schedule.Clusters
+	if len(clusters) == 0 {
+		healthy, offline, err := r.k8sClient.HealthyClusters(ctx)
+		if err != nil {
+			return notifications.Report{}, err
+		}
+		for _, c := range append(healthy, offline...) {
+			clusters = append(clusters, c.Name)
+		}
+	}
+
+	sections := make(map[string]bool)
+	for _, s := range schedule.Sections {
+		sections[s] = true
+	}
+	if len(sections) == 0 {
+		for _, s := range reportSections {
+			sections[s] = true
+		}
+	}
+
+	results := make([]reportCluster, len(clusters))
+	issues := make([][]k8s.PodIssue, len(clusters))
+	var wg sync.WaitGroup
+	for i, name := range clusters {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, reportClusterTimeout)
+			defer cancel()
+			results[i], issues[i] = r.collectCluster(cctx, name, sections)
+		}(i, name)
+	}
+	wg.Wait()
+
+	var allIssues []k8s.PodIssue
+	for _, list := range issues {
+		allIssues = append(allIssues, list...)
+	}
+	data := newReportData(schedule, r.now(), results, allIssues, sections)
+	return renderReport(data, schedule.Format)
+}
+
+func (r *ReportScheduler) collectCluster(ctx context.Context, name string, sections map[string]bool) (reportCluster, []k8s.PodIssue) {
+	c := reportCluster{Name: name}
+	health, err := r.k8sClient.GetClusterHealth(ctx, name)
+	if err != nil || health == nil || !health.Reachable {
+		return c, nil
+	}
+	c.Reachable, c.Healthy = true, health.Healthy
+	c.Nodes, c.ReadyNodes, c.Pods = health.NodeCount, health.ReadyNodes, health.PodCount
+	c.CPUCores, c.CPURequests = health.CpuCores, health.CpuRequestsCores
+	c.MemoryGB, c.MemRequests = health.MemoryGB, health.MemoryRequestsGB
+	c.Issues = health.Issues
+
+	if sections[reportSectionGPU] || sections[reportSectionCost] {
+		if nodes, err := r.k8sClient.GetGPUNodes(ctx, name); err == nil {
+			for _, n := range nodes {
+				c.GPUs += n.GPUCount
+				c.GPUsUsed += n.GPUAllocated
+			}
+		}
+	}
+	var issues []k8s.PodIssue
+	if sections[reportSectionIssues] {
+		issues, _ = r.k8sClient.FindPodIssues(ctx, name, "")
+		for i := range issues {
+			issues[i].Cluster = name
+		}
+	}
+	return c, issues
+}
+
+// newReportData aggregates collected clusters into report totals, the most
+// restarted pod issues and a cost estimate for the report period
+func newReportData(schedule settings.ReportSchedule, now time.Time, clusters []reportCluster, issues []k8s.PodIssue, sections map[string]bool) reportData {
+	loc, err := reportLocation(schedule.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	period := "daily"
+	hours := 24
+	if schedule.Frequency == "weekly" {
+		period, hours = "weekly", 24*7
+	}
+	title := schedule.Name
+	if title == "" {
+		title = "Cluster report"
+	}
+	data := reportData{
+		Title:       fmt.Sprintf("%s — %s", title, now.In(loc).Format("Mon Jan 2, 2006")),
+		Period:      period,
+		GeneratedAt: now.In(loc).Format("2006-01-02 15:04 MST"),
+		Sections:    sections,
+		Clusters:    clusters,
+	}
+
+	rates := settings.ReportCostRates{CPUCoreHour: defaultCPUCoreHourUSD, MemoryGBHour: defaultMemoryGBHourUSD, GPUHour: defaultGPUHourUSD}
+	if schedule.CostRates != nil {
+		rates = *schedule.CostRates
+	}
+	data.Cost.Hours = hours
+	for i := range data.Clusters {
+		c := &data.Clusters[i]
+		data.GPUs += c.GPUs
+		data.GPUsUsed += c.GPUsUsed
+		cpu := c.CPURequests * rates.CPUCoreHour * float64(hours)
+		mem := c.MemRequests * rates.MemoryGBHour * float64(hours)
+		gpu := float64(c.GPUsUsed) * rates.GPUHour * float64(hours)
+		c.cost = cpu + mem + gpu
+		data.Cost.CPU += cpu
+		data.Cost.Memory += mem
+		data.Cost.GPU += gpu
+		data.Cost.Total += c.cost
+		if c.Reachable {
+			data.Cost.Clusters = append(data.Cost.Clusters, reportClusterCost{Name: c.Name, Total: c.cost})
+		}
+	}
+	sort.SliceStable(data.Cost.Clusters, func(i, j int) bool {
+		return data.Cost.Clusters[i].Total > data.Cost.Clusters[j].Total
+	})
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Restarts > issues[j].Restarts
+	})
+	if len(issues) > maxReportIssues {
+		issues = issues[:maxReportIssues]
+	}
+	data.Issues = issues
+	return data
+}
+
+var reportFuncs = map[string]interface{}{
+	"join": strings.Join,
+	"pct": func(used, total int) string {
+		if total == 0 {
+			return "0%"
+		}
+		return fmt.Sprintf("%.0f%%", float64(used)*100/float64(total))
+	},
+	"usd": func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+}
+
+const reportMarkdownTemplate = `# {{.Title}}
+
+Generated {{.GeneratedAt}} ({{.Period}} report)
+{{if .Sections.health}}
+## Cluster health
+{{range .Clusters}}
+- **{{.Name}}**: {{if not .Reachable}}unreachable{{else}}{{if .Healthy}}healthy{{else}}degraded{{end}}, {{.ReadyNodes}}/{{.Nodes}} nodes ready, {{.Pods}} pods, CPU {{printf "%.1f" .CPURequests}}/{{.CPUCores}} cores requested, memory {{printf "%.1f" .MemRequests}}/{{printf "%.1f" .MemoryGB}} GB requested{{if .Issues}} — {{join .Issues "; "}}{{end}}{{end}}
+{{- end}}
+{{end}}{{if .Sections.gpu}}
+## GPU allocation
+
+{{.GPUsUsed}} of {{.GPUs}} GPUs allocated ({{pct .GPUsUsed .GPUs}})
+{{range .Clusters}}{{if .GPUs}}
+- **{{.Name}}**: {{.GPUsUsed}}/{{.GPUs}} ({{pct .GPUsUsed .GPUs}}){{end}}{{end}}
+{{end}}{{if .Sections.issues}}
+## Top issues
+{{if .Issues}}{{range .Issues}}
+- **{{.Cluster}}/{{.Namespace}}/{{.Name}}**: {{.Status}}{{if .Restarts}}, {{.Restarts}} restarts{{end}}{{if .Issues}} — {{join .Issues "; "}}{{end}}{{end}}
+{{else}}
+No pod issues found.
+{{end}}{{end}}{{if .Sections.cost}}
+## Estimated cost ({{.Cost.Hours}}h)
+
+Total {{usd .Cost.Total}}: CPU {{usd .Cost.CPU}}, memory {{usd .Cost.Memory}}, GPU {{usd .Cost.GPU}}
+{{range .Cost.Clusters}}
+- **{{.Name}}**: {{usd .Total}}{{end}}
+
+_Estimated from current resource requests and allocated GPUs._
+{{end}}`
+
+const reportHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+	<style>
+		body { font-family: Arial, sans-serif; line-height: 1.5; color: #333; }
+		.container { max-width: 720px; margin: 0 auto; padding: 20px; }
+		.header { background-color: #1f6feb; color: white; padding: 20px; border-radius: 5px 5px 0 0; }
+		.content { background-color: #f9f9f9; padding: 20px; border: 1px solid #ddd; border-top: none; }
+		table { width: 100%; border-collapse: collapse; margin-bottom: 15px; }
+		th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #ddd; font-size: 13px; }
+		.ok { color: #1a7f37; }
+		.bad { color: #dc3545; }
+		.footer { margin-top: 20px; font-size: 12px; color: #777; text-align: center; }
+	</style>
+</head>
+<body>
+	<div class="container">
+		<div class="header">
+			<h2>{{.Title}}</h2>
+			<div>Generated {{.GeneratedAt}} ({{.Period}} report)</div>
+		</div>
+		<div class="content">
+			{{if .Sections.health}}
+			<h3>Cluster health</h3>
+			<table>
+				<tr><th>Cluster</th><th>Status</th><th>Nodes</th><th>Pods</th><th>CPU requested</th><th>Memory requested</th></tr>
+				{{range .Clusters}}
+				<tr>
+					<td>{{.Name}}</td>
+					{{if not .Reachable}}<td class="bad" colspan="5">unreachable</td>{{else}}
+					<td class="{{if .Healthy}}ok{{else}}bad{{end}}">{{if .Healthy}}healthy{{else}}degraded{{end}}</td>
+					<td>{{.ReadyNodes}}/{{.Nodes}}</td>
+					<td>{{.Pods}}</td>
+					<td>{{printf "%.1f" .CPURequests}}/{{.CPUCores}} cores</td>
+					<td>{{printf "%.1f" .MemRequests}}/{{printf "%.1f" .MemoryGB}} GB</td>{{end}}
+				</tr>
+				{{end}}
+			</table>
+			{{end}}
+			{{if .Sections.gpu}}
+			<h3>GPU allocation</h3>
+			<p>{{.GPUsUsed}} of {{.GPUs}} GPUs allocated ({{pct .GPUsUsed .GPUs}})</p>
+			<table>
+				{{range .Clusters}}{{if .GPUs}}<tr><td>{{.Name}}</td><td>{{.GPUsUsed}}/{{.GPUs}}</td><td>{{pct .GPUsUsed .GPUs}}</td></tr>{{end}}{{end}}
+			</table>
+			{{end}}
+			{{if .Sections.issues}}
+			<h3>Top issues</h3>
+			{{if .Issues}}
+			<table>
+				<tr><th>Pod</th><th>Status</th><th>Restarts</th><th>Issues</th></tr>
+				{{range .Issues}}<tr><td>{{.Cluster}}/{{.Namespace}}/{{.Name}}</td><td>{{.Status}}</td><td>{{.Restarts}}</td><td>{{join .Issues "; "}}</td></tr>{{end}}
+			</table>
+			{{else}}<p>No pod issues found.</p>{{end}}
+			{{end}}
+			{{if .Sections.cost}}
+			<h3>Estimated cost ({{.Cost.Hours}}h)</h3>
+			<p>Total {{usd .Cost.Total}}: CPU {{usd .Cost.CPU}}, memory {{usd .Cost.Memory}}, GPU {{usd .Cost.GPU}}</p>
+			<table>
+				{{range .Cost.Clusters}}<tr><td>{{.Name}}</td><td>{{usd .Total}}</td></tr>{{end}}
+			</table>
+			<p><em>Estimated from current resource requests and allocated GPUs.</em></p>
+			{{end}}
+		</div>
+		<div class="footer">
+			<p>This report was generated by KubeStellar Console</p>
+		</div>
+	</div>
+</body>
+</html>
+`
+
+var (
+	reportMarkdown = template.Must(template.New("report").Funcs(reportFuncs).Parse(reportMarkdownTemplate))
+	reportHTML     = htmltemplate.Must(htmltemplate.New("report").Funcs(reportFuncs).Parse(reportHTMLTemplate))
+)
+
+// renderReport renders report data as Markdown, plus HTML unless format is markdown
+func renderReport(data reportData, format string) (notifications.Report, error) {
+	report := notifications.Report{Title: data.Title, GeneratedAt: time.Now()}
+	var buf bytes.Buffer
+	if err := reportMarkdown.Execute(&buf, data); err != nil {
+		return report, err
+	}
+	report.Markdown = buf.String()
+	if format == "markdown" {
+		return report, nil
+	}
+	buf.Reset()
+	if err := reportHTML.Execute(&buf, data); err != nil {
+		return report, err
+	}
+	report.HTML = buf.String()
+	return report, nil
+}
+
+// deliverReport sends a report through the schedule's channel using the
+// notification settings; schedule recipients replace the configured email recipients
+func deliverReport(schedule settings.ReportSchedule, report notifications.Report) error {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil {
+		return err
+	}
+	secrets := all.Notifications
+	channel := notifications.NotificationChannel{
+		Type:    notifications.NotificationType(schedule.Channel),
+		Enabled: true,
+		Config:  map[string]interface{}{},
+	}
+	switch channel.Type {
+	case notifications.NotificationTypeSlack:
+		channel.Config["slackWebhookUrl"] = secrets.SlackWebhookURL
+		channel.Config["slackChannel"] = secrets.SlackChannel
+	case notifications.NotificationTypeEmail:
+		to := secrets.EmailTo
+		if len(schedule.Recipients) > 0 {
+			to = strings.Join(schedule.Recipients, ",")
+		}
+		channel.Config["emailSMTPHost"] = secrets.EmailSMTPHost
+		channel.Config["emailSMTPPort"] = float64(secrets.EmailSMTPPort)
+		channel.Config["emailUsername"] = secrets.EmailUsername
+		channel.Config["emailPassword"] = secrets.EmailPassword
+		channel.Config["emailFrom"] = secrets.EmailFrom
+		channel.Config["emailTo"] = to
+	}
+	return notifications.NewService().SendReportToChannels(report, []notifications.NotificationChannel{channel})
+}
+
+// findReportSchedule returns the stored schedule with the given ID
+func findReportSchedule(id string) (settings.ReportSchedule, bool) {
+	for _, schedule := range settingsReportSchedules() {
+		if schedule.ID == id {
+			return schedule, true
+		}
+	}
+	return settings.ReportSchedule{}, false
+}
+
+// handleReportSchedules returns (GET) or replaces (PUT) the report schedules
+func (s *Server) handleReportSchedules(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		schedules := settingsReportSchedules()
+		if schedules == nil {
+			schedules = []settings.ReportSchedule{}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"schedules": schedules})
+
+	case "PUT":
+		var schedules []settings.ReportSchedule
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&schedules); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		seen := make(map[string]bool)
+		for i := range schedules {
+			if err := validateReportSchedule(schedules[i]); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("schedule %d: %v", i, err)})
+				return
+			}
+			if schedules[i].ID == "" {
+				schedules[i].ID = uuid.New().String()
+			}
+			if seen[schedules[i].ID] {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("schedule %d: duplicate id %q", i, schedules[i].ID)})
+				return
+			}
+			seen[schedules[i].ID] = true
+		}
+		if schedules == nil {
+			schedules = []settings.ReportSchedule{}
+		}
+
+		mgr := settings.GetSettingsManager()
+		all, err := mgr.GetAll()
+		if err == nil {
+			all.ReportSchedules = schedules
+			err = mgr.SaveAll(all)
+		}
+		if err != nil {
+			log.Printf("[Reports] failed to save schedules: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to save schedules"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "schedules": schedules})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleReportPreview renders a schedule's report now without sending it. Without
+// an id it renders a daily report of every section for all clusters.
+func (s *Server) handleReportPreview(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.reportScheduler == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "report scheduler not initialized"})
+		return
+	}
+
+	schedule := settings.ReportSchedule{Name: "Cluster report", Frequency: "daily"}
+	if id := r.URL.Query().Get("id"); id != "" {
+		var ok bool
+		if schedule, ok = findReportSchedule(id); !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "report schedule not found"})
+			return
+		}
+	}
+
+	report, err := s.reportScheduler.build(r.Context(), schedule)
+	if err != nil {
+		log.Printf("[Reports] failed to build preview: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "failed to build report"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{
+		"title":    report.Title,
+		"markdown": report.Markdown,
+		"html":     report.HTML,
+	})
+}
+
+// handleReportSend builds and delivers a schedule's report immediately. Manual
+// sends do not move the schedule's next run.
+func (s *Server) handleReportSend(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.reportScheduler == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "report scheduler not initialized"})
+		return
+	}
+
+	schedule, ok := findReportSchedule(r.URL.Query().Get("id"))
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "report schedule not found"})
+		return
+	}
+	if err := s.reportScheduler.send(schedule); err != nil {
+		log.Printf("[Reports] failed to send %q: %v", schedule.Name, err)
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+}
@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+func TestLastScheduledRun(t *testing.T) {
+	now := time.Date(2026, 3, 11, 7, 30, 0, 0, time.UTC) // a Wednesday
+
+	tests := []struct {
+		name     string
+		schedule settings.ReportSchedule
+		want     string
+	}{
+		{"daily later today runs yesterday", settings.ReportSchedule{Frequency: "daily", Time: "09:00"}, "2026-03-10T09:00:00Z"},
+		{"daily earlier today", settings.ReportSchedule{Frequency: "daily", Time: "06:15"}, "2026-03-11T06:15:00Z"},
+		{"daily in time zone", settings.ReportSchedule{Frequency: "daily", Time: "09:00", TimeZone: "Asia/Tokyo"}, "2026-03-11T00:00:00Z"},
+		{"weekly earlier this week", settings.ReportSchedule{Frequency: "weekly", Weekday: "Monday", Time: "08:00"}, "2026-03-09T08:00:00Z"},
+		{"weekly later today runs last week", settings.ReportSchedule{Frequency: "weekly", Weekday: "wednesday", Time: "08:00"}, "2026-03-04T08:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, err := lastScheduledRun(tt.schedule, now)
+			if err != nil {
+				t.Fatalf("lastScheduledRun: %v", err)
+			}
+			if got := run.UTC().Format(time.RFC3339); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := lastScheduledRun(settings.ReportSchedule{Frequency: "hourly", Time: "09:00"}, now); err == nil {
+		t.Error("expected an error for an unknown frequency")
+	}
+	if _, err := lastScheduledRun(settings.ReportSchedule{Frequency: "daily", Time: "9am"}, now); err == nil {
+		t.Error("expected an error for a malformed time")
+	}
+}
+
+func TestReportDue(t *testing.T) {
+	schedule := settings.ReportSchedule{Enabled: true, Frequency: "daily", Time: "09:00"}
+	at := func(s string) time.Time {
+		v, _ := time.Parse(time.RFC3339, s)
+		return v
+	}
+
+	if !reportDue(schedule, at("2026-03-11T09:00:30Z")) {
+		t.Error("expected report to be due just after its run time")
+	}
+	if reportDue(schedule, at("2026-03-11T08:59:00Z").Add(-2*time.Hour)) {
+		t.Error("runs older than the catch-up window should be skipped")
+	}
+
+	schedule.LastSentAt = "2026-03-11T09:00:40Z"
+	if reportDue(schedule, at("2026-03-11T09:05:00Z")) {
+		t.Error("a sent report should not be due again")
+	}
+	if !reportDue(schedule, at("2026-03-12T09:01:00Z")) {
+		t.Error("expected the next day's report to be due")
+	}
+
+	schedule.Enabled = false
+	if reportDue(schedule, at("2026-03-12T09:01:00Z")) {
+		t.Error("disabled schedules are never due")
+	}
+}
+
+func TestRenderReport(t *testing.T) {
+	schedule := settings.ReportSchedule{
+		Name:      "Daily GPU",
+		Frequency: "daily",
+		CostRates: &settings.ReportCostRates{CPUCoreHour: 1, GPUHour: 10},
+	}
+	clusters := []reportCluster{
+		{Name: "prod", Reachable: true, Healthy: true, Nodes: 3, ReadyNodes: 3, Pods: 40, CPUCores: 24, CPURequests: 2, GPUs: 8, GPUsUsed: 6},
+		{Name: "edge"},
+	}
+	issues := []k8s.PodIssue{
+		{Name: "api-1", Namespace: "default", Cluster: "prod", Status: "CrashLoopBackOff", Restarts: 3},
+		{Name: "web-1", Namespace: "default", Cluster: "prod", Status: "CrashLoopBackOff", Restarts: 12},
+	}
+	sections := map[string]bool{reportSectionHealth: true, reportSectionGPU: true, reportSectionIssues: true, reportSectionCost: true}
+	data := newReportData(schedule, time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC), clusters, issues, sections)
+
+	// 24h x (2 cores x $1 + 6 GPUs x $10)
+	if data.Cost.Total != 24*62 {
+		t.Errorf("cost total = %v, want %v", data.Cost.Total, 24*62)
+	}
+	if data.Issues[0].Name != "web-1" {
+		t.Errorf("issues should be ordered by restarts, got %s first", data.Issues[0].Name)
+	}
+
+	report, err := renderReport(data, "html")
+	if err != nil {
+		t.Fatalf("renderReport: %v", err)
+	}
+	for _, want := range []string{"# Daily GPU", "**prod**: healthy, 3/3 nodes ready", "**edge**: unreachable", "6 of 8 GPUs allocated (75%)", "web-1", "Total $1488.00"} {
+		if !strings.Contains(report.Markdown, want) {
+			t.Errorf("markdown missing %q:\n%s", want, report.Markdown)
+		}
+	}
+	if !strings.Contains(report.HTML, "<td>prod</td>") {
+		t.Errorf("html missing cluster row:\n%s", report.HTML)
+	}
+
+	report, _ = renderReport(data, "markdown")
+	if report.HTML != "" {
+		t.Error("markdown reports should not render HTML")
+	}
+}
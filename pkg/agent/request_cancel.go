@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubestellar/console/pkg/agent/protocol"
+)
+
+// inflightRequests tracks the cancellable requests of one WebSocket connection
+// by message ID, so a cancel message can stop the AI call or kubectl process
+// behind a request the user no longer waits for
+type inflightRequests struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newInflightRequests() *inflightRequests {
+	return &inflightRequests{cancels: make(map[string]context.CancelFunc)}
+}
+
+// start derives a context for the request with the given message ID. The
+// returned done func must be called when the request finishes.
+func (r *inflightRequests) start(parent context.Context, id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	if id == "" {
+		return ctx, cancel
+	}
+	r.mu.Lock()
+	r.cancels[id] = cancel
+	r.mu.Unlock()
+	return ctx, func() {
+		r.mu.Lock()
+		delete(r.cancels, id)
+		r.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels the request with the given message ID, reporting whether it was in flight
+func (r *inflightRequests) cancel(id string) bool {
+	r.mu.Lock()
+	cancel, ok := r.cancels[id]
+	delete(r.cancels, id)
+	r.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// handleCancel cancels an in-flight request of this connection by message ID
+func (s *Server) handleCancel(conn *websocket.Conn, msg protocol.Message, inflight *inflightRequests, writeMu *sync.Mutex) {
+	var req protocol.CancelRequest
+	payloadBytes, err := json.Marshal(msg.Payload)
+	if err == nil {
+		err = json.Unmarshal(payloadBytes, &req)
+	}
+	if err != nil || req.RequestID == "" {
+		writeMu.Lock()
+		conn.WriteJSON(s.errorResponse(msg.ID, "invalid_payload", "cancel requires a requestId"))
+		writeMu.Unlock()
+		return
+	}
+
+	cancelled := inflight.cancel(req.RequestID)
+	if cancelled {
+		log.Printf("[WebSocket] Cancelled request %s", req.RequestID)
+	}
+
+	writeMu.Lock()
+	conn.WriteJSON(protocol.Message{
+		ID:   msg.ID,
+		Type: protocol.TypeResult,
+		Payload: map[string]interface{}{
+			"cancelled": cancelled,
+			"requestId": req.RequestID,
+		},
+	})
+	writeMu.Unlock()
+}
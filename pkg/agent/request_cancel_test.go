@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kubestellar/console/pkg/agent/protocol"
+)
+
+func TestInflightRequests(t *testing.T) {
+	inflight := newInflightRequests()
+	conn, closeConn := context.WithCancel(context.Background())
+
+	chat, chatDone := inflight.start(conn, "msg-1")
+	kubectl, kubectlDone := inflight.start(conn, "msg-2")
+
+	if !inflight.cancel("msg-1") || chat.Err() == nil {
+		t.Fatal("Expected msg-1 to be cancelled")
+	}
+	if inflight.cancel("msg-1") || inflight.cancel("unknown") {
+		t.Error("Expected only in-flight requests to be cancellable")
+	}
+	chatDone()
+	if kubectl.Err() != nil {
+		t.Error("Expected cancelling msg-1 to leave msg-2 running")
+	}
+
+	closeConn()
+	if kubectl.Err() == nil {
+		t.Error("Expected a disconnect to cancel every request")
+	}
+	kubectlDone()
+
+	_, done := inflight.start(context.Background(), "msg-3")
+	done()
+	if len(inflight.cancels) != 0 {
+		t.Errorf("Expected finished requests to be forgotten, got %d", len(inflight.cancels))
+	}
+}
+
+func TestKubectlExecuteContextCancel(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	sleep := func(string, ...string) *exec.Cmd { return exec.Command("sleep", "30") }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	resp := (&KubectlProxy{command: sleep}).ExecuteContext(ctx, "", "", []string{"get", "pods"})
+	if resp.Error != "cancelled" || resp.ExitCode == 0 {
+		t.Errorf("Expected a cancelled response, got %+v", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected kubectl to be killed on cancel, took %v", elapsed)
+	}
+}
+
+func TestWebSocketConcurrentRequests(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	kubectl, _ := NewKubectlProxy(filepath.Join(t.TempDir(), "config"))
+	kubectl.command = func(string, ...string) *exec.Cmd { return exec.Command("sleep", "30") }
+	s := &Server{
+		kubectl:  kubectl,
+		clients:  make(map[*websocket.Conn]bool),
+		upgrader: websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(s.handleWebSocket))
+	defer srv.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	req := protocol.KubectlRequest{Args: []string{"get", "pods"}}
+	for i := 0; i < maxWSConcurrentRequests+1; i++ {
+		if err := conn.WriteJSON(protocol.Message{ID: fmt.Sprintf("k%d", i), Type: protocol.TypeKubectl, Payload: req}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var resp protocol.Message
+	if err := conn.ReadJSON(&resp); err != nil || resp.ID != fmt.Sprintf("k%d", maxWSConcurrentRequests) || resp.Type != protocol.TypeError {
+		t.Fatalf("Expected the request over the cap to be rejected, got %+v %v", resp, err)
+	}
+
+	// A slow kubectl doesn't hold up a cancel, which frees its slot
+	conn.WriteJSON(protocol.Message{ID: "c1", Type: protocol.TypeCancel, Payload: protocol.CancelRequest{RequestID: "k0"}})
+	if err := conn.ReadJSON(&resp); err != nil || resp.ID != "c1" || resp.Payload.(map[string]interface{})["cancelled"] != true {
+		t.Fatalf("Expected k0 to be cancelled, got %+v %v", resp, err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn.WriteJSON(protocol.Message{ID: "h1", Type: protocol.TypeHealth})
+		if err := conn.ReadJSON(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.ID == "h1" && resp.Type == protocol.TypeResult {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a health result once k0 released its slot, got %+v", resp)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+func clusterTimeoutsFromSettings(timeouts map[string]settings.ClusterTimeouts) map[string]k8s.ClusterTimeouts {
+	if len(timeouts) == 0 {
+		return nil
+	}
+	out := make(map[string]k8s.ClusterTimeouts, len(timeouts))
+	for name, t := range timeouts {
+		parsed, err := k8s.ParseClusterTimeouts(t.List, t.Health, t.Logs)
+		if err != nil {
+			log.Printf("Warning: ignoring timeouts for cluster %q: %v", name, err)
+			continue
+		}
+		out[name] = parsed
+	}
+	return out
+}
+
+// requestTimeout is how long a request may spend on op against cluster ("" for
+// every cluster). A ?timeout= query parameter ("90s" or seconds) overrides the
+// configured timeout, up to the cluster's client timeout.
+func (s *Server) requestTimeout(r *http.Request, cluster string, op k8s.OperationClass, fallback time.Duration) time.Duration {
+	if s.k8sClient == nil {
+		return fallback
+	}
+	if override := parseTimeoutParam(r.URL.Query().Get("timeout")); override > 0 {
+		limit := max(s.k8sClient.OperationTimeout(cluster, op, fallback), s.k8sClient.ClientTimeout(cluster))
+		return min(override, limit)
+	}
+	return s.k8sClient.OperationTimeout(cluster, op, fallback)
+}
+
+// parseTimeoutParam accepts a Go duration or a number of seconds; 0 means unset
+func parseTimeoutParam(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return d
+	}
+	if secs, err := strconv.Atoi(raw); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
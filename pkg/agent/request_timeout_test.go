@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"wan": {Cluster: "wan"}, "kind": {Cluster: "kind"}}})
+	m.SetClusterPolicySource(func() k8s.ClusterPolicy {
+		return k8s.ClusterPolicy{Timeouts: map[string]k8s.ClusterTimeouts{"wan": {List: 90 * time.Second}}}
+	})
+	s := &Server{k8sClient: m}
+
+	tests := []struct {
+		name    string
+		url     string
+		cluster string
+		want    time.Duration
+	}{
+		{"configured", "/nodes", "wan", 90 * time.Second},
+		{"fallback", "/nodes", "kind", agentDefaultTimeout},
+		{"fan-out waits for the slowest", "/nodes", "", 90 * time.Second},
+		{"duration override", "/nodes?timeout=5s", "kind", 5 * time.Second},
+		{"seconds override", "/nodes?timeout=10", "kind", 10 * time.Second},
+		{"override capped at the client timeout", "/nodes?timeout=10m", "kind", 45 * time.Second},
+		{"override capped at the configured timeout", "/nodes?timeout=10m", "wan", 90 * time.Second},
+		{"invalid override ignored", "/nodes?timeout=soon", "wan", 90 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if got := s.requestTimeout(r, tt.cluster, k8s.OpList, agentDefaultTimeout); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	// responseCacheTTL is how long a list response is reused for identical requests
+	responseCacheTTL = 5 * time.Second
+	// responseCacheMaxEntries bounds the cache; expired entries are swept when full
+	responseCacheMaxEntries = 256
+)
+
+// cachedResponse is a successful list response kept for responseCacheTTL
+type cachedResponse struct {
+	contentType string
+	body        []byte
+	etag        string
+	storedAt    time.Time
+}
+
+// responseCache holds recent list responses keyed by path and query, so rapid
+// repeated polls are served without listing the clusters again
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+func (c *responseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.storedAt) >= responseCacheTTL {
+		return nil
+	}
+	return entry
+}
+
+func (c *responseCache) put(key string, entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*cachedResponse)
+	}
+	if len(c.entries) >= responseCacheMaxEntries {
+		for k, e := range c.entries {
+			if time.Since(e.storedAt) >= responseCacheTTL {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= responseCacheMaxEntries {
+			c.entries = make(map[string]*cachedResponse)
+		}
+	}
+	c.entries[key] = entry
+}
+
+// clear drops every cached response, e.g. after a kubeconfig reload
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+// responseCacheKey identifies a request by path, sorted query and impersonated
+// identity, ignoring the auth token
+func responseCacheKey(r *http.Request) string {
+	q := r.URL.Query()
+	q.Del("token")
+	key := r.URL.Path + "?" + q.Encode()
+	if imp, ok := k8s.ImpersonationFrom(r.Context()); ok {
+		key += "#" + imp.String()
+	}
+	return key
+}
+
+// responseETag is a strong validator derived from the response body
+func responseETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorResponse reports whether a JSON body carries an "error" field; list
+// handlers report failures with status 200, and those must not be cached
+func isErrorResponse(body []byte) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return true
+	}
+	raw, ok := fields["error"]
+	return ok && string(raw) != `""` && string(raw) != "null"
+}
+
+// bufferedResponse captures a handler's response so it can be cached
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// cachedList serves GET requests from the response cache and answers matching
+// If-None-Match requests with 304. "Cache-Control: no-cache" forces a fresh listing.
+// NDJSON requests are streamed, never cached.
+func (s *Server) cachedList(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !s.validateToken(r) || wantsNDJSON(r) {
+			next(w, r)
+			return
+		}
+
+		key := responseCacheKey(r)
+		var entry *cachedResponse
+		if !strings.Contains(r.Header.Get("Cache-Control"), "no-cache") {
+			entry = s.responseCache.get(key)
+		}
+		cacheStatus := "HIT"
+
+		if entry == nil {
+			cacheStatus = "MISS"
+			buf := &bufferedResponse{header: w.Header()}
+			next(buf, r)
+			if buf.status != http.StatusOK || isErrorResponse(buf.body.Bytes()) {
+				w.WriteHeader(max(buf.status, http.StatusOK))
+				w.Write(buf.body.Bytes())
+				return
+			}
+			entry = &cachedResponse{
+				contentType: w.Header().Get("Content-Type"),
+				body:        buf.body.Bytes(),
+				etag:        responseETag(buf.body.Bytes()),
+				storedAt:    time.Now(),
+			}
+			s.responseCache.put(key, entry)
+		} else {
+			s.setCORSHeaders(w, r)
+			w.Header().Set("Content-Type", entry.contentType)
+		}
+
+		w.Header().Set("ETag", entry.etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Cache", cacheStatus)
+		if etagMatches(r.Header.Get("If-None-Match"), entry.etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write(entry.body)
+	}
+}
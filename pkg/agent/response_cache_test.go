@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCachedList(t *testing.T) {
+	s := &Server{}
+	calls := 0
+	failing := false
+	handler := s.cachedList(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if failing {
+			json.NewEncoder(w).Encode(map[string]interface{}{"pods": []interface{}{}, "error": "cluster unreachable"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"pods": []string{"a", "b"}})
+	})
+
+	get := func(url string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", url, nil)
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	first := get("/pods?cluster=c1&namespace=default", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("Expected a fresh 200 with an ETag, got %d %q %q", first.Code, etag, first.Header().Get("X-Cache"))
+	}
+
+	// Same query in a different order is served from the cache
+	second := get("/pods?namespace=default&cluster=c1", nil)
+	if calls != 1 || second.Header().Get("X-Cache") != "HIT" || second.Body.String() != first.Body.String() {
+		t.Errorf("Expected a cache hit with the same body, handler called %d times", calls)
+	}
+	if second.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected the cached content type, got %q", second.Header().Get("Content-Type"))
+	}
+
+	notModified := get("/pods?cluster=c1&namespace=default", http.Header{"If-None-Match": {etag}})
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Errorf("Expected 304 without a body, got %d", notModified.Code)
+	}
+
+	// no-cache lists again; a different query is a different entry
+	get("/pods?cluster=c1&namespace=default", http.Header{"Cache-Control": {"no-cache"}})
+	get("/pods?cluster=c2", nil)
+	if calls != 3 {
+		t.Errorf("Expected 3 handler calls, got %d", calls)
+	}
+
+	// Error responses are passed through uncached
+	failing = true
+	get("/pods?cluster=c3", nil)
+	errResp := get("/pods?cluster=c3", nil)
+	if calls != 5 || errResp.Header().Get("ETag") != "" {
+		t.Errorf("Expected error responses to bypass the cache, handler called %d times", calls)
+	}
+
+	s.responseCache.clear()
+	failing = false
+	get("/pods?cluster=c1&namespace=default", nil)
+	if calls != 6 {
+		t.Errorf("Expected a listing after clear, handler called %d times", calls)
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	if !etagMatches(`"x", W/"abc"`, `"abc"`) || !etagMatches("*", `"abc"`) {
+		t.Error("Expected a match")
+	}
+	if etagMatches(`"x"`, `"abc"`) || etagMatches("", `"abc"`) {
+		t.Error("Expected no match")
+	}
+}
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// RetryCountHeader reports how many Kubernetes API reads were retried while
+// serving a response, so the UI can tell a flaky cluster from an empty one
+const RetryCountHeader = "X-Retry-Count"
+
+// withRetryCount counts the API retries made for each request and reports them
+// in RetryCountHeader. WebSocket upgrades pass through untouched.
+func withRetryCount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, stats := k8s.WithRetryStats(r.Context())
+		next.ServeHTTP(&retryCountWriter{ResponseWriter: w, stats: stats}, r.WithContext(ctx))
+	})
+}
+
+// retryCountWriter sets RetryCountHeader just before the headers are written
+type retryCountWriter struct {
+	http.ResponseWriter
+	stats       *k8s.RetryStats
+	wroteHeader bool
+}
+
+func (w *retryCountWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if n := w.stats.Retries(); n > 0 {
+			w.Header().Set(RetryCountHeader, strconv.Itoa(n))
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *retryCountWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush keeps SSE and long-poll handlers streaming through the wrapper
+func (w *retryCountWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *retryCountWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
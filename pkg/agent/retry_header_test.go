@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestWithRetryCount(t *testing.T) {
+	var calls atomic.Int32
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[]}`))
+	}))
+	defer apiServer.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(api.Config{
+		Clusters: map[string]*api.Cluster{"c": {Server: apiServer.URL}},
+		Contexts: map[string]*api.Context{"c1": {Cluster: "c"}},
+	}, kubeconfig); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+	m, _ := k8s.NewMultiClusterClient(kubeconfig)
+	m.SetRetryPolicy(k8s.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	client, err := m.GetClient("c1")
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+
+	handler := withRetryCount(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := client.CoreV1().Namespaces().List(r.Context(), metav1.ListOptions{}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/namespaces?cluster=c1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get(RetryCountHeader); got != "1" {
+		t.Errorf("Expected %s: 1, got %q", RetryCountHeader, got)
+	}
+
+	// No header when nothing was retried
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/namespaces?cluster=c1", nil))
+	if got := w.Header().Get(RetryCountHeader); got != "" {
+		t.Errorf("Expected no %s header, got %q", RetryCountHeader, got)
+	}
+}
@@ -3,12 +3,14 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,24 +19,30 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/audit"
 	"github.com/kubestellar/console/pkg/k8s"
 	"github.com/kubestellar/console/pkg/settings"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Request timeouts; agent.yaml can override them at startup
+var (
+	agentDefaultTimeout  = 30 * time.Second
+	agentExtendedTimeout = 60 * time.Second
+	agentCommandTimeout  = 45 * time.Second
 )
 
 const (
-	agentDefaultTimeout   = 30 * time.Second
-	agentExtendedTimeout  = 60 * time.Second
-	agentCommandTimeout   = 45 * time.Second
-	healthCheckTimeout    = 2 * time.Second
-	registryTimeout       = 10 * time.Second
-	consoleHealthTimeout  = 5 * time.Second
-	stabilizationDelay    = 3 * time.Second
-	startupDelay          = 500 * time.Millisecond
-	metricsHistoryTick    = 10 * time.Minute
-	agentFileMode         = 0600
-	defaultHealthCheckURL = "http://127.0.0.1:8080/health"
-	maxQueryLimit         = 1000     // Upper bound for client-supplied limit query parameter
-	maxRequestBodyBytes   = 1 << 20 // 1MB upper bound for request body reads
+	healthCheckTimeout   = 2 * time.Second
+	registryTimeout      = 10 * time.Second
+	consoleHealthTimeout = 5 * time.Second
+	stabilizationDelay   = 3 * time.Second
+	metricsHistoryTick   = 10 * time.Minute
+	agentFileMode        = 0600
+	maxQueryLimit        = 1000     // Upper bound for client-supplied limit query parameter
+	maxRequestBodyBytes  = 1 << 20 // 1MB upper bound for request body reads
+	// maxWSConcurrentRequests caps the requests one WebSocket connection runs at once
+	maxWSConcurrentRequests = 16
 )
 
 // Version is set by ldflags during build
@@ -45,6 +53,24 @@ type Config struct {
 	Port           int
 	Kubeconfig     string
 	AllowedOrigins []string // Additional allowed origins (from --allowed-origins flag)
+
+	// Leader election for multi-replica in-cluster deployments (--leader-elect)
+	LeaderElect             bool
+	LeaderElectionNamespace string // defaults to the pod's namespace
+
+	// GRPCPort serves the gRPC API (pkg/agent/rpc/consolev1) when non-zero (--grpc-port)
+	GRPCPort int
+
+	// MDNS advertises the agent on the local network and browses for other agents (--mdns)
+	MDNS bool
+
+	// AgentFile is the parsed ~/.kc/agent.yaml (nil if unused); AgentFilePath is
+	// watched for live changes when set
+	AgentFile     *AgentFile
+	AgentFilePath string
+
+	// Profile selects a profile from AgentFile at startup, overriding its profile setting (--profile)
+	Profile string
 }
 
 // AllowedOrigins for WebSocket connections (can be extended via env var)
@@ -72,7 +98,8 @@ type Server struct {
 	clientsMux     sync.RWMutex
 	wsMux          sync.Mutex // protects concurrent WebSocket writes
 	allowedOrigins []string
-	agentToken     string // Optional shared secret for authentication
+	agentToken     string       // Optional shared secret for authentication
+	authMu         sync.RWMutex // guards allowedOrigins and agentToken (reloaded from agent.yaml)
 
 	// Token tracking
 	tokenMux         sync.RWMutex
@@ -93,10 +120,44 @@ type Server struct {
 	// Hardware device tracking
 	deviceTracker *DeviceTracker
 
+	// Leader election: background subsystems run on one replica only
+	leader leaderState
+
+	// Broadcast subscribers (gRPC WatchEvents, SSE, long-poll) and the recent
+	// event log, fed by BroadcastToClients
+	eventSubs   map[chan agentEvent]struct{}
+	eventLog    []agentEvent
+	eventSeq    uint64
+	eventSubsMu sync.Mutex
+
+	// Recent list responses served to repeated polls (with ETags)
+	responseCache responseCache
+
+	// Alert silences / maintenance windows
+	alertSilences *AlertSilencer
+
+	// Scheduled cluster reports
+	reportScheduler *ReportScheduler
+
+	// Reachability history per kubeconfig context, for stale context cleanup
+	contextHealth *ContextHealthHistory
+
+	// mDNS advertisement and LAN peers (nil unless --mdns)
+	discovery *mdnsDiscovery
+
 	// Local cluster management
 	localClusters *LocalClusterManager
 
+	// Latest KubeStellar bootstrap run
+	kubestellarBootstrap kubestellarBootstrap
+	// Workload execution clusters registered with OCM hubs
+	wecRegistrations *WECRegistrations
+
+	// Subsystems switched on or off in agent.yaml or KC_FEATURES
+	features FeatureFlags
+
 	// Backend process management (for restart-from-UI)
+	backend    AgentFileBackend
 	backendCmd *exec.Cmd
 	backendMux sync.Mutex
 
@@ -107,22 +168,75 @@ type Server struct {
 	// Auto-update system
 	updateChecker *UpdateChecker
 
+	// Approval gates and audit trail for AI-executed commands
+	commandApprovals *CommandApprovalManager
+
+	// Per-provider token/cost budgets and the daily usage ledger behind /usage
+	tokenBudget *TokenBudgetTracker
+	tokenLedger *TokenLedger
+
+	// Config profiles: agent.yaml as last loaded, the active profile ("" for
+	// none) and the kubeconfig used without a profile
+	profileMu      sync.Mutex
+	agentFile      *AgentFile
+	activeProfile  string
+	baseKubeconfig string
+
 	SkipKeyValidation bool // For testing purposes
 }
 
 // NewServer creates a new agent server
 func NewServer(cfg Config) (*Server, error) {
-	kubectl, err := NewKubectlProxy(cfg.Kubeconfig)
+	baseFile := cfg.AgentFile
+	if baseFile == nil {
+		baseFile = &AgentFile{}
+	}
+	activeProfile := baseFile.Profile
+	if cfg.Profile != "" {
+		activeProfile = cfg.Profile
+	}
+	// agentFile is agent.yaml with the active profile's settings applied
+	agentFile, err := baseFile.withProfile(activeProfile)
+	if err != nil {
+		return nil, err
+	}
+	baseKubeconfig := resolveKubeconfigPath(cfg.Kubeconfig)
+	kubeconfig := profileKubeconfig(baseFile, activeProfile, baseKubeconfig)
+	if activeProfile != "" {
+		log.Printf("Using config profile %q", activeProfile)
+	}
+
+	kubectl, err := NewKubectlProxy(kubeconfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize kubectl proxy: %w", err)
 	}
 
+	agentFile.applyTimeouts()
+	GetConfigManager().SetFileDefaults(agentFile.DefaultAgent, agentFile.Providers)
+
 	// Initialize k8s client for rich cluster data queries
-	k8sClient, err := k8s.NewMultiClusterClient(cfg.Kubeconfig)
+	k8sClient, err := k8s.NewMultiClusterClient(kubeconfig)
 	if err != nil {
 		log.Printf("Warning: failed to initialize k8s client: %v", err)
 		// Don't fail - kubectl functionality still works
 	}
+	if filter, err := k8s.NewClusterFilter(agentFile.Clusters.Include, agentFile.Clusters.Exclude); err != nil {
+		log.Printf("Warning: ignoring cluster filter: %v", err)
+	} else if k8sClient != nil {
+		k8sClient.SetClusterFilter(filter)
+	}
+	if k8sClient != nil {
+		k8sClient.SetClusterPolicySource(SettingsClusterPolicy)
+		if agentFile.NamespaceScope.Enabled() && os.Getenv(k8s.NamespaceScopeEnv) == "" {
+			k8sClient.SetNamespaceScope(agentFile.NamespaceScope.scope())
+		}
+		if agentFile.Retries != (AgentFileRetries{}) {
+			k8sClient.SetRetryPolicy(agentFile.retryPolicy(k8sClient.RetryPolicy()))
+		}
+		if !agentFile.APIClient.isZero() {
+			k8sClient.SetClientTuning(agentFile.clientTuning(k8sClient.ClientTuning()))
+		}
+	}
 
 	// Initialize AI providers
 	if err := InitializeProviders(); err != nil {
@@ -130,26 +244,8 @@ func NewServer(cfg Config) (*Server, error) {
 		// Don't fail - kubectl functionality still works without AI
 	}
 
-	// Build allowed origins list
-	allowedOrigins := append([]string{}, defaultAllowedOrigins...)
-
-	// Add custom origins from environment variable (comma-separated)
-	if extraOrigins := os.Getenv("KC_ALLOWED_ORIGINS"); extraOrigins != "" {
-		for _, origin := range strings.Split(extraOrigins, ",") {
-			origin = strings.TrimSpace(origin)
-			if origin != "" {
-				allowedOrigins = append(allowedOrigins, origin)
-			}
-		}
-	}
-
-	// Add custom origins from CLI flag
-	for _, origin := range cfg.AllowedOrigins {
-		origin = strings.TrimSpace(origin)
-		if origin != "" {
-			allowedOrigins = append(allowedOrigins, origin)
-		}
-	}
+	// Build allowed origins list: defaults, agent.yaml, KC_ALLOWED_ORIGINS, --allowed-origins
+	allowedOrigins := buildAllowedOrigins(agentFile, cfg.AllowedOrigins)
 
 	// Log non-default origins so users can verify their configuration
 	if len(allowedOrigins) > len(defaultAllowedOrigins) {
@@ -157,7 +253,7 @@ func NewServer(cfg Config) (*Server, error) {
 	}
 
 	// Optional shared secret for authentication
-	agentToken := os.Getenv("KC_AGENT_TOKEN")
+	agentToken := agentTokenFrom(agentFile)
 	if agentToken != "" {
 		log.Println("Agent token authentication enabled")
 	}
@@ -174,6 +270,10 @@ func NewServer(cfg Config) (*Server, error) {
 		sessionStart:   now,
 		todayDate:      now.Format("2006-01-02"),
 		activeChatCtxs: make(map[string]context.CancelFunc),
+		features:       newFeatureFlags(agentFile),
+		agentFile:      baseFile,
+		activeProfile:  activeProfile,
+		baseKubeconfig: baseKubeconfig,
 	}
 
 	server.upgrader = websocket.Upgrader{
@@ -186,6 +286,23 @@ func NewServer(cfg Config) (*Server, error) {
 	// Initialize prediction system
 	server.predictionWorker = NewPredictionWorker(k8sClient, server.registry, server.BroadcastToClients, server.addTokenUsage)
 	server.metricsHistory = NewMetricsHistory(k8sClient, "")
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		server.contextHealth = NewContextHealthHistory(filepath.Join(homeDir, configDirName))
+		kubectl.SetBackupDir(filepath.Join(homeDir, configDirName, kubeconfigBackupDirName))
+		server.wecRegistrations = NewWECRegistrations(filepath.Join(homeDir, configDirName), func(managed map[string]bool) {
+			if k8sClient != nil {
+				k8sClient.SetManagedContexts(managed)
+			}
+		})
+		server.metricsHistory.SetHealthRecorder(server.contextHealth.Record)
+	}
+	server.predictionWorker.RegisterAnalyzer(NewMetricsAnomalyAnalyzer(server.metricsHistory))
+
+	// Initialize token budgets; limits are read from settings on each check
+	server.tokenBudget = NewTokenBudgetTracker("", settingsTokenBudgets, server.BroadcastToClients)
+	server.tokenLedger = NewTokenLedger("")
+	server.predictionWorker.SetBudgetHooks(server.tokenBudget.Check, server.recordPredictionTokens)
+	server.predictionWorker.SetAnalyzerConfigSource(settingsPredictionAnalyzers)
 
 	// Initialize insight enrichment
 	server.insightWorker = NewInsightWorker(server.registry, server.BroadcastToClients)
@@ -194,22 +311,54 @@ func NewServer(cfg Config) (*Server, error) {
 	server.localClusters = NewLocalClusterManager(server.BroadcastToClients)
 
 	// Initialize auto-update checker
+	server.backend = agentFile.Backend.withEnv()
 	server.updateChecker = NewUpdateChecker(UpdateCheckerConfig{
-		Broadcast:      server.BroadcastToClients,
-		RestartBackend: server.startBackendProcess,
-		KillBackend:    server.killBackendProcess,
+		Broadcast:        server.BroadcastToClients,
+		RestartBackend:   server.startBackendProcess,
+		KillBackend:      server.killBackendProcess,
+		BackendBinary:    server.backend.Binary,
+		BackendHealthURL: server.backend.healthURL(),
 	})
 
+	// Initialize command approval gates for mixed-mode execution
+	server.commandApprovals = NewCommandApprovalManager("")
+	if forwarder, err := audit.NewForwarder(audit.ConfigFromEnv("kc-agent")); err != nil {
+		log.Printf("Warning: audit forwarding disabled: %v", err)
+	} else {
+		server.commandApprovals.SetForwarder(forwarder)
+	}
+
+	// Initialize alert silences; predictions and device alerts consult them before notifying
+	server.alertSilences = NewAlertSilencer(settingsAlertSilences, saveSettingsAlertSilences)
+	server.predictionWorker.SetSilenceFilter(server.predictionSilenced)
+
 	// Initialize device tracker with notification callback
 	server.deviceTracker = NewDeviceTracker(k8sClient, func(msgType string, payload interface{}) {
+		resp, isAlerts := payload.(DeviceAlertsResponse)
+		if msgType == "device_alerts_updated" && isAlerts {
+			// Skip the broadcast entirely when every alert is silenced
+			if len(resp.Alerts) > 0 && server.applyDeviceSilences(&resp) == 0 {
+				return
+			}
+			payload = resp
+		}
 		server.BroadcastToClients(msgType, payload)
 		// Send native notification for device alerts
-		if msgType == "device_alerts_updated" {
-			if resp, ok := payload.(DeviceAlertsResponse); ok && len(resp.Alerts) > 0 {
-				server.sendNativeNotification(resp.Alerts)
+		if msgType == "device_alerts_updated" && isAlerts {
+			if active := unsilencedDeviceAlerts(resp.Alerts); len(active) > 0 {
+				server.sendNativeNotification(active)
 			}
 		}
 	})
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		server.deviceTracker.SetDataDir(filepath.Join(homeDir, configDirName))
+	}
+	server.deviceTracker.SetThresholdSource(settingsDeviceThresholds)
+
+	server.reportScheduler = NewReportScheduler(k8sClient, settingsReportSchedules, saveReportSent)
+	if k8sClient != nil {
+		k8sClient.SetGPUOperatorCheckSource(settingsGPUOperatorChecks)
+	}
 
 	return server, nil
 }
@@ -225,7 +374,7 @@ func (s *Server) checkOrigin(r *http.Request) bool {
 	}
 
 	// Check against allowed origins (supports wildcards like "https://*.ibm.com")
-	for _, allowed := range s.allowedOrigins {
+	for _, allowed := range s.origins() {
 		if matchOrigin(origin, allowed) {
 			return true
 		}
@@ -237,8 +386,9 @@ func (s *Server) checkOrigin(r *http.Request) bool {
 
 // validateToken checks the authentication token (if configured)
 func (s *Server) validateToken(r *http.Request) bool {
+	agentToken := s.token()
 	// If no token configured, skip token validation
-	if s.agentToken == "" {
+	if agentToken == "" {
 		return true
 	}
 
@@ -246,19 +396,32 @@ func (s *Server) validateToken(r *http.Request) bool {
 	authHeader := r.Header.Get("Authorization")
 	if strings.HasPrefix(authHeader, "Bearer ") {
 		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == s.agentToken {
+		if token == agentToken {
 			return true
 		}
 	}
 
 	// Check query parameter as fallback (for WebSocket connections)
-	if r.URL.Query().Get("token") == s.agentToken {
+	if r.URL.Query().Get("token") == agentToken {
 		return true
 	}
 
 	return false
 }
 
+// kubeconfigReloaded refreshes kubectl and tells clients about the new cluster list
+func (s *Server) kubeconfigReloaded() {
+	log.Println("[Server] Kubeconfig reloaded, broadcasting to clients...")
+	s.responseCache.clear()
+	s.kubectl.Reload()
+	clusters, current := s.listContexts()
+	s.BroadcastToClients("clusters_updated", protocol.ClustersPayload{
+		Clusters: clusters,
+		Current:  current,
+	})
+	log.Printf("[Server] Broadcasted %d clusters to clients", len(clusters))
+}
+
 // Start starts the agent server
 func (s *Server) Start() error {
 	mux := http.NewServeMux()
@@ -266,30 +429,49 @@ func (s *Server) Start() error {
 	// Health endpoint (HTTP for easy browser detection)
 	mux.HandleFunc("/health", s.handleHealth)
 
+	// LAN discovery of this and teammates' agents (mDNS, see --mdns)
+	mux.HandleFunc("/discovery", s.handleDiscovery)
+
 	// Clusters endpoint - returns fresh kubeconfig contexts
 	mux.HandleFunc("/clusters", s.handleClustersHTTP)
-
-	// Cluster data endpoints - direct k8s queries without backend
-	mux.HandleFunc("/gpu-nodes", s.handleGPUNodesHTTP)
-	mux.HandleFunc("/nodes", s.handleNodesHTTP)
-	mux.HandleFunc("/pods", s.handlePodsHTTP)
-	mux.HandleFunc("/events", s.handleEventsHTTP)
-	mux.HandleFunc("/namespaces", s.handleNamespacesHTTP)
-	mux.HandleFunc("/deployments", s.handleDeploymentsHTTP)
-	mux.HandleFunc("/replicasets", s.handleReplicaSetsHTTP)
-	mux.HandleFunc("/statefulsets", s.handleStatefulSetsHTTP)
-	mux.HandleFunc("/daemonsets", s.handleDaemonSetsHTTP)
-	mux.HandleFunc("/cronjobs", s.handleCronJobsHTTP)
-	mux.HandleFunc("/ingresses", s.handleIngressesHTTP)
-	mux.HandleFunc("/networkpolicies", s.handleNetworkPoliciesHTTP)
-	mux.HandleFunc("/services", s.handleServicesHTTP)
-	mux.HandleFunc("/configmaps", s.handleConfigMapsHTTP)
-	mux.HandleFunc("/secrets", s.handleSecretsHTTP)
-	mux.HandleFunc("/serviceaccounts", s.handleServiceAccountsHTTP)
-	mux.HandleFunc("/jobs", s.handleJobsHTTP)
-	mux.HandleFunc("/hpas", s.handleHPAsHTTP)
-	mux.HandleFunc("/pvcs", s.handlePVCsHTTP)
+	mux.HandleFunc("/cluster-filters", s.handleClusterFilters)
+	mux.HandleFunc("/cluster-preferences", s.handleClusterPreferences)
+	mux.HandleFunc("/clusters/focus", s.handleClusterFocus)
+
+	// Cluster data endpoints - direct k8s queries without backend. Lists are cached
+	// briefly and carry ETags so rapid polls get 304s instead of re-listing.
+	mux.HandleFunc("/gpu-nodes", s.cachedList(s.handleGPUNodesHTTP))
+	mux.HandleFunc("/gpu-nodes/export", s.handleGPUNodesExport)
+	mux.HandleFunc("/nodes", s.cachedList(s.handleNodesHTTP))
+	mux.HandleFunc("/nodes/hardware", s.cachedList(s.handleNodesHardwareHTTP))
+	mux.HandleFunc("/versions", s.cachedList(s.handleVersionSkew))
+	mux.HandleFunc("/pods", s.cachedList(s.handlePodsHTTP))
+	mux.HandleFunc("/pods/export", s.handlePodsExport)
+	mux.HandleFunc("/events", s.cachedList(s.handleEventsHTTP))
+	mux.HandleFunc("/events/workload", s.cachedList(s.handleWorkloadEventsHTTP))
+	mux.HandleFunc("/events/stream", s.handleFleetEventStream)
+	mux.HandleFunc("/namespaces", s.cachedList(s.handleNamespacesHTTP))
+	mux.HandleFunc("/deployments", s.cachedList(s.handleDeploymentsHTTP))
+	mux.HandleFunc("/workloads", s.cachedList(s.handleWorkloadsHTTP))
+	mux.HandleFunc("/replicasets", s.cachedList(s.handleReplicaSetsHTTP))
+	mux.HandleFunc("/statefulsets", s.cachedList(s.handleStatefulSetsHTTP))
+	mux.HandleFunc("/daemonsets", s.cachedList(s.handleDaemonSetsHTTP))
+	mux.HandleFunc("/cronjobs", s.cachedList(s.handleCronJobsHTTP))
+	mux.HandleFunc("/ingresses", s.cachedList(s.handleIngressesHTTP))
+	mux.HandleFunc("/networkpolicies", s.cachedList(s.handleNetworkPoliciesHTTP))
+	mux.HandleFunc("/services", s.cachedList(s.handleServicesHTTP))
+	mux.HandleFunc("/configmaps", s.cachedList(s.handleConfigMapsHTTP))
+	mux.HandleFunc("/secrets", s.cachedList(s.handleSecretsHTTP))
+	mux.HandleFunc("/serviceaccounts", s.cachedList(s.handleServiceAccountsHTTP))
+	mux.HandleFunc("/jobs", s.cachedList(s.handleJobsHTTP))
+	mux.HandleFunc("/jobs/logs", s.handleJobLogsHTTP)
+	mux.HandleFunc("/hpas", s.cachedList(s.handleHPAsHTTP))
+	mux.HandleFunc("/hpas/detail", s.cachedList(s.handleHPADetailHTTP))
+	mux.HandleFunc("/pdbs", s.cachedList(s.handlePDBsHTTP))
+	mux.HandleFunc("/pdbs/risks", s.cachedList(s.handleDisruptionRisksHTTP))
+	mux.HandleFunc("/pvcs", s.cachedList(s.handlePVCsHTTP))
 	mux.HandleFunc("/cluster-health", s.handleClusterHealthHTTP)
+	mux.HandleFunc("/summary", s.handleSummaryHTTP)
 
 	// Rename context endpoint
 	mux.HandleFunc("/rename-context", s.handleRenameContextHTTP)
@@ -299,6 +481,9 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/kubeconfig/import", s.handleKubeconfigImportHTTP)
 	mux.HandleFunc("/kubeconfig/add", s.handleKubeconfigAddHTTP)
 	mux.HandleFunc("/kubeconfig/test", s.handleKubeconfigTestHTTP)
+	mux.HandleFunc("/doctor", s.handleDoctor)
+	mux.HandleFunc("/contexts/stale", s.handleStaleContexts)
+	mux.HandleFunc("/undo", s.handleUndo)
 
 	// Settings endpoints for API key management
 	mux.HandleFunc("/settings/keys", s.handleSettingsKeys)
@@ -313,20 +498,31 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/providers/health", s.handleProvidersHealth)
 
 	// Prediction endpoints
-	mux.HandleFunc("/predictions/ai", s.handlePredictionsAI)
-	mux.HandleFunc("/predictions/analyze", s.handlePredictionsAnalyze)
-	mux.HandleFunc("/predictions/feedback", s.handlePredictionsFeedback)
-	mux.HandleFunc("/predictions/stats", s.handlePredictionsStats)
+	mux.HandleFunc("/predictions/ai", s.requireFeature(FeaturePredictions, s.handlePredictionsAI))
+	mux.HandleFunc("/predictions/analyze", s.requireFeature(FeaturePredictions, s.handlePredictionsAnalyze))
+	mux.HandleFunc("/predictions/feedback", s.requireFeature(FeaturePredictions, s.handlePredictionsFeedback))
+	mux.HandleFunc("/predictions/stats", s.requireFeature(FeaturePredictions, s.handlePredictionsStats))
+	mux.HandleFunc("/predictions/analyzers", s.requireFeature(FeaturePredictions, s.handlePredictionAnalyzers))
 
 	// Insight enrichment endpoints
 	mux.HandleFunc("/insights/enrich", s.handleInsightsEnrich)
 	mux.HandleFunc("/insights/ai", s.handleInsightsAI)
 
 	// Device tracking endpoints
-	mux.HandleFunc("/devices/alerts", s.handleDeviceAlerts)
-	mux.HandleFunc("/devices/alerts/clear", s.handleDeviceAlertsClear)
-	mux.HandleFunc("/devices/inventory", s.handleDeviceInventory)
-	mux.HandleFunc("/metrics/history", s.handleMetricsHistory)
+	mux.HandleFunc("/devices/alerts", s.requireFeature(FeatureDeviceTracker, s.handleDeviceAlerts))
+	mux.HandleFunc("/devices/alerts/clear", s.requireFeature(FeatureDeviceTracker, s.handleDeviceAlertsClear))
+	mux.HandleFunc("/devices/inventory", s.requireFeature(FeatureDeviceTracker, s.handleDeviceInventory))
+	mux.HandleFunc("/devices/trends", s.requireFeature(FeatureDeviceTracker, s.handleDeviceTrends))
+	mux.HandleFunc("/devices/thresholds", s.requireFeature(FeatureDeviceTracker, s.handleDeviceThresholds))
+
+	// Alert silences / maintenance windows
+	mux.HandleFunc("/alerts/silences", s.handleAlertSilences)
+
+	// Scheduled reports
+	mux.HandleFunc("/reports/schedules", s.requireFeature(FeatureReports, s.handleReportSchedules))
+	mux.HandleFunc("/reports/preview", s.requireFeature(FeatureReports, s.handleReportPreview))
+	mux.HandleFunc("/reports/send", s.requireFeature(FeatureReports, s.handleReportSend))
+	mux.HandleFunc("/metrics/history", s.requireFeature(FeatureMetricsHistory, s.handleMetricsHistory))
 
 	// Kagenti AI agent platform endpoints
 	mux.HandleFunc("/kagenti/agents", s.handleKagentiAgents)
@@ -335,15 +531,44 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/kagenti/tools", s.handleKagentiTools)
 	mux.HandleFunc("/kagenti/summary", s.handleKagentiSummary)
 
+	// AI/ML workloads: model serving (vLLM, TGI, KServe) and distributed training (Kubeflow, Ray)
+	mux.HandleFunc("/inference", s.handleInference)
+	mux.HandleFunc("/training-jobs", s.handleTrainingJobs)
+
+	// Node capacity: Cluster API node pools (list/scale), autoscaler activity and drain safety
+	mux.HandleFunc("/machine-pools", s.handleMachinePools)
+	mux.HandleFunc("/autoscaler", s.handleAutoscaler)
+	mux.HandleFunc("/nodes/drain-check", s.handleDrainCheck)
+
 	// Cloud CLI status (detects installed cloud CLIs for IAM auth guidance)
 	mux.HandleFunc("/cloud-cli-status", s.handleCloudCLIStatus)
 
 	// Local cluster management endpoints
-	mux.HandleFunc("/local-cluster-tools", s.handleLocalClusterTools)
-	mux.HandleFunc("/local-clusters", s.handleLocalClusters)
+	mux.HandleFunc("/local-cluster-tools", s.requireFeature(FeatureLocalClusters, s.handleLocalClusterTools))
+	mux.HandleFunc("/local-clusters", s.requireFeature(FeatureLocalClusters, s.handleLocalClusters))
+	mux.HandleFunc("/local-cluster-templates", s.requireFeature(FeatureLocalClusters, s.handleLocalClusterTemplates))
+	mux.HandleFunc("/local-clusters/lifecycle", s.requireFeature(FeatureLocalClusters, s.handleLocalClusterLifecycle))
+	mux.HandleFunc("/local-clusters/usage", s.requireFeature(FeatureLocalClusters, s.handleLocalClusterUsage))
+	mux.HandleFunc("/kubestellar/bootstrap", s.handleKubeStellarBootstrap)
+	mux.HandleFunc("/kubestellar/wecs", s.handleWECs)
+	mux.HandleFunc("/kubestellar/wecs/join", s.handleWECJoin)
+	mux.HandleFunc("/controlplanes", s.cachedList(s.handleControlPlanes))
+	mux.HandleFunc("/validate", s.handleValidate)
+	mux.HandleFunc("/render", s.handleRender)
 
 	// Chat cancel endpoint — HTTP fallback when WebSocket is disconnected
-	mux.HandleFunc("/cancel-chat", s.handleCancelChatHTTP)
+	mux.HandleFunc("/cancel-chat", s.requireFeature(FeatureAIChat, s.handleCancelChatHTTP))
+
+	// AI command approval endpoints — HTTP fallback and audit trail
+	mux.HandleFunc("/commands/approve", s.handleCommandApprovalHTTP)
+	mux.HandleFunc("/commands/audit", s.handleCommandAudit)
+
+	// AI token budget configuration and status
+	mux.HandleFunc("/token-budget", s.handleTokenBudget)
+	mux.HandleFunc("/usage", s.handleUsage)
+	mux.HandleFunc("/usage/export", s.handleUsageExport)
+	mux.HandleFunc("/profiles", s.handleProfiles)
+	mux.HandleFunc("/profiles/active", s.handleActiveProfile)
 
 	// Backend process management
 	mux.HandleFunc("/restart-backend", s.handleRestartBackend)
@@ -352,16 +577,24 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/auto-update/config", s.handleAutoUpdateConfig)
 	mux.HandleFunc("/auto-update/status", s.handleAutoUpdateStatus)
 	mux.HandleFunc("/auto-update/trigger", s.handleAutoUpdateTrigger)
+	mux.HandleFunc("/auto-update/history", s.handleAutoUpdateHistory)
 
 	// Prometheus query proxy - queries Prometheus in user clusters via K8s API server proxy
 	mux.HandleFunc("/prometheus/query", s.handlePrometheusQuery)
 
+	// Allowlisted read-only Kubernetes API proxy for direct browser access (opt-in via agent.yaml)
+	mux.HandleFunc("/proxy/", s.handleAPIProxy)
+
 	// Prometheus metrics endpoint (agent's own metrics)
 	mux.Handle("/metrics", GetMetricsHandler())
 
 	// WebSocket endpoint
 	mux.HandleFunc("/ws", s.handleWebSocket)
 
+	// SSE and long-poll fallbacks for networks that block WebSockets
+	mux.HandleFunc("/stream", s.handleEventStreamSSE)
+	mux.HandleFunc("/stream/poll", s.handleEventStreamPoll)
+
 	// CORS preflight - includes Private Network Access header for browser security
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -380,41 +613,55 @@ func (s *Server) Start() error {
 	log.Printf("Health: http://%s/health", addr)
 	log.Printf("WebSocket: ws://%s/ws", addr)
 
+	if s.config.AgentFilePath != "" {
+		go s.watchAgentFile(s.config.AgentFilePath, s.config.AgentFile)
+	}
+
+	handler := withRetryCount(withImpersonation(mux))
+	if s.config.MDNS {
+		// Advertised peers connect over the LAN, which must never be unauthenticated
+		if s.token() == "" {
+			return errMDNSNeedsToken
+		}
+		var name string
+		if s.config.AgentFile != nil {
+			name = s.config.AgentFile.Discovery.Name
+		}
+		if addrs := s.listenLAN(handler); len(addrs) == 0 {
+			log.Printf("Warning: mDNS discovery disabled: no local network address to listen on")
+		} else {
+			d := newMDNSDiscovery(name, s.config.Port, addrs, func() bool { return s.token() != "" })
+			if err := d.Start(); err != nil {
+				log.Printf("Warning: mDNS discovery disabled: %v", err)
+			} else {
+				s.discovery = d
+			}
+		}
+	}
+
+	if s.config.GRPCPort > 0 {
+		go s.serveGRPC(fmt.Sprintf("127.0.0.1:%d", s.config.GRPCPort))
+	}
+
 	// Validate all configured API keys on startup (run in background to not delay startup)
 	go s.ValidateAllKeys()
 
 	// Start kubeconfig file watcher (uses k8s client's built-in watcher)
 	if s.k8sClient != nil {
-		s.k8sClient.SetOnReload(func() {
-			log.Println("[Server] Kubeconfig reloaded, broadcasting to clients...")
-			s.kubectl.Reload()
-			clusters, current := s.kubectl.ListContexts()
-			s.BroadcastToClients("clusters_updated", protocol.ClustersPayload{
-				Clusters: clusters,
-				Current:  current,
-			})
-			log.Printf("[Server] Broadcasted %d clusters to clients", len(clusters))
-		})
+		s.k8sClient.SetOnReload(s.kubeconfigReloaded)
 		if err := s.k8sClient.StartWatching(); err != nil {
 			log.Printf("Warning: failed to start kubeconfig watcher: %v", err)
 		}
+		// Map KubeFlex control planes into clusters as they come and go
+		go s.watchControlPlanes()
+		if s.features.Enabled(FeatureHealthRefresh) {
+			s.k8sClient.StartHealthRefresher()
+		}
 	}
 
-	// Start prediction system
-	if s.predictionWorker != nil {
-		s.predictionWorker.Start()
-		log.Println("Prediction worker started")
-	}
-	if s.metricsHistory != nil {
-		s.metricsHistory.Start(metricsHistoryTick)
-		log.Println("Metrics history started")
-	}
-
-	// Start device tracker
-	if s.deviceTracker != nil {
-		s.deviceTracker.Start()
-		log.Println("Device tracker started")
-	}
+	// Start prediction system, metrics history and device tracker (on the leader only
+	// when leader election is enabled)
+	s.runBackgroundSubsystems()
 
 	// Load auto-update config from settings and start if enabled
 	if s.updateChecker != nil {
@@ -429,7 +676,7 @@ func (s *Server) Start() error {
 		}
 	}
 
-	return http.ListenAndServe(addr, mux)
+	return http.ListenAndServe(addr, handler)
 }
 
 // handleHealth handles HTTP health checks
@@ -453,7 +700,7 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	// Health endpoint doesn't require token auth (used for discovery)
 	// but does enforce origin checks via CORS
 
-	clusters, _ := s.kubectl.ListContexts()
+	clusters, _ := s.listContexts()
 	hasClaude := s.checkClaudeAvailable()
 
 	// Build lightweight provider summaries for telemetry
@@ -474,6 +721,20 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		Claude:             s.getClaudeInfo(),
 		InstallMethod:      detectAgentInstallMethod(),
 		AvailableProviders: providerSummaries,
+		Features:           s.features,
+	}
+	if s.leader.enabled {
+		payload.LeaderElection = "follower"
+		if s.IsLeader() {
+			payload.LeaderElection = "leader"
+		}
+	}
+	if s.k8sClient != nil {
+		if scope := s.k8sClient.NamespaceScope(); scope.Discover && len(scope.Namespaces) == 0 {
+			payload.NamespaceScope = "discover"
+		} else if scope.Enabled() {
+			payload.NamespaceScope = "namespaces"
+		}
 	}
 
 	json.NewEncoder(w).Encode(payload)
@@ -485,7 +746,7 @@ func (s *Server) isAllowedOrigin(origin string) bool {
 	if origin == "" {
 		return false
 	}
-	for _, allowed := range s.allowedOrigins {
+	for _, allowed := range s.origins() {
 		if matchOrigin(origin, allowed) {
 			return true
 		}
@@ -493,6 +754,20 @@ func (s *Server) isAllowedOrigin(origin string) bool {
 	return false
 }
 
+// origins returns the allowed origins, which agent.yaml reloads may replace
+func (s *Server) origins() []string {
+	s.authMu.RLock()
+	defer s.authMu.RUnlock()
+	return s.allowedOrigins
+}
+
+// token returns the agent token, which agent.yaml reloads may replace
+func (s *Server) token() string {
+	s.authMu.RLock()
+	defer s.authMu.RUnlock()
+	return s.agentToken
+}
+
 // matchOrigin checks if an origin matches an allowed pattern.
 // Supports prefix matching (e.g. "http://localhost" matches "http://localhost:5174")
 // and wildcard suffix matching (e.g. "https://*.ibm.com" matches "https://kc.apps.example.ibm.com").
@@ -530,7 +805,10 @@ func (s *Server) handleClustersHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.kubectl.Reload()
-	clusters, current := s.kubectl.ListContexts()
+	clusters, current := s.listContexts()
+	if group := r.URL.Query().Get("group"); group != "" {
+		clusters = clustersInGroup(clusters, group)
+	}
 	json.NewEncoder(w).Encode(protocol.ClustersPayload{Clusters: clusters, Current: current})
 }
 
@@ -555,7 +833,7 @@ func (s *Server) handleGPUNodesHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	cluster := r.URL.Query().Get("cluster")
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 
 	var allNodes []k8s.GPUNode
@@ -588,7 +866,7 @@ func (s *Server) handleGPUNodesHTTP(w http.ResponseWriter, r *http.Request) {
 						log.Printf("[GPUNodes] recovered from panic for cluster %s: %v", clusterName, r)
 					}
 				}()
-				clusterCtx, clusterCancel := context.WithTimeout(ctx, agentDefaultTimeout)
+				clusterCtx, clusterCancel := context.WithTimeout(ctx, s.requestTimeout(r, clusterName, k8s.OpList, agentDefaultTimeout))
 				defer clusterCancel()
 				nodes, err := s.k8sClient.GetGPUNodes(clusterCtx, clusterName)
 				if err == nil && len(nodes) > 0 {
@@ -625,7 +903,7 @@ func (s *Server) handleNodesHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	cluster := r.URL.Query().Get("cluster")
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 
 	var allNodes []k8s.NodeInfo
@@ -660,7 +938,7 @@ func (s *Server) handleNodesHTTP(w http.ResponseWriter, r *http.Request) {
 						log.Printf("[Nodes] recovered from panic for cluster %s: %v", clusterName, r)
 					}
 				}()
-				clusterCtx, clusterCancel := context.WithTimeout(ctx, agentDefaultTimeout)
+				clusterCtx, clusterCancel := context.WithTimeout(ctx, s.requestTimeout(r, clusterName, k8s.OpList, agentDefaultTimeout))
 				defer clusterCancel()
 				nodes, err := s.k8sClient.GetNodes(clusterCtx, clusterName)
 				if err == nil && len(nodes) > 0 {
@@ -676,6 +954,87 @@ func (s *Server) handleNodesHTTP(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{"nodes": allNodes, "source": "agent"})
 }
 
+// handleNodesHardwareHTTP returns the NFD hardware inventory for a cluster or all clusters
+func (s *Server) handleNodesHardwareHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"nodes": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+
+	cluster := r.URL.Query().Get("cluster")
+	node := r.URL.Query().Get("node")
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+
+	allNodes := []k8s.NodeHardwareInventory{}
+
+	if cluster != "" {
+		nodes, err := s.k8sClient.GetNodeHardware(ctx, cluster)
+		if err != nil {
+			log.Printf("error fetching node hardware: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"nodes": []interface{}{}, "error": "internal server error"})
+			return
+		}
+		allNodes = append(allNodes, nodes...)
+	} else {
+		clusters, err := s.k8sClient.ListClusters(ctx)
+		if err != nil {
+			log.Printf("error fetching node hardware: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"nodes": []interface{}{}, "error": "internal server error"})
+			return
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+
+		for _, cl := range clusters {
+			wg.Add(1)
+			go func(clusterName string) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[NodeHardware] recovered from panic for cluster %s: %v", clusterName, r)
+					}
+				}()
+				clusterCtx, clusterCancel := context.WithTimeout(ctx, s.requestTimeout(r, clusterName, k8s.OpList, agentDefaultTimeout))
+				defer clusterCancel()
+				nodes, err := s.k8sClient.GetNodeHardware(clusterCtx, clusterName)
+				if err == nil && len(nodes) > 0 {
+					mu.Lock()
+					allNodes = append(allNodes, nodes...)
+					mu.Unlock()
+				}
+			}(cl.Name)
+		}
+		wg.Wait()
+	}
+
+	if node != "" {
+		filtered := []k8s.NodeHardwareInventory{}
+		for _, n := range allNodes {
+			if n.Name == node {
+				filtered = append(filtered, n)
+			}
+		}
+		allNodes = filtered
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"nodes": allNodes, "source": "agent"})
+}
+
 // handleEventsHTTP returns events for a cluster/namespace/object
 func (s *Server) handleEventsHTTP(w http.ResponseWriter, r *http.Request) {
 	s.setCORSHeaders(w, r)
@@ -713,7 +1072,7 @@ func (s *Server) handleEventsHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 
 	// Get events from the cluster
@@ -728,13 +1087,56 @@ func (s *Server) handleEventsHTTP(w http.ResponseWriter, r *http.Request) {
 	if objectName != "" {
 		var filtered []k8s.Event
 		for _, e := range events {
-			if strings.Contains(e.Object, objectName) {
+			if strings.Contains(e.Object, objectName) || strings.Contains(e.Owner, objectName) {
 				filtered = append(filtered, e)
 			}
 		}
 		events = filtered
 	}
 
+	if wantsNDJSON(r) {
+		writeNDJSON(w, events)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"events": events, "source": "agent"})
+}
+
+// handleWorkloadEventsHTTP returns the events of a workload together with those
+// of its ReplicaSets, Jobs and pods, deduplicated and newest first
+func (s *Server) handleWorkloadEventsHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"events": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+	q := r.URL.Query()
+	cluster := q.Get("cluster")
+	namespace := q.Get("namespace")
+	kind := q.Get("kind")
+	name := q.Get("name")
+	if cluster == "" || namespace == "" || kind == "" || name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"events": []interface{}{}, "error": "cluster, namespace, kind and name parameters required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+	events, err := s.k8sClient.GetWorkloadEvents(ctx, cluster, namespace, kind, name)
+	if err != nil {
+		if errors.Is(err, k8s.ErrUnsupportedWorkloadKind) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"events": []interface{}{}, "error": err.Error()})
+			return
+		}
+		log.Printf("error fetching workload events: %v", err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"events": []interface{}{}, "error": "internal server error"})
+		return
+	}
 	json.NewEncoder(w).Encode(map[string]interface{}{"events": events, "source": "agent"})
 }
 
@@ -759,7 +1161,7 @@ func (s *Server) handleNamespacesHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), agentExtendedTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentExtendedTimeout))
 	defer cancel()
 
 	namespaces, err := s.k8sClient.ListNamespacesWithDetails(ctx, cluster)
@@ -794,7 +1196,7 @@ func (s *Server) handleDeploymentsHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 
 	// If namespace not specified, get deployments from all namespaces
@@ -809,6 +1211,10 @@ func (s *Server) handleDeploymentsHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if wantsNDJSON(r) {
+		writeNDJSON(w, deployments)
+		return
+	}
 	json.NewEncoder(w).Encode(map[string]interface{}{"deployments": deployments, "source": "agent"})
 }
 
@@ -830,7 +1236,7 @@ func (s *Server) handleReplicaSetsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"replicasets": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	replicasets, err := s.k8sClient.GetReplicaSets(ctx, cluster, namespace)
 	if err != nil {
@@ -859,7 +1265,7 @@ func (s *Server) handleStatefulSetsHTTP(w http.ResponseWriter, r *http.Request)
 		json.NewEncoder(w).Encode(map[string]interface{}{"statefulsets": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	statefulsets, err := s.k8sClient.GetStatefulSets(ctx, cluster, namespace)
 	if err != nil {
@@ -888,7 +1294,7 @@ func (s *Server) handleDaemonSetsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"daemonsets": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	daemonsets, err := s.k8sClient.GetDaemonSets(ctx, cluster, namespace)
 	if err != nil {
@@ -917,7 +1323,7 @@ func (s *Server) handleCronJobsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"cronjobs": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	cronjobs, err := s.k8sClient.GetCronJobs(ctx, cluster, namespace)
 	if err != nil {
@@ -946,7 +1352,7 @@ func (s *Server) handleIngressesHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"ingresses": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	ingresses, err := s.k8sClient.GetIngresses(ctx, cluster, namespace)
 	if err != nil {
@@ -975,7 +1381,7 @@ func (s *Server) handleNetworkPoliciesHTTP(w http.ResponseWriter, r *http.Reques
 		json.NewEncoder(w).Encode(map[string]interface{}{"networkpolicies": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	policies, err := s.k8sClient.GetNetworkPolicies(ctx, cluster, namespace)
 	if err != nil {
@@ -1004,7 +1410,7 @@ func (s *Server) handleServicesHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"services": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	services, err := s.k8sClient.GetServices(ctx, cluster, namespace)
 	if err != nil {
@@ -1033,7 +1439,7 @@ func (s *Server) handleConfigMapsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"configmaps": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	configmaps, err := s.k8sClient.GetConfigMaps(ctx, cluster, namespace)
 	if err != nil {
@@ -1069,7 +1475,7 @@ func (s *Server) handleSecretsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"secrets": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	secrets, err := s.k8sClient.GetSecrets(ctx, cluster, namespace)
 	if err != nil {
@@ -1098,7 +1504,7 @@ func (s *Server) handleServiceAccountsHTTP(w http.ResponseWriter, r *http.Reques
 		json.NewEncoder(w).Encode(map[string]interface{}{"serviceaccounts": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	serviceaccounts, err := s.k8sClient.GetServiceAccounts(ctx, cluster, namespace)
 	if err != nil {
@@ -1127,7 +1533,7 @@ func (s *Server) handleJobsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"jobs": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	jobs, err := s.k8sClient.GetJobs(ctx, cluster, namespace)
 	if err != nil {
@@ -1138,6 +1544,60 @@ func (s *Server) handleJobsHTTP(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{"jobs": jobs, "source": "agent"})
 }
 
+// handleJobLogsHTTP returns the interleaved logs of every pod of a Job, labeled
+// by pod and completion index, with the failures found in them
+func (s *Server) handleJobLogsHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.k8sClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "k8s client not initialized"})
+		return
+	}
+	q := r.URL.Query()
+	cluster := q.Get("cluster")
+	namespace := q.Get("namespace")
+	name := q.Get("job")
+	if cluster == "" || namespace == "" || name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "cluster, namespace and job parameters required"})
+		return
+	}
+	var tail int64
+	if t := q.Get("tail"); t != "" {
+		n, err := strconv.ParseInt(t, 10, 64)
+		if err != nil || n <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": "tail must be a positive integer"})
+			return
+		}
+		tail = n
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+	logs, err := s.k8sClient.GetJobLogs(ctx, cluster, namespace, name, q.Get("container"), tail)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": "job not found"})
+			return
+		}
+		log.Printf("error fetching job logs: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "internal server error"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"logs": logs, "source": "agent"})
+}
+
 // handleHPAsHTTP returns HPAs for a cluster/namespace
 func (s *Server) handleHPAsHTTP(w http.ResponseWriter, r *http.Request) {
 	s.setCORSHeaders(w, r)
@@ -1156,7 +1616,7 @@ func (s *Server) handleHPAsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"hpas": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	hpas, err := s.k8sClient.GetHPAs(ctx, cluster, namespace)
 	if err != nil {
@@ -1167,6 +1627,43 @@ func (s *Server) handleHPAsHTTP(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{"hpas": hpas, "source": "agent"})
 }
 
+// handleHPADetailHTTP returns one HPA with all metric targets, conditions, its
+// scaling event timeline and flapping detection
+func (s *Server) handleHPADetailHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "k8s client not initialized"})
+		return
+	}
+	cluster := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	if cluster == "" || namespace == "" || name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "cluster, namespace and name parameters required"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+	detail, err := s.k8sClient.GetHPADetail(ctx, cluster, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": "hpa not found"})
+			return
+		}
+		log.Printf("error fetching hpa detail: %v", err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "internal server error"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"hpa": detail, "source": "agent"})
+}
+
 // handlePVCsHTTP returns PVCs for a cluster/namespace
 func (s *Server) handlePVCsHTTP(w http.ResponseWriter, r *http.Request) {
 	s.setCORSHeaders(w, r)
@@ -1185,7 +1682,7 @@ func (s *Server) handlePVCsHTTP(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]interface{}{"pvcs": []interface{}{}, "error": "cluster parameter required"})
 		return
 	}
-	ctx, cancel := context.WithTimeout(r.Context(), agentDefaultTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
 	defer cancel()
 	pvcs, err := s.k8sClient.GetPVCs(ctx, cluster, namespace)
 	if err != nil {
@@ -1223,9 +1720,13 @@ func (s *Server) handlePodsHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), agentCommandTimeout)
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentCommandTimeout))
 	defer cancel()
 
+	if wantsNDJSON(r) {
+		s.streamPods(ctx, w, cluster, namespace)
+		return
+	}
 	pods, err := s.k8sClient.GetPods(ctx, cluster, namespace)
 	if err != nil {
 		log.Printf("error fetching pods: %v", err)
@@ -1265,7 +1766,7 @@ func (s *Server) handleClusterHealthHTTP(w http.ResponseWriter, r *http.Request)
 	// Use background context instead of request context so the health check
 	// continues even if the frontend disconnects. Results are cached, so
 	// completing the check benefits subsequent requests.
-	ctx, cancel := context.WithTimeout(context.Background(), agentExtendedTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout(r, cluster, k8s.OpHealth, agentExtendedTimeout))
 	defer cancel()
 
 	health, err := s.k8sClient.GetClusterHealth(ctx, cluster)
@@ -1286,10 +1787,14 @@ func (s *Server) setCORSHeaders(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Header().Set("Access-Control-Allow-Private-Network", "true")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join([]string{
+		"Authorization", "Content-Type", "If-None-Match", "Cache-Control",
+		ImpersonateUserHeader, ImpersonateGroupHeader, ImpersonateServiceAccountHeader,
+	}, ", "))
+	w.Header().Set("Access-Control-Expose-Headers", RetryCountHeader+", ETag, X-Cache")
 }
 
-// handleRestartBackend kills the existing backend on port 8080 and starts a new one
+// handleRestartBackend kills the existing backend on its port and starts a new one
 func (s *Server) handleRestartBackend(w http.ResponseWriter, r *http.Request) {
 	origin := r.Header.Get("Origin")
 	if s.isAllowedOrigin(origin) {
@@ -1343,47 +1848,62 @@ func (s *Server) handleRestartBackend(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// killBackendProcess finds and kills the process listening on port 8080
+// killBackendProcess kills the tracked backend, or whatever listens on the
+// backend port when the backend was started outside the agent
 func (s *Server) killBackendProcess() bool {
+	port := s.backend.port()
+
 	// If we have a tracked process, kill it
 	if s.backendCmd != nil && s.backendCmd.Process != nil {
-		s.backendCmd.Process.Kill()
+		if err := killProcessTree(s.backendCmd.Process.Pid); err != nil {
+			s.backendCmd.Process.Kill()
+		}
 		s.backendCmd.Wait()
 		s.backendCmd = nil
+		waitPortReleased(port, portReleaseTimeout)
 		return true
 	}
 
-	// Fallback: find only the LISTEN process on port 8080 (not connected clients)
-	// Using -sTCP:LISTEN ensures we only kill the server, not browsers/proxies
-	out, err := exec.Command("lsof", "-ti", ":8080", "-sTCP:LISTEN").Output()
-	if err != nil || len(strings.TrimSpace(string(out))) == 0 {
+	if !portListening(port) {
 		return false
 	}
-
-	for _, pidStr := range strings.Fields(strings.TrimSpace(string(out))) {
-		pid, err := strconv.Atoi(pidStr)
-		if err != nil {
+	// Only the LISTEN process, never connected clients (browsers, proxies)
+	pids, err := listeningPIDs(port)
+	if err != nil {
+		log.Printf("[RestartBackend] Cannot find the process on port %d: %v", port, err)
+		return false
+	}
+	killed := false
+	for _, pid := range pids {
+		if pid == os.Getpid() {
 			continue
 		}
-		if proc, err := os.FindProcess(pid); err == nil {
-			proc.Kill()
+		if err := killProcessTree(pid); err != nil {
+			log.Printf("[RestartBackend] Failed to kill pid %d: %v", pid, err)
+			continue
 		}
+		killed = true
 	}
-
-	time.Sleep(startupDelay)
-	return true
+	if killed && !waitPortReleased(port, portReleaseTimeout) {
+		log.Printf("[RestartBackend] Port %d still in use after %s", port, portReleaseTimeout)
+	}
+	return killed
 }
 
-// startBackendProcess starts the backend via `go run ./cmd/console`
+// startBackendProcess starts the configured backend, see AgentFileBackend
 func (s *Server) startBackendProcess() error {
-	cmd := exec.Command("go", "run", "./cmd/console")
-	cmd.Env = append(os.Environ(), "GOWORK=off")
+	cmd, err := s.backend.command()
+	if err != nil {
+		return err
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	setProcessGroup(cmd)
 
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start backend: %w", err)
 	}
+	log.Printf("[Backend] Started %s (pid %d)", strings.Join(cmd.Args, " "), cmd.Process.Pid)
 
 	s.backendCmd = cmd
 
@@ -1405,10 +1925,10 @@ func (s *Server) startBackendProcess() error {
 	return nil
 }
 
-// checkBackendHealth verifies the backend is responding on port 8080
+// checkBackendHealth verifies the backend is responding on its port
 func (s *Server) checkBackendHealth() bool {
 	client := &http.Client{Timeout: healthCheckTimeout}
-	resp, err := client.Get(defaultHealthCheckURL)
+	resp, err := client.Get(s.backend.healthURL())
 	if err != nil {
 		return false
 	}
@@ -1862,6 +2382,15 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	var writeMu sync.Mutex
 	// closed is set when the read loop exits; goroutines check it before writing
 	var closed atomic.Bool
+	// Chat and kubectl requests are cancelled by cancel messages and when the client disconnects
+	inflight := newInflightRequests()
+	connCtx, cancelConn := context.WithCancel(context.Background())
+	slots := make(chan struct{}, maxWSConcurrentRequests)
+	defer func() {
+		closed.Store(true)
+		cancelConn()
+		log.Printf("Client disconnected: %s", conn.RemoteAddr())
+	}()
 
 	for {
 		var msg protocol.Message
@@ -1869,37 +2398,64 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
-			break
+			return
 		}
 
-		// For chat messages, run in a goroutine so cancel messages can be received
-		if msg.Type == protocol.TypeChat || msg.Type == protocol.TypeClaude {
-			forceAgent := ""
-			if msg.Type == protocol.TypeClaude {
-				forceAgent = "claude"
-			}
-			go func(m protocol.Message, fa string) {
-				defer func() {
-					if r := recover(); r != nil {
-						log.Printf("[Chat] recovered from panic in streaming handler: %v", r)
-					}
-				}()
-				s.handleChatMessageStreaming(conn, m, fa, &writeMu, &closed)
-			}(msg, forceAgent)
-		} else if msg.Type == protocol.TypeCancelChat {
+		isChat := msg.Type == protocol.TypeChat || msg.Type == protocol.TypeClaude
+		switch {
+		case isChat && !s.features.Enabled(FeatureAIChat):
+			writeMu.Lock()
+			conn.WriteJSON(s.errorResponse(msg.ID, "feature_disabled", "AI chat is disabled on this agent"))
+			writeMu.Unlock()
+		case msg.Type == protocol.TypeCancelChat:
 			// Cancel an in-progress chat by session ID
 			s.handleCancelChat(conn, msg, &writeMu)
-		} else if msg.Type == protocol.TypeKubectl {
-			// Handle kubectl messages concurrently so one slow cluster
-			// doesn't block the entire WebSocket message loop.
+		case msg.Type == protocol.TypeCancel:
+			// Cancel an in-flight request by message ID
+			s.handleCancel(conn, msg, inflight, &writeMu)
+		case msg.Type == protocol.TypeSelectAgent || msg.Type == protocol.TypeCommandApproval:
+			// Handled in order so a following chat sees the selected agent or decision
+			response := s.handleMessage(connCtx, msg)
+			writeMu.Lock()
+			err := conn.WriteJSON(response)
+			writeMu.Unlock()
+			if err != nil {
+				log.Printf("Write error: %v", err)
+				return
+			}
+		default:
+			// Everything else runs in its own goroutine so a long chat or slow
+			// cluster doesn't block the connection, up to a per-connection cap
+			select {
+			case slots <- struct{}{}:
+			default:
+				writeMu.Lock()
+				conn.WriteJSON(s.errorResponse(msg.ID, "too_many_requests",
+					fmt.Sprintf("at most %d concurrent requests per connection", maxWSConcurrentRequests)))
+				writeMu.Unlock()
+				continue
+			}
+			// Registered before the goroutine starts so an immediate cancel finds it
+			ctx, done := inflight.start(connCtx, msg.ID)
 			go func(m protocol.Message) {
+				defer func() { <-slots }()
+				defer done()
 				defer func() {
 					if r := recover(); r != nil {
-						log.Printf("[Kubectl] recovered from panic in message handler: %v", r)
+						log.Printf("[WebSocket] recovered from panic handling %s message: %v", m.Type, r)
 					}
 				}()
-				response := s.handleMessage(m)
-				if closed.Load() {
+				if m.Type == protocol.TypeChat || m.Type == protocol.TypeClaude {
+					forceAgent := ""
+					if m.Type == protocol.TypeClaude {
+						forceAgent = "claude"
+					}
+					s.handleChatMessageStreaming(ctx, conn, m, forceAgent, &writeMu, &closed)
+					return
+				}
+				response := s.handleMessage(ctx, m)
+				// A cancelled request was already answered by the cancel result
+				if closed.Load() || ctx.Err() != nil {
 					return
 				}
 				writeMu.Lock()
@@ -1908,36 +2464,26 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 					log.Printf("Write error: %v", err)
 				}
 			}(msg)
-		} else {
-			response := s.handleMessage(msg)
-			writeMu.Lock()
-			err := conn.WriteJSON(response)
-			writeMu.Unlock()
-			if err != nil {
-				log.Printf("Write error: %v", err)
-				break
-			}
 		}
 	}
-	closed.Store(true)
-
-	log.Printf("Client disconnected: %s", conn.RemoteAddr())
 }
 
 // handleMessage processes incoming messages (non-streaming)
-func (s *Server) handleMessage(msg protocol.Message) protocol.Message {
+func (s *Server) handleMessage(ctx context.Context, msg protocol.Message) protocol.Message {
 	switch msg.Type {
 	case protocol.TypeHealth:
 		return s.handleHealthMessage(msg)
 	case protocol.TypeClusters:
 		return s.handleClustersMessage(msg)
 	case protocol.TypeKubectl:
-		return s.handleKubectlMessage(msg)
+		return s.handleKubectlMessage(ctx, msg)
 	// TypeChat and TypeClaude are handled by handleChatMessageStreaming in the WebSocket loop
 	case protocol.TypeListAgents:
 		return s.handleListAgentsMessage(msg)
 	case protocol.TypeSelectAgent:
 		return s.handleSelectAgentMessage(msg)
+	case protocol.TypeCommandApproval:
+		return s.handleCommandApprovalMessage(msg)
 	default:
 		return protocol.Message{
 			ID:   msg.ID,
@@ -1951,7 +2497,7 @@ func (s *Server) handleMessage(msg protocol.Message) protocol.Message {
 }
 
 func (s *Server) handleHealthMessage(msg protocol.Message) protocol.Message {
-	clusters, _ := s.kubectl.ListContexts()
+	clusters, _ := s.listContexts()
 	return protocol.Message{
 		ID:   msg.ID,
 		Type: protocol.TypeResult,
@@ -1961,12 +2507,13 @@ func (s *Server) handleHealthMessage(msg protocol.Message) protocol.Message {
 			Clusters:  len(clusters),
 			HasClaude: s.checkClaudeAvailable(),
 			Claude:    s.getClaudeInfo(),
+			Features:  s.features,
 		},
 	}
 }
 
 func (s *Server) handleClustersMessage(msg protocol.Message) protocol.Message {
-	clusters, current := s.kubectl.ListContexts()
+	clusters, current := s.listContexts()
 	return protocol.Message{
 		ID:   msg.ID,
 		Type: protocol.TypeResult,
@@ -1977,7 +2524,7 @@ func (s *Server) handleClustersMessage(msg protocol.Message) protocol.Message {
 	}
 }
 
-func (s *Server) handleKubectlMessage(msg protocol.Message) protocol.Message {
+func (s *Server) handleKubectlMessage(ctx context.Context, msg protocol.Message) protocol.Message {
 	// Parse payload
 	payloadBytes, err := json.Marshal(msg.Payload)
 	if err != nil {
@@ -1990,7 +2537,7 @@ func (s *Server) handleKubectlMessage(msg protocol.Message) protocol.Message {
 	}
 
 	// Execute kubectl
-	result := s.kubectl.Execute(req.Context, req.Namespace, req.Args)
+	result := s.kubectl.ExecuteContext(ctx, req.Context, req.Namespace, req.Args)
 	return protocol.Message{
 		ID:      msg.ID,
 		Type:    protocol.TypeResult,
@@ -2001,7 +2548,8 @@ func (s *Server) handleKubectlMessage(msg protocol.Message) protocol.Message {
 // handleChatMessageStreaming handles chat messages with streaming support.
 // Runs in a goroutine so the WebSocket read loop stays free to receive cancel messages.
 // writeMu/closed are shared with the read loop for safe concurrent WebSocket writes.
-func (s *Server) handleChatMessageStreaming(conn *websocket.Conn, msg protocol.Message, forceAgent string, writeMu *sync.Mutex, closed *atomic.Bool) {
+// Cancelling parent (a cancel message for msg.ID, or the client disconnecting) stops the chat.
+func (s *Server) handleChatMessageStreaming(parent context.Context, conn *websocket.Conn, msg protocol.Message, forceAgent string, writeMu *sync.Mutex, closed *atomic.Bool) {
 	// safeWrite sends a WebSocket message only if the connection is still open and not cancelled
 	safeWrite := func(ctx context.Context, outMsg protocol.Message) {
 		if closed.Load() || ctx.Err() != nil {
@@ -2037,7 +2585,7 @@ func (s *Server) handleChatMessageStreaming(conn *websocket.Conn, msg protocol.M
 	}
 
 	// Create cancellable context — cancel_chat messages will call the cancel function
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(parent)
 	defer cancel()
 
 	// Register cancel function so handleCancelChat can stop this session
@@ -2076,7 +2624,11 @@ func (s *Server) handleChatMessageStreaming(conn *websocket.Conn, msg protocol.M
 		}
 	}
 
-	if needsTools && !s.isToolCapableAgent(agentName) {
+	// API providers with native function calling can answer tool-needing prompts
+	// directly using the built-in read-only Kubernetes tools
+	useKubeTools := needsTools && s.k8sClient != nil && s.supportsKubeTools(agentName)
+
+	if needsTools && !s.isToolCapableAgent(agentName) && !useKubeTools {
 		// Try mixed-mode: use thinking agent + CLI execution agent
 		if toolAgent := s.findToolCapableAgent(); toolAgent != "" {
 			log.Printf("[Chat] Mixed-mode: thinking=%s, execution=%s", agentName, toolAgent)
@@ -2108,6 +2660,11 @@ func (s *Server) handleChatMessageStreaming(conn *websocket.Conn, msg protocol.M
 		return
 	}
 
+	if err := s.checkTokenBudget(agentName); err != nil {
+		safeWrite(ctx, s.errorResponse(msg.ID, "budget_exceeded", err.Error()))
+		return
+	}
+
 	// Convert protocol history to provider history
 	var history []ChatMessage
 	for _, m := range req.History {
@@ -2134,7 +2691,43 @@ func (s *Server) handleChatMessageStreaming(conn *websocket.Conn, msg protocol.M
 
 	// Check if provider supports streaming with progress events
 	var resp *ChatResponse
-	if streamingProvider, ok := provider.(StreamingProvider); ok {
+	if toolProvider, ok := provider.(KubeToolProvider); ok && useKubeTools {
+		executor := NewKubeToolExecutor(s.k8sClient)
+		const maxToolOutputDisplayLen = 500
+		execute := func(toolCtx context.Context, call KubeToolCall) string {
+			safeWrite(ctx, protocol.Message{
+				ID:   msg.ID,
+				Type: protocol.TypeProgress,
+				Payload: protocol.ProgressPayload{
+					Step:  fmt.Sprintf("%s: running", call.Name),
+					Tool:  call.Name,
+					Input: call.Input,
+				},
+			})
+			output := executor.Execute(toolCtx, call)
+			safeWrite(ctx, protocol.Message{
+				ID:   msg.ID,
+				Type: protocol.TypeProgress,
+				Payload: protocol.ProgressPayload{
+					Step:   fmt.Sprintf("%s completed", call.Name),
+					Tool:   call.Name,
+					Output: truncateString(output, maxToolOutputDisplayLen),
+				},
+			})
+			return output
+		}
+
+		resp, err = toolProvider.ChatWithKubeTools(ctx, chatReq, kubeToolDefinitions, execute)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Printf("[Chat] Session %s cancelled", req.SessionID)
+				return
+			}
+			log.Printf("[Chat] tool execution error for %s: %v", agentName, err)
+			safeWrite(ctx, s.errorResponse(msg.ID, "execution_error", fmt.Sprintf("Failed to execute %s", agentName)))
+			return
+		}
+	} else if streamingProvider, ok := provider.(StreamingProvider); ok {
 		// Use streaming with progress callbacks
 		var streamedContent strings.Builder
 
@@ -2227,7 +2820,7 @@ func (s *Server) handleChatMessageStreaming(conn *websocket.Conn, msg protocol.M
 
 	// Track token usage
 	if resp.TokenUsage != nil {
-		s.addTokenUsage(resp.TokenUsage)
+		s.trackProviderTokens(agentName, req.SessionID, resp.TokenUsage)
 	}
 
 	var inputTokens, outputTokens, totalTokens int
@@ -2384,6 +2977,10 @@ func (s *Server) handleChatMessage(msg protocol.Message, forceAgent string) prot
 		return s.errorResponse(msg.ID, "agent_unavailable", fmt.Sprintf("Agent %s is not available - API key may be missing", agentName))
 	}
 
+	if err := s.checkTokenBudget(agentName); err != nil {
+		return s.errorResponse(msg.ID, "budget_exceeded", err.Error())
+	}
+
 	// Convert protocol history to provider history
 	var history []ChatMessage
 	for _, msg := range req.History {
@@ -2416,7 +3013,7 @@ func (s *Server) handleChatMessage(msg protocol.Message, forceAgent string) prot
 
 	// Track token usage
 	if resp.TokenUsage != nil {
-		s.addTokenUsage(resp.TokenUsage)
+		s.trackProviderTokens(agentName, req.SessionID, resp.TokenUsage)
 	}
 
 	var inputTokens, outputTokens, totalTokens int
@@ -2543,6 +3140,10 @@ func (s *Server) handleMixedModeChat(ctx context.Context, conn *websocket.Conn,
 		safeWrite(s.errorResponse(msg.ID, "agent_error", fmt.Sprintf("Execution agent %s not found", executionAgent)))
 		return
 	}
+	if err := s.checkTokenBudget(thinkingAgent); err != nil {
+		safeWrite(s.errorResponse(msg.ID, "budget_exceeded", err.Error()))
+		return
+	}
 
 	// Convert protocol history to provider history
 	var history []ChatMessage
@@ -2590,6 +3191,9 @@ User request: %s`, req.Prompt)
 		safeWrite(s.errorResponse(msg.ID, "mixed_mode_error", "Thinking agent returned empty response"))
 		return
 	}
+	if thinkingResp.TokenUsage != nil {
+		s.trackProviderTokens(thinkingAgent, sessionID, thinkingResp.TokenUsage)
+	}
 
 	// Stream the thinking response
 	safeWrite(protocol.Message{
@@ -2613,6 +3217,39 @@ User request: %s`, req.Prompt)
 		}
 	}
 
+	// Gate commands: read-only ones run automatically, everything else
+	// is sent to the client for approval and audited
+	if len(commands) > 0 && s.commandApprovals != nil {
+		allowed, rejected := s.gateCommands(ctx, "", sessionID, executionAgent, commands, func(req CommandApprovalRequest) {
+			safeWrite(protocol.Message{
+				ID:      msg.ID,
+				Type:    protocol.TypeCommandApprovalRequest,
+				Payload: req,
+			})
+		})
+		if ctx.Err() != nil {
+			log.Printf("[MixedMode] Session %s cancelled during command approval", sessionID)
+			return
+		}
+		if len(rejected) > 0 {
+			var sb strings.Builder
+			sb.WriteString("**⛔ Skipped commands (not approved):**\n")
+			for _, cc := range rejected {
+				sb.WriteString(fmt.Sprintf("- `%s` (%s: %s)\n", cc.Command, cc.Risk, cc.Reason))
+			}
+			safeWrite(protocol.Message{
+				ID:   msg.ID,
+				Type: "stream_chunk",
+				Payload: map[string]interface{}{
+					"content": sb.String() + "\n",
+					"agent":   thinkingAgent,
+					"phase":   "approval",
+				},
+			})
+		}
+		commands = allowed
+	}
+
 	if len(commands) == 0 {
 		// No commands to execute - just return thinking response
 		safeWrite(protocol.Message{
@@ -2713,6 +3350,9 @@ Command output:
 		}
 		log.Printf("[MixedMode] Analysis error: %v", err)
 	} else if analysisResp != nil {
+		if analysisResp.TokenUsage != nil {
+			s.trackProviderTokens(thinkingAgent, sessionID, analysisResp.TokenUsage)
+		}
 		safeWrite(protocol.Message{
 			ID:   msg.ID,
 			Type: "stream_chunk",
@@ -2771,6 +3411,19 @@ func (s *Server) isToolCapableAgent(agentName string) bool {
 	return provider.Capabilities().HasCapability(CapabilityToolExec)
 }
 
+// supportsKubeTools reports whether an agent can use the built-in Kubernetes tools
+func (s *Server) supportsKubeTools(agentName string) bool {
+	if s.registry == nil {
+		return false
+	}
+	provider, err := s.registry.Get(agentName)
+	if err != nil {
+		return false
+	}
+	_, ok := provider.(KubeToolProvider)
+	return ok && provider.IsAvailable()
+}
+
 // findToolCapableAgent finds an available agent with tool execution capabilities
 func (s *Server) findToolCapableAgent() string {
 	// Check all registered providers for tool execution capability
@@ -2920,7 +3573,7 @@ type KeyStatus struct {
 	Provider    string `json:"provider"`
 	DisplayName string `json:"displayName"`
 	Configured  bool   `json:"configured"`
-	Source      string `json:"source,omitempty"` // "env" or "config"
+	Source      string `json:"source,omitempty"` // "env", "keychain" or "config"
 	Valid       *bool  `json:"valid,omitempty"`  // nil = not tested, true/false = test result
 	Error       string `json:"error,omitempty"`
 }
@@ -2929,6 +3582,7 @@ type KeyStatus struct {
 type KeysStatusResponse struct {
 	Keys       []KeyStatus `json:"keys"`
 	ConfigPath string      `json:"configPath"`
+	KeyStorage string      `json:"keyStorage"` // file or keychain
 }
 
 // SetKeyRequest is the request body for POST /settings/keys
@@ -3234,11 +3888,7 @@ func (s *Server) handleGetKeysStatus(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if status.Configured {
-			if cm.IsFromEnv(p.name) {
-				status.Source = "env"
-			} else {
-				status.Source = "config"
-			}
+			status.Source = cm.KeySource(p.name)
 
 			// Test if the key is valid
 			valid, err := s.validateAPIKey(p.name)
@@ -3257,6 +3907,7 @@ func (s *Server) handleGetKeysStatus(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(KeysStatusResponse{
 		Keys:       keys,
 		ConfigPath: cm.GetConfigPath(),
+		KeyStorage: cm.KeyStorage(),
 	})
 }
 
@@ -3856,7 +4507,9 @@ func (s *Server) handleDeviceAlerts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	json.NewEncoder(w).Encode(s.deviceTracker.GetAlerts())
+	resp := s.deviceTracker.GetAlerts()
+	s.applyDeviceSilences(&resp)
+	json.NewEncoder(w).Encode(resp)
 }
 
 // handleDeviceAlertsClear clears a specific device alert
@@ -4092,8 +4745,10 @@ func (s *Server) handleLocalClusters(w http.ResponseWriter, r *http.Request) {
 	case "POST":
 		// Create a new cluster
 		var req struct {
-			Tool string `json:"tool"`
-			Name string `json:"name"`
+			Tool     string `json:"tool"`
+			Name     string `json:"name"`
+			Template string `json:"template,omitempty"`
+			LocalClusterOptions
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -4103,6 +4758,15 @@ func (s *Server) handleLocalClusters(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "tool and name are required", http.StatusBadRequest)
 			return
 		}
+		if err := req.LocalClusterOptions.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tmpl, ok := findLocalClusterTemplate(req.Template)
+		if req.Template != "" && !ok {
+			http.Error(w, "unknown template", http.StatusBadRequest)
+			return
+		}
 
 		// Create cluster in background and return immediately
 		go func() {
@@ -4111,7 +4775,13 @@ func (s *Server) handleLocalClusters(w http.ResponseWriter, r *http.Request) {
 					log.Printf("[LocalClusters] recovered from panic creating cluster %s: %v", req.Name, r)
 				}
 			}()
-			if err := s.localClusters.CreateCluster(req.Tool, req.Name); err != nil {
+			create := func() error { return s.localClusters.CreateCluster(req.Tool, req.Name, req.LocalClusterOptions) }
+			if req.Template != "" {
+				create = func() error {
+					return s.localClusters.CreateClusterFromTemplate(req.Tool, req.Name, tmpl, req.LocalClusterOptions)
+				}
+			}
+			if err := create(); err != nil {
 				log.Printf("[LocalClusters] Failed to create cluster %s with %s: %v", req.Name, req.Tool, err)
 				s.BroadcastToClients("local_cluster_progress", map[string]interface{}{
 					"tool":     req.Tool,
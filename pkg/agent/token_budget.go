@@ -0,0 +1,380 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+const (
+	tokenBudgetUsageFile   = "token_budget_usage.json"
+	tokenBudgetWarnPercent = 80.0
+	tokenBudgetFullPercent = 100.0
+	tokenBudgetAllKey      = "*" // budget key that applies to all providers combined
+	tokensPerMillion       = 1_000_000.0
+)
+
+// providerUsage holds token counts for the current day and month
+type providerUsage struct {
+	Date       string `json:"date"`
+	Month      string `json:"month"`
+	DailyIn    int64  `json:"dailyIn"`
+	DailyOut   int64  `json:"dailyOut"`
+	MonthlyIn  int64  `json:"monthlyIn"`
+	MonthlyOut int64  `json:"monthlyOut"`
+}
+
+// BudgetStatus describes usage against one budget limit
+type BudgetStatus struct {
+	Provider string  `json:"provider"`
+	Period   string  `json:"period"` // daily, monthly
+	Metric   string  `json:"metric"` // tokens, usd
+	Used     float64 `json:"used"`
+	Limit    float64 `json:"limit"`
+	Percent  float64 `json:"percent"`
+	Exceeded bool    `json:"exceeded"`
+	Action   string  `json:"action"`
+}
+
+// TokenBudgetTracker tracks per-provider usage and enforces the configured budgets
+type TokenBudgetTracker struct {
+	mu      sync.Mutex
+	usage   map[string]*providerUsage
+	warned  map[string]bool // budget key + period window → warning already sent
+	budgets func() map[string]settings.TokenBudget
+	notify  func(msgType string, payload interface{})
+	dataDir string
+	now     func() time.Time
+	saveMu  sync.Mutex     // serializes writes of the usage file
+	saves   sync.WaitGroup // background saves in flight
+}
+
+// NewTokenBudgetTracker creates a tracker. budgets is called on every check so
+// edits made through the settings API take effect immediately.
+func NewTokenBudgetTracker(dataDir string, budgets func() map[string]settings.TokenBudget, notify func(string, interface{})) *TokenBudgetTracker {
+	if dataDir == "" {
+		homeDir, _ := os.UserHomeDir()
+		dataDir = filepath.Join(homeDir, ".kc")
+	}
+	t := &TokenBudgetTracker{
+		usage:   make(map[string]*providerUsage),
+		warned:  make(map[string]bool),
+		budgets: budgets,
+		notify:  notify,
+		dataDir: dataDir,
+		now:     time.Now,
+	}
+	t.loadFromDisk()
+	return t
+}
+
+// settingsTokenBudgets reads the budgets from the persisted settings
+func settingsTokenBudgets() map[string]settings.TokenBudget {
+	all, err := settings.GetSettingsManager().GetAll()
+	if err != nil || all == nil {
+		return nil
+	}
+	return all.TokenBudgets
+}
+
+// Check returns an error if a rejecting budget for provider is already exhausted
+func (t *TokenBudgetTracker) Check(provider string) error {
+	for _, st := range t.Status(provider) {
+		if st.Exceeded && st.Action != settings.TokenBudgetActionWarn {
+			return fmt.Errorf("%s %s budget exceeded for %s (%.0f of %.0f)", st.Period, st.Metric, st.Provider, st.Used, st.Limit)
+		}
+	}
+	return nil
+}
+
+// Record adds usage for a provider and broadcasts budget_warning events the
+// first time a budget crosses 80% and again when it is exceeded
+func (t *TokenBudgetTracker) Record(provider string, usage *ProviderTokenUsage) {
+	if usage == nil || (usage.InputTokens == 0 && usage.OutputTokens == 0) {
+		return
+	}
+
+	t.mu.Lock()
+	for _, key := range []string{provider, tokenBudgetAllKey} {
+		u := t.rolloverLocked(key)
+		u.DailyIn += int64(usage.InputTokens)
+		u.DailyOut += int64(usage.OutputTokens)
+		u.MonthlyIn += int64(usage.InputTokens)
+		u.MonthlyOut += int64(usage.OutputTokens)
+	}
+	t.mu.Unlock()
+
+	t.saves.Add(1)
+	go func() {
+		defer t.saves.Done()
+		t.saveToDisk()
+	}()
+
+	var warnings []BudgetStatus
+	statuses := t.Status(provider)
+	t.mu.Lock()
+	for _, st := range statuses {
+		if st.Percent < tokenBudgetWarnPercent {
+			continue
+		}
+		level := "warn"
+		if st.Exceeded {
+			level = "exceeded"
+		}
+		key := fmt.Sprintf("%s|%s|%s|%s|%s", st.Provider, st.Period, st.Metric, t.periodWindow(st.Period), level)
+		if t.warned[key] {
+			continue
+		}
+		t.warned[key] = true
+		warnings = append(warnings, st)
+	}
+	t.mu.Unlock()
+
+	for _, st := range warnings {
+		log.Printf("[TokenBudget] %s %s %s budget at %.0f%% (%.0f/%.0f)", st.Provider, st.Period, st.Metric, st.Percent, st.Used, st.Limit)
+		if t.notify != nil {
+			t.notify("budget_warning", st)
+		}
+	}
+}
+
+// Status returns usage against every budget that applies to provider.
+// An empty provider returns the status of all configured budgets.
+func (t *TokenBudgetTracker) Status(provider string) []BudgetStatus {
+	var budgets map[string]settings.TokenBudget
+	if t.budgets != nil {
+		budgets = t.budgets()
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(budgets))
+	for key := range budgets {
+		if provider == "" || key == provider || key == tokenBudgetAllKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var out []BudgetStatus
+	for _, key := range keys {
+		b := budgets[key]
+		u := t.rolloverLocked(key)
+		action := b.Action
+		if action == "" {
+			action = settings.TokenBudgetActionReject
+		}
+		add := func(period, metric string, used, limit float64) {
+			if limit <= 0 {
+				return
+			}
+			pct := used / limit * 100
+			out = append(out, BudgetStatus{
+				Provider: key,
+				Period:   period,
+				Metric:   metric,
+				Used:     used,
+				Limit:    limit,
+				Percent:  pct,
+				Exceeded: pct >= tokenBudgetFullPercent,
+				Action:   action,
+			})
+		}
+		add("daily", "tokens", float64(u.DailyIn+u.DailyOut), float64(b.DailyTokens))
+		add("monthly", "tokens", float64(u.MonthlyIn+u.MonthlyOut), float64(b.MonthlyTokens))
+		add("daily", "usd", budgetCost(b, u.DailyIn, u.DailyOut), b.DailyUSD)
+		add("monthly", "usd", budgetCost(b, u.MonthlyIn, u.MonthlyOut), b.MonthlyUSD)
+	}
+	return out
+}
+
+// budgetCost converts token counts into dollars using the budget's prices
+func budgetCost(b settings.TokenBudget, in, out int64) float64 {
+	return float64(in)/tokensPerMillion*b.InputCostPerMTok + float64(out)/tokensPerMillion*b.OutputCostPerMTok
+}
+
+// rolloverLocked returns usage for key, resetting counters when the day or month changed
+func (t *TokenBudgetTracker) rolloverLocked(key string) *providerUsage {
+	now := t.now()
+	today := now.Format("2006-01-02")
+	month := now.Format("2006-01")
+
+	u, ok := t.usage[key]
+	if !ok {
+		u = &providerUsage{Date: today, Month: month}
+		t.usage[key] = u
+	}
+	if u.Date != today {
+		u.Date = today
+		u.DailyIn, u.DailyOut = 0, 0
+	}
+	if u.Month != month {
+		u.Month = month
+		u.MonthlyIn, u.MonthlyOut = 0, 0
+	}
+	return u
+}
+
+func (t *TokenBudgetTracker) periodWindow(period string) string {
+	if period == "monthly" {
+		return t.now().Format("2006-01")
+	}
+	return t.now().Format("2006-01-02")
+}
+
+// saveToDisk writes the usage file. Saves run in the background after every Record,
+// so they are serialized and written via rename to never leave a partial file.
+func (t *TokenBudgetTracker) saveToDisk() {
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+
+	t.mu.Lock()
+	data, err := json.Marshal(t.usage)
+	t.mu.Unlock()
+	if err != nil {
+		log.Printf("[TokenBudget] Error marshaling usage: %v", err)
+		return
+	}
+	if err := os.MkdirAll(t.dataDir, metricsDirMode); err != nil {
+		log.Printf("[TokenBudget] Error creating data dir: %v", err)
+		return
+	}
+	path := filepath.Join(t.dataDir, tokenBudgetUsageFile)
+	if err := os.WriteFile(path+".tmp", data, agentFileMode); err != nil {
+		log.Printf("[TokenBudget] Error writing usage file: %v", err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		log.Printf("[TokenBudget] Error writing usage file: %v", err)
+	}
+}
+
+func (t *TokenBudgetTracker) loadFromDisk() {
+	data, err := os.ReadFile(filepath.Join(t.dataDir, tokenBudgetUsageFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[TokenBudget] Error reading usage file: %v", err)
+		}
+		return
+	}
+	var usage map[string]*providerUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		log.Printf("[TokenBudget] Error parsing usage file: %v", err)
+		return
+	}
+	t.mu.Lock()
+	for k, v := range usage {
+		if v != nil {
+			t.usage[k] = v
+		}
+	}
+	t.mu.Unlock()
+}
+
+// checkTokenBudget returns an error if provider may not be used right now
+func (s *Server) checkTokenBudget(provider string) error {
+	if s.tokenBudget == nil {
+		return nil
+	}
+	return s.tokenBudget.Check(provider)
+}
+
+// trackProviderTokens records usage in the session counters, provider budgets
+// and usage ledger
+func (s *Server) trackProviderTokens(provider, sessionID string, usage *ProviderTokenUsage) {
+	s.addTokenUsage(usage)
+	if s.tokenBudget != nil {
+		s.tokenBudget.Record(provider, usage)
+	}
+	s.tokenLedger.Record(provider, GetConfigManager().GetModel(provider, ""), sessionID, usage)
+}
+
+// recordPredictionTokens records background prediction usage; the prediction
+// worker already adds it to the session counters
+func (s *Server) recordPredictionTokens(provider string, usage *ProviderTokenUsage) {
+	s.tokenBudget.Record(provider, usage)
+	s.tokenLedger.Record(provider, GetConfigManager().GetModel(provider, ""), predictionSessionID, usage)
+}
+
+// handleTokenBudget returns or updates per-provider token budgets
+func (s *Server) handleTokenBudget(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		var status []BudgetStatus
+		if s.tokenBudget != nil {
+			status = s.tokenBudget.Status("")
+		}
+		if status == nil {
+			status = []BudgetStatus{}
+		}
+		budgets := settingsTokenBudgets()
+		if budgets == nil {
+			budgets = map[string]settings.TokenBudget{}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"budgets": budgets,
+			"status":  status,
+		})
+
+	case "PUT":
+		var budgets map[string]settings.TokenBudget
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&budgets); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+			return
+		}
+		for name, b := range budgets {
+			if b.Action != "" && b.Action != settings.TokenBudgetActionReject && b.Action != settings.TokenBudgetActionWarn {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("invalid action for %s", name)})
+				return
+			}
+		}
+		if budgets == nil {
+			budgets = map[string]settings.TokenBudget{}
+		}
+
+		mgr := settings.GetSettingsManager()
+		all, err := mgr.GetAll()
+		if err == nil {
+			all.TokenBudgets = budgets
+			err = mgr.SaveAll(all)
+		}
+		if err != nil {
+			log.Printf("[TokenBudget] failed to save budgets: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to save budgets"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/settings"
+)
+
+type budgetEvents struct {
+	mu     sync.Mutex
+	events []BudgetStatus
+}
+
+func (b *budgetEvents) notify(msgType string, payload interface{}) {
+	if msgType != "budget_warning" {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, payload.(BudgetStatus))
+}
+
+func TestTokenBudgetTracker_WarnAndReject(t *testing.T) {
+	budgets := map[string]settings.TokenBudget{
+		"claude": {DailyTokens: 1000},
+	}
+	events := &budgetEvents{}
+	tr := NewTokenBudgetTracker(t.TempDir(), func() map[string]settings.TokenBudget { return budgets }, events.notify)
+	t.Cleanup(tr.saves.Wait)
+
+	tr.Record("claude", &ProviderTokenUsage{InputTokens: 500, OutputTokens: 200})
+	if err := tr.Check("claude"); err != nil {
+		t.Fatalf("Expected claude to be within budget: %v", err)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("Expected no warnings at 70%%, got %d", len(events.events))
+	}
+
+	tr.Record("claude", &ProviderTokenUsage{InputTokens: 100})
+	if len(events.events) != 1 || events.events[0].Exceeded {
+		t.Fatalf("Expected one 80%% warning, got %+v", events.events)
+	}
+
+	// Crossing 80% again in the same day should not re-warn
+	tr.Record("claude", &ProviderTokenUsage{InputTokens: 50})
+	if len(events.events) != 1 {
+		t.Fatalf("Expected warning to be sent once, got %d", len(events.events))
+	}
+
+	tr.Record("claude", &ProviderTokenUsage{OutputTokens: 200})
+	if len(events.events) != 2 || !events.events[1].Exceeded {
+		t.Fatalf("Expected exceeded event, got %+v", events.events)
+	}
+	if err := tr.Check("claude"); err == nil {
+		t.Error("Expected claude to be rejected after exceeding its budget")
+	}
+	if err := tr.Check("openai"); err != nil {
+		t.Errorf("Expected openai to be unaffected: %v", err)
+	}
+}
+
+func TestTokenBudgetTracker_WarnActionAndCost(t *testing.T) {
+	budgets := map[string]settings.TokenBudget{
+		"*": {
+			DailyUSD:          1.0,
+			InputCostPerMTok:  3.0,
+			OutputCostPerMTok: 15.0,
+			Action:            settings.TokenBudgetActionWarn,
+		},
+	}
+	tr := NewTokenBudgetTracker(t.TempDir(), func() map[string]settings.TokenBudget { return budgets }, nil)
+	t.Cleanup(tr.saves.Wait)
+
+	// 100k in ($0.30) + 50k out ($0.75) = $1.05
+	tr.Record("gemini", &ProviderTokenUsage{InputTokens: 100000, OutputTokens: 50000})
+
+	status := tr.Status("gemini")
+	if len(status) != 1 || status[0].Metric != "usd" || !status[0].Exceeded {
+		t.Fatalf("Expected exceeded usd status for global budget, got %+v", status)
+	}
+	if err := tr.Check("gemini"); err != nil {
+		t.Errorf("Expected warn action not to reject: %v", err)
+	}
+}
+
+func TestTokenBudgetTracker_Rollover(t *testing.T) {
+	budgets := map[string]settings.TokenBudget{
+		"claude": {DailyTokens: 100, MonthlyTokens: 1000},
+	}
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	tr := NewTokenBudgetTracker(dir, func() map[string]settings.TokenBudget { return budgets }, nil)
+	t.Cleanup(tr.saves.Wait)
+	tr.now = func() time.Time { return now }
+
+	tr.Record("claude", &ProviderTokenUsage{InputTokens: 150})
+	if tr.Check("claude") == nil {
+		t.Fatal("Expected daily budget to be exceeded")
+	}
+
+	now = now.Add(2 * time.Hour) // next day, next month
+	if err := tr.Check("claude"); err != nil {
+		t.Errorf("Expected budgets to reset on rollover: %v", err)
+	}
+	for _, st := range tr.Status("claude") {
+		if st.Used != 0 {
+			t.Errorf("Expected %s usage to reset, got %.0f", st.Period, st.Used)
+		}
+	}
+
+	// Usage is persisted and reloaded
+	tr.Record("claude", &ProviderTokenUsage{InputTokens: 10})
+	tr.saveToDisk()
+	tr2 := NewTokenBudgetTracker(dir, func() map[string]settings.TokenBudget { return budgets }, nil)
+	t.Cleanup(tr2.saves.Wait)
+	tr2.now = func() time.Time { return now }
+	if st := tr2.Status("claude"); len(st) == 0 || st[0].Used != 10 {
+		t.Errorf("Expected persisted usage of 10, got %+v", st)
+	}
+}
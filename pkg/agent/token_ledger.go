@@ -0,0 +1,350 @@
+package agent
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	tokenLedgerFile = "token-ledger.json"
+	// tokenLedgerRetentionDays keeps a little over a year for chargeback
+	tokenLedgerRetentionDays = 400
+	// defaultUsageRangeDays is the range of /usage queries without from
+	defaultUsageRangeDays = 30
+	ledgerDateFormat      = "2006-01-02"
+	// predictionSessionID groups background prediction requests in the ledger
+	predictionSessionID = "predictions"
+)
+
+// Dimensions /usage can group by
+const (
+	usageGroupProvider = "provider"
+	usageGroupModel    = "model"
+	usageGroupSession  = "session"
+	usageGroupDay      = "day"
+)
+
+// TokenLedgerEntry is the token usage of one provider, model and session on one day
+type TokenLedgerEntry struct {
+	Date         string `json:"date,omitempty"` // YYYY-MM-DD, local time
+	Provider     string `json:"provider,omitempty"`
+	Model        string `json:"model,omitempty"`
+	SessionID    string `json:"sessionId,omitempty"`
+	Requests     int64  `json:"requests"`
+	InputTokens  int64  `json:"inputTokens"`
+	OutputTokens int64  `json:"outputTokens"`
+	TotalTokens  int64  `json:"totalTokens"`
+}
+
+type ledgerKey struct {
+	date, provider, model, session string
+}
+
+// TokenLedger keeps daily token usage per provider, model and session in
+// ~/.kc/token-ledger.json
+type TokenLedger struct {
+	mu      sync.Mutex
+	entries map[ledgerKey]*TokenLedgerEntry
+	dataDir string
+	now     func() time.Time
+	saveMu  sync.Mutex     // serializes writes of the ledger file
+	saves   sync.WaitGroup // background saves in flight
+}
+
+// NewTokenLedger loads the ledger kept in dataDir (default ~/.kc)
+func NewTokenLedger(dataDir string) *TokenLedger {
+	if dataDir == "" {
+		homeDir, _ := os.UserHomeDir()
+		dataDir = filepath.Join(homeDir, configDirName)
+	}
+	l := &TokenLedger{
+		entries: make(map[ledgerKey]*TokenLedgerEntry),
+		dataDir: dataDir,
+		now:     time.Now,
+	}
+	l.loadFromDisk()
+	return l
+}
+
+// Record adds one request's usage to the ledger
+func (l *TokenLedger) Record(provider, model, sessionID string, usage *ProviderTokenUsage) {
+	if l == nil || usage == nil || (usage.InputTokens == 0 && usage.OutputTokens == 0) {
+		return
+	}
+	total := usage.TotalTokens
+	if total == 0 {
+		total = usage.InputTokens + usage.OutputTokens
+	}
+
+	now := l.now()
+	key := ledgerKey{now.Format(ledgerDateFormat), provider, model, sessionID}
+	l.mu.Lock()
+	e, ok := l.entries[key]
+	if !ok {
+		e = &TokenLedgerEntry{Date: key.date, Provider: provider, Model: model, SessionID: sessionID}
+		l.entries[key] = e
+	}
+	e.Requests++
+	e.InputTokens += int64(usage.InputTokens)
+	e.OutputTokens += int64(usage.OutputTokens)
+	e.TotalTokens += int64(total)
+	l.pruneLocked(now)
+	l.mu.Unlock()
+
+	l.saves.Add(1)
+	go func() {
+		defer l.saves.Done()
+		l.saveToDisk()
+	}()
+}
+
+// pruneLocked drops days older than the retention window
+func (l *TokenLedger) pruneLocked(now time.Time) {
+	cutoff := now.AddDate(0, 0, -tokenLedgerRetentionDays).Format(ledgerDateFormat)
+	for key := range l.entries {
+		if key.date < cutoff {
+			delete(l.entries, key)
+		}
+	}
+}
+
+// Entries returns the ledger rows dated from..to inclusive (YYYY-MM-DD),
+// ordered by date, provider, model and session
+func (l *TokenLedger) Entries(from, to string) []TokenLedgerEntry {
+	out := []TokenLedgerEntry{}
+	if l == nil {
+		return out
+	}
+	l.mu.Lock()
+	for key, e := range l.entries {
+		if key.date >= from && key.date <= to {
+			out = append(out, *e)
+		}
+	}
+	l.mu.Unlock()
+	sortLedgerEntries(out)
+	return out
+}
+
+// summarizeUsage adds up entries by one dimension (provider, model, session or day)
+func summarizeUsage(entries []TokenLedgerEntry, groupBy string) ([]TokenLedgerEntry, TokenLedgerEntry) {
+	var totals TokenLedgerEntry
+	groups := map[TokenLedgerEntry]*TokenLedgerEntry{}
+	for _, e := range entries {
+		var k TokenLedgerEntry
+		switch groupBy {
+		case usageGroupModel:
+			k.Provider, k.Model = e.Provider, e.Model
+		case usageGroupSession:
+			k.SessionID = e.SessionID
+		case usageGroupDay:
+			k.Date = e.Date
+		default:
+			k.Provider = e.Provider
+		}
+		g, ok := groups[k]
+		if !ok {
+			g = &TokenLedgerEntry{Date: k.Date, Provider: k.Provider, Model: k.Model, SessionID: k.SessionID}
+			groups[k] = g
+		}
+		for _, sum := range []*TokenLedgerEntry{g, &totals} {
+			sum.Requests += e.Requests
+			sum.InputTokens += e.InputTokens
+			sum.OutputTokens += e.OutputTokens
+			sum.TotalTokens += e.TotalTokens
+		}
+	}
+	rows := make([]TokenLedgerEntry, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, *g)
+	}
+	sortLedgerEntries(rows)
+	return rows, totals
+}
+
+func sortLedgerEntries(entries []TokenLedgerEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.SessionID < b.SessionID
+	})
+}
+
+// saveToDisk writes the ledger via rename so a crash never leaves a partial file
+func (l *TokenLedger) saveToDisk() {
+	l.saveMu.Lock()
+	defer l.saveMu.Unlock()
+
+	l.mu.Lock()
+	entries := make([]TokenLedgerEntry, 0, len(l.entries))
+	for _, e := range l.entries {
+		entries = append(entries, *e)
+	}
+	l.mu.Unlock()
+	sortLedgerEntries(entries)
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		log.Printf("[TokenLedger] Error marshaling ledger: %v", err)
+		return
+	}
+	if err := os.MkdirAll(l.dataDir, metricsDirMode); err != nil {
+		log.Printf("[TokenLedger] Error creating data dir: %v", err)
+		return
+	}
+	path := filepath.Join(l.dataDir, tokenLedgerFile)
+	if err := os.WriteFile(path+".tmp", data, agentFileMode); err != nil {
+		log.Printf("[TokenLedger] Error writing ledger: %v", err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		log.Printf("[TokenLedger] Error writing ledger: %v", err)
+	}
+}
+
+func (l *TokenLedger) loadFromDisk() {
+	data, err := os.ReadFile(filepath.Join(l.dataDir, tokenLedgerFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[TokenLedger] Error reading ledger: %v", err)
+		}
+		return
+	}
+	var entries []TokenLedgerEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Printf("[TokenLedger] Error parsing ledger: %v", err)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range entries {
+		e := entries[i]
+		l.entries[ledgerKey{e.Date, e.Provider, e.Model, e.SessionID}] = &e
+	}
+}
+
+// usageRange parses the from/to query parameters (YYYY-MM-DD, inclusive).
+// to defaults to today and from to 30 days before to.
+func usageRange(r *http.Request, now time.Time) (string, string, error) {
+	to := now.Format(ledgerDateFormat)
+	if v := r.URL.Query().Get("to"); v != "" {
+		if _, err := time.Parse(ledgerDateFormat, v); err != nil {
+			return "", "", fmt.Errorf("invalid to date %q (use YYYY-MM-DD)", v)
+		}
+		to = v
+	}
+	toDate, _ := time.Parse(ledgerDateFormat, to)
+	from := toDate.AddDate(0, 0, 1-defaultUsageRangeDays).Format(ledgerDateFormat)
+	if v := r.URL.Query().Get("from"); v != "" {
+		if _, err := time.Parse(ledgerDateFormat, v); err != nil {
+			return "", "", fmt.Errorf("invalid from date %q (use YYYY-MM-DD)", v)
+		}
+		from = v
+	}
+	if from > to {
+		return "", "", fmt.Errorf("from %s is after to %s", from, to)
+	}
+	return from, to, nil
+}
+
+// handleUsage returns token usage for a date range grouped by provider (default),
+// model, session or day
+func (s *Server) handleUsage(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, err := usageRange(r, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	groupBy := r.URL.Query().Get("groupBy")
+	switch groupBy {
+	case "":
+		groupBy = usageGroupProvider
+	case usageGroupProvider, usageGroupModel, usageGroupSession, usageGroupDay:
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "groupBy must be provider, model, session or day"})
+		return
+	}
+
+	rows, totals := summarizeUsage(s.tokenLedger.Entries(from, to), groupBy)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"from":    from,
+		"to":      to,
+		"groupBy": groupBy,
+		"rows":    rows,
+		"totals":  totals,
+	})
+}
+
+// handleUsageExport returns the ledger rows for a date range as CSV
+func (s *Server) handleUsageExport(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	from, to, err := usageRange(r, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"token-usage_%s_%s.csv\"", from, to))
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "provider", "model", "session_id", "requests", "input_tokens", "output_tokens", "total_tokens"}) //nolint:errcheck
+	for _, e := range s.tokenLedger.Entries(from, to) {
+		cw.Write([]string{ //nolint:errcheck
+			e.Date, e.Provider, e.Model, e.SessionID,
+			strconv.FormatInt(e.Requests, 10),
+			strconv.FormatInt(e.InputTokens, 10),
+			strconv.FormatInt(e.OutputTokens, 10),
+			strconv.FormatInt(e.TotalTokens, 10),
+		})
+	}
+	cw.Flush()
+}
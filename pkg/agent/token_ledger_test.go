@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenLedgerRecordAndSummarize(t *testing.T) {
+	dir := t.TempDir()
+	l := NewTokenLedger(dir)
+	day := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+	l.now = func() time.Time { return day }
+
+	l.Record("claude", "sonnet", "s1", &ProviderTokenUsage{InputTokens: 100, OutputTokens: 50, TotalTokens: 150})
+	l.Record("claude", "sonnet", "s1", &ProviderTokenUsage{InputTokens: 10, OutputTokens: 5})
+	l.Record("openai", "gpt-4o", "s2", &ProviderTokenUsage{InputTokens: 20, OutputTokens: 20})
+	l.Record("openai", "gpt-4o", "s2", &ProviderTokenUsage{})
+	day = day.AddDate(0, 0, 1)
+	l.Record("claude", "opus", "s3", &ProviderTokenUsage{InputTokens: 1, OutputTokens: 1})
+	l.saves.Wait()
+
+	entries := NewTokenLedger(dir).Entries("2025-03-10", "2025-03-10")
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 persisted rows on the first day, got %+v", entries)
+	}
+	if e := entries[0]; e.Provider != "claude" || e.Requests != 2 || e.InputTokens != 110 || e.TotalTokens != 165 {
+		t.Errorf("Unexpected claude row: %+v", e)
+	}
+
+	all := l.Entries("2025-03-01", "2025-03-31")
+	rows, totals := summarizeUsage(all, usageGroupProvider)
+	if len(rows) != 2 || rows[0].Provider != "claude" || rows[0].TotalTokens != 167 || rows[0].Model != "" {
+		t.Errorf("Unexpected provider rows: %+v", rows)
+	}
+	if totals.Requests != 4 || totals.TotalTokens != 207 {
+		t.Errorf("Unexpected totals: %+v", totals)
+	}
+	if rows, _ := summarizeUsage(all, usageGroupModel); len(rows) != 3 {
+		t.Errorf("Expected 3 provider/model rows, got %+v", rows)
+	}
+	if rows, _ := summarizeUsage(all, usageGroupDay); len(rows) != 2 || rows[1].Date != "2025-03-11" {
+		t.Errorf("Unexpected daily rows: %+v", rows)
+	}
+}
+
+func TestHandleUsage(t *testing.T) {
+	l := NewTokenLedger(t.TempDir())
+	t.Cleanup(l.saves.Wait)
+	l.Record("claude", "sonnet", "s1", &ProviderTokenUsage{InputTokens: 7, OutputTokens: 3})
+	s := &Server{tokenLedger: l, allowedOrigins: []string{"http://localhost"}}
+	today := time.Now().Format(ledgerDateFormat)
+
+	rec := httptest.NewRecorder()
+	s.handleUsage(rec, httptest.NewRequest("GET", "/usage?groupBy=session", nil))
+	var body struct {
+		From   string             `json:"from"`
+		To     string             `json:"to"`
+		Rows   []TokenLedgerEntry `json:"rows"`
+		Totals TokenLedgerEntry   `json:"totals"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.To != today || len(body.Rows) != 1 || body.Rows[0].SessionID != "s1" || body.Totals.TotalTokens != 10 {
+		t.Errorf("Unexpected usage response: %+v", body)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleUsage(rec, httptest.NewRequest("GET", "/usage?from=2025-02-01&to=2025-01-01", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a reversed range to be rejected, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleUsageExport(rec, httptest.NewRequest("GET", "/usage/export?from="+today, nil))
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1][1] != "claude" || records[1][2] != "sonnet" || records[1][7] != "10" {
+		t.Errorf("Unexpected CSV export: %v", records)
+	}
+	if rec.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("Unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+}
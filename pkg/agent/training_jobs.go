@@ -0,0 +1,404 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Training job statuses
+const (
+	trainingStatusPending    = "Pending"
+	trainingStatusRunning    = "Running"
+	trainingStatusRestarting = "Restarting"
+	trainingStatusSucceeded  = "Succeeded"
+	trainingStatusFailed     = "Failed"
+	trainingStatusSuspended  = "Suspended"
+)
+
+// kubeflowTrainingKind describes a Kubeflow training operator CRD
+type kubeflowTrainingKind struct {
+	kind         string
+	framework    string
+	gvr          schema.GroupVersionResource
+	replicaSpecs string // spec field holding the per-role replica specs
+}
+
+var kubeflowTrainingKinds = []kubeflowTrainingKind{
+	{"PyTorchJob", "pytorch", schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "pytorchjobs"}, "pytorchReplicaSpecs"},
+	{"TFJob", "tensorflow", schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "tfjobs"}, "tfReplicaSpecs"},
+	{"MPIJob", "mpi", schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "mpijobs"}, "mpiReplicaSpecs"},
+	{"XGBoostJob", "xgboost", schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "xgboostjobs"}, "xgbReplicaSpecs"},
+	{"PaddleJob", "paddle", schema.GroupVersionResource{Group: "kubeflow.org", Version: "v1", Resource: "paddlejobs"}, "paddleReplicaSpecs"},
+}
+
+var (
+	rayJobGVR     = schema.GroupVersionResource{Group: "ray.io", Version: "v1", Resource: "rayjobs"}
+	rayClusterGVR = schema.GroupVersionResource{Group: "ray.io", Version: "v1", Resource: "rayclusters"}
+)
+
+// TrainingJob is a distributed training job from any supported operator
+type TrainingJob struct {
+	Name           string `json:"name"`
+	Namespace      string `json:"namespace"`
+	Cluster        string `json:"cluster"`
+	Kind           string `json:"kind"`      // PyTorchJob, TFJob, MPIJob, RayJob, RayCluster, ...
+	Framework      string `json:"framework"` // pytorch, tensorflow, mpi, ray, ...
+	Status         string `json:"status"`
+	Message        string `json:"message,omitempty"`
+	Replicas       int64  `json:"replicas"`
+	ActiveReplicas int64  `json:"activeReplicas"`
+	FailedReplicas int64  `json:"failedReplicas"`
+	GPUsRequested  int64  `json:"gpusRequested"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	ElapsedSeconds int64  `json:"elapsedSeconds"`
+}
+
+// templateGPUs returns the GPUs requested by an unstructured pod template
+func templateGPUs(template map[string]interface{}) int64 {
+	if template == nil {
+		return 0
+	}
+	var tpl corev1.PodTemplateSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, &tpl); err != nil {
+		return 0
+	}
+	return podGPUs(tpl.Spec)
+}
+
+// setTrainingTimes fills start/completion time and elapsed seconds
+func setTrainingTimes(job *TrainingJob, start, end string, now time.Time) {
+	job.StartTime = start
+	job.CompletionTime = end
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return
+	}
+	endTime := now
+	if t, err := time.Parse(time.RFC3339, end); err == nil {
+		endTime = t
+	}
+	if endTime.After(startTime) {
+		job.ElapsedSeconds = int64(endTime.Sub(startTime).Seconds())
+	}
+}
+
+// kubeflowJobStatus derives status from the last true condition of a Kubeflow job
+func kubeflowJobStatus(obj map[string]interface{}) (string, string) {
+	if suspended, _, _ := unstructured.NestedBool(obj, "spec", "runPolicy", "suspend"); suspended {
+		return trainingStatusSuspended, ""
+	}
+	status, message := trainingStatusPending, ""
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if !ok || cm["status"] != "True" {
+			continue
+		}
+		switch cm["type"] {
+		case "Running":
+			status = trainingStatusRunning
+		case "Restarting":
+			status = trainingStatusRestarting
+		case "Succeeded":
+			status = trainingStatusSucceeded
+		case "Failed":
+			status = trainingStatusFailed
+		case "Suspended":
+			status = trainingStatusSuspended
+		default:
+			continue
+		}
+		message, _ = cm["message"].(string)
+	}
+	return status, message
+}
+
+// trainingJobFromKubeflow converts a Kubeflow training operator job
+func trainingJobFromKubeflow(cluster string, k kubeflowTrainingKind, item *unstructured.Unstructured, now time.Time) TrainingJob {
+	obj := item.Object
+	job := TrainingJob{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   cluster,
+		Kind:      k.kind,
+		Framework: k.framework,
+	}
+	job.Status, job.Message = kubeflowJobStatus(obj)
+
+	specs, _, _ := unstructured.NestedMap(obj, "spec", k.replicaSpecs)
+	for _, v := range specs {
+		spec, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		replicas := int64(1)
+		if r, found, _ := unstructured.NestedInt64(spec, "replicas"); found {
+			replicas = r
+		}
+		template, _, _ := unstructured.NestedMap(spec, "template")
+		job.Replicas += replicas
+		job.GPUsRequested += replicas * templateGPUs(template)
+	}
+
+	statuses, _, _ := unstructured.NestedMap(obj, "status", "replicaStatuses")
+	for _, v := range statuses {
+		rs, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		job.ActiveReplicas += nestedInt64(rs, "active")
+		job.FailedReplicas += nestedInt64(rs, "failed")
+	}
+
+	setTrainingTimes(&job, nestedString(obj, "status", "startTime"), nestedString(obj, "status", "completionTime"), now)
+	return job
+}
+
+// rayClusterShape returns total replicas and GPUs for a RayCluster spec (head + worker groups)
+func rayClusterShape(spec map[string]interface{}) (replicas, gpus int64) {
+	if head, found, _ := unstructured.NestedMap(spec, "headGroupSpec", "template"); found {
+		replicas++
+		gpus += templateGPUs(head)
+	}
+	groups, _, _ := unstructured.NestedSlice(spec, "workerGroupSpecs")
+	for _, g := range groups {
+		group, ok := g.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		n := int64(1)
+		if r, found, _ := unstructured.NestedInt64(group, "replicas"); found {
+			n = r
+		}
+		template, _, _ := unstructured.NestedMap(group, "template")
+		replicas += n
+		gpus += n * templateGPUs(template)
+	}
+	return replicas, gpus
+}
+
+// trainingJobFromRayJob converts a KubeRay RayJob
+func trainingJobFromRayJob(cluster string, item *unstructured.Unstructured, now time.Time) TrainingJob {
+	obj := item.Object
+	job := TrainingJob{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   cluster,
+		Kind:      "RayJob",
+		Framework: "ray",
+		Message:   nestedString(obj, "status", "message"),
+	}
+	if spec, found, _ := unstructured.NestedMap(obj, "spec", "rayClusterSpec"); found {
+		job.Replicas, job.GPUsRequested = rayClusterShape(spec)
+	}
+
+	switch strings.ToUpper(nestedString(obj, "status", "jobStatus")) {
+	case "RUNNING":
+		job.Status = trainingStatusRunning
+	case "SUCCEEDED":
+		job.Status = trainingStatusSucceeded
+	case "FAILED":
+		job.Status = trainingStatusFailed
+	case "STOPPED":
+		job.Status = trainingStatusFailed
+	default:
+		job.Status = trainingStatusPending
+	}
+	if nestedString(obj, "status", "jobDeploymentStatus") == "Suspended" {
+		job.Status = trainingStatusSuspended
+	}
+	if job.Status == trainingStatusRunning {
+		job.ActiveReplicas = job.Replicas
+	}
+	job.FailedReplicas = nestedInt64(obj, "status", "failed")
+
+	setTrainingTimes(&job, nestedString(obj, "status", "startTime"), nestedString(obj, "status", "endTime"), now)
+	return job
+}
+
+// trainingJobFromRayCluster converts a standalone KubeRay RayCluster
+func trainingJobFromRayCluster(cluster string, item *unstructured.Unstructured, now time.Time) TrainingJob {
+	obj := item.Object
+	job := TrainingJob{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   cluster,
+		Kind:      "RayCluster",
+		Framework: "ray",
+		Message:   nestedString(obj, "status", "reason"),
+	}
+	if spec, found, _ := unstructured.NestedMap(obj, "spec"); found {
+		job.Replicas, job.GPUsRequested = rayClusterShape(spec)
+	}
+
+	switch nestedString(obj, "status", "state") {
+	case "ready":
+		job.Status = trainingStatusRunning
+	case "suspended":
+		job.Status = trainingStatusSuspended
+	case "failed":
+		job.Status = trainingStatusFailed
+	default:
+		job.Status = trainingStatusPending
+	}
+	job.ActiveReplicas = nestedInt64(obj, "status", "readyWorkerReplicas")
+	if job.Status == trainingStatusRunning {
+		job.ActiveReplicas++ // head
+	}
+
+	setTrainingTimes(&job, item.GetCreationTimestamp().UTC().Format(time.RFC3339), "", now)
+	return job
+}
+
+// isRayJobCluster reports whether a RayCluster was created by a RayJob (reported through the job)
+func isRayJobCluster(item *unstructured.Unstructured) bool {
+	for _, ref := range item.GetOwnerReferences() {
+		if ref.Kind == "RayJob" {
+			return true
+		}
+	}
+	return false
+}
+
+// listTrainingJobs returns training jobs of every supported operator in a cluster.
+// Operators that aren't installed are skipped.
+func (s *Server) listTrainingJobs(ctx context.Context, cluster, namespace string) ([]TrainingJob, error) {
+	dyn, err := s.k8sClient.GetDynamicClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list := func(gvr schema.GroupVersionResource) []unstructured.Unstructured {
+		var l *unstructured.UnstructuredList
+		var err error
+		if namespace != "" {
+			l, err = dyn.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		} else {
+			l, err = dyn.Resource(gvr).List(ctx, metav1.ListOptions{})
+		}
+		if err != nil {
+			return nil
+		}
+		return l.Items
+	}
+
+	now := time.Now()
+	jobs := []TrainingJob{}
+	for _, k := range kubeflowTrainingKinds {
+		items := list(k.gvr)
+		for i := range items {
+			jobs = append(jobs, trainingJobFromKubeflow(cluster, k, &items[i], now))
+		}
+	}
+	rayJobs := list(rayJobGVR)
+	for i := range rayJobs {
+		jobs = append(jobs, trainingJobFromRayJob(cluster, &rayJobs[i], now))
+	}
+	rayClusters := list(rayClusterGVR)
+	for i := range rayClusters {
+		if !isRayJobCluster(&rayClusters[i]) {
+			jobs = append(jobs, trainingJobFromRayCluster(cluster, &rayClusters[i], now))
+		}
+	}
+	return jobs, nil
+}
+
+// handleTrainingJobs returns distributed training jobs (Kubeflow, Ray) for a cluster or all clusters
+func (s *Server) handleTrainingJobs(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"jobs": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+
+	cluster := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	status := r.URL.Query().Get("status")
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+
+	all := []TrainingJob{}
+
+	if cluster != "" {
+		jobs, err := s.listTrainingJobs(ctx, cluster, namespace)
+		if err != nil {
+			log.Printf("error fetching training jobs: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"jobs": []interface{}{}, "error": "internal server error"})
+			return
+		}
+		all = jobs
+	} else {
+		clusters, err := s.k8sClient.ListClusters(ctx)
+		if err != nil {
+			log.Printf("error fetching training jobs: %v", err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"jobs": []interface{}{}, "error": "internal server error"})
+			return
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+
+		for _, cl := range clusters {
+			wg.Add(1)
+			go func(clusterName string) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[TrainingJobs] recovered from panic for cluster %s: %v", clusterName, r)
+					}
+				}()
+				clusterCtx, clusterCancel := context.WithTimeout(ctx, s.requestTimeout(r, clusterName, k8s.OpList, agentDefaultTimeout))
+				defer clusterCancel()
+				jobs, err := s.listTrainingJobs(clusterCtx, clusterName, namespace)
+				if err == nil && len(jobs) > 0 {
+					mu.Lock()
+					all = append(all, jobs...)
+					mu.Unlock()
+				}
+			}(cl.Name)
+		}
+		wg.Wait()
+	}
+
+	if status != "" {
+		filtered := []TrainingJob{}
+		for _, j := range all {
+			if strings.EqualFold(j.Status, status) {
+				filtered = append(filtered, j)
+			}
+		}
+		all = filtered
+	}
+
+	// Most recently started first
+	sort.SliceStable(all, func(i, j int) bool { return all[i].StartTime > all[j].StartTime })
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"jobs": all, "source": "agent"})
+}
@@ -0,0 +1,155 @@
+package agent
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func gpuTemplate(gpus string) map[string]interface{} {
+	return map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":  "trainer",
+					"image": "pytorch/pytorch",
+					"resources": map[string]interface{}{
+						"limits": map[string]interface{}{"nvidia.com/gpu": gpus},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTrainingJobFromKubeflow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "bert", "namespace": "ml"},
+		"spec": map[string]interface{}{
+			"pytorchReplicaSpecs": map[string]interface{}{
+				"Master": map[string]interface{}{"replicas": int64(1), "template": gpuTemplate("1")},
+				"Worker": map[string]interface{}{"replicas": int64(3), "template": gpuTemplate("2")},
+			},
+		},
+		"status": map[string]interface{}{
+			"startTime": "2026-01-01T11:00:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Created", "status": "True"},
+				map[string]interface{}{"type": "Running", "status": "False"},
+				map[string]interface{}{"type": "Failed", "status": "True", "message": "Worker 2 exited with code 137"},
+			},
+			"replicaStatuses": map[string]interface{}{
+				"Master": map[string]interface{}{"active": int64(1)},
+				"Worker": map[string]interface{}{"active": int64(2), "failed": int64(1)},
+			},
+		},
+	}}
+
+	got := trainingJobFromKubeflow("c1", kubeflowTrainingKinds[0], job, now)
+	if got.Kind != "PyTorchJob" || got.Framework != "pytorch" {
+		t.Errorf("Unexpected kind/framework: %+v", got)
+	}
+	if got.Status != trainingStatusFailed || got.Message != "Worker 2 exited with code 137" {
+		t.Errorf("Expected failed status with message, got %q %q", got.Status, got.Message)
+	}
+	if got.Replicas != 4 || got.ActiveReplicas != 3 || got.FailedReplicas != 1 || got.GPUsRequested != 7 {
+		t.Errorf("Unexpected replica/GPU counts: %+v", got)
+	}
+	if got.ElapsedSeconds != 3600 {
+		t.Errorf("Expected 1h elapsed without a completion time, got %d", got.ElapsedSeconds)
+	}
+}
+
+func TestHandleTrainingJobs(t *testing.T) {
+	m, _ := k8s.NewMultiClusterClient("")
+
+	rayJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "ray.io/v1",
+		"kind":       "RayJob",
+		"metadata":   map[string]interface{}{"name": "tune", "namespace": "ml"},
+		"spec": map[string]interface{}{
+			"rayClusterSpec": map[string]interface{}{
+				"headGroupSpec": map[string]interface{}{"template": gpuTemplate("0")},
+				"workerGroupSpecs": []interface{}{
+					map[string]interface{}{"groupName": "gpu", "replicas": int64(2), "template": gpuTemplate("4")},
+				},
+			},
+		},
+		"status": map[string]interface{}{
+			"jobStatus":           "SUCCEEDED",
+			"startTime":           "2026-01-01T10:00:00Z",
+			"endTime":             "2026-01-01T10:30:00Z",
+			"jobDeploymentStatus": "Complete",
+		},
+	}}
+	// Created by the RayJob above; must not be reported twice
+	ownedCluster := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "ray.io/v1",
+		"kind":       "RayCluster",
+		"metadata": map[string]interface{}{
+			"name":            "tune-raycluster",
+			"namespace":       "ml",
+			"ownerReferences": []interface{}{map[string]interface{}{"apiVersion": "ray.io/v1", "kind": "RayJob", "name": "tune", "uid": "1"}},
+		},
+	}}
+	standalone := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "ray.io/v1",
+		"kind":       "RayCluster",
+		"metadata":   map[string]interface{}{"name": "notebook", "namespace": "ml", "creationTimestamp": metav1.Now().Format(time.RFC3339)},
+		"spec": map[string]interface{}{
+			"headGroupSpec": map[string]interface{}{"template": gpuTemplate("1")},
+		},
+		"status": map[string]interface{}{"state": "ready"},
+	}}
+
+	gvrs := map[schema.GroupVersionResource]string{
+		rayJobGVR:     "RayJobList",
+		rayClusterGVR: "RayClusterList",
+	}
+	for _, k := range kubeflowTrainingKinds {
+		gvrs[k.gvr] = k.kind + "List"
+	}
+	m.InjectDynamicClient("c1", fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrs, rayJob, ownedCluster, standalone))
+
+	s := &Server{k8sClient: m, allowedOrigins: []string{"*"}}
+	w := httptest.NewRecorder()
+	s.handleTrainingJobs(w, httptest.NewRequest("GET", "/training-jobs?cluster=c1", nil))
+
+	var resp struct {
+		Jobs []TrainingJob `json:"jobs"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Jobs) != 2 {
+		t.Fatalf("Expected RayJob and standalone RayCluster, got %+v", resp.Jobs)
+	}
+	got := map[string]TrainingJob{}
+	for _, j := range resp.Jobs {
+		got[j.Name] = j
+	}
+	if j := got["tune"]; j.Status != trainingStatusSucceeded || j.Replicas != 3 || j.GPUsRequested != 8 || j.ElapsedSeconds != 1800 {
+		t.Errorf("Unexpected RayJob: %+v", j)
+	}
+	if j := got["notebook"]; j.Kind != "RayCluster" || j.Status != trainingStatusRunning || j.ActiveReplicas != 1 || j.GPUsRequested != 1 {
+		t.Errorf("Unexpected RayCluster: %+v", j)
+	}
+
+	// Status filter
+	w = httptest.NewRecorder()
+	s.handleTrainingJobs(w, httptest.NewRequest("GET", "/training-jobs?cluster=c1&status=running", nil))
+	resp.Jobs = nil
+	json.NewDecoder(w.Body).Decode(&resp)
+	if len(resp.Jobs) != 1 || resp.Jobs[0].Name != "notebook" {
+		t.Errorf("Expected only the running RayCluster, got %+v", resp.Jobs)
+	}
+}
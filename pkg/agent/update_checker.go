@@ -14,7 +14,6 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
 )
 
@@ -52,11 +51,17 @@ type UpdateChecker struct {
 	broadcast       func(string, interface{})
 	restartBackend  func() error
 	killBackend     func() bool
+	backendBinary   string // configured console binary, "" to look it up
+	healthURL       string
 	lastUpdateTime  time.Time
 	lastUpdateError string
 	cancel          context.CancelFunc
 	updating        int32 // atomic: 1 = update in progress, 0 = idle
 
+	// history records each update attempt; attempt is the one in progress
+	history *UpdateHistory
+	attempt *UpdateHistoryEntry
+
 	// exitFunc terminates the process after spawning the restart script.
 	// Defaults to os.Exit. Overridden in tests to prevent the test runner from exiting.
 	exitFunc func(code int)
@@ -67,6 +72,10 @@ type UpdateCheckerConfig struct {
 	Broadcast      func(string, interface{})
 	RestartBackend func() error
 	KillBackend    func() bool
+	// BackendBinary is the configured console binary that binary updates
+	// replace; empty looks up console on PATH
+	BackendBinary    string
+	BackendHealthURL string
 }
 
 // UpdateProgressPayload is broadcast via WebSocket during updates.
@@ -110,7 +119,7 @@ func NewUpdateChecker(cfg UpdateCheckerConfig) *UpdateChecker {
 	repoPath := detectRepoPath()
 	currentSHA := detectCurrentSHA(repoPath)
 
-	return &UpdateChecker{
+	uc := &UpdateChecker{
 		channel:        "stable",
 		installMethod:  installMethod,
 		repoPath:       repoPath,
@@ -119,7 +128,13 @@ func NewUpdateChecker(cfg UpdateCheckerConfig) *UpdateChecker {
 		broadcast:      cfg.Broadcast,
 		restartBackend: cfg.RestartBackend,
 		killBackend:    cfg.KillBackend,
+		backendBinary:  cfg.BackendBinary,
+		healthURL:      cfg.BackendHealthURL,
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		uc.history = NewUpdateHistory(filepath.Join(homeDir, configDirName))
 	}
+	return uc
 }
 
 // Start begins the periodic update check loop. Call Stop() to cancel.
@@ -350,6 +365,7 @@ func (uc *UpdateChecker) executeDeveloperUpdate(newSHA string) {
 	start := time.Now()
 	total := devUpdateTotalSteps
 	log.Printf("[AutoUpdate] === Starting update: %s -> %s ===", short(previousSHA), short(newSHA))
+	uc.startAttempt("developer", short(previousSHA), short(newSHA))
 
 	// Step 1/7: Git pull
 	log.Printf("[AutoUpdate] Step 1/%d: git pull --rebase origin main", total)
@@ -522,6 +538,7 @@ func (uc *UpdateChecker) executeDeveloperUpdate(newSHA string) {
 	uc.mu.Unlock()
 
 	log.Printf("[AutoUpdate] === Build complete: %s -> %s (total: %s), restarting... ===", short(previousSHA), short(newSHA), time.Since(start))
+	uc.finishAttempt(UpdateSucceeded, "built, restarting via startup-oauth.sh")
 
 	// Step 7/7: Restart via startup-oauth.sh
 	log.Printf("[AutoUpdate] Step 7/%d: restart via startup-oauth.sh", total)
@@ -568,7 +585,7 @@ func (uc *UpdateChecker) restartViaStartupScript(repoPath string) {
 		cmd.Stdout = logFile
 		cmd.Stderr = logFile
 	}
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	setProcessGroup(cmd)
 
 	if err := cmd.Start(); err != nil {
 		log.Printf("[AutoUpdate] Failed to spawn startup-oauth.sh: %v", err)
@@ -614,7 +631,7 @@ func (uc *UpdateChecker) selfUpdateFallback(repoPath string) {
 	}
 
 	// Re-exec with the same args — replaces this process atomically
-	if err := syscall.Exec(currentBinary, os.Args, os.Environ()); err != nil {
+	if err := execSelf(currentBinary, os.Args, os.Environ()); err != nil {
 		log.Printf("[AutoUpdate] exec into new kc-agent failed: %v", err)
 	}
 	// If exec succeeds, this line is never reached
@@ -665,7 +682,16 @@ func (uc *UpdateChecker) checkReleaseChannel(channel string) {
 	}
 }
 
+// executeBinaryUpdate downloads a release archive, verifies it against the
+// release checksums (and signature, when a public key is configured), stages
+// the new binary next to the current one and rolls back if the restarted
+// backend fails its health check.
 func (uc *UpdateChecker) executeBinaryUpdate(release *githubReleaseInfo) {
+	uc.mu.Lock()
+	currentVersion := uc.currentVersion
+	uc.mu.Unlock()
+	uc.startAttempt("binary", currentVersion, release.TagName)
+
 	uc.broadcast("update_progress", UpdateProgressPayload{
 		Status:   "pulling",
 		Message:  fmt.Sprintf("Downloading %s...", release.TagName),
@@ -675,14 +701,7 @@ func (uc *UpdateChecker) executeBinaryUpdate(release *githubReleaseInfo) {
 	platform := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
 	assetName := fmt.Sprintf("console_%s_%s.tar.gz", strings.TrimPrefix(release.TagName, "v"), platform)
 
-	var assetURL string
-	for _, a := range release.Assets {
-		if a.Name == assetName {
-			assetURL = a.BrowserDownloadURL
-			break
-		}
-	}
-
+	assetURL := releaseAssetURL(release, assetName)
 	if assetURL == "" {
 		uc.recordError("no matching asset found for platform " + platform)
 		uc.broadcast("update_progress", UpdateProgressPayload{
@@ -693,8 +712,14 @@ func (uc *UpdateChecker) executeBinaryUpdate(release *githubReleaseInfo) {
 		return
 	}
 
-	// Download to temp file
-	tmpFile := fmt.Sprintf("/tmp/kc-update-%s.tar.gz", release.TagName)
+	workDir, err := os.MkdirTemp("", "kc-update-")
+	if err != nil {
+		uc.recordError(fmt.Sprintf("create staging dir failed: %v", err))
+		return
+	}
+	defer os.RemoveAll(workDir)
+
+	tmpFile := filepath.Join(workDir, assetName)
 	if err := downloadFile(assetURL, tmpFile); err != nil {
 		uc.recordError(fmt.Sprintf("download failed: %v", err))
 		uc.broadcast("update_progress", UpdateProgressPayload{
@@ -705,6 +730,24 @@ func (uc *UpdateChecker) executeBinaryUpdate(release *githubReleaseInfo) {
 		return
 	}
 
+	uc.broadcast("update_progress", UpdateProgressPayload{
+		Status:   "verifying",
+		Message:  "Verifying checksum...",
+		Progress: 35,
+	})
+
+	sum, err := verifyReleaseAsset(release, assetName, tmpFile)
+	if err != nil {
+		uc.recordError(fmt.Sprintf("verification failed: %v", err))
+		uc.broadcast("update_progress", UpdateProgressPayload{
+			Status:  "failed",
+			Message: "Download could not be verified, update aborted",
+			Error:   err.Error(),
+		})
+		return
+	}
+	uc.setAttemptChecksum(sum)
+
 	uc.broadcast("update_progress", UpdateProgressPayload{
 		Status:   "building",
 		Message:  "Extracting update...",
@@ -712,9 +755,11 @@ func (uc *UpdateChecker) executeBinaryUpdate(release *githubReleaseInfo) {
 	})
 
 	// Extract to staging directory
-	stagingDir := "/tmp/kc-update-staging"
-	os.RemoveAll(stagingDir)
-	os.MkdirAll(stagingDir, 0755)
+	stagingDir := filepath.Join(workDir, "staging")
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		uc.recordError(fmt.Sprintf("create staging dir failed: %v", err))
+		return
+	}
 
 	extractCmd := exec.Command("tar", "xzf", tmpFile, "-C", stagingDir)
 	if err := extractCmd.Run(); err != nil {
@@ -728,18 +773,23 @@ func (uc *UpdateChecker) executeBinaryUpdate(release *githubReleaseInfo) {
 	}
 
 	// Find current binary location
-	consolePath, err := exec.LookPath("console")
-	if err != nil {
-		// Try relative path
-		consolePath = "./console"
+	consolePath := uc.backendBinary
+	if consolePath == "" {
+		if consolePath = findConsoleBinary(); consolePath == "" {
+			// Try relative path
+			consolePath = "./console"
+		}
 	}
 
-	// Backup current binary
-	os.Rename(consolePath, consolePath+".backup")
-
-	// Replace
-	os.Rename(stagingDir+"/console", consolePath)
-	os.Chmod(consolePath, 0755)
+	if err := installStagedBinary(filepath.Join(stagingDir, "console"), consolePath); err != nil {
+		uc.recordError(fmt.Sprintf("install failed: %v", err))
+		uc.broadcast("update_progress", UpdateProgressPayload{
+			Status:  "failed",
+			Message: "Install failed, current version kept",
+			Error:   err.Error(),
+		})
+		return
+	}
 
 	uc.broadcast("update_progress", UpdateProgressPayload{
 		Status:   "restarting",
@@ -748,46 +798,42 @@ func (uc *UpdateChecker) executeBinaryUpdate(release *githubReleaseInfo) {
 	})
 
 	uc.killBackend()
-	if err := uc.restartBackend(); err != nil {
-		// Rollback
-		os.Rename(consolePath+".backup", consolePath)
-		uc.recordError(fmt.Sprintf("restart failed: %v", err))
-		uc.broadcast("update_progress", UpdateProgressPayload{
-			Status:  "failed",
-			Message: "Restart failed, rolled back",
-			Error:   err.Error(),
-		})
-		return
-	}
+	restartErr := uc.restartBackend()
+	if restartErr == nil && waitForBackendHealth(uc.healthURL) {
+		os.Remove(consolePath + ".backup")
+
+		uc.mu.Lock()
+		uc.currentVersion = release.TagName
+		uc.lastUpdateTime = time.Now()
+		uc.lastUpdateError = ""
+		uc.mu.Unlock()
+		uc.finishAttempt(UpdateSucceeded, "")
 
-	if !waitForBackendHealth() {
-		os.Rename(consolePath+".backup", consolePath)
-		uc.killBackend()
-		uc.restartBackend() //nolint:errcheck
-		uc.recordError("new version failed health check")
+		log.Printf("[AutoUpdate] Binary updated to %s (sha256 %s)", release.TagName, sum)
 		uc.broadcast("update_progress", UpdateProgressPayload{
-			Status:  "failed",
-			Message: "New version unhealthy, rolled back",
+			Status:   "done",
+			Message:  fmt.Sprintf("Updated to %s", release.TagName),
+			Progress: 100,
 		})
 		return
 	}
 
-	// Cleanup
-	os.Remove(consolePath + ".backup")
-	os.Remove(tmpFile)
-	os.RemoveAll(stagingDir)
-
-	uc.mu.Lock()
-	uc.currentVersion = release.TagName
-	uc.lastUpdateTime = time.Now()
-	uc.lastUpdateError = ""
-	uc.mu.Unlock()
-
-	log.Printf("[AutoUpdate] Binary updated to %s", release.TagName)
+	// Roll back to the previous binary and bring it back up
+	reason := "new version failed health check"
+	if restartErr != nil {
+		reason = fmt.Sprintf("restart failed: %v", restartErr)
+	}
+	if err := restoreBinary(consolePath); err != nil {
+		log.Printf("[AutoUpdate] Failed to restore previous binary: %v", err)
+	}
+	uc.killBackend()
+	uc.restartBackend() //nolint:errcheck
+	uc.finishAttempt(UpdateRolledBack, reason)
+	uc.recordError(reason)
 	uc.broadcast("update_progress", UpdateProgressPayload{
-		Status:   "done",
-		Message:  fmt.Sprintf("Updated to %s", release.TagName),
-		Progress: 100,
+		Status:  "failed",
+		Message: "New version unhealthy, rolled back",
+		Error:   reason,
 	})
 }
 
@@ -800,6 +846,11 @@ func (uc *UpdateChecker) executeDevReleaseUpdate(release *githubReleaseInfo) {
 		return
 	}
 
+	uc.mu.Lock()
+	currentVersion := uc.currentVersion
+	uc.mu.Unlock()
+	uc.startAttempt("dev-release", currentVersion, release.TagName)
+
 	// Stash any local changes so the checkout succeeds
 	stashed := gitStash(repoPath)
 
@@ -864,6 +915,7 @@ func (uc *UpdateChecker) executeDevReleaseUpdate(release *githubReleaseInfo) {
 	uc.lastUpdateError = ""
 	uc.mu.Unlock()
 
+	uc.finishAttempt(UpdateSucceeded, "built, restarting via startup-oauth.sh")
 	log.Printf("[AutoUpdate] Build complete for %s, restarting via startup-oauth.sh...", release.TagName)
 	uc.restartViaStartupScript(repoPath)
 }
@@ -873,6 +925,7 @@ func (uc *UpdateChecker) recordError(msg string) {
 	uc.lastUpdateError = msg
 	uc.lastUpdateTime = time.Now()
 	uc.mu.Unlock()
+	uc.finishAttempt(UpdateFailed, msg)
 	log.Printf("[AutoUpdate] Error: %s", msg)
 }
 
@@ -937,11 +990,13 @@ func (uc *UpdateChecker) resilientNpmInstall(webDir string, step, totalSteps int
 // --- Utility functions ---
 
 type githubReleaseInfo struct {
-	TagName string `json:"tag_name"`
-	Assets  []struct {
-		Name               string `json:"name"`
-		BrowserDownloadURL string `json:"browser_download_url"`
-	} `json:"assets"`
+	TagName string               `json:"tag_name"`
+	Assets  []githubReleaseAsset `json:"assets"`
+}
+
+type githubReleaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
 }
 
 type githubRefResponse struct {
@@ -1219,10 +1274,13 @@ func rollbackGit(repoPath, sha string) {
 	}
 }
 
-func waitForBackendHealth() bool {
+func waitForBackendHealth(url string) bool {
+	if url == "" {
+		url = AgentFileBackend{}.healthURL()
+	}
 	client := &http.Client{Timeout: healthCheckTimeout}
 	for i := 0; i < healthCheckRetries; i++ {
-		resp, err := client.Get(defaultHealthCheckURL)
+		resp, err := client.Get(url)
 		if err == nil && resp.StatusCode == http.StatusOK {
 			resp.Body.Close()
 			return true
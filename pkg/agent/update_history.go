@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	updateHistoryFile = "update-history.json"
+	// maxUpdateHistory is how many update attempts are kept
+	maxUpdateHistory = 50
+)
+
+// Outcomes of an update attempt
+const (
+	UpdateSucceeded  = "succeeded"
+	UpdateFailed     = "failed"
+	UpdateRolledBack = "rolled_back"
+)
+
+// UpdateHistoryEntry is one auto-update attempt
+type UpdateHistoryEntry struct {
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+	Method      string    `json:"method"` // binary, developer or dev-release
+	FromVersion string    `json:"fromVersion"`
+	ToVersion   string    `json:"toVersion"`
+	Outcome     string    `json:"outcome"`
+	SHA256      string    `json:"sha256,omitempty"` // verified artifact checksum (binary updates)
+	Detail      string    `json:"detail,omitempty"`
+}
+
+// UpdateHistory keeps the recent update attempts in ~/.kc/update-history.json
+type UpdateHistory struct {
+	mu      sync.Mutex
+	path    string
+	entries []UpdateHistoryEntry
+}
+
+// NewUpdateHistory loads the history kept in dataDir
+func NewUpdateHistory(dataDir string) *UpdateHistory {
+	h := &UpdateHistory{path: filepath.Join(dataDir, updateHistoryFile)}
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[AutoUpdate] Error reading %s: %v", h.path, err)
+		}
+		return h
+	}
+	if err := json.Unmarshal(data, &h.entries); err != nil {
+		log.Printf("[AutoUpdate] Error parsing %s: %v", h.path, err)
+	}
+	return h
+}
+
+// Add records a finished attempt, dropping the oldest beyond maxUpdateHistory
+func (h *UpdateHistory) Add(entry UpdateHistoryEntry) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxUpdateHistory {
+		h.entries = h.entries[len(h.entries)-maxUpdateHistory:]
+	}
+	data, err := json.MarshalIndent(h.entries, "", "  ")
+	h.mu.Unlock()
+	if err != nil {
+		log.Printf("[AutoUpdate] Error marshaling update history: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), metricsDirMode); err != nil {
+		log.Printf("[AutoUpdate] Error creating data dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(h.path, data, metricsFileMode); err != nil {
+		log.Printf("[AutoUpdate] Error writing update history: %v", err)
+	}
+}
+
+// List returns the attempts, most recent first
+func (h *UpdateHistory) List() []UpdateHistoryEntry {
+	if h == nil {
+		return []UpdateHistoryEntry{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entries := make([]UpdateHistoryEntry, 0, len(h.entries))
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		entries = append(entries, h.entries[i])
+	}
+	return entries
+}
+
+// startAttempt opens the history entry of an update; recordError and
+// finishAttempt close it
+func (uc *UpdateChecker) startAttempt(method, from, to string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.attempt = &UpdateHistoryEntry{StartedAt: time.Now(), Method: method, FromVersion: from, ToVersion: to}
+}
+
+// finishAttempt records the outcome of the open attempt, if any
+func (uc *UpdateChecker) finishAttempt(outcome, detail string) {
+	uc.mu.Lock()
+	attempt := uc.attempt
+	uc.attempt = nil
+	uc.mu.Unlock()
+	if attempt == nil {
+		return
+	}
+	attempt.FinishedAt = time.Now()
+	attempt.Outcome = outcome
+	attempt.Detail = detail
+	uc.history.Add(*attempt)
+}
+
+// setAttemptChecksum notes the verified artifact checksum on the open attempt
+func (uc *UpdateChecker) setAttemptChecksum(sum string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.attempt != nil {
+		uc.attempt.SHA256 = sum
+	}
+}
+
+// History returns the recorded update attempts, most recent first
+func (uc *UpdateChecker) History() []UpdateHistoryEntry {
+	return uc.history.List()
+}
+
+// handleAutoUpdateHistory returns the recent auto-update attempts and their outcomes
+func (s *Server) handleAutoUpdateHistory(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.updateChecker == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "update checker not initialized"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"history": s.updateChecker.History()})
+}
@@ -0,0 +1,210 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// releaseChecksumsAsset is the goreleaser checksum file published with every release
+	releaseChecksumsAsset = "checksums.txt"
+	// releaseSignatureAsset is a detached ed25519 signature of releaseChecksumsAsset
+	releaseSignatureAsset = releaseChecksumsAsset + ".sig"
+	// UpdatePublicKeyEnv holds the base64 ed25519 public key release checksums
+	// must be signed with. Unset, updates are verified by checksum only.
+	UpdatePublicKeyEnv = "KC_UPDATE_PUBLIC_KEY"
+
+	maxChecksumsBytes = 1 << 20
+	metadataTimeout   = 30 * time.Second
+)
+
+// verifyReleaseAsset checks a downloaded release asset against the release's
+// checksums.txt, after verifying the signature of checksums.txt when a public
+// key is configured. Returns the verified SHA256.
+func verifyReleaseAsset(release *githubReleaseInfo, assetName, path string) (string, error) {
+	checksumsURL := releaseAssetURL(release, releaseChecksumsAsset)
+	if checksumsURL == "" {
+		return "", fmt.Errorf("release %s has no %s", release.TagName, releaseChecksumsAsset)
+	}
+	checksums, err := downloadBytes(checksumsURL, maxChecksumsBytes)
+	if err != nil {
+		return "", fmt.Errorf("download %s: %w", releaseChecksumsAsset, err)
+	}
+
+	if key := os.Getenv(UpdatePublicKeyEnv); key != "" {
+		sigURL := releaseAssetURL(release, releaseSignatureAsset)
+		if sigURL == "" {
+			return "", fmt.Errorf("release %s is not signed (%s missing)", release.TagName, releaseSignatureAsset)
+		}
+		sig, err := downloadBytes(sigURL, maxChecksumsBytes)
+		if err != nil {
+			return "", fmt.Errorf("download %s: %w", releaseSignatureAsset, err)
+		}
+		if err := verifyChecksumsSignature(checksums, sig, key); err != nil {
+			return "", err
+		}
+	}
+
+	want, err := findChecksum(checksums, assetName)
+	if err != nil {
+		return "", err
+	}
+	got, err := fileSHA256(path)
+	if err != nil {
+		return "", err
+	}
+	if got != want {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", assetName, want, got)
+	}
+	return got, nil
+}
+
+// findChecksum returns the SHA256 listed for name in a sha256sum-style file
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := strings.ToLower(fields[0])
+		if decoded, err := hex.DecodeString(sum); err != nil || len(decoded) != sha256.Size {
+			return "", fmt.Errorf("invalid checksum for %s", name)
+		}
+		return sum, nil
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// verifyChecksumsSignature checks a detached ed25519 signature, raw or base64
+func verifyChecksumsSignature(checksums, sig []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%s is not a base64 ed25519 public key", UpdatePublicKeyEnv)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil {
+			return fmt.Errorf("invalid signature encoding: %w", err)
+		}
+		sig = decoded
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), checksums, sig) {
+		return fmt.Errorf("signature of %s does not match %s", releaseChecksumsAsset, UpdatePublicKeyEnv)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func releaseAssetURL(release *githubReleaseInfo, name string) string {
+	for _, a := range release.Assets {
+		if a.Name == name {
+			return a.BrowserDownloadURL
+		}
+	}
+	return ""
+}
+
+// downloadBytes fetches a small release file, failing beyond limit bytes
+func downloadBytes(url string, limit int64) ([]byte, error) {
+	client := &http.Client{Timeout: metadataTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download returned %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("file larger than %d bytes", limit)
+	}
+	return data, nil
+}
+
+// installStagedBinary replaces dest with a verified staged binary. The staged
+// file is copied next to dest first so the final rename is atomic, and the
+// previous binary is kept as dest.backup for restoreBinary.
+func installStagedBinary(staged, dest string) error {
+	info, err := os.Stat(staged)
+	if err != nil {
+		return fmt.Errorf("staged binary missing: %w", err)
+	}
+	if !info.Mode().IsRegular() || info.Size() == 0 {
+		return fmt.Errorf("staged binary %s is not a regular file", staged)
+	}
+
+	tmp := dest + ".update-tmp"
+	if err := copyFile(staged, tmp, 0755); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("stage binary: %w", err)
+	}
+	backup := dest + ".backup"
+	hadPrevious := true
+	if err := os.Rename(dest, backup); err != nil {
+		if !os.IsNotExist(err) {
+			os.Remove(tmp)
+			return fmt.Errorf("back up current binary: %w", err)
+		}
+		hadPrevious = false
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		if hadPrevious {
+			os.Rename(backup, dest) //nolint:errcheck
+		}
+		return fmt.Errorf("install binary: %w", err)
+	}
+	return nil
+}
+
+// restoreBinary puts back the binary saved by installStagedBinary
+func restoreBinary(dest string) error {
+	return os.Rename(dest+".backup", dest)
+}
+
+func copyFile(src, dest string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(dest, mode)
+}
@@ -0,0 +1,139 @@
+package agent
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyReleaseAsset(t *testing.T) {
+	artifact := []byte("console binary archive")
+	sum := sha256.Sum256(artifact)
+	checksums := []byte(fmt.Sprintf("%s  console_1.2.3_linux_amd64.tar.gz\n%s  kc-agent_1.2.3_linux_amd64.tar.gz\n",
+		hex.EncodeToString(sum[:]), hex.EncodeToString(make([]byte, sha256.Size))))
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := ed25519.Sign(priv, checksums)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums.txt":
+			w.Write(checksums)
+		case "/checksums.txt.sig":
+			w.Write([]byte(base64.StdEncoding.EncodeToString(sig)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	release := &githubReleaseInfo{TagName: "v1.2.3", Assets: []githubReleaseAsset{
+		{Name: "checksums.txt", BrowserDownloadURL: srv.URL + "/checksums.txt"},
+		{Name: "checksums.txt.sig", BrowserDownloadURL: srv.URL + "/checksums.txt.sig"},
+	}}
+
+	path := filepath.Join(t.TempDir(), "console.tar.gz")
+	if err := os.WriteFile(path, artifact, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(UpdatePublicKeyEnv, "")
+	got, err := verifyReleaseAsset(release, "console_1.2.3_linux_amd64.tar.gz", path)
+	if err != nil || got != hex.EncodeToString(sum[:]) {
+		t.Fatalf("Expected the artifact to verify, got %q, %v", got, err)
+	}
+	if _, err := verifyReleaseAsset(release, "kc-agent_1.2.3_linux_amd64.tar.gz", path); err == nil {
+		t.Error("Expected a checksum mismatch to be rejected")
+	}
+	if _, err := verifyReleaseAsset(release, "console_1.2.3_darwin_arm64.tar.gz", path); err == nil {
+		t.Error("Expected an unlisted artifact to be rejected")
+	}
+
+	t.Setenv(UpdatePublicKeyEnv, base64.StdEncoding.EncodeToString(pub))
+	if _, err := verifyReleaseAsset(release, "console_1.2.3_linux_amd64.tar.gz", path); err != nil {
+		t.Errorf("Expected a signed release to verify, got %v", err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	t.Setenv(UpdatePublicKeyEnv, base64.StdEncoding.EncodeToString(otherPub))
+	if _, err := verifyReleaseAsset(release, "console_1.2.3_linux_amd64.tar.gz", path); err == nil {
+		t.Error("Expected a signature from another key to be rejected")
+	}
+}
+
+func TestInstallStagedBinaryAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "console")
+	staged := filepath.Join(dir, "staging-console")
+	if err := os.WriteFile(dest, []byte("v1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(staged, []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := installStagedBinary(staged, dest); err != nil {
+		t.Fatalf("installStagedBinary failed: %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "v2" {
+		t.Errorf("Expected the staged binary to be installed, got %q", data)
+	}
+	if info, _ := os.Stat(dest); info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected the installed binary to be executable, got %v", info.Mode())
+	}
+
+	if err := restoreBinary(dest); err != nil {
+		t.Fatalf("restoreBinary failed: %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "v1" {
+		t.Errorf("Expected the previous binary after rollback, got %q", data)
+	}
+
+	if err := installStagedBinary(filepath.Join(dir, "missing"), dest); err == nil {
+		t.Error("Expected a missing staged binary to be rejected")
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "v1" {
+		t.Errorf("Expected a failed install to leave the binary alone, got %q", data)
+	}
+}
+
+func TestUpdateHistoryAttempts(t *testing.T) {
+	dir := t.TempDir()
+	uc := &UpdateChecker{history: NewUpdateHistory(dir)}
+
+	uc.startAttempt("binary", "v1.0.0", "v1.1.0")
+	uc.setAttemptChecksum("abc")
+	uc.recordError("download failed: timeout")
+	uc.recordError("not part of an attempt")
+
+	uc.startAttempt("binary", "v1.0.0", "v1.1.1")
+	uc.finishAttempt(UpdateRolledBack, "new version failed health check")
+
+	history := NewUpdateHistory(dir).List()
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 persisted attempts, got %+v", history)
+	}
+	if history[0].ToVersion != "v1.1.1" || history[0].Outcome != UpdateRolledBack {
+		t.Errorf("Expected the rollback first, got %+v", history[0])
+	}
+	if history[1].Outcome != UpdateFailed || history[1].SHA256 != "abc" || history[1].Detail != "download failed: timeout" {
+		t.Errorf("Unexpected failed attempt: %+v", history[1])
+	}
+
+	for i := 0; i < maxUpdateHistory+5; i++ {
+		uc.startAttempt("developer", "a", "b")
+		uc.finishAttempt(UpdateSucceeded, "")
+	}
+	if n := len(uc.History()); n != maxUpdateHistory {
+		t.Errorf("Expected history capped at %d, got %d", maxUpdateHistory, n)
+	}
+}
@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// handleValidate validates a YAML or JSON manifest against a cluster with a
+// server-side dry-run, CRD schema checks and the security checks, without
+// applying anything
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.k8sClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	var req struct {
+		Cluster   string `json:"cluster"`
+		Namespace string `json:"namespace,omitempty"` // for objects without one
+		Manifest  string `json:"manifest"`            // YAML or JSON, multiple documents allowed
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Cluster == "" || req.Manifest == "" {
+		http.Error(w, "cluster and manifest are required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, req.Cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+
+	result, err := s.k8sClient.ValidateManifest(ctx, req.Cluster, req.Namespace, []byte(req.Manifest))
+	if err != nil {
+		log.Printf("[Validate] %s: %v", req.Cluster, err)
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]string{"error": "cluster unavailable"})
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
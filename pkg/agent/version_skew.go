@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// handleVersionSkew reports kubelet, control-plane, container runtime and GPU
+// driver versions across clusters, with skew and end-of-life findings.
+// Unreachable clusters are listed with an error instead of failing the report.
+func (s *Server) handleVersionSkew(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	clusters, err := s.exportClusters(r.Context(), r.URL.Query().Get("cluster"))
+	if err != nil {
+		log.Printf("error listing clusters for version skew: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+		return
+	}
+
+	results := make([]k8s.ClusterVersions, len(clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func(i int, cluster string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+			defer cancel()
+			cv, err := s.k8sClient.GetClusterVersions(ctx, cluster)
+			if err != nil {
+				log.Printf("[VersionSkew] %s: %v", cluster, err)
+				results[i] = k8s.ClusterVersions{Cluster: cluster, Error: "cluster unreachable"}
+				return
+			}
+			results[i] = *cv
+		}(i, cluster)
+	}
+	wg.Wait()
+
+	json.NewEncoder(w).Encode(k8s.NewFleetVersionReport(results, time.Now()))
+}
@@ -0,0 +1,421 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const wecRegistrationsFile = "wec-registrations.json"
+
+// WEC registration states
+const (
+	wecJoining    = "joining"
+	wecAccepting  = "accepting"
+	wecRegistered = "registered"
+	wecFailed     = "failed"
+)
+
+var (
+	// wecAcceptInterval and wecAcceptTimeout bound how long the hub waits for the
+	// klusterlet's certificate signing request after joining
+	wecAcceptInterval = 10 * time.Second
+	wecAcceptTimeout  = 5 * time.Minute
+
+	errRegistrationInProgress = errors.New("registration already in progress")
+)
+
+// WECRegistration tracks a workload execution cluster joining an OCM hub (a KubeStellar ITS)
+type WECRegistration struct {
+	Name      string            `json:"name"`    // ManagedCluster name on the hub
+	Context   string            `json:"context"` // kubeconfig context of the WEC
+	Hub       string            `json:"hub"`     // kubeconfig context of the hub
+	Labels    map[string]string `json:"labels,omitempty"`
+	Status    string            `json:"status"` // joining, accepting, registered, failed
+	Error     string            `json:"error,omitempty"`
+	StartedAt string            `json:"startedAt"`
+	UpdatedAt string            `json:"updatedAt"`
+}
+
+// WECRegistrations persists WEC registrations so registered clusters stay
+// tagged as managed across restarts
+type WECRegistrations struct {
+	mu       sync.Mutex
+	items    map[string]*WECRegistration
+	path     string
+	onChange func(managed map[string]bool)
+}
+
+// NewWECRegistrations loads the registrations stored in dataDir. onChange is
+// called with the registered contexts after loading and after every change.
+func NewWECRegistrations(dataDir string, onChange func(map[string]bool)) *WECRegistrations {
+	w := &WECRegistrations{
+		items:    make(map[string]*WECRegistration),
+		path:     filepath.Join(dataDir, wecRegistrationsFile),
+		onChange: onChange,
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("[WECRegistration] Error reading %s: %v", w.path, err)
+	}
+	if err == nil {
+		var items []*WECRegistration
+		if err := json.Unmarshal(data, &items); err != nil {
+			log.Printf("[WECRegistration] Error parsing %s: %v", w.path, err)
+		}
+		for _, r := range items {
+			w.items[r.Name] = r
+		}
+	}
+	w.notify()
+	return w
+}
+
+// List returns all registrations sorted by name
+func (w *WECRegistrations) List() []WECRegistration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.listLocked()
+}
+
+func (w *WECRegistrations) listLocked() []WECRegistration {
+	out := make([]WECRegistration, 0, len(w.items))
+	for _, r := range w.items {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// start records a new registration, refusing one already in progress
+func (w *WECRegistrations) start(r WECRegistration) error {
+	w.mu.Lock()
+	if existing, ok := w.items[r.Name]; ok && (existing.Status == wecJoining || existing.Status == wecAccepting) {
+		w.mu.Unlock()
+		return errRegistrationInProgress
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	r.Status, r.StartedAt, r.UpdatedAt = wecJoining, now, now
+	w.items[r.Name] = &r
+	w.saveLocked()
+	w.mu.Unlock()
+	return nil
+}
+
+// setStatus updates a registration and returns a copy of it. The change is
+// persisted and published before any reader can observe the new status.
+func (w *WECRegistrations) setStatus(name, status, errMsg string) WECRegistration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	r := w.items[name]
+	r.Status, r.Error = status, errMsg
+	r.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	w.saveLocked()
+	w.notifyLocked()
+	return *r
+}
+
+func (w *WECRegistrations) notify() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.notifyLocked()
+}
+
+func (w *WECRegistrations) notifyLocked() {
+	if w.onChange == nil {
+		return
+	}
+	managed := make(map[string]bool)
+	for _, r := range w.items {
+		if r.Status == wecRegistered {
+			managed[r.Context] = true
+		}
+	}
+	w.onChange(managed)
+}
+
+func (w *WECRegistrations) saveLocked() {
+	data, err := json.Marshal(w.listLocked())
+	if err != nil {
+		log.Printf("[WECRegistration] Error marshaling registrations: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(w.path), metricsDirMode); err != nil {
+		log.Printf("[WECRegistration] Error creating data dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(w.path, data, metricsFileMode); err != nil {
+		log.Printf("[WECRegistration] Error writing registrations file: %v", err)
+	}
+}
+
+// hubJoinToken is the bootstrap token and API server a WEC uses to join a hub
+type hubJoinToken struct {
+	Token     string
+	APIServer string
+}
+
+// parseHubJoinToken extracts the token and API server from the join command
+// printed by clusteradm get token
+func parseHubJoinToken(output string) (hubJoinToken, error) {
+	var t hubJoinToken
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "clusteradm" || fields[1] != "join" {
+			continue
+		}
+		for i := 2; i < len(fields)-1; i++ {
+			switch fields[i] {
+			case "--hub-token":
+				t.Token = fields[i+1]
+			case "--hub-apiserver":
+				t.APIServer = fields[i+1]
+			}
+		}
+	}
+	if t.Token == "" || t.APIServer == "" {
+		return t, fmt.Errorf("no join command in clusteradm output")
+	}
+	return t, nil
+}
+
+// runClusteradm runs clusteradm and returns its stdout
+func runClusteradm(args ...string) (string, error) {
+	cmd := execCommand("clusteradm", args...)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("clusteradm %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out.String(), nil
+}
+
+// wecJoinRequest asks for a WEC to be registered with a hub
+type wecJoinRequest struct {
+	Hub     string            `json:"hub"`     // hub (ITS) context
+	Cluster string            `json:"cluster"` // WEC context
+	Name    string            `json:"name,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	// Apply joins the cluster; otherwise the join manifests are only generated
+	Apply bool `json:"apply"`
+	// ForceInternalEndpointLookup is needed when the hub runs in kind
+	ForceInternalEndpointLookup bool `json:"forceInternalEndpointLookup,omitempty"`
+}
+
+func (req *wecJoinRequest) validate() error {
+	if req.Hub == "" || req.Cluster == "" {
+		return fmt.Errorf("hub and cluster are required")
+	}
+	if req.Name == "" {
+		req.Name = req.Cluster
+	}
+	if errs := validation.IsDNS1123Label(req.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster name %q: %s", req.Name, errs[0])
+	}
+	for k, v := range req.Labels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", k, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid label value %q: %s", v, errs[0])
+		}
+	}
+	return nil
+}
+
+// joinArgs builds the clusteradm join command run against the WEC
+func (req *wecJoinRequest) joinArgs(token hubJoinToken) []string {
+	args := []string{"join", "--hub-token", token.Token, "--hub-apiserver", token.APIServer,
+		"--cluster-name", req.Name, "--context", req.Cluster, "--singleton"}
+	if req.ForceInternalEndpointLookup {
+		args = append(args, "--force-internal-endpoint-lookup")
+	}
+	return args
+}
+
+// completeWECRegistration joins the WEC, accepts it on the hub once its
+// certificate request arrives, and applies the requested labels
+func (s *Server) completeWECRegistration(req wecJoinRequest, token hubJoinToken) {
+	progress := func(status, errMsg string) {
+		r := s.wecRegistrations.setStatus(req.Name, status, errMsg)
+		s.BroadcastToClients("wec_registration_progress", r)
+	}
+	if _, err := runClusteradm(req.joinArgs(token)...); err != nil {
+		log.Printf("[WECRegistration] join of %s failed: %v", req.Name, err)
+		progress(wecFailed, err.Error())
+		return
+	}
+
+	progress(wecAccepting, "")
+	deadline := time.Now().Add(wecAcceptTimeout)
+	for {
+		_, err := runClusteradm("accept", "--clusters", req.Name, "--context", req.Hub)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			log.Printf("[WECRegistration] accept of %s failed: %v", req.Name, err)
+			progress(wecFailed, err.Error())
+			return
+		}
+		time.Sleep(wecAcceptInterval)
+	}
+
+	labels := map[string]string{"name": req.Name}
+	for k, v := range req.Labels {
+		labels[k] = v
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), agentDefaultTimeout)
+	defer cancel()
+	if err := s.k8sClient.LabelManagedCluster(ctx, req.Hub, req.Name, labels); err != nil {
+		log.Printf("[WECRegistration] labeling %s failed: %v", req.Name, err)
+		progress(wecFailed, "accepted but labeling failed: "+err.Error())
+		return
+	}
+	log.Printf("[WECRegistration] %s registered with %s", req.Name, req.Hub)
+	progress(wecRegistered, "")
+}
+
+// handleWECs lists WEC registrations and, with ?hub=, the ManagedClusters on that hub
+func (s *Server) handleWECs(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil || s.wecRegistrations == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	resp := map[string]interface{}{"registrations": s.wecRegistrations.List()}
+	if hub := r.URL.Query().Get("hub"); hub != "" {
+		ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, hub, k8s.OpList, agentDefaultTimeout))
+		defer cancel()
+		clusters, err := s.k8sClient.ListManagedClusters(ctx, hub)
+		if err != nil {
+			log.Printf("[WECRegistration] listing managed clusters on %s: %v", hub, err)
+			w.WriteHeader(http.StatusBadGateway)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to list managed clusters on hub"})
+			return
+		}
+		resp["managedClusters"] = clusters
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleWECJoin generates the join manifests for registering a WEC with a hub,
+// or with apply set, joins it and tracks the registration in the background
+func (s *Server) handleWECJoin(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.k8sClient == nil || s.wecRegistrations == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "k8s client not initialized"})
+		return
+	}
+
+	var req wecJoinRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid request body"})
+		return
+	}
+	if err := req.validate(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if _, err := lookPath("clusteradm"); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"error": "clusteradm is not installed"})
+		return
+	}
+
+	out, err := runClusteradm("get", "token", "--context", req.Hub)
+	var token hubJoinToken
+	if err == nil {
+		token, err = parseHubJoinToken(out)
+	}
+	if err != nil {
+		log.Printf("[WECRegistration] getting join token from %s: %v", req.Hub, err)
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]string{"error": "failed to get join token from hub"})
+		return
+	}
+	command := "clusteradm " + strings.Join(req.joinArgs(token), " ")
+
+	if !req.Apply {
+		manifests, err := runClusteradm(append(req.joinArgs(token), "--dry-run")...)
+		if err != nil {
+			log.Printf("[WECRegistration] generating join manifests for %s: %v", req.Name, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to generate join manifests"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":      req.Name,
+			"command":   command,
+			"acceptCmd": fmt.Sprintf("clusteradm accept --clusters %s --context %s", req.Name, req.Hub),
+			"manifests": manifests,
+		})
+		return
+	}
+
+	if err := s.wecRegistrations.start(WECRegistration{Name: req.Name, Context: req.Cluster, Hub: req.Hub, Labels: req.Labels}); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[WECRegistration] recovered from panic registering %s: %v", req.Name, r)
+			}
+		}()
+		s.completeWECRegistration(req, token)
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{"name": req.Name, "status": wecJoining})
+}
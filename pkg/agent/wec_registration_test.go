@@ -0,0 +1,143 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const testClusteradmToken = `token=abc
+please log on spoke and run:
+clusteradm join --hub-token abc.def --hub-apiserver https://its1.localtest.me:9443 --cluster-name <cluster_name>
+`
+
+func TestParseHubJoinToken(t *testing.T) {
+	token, err := parseHubJoinToken(testClusteradmToken)
+	if err != nil {
+		t.Fatalf("parseHubJoinToken failed: %v", err)
+	}
+	if token.Token != "abc.def" || token.APIServer != "https://its1.localtest.me:9443" {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+	if _, err := parseHubJoinToken("error: no bootstrap token"); err == nil {
+		t.Error("Expected an error without a join command")
+	}
+}
+
+func TestHandleWECJoin(t *testing.T) {
+	oldLookPath, oldExecCommand, oldInterval := lookPath, execCommand, wecAcceptInterval
+	defer func() {
+		lookPath, execCommand, wecAcceptInterval = oldLookPath, oldExecCommand, oldInterval
+	}()
+	lookPath = func(file string) (string, error) { return "/usr/local/bin/" + file, nil }
+	wecAcceptInterval = time.Millisecond
+	accepts := 0
+	execCommand = func(name string, arg ...string) *exec.Cmd {
+		call := strings.Join(arg, " ")
+		switch {
+		case strings.HasPrefix(call, "get token"):
+			return exec.Command("printf", "%s", testClusteradmToken)
+		case strings.HasPrefix(call, "accept"):
+			// The CSR shows up after the klusterlet starts
+			accepts++
+			if accepts < 3 {
+				return exec.Command("false")
+			}
+		case strings.Contains(call, "--dry-run"):
+			return exec.Command("echo", "kind: Klusterlet")
+		}
+		return exec.Command("true")
+	}
+
+	dir := t.TempDir()
+	kubeconfigPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeconfigPath, []byte(sampleKubeconfig("cluster1", "cluster1", "cluster1", "https://cluster1:6443")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	client, err := k8s.NewMultiClusterClient(kubeconfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.InjectDynamicClient("its1", dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "cluster.open-cluster-management.io", Version: "v1", Resource: "managedclusters"}: "ManagedClusterList",
+		}, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "cluster.open-cluster-management.io/v1",
+			"kind":       "ManagedCluster",
+			"metadata":   map[string]interface{}{"name": "cluster1"},
+		}}))
+
+	s := &Server{k8sClient: client, allowedOrigins: []string{"*"}}
+	s.wecRegistrations = NewWECRegistrations(dir, client.SetManagedContexts)
+
+	post := func(req wecJoinRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		s.handleWECJoin(w, httptest.NewRequest("POST", "/kubestellar/wecs/join", bytes.NewReader(body)))
+		return w
+	}
+
+	if w := post(wecJoinRequest{Hub: "its1", Cluster: "cluster1", Labels: map[string]string{"bad key!": "x"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid label, got %d", w.Code)
+	}
+
+	// Without apply only the manifests are generated
+	w := post(wecJoinRequest{Hub: "its1", Cluster: "cluster1"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var preview map[string]string
+	json.Unmarshal(w.Body.Bytes(), &preview)
+	if !strings.Contains(preview["manifests"], "Klusterlet") || !strings.Contains(preview["command"], "--hub-token abc.def --hub-apiserver https://its1.localtest.me:9443 --cluster-name cluster1 --context cluster1") {
+		t.Errorf("Unexpected preview: %v", preview)
+	}
+	if len(s.wecRegistrations.List()) != 0 {
+		t.Error("A preview must not start a registration")
+	}
+
+	w = post(wecJoinRequest{Hub: "its1", Cluster: "cluster1", Labels: map[string]string{"location-group": "edge"}, Apply: true})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var reg WECRegistration
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		reg = s.wecRegistrations.List()[0]
+		if reg.Status == wecRegistered || reg.Status == wecFailed {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if reg.Status != wecRegistered {
+		t.Fatalf("Expected registration to complete, got %+v", reg)
+	}
+
+	managed, err := client.ListManagedClusters(context.Background(), "its1")
+	if err != nil || len(managed) != 1 || managed[0].Labels["location-group"] != "edge" || managed[0].Labels["name"] != "cluster1" {
+		t.Errorf("Expected labeled managed cluster, got %+v (%v)", managed, err)
+	}
+	clusters, err := client.ListClusters(context.Background())
+	if err != nil || len(clusters) != 1 || clusters[0].Source != k8s.ClusterSourceManaged {
+		t.Errorf("Expected cluster1 to be tagged managed, got %+v (%v)", clusters, err)
+	}
+
+	// Registrations survive a restart
+	reloaded := NewWECRegistrations(dir, nil)
+	if list := reloaded.List(); len(list) != 1 || list[0].Status != wecRegistered {
+		t.Errorf("Expected persisted registration, got %+v", list)
+	}
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// handleWorkloadsHTTP returns one row per top-level workload of a cluster, with
+// its pods rolled up through ReplicaSets and Jobs (see GetWorkloadRollups)
+func (s *Server) handleWorkloadsHTTP(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.validateToken(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.k8sClient == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"workloads": []interface{}{}, "error": "k8s client not initialized"})
+		return
+	}
+
+	cluster := r.URL.Query().Get("cluster")
+	namespace := r.URL.Query().Get("namespace")
+	if cluster == "" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"workloads": []interface{}{}, "error": "cluster parameter required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.requestTimeout(r, cluster, k8s.OpList, agentDefaultTimeout))
+	defer cancel()
+
+	workloads, err := s.k8sClient.GetWorkloadRollups(ctx, cluster, namespace)
+	if err != nil {
+		log.Printf("error fetching workloads: %v", err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"workloads": []interface{}{}, "error": "internal server error"})
+		return
+	}
+
+	if wantsNDJSON(r) {
+		writeNDJSON(w, workloads)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"workloads": workloads, "source": "agent"})
+}
@@ -0,0 +1,24 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServer_HandleWorkloadsHTTP_Unauthorized(t *testing.T) {
+	server := &Server{
+		k8sClient:      nil,
+		agentToken:     "secret",
+		allowedOrigins: []string{"*"},
+	}
+
+	req := httptest.NewRequest("GET", "/workloads?cluster=test", nil)
+	w := httptest.NewRecorder()
+
+	server.handleWorkloadsHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401, got %d", w.Code)
+	}
+}
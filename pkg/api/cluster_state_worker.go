@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// defaultClusterStateIntervalMs is the default interval between cluster state snapshots (1 hour)
+const defaultClusterStateIntervalMs = 3_600_000
+
+// ClusterStateWorker periodically records the state of every healthy cluster
+// so /changes can report what changed since an earlier point in time
+type ClusterStateWorker struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+	interval  time.Duration
+	stopCh    chan struct{}
+}
+
+// NewClusterStateWorker creates a new cluster state worker
+func NewClusterStateWorker(s store.Store, k8sClient *k8s.MultiClusterClient) *ClusterStateWorker {
+	intervalMs := defaultClusterStateIntervalMs
+	if envVal := os.Getenv("CLUSTER_STATE_INTERVAL_MS"); envVal != "" {
+		if parsed, err := strconv.Atoi(envVal); err == nil && parsed > 0 {
+			intervalMs = parsed
+		}
+	}
+
+	return &ClusterStateWorker{
+		store:     s,
+		k8sClient: k8sClient,
+		interval:  time.Duration(intervalMs) * time.Millisecond,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the background polling loop
+func (w *ClusterStateWorker) Start() {
+	go func() {
+		w.cleanupOldSnapshots()
+		w.captureStates()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.captureStates()
+			case <-w.stopCh:
+				return
+			}
+		}
+	}()
+	log.Printf("Cluster state worker started (interval: %v)", w.interval)
+}
+
+// Stop signals the worker to stop
+func (w *ClusterStateWorker) Stop() {
+	close(w.stopCh)
+}
+
+// captureStates records a snapshot of every healthy cluster
+func (w *ClusterStateWorker) captureStates() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.interval/2)
+	defer cancel()
+
+	clusters, _, err := w.k8sClient.HealthyClusters(ctx)
+	if err != nil {
+		log.Printf("Cluster state worker: failed to list clusters: %v", err)
+		return
+	}
+
+	for _, cluster := range clusters {
+		state, err := w.k8sClient.CaptureClusterState(ctx, cluster.Name)
+		if err != nil {
+			log.Printf("Cluster state worker: failed to capture %s: %v", cluster.Name, err)
+			continue
+		}
+		data, err := json.Marshal(state)
+		if err != nil {
+			log.Printf("Cluster state worker: failed to encode %s: %v", cluster.Name, err)
+			continue
+		}
+		snapshot := &models.ClusterStateSnapshot{
+			ID:        uuid.New().String(),
+			Cluster:   cluster.Name,
+			Timestamp: state.CapturedAt,
+			State:     data,
+		}
+		if err := w.store.InsertClusterStateSnapshot(snapshot); err != nil {
+			log.Printf("Cluster state worker: failed to insert snapshot for %s: %v", cluster.Name, err)
+		}
+	}
+}
+
+// cleanupOldSnapshots removes snapshots older than the retention period
+func (w *ClusterStateWorker) cleanupOldSnapshots() {
+	cutoff := time.Now().AddDate(0, 0, -snapshotRetentionDays)
+	deleted, err := w.store.DeleteOldClusterStateSnapshots(cutoff)
+	if err != nil {
+		log.Printf("Cluster state worker: failed to cleanup old snapshots: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Cluster state worker: cleaned up %d old snapshots", deleted)
+	}
+}
@@ -1,6 +1,10 @@
 package handlers
 
 import (
+	"context"
+	"sort"
+	"sync"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/kubestellar/console/pkg/k8s"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -105,6 +109,56 @@ func (h *WebhookHandlers) ListWebhooks(c *fiber.Ctx) error {
 	})
 }
 
+// GetWebhookHealth checks that each webhook's backing service has ready
+// endpoints and times a dry-run canary through admission on every cluster,
+// or only ?cluster. ?canary=false skips the canary.
+// GET /api/admission-webhooks/health
+func (h *WebhookHandlers) GetWebhookHealth(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(statusServiceUnavailableWebhook).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	canary := c.Query("canary") != "false"
+	var clusterNames []string
+	if cluster := c.Query("cluster"); cluster != "" {
+		clusterNames = []string{cluster}
+	} else {
+		clusters, err := h.k8sClient.DeduplicatedClusters(c.Context())
+		if err != nil {
+			clusters, _ = h.k8sClient.ListClusters(c.Context())
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reports := make([]k8s.WebhookHealthReport, 0, len(clusterNames))
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(cluster string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			report, err := h.k8sClient.CheckWebhookHealth(ctx, cluster, canary)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			reports = append(reports, *report)
+			mu.Unlock()
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Cluster < reports[j].Cluster })
+
+	return c.JSON(fiber.Map{"clusters": reports})
+}
+
 // parseWebhookFromUnstructured extracts webhook info from an unstructured object
 func parseWebhookFromUnstructured(item *unstructured.Unstructured, cluster, whType string) *WebhookSummary {
 	name := item.GetName()
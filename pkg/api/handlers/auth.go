@@ -17,6 +17,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/audit"
 	"github.com/kubestellar/console/pkg/models"
 	"github.com/kubestellar/console/pkg/store"
 )
@@ -79,6 +80,7 @@ type AuthConfig struct {
 	GitHubToken      string // Personal access token for dev mode profile lookup
 	DevMode          bool   // Force dev mode bypass even if OAuth credentials present
 	SkipOnboarding   bool   // Skip onboarding questionnaire for new users
+	Audit            *audit.Forwarder // Optional forwarding of login attempts
 }
 
 // AuthHandler handles authentication
@@ -94,6 +96,7 @@ type AuthHandler struct {
 	githubToken      string
 	devMode          bool
 	skipOnboarding   bool
+	audit            *audit.Forwarder
 }
 
 // NewAuthHandler creates a new auth handler
@@ -149,6 +152,7 @@ func NewAuthHandler(s store.Store, cfg AuthConfig) *AuthHandler {
 		githubToken:      cfg.GitHubToken,
 		devMode:          cfg.DevMode,
 		skipOnboarding:   cfg.SkipOnboarding,
+		audit:            cfg.Audit,
 	}
 }
 
@@ -254,6 +258,7 @@ func (h *AuthHandler) devModeLogin(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Redirect(h.frontendURL+"/login?error=jwt_failed", fiber.StatusTemporaryRedirect)
 	}
+	h.auditLogin(c, user.GitHubLogin, audit.OutcomeSuccess, "dev_mode")
 
 	// Redirect to frontend with token
 	redirectURL := fmt.Sprintf("%s/auth/callback?token=%s&onboarded=%t", h.frontendURL, jwtToken, user.Onboarded)
@@ -272,6 +277,7 @@ func (h *AuthHandler) GitHubCallback(c *fiber.Ctx) error {
 	state := c.Query("state")
 	if state == "" || !validateAndConsumeOAuthState(state) {
 		log.Printf("[Auth] CSRF validation failed: invalid or expired state token")
+		h.auditLogin(c, "", audit.OutcomeFailure, "csrf_validation_failed")
 		return c.Redirect(h.frontendURL+"/login?error=csrf_validation_failed", fiber.StatusTemporaryRedirect)
 	}
 
@@ -279,6 +285,7 @@ func (h *AuthHandler) GitHubCallback(c *fiber.Ctx) error {
 	token, err := h.oauthConfig.Exchange(context.Background(), code)
 	if err != nil {
 		log.Printf("[Auth] Token exchange failed: %v", err)
+		h.auditLogin(c, "", audit.OutcomeFailure, "exchange_failed")
 		return c.Redirect(h.frontendURL+"/login?error=exchange_failed", fiber.StatusTemporaryRedirect)
 	}
 
@@ -324,12 +331,26 @@ func (h *AuthHandler) GitHubCallback(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Redirect(h.frontendURL+"/login?error=jwt_failed", fiber.StatusTemporaryRedirect)
 	}
+	h.auditLogin(c, user.GitHubLogin, audit.OutcomeSuccess, "github_oauth")
 
 	// Redirect to frontend with token
 	redirectURL := fmt.Sprintf("%s/auth/callback?token=%s&onboarded=%t", h.frontendURL, jwtToken, user.Onboarded)
 	return c.Redirect(redirectURL, fiber.StatusTemporaryRedirect)
 }
 
+// auditLogin forwards a login attempt to the audit log. detail is the login
+// method on success and the failure reason otherwise.
+func (h *AuthHandler) auditLogin(c *fiber.Ctx, login, outcome, detail string) {
+	h.audit.Record(audit.Entry{
+		Category: audit.CategoryLogin,
+		Action:   "login",
+		Actor:    login,
+		Outcome:  outcome,
+		SourceIP: c.IP(),
+		Details:  map[string]string{"detail": detail},
+	})
+}
+
 // RefreshToken refreshes the JWT token
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	// Get current user from context
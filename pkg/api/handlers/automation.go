@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultAutomationRunsLimit is how many audit records are returned by default
+	defaultAutomationRunsLimit = 100
+	// maxAutomationRunsLimit caps the audit records returned per request
+	maxAutomationRunsLimit = 1000
+)
+
+// AutomationHandler manages automation rules and their audit log. Rules can
+// restart workloads and cordon nodes, so changing or running them is limited
+// to admins.
+type AutomationHandler struct {
+	store  store.Store
+	engine *AutomationEngine
+}
+
+// NewAutomationHandler creates a new automation handler
+func NewAutomationHandler(s store.Store, engine *AutomationEngine) *AutomationHandler {
+	return &AutomationHandler{store: s, engine: engine}
+}
+
+// ListRules lists every automation rule
+func (h *AutomationHandler) ListRules(c *fiber.Ctx) error {
+	rules, err := h.store.ListAutomationRules()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list automation rules")
+	}
+	if rules == nil {
+		rules = []models.AutomationRule{}
+	}
+	return c.JSON(rules)
+}
+
+// GetRule gets a single automation rule by ID
+func (h *AutomationHandler) GetRule(c *fiber.Ctx) error {
+	rule, err := h.getRule(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(rule)
+}
+
+// CreateRule creates a new automation rule (admin only)
+func (h *AutomationHandler) CreateRule(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+
+	var rule models.AutomationRule
+	if err := c.BodyParser(&rule); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := rule.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	rule.ID = uuid.Nil
+	rule.UserID = middleware.GetUserID(c)
+	rule.LastFiredAt = nil
+
+	if err := h.store.CreateAutomationRule(&rule); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create automation rule")
+	}
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+// UpdateRule replaces an automation rule, including its enabled and dry-run
+// switches (admin only)
+func (h *AutomationHandler) UpdateRule(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	existing, err := h.getRule(c)
+	if err != nil {
+		return err
+	}
+
+	var rule models.AutomationRule
+	if err := c.BodyParser(&rule); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := rule.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	rule.ID = existing.ID
+	rule.UserID = existing.UserID
+	rule.CreatedAt = existing.CreatedAt
+	rule.LastFiredAt = existing.LastFiredAt
+
+	if err := h.store.UpdateAutomationRule(&rule); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update automation rule")
+	}
+	return c.JSON(rule)
+}
+
+// DeleteRule deletes an automation rule; its audit records are kept (admin only)
+func (h *AutomationHandler) DeleteRule(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	rule, err := h.getRule(c)
+	if err != nil {
+		return err
+	}
+	if err := h.store.DeleteAutomationRule(rule.ID); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete automation rule")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RunRule evaluates a rule now, even when disabled or in cooldown, and
+// returns the recorded runs. ?dryRun=true simulates the actions. (admin only)
+func (h *AutomationHandler) RunRule(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	rule, err := h.getRule(c)
+	if err != nil {
+		return err
+	}
+	if h.engine == nil || h.engine.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	runs, err := h.engine.RunRule(c.Context(), rule, AutomationRunOptions{
+		TriggeredBy:    middleware.GetGitHubLogin(c),
+		DryRun:         c.QueryBool("dryRun"),
+		IgnoreCooldown: true,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to run automation rule")
+	}
+	return c.JSON(fiber.Map{"runs": runs})
+}
+
+// ListRuns returns the audit log newest first, for one ?rule or all rules,
+// up to ?limit entries
+func (h *AutomationHandler) ListRuns(c *fiber.Ctx) error {
+	var ruleID *uuid.UUID
+	if v := c.Query("rule"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid rule ID")
+		}
+		ruleID = &id
+	}
+	limit := defaultAutomationRunsLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return fiber.NewError(fiber.StatusBadRequest, "limit must be a positive integer")
+		}
+		limit = min(parsed, maxAutomationRunsLimit)
+	}
+
+	runs, err := h.store.ListAutomationRuns(ruleID, limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list automation runs")
+	}
+	if runs == nil {
+		runs = []models.AutomationRun{}
+	}
+	return c.JSON(runs)
+}
+
+// getRule loads the rule named by the :id parameter
+func (h *AutomationHandler) getRule(c *fiber.Ctx) (*models.AutomationRule, error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid rule ID")
+	}
+	rule, err := h.store.GetAutomationRule(id)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to get automation rule")
+	}
+	if rule == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Automation rule not found")
+	}
+	return rule, nil
+}
+
+// requireAdmin rejects requests from users without the admin role
+func requireAdmin(c *fiber.Ctx, s store.Store) error {
+	user, err := s.GetUser(middleware.GetUserID(c))
+	if err != nil || user == nil || user.Role != string(models.UserRoleAdmin) {
+		return fiber.NewError(fiber.StatusForbidden, "Admin access required")
+	}
+	return nil
+}
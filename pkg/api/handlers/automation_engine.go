@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/agent"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/notifications"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultAutomationIntervalMs is the default interval between rule evaluations (1 minute)
+	defaultAutomationIntervalMs = 60_000
+	// automationTriggeredBySchedule marks runs started by the evaluation loop
+	automationTriggeredBySchedule = "schedule"
+	// maxAutomationMessageLen caps the AI analysis kept in the audit log
+	maxAutomationMessageLen = 4000
+)
+
+// AutomationRunOptions controls a single evaluation of a rule
+type AutomationRunOptions struct {
+	TriggeredBy    string // "schedule" or the user who ran the rule
+	DryRun         bool   // simulate actions even when the rule is live
+	IgnoreCooldown bool   // act on targets the rule acted on recently
+}
+
+// automationTarget is a pod or node that matched a rule's trigger
+type automationTarget struct {
+	cluster   string
+	namespace string
+	name      string
+	kind      string // Pod or Node
+	detail    string
+
+	// deployment owns a pod target; resolved only for rules that restart it
+	deployment string
+	resolveErr error
+}
+
+// key identifies the object the rule acts on: the owning Deployment when one
+// was resolved, so replicas of one Deployment share a cooldown
+func (t automationTarget) key() string {
+	if t.deployment != "" {
+		return t.cluster + "/" + t.namespace + "/Deployment/" + t.deployment
+	}
+	return t.cluster + "/" + t.namespace + "/" + t.kind + "/" + t.name
+}
+
+// AutomationEngine evaluates automation rules periodically and runs their
+// actions, recording every action in the audit log
+type AutomationEngine struct {
+	store         store.Store
+	k8sClient     *k8s.MultiClusterClient
+	notifications *notifications.Service
+	interval      time.Duration
+	stopCh        chan struct{}
+
+	mu        sync.Mutex
+	lastActed map[string]time.Time // rule ID + target -> last action
+}
+
+// NewAutomationEngine creates a new automation engine
+func NewAutomationEngine(s store.Store, k8sClient *k8s.MultiClusterClient, notificationService *notifications.Service) *AutomationEngine {
+	intervalMs := defaultAutomationIntervalMs
+	if envVal := os.Getenv("AUTOMATION_INTERVAL_MS"); envVal != "" {
+		if parsed, err := strconv.Atoi(envVal); err == nil && parsed > 0 {
+			intervalMs = parsed
+		}
+	}
+
+	return &AutomationEngine{
+		store:         s,
+		k8sClient:     k8sClient,
+		notifications: notificationService,
+		interval:      time.Duration(intervalMs) * time.Millisecond,
+		stopCh:        make(chan struct{}),
+		lastActed:     make(map[string]time.Time),
+	}
+}
+
+// Start begins the background evaluation loop
+func (e *AutomationEngine) Start() {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				e.evaluateAll()
+			case <-e.stopCh:
+				return
+			}
+		}
+	}()
+	log.Printf("Automation engine started (interval: %v)", e.interval)
+}
+
+// Stop signals the engine to stop
+func (e *AutomationEngine) Stop() {
+	close(e.stopCh)
+}
+
+// evaluateAll runs every enabled rule once
+func (e *AutomationEngine) evaluateAll() {
+	rules, err := e.store.ListAutomationRules()
+	if err != nil {
+		log.Printf("Automation engine: failed to list rules: %v", err)
+		return
+	}
+	for i := range rules {
+		if !rules[i].Enabled {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), e.interval/2)
+		if _, err := e.RunRule(ctx, &rules[i], AutomationRunOptions{TriggeredBy: automationTriggeredBySchedule}); err != nil {
+			log.Printf("Automation engine: rule %q failed: %v", rules[i].Name, err)
+		}
+		cancel()
+	}
+}
+
+// RunRule evaluates a rule's trigger and runs its actions on every matching
+// target outside its cooldown, returning the recorded runs
+func (e *AutomationEngine) RunRule(ctx context.Context, rule *models.AutomationRule, opts AutomationRunOptions) ([]models.AutomationRun, error) {
+	targets, err := e.findTargets(ctx, rule.Trigger)
+	if err != nil {
+		return nil, err
+	}
+	if restartsDeployment(rule) {
+		e.resolveDeployments(ctx, targets)
+	}
+	dryRun := rule.DryRun || opts.DryRun
+	cooldown := time.Duration(rule.CooldownMinutes) * time.Minute
+
+	runs := make([]models.AutomationRun, 0)
+	now := time.Now()
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		key := rule.ID.String() + "/" + target.key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		e.mu.Lock()
+		last, acted := e.lastActed[key]
+		e.mu.Unlock()
+		if acted && !opts.IgnoreCooldown && now.Sub(last) < cooldown {
+			continue
+		}
+
+		for _, action := range rule.Actions {
+			run := models.AutomationRun{
+				ID:          uuid.New(),
+				RuleID:      rule.ID,
+				RuleName:    rule.Name,
+				Trigger:     rule.Trigger.Type,
+				Action:      action.Type,
+				Cluster:     target.cluster,
+				Namespace:   target.namespace,
+				Target:      target.name,
+				DryRun:      dryRun,
+				TriggeredBy: opts.TriggeredBy,
+				CreatedAt:   time.Now(),
+			}
+			run.Status, run.Message = e.runAction(ctx, rule, action, target, dryRun)
+			if err := e.store.InsertAutomationRun(&run); err != nil {
+				log.Printf("Automation engine: failed to record run of %q: %v", rule.Name, err)
+			}
+			log.Printf("[Automation] rule=%q action=%s target=%s %s: %s", rule.Name, action.Type, target.key(), run.Status, firstLine(run.Message))
+			runs = append(runs, run)
+		}
+
+		// Dry runs don't start the cooldown so switching the rule live acts right away
+		if !dryRun {
+			e.mu.Lock()
+			e.lastActed[key] = now
+			e.mu.Unlock()
+		}
+	}
+
+	if len(runs) > 0 && !dryRun {
+		if err := e.store.SetAutomationRuleFired(rule.ID, now); err != nil {
+			log.Printf("Automation engine: failed to update rule %q: %v", rule.Name, err)
+		}
+	}
+	return runs, nil
+}
+
+// resolveDeployments records the Deployment owning each pod target
+func (e *AutomationEngine) resolveDeployments(ctx context.Context, targets []automationTarget) {
+	for i := range targets {
+		if targets[i].kind == "Pod" {
+			targets[i].deployment, targets[i].resolveErr = e.k8sClient.PodDeployment(ctx, targets[i].cluster, targets[i].namespace, targets[i].name)
+		}
+	}
+}
+
+// findTargets returns the pods or nodes currently matching a trigger
+func (e *AutomationEngine) findTargets(ctx context.Context, trigger models.AutomationTrigger) ([]automationTarget, error) {
+	if e.k8sClient == nil {
+		return nil, fmt.Errorf("no cluster access available")
+	}
+	var clusterNames []string
+	if trigger.Cluster != "" {
+		clusterNames = []string{trigger.Cluster}
+	} else {
+		clusters, _, err := e.k8sClient.HealthyClusters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	targets := make([]automationTarget, 0)
+	for _, cluster := range clusterNames {
+		clusterCtx, cancel := context.WithTimeout(ctx, mcpDefaultTimeout)
+		switch trigger.Type {
+		case models.TriggerPodCrashLoop:
+			issues, err := e.k8sClient.FindPodIssues(clusterCtx, cluster, trigger.Namespace)
+			if err != nil {
+				log.Printf("Automation engine: pod issues on %s: %v", cluster, err)
+				break
+			}
+			for _, issue := range issues {
+				if issue.Restarts <= trigger.Threshold || !hasCrashLoop(issue.Issues) {
+					continue
+				}
+				targets = append(targets, automationTarget{
+					cluster:   cluster,
+					namespace: issue.Namespace,
+					name:      issue.Name,
+					kind:      "Pod",
+					detail:    fmt.Sprintf("Pod %s/%s is in CrashLoopBackOff with %d restarts", issue.Namespace, issue.Name, issue.Restarts),
+				})
+			}
+		case models.TriggerGPUNodeUnhealthy:
+			nodes, err := e.k8sClient.GetGPUNodeHealth(clusterCtx, cluster)
+			if err != nil {
+				log.Printf("Automation engine: GPU node health on %s: %v", cluster, err)
+				break
+			}
+			for _, node := range nodes {
+				if node.Status != "unhealthy" {
+					continue
+				}
+				targets = append(targets, automationTarget{
+					cluster: cluster,
+					name:    node.NodeName,
+					kind:    "Node",
+					detail:  fmt.Sprintf("GPU node %s is unhealthy: %s", node.NodeName, strings.Join(node.Issues, "; ")),
+				})
+			}
+		}
+		cancel()
+	}
+	return targets, nil
+}
+
+// runAction performs (or simulates) one action on a target
+func (e *AutomationEngine) runAction(ctx context.Context, rule *models.AutomationRule, action models.AutomationAction, target automationTarget, dryRun bool) (models.AutomationRunStatus, string) {
+	switch action.Type {
+	case models.ActionRestartDeployment:
+		if target.resolveErr != nil {
+			return models.AutomationRunFailed, fmt.Sprintf("could not resolve the pod's deployment: %v", target.resolveErr)
+		}
+		deployment := target.deployment
+		if deployment == "" {
+			return models.AutomationRunSkipped, "pod is not managed by a Deployment"
+		}
+		if dryRun {
+			return models.AutomationRunDryRun, fmt.Sprintf("would restart deployment %s/%s", target.namespace, deployment)
+		}
+		if err := e.k8sClient.RestartDeployment(ctx, target.cluster, target.namespace, deployment); err != nil {
+			return models.AutomationRunFailed, err.Error()
+		}
+		return models.AutomationRunSucceeded, fmt.Sprintf("restarted deployment %s/%s", target.namespace, deployment)
+
+	case models.ActionCordonNode:
+		if dryRun {
+			return models.AutomationRunDryRun, fmt.Sprintf("would cordon node %s", target.name)
+		}
+		if err := e.k8sClient.CordonNode(ctx, target.cluster, target.name); err != nil {
+			return models.AutomationRunFailed, err.Error()
+		}
+		return models.AutomationRunSucceeded, fmt.Sprintf("cordoned node %s", target.name)
+
+	case models.ActionSlackAlert:
+		if dryRun {
+			return models.AutomationRunDryRun, "would send Slack alert: " + target.detail
+		}
+		if e.notifications == nil {
+			return models.AutomationRunFailed, "notifications are not available"
+		}
+		severity := notifications.SeverityWarning
+		if target.kind == "Node" {
+			severity = notifications.SeverityCritical
+		}
+		alert := notifications.Alert{
+			ID:           uuid.New().String(),
+			RuleID:       rule.ID.String(),
+			RuleName:     rule.Name,
+			Severity:     severity,
+			Status:       "firing",
+			Message:      target.detail,
+			Cluster:      target.cluster,
+			Namespace:    target.namespace,
+			Resource:     target.name,
+			ResourceKind: target.kind,
+			FiredAt:      time.Now(),
+		}
+		channel := notifications.NotificationChannel{
+			Type:    notifications.NotificationTypeSlack,
+			Enabled: true,
+			Config: map[string]interface{}{
+				"slackWebhookUrl": action.Config["slackWebhookUrl"],
+				"slackChannel":    action.Config["slackChannel"],
+			},
+		}
+		if err := e.notifications.SendAlertToChannels(alert, []notifications.NotificationChannel{channel}); err != nil {
+			return models.AutomationRunFailed, err.Error()
+		}
+		return models.AutomationRunSucceeded, "sent Slack alert"
+
+	case models.ActionAIAnalysis:
+		if dryRun {
+			return models.AutomationRunDryRun, "would request AI analysis: " + target.detail
+		}
+		provider, err := agent.GetRegistry().GetDefault()
+		if err != nil {
+			return models.AutomationRunFailed, "no AI provider available"
+		}
+		resp, err := provider.Chat(ctx, &agent.ChatRequest{
+			Prompt: fmt.Sprintf("%s (cluster %s). Explain the most likely root cause and the next steps to fix it.", target.detail, target.cluster),
+			SystemPrompt: "You are a Kubernetes SRE assistant. Answer concisely with a short diagnosis " +
+				"followed by concrete remediation steps.",
+		})
+		if err != nil {
+			return models.AutomationRunFailed, fmt.Sprintf("AI analysis failed: %v", err)
+		}
+		analysis := resp.Content
+		if len(analysis) > maxAutomationMessageLen {
+			analysis = analysis[:maxAutomationMessageLen]
+		}
+		return models.AutomationRunSucceeded, analysis
+	}
+	return models.AutomationRunSkipped, fmt.Sprintf("unknown action %q", action.Type)
+}
+
+// restartsDeployment reports whether any of a rule's actions restarts a Deployment
+func restartsDeployment(rule *models.AutomationRule) bool {
+	for _, action := range rule.Actions {
+		if action.Type == models.ActionRestartDeployment {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCrashLoop reports whether a pod's issues include CrashLoopBackOff of an app or init container
+func hasCrashLoop(issues []string) bool {
+	for _, issue := range issues {
+		if strings.HasSuffix(issue, "CrashLoopBackOff") {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+// automationTestStore records the audit log in memory
+type automationTestStore struct {
+	test.MockStore
+	runs  []models.AutomationRun
+	fired int
+}
+
+func (s *automationTestStore) InsertAutomationRun(run *models.AutomationRun) error {
+	s.runs = append(s.runs, *run)
+	return nil
+}
+
+func (s *automationTestStore) SetAutomationRuleFired(id uuid.UUID, _ time.Time) error {
+	s.fired++
+	return nil
+}
+
+func TestAutomationEngineRunRule(t *testing.T) {
+	controller := true
+	owned := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	crashing := func(name string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", OwnerReferences: owned("ReplicaSet", "api-5d8f")},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "api",
+				RestartCount: restarts,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		}
+	}
+	fakeClient := k8sfake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "api-5d8f", Namespace: "shop", OwnerReferences: owned("Deployment", "api")}},
+		crashing("api-5d8f-a", 12),
+		crashing("api-5d8f-b", 3),
+		crashing("api-5d8f-c", 9),
+	)
+	k8sClient, _ := k8s.NewMultiClusterClient("")
+	k8sClient.InjectClient("c1", fakeClient)
+	s := &automationTestStore{}
+	engine := NewAutomationEngine(s, k8sClient, nil)
+
+	rule := &models.AutomationRule{
+		ID:      uuid.New(),
+		Name:    "restart crashlooping api",
+		Enabled: true,
+		DryRun:  true,
+		Trigger: models.AutomationTrigger{Type: models.TriggerPodCrashLoop, Cluster: "c1", Namespace: "shop"},
+		Actions: []models.AutomationAction{{Type: models.ActionRestartDeployment}, {Type: models.ActionAIAnalysis}},
+	}
+	require.NoError(t, rule.Validate())
+	assert.Equal(t, models.DefaultCrashLoopRestarts, rule.Trigger.Threshold)
+	ctx := context.Background()
+
+	// Dry run: both actions are recorded once for the deployment owning the pods over
+	// the threshold, nothing changes
+	runs, err := engine.RunRule(ctx, rule, AutomationRunOptions{TriggeredBy: automationTriggeredBySchedule})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "api-5d8f-a", runs[0].Target)
+	assert.Equal(t, models.AutomationRunDryRun, runs[0].Status)
+	assert.Equal(t, "would restart deployment shop/api", runs[0].Message)
+	assert.Equal(t, models.AutomationRunDryRun, runs[1].Status)
+	assert.Len(t, s.runs, 2)
+	assert.Zero(t, s.fired)
+	deploy, _ := fakeClient.AppsV1().Deployments("shop").Get(ctx, "api", metav1.GetOptions{})
+	assert.Empty(t, deploy.Spec.Template.Annotations, "dry run must not restart the deployment")
+
+	// Live: the deployment restarts once, then the cooldown holds until ignored
+	rule.DryRun = false
+	rule.Actions = rule.Actions[:1]
+	runs, err = engine.RunRule(ctx, rule, AutomationRunOptions{TriggeredBy: automationTriggeredBySchedule})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.AutomationRunSucceeded, runs[0].Status)
+	assert.Equal(t, 1, s.fired)
+	deploy, _ = fakeClient.AppsV1().Deployments("shop").Get(ctx, "api", metav1.GetOptions{})
+	assert.NotEmpty(t, deploy.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+
+	runs, err = engine.RunRule(ctx, rule, AutomationRunOptions{TriggeredBy: automationTriggeredBySchedule})
+	require.NoError(t, err)
+	assert.Empty(t, runs, "cooldown should suppress a second restart")
+	runs, err = engine.RunRule(ctx, rule, AutomationRunOptions{TriggeredBy: "admin", IgnoreCooldown: true})
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	invalid := &models.AutomationRule{
+		Name:    "cordon on crash",
+		Trigger: models.AutomationTrigger{Type: models.TriggerPodCrashLoop},
+		Actions: []models.AutomationAction{{Type: models.ActionCordonNode}},
+	}
+	assert.True(t, errors.Is(invalid.Validate(), models.ErrInvalidAutomationRule))
+}
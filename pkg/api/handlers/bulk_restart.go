@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultRestartConcurrency is how many workloads restart at once by default
+	defaultRestartConcurrency = 2
+	// maxRestartConcurrency caps parallel restarts so a fleet-wide rotation
+	// never takes down many replicas at once
+	maxRestartConcurrency = 10
+	// maxRestartTargets caps the workloads one operation may touch
+	maxRestartTargets = 500
+	// maxRestartOperations is how many operations are kept for status queries
+	maxRestartOperations = 50
+)
+
+// Bulk restart operation and result states
+const (
+	restartStatusRunning   = "running"
+	restartStatusCompleted = "completed"
+	restartStatusAborted   = "aborted"
+	restartStatusPending   = "pending"
+	restartStatusRestarted = "restarted"
+	restartStatusFailed    = "failed"
+)
+
+// BulkRestartPreviewRequest selects the workloads of a bulk restart in the
+// given clusters, or in every healthy cluster
+type BulkRestartPreviewRequest struct {
+	k8s.RestartSelector
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// BulkRestartRequest starts a bulk restart of exactly the previewed targets
+type BulkRestartRequest struct {
+	Targets     []k8s.RestartTarget `json:"targets"`
+	Concurrency int                 `json:"concurrency,omitempty"`
+	MaxFailures int                 `json:"maxFailures,omitempty"` // abort after this many failures; 0 never aborts
+}
+
+// BulkRestartResult is the outcome for one target
+type BulkRestartResult struct {
+	k8s.RestartTarget
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkRestartOperation tracks a running or finished bulk restart
+type BulkRestartOperation struct {
+	ID          string              `json:"id"`
+	Status      string              `json:"status"`
+	Reason      string              `json:"reason,omitempty"`
+	StartedBy   string              `json:"startedBy"`
+	StartedAt   time.Time           `json:"startedAt"`
+	FinishedAt  *time.Time          `json:"finishedAt,omitempty"`
+	Concurrency int                 `json:"concurrency"`
+	MaxFailures int                 `json:"maxFailures,omitempty"`
+	Results     []BulkRestartResult `json:"results"`
+
+	cancel context.CancelFunc
+}
+
+// BulkRestartHandler runs guarded fleet-wide rollout restarts: callers preview
+// exactly which workloads are touched, then restart those with bounded
+// concurrency and can abort at any time. Restarts are limited to admins.
+type BulkRestartHandler struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+
+	mu         sync.Mutex
+	operations map[string]*BulkRestartOperation
+}
+
+// NewBulkRestartHandler creates a new bulk restart handler
+func NewBulkRestartHandler(s store.Store, k8sClient *k8s.MultiClusterClient) *BulkRestartHandler {
+	return &BulkRestartHandler{store: s, k8sClient: k8sClient, operations: make(map[string]*BulkRestartOperation)}
+}
+
+// Preview lists the workloads a bulk restart would touch in each cluster
+// POST /api/maintenance/restart/preview
+func (h *BulkRestartHandler) Preview(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+	var req BulkRestartPreviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := req.RestartSelector.Validate(); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	clusterNames := req.Clusters
+	if len(clusterNames) == 0 {
+		clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	targets := make([]k8s.RestartTarget, 0)
+	failed := make([]string, 0)
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			found, err := h.k8sClient.PreviewRestart(ctx, clusterName, req.RestartSelector)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("[BulkRestart] preview %s: %v", clusterName, err)
+				failed = append(failed, clusterName)
+				return
+			}
+			targets = append(targets, found...)
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+
+	k8s.SortRestartTargets(targets)
+	sort.Strings(failed)
+	return c.JSON(fiber.Map{
+		"targets":        targets,
+		"total":          len(targets),
+		"failedClusters": failed,
+	})
+}
+
+// Start restarts the given targets in the background and returns the
+// operation to poll (admin only)
+// POST /api/maintenance/restart
+func (h *BulkRestartHandler) Start(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+	var req BulkRestartRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if err := validateRestartRequest(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	op := &BulkRestartOperation{
+		ID:          uuid.New().String(),
+		Status:      restartStatusRunning,
+		StartedBy:   middleware.GetGitHubLogin(c),
+		StartedAt:   time.Now(),
+		Concurrency: req.Concurrency,
+		MaxFailures: req.MaxFailures,
+		Results:     make([]BulkRestartResult, len(req.Targets)),
+		cancel:      cancel,
+	}
+	for i, t := range req.Targets {
+		op.Results[i] = BulkRestartResult{RestartTarget: t, Status: restartStatusPending}
+	}
+	h.mu.Lock()
+	h.pruneOperationsLocked()
+	h.operations[op.ID] = op
+	snapshot := op.snapshot()
+	h.mu.Unlock()
+
+	log.Printf("[BulkRestart] %s started by %s: %d workloads, concurrency %d", op.ID, op.StartedBy, len(req.Targets), req.Concurrency)
+	go h.run(ctx, op)
+	return c.Status(fiber.StatusAccepted).JSON(snapshot)
+}
+
+// GetOperation returns the progress of a bulk restart
+// GET /api/maintenance/restart/:id
+func (h *BulkRestartHandler) GetOperation(c *fiber.Ctx) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	op, ok := h.operations[c.Params("id")]
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Operation not found"})
+	}
+	return c.JSON(op.snapshot())
+}
+
+// ListOperations returns recent bulk restarts, newest first
+// GET /api/maintenance/restart
+func (h *BulkRestartHandler) ListOperations(c *fiber.Ctx) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ops := make([]BulkRestartOperation, 0, len(h.operations))
+	for _, op := range h.operations {
+		ops = append(ops, op.snapshot())
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].StartedAt.After(ops[j].StartedAt) })
+	return c.JSON(fiber.Map{"operations": ops})
+}
+
+// Abort stops a running bulk restart; restarts already issued are not undone
+// and pending targets are skipped (admin only)
+// POST /api/maintenance/restart/:id/abort
+func (h *BulkRestartHandler) Abort(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	op, ok := h.operations[c.Params("id")]
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Operation not found"})
+	}
+	if op.Status == restartStatusRunning && op.Reason == "" {
+		op.Reason = "aborted by " + middleware.GetGitHubLogin(c)
+		op.cancel()
+	}
+	return c.JSON(op.snapshot())
+}
+
+// run restarts the operation's targets with bounded concurrency until done,
+// aborted, or MaxFailures is reached
+func (h *BulkRestartHandler) run(ctx context.Context, op *BulkRestartOperation) {
+	defer op.cancel()
+	sem := make(chan struct{}, op.Concurrency)
+	var wg sync.WaitGroup
+	failures := 0
+
+	for i := range op.Results {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			h.mu.Lock()
+			target := op.Results[i].RestartTarget
+			h.mu.Unlock()
+
+			restartCtx, cancel := context.WithTimeout(ctx, mcpDefaultTimeout)
+			err := h.k8sClient.RestartWorkload(restartCtx, target.Cluster, target)
+			cancel()
+
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if err != nil {
+				op.Results[i].Status = restartStatusFailed
+				op.Results[i].Error = err.Error()
+				failures++
+				if op.MaxFailures > 0 && failures >= op.MaxFailures && op.Reason == "" {
+					op.Reason = fmt.Sprintf("aborted after %d failures", failures)
+					op.cancel()
+				}
+				return
+			}
+			op.Results[i].Status = restartStatusRestarted
+		}(i)
+	}
+	wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	op.FinishedAt = &now
+	op.Status = restartStatusCompleted
+	for i := range op.Results {
+		if op.Results[i].Status == restartStatusPending {
+			op.Results[i].Status = restartStatusAborted
+			op.Status = restartStatusAborted
+		}
+	}
+	log.Printf("[BulkRestart] %s %s (%d failures)", op.ID, op.Status, failures)
+}
+
+// pruneOperationsLocked drops the oldest finished operations beyond the limit
+func (h *BulkRestartHandler) pruneOperationsLocked() {
+	if len(h.operations) < maxRestartOperations {
+		return
+	}
+	finished := make([]*BulkRestartOperation, 0, len(h.operations))
+	for _, op := range h.operations {
+		if op.FinishedAt != nil {
+			finished = append(finished, op)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].StartedAt.Before(finished[j].StartedAt) })
+	for i := 0; i < len(finished) && len(h.operations) >= maxRestartOperations; i++ {
+		delete(h.operations, finished[i].ID)
+	}
+}
+
+// snapshot copies the operation for serialization; callers hold the handler lock
+func (op *BulkRestartOperation) snapshot() BulkRestartOperation {
+	cp := *op
+	cp.Results = append([]BulkRestartResult(nil), op.Results...)
+	cp.cancel = nil
+	return cp
+}
+
+// validateRestartRequest checks the targets and fills in defaults
+func validateRestartRequest(req *BulkRestartRequest) error {
+	if len(req.Targets) == 0 {
+		return errors.New("targets are required; preview the restart first")
+	}
+	if len(req.Targets) > maxRestartTargets {
+		return fmt.Errorf("at most %d workloads can be restarted at once", maxRestartTargets)
+	}
+	seen := make(map[k8s.RestartTarget]bool, len(req.Targets))
+	for i := range req.Targets {
+		t := req.Targets[i]
+		if t.Cluster == "" || t.Namespace == "" || t.Name == "" {
+			return errors.New("every target needs cluster, namespace and name")
+		}
+		if t.Kind != "Deployment" && t.Kind != "StatefulSet" && t.Kind != "DaemonSet" {
+			return fmt.Errorf("cannot restart kind %q", t.Kind)
+		}
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		t.Replicas = 0
+		if seen[t] {
+			return fmt.Errorf("duplicate target %s/%s/%s", t.Cluster, t.Namespace, t.Name)
+		}
+		seen[t] = true
+	}
+	if req.Concurrency == 0 {
+		req.Concurrency = defaultRestartConcurrency
+	}
+	if req.Concurrency < 1 || req.Concurrency > maxRestartConcurrency {
+		return fmt.Errorf("concurrency must be between 1 and %d", maxRestartConcurrency)
+	}
+	if req.MaxFailures < 0 {
+		return errors.New("maxFailures must not be negative")
+	}
+	return nil
+}
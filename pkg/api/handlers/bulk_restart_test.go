@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+func TestBulkRestartRun(t *testing.T) {
+	k8sClient, _ := k8s.NewMultiClusterClient("")
+	k8sClient.InjectClient("c1", k8sfake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}},
+	))
+	h := NewBulkRestartHandler(nil, k8sClient)
+	target := func(name string) k8s.RestartTarget {
+		return k8s.RestartTarget{Cluster: "c1", Namespace: "shop", Kind: "Deployment", Name: name}
+	}
+	start := func(req BulkRestartRequest) *BulkRestartOperation {
+		require.NoError(t, validateRestartRequest(&req))
+		ctx, cancel := context.WithCancel(context.Background())
+		op := &BulkRestartOperation{ID: "op", Status: restartStatusRunning, StartedAt: time.Now(),
+			Concurrency: req.Concurrency, MaxFailures: req.MaxFailures, cancel: cancel}
+		for _, t := range req.Targets {
+			op.Results = append(op.Results, BulkRestartResult{RestartTarget: t, Status: restartStatusPending})
+		}
+		h.run(ctx, op)
+		return op
+	}
+
+	op := start(BulkRestartRequest{Targets: []k8s.RestartTarget{target("api"), target("web")}})
+	assert.Equal(t, restartStatusCompleted, op.Status)
+	assert.Equal(t, 2, op.Concurrency)
+	assert.Equal(t, restartStatusRestarted, op.Results[0].Status)
+	assert.Equal(t, restartStatusRestarted, op.Results[1].Status)
+
+	// Concurrency 1 restarts in order, so the missing deployment trips MaxFailures first
+	op = start(BulkRestartRequest{Targets: []k8s.RestartTarget{target("gone"), target("api"), target("web")}, Concurrency: 1, MaxFailures: 1})
+	assert.Equal(t, restartStatusAborted, op.Status)
+	assert.Equal(t, "aborted after 1 failures", op.Reason)
+	assert.Equal(t, restartStatusFailed, op.Results[0].Status)
+	assert.Equal(t, restartStatusAborted, op.Results[2].Status)
+
+	bad := BulkRestartRequest{Targets: []k8s.RestartTarget{target("api"), target("api")}}
+	assert.Error(t, validateRestartRequest(&bad), "duplicate targets should be refused")
+	bad = BulkRestartRequest{Targets: []k8s.RestartTarget{target("api")}, Concurrency: maxRestartConcurrency + 1}
+	assert.Error(t, validateRestartRequest(&bad))
+	bad = BulkRestartRequest{Targets: []k8s.RestartTarget{{Cluster: "c1", Namespace: "kube-system", Kind: "DaemonSet", Name: "kube-proxy"}}}
+	assert.ErrorIs(t, validateRestartRequest(&bad), k8s.ErrInvalidRestart, "system namespace workloads the preview refuses should be refused")
+	named := BulkRestartRequest{Targets: []k8s.RestartTarget{{Cluster: "c1", Namespace: "kube-system", Kind: "Deployment", Name: "coredns"}}}
+	assert.NoError(t, validateRestartRequest(&named))
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultChangesWindow is how far back /changes compares by default
+	defaultChangesWindow = 24 * time.Hour
+	// maxChangesWindow matches the cluster state snapshot retention period
+	maxChangesWindow = 90 * 24 * time.Hour
+)
+
+// ChangesHandler reports what changed in each cluster since an earlier point
+// in time by diffing the live state against recorded snapshots
+type ChangesHandler struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewChangesHandler creates a new changes handler
+func NewChangesHandler(s store.Store, k8sClient *k8s.MultiClusterClient) *ChangesHandler {
+	return &ChangesHandler{store: s, k8sClient: k8sClient}
+}
+
+// GetChanges compares every healthy cluster (or ?cluster) with its latest
+// snapshot at or before ?since, a duration like 24h or 7d, or an RFC 3339
+// time (default 24h ago): new unhealthy nodes, newly failing deployments, GPU
+// count changes and version bumps
+func (h *ChangesHandler) GetChanges(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	since, err := parseChangesSince(c.Query("since"), time.Now())
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var clusterNames []string
+	if cluster := c.Query("cluster"); cluster != "" {
+		clusterNames = []string{cluster}
+	} else {
+		clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	results := make([]k8s.ClusterChanges, 0, len(clusterNames))
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			changes := h.clusterChanges(ctx, clusterName, since)
+			mu.Lock()
+			results = append(results, changes)
+			mu.Unlock()
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Slice(results, func(i, j int) bool { return results[i].Cluster < results[j].Cluster })
+	changed := 0
+	for _, r := range results {
+		if r.Changed {
+			changed++
+		}
+	}
+	return c.JSON(fiber.Map{
+		"since":           since,
+		"clusters":        results,
+		"changedClusters": changed,
+	})
+}
+
+// clusterChanges diffs a cluster's live state against its snapshot at since
+func (h *ChangesHandler) clusterChanges(ctx context.Context, cluster string, since time.Time) k8s.ClusterChanges {
+	failed := func(msg string) k8s.ClusterChanges {
+		return k8s.ClusterChanges{Cluster: cluster, Since: since, Now: time.Now(), Error: msg}
+	}
+
+	snapshot, err := h.store.GetClusterStateSnapshotAt(cluster, since)
+	if err != nil {
+		log.Printf("Changes: failed to load snapshot for %s: %v", cluster, err)
+		return failed("failed to load snapshot")
+	}
+	if snapshot == nil {
+		return failed("no snapshot recorded at or before " + since.Format(time.RFC3339))
+	}
+	var before k8s.ClusterState
+	if err := json.Unmarshal(snapshot.State, &before); err != nil {
+		log.Printf("Changes: invalid snapshot %s for %s: %v", snapshot.ID, cluster, err)
+		return failed("invalid snapshot")
+	}
+
+	after, err := h.k8sClient.CaptureClusterState(ctx, cluster)
+	if err != nil {
+		log.Printf("Changes: failed to capture %s: %v", cluster, err)
+		return failed("cluster unreachable")
+	}
+	return k8s.DiffClusterState(&before, after)
+}
+
+// parseChangesSince reads a duration (24h, 90m, 7d) or an RFC 3339 time
+func parseChangesSince(v string, now time.Time) (time.Time, error) {
+	if v == "" {
+		return now.Add(-defaultChangesWindow), nil
+	}
+	var window time.Duration
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid since %q", v)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else if d, err := time.ParseDuration(v); err == nil {
+		window = d
+	} else if t, err := time.Parse(time.RFC3339, v); err == nil {
+		window = now.Sub(t)
+	} else {
+		return time.Time{}, fmt.Errorf("since must be a duration like 24h or 7d, or an RFC 3339 time")
+	}
+	if window <= 0 || window > maxChangesWindow {
+		return time.Time{}, fmt.Errorf("since must be within the last 90 days")
+	}
+	return now.Add(-window), nil
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+// changesTestStore returns a fixed baseline snapshot for every cluster
+type changesTestStore struct {
+	test.MockStore
+	state *k8s.ClusterState
+}
+
+func (s *changesTestStore) GetClusterStateSnapshotAt(cluster string, at time.Time) (*models.ClusterStateSnapshot, error) {
+	if s.state == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ClusterStateSnapshot{ID: "snap-1", Cluster: cluster, Timestamp: s.state.CapturedAt, State: data}, nil
+}
+
+func TestGetChanges(t *testing.T) {
+	env := setupTestEnv(t)
+	client, err := env.K8sClient.GetClient("test-cluster")
+	require.NoError(t, err)
+	replicas := int32(2)
+	_, err = client.AppsV1().Deployments("shop").Create(context.Background(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	store := &changesTestStore{state: &k8s.ClusterState{
+		Cluster:            "test-cluster",
+		CapturedAt:         time.Now().Add(-25 * time.Hour),
+		Nodes:              map[string]k8s.NodeState{},
+		FailingDeployments: map[string]string{},
+	}}
+	handler := NewChangesHandler(store, env.K8sClient)
+	env.App.Get("/api/changes", handler.GetChanges)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/changes?cluster=test-cluster&since=1d", nil)
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body struct {
+		Clusters        []k8s.ClusterChanges `json:"clusters"`
+		ChangedClusters int                  `json:"changedClusters"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Clusters, 1)
+	assert.Empty(t, body.Clusters[0].Error)
+	assert.Equal(t, 1, body.ChangedClusters)
+	require.Len(t, body.Clusters[0].NewlyFailingDeployments, 1)
+	assert.Equal(t, "api", body.Clusters[0].NewlyFailingDeployments[0].Name)
+
+	store.state = nil
+	req = httptest.NewRequest(http.MethodGet, "/api/changes?cluster=test-cluster", nil)
+	resp, err = env.App.Test(req, 5000)
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Clusters, 1)
+	assert.Contains(t, body.Clusters[0].Error, "no snapshot")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/changes?since=yesterday", nil)
+	resp, err = env.App.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestParseChangesSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	for input, want := range map[string]time.Time{
+		"":                     now.Add(-24 * time.Hour),
+		"90m":                  now.Add(-90 * time.Minute),
+		"7d":                   now.AddDate(0, 0, -7),
+		"2026-03-09T17:30:00Z": time.Date(2026, 3, 9, 17, 30, 0, 0, time.UTC),
+	} {
+		got, err := parseChangesSince(input, now)
+		require.NoError(t, err, input)
+		assert.True(t, got.Equal(want), "%q: expected %v, got %v", input, want, got)
+	}
+	for _, input := range []string{"-1h", "100d", "soon"} {
+		_, err := parseChangesSince(input, now)
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// DataProtectionHandler reports volume snapshots and Velero backups
+type DataProtectionHandler struct {
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewDataProtectionHandler creates a new data protection handler
+func NewDataProtectionHandler(k8sClient *k8s.MultiClusterClient) *DataProtectionHandler {
+	return &DataProtectionHandler{k8sClient: k8sClient}
+}
+
+// GetDataProtection returns VolumeSnapshots, VolumeSnapshotContents and
+// Velero Backups, Restores and Schedules per cluster, for ?cluster or all
+// healthy clusters, optionally limited to ?namespace
+// GET /api/data-protection
+func (h *DataProtectionHandler) GetDataProtection(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+	namespace := c.Query("namespace")
+
+	if cluster := c.Query("cluster"); cluster != "" {
+		dp, err := h.k8sClient.GetDataProtection(c.Context(), cluster, namespace)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"clusters": []*k8s.DataProtection{dp}})
+	}
+
+	clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+	if err != nil {
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	results := make([]*k8s.DataProtection, 0, len(clusters))
+	for _, cl := range clusters {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			dp, err := h.k8sClient.GetDataProtection(ctx, clusterName, namespace)
+			if err != nil {
+				log.Printf("[DataProtection] %s: %v", clusterName, err)
+				return
+			}
+			mu.Lock()
+			results = append(results, dp)
+			mu.Unlock()
+		}(cl.Name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Cluster < results[j].Cluster
+	})
+	return c.JSON(fiber.Map{"clusters": results})
+}
@@ -161,6 +161,70 @@ func getDemoSecurityIssues() []k8s.SecurityIssue {
 	}
 }
 
+// Demo Pod Security Standards levels
+func getDemoPodSecurityLevels() []k8s.PodSecurityLevel {
+	return []k8s.PodSecurityLevel{
+		{Name: "frontend-7d8f9b6c5d-x2k4m", Namespace: "production", Cluster: "eks-prod-us-east-1", OwnerKind: "ReplicaSet", OwnerName: "frontend-7d8f9b6c5d", Level: k8s.PSSBaseline, Violations: []string{"container frontend must set runAsNonRoot=true", "container frontend must drop ALL capabilities"}},
+		{Name: "api-server-5c9d8f7b6-p4q2r", Namespace: "production", Cluster: "eks-prod-us-east-1", OwnerKind: "ReplicaSet", OwnerName: "api-server-5c9d8f7b6", Level: k8s.PSSRestricted},
+		{Name: "batch-job-x7k2p", Namespace: "batch", Cluster: "gke-staging", OwnerKind: "Job", OwnerName: "batch-job", Level: k8s.PSSPrivileged, Violations: []string{"container worker is privileged", "volume docker-sock uses hostPath"}},
+	}
+}
+
+// Demo OS/architecture scheduling issues
+func getDemoPlatformIssues() []k8s.PlatformIssue {
+	return []k8s.PlatformIssue{
+		{Name: "legacy-api-6f7c9d8b4-k2m9p", Namespace: "production", Cluster: "eks-prod-us-east-1", Node: "ip-10-0-3-41.ec2.internal", NodePlatform: "linux/arm64", Issue: "Image not built for node platform", Severity: "high", Details: "container legacy-api: exec /app/server: exec format error"},
+		{Name: "frontend-7d8f9b6c5d-x2k4m", Namespace: "production", Cluster: "eks-prod-us-east-1", Node: "ip-10-0-1-12.ec2.internal", NodePlatform: "linux/amd64", Issue: "Pod not pinned to an OS or architecture", Severity: "medium", Details: "Cluster mixes linux/amd64, linux/arm64; set a kubernetes.io/os or kubernetes.io/arch node selector"},
+	}
+}
+
+// Demo volume usage
+func getDemoVolumeUsage() []k8s.VolumeUsage {
+	return []k8s.VolumeUsage{
+		{PVC: "prometheus-data", Namespace: "monitoring", Cluster: "eks-prod-us-east-1", Pod: "prometheus-0", Node: "ip-10-0-2-17.ec2.internal", CapacityBytes: 107374182400, UsedBytes: 99857989632, AvailableBytes: 7516192768, UsedPercent: 93, InodesUsedPercent: 4.2, Issue: "Volume 93% full", Severity: "high"},
+		{PVC: "postgres-data", Namespace: "production", Cluster: "eks-prod-us-east-1", Pod: "postgres-0", Node: "ip-10-0-1-12.ec2.internal", CapacityBytes: 53687091200, UsedBytes: 44023414784, AvailableBytes: 9663676416, UsedPercent: 82, InodesUsedPercent: 1.8, Issue: "Volume 82% full", Severity: "medium"},
+		{PVC: "redis-data", Namespace: "production", Cluster: "gke-staging", Pod: "redis-0", Node: "gke-staging-pool-1-a3f2", CapacityBytes: 10737418240, UsedBytes: 2147483648, AvailableBytes: 8589934592, UsedPercent: 20, InodesUsedPercent: 0.5},
+	}
+}
+
+// Demo StorageClasses
+func getDemoStorageClasses() []k8s.StorageClass {
+	return []k8s.StorageClass{
+		{Name: "gp3", Cluster: "eks-prod-us-east-1", Provisioner: "ebs.csi.aws.com", ReclaimPolicy: "Delete", VolumeBindingMode: "WaitForFirstConsumer", AllowVolumeExpansion: true, IsDefault: true, Parameters: map[string]string{"type": "gp3"}, Age: "120d"},
+		{Name: "gp2", Cluster: "eks-prod-us-east-1", Provisioner: "kubernetes.io/aws-ebs", ReclaimPolicy: "Delete", VolumeBindingMode: "WaitForFirstConsumer", Parameters: map[string]string{"type": "gp2"}, Age: "365d"},
+		{Name: "standard-rwo", Cluster: "gke-staging", Provisioner: "pd.csi.storage.gke.io", ReclaimPolicy: "Delete", VolumeBindingMode: "WaitForFirstConsumer", AllowVolumeExpansion: true, IsDefault: true, Age: "90d"},
+		{Name: "ceph-block", Cluster: "openshift-prod", Provisioner: "rook-ceph.rbd.csi.ceph.com", ReclaimPolicy: "Retain", VolumeBindingMode: "Immediate", AllowVolumeExpansion: true, Age: "45d"},
+	}
+}
+
+// Demo storage issues
+func getDemoStorageIssues() []k8s.StorageIssue {
+	return []k8s.StorageIssue{
+		{Kind: "PersistentVolumeClaim", Name: "prometheus-data", Namespace: "monitoring", Cluster: "eks-prod-us-east-1", Issue: "Volume 93% full", Severity: "high", Details: "99857989632 of 107374182400 bytes used, mounted by prometheus-0 on ip-10-0-2-17.ec2.internal"},
+		{Kind: "StorageClass", Cluster: "openshift-prod", Issue: "No default StorageClass", Severity: "medium", Details: "PVCs without a storageClassName stay Pending; annotate one class with storageclass.kubernetes.io/is-default-class=true"},
+		{Kind: "StorageClass", Name: "ceph-block", Cluster: "openshift-prod", Issue: "CSI driver not installed", Severity: "medium", Details: "No CSIDriver or node registration for provisioner rook-ceph.rbd.csi.ceph.com; PVCs using this class cannot be provisioned"},
+	}
+}
+
+// Demo PriorityClasses
+func getDemoPriorityClasses() []k8s.PriorityClass {
+	return []k8s.PriorityClass{
+		{Name: "system-node-critical", Cluster: "eks-prod-us-east-1", Value: 2000001000, PreemptionPolicy: "PreemptLowerPriority", Description: "Used for system critical pods that must not be moved from their current node.", Pods: 12, Age: "365d"},
+		{Name: "system-cluster-critical", Cluster: "eks-prod-us-east-1", Value: 2000000000, PreemptionPolicy: "PreemptLowerPriority", Description: "Used for system critical pods that must run in the cluster, but can be moved to another node if necessary.", Pods: 8, Age: "365d"},
+		{Name: "inference-high", Cluster: "eks-prod-us-east-1", Value: 100000, PreemptionPolicy: "PreemptLowerPriority", Description: "Production inference serving", Pods: 6, Age: "60d"},
+		{Name: "training-batch", Cluster: "eks-prod-us-east-1", Value: 1000, GlobalDefault: true, PreemptionPolicy: "Never", Description: "Preemptible training jobs", Pods: 14, Age: "60d"},
+	}
+}
+
+// Demo preemptions
+func getDemoPreemptions() []k8s.Preemption {
+	highPriority, batchPriority := int32(100000), int32(1000)
+	return []k8s.Preemption{
+		{Cluster: "eks-prod-us-east-1", Namespace: "ml-training", Victim: "llama-finetune-worker-3", VictimPriorityClass: "training-batch", VictimPriority: &batchPriority, Node: "ip-10-0-4-88.ec2.internal", Preemptor: "ml-serving/vllm-inference-7c9d8f6b5-q4w2e", PreemptorPriorityClass: "inference-high", PreemptorPriority: &highPriority, Time: time.Now().Add(-12 * time.Minute).UTC().Format(time.RFC3339), Message: "Preempted by pod 8f14e45f-ceea-467f-a0e6-7b2b9c1d3e5a on node ip-10-0-4-88.ec2.internal"},
+		{Cluster: "eks-prod-us-east-1", Namespace: "ml-training", Victim: "llama-finetune-worker-1", Node: "ip-10-0-4-91.ec2.internal", Preemptor: "ml-serving/vllm-inference-7c9d8f6b5-z8x7c", PreemptorPriorityClass: "inference-high", PreemptorPriority: &highPriority, Time: time.Now().Add(-41 * time.Minute).UTC().Format(time.RFC3339), Message: "Preempted by pod 3c59dc04-8e3b-4c2f-9a1d-6f0e2b7a4d19 on node ip-10-0-4-91.ec2.internal"},
+	}
+}
+
 // Demo jobs
 func getDemoJobs() []k8s.Job {
 	return []k8s.Job{
@@ -328,6 +392,29 @@ func getDemoNVIDIAOperatorStatus() []*k8s.NVIDIAOperatorStatus {
 	}
 }
 
+// Demo AMD GPU Operator Status
+func getDemoAMDGPUOperatorStatus() []*k8s.AMDGPUOperatorStatus {
+	return []*k8s.AMDGPUOperatorStatus{
+		{
+			Cluster: "openshift-prod",
+			DeviceConfigs: []k8s.AMDDeviceConfigState{
+				{
+					Name:          "gpu-operator",
+					Namespace:     "kube-amd-gpu",
+					ROCmVersion:   "6.2.2",
+					DriverEnabled: true,
+					Ready:         true,
+					Components: []k8s.OperatorComponent{
+						{Name: "driver", Status: "ready"},
+						{Name: "devicePlugin", Status: "ready"},
+						{Name: "metricsExporter", Status: "ready"},
+					},
+				},
+			},
+		},
+	}
+}
+
 // Demo pod logs
 func getDemoPodLogs() string {
 	return `2024-01-15T10:30:00Z INFO  Starting application...
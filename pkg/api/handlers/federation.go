@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// federationRequestTimeout bounds each request to a remote agent
+	federationRequestTimeout = 15 * time.Second
+	// federationHealthTimeout bounds a remote agent health probe
+	federationHealthTimeout = 5 * time.Second
+	// maxFederationResponseBytes caps the body read from a remote agent
+	maxFederationResponseBytes = 32 << 20
+)
+
+// Remote agent health states
+const (
+	RemoteAgentHealthy      = "healthy"
+	RemoteAgentUnreachable  = "unreachable"
+	RemoteAgentUnauthorized = "unauthorized"
+	RemoteAgentError        = "error"
+)
+
+// errRemoteAgentUnauthorized is returned when an agent rejects the registered token
+var errRemoteAgentUnauthorized = errors.New("remote agent rejected the token")
+
+// federatedEndpoints are the read-only kc-agent endpoints routed through
+// /federation/proxy. Secrets, settings and kubeconfig endpoints stay local to
+// each agent.
+var federatedEndpoints = map[string]bool{
+	"nodes":          true,
+	"gpu-nodes":      true,
+	"pods":           true,
+	"events":         true,
+	"namespaces":     true,
+	"deployments":    true,
+	"replicasets":    true,
+	"statefulsets":   true,
+	"daemonsets":     true,
+	"cronjobs":       true,
+	"jobs":           true,
+	"services":       true,
+	"ingresses":      true,
+	"pvcs":           true,
+	"hpas":           true,
+	"pdbs":           true,
+	"versions":       true,
+	"cluster-health": true,
+	"summary":        true,
+}
+
+// RemoteAgentStatus is a registered agent with the result of its last probe
+type RemoteAgentStatus struct {
+	models.RemoteAgent
+	Status    string    `json:"status"`
+	Version   string    `json:"version,omitempty"`
+	Clusters  int       `json:"clusters"`
+	LatencyMs int64     `json:"latencyMs"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// FederatedCluster is a kubeconfig context of a remote agent. Name is
+// "{agent}/{context}" so clusters of different agents never collide.
+type FederatedCluster struct {
+	protocol.ClusterInfo
+	Agent   string    `json:"agent"`
+	AgentID uuid.UUID `json:"agentId"`
+}
+
+// FederatedResult is one agent's response to a fanned-out request
+type FederatedResult struct {
+	Agent  string          `json:"agent"`
+	Status int             `json:"status"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// FederationHandler registers remote kc-agents (laptops, jump hosts) and
+// serves their clusters and data behind the console API
+type FederationHandler struct {
+	store  store.Store
+	client *http.Client
+}
+
+// NewFederationHandler creates a new federation handler
+func NewFederationHandler(s store.Store) *FederationHandler {
+	return &FederationHandler{store: s, client: &http.Client{}}
+}
+
+// ListAgents lists the registered agents with their current health
+func (h *FederationHandler) ListAgents(c *fiber.Ctx) error {
+	agents, err := h.store.ListRemoteAgents()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list remote agents")
+	}
+	statuses := make([]RemoteAgentStatus, len(agents))
+	var wg sync.WaitGroup
+	for i := range agents {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i] = h.probe(c.Context(), agents[i])
+		}(i)
+	}
+	wg.Wait()
+	return c.JSON(statuses)
+}
+
+// RegisterAgent registers a remote agent by name, URL and optional token (admin only)
+func (h *FederationHandler) RegisterAgent(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	var agent models.RemoteAgent
+	if err := c.BodyParser(&agent); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := agent.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	existing, err := h.store.ListRemoteAgents()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list remote agents")
+	}
+	for _, a := range existing {
+		if a.Name == agent.Name {
+			return fiber.NewError(fiber.StatusConflict, "A remote agent with this name already exists")
+		}
+	}
+	agent.ID = uuid.Nil
+	agent.CreatedBy = middleware.GetUserID(c)
+	if err := h.store.CreateRemoteAgent(&agent); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to register remote agent")
+	}
+	return c.Status(fiber.StatusCreated).JSON(h.probe(c.Context(), agent))
+}
+
+// DeleteAgent unregisters a remote agent (admin only)
+func (h *FederationHandler) DeleteAgent(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid agent ID")
+	}
+	agent, err := h.store.GetRemoteAgent(id)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get remote agent")
+	}
+	if agent == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Remote agent not found")
+	}
+	if err := h.store.DeleteRemoteAgent(id); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete remote agent")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListClusters aggregates the cluster lists of every registered agent. Agents
+// that can't be reached are reported with their error instead of failing the list.
+func (h *FederationHandler) ListClusters(c *fiber.Ctx) error {
+	agents, err := h.store.ListRemoteAgents()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list remote agents")
+	}
+	results := h.fanOut(c.Context(), agents, "clusters", nil)
+
+	clusters := []FederatedCluster{}
+	for i, agent := range agents {
+		if results[i].Error != "" {
+			continue
+		}
+		var payload protocol.ClustersPayload
+		if err := json.Unmarshal(results[i].Data, &payload); err != nil {
+			results[i].Error = "invalid cluster list: " + err.Error()
+			continue
+		}
+		for _, cluster := range payload.Clusters {
+			cluster.Name = agent.Name + "/" + cluster.Name
+			clusters = append(clusters, FederatedCluster{ClusterInfo: cluster, Agent: agent.Name, AgentID: agent.ID})
+		}
+		results[i].Data = nil
+	}
+	return c.JSON(fiber.Map{"clusters": clusters, "agents": results})
+}
+
+// Proxy routes a read-only agent endpoint (pods, nodes, events, ...) through
+// the console. With ?cluster={agent}/{context} the request goes to that agent
+// for that context and its response is returned as is; without it, every
+// agent is asked and the responses are returned per agent.
+func (h *FederationHandler) Proxy(c *fiber.Ctx) error {
+	endpoint := c.Params("endpoint")
+	if !federatedEndpoints[endpoint] {
+		return fiber.NewError(fiber.StatusNotFound, "Endpoint is not available through federation")
+	}
+	query := url.Values{}
+	c.Context().QueryArgs().VisitAll(func(k, v []byte) {
+		query.Add(string(k), string(v))
+	})
+
+	agents, err := h.store.ListRemoteAgents()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list remote agents")
+	}
+
+	if cluster := query.Get("cluster"); cluster != "" {
+		agentName, contextName, ok := strings.Cut(cluster, "/")
+		if !ok || contextName == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "cluster must be {agent}/{context}")
+		}
+		var target *models.RemoteAgent
+		for i := range agents {
+			if agents[i].Name == agentName {
+				target = &agents[i]
+				break
+			}
+		}
+		if target == nil {
+			return fiber.NewError(fiber.StatusNotFound, "Remote agent not found")
+		}
+		query.Set("cluster", contextName)
+		ctx, cancel := context.WithTimeout(c.Context(), federationRequestTimeout)
+		defer cancel()
+		body, status, err := h.get(ctx, *target, endpoint, query)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadGateway, fmt.Sprintf("Remote agent %s: %v", target.Name, err))
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Status(status).Send(body)
+	}
+
+	return c.JSON(fiber.Map{"results": h.fanOut(c.Context(), agents, endpoint, query)})
+}
+
+// fanOut sends the same request to every agent concurrently; results are in agent order
+func (h *FederationHandler) fanOut(parent context.Context, agents []models.RemoteAgent, endpoint string, query url.Values) []FederatedResult {
+	results := make([]FederatedResult, len(agents))
+	var wg sync.WaitGroup
+	for i := range agents {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(parent, federationRequestTimeout)
+			defer cancel()
+			result := FederatedResult{Agent: agents[i].Name}
+			body, status, err := h.get(ctx, agents[i], endpoint, query)
+			result.Status = status
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case status != http.StatusOK:
+				result.Error = fmt.Sprintf("agent returned %d: %s", status, strings.TrimSpace(string(body)))
+			default:
+				result.Data = body
+			}
+			results[i] = result
+		}(i)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	return results
+}
+
+// probe checks an agent's health endpoint, then that the registered token is
+// accepted for data requests
+func (h *FederationHandler) probe(parent context.Context, agent models.RemoteAgent) RemoteAgentStatus {
+	ctx, cancel := context.WithTimeout(parent, federationHealthTimeout)
+	defer cancel()
+
+	status := RemoteAgentStatus{RemoteAgent: agent, CheckedAt: time.Now()}
+	status.Token = "" // probe with the token but never echo it back
+	start := time.Now()
+	body, code, err := h.get(ctx, agent, "health", nil)
+	status.LatencyMs = time.Since(start).Milliseconds()
+	switch {
+	case err != nil:
+		status.Status = RemoteAgentUnreachable
+		status.Error = err.Error()
+		return status
+	case code != http.StatusOK:
+		status.Status = RemoteAgentError
+		status.Error = fmt.Sprintf("health check returned %d", code)
+		return status
+	}
+	var health protocol.HealthPayload
+	if err := json.Unmarshal(body, &health); err != nil {
+		status.Status = RemoteAgentError
+		status.Error = "invalid health response"
+		return status
+	}
+	status.Version = health.Version
+	status.Clusters = health.Clusters
+	if _, _, err := h.get(ctx, agent, "clusters", nil); errors.Is(err, errRemoteAgentUnauthorized) {
+		status.Status = RemoteAgentUnauthorized
+		status.Error = err.Error()
+		return status
+	}
+	status.Status = RemoteAgentHealthy
+	return status
+}
+
+// get requests an agent endpoint with the agent's token. Errors are transport
+// failures and rejected tokens; other statuses are returned with the body.
+func (h *FederationHandler) get(ctx context.Context, agent models.RemoteAgent, endpoint string, query url.Values) ([]byte, int, error) {
+	target := agent.URL + "/" + endpoint
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if agent.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+agent.Token)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, resp.StatusCode, errRemoteAgentUnauthorized
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFederationResponseBytes))
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return body, resp.StatusCode, nil
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/agent/protocol"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+// federationTestStore serves a fixed set of remote agents
+type federationTestStore struct {
+	test.MockStore
+	agents []models.RemoteAgent
+}
+
+func (s *federationTestStore) ListRemoteAgents() ([]models.RemoteAgent, error) {
+	return s.agents, nil
+}
+
+// fakeRemoteAgent answers /health, /clusters and /pods like a kc-agent and
+// records the query of the last /pods request
+func fakeRemoteAgent(t *testing.T, token string, contexts ...string) (*httptest.Server, *string) {
+	var lastPodsQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			json.NewEncoder(w).Encode(protocol.HealthPayload{Status: "ok", Version: "test", Clusters: len(contexts)})
+			return
+		}
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/clusters":
+			clusters := []map[string]string{}
+			for _, ctx := range contexts {
+				clusters = append(clusters, map[string]string{"name": ctx, "context": ctx})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"clusters": clusters, "current": contexts[0]})
+		case "/pods":
+			lastPodsQuery = r.URL.RawQuery
+			io.WriteString(w, `{"pods":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &lastPodsQuery
+}
+
+func TestFederationListClustersAndRouting(t *testing.T) {
+	env := setupTestEnv(t)
+	laptop, podsQuery := fakeRemoteAgent(t, "secret", "kind-dev", "prod")
+	jumpHost, _ := fakeRemoteAgent(t, "", "staging")
+
+	store := &federationTestStore{agents: []models.RemoteAgent{
+		{Name: "laptop", URL: laptop.URL, Token: "secret"},
+		{Name: "jump", URL: jumpHost.URL, Token: "stale"},
+		{Name: "offline", URL: "http://127.0.0.1:1"},
+	}}
+	h := NewFederationHandler(store)
+	env.App.Get("/federation/clusters", h.ListClusters)
+	env.App.Get("/federation/proxy/:endpoint", h.Proxy)
+
+	resp, err := env.App.Test(httptest.NewRequest("GET", "/federation/clusters", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body struct {
+		Clusters []FederatedCluster `json:"clusters"`
+		Agents   []FederatedResult  `json:"agents"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	names := []string{}
+	for _, c := range body.Clusters {
+		names = append(names, c.Name)
+	}
+	assert.ElementsMatch(t, []string{"laptop/kind-dev", "laptop/prod", "jump/staging"}, names)
+	require.Len(t, body.Agents, 3)
+	assert.Empty(t, body.Agents[0].Error)
+	assert.Empty(t, body.Agents[1].Error, "agents without a token accept any")
+	assert.NotEmpty(t, body.Agents[2].Error, "unreachable agents are reported, not fatal")
+
+	resp, err = env.App.Test(httptest.NewRequest("GET", "/federation/proxy/pods?cluster=laptop/prod&namespace=shop", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	data, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"pods":[]}`, string(data))
+	assert.Equal(t, "cluster=prod&namespace=shop", *podsQuery)
+
+	resp, err = env.App.Test(httptest.NewRequest("GET", "/federation/proxy/pods?cluster=unknown/prod", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = env.App.Test(httptest.NewRequest("GET", "/federation/proxy/secrets", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "endpoints outside the allowlist are not routed")
+}
+
+func TestFederationListAgentsProbesWithToken(t *testing.T) {
+	env := setupTestEnv(t)
+	laptop, _ := fakeRemoteAgent(t, "secret", "kind-dev", "prod")
+
+	store := &federationTestStore{agents: []models.RemoteAgent{
+		{Name: "laptop", URL: laptop.URL, Token: "secret"},
+		{Name: "stale", URL: laptop.URL, Token: "old"},
+		{Name: "offline", URL: "http://127.0.0.1:1"},
+	}}
+	h := NewFederationHandler(store)
+	env.App.Get("/federation/agents", h.ListAgents)
+
+	resp, err := env.App.Test(httptest.NewRequest("GET", "/federation/agents", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var statuses []RemoteAgentStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&statuses))
+	require.Len(t, statuses, 3)
+
+	assert.Equal(t, RemoteAgentHealthy, statuses[0].Status, statuses[0].Error)
+	assert.Equal(t, 2, statuses[0].Clusters)
+	assert.Equal(t, RemoteAgentUnauthorized, statuses[1].Status)
+	assert.Equal(t, RemoteAgentUnreachable, statuses[2].Status)
+	for _, s := range statuses {
+		assert.Empty(t, s.Token, "tokens are never returned")
+	}
+	assert.Equal(t, "secret", store.agents[0].Token, "the stored agent keeps its token")
+}
+
+func TestRemoteAgentValidate(t *testing.T) {
+	agent := models.RemoteAgent{Name: "jump-host-1", URL: "https://jump.example.com:8585/"}
+	require.NoError(t, agent.Validate())
+	assert.Equal(t, "https://jump.example.com:8585", agent.URL)
+
+	assert.Error(t, (&models.RemoteAgent{Name: "Jump/Host", URL: "https://jump.example.com"}).Validate())
+	assert.Error(t, (&models.RemoteAgent{Name: "jump", URL: "ftp://jump.example.com"}).Validate())
+	assert.Error(t, (&models.RemoteAgent{Name: "jump", URL: "https://jump.example.com?x=1"}).Validate())
+}
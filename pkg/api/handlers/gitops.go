@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubestellar/console/pkg/api/v1alpha1"
@@ -2108,3 +2109,65 @@ func (h *GitOpsHandlers) TriggerArgoSync(c *fiber.Ctx) error {
 		"message": "Sync triggered via Application resource annotation",
 	})
 }
+
+// ListFluxResources returns Flux Kustomizations and HelmReleases with their
+// readiness, read through the dynamic client.
+// GET /api/gitops/flux?cluster=<name>
+func (h *GitOpsHandlers) ListFluxResources(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not configured"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), argocdQueryTimeout)
+	defer cancel()
+
+	if cluster := c.Query("cluster"); cluster != "" {
+		items, err := h.k8sClient.ListFluxResourcesForCluster(ctx, cluster, c.Query("namespace"))
+		if err != nil {
+			log.Printf("[Flux] Failed to list resources on %s: %v", cluster, err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"items": items, "totalCount": len(items)})
+	}
+
+	list, err := h.k8sClient.ListFluxResources(ctx)
+	if err != nil {
+		log.Printf("[Flux] Failed to list resources: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+	return c.JSON(list)
+}
+
+// GetGitOpsOwners returns the Argo CD Applications and Flux resources that
+// manage a workload and whether it has drifted from them.
+// GET /api/gitops/owners/:cluster/:namespace/:name?kind=Deployment
+func (h *GitOpsHandlers) GetGitOpsOwners(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not configured"})
+	}
+
+	kind := c.Query("kind", "Deployment")
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "kind must be Deployment, StatefulSet or DaemonSet"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), argocdQueryTimeout)
+	defer cancel()
+
+	owners, err := h.k8sClient.GetGitOpsOwners(ctx, c.Params("cluster"), kind, c.Params("namespace"), c.Params("name"))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return c.Status(404).JSON(fiber.Map{"error": "workload not found"})
+		}
+		log.Printf("[GitOps] Failed to resolve owners: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	drifted := false
+	for _, o := range owners {
+		drifted = drifted || o.Drifted
+	}
+	return c.JSON(fiber.Map{"owners": owners, "managed": len(owners) > 0, "drifted": drifted})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	// defaultGPUAdvisorLimit is how many placement suggestions the advisor returns by default
+	defaultGPUAdvisorLimit = 20
+	// maxGPUAdvisorLimit caps the number of placement suggestions
+	maxGPUAdvisorLimit = 200
+)
+
+// GPUAdvisorHandler suggests where GPU jobs can run across the fleet
+type GPUAdvisorHandler struct {
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewGPUAdvisorHandler creates a new GPU advisor handler
+func NewGPUAdvisorHandler(k8sClient *k8s.MultiClusterClient) *GPUAdvisorHandler {
+	return &GPUAdvisorHandler{k8sClient: k8sClient}
+}
+
+// SuggestPlacement answers where ?namespace can run a job needing ?gpus GPUs
+// of ?type, ranking nodes of ?cluster or of every healthy cluster by free
+// GPUs, namespace quota and pending GPU demand. Candidates that don't fit are
+// included after those that do, with the reasons why, up to ?limit.
+// GET /api/gpus/advisor
+func (h *GPUAdvisorHandler) SuggestPlacement(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	req := k8s.GPUPlacementRequest{Namespace: c.Query("namespace"), GPUType: c.Query("type")}
+	gpus, err := strconv.Atoi(c.Query("gpus", "1"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "gpus must be an integer"})
+	}
+	req.GPUs = gpus
+	if err := req.Validate(); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	limit := defaultGPUAdvisorLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return c.Status(400).JSON(fiber.Map{"error": "limit must be a positive integer"})
+		}
+		limit = min(parsed, maxGPUAdvisorLimit)
+	}
+
+	var clusterNames []string
+	if cluster := c.Query("cluster"); cluster != "" {
+		clusterNames = []string{cluster}
+	} else {
+		clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	placements := make([]k8s.GPUPlacement, 0)
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			suggestions, err := h.k8sClient.SuggestGPUPlacement(ctx, clusterName, req)
+			if err != nil {
+				if !errors.Is(err, k8s.ErrInvalidGPUPlacement) {
+					log.Printf("[GPUAdvisor] %s: %v", clusterName, err)
+				}
+				return
+			}
+			mu.Lock()
+			placements = append(placements, suggestions...)
+			mu.Unlock()
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+
+	k8s.RankGPUPlacements(placements, req.GPUs)
+	fits := 0
+	for _, p := range placements {
+		if p.Fits {
+			fits++
+		}
+	}
+	if len(placements) > limit {
+		placements = placements[:limit]
+	}
+	return c.JSON(fiber.Map{"request": req, "fits": fits, "suggestions": placements})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// GPUOverviewHandler serves fleet-wide accelerator capacity per model
+type GPUOverviewHandler struct {
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewGPUOverviewHandler creates a new GPU overview handler
+func NewGPUOverviewHandler(k8sClient *k8s.MultiClusterClient) *GPUOverviewHandler {
+	return &GPUOverviewHandler{k8sClient: k8sClient}
+}
+
+// GetOverview returns total, allocated and available accelerators per model
+// across every healthy cluster (or ?cluster), with the per-cluster breakdown
+// and MIG slice availability, so clients need not fetch and merge
+// /mcp/gpu-nodes per cluster
+// GET /api/gpus/overview
+func (h *GPUOverviewHandler) GetOverview(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	var clusterNames []string
+	if cluster := c.Query("cluster"); cluster != "" {
+		clusterNames = []string{cluster}
+	} else {
+		clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	capacities := make([]k8s.GPUModelCapacity, 0)
+	failed := make([]string, 0)
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			models, err := h.k8sClient.GetGPUModelCapacity(ctx, clusterName)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("[GPUOverview] %s: %v", clusterName, err)
+				failed = append(failed, clusterName)
+				return
+			}
+			capacities = append(capacities, models...)
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+
+	overview := k8s.MergeGPUOverview(capacities)
+	var total, allocated int
+	for _, o := range overview {
+		total += o.Total
+		allocated += o.Allocated
+	}
+	return c.JSON(fiber.Map{
+		"models":         overview,
+		"total":          total,
+		"allocated":      allocated,
+		"available":      max(total-allocated, 0),
+		"failedClusters": failed,
+	})
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	// hooksSignatureHeader carries "sha256=" + the hex HMAC-SHA256 of
+	// "<timestamp>.<body>", where timestamp is the hooksTimestampHeader value
+	hooksSignatureHeader = "X-Hub-Signature-256"
+	// hooksTimestampHeader carries the Unix time the hook was signed at
+	hooksTimestampHeader = "X-Hook-Timestamp"
+	// hookTimestampTolerance is how far a hook's timestamp may be from now;
+	// signatures seen within it are rejected as replays
+	hookTimestampTolerance = 5 * time.Minute
+	// hookReprobeConcurrency bounds the cluster re-probes running at once
+	hookReprobeConcurrency = 8
+	// cacheResponses names the cached per-cluster SSE responses
+	cacheResponses = "responses"
+)
+
+// HookRequest asks the console to drop cached data after an external change,
+// e.g. a CI deploy or GitOps sync
+type HookRequest struct {
+	Cluster   string   `json:"cluster,omitempty"`   // empty means every cluster
+	Namespace string   `json:"namespace,omitempty"` // informational; caches are per cluster
+	Caches    []string `json:"caches,omitempty"`    // empty means all
+	Reprobe   bool     `json:"reprobe,omitempty"`   // check cluster health again right away
+	Source    string   `json:"source,omitempty"`    // caller, e.g. "argocd" or "github-actions"
+}
+
+// HooksHandler handles inbound refresh hooks from CI, GitOps and cloud event sources
+type HooksHandler struct {
+	k8sClient *k8s.MultiClusterClient
+	hub       *Hub
+	secret    string
+
+	mu        sync.Mutex
+	seen      map[string]time.Time     // accepted signatures, until their timestamp leaves the window
+	reprobing map[string]chan struct{} // clusters being re-probed, closed when done
+	slots     chan struct{}            // bounds concurrent re-probes
+	// probe re-probes one cluster; tests replace it
+	probe func(ctx context.Context, cluster string)
+}
+
+// NewHooksHandler creates a new hooks handler. Hooks are rejected until a secret is set.
+func NewHooksHandler(k8sClient *k8s.MultiClusterClient, hub *Hub, secret string) *HooksHandler {
+	h := &HooksHandler{
+		k8sClient: k8sClient,
+		hub:       hub,
+		secret:    secret,
+		seen:      make(map[string]time.Time),
+		reprobing: make(map[string]chan struct{}),
+		slots:     make(chan struct{}, hookReprobeConcurrency),
+	}
+	h.probe = func(ctx context.Context, cluster string) {
+		h.k8sClient.ReprobeCluster(ctx, cluster)
+	}
+	return h
+}
+
+// HandleHook invalidates the requested caches of a cluster (or all clusters)
+// and optionally re-probes it, then tells connected clients to refetch.
+// Requests must be signed with the shared secret and a current timestamp, and
+// each signed request is accepted once.
+// POST /hooks
+func (h *HooksHandler) HandleHook(c *fiber.Ctx) error {
+	if h.secret == "" {
+		return c.Status(503).JSON(fiber.Map{"error": "Hooks are not configured"})
+	}
+	timestamp := c.Get(hooksTimestampHeader)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Missing or invalid hook timestamp"})
+	}
+	signature := c.Get(hooksSignatureHeader)
+	if !verifyHookSignature(h.secret, timestamp, c.Body(), signature) {
+		return c.Status(401).JSON(fiber.Map{"error": "Invalid hook signature"})
+	}
+	if !h.acceptOnce(signature, time.Unix(signedAt, 0), time.Now()) {
+		return c.Status(401).JSON(fiber.Map{"error": "Hook timestamp outside the allowed window or hook replayed"})
+	}
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	var req HookRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid JSON payload"})
+	}
+	caches := req.Caches
+	if len(caches) == 0 {
+		caches = append(slices.Clone(k8s.ClusterCaches), cacheResponses)
+	}
+	var clusterCaches []string
+	dropResponses := false
+	for _, name := range caches {
+		if name == cacheResponses {
+			dropResponses = true
+		} else {
+			clusterCaches = append(clusterCaches, name)
+		}
+	}
+	if len(clusterCaches) > 0 {
+		if err := h.k8sClient.InvalidateCluster(req.Cluster, clusterCaches...); err != nil {
+			if errors.Is(err, k8s.ErrUnknownCache) {
+				return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+			}
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+	}
+	responsesDropped := 0
+	if dropResponses {
+		responsesDropped = sseCacheInvalidate(req.Cluster)
+	}
+	log.Printf("[Hooks] %s invalidated %s for cluster=%q namespace=%q (reprobe=%v)",
+		hookSource(req.Source), strings.Join(caches, ","), req.Cluster, req.Namespace, req.Reprobe)
+
+	if req.Reprobe {
+		// Probing can take the full timeout per cluster; don't hold the caller
+		go h.reprobe(req)
+		return c.Status(202).JSON(fiber.Map{
+			"invalidated":      caches,
+			"responsesDropped": responsesDropped,
+			"reprobe":          "started",
+		})
+	}
+	h.notify(req)
+	return c.JSON(fiber.Map{
+		"invalidated":      caches,
+		"responsesDropped": responsesDropped,
+	})
+}
+
+// acceptOnce reports whether a verified signature was signed within
+// hookTimestampTolerance of now and has not been accepted before
+func (h *HooksHandler) acceptOnce(signature string, signedAt, now time.Time) bool {
+	if signedAt.Before(now.Add(-hookTimestampTolerance)) || signedAt.After(now.Add(hookTimestampTolerance)) {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sig, at := range h.seen {
+		if at.Before(now.Add(-hookTimestampTolerance)) {
+			delete(h.seen, sig)
+		}
+	}
+	if _, replayed := h.seen[signature]; replayed {
+		return false
+	}
+	// fiber reuses the request buffer the header string points into
+	h.seen[strings.Clone(signature)] = signedAt
+	return true
+}
+
+// reprobe checks the hook's cluster, or every cluster, again and notifies
+// clients once fresh health is cached. A cluster already being re-probed for
+// another hook is not probed twice; this hook waits for that probe instead.
+func (h *HooksHandler) reprobe(req HookRequest) {
+	clusterNames := []string{req.Cluster}
+	if req.Cluster == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), mcpDefaultTimeout)
+		clusters, err := h.k8sClient.ListClusters(ctx)
+		cancel()
+		if err != nil {
+			log.Printf("[Hooks] listing clusters for reprobe failed: %v", err)
+			return
+		}
+		clusterNames = clusterNames[:0]
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range clusterNames {
+		done := h.startReprobe(name)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-done
+		}()
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	h.notify(req)
+}
+
+// startReprobe re-probes a cluster unless that is already under way, and
+// returns a channel closed when the probe finishes
+func (h *HooksHandler) startReprobe(cluster string) <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if done, ok := h.reprobing[cluster]; ok {
+		return done
+	}
+	done := make(chan struct{})
+	h.reprobing[cluster] = done
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			delete(h.reprobing, cluster)
+			h.mu.Unlock()
+			close(done)
+		}()
+		h.slots <- struct{}{}
+		defer func() { <-h.slots }()
+		ctx, cancel := context.WithTimeout(context.Background(), mcpDefaultTimeout)
+		defer cancel()
+		h.probe(ctx, cluster)
+	}()
+	return done
+}
+
+// notify tells connected clients which cluster's data changed
+func (h *HooksHandler) notify(req HookRequest) {
+	if h.hub == nil {
+		return
+	}
+	h.hub.BroadcastAll(Message{
+		Type: "cache_invalidated",
+		Data: fiber.Map{
+			"cluster":   req.Cluster,
+			"namespace": req.Namespace,
+			"source":    hookSource(req.Source),
+		},
+	})
+}
+
+// verifyHookSignature checks a "sha256=<hex>" HMAC of "<timestamp>.<body>"
+// in constant time
+func verifyHookSignature(secret, timestamp string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+func hookSource(source string) string {
+	if source == "" {
+		return "hook"
+	}
+	return source
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+func signHook(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHandleHook(t *testing.T) {
+	k8sClient, err := k8s.NewMultiClusterClient("")
+	require.NoError(t, err)
+	app := fiber.New()
+	app.Post("/hooks", NewHooksHandler(k8sClient, nil, "s3cret").HandleHook)
+	app.Post("/disabled", NewHooksHandler(k8sClient, nil, "").HandleHook)
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	body := `{"cluster":"c1","namespace":"shop","source":"argocd"}`
+	assert.Equal(t, 503, postHook(t, app, "/disabled", body, now, signHook("s3cret", now, body)))
+	assert.Equal(t, 401, postHook(t, app, "/hooks", body, now, ""))
+	assert.Equal(t, 401, postHook(t, app, "/hooks", body, now, signHook("wrong", now, body)))
+	assert.Equal(t, 401, postHook(t, app, "/hooks", body, "", signHook("s3cret", "", body)), "hooks need a timestamp")
+
+	sseCacheSet("pods:c1", []string{"stale"})
+	sseCacheSet("pods:c2", []string{"kept"})
+	t.Cleanup(func() { sseCacheInvalidate("") })
+	assert.Equal(t, 200, postHook(t, app, "/hooks", body, now, signHook("s3cret", now, body)))
+	assert.Nil(t, sseCacheGet("pods:c1"), "c1 responses should be dropped")
+	assert.NotNil(t, sseCacheGet("pods:c2"), "other clusters should keep their responses")
+
+	unknown := `{"cluster":"c1","caches":["bogus"]}`
+	assert.Equal(t, 400, postHook(t, app, "/hooks", unknown, now, signHook("s3cret", now, unknown)))
+}
+
+func postHook(t *testing.T, app *fiber.App, path, body, timestamp, signature string) int {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if timestamp != "" {
+		req.Header.Set(hooksTimestampHeader, timestamp)
+	}
+	if signature != "" {
+		req.Header.Set(hooksSignatureHeader, signature)
+	}
+	resp, err := app.Test(req, 5000)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestHandleHookRejectsReplays(t *testing.T) {
+	k8sClient, err := k8s.NewMultiClusterClient("")
+	require.NoError(t, err)
+	app := fiber.New()
+	app.Post("/hooks", NewHooksHandler(k8sClient, nil, "s3cret").HandleHook)
+	t.Cleanup(func() { sseCacheInvalidate("") })
+
+	body := `{"cluster":"c1","caches":["responses"]}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := signHook("s3cret", now, body)
+	assert.Equal(t, 200, postHook(t, app, "/hooks", body, now, signature))
+	assert.Equal(t, 401, postHook(t, app, "/hooks", body, now, signature), "a captured hook cannot be replayed")
+
+	stale := strconv.FormatInt(time.Now().Add(-2*hookTimestampTolerance).Unix(), 10)
+	assert.Equal(t, 401, postHook(t, app, "/hooks", body, stale, signHook("s3cret", stale, body)))
+	future := strconv.FormatInt(time.Now().Add(2*hookTimestampTolerance).Unix(), 10)
+	assert.Equal(t, 401, postHook(t, app, "/hooks", body, future, signHook("s3cret", future, body)))
+}
+
+func TestHookReprobeFanOut(t *testing.T) {
+	h := NewHooksHandler(nil, nil, "s3cret")
+	release := make(chan struct{})
+	var mu sync.Mutex
+	running, peak := 0, 0
+	calls := map[string]int{}
+	h.probe = func(ctx context.Context, cluster string) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		calls[cluster]++
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+	}
+
+	const clusters = 3 * hookReprobeConcurrency
+	var wg sync.WaitGroup
+	for i := 0; i < clusters; i++ {
+		for range 3 {
+			wg.Add(1)
+			go func(cluster string) {
+				defer wg.Done()
+				h.reprobe(HookRequest{Cluster: cluster})
+			}(fmt.Sprintf("c%d", i))
+		}
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return running == hookReprobeConcurrency
+	}, 5*time.Second, 10*time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, hookReprobeConcurrency, peak, "re-probes are bounded")
+	assert.Len(t, calls, clusters)
+	for cluster, n := range calls {
+		assert.Equal(t, 1, n, "hooks for %s should share one re-probe", cluster)
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultIssueSyncIntervalMs is the default interval between issue scans (2 minutes)
+	defaultIssueSyncIntervalMs = 120_000
+	// issueRetentionDays is how long resolved issues and their history are kept
+	issueRetentionDays = 90
+	// issueCleanupInterval is how often expired resolved issues are removed
+	issueCleanupInterval = 24 * time.Hour
+	// issueActorSystem is the actor of history entries recorded by scans
+	issueActorSystem = "system"
+)
+
+// IssueRegistry turns the pod, deployment and security issues detected in
+// every cluster into persistent issues with stable IDs. Each scan opens new
+// issues, reopens recurring ones and resolves the ones no longer detected;
+// users acknowledge, assign and snooze them in between.
+type IssueRegistry struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+	interval  time.Duration
+	stopCh    chan struct{}
+
+	mu          sync.Mutex // serializes scans and user actions on issues
+	lastCleanup time.Time
+}
+
+// NewIssueRegistry creates a new issue registry
+func NewIssueRegistry(s store.Store, k8sClient *k8s.MultiClusterClient) *IssueRegistry {
+	intervalMs := defaultIssueSyncIntervalMs
+	if envVal := os.Getenv("ISSUE_SYNC_INTERVAL_MS"); envVal != "" {
+		if parsed, err := strconv.Atoi(envVal); err == nil && parsed > 0 {
+			intervalMs = parsed
+		}
+	}
+
+	return &IssueRegistry{
+		store:     s,
+		k8sClient: k8sClient,
+		interval:  time.Duration(intervalMs) * time.Millisecond,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the background scan loop
+func (r *IssueRegistry) Start() {
+	go func() {
+		r.syncOnce()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.syncOnce()
+			case <-r.stopCh:
+				return
+			}
+		}
+	}()
+	log.Printf("Issue registry started (interval: %v)", r.interval)
+}
+
+// Stop signals the registry to stop
+func (r *IssueRegistry) Stop() {
+	close(r.stopCh)
+}
+
+func (r *IssueRegistry) syncOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.interval/2)
+	defer cancel()
+	if err := r.Sync(ctx); err != nil {
+		log.Printf("Issue registry: sync failed: %v", err)
+	}
+}
+
+// Sync scans every healthy cluster and reconciles the detected issues with
+// the stored ones. Issues are only resolved for the clusters and kinds that
+// were scanned successfully, so an unreachable cluster doesn't resolve its
+// issues.
+func (r *IssueRegistry) Sync(ctx context.Context) error {
+	clusters, _, err := r.k8sClient.HealthyClusters(ctx)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	detected := make(map[string]models.Issue)
+	scanned := make(map[string]bool) // cluster/kind
+	for _, cl := range clusters {
+		wg.Add(1)
+		go func(cluster string) {
+			defer wg.Done()
+			issues, ok := r.detect(ctx, cluster)
+			mu.Lock()
+			defer mu.Unlock()
+			for kind := range ok {
+				scanned[cluster+"/"+string(kind)] = true
+			}
+			for _, issue := range issues {
+				detected[issue.ID] = issue
+			}
+		}(cl.Name)
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, issue := range detected {
+		if err := r.observe(issue, now); err != nil {
+			return err
+		}
+	}
+
+	open, err := r.store.ListIssues(models.IssueFilter{Status: models.IssueStatusOpen})
+	if err != nil {
+		return err
+	}
+	for i := range open {
+		issue := &open[i]
+		if _, ok := detected[issue.ID]; ok || !scanned[issue.Cluster+"/"+string(issue.Kind)] {
+			continue
+		}
+		issue.Status = models.IssueStatusResolved
+		issue.ResolvedAt = &now
+		if err := r.save(issue, models.IssueActionResolved, issueActorSystem, "no longer detected"); err != nil {
+			return err
+		}
+	}
+
+	if now.Sub(r.lastCleanup) >= issueCleanupInterval {
+		r.lastCleanup = now
+		if deleted, err := r.store.DeleteResolvedIssues(now.AddDate(0, 0, -issueRetentionDays)); err != nil {
+			log.Printf("Issue registry: failed to cleanup resolved issues: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Issue registry: cleaned up %d resolved issues", deleted)
+		}
+	}
+	return nil
+}
+
+// observe records that an issue was detected: it is opened when new,
+// reopened when it had been resolved, and refreshed otherwise
+func (r *IssueRegistry) observe(detected models.Issue, now time.Time) error {
+	existing, err := r.store.GetIssue(detected.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		detected.Status = models.IssueStatusOpen
+		detected.Occurrences = 1
+		detected.FirstSeen = now
+		detected.LastSeen = now
+		return r.save(&detected, models.IssueActionOpened, issueActorSystem, detected.Message)
+	}
+
+	existing.Severity = detected.Severity
+	existing.Message = detected.Message
+	existing.LastSeen = now
+	if existing.Status == models.IssueStatusOpen {
+		return r.store.UpsertIssue(existing)
+	}
+	// A recurring problem needs attention again
+	existing.Status = models.IssueStatusOpen
+	existing.Occurrences++
+	existing.ResolvedAt = nil
+	existing.AcknowledgedBy = ""
+	existing.AcknowledgedAt = nil
+	return r.save(existing, models.IssueActionReopened, issueActorSystem, fmt.Sprintf("occurrence %d", existing.Occurrences))
+}
+
+// detect lists the issues of one cluster and the kinds that were scanned
+func (r *IssueRegistry) detect(ctx context.Context, cluster string) ([]models.Issue, map[models.IssueKind]bool) {
+	var issues []models.Issue
+	scanned := make(map[models.IssueKind]bool)
+
+	if pods, err := r.k8sClient.FindPodIssues(ctx, cluster, ""); err != nil {
+		log.Printf("Issue registry: failed to scan pods in %s: %v", cluster, err)
+	} else {
+		scanned[models.IssueKindPod] = true
+		for _, p := range pods {
+			issueType := p.Reason
+			if issueType == "" {
+				issueType = p.Status
+			}
+			issues = append(issues, newIssue(models.IssueKindPod, cluster, p.Namespace, p.Name, issueType, "", strings.Join(p.Issues, "; ")))
+		}
+	}
+
+	if deployments, err := r.k8sClient.FindDeploymentIssues(ctx, cluster, ""); err != nil {
+		log.Printf("Issue registry: failed to scan deployments in %s: %v", cluster, err)
+	} else {
+		scanned[models.IssueKindDeployment] = true
+		for _, d := range deployments {
+			issueType := d.Reason
+			if issueType == "" {
+				issueType = "Unavailable"
+			}
+			message := d.Message
+			if message == "" {
+				message = fmt.Sprintf("%d/%d replicas ready", d.ReadyReplicas, d.Replicas)
+			}
+			issues = append(issues, newIssue(models.IssueKindDeployment, cluster, d.Namespace, d.Name, issueType, "", message))
+		}
+	}
+
+	if security, err := r.k8sClient.CheckSecurityIssues(ctx, cluster, ""); err != nil {
+		log.Printf("Issue registry: failed to scan security in %s: %v", cluster, err)
+	} else {
+		scanned[models.IssueKindSecurity] = true
+		for _, s := range security {
+			issues = append(issues, newIssue(models.IssueKindSecurity, cluster, s.Namespace, s.Name, s.Issue, s.Severity, s.Details))
+		}
+	}
+	return issues, scanned
+}
+
+func newIssue(kind models.IssueKind, cluster, namespace, name, issueType, severity, message string) models.Issue {
+	return models.Issue{
+		ID:        models.IssueFingerprint(kind, cluster, namespace, name, issueType),
+		Kind:      kind,
+		Cluster:   cluster,
+		Namespace: namespace,
+		Name:      name,
+		Type:      issueType,
+		Severity:  severity,
+		Message:   message,
+	}
+}
+
+// Acknowledge marks an issue as seen by actor. It returns nil when the
+// issue doesn't exist.
+func (r *IssueRegistry) Acknowledge(id, actor string) (*models.Issue, error) {
+	return r.update(id, func(issue *models.Issue) (models.IssueAction, string) {
+		now := time.Now()
+		issue.AcknowledgedBy = actor
+		issue.AcknowledgedAt = &now
+		return models.IssueActionAcknowledged, ""
+	}, actor)
+}
+
+// Assign sets who is responsible for an issue; an empty assignee unassigns it
+func (r *IssueRegistry) Assign(id, assignee, actor string) (*models.Issue, error) {
+	return r.update(id, func(issue *models.Issue) (models.IssueAction, string) {
+		issue.Assignee = assignee
+		return models.IssueActionAssigned, assignee
+	}, actor)
+}
+
+// Snooze hides an issue from default listings until the given time; nil
+// wakes it up
+func (r *IssueRegistry) Snooze(id string, until *time.Time, actor string) (*models.Issue, error) {
+	return r.update(id, func(issue *models.Issue) (models.IssueAction, string) {
+		issue.SnoozedUntil = until
+		if until == nil {
+			return models.IssueActionUnsnoozed, ""
+		}
+		return models.IssueActionSnoozed, until.Format(time.RFC3339)
+	}, actor)
+}
+
+func (r *IssueRegistry) update(id string, apply func(*models.Issue) (models.IssueAction, string), actor string) (*models.Issue, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	issue, err := r.store.GetIssue(id)
+	if err != nil || issue == nil {
+		return nil, err
+	}
+	action, detail := apply(issue)
+	if err := r.save(issue, action, actor, detail); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// save stores an issue and appends an entry to its history
+func (r *IssueRegistry) save(issue *models.Issue, action models.IssueAction, actor, detail string) error {
+	if err := r.store.UpsertIssue(issue); err != nil {
+		return err
+	}
+	return r.store.InsertIssueEvent(&models.IssueEvent{
+		IssueID:   issue.ID,
+		Action:    action,
+		Actor:     actor,
+		Detail:    detail,
+		CreatedAt: time.Now(),
+	})
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+// issueTestStore keeps issues and their history in memory
+type issueTestStore struct {
+	test.MockStore
+	issues map[string]models.Issue
+	events []models.IssueEvent
+}
+
+func (s *issueTestStore) UpsertIssue(issue *models.Issue) error {
+	s.issues[issue.ID] = *issue
+	return nil
+}
+
+func (s *issueTestStore) GetIssue(id string) (*models.Issue, error) {
+	issue, ok := s.issues[id]
+	if !ok {
+		return nil, nil
+	}
+	return &issue, nil
+}
+
+func (s *issueTestStore) ListIssues(filter models.IssueFilter) ([]models.Issue, error) {
+	var issues []models.Issue
+	for _, issue := range s.issues {
+		if filter.Status == "" || issue.Status == filter.Status {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+func (s *issueTestStore) InsertIssueEvent(event *models.IssueEvent) error {
+	s.events = append(s.events, *event)
+	return nil
+}
+
+func TestIssueRegistryLifecycle(t *testing.T) {
+	env := setupTestEnv(t)
+	store := &issueTestStore{issues: make(map[string]models.Issue)}
+	registry := NewIssueRegistry(store, env.K8sClient)
+	client, err := env.K8sClient.GetClient("test-cluster")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	crashing := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop"},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "api",
+			RestartCount: 9,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	}
+	podIssue := func() models.Issue {
+		for _, issue := range store.issues {
+			if issue.Kind == models.IssueKindPod && issue.Name == "api-1" {
+				return issue
+			}
+		}
+		t.Fatalf("Expected an issue for pod api-1, got %+v", store.issues)
+		return models.Issue{}
+	}
+
+	_, err = client.CoreV1().Pods("shop").Create(ctx, crashing, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, registry.Sync(ctx))
+	opened := podIssue()
+	assert.Equal(t, models.IssueStatusOpen, opened.Status)
+	assert.Equal(t, 1, opened.Occurrences)
+
+	_, err = registry.Acknowledge(opened.ID, "alice")
+	require.NoError(t, err)
+	until := time.Now().Add(time.Hour)
+	_, err = registry.Snooze(opened.ID, &until, "alice")
+	require.NoError(t, err)
+	snoozed := podIssue()
+	assert.True(t, snoozed.Snoozed(time.Now()))
+
+	require.NoError(t, client.CoreV1().Pods("shop").Delete(ctx, "api-1", metav1.DeleteOptions{}))
+	require.NoError(t, registry.Sync(ctx))
+	resolved := podIssue()
+	assert.Equal(t, models.IssueStatusResolved, resolved.Status)
+	assert.NotNil(t, resolved.ResolvedAt)
+
+	_, err = client.CoreV1().Pods("shop").Create(ctx, crashing, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, registry.Sync(ctx))
+	reopened := podIssue()
+	assert.Equal(t, opened.ID, reopened.ID, "a recurring problem keeps its ID")
+	assert.Equal(t, models.IssueStatusOpen, reopened.Status)
+	assert.Equal(t, 2, reopened.Occurrences)
+	assert.Empty(t, reopened.AcknowledgedBy, "a recurring problem needs a new acknowledgment")
+
+	var actions []models.IssueAction
+	for _, e := range store.events {
+		if e.IssueID == opened.ID {
+			actions = append(actions, e.Action)
+		}
+	}
+	assert.Equal(t, []models.IssueAction{
+		models.IssueActionOpened, models.IssueActionAcknowledged, models.IssueActionSnoozed,
+		models.IssueActionResolved, models.IssueActionReopened,
+	}, actions)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultIssuesLimit is how many issues are listed by default
+	defaultIssuesLimit = 200
+	// maxIssuesLimit caps the issues listed per request
+	maxIssuesLimit = 1000
+	// issueHistoryLimit caps the history entries returned with an issue
+	issueHistoryLimit = 100
+	// maxIssueSnooze is the longest an issue can be snoozed
+	maxIssueSnooze = 30 * 24 * time.Hour
+)
+
+// IssueHandler serves the persistent issue registry and its acknowledgment
+// workflow
+type IssueHandler struct {
+	store    store.Store
+	registry *IssueRegistry
+}
+
+// NewIssueHandler creates a new issue handler
+func NewIssueHandler(s store.Store, registry *IssueRegistry) *IssueHandler {
+	return &IssueHandler{store: s, registry: registry}
+}
+
+// ListIssues lists issues, most recently seen first. ?status is open
+// (default), resolved or all; ?kind and ?cluster narrow the list; snoozed
+// issues are left out unless ?includeSnoozed=true.
+func (h *IssueHandler) ListIssues(c *fiber.Ctx) error {
+	filter := models.IssueFilter{
+		Status:  models.IssueStatus(c.Query("status", string(models.IssueStatusOpen))),
+		Kind:    models.IssueKind(c.Query("kind")),
+		Cluster: c.Query("cluster"),
+		Limit:   defaultIssuesLimit,
+	}
+	switch filter.Status {
+	case "all":
+		filter.Status = ""
+	case models.IssueStatusOpen, models.IssueStatusResolved:
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "status must be open, resolved or all")
+	}
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return fiber.NewError(fiber.StatusBadRequest, "limit must be a positive integer")
+		}
+		filter.Limit = min(parsed, maxIssuesLimit)
+	}
+
+	issues, err := h.store.ListIssues(filter)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list issues")
+	}
+	includeSnoozed := c.QueryBool("includeSnoozed")
+	now := time.Now()
+	result := make([]models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if !includeSnoozed && issue.Snoozed(now) {
+			continue
+		}
+		result = append(result, issue)
+	}
+	return c.JSON(result)
+}
+
+// GetIssue returns an issue with its recent history
+func (h *IssueHandler) GetIssue(c *fiber.Ctx) error {
+	issue, err := h.store.GetIssue(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get issue")
+	}
+	if issue == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Issue not found")
+	}
+	history, err := h.store.ListIssueEvents(issue.ID, issueHistoryLimit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get issue history")
+	}
+	if history == nil {
+		history = []models.IssueEvent{}
+	}
+	return c.JSON(fiber.Map{"issue": issue, "history": history})
+}
+
+// Acknowledge marks an issue as seen by the current user
+func (h *IssueHandler) Acknowledge(c *fiber.Ctx) error {
+	issue, err := h.registry.Acknowledge(c.Params("id"), middleware.GetGitHubLogin(c))
+	return h.respond(c, issue, err)
+}
+
+// Assign sets the issue's assignee from {"assignee": "login"}; an empty
+// assignee unassigns it
+func (h *IssueHandler) Assign(c *fiber.Ctx) error {
+	var input struct {
+		Assignee string `json:"assignee"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	issue, err := h.registry.Assign(c.Params("id"), input.Assignee, middleware.GetGitHubLogin(c))
+	return h.respond(c, issue, err)
+}
+
+// Snooze hides an issue for {"duration": "4h"}, up to 30 days; an empty or
+// zero duration wakes it up
+func (h *IssueHandler) Snooze(c *fiber.Ctx) error {
+	var input struct {
+		Duration string `json:"duration"`
+	}
+	if err := c.BodyParser(&input); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	var until *time.Time
+	if input.Duration != "" {
+		duration, err := time.ParseDuration(input.Duration)
+		if err != nil || duration < 0 || duration > maxIssueSnooze {
+			return fiber.NewError(fiber.StatusBadRequest, "duration must be a Go duration up to 720h")
+		}
+		if duration > 0 {
+			t := time.Now().Add(duration)
+			until = &t
+		}
+	}
+	issue, err := h.registry.Snooze(c.Params("id"), until, middleware.GetGitHubLogin(c))
+	return h.respond(c, issue, err)
+}
+
+func (h *IssueHandler) respond(c *fiber.Ctx, issue *models.Issue, err error) error {
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update issue")
+	}
+	if issue == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Issue not found")
+	}
+	return c.JSON(issue)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// defaultLogTailLines is how many existing lines each container starts with
+const defaultLogTailLines = 10
+
+// LogTailHandlers handles multi-pod log tailing over WebSocket
+type LogTailHandlers struct {
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewLogTailHandlers creates a new log tail handlers instance
+func NewLogTailHandlers(k8sClient *k8s.MultiClusterClient) *LogTailHandlers {
+	return &LogTailHandlers{
+		k8sClient: k8sClient,
+	}
+}
+
+// logTailInitMessage is sent by the client to start tailing
+type logTailInitMessage struct {
+	Type      string `json:"type"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Selector  string `json:"selector"`
+	// Container is a regular expression; empty tails every container
+	Container    string `json:"container"`
+	TailLines    int64  `json:"tailLines"`
+	SinceSeconds int64  `json:"sinceSeconds"`
+	Timestamps   bool   `json:"timestamps"`
+}
+
+// HandleLogTail streams the logs of all pods matching a label selector, like
+// stern. Each message is a k8s.LogTailEvent carrying the pod and container the
+// line came from plus color hints for their prefixes. The session ends when
+// the client disconnects.
+func (h *LogTailHandlers) HandleLogTail(c *websocket.Conn) {
+	defer c.Close()
+
+	if h.k8sClient == nil {
+		writeTailError(c, "No Kubernetes client available")
+		return
+	}
+
+	_, msg, err := c.ReadMessage()
+	if err != nil {
+		log.Printf("logs: failed to read init message: %v", err)
+		return
+	}
+
+	var init logTailInitMessage
+	if err := json.Unmarshal(msg, &init); err != nil {
+		writeTailError(c, "Invalid init message")
+		return
+	}
+	if init.Type != "logs_init" {
+		writeTailError(c, "Expected logs_init message")
+		return
+	}
+	if init.Cluster == "" || init.Namespace == "" || init.Selector == "" {
+		writeTailError(c, "Missing cluster, namespace, or selector")
+		return
+	}
+	if init.TailLines <= 0 && init.SinceSeconds <= 0 {
+		init.TailLines = defaultLogTailLines
+	}
+
+	// The client only sends to close the session; a failed read means it is gone
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	opts := k8s.LogTailOptions{
+		Container:    init.Container,
+		TailLines:    init.TailLines,
+		SinceSeconds: init.SinceSeconds,
+		Timestamps:   init.Timestamps,
+	}
+	err = h.k8sClient.TailPodLogs(ctx, init.Cluster, init.Namespace, init.Selector, opts, func(e k8s.LogTailEvent) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+		if err := c.WriteMessage(websocket.TextMessage, data); err != nil {
+			cancel()
+		}
+	})
+	if err != nil {
+		log.Printf("logs: tail of %s/%s %q ended: %v", init.Cluster, init.Namespace, init.Selector, err)
+		writeTailError(c, err.Error())
+	}
+}
+
+func writeTailError(c *websocket.Conn, msg string) {
+	errMsg, _ := json.Marshal(k8s.LogTailEvent{Type: "error", Error: msg})
+	_ = c.WriteMessage(websocket.TextMessage, errMsg)
+}
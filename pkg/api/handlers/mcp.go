@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
 	"sync"
 	"time"
 
@@ -595,6 +596,60 @@ func (h *MCPHandlers) GetNVIDIAOperatorStatus(c *fiber.Ctx) error {
 	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
 }
 
+// GetAMDGPUOperatorStatus returns AMD GPU Operator DeviceConfig status
+func (h *MCPHandlers) GetAMDGPUOperatorStatus(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "operators", getDemoAMDGPUOperatorStatus())
+	}
+
+	cluster := c.Query("cluster")
+
+	if h.k8sClient != nil {
+		// If no cluster specified, query all clusters in parallel
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allStatus := []*k8s.AMDGPUOperatorStatus{}
+			clusterTimeout := mcpDefaultTimeout
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), clusterTimeout)
+					defer cancel()
+
+					status, err := h.k8sClient.GetAMDGPUOperatorStatus(ctx, clusterName)
+					if err == nil && len(status.DeviceConfigs) > 0 {
+						mu.Lock()
+						allStatus = append(allStatus, status)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"operators": allStatus, "source": "k8s"})
+		}
+
+		status, err := h.k8sClient.GetAMDGPUOperatorStatus(c.Context(), cluster)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"operator": status, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
 // GetNodes returns detailed node information
 func (h *MCPHandlers) GetNodes(c *fiber.Ctx) error {
 	// Demo mode: return demo data immediately
@@ -1191,6 +1246,59 @@ func (h *MCPHandlers) GetPVs(c *fiber.Ctx) error {
 	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
 }
 
+// GetStorageClasses returns StorageClasses from clusters
+func (h *MCPHandlers) GetStorageClasses(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "storageClasses", getDemoStorageClasses())
+	}
+
+	cluster := c.Query("cluster")
+
+	if h.k8sClient != nil {
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			var allClasses []k8s.StorageClass
+			clusterTimeout := mcpDefaultTimeout
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), clusterTimeout)
+					defer cancel()
+
+					classes, err := h.k8sClient.GetStorageClasses(ctx, clusterName)
+					if err == nil && len(classes) > 0 {
+						mu.Lock()
+						allClasses = append(allClasses, classes...)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"storageClasses": allClasses, "source": "k8s"})
+		}
+
+		classes, err := h.k8sClient.GetStorageClasses(c.Context(), cluster)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"storageClasses": classes, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
 // GetResourceQuotas returns resource quotas from clusters
 func (h *MCPHandlers) GetResourceQuotas(c *fiber.Ctx) error {
 	// Demo mode: return demo data immediately
@@ -1375,7 +1483,11 @@ func (h *MCPHandlers) DeleteResourceQuota(c *fiber.Ctx) error {
 	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
 }
 
-// GetPodLogs returns logs from a pod
+// defaultPodLogTail is how many lines GetPodLogs returns when no tail is given
+const defaultPodLogTail = 100
+
+// GetPodLogs returns logs from a pod. previous=true fetches the last crashed
+// container instance; grep filters lines server-side with a regular expression.
 func (h *MCPHandlers) GetPodLogs(c *fiber.Ctx) error {
 	// Demo mode: return demo data immediately
 	if isDemoMode(c) {
@@ -1385,20 +1497,48 @@ func (h *MCPHandlers) GetPodLogs(c *fiber.Ctx) error {
 	cluster := c.Query("cluster")
 	namespace := c.Query("namespace")
 	pod := c.Query("pod")
-	container := c.Query("container")
-	tailLines := c.QueryInt("tail", 100)
 
 	if cluster == "" || namespace == "" || pod == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "cluster, namespace, and pod are required"})
 	}
 
+	opts := k8s.PodLogOptions{
+		Container:    c.Query("container"),
+		TailLines:    int64(c.QueryInt("tail", defaultPodLogTail)),
+		Previous:     c.QueryBool("previous"),
+		SinceSeconds: int64(c.QueryInt("sinceSeconds")),
+		Timestamps:   c.QueryBool("timestamps"),
+		Grep:         c.Query("grep"),
+		MaxMatches:   c.QueryInt("maxMatches"),
+	}
+	if since := c.Query("sinceTime"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "sinceTime must be RFC3339"})
+		}
+		opts.SinceTime = &t
+	}
+	if opts.SinceSeconds > 0 && opts.SinceTime != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "sinceSeconds and sinceTime are mutually exclusive"})
+	}
+	if opts.Grep != "" {
+		if _, err := regexp.Compile(opts.Grep); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "invalid grep pattern"})
+		}
+		// Search the whole log unless a tail is requested; maxMatches bounds the response
+		opts.TailLines = int64(c.QueryInt("tail"))
+	}
+
 	if h.k8sClient != nil {
-		logs, err := h.k8sClient.GetPodLogs(c.Context(), cluster, namespace, pod, container, int64(tailLines))
+		result, err := h.k8sClient.GetPodLogsWithOptions(c.Context(), cluster, namespace, pod, opts)
 		if err != nil {
 			log.Printf("internal error: %v", err)
 			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
 		}
-		return c.JSON(fiber.Map{"logs": logs, "source": "k8s"})
+		if opts.Grep != "" {
+			return c.JSON(fiber.Map{"logs": result.Logs, "matches": result.Matches, "truncated": result.Truncated, "source": "k8s"})
+		}
+		return c.JSON(fiber.Map{"logs": result.Logs, "source": "k8s"})
 	}
 
 	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
@@ -1615,6 +1755,333 @@ func (h *MCPHandlers) CheckSecurityIssues(c *fiber.Ctx) error {
 	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
 }
 
+// GetPodSecurityLevels returns the Pod Security Standards level each pod satisfies
+func (h *MCPHandlers) GetPodSecurityLevels(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "pods", getDemoPodSecurityLevels())
+	}
+
+	cluster := c.Query("cluster")
+	namespace := c.Query("namespace")
+
+	if h.k8sClient != nil {
+		// If no cluster specified, query all clusters in parallel
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allLevels := make([]k8s.PodSecurityLevel, 0)
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+					defer cancel()
+
+					levels, err := h.k8sClient.CheckPodSecurityLevels(ctx, clusterName, namespace)
+					if err == nil && len(levels) > 0 {
+						mu.Lock()
+						allLevels = append(allLevels, levels...)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"pods": allLevels, "source": "k8s"})
+		}
+
+		levels, err := h.k8sClient.CheckPodSecurityLevels(c.Context(), cluster, namespace)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"pods": levels, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
+// GetPlatformIssues returns pods failing on, or at risk of landing on, nodes
+// whose OS or architecture their images may not support
+func (h *MCPHandlers) GetPlatformIssues(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "issues", getDemoPlatformIssues())
+	}
+
+	cluster := c.Query("cluster")
+	namespace := c.Query("namespace")
+
+	if h.k8sClient != nil {
+		// If no cluster specified, query all clusters in parallel
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allIssues := make([]k8s.PlatformIssue, 0)
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+					defer cancel()
+
+					issues, err := h.k8sClient.CheckPlatformScheduling(ctx, clusterName, namespace)
+					if err == nil && len(issues) > 0 {
+						mu.Lock()
+						allIssues = append(allIssues, issues...)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"issues": allIssues, "source": "k8s"})
+		}
+
+		issues, err := h.k8sClient.CheckPlatformScheduling(c.Context(), cluster, namespace)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"issues": issues, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
+// GetVolumeUsage returns actual filesystem usage of PVC-backed volumes,
+// flagging those nearly full as storage issues
+func (h *MCPHandlers) GetVolumeUsage(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "volumes", getDemoVolumeUsage())
+	}
+
+	cluster := c.Query("cluster")
+	namespace := c.Query("namespace")
+
+	if h.k8sClient != nil {
+		// If no cluster specified, query all clusters in parallel
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allVolumes := make([]k8s.VolumeUsage, 0)
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+					defer cancel()
+
+					volumes, err := h.k8sClient.GetVolumeUsage(ctx, clusterName, namespace)
+					if err == nil && len(volumes) > 0 {
+						mu.Lock()
+						allVolumes = append(allVolumes, volumes...)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"volumes": allVolumes, "source": "k8s"})
+		}
+
+		volumes, err := h.k8sClient.GetVolumeUsage(c.Context(), cluster, namespace)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"volumes": volumes, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
+// GetStorageIssues returns StorageClass misconfigurations and nearly full
+// volumes
+func (h *MCPHandlers) GetStorageIssues(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "issues", getDemoStorageIssues())
+	}
+
+	cluster := c.Query("cluster")
+	namespace := c.Query("namespace")
+
+	if h.k8sClient != nil {
+		// If no cluster specified, query all clusters in parallel
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allIssues := make([]k8s.StorageIssue, 0)
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+					defer cancel()
+
+					issues, err := h.k8sClient.FindStorageIssues(ctx, clusterName, namespace)
+					if err == nil && len(issues) > 0 {
+						mu.Lock()
+						allIssues = append(allIssues, issues...)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"issues": allIssues, "source": "k8s"})
+		}
+
+		issues, err := h.k8sClient.FindStorageIssues(c.Context(), cluster, namespace)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"issues": issues, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
+// GetPriorityClasses returns PriorityClasses from clusters
+func (h *MCPHandlers) GetPriorityClasses(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "priorityClasses", getDemoPriorityClasses())
+	}
+
+	cluster := c.Query("cluster")
+
+	if h.k8sClient != nil {
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			var allClasses []k8s.PriorityClass
+			clusterTimeout := mcpDefaultTimeout
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), clusterTimeout)
+					defer cancel()
+
+					classes, err := h.k8sClient.GetPriorityClasses(ctx, clusterName)
+					if err == nil && len(classes) > 0 {
+						mu.Lock()
+						allClasses = append(allClasses, classes...)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"priorityClasses": allClasses, "source": "k8s"})
+		}
+
+		classes, err := h.k8sClient.GetPriorityClasses(c.Context(), cluster)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"priorityClasses": classes, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
+// GetPreemptions returns pods preempted in the last hour and the pods that
+// preempted them
+func (h *MCPHandlers) GetPreemptions(c *fiber.Ctx) error {
+	// Demo mode: return demo data immediately
+	if isDemoMode(c) {
+		return demoResponse(c, "preemptions", getDemoPreemptions())
+	}
+
+	cluster := c.Query("cluster")
+	namespace := c.Query("namespace")
+
+	if h.k8sClient != nil {
+		// If no cluster specified, query all clusters in parallel
+		if cluster == "" {
+			clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+			if err != nil {
+				log.Printf("internal error: %v", err)
+				return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+			}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allPreemptions := make([]k8s.Preemption, 0)
+
+			for _, cl := range clusters {
+				wg.Add(1)
+				go func(clusterName string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+					defer cancel()
+
+					preemptions, err := h.k8sClient.GetPreemptions(ctx, clusterName, namespace)
+					if err == nil && len(preemptions) > 0 {
+						mu.Lock()
+						allPreemptions = append(allPreemptions, preemptions...)
+						mu.Unlock()
+					}
+				}(cl.Name)
+			}
+
+			waitWithDeadline(&wg, maxResponseDeadline)
+			return c.JSON(fiber.Map{"preemptions": allPreemptions, "source": "k8s"})
+		}
+
+		preemptions, err := h.k8sClient.GetPreemptions(c.Context(), cluster, namespace)
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		return c.JSON(fiber.Map{"preemptions": preemptions, "source": "k8s"})
+	}
+
+	return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+}
+
 // CallToolRequest represents a request to call an MCP tool
 type CallToolRequest struct {
 	Name      string                 `json:"name"`
@@ -3,6 +3,7 @@ package handlers
 import (
 	"log"
 	"github.com/gofiber/fiber/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kubestellar/console/pkg/api/middleware"
 	"github.com/kubestellar/console/pkg/k8s"
@@ -42,6 +43,31 @@ func (h *NamespaceHandler) ListNamespaces(c *fiber.Ctx) error {
 	return c.JSON(namespaces)
 }
 
+// GetNamespaceDetail returns a namespace's quota utilization, limit ranges,
+// pod and workload counts, failing pods and secret and configmap counts
+func (h *NamespaceHandler) GetNamespaceDetail(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Kubernetes client not available")
+	}
+
+	cluster := c.Query("cluster")
+	name := c.Params("name")
+	if cluster == "" || name == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Cluster and namespace name are required")
+	}
+
+	detail, err := h.k8sClient.GetNamespaceDetail(c.Context(), cluster, name)
+	if apierrors.IsNotFound(err) {
+		return fiber.NewError(fiber.StatusNotFound, "Namespace not found")
+	}
+	if err != nil {
+		log.Printf("failed to get namespace detail: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	return c.JSON(detail)
+}
+
 // CreateNamespace creates a new namespace
 func (h *NamespaceHandler) CreateNamespace(c *fiber.Ctx) error {
 	if h.k8sClient == nil {
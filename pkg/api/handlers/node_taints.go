@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// NodeTaintHandler manages node taints
+type NodeTaintHandler struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewNodeTaintHandler creates a new node taint handler
+func NewNodeTaintHandler(s store.Store, k8sClient *k8s.MultiClusterClient) *NodeTaintHandler {
+	return &NodeTaintHandler{store: s, k8sClient: k8sClient}
+}
+
+// ListTaintTemplates returns the common GPU taints that can be added by name
+// GET /api/nodes/taint-templates
+func (h *NodeTaintHandler) ListTaintTemplates(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"templates": k8s.TaintTemplates})
+}
+
+// PreviewTaints returns a node's taints after a change and the pods it would
+// evict or keep from being scheduled back, without changing the node
+// POST /api/nodes/:cluster/:name/taints/preview
+func (h *NodeTaintHandler) PreviewTaints(c *fiber.Ctx) error {
+	return h.updateTaints(c, true)
+}
+
+// UpdateTaints adds and removes taints on a node. NoExecute taints evict the
+// node's pods, so this is admin only.
+// PUT /api/nodes/:cluster/:name/taints
+func (h *NodeTaintHandler) UpdateTaints(c *fiber.Ctx) error {
+	if err := requireAdmin(c, h.store); err != nil {
+		return err
+	}
+	return h.updateTaints(c, false)
+}
+
+func (h *NodeTaintHandler) updateTaints(c *fiber.Ctx, dryRun bool) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not available"})
+	}
+
+	var change k8s.TaintChange
+	if err := c.BodyParser(&change); err != nil {
+		log.Printf("invalid request body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	preview, err := h.k8sClient.UpdateNodeTaints(c.Context(), c.Params("cluster"), c.Params("name"), change, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, k8s.ErrInvalidTaintChange):
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case apierrors.IsNotFound(err):
+			return c.Status(404).JSON(fiber.Map{"error": "Node not found"})
+		case apierrors.IsForbidden(err):
+			return c.Status(403).JSON(fiber.Map{"error": "Forbidden"})
+		}
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.JSON(preview)
+}
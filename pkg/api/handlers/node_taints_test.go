@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+func TestUpdateTaintsRequiresAdmin(t *testing.T) {
+	fakeCS := k8sfake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-1"}})
+	k8sClient, _ := k8s.NewMultiClusterClient("")
+	k8sClient.InjectClient("c1", fakeCS)
+
+	userID := uuid.New()
+	mockStore := new(test.MockStore)
+	h := NewNodeTaintHandler(mockStore, k8sClient)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userID", userID)
+		return c.Next()
+	})
+	app.Put("/nodes/:cluster/:name/taints", h.UpdateTaints)
+
+	update := func() int {
+		body := `{"add":[{"key":"maintenance","effect":"NoExecute"}]}`
+		req, err := http.NewRequest("PUT", "/nodes/c1/gpu-1/taints", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, fiberTestTimeout)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	taints := func() []corev1.Taint {
+		node, err := fakeCS.CoreV1().Nodes().Get(context.Background(), "gpu-1", metav1.GetOptions{})
+		require.NoError(t, err)
+		return node.Spec.Taints
+	}
+
+	mockStore.On("GetUser", userID).Return(&models.User{ID: userID, Role: string(models.UserRoleViewer)}, nil).Once()
+	assert.Equal(t, http.StatusForbidden, update())
+	assert.Empty(t, taints(), "a non-admin must not taint the node")
+
+	mockStore.On("GetUser", userID).Return(&models.User{ID: userID, Role: string(models.UserRoleAdmin)}, nil).Once()
+	assert.Equal(t, http.StatusOK, update())
+	assert.Len(t, taints(), 1)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+// NodePoolHandler serves nodes grouped by their provisioner pool
+type NodePoolHandler struct {
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewNodePoolHandler creates a new node pool handler
+func NewNodePoolHandler(k8sClient *k8s.MultiClusterClient) *NodePoolHandler {
+	return &NodePoolHandler{k8sClient: k8sClient}
+}
+
+// ListNodePools returns the node pools of ?cluster or of every healthy cluster
+// with their aggregate capacity, GPUs, versions and health
+// GET /api/nodepools
+func (h *NodePoolHandler) ListNodePools(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	var clusterNames []string
+	if cluster := c.Query("cluster"); cluster != "" {
+		clusterNames = []string{cluster}
+	} else {
+		clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	pools := make([]k8s.NodePool, 0)
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			clusterPools, err := h.k8sClient.GetNodePools(ctx, clusterName)
+			if err != nil {
+				log.Printf("[NodePools] %s: %v", clusterName, err)
+				return
+			}
+			mu.Lock()
+			pools = append(pools, clusterPools...)
+			mu.Unlock()
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+	sort.SliceStable(pools, func(i, j int) bool { return pools[i].Cluster < pools[j].Cluster })
+
+	return c.JSON(fiber.Map{"pools": pools})
+}
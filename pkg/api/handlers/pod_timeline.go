@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// PodTimelineHandler rebuilds pod lifecycle timelines for incident reviews
+type PodTimelineHandler struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewPodTimelineHandler creates a new pod timeline handler
+func NewPodTimelineHandler(s store.Store, k8sClient *k8s.MultiClusterClient) *PodTimelineHandler {
+	return &PodTimelineHandler{store: s, k8sClient: k8sClient}
+}
+
+// GetTimeline returns the lifecycle of the pods named ?pod in ?cluster and
+// ?namespace (scheduled, pulled, started, restarts, OOMKilled, deleted...)
+// from the recorded history merged with what the cluster still reports, so
+// it works after the pod has been garbage collected. Pods recreated with the
+// same name appear as separate instances; ?uid selects one of them.
+func (h *PodTimelineHandler) GetTimeline(c *fiber.Ctx) error {
+	cluster := c.Query("cluster")
+	namespace := c.Query("namespace")
+	pod := c.Query("pod")
+	uid := c.Query("uid")
+	if cluster == "" || namespace == "" || pod == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "cluster, namespace and pod are required"})
+	}
+
+	stored, err := h.store.GetPodTimelineEvents(cluster, namespace, pod)
+	if err != nil {
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+	entries := make([]k8s.PodTimelineEntry, 0, len(stored))
+	for _, e := range stored {
+		entries = append(entries, k8s.PodTimelineEntry(e))
+	}
+
+	exists := false
+	if h.k8sClient != nil {
+		ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+		defer cancel()
+		live, current, err := h.k8sClient.CollectPodTimeline(ctx, cluster, namespace)
+		if err != nil {
+			// The cluster may be gone too; the recorded history still answers
+			log.Printf("Pod timeline: live lookup in %s failed: %v", cluster, err)
+		} else {
+			currentUID, ok := current[namespace+"/"+pod]
+			exists = ok && (uid == "" || currentUID == uid)
+			for _, e := range live {
+				if e.Namespace == namespace && e.Pod == pod {
+					entries = append(entries, e)
+				}
+			}
+		}
+	}
+
+	timeline := make([]k8s.PodTimelineEntry, 0, len(entries))
+	instances := make([]string, 0)
+	seenUID := make(map[string]bool)
+	for _, e := range k8s.MergePodTimeline(entries) {
+		if e.PodUID != "" && !seenUID[e.PodUID] {
+			seenUID[e.PodUID] = true
+			instances = append(instances, e.PodUID)
+		}
+		if uid != "" && e.PodUID != uid {
+			continue
+		}
+		timeline = append(timeline, e)
+	}
+	if len(timeline) == 0 && !exists {
+		return c.Status(404).JSON(fiber.Map{"error": "no timeline recorded for this pod"})
+	}
+
+	resp := fiber.Map{
+		"cluster":   cluster,
+		"namespace": namespace,
+		"pod":       pod,
+		"exists":    exists,
+		"instances": instances,
+		"timeline":  timeline,
+	}
+	if len(timeline) > 0 {
+		resp["firstSeen"] = timeline[0].Timestamp
+		resp["lastSeen"] = timeline[len(timeline)-1].Timestamp
+	}
+	return c.JSON(resp)
+}
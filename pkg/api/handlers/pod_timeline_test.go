@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+// podTimelineTestStore returns a recorded history for a pod that no longer exists
+type podTimelineTestStore struct {
+	test.MockStore
+	events []models.PodTimelineEvent
+}
+
+func (s *podTimelineTestStore) GetPodTimelineEvents(cluster, namespace, pod string) ([]models.PodTimelineEvent, error) {
+	return s.events, nil
+}
+
+func TestPodTimelineAfterGarbageCollection(t *testing.T) {
+	env := setupTestEnv(t)
+	at := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	event := func(id, uid, stage string, offset time.Duration) models.PodTimelineEvent {
+		return models.PodTimelineEvent{ID: id, Cluster: "test-cluster", Namespace: "shop", Pod: "api-1",
+			PodUID: uid, Stage: stage, Source: "event", Timestamp: at.Add(offset)}
+	}
+	store := &podTimelineTestStore{events: []models.PodTimelineEvent{
+		event("1", "old", k8s.PodStageScheduled, 0),
+		event("2", "old", k8s.PodStageOOMKilled, 3*time.Minute),
+		event("3", "old", k8s.PodStageDeleted, 5*time.Minute),
+		event("4", "new", k8s.PodStageScheduled, 6*time.Minute),
+	}}
+	handler := NewPodTimelineHandler(store, env.K8sClient)
+	env.App.Get("/api/pods/timeline", handler.GetTimeline)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pods/timeline?cluster=test-cluster&namespace=shop&pod=api-1&uid=old", nil)
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Exists    bool                   `json:"exists"`
+		Instances []string               `json:"instances"`
+		Timeline  []k8s.PodTimelineEntry `json:"timeline"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.False(t, body.Exists)
+	assert.Equal(t, []string{"old", "new"}, body.Instances)
+	require.Len(t, body.Timeline, 3)
+	assert.Equal(t, k8s.PodStageOOMKilled, body.Timeline[1].Stage)
+	assert.Equal(t, k8s.PodStageDeleted, body.Timeline[2].Stage)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/pods/timeline?cluster=test-cluster&namespace=shop", nil)
+	resp, err = env.App.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -454,3 +454,41 @@ func (h *RBACHandler) CheckCanI(c *fiber.Ctx) error {
 		Reason:  result.Reason,
 	})
 }
+
+// GetRBACRisks returns the risky RBAC permissions per subject and the unused
+// roles of one cluster, or of every healthy cluster when none is given
+func (h *RBACHandler) GetRBACRisks(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Kubernetes client not available")
+	}
+
+	cluster := c.Query("cluster")
+	includeSystem := c.Query("includeSystem") == "true"
+
+	ctx, cancel := context.WithTimeout(c.Context(), rbacAnalysisTimeout)
+	defer cancel()
+
+	if cluster != "" {
+		report, err := h.k8sClient.AnalyzeRBACRisks(ctx, cluster, includeSystem)
+		if err != nil {
+			log.Printf("failed to analyze RBAC risks: %v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+		}
+		return c.JSON([]k8s.RBACRiskReport{*report})
+	}
+
+	clusters, _, err := h.k8sClient.HealthyClusters(ctx)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list clusters")
+	}
+
+	reports := make([]k8s.RBACRiskReport, 0, len(clusters))
+	for _, cl := range clusters {
+		report, err := h.k8sClient.AnalyzeRBACRisks(ctx, cl.Name, includeSystem)
+		if err != nil {
+			continue // Skip clusters we can't access
+		}
+		reports = append(reports, *report)
+	}
+	return c.JSON(reports)
+}
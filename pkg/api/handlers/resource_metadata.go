@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// ResourceMetadataHandler edits the labels and annotations of arbitrary resources
+type ResourceMetadataHandler struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewResourceMetadataHandler creates a new resource metadata handler
+func NewResourceMetadataHandler(s store.Store, k8sClient *k8s.MultiClusterClient) *ResourceMetadataHandler {
+	return &ResourceMetadataHandler{store: s, k8sClient: k8sClient}
+}
+
+// EditMetadata adds and removes labels and annotations on one resource or on
+// every resource of a kind matching a label selector. With dryRun set the
+// response previews each object's resulting metadata without changing it.
+// Labels steer scheduling and policy on nodes and namespaces alike, so only
+// admins can apply edits.
+// POST /api/resources/metadata
+func (h *ResourceMetadataHandler) EditMetadata(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not available"})
+	}
+
+	var req struct {
+		Cluster string `json:"cluster"`
+		k8s.MetadataEdit
+	}
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("invalid request body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if req.Cluster == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "cluster is required"})
+	}
+	if !req.DryRun {
+		if err := requireAdmin(c, h.store); err != nil {
+			return err
+		}
+	}
+
+	report, err := h.k8sClient.EditMetadata(c.Context(), req.Cluster, req.MetadataEdit)
+	if err != nil {
+		switch {
+		case errors.Is(err, k8s.ErrInvalidMetadataEdit):
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case apierrors.IsNotFound(err):
+			return c.Status(404).JSON(fiber.Map{"error": "Resource not found"})
+		case apierrors.IsForbidden(err):
+			return c.Status(403).JSON(fiber.Map{"error": "Forbidden"})
+		}
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.JSON(report)
+}
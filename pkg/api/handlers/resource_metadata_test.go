@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+func TestEditMetadataRequiresAdmin(t *testing.T) {
+	k8sClient, _ := k8s.NewMultiClusterClient("")
+	k8sClient.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1"}}})
+	fakeCS := k8sfake.NewSimpleClientset()
+	fakeCS.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "nodes", Kind: "Node"}}},
+	}
+	k8sClient.InjectClient("c1", fakeCS)
+	scheme := runtime.NewScheme()
+	metav1.AddMetaToScheme(scheme)
+	fakeMeta := metadatafake.NewSimpleMetadataClient(scheme, &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-1"},
+	})
+	k8sClient.InjectMetadataClient("c1", fakeMeta)
+
+	userID := uuid.New()
+	mockStore := new(test.MockStore)
+	h := NewResourceMetadataHandler(mockStore, k8sClient)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userID", userID)
+		return c.Next()
+	})
+	app.Post("/resources/metadata", h.EditMetadata)
+
+	edit := func(dryRun bool) int {
+		body := `{"cluster":"c1","apiVersion":"v1","kind":"Node","name":"gpu-1","addLabels":{"pool":"training"}`
+		if dryRun {
+			body += `,"dryRun":true`
+		}
+		req, err := http.NewRequest("POST", "/resources/metadata", strings.NewReader(body+"}"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, fiberTestTimeout)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	labels := func() map[string]string {
+		obj, err := fakeMeta.Resource(schema.GroupVersionResource{Version: "v1", Resource: "nodes"}).Get(context.Background(), "gpu-1", metav1.GetOptions{})
+		require.NoError(t, err)
+		return obj.Labels
+	}
+
+	// Anyone can preview an edit
+	assert.Equal(t, http.StatusOK, edit(true))
+
+	mockStore.On("GetUser", userID).Return(&models.User{ID: userID, Role: string(models.UserRoleViewer)}, nil).Once()
+	assert.Equal(t, http.StatusForbidden, edit(false))
+	assert.Empty(t, labels(), "a non-admin must not relabel the node")
+
+	mockStore.On("GetUser", userID).Return(&models.User{ID: userID, Role: string(models.UserRoleAdmin)}, nil).Once()
+	assert.Equal(t, http.StatusOK, edit(false))
+	assert.Equal(t, "training", labels()["pool"])
+}
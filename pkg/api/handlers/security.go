@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultPostureTrendDays is how far back the posture trend goes by default
+	defaultPostureTrendDays = 30
+	// maxPostureTrendDays caps the trend window to the snapshot retention period
+	maxPostureTrendDays = 90
+)
+
+// SecurityHandler serves security posture scores and their history
+type SecurityHandler struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewSecurityHandler creates a new security handler
+func NewSecurityHandler(s store.Store, k8sClient *k8s.MultiClusterClient) *SecurityHandler {
+	return &SecurityHandler{store: s, k8sClient: k8sClient}
+}
+
+// GetPosture returns the current security score of each cluster and its
+// namespaces, with the recorded scores over the last ?days (default 30).
+// ?cluster limits both to one cluster; ?namespace selects which trend is
+// returned, the cluster-wide one when empty.
+func (h *SecurityHandler) GetPosture(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	cluster := c.Query("cluster")
+	namespace := c.Query("namespace")
+	days := defaultPostureTrendDays
+	if v := c.Query("days"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return c.Status(400).JSON(fiber.Map{"error": "days must be a positive integer"})
+		}
+		days = min(parsed, maxPostureTrendDays)
+	}
+
+	var clusterNames []string
+	if cluster != "" {
+		clusterNames = []string{cluster}
+	} else {
+		clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	postures := make([]k8s.SecurityPosture, 0, len(clusterNames))
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			posture, err := h.k8sClient.GetSecurityPosture(ctx, clusterName)
+			if err != nil {
+				log.Printf("[Security] posture of %s: %v", clusterName, err)
+				return
+			}
+			mu.Lock()
+			postures = append(postures, *posture)
+			mu.Unlock()
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Slice(postures, func(i, j int) bool { return postures[i].Cluster < postures[j].Cluster })
+
+	snapshots, err := h.store.GetSecurityPostureSnapshots(cluster, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+	trend := make([]models.SecurityPostureSnapshot, 0, len(snapshots))
+	for _, s := range snapshots {
+		if s.Namespace == namespace {
+			trend = append(trend, s)
+		}
+	}
+
+	return c.JSON(fiber.Map{"clusters": postures, "trend": trend, "days": days})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/test"
+)
+
+func TestGetSecurityPosture(t *testing.T) {
+	env := setupTestEnv(t)
+	privileged := true
+	env.K8sClient.InjectClient("test-cluster", k8sfake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "priv", Namespace: "default"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:            "app",
+			SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+		}}},
+	}))
+	handler := NewSecurityHandler(new(test.MockStore), env.K8sClient)
+	env.App.Get("/api/security/posture", handler.GetPosture)
+
+	req, err := http.NewRequest("GET", "/api/security/posture", nil)
+	require.NoError(t, err)
+	resp, err := env.App.Test(req, fiberTestTimeout)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	var result struct {
+		Clusters []k8s.SecurityPosture `json:"clusters"`
+		Trend    []json.RawMessage     `json:"trend"`
+		Days     int                   `json:"days"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.Len(t, result.Clusters, 1)
+	assert.Equal(t, "test-cluster", result.Clusters[0].Cluster)
+	assert.Equal(t, 1, result.Clusters[0].Pods)
+	assert.Less(t, result.Clusters[0].Score, 100.0)
+	assert.Equal(t, defaultPostureTrendDays, result.Days)
+	assert.NotNil(t, result.Trend)
+
+	req, _ = http.NewRequest("GET", "/api/security/posture?days=abc", nil)
+	resp, err = env.App.Test(req, fiberTestTimeout)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
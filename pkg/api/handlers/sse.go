@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
 )
 
 // sseClusterStreamConfig describes a single streaming endpoint configuration.
@@ -70,6 +73,21 @@ func sseCacheSet(key string, data interface{}) {
 	sseCacheMu.Unlock()
 }
 
+// sseCacheInvalidate drops the cached responses of a cluster, or of every
+// cluster when cluster is empty
+func sseCacheInvalidate(cluster string) int {
+	sseCacheMu.Lock()
+	defer sseCacheMu.Unlock()
+	dropped := 0
+	for key := range sseCache {
+		if cluster == "" || strings.HasSuffix(key, ":"+cluster) {
+			delete(sseCache, key)
+			dropped++
+		}
+	}
+	return dropped
+}
+
 // streamClusters is a generic helper that streams per-cluster results as SSE events.
 //
 // It uses HealthyClusters() to skip known-offline clusters (emitting
@@ -136,7 +154,7 @@ func streamClusters(
 				defer wg.Done()
 
 				// Use shorter timeout for clusters that recently timed out
-				timeout := cfg.clusterTimeout
+				timeout := h.k8sClient.OperationTimeout(clusterName, k8s.OpList, cfg.clusterTimeout)
 				if h.k8sClient.IsSlow(clusterName) {
 					timeout = sseSlowClusterTimeout
 				}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/k8s"
+)
+
+const (
+	// defaultTopLimit is how many consumers /top returns by default
+	defaultTopLimit = 10
+	// maxTopLimit caps the number of consumers /top returns
+	maxTopLimit = 100
+)
+
+// TopHandler ranks pods and namespaces by resource consumption
+type TopHandler struct {
+	k8sClient *k8s.MultiClusterClient
+}
+
+// NewTopHandler creates a new top consumers handler
+func NewTopHandler(k8sClient *k8s.MultiClusterClient) *TopHandler {
+	return &TopHandler{k8sClient: k8sClient}
+}
+
+// GetTopPods returns the ?limit (default 10) pods with the highest ?metric
+// (cpu, memory, restarts or gpu; default cpu) in ?cluster or across all
+// healthy clusters
+func (h *TopHandler) GetTopPods(c *fiber.Ctx) error {
+	return h.getTop(c, false)
+}
+
+// GetTopNamespaces is GetTopPods with consumption summed per namespace
+func (h *TopHandler) GetTopNamespaces(c *fiber.Ctx) error {
+	return h.getTop(c, true)
+}
+
+func (h *TopHandler) getTop(c *fiber.Ctx, byNamespace bool) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "No cluster access available"})
+	}
+
+	metric := c.Query("metric", k8s.TopMetricCPU)
+	if !k8s.IsTopMetric(metric) {
+		return c.Status(400).JSON(fiber.Map{"error": "metric must be one of cpu, memory, restarts, gpu"})
+	}
+	limit := defaultTopLimit
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return c.Status(400).JSON(fiber.Map{"error": "limit must be a positive integer"})
+		}
+		limit = min(parsed, maxTopLimit)
+	}
+
+	var clusterNames []string
+	if cluster := c.Query("cluster"); cluster != "" {
+		clusterNames = []string{cluster}
+	} else {
+		clusters, _, err := h.k8sClient.HealthyClusters(c.Context())
+		if err != nil {
+			log.Printf("internal error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+		}
+		for _, cl := range clusters {
+			clusterNames = append(clusterNames, cl.Name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	consumers := make([]k8s.TopConsumer, 0)
+	for _, name := range clusterNames {
+		wg.Add(1)
+		go func(clusterName string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.Context(), mcpDefaultTimeout)
+			defer cancel()
+
+			top, err := h.k8sClient.GetTopConsumers(ctx, clusterName, metric, byNamespace, limit)
+			if err != nil {
+				log.Printf("[Top] %s consumers of %s: %v", metric, clusterName, err)
+				return
+			}
+			mu.Lock()
+			consumers = append(consumers, top...)
+			mu.Unlock()
+		}(name)
+	}
+	waitWithDeadline(&wg, maxResponseDeadline)
+	mu.Lock()
+	defer mu.Unlock()
+
+	return c.JSON(fiber.Map{"metric": metric, "items": k8s.RankTopConsumers(consumers, limit)})
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -16,6 +17,7 @@ import (
 	"github.com/kubestellar/console/pkg/api/middleware"
 	"github.com/kubestellar/console/pkg/k8s"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -808,6 +810,82 @@ func (h *WorkloadHandlers) ScaleWorkload(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
+// GetRolloutHistory returns the revision history of a Deployment or StatefulSet
+// with the image and env changes of each revision
+// GET /api/workloads/history/:cluster/:namespace/:name?kind=Deployment
+func (h *WorkloadHandlers) GetRolloutHistory(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not available"})
+	}
+
+	kind := c.Query("kind", "Deployment")
+	history, err := h.k8sClient.GetRolloutHistory(c.Context(), c.Params("cluster"), c.Params("namespace"), kind, c.Params("name"))
+	if err != nil {
+		if errors.Is(err, k8s.ErrUnsupportedRolloutKind) {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		if apierrors.IsNotFound(err) {
+			return c.Status(404).JSON(fiber.Map{"error": "Workload not found"})
+		}
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.JSON(history)
+}
+
+// RollbackWorkload rolls a Deployment or StatefulSet back to a revision;
+// revision 0 means the one before the current revision
+// POST /api/workloads/rollback
+func (h *WorkloadHandlers) RollbackWorkload(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not available"})
+	}
+
+	type RollbackRequest struct {
+		Cluster   string `json:"cluster"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+		Kind      string `json:"kind"`
+		Revision  int64  `json:"revision"`
+	}
+
+	var req RollbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("invalid request body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if req.Cluster == "" || req.Namespace == "" || req.Name == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "cluster, namespace and name are required"})
+	}
+	if req.Revision < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "revision must not be negative"})
+	}
+	if req.Kind == "" {
+		req.Kind = "Deployment"
+	}
+
+	revision, err := h.k8sClient.RollbackWorkload(c.Context(), req.Cluster, req.Namespace, req.Kind, req.Name, req.Revision)
+	if err != nil {
+		switch {
+		case errors.Is(err, k8s.ErrUnsupportedRolloutKind):
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, k8s.ErrRevisionNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": "revision not found"})
+		case apierrors.IsNotFound(err):
+			return c.Status(404).JSON(fiber.Map{"error": "Workload not found"})
+		}
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	log.Printf("rolled back %s %s/%s on %s to revision %d", req.Kind, req.Namespace, req.Name, req.Cluster, revision)
+	return c.JSON(fiber.Map{
+		"message":  fmt.Sprintf("Rolled back %s to revision %d", req.Name, revision),
+		"revision": revision,
+	})
+}
+
 // DeleteWorkload deletes a workload from specified clusters
 // DELETE /api/workloads/:cluster/:namespace/:name
 func (h *WorkloadHandlers) DeleteWorkload(c *fiber.Ctx) error {
@@ -864,6 +942,64 @@ func (h *WorkloadHandlers) ListBindingPolicies(c *fiber.Ctx) error {
 	return c.JSON(policies)
 }
 
+// SimulateBindingPolicy reports which clusters a proposed BindingPolicy would
+// select and which workloads it would propagate, without applying anything
+// POST /api/workloads/policies/simulate
+func (h *WorkloadHandlers) SimulateBindingPolicy(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not available"})
+	}
+
+	var req k8s.PlacementSimulationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("invalid request body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if err := req.Policy.Validate(); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), workloadListTimeout)
+	defer cancel()
+
+	sim, err := h.k8sClient.SimulatePlacement(ctx, req)
+	if err != nil {
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.JSON(sim)
+}
+
+// GetWorkloadPropagation reports per execution cluster whether a WDS workload
+// was applied and whether it is available or degraded there
+// GET /api/workloads/propagation/:namespace/:name?kind=Deployment&its=its1&wds=wds1
+func (h *WorkloadHandlers) GetWorkloadPropagation(c *fiber.Ctx) error {
+	if h.k8sClient == nil {
+		return c.Status(503).JSON(fiber.Map{"error": "Kubernetes client not available"})
+	}
+
+	namespace := c.Params("namespace")
+	name := c.Params("name")
+	kind := c.Query("kind", "Deployment")
+	switch kind {
+	case "Deployment", "StatefulSet", "DaemonSet":
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "kind must be Deployment, StatefulSet or DaemonSet"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), workloadListTimeout)
+	defer cancel()
+
+	propagation, err := h.k8sClient.GetWorkloadPropagation(ctx, c.Query("its"), c.Query("wds"), kind, namespace, name)
+	if err != nil {
+		log.Printf("internal error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "internal server error"})
+	}
+
+	return c.JSON(propagation)
+}
+
 // GetDeployLogs returns Kubernetes events and recent log lines from a workload's pods.
 // Events are more useful than pod stdout during deployment (image pulls, scheduling, etc.).
 // GET /api/workloads/deploy-logs/:cluster/:namespace/:name?tail=8
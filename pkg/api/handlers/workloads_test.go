@@ -269,6 +269,41 @@ func TestDeleteWorkload(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+func TestRolloutHistoryAndRollback(t *testing.T) {
+	env := setupTestEnv(t)
+	handler := NewWorkloadHandlers(env.K8sClient, env.Hub)
+	env.App.Get("/api/workloads/history/:cluster/:namespace/:name", handler.GetRolloutHistory)
+	env.App.Post("/api/workloads/rollback", handler.RollbackWorkload)
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	injectDynamicClusterWithObjects(env, "c1", newK8sScheme(), nil, deploy)
+
+	get := func(url string) int {
+		req, err := http.NewRequest("GET", url, nil)
+		require.NoError(t, err)
+		resp, err := env.App.Test(req, 5000)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	assert.Equal(t, 200, get("/api/workloads/history/c1/default/web"))
+	assert.Equal(t, 404, get("/api/workloads/history/c1/default/missing"))
+	assert.Equal(t, 400, get("/api/workloads/history/c1/default/web?kind=DaemonSet"))
+
+	// The deployment has no ReplicaSets, so there is nothing to roll back to
+	data, _ := json.Marshal(map[string]interface{}{"cluster": "c1", "namespace": "default", "name": "web"})
+	req, err := http.NewRequest("POST", "/api/workloads/rollback", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := env.App.Test(req, 5000)
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
 func TestClusterGroupsCRUD(t *testing.T) {
 	env := setupTestEnv(t)
 	handler := NewWorkloadHandlers(env.K8sClient, env.Hub)
@@ -633,3 +668,53 @@ func TestGetDeployLogs(t *testing.T) {
 		assert.Equal(t, 500, resp.StatusCode)
 	})
 }
+
+func TestSimulateBindingPolicy(t *testing.T) {
+	env := setupTestEnv(t)
+	handler := NewWorkloadHandlers(env.K8sClient, env.Hub)
+	env.App.Post("/api/workloads/policies/simulate", handler.SimulateBindingPolicy)
+
+	scheme := newK8sScheme()
+	deployment := func(name, namespace string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": name}},
+		}
+	}
+	injectDynamicClusterWithObjects(env, "wds1", scheme,
+		[]runtime.Object{deployment("web", "default"), deployment("db", "data")})
+	injectDynamicClusterWithObjects(env, "edge-1", scheme, nil)
+
+	post := func(body string) (*http.Response, map[string]interface{}) {
+		req, err := http.NewRequest("POST", "/api/workloads/policies/simulate", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := env.App.Test(req, 5000)
+		require.NoError(t, err)
+		var result map[string]interface{}
+		data, _ := io.ReadAll(resp.Body)
+		json.Unmarshal(data, &result)
+		return resp, result
+	}
+
+	t.Run("MatchesClustersAndWorkloads", func(t *testing.T) {
+		resp, result := post(`{
+			"wds": "wds1",
+			"policy": {
+				"clusterSelectors": [{"matchLabels": {"name": "edge-1"}}],
+				"downsync": [{"apiGroup": "apps", "resources": ["deployments"], "objectSelectors": [{"matchLabels": {"app": "web"}}]}]
+			}
+		}`)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, []interface{}{"edge-1"}, result["matchedClusters"])
+		workloads := result["workloads"].([]interface{})
+		require.Len(t, workloads, 1)
+		assert.Equal(t, "web", workloads[0].(map[string]interface{})["name"])
+		assert.Equal(t, float64(1), result["placements"])
+	})
+
+	t.Run("InvalidSelector_Returns400", func(t *testing.T) {
+		resp, _ := post(`{"policy": {"clusterSelectors": [{"matchExpressions": [{"key": "name", "operator": "Bogus"}]}]}}`)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/audit"
+)
+
+// AuditMutations forwards every POST, PUT, PATCH and DELETE request with its
+// user and outcome to the audit forwarder. It must run after JWTAuth so the
+// user is known. Without a forwarder it does nothing.
+func AuditMutations(forwarder *audit.Forwarder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if forwarder == nil {
+			return c.Next()
+		}
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		err := c.Next()
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+		outcome := audit.OutcomeSuccess
+		if status >= fiber.StatusBadRequest {
+			outcome = audit.OutcomeFailure
+		}
+		forwarder.Record(audit.Entry{
+			Category: audit.CategoryMutation,
+			Action:   c.Method() + " " + c.Route().Path,
+			Actor:    GetGitHubLogin(c),
+			Outcome:  outcome,
+			Target:   c.Path(),
+			SourceIP: c.IP(),
+			Details:  map[string]string{"status": strconv.Itoa(status)},
+		})
+		return err
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/kubestellar/console/pkg/audit"
+)
+
+func TestAuditMutations(t *testing.T) {
+	var mu sync.Mutex
+	var received []audit.Entry
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			var e audit.Entry
+			if err := json.Unmarshal([]byte(line), &e); err == nil {
+				received = append(received, e)
+			}
+		}
+	}))
+	defer collector.Close()
+
+	forwarder, err := audit.NewForwarder(audit.Config{URL: collector.URL, Source: "console"})
+	if err != nil {
+		t.Fatalf("NewForwarder failed: %v", err)
+	}
+
+	app := fiber.New()
+	api := app.Group("/api", func(c *fiber.Ctx) error {
+		c.Locals("githubLogin", "alice")
+		return c.Next()
+	}, AuditMutations(forwarder))
+	api.Get("/items", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	api.Delete("/items/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+	api.Post("/items", func(c *fiber.Ctx) error { return fiber.NewError(fiber.StatusForbidden, "nope") })
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/items", nil),
+		httptest.NewRequest(http.MethodDelete, "/api/items/42", nil),
+		httptest.NewRequest(http.MethodPost, "/api/items", nil),
+	} {
+		if _, err := app.Test(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	forwarder.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("Expected only the 2 mutations to be audited, got %+v", received)
+	}
+	del, post := received[0], received[1]
+	if del.Action != "DELETE /api/items/:id" || del.Target != "/api/items/42" || del.Actor != "alice" || del.Outcome != audit.OutcomeSuccess {
+		t.Errorf("Unexpected DELETE entry: %+v", del)
+	}
+	if post.Outcome != audit.OutcomeFailure || post.Details["status"] != "403" {
+		t.Errorf("Unexpected POST entry: %+v", post)
+	}
+}
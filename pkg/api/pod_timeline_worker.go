@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+const (
+	// defaultPodTimelineIntervalMs is the default interval between pod timeline collections (1 minute)
+	defaultPodTimelineIntervalMs = 60_000
+	// podTimelineRetentionDays is how long recorded pod lifecycle events are kept
+	podTimelineRetentionDays = 30
+	// podTimelineCleanupInterval is how often expired pod timeline events are removed
+	podTimelineCleanupInterval = 24 * time.Hour
+)
+
+// PodTimelineWorker periodically records pod lifecycle events of every healthy
+// cluster, and notices pods disappearing, so a pod's timeline can be rebuilt
+// after the pod and its Kubernetes events have been garbage collected
+type PodTimelineWorker struct {
+	store       store.Store
+	k8sClient   *k8s.MultiClusterClient
+	interval    time.Duration
+	stopCh      chan struct{}
+	seen        map[string]map[string]string // cluster -> namespace/pod -> UID
+	lastCleanup time.Time
+}
+
+// NewPodTimelineWorker creates a new pod timeline worker
+func NewPodTimelineWorker(s store.Store, k8sClient *k8s.MultiClusterClient) *PodTimelineWorker {
+	intervalMs := defaultPodTimelineIntervalMs
+	if envVal := os.Getenv("POD_TIMELINE_INTERVAL_MS"); envVal != "" {
+		if parsed, err := strconv.Atoi(envVal); err == nil && parsed > 0 {
+			intervalMs = parsed
+		}
+	}
+
+	return &PodTimelineWorker{
+		store:     s,
+		k8sClient: k8sClient,
+		interval:  time.Duration(intervalMs) * time.Millisecond,
+		stopCh:    make(chan struct{}),
+		seen:      make(map[string]map[string]string),
+	}
+}
+
+// Start begins the background polling loop
+func (w *PodTimelineWorker) Start() {
+	go func() {
+		w.collect()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.collect()
+			case <-w.stopCh:
+				return
+			}
+		}
+	}()
+	log.Printf("Pod timeline worker started (interval: %v)", w.interval)
+}
+
+// Stop signals the worker to stop
+func (w *PodTimelineWorker) Stop() {
+	close(w.stopCh)
+}
+
+// collect records the current timeline entries of every healthy cluster and
+// a deleted entry for each pod that was present last time but is gone now
+func (w *PodTimelineWorker) collect() {
+	if time.Since(w.lastCleanup) >= podTimelineCleanupInterval {
+		w.cleanupOldEvents()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.interval/2)
+	defer cancel()
+
+	clusters, _, err := w.k8sClient.HealthyClusters(ctx)
+	if err != nil {
+		log.Printf("Pod timeline worker: failed to list clusters: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, cluster := range clusters {
+		entries, current, err := w.k8sClient.CollectPodTimeline(ctx, cluster.Name, "")
+		if err != nil {
+			log.Printf("Pod timeline worker: failed to collect %s: %v", cluster.Name, err)
+			continue
+		}
+		entries = append(entries, deletedPods(cluster.Name, w.seen[cluster.Name], current, now)...)
+		w.seen[cluster.Name] = current
+		if len(entries) == 0 {
+			continue
+		}
+		if err := w.store.UpsertPodTimelineEvents(podTimelineEvents(entries)); err != nil {
+			log.Printf("Pod timeline worker: failed to record events for %s: %v", cluster.Name, err)
+		}
+	}
+}
+
+// cleanupOldEvents removes pod timeline events older than the retention period
+func (w *PodTimelineWorker) cleanupOldEvents() {
+	w.lastCleanup = time.Now()
+	cutoff := time.Now().AddDate(0, 0, -podTimelineRetentionDays)
+	deleted, err := w.store.DeleteOldPodTimelineEvents(cutoff)
+	if err != nil {
+		log.Printf("Pod timeline worker: failed to cleanup old events: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Pod timeline worker: cleaned up %d old events", deleted)
+	}
+}
+
+// deletedPods returns a deleted entry for every previously seen pod that is
+// missing now or was replaced by a pod with the same name
+func deletedPods(cluster string, previous, current map[string]string, at time.Time) []k8s.PodTimelineEntry {
+	var entries []k8s.PodTimelineEntry
+	for key, uid := range previous {
+		if current[key] == uid {
+			continue
+		}
+		namespace, pod, _ := strings.Cut(key, "/")
+		entries = append(entries, k8s.PodDeletedEntry(cluster, namespace, pod, uid, at))
+	}
+	return entries
+}
+
+// podTimelineEvents converts timeline entries to their stored form
+func podTimelineEvents(entries []k8s.PodTimelineEntry) []models.PodTimelineEvent {
+	events := make([]models.PodTimelineEvent, len(entries))
+	for i, e := range entries {
+		events[i] = models.PodTimelineEvent(e)
+	}
+	return events
+}
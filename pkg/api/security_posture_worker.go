@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kubestellar/console/pkg/k8s"
+	"github.com/kubestellar/console/pkg/models"
+	"github.com/kubestellar/console/pkg/store"
+)
+
+// defaultPosturePollIntervalMs is the default interval between security posture snapshots (1 hour)
+const defaultPosturePollIntervalMs = 3_600_000
+
+// SecurityPostureWorker periodically records the security posture of every
+// healthy cluster so scores can be tracked over time
+type SecurityPostureWorker struct {
+	store     store.Store
+	k8sClient *k8s.MultiClusterClient
+	interval  time.Duration
+	stopCh    chan struct{}
+}
+
+// NewSecurityPostureWorker creates a new security posture worker
+func NewSecurityPostureWorker(s store.Store, k8sClient *k8s.MultiClusterClient) *SecurityPostureWorker {
+	intervalMs := defaultPosturePollIntervalMs
+	if envVal := os.Getenv("SECURITY_POSTURE_INTERVAL_MS"); envVal != "" {
+		if parsed, err := strconv.Atoi(envVal); err == nil && parsed > 0 {
+			intervalMs = parsed
+		}
+	}
+
+	return &SecurityPostureWorker{
+		store:     s,
+		k8sClient: k8sClient,
+		interval:  time.Duration(intervalMs) * time.Millisecond,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the background polling loop
+func (w *SecurityPostureWorker) Start() {
+	go func() {
+		w.cleanupOldSnapshots()
+		w.collectPosture()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.collectPosture()
+			case <-w.stopCh:
+				return
+			}
+		}
+	}()
+	log.Printf("Security posture worker started (interval: %v)", w.interval)
+}
+
+// Stop signals the worker to stop
+func (w *SecurityPostureWorker) Stop() {
+	close(w.stopCh)
+}
+
+// collectPosture records a cluster-level snapshot and one per namespace for every healthy cluster
+func (w *SecurityPostureWorker) collectPosture() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.interval/2)
+	defer cancel()
+
+	clusters, _, err := w.k8sClient.HealthyClusters(ctx)
+	if err != nil {
+		log.Printf("Security posture worker: failed to list clusters: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, cluster := range clusters {
+		posture, err := w.k8sClient.GetSecurityPosture(ctx, cluster.Name)
+		if err != nil {
+			log.Printf("Security posture worker: failed to score %s: %v", cluster.Name, err)
+			continue
+		}
+		snapshots := []*models.SecurityPostureSnapshot{{
+			ID:           uuid.New().String(),
+			Cluster:      posture.Cluster,
+			Timestamp:    now,
+			Score:        posture.Score,
+			Pods:         posture.Pods,
+			HighIssues:   posture.High,
+			MediumIssues: posture.Medium,
+			LowIssues:    posture.Low,
+		}}
+		for _, ns := range posture.Namespaces {
+			snapshots = append(snapshots, &models.SecurityPostureSnapshot{
+				ID:           uuid.New().String(),
+				Cluster:      posture.Cluster,
+				Namespace:    ns.Namespace,
+				Timestamp:    now,
+				Score:        ns.Score,
+				Pods:         ns.Pods,
+				HighIssues:   ns.High,
+				MediumIssues: ns.Medium,
+				LowIssues:    ns.Low,
+			})
+		}
+		for _, snapshot := range snapshots {
+			if err := w.store.InsertSecurityPostureSnapshot(snapshot); err != nil {
+				log.Printf("Security posture worker: failed to insert snapshot for %s: %v", cluster.Name, err)
+				break
+			}
+		}
+	}
+}
+
+// cleanupOldSnapshots removes snapshots older than the retention period
+func (w *SecurityPostureWorker) cleanupOldSnapshots() {
+	cutoff := time.Now().AddDate(0, 0, -snapshotRetentionDays)
+	deleted, err := w.store.DeleteOldSecurityPostureSnapshots(cutoff)
+	if err != nil {
+		log.Printf("Security posture worker: failed to cleanup old snapshots: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Security posture worker: cleaned up %d old snapshots", deleted)
+	}
+}
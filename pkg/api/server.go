@@ -22,6 +22,7 @@ import (
 	"github.com/kubestellar/console/pkg/agent"
 	"github.com/kubestellar/console/pkg/api/handlers"
 	"github.com/kubestellar/console/pkg/api/middleware"
+	"github.com/kubestellar/console/pkg/audit"
 	"github.com/kubestellar/console/pkg/k8s"
 	"github.com/kubestellar/console/pkg/mcp"
 	"github.com/kubestellar/console/pkg/notifications"
@@ -65,6 +66,7 @@ type Config struct {
 	// Feature request/feedback configuration
 	FeedbackGitHubToken  string // PAT for creating issues
 	GitHubWebhookSecret  string // Secret for validating GitHub webhooks
+	HooksSecret          string // Secret for validating inbound refresh hooks (/hooks)
 	FeedbackRepoOwner    string // GitHub org/owner (e.g., "kubestellar")
 	FeedbackRepoName     string // GitHub repo name (e.g., "console")
 	// GitHub activity rewards
@@ -76,6 +78,8 @@ type Config struct {
 	EnabledDashboards string // Comma-separated list of dashboard IDs to show in sidebar (empty = all)
 	// Watchdog support: when set, the backend listens on this port instead of Port
 	BackendPort int
+	// Optional forwarding of audit entries to a SIEM (AUDIT_FORWARD_*)
+	Audit audit.Config
 }
 
 // Server represents the API server
@@ -91,6 +95,12 @@ type Server struct {
 	loadingSrv          *http.Server // temporary loading screen server
 	shuttingDown        int32        // atomic flag: 1 during graceful shutdown
 	gpuUtilWorker       *GPUUtilizationWorker
+	postureWorker       *SecurityPostureWorker
+	podTimelineWorker   *PodTimelineWorker
+	clusterStateWorker  *ClusterStateWorker
+	automationEngine    *handlers.AutomationEngine
+	issueRegistry       *handlers.IssueRegistry
+	auditForwarder      *audit.Forwarder
 }
 
 // NewServer creates a new API server. It starts a temporary loading page
@@ -157,6 +167,7 @@ func NewServer(cfg Config) (*Server, error) {
 	if err != nil {
 		log.Println("No kubeconfig found — connect clusters via Settings or place a kubeconfig at ~/.kube/config")
 	} else {
+		k8sClient.SetClusterPolicySource(agent.SettingsClusterPolicy)
 		if err := k8sClient.LoadConfig(); err != nil {
 			log.Println("No kubeconfig found — connect clusters via Settings or place a kubeconfig at ~/.kube/config")
 		} else {
@@ -164,6 +175,8 @@ func NewServer(cfg Config) (*Server, error) {
 			// Warmup: probe all clusters to populate health cache before serving.
 			// Without this, first load hits ALL clusters (including offline) = 30s+ load.
 			k8sClient.WarmupHealthCache()
+			// Keep it warm afterwards so requests don't wait on probes
+			k8sClient.StartHealthRefresher()
 		}
 		k8sClient.SetOnReload(func() {
 			hub.BroadcastAll(handlers.Message{
@@ -207,6 +220,13 @@ func NewServer(cfg Config) (*Server, error) {
 	notificationService := notifications.NewService()
 	log.Println("Notification service initialized")
 
+	// Initialize audit forwarding (disabled unless AUDIT_FORWARD_URL is set)
+	audit.SetVersion(Version)
+	auditForwarder, err := audit.NewForwarder(cfg.Audit)
+	if err != nil {
+		log.Printf("Warning: audit forwarding disabled: %v", err)
+	}
+
 	// Initialize persistence store
 	persistenceConfigPath := filepath.Join(filepath.Dir(cfg.DatabasePath), "persistence.json")
 	persistenceStore := store.NewPersistenceStore(persistenceConfigPath)
@@ -232,15 +252,26 @@ func NewServer(cfg Config) (*Server, error) {
 		notificationService: notificationService,
 		persistenceStore:    persistenceStore,
 		loadingSrv:          loadingSrv,
+		automationEngine:    handlers.NewAutomationEngine(db, k8sClient, notificationService),
+		issueRegistry:       handlers.NewIssueRegistry(db, k8sClient),
+		auditForwarder:      auditForwarder,
 	}
 
 	server.setupMiddleware()
 	server.setupRoutes()
 
-	// Start GPU utilization background worker (collects hourly snapshots)
+	// Start GPU utilization and security posture background workers (collect periodic snapshots)
 	if k8sClient != nil {
 		server.gpuUtilWorker = NewGPUUtilizationWorker(db, k8sClient)
 		server.gpuUtilWorker.Start()
+		server.postureWorker = NewSecurityPostureWorker(db, k8sClient)
+		server.postureWorker.Start()
+		server.podTimelineWorker = NewPodTimelineWorker(db, k8sClient)
+		server.podTimelineWorker.Start()
+		server.clusterStateWorker = NewClusterStateWorker(db, k8sClient)
+		server.clusterStateWorker.Start()
+		server.automationEngine.Start()
+		server.issueRegistry.Start()
 	}
 
 	log.Println("Server initialization complete")
@@ -386,6 +417,7 @@ func (s *Server) setupRoutes() {
 		GitHubToken:      s.config.GitHubToken,
 		DevMode:          s.config.DevMode,
 		SkipOnboarding:   s.config.SkipOnboarding,
+		Audit:            s.auditForwarder,
 	})
 	s.app.Get("/auth/github", auth.GitHubLogin)
 	s.app.Get("/auth/github/callback", auth.GitHubCallback)
@@ -453,7 +485,7 @@ func (s *Server) setupRoutes() {
 	missions.RegisterPublicRoutes(s.app.Group("/api/missions"))
 
 	// API routes (protected)
-	api := s.app.Group("/api", middleware.JWTAuth(s.config.JWTSecret))
+	api := s.app.Group("/api", middleware.JWTAuth(s.config.JWTSecret), middleware.AuditMutations(s.auditForwarder))
 
 	// User routes
 	user := handlers.NewUserHandler(s.store)
@@ -523,11 +555,13 @@ func (s *Server) setupRoutes() {
 	api.Post("/rbac/bindings", rbac.CreateRoleBinding)
 	api.Get("/permissions/summary", rbac.GetPermissionsSummary)
 	api.Post("/rbac/can-i", rbac.CheckCanI)
+	api.Get("/rbac/risks", rbac.GetRBACRisks)
 
 	// Namespace management routes (admin only)
 	namespaces := handlers.NewNamespaceHandler(s.store, s.k8sClient)
 	api.Get("/namespaces", namespaces.ListNamespaces)
 	api.Post("/namespaces", namespaces.CreateNamespace)
+	api.Get("/namespaces/:name", namespaces.GetNamespaceDetail)
 	api.Delete("/namespaces/:name", namespaces.DeleteNamespace)
 	api.Get("/namespaces/:name/access", namespaces.GetNamespaceAccess)
 	api.Post("/namespaces/:name/access", namespaces.GrantNamespaceAccess)
@@ -555,10 +589,13 @@ func (s *Server) setupRoutes() {
 	api.Delete("/mcp/gpu-nodes/health/cronjob", mcpHandlers.UninstallGPUHealthCronJob)
 	api.Get("/mcp/gpu-nodes/health/cronjob/results", mcpHandlers.GetGPUHealthCronJobResults)
 	api.Get("/mcp/nvidia-operators", mcpHandlers.GetNVIDIAOperatorStatus)
+	api.Get("/mcp/amd-gpu-operators", mcpHandlers.GetAMDGPUOperatorStatus)
 	api.Get("/mcp/nodes", mcpHandlers.GetNodes)
 	api.Get("/mcp/events", mcpHandlers.GetEvents)
 	api.Get("/mcp/events/warnings", mcpHandlers.GetWarningEvents)
 	api.Get("/mcp/security-issues", mcpHandlers.CheckSecurityIssues)
+	api.Get("/mcp/pod-security", mcpHandlers.GetPodSecurityLevels)
+	api.Get("/mcp/platform-issues", mcpHandlers.GetPlatformIssues)
 	api.Get("/mcp/services", mcpHandlers.GetServices)
 	api.Get("/mcp/jobs", mcpHandlers.GetJobs)
 	api.Get("/mcp/hpas", mcpHandlers.GetHPAs)
@@ -566,7 +603,12 @@ func (s *Server) setupRoutes() {
 	api.Get("/mcp/secrets", mcpHandlers.GetSecrets)
 	api.Get("/mcp/serviceaccounts", mcpHandlers.GetServiceAccounts)
 	api.Get("/mcp/pvcs", mcpHandlers.GetPVCs)
+	api.Get("/mcp/volume-usage", mcpHandlers.GetVolumeUsage)
 	api.Get("/mcp/pvs", mcpHandlers.GetPVs)
+	api.Get("/mcp/storageclasses", mcpHandlers.GetStorageClasses)
+	api.Get("/mcp/storage-issues", mcpHandlers.GetStorageIssues)
+	api.Get("/mcp/priorityclasses", mcpHandlers.GetPriorityClasses)
+	api.Get("/mcp/preemptions", mcpHandlers.GetPreemptions)
 	api.Get("/mcp/resourcequotas", mcpHandlers.GetResourceQuotas)
 	api.Post("/mcp/resourcequotas", mcpHandlers.CreateOrUpdateResourceQuota)
 	api.Delete("/mcp/resourcequotas", mcpHandlers.DeleteResourceQuota)
@@ -623,6 +665,9 @@ func (s *Server) setupRoutes() {
 	api.Get("/gitops/argocd/health", gitopsHandlers.GetArgoHealthSummary)
 	api.Get("/gitops/argocd/sync", gitopsHandlers.GetArgoSyncSummary)
 	api.Post("/gitops/argocd/sync", gitopsHandlers.TriggerArgoSync)
+	// Flux resources and GitOps ownership of workloads (read-only)
+	api.Get("/gitops/flux", gitopsHandlers.ListFluxResources)
+	api.Get("/gitops/owners/:cluster/:namespace/:name", gitopsHandlers.GetGitOpsOwners)
 	// Frontend compatibility alias
 	api.Get("/mcp/operator-subscriptions", gitopsHandlers.ListOperatorSubscriptions)
 
@@ -655,6 +700,7 @@ func (s *Server) setupRoutes() {
 	// Admission webhook routes
 	webhookHandlers := handlers.NewWebhookHandlers(s.k8sClient)
 	api.Get("/admission-webhooks", webhookHandlers.ListWebhooks)
+	api.Get("/admission-webhooks/health", webhookHandlers.GetWebhookHealth)
 
 	// Service Topology routes
 	topologyHandlers := handlers.NewTopologyHandlers(s.k8sClient, s.hub)
@@ -665,15 +711,43 @@ func (s *Server) setupRoutes() {
 	api.Get("/workloads", workloadHandlers.ListWorkloads)
 	api.Get("/workloads/capabilities", workloadHandlers.GetClusterCapabilities)
 	api.Get("/workloads/policies", workloadHandlers.ListBindingPolicies)
+	api.Post("/workloads/policies/simulate", workloadHandlers.SimulateBindingPolicy)
 	api.Get("/workloads/deploy-status/:cluster/:namespace/:name", workloadHandlers.GetDeployStatus)
 	api.Get("/workloads/deploy-logs/:cluster/:namespace/:name", workloadHandlers.GetDeployLogs)
 	api.Get("/workloads/resolve-deps/:cluster/:namespace/:name", workloadHandlers.ResolveDependencies)
 	api.Get("/workloads/monitor/:cluster/:namespace/:name", workloadHandlers.MonitorWorkload)
+	api.Get("/workloads/history/:cluster/:namespace/:name", workloadHandlers.GetRolloutHistory)
+	api.Get("/workloads/propagation/:namespace/:name", workloadHandlers.GetWorkloadPropagation)
 	api.Get("/workloads/:cluster/:namespace/:name", workloadHandlers.GetWorkload)
 	api.Post("/workloads/deploy", workloadHandlers.DeployWorkload)
 	api.Post("/workloads/scale", workloadHandlers.ScaleWorkload)
+	api.Post("/workloads/rollback", workloadHandlers.RollbackWorkload)
 	api.Delete("/workloads/:cluster/:namespace/:name", workloadHandlers.DeleteWorkload)
 
+	// Label and annotation editing on arbitrary resources
+	resourceMetadata := handlers.NewResourceMetadataHandler(s.store, s.k8sClient)
+	api.Post("/resources/metadata", resourceMetadata.EditMetadata)
+
+	// Node taint routes
+	nodeTaints := handlers.NewNodeTaintHandler(s.store, s.k8sClient)
+	api.Get("/nodes/taint-templates", nodeTaints.ListTaintTemplates)
+	api.Post("/nodes/:cluster/:name/taints/preview", nodeTaints.PreviewTaints)
+	api.Put("/nodes/:cluster/:name/taints", nodeTaints.UpdateTaints)
+
+	// Nodes grouped by provisioner pool
+	nodePools := handlers.NewNodePoolHandler(s.k8sClient)
+	api.Get("/nodepools", nodePools.ListNodePools)
+
+	// Fleet GPU capacity per model and placement advice
+	gpuAdvisor := handlers.NewGPUAdvisorHandler(s.k8sClient)
+	api.Get("/gpus/advisor", gpuAdvisor.SuggestPlacement)
+	gpuOverview := handlers.NewGPUOverviewHandler(s.k8sClient)
+	api.Get("/gpus/overview", gpuOverview.GetOverview)
+
+	// Volume snapshots and Velero backups
+	dataProtection := handlers.NewDataProtectionHandler(s.k8sClient)
+	api.Get("/data-protection", dataProtection.GetDataProtection)
+
 	// Cluster Group routes
 	api.Get("/cluster-groups", workloadHandlers.ListClusterGroups)
 	api.Post("/cluster-groups", workloadHandlers.CreateClusterGroup)
@@ -730,6 +804,23 @@ func (s *Server) setupRoutes() {
 	api.Get("/gpu/reservations/:id/utilization", gpuHandler.GetReservationUtilization)
 	api.Get("/gpu/utilizations", gpuHandler.GetBulkUtilizations)
 
+	// Security posture scores and trend
+	securityHandler := handlers.NewSecurityHandler(s.store, s.k8sClient)
+	api.Get("/security/posture", securityHandler.GetPosture)
+
+	// What changed since an earlier point in time, from cluster state snapshots
+	changesHandler := handlers.NewChangesHandler(s.store, s.k8sClient)
+	api.Get("/changes", changesHandler.GetChanges)
+
+	// Pod lifecycle timelines for incident reviews, kept after pods are gone
+	podTimeline := handlers.NewPodTimelineHandler(s.store, s.k8sClient)
+	api.Get("/pods/timeline", podTimeline.GetTimeline)
+
+	// Top resource consumers per cluster or fleet-wide
+	topHandler := handlers.NewTopHandler(s.k8sClient)
+	api.Get("/top/pods", topHandler.GetTopPods)
+	api.Get("/top/namespaces", topHandler.GetTopNamespaces)
+
 	// Alert notification routes
 	notificationHandler := handlers.NewNotificationHandler(s.store, s.notificationService)
 	api.Post("/notifications/test", notificationHandler.TestNotification)
@@ -737,6 +828,40 @@ func (s *Server) setupRoutes() {
 	api.Get("/notifications/config", notificationHandler.GetNotificationConfig)
 	api.Post("/notifications/config", notificationHandler.SaveNotificationConfig)
 
+	// Automation rules ("if X happens, do Y") and their audit log
+	automation := handlers.NewAutomationHandler(s.store, s.automationEngine)
+	api.Get("/automation/rules", automation.ListRules)
+	api.Post("/automation/rules", automation.CreateRule)
+	api.Get("/automation/rules/:id", automation.GetRule)
+	api.Put("/automation/rules/:id", automation.UpdateRule)
+	api.Delete("/automation/rules/:id", automation.DeleteRule)
+	api.Post("/automation/rules/:id/run", automation.RunRule)
+	api.Get("/automation/runs", automation.ListRuns)
+
+	// Persistent issue registry with acknowledge, assign and snooze
+	issues := handlers.NewIssueHandler(s.store, s.issueRegistry)
+	api.Get("/issues", issues.ListIssues)
+	api.Get("/issues/:id", issues.GetIssue)
+	api.Post("/issues/:id/acknowledge", issues.Acknowledge)
+	api.Post("/issues/:id/assign", issues.Assign)
+	api.Post("/issues/:id/snooze", issues.Snooze)
+
+	// Remote kc-agent federation: registered agents, aggregated clusters, routed reads
+	federation := handlers.NewFederationHandler(s.store)
+	api.Get("/federation/agents", federation.ListAgents)
+	api.Post("/federation/agents", federation.RegisterAgent)
+	api.Delete("/federation/agents/:id", federation.DeleteAgent)
+	api.Get("/federation/clusters", federation.ListClusters)
+	api.Get("/federation/proxy/:endpoint", federation.Proxy)
+
+	// Guarded bulk rollout restarts: preview, bounded concurrency, abort
+	bulkRestart := handlers.NewBulkRestartHandler(s.store, s.k8sClient)
+	api.Post("/maintenance/restart/preview", bulkRestart.Preview)
+	api.Post("/maintenance/restart", bulkRestart.Start)
+	api.Get("/maintenance/restart", bulkRestart.ListOperations)
+	api.Get("/maintenance/restart/:id", bulkRestart.GetOperation)
+	api.Post("/maintenance/restart/:id/abort", bulkRestart.Abort)
+
 	// Console persistence routes (CRD-based state management)
 	persistenceHandler := handlers.NewConsolePersistenceHandlers(s.persistenceStore, s.k8sClient, s.hub)
 	api.Get("/persistence/config", persistenceHandler.GetConfig)
@@ -766,6 +891,10 @@ func (s *Server) setupRoutes() {
 	// GitHub webhook (public endpoint, uses signature verification)
 	s.app.Post("/webhooks/github", feedback.HandleGitHubWebhook)
 
+	// Refresh hooks from CI/GitOps (public endpoint, uses signature verification)
+	hooks := handlers.NewHooksHandler(s.k8sClient, s.hub, s.config.HooksSecret)
+	s.app.Post("/hooks", hooks.HandleHook)
+
 	// WebSocket for real-time updates
 	s.app.Use("/ws", middleware.WebSocketUpgrade())
 	s.app.Get("/ws", websocket.New(func(c *websocket.Conn) {
@@ -780,6 +909,13 @@ func (s *Server) setupRoutes() {
 		execHandlers.HandleExec(c)
 	}))
 
+	// WebSocket for tailing logs of every pod matching a label selector
+	logTailHandlers := handlers.NewLogTailHandlers(s.k8sClient)
+	s.app.Use("/ws/logs", middleware.WebSocketUpgrade())
+	s.app.Get("/ws/logs", websocket.New(func(c *websocket.Conn) {
+		logTailHandlers.HandleLogTail(c)
+	}))
+
 	// Serve static files in production
 	if !s.config.DevMode {
 		// Serve pre-compressed assets (.gz/.br) with Content-Length to avoid chunked encoding
@@ -910,15 +1046,30 @@ func (s *Server) Shutdown() error {
 	if s.gpuUtilWorker != nil {
 		s.gpuUtilWorker.Stop()
 	}
+	if s.postureWorker != nil {
+		s.postureWorker.Stop()
+	}
+	if s.podTimelineWorker != nil {
+		s.podTimelineWorker.Stop()
+	}
+	if s.clusterStateWorker != nil {
+		s.clusterStateWorker.Stop()
+	}
+	if s.k8sClient != nil {
+		s.automationEngine.Stop()
+		s.issueRegistry.Stop()
+	}
 	s.hub.Close()
 	if s.k8sClient != nil {
 		s.k8sClient.StopWatching()
+		s.k8sClient.StopHealthRefresher()
 	}
 	if s.bridge != nil {
 		if err := s.bridge.Stop(); err != nil {
 			log.Printf("Warning: MCP bridge shutdown error: %v", err)
 		}
 	}
+	s.auditForwarder.Close()
 	if err := s.store.Close(); err != nil {
 		return err
 	}
@@ -993,6 +1144,7 @@ func LoadConfigFromEnv() Config {
 		// Feature request/feedback configuration
 		FeedbackGitHubToken: os.Getenv("FEEDBACK_GITHUB_TOKEN"),
 		GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		HooksSecret:         os.Getenv("KC_HOOKS_SECRET"),
 		FeedbackRepoOwner:   getEnvOrDefault("FEEDBACK_REPO_OWNER", "kubestellar"),
 		FeedbackRepoName:    getEnvOrDefault("FEEDBACK_REPO_NAME", "console"),
 		// GitHub activity rewards
@@ -1006,6 +1158,8 @@ func LoadConfigFromEnv() Config {
 		EnabledDashboards: os.Getenv("ENABLED_DASHBOARDS"),
 		// Watchdog backend port override
 		BackendPort: backendPort,
+		// SIEM audit forwarding
+		Audit: audit.ConfigFromEnv("console"),
 	}
 }
 
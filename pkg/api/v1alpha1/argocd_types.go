@@ -27,6 +27,8 @@ type ArgoApplication struct {
 	HealthStatus string                `json:"healthStatus"` // Healthy, Degraded, Progressing, Missing, Unknown
 	Source       ArgoApplicationSource `json:"source"`
 	LastSynced   string                `json:"lastSynced,omitempty"`
+	// Resources are the objects the application manages, from status.resources
+	Resources []GitOpsResource `json:"resources,omitempty"`
 }
 
 // ArgoApplicationSource represents the source of an ArgoCD Application
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Flux CRD Group Version Resources
+var (
+	// FluxKustomizationGVR is the GroupVersionResource for Flux Kustomization (v1)
+	FluxKustomizationGVR = schema.GroupVersionResource{
+		Group:    "kustomize.toolkit.fluxcd.io",
+		Version:  "v1",
+		Resource: "kustomizations",
+	}
+
+	// FluxHelmReleaseGVR is the GroupVersionResource for Flux HelmRelease (v2)
+	FluxHelmReleaseGVR = schema.GroupVersionResource{
+		Group:    "helm.toolkit.fluxcd.io",
+		Version:  "v2",
+		Resource: "helmreleases",
+	}
+)
+
+// Flux resource kinds
+const (
+	FluxKindKustomization = "Kustomization"
+	FluxKindHelmRelease   = "HelmRelease"
+)
+
+// GitOpsResource is an object managed by a GitOps application, with its sync
+// and health state when the tool reports them
+type GitOpsResource struct {
+	Group        string `json:"group,omitempty"`
+	Kind         string `json:"kind"`
+	Namespace    string `json:"namespace,omitempty"`
+	Name         string `json:"name"`
+	SyncStatus   string `json:"syncStatus,omitempty"`   // Synced, OutOfSync
+	HealthStatus string `json:"healthStatus,omitempty"` // Healthy, Degraded, Progressing, Missing
+}
+
+// FluxResource represents a Flux Kustomization or HelmRelease
+type FluxResource struct {
+	Kind      string `json:"kind"` // Kustomization, HelmRelease
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	Ready     string `json:"ready"` // True, False, Unknown
+	Suspended bool   `json:"suspended"`
+	Message   string `json:"message,omitempty"`
+	// Source is the source or chart reconciled, e.g. GitRepository/flux-system or podinfo@6.5.0
+	Source      string           `json:"source,omitempty"`
+	Revision    string           `json:"revision,omitempty"` // last applied revision
+	LastApplied string           `json:"lastApplied,omitempty"`
+	Inventory   []GitOpsResource `json:"inventory,omitempty"`
+}
+
+// FluxResourceList is a list of Flux resources
+type FluxResourceList struct {
+	Items      []FluxResource `json:"items"`
+	TotalCount int            `json:"totalCount"`
+}
+
+// GitOpsOwner is a GitOps application that manages a workload, and whether
+// the workload has drifted from what it declares
+type GitOpsOwner struct {
+	Tool         string `json:"tool"` // argocd, flux
+	Kind         string `json:"kind"` // Application, Kustomization, HelmRelease
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Cluster      string `json:"cluster"`
+	SyncStatus   string `json:"syncStatus"`
+	HealthStatus string `json:"healthStatus"`
+	Drifted      bool   `json:"drifted"`
+	Message      string `json:"message,omitempty"`
+}
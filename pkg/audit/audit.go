@@ -0,0 +1,46 @@
+// Package audit forwards audit entries (mutations, logins and AI-executed
+// commands) to an external collector such as a SIEM, over HTTP or syslog, in
+// JSON or CEF
+package audit
+
+import "time"
+
+// Categories of audit entries
+const (
+	CategoryMutation = "mutation"
+	CategoryLogin    = "login"
+	CategoryCommand  = "command"
+)
+
+// Outcomes of audited actions
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Entry is one audited action
+type Entry struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Source    string            `json:"source"` // console or kc-agent
+	Category  string            `json:"category"`
+	Action    string            `json:"action"`
+	Actor     string            `json:"actor,omitempty"`
+	Outcome   string            `json:"outcome"`
+	Target    string            `json:"target,omitempty"`
+	SourceIP  string            `json:"sourceIp,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// severity rates an entry on the CEF 0-10 scale
+func (e Entry) severity() int {
+	switch {
+	case e.Outcome == OutcomeFailure:
+		return 7
+	case e.Category == CategoryCommand:
+		return 5
+	case e.Category == CategoryMutation:
+		return 3
+	default:
+		return 1
+	}
+}
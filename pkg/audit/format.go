@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Formats entries are forwarded in
+const (
+	FormatJSON = "json"
+	FormatCEF  = "cef"
+)
+
+const (
+	// cefVendor and cefProduct identify the console in CEF headers
+	cefVendor  = "KubeStellar"
+	cefProduct = "Console"
+	// syslogPriority is facility "log audit" (13) at severity informational (6)
+	syslogPriority = 13*8 + 6
+)
+
+// cefVersion is the product version reported in CEF headers
+var cefVersion = "dev"
+
+// SetVersion sets the product version reported in CEF headers
+func SetVersion(v string) {
+	if v != "" {
+		cefVersion = v
+	}
+}
+
+// Format encodes an entry as a single line in the given format
+func Format(e Entry, format string) ([]byte, error) {
+	if format == FormatCEF {
+		return []byte(formatCEF(e)), nil
+	}
+	return json.Marshal(e)
+}
+
+// formatCEF encodes an entry as ArcSight Common Event Format:
+// CEF:0|Vendor|Product|Version|SignatureID|Name|Severity|Extension
+func formatCEF(e Entry) string {
+	ext := []string{
+		"rt=" + cefExtension(fmt.Sprint(e.Timestamp.UnixMilli())),
+		"cat=" + cefExtension(e.Category),
+		"outcome=" + cefExtension(e.Outcome),
+	}
+	if e.Actor != "" {
+		ext = append(ext, "suser="+cefExtension(e.Actor))
+	}
+	if e.SourceIP != "" {
+		ext = append(ext, "src="+cefExtension(e.SourceIP))
+	}
+	if e.Target != "" {
+		ext = append(ext, "request="+cefExtension(e.Target))
+	}
+	if e.Source != "" {
+		ext = append(ext, "deviceProcessName="+cefExtension(e.Source))
+	}
+	if len(e.Details) > 0 {
+		keys := make([]string, 0, len(e.Details))
+		for k := range e.Details {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = k + ":" + e.Details[k]
+		}
+		ext = append(ext, "msg="+cefExtension(strings.Join(pairs, " ")))
+	}
+	return fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|%d|%s",
+		cefHeader(cefVendor), cefHeader(cefProduct), cefHeader(cefVersion),
+		cefHeader(e.Category), cefHeader(e.Action), e.severity(), strings.Join(ext, " "))
+}
+
+// cefHeader escapes a CEF header field
+func cefHeader(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// cefExtension escapes a CEF extension value
+func cefExtension(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "=", `\=`)
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+// syslogMessage wraps a formatted entry in an RFC 5424 syslog message
+func syslogMessage(e Entry, line []byte) []byte {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "-"
+	}
+	app := e.Source
+	if app == "" {
+		app = "-"
+	}
+	return fmt.Appendf(nil, "<%d>1 %s %s %s - %s - %s\n",
+		syslogPriority, e.Timestamp.UTC().Format(time.RFC3339Nano), host, app, e.Category, line)
+}
@@ -0,0 +1,287 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultBufferSize is how many entries wait for delivery before new ones are dropped
+	defaultBufferSize = 1000
+	// defaultMaxRetries is how many times a failed batch is retried before it is dropped
+	defaultMaxRetries = 5
+	// maxBatchSize caps the entries sent in one delivery
+	maxBatchSize = 100
+	// flushInterval is the longest an entry waits for its batch to fill
+	flushInterval = time.Second
+	// initialRetryDelay doubles after every failed attempt up to maxRetryDelay
+	initialRetryDelay = time.Second
+	maxRetryDelay     = 30 * time.Second
+	// sendTimeout bounds a single delivery attempt
+	sendTimeout = 10 * time.Second
+	// closeTimeout bounds flushing the buffer on shutdown
+	closeTimeout = 5 * time.Second
+	// dropLogInterval logs dropped entries once per this many drops
+	dropLogInterval = 100
+)
+
+// Config configures forwarding. URL selects the transport: an http(s)://
+// collector endpoint, syslog://host:port (UDP) or syslog+tcp://host:port.
+type Config struct {
+	URL        string
+	Format     string // json (default) or cef
+	Token      string // bearer token sent to HTTP collectors
+	Source     string // console or kc-agent
+	BufferSize int
+	MaxRetries int
+}
+
+// ConfigFromEnv reads the AUDIT_FORWARD_* environment variables
+func ConfigFromEnv(source string) Config {
+	cfg := Config{
+		URL:    os.Getenv("AUDIT_FORWARD_URL"),
+		Format: os.Getenv("AUDIT_FORWARD_FORMAT"),
+		Token:  os.Getenv("AUDIT_FORWARD_TOKEN"),
+		Source: source,
+	}
+	if v, err := strconv.Atoi(os.Getenv("AUDIT_FORWARD_BUFFER")); err == nil && v > 0 {
+		cfg.BufferSize = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("AUDIT_FORWARD_RETRIES")); err == nil && v > 0 {
+		cfg.MaxRetries = v
+	}
+	return cfg
+}
+
+// Forwarder delivers audit entries in the background. Entries are buffered
+// and sent in batches; failed batches are retried with backoff. A nil
+// Forwarder is valid and discards everything, so callers don't need to check
+// whether forwarding is enabled.
+type Forwarder struct {
+	cfg     Config
+	send    func(ctx context.Context, batch []Entry) error
+	entries chan Entry
+	done    chan struct{}
+	stopped chan struct{}
+	dropped atomic.Int64
+	once    sync.Once
+}
+
+// NewForwarder validates the configuration and starts delivering. It returns
+// nil without error when no URL is configured.
+func NewForwarder(cfg Config) (*Forwarder, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	switch cfg.Format {
+	case "":
+		cfg.Format = FormatJSON
+	case FormatJSON, FormatCEF:
+	default:
+		return nil, fmt.Errorf("unsupported audit format %q (want json or cef)", cfg.Format)
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid audit forward URL %q", cfg.URL)
+	}
+	f := &Forwarder{
+		cfg:     cfg,
+		entries: make(chan Entry, cfg.BufferSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	switch u.Scheme {
+	case "http", "https":
+		f.send = f.sendHTTP
+	case "syslog", "syslog+udp":
+		f.send = f.syslogSender("udp", u.Host)
+	case "syslog+tcp":
+		f.send = f.syslogSender("tcp", u.Host)
+	default:
+		return nil, fmt.Errorf("unsupported audit forward scheme %q (want http, https, syslog or syslog+tcp)", u.Scheme)
+	}
+
+	go f.run()
+	log.Printf("[Audit] Forwarding audit entries to %s://%s as %s", u.Scheme, u.Host, cfg.Format)
+	return f, nil
+}
+
+// Record queues an entry for delivery without blocking. Entries are dropped
+// when the buffer is full.
+func (f *Forwarder) Record(e Entry) {
+	if f == nil {
+		return
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	if e.Source == "" {
+		e.Source = f.cfg.Source
+	}
+	select {
+	case <-f.done:
+		return
+	default:
+	}
+	select {
+	case f.entries <- e:
+	default:
+		if n := f.dropped.Add(1); n%dropLogInterval == 1 {
+			log.Printf("[Audit] Forwarding buffer full, %d entries dropped so far", n)
+		}
+	}
+}
+
+// Close flushes buffered entries, waiting at most a few seconds
+func (f *Forwarder) Close() {
+	if f == nil {
+		return
+	}
+	f.once.Do(func() { close(f.done) })
+	select {
+	case <-f.stopped:
+	case <-time.After(closeTimeout):
+		log.Printf("[Audit] Timed out flushing audit entries on shutdown")
+	}
+}
+
+// run batches entries and delivers them until the forwarder is closed
+func (f *Forwarder) run() {
+	defer close(f.stopped)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, maxBatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			f.deliver(batch)
+			batch = make([]Entry, 0, maxBatchSize)
+		}
+	}
+	for {
+		select {
+		case e := <-f.entries:
+			batch = append(batch, e)
+			if len(batch) >= maxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-f.done:
+			for {
+				select {
+				case e := <-f.entries:
+					batch = append(batch, e)
+					if len(batch) >= maxBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver sends a batch, retrying with exponential backoff. Retries stop
+// early on shutdown.
+func (f *Forwarder) deliver(batch []Entry) {
+	delay := initialRetryDelay
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		err := f.send(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+		if attempt >= f.cfg.MaxRetries {
+			log.Printf("[Audit] Dropping %d audit entries after %d attempts: %v", len(batch), attempt+1, err)
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-f.done:
+			log.Printf("[Audit] Dropping %d audit entries on shutdown: %v", len(batch), err)
+			return
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// sendHTTP posts a batch as newline-delimited entries
+func (f *Forwarder) sendHTTP(ctx context.Context, batch []Entry) error {
+	var body bytes.Buffer
+	for _, e := range batch {
+		line, err := Format(e, f.cfg.Format)
+		if err != nil {
+			return err
+		}
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.cfg.URL, &body)
+	if err != nil {
+		return err
+	}
+	if f.cfg.Format == FormatCEF {
+		req.Header.Set("Content-Type", "text/plain")
+	} else {
+		req.Header.Set("Content-Type", "application/x-ndjson")
+	}
+	if f.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.cfg.Token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// syslogSender sends each entry as an RFC 5424 message, one datagram per
+// entry over UDP or newline-framed over TCP
+func (f *Forwarder) syslogSender(network, addr string) func(ctx context.Context, batch []Entry) error {
+	return func(ctx context.Context, batch []Entry) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, network, addr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		for _, e := range batch {
+			line, err := Format(e, f.cfg.Format)
+			if err != nil {
+				return err
+			}
+			if _, err := conn.Write(syslogMessage(e, line)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
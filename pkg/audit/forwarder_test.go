@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForwarderHTTPRetries(t *testing.T) {
+	var attempts atomic.Int32
+	var mu sync.Mutex
+	var received []Entry
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			var e Entry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Errorf("Invalid JSON line %q: %v", line, err)
+			}
+			received = append(received, e)
+		}
+	}))
+	defer srv.Close()
+
+	f, err := NewForwarder(Config{URL: srv.URL, Token: "s3cret", Source: "console"})
+	if err != nil {
+		t.Fatalf("NewForwarder failed: %v", err)
+	}
+	f.Record(Entry{Category: CategoryLogin, Action: "login", Actor: "alice", Outcome: OutcomeSuccess})
+	f.Record(Entry{Category: CategoryMutation, Action: "DELETE /api/x", Actor: "bob", Outcome: OutcomeFailure})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	f.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 entries after a retry, got %d (attempts %d)", len(received), attempts.Load())
+	}
+	if received[0].Source != "console" || received[0].Timestamp.IsZero() || received[1].Actor != "bob" {
+		t.Errorf("Unexpected entries: %+v", received)
+	}
+}
+
+func TestForwarderSyslogCEF(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	f, err := NewForwarder(Config{URL: "syslog+tcp://" + ln.Addr().String(), Format: FormatCEF, Source: "kc-agent"})
+	if err != nil {
+		t.Fatalf("NewForwarder failed: %v", err)
+	}
+	defer f.Close()
+	f.Record(Entry{Category: CategoryCommand, Action: "command_approved", Outcome: OutcomeSuccess, Target: "kubectl delete pod a=b"})
+
+	select {
+	case line := <-lines:
+		if !strings.HasPrefix(line, "<110>1 ") || !strings.Contains(line, " kc-agent - command - CEF:0|KubeStellar|Console|") {
+			t.Errorf("Unexpected syslog header: %q", line)
+		}
+		if !strings.Contains(line, "|command|command_approved|5|") || !strings.Contains(line, `request=kubectl delete pod a\=b`) {
+			t.Errorf("Unexpected CEF body: %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for syslog message")
+	}
+}
+
+func TestNewForwarderConfig(t *testing.T) {
+	if f, err := NewForwarder(Config{}); f != nil || err != nil {
+		t.Errorf("Expected forwarding to be disabled without a URL, got %v, %v", f, err)
+	}
+	for _, cfg := range []Config{
+		{URL: "ftp://collector:21"},
+		{URL: "https://collector", Format: "xml"},
+		{URL: "not a url"},
+	} {
+		if _, err := NewForwarder(cfg); err == nil {
+			t.Errorf("Expected %+v to be rejected", cfg)
+		}
+	}
+
+	var disabled *Forwarder
+	disabled.Record(Entry{Action: "ignored"})
+	disabled.Close()
+}
+
+func TestFormatCEFEscaping(t *testing.T) {
+	line := formatCEF(Entry{
+		Timestamp: time.UnixMilli(1700000000000),
+		Category:  CategoryMutation,
+		Action:    "POST /api/a|b",
+		Outcome:   OutcomeSuccess,
+		Details:   map[string]string{"status": "200", "note": "x=y\nz"},
+	})
+	want := `CEF:0|KubeStellar|Console|` + cefVersion + `|mutation|POST /api/a\|b|3|rt=1700000000000 cat=mutation outcome=success msg=note:x\=y\nz status:200`
+	if line != want {
+		t.Errorf("Unexpected CEF line:\n got %s\nwant %s", line, want)
+	}
+}
@@ -0,0 +1,172 @@
+package k8s
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// amdDeviceConfigGVR is the AMD GPU Operator DeviceConfig resource
+var amdDeviceConfigGVR = schema.GroupVersionResource{
+	Group:    "amd.com",
+	Version:  "v1alpha1",
+	Resource: "deviceconfigs",
+}
+
+// AMD node labeller label prefixes; older releases use the beta. prefix
+var amdGPULabelPrefixes = []string{"amd.com/gpu.", "beta.amd.com/gpu."}
+
+// amdGPULabel returns the first non-empty AMD node labeller value for a key
+func amdGPULabel(labels map[string]string, key string) string {
+	for _, prefix := range amdGPULabelPrefixes {
+		if v := labels[prefix+key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseAMDVRAM converts node labeller VRAM values ("64G", "192GB", "16384M") to MB
+func parseAMDVRAM(value string) int {
+	v := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(value)), "B")
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(v, "T"):
+		multiplier = 1024 * 1024
+		v = strings.TrimSuffix(v, "T")
+	case strings.HasSuffix(v, "G"):
+		multiplier = 1024
+		v = strings.TrimSuffix(v, "G")
+	case strings.HasSuffix(v, "M"):
+		v = strings.TrimSuffix(v, "M")
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0
+	}
+	return n * multiplier
+}
+
+// applyAMDGPULabels fills GFD-equivalent details from AMD node labeller labels
+func applyAMDGPULabels(labels map[string]string, gpu *GPUNode) {
+	if product := amdGPULabel(labels, "product-name"); product != "" && gpu.GPUType == "AMD GPU" {
+		gpu.GPUType = strings.ReplaceAll(product, "_", " ")
+	}
+	if vram := amdGPULabel(labels, "vram"); vram != "" {
+		gpu.GPUMemoryMB = parseAMDVRAM(vram)
+	}
+	if family := amdGPULabel(labels, "family"); family != "" {
+		gpu.GPUFamily = family
+	}
+	gpu.ROCmVersion = amdGPULabel(labels, "driver-version")
+	gpu.ComputePartitioning = amdGPULabel(labels, "compute-partitioning")
+	gpu.MemoryPartitioning = amdGPULabel(labels, "memory-partitioning")
+}
+
+// AMDGPUOperatorStatus represents the AMD GPU Operator DeviceConfig status for a cluster
+type AMDGPUOperatorStatus struct {
+	Cluster       string                 `json:"cluster"`
+	DeviceConfigs []AMDDeviceConfigState `json:"deviceConfigs"`
+}
+
+// AMDDeviceConfigState summarizes a single DeviceConfig
+type AMDDeviceConfigState struct {
+	Name          string              `json:"name"`
+	Namespace     string              `json:"namespace"`
+	ROCmVersion   string              `json:"rocmVersion,omitempty"` // spec.driver.version
+	DriverEnabled bool                `json:"driverEnabled"`
+	Ready         bool                `json:"ready"`
+	Components    []OperatorComponent `json:"components,omitempty"`
+	NodeModules   map[string]string   `json:"nodeModules,omitempty"` // node → driver module status
+}
+
+// GetAMDGPUOperatorStatus fetches AMD GPU Operator DeviceConfig status.
+// Returns an empty DeviceConfigs list when the operator isn't installed.
+func (m *MultiClusterClient) GetAMDGPUOperatorStatus(ctx context.Context, contextName string) (*AMDGPUOperatorStatus, error) {
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &AMDGPUOperatorStatus{Cluster: contextName, DeviceConfigs: []AMDDeviceConfigState{}}
+
+	list, err := dynamicClient.Resource(amdDeviceConfigGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return status, nil // CRD not installed
+	}
+
+	for _, dc := range list.Items {
+		state := AMDDeviceConfigState{
+			Name:          dc.GetName(),
+			Namespace:     dc.GetNamespace(),
+			DriverEnabled: true,
+			Ready:         true,
+		}
+		if driver, found, _ := unstructuredNestedMap(dc.Object, "spec", "driver"); found {
+			if v, ok := driver["version"].(string); ok {
+				state.ROCmVersion = v
+			}
+			if enable, ok := driver["enable"].(bool); ok {
+				state.DriverEnabled = enable
+			}
+		}
+
+		for _, component := range []string{"driver", "devicePlugin", "metricsExporter"} {
+			if component == "driver" && !state.DriverEnabled {
+				continue
+			}
+			obj, found, _ := unstructuredNestedMap(dc.Object, "status", component)
+			if !found {
+				continue
+			}
+			c := amdComponentState(component, obj)
+			if c.Status != "ready" {
+				state.Ready = false
+			}
+			state.Components = append(state.Components, c)
+		}
+
+		if modules, found, _ := unstructuredNestedMap(dc.Object, "status", "nodeModuleStatus"); found {
+			state.NodeModules = make(map[string]string, len(modules))
+			for node, v := range modules {
+				if mm, ok := v.(map[string]interface{}); ok {
+					s, _ := mm["status"].(string)
+					state.NodeModules[node] = s
+				}
+			}
+		}
+
+		status.DeviceConfigs = append(status.DeviceConfigs, state)
+	}
+
+	return status, nil
+}
+
+// amdComponentState derives readiness from a DeviceConfig daemonset status block
+func amdComponentState(name string, obj map[string]interface{}) OperatorComponent {
+	desired := nestedInt(obj, "desiredNumber")
+	available := nestedInt(obj, "availableNumber")
+	c := OperatorComponent{Name: name, Status: "ready"}
+	switch {
+	case desired == 0:
+		c.Status = "pending"
+		c.Reason = "no nodes scheduled"
+	case available < desired:
+		c.Status = "pending"
+		c.Reason = strconv.FormatInt(available, 10) + "/" + strconv.FormatInt(desired, 10) + " available"
+	}
+	return c
+}
+
+func nestedInt(obj map[string]interface{}, key string) int64 {
+	switch v := obj[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseAMDVRAM(t *testing.T) {
+	tests := map[string]int{
+		"64G":    65536,
+		"192GB":  196608,
+		"16384M": 16384,
+		"1T":     1048576,
+		"lots":   0,
+	}
+	for in, want := range tests {
+		if got := parseAMDVRAM(in); got != want {
+			t.Errorf("parseAMDVRAM(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestGetGPUNodes_AMDLabels(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mi300-1",
+			Labels: map[string]string{
+				"amd.com/gpu.product-name":             "AMD_Instinct_MI300X",
+				"amd.com/gpu.vram":                     "192G",
+				"amd.com/gpu.family":                   "AI",
+				"amd.com/gpu.driver-version":           "6.2.2",
+				"amd.com/gpu.compute-partitioning":     "CPX",
+				"beta.amd.com/gpu.memory-partitioning": "NPS4",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{"amd.com/gpu": resource.MustParse("8")},
+		},
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(node)
+
+	nodes, err := m.GetGPUNodes(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetGPUNodes failed: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("Expected 1 GPU node, got %d", len(nodes))
+	}
+	n := nodes[0]
+	if n.Manufacturer != "AMD" || n.GPUType != "AMD Instinct MI300X" || n.GPUMemoryMB != 196608 || n.GPUFamily != "AI" {
+		t.Errorf("Unexpected AMD GPU node info: %+v", n)
+	}
+	if n.ROCmVersion != "6.2.2" || n.ComputePartitioning != "CPX" || n.MemoryPartitioning != "NPS4" {
+		t.Errorf("Unexpected AMD driver/partition info: %+v", n)
+	}
+}
+
+func TestCheckAMDOperatorPods(t *testing.T) {
+	running := corev1.PodStatus{Phase: corev1.PodRunning}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "gpu-operator-device-plugin-abcde"}, Spec: corev1.PodSpec{NodeName: "n1"}, Status: running},
+		{ObjectMeta: metav1.ObjectMeta{Name: "gpu-operator-node-labeller-xyz"}, Spec: corev1.PodSpec{NodeName: "n1"}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+		// NVIDIA pods must not satisfy the AMD checks
+		{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-device-plugin-daemonset-q"}, Spec: corev1.PodSpec{NodeName: "n2"}, Status: running},
+	}
+
+	checks := runOperatorPodChecks(pods, "n1", defaultGPUOperatorPodSets[GPUStackAMD])
+	if len(checks) != 3 {
+		t.Fatalf("Expected 3 checks, got %d", len(checks))
+	}
+	if checks[0].Name != "amd-device-plugin" || !checks[0].Passed {
+		t.Errorf("Expected device plugin to pass, got %+v", checks[0])
+	}
+	if checks[1].Name != "amd-node-labeller" || checks[1].Passed || checks[1].Message != "Pending" {
+		t.Errorf("Expected node labeller to fail as Pending, got %+v", checks[1])
+	}
+	if checks[2].Name != "amd-metrics-exporter" || !checks[2].Passed {
+		t.Errorf("Expected missing metrics exporter to pass as not installed, got %+v", checks[2])
+	}
+
+	if c := runOperatorPodChecks(pods, "n2", defaultGPUOperatorPodSets[GPUStackAMD])[0]; c.Message == "" {
+		t.Errorf("Expected NVIDIA device plugin to be ignored, got %+v", c)
+	}
+}
+
+func TestGetAMDGPUOperatorStatus(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	dc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "amd.com/v1alpha1",
+		"kind":       "DeviceConfig",
+		"metadata":   map[string]interface{}{"name": "gpu-operator", "namespace": "kube-amd-gpu"},
+		"spec": map[string]interface{}{
+			"driver": map[string]interface{}{"enable": true, "version": "6.2.2"},
+		},
+		"status": map[string]interface{}{
+			"driver":       map[string]interface{}{"desiredNumber": int64(2), "availableNumber": int64(2)},
+			"devicePlugin": map[string]interface{}{"desiredNumber": int64(2), "availableNumber": int64(1)},
+			"nodeModuleStatus": map[string]interface{}{
+				"mi300-1": map[string]interface{}{"status": "Installed"},
+			},
+		},
+	}}
+	m.dynamicClients["c1"] = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{amdDeviceConfigGVR: "DeviceConfigList"}, dc)
+
+	status, err := m.GetAMDGPUOperatorStatus(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetAMDGPUOperatorStatus failed: %v", err)
+	}
+	if len(status.DeviceConfigs) != 1 {
+		t.Fatalf("Expected 1 DeviceConfig, got %d", len(status.DeviceConfigs))
+	}
+	state := status.DeviceConfigs[0]
+	if state.ROCmVersion != "6.2.2" || state.Ready {
+		t.Errorf("Expected not-ready DeviceConfig with ROCm 6.2.2, got %+v", state)
+	}
+	if len(state.Components) != 2 || state.Components[1].Status != "pending" || state.Components[1].Reason != "1/2 available" {
+		t.Errorf("Unexpected components: %+v", state.Components)
+	}
+	if state.NodeModules["mi300-1"] != "Installed" {
+		t.Errorf("Unexpected node modules: %v", state.NodeModules)
+	}
+}
@@ -59,6 +59,11 @@ func (m *MultiClusterClient) ListArgoApplications(ctx context.Context) (*v1alpha
 // ListArgoApplicationsForCluster lists ArgoCD Application resources in a specific cluster.
 // Returns an empty list (not an error) if ArgoCD CRDs are not installed.
 func (m *MultiClusterClient) ListArgoApplicationsForCluster(ctx context.Context, contextName, namespace string) ([]v1alpha1.ArgoApplication, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]v1alpha1.ArgoApplication, error) {
+			return m.ListArgoApplicationsForCluster(ctx, contextName, ns)
+		})
+	}
 	dynamicClient, err := m.GetDynamicClient(contextName)
 	if err != nil {
 		return nil, err
@@ -142,6 +147,8 @@ func (m *MultiClusterClient) parseArgoApplicationsFromList(list interface{}, con
 					app.LastSynced = parseArgoTimeAgo(reconciledAt)
 				}
 			}
+
+			app.Resources = parseArgoResources(status)
 		}
 
 		apps = append(apps, app)
@@ -150,6 +157,27 @@ func (m *MultiClusterClient) parseArgoApplicationsFromList(list interface{}, con
 	return apps, nil
 }
 
+// parseArgoResources parses status.resources, the objects an Application manages
+func parseArgoResources(status map[string]interface{}) []v1alpha1.GitOpsResource {
+	items, _ := status["resources"].([]interface{})
+	resources := make([]v1alpha1.GitOpsResource, 0, len(items))
+	for _, item := range items {
+		r, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var res v1alpha1.GitOpsResource
+		res.Group, _, _ = unstructured.NestedString(r, "group")
+		res.Kind, _, _ = unstructured.NestedString(r, "kind")
+		res.Namespace, _, _ = unstructured.NestedString(r, "namespace")
+		res.Name, _, _ = unstructured.NestedString(r, "name")
+		res.SyncStatus, _, _ = unstructured.NestedString(r, "status")
+		res.HealthStatus, _, _ = unstructured.NestedString(r, "health", "status")
+		resources = append(resources, res)
+	}
+	return resources
+}
+
 // parseArgoTimeAgo converts an ISO 8601 timestamp string to a human-readable "X ago" format
 func parseArgoTimeAgo(timeStr string) string {
 	if timeStr == "" {
@@ -0,0 +1,475 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Karpenter resources. NodeClaims and NodePools replaced Machines and Provisioners in v0.32.
+var (
+	karpenterNodeClaimGVRs = []schema.GroupVersionResource{
+		{Group: "karpenter.sh", Version: "v1", Resource: "nodeclaims"},
+		{Group: "karpenter.sh", Version: "v1beta1", Resource: "nodeclaims"},
+	}
+	karpenterNodePoolGVRs = []schema.GroupVersionResource{
+		{Group: "karpenter.sh", Version: "v1", Resource: "nodepools"},
+		{Group: "karpenter.sh", Version: "v1beta1", Resource: "nodepools"},
+	}
+	karpenterProvisionerGVR = schema.GroupVersionResource{Group: "karpenter.sh", Version: "v1alpha5", Resource: "provisioners"}
+)
+
+const (
+	// clusterAutoscalerStatusConfigMap is written by cluster-autoscaler when --write-status-configmap is set
+	clusterAutoscalerStatusConfigMap = "cluster-autoscaler-status"
+
+	// maxAutoscalerEvents caps the scale events returned per cluster
+	maxAutoscalerEvents = 50
+)
+
+// autoscalerEventReasons are the event reasons emitted by cluster-autoscaler and Karpenter
+// when they add or remove capacity, or decide not to
+var autoscalerEventReasons = map[string]string{
+	// cluster-autoscaler
+	"TriggeredScaleUp":     "cluster-autoscaler",
+	"NotTriggerScaleUp":    "cluster-autoscaler",
+	"ScaledUpGroup":        "cluster-autoscaler",
+	"FailedToScaleUpGroup": "cluster-autoscaler",
+	"ScaleDown":            "cluster-autoscaler",
+	"ScaleDownEmpty":       "cluster-autoscaler",
+	"ScaleDownFailed":      "cluster-autoscaler",
+	// Karpenter
+	"Nominated":         "karpenter",
+	"Launched":          "karpenter",
+	"Registered":        "karpenter",
+	"Initialized":       "karpenter",
+	"DisruptionBlocked": "karpenter",
+	"Disrupting":        "karpenter",
+	"Unconsolidatable":  "karpenter",
+}
+
+// AutoscalerStatus summarizes node autoscaling activity in a cluster
+type AutoscalerStatus struct {
+	Cluster           string                   `json:"cluster"`
+	Autoscalers       []string                 `json:"autoscalers"` // cluster-autoscaler, karpenter
+	ClusterAutoscaler *ClusterAutoscalerStatus `json:"clusterAutoscaler,omitempty"`
+	Karpenter         *KarpenterStatus         `json:"karpenter,omitempty"`
+	PendingPods       []AutoscalerPendingPod   `json:"pendingPods"`
+	ScaleEvents       []Event                  `json:"scaleEvents"`
+}
+
+// ClusterAutoscalerStatus is parsed from the cluster-autoscaler-status ConfigMap
+type ClusterAutoscalerStatus struct {
+	Health        string                       `json:"health"`
+	ScaleUp       string                       `json:"scaleUp,omitempty"`
+	ScaleDown     string                       `json:"scaleDown,omitempty"`
+	LastProbeTime string                       `json:"lastProbeTime,omitempty"`
+	NodeGroups    []ClusterAutoscalerNodeGroup `json:"nodeGroups"`
+}
+
+// ClusterAutoscalerNodeGroup is the cluster-autoscaler view of one node group
+type ClusterAutoscalerNodeGroup struct {
+	Name                string `json:"name"`
+	Health              string `json:"health"`
+	Ready               int    `json:"ready"`
+	Registered          int    `json:"registered"`
+	CloudProviderTarget int    `json:"cloudProviderTarget"`
+	MinSize             int    `json:"minSize"`
+	MaxSize             int    `json:"maxSize"`
+	ScaleUp             string `json:"scaleUp,omitempty"`
+	ScaleDown           string `json:"scaleDown,omitempty"`
+}
+
+// KarpenterStatus lists Karpenter node pools and the nodes it launched
+type KarpenterStatus struct {
+	NodePools  []KarpenterNodePool  `json:"nodePools"`
+	NodeClaims []KarpenterNodeClaim `json:"nodeClaims"`
+}
+
+// KarpenterNodePool is a Karpenter NodePool (or legacy Provisioner)
+type KarpenterNodePool struct {
+	Name                string            `json:"name"`
+	Kind                string            `json:"kind"` // NodePool or Provisioner
+	Nodes               int64             `json:"nodes"`
+	Limits              map[string]string `json:"limits,omitempty"`
+	Resources           map[string]string `json:"resources,omitempty"` // currently provisioned
+	ConsolidationPolicy string            `json:"consolidationPolicy,omitempty"`
+}
+
+// KarpenterNodeClaim is a node requested by Karpenter
+type KarpenterNodeClaim struct {
+	Name         string `json:"name"`
+	NodePool     string `json:"nodePool,omitempty"`
+	NodeName     string `json:"nodeName,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	CapacityType string `json:"capacityType,omitempty"` // spot, on-demand
+	Zone         string `json:"zone,omitempty"`
+	Status       string `json:"status"` // Launching, Registered, Ready, Terminating
+	CreatedAt    string `json:"createdAt,omitempty"`
+}
+
+// AutoscalerPendingPod is an unschedulable pod and the autoscaler's latest decision about it
+type AutoscalerPendingPod struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Message   string `json:"message,omitempty"`  // scheduler reason
+	Decision  string `json:"decision,omitempty"` // e.g. TriggeredScaleUp, NotTriggerScaleUp, Nominated
+	Detail    string `json:"detail,omitempty"`
+	Since     string `json:"since,omitempty"`
+}
+
+// caStatusYAML is the structured status format written by cluster-autoscaler 1.30+
+type caStatusYAML struct {
+	Time        string `yaml:"time"`
+	ClusterWide struct {
+		Health    caStatusCondition `yaml:"health"`
+		ScaleUp   caStatusCondition `yaml:"scaleUp"`
+		ScaleDown caStatusCondition `yaml:"scaleDown"`
+	} `yaml:"clusterWide"`
+	NodeGroups []struct {
+		Name   string `yaml:"name"`
+		Health struct {
+			caStatusCondition   `yaml:",inline"`
+			CloudProviderTarget int `yaml:"cloudProviderTarget"`
+			MinSize             int `yaml:"minSize"`
+			MaxSize             int `yaml:"maxSize"`
+		} `yaml:"health"`
+		ScaleUp   caStatusCondition `yaml:"scaleUp"`
+		ScaleDown caStatusCondition `yaml:"scaleDown"`
+	} `yaml:"nodeGroups"`
+}
+
+type caStatusCondition struct {
+	Status        string `yaml:"status"`
+	LastProbeTime string `yaml:"lastProbeTime"`
+	NodeCounts    struct {
+		Registered struct {
+			Total int `yaml:"total"`
+			Ready int `yaml:"ready"`
+		} `yaml:"registered"`
+	} `yaml:"nodeCounts"`
+}
+
+var (
+	caFieldRe = regexp.MustCompile(`^\s*(Name|Health|ScaleUp|ScaleDown|LastProbeTime):\s*(.*)$`)
+	caKVRe    = regexp.MustCompile(`(\w+)=(\d+)`)
+)
+
+// ParseClusterAutoscalerStatus parses the cluster-autoscaler-status ConfigMap's "status"
+// key, in either the structured YAML format or the older human-readable format
+func ParseClusterAutoscalerStatus(data string) (*ClusterAutoscalerStatus, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, fmt.Errorf("empty status")
+	}
+
+	var structured caStatusYAML
+	if err := yaml.Unmarshal([]byte(data), &structured); err == nil && structured.ClusterWide.Health.Status != "" {
+		status := &ClusterAutoscalerStatus{
+			Health:        structured.ClusterWide.Health.Status,
+			ScaleUp:       structured.ClusterWide.ScaleUp.Status,
+			ScaleDown:     structured.ClusterWide.ScaleDown.Status,
+			LastProbeTime: structured.ClusterWide.Health.LastProbeTime,
+			NodeGroups:    []ClusterAutoscalerNodeGroup{},
+		}
+		for _, ng := range structured.NodeGroups {
+			status.NodeGroups = append(status.NodeGroups, ClusterAutoscalerNodeGroup{
+				Name:                ng.Name,
+				Health:              ng.Health.Status,
+				Ready:               ng.Health.NodeCounts.Registered.Ready,
+				Registered:          ng.Health.NodeCounts.Registered.Total,
+				CloudProviderTarget: ng.Health.CloudProviderTarget,
+				MinSize:             ng.Health.MinSize,
+				MaxSize:             ng.Health.MaxSize,
+				ScaleUp:             ng.ScaleUp.Status,
+				ScaleDown:           ng.ScaleDown.Status,
+			})
+		}
+		return status, nil
+	}
+
+	return parseClusterAutoscalerStatusText(data)
+}
+
+// parseClusterAutoscalerStatusText handles the pre-1.30 format, e.g.
+//
+//	Cluster-wide:
+//	  Health:      Healthy (ready=3 unready=0 notStarted=0 registered=3)
+//	NodeGroups:
+//	  Name:        gpu-pool
+//	  Health:      Healthy (ready=2 ... cloudProviderTarget=2 (minSize=0, maxSize=4))
+func parseClusterAutoscalerStatusText(data string) (*ClusterAutoscalerStatus, error) {
+	status := &ClusterAutoscalerStatus{NodeGroups: []ClusterAutoscalerNodeGroup{}}
+	var group *ClusterAutoscalerNodeGroup
+	inNodeGroups := false
+
+	for _, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "NodeGroups:") {
+			inNodeGroups = true
+			continue
+		}
+		match := caFieldRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		field, value := match[1], strings.TrimSpace(match[2])
+		state := value
+		if i := strings.Index(value, " "); i > 0 {
+			state = value[:i]
+		}
+
+		if !inNodeGroups {
+			switch field {
+			case "Health":
+				status.Health = state
+			case "ScaleUp":
+				status.ScaleUp = state
+			case "ScaleDown":
+				status.ScaleDown = state
+			case "LastProbeTime":
+				if status.LastProbeTime == "" {
+					status.LastProbeTime = value
+				}
+			}
+			continue
+		}
+
+		switch field {
+		case "Name":
+			status.NodeGroups = append(status.NodeGroups, ClusterAutoscalerNodeGroup{Name: value})
+			group = &status.NodeGroups[len(status.NodeGroups)-1]
+		case "Health":
+			if group == nil {
+				continue
+			}
+			group.Health = state
+			for _, kv := range caKVRe.FindAllStringSubmatch(value, -1) {
+				n, _ := strconv.Atoi(kv[2])
+				switch kv[1] {
+				case "ready":
+					group.Ready = n
+				case "registered":
+					group.Registered = n
+				case "cloudProviderTarget":
+					group.CloudProviderTarget = n
+				case "minSize":
+					group.MinSize = n
+				case "maxSize":
+					group.MaxSize = n
+				}
+			}
+		case "ScaleUp":
+			if group != nil {
+				group.ScaleUp = state
+			}
+		case "ScaleDown":
+			if group != nil {
+				group.ScaleDown = state
+			}
+		}
+	}
+
+	if status.Health == "" {
+		return nil, fmt.Errorf("unrecognized cluster-autoscaler status format")
+	}
+	return status, nil
+}
+
+// listFirstServed lists the first version of a resource the cluster serves
+func listFirstServed(ctx context.Context, dynamicClient dynamic.Interface, gvrs []schema.GroupVersionResource) []unstructured.Unstructured {
+	for _, gvr := range gvrs {
+		if list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{}); err == nil {
+			return list.Items
+		}
+	}
+	return nil
+}
+
+// karpenterNodeClaimStatus derives a lifecycle stage from NodeClaim conditions
+func karpenterNodeClaimStatus(item *unstructured.Unstructured) string {
+	if item.GetDeletionTimestamp() != nil {
+		return "Terminating"
+	}
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	state := map[string]bool{}
+	for _, c := range conditions {
+		if cm, ok := c.(map[string]interface{}); ok {
+			t, _ := cm["type"].(string)
+			state[t] = cm["status"] == "True"
+		}
+	}
+	switch {
+	case state["Ready"]:
+		return "Ready"
+	case state["Initialized"], state["Registered"]:
+		return "Registered"
+	case state["Launched"]:
+		return "Launched"
+	}
+	return "Launching"
+}
+
+// getKarpenterStatus returns nil when Karpenter is not installed
+func getKarpenterStatus(ctx context.Context, dynamicClient dynamic.Interface) *KarpenterStatus {
+	status := &KarpenterStatus{NodePools: []KarpenterNodePool{}, NodeClaims: []KarpenterNodeClaim{}}
+	found := false
+
+	pools := listFirstServed(ctx, dynamicClient, karpenterNodePoolGVRs)
+	kind := "NodePool"
+	if pools == nil {
+		pools = listFirstServed(ctx, dynamicClient, []schema.GroupVersionResource{karpenterProvisionerGVR})
+		kind = "Provisioner"
+	}
+	for i := range pools {
+		found = true
+		obj := pools[i].Object
+		pool := KarpenterNodePool{Name: pools[i].GetName(), Kind: kind}
+		pool.Limits, _, _ = unstructured.NestedStringMap(obj, "spec", "limits")
+		if kind == "Provisioner" {
+			pool.Limits, _, _ = unstructured.NestedStringMap(obj, "spec", "limits", "resources")
+		}
+		pool.Resources, _, _ = unstructured.NestedStringMap(obj, "status", "resources")
+		if n, ok := pool.Resources["nodes"]; ok {
+			pool.Nodes, _ = strconv.ParseInt(n, 10, 64)
+		}
+		pool.ConsolidationPolicy, _, _ = unstructured.NestedString(obj, "spec", "disruption", "consolidationPolicy")
+		status.NodePools = append(status.NodePools, pool)
+	}
+
+	claims := listFirstServed(ctx, dynamicClient, karpenterNodeClaimGVRs)
+	for i := range claims {
+		found = true
+		labels := claims[i].GetLabels()
+		claim := KarpenterNodeClaim{
+			Name:         claims[i].GetName(),
+			NodePool:     labels["karpenter.sh/nodepool"],
+			InstanceType: labels["node.kubernetes.io/instance-type"],
+			CapacityType: labels["karpenter.sh/capacity-type"],
+			Zone:         labels["topology.kubernetes.io/zone"],
+			Status:       karpenterNodeClaimStatus(&claims[i]),
+			CreatedAt:    claims[i].GetCreationTimestamp().Format(time.RFC3339),
+		}
+		claim.NodeName, _, _ = unstructured.NestedString(claims[i].Object, "status", "nodeName")
+		status.NodeClaims = append(status.NodeClaims, claim)
+	}
+
+	if !found {
+		return nil
+	}
+	return status
+}
+
+// GetAutoscalerStatus reports cluster-autoscaler and Karpenter state, unschedulable pods with
+// the autoscaler's decision for each, and recent scale events
+func (m *MultiClusterClient) GetAutoscalerStatus(ctx context.Context, contextName string) (*AutoscalerStatus, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &AutoscalerStatus{
+		Cluster:     contextName,
+		Autoscalers: []string{},
+		PendingPods: []AutoscalerPendingPod{},
+		ScaleEvents: []Event{},
+	}
+
+	if cm, err := client.CoreV1().ConfigMaps("kube-system").Get(ctx, clusterAutoscalerStatusConfigMap, metav1.GetOptions{}); err == nil {
+		if ca, err := ParseClusterAutoscalerStatus(cm.Data["status"]); err == nil {
+			status.ClusterAutoscaler = ca
+			status.Autoscalers = append(status.Autoscalers, "cluster-autoscaler")
+		}
+	}
+
+	if dynamicClient, err := m.GetDynamicClient(contextName); err == nil {
+		if karpenter := getKarpenterStatus(ctx, dynamicClient); karpenter != nil {
+			status.Karpenter = karpenter
+			status.Autoscalers = append(status.Autoscalers, "karpenter")
+		}
+	}
+
+	// Latest autoscaler decision per pod, and the recent scale event feed
+	events, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.After(events.Items[j].LastTimestamp.Time)
+	})
+	podDecision := map[string]corev1.Event{}
+	sawCA := status.ClusterAutoscaler != nil
+	for _, event := range events.Items {
+		source, ok := autoscalerEventReasons[event.Reason]
+		if !ok {
+			continue
+		}
+		if source == "cluster-autoscaler" {
+			sawCA = true
+		}
+		if event.InvolvedObject.Kind == "Pod" {
+			key := event.Namespace + "/" + event.InvolvedObject.Name
+			if _, seen := podDecision[key]; !seen {
+				podDecision[key] = event
+			}
+		}
+		if len(status.ScaleEvents) < maxAutoscalerEvents {
+			e := Event{
+				Type:      event.Type,
+				Reason:    event.Reason,
+				Message:   event.Message,
+				Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+				Namespace: event.Namespace,
+				Cluster:   contextName,
+				Count:     event.Count,
+				Age:       formatDuration(time.Since(event.LastTimestamp.Time)),
+			}
+			if !event.LastTimestamp.IsZero() {
+				e.LastSeen = event.LastTimestamp.Time.Format(time.RFC3339)
+			}
+			status.ScaleEvents = append(status.ScaleEvents, e)
+		}
+	}
+	// cluster-autoscaler may run without the status ConfigMap; its events still identify it
+	if sawCA && status.ClusterAutoscaler == nil {
+		status.Autoscalers = append([]string{"cluster-autoscaler"}, status.Autoscalers...)
+	}
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "status.phase=Pending"})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		var unschedulable *corev1.PodCondition
+		for i, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+				unschedulable = &pod.Status.Conditions[i]
+			}
+		}
+		if unschedulable == nil {
+			continue
+		}
+		p := AutoscalerPendingPod{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+			Message:   unschedulable.Message,
+			Since:     unschedulable.LastTransitionTime.Format(time.RFC3339),
+		}
+		if event, ok := podDecision[pod.Namespace+"/"+pod.Name]; ok {
+			p.Decision = event.Reason
+			p.Detail = event.Message
+		}
+		status.PendingPods = append(status.PendingPods, p)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,158 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseClusterAutoscalerStatus(t *testing.T) {
+	text := `Cluster-autoscaler status at 2026-01-01 12:00:00.000000000 +0000 UTC:
+Cluster-wide:
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3 longUnregistered=0)
+               LastProbeTime:      2026-01-01 11:59:50.000000000 +0000 UTC
+  ScaleUp:     InProgress (ready=3 registered=3)
+  ScaleDown:   NoCandidates (candidates=0)
+
+NodeGroups:
+  Name:        gpu-pool
+  Health:      Healthy (ready=1 unready=0 notStarted=0 longNotStarted=0 registered=1 longUnregistered=0 cloudProviderTarget=2 (minSize=0, maxSize=4))
+  ScaleUp:     InProgress (ready=1 cloudProviderTarget=2)
+  ScaleDown:   NoCandidates (candidates=0)
+`
+	structured := `time: "2026-01-01 12:00:00 +0000 UTC"
+autoscalerStatus: Running
+clusterWide:
+  health:
+    status: Healthy
+    lastProbeTime: "2026-01-01T11:59:50Z"
+  scaleUp:
+    status: InProgress
+  scaleDown:
+    status: NoCandidates
+nodeGroups:
+- name: gpu-pool
+  health:
+    status: Healthy
+    nodeCounts:
+      registered:
+        total: 1
+        ready: 1
+    cloudProviderTarget: 2
+    minSize: 0
+    maxSize: 4
+  scaleUp:
+    status: InProgress
+  scaleDown:
+    status: NoCandidates
+`
+	for name, data := range map[string]string{"text": text, "yaml": structured} {
+		t.Run(name, func(t *testing.T) {
+			status, err := ParseClusterAutoscalerStatus(data)
+			if err != nil {
+				t.Fatalf("ParseClusterAutoscalerStatus failed: %v", err)
+			}
+			if status.Health != "Healthy" || status.ScaleUp != "InProgress" || status.LastProbeTime == "" {
+				t.Errorf("Unexpected cluster-wide status: %+v", status)
+			}
+			if len(status.NodeGroups) != 1 {
+				t.Fatalf("Expected 1 node group, got %+v", status.NodeGroups)
+			}
+			ng := status.NodeGroups[0]
+			if ng.Name != "gpu-pool" || ng.Ready != 1 || ng.Registered != 1 || ng.CloudProviderTarget != 2 || ng.MaxSize != 4 || ng.ScaleUp != "InProgress" {
+				t.Errorf("Unexpected node group: %+v", ng)
+			}
+		})
+	}
+
+	if _, err := ParseClusterAutoscalerStatus("not a status"); err == nil {
+		t.Error("Expected unrecognized status to fail")
+	}
+}
+
+func TestGetAutoscalerStatus_Karpenter(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "trainer-0", Namespace: "ml"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+			}},
+		},
+	}
+	nominated := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "trainer-0.1", Namespace: "ml"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "trainer-0", Namespace: "ml"},
+		Reason:         "Nominated",
+		Message:        "Pod should schedule on: nodeclaim/gpu-abcde",
+		LastTimestamp:  metav1.Now(),
+	}
+	unrelated := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "web.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+		Reason:         "Pulled",
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(pending, nominated, unrelated)
+
+	nodePool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodePool",
+		"metadata":   map[string]interface{}{"name": "gpu"},
+		"spec": map[string]interface{}{
+			"limits":     map[string]interface{}{"nvidia.com/gpu": "16"},
+			"disruption": map[string]interface{}{"consolidationPolicy": "WhenEmpty"},
+		},
+		"status": map[string]interface{}{"resources": map[string]interface{}{"nodes": "1", "nvidia.com/gpu": "8"}},
+	}}
+	nodeClaim := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodeClaim",
+		"metadata": map[string]interface{}{
+			"name":   "gpu-abcde",
+			"labels": map[string]interface{}{"karpenter.sh/nodepool": "gpu", "karpenter.sh/capacity-type": "spot"},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Launched", "status": "True"}},
+		},
+	}}
+	gvrs := map[schema.GroupVersionResource]string{karpenterProvisionerGVR: "ProvisionerList"}
+	for _, gvr := range karpenterNodePoolGVRs {
+		gvrs[gvr] = "NodePoolList"
+	}
+	for _, gvr := range karpenterNodeClaimGVRs {
+		gvrs[gvr] = "NodeClaimList"
+	}
+	m.dynamicClients["c1"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrs, nodePool, nodeClaim)
+
+	status, err := m.GetAutoscalerStatus(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetAutoscalerStatus failed: %v", err)
+	}
+	if len(status.Autoscalers) != 1 || status.Autoscalers[0] != "karpenter" || status.ClusterAutoscaler != nil {
+		t.Errorf("Expected only Karpenter to be detected, got %v", status.Autoscalers)
+	}
+	if len(status.Karpenter.NodePools) != 1 || status.Karpenter.NodePools[0].Nodes != 1 || status.Karpenter.NodePools[0].ConsolidationPolicy != "WhenEmpty" {
+		t.Errorf("Unexpected node pools: %+v", status.Karpenter.NodePools)
+	}
+	if len(status.Karpenter.NodeClaims) != 1 || status.Karpenter.NodeClaims[0].Status != "Launched" || status.Karpenter.NodeClaims[0].CapacityType != "spot" {
+		t.Errorf("Unexpected node claims: %+v", status.Karpenter.NodeClaims)
+	}
+	if len(status.PendingPods) != 1 || status.PendingPods[0].Decision != "Nominated" {
+		t.Errorf("Expected pending pod nominated by Karpenter, got %+v", status.PendingPods)
+	}
+	if len(status.ScaleEvents) != 1 || status.ScaleEvents[0].Reason != "Nominated" {
+		t.Errorf("Expected only the autoscaler event, got %+v", status.ScaleEvents)
+	}
+}
@@ -0,0 +1,340 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kubestellar/console/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DownsyncRule selects workload objects in a WDS, following a BindingPolicy
+// downsync clause. Every criterion that is set must match; within a criterion
+// any entry may match.
+type DownsyncRule struct {
+	APIGroup           string                 `json:"apiGroup,omitempty"`
+	Resources          []string               `json:"resources,omitempty"` // "*" matches all
+	Namespaces         []string               `json:"namespaces,omitempty"`
+	NamespaceSelectors []metav1.LabelSelector `json:"namespaceSelectors,omitempty"`
+	ObjectNames        []string               `json:"objectNames,omitempty"`
+	ObjectSelectors    []metav1.LabelSelector `json:"objectSelectors,omitempty"`
+}
+
+// BindingPolicySpec is the placement part of a proposed BindingPolicy
+type BindingPolicySpec struct {
+	ClusterSelectors []metav1.LabelSelector `json:"clusterSelectors"`
+	Downsync         []DownsyncRule         `json:"downsync"`
+}
+
+// PlacementSimulationRequest asks which clusters and workloads a BindingPolicy would bind
+type PlacementSimulationRequest struct {
+	Policy BindingPolicySpec `json:"policy"`
+	// ITS is the hub context whose ManagedCluster labels the cluster selectors
+	// match; without it the console's clusters are matched by a name label only
+	ITS string `json:"its,omitempty"`
+	// WDS is the context holding the workload definitions; without it the
+	// workloads of every cluster are considered
+	WDS string `json:"wds,omitempty"`
+}
+
+// SimulatedCluster is a candidate cluster and whether the policy selects it
+type SimulatedCluster struct {
+	Name    string            `json:"name"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Matched bool              `json:"matched"`
+}
+
+// SimulatedWorkload is a workload the policy would propagate
+type SimulatedWorkload struct {
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Sources   []string `json:"sources"` // clusters the workload was found in
+	Rule      int      `json:"rule"`    // index of the first matching downsync rule
+}
+
+// PlacementSimulation is the outcome of a BindingPolicy what-if
+type PlacementSimulation struct {
+	Clusters        []SimulatedCluster  `json:"clusters"`
+	MatchedClusters []string            `json:"matchedClusters"`
+	Workloads       []SimulatedWorkload `json:"workloads"`
+	// Placements is the number of objects that would be created: one per
+	// matched cluster and workload
+	Placements int      `json:"placements"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// workloadResources maps workload types to their API group and resource
+var workloadResources = map[v1alpha1.WorkloadType][2]string{
+	v1alpha1.WorkloadTypeDeployment:  {"apps", "deployments"},
+	v1alpha1.WorkloadTypeStatefulSet: {"apps", "statefulsets"},
+	v1alpha1.WorkloadTypeDaemonSet:   {"apps", "daemonsets"},
+}
+
+// Validate checks that the policy's label selectors parse
+func (p BindingPolicySpec) Validate() error {
+	_, _, err := p.compile()
+	return err
+}
+
+func (p BindingPolicySpec) compile() ([]labels.Selector, []compiledDownsyncRule, error) {
+	clusterSelectors, err := compileSelectors(p.ClusterSelectors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+	rules := make([]compiledDownsyncRule, 0, len(p.Downsync))
+	for i, rule := range p.Downsync {
+		compiled, err := compileDownsyncRule(rule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid downsync rule %d: %w", i, err)
+		}
+		rules = append(rules, compiled)
+	}
+	return clusterSelectors, rules, nil
+}
+
+// SimulatePlacement reports which clusters and workloads a proposed
+// BindingPolicy would bind, without creating anything
+func (m *MultiClusterClient) SimulatePlacement(ctx context.Context, req PlacementSimulationRequest) (*PlacementSimulation, error) {
+	clusterSelectors, rules, err := req.Policy.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &PlacementSimulation{
+		Clusters:        []SimulatedCluster{},
+		MatchedClusters: []string{},
+		Workloads:       []SimulatedWorkload{},
+	}
+	if len(clusterSelectors) == 0 {
+		sim.Warnings = append(sim.Warnings, "policy has no cluster selectors and selects no clusters")
+	}
+	if len(rules) == 0 {
+		sim.Warnings = append(sim.Warnings, "policy has no downsync rules and propagates no workloads")
+	}
+
+	candidates, warning, err := m.placementCandidates(ctx, req.ITS)
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		sim.Warnings = append(sim.Warnings, warning)
+	}
+	for _, c := range candidates {
+		c.Matched = matchesAny(clusterSelectors, c.Labels)
+		if c.Matched {
+			sim.MatchedClusters = append(sim.MatchedClusters, c.Name)
+		}
+		sim.Clusters = append(sim.Clusters, c)
+	}
+
+	workloads, err := m.placementWorkloads(ctx, req.WDS)
+	if err != nil {
+		return nil, err
+	}
+	nsLabels := newNamespaceLabelCache(m)
+	for _, w := range workloads {
+		for i, rule := range rules {
+			if rule.matches(ctx, w, nsLabels) {
+				w.Rule = i
+				sim.Workloads = append(sim.Workloads, w.SimulatedWorkload)
+				break
+			}
+		}
+	}
+	sim.Warnings = append(sim.Warnings, nsLabels.warnings...)
+	sim.Placements = len(sim.MatchedClusters) * len(sim.Workloads)
+	return sim, nil
+}
+
+// placementCandidates returns the clusters a policy can select: the
+// ManagedClusters of the ITS, or the console's clusters labeled by name
+func (m *MultiClusterClient) placementCandidates(ctx context.Context, its string) ([]SimulatedCluster, string, error) {
+	var candidates []SimulatedCluster
+	var warning string
+	if its != "" {
+		managed, err := m.ListManagedClusters(ctx, its)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list managed clusters on %s: %w", its, err)
+		}
+		for _, mc := range managed {
+			candidates = append(candidates, SimulatedCluster{Name: mc.Name, Labels: mc.Labels})
+		}
+	} else {
+		clusters, err := m.DeduplicatedClusters(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list clusters: %w", err)
+		}
+		for _, c := range clusters {
+			candidates = append(candidates, SimulatedCluster{Name: c.Name, Labels: map[string]string{"name": c.Name}})
+		}
+		warning = "no ITS given; clusters were matched by their name label only"
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return candidates, warning, nil
+}
+
+// placementWorkload is a candidate workload with the labels rules match against
+type placementWorkload struct {
+	SimulatedWorkload
+	labels map[string]string
+	group  string
+	res    string
+}
+
+// placementWorkloads lists the candidate workloads of the WDS, or of every
+// cluster merged by kind, namespace and name
+func (m *MultiClusterClient) placementWorkloads(ctx context.Context, wds string) ([]placementWorkload, error) {
+	var items []v1alpha1.Workload
+	if wds != "" {
+		list, err := m.ListWorkloadsForCluster(ctx, wds, "", "")
+		if err != nil {
+			return nil, err
+		}
+		items = list
+	} else {
+		list, err := m.ListWorkloads(ctx, "", "", "")
+		if err != nil {
+			return nil, err
+		}
+		items = list.Items
+	}
+
+	byKey := make(map[string]*placementWorkload)
+	var keys []string
+	for _, item := range items {
+		key := fmt.Sprintf("%s/%s/%s", item.Type, item.Namespace, item.Name)
+		w, ok := byKey[key]
+		if !ok {
+			gr := workloadResources[item.Type]
+			w = &placementWorkload{
+				SimulatedWorkload: SimulatedWorkload{Kind: string(item.Type), Name: item.Name, Namespace: item.Namespace},
+				labels:            item.Labels,
+				group:             gr[0],
+				res:               gr[1],
+			}
+			byKey[key] = w
+			keys = append(keys, key)
+		}
+		w.Sources = append(w.Sources, item.TargetClusters...)
+	}
+	sort.Strings(keys)
+
+	workloads := make([]placementWorkload, 0, len(keys))
+	for _, key := range keys {
+		w := byKey[key]
+		sort.Strings(w.Sources)
+		workloads = append(workloads, *w)
+	}
+	return workloads, nil
+}
+
+// compiledDownsyncRule is a DownsyncRule with its selectors parsed
+type compiledDownsyncRule struct {
+	DownsyncRule
+	namespaceSelectors []labels.Selector
+	objectSelectors    []labels.Selector
+}
+
+func compileDownsyncRule(rule DownsyncRule) (compiledDownsyncRule, error) {
+	compiled := compiledDownsyncRule{DownsyncRule: rule}
+	var err error
+	if compiled.namespaceSelectors, err = compileSelectors(rule.NamespaceSelectors); err != nil {
+		return compiled, err
+	}
+	if compiled.objectSelectors, err = compileSelectors(rule.ObjectSelectors); err != nil {
+		return compiled, err
+	}
+	return compiled, nil
+}
+
+func (r compiledDownsyncRule) matches(ctx context.Context, w placementWorkload, nsLabels *namespaceLabelCache) bool {
+	if r.APIGroup != "" && r.APIGroup != w.group {
+		return false
+	}
+	if len(r.Resources) > 0 && !containsOrWildcard(r.Resources, w.res) {
+		return false
+	}
+	if len(r.Namespaces) > 0 && !containsOrWildcard(r.Namespaces, w.Namespace) {
+		return false
+	}
+	if len(r.ObjectNames) > 0 && !containsOrWildcard(r.ObjectNames, w.Name) {
+		return false
+	}
+	if len(r.objectSelectors) > 0 && !matchesAny(r.objectSelectors, w.labels) {
+		return false
+	}
+	if len(r.namespaceSelectors) > 0 && !matchesAny(r.namespaceSelectors, nsLabels.get(ctx, w.Sources, w.Namespace)) {
+		return false
+	}
+	return true
+}
+
+func compileSelectors(selectors []metav1.LabelSelector) ([]labels.Selector, error) {
+	compiled := make([]labels.Selector, 0, len(selectors))
+	for i := range selectors {
+		s, err := metav1.LabelSelectorAsSelector(&selectors[i])
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, s)
+	}
+	return compiled, nil
+}
+
+func matchesAny(selectors []labels.Selector, set map[string]string) bool {
+	for _, s := range selectors {
+		if s.Matches(labels.Set(set)) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOrWildcard(values []string, v string) bool {
+	for _, value := range values {
+		if value == "*" || value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceLabelCache looks up namespace labels once per cluster and namespace
+type namespaceLabelCache struct {
+	m        *MultiClusterClient
+	labels   map[string]map[string]string
+	warnings []string
+}
+
+func newNamespaceLabelCache(m *MultiClusterClient) *namespaceLabelCache {
+	return &namespaceLabelCache{m: m, labels: make(map[string]map[string]string)}
+}
+
+// get returns the labels of a namespace in the first source cluster that has it
+func (c *namespaceLabelCache) get(ctx context.Context, clusters []string, namespace string) map[string]string {
+	for _, cluster := range clusters {
+		key := cluster + "/" + namespace
+		if set, ok := c.labels[key]; ok {
+			if set != nil {
+				return set
+			}
+			continue
+		}
+		client, err := c.m.GetClient(cluster)
+		if err != nil {
+			continue
+		}
+		ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			c.warnings = append(c.warnings, fmt.Sprintf("could not read labels of namespace %s on %s: %v", namespace, cluster, err))
+			c.labels[key] = nil
+			continue
+		}
+		c.labels[key] = ns.Labels
+		return ns.Labels
+	}
+	return nil
+}
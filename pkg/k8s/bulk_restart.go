@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrInvalidRestart is returned for restart selections that are refused
+var ErrInvalidRestart = errors.New("invalid restart request")
+
+// RestartSelector chooses the workloads of a bulk rollout restart: one
+// Deployment by name (in Namespace, or in any non-system namespace when
+// empty), or every Deployment, StatefulSet and DaemonSet in Namespace
+type RestartSelector struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Deployment string `json:"deployment,omitempty"`
+}
+
+// RestartTarget is one workload a bulk restart will touch
+type RestartTarget struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"` // Deployment, StatefulSet or DaemonSet
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas"`
+}
+
+// Validate refuses selections that would touch more than intended
+func (s RestartSelector) Validate() error {
+	if s.Namespace == "" && s.Deployment == "" {
+		return fmt.Errorf("%w: namespace or deployment is required", ErrInvalidRestart)
+	}
+	if s.Deployment == "" && isSystemNamespace(s.Namespace) {
+		return fmt.Errorf("%w: restarting everything in system namespace %s is not allowed", ErrInvalidRestart, s.Namespace)
+	}
+	return nil
+}
+
+// Validate refuses targets no valid selection previews: in a system namespace
+// only a Deployment named together with that namespace can be restarted
+func (t RestartTarget) Validate() error {
+	if isSystemNamespace(t.Namespace) && t.Kind != "Deployment" {
+		return fmt.Errorf("%w: restarting %s %s/%s in a system namespace is not allowed", ErrInvalidRestart, t.Kind, t.Namespace, t.Name)
+	}
+	return nil
+}
+
+// PreviewRestart lists exactly the workloads of a cluster a bulk restart with
+// the selector would touch, without changing anything
+func (m *MultiClusterClient) PreviewRestart(ctx context.Context, contextName string, sel RestartSelector) ([]RestartTarget, error) {
+	if err := sel.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]RestartTarget, 0)
+	deployments, err := client.AppsV1().Deployments(sel.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deployments.Items {
+		if sel.Deployment != "" && (d.Name != sel.Deployment || (sel.Namespace == "" && isSystemNamespace(d.Namespace))) {
+			continue
+		}
+		var replicas int32 = 1
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		targets = append(targets, RestartTarget{Cluster: contextName, Namespace: d.Namespace, Kind: "Deployment", Name: d.Name, Replicas: replicas})
+	}
+
+	if sel.Deployment == "" {
+		statefulSets, err := client.AppsV1().StatefulSets(sel.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range statefulSets.Items {
+			var replicas int32 = 1
+			if s.Spec.Replicas != nil {
+				replicas = *s.Spec.Replicas
+			}
+			targets = append(targets, RestartTarget{Cluster: contextName, Namespace: s.Namespace, Kind: "StatefulSet", Name: s.Name, Replicas: replicas})
+		}
+		daemonSets, err := client.AppsV1().DaemonSets(sel.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range daemonSets.Items {
+			targets = append(targets, RestartTarget{Cluster: contextName, Namespace: d.Namespace, Kind: "DaemonSet", Name: d.Name, Replicas: d.Status.DesiredNumberScheduled})
+		}
+	}
+
+	SortRestartTargets(targets)
+	return targets, nil
+}
+
+// SortRestartTargets orders targets by cluster, namespace, kind and name
+func SortRestartTargets(targets []RestartTarget) {
+	sort.Slice(targets, func(i, j int) bool {
+		a, b := targets[i], targets[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+// isSystemNamespace reports whether a namespace belongs to Kubernetes itself
+func isSystemNamespace(namespace string) bool {
+	return strings.HasPrefix(namespace, "kube-")
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPreviewRestart(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	replicas := int32(3)
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: &replicas}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "staging"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "kube-system"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "log-agent", Namespace: "shop"}},
+	)
+	ctx := context.Background()
+
+	byName, err := m.PreviewRestart(ctx, "c1", RestartSelector{Deployment: "api"})
+	if err != nil {
+		t.Fatalf("PreviewRestart failed: %v", err)
+	}
+	if len(byName) != 2 || byName[0].Namespace != "shop" || byName[0].Replicas != 3 || byName[1].Namespace != "staging" {
+		t.Errorf("Expected api in shop and staging only, got %+v", byName)
+	}
+
+	namespace, err := m.PreviewRestart(ctx, "c1", RestartSelector{Namespace: "shop"})
+	if err != nil {
+		t.Fatalf("PreviewRestart failed: %v", err)
+	}
+	if len(namespace) != 4 || namespace[0].Kind != "DaemonSet" || namespace[3].Kind != "StatefulSet" {
+		t.Errorf("Expected every workload in shop, got %+v", namespace)
+	}
+
+	if _, err := m.PreviewRestart(ctx, "c1", RestartSelector{Namespace: "kube-system"}); !errors.Is(err, ErrInvalidRestart) {
+		t.Errorf("Expected system namespace restart to be refused, got %v", err)
+	}
+	if _, err := m.PreviewRestart(ctx, "c1", RestartSelector{}); !errors.Is(err, ErrInvalidRestart) {
+		t.Errorf("Expected empty selector to be refused, got %v", err)
+	}
+
+	if err := m.RestartWorkload(ctx, "c1", namespace[3]); err != nil {
+		t.Fatalf("RestartWorkload failed: %v", err)
+	}
+	sts, _ := m.clients["c1"].AppsV1().StatefulSets("shop").Get(ctx, "db", metav1.GetOptions{})
+	if sts.Spec.Template.Annotations[restartedAtAnnotation] == "" {
+		t.Error("Expected StatefulSet pod template to be stamped")
+	}
+}
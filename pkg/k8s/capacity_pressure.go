@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// gpuResourceNames are the extended resources counted as GPUs
+var gpuResourceNames = []corev1.ResourceName{"nvidia.com/gpu", "amd.com/gpu"}
+
+// ResourceAmounts is an amount of CPU, memory and GPUs
+type ResourceAmounts struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+	GPUs          int64 `json:"gpus"`
+}
+
+// CapacityPressure compares the resources requested by pods stuck in Pending
+// with the headroom left on ready, schedulable nodes
+type CapacityPressure struct {
+	PendingPods       int             `json:"pendingPods"`       // Pending longer than podPendingAgeThreshold
+	UnschedulablePods int             `json:"unschedulablePods"` // of those, rejected by the scheduler
+	PendingRequests   ResourceAmounts `json:"pendingRequests"`   // requested by the unschedulable pods
+	Headroom          ResourceAmounts `json:"headroom"`          // allocatable minus requests of scheduled pods
+	NeedsCapacity     bool            `json:"needsCapacity"`
+}
+
+// computeCapacityPressure counts the pods that have been unschedulable for
+// longer than podPendingAgeThreshold and the resources they request. A cluster
+// needs capacity when any such pod exists, since the scheduler found no node
+// for it, whether or not the summed headroom would fit it.
+func computeCapacityPressure(nodes []corev1.Node, pods []corev1.Pod, now time.Time) *CapacityPressure {
+	p := &CapacityPressure{}
+
+	schedulable := make(map[string]bool)
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !isNodeReady(&node) {
+			continue
+		}
+		schedulable[node.Name] = true
+		p.Headroom.CPUMillicores += node.Status.Allocatable.Cpu().MilliValue()
+		p.Headroom.MemoryBytes += node.Status.Allocatable.Memory().Value()
+		for _, name := range gpuResourceNames {
+			if qty, ok := node.Status.Allocatable[name]; ok {
+				p.Headroom.GPUs += qty.Value()
+			}
+		}
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requests := podResourceRequests(&pod)
+		if schedulable[pod.Spec.NodeName] {
+			p.Headroom.CPUMillicores -= requests.CPUMillicores
+			p.Headroom.MemoryBytes -= requests.MemoryBytes
+			p.Headroom.GPUs -= requests.GPUs
+			continue
+		}
+		if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" || now.Sub(pod.CreationTimestamp.Time) < podPendingAgeThreshold {
+			continue
+		}
+		p.PendingPods++
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+				p.UnschedulablePods++
+				p.PendingRequests.CPUMillicores += requests.CPUMillicores
+				p.PendingRequests.MemoryBytes += requests.MemoryBytes
+				p.PendingRequests.GPUs += requests.GPUs
+				break
+			}
+		}
+	}
+	p.Headroom.CPUMillicores = max(p.Headroom.CPUMillicores, 0)
+	p.Headroom.MemoryBytes = max(p.Headroom.MemoryBytes, 0)
+	p.Headroom.GPUs = max(p.Headroom.GPUs, 0)
+	p.NeedsCapacity = p.UnschedulablePods > 0
+	return p
+}
+
+// podResourceRequests returns what the scheduler reserves for a pod: the sum
+// of its containers' requests, or its largest init container request if higher
+func podResourceRequests(pod *corev1.Pod) ResourceAmounts {
+	amounts := func(rl corev1.ResourceList) ResourceAmounts {
+		a := ResourceAmounts{CPUMillicores: rl.Cpu().MilliValue(), MemoryBytes: rl.Memory().Value()}
+		for _, name := range gpuResourceNames {
+			if qty, ok := rl[name]; ok {
+				a.GPUs += qty.Value()
+			}
+		}
+		return a
+	}
+	var total ResourceAmounts
+	for _, c := range pod.Spec.Containers {
+		a := amounts(c.Resources.Requests)
+		total.CPUMillicores += a.CPUMillicores
+		total.MemoryBytes += a.MemoryBytes
+		total.GPUs += a.GPUs
+	}
+	for _, c := range pod.Spec.InitContainers {
+		a := amounts(c.Resources.Requests)
+		total.CPUMillicores = max(total.CPUMillicores, a.CPUMillicores)
+		total.MemoryBytes = max(total.MemoryBytes, a.MemoryBytes)
+		total.GPUs = max(total.GPUs, a.GPUs)
+	}
+	return total
+}
+
+// capacityPressureIssue describes the capacity a cluster is short of, or ""
+func capacityPressureIssue(p *CapacityPressure) string {
+	if !p.NeedsCapacity {
+		return ""
+	}
+	return fmt.Sprintf("%d pod(s) unschedulable for over %s requesting %dm CPU, %.1fGB memory, %d GPU(s); headroom %dm CPU, %.1fGB memory, %d GPU(s)",
+		p.UnschedulablePods, podPendingAgeThreshold,
+		p.PendingRequests.CPUMillicores, float64(p.PendingRequests.MemoryBytes)/(1024*1024*1024), p.PendingRequests.GPUs,
+		p.Headroom.CPUMillicores, float64(p.Headroom.MemoryBytes)/(1024*1024*1024), p.Headroom.GPUs)
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCapacityPressure(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	node := func(name string, ready corev1.ConditionStatus, cordoned bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Unschedulable: cordoned},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+					"nvidia.com/gpu":      resource.MustParse("2"),
+				},
+			},
+		}
+	}
+	pod := func(name, nodeName string, phase corev1.PodPhase, age time.Duration, cpu, gpus string, unschedulable bool) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(time.Now().Add(-age))},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+					"nvidia.com/gpu":      resource.MustParse(gpus),
+				}}}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+		if unschedulable {
+			p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}}
+		}
+		return p
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		node("ready", corev1.ConditionTrue, false),
+		node("notready", corev1.ConditionFalse, false),
+		node("cordoned", corev1.ConditionTrue, true),
+		pod("running", "ready", corev1.PodRunning, time.Hour, "3", "1", false),
+		pod("stuck", "", corev1.PodPending, 10*time.Minute, "2", "2", true),
+		pod("waiting", "", corev1.PodPending, 10*time.Minute, "1", "0", false),
+		pod("fresh", "", corev1.PodPending, 10*time.Second, "8", "4", true),
+	)
+
+	health, err := m.GetClusterHealth(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetClusterHealth failed: %v", err)
+	}
+	p := health.Pressure
+	if p == nil {
+		t.Fatal("Expected capacity pressure to be computed")
+	}
+	if p.PendingPods != 2 || p.UnschedulablePods != 1 || !p.NeedsCapacity {
+		t.Errorf("Unexpected pending pod counts: %+v", p)
+	}
+	if p.PendingRequests != (ResourceAmounts{CPUMillicores: 2000, MemoryBytes: 1 << 30, GPUs: 2}) {
+		t.Errorf("Unexpected pending requests: %+v", p.PendingRequests)
+	}
+	// Only the ready, schedulable node counts, less the running pod's requests
+	if p.Headroom != (ResourceAmounts{CPUMillicores: 1000, MemoryBytes: 7 << 30, GPUs: 1}) {
+		t.Errorf("Unexpected headroom: %+v", p.Headroom)
+	}
+	var found bool
+	for _, issue := range health.Issues {
+		found = found || strings.HasPrefix(issue, "1 pod(s) unschedulable")
+	}
+	if !found {
+		t.Errorf("Expected a capacity issue, got %v", health.Issues)
+	}
+
+	// Init containers reserve their largest request when it exceeds the containers'
+	initPod := pod("init", "", corev1.PodPending, 0, "1", "0", false)
+	initPod.Spec.InitContainers = []corev1.Container{{Name: "init", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("3"),
+	}}}}
+	if r := podResourceRequests(initPod); r.CPUMillicores != 3000 || r.MemoryBytes != 1<<30 {
+		t.Errorf("Unexpected init container requests: %+v", r)
+	}
+}
@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Cluster API scalable node pool resources
+var (
+	capiMachineDeploymentGVR = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Version:  "v1beta1",
+		Resource: "machinedeployments",
+	}
+	capiMachinePoolGVR = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Version:  "v1beta1",
+		Resource: "machinepools",
+	}
+)
+
+// Cluster autoscaler node group bounds, set on MachineDeployments and MachinePools
+const (
+	capiAutoscalerMinAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+	capiAutoscalerMaxAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+
+	// maxMachinePoolReplicas guards against typos when no autoscaler max is set
+	maxMachinePoolReplicas = 1000
+)
+
+// MachinePool is a Cluster API MachineDeployment or MachinePool that can be scaled
+type MachinePool struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	Cluster            string `json:"cluster"`     // management cluster context
+	Kind               string `json:"kind"`        // MachineDeployment or MachinePool
+	ClusterName        string `json:"clusterName"` // workload cluster (spec.clusterName)
+	Replicas           int32  `json:"replicas"`
+	ReadyReplicas      int32  `json:"readyReplicas"`
+	AvailableReplicas  int32  `json:"availableReplicas"`
+	Phase              string `json:"phase,omitempty"`
+	InfrastructureKind string `json:"infrastructureKind,omitempty"` // e.g. AWSMachineTemplate
+	Version            string `json:"version,omitempty"`
+	AutoscalerMin      *int32 `json:"autoscalerMin,omitempty"`
+	AutoscalerMax      *int32 `json:"autoscalerMax,omitempty"`
+}
+
+// capiKindGVR maps a MachinePool kind to its resource
+func capiKindGVR(kind string) (schema.GroupVersionResource, error) {
+	switch kind {
+	case "", "MachineDeployment":
+		return capiMachineDeploymentGVR, nil
+	case "MachinePool":
+		return capiMachinePoolGVR, nil
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("unsupported kind %q", kind)
+}
+
+// annotationInt32 parses an integer annotation, returning nil when absent or invalid
+func annotationInt32(annotations map[string]string, key string) *int32 {
+	v, ok := annotations[key]
+	if !ok {
+		return nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return nil
+	}
+	i := int32(n)
+	return &i
+}
+
+// machinePoolFromUnstructured converts a MachineDeployment or MachinePool
+func machinePoolFromUnstructured(cluster, kind string, item *unstructured.Unstructured) MachinePool {
+	obj := item.Object
+	p := MachinePool{
+		Name:          item.GetName(),
+		Namespace:     item.GetNamespace(),
+		Cluster:       cluster,
+		Kind:          kind,
+		AutoscalerMin: annotationInt32(item.GetAnnotations(), capiAutoscalerMinAnnotation),
+		AutoscalerMax: annotationInt32(item.GetAnnotations(), capiAutoscalerMaxAnnotation),
+	}
+	p.ClusterName, _, _ = unstructured.NestedString(obj, "spec", "clusterName")
+	p.Phase, _, _ = unstructured.NestedString(obj, "status", "phase")
+	p.Version, _, _ = unstructured.NestedString(obj, "spec", "template", "spec", "version")
+	p.InfrastructureKind, _, _ = unstructured.NestedString(obj, "spec", "template", "spec", "infrastructureRef", "kind")
+
+	if v, found, _ := unstructured.NestedInt64(obj, "spec", "replicas"); found {
+		p.Replicas = int32(v)
+	}
+	if v, found, _ := unstructured.NestedInt64(obj, "status", "readyReplicas"); found {
+		p.ReadyReplicas = int32(v)
+	}
+	if v, found, _ := unstructured.NestedInt64(obj, "status", "availableReplicas"); found {
+		p.AvailableReplicas = int32(v)
+	}
+	return p
+}
+
+// ListMachinePools returns the Cluster API MachineDeployments and MachinePools in a
+// management cluster. Clusters without Cluster API return an empty list.
+func (m *MultiClusterClient) ListMachinePools(ctx context.Context, contextName string) ([]MachinePool, error) {
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	pools := []MachinePool{}
+	for _, kind := range []string{"MachineDeployment", "MachinePool"} {
+		gvr, _ := capiKindGVR(kind)
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue // CRD not installed
+		}
+		for i := range list.Items {
+			pools = append(pools, machinePoolFromUnstructured(contextName, kind, &list.Items[i]))
+		}
+	}
+	return pools, nil
+}
+
+// validateMachinePoolReplicas checks a requested replica count against the pool's autoscaler bounds
+func validateMachinePoolReplicas(pool MachinePool, replicas int32) error {
+	if replicas < 0 {
+		return fmt.Errorf("replicas must not be negative")
+	}
+	if pool.AutoscalerMin != nil && replicas < *pool.AutoscalerMin {
+		return fmt.Errorf("replicas %d is below the autoscaler minimum of %d", replicas, *pool.AutoscalerMin)
+	}
+	if pool.AutoscalerMax != nil {
+		if replicas > *pool.AutoscalerMax {
+			return fmt.Errorf("replicas %d is above the autoscaler maximum of %d", replicas, *pool.AutoscalerMax)
+		}
+	} else if replicas > maxMachinePoolReplicas {
+		return fmt.Errorf("replicas must not exceed %d", maxMachinePoolReplicas)
+	}
+	return nil
+}
+
+// ScaleMachinePool sets spec.replicas on a MachineDeployment or MachinePool and returns the updated pool
+func (m *MultiClusterClient) ScaleMachinePool(ctx context.Context, contextName, kind, namespace, name string, replicas int32) (*MachinePool, error) {
+	gvr, err := capiKindGVR(kind)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		kind = "MachineDeployment"
+	}
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMachinePoolReplicas(machinePoolFromUnstructured(contextName, kind, current), replicas); err != nil {
+		return nil, err
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"replicas": replicas}})
+	updated, err := dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pool := machinePoolFromUnstructured(contextName, kind, updated)
+	return &pool, nil
+}
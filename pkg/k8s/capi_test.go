@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestListAndScaleMachinePools(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	md := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cluster.x-k8s.io/v1beta1",
+		"kind":       "MachineDeployment",
+		"metadata": map[string]interface{}{
+			"name":      "gpu-workers",
+			"namespace": "default",
+			"annotations": map[string]interface{}{
+				capiAutoscalerMinAnnotation: "1",
+				capiAutoscalerMaxAnnotation: "6",
+			},
+		},
+		"spec": map[string]interface{}{
+			"clusterName": "prod",
+			"replicas":    int64(2),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"version":           "v1.30.2",
+					"infrastructureRef": map[string]interface{}{"kind": "AWSMachineTemplate", "name": "gpu"},
+				},
+			},
+		},
+		"status": map[string]interface{}{"phase": "Running", "readyReplicas": int64(2), "availableReplicas": int64(2)},
+	}}
+	m.dynamicClients["mgmt"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			capiMachineDeploymentGVR: "MachineDeploymentList",
+			capiMachinePoolGVR:       "MachinePoolList",
+		}, md)
+
+	pools, err := m.ListMachinePools(context.Background(), "mgmt")
+	if err != nil {
+		t.Fatalf("ListMachinePools failed: %v", err)
+	}
+	if len(pools) != 1 {
+		t.Fatalf("Expected 1 pool, got %d", len(pools))
+	}
+	p := pools[0]
+	if p.ClusterName != "prod" || p.Replicas != 2 || p.InfrastructureKind != "AWSMachineTemplate" || p.Version != "v1.30.2" {
+		t.Errorf("Unexpected pool: %+v", p)
+	}
+	if p.AutoscalerMin == nil || *p.AutoscalerMin != 1 || p.AutoscalerMax == nil || *p.AutoscalerMax != 6 {
+		t.Errorf("Expected autoscaler bounds 1-6, got %+v", p)
+	}
+
+	if _, err := m.ScaleMachinePool(context.Background(), "mgmt", "", "default", "gpu-workers", 8); err == nil {
+		t.Error("Expected scaling above the autoscaler maximum to fail")
+	}
+	scaled, err := m.ScaleMachinePool(context.Background(), "mgmt", "MachineDeployment", "default", "gpu-workers", 4)
+	if err != nil {
+		t.Fatalf("ScaleMachinePool failed: %v", err)
+	}
+	if scaled.Replicas != 4 {
+		t.Errorf("Expected 4 replicas after scaling, got %d", scaled.Replicas)
+	}
+
+	if _, err := m.ScaleMachinePool(context.Background(), "mgmt", "Deployment", "default", "gpu-workers", 4); err == nil {
+		t.Error("Expected unsupported kind to be rejected")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/fsnotify/fsnotify"
 	authorizationv1 "k8s.io/api/authorization/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -21,8 +23,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -46,17 +50,40 @@ type MultiClusterClient struct {
 	kubeconfig      string
 	clients         map[string]kubernetes.Interface
 	dynamicClients  map[string]dynamic.Interface
+	metadataClients map[string]metadata.Interface // PartialObjectMetadata-only listing
 	configs         map[string]*rest.Config
+	httpClients     map[string]*http.Client // one transport per context, shared by its clients
 	rawConfig       *api.Config
 	healthCache     map[string]*ClusterHealth
 	cacheTTL        time.Duration
 	cacheTime       map[string]time.Time
+	healthExpiry    map[string]time.Time // set by the refresher to its next probe of the cluster
 	watcher         *fsnotify.Watcher
 	stopWatch       chan struct{}
 	onReload        func()               // Callback when config is reloaded
 	inClusterConfig *rest.Config         // In-cluster config when running inside k8s
 	inClusterName   string               // Detected friendly name for in-cluster (e.g. "fmaas-vllm-d")
 	slowClusters    map[string]time.Time // clusters that recently timed out (reduced timeout)
+	kubeconfigDirs  []string             // mounted kubeconfig Secrets/ConfigMaps (KUBECONFIG_DIRS)
+	mountedConfig   *api.Config          // contexts merged from kubeconfigDirs
+	sharedCache     SharedCache          // optional cache shared with other replicas (KC_CACHE_URL)
+	clusterFilter   *ClusterFilter       // optional include/exclude patterns applied by ListClusters
+	clusterPolicy   func() ClusterPolicy // user-defined filters and groups from settings
+	compiledPolicy  *compiledClusterPolicy
+	policyMu        sync.Mutex         // guards compiledPolicy
+	nodeSnapshots   nodePodSnapshots   // shared node+pod listings per context
+	retryPolicy     RetryPolicy        // backoff for transient API read failures
+	clientTuning    ClientTuning       // QPS/Burst and keep-alive of API clients
+	probeFocus      probeFocus         // clusters the UI shows, probed first and more often
+	stopRefresher   context.CancelFunc // stops the background health refresher
+	namespaceScope  NamespaceScope     // optional namespace restriction for non-admin identities
+	scopeCache      map[string]*scopedNamespaces
+	scopeMu         sync.Mutex // guards scopeCache
+
+	gpuOperatorChecks  func() map[string][]GPUOperatorPodCheck // per-stack overrides of operator pod checks
+	managedContexts    map[string]bool                         // contexts registered with an OCM hub
+	controlPlaneConfig *api.Config                             // contexts of KubeFlex control planes, see RefreshControlPlanes
+	probeTimings       probeTimings                            // recent API server probe latencies per context
 }
 
 // IsInCluster returns true if the server is running inside a Kubernetes cluster
@@ -136,7 +163,7 @@ func (m *MultiClusterClient) InjectDynamicClient(contextName string, client dyna
 
 // Reload reloads the kubeconfig from disk
 func (m *MultiClusterClient) Reload() error {
-	config, err := clientcmd.LoadFromFile(m.kubeconfig)
+	config, err := clientcmd.LoadFromFile(m.KubeconfigPath())
 	if err != nil {
 		return err
 	}
@@ -159,6 +186,12 @@ type ClusterInfo struct {
 	NodeCount  int    `json:"nodeCount,omitempty"`
 	PodCount   int    `json:"podCount,omitempty"`
 	IsCurrent  bool   `json:"isCurrent,omitempty"`
+	// Groups are the user-defined cluster groups (settings) this cluster belongs to
+	Groups []string `json:"groups,omitempty"`
+	// DisplayName, Color and Pinned are user preferences from settings
+	DisplayName string `json:"displayName,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Pinned      bool   `json:"pinned,omitempty"`
 }
 
 // ClusterHealth represents cluster health status
@@ -179,6 +212,8 @@ type ClusterHealth struct {
 	MemoryGB     float64 `json:"memoryGB"`     // Total allocatable memory in GB
 	StorageBytes int64   `json:"storageBytes"` // Total ephemeral storage in bytes
 	StorageGB    float64 `json:"storageGB"`    // Total ephemeral storage in GB
+	// Allocatable capacity split by node OS/architecture, largest first
+	Platforms []PlatformCapacity `json:"platforms,omitempty"`
 	// Resource requests (allocated/used)
 	CpuRequestsMillicores int64   `json:"cpuRequestsMillicores,omitempty"` // Sum of pod CPU requests in millicores
 	CpuRequestsCores      float64 `json:"cpuRequestsCores,omitempty"`      // Sum of pod CPU requests in cores
@@ -187,6 +222,10 @@ type ClusterHealth struct {
 	// PVC metrics
 	PVCCount      int `json:"pvcCount,omitempty"`      // Total PVC count
 	PVCBoundCount int `json:"pvcBoundCount,omitempty"` // Bound PVC count
+	// Control plane components, etcd and API server latency
+	ControlPlane *ControlPlaneHealth `json:"controlPlane,omitempty"`
+	// Unschedulable pending pods against the remaining headroom
+	Pressure *CapacityPressure `json:"pressure,omitempty"`
 	// Issues and timing
 	Issues    []string `json:"issues,omitempty"`
 	CheckedAt string   `json:"checkedAt,omitempty"`
@@ -241,6 +280,8 @@ type Event struct {
 	Age       string `json:"age,omitempty"`
 	FirstSeen string `json:"firstSeen,omitempty"`
 	LastSeen  string `json:"lastSeen,omitempty"`
+	// Owner is the workload the object rolls up to, e.g. Deployment/api for a pod event
+	Owner string `json:"owner,omitempty"`
 }
 
 // DeploymentIssue represents a deployment with issues
@@ -280,6 +321,10 @@ type GPUNode struct {
 	MIGCapable         bool   `json:"migCapable,omitempty"`         // Whether MIG is supported
 	MIGStrategy        string `json:"migStrategy,omitempty"`        // MIG strategy if enabled
 	Manufacturer       string `json:"manufacturer,omitempty"`       // Manufacturer (NVIDIA, AMD, Intel, Google)
+	// AMD GPU info from the AMD node labeller
+	ROCmVersion         string `json:"rocmVersion,omitempty"`         // amdgpu driver (ROCm) version
+	ComputePartitioning string `json:"computePartitioning,omitempty"` // SPX, DPX, QPX, CPX
+	MemoryPartitioning  string `json:"memoryPartitioning,omitempty"`  // NPS1, NPS4
 }
 
 // NodeCondition represents a node condition status
@@ -395,6 +440,9 @@ type Deployment struct {
 	Age               string            `json:"age,omitempty"`
 	Labels            map[string]string `json:"labels,omitempty"`
 	Annotations       map[string]string `json:"annotations,omitempty"`
+	// PDBs covering the deployment's pods and the fewest disruptions they allow
+	PDBs               []string `json:"pdbs,omitempty"`
+	DisruptionsAllowed *int32   `json:"disruptionsAllowed,omitempty"`
 }
 
 // Service represents a Kubernetes service
@@ -554,6 +602,15 @@ type CronJob struct {
 	LastSchedule string            `json:"lastSchedule,omitempty"`
 	Age          string            `json:"age,omitempty"`
 	Labels       map[string]string `json:"labels,omitempty"`
+	TimeZone     string            `json:"timeZone,omitempty"`
+	// Schedule analysis; times are RFC3339
+	LastScheduleTime   string       `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime string       `json:"lastSuccessfulTime,omitempty"`
+	NextSchedule       string       `json:"nextSchedule,omitempty"`
+	Missed             bool         `json:"missed,omitempty"`        // a run that was due did not start
+	MissedSince        string       `json:"missedSince,omitempty"`   // when the missed run was due
+	ScheduleError      string       `json:"scheduleError,omitempty"` // the schedule or time zone can't be parsed
+	RecentRuns         []CronJobRun `json:"recentRuns,omitempty"`    // newest first
 }
 
 // Ingress represents a Kubernetes Ingress
@@ -638,7 +695,13 @@ func NewMultiClusterClient(kubeconfig string) (*MultiClusterClient, error) {
 		healthCache:    make(map[string]*ClusterHealth),
 		cacheTTL:       clusterCacheTTL,
 		cacheTime:      make(map[string]time.Time),
+		healthExpiry:   make(map[string]time.Time),
 		slowClusters:   make(map[string]time.Time),
+		kubeconfigDirs: kubeconfigDirsFromEnv(),
+		sharedCache:    SharedCacheFromEnv(),
+		retryPolicy:    retryPolicyFromEnv(),
+		clientTuning:   clientTuningFromEnv(),
+		namespaceScope: namespaceScopeFromEnv(),
 	}
 
 	// Try to detect if we're running in-cluster
@@ -717,15 +780,18 @@ func (m *MultiClusterClient) LoadConfig() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// If we have in-cluster config and no kubeconfig file, use that
-	if m.inClusterConfig != nil {
+	// If we have in-cluster config (or mounted kubeconfigs) and no kubeconfig file, use that
+	if m.inClusterConfig != nil || len(m.kubeconfigDirs) > 0 {
 		if _, err := os.Stat(m.kubeconfig); os.IsNotExist(err) {
-			log.Println("No kubeconfig file, using in-cluster config only")
+			log.Println("No kubeconfig file, using in-cluster config and mounted kubeconfigs only")
 			m.rawConfig = nil
-			m.clients = make(map[string]kubernetes.Interface)
-			m.configs = make(map[string]*rest.Config)
+			m.loadMountedConfigLocked()
+			m.resetClientsLocked()
 			m.healthCache = make(map[string]*ClusterHealth)
 			m.cacheTime = make(map[string]time.Time)
+			m.healthExpiry = make(map[string]time.Time)
+			m.resetNodePodSnapshots()
+			m.resetNamespaceScopeCache()
 			return nil
 		}
 	}
@@ -736,12 +802,14 @@ func (m *MultiClusterClient) LoadConfig() error {
 	}
 
 	m.rawConfig = config
+	m.loadMountedConfigLocked()
 	// Clear cached clients when config reloads
-	m.clients = make(map[string]kubernetes.Interface)
-	m.dynamicClients = make(map[string]dynamic.Interface)
-	m.configs = make(map[string]*rest.Config)
+	m.resetClientsLocked()
 	m.healthCache = make(map[string]*ClusterHealth)
 	m.cacheTime = make(map[string]time.Time)
+	m.healthExpiry = make(map[string]time.Time)
+	m.resetNodePodSnapshots()
+	m.resetNamespaceScopeCache()
 	return nil
 }
 
@@ -757,16 +825,27 @@ func (m *MultiClusterClient) StartWatching() error {
 	m.watcher = watcher
 	m.stopWatch = make(chan struct{})
 
-	// Watch the kubeconfig file
+	// Watch the kubeconfig file. It may legitimately be absent when running
+	// in-cluster with mounted kubeconfigs.
 	if err := watcher.Add(m.kubeconfig); err != nil {
-		watcher.Close()
-		return fmt.Errorf("failed to watch kubeconfig: %w", err)
+		if len(m.kubeconfigDirs) == 0 {
+			watcher.Close()
+			return fmt.Errorf("failed to watch kubeconfig: %w", err)
+		}
+	} else {
+		// Also watch the directory (for editors that do atomic saves)
+		dir := filepath.Dir(m.kubeconfig)
+		if err := watcher.Add(dir); err != nil {
+			log.Printf("Warning: could not watch kubeconfig directory: %v", err)
+		}
 	}
 
-	// Also watch the directory (for editors that do atomic saves)
-	dir := filepath.Dir(m.kubeconfig)
-	if err := watcher.Add(dir); err != nil {
-		log.Printf("Warning: could not watch kubeconfig directory: %v", err)
+	// Mounted Secrets/ConfigMaps are updated by kubelet swapping a ..data symlink,
+	// which shows up as a Create in the mount directory
+	for _, dir := range mountedKubeconfigWatchDirs(m.kubeconfigDirs) {
+		if err := watcher.Add(dir); err != nil {
+			log.Printf("Warning: could not watch mounted kubeconfig directory %s: %v", dir, err)
+		}
 	}
 
 	go m.watchLoop()
@@ -788,13 +867,18 @@ func (m *MultiClusterClient) reloadAndNotify() {
 	// Re-add file watch — after atomic writes (rm+create or rename-over),
 	// the old inode-level watch is dead. This re-establishes it on the new inode.
 	if m.watcher != nil {
-		_ = m.watcher.Remove(m.kubeconfig)
-		if err := m.watcher.Add(m.kubeconfig); err != nil {
+		kubeconfig := m.KubeconfigPath()
+		_ = m.watcher.Remove(kubeconfig)
+		if err := m.watcher.Add(kubeconfig); err != nil {
 			log.Printf("Warning: could not re-watch kubeconfig file: %v", err)
 		}
 	}
 
-	// Notify listeners
+	m.notifyReload()
+}
+
+// notifyReload calls the SetOnReload callback
+func (m *MultiClusterClient) notifyReload() {
 	m.mu.RLock()
 	callback := m.onReload
 	m.mu.RUnlock()
@@ -803,6 +887,49 @@ func (m *MultiClusterClient) reloadAndNotify() {
 	}
 }
 
+// KubeconfigPath returns the kubeconfig file the client reads
+func (m *MultiClusterClient) KubeconfigPath() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.kubeconfig
+}
+
+// SetKubeconfig switches to another kubeconfig file, e.g. when a config profile
+// is activated. Clients and caches are rebuilt, the file watch moves to the new
+// file and the SetOnReload callback runs. The previous file stays in use if the
+// new one cannot be loaded.
+func (m *MultiClusterClient) SetKubeconfig(path string) error {
+	if _, err := clientcmd.LoadFromFile(path); err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	m.mu.Lock()
+	previous := m.kubeconfig
+	m.kubeconfig = path
+	m.mu.Unlock()
+	if err := m.LoadConfig(); err != nil {
+		m.mu.Lock()
+		m.kubeconfig = previous
+		m.mu.Unlock()
+		return err
+	}
+
+	if m.watcher != nil && path != previous {
+		_ = m.watcher.Remove(previous)
+		if filepath.Dir(previous) != filepath.Dir(path) {
+			_ = m.watcher.Remove(filepath.Dir(previous))
+		}
+		if err := m.watcher.Add(path); err != nil {
+			log.Printf("Warning: could not watch kubeconfig %s: %v", path, err)
+		}
+		if err := m.watcher.Add(filepath.Dir(path)); err != nil {
+			log.Printf("Warning: could not watch kubeconfig directory: %v", err)
+		}
+	}
+	log.Printf("Switched kubeconfig to %s", path)
+	m.notifyReload()
+	return nil
+}
+
 func (m *MultiClusterClient) watchLoop() {
 	// Debounce timer to avoid reloading multiple times for rapid changes
 	var debounceTimer *time.Timer
@@ -813,9 +940,10 @@ func (m *MultiClusterClient) watchLoop() {
 	pollTicker := time.NewTicker(clusterEventPollInterval)
 	defer pollTicker.Stop()
 	var lastModTime time.Time
-	if info, err := os.Stat(m.kubeconfig); err == nil {
+	if info, err := os.Stat(m.KubeconfigPath()); err == nil {
 		lastModTime = info.ModTime()
 	}
+	lastMounted := mountedKubeconfigFingerprint(m.kubeconfigDirs)
 
 	triggerReload := func() {
 		if debounceTimer != nil {
@@ -835,15 +963,19 @@ func (m *MultiClusterClient) watchLoop() {
 			if !ok {
 				return
 			}
-			// Check if this event is for our kubeconfig file
-			if event.Name == m.kubeconfig || filepath.Base(event.Name) == filepath.Base(m.kubeconfig) {
+			// Check if this event is for our kubeconfig file (it can change with SetKubeconfig)
+			kubeconfig := m.KubeconfigPath()
+			if event.Name == kubeconfig || filepath.Base(event.Name) == filepath.Base(kubeconfig) {
 				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
 					// Update lastModTime so the poller doesn't double-trigger
-					if info, err := os.Stat(m.kubeconfig); err == nil {
+					if info, err := os.Stat(kubeconfig); err == nil {
 						lastModTime = info.ModTime()
 					}
 					triggerReload()
 				}
+			} else if m.isMountedKubeconfigEvent(event.Name) {
+				lastMounted = mountedKubeconfigFingerprint(m.kubeconfigDirs)
+				triggerReload()
 			}
 		case err, ok := <-m.watcher.Errors:
 			if !ok {
@@ -852,7 +984,15 @@ func (m *MultiClusterClient) watchLoop() {
 			log.Printf("Kubeconfig watcher error: %v", err)
 		case <-pollTicker.C:
 			// Polling fallback: detect changes that fsnotify missed
-			info, err := os.Stat(m.kubeconfig)
+			if len(m.kubeconfigDirs) > 0 {
+				if fp := mountedKubeconfigFingerprint(m.kubeconfigDirs); fp != lastMounted {
+					lastMounted = fp
+					log.Printf("Mounted kubeconfig change detected by poll")
+					triggerReload()
+					continue
+				}
+			}
+			info, err := os.Stat(m.KubeconfigPath())
 			if err != nil {
 				continue
 			}
@@ -887,15 +1027,21 @@ func (m *MultiClusterClient) ListClusters(ctx context.Context) ([]ClusterInfo, e
 	m.mu.RLock()
 	rawConfig := m.rawConfig
 	inClusterConfig := m.inClusterConfig
+	mountedConfig := m.mountedConfig
+	filter := m.clusterFilter
+	policy := m.clusterPolicy
+	managed := m.managedContexts
+	controlPlaneConfig := m.controlPlaneConfig
 	m.mu.RUnlock()
 
-	if rawConfig == nil && inClusterConfig == nil {
+	if rawConfig == nil && inClusterConfig == nil && mountedConfig == nil {
 		if err := m.LoadConfig(); err != nil {
 			return nil, err
 		}
 		m.mu.RLock()
 		rawConfig = m.rawConfig
 		inClusterConfig = m.inClusterConfig
+		mountedConfig = m.mountedConfig
 		m.mu.RUnlock()
 	}
 
@@ -918,53 +1064,73 @@ func (m *MultiClusterClient) ListClusters(ctx context.Context) ([]ClusterInfo, e
 
 	// Add clusters from kubeconfig if available
 	if rawConfig != nil {
-		currentContext := rawConfig.CurrentContext
-
-		for contextName, contextInfo := range rawConfig.Contexts {
-			clusterInfo, exists := rawConfig.Clusters[contextInfo.Cluster]
-			server := ""
-			if exists {
-				server = clusterInfo.Server
-			}
+		kubeconfigClusters := clustersFromConfig(rawConfig, "kubeconfig")
+		tagManagedClusters(kubeconfigClusters, managed)
+		clusters = append(clusters, kubeconfigClusters...)
+	}
 
-			// Get the user name from the AuthInfo reference
-			user := contextInfo.AuthInfo
-
-			// Detect auth method from kubeconfig AuthInfo
-			authMethod := "unknown"
-			if ai, ok := rawConfig.AuthInfos[contextInfo.AuthInfo]; ok && ai != nil {
-				switch {
-				case ai.Exec != nil:
-					authMethod = "exec"
-				case ai.Token != "" || ai.TokenFile != "":
-					authMethod = "token"
-				case len(ai.ClientCertificateData) > 0 || ai.ClientCertificate != "":
-					authMethod = "certificate"
-				case ai.AuthProvider != nil:
-					authMethod = "auth-provider"
-				}
-			}
+	// Add remote clusters from mounted kubeconfig Secrets/ConfigMaps
+	if mountedConfig != nil {
+		clusters = append(clusters, clustersFromConfig(mountedConfig, kubeconfigSourceMounted)...)
+	}
 
-			clusters = append(clusters, ClusterInfo{
-				Name:       contextName,
-				Context:    contextName,
-				Server:     server,
-				User:       user,
-				AuthMethod: authMethod,
-				Source:     "kubeconfig",
-				IsCurrent:  contextName == currentContext,
-			})
-		}
+	// Add control planes hosted by KubeFlex
+	if controlPlaneConfig != nil {
+		clusters = append(clusters, clustersFromConfig(controlPlaneConfig, ClusterSourceKubeFlex)...)
 	}
 
-	// Sort by name
-	sort.Slice(clusters, func(i, j int) bool {
-		return clusters[i].Name < clusters[j].Name
-	})
+	clusters = filter.Apply(clusters)
+	clusters = m.applyClusterPolicy(clusters, policy)
+
+	// Sort by name, pinned clusters first
+	sortClusters(clusters)
 
 	return clusters, nil
 }
 
+// clustersFromConfig lists the contexts of a kubeconfig
+func clustersFromConfig(config *api.Config, source string) []ClusterInfo {
+	var clusters []ClusterInfo
+	currentContext := config.CurrentContext
+
+	for contextName, contextInfo := range config.Contexts {
+		clusterInfo, exists := config.Clusters[contextInfo.Cluster]
+		server := ""
+		if exists {
+			server = clusterInfo.Server
+		}
+
+		// Get the user name from the AuthInfo reference
+		user := contextInfo.AuthInfo
+
+		// Detect auth method from kubeconfig AuthInfo
+		authMethod := "unknown"
+		if ai, ok := config.AuthInfos[contextInfo.AuthInfo]; ok && ai != nil {
+			switch {
+			case ai.Exec != nil:
+				authMethod = "exec"
+			case ai.Token != "" || ai.TokenFile != "":
+				authMethod = "token"
+			case len(ai.ClientCertificateData) > 0 || ai.ClientCertificate != "":
+				authMethod = "certificate"
+			case ai.AuthProvider != nil:
+				authMethod = "auth-provider"
+			}
+		}
+
+		clusters = append(clusters, ClusterInfo{
+			Name:       contextName,
+			Context:    contextName,
+			Server:     server,
+			User:       user,
+			AuthMethod: authMethod,
+			Source:     source,
+			IsCurrent:  contextName == currentContext,
+		})
+	}
+	return clusters
+}
+
 // DeduplicatedClusters returns one cluster per unique server URL, preferring
 // short/user-friendly context names over auto-generated OpenShift names.
 // This prevents double-counting when the same physical cluster is reachable
@@ -994,8 +1160,12 @@ func (m *MultiClusterClient) DeduplicatedClusters(ctx context.Context) ([]Cluste
 			serverGroups[cl.Server] = &group{primary: cl}
 			continue
 		}
-		// Pick the shorter/friendlier name as primary
-		if isBetterClusterName(cl.Name, g.primary.Name) {
+		// Prefer the context the user named or pinned, then the shorter/friendlier name
+		better := hasClusterPreference(cl) && !hasClusterPreference(g.primary)
+		if !better && hasClusterPreference(cl) == hasClusterPreference(g.primary) {
+			better = isBetterClusterName(cl.Name, g.primary.Name)
+		}
+		if better {
 			g.others = append(g.others, g.primary.Name)
 			g.primary = cl
 		} else {
@@ -1009,16 +1179,15 @@ func (m *MultiClusterClient) DeduplicatedClusters(ctx context.Context) ([]Cluste
 	}
 	result = append(result, noServer...)
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Name < result[j].Name
-	})
+	sortClusters(result)
 	return result, nil
 }
 
 // WarmupHealthCache probes all clusters on startup to populate the health cache.
 // Without this, HealthyClusters() treats unknown clusters as healthy, causing
 // every SSE stream to hit all clusters (including offline ones) on first load.
-// Uses a lightweight namespace list (Limit=1) with a 5s per-cluster timeout.
+// Uses a lightweight namespace list (Limit=1) with a 5s per-cluster timeout
+// unless the cluster configures its own health timeout.
 // Blocks for at most 8s total.
 func (m *MultiClusterClient) WarmupHealthCache() {
 	ctx, cancel := context.WithTimeout(context.Background(), clusterHealthCheckTimeout)
@@ -1030,13 +1199,14 @@ func (m *MultiClusterClient) WarmupHealthCache() {
 		return
 	}
 
+	m.prioritizeFocused(clusters)
 	log.Printf("[Warmup] probing %d clusters for reachability...", len(clusters))
 	var wg sync.WaitGroup
 	for _, cl := range clusters {
 		wg.Add(1)
 		go func(name, ctxName string) {
 			defer wg.Done()
-			probeCtx, probeCancel := context.WithTimeout(ctx, clusterProbeTimeout)
+			probeCtx, probeCancel := context.WithTimeout(ctx, m.OperationTimeout(ctxName, OpHealth, clusterProbeTimeout))
 			defer probeCancel()
 
 			client, clientErr := m.GetClient(ctxName)
@@ -1056,7 +1226,11 @@ func (m *MultiClusterClient) WarmupHealthCache() {
 				return
 			}
 
+			probeStart := time.Now()
 			_, listErr := client.CoreV1().Namespaces().List(probeCtx, metav1.ListOptions{Limit: 1})
+			if listErr == nil {
+				m.recordProbeLatency(ctxName, time.Since(probeStart))
+			}
 			if listErr != nil {
 				m.mu.Lock()
 				m.healthCache[ctxName] = &ClusterHealth{
@@ -1121,24 +1295,38 @@ func (m *MultiClusterClient) HealthyClusters(ctx context.Context) (healthy []Clu
 			healthy = append(healthy, cl)
 		}
 	}
+	m.prioritizeFocusedLocked(healthy)
 	return healthy, offline, nil
 }
 
 // MarkSlow flags a cluster as slow (recently timed out or took >5s).
 // Slow clusters receive a reduced timeout for slowClusterTTL.
 func (m *MultiClusterClient) MarkSlow(clusterName string) {
+	now := time.Now()
 	m.mu.Lock()
-	m.slowClusters[clusterName] = time.Now()
+	m.slowClusters[clusterName] = now
+	shared := m.sharedCache
 	m.mu.Unlock()
+	SharedSetJSON(shared, "slow:"+clusterName, now, slowClusterTTL)
 	log.Printf("[Slow] cluster %s marked as slow (reduced timeout for %v)", clusterName, slowClusterTTL)
 }
 
-// IsSlow returns true if the cluster was recently marked as slow.
+// IsSlow returns true if the cluster was recently marked as slow, by this
+// replica or (with a shared cache) by another one.
 func (m *MultiClusterClient) IsSlow(clusterName string) bool {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-	if t, ok := m.slowClusters[clusterName]; ok {
-		return time.Since(t) < slowClusterTTL
+	t, ok := m.slowClusters[clusterName]
+	shared := m.sharedCache
+	m.mu.RUnlock()
+	if ok && time.Since(t) < slowClusterTTL {
+		return true
+	}
+	var sharedMarked time.Time
+	if SharedGetJSON(shared, "slow:"+clusterName, &sharedMarked) && time.Since(sharedMarked) < slowClusterTTL {
+		m.mu.Lock()
+		m.slowClusters[clusterName] = sharedMarked
+		m.mu.Unlock()
+		return true
 	}
 	return false
 }
@@ -1164,7 +1352,6 @@ func (m *MultiClusterClient) GetClient(contextName string) (kubernetes.Interface
 		m.mu.RUnlock()
 		return client, nil
 	}
-	inClusterConfig := m.inClusterConfig
 	m.mu.RUnlock()
 
 	m.mu.Lock()
@@ -1175,34 +1362,17 @@ func (m *MultiClusterClient) GetClient(contextName string) (kubernetes.Interface
 		return client, nil
 	}
 
-	var config *rest.Config
-	var err error
-
-	// Handle in-cluster context specially — accept both "in-cluster" and the detected name
-	isInCluster := inClusterConfig != nil && (contextName == "in-cluster" || contextName == m.inClusterName)
-	if isInCluster {
-		config = rest.CopyConfig(inClusterConfig)
-	} else {
-		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			&clientcmd.ClientConfigLoadingRules{ExplicitPath: m.kubeconfig},
-			&clientcmd.ConfigOverrides{CurrentContext: contextName},
-		).ClientConfig()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get config for context %s: %w", contextName, err)
-		}
+	config, httpClient, err := m.clientConfigLocked(contextName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Set reasonable timeouts — large OpenShift clusters (18+ nodes) can return
-	// 800KB+ node payloads that take >10s over higher-latency links
-	config.Timeout = k8sClientTimeout
-
-	client, err := kubernetes.NewForConfig(config)
+	client, err := kubernetes.NewForConfigAndClient(protobufConfig(config), httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client for context %s: %w", contextName, err)
 	}
 
 	m.clients[contextName] = client
-	m.configs[contextName] = config
 	return client, nil
 }
 
@@ -1238,27 +1408,12 @@ func (m *MultiClusterClient) GetDynamicClient(contextName string) (dynamic.Inter
 		return client, nil
 	}
 
-	// Get or create config
-	config, ok := m.configs[contextName]
-	if !ok {
-		var err error
-		isInCluster := m.inClusterConfig != nil && (contextName == "in-cluster" || contextName == m.inClusterName)
-		if isInCluster {
-			config = rest.CopyConfig(m.inClusterConfig)
-		} else {
-			config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-				&clientcmd.ClientConfigLoadingRules{ExplicitPath: m.kubeconfig},
-				&clientcmd.ConfigOverrides{CurrentContext: contextName},
-			).ClientConfig()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get config for context %s: %w", contextName, err)
-			}
-		}
-		config.Timeout = k8sClientTimeout
-		m.configs[contextName] = config
+	config, httpClient, err := m.clientConfigLocked(contextName)
+	if err != nil {
+		return nil, err
 	}
 
-	client, err := dynamic.NewForConfig(config)
+	client, err := dynamic.NewForConfigAndClient(config, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create dynamic client for context %s: %w", contextName, err)
 	}
@@ -1316,18 +1471,36 @@ func classifyError(errMsg string) string {
 
 // GetClusterHealth returns health status for a cluster
 func (m *MultiClusterClient) GetClusterHealth(ctx context.Context, contextName string) (*ClusterHealth, error) {
-	// Check cache — also save previous cached data for fallback on partial failures
+	// Check cache — also save previous cached data for fallback on partial failures.
+	// An impersonated identity sees different counts, so it never shares the cache.
+	// The background refresher probes even while the cached entry is fresh.
+	_, impersonated := ImpersonationFrom(ctx)
+	refresh, _ := ctx.Value(refreshHealthKey{}).(bool)
 	var prevCached *ClusterHealth
 	m.mu.RLock()
-	if health, ok := m.healthCache[contextName]; ok {
-		if time.Since(m.cacheTime[contextName]) < m.cacheTTL {
+	if health, ok := m.healthCache[contextName]; ok && !impersonated {
+		if !refresh && m.healthFreshLocked(contextName, time.Now()) {
 			m.mu.RUnlock()
 			return health, nil
 		}
 		prevCached = health
 	}
+	shared := m.sharedCache
+	healthTTL := m.healthTTLLocked(contextName)
 	m.mu.RUnlock()
 
+	// Another replica may have checked this cluster recently
+	var sharedHealth ClusterHealth
+	if !impersonated && !refresh && SharedGetJSON(shared, "health:"+contextName, &sharedHealth) {
+		if checked, err := time.Parse(time.RFC3339, sharedHealth.CheckedAt); err == nil && time.Since(checked) < healthTTL {
+			m.mu.Lock()
+			m.healthCache[contextName] = &sharedHealth
+			m.cacheTime[contextName] = checked
+			m.mu.Unlock()
+			return &sharedHealth, nil
+		}
+	}
+
 	now := time.Now().Format(time.RFC3339)
 
 	client, err := m.GetClient(contextName)
@@ -1355,6 +1528,7 @@ func (m *MultiClusterClient) GetClusterHealth(ctx context.Context, contextName s
 	// Fetch nodes, pods, and PVCs in parallel to avoid sequential timeout accumulation.
 	// Large clusters (e.g. 18 nodes, 972 pods) can take 10-20s per call sequentially,
 	// exceeding the context deadline. Parallel fetches reduce wall-clock time to max(individual).
+	// Nodes and pods come from the snapshot shared with the node and GPU queries.
 	var (
 		nodes    *corev1.NodeList
 		pods     *corev1.PodList
@@ -1362,22 +1536,29 @@ func (m *MultiClusterClient) GetClusterHealth(ctx context.Context, contextName s
 		nodesErr error
 		podsErr  error
 		pvcsErr  error
+		webhooks []string
+		cp       *ControlPlaneHealth
 		wg       sync.WaitGroup
 	)
 
 	wg.Add(3)
 	go func() {
 		defer wg.Done()
-		nodes, nodesErr = client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		pvcs, pvcsErr = client.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
 	}()
 	go func() {
 		defer wg.Done()
-		pods, podsErr = client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+		webhooks = webhookHealthIssues(ctx, client, contextName)
 	}()
 	go func() {
 		defer wg.Done()
-		pvcs, pvcsErr = client.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+		cp, _ = m.GetControlPlaneHealth(ctx, contextName)
 	}()
+	if snap, err := m.getNodePodSnapshot(ctx, contextName); err != nil {
+		nodesErr, podsErr = err, err
+	} else {
+		nodes, pods, nodesErr, podsErr = snap.nodes, snap.pods, snap.nodesErr, snap.podsErr
+	}
 	wg.Wait()
 
 	// Process nodes - determines reachability
@@ -1433,6 +1614,7 @@ func (m *MultiClusterClient) GetClusterHealth(ctx context.Context, contextName s
 		health.MemoryGB = float64(totalMemory) / (1024 * 1024 * 1024)
 		health.StorageBytes = totalStorage
 		health.StorageGB = float64(totalStorage) / (1024 * 1024 * 1024)
+		health.Platforms = platformCapacity(nodes.Items)
 		if health.ReadyNodes < health.NodeCount {
 			health.Issues = append(health.Issues, fmt.Sprintf("%d/%d nodes not ready", health.NodeCount-health.ReadyNodes, health.NodeCount))
 		}
@@ -1471,9 +1653,16 @@ func (m *MultiClusterClient) GetClusterHealth(ctx context.Context, contextName s
 		health.CpuRequestsCores = float64(totalCPURequests) / 1000.0
 		health.MemoryRequestsBytes = totalMemoryRequests
 		health.MemoryRequestsGB = float64(totalMemoryRequests) / (1024 * 1024 * 1024)
+		if nodes != nil {
+			health.Pressure = computeCapacityPressure(nodes.Items, pods.Items, time.Now())
+			if issue := capacityPressureIssue(health.Pressure); issue != "" {
+				health.Issues = append(health.Issues, issue)
+			}
+		}
 	} else if prevCached != nil {
 		// Pod listing timed out — preserve previous cached pod data instead of showing 0
 		health.PodCount = prevCached.PodCount
+		health.Pressure = prevCached.Pressure
 		health.CpuRequestsMillicores = prevCached.CpuRequestsMillicores
 		health.CpuRequestsCores = prevCached.CpuRequestsCores
 		health.MemoryRequestsBytes = prevCached.MemoryRequestsBytes
@@ -1493,13 +1682,21 @@ func (m *MultiClusterClient) GetClusterHealth(ctx context.Context, contextName s
 		health.PVCBoundCount = prevCached.PVCBoundCount
 	}
 
+	// Broken webhooks with failurePolicy Fail silently stall deployments
+	health.Issues = append(health.Issues, webhooks...)
+	if cp != nil {
+		health.ControlPlane = cp
+		health.Issues = append(health.Issues, cp.Issues...)
+	}
+
 	// Only cache successful results — don't cache failures (timeout, context canceled)
 	// so the next request retries immediately instead of serving stale errors
-	if health.Reachable {
+	if health.Reachable && !impersonated {
 		m.mu.Lock()
 		m.healthCache[contextName] = health
 		m.cacheTime[contextName] = time.Now()
 		m.mu.Unlock()
+		SharedSetJSON(shared, "health:"+contextName, health, m.cacheTTL)
 	}
 
 	return health, nil
@@ -1507,6 +1704,11 @@ func (m *MultiClusterClient) GetClusterHealth(ctx context.Context, contextName s
 
 // GetPods returns pods for a namespace/cluster
 func (m *MultiClusterClient) GetPods(ctx context.Context, contextName, namespace string) ([]PodInfo, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]PodInfo, error) {
+			return m.GetPods(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -1518,80 +1720,90 @@ func (m *MultiClusterClient) GetPods(ctx context.Context, contextName, namespace
 	}
 
 	var result []PodInfo
-	for _, pod := range pods.Items {
-		ready := 0
-		total := len(pod.Spec.Containers)
-		restarts := 0
+	for i := range pods.Items {
+		result = append(result, podInfo(&pods.Items[i], contextName))
+	}
 
-		// Build container status map
-		statusMap := make(map[string]corev1.ContainerStatus)
-		for _, cs := range pod.Status.ContainerStatuses {
-			statusMap[cs.Name] = cs
-			if cs.Ready {
-				ready++
-			}
-			restarts += int(cs.RestartCount)
-		}
+	return result, nil
+}
 
-		// Build container info
-		var containers []ContainerInfo
-		for _, c := range pod.Spec.Containers {
-			ci := ContainerInfo{
-				Name:  c.Name,
-				Image: c.Image,
-			}
-			if cs, ok := statusMap[c.Name]; ok {
-				ci.Ready = cs.Ready
-				if cs.State.Running != nil {
-					ci.State = "running"
-				} else if cs.State.Waiting != nil {
-					ci.State = "waiting"
-					ci.Reason = cs.State.Waiting.Reason
-					ci.Message = cs.State.Waiting.Message
-				} else if cs.State.Terminated != nil {
-					ci.State = "terminated"
-					ci.Reason = cs.State.Terminated.Reason
-					ci.Message = cs.State.Terminated.Message
-				}
+// podInfo summarizes a pod's readiness, restarts and containers
+func podInfo(pod *corev1.Pod, contextName string) PodInfo {
+	ready := 0
+	total := len(pod.Spec.Containers)
+	restarts := 0
+
+	// Build container status map
+	statusMap := make(map[string]corev1.ContainerStatus)
+	for _, cs := range pod.Status.ContainerStatuses {
+		statusMap[cs.Name] = cs
+		if cs.Ready {
+			ready++
+		}
+		restarts += int(cs.RestartCount)
+	}
+
+	// Build container info
+	var containers []ContainerInfo
+	for _, c := range pod.Spec.Containers {
+		ci := ContainerInfo{
+			Name:  c.Name,
+			Image: c.Image,
+		}
+		if cs, ok := statusMap[c.Name]; ok {
+			ci.Ready = cs.Ready
+			if cs.State.Running != nil {
+				ci.State = "running"
+			} else if cs.State.Waiting != nil {
+				ci.State = "waiting"
+				ci.Reason = cs.State.Waiting.Reason
+				ci.Message = cs.State.Waiting.Message
+			} else if cs.State.Terminated != nil {
+				ci.State = "terminated"
+				ci.Reason = cs.State.Terminated.Reason
+				ci.Message = cs.State.Terminated.Message
 			}
-			// Check for GPU resource requests (nvidia.com/gpu, amd.com/gpu)
-			if c.Resources.Requests != nil {
-				for resourceName, qty := range c.Resources.Requests {
-					if resourceName == "nvidia.com/gpu" || resourceName == "amd.com/gpu" {
-						ci.GPURequested = int(qty.Value())
-					}
+		}
+		// Check for GPU resource requests (nvidia.com/gpu, amd.com/gpu)
+		if c.Resources.Requests != nil {
+			for resourceName, qty := range c.Resources.Requests {
+				if resourceName == "nvidia.com/gpu" || resourceName == "amd.com/gpu" {
+					ci.GPURequested = int(qty.Value())
 				}
 			}
-			if ci.GPURequested == 0 && c.Resources.Limits != nil {
-				for resourceName, qty := range c.Resources.Limits {
-					if resourceName == "nvidia.com/gpu" || resourceName == "amd.com/gpu" {
-						ci.GPURequested = int(qty.Value())
-					}
+		}
+		if ci.GPURequested == 0 && c.Resources.Limits != nil {
+			for resourceName, qty := range c.Resources.Limits {
+				if resourceName == "nvidia.com/gpu" || resourceName == "amd.com/gpu" {
+					ci.GPURequested = int(qty.Value())
 				}
 			}
-			containers = append(containers, ci)
 		}
-
-		result = append(result, PodInfo{
-			Name:        pod.Name,
-			Namespace:   pod.Namespace,
-			Cluster:     contextName,
-			Status:      string(pod.Status.Phase),
-			Ready:       fmt.Sprintf("%d/%d", ready, total),
-			Restarts:    restarts,
-			Age:         formatDuration(time.Since(pod.CreationTimestamp.Time)),
-			Node:        pod.Spec.NodeName,
-			Labels:      pod.Labels,
-			Annotations: pod.Annotations,
-			Containers:  containers,
-		})
+		containers = append(containers, ci)
 	}
 
-	return result, nil
+	return PodInfo{
+		Name:        pod.Name,
+		Namespace:   pod.Namespace,
+		Cluster:     contextName,
+		Status:      string(pod.Status.Phase),
+		Ready:       fmt.Sprintf("%d/%d", ready, total),
+		Restarts:    restarts,
+		Age:         formatDuration(time.Since(pod.CreationTimestamp.Time)),
+		Node:        pod.Spec.NodeName,
+		Labels:      pod.Labels,
+		Annotations: pod.Annotations,
+		Containers:  containers,
+	}
 }
 
 // FindPodIssues returns pods with issues
 func (m *MultiClusterClient) FindPodIssues(ctx context.Context, contextName, namespace string) ([]PodIssue, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]PodIssue, error) {
+			return m.FindPodIssues(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -1601,7 +1813,11 @@ func (m *MultiClusterClient) FindPodIssues(ctx context.Context, contextName, nam
 	if err != nil {
 		return nil, err
 	}
+	return collectPodIssues(pods.Items, contextName, time.Now()), nil
+}
 
+// collectPodIssues returns the pods that are failing, pending too long or stuck
+func collectPodIssues(pods []corev1.Pod, contextName string, now time.Time) []PodIssue {
 	// Waiting reasons that indicate a problem
 	problemWaitingReasons := map[string]bool{
 		"CrashLoopBackOff":           true,
@@ -1614,10 +1830,8 @@ func (m *MultiClusterClient) FindPodIssues(ctx context.Context, contextName, nam
 		"PostStartHookError":         true,
 	}
 
-	now := time.Now()
-
 	var issues []PodIssue
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		// Skip completed/succeeded pods (e.g. finished Jobs)
 		if pod.Status.Phase == corev1.PodSucceeded {
 			continue
@@ -1737,11 +1951,17 @@ func (m *MultiClusterClient) FindPodIssues(ctx context.Context, contextName, nam
 		}
 	}
 
-	return issues, nil
+	return issues
 }
 
 // GetEvents returns events from a cluster
 func (m *MultiClusterClient) GetEvents(ctx context.Context, contextName, namespace string, limit int) ([]Event, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		events, err := listScoped(namespaces, err, func(ns string) ([]Event, error) {
+			return m.GetEvents(ctx, contextName, ns, limit)
+		})
+		return mergeScopedEvents(events, err, limit)
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -1759,28 +1979,13 @@ func (m *MultiClusterClient) GetEvents(ctx context.Context, contextName, namespa
 		return events.Items[i].LastTimestamp.After(events.Items[j].LastTimestamp.Time)
 	})
 
+	owners := m.ownerResolverFor(contextName, client)
 	var result []Event
 	for i, event := range events.Items {
 		if limit > 0 && i >= limit {
 			break
 		}
-		e := Event{
-			Type:      event.Type,
-			Reason:    event.Reason,
-			Message:   event.Message,
-			Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
-			Namespace: event.Namespace,
-			Cluster:   contextName,
-			Count:     event.Count,
-			Age:       formatDuration(time.Since(event.LastTimestamp.Time)),
-		}
-		if !event.FirstTimestamp.IsZero() {
-			e.FirstSeen = event.FirstTimestamp.Time.Format(time.RFC3339)
-		}
-		if !event.LastTimestamp.IsZero() {
-			e.LastSeen = event.LastTimestamp.Time.Format(time.RFC3339)
-		}
-		result = append(result, e)
+		result = append(result, eventSummary(ctx, event, contextName, owners))
 	}
 
 	return result, nil
@@ -1788,6 +1993,12 @@ func (m *MultiClusterClient) GetEvents(ctx context.Context, contextName, namespa
 
 // GetWarningEvents returns warning events from a cluster
 func (m *MultiClusterClient) GetWarningEvents(ctx context.Context, contextName, namespace string, limit int) ([]Event, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		events, err := listScoped(namespaces, err, func(ns string) ([]Event, error) {
+			return m.GetWarningEvents(ctx, contextName, ns, limit)
+		})
+		return mergeScopedEvents(events, err, limit)
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -1805,28 +2016,13 @@ func (m *MultiClusterClient) GetWarningEvents(ctx context.Context, contextName,
 		return events.Items[i].LastTimestamp.After(events.Items[j].LastTimestamp.Time)
 	})
 
+	owners := m.ownerResolverFor(contextName, client)
 	var result []Event
 	for i, event := range events.Items {
 		if limit > 0 && i >= limit {
 			break
 		}
-		e := Event{
-			Type:      event.Type,
-			Reason:    event.Reason,
-			Message:   event.Message,
-			Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
-			Namespace: event.Namespace,
-			Cluster:   contextName,
-			Count:     event.Count,
-			Age:       formatDuration(time.Since(event.LastTimestamp.Time)),
-		}
-		if !event.FirstTimestamp.IsZero() {
-			e.FirstSeen = event.FirstTimestamp.Time.Format(time.RFC3339)
-		}
-		if !event.LastTimestamp.IsZero() {
-			e.LastSeen = event.LastTimestamp.Time.Format(time.RFC3339)
-		}
-		result = append(result, e)
+		result = append(result, eventSummary(ctx, event, contextName, owners))
 	}
 
 	return result, nil
@@ -1834,19 +2030,18 @@ func (m *MultiClusterClient) GetWarningEvents(ctx context.Context, contextName,
 
 // GetGPUNodes returns nodes with GPU resources
 func (m *MultiClusterClient) GetGPUNodes(ctx context.Context, contextName string) ([]GPUNode, error) {
-	client, err := m.GetClient(contextName)
+	snap, err := m.getNodePodSnapshot(ctx, contextName)
 	if err != nil {
 		return nil, err
 	}
-
-	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+	if snap.nodesErr != nil {
+		return nil, snap.nodesErr
 	}
+	nodes := snap.nodes
 
-	// Fetch all pods once upfront to calculate accelerator allocations per node
+	// Pods are listed once per snapshot to calculate accelerator allocations per node
 	// This is much faster than querying pods per-node for large clusters
-	allPods, _ := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	allPods := snap.pods
 	// Track allocations by node and accelerator type
 	gpuAllocationByNode := make(map[string]int) // GPU allocations
 	tpuAllocationByNode := make(map[string]int) // TPU allocations
@@ -2075,7 +2270,7 @@ func (m *MultiClusterClient) GetGPUNodes(ctx context.Context, contextName string
 			allocated = xpuAllocationByNode[node.Name]
 		}
 
-		gpuNodes = append(gpuNodes, GPUNode{
+		gpuNode := GPUNode{
 			Name:               node.Name,
 			Cluster:            contextName,
 			GPUType:            deviceType,
@@ -2089,7 +2284,11 @@ func (m *MultiClusterClient) GetGPUNodes(ctx context.Context, contextName string
 			MIGCapable:         migCapable,
 			MIGStrategy:        migStrategy,
 			Manufacturer:       manufacturer,
-		})
+		}
+		if manufacturer == "AMD" {
+			applyAMDGPULabels(node.Labels, &gpuNode)
+		}
+		gpuNodes = append(gpuNodes, gpuNode)
 	}
 
 	return gpuNodes, nil
@@ -2100,6 +2299,13 @@ var gpuOperatorNamespaces = []string{
 	"nvidia-gpu-operator",
 	"gpu-operator",
 	"nvidia-device-plugin",
+	"kube-amd-gpu",
+	"amd-gpu-operator",
+	"habana-ai-operator",
+	"habana-system",
+	"inteldeviceplugins-system",
+	"intel-device-plugins",
+	"node-feature-discovery",
 	"kube-system",
 }
 
@@ -2120,28 +2326,46 @@ func (m *MultiClusterClient) GetGPUNodeHealth(ctx context.Context, contextName s
 		return nil, nil
 	}
 
-	// 2. Get node objects for condition checks
-	nodeList, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	// 2. Get node objects for condition checks (same snapshot GetGPUNodes used)
+	snap, err := m.getNodePodSnapshot(ctx, contextName)
+	if err == nil {
+		err = snap.nodesErr
+	}
 	if err != nil {
 		return nil, fmt.Errorf("listing nodes: %w", err)
 	}
+	nodeList := snap.nodes
 	nodeMap := make(map[string]corev1.Node, len(nodeList.Items))
 	for _, n := range nodeList.Items {
 		nodeMap[n.Name] = n
 	}
 
-	// 3. Find GPU operator pods across known namespaces
+	// 3. Find GPU operator pods across known namespaces in the snapshot's pods,
+	// listing them per namespace only when the cluster-wide listing failed
+	podSets := m.gpuOperatorPodSets()
 	var operatorPods []corev1.Pod
-	for _, ns := range gpuOperatorNamespaces {
-		pods, listErr := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
-		if listErr != nil {
-			continue // namespace may not exist
+	if snap.podsErr == nil && snap.pods != nil {
+		operatorNamespaces := make(map[string]bool)
+		for _, ns := range gpuOperatorCheckNamespaces(podSets) {
+			operatorNamespaces[ns] = true
+		}
+		for _, pod := range snap.pods.Items {
+			if operatorNamespaces[pod.Namespace] {
+				operatorPods = append(operatorPods, pod)
+			}
+		}
+	} else {
+		for _, ns := range gpuOperatorCheckNamespaces(podSets) {
+			pods, listErr := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+			if listErr != nil {
+				continue // namespace may not exist
+			}
+			operatorPods = append(operatorPods, pods.Items...)
 		}
-		operatorPods = append(operatorPods, pods.Items...)
 	}
 
 	// 4. Find non-running pods for stuck pod detection (exclude Succeeded/Running)
-	allPods, _ := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	allPods := snap.pods
 
 	// 5. Get warning events from the last hour for GPU reset detection
 	oneHourAgo := time.Now().Add(-1 * time.Hour)
@@ -2189,25 +2413,13 @@ func (m *MultiClusterClient) GetGPUNodeHealth(ctx context.Context, contextName s
 			checks = append(checks, GPUNodeHealthCheck{Name: "scheduling", Passed: true})
 		}
 
-		// Check 3: gpu-feature-discovery pod
-		gfdCheck := checkOperatorPod(operatorPods, gpuNode.Name, "gpu-feature-discovery")
-		checks = append(checks, gfdCheck)
-		if !gfdCheck.Passed {
-			issues = append(issues, "gpu-feature-discovery: "+gfdCheck.Message)
-		}
-
-		// Check 4: nvidia-device-plugin pod
-		dpCheck := checkOperatorPod(operatorPods, gpuNode.Name, "nvidia-device-plugin")
-		checks = append(checks, dpCheck)
-		if !dpCheck.Passed {
-			issues = append(issues, "nvidia-device-plugin: "+dpCheck.Message)
-		}
-
-		// Check 5: dcgm-exporter pod
-		dcgmCheck := checkOperatorPod(operatorPods, gpuNode.Name, "dcgm-exporter")
-		checks = append(checks, dcgmCheck)
-		if !dcgmCheck.Passed {
-			issues = append(issues, "dcgm-exporter: "+dcgmCheck.Message)
+		// Checks 3+: the vendor's operator pods (device plugin, feature discovery, exporters)
+		operatorChecks := runOperatorPodChecks(operatorPods, gpuNode.Name, podSets[gpuOperatorStack(gpuNode)])
+		for _, oc := range operatorChecks {
+			checks = append(checks, oc)
+			if !oc.Passed {
+				issues = append(issues, oc.Name+": "+oc.Message)
+			}
 		}
 
 		// Check 6: Stuck pods on this node
@@ -2275,12 +2487,13 @@ func (m *MultiClusterClient) GetGPUNodeHealth(ctx context.Context, contextName s
 	return results, nil
 }
 
-// checkOperatorPod checks if a specific GPU operator pod is running on a node.
-// It searches by pod name prefix and node name match (for DaemonSet pods).
-func checkOperatorPod(pods []corev1.Pod, nodeName, podPrefix string) GPUNodeHealthCheck {
+// checkOperatorPodMatching checks if a specific GPU operator pod is running on a node.
+// It searches by pod name match and node name match (for DaemonSet pods);
+// the result is reported under checkName.
+func checkOperatorPodMatching(pods []corev1.Pod, nodeName, checkName string, match func(string) bool) GPUNodeHealthCheck {
 	for i := range pods {
 		pod := &pods[i]
-		if !strings.Contains(pod.Name, podPrefix) {
+		if !match(pod.Name) {
 			continue
 		}
 		// DaemonSet pods run on specific nodes
@@ -2292,10 +2505,10 @@ func checkOperatorPod(pods []corev1.Pod, nodeName, podPrefix string) GPUNodeHeal
 			for _, cs := range pod.Status.ContainerStatuses {
 				if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
 					msg := fmt.Sprintf("CrashLoopBackOff (%d restarts)", cs.RestartCount)
-					return GPUNodeHealthCheck{Name: podPrefix, Passed: false, Message: msg}
+					return GPUNodeHealthCheck{Name: checkName, Passed: false, Message: msg}
 				}
 			}
-			return GPUNodeHealthCheck{Name: podPrefix, Passed: true}
+			return GPUNodeHealthCheck{Name: checkName, Passed: true}
 		}
 		// Not running
 		reason := string(pod.Status.Phase)
@@ -2308,10 +2521,10 @@ func checkOperatorPod(pods []corev1.Pod, nodeName, podPrefix string) GPUNodeHeal
 				break
 			}
 		}
-		return GPUNodeHealthCheck{Name: podPrefix, Passed: false, Message: reason}
+		return GPUNodeHealthCheck{Name: checkName, Passed: false, Message: reason}
 	}
 	// Pod not found on this node — could be normal if operator not installed
-	return GPUNodeHealthCheck{Name: podPrefix, Passed: true, Message: "not found (operator may not be installed)"}
+	return GPUNodeHealthCheck{Name: checkName, Passed: true, Message: "not found (operator may not be installed)"}
 }
 
 // isStuckPod returns true if a pod appears stuck (ContainerStatusUnknown, long-Terminating, etc.)
@@ -3032,15 +3245,14 @@ func (m *MultiClusterClient) canManageCronJobs(ctx context.Context, client kuber
 
 // GetNodes returns detailed information about all nodes in a cluster
 func (m *MultiClusterClient) GetNodes(ctx context.Context, contextName string) ([]NodeInfo, error) {
-	client, err := m.GetClient(contextName)
+	snap, err := m.getNodePodSnapshot(ctx, contextName)
 	if err != nil {
 		return nil, err
 	}
-
-	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+	if snap.nodesErr != nil {
+		return nil, snap.nodesErr
 	}
+	nodes := snap.nodes
 
 	var nodeInfos []NodeInfo
 	for _, node := range nodes.Items {
@@ -3104,6 +3316,9 @@ func (m *MultiClusterClient) GetNodes(ctx context.Context, contextName string) (
 		} else if gpu, ok := node.Status.Allocatable["amd.com/gpu"]; ok {
 			info.GPUCount = int(gpu.Value())
 			info.GPUType = "AMD GPU"
+			if product := amdGPULabel(node.Labels, "product-name"); product != "" {
+				info.GPUType = strings.ReplaceAll(product, "_", " ")
+			}
 		} else if gpu, ok := node.Status.Allocatable["gpu.intel.com/i915"]; ok {
 			info.GPUCount = int(gpu.Value())
 			info.GPUType = "Intel GPU"
@@ -3211,6 +3426,11 @@ func (m *MultiClusterClient) GetNodes(ctx context.Context, contextName string) (
 
 // FindDeploymentIssues returns deployments with issues
 func (m *MultiClusterClient) FindDeploymentIssues(ctx context.Context, contextName, namespace string) ([]DeploymentIssue, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]DeploymentIssue, error) {
+			return m.FindDeploymentIssues(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3265,6 +3485,11 @@ func (m *MultiClusterClient) FindDeploymentIssues(ctx context.Context, contextNa
 
 // GetDeployments returns all deployments with rollout status
 func (m *MultiClusterClient) GetDeployments(ctx context.Context, contextName, namespace string) ([]Deployment, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]Deployment, error) {
+			return m.GetDeployments(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3274,6 +3499,8 @@ func (m *MultiClusterClient) GetDeployments(ctx context.Context, contextName, na
 	if err != nil {
 		return nil, err
 	}
+	// PDBs are best effort; deployments are listed even when PDBs can't be read
+	pdbs, _ := m.listPDBs(ctx, contextName, namespace)
 
 	var result []Deployment
 	for _, deploy := range deployments.Items {
@@ -3321,7 +3548,7 @@ func (m *MultiClusterClient) GetDeployments(ctx context.Context, contextName, na
 			}
 		}
 
-		result = append(result, Deployment{
+		d := Deployment{
 			Name:              deploy.Name,
 			Namespace:         deploy.Namespace,
 			Cluster:           contextName,
@@ -3335,7 +3562,9 @@ func (m *MultiClusterClient) GetDeployments(ctx context.Context, contextName, na
 			Age:               age,
 			Labels:            deploy.Labels,
 			Annotations:       deploy.Annotations,
-		})
+		}
+		d.PDBs, d.DisruptionsAllowed = deploymentPDBs(pdbs, deploy.Namespace, deploy.Spec.Template.Labels)
+		result = append(result, d)
 	}
 
 	return result, nil
@@ -3343,6 +3572,11 @@ func (m *MultiClusterClient) GetDeployments(ctx context.Context, contextName, na
 
 // GetServices returns all services in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetServices(ctx context.Context, contextName, namespace string) ([]Service, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]Service, error) {
+			return m.GetServices(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3400,6 +3634,11 @@ func (m *MultiClusterClient) GetServices(ctx context.Context, contextName, names
 
 // GetJobs returns all jobs in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetJobs(ctx context.Context, contextName, namespace string) ([]Job, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]Job, error) {
+			return m.GetJobs(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3464,6 +3703,11 @@ func (m *MultiClusterClient) GetJobs(ctx context.Context, contextName, namespace
 
 // GetHPAs returns all HPAs in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetHPAs(ctx context.Context, contextName, namespace string) ([]HPA, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]HPA, error) {
+			return m.GetHPAs(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3475,58 +3719,68 @@ func (m *MultiClusterClient) GetHPAs(ctx context.Context, contextName, namespace
 	}
 
 	var result []HPA
-	for _, hpa := range hpas.Items {
-		// Get target reference
-		reference := fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name)
-
-		// Get min/max replicas
-		minReplicas := int32(1)
-		if hpa.Spec.MinReplicas != nil {
-			minReplicas = *hpa.Spec.MinReplicas
-		}
-
-		// Get target/current CPU
-		targetCPU := ""
-		currentCPU := ""
-		for _, metric := range hpa.Spec.Metrics {
-			if metric.Type == "Resource" && metric.Resource != nil && metric.Resource.Name == "cpu" {
-				if metric.Resource.Target.AverageUtilization != nil {
-					targetCPU = fmt.Sprintf("%d%%", *metric.Resource.Target.AverageUtilization)
-				}
+	for i := range hpas.Items {
+		result = append(result, hpaSummary(&hpas.Items[i], contextName))
+	}
+
+	return result, nil
+}
+
+// hpaSummary converts an HPA to its list view
+func hpaSummary(hpa *autoscalingv2.HorizontalPodAutoscaler, contextName string) HPA {
+	// Get target reference
+	reference := fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name)
+
+	// Get min/max replicas
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+
+	// Get target/current CPU
+	targetCPU := ""
+	currentCPU := ""
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type == "Resource" && metric.Resource != nil && metric.Resource.Name == "cpu" {
+			if metric.Resource.Target.AverageUtilization != nil {
+				targetCPU = fmt.Sprintf("%d%%", *metric.Resource.Target.AverageUtilization)
 			}
 		}
-		for _, condition := range hpa.Status.CurrentMetrics {
-			if condition.Type == "Resource" && condition.Resource != nil && condition.Resource.Name == "cpu" {
-				if condition.Resource.Current.AverageUtilization != nil {
-					currentCPU = fmt.Sprintf("%d%%", *condition.Resource.Current.AverageUtilization)
-				}
+	}
+	for _, condition := range hpa.Status.CurrentMetrics {
+		if condition.Type == "Resource" && condition.Resource != nil && condition.Resource.Name == "cpu" {
+			if condition.Resource.Current.AverageUtilization != nil {
+				currentCPU = fmt.Sprintf("%d%%", *condition.Resource.Current.AverageUtilization)
 			}
 		}
-
-		// Calculate age
-		age := formatAge(hpa.CreationTimestamp.Time)
-
-		result = append(result, HPA{
-			Name:            hpa.Name,
-			Namespace:       hpa.Namespace,
-			Cluster:         contextName,
-			Reference:       reference,
-			MinReplicas:     minReplicas,
-			MaxReplicas:     hpa.Spec.MaxReplicas,
-			CurrentReplicas: hpa.Status.CurrentReplicas,
-			TargetCPU:       targetCPU,
-			CurrentCPU:      currentCPU,
-			Age:             age,
-			Labels:          hpa.Labels,
-			Annotations:     hpa.Annotations,
-		})
 	}
 
-	return result, nil
+	// Calculate age
+	age := formatAge(hpa.CreationTimestamp.Time)
+
+	return HPA{
+		Name:            hpa.Name,
+		Namespace:       hpa.Namespace,
+		Cluster:         contextName,
+		Reference:       reference,
+		MinReplicas:     minReplicas,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		TargetCPU:       targetCPU,
+		CurrentCPU:      currentCPU,
+		Age:             age,
+		Labels:          hpa.Labels,
+		Annotations:     hpa.Annotations,
+	}
 }
 
 // GetConfigMaps returns all ConfigMaps in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetConfigMaps(ctx context.Context, contextName, namespace string) ([]ConfigMap, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]ConfigMap, error) {
+			return m.GetConfigMaps(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3558,6 +3812,11 @@ func (m *MultiClusterClient) GetConfigMaps(ctx context.Context, contextName, nam
 
 // GetSecrets returns all Secrets in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetSecrets(ctx context.Context, contextName, namespace string) ([]Secret, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]Secret, error) {
+			return m.GetSecrets(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3590,6 +3849,11 @@ func (m *MultiClusterClient) GetSecrets(ctx context.Context, contextName, namesp
 
 // GetServiceAccounts returns ServiceAccounts from a cluster
 func (m *MultiClusterClient) GetServiceAccounts(ctx context.Context, contextName, namespace string) ([]ServiceAccount, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]ServiceAccount, error) {
+			return m.GetServiceAccounts(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3634,6 +3898,11 @@ func (m *MultiClusterClient) GetServiceAccounts(ctx context.Context, contextName
 
 // GetPVCs returns all PersistentVolumeClaims in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetPVCs(ctx context.Context, contextName, namespace string) ([]PVC, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]PVC, error) {
+			return m.GetPVCs(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3747,6 +4016,11 @@ func (m *MultiClusterClient) GetPVs(ctx context.Context, contextName string) ([]
 
 // GetReplicaSets returns all ReplicaSets in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetReplicaSets(ctx context.Context, contextName, namespace string) ([]ReplicaSet, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]ReplicaSet, error) {
+			return m.GetReplicaSets(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3786,6 +4060,11 @@ func (m *MultiClusterClient) GetReplicaSets(ctx context.Context, contextName, na
 
 // GetStatefulSets returns all StatefulSets in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetStatefulSets(ctx context.Context, contextName, namespace string) ([]StatefulSet, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]StatefulSet, error) {
+			return m.GetStatefulSets(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3831,6 +4110,11 @@ func (m *MultiClusterClient) GetStatefulSets(ctx context.Context, contextName, n
 
 // GetDaemonSets returns all DaemonSets in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetDaemonSets(ctx context.Context, contextName, namespace string) ([]DaemonSet, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]DaemonSet, error) {
+			return m.GetDaemonSets(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3868,6 +4152,11 @@ func (m *MultiClusterClient) GetDaemonSets(ctx context.Context, contextName, nam
 
 // GetCronJobs returns all CronJobs in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetCronJobs(ctx context.Context, contextName, namespace string) ([]CronJob, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]CronJob, error) {
+			return m.GetCronJobs(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3878,6 +4167,13 @@ func (m *MultiClusterClient) GetCronJobs(ctx context.Context, contextName, names
 		return nil, err
 	}
 
+	// Job history is best effort; CronJobs are listed even when Jobs can't be read
+	runs := map[types.UID][]CronJobRun{}
+	if jobList, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		runs = cronJobRuns(jobList.Items)
+	}
+
+	now := time.Now()
 	var result []CronJob
 	for _, cj := range cronList.Items {
 		lastSchedule := ""
@@ -3888,7 +4184,7 @@ func (m *MultiClusterClient) GetCronJobs(ctx context.Context, contextName, names
 		if cj.Spec.Suspend != nil {
 			suspend = *cj.Spec.Suspend
 		}
-		result = append(result, CronJob{
+		cronJob := CronJob{
 			Name:         cj.Name,
 			Namespace:    cj.Namespace,
 			Cluster:      contextName,
@@ -3898,7 +4194,10 @@ func (m *MultiClusterClient) GetCronJobs(ctx context.Context, contextName, names
 			LastSchedule: lastSchedule,
 			Age:          formatAge(cj.CreationTimestamp.Time),
 			Labels:       cj.Labels,
-		})
+			RecentRuns:   runs[cj.UID],
+		}
+		analyzeCronSchedule(&cronJob, &cj, now)
+		result = append(result, cronJob)
 	}
 
 	return result, nil
@@ -3906,6 +4205,11 @@ func (m *MultiClusterClient) GetCronJobs(ctx context.Context, contextName, names
 
 // GetIngresses returns all Ingresses in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetIngresses(ctx context.Context, contextName, namespace string) ([]Ingress, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]Ingress, error) {
+			return m.GetIngresses(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3954,6 +4258,11 @@ func (m *MultiClusterClient) GetIngresses(ctx context.Context, contextName, name
 
 // GetNetworkPolicies returns all NetworkPolicies in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetNetworkPolicies(ctx context.Context, contextName, namespace string) ([]NetworkPolicy, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]NetworkPolicy, error) {
+			return m.GetNetworkPolicies(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -3996,6 +4305,11 @@ func (m *MultiClusterClient) GetNetworkPolicies(ctx context.Context, contextName
 
 // GetResourceQuotas returns all ResourceQuotas in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetResourceQuotas(ctx context.Context, contextName, namespace string) ([]ResourceQuota, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]ResourceQuota, error) {
+			return m.GetResourceQuotas(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -4038,6 +4352,11 @@ func (m *MultiClusterClient) GetResourceQuotas(ctx context.Context, contextName,
 
 // GetLimitRanges returns all LimitRanges in a namespace or all namespaces if namespace is empty
 func (m *MultiClusterClient) GetLimitRanges(ctx context.Context, contextName, namespace string) ([]LimitRange, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]LimitRange, error) {
+			return m.GetLimitRanges(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -4257,26 +4576,11 @@ func (m *MultiClusterClient) EnsureNamespaceExists(ctx context.Context, contextN
 
 // GetPodLogs returns logs from a pod
 func (m *MultiClusterClient) GetPodLogs(ctx context.Context, contextName, namespace, podName, container string, tailLines int64) (string, error) {
-	client, err := m.GetClient(contextName)
-	if err != nil {
-		return "", err
-	}
-
-	opts := &corev1.PodLogOptions{}
-	if tailLines > 0 {
-		opts.TailLines = &tailLines
-	}
-	if container != "" {
-		opts.Container = container
-	}
-
-	req := client.CoreV1().Pods(namespace).GetLogs(podName, opts)
-	logs, err := req.DoRaw(ctx)
+	result, err := m.GetPodLogsWithOptions(ctx, contextName, namespace, podName, PodLogOptions{Container: container, TailLines: tailLines})
 	if err != nil {
 		return "", err
 	}
-
-	return string(logs), nil
+	return result.Logs, nil
 }
 
 // formatAge formats a time.Time as a human-readable age string
@@ -4314,6 +4618,8 @@ func (m *MultiClusterClient) GetAllClusterHealth(ctx context.Context) ([]Cluster
 		return nil, err
 	}
 
+	m.prioritizeFocused(clusters)
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	results := make([]ClusterHealth, 0, len(clusters))
@@ -4348,80 +4654,90 @@ func (m *MultiClusterClient) CheckSecurityIssues(ctx context.Context, contextNam
 	}
 
 	var issues []SecurityIssue
-	for _, pod := range pods.Items {
-		for _, container := range pod.Spec.Containers {
-			sc := container.SecurityContext
-			podSC := pod.Spec.SecurityContext
-
-			// Check for privileged containers
-			if sc != nil && sc.Privileged != nil && *sc.Privileged {
-				issues = append(issues, SecurityIssue{
-					Name:      pod.Name,
-					Namespace: pod.Namespace,
-					Cluster:   contextName,
-					Issue:     "Privileged container",
-					Severity:  "high",
-					Details:   fmt.Sprintf("Container '%s' running in privileged mode", container.Name),
-				})
-			}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		issues = append(issues, podSecurityIssues(pod.Name, pod.Namespace, contextName, &pod.Spec)...)
+	}
 
-			// Check for running as root
-			runAsRoot := false
-			if sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
-				runAsRoot = true
-			} else if sc == nil && podSC != nil && podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
-				runAsRoot = true
-			}
-			if runAsRoot {
-				issues = append(issues, SecurityIssue{
-					Name:      pod.Name,
-					Namespace: pod.Namespace,
-					Cluster:   contextName,
-					Issue:     "Running as root",
-					Severity:  "high",
-					Details:   fmt.Sprintf("Container '%s' running as root user (UID 0)", container.Name),
-				})
-			}
+	return issues, nil
+}
 
-			// Check for missing security context
-			if sc == nil && podSC == nil {
-				issues = append(issues, SecurityIssue{
-					Name:      pod.Name,
-					Namespace: pod.Namespace,
-					Cluster:   contextName,
-					Issue:     "Missing security context",
-					Severity:  "low",
-					Details:   fmt.Sprintf("Container '%s' has no security context defined", container.Name),
-				})
-			}
+// podSecurityIssues checks a pod spec for privileged or root containers,
+// missing security contexts, host namespaces and the hardening gaps found by
+// podHardeningIssues
+func podSecurityIssues(name, namespace, contextName string, spec *corev1.PodSpec) []SecurityIssue {
+	var issues []SecurityIssue
+	podSC := spec.SecurityContext
+	for _, container := range spec.Containers {
+		sc := container.SecurityContext
+
+		// Check for privileged containers
+		if sc != nil && sc.Privileged != nil && *sc.Privileged {
+			issues = append(issues, SecurityIssue{
+				Name:      name,
+				Namespace: namespace,
+				Cluster:   contextName,
+				Issue:     "Privileged container",
+				Severity:  "high",
+				Details:   fmt.Sprintf("Container '%s' running in privileged mode", container.Name),
+			})
 		}
 
-		// Check for host network
-		if pod.Spec.HostNetwork {
+		// Check for running as root
+		runAsRoot := false
+		if sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			runAsRoot = true
+		} else if sc == nil && podSC != nil && podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
+			runAsRoot = true
+		}
+		if runAsRoot {
 			issues = append(issues, SecurityIssue{
-				Name:      pod.Name,
-				Namespace: pod.Namespace,
+				Name:      name,
+				Namespace: namespace,
 				Cluster:   contextName,
-				Issue:     "Host network enabled",
-				Severity:  "medium",
-				Details:   "Pod using host network namespace",
+				Issue:     "Running as root",
+				Severity:  "high",
+				Details:   fmt.Sprintf("Container '%s' running as root user (UID 0)", container.Name),
 			})
 		}
 
-		// Check for host PID
-		if pod.Spec.HostPID {
+		// Check for missing security context
+		if sc == nil && podSC == nil {
 			issues = append(issues, SecurityIssue{
-				Name:      pod.Name,
-				Namespace: pod.Namespace,
+				Name:      name,
+				Namespace: namespace,
 				Cluster:   contextName,
-				Issue:     "Host PID enabled",
-				Severity:  "medium",
-				Details:   "Pod sharing host PID namespace",
+				Issue:     "Missing security context",
+				Severity:  "low",
+				Details:   fmt.Sprintf("Container '%s' has no security context defined", container.Name),
 			})
 		}
 	}
 
-	return issues, nil
+	// Check for host network
+	if spec.HostNetwork {
+		issues = append(issues, SecurityIssue{
+			Name:      name,
+			Namespace: namespace,
+			Cluster:   contextName,
+			Issue:     "Host network enabled",
+			Severity:  "medium",
+			Details:   "Pod using host network namespace",
+		})
+	}
+
+	// Check for host PID
+	if spec.HostPID {
+		issues = append(issues, SecurityIssue{
+			Name:      name,
+			Namespace: namespace,
+			Cluster:   contextName,
+			Issue:     "Host PID enabled",
+			Severity:  "medium",
+			Details:   "Pod sharing host PID namespace",
+		})
+	}
+	return append(issues, podHardeningIssues(name, namespace, contextName, spec)...)
 }
 
 func formatDuration(d time.Duration) string {
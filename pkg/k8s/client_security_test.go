@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -223,6 +224,133 @@ func TestCheckSecurityIssues(t *testing.T) {
 	}
 }
 
+func TestCheckPodSecurityLevels(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.rawConfig = &api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "cl1"}}}
+
+	yes, no := true, false
+	hardened := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &no,
+		RunAsNonRoot:             &yes,
+		ReadOnlyRootFilesystem:   &yes,
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+	m.clients["c1"] = fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "restricted-pod", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", SecurityContext: hardened}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline-pod", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app"}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "privileged-pod", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{Name: "sock", VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"},
+				}}},
+				Containers: []corev1.Container{{
+					Name:  "app",
+					Ports: []corev1.ContainerPort{{ContainerPort: 80, HostPort: 8080}},
+					SecurityContext: &corev1.SecurityContext{
+						Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+					},
+				}},
+			},
+		},
+	)
+
+	levels, err := m.CheckPodSecurityLevels(context.Background(), "c1", "default")
+	if err != nil {
+		t.Fatalf("CheckPodSecurityLevels failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, l := range levels {
+		got[l.Name] = l.Level
+	}
+	want := map[string]string{"restricted-pod": PSSRestricted, "baseline-pod": PSSBaseline, "privileged-pod": PSSPrivileged}
+	for name, level := range want {
+		if got[name] != level {
+			t.Errorf("Expected %s to satisfy %s, got %q", name, level, got[name])
+		}
+	}
+
+	issues, err := m.CheckSecurityIssues(context.Background(), "c1", "default")
+	if err != nil {
+		t.Fatalf("CheckSecurityIssues failed: %v", err)
+	}
+	issueMap := make(map[string]bool)
+	for _, i := range issues {
+		issueMap[i.Name+":"+i.Issue] = true
+	}
+	for _, key := range []string{
+		"privileged-pod:HostPath volume",
+		"privileged-pod:Host port",
+		"privileged-pod:Dangerous capability",
+		"baseline-pod:Missing seccomp profile",
+		"baseline-pod:Privilege escalation allowed",
+		"baseline-pod:Writable root filesystem",
+	} {
+		if !issueMap[key] {
+			t.Errorf("Expected issue %s", key)
+		}
+	}
+	for key := range issueMap {
+		if strings.HasPrefix(key, "restricted-pod:") {
+			t.Errorf("Expected no issues for the hardened pod, got %s", key)
+		}
+	}
+}
+
+func TestGetSecurityPosture(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.rawConfig = &api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "cl1"}}}
+
+	yes, no := true, false
+	hardened := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &no,
+		RunAsNonRoot:             &yes,
+		ReadOnlyRootFilesystem:   &yes,
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	m.clients["c1"] = fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "clean", Namespace: "good"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", SecurityContext: hardened}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "priv", Namespace: "bad"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", SecurityContext: &corev1.SecurityContext{
+				Privileged:               &yes,
+				AllowPrivilegeEscalation: &no,
+				ReadOnlyRootFilesystem:   &yes,
+				SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			}}}},
+		},
+	)
+
+	posture, err := m.GetSecurityPosture(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetSecurityPosture failed: %v", err)
+	}
+	if posture.Pods != 2 || posture.High != 1 || posture.Score != 87.5 {
+		t.Errorf("Unexpected cluster posture: %+v", posture)
+	}
+	if len(posture.Namespaces) != 2 || posture.Namespaces[0].Namespace != "bad" || posture.Namespaces[0].Score != 75 || posture.Namespaces[1].Score != 100 {
+		t.Errorf("Expected the worst namespace first, got %+v", posture.Namespaces)
+	}
+
+	if _, err := m.GetSecurityPosture(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for unknown cluster")
+	}
+}
+
 func TestFindDeploymentIssues(t *testing.T) {
 	m, _ := NewMultiClusterClient("")
 	m.rawConfig = &api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "cl1"}}}
@@ -0,0 +1,203 @@
+package k8s
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// QPSEnv overrides ClientTuning.QPS for both the agent and the backend
+	QPSEnv = "KC_K8S_QPS"
+	// BurstEnv overrides ClientTuning.Burst for both the agent and the backend
+	BurstEnv = "KC_K8S_BURST"
+
+	dialTimeout = 30 * time.Second
+)
+
+// ClientTuning controls the client-side rate limit and connection reuse of the
+// API clients built for each cluster. client-go defaults to 5 QPS with a burst
+// of 10, which throttles fan-outs that list many resource types at once.
+// HTTP/2 connection health pings are left to client-go, which sends one after
+// 30s without a frame and drops the connection if it is not answered in 15s
+// (HTTP2_READ_IDLE_TIMEOUT_SECONDS and HTTP2_PING_TIMEOUT_SECONDS override them).
+type ClientTuning struct {
+	QPS       float32                     // sustained requests per second to one cluster
+	Burst     int                         // requests allowed above QPS in short bursts
+	KeepAlive time.Duration               // TCP keep-alive probe interval; 0 keeps the Go default
+	Clusters  map[string]ClusterRateLimit // per-context overrides of QPS and Burst
+}
+
+// ClusterRateLimit overrides the rate limit of one context; zero fields keep the default
+type ClusterRateLimit struct {
+	QPS   float32
+	Burst int
+}
+
+// DefaultClientTuning is used unless SetClientTuning, KC_K8S_QPS or KC_K8S_BURST say otherwise
+var DefaultClientTuning = ClientTuning{QPS: 50, Burst: 100, KeepAlive: 30 * time.Second}
+
+func clientTuningFromEnv() ClientTuning {
+	tuning := DefaultClientTuning
+	if raw := os.Getenv(QPSEnv); raw != "" {
+		qps, err := strconv.ParseFloat(raw, 32)
+		if err != nil || qps <= 0 {
+			log.Printf("Warning: ignoring %s=%q (expected a positive number)", QPSEnv, raw)
+		} else {
+			tuning.QPS = float32(qps)
+		}
+	}
+	if raw := os.Getenv(BurstEnv); raw != "" {
+		burst, err := strconv.Atoi(raw)
+		if err != nil || burst < 1 {
+			log.Printf("Warning: ignoring %s=%q (expected a positive integer)", BurstEnv, raw)
+		} else {
+			tuning.Burst = burst
+		}
+	}
+	return tuning
+}
+
+// rateLimit returns the QPS and burst for a context, applying its override
+func (t ClientTuning) rateLimit(contextName string) (float32, int) {
+	qps, burst := t.QPS, t.Burst
+	if override, ok := t.Clusters[contextName]; ok {
+		if override.QPS > 0 {
+			qps = override.QPS
+		}
+		if override.Burst > 0 {
+			burst = override.Burst
+		}
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return qps, burst
+}
+
+// SetClientTuning replaces the client tuning. Cached clients are dropped so
+// the next request to each cluster picks up the new limits.
+func (m *MultiClusterClient) SetClientTuning(t ClientTuning) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clientTuning = t
+	m.resetClientsLocked()
+}
+
+// ClientTuning returns the current client tuning
+func (m *MultiClusterClient) ClientTuning() ClientTuning {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.clientTuningLocked()
+}
+
+func (m *MultiClusterClient) clientTuningLocked() ClientTuning {
+	if m.clientTuning.QPS == 0 {
+		return DefaultClientTuning
+	}
+	return m.clientTuning
+}
+
+// tuneConfigLocked gives a context's config a rate limiter shared by every
+// client built from it, so the typed, dynamic and metadata clients of a
+// cluster draw from one budget instead of three
+func (m *MultiClusterClient) tuneConfigLocked(config *rest.Config, contextName string) {
+	tuning := m.clientTuningLocked()
+	config.QPS, config.Burst = tuning.rateLimit(contextName)
+	config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst)
+}
+
+// httpClientForLocked builds the shared HTTP client of a context with the TCP
+// keep-alive of the tuning. The dialer is set on a copy only: client-go cannot
+// cache transports of configs with a Dial, so leaving it on the stored config
+// would make every TransportFor on a copy build and keep a new transport.
+func (m *MultiClusterClient) httpClientForLocked(config *rest.Config) (*http.Client, error) {
+	if keepAlive := m.clientTuningLocked().KeepAlive; keepAlive > 0 && config.Dial == nil {
+		config = rest.CopyConfig(config)
+		config.Dial = (&net.Dialer{Timeout: dialTimeout, KeepAlive: keepAlive}).DialContext
+	}
+	return rest.HTTPClientFor(config)
+}
+
+// clientConfigLocked returns the config and HTTP client of a context, building
+// and caching them on first use. Every client of the context shares the HTTP
+// client, and with it one transport and its pooled HTTP/2 connection, rather
+// than dialing and handshaking per client.
+func (m *MultiClusterClient) clientConfigLocked(contextName string) (*rest.Config, *http.Client, error) {
+	if config, ok := m.configs[contextName]; ok {
+		if httpClient, ok := m.httpClients[contextName]; ok {
+			return config, httpClient, nil
+		}
+	}
+
+	var config *rest.Config
+	var err error
+
+	// Handle in-cluster context specially — accept both "in-cluster" and the detected name
+	isInCluster := m.inClusterConfig != nil && (contextName == "in-cluster" || contextName == m.inClusterName)
+	if isInCluster {
+		config = rest.CopyConfig(m.inClusterConfig)
+	} else {
+		config, err = m.contextRestConfigLocked(contextName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get config for context %s: %w", contextName, err)
+		}
+	}
+
+	// Set reasonable timeouts — large OpenShift clusters (18+ nodes) can return
+	// 800KB+ node payloads that take >10s over higher-latency links. Slow WAN
+	// clusters can raise it through their configured operation timeouts.
+	config.Timeout = clientTimeout(m.clusterPolicy, m.rawConfig, contextName)
+	m.tuneConfigLocked(config, contextName)
+	m.wrapRetries(config)
+	wrapImpersonation(config)
+
+	httpClient, err := m.httpClientForLocked(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create HTTP client for context %s: %w", contextName, err)
+	}
+	if m.httpClients == nil {
+		m.httpClients = make(map[string]*http.Client)
+	}
+	m.configs[contextName] = config
+	m.httpClients[contextName] = httpClient
+	return config, httpClient, nil
+}
+
+// GetHTTPClient returns the HTTP client shared by every API client of a
+// context, authenticated and rate limited like them. Use it for raw requests
+// to the API server rather than building a transport per request.
+func (m *MultiClusterClient) GetHTTPClient(contextName string) (*http.Client, error) {
+	if _, err := m.GetClient(contextName); err != nil {
+		return nil, err
+	}
+	if httpClient := m.sharedHTTPClient(contextName); httpClient != nil {
+		return httpClient, nil
+	}
+	return nil, fmt.Errorf("no HTTP client for context %s", contextName)
+}
+
+// sharedHTTPClient returns the cached HTTP client of a context, if any
+func (m *MultiClusterClient) sharedHTTPClient(contextName string) *http.Client {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.httpClients[contextName]
+}
+
+// resetClientsLocked drops every cached client, config and transport
+func (m *MultiClusterClient) resetClientsLocked() {
+	m.clients = make(map[string]kubernetes.Interface)
+	m.dynamicClients = make(map[string]dynamic.Interface)
+	m.metadataClients = nil
+	m.configs = make(map[string]*rest.Config)
+	m.httpClients = nil
+}
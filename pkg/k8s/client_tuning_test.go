@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestClientTuningFromEnv(t *testing.T) {
+	t.Setenv(QPSEnv, "200")
+	t.Setenv(BurstEnv, "bogus")
+	tuning := clientTuningFromEnv()
+	if tuning.QPS != 200 || tuning.Burst != DefaultClientTuning.Burst {
+		t.Errorf("Expected QPS from env and the default burst, got %+v", tuning)
+	}
+
+	tuning.Clusters = map[string]ClusterRateLimit{"slow": {QPS: 5}}
+	if qps, burst := tuning.rateLimit("slow"); qps != 5 || burst != DefaultClientTuning.Burst {
+		t.Errorf("Expected the per-cluster QPS override, got %v/%d", qps, burst)
+	}
+	if qps, _ := tuning.rateLimit("other"); qps != 200 {
+		t.Errorf("Expected other clusters to keep the default, got %v", qps)
+	}
+}
+
+func TestSharedClientTransport(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[]}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(api.Config{
+		Clusters: map[string]*api.Cluster{"c": {Server: srv.URL}},
+		Contexts: map[string]*api.Context{"c1": {Cluster: "c"}, "c2": {Cluster: "c"}},
+	}, kubeconfig); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+	m, _ := NewMultiClusterClient(kubeconfig)
+	m.SetClientTuning(ClientTuning{QPS: 80, Burst: 160, Clusters: map[string]ClusterRateLimit{"c2": {QPS: 5, Burst: 7}}})
+
+	client, err := m.GetClient("c1")
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+	dyn, err := m.GetDynamicClient("c1")
+	if err != nil {
+		t.Fatalf("GetDynamicClient failed: %v", err)
+	}
+	if _, err := client.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{}); err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if _, err := dyn.Resource(namespacesGVR).List(context.Background(), metav1.ListOptions{}); err != nil {
+		t.Fatalf("Dynamic list failed: %v", err)
+	}
+	httpClient, err := m.GetHTTPClient("c1")
+	if err != nil {
+		t.Fatalf("GetHTTPClient failed: %v", err)
+	}
+	resp, err := httpClient.Get(srv.URL + "/api/v1/namespaces")
+	if err != nil {
+		t.Fatalf("Raw request failed: %v", err)
+	}
+	resp.Body.Close()
+	if n := conns.Load(); n != 1 {
+		t.Errorf("Expected the typed, dynamic and raw clients to share one connection, got %d", n)
+	}
+
+	c1, _ := m.GetRestConfig("c1")
+	if c1.Dial != nil {
+		t.Error("Expected no Dial on the stored config, which would defeat client-go's transport cache")
+	}
+	if c1.QPS != 80 || c1.Burst != 160 || c1.RateLimiter == nil {
+		t.Errorf("Expected the configured rate limit, got %v/%d", c1.QPS, c1.Burst)
+	}
+	c2, err := m.GetRestConfig("c2")
+	if err != nil || c2.QPS != 5 || c2.Burst != 7 {
+		t.Errorf("Expected the c2 override, got %+v %v", c2, err)
+	}
+	if m.sharedHTTPClient("c1") == m.sharedHTTPClient("c2") {
+		t.Error("Expected one HTTP client per context")
+	}
+
+	m.SetClientTuning(DefaultClientTuning)
+	if m.sharedHTTPClient("c1") != nil {
+		t.Error("Expected new tuning to drop cached clients")
+	}
+}
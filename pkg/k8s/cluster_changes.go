@@ -0,0 +1,210 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClusterState is a compact point-in-time record of the parts of a cluster
+// on-call engineers compare first: node health, failing deployments, GPUs and
+// versions
+type ClusterState struct {
+	Cluster            string               `json:"cluster"`
+	CapturedAt         time.Time            `json:"capturedAt"`
+	Version            string               `json:"version,omitempty"`
+	Nodes              map[string]NodeState `json:"nodes"`
+	FailingDeployments map[string]string    `json:"failingDeployments"` // namespace/name -> reason
+}
+
+// NodeState is the recorded state of one node
+type NodeState struct {
+	Ready         bool   `json:"ready"`
+	Unschedulable bool   `json:"unschedulable,omitempty"`
+	Kubelet       string `json:"kubelet,omitempty"`
+	GPUs          int    `json:"gpus,omitempty"`
+	GPUType       string `json:"gpuType,omitempty"`
+}
+
+// ClusterChanges lists what changed in a cluster between two states
+type ClusterChanges struct {
+	Cluster                 string             `json:"cluster"`
+	Since                   time.Time          `json:"since"`
+	Now                     time.Time          `json:"now"`
+	NewUnhealthyNodes       []string           `json:"newUnhealthyNodes"`
+	RecoveredNodes          []string           `json:"recoveredNodes"`
+	AddedNodes              []string           `json:"addedNodes"`
+	RemovedNodes            []string           `json:"removedNodes"`
+	NewlyFailingDeployments []DeploymentChange `json:"newlyFailingDeployments"`
+	RecoveredDeployments    []string           `json:"recoveredDeployments"`
+	GPUs                    GPUCountChange     `json:"gpus"`
+	VersionChanges          []VersionChange    `json:"versionChanges"`
+	Changed                 bool               `json:"changed"`
+	Error                   string             `json:"error,omitempty"`
+}
+
+// DeploymentChange is a deployment that started failing
+type DeploymentChange struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// GPUCountChange compares the GPUs of a cluster, in total and per type
+type GPUCountChange struct {
+	Before int            `json:"before"`
+	After  int            `json:"after"`
+	ByType map[string]int `json:"byType,omitempty"` // type -> delta, only types that changed
+}
+
+// VersionChange is a control plane or kubelet version bump
+type VersionChange struct {
+	Component string `json:"component"` // control-plane or kubelet
+	Node      string `json:"node,omitempty"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+// CaptureClusterState records the current state of a cluster
+func (m *MultiClusterClient) CaptureClusterState(ctx context.Context, contextName string) (*ClusterState, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	nodes, err := m.GetNodes(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	failing, err := m.FindDeploymentIssues(ctx, contextName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ClusterState{
+		Cluster:            contextName,
+		CapturedAt:         time.Now(),
+		Version:            info.GitVersion,
+		Nodes:              make(map[string]NodeState, len(nodes)),
+		FailingDeployments: make(map[string]string, len(failing)),
+	}
+	for _, n := range nodes {
+		state.Nodes[n.Name] = NodeState{
+			Ready:         n.Status == "Ready",
+			Unschedulable: n.Unschedulable,
+			Kubelet:       n.KubeletVersion,
+			GPUs:          n.GPUCount,
+			GPUType:       n.GPUType,
+		}
+	}
+	for _, d := range failing {
+		state.FailingDeployments[d.Namespace+"/"+d.Name] = d.Reason
+	}
+	return state, nil
+}
+
+// DiffClusterState reports what changed from before to after
+func DiffClusterState(before, after *ClusterState) ClusterChanges {
+	changes := ClusterChanges{
+		Cluster:                 after.Cluster,
+		Since:                   before.CapturedAt,
+		Now:                     after.CapturedAt,
+		NewUnhealthyNodes:       []string{},
+		RecoveredNodes:          []string{},
+		AddedNodes:              []string{},
+		RemovedNodes:            []string{},
+		NewlyFailingDeployments: []DeploymentChange{},
+		RecoveredDeployments:    []string{},
+		VersionChanges:          []VersionChange{},
+	}
+
+	if before.Version != "" && after.Version != "" && before.Version != after.Version {
+		changes.VersionChanges = append(changes.VersionChanges, VersionChange{Component: "control-plane", From: before.Version, To: after.Version})
+	}
+
+	gpuDelta := make(map[string]int)
+	for name, prev := range before.Nodes {
+		changes.GPUs.Before += prev.GPUs
+		if prev.GPUs > 0 {
+			gpuDelta[prev.GPUType] -= prev.GPUs
+		}
+		if _, ok := after.Nodes[name]; !ok {
+			changes.RemovedNodes = append(changes.RemovedNodes, name)
+		}
+	}
+	for name, cur := range after.Nodes {
+		changes.GPUs.After += cur.GPUs
+		if cur.GPUs > 0 {
+			gpuDelta[cur.GPUType] += cur.GPUs
+		}
+		prev, existed := before.Nodes[name]
+		switch {
+		case !existed:
+			changes.AddedNodes = append(changes.AddedNodes, name)
+			if !cur.Ready {
+				changes.NewUnhealthyNodes = append(changes.NewUnhealthyNodes, name)
+			}
+		case prev.Ready && !cur.Ready:
+			changes.NewUnhealthyNodes = append(changes.NewUnhealthyNodes, name)
+		case !prev.Ready && cur.Ready:
+			changes.RecoveredNodes = append(changes.RecoveredNodes, name)
+		}
+		if existed && prev.Kubelet != "" && cur.Kubelet != "" && prev.Kubelet != cur.Kubelet {
+			changes.VersionChanges = append(changes.VersionChanges, VersionChange{Component: "kubelet", Node: name, From: prev.Kubelet, To: cur.Kubelet})
+		}
+	}
+	for gpuType, delta := range gpuDelta {
+		if delta == 0 {
+			continue
+		}
+		if changes.GPUs.ByType == nil {
+			changes.GPUs.ByType = make(map[string]int)
+		}
+		changes.GPUs.ByType[gpuType] = delta
+	}
+
+	for key, reason := range after.FailingDeployments {
+		if _, failed := before.FailingDeployments[key]; failed {
+			continue
+		}
+		namespace, name, _ := strings.Cut(key, "/")
+		changes.NewlyFailingDeployments = append(changes.NewlyFailingDeployments, DeploymentChange{Namespace: namespace, Name: name, Reason: reason})
+	}
+	for key := range before.FailingDeployments {
+		if _, failing := after.FailingDeployments[key]; !failing {
+			changes.RecoveredDeployments = append(changes.RecoveredDeployments, key)
+		}
+	}
+
+	sort.Strings(changes.NewUnhealthyNodes)
+	sort.Strings(changes.RecoveredNodes)
+	sort.Strings(changes.AddedNodes)
+	sort.Strings(changes.RemovedNodes)
+	sort.Strings(changes.RecoveredDeployments)
+	sort.Slice(changes.NewlyFailingDeployments, func(i, j int) bool {
+		a, b := changes.NewlyFailingDeployments[i], changes.NewlyFailingDeployments[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(changes.VersionChanges, func(i, j int) bool {
+		a, b := changes.VersionChanges[i], changes.VersionChanges[j]
+		if a.Component != b.Component {
+			return a.Component < b.Component
+		}
+		return a.Node < b.Node
+	})
+
+	changes.Changed = len(changes.NewUnhealthyNodes) > 0 || len(changes.RecoveredNodes) > 0 ||
+		len(changes.AddedNodes) > 0 || len(changes.RemovedNodes) > 0 ||
+		len(changes.NewlyFailingDeployments) > 0 || len(changes.RecoveredDeployments) > 0 ||
+		changes.GPUs.Before != changes.GPUs.After || len(changes.GPUs.ByType) > 0 ||
+		len(changes.VersionChanges) > 0
+	return changes
+}
@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffClusterState(t *testing.T) {
+	yesterday := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	before := &ClusterState{
+		Cluster:    "c1",
+		CapturedAt: yesterday,
+		Version:    "v1.30.4",
+		Nodes: map[string]NodeState{
+			"cpu-1": {Ready: true, Kubelet: "v1.30.4"},
+			"gpu-1": {Ready: true, Kubelet: "v1.30.4", GPUs: 8, GPUType: "H100"},
+			"gpu-2": {Ready: false, Kubelet: "v1.30.4", GPUs: 8, GPUType: "H100"},
+			"old-1": {Ready: true, Kubelet: "v1.30.4"},
+		},
+		FailingDeployments: map[string]string{"shop/cart": "Unavailable"},
+	}
+	after := &ClusterState{
+		Cluster:    "c1",
+		CapturedAt: yesterday.Add(24 * time.Hour),
+		Version:    "v1.31.1",
+		Nodes: map[string]NodeState{
+			"cpu-1": {Ready: false, Kubelet: "v1.31.1"},
+			"gpu-1": {Ready: true, Kubelet: "v1.30.4", GPUs: 8, GPUType: "H100"},
+			"gpu-2": {Ready: true, Kubelet: "v1.30.4", GPUs: 8, GPUType: "H100"},
+			"gpu-3": {Ready: true, Kubelet: "v1.30.4", GPUs: 4, GPUType: "A100"},
+		},
+		FailingDeployments: map[string]string{"shop/api": "ProgressDeadlineExceeded"},
+	}
+
+	changes := DiffClusterState(before, after)
+	if !changes.Changed {
+		t.Fatal("Expected changes to be reported")
+	}
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"new unhealthy nodes", changes.NewUnhealthyNodes, []string{"cpu-1"}},
+		{"recovered nodes", changes.RecoveredNodes, []string{"gpu-2"}},
+		{"added nodes", changes.AddedNodes, []string{"gpu-3"}},
+		{"removed nodes", changes.RemovedNodes, []string{"old-1"}},
+		{"newly failing deployments", changes.NewlyFailingDeployments, []DeploymentChange{{Namespace: "shop", Name: "api", Reason: "ProgressDeadlineExceeded"}}},
+		{"recovered deployments", changes.RecoveredDeployments, []string{"shop/cart"}},
+		{"gpus", changes.GPUs, GPUCountChange{Before: 16, After: 20, ByType: map[string]int{"A100": 4}}},
+		{"version changes", changes.VersionChanges, []VersionChange{
+			{Component: "control-plane", From: "v1.30.4", To: "v1.31.1"},
+			{Component: "kubelet", Node: "cpu-1", From: "v1.30.4", To: "v1.31.1"},
+		}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.want, c.got)
+		}
+	}
+
+	if unchanged := DiffClusterState(after, after); unchanged.Changed {
+		t.Errorf("Expected no changes against the same state, got %+v", unchanged)
+	}
+}
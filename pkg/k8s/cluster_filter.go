@@ -0,0 +1,227 @@
+package k8s
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// ClusterFilter limits which contexts ListClusters returns, and therefore which
+// clusters every fan-out query touches. Patterns are regular expressions matched
+// against the whole context name, so a plain name matches only itself.
+type ClusterFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewClusterFilter compiles include/exclude patterns. With no include patterns every
+// cluster is included; exclude patterns always win. Returns nil when both are empty.
+func NewClusterFilter(include, exclude []string) (*ClusterFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	f := &ClusterFilter{}
+	var err error
+	if f.include, err = compileClusterPatterns(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compileClusterPatterns(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func compileClusterPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var out []*regexp.Regexp
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster pattern %q: %w", p, err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// Allows reports whether a cluster passes the filter. A nil filter allows everything.
+func (f *ClusterFilter) Allows(name string) bool {
+	if f == nil {
+		return true
+	}
+	for _, re := range f.exclude {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the clusters allowed by the filter
+func (f *ClusterFilter) Apply(clusters []ClusterInfo) []ClusterInfo {
+	if f == nil {
+		return clusters
+	}
+	out := clusters[:0]
+	for _, c := range clusters {
+		if f.Allows(c.Name) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// SetClusterFilter replaces the include/exclude filter; nil removes it
+func (m *MultiClusterClient) SetClusterFilter(f *ClusterFilter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clusterFilter = f
+}
+
+// ClusterGroup tags the clusters whose names match any of its patterns
+type ClusterGroup struct {
+	Name     string
+	Patterns []string
+}
+
+// ClusterPolicy is the user-editable cluster selection (persisted in settings). It
+// is read on every ListClusters call so edits apply without a reload.
+type ClusterPolicy struct {
+	Include     []string
+	Exclude     []string
+	Groups      []ClusterGroup
+	Preferences map[string]ClusterPreference // keyed by context name
+	Timeouts    map[string]ClusterTimeouts   // keyed by context name or AllClustersTimeoutKey
+}
+
+// ClusterPreference is a user-assigned display name, color and pin for one cluster
+type ClusterPreference struct {
+	DisplayName string
+	Color       string
+	Pinned      bool
+}
+
+// compiledClusterPolicy caches the compiled form of the last policy seen
+type compiledClusterPolicy struct {
+	source ClusterPolicy
+	filter *ClusterFilter
+	groups []compiledClusterGroup
+}
+
+type compiledClusterGroup struct {
+	name     string
+	patterns []*regexp.Regexp
+}
+
+// SetClusterPolicySource sets the function providing filters and groups; nil removes it
+func (m *MultiClusterClient) SetClusterPolicySource(fn func() ClusterPolicy) {
+	m.mu.Lock()
+	m.clusterPolicy = fn
+	m.mu.Unlock()
+
+	m.policyMu.Lock()
+	m.compiledPolicy = nil
+	m.policyMu.Unlock()
+}
+
+// compilePolicy returns the compiled policy, recompiling only when it changed.
+// Invalid patterns are logged and skipped so a typo can't hide every cluster.
+func (m *MultiClusterClient) compilePolicy(policy ClusterPolicy) *compiledClusterPolicy {
+	m.policyMu.Lock()
+	defer m.policyMu.Unlock()
+	if m.compiledPolicy != nil && reflect.DeepEqual(m.compiledPolicy.source, policy) {
+		return m.compiledPolicy
+	}
+
+	compiled := &compiledClusterPolicy{source: policy}
+	filter, err := NewClusterFilter(policy.Include, policy.Exclude)
+	if err != nil {
+		log.Printf("Warning: ignoring cluster filters from settings: %v", err)
+	} else {
+		compiled.filter = filter
+	}
+	for _, g := range policy.Groups {
+		patterns, err := compileClusterPatterns(g.Patterns)
+		if err != nil {
+			log.Printf("Warning: ignoring cluster group %q: %v", g.Name, err)
+			continue
+		}
+		compiled.groups = append(compiled.groups, compiledClusterGroup{name: g.Name, patterns: patterns})
+	}
+	m.compiledPolicy = compiled
+	return compiled
+}
+
+// applyClusterPolicy filters clusters by the settings policy and sets their groups
+func (m *MultiClusterClient) applyClusterPolicy(clusters []ClusterInfo, source func() ClusterPolicy) []ClusterInfo {
+	if source == nil {
+		return clusters
+	}
+	policy := m.compilePolicy(source())
+	clusters = policy.filter.Apply(clusters)
+	for i := range clusters {
+		pref := policy.source.Preferences[clusters[i].Name]
+		clusters[i].DisplayName = pref.DisplayName
+		clusters[i].Color = pref.Color
+		clusters[i].Pinned = pref.Pinned
+		clusters[i].Groups = nil
+		for _, g := range policy.groups {
+			for _, re := range g.patterns {
+				if re.MatchString(clusters[i].Name) {
+					clusters[i].Groups = append(clusters[i].Groups, g.name)
+					break
+				}
+			}
+		}
+	}
+	return clusters
+}
+
+// ClusterVisibility returns the names that pass the configured filters, mapped to
+// their groups and preferences. Callers that list contexts without ListClusters
+// (e.g. straight from a kubeconfig) use it to stay consistent.
+func (m *MultiClusterClient) ClusterVisibility(names []string) map[string]ClusterInfo {
+	m.mu.RLock()
+	filter := m.clusterFilter
+	source := m.clusterPolicy
+	m.mu.RUnlock()
+
+	clusters := make([]ClusterInfo, 0, len(names))
+	for _, name := range names {
+		clusters = append(clusters, ClusterInfo{Name: name})
+	}
+	clusters = m.applyClusterPolicy(filter.Apply(clusters), source)
+
+	visible := make(map[string]ClusterInfo, len(clusters))
+	for _, c := range clusters {
+		visible[c.Name] = c
+	}
+	return visible
+}
+
+// sortClusters orders pinned clusters first, then by name
+func sortClusters(clusters []ClusterInfo) {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Pinned != clusters[j].Pinned {
+			return clusters[i].Pinned
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+}
+
+// hasClusterPreference reports whether the user named or pinned the cluster
+func hasClusterPreference(c ClusterInfo) bool {
+	return c.DisplayName != "" || c.Pinned
+}
@@ -0,0 +1,161 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestClusterFilter(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{
+		Contexts: map[string]*api.Context{
+			"prod-east":   {Cluster: "pe"},
+			"prod-west":   {Cluster: "pw"},
+			"prod-legacy": {Cluster: "pl"},
+			"staging":     {Cluster: "st"},
+			"kind-dev":    {Cluster: "kd"},
+		},
+		Clusters: map[string]*api.Cluster{},
+	})
+
+	names := func() []string {
+		clusters, err := m.ListClusters(context.Background())
+		if err != nil {
+			t.Fatalf("ListClusters failed: %v", err)
+		}
+		var out []string
+		for _, c := range clusters {
+			out = append(out, c.Name)
+		}
+		return out
+	}
+
+	if got := names(); len(got) != 5 {
+		t.Fatalf("Expected all 5 clusters without a filter, got %v", got)
+	}
+
+	f, err := NewClusterFilter([]string{"prod-.*", "staging"}, []string{"prod-legacy"})
+	if err != nil {
+		t.Fatalf("NewClusterFilter failed: %v", err)
+	}
+	m.SetClusterFilter(f)
+	got := names()
+	want := []string{"prod-east", "prod-west", "staging"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+
+	// Patterns match whole names: "prod" alone matches nothing
+	f, _ = NewClusterFilter([]string{"prod"}, nil)
+	if f.Allows("prod-east") {
+		t.Error("Expected pattern to be anchored to the whole name")
+	}
+	// Exclude-only filters keep everything else
+	f, _ = NewClusterFilter(nil, []string{"kind-.*"})
+	if f.Allows("kind-dev") || !f.Allows("staging") {
+		t.Error("Expected exclude-only filter to drop kind-* only")
+	}
+
+	if _, err := NewClusterFilter([]string{"prod-("}, nil); err == nil {
+		t.Error("Expected invalid pattern to be rejected")
+	}
+	if f, _ := NewClusterFilter(nil, nil); f != nil || !f.Allows("anything") {
+		t.Error("Expected empty filter to be nil and allow everything")
+	}
+}
+
+func TestClusterPolicy(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{
+		Contexts: map[string]*api.Context{
+			"prod-east": {Cluster: "pe"},
+			"prod-gpu":  {Cluster: "pg"},
+			"kind-dev":  {Cluster: "kd"},
+		},
+		Clusters: map[string]*api.Cluster{},
+	})
+
+	policy := ClusterPolicy{
+		Exclude: []string{"kind-.*"},
+		Groups: []ClusterGroup{
+			{Name: "production", Patterns: []string{"prod-.*"}},
+			{Name: "gpu", Patterns: []string{".*-gpu"}},
+			{Name: "broken", Patterns: []string{"prod-("}},
+		},
+	}
+	m.SetClusterPolicySource(func() ClusterPolicy { return policy })
+
+	clusters, err := m.ListClusters(context.Background())
+	if err != nil {
+		t.Fatalf("ListClusters failed: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("Expected kind-dev to be excluded, got %+v", clusters)
+	}
+	groups := map[string][]string{}
+	for _, c := range clusters {
+		groups[c.Name] = c.Groups
+	}
+	if len(groups["prod-east"]) != 1 || groups["prod-east"][0] != "production" {
+		t.Errorf("Expected prod-east in production only, got %v", groups["prod-east"])
+	}
+	if len(groups["prod-gpu"]) != 2 {
+		t.Errorf("Expected prod-gpu in production and gpu, got %v", groups["prod-gpu"])
+	}
+
+	// Policy edits apply on the next call
+	policy.Exclude = nil
+	visible := m.ClusterVisibility([]string{"kind-dev", "prod-gpu"})
+	if _, ok := visible["kind-dev"]; !ok {
+		t.Errorf("Expected kind-dev to be visible after the exclude was removed, got %v", visible)
+	}
+
+	f, _ := NewClusterFilter(nil, []string{"prod-gpu"})
+	m.SetClusterFilter(f)
+	if _, ok := m.ClusterVisibility([]string{"prod-gpu"})["prod-gpu"]; ok {
+		t.Error("Expected the static filter to apply before the settings policy")
+	}
+}
+
+func TestClusterPreferences(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{
+		Contexts: map[string]*api.Context{
+			"alpha":                           {Cluster: "c1"},
+			"long/auto/generated/name/for/c1": {Cluster: "c1"},
+			"zeta":                            {Cluster: "c2"},
+		},
+		Clusters: map[string]*api.Cluster{
+			"c1": {Server: "https://shared.com"},
+			"c2": {Server: "https://unique.com"},
+		},
+	})
+	m.SetClusterPolicySource(func() ClusterPolicy {
+		return ClusterPolicy{Preferences: map[string]ClusterPreference{
+			"long/auto/generated/name/for/c1": {DisplayName: "Production", Color: "#f00"},
+			"zeta":                            {Pinned: true},
+		}}
+	})
+
+	clusters, err := m.DeduplicatedClusters(context.Background())
+	if err != nil {
+		t.Fatalf("DeduplicatedClusters failed: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 unique clusters, got %+v", clusters)
+	}
+	if clusters[0].Name != "zeta" || !clusters[0].Pinned {
+		t.Errorf("Expected the pinned cluster first, got %+v", clusters[0])
+	}
+	// The explicitly named context wins over the shorter heuristic name
+	if c := clusters[1]; c.Name != "long/auto/generated/name/for/c1" || c.DisplayName != "Production" || c.Color != "#f00" {
+		t.Errorf("Expected the named context to be kept with its preferences, got %+v", c)
+	}
+}
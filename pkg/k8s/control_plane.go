@@ -0,0 +1,290 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// probeLatencyWindow is how many recent API server probe timings are kept per context
+	probeLatencyWindow = 128
+	// slowAPIServerP99 is the p99 probe latency above which the API server is reported as slow
+	slowAPIServerP99 = time.Second
+	// controlPlaneNamespace is where self-hosted control plane components run
+	controlPlaneNamespace = "kube-system"
+)
+
+// controlPlaneComponents are the kube-system components reported, keyed by the
+// "component" label kubeadm and most installers put on their static pods
+var controlPlaneComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"}
+
+// leaderLeases are the kube-system Leases through which components elect a leader
+var leaderLeases = map[string]string{
+	"kube-controller-manager": "kube-controller-manager",
+	"kube-scheduler":          "kube-scheduler",
+}
+
+// ControlPlaneComponent is the health of one control plane component
+type ControlPlaneComponent struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Source    string `json:"source"` // pod, componentstatus
+	Pods      int    `json:"pods,omitempty"`
+	ReadyPods int    `json:"readyPods,omitempty"`
+	Restarts  int    `json:"restarts,omitempty"`
+	Leader    string `json:"leader,omitempty"` // holder of the component's leader Lease
+	Message   string `json:"message,omitempty"`
+}
+
+// APIServerLatency summarizes the timings of recent API server probes
+type APIServerLatency struct {
+	Samples int   `json:"samples"`
+	P50Ms   int64 `json:"p50Ms"`
+	P99Ms   int64 `json:"p99Ms"`
+}
+
+// EtcdStatus is the etcd health the API server exposes
+type EtcdStatus struct {
+	Healthy bool     `json:"healthy"`
+	Members int      `json:"members,omitempty"`
+	Alarms  []string `json:"alarms,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// ControlPlaneHealth is the health of a cluster's control plane. Managed is set
+// when no control plane pods are visible, as on hosted Kubernetes offerings,
+// where only API server latency and component statuses can be reported.
+type ControlPlaneHealth struct {
+	Healthy    bool                    `json:"healthy"`
+	Managed    bool                    `json:"managed"`
+	Components []ControlPlaneComponent `json:"components"`
+	APIServer  APIServerLatency        `json:"apiServer"`
+	Etcd       *EtcdStatus             `json:"etcd,omitempty"`
+	Issues     []string                `json:"issues,omitempty"`
+}
+
+// probeTimings keeps the latest probe latencies of each context
+type probeTimings struct {
+	mu      sync.Mutex
+	samples map[string][]time.Duration
+}
+
+// recordProbeLatency adds a probe timing to the context's window
+func (m *MultiClusterClient) recordProbeLatency(contextName string, d time.Duration) {
+	m.probeTimings.mu.Lock()
+	defer m.probeTimings.mu.Unlock()
+	if m.probeTimings.samples == nil {
+		m.probeTimings.samples = make(map[string][]time.Duration)
+	}
+	window := append(m.probeTimings.samples[contextName], d)
+	if len(window) > probeLatencyWindow {
+		window = window[len(window)-probeLatencyWindow:]
+	}
+	m.probeTimings.samples[contextName] = window
+}
+
+// apiServerLatency returns the p50 and p99 of the context's recent probe timings
+func (m *MultiClusterClient) apiServerLatency(contextName string) APIServerLatency {
+	m.probeTimings.mu.Lock()
+	sorted := append([]time.Duration(nil), m.probeTimings.samples[contextName]...)
+	m.probeTimings.mu.Unlock()
+	if len(sorted) == 0 {
+		return APIServerLatency{}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p float64) int64 {
+		return sorted[int(p*float64(len(sorted)-1))].Milliseconds()
+	}
+	return APIServerLatency{Samples: len(sorted), P50Ms: percentile(0.50), P99Ms: percentile(0.99)}
+}
+
+// GetControlPlaneHealth probes the API server and reports the health of the
+// control plane components, from their kube-system pods where they are
+// self-hosted and from the deprecated ComponentStatus API otherwise, with the
+// leader of each elected component and the etcd health and alarms the API
+// server exposes
+func (m *MultiClusterClient) GetControlPlaneHealth(ctx context.Context, contextName string) (*ControlPlaneHealth, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if _, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		return nil, err
+	}
+	m.recordProbeLatency(contextName, time.Since(start))
+
+	cp := &ControlPlaneHealth{Healthy: true, APIServer: m.apiServerLatency(contextName)}
+	byName := make(map[string]*ControlPlaneComponent)
+
+	pods, err := client.CoreV1().Pods(controlPlaneNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "component in (" + strings.Join(controlPlaneComponents, ",") + ")",
+	})
+	if err == nil {
+		for _, pod := range pods.Items {
+			name := pod.Labels["component"]
+			c, ok := byName[name]
+			if !ok {
+				c = &ControlPlaneComponent{Name: name, Source: "pod"}
+				byName[name] = c
+			}
+			c.Pods++
+			if isPodReady(&pod) {
+				c.ReadyPods++
+			}
+			for _, cs := range pod.Status.ContainerStatuses {
+				c.Restarts += int(cs.RestartCount)
+			}
+		}
+		for _, c := range byName {
+			c.Healthy = c.ReadyPods == c.Pods
+			if !c.Healthy {
+				c.Message = fmt.Sprintf("%d/%d pods ready", c.ReadyPods, c.Pods)
+			}
+		}
+	}
+	cp.Managed = len(byName) == 0
+
+	// ComponentStatus is deprecated but still served, and is the only view
+	// of the scheduler, controller manager and etcd on many managed offerings
+	var etcdMembers []corev1.ComponentStatus
+	if statuses, err := client.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, cs := range statuses.Items {
+			if strings.HasPrefix(cs.Name, "etcd") {
+				etcdMembers = append(etcdMembers, cs)
+				continue
+			}
+			if _, ok := byName[cs.Name]; ok {
+				continue
+			}
+			c := &ControlPlaneComponent{Name: cs.Name, Source: "componentstatus", Healthy: true}
+			for _, cond := range cs.Conditions {
+				if cond.Type == corev1.ComponentHealthy && cond.Status != corev1.ConditionTrue {
+					c.Healthy = false
+					c.Message = strings.TrimSpace(cond.Message + " " + cond.Error)
+				}
+			}
+			byName[cs.Name] = c
+		}
+	}
+	cp.Etcd = etcdStatus(ctx, client, etcdMembers)
+
+	for component, lease := range leaderLeases {
+		c, ok := byName[component]
+		if !ok {
+			continue
+		}
+		if l, err := client.CoordinationV1().Leases(controlPlaneNamespace).Get(ctx, lease, metav1.GetOptions{}); err == nil && l.Spec.HolderIdentity != nil {
+			c.Leader = *l.Spec.HolderIdentity
+		}
+	}
+
+	cp.Components = make([]ControlPlaneComponent, 0, len(byName))
+	for _, c := range byName {
+		cp.Components = append(cp.Components, *c)
+	}
+	sort.Slice(cp.Components, func(i, j int) bool { return cp.Components[i].Name < cp.Components[j].Name })
+	for _, c := range cp.Components {
+		if c.Healthy {
+			continue
+		}
+		cp.Healthy = false
+		msg := fmt.Sprintf("Control plane component %s unhealthy", c.Name)
+		if c.Message != "" {
+			msg += ": " + c.Message
+		}
+		cp.Issues = append(cp.Issues, msg)
+	}
+
+	if cp.Etcd != nil && !cp.Etcd.Healthy {
+		cp.Healthy = false
+		msg := "etcd unhealthy"
+		if len(cp.Etcd.Alarms) > 0 {
+			msg += ": alarms " + strings.Join(cp.Etcd.Alarms, ", ")
+		} else if cp.Etcd.Message != "" {
+			msg += ": " + cp.Etcd.Message
+		}
+		cp.Issues = append(cp.Issues, msg)
+	}
+	if cp.APIServer.P99Ms > slowAPIServerP99.Milliseconds() {
+		cp.Issues = append(cp.Issues, fmt.Sprintf("API server slow: p99 %dms over %d probes", cp.APIServer.P99Ms, cp.APIServer.Samples))
+	}
+	return cp, nil
+}
+
+// etcdStatus combines the etcd ComponentStatuses, whose message is etcd's own
+// /health response including active alarms, with the API server's etcd
+// readiness check when the client can make raw requests and is allowed to.
+// Returns nil when neither is available.
+func etcdStatus(ctx context.Context, client kubernetes.Interface, members []corev1.ComponentStatus) *EtcdStatus {
+	var status *EtcdStatus
+	if len(members) > 0 {
+		status = &EtcdStatus{Healthy: true, Members: len(members)}
+		for _, member := range members {
+			for _, cond := range member.Conditions {
+				if cond.Type != corev1.ComponentHealthy {
+					continue
+				}
+				if cond.Status != corev1.ConditionTrue {
+					status.Healthy = false
+					status.Message = strings.TrimSpace(cond.Message + " " + cond.Error)
+				}
+				var health struct {
+					Reason string `json:"reason"`
+				}
+				if json.Unmarshal([]byte(cond.Message), &health) == nil && strings.HasPrefix(health.Reason, "ALARM") {
+					status.Healthy = false
+					status.Alarms = append(status.Alarms, strings.TrimSpace(strings.TrimPrefix(health.Reason, "ALARM")))
+				}
+			}
+		}
+	}
+
+	rest := client.Discovery().RESTClient()
+	if rest == nil {
+		return status
+	}
+	body, err := rest.Get().AbsPath("/readyz/etcd").DoRaw(ctx)
+	if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) {
+		return status
+	}
+	if status == nil {
+		status = &EtcdStatus{Healthy: true}
+	}
+	if err != nil {
+		status.Healthy = false
+		status.Message = strings.TrimSpace(string(body) + " " + err.Error())
+	}
+	return status
+}
+
+// isPodReady reports whether a pod's Ready condition is true
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isNodeReady reports whether a node's Ready condition is true
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetControlPlaneHealth(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	controlPlanePod := func(name, component string, ready corev1.ConditionStatus, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system", Labels: map[string]string{"component": component, "tier": "control-plane"}},
+			Status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: component, RestartCount: restarts}},
+			},
+		}
+	}
+	holder := "cp-1_abc"
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		controlPlanePod("kube-apiserver-cp-1", "kube-apiserver", corev1.ConditionTrue, 0),
+		controlPlanePod("kube-scheduler-cp-1", "kube-scheduler", corev1.ConditionFalse, 7),
+		controlPlanePod("kube-controller-manager-cp-1", "kube-controller-manager", corev1.ConditionTrue, 0),
+		&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-controller-manager", Namespace: "kube-system"},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder},
+		},
+		&corev1.ComponentStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"},
+			Conditions: []corev1.ComponentCondition{{
+				Type:    corev1.ComponentHealthy,
+				Status:  corev1.ConditionFalse,
+				Message: `{"health":"false","reason":"ALARM NOSPACE"}`,
+			}},
+		},
+	)
+
+	// Earlier probes feed the percentiles
+	for i := 1; i <= 98; i++ {
+		m.recordProbeLatency("c1", time.Duration(i)*time.Millisecond)
+	}
+	m.recordProbeLatency("c1", 3*time.Second)
+	m.recordProbeLatency("c1", 3*time.Second)
+
+	cp, err := m.GetControlPlaneHealth(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetControlPlaneHealth failed: %v", err)
+	}
+	if cp.Healthy || cp.Managed {
+		t.Errorf("Expected an unhealthy self-hosted control plane, got %+v", cp)
+	}
+	byName := make(map[string]ControlPlaneComponent)
+	for _, c := range cp.Components {
+		byName[c.Name] = c
+	}
+	if c := byName["kube-scheduler"]; c.Healthy || c.Restarts != 7 || c.Message != "0/1 pods ready" {
+		t.Errorf("Expected unready scheduler, got %+v", c)
+	}
+	if c := byName["kube-controller-manager"]; !c.Healthy || c.Leader != holder {
+		t.Errorf("Expected healthy controller manager led by %s, got %+v", holder, c)
+	}
+	if cp.Etcd == nil || cp.Etcd.Healthy || len(cp.Etcd.Alarms) != 1 || cp.Etcd.Alarms[0] != "NOSPACE" {
+		t.Errorf("Expected etcd NOSPACE alarm, got %+v", cp.Etcd)
+	}
+	if cp.APIServer.Samples != 101 || cp.APIServer.P50Ms != 50 || cp.APIServer.P99Ms < 1000 {
+		t.Errorf("Unexpected API server latency: %+v", cp.APIServer)
+	}
+	joined := strings.Join(cp.Issues, "\n")
+	for _, want := range []string{"kube-scheduler unhealthy", "alarms NOSPACE", "API server slow"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected issue %q, got %v", want, cp.Issues)
+		}
+	}
+
+	// Without visible control plane pods the cluster is treated as managed
+	m.clients["c2"] = k8sfake.NewSimpleClientset()
+	cp, err = m.GetControlPlaneHealth(context.Background(), "c2")
+	if err != nil {
+		t.Fatalf("GetControlPlaneHealth failed: %v", err)
+	}
+	if !cp.Healthy || !cp.Managed || cp.Etcd != nil || len(cp.Issues) != 0 {
+		t.Errorf("Expected a healthy managed control plane, got %+v", cp)
+	}
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// maxCronJobRuns is how many recent Job runs are returned per CronJob
+	maxCronJobRuns = 5
+	// cronMissedGrace is how late a run may start before it counts as missed
+	cronMissedGrace = 2 * time.Minute
+)
+
+// CronJobRun is a Job created by a CronJob
+type CronJobRun struct {
+	Name           string `json:"name"`
+	Status         string `json:"status"` // Running, Succeeded, Failed
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	Duration       string `json:"duration,omitempty"`
+	Failures       int32  `json:"failures"`
+}
+
+// cronJobRuns groups Jobs by the CronJob that owns them, newest first
+func cronJobRuns(jobs []batchv1.Job) map[types.UID][]CronJobRun {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTimestamp.After(jobs[j].CreationTimestamp.Time)
+	})
+
+	runs := make(map[types.UID][]CronJobRun)
+	for _, job := range jobs {
+		var owner types.UID
+		for _, ref := range job.OwnerReferences {
+			if ref.Kind == "CronJob" {
+				owner = ref.UID
+			}
+		}
+		if owner == "" || len(runs[owner]) >= maxCronJobRuns {
+			continue
+		}
+
+		run := CronJobRun{Name: job.Name, Status: "Running", Failures: job.Status.Failed}
+		for _, c := range job.Status.Conditions {
+			if c.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch c.Type {
+			case batchv1.JobComplete:
+				run.Status = "Succeeded"
+			case batchv1.JobFailed:
+				run.Status = "Failed"
+			}
+		}
+		end := time.Now()
+		if job.Status.CompletionTime != nil {
+			run.CompletionTime = job.Status.CompletionTime.Format(time.RFC3339)
+			end = job.Status.CompletionTime.Time
+		}
+		if job.Status.StartTime != nil {
+			run.StartTime = job.Status.StartTime.Format(time.RFC3339)
+			run.Duration = formatDuration(end.Sub(job.Status.StartTime.Time))
+		}
+		runs[owner] = append(runs[owner], run)
+	}
+	return runs
+}
+
+// analyzeCronSchedule fills in the next scheduled time and flags CronJobs
+// whose last due run never started
+func analyzeCronSchedule(view *CronJob, cj *batchv1.CronJob, now time.Time) {
+	timeZone := ""
+	if cj.Spec.TimeZone != nil {
+		timeZone = *cj.Spec.TimeZone
+	}
+	view.TimeZone = timeZone
+	if cj.Status.LastScheduleTime != nil {
+		view.LastScheduleTime = cj.Status.LastScheduleTime.Format(time.RFC3339)
+	}
+	if cj.Status.LastSuccessfulTime != nil {
+		view.LastSuccessfulTime = cj.Status.LastSuccessfulTime.Format(time.RFC3339)
+	}
+
+	schedule, err := parseCronSchedule(cj.Spec.Schedule, timeZone)
+	if err != nil {
+		view.ScheduleError = err.Error()
+		return
+	}
+	if view.Suspend {
+		return
+	}
+	if next := schedule.Next(now); !next.IsZero() {
+		view.NextSchedule = next.Format(time.RFC3339)
+	}
+
+	since := cj.CreationTimestamp.Time
+	if cj.Status.LastScheduleTime != nil {
+		since = cj.Status.LastScheduleTime.Time
+	}
+	if due := schedule.Next(since); !due.IsZero() && now.Sub(due) > cronMissedGrace {
+		view.Missed = true
+		view.MissedSince = due.Format(time.RFC3339)
+	}
+}
@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	from := time.Date(2026, 1, 30, 10, 7, 30, 0, time.UTC) // a Friday
+	tests := []struct {
+		schedule, timeZone string
+		want               string
+	}{
+		{"*/15 * * * *", "", "2026-01-30T10:15:00Z"},
+		{"0 3 * * *", "", "2026-01-31T03:00:00Z"},
+		{"@hourly", "", "2026-01-30T11:00:00Z"},
+		{"30 9 * * mon-fri", "", "2026-02-02T09:30:00Z"},
+		{"0 0 1 * *", "", "2026-02-01T00:00:00Z"},
+		{"0 0 29 2 *", "", "2028-02-29T00:00:00Z"},
+		// Day-of-month or day-of-week when both are restricted
+		{"0 12 15 * 0", "", "2026-02-01T12:00:00Z"},
+		{"0 9 * * *", "America/New_York", "2026-01-30T09:00:00-05:00"},
+		{"CRON_TZ=Asia/Tokyo 0 9 * * *", "", "2026-01-31T09:00:00+09:00"},
+	}
+	for _, tt := range tests {
+		s, err := parseCronSchedule(tt.schedule, tt.timeZone)
+		if err != nil {
+			t.Fatalf("parseCronSchedule(%q) failed: %v", tt.schedule, err)
+		}
+		if got := s.Next(from).Format(time.RFC3339); got != tt.want {
+			t.Errorf("Next(%q) = %s; expected %s", tt.schedule, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"* * * *", "61 * * * *", "*/0 * * * *", "0 0 * * funday"} {
+		if _, err := parseCronSchedule(bad, ""); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+	if _, err := parseCronSchedule("0 * * * *", "Mars/Olympus"); err == nil {
+		t.Error("Expected an unknown time zone to be rejected")
+	}
+	if s, _ := parseCronSchedule("0 0 30 2 *", ""); !s.Next(from).IsZero() {
+		t.Error("Expected a schedule that never fires to have no next run")
+	}
+}
+
+func TestGetCronJobsHistoryAndMissed(t *testing.T) {
+	now := time.Now()
+	cronJob := func(name, uid, schedule string, lastSchedule time.Time) *batchv1.CronJob {
+		return &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid), CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour))},
+			Spec:       batchv1.CronJobSpec{Schedule: schedule},
+			Status:     batchv1.CronJobStatus{LastScheduleTime: &metav1.Time{Time: lastSchedule}},
+		}
+	}
+	job := func(name, owner string, age time.Duration, condition batchv1.JobConditionType) *batchv1.Job {
+		start := metav1.NewTime(now.Add(-age))
+		j := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default", CreationTimestamp: start,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "backup", UID: types.UID(owner)}},
+			},
+			Status: batchv1.JobStatus{StartTime: &start},
+		}
+		if condition != "" {
+			done := metav1.NewTime(start.Add(90 * time.Second))
+			j.Status.CompletionTime = &done
+			j.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+		}
+		return j
+	}
+
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(
+		// Hourly, but the last run was scheduled three hours ago
+		cronJob("backup", "backup-uid", "0 * * * *", now.Add(-3*time.Hour)),
+		cronJob("report", "report-uid", "* * * * *", now.Add(-30*time.Second)),
+		job("backup-1", "backup-uid", 5*time.Hour, batchv1.JobComplete),
+		job("backup-2", "backup-uid", 4*time.Hour, batchv1.JobFailed),
+		job("backup-3", "backup-uid", 3*time.Hour, ""),
+	))
+
+	cronJobs, err := m.GetCronJobs(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("GetCronJobs failed: %v", err)
+	}
+	byName := map[string]CronJob{}
+	for _, cj := range cronJobs {
+		byName[cj.Name] = cj
+	}
+
+	backup := byName["backup"]
+	if !backup.Missed || backup.MissedSince == "" || backup.NextSchedule == "" {
+		t.Errorf("Expected backup to have missed a run, got %+v", backup)
+	}
+	if len(backup.RecentRuns) != 3 || backup.RecentRuns[0].Status != "Running" || backup.RecentRuns[1].Status != "Failed" {
+		t.Fatalf("Expected 3 runs newest first, got %+v", backup.RecentRuns)
+	}
+	if backup.RecentRuns[2].Status != "Succeeded" || backup.RecentRuns[2].Duration != "1m" {
+		t.Errorf("Expected a succeeded run lasting 1m, got %+v", backup.RecentRuns[2])
+	}
+
+	if report := byName["report"]; report.Missed || len(report.RecentRuns) != 0 {
+		t.Errorf("Expected report to be on schedule without runs, got %+v", report)
+	}
+}
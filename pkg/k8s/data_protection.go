@@ -0,0 +1,305 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	volumeSnapshotGVR        = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshots"}
+	volumeSnapshotContentGVR = schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshotcontents"}
+	veleroBackupGVR          = schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "backups"}
+	veleroRestoreGVR         = schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "restores"}
+	veleroScheduleGVR        = schema.GroupVersionResource{Group: "velero.io", Version: "v1", Resource: "schedules"}
+)
+
+// veleroScheduleLabel names the Schedule that created a Backup
+const veleroScheduleLabel = "velero.io/schedule-name"
+
+// VolumeSnapshot represents a CSI VolumeSnapshot of a PVC
+type VolumeSnapshot struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	Cluster       string `json:"cluster,omitempty"`
+	SourcePVC     string `json:"sourcePVC,omitempty"`
+	SnapshotClass string `json:"snapshotClass,omitempty"`
+	Content       string `json:"content,omitempty"` // bound VolumeSnapshotContent
+	ReadyToUse    bool   `json:"readyToUse"`
+	RestoreSize   string `json:"restoreSize,omitempty"`
+	CreatedAt     string `json:"createdAt,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Age           string `json:"age,omitempty"`
+}
+
+// VolumeSnapshotContent represents the storage-side snapshot bound to a VolumeSnapshot
+type VolumeSnapshotContent struct {
+	Name             string `json:"name"`
+	Cluster          string `json:"cluster,omitempty"`
+	Driver           string `json:"driver"`
+	DeletionPolicy   string `json:"deletionPolicy"`
+	VolumeSnapshot   string `json:"volumeSnapshot,omitempty"` // namespace/name
+	SnapshotHandle   string `json:"snapshotHandle,omitempty"`
+	ReadyToUse       bool   `json:"readyToUse"`
+	RestoreSizeBytes int64  `json:"restoreSizeBytes,omitempty"`
+	Age              string `json:"age,omitempty"`
+}
+
+// VeleroBackup represents a Velero Backup
+type VeleroBackup struct {
+	Name               string   `json:"name"`
+	Namespace          string   `json:"namespace"`
+	Cluster            string   `json:"cluster,omitempty"`
+	Phase              string   `json:"phase"`
+	Schedule           string   `json:"schedule,omitempty"`
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"` // empty means all
+	StorageLocation    string   `json:"storageLocation,omitempty"`
+	StartedAt          string   `json:"startedAt,omitempty"`
+	CompletedAt        string   `json:"completedAt,omitempty"`
+	Expiration         string   `json:"expiration,omitempty"`
+	Errors             int64    `json:"errors,omitempty"`
+	Warnings           int64    `json:"warnings,omitempty"`
+}
+
+// VeleroRestore represents a Velero Restore
+type VeleroRestore struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Cluster     string `json:"cluster,omitempty"`
+	Phase       string `json:"phase"`
+	Backup      string `json:"backup,omitempty"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	CompletedAt string `json:"completedAt,omitempty"`
+	Errors      int64  `json:"errors,omitempty"`
+	Warnings    int64  `json:"warnings,omitempty"`
+}
+
+// VeleroSchedule represents a Velero Schedule with the outcome of its backups
+type VeleroSchedule struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Cluster     string `json:"cluster,omitempty"`
+	Schedule    string `json:"schedule"` // cron expression
+	Paused      bool   `json:"paused"`
+	Phase       string `json:"phase,omitempty"`
+	LastBackup  string `json:"lastBackup,omitempty"`  // when the schedule last created a backup
+	LastSuccess string `json:"lastSuccess,omitempty"` // completion of its latest Completed backup
+}
+
+// DataProtection is a cluster's snapshot and backup state. Sections are
+// empty when the snapshot or Velero CRDs are not installed.
+type DataProtection struct {
+	Cluster          string                  `json:"cluster"`
+	SnapshotsEnabled bool                    `json:"snapshotsEnabled"`
+	VeleroInstalled  bool                    `json:"veleroInstalled"`
+	Snapshots        []VolumeSnapshot        `json:"snapshots"`
+	SnapshotContents []VolumeSnapshotContent `json:"snapshotContents"`
+	Backups          []VeleroBackup          `json:"backups"`
+	Restores         []VeleroRestore         `json:"restores"`
+	Schedules        []VeleroSchedule        `json:"schedules"`
+}
+
+// GetDataProtection lists VolumeSnapshots, VolumeSnapshotContents and, when
+// Velero is installed, its Backups, Restores and Schedules. With a namespace,
+// snapshots are limited to it and backups to those that include it. Backups
+// and restores are listed newest first.
+func (m *MultiClusterClient) GetDataProtection(ctx context.Context, contextName, namespace string) (*DataProtection, error) {
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	dp := &DataProtection{
+		Cluster:          contextName,
+		Snapshots:        []VolumeSnapshot{},
+		SnapshotContents: []VolumeSnapshotContent{},
+		Backups:          []VeleroBackup{},
+		Restores:         []VeleroRestore{},
+		Schedules:        []VeleroSchedule{},
+	}
+
+	// list returns nil without an error when the CRD is not installed
+	list := func(gvr schema.GroupVersionResource, ns string) (*unstructured.UnstructuredList, error) {
+		l, err := dynamicClient.Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return l, err
+	}
+
+	snapshots, err := list(volumeSnapshotGVR, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if snapshots != nil {
+		dp.SnapshotsEnabled = true
+		for i := range snapshots.Items {
+			dp.Snapshots = append(dp.Snapshots, parseVolumeSnapshot(&snapshots.Items[i], contextName))
+		}
+		contents, err := list(volumeSnapshotContentGVR, "")
+		if err != nil {
+			return nil, err
+		}
+		if contents != nil {
+			for i := range contents.Items {
+				content := parseVolumeSnapshotContent(&contents.Items[i], contextName)
+				ref, _, _ := unstructured.NestedString(contents.Items[i].Object, "spec", "volumeSnapshotRef", "namespace")
+				if namespace == "" || ref == namespace {
+					dp.SnapshotContents = append(dp.SnapshotContents, content)
+				}
+			}
+		}
+	}
+
+	backups, err := list(veleroBackupGVR, "")
+	if err != nil {
+		return nil, err
+	}
+	if backups == nil {
+		return dp, nil
+	}
+	dp.VeleroInstalled = true
+	for i := range backups.Items {
+		backup := parseVeleroBackup(&backups.Items[i], contextName)
+		if namespace == "" || len(backup.IncludedNamespaces) == 0 || slices.Contains(backup.IncludedNamespaces, "*") || slices.Contains(backup.IncludedNamespaces, namespace) {
+			dp.Backups = append(dp.Backups, backup)
+		}
+	}
+	sort.Slice(dp.Backups, func(i, j int) bool {
+		return dp.Backups[i].StartedAt > dp.Backups[j].StartedAt
+	})
+
+	restores, err := list(veleroRestoreGVR, "")
+	if err != nil {
+		return nil, err
+	}
+	if restores != nil {
+		for i := range restores.Items {
+			dp.Restores = append(dp.Restores, parseVeleroRestore(&restores.Items[i], contextName))
+		}
+		sort.Slice(dp.Restores, func(i, j int) bool {
+			return dp.Restores[i].StartedAt > dp.Restores[j].StartedAt
+		})
+	}
+
+	schedules, err := list(veleroScheduleGVR, "")
+	if err != nil {
+		return nil, err
+	}
+	if schedules != nil {
+		for i := range schedules.Items {
+			schedule := parseVeleroSchedule(&schedules.Items[i], contextName)
+			// Timestamps are RFC 3339 in UTC, so they compare as strings
+			for j := range backups.Items {
+				backup := &backups.Items[j]
+				if backup.GetLabels()[veleroScheduleLabel] != schedule.Name || backup.GetNamespace() != schedule.Namespace {
+					continue
+				}
+				phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+				completed, _, _ := unstructured.NestedString(backup.Object, "status", "completionTimestamp")
+				if phase == "Completed" && completed > schedule.LastSuccess {
+					schedule.LastSuccess = completed
+				}
+			}
+			dp.Schedules = append(dp.Schedules, schedule)
+		}
+	}
+	return dp, nil
+}
+
+// parseVolumeSnapshot parses a snapshot.storage.k8s.io VolumeSnapshot
+func parseVolumeSnapshot(item *unstructured.Unstructured, contextName string) VolumeSnapshot {
+	s := VolumeSnapshot{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   contextName,
+		Age:       formatAge(item.GetCreationTimestamp().Time),
+	}
+	s.SourcePVC, _, _ = unstructured.NestedString(item.Object, "spec", "source", "persistentVolumeClaimName")
+	s.SnapshotClass, _, _ = unstructured.NestedString(item.Object, "spec", "volumeSnapshotClassName")
+	s.Content, _, _ = unstructured.NestedString(item.Object, "status", "boundVolumeSnapshotContentName")
+	s.ReadyToUse, _, _ = unstructured.NestedBool(item.Object, "status", "readyToUse")
+	s.RestoreSize, _, _ = unstructured.NestedString(item.Object, "status", "restoreSize")
+	s.CreatedAt, _, _ = unstructured.NestedString(item.Object, "status", "creationTime")
+	s.Error, _, _ = unstructured.NestedString(item.Object, "status", "error", "message")
+	return s
+}
+
+// parseVolumeSnapshotContent parses a snapshot.storage.k8s.io VolumeSnapshotContent
+func parseVolumeSnapshotContent(item *unstructured.Unstructured, contextName string) VolumeSnapshotContent {
+	c := VolumeSnapshotContent{
+		Name:    item.GetName(),
+		Cluster: contextName,
+		Age:     formatAge(item.GetCreationTimestamp().Time),
+	}
+	c.Driver, _, _ = unstructured.NestedString(item.Object, "spec", "driver")
+	c.DeletionPolicy, _, _ = unstructured.NestedString(item.Object, "spec", "deletionPolicy")
+	refNamespace, _, _ := unstructured.NestedString(item.Object, "spec", "volumeSnapshotRef", "namespace")
+	refName, _, _ := unstructured.NestedString(item.Object, "spec", "volumeSnapshotRef", "name")
+	if refName != "" {
+		c.VolumeSnapshot = refNamespace + "/" + refName
+	}
+	c.SnapshotHandle, _, _ = unstructured.NestedString(item.Object, "status", "snapshotHandle")
+	c.ReadyToUse, _, _ = unstructured.NestedBool(item.Object, "status", "readyToUse")
+	c.RestoreSizeBytes, _, _ = unstructured.NestedInt64(item.Object, "status", "restoreSize")
+	return c
+}
+
+// parseVeleroBackup parses a velero.io Backup
+func parseVeleroBackup(item *unstructured.Unstructured, contextName string) VeleroBackup {
+	b := VeleroBackup{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   contextName,
+		Schedule:  item.GetLabels()[veleroScheduleLabel],
+	}
+	b.IncludedNamespaces, _, _ = unstructured.NestedStringSlice(item.Object, "spec", "includedNamespaces")
+	b.StorageLocation, _, _ = unstructured.NestedString(item.Object, "spec", "storageLocation")
+	b.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+	b.StartedAt, _, _ = unstructured.NestedString(item.Object, "status", "startTimestamp")
+	b.CompletedAt, _, _ = unstructured.NestedString(item.Object, "status", "completionTimestamp")
+	b.Expiration, _, _ = unstructured.NestedString(item.Object, "status", "expiration")
+	b.Errors, _, _ = unstructured.NestedInt64(item.Object, "status", "errors")
+	b.Warnings, _, _ = unstructured.NestedInt64(item.Object, "status", "warnings")
+	if b.Phase == "" {
+		b.Phase = "New"
+	}
+	return b
+}
+
+// parseVeleroRestore parses a velero.io Restore
+func parseVeleroRestore(item *unstructured.Unstructured, contextName string) VeleroRestore {
+	r := VeleroRestore{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   contextName,
+	}
+	r.Backup, _, _ = unstructured.NestedString(item.Object, "spec", "backupName")
+	r.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+	r.StartedAt, _, _ = unstructured.NestedString(item.Object, "status", "startTimestamp")
+	r.CompletedAt, _, _ = unstructured.NestedString(item.Object, "status", "completionTimestamp")
+	r.Errors, _, _ = unstructured.NestedInt64(item.Object, "status", "errors")
+	r.Warnings, _, _ = unstructured.NestedInt64(item.Object, "status", "warnings")
+	if r.Phase == "" {
+		r.Phase = "New"
+	}
+	return r
+}
+
+// parseVeleroSchedule parses a velero.io Schedule
+func parseVeleroSchedule(item *unstructured.Unstructured, contextName string) VeleroSchedule {
+	s := VeleroSchedule{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   contextName,
+	}
+	s.Schedule, _, _ = unstructured.NestedString(item.Object, "spec", "schedule")
+	s.Paused, _, _ = unstructured.NestedBool(item.Object, "spec", "paused")
+	s.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+	s.LastBackup, _, _ = unstructured.NestedString(item.Object, "status", "lastBackup")
+	return s
+}
@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetDataProtection(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	listKinds := map[schema.GroupVersionResource]string{
+		volumeSnapshotGVR:        "VolumeSnapshotList",
+		volumeSnapshotContentGVR: "VolumeSnapshotContentList",
+		veleroBackupGVR:          "BackupList",
+		veleroRestoreGVR:         "RestoreList",
+		veleroScheduleGVR:        "ScheduleList",
+	}
+
+	obj := func(apiVersion, kind, namespace, name string, labels map[string]interface{}, spec, status map[string]interface{}) *unstructured.Unstructured {
+		meta := map[string]interface{}{"name": name}
+		if namespace != "" {
+			meta["namespace"] = namespace
+		}
+		if labels != nil {
+			meta["labels"] = labels
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion, "kind": kind, "metadata": meta, "spec": spec, "status": status,
+		}}
+	}
+	snapshot := obj("snapshot.storage.k8s.io/v1", "VolumeSnapshot", "db", "data-snap", nil,
+		map[string]interface{}{"source": map[string]interface{}{"persistentVolumeClaimName": "data"}, "volumeSnapshotClassName": "csi-snap"},
+		map[string]interface{}{"boundVolumeSnapshotContentName": "snapcontent-1", "readyToUse": true, "restoreSize": "10Gi"})
+	content := obj("snapshot.storage.k8s.io/v1", "VolumeSnapshotContent", "", "snapcontent-1", nil,
+		map[string]interface{}{"driver": "ebs.csi.aws.com", "deletionPolicy": "Delete", "volumeSnapshotRef": map[string]interface{}{"name": "data-snap", "namespace": "db"}},
+		map[string]interface{}{"readyToUse": true, "restoreSize": int64(10737418240)})
+	backup := func(name, phase, started, completed string, namespaces ...interface{}) *unstructured.Unstructured {
+		return obj("velero.io/v1", "Backup", "velero", name, map[string]interface{}{veleroScheduleLabel: "nightly"},
+			map[string]interface{}{"includedNamespaces": namespaces},
+			map[string]interface{}{"phase": phase, "startTimestamp": started, "completionTimestamp": completed})
+	}
+	schedule := obj("velero.io/v1", "Schedule", "velero", "nightly", nil,
+		map[string]interface{}{"schedule": "0 2 * * *"},
+		map[string]interface{}{"phase": "Enabled", "lastBackup": "2026-10-03T02:00:00Z"})
+	// A manual backup, not created by the schedule
+	webOnly := backup("web-only", "Completed", "2026-10-03T03:00:00Z", "2026-10-03T03:10:00Z", "web")
+	unstructured.RemoveNestedField(webOnly.Object, "metadata", "labels")
+	restore := obj("velero.io/v1", "Restore", "velero", "restore-1", nil,
+		map[string]interface{}{"backupName": "nightly-1"},
+		map[string]interface{}{"phase": "Completed"})
+
+	m.dynamicClients["c1"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		snapshot, content, schedule, restore,
+		backup("nightly-1", "Completed", "2026-10-01T02:00:00Z", "2026-10-01T02:10:00Z", "db"),
+		backup("nightly-2", "Completed", "2026-10-02T02:00:00Z", "2026-10-02T02:10:00Z", "db"),
+		backup("nightly-3", "PartiallyFailed", "2026-10-03T02:00:00Z", "2026-10-03T02:10:00Z", "db"),
+		webOnly,
+	)
+
+	dp, err := m.GetDataProtection(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("GetDataProtection failed: %v", err)
+	}
+	if !dp.SnapshotsEnabled || !dp.VeleroInstalled {
+		t.Errorf("Expected snapshots and Velero to be detected, got %+v", dp)
+	}
+	if len(dp.Snapshots) != 1 || dp.Snapshots[0].SourcePVC != "data" || !dp.Snapshots[0].ReadyToUse || dp.Snapshots[0].Content != "snapcontent-1" {
+		t.Errorf("Unexpected snapshots: %+v", dp.Snapshots)
+	}
+	if len(dp.SnapshotContents) != 1 || dp.SnapshotContents[0].VolumeSnapshot != "db/data-snap" || dp.SnapshotContents[0].RestoreSizeBytes != 10737418240 {
+		t.Errorf("Unexpected snapshot contents: %+v", dp.SnapshotContents)
+	}
+	if len(dp.Backups) != 4 || dp.Backups[0].Name != "web-only" {
+		t.Errorf("Expected backups newest first, got %+v", dp.Backups)
+	}
+	if len(dp.Schedules) != 1 || dp.Schedules[0].LastSuccess != "2026-10-02T02:10:00Z" || dp.Schedules[0].LastBackup != "2026-10-03T02:00:00Z" {
+		t.Errorf("Expected last success to skip the partially failed backup, got %+v", dp.Schedules)
+	}
+	if len(dp.Restores) != 1 || dp.Restores[0].Backup != "nightly-1" {
+		t.Errorf("Unexpected restores: %+v", dp.Restores)
+	}
+
+	dp, _ = m.GetDataProtection(context.Background(), "c1", "web")
+	if len(dp.Snapshots) != 0 || len(dp.SnapshotContents) != 0 || len(dp.Backups) != 1 || dp.Backups[0].Name != "web-only" {
+		t.Errorf("Expected namespace filter to keep only the web backup, got %+v", dp)
+	}
+
+	// Clusters without the CRDs report them as not installed rather than failing
+	fakeC := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	fakeC.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), "")
+	})
+	m.dynamicClients["c2"] = fakeC
+	dp, err = m.GetDataProtection(context.Background(), "c2", "")
+	if err != nil || dp.SnapshotsEnabled || dp.VeleroInstalled || dp.Backups == nil {
+		t.Errorf("Expected empty report without CRDs, got %+v, %v", dp, err)
+	}
+}
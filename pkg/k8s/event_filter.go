@@ -0,0 +1,115 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// EventFilter selects events with a small expression language: space-separated
+// terms that must all match, each "field=values" or "field!=values". Fields are
+// cluster, namespace (or ns), reason and kind (of the involved object); values
+// are comma-separated alternatives and may use * as a wildcard. Kinds compare
+// case-insensitively. For example:
+//
+//	cluster=prod-* namespace!=kube-system,openshift-* reason=BackOff,FailedScheduling kind=Pod
+type EventFilter struct {
+	terms []eventFilterTerm
+}
+
+type eventFilterTerm struct {
+	field  string
+	negate bool
+	values *regexp.Regexp
+}
+
+// eventFilterFields maps the accepted field names to the canonical one
+var eventFilterFields = map[string]string{
+	"cluster":   "cluster",
+	"namespace": "namespace",
+	"ns":        "namespace",
+	"reason":    "reason",
+	"kind":      "kind",
+}
+
+// ParseEventFilter parses a filter expression; an empty expression matches everything
+func ParseEventFilter(expr string) (*EventFilter, error) {
+	f := &EventFilter{}
+	for _, raw := range strings.Fields(expr) {
+		field, values, negate, ok := splitFilterTerm(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid filter term %q (expected field=value or field!=value)", raw)
+		}
+		canonical, known := eventFilterFields[strings.ToLower(field)]
+		if !known {
+			return nil, fmt.Errorf("unknown filter field %q (expected cluster, namespace, reason or kind)", field)
+		}
+		var alternatives []string
+		for _, v := range strings.Split(values, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				alternatives = append(alternatives, strings.ReplaceAll(regexp.QuoteMeta(v), `\*`, ".*"))
+			}
+		}
+		if len(alternatives) == 0 {
+			return nil, fmt.Errorf("filter term %q has no value", raw)
+		}
+		pattern := "^(?:" + strings.Join(alternatives, "|") + ")$"
+		if canonical == "kind" {
+			pattern = "(?i)" + pattern
+		}
+		f.terms = append(f.terms, eventFilterTerm{field: canonical, negate: negate, values: regexp.MustCompile(pattern)})
+	}
+	return f, nil
+}
+
+func splitFilterTerm(raw string) (field, values string, negate, ok bool) {
+	if i := strings.Index(raw, "!="); i > 0 {
+		return raw[:i], raw[i+2:], true, true
+	}
+	if i := strings.Index(raw, "="); i > 0 {
+		return raw[:i], raw[i+1:], false, true
+	}
+	return "", "", false, false
+}
+
+// Matches reports whether an event passes every term
+func (f *EventFilter) Matches(e Event) bool {
+	if f == nil {
+		return true
+	}
+	for _, t := range f.terms {
+		if t.values.MatchString(eventField(e, t.field)) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesCluster reports whether events of a cluster can pass the cluster
+// terms, so clusters filtered out are not watched at all
+func (f *EventFilter) MatchesCluster(name string) bool {
+	if f == nil {
+		return true
+	}
+	for _, t := range f.terms {
+		if t.field == "cluster" && t.values.MatchString(name) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func eventField(e Event, field string) string {
+	switch field {
+	case "cluster":
+		return e.Cluster
+	case "namespace":
+		return e.Namespace
+	case "reason":
+		return e.Reason
+	case "kind":
+		kind, _, _ := strings.Cut(e.Object, "/")
+		return kind
+	}
+	return ""
+}
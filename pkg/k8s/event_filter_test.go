@@ -0,0 +1,31 @@
+package k8s
+
+import "testing"
+
+func TestEventFilter(t *testing.T) {
+	backoff := Event{Cluster: "prod-east", Namespace: "shop", Reason: "BackOff", Object: "Pod/api-1"}
+	system := Event{Cluster: "prod-west", Namespace: "kube-system", Reason: "FailedScheduling", Object: "Pod/dns"}
+	staging := Event{Cluster: "staging", Namespace: "shop", Reason: "BackOff", Object: "Deployment/api"}
+
+	f, err := ParseEventFilter("cluster=prod-* ns!=kube-system,openshift-* reason=BackOff,FailedScheduling kind=pod")
+	if err != nil {
+		t.Fatalf("ParseEventFilter failed: %v", err)
+	}
+	if !f.Matches(backoff) || f.Matches(system) || f.Matches(staging) {
+		t.Error("Expected only the prod BackOff outside kube-system to match")
+	}
+	if !f.MatchesCluster("prod-east") || f.MatchesCluster("staging") {
+		t.Error("Expected cluster terms to select clusters to watch")
+	}
+
+	all, _ := ParseEventFilter("  ")
+	if !all.Matches(staging) || !(*EventFilter)(nil).Matches(staging) {
+		t.Error("Expected an empty filter to match everything")
+	}
+
+	for _, bad := range []string{"cluster", "owner=api", "reason=", "=BackOff"} {
+		if _, err := ParseEventFilter(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+)
+
+// maxOwnerDepth bounds owner chain walks, e.g. Pod -> Job -> CronJob
+const maxOwnerDepth = 4
+
+// ErrUnsupportedWorkloadKind is returned for kinds events cannot be rolled up to
+var ErrUnsupportedWorkloadKind = errors.New("unsupported workload kind")
+
+// ownerKindResources are the kinds ownerResolver lists, by their resource
+var ownerKindResources = map[string]schema.GroupVersionResource{
+	"Pod":        {Version: "v1", Resource: "pods"},
+	"ReplicaSet": {Group: "apps", Version: "v1", Resource: "replicasets"},
+	"Job":        {Group: "batch", Version: "v1", Resource: "jobs"},
+}
+
+// ownerResolver walks controller owner references of namespaced objects. Pods,
+// ReplicaSets and Jobs are listed lazily, once per namespace, the first time an
+// object of that kind needs resolving; lookups that fail leave objects unowned.
+// Only owner references are read, so they are listed as metadata when a
+// metadata client is available.
+type ownerResolver struct {
+	client kubernetes.Interface
+	meta   metadata.Interface // nil lists full objects through client
+	// owners maps namespace/Kind/name to the controller's Kind/name
+	owners map[string]string
+	loaded map[string]bool // namespace/Kind
+}
+
+func newOwnerResolver(client kubernetes.Interface, meta metadata.Interface) *ownerResolver {
+	return &ownerResolver{client: client, meta: meta, owners: make(map[string]string), loaded: make(map[string]bool)}
+}
+
+// ownerResolverFor returns an ownerResolver for a cluster, listing metadata
+// only when the cluster has a metadata client
+func (m *MultiClusterClient) ownerResolverFor(contextName string, client kubernetes.Interface) *ownerResolver {
+	meta, err := m.GetMetadataClient(contextName)
+	if err != nil {
+		meta = nil
+	}
+	return newOwnerResolver(client, meta)
+}
+
+// chain returns the object followed by its controllers, e.g.
+// [Pod/api-7d9-x2, ReplicaSet/api-7d9, Deployment/api]
+func (r *ownerResolver) chain(ctx context.Context, namespace, kind, name string) []string {
+	chain := []string{kind + "/" + name}
+	if namespace == "" {
+		return chain
+	}
+	for i := 0; i < maxOwnerDepth; i++ {
+		r.load(ctx, namespace, kind)
+		owner, ok := r.owners[namespace+"/"+kind+"/"+name]
+		if !ok {
+			break
+		}
+		chain = append(chain, owner)
+		kind, name, _ = strings.Cut(owner, "/")
+	}
+	return chain
+}
+
+// root returns the top-level controller of an object, or "" if it has none
+func (r *ownerResolver) root(ctx context.Context, namespace, kind, name string) string {
+	if chain := r.chain(ctx, namespace, kind, name); len(chain) > 1 {
+		return chain[len(chain)-1]
+	}
+	return ""
+}
+
+func (r *ownerResolver) load(ctx context.Context, namespace, kind string) {
+	key := namespace + "/" + kind
+	if r.loaded[key] {
+		return
+	}
+	r.loaded[key] = true
+	r.record(kind, r.list(ctx, namespace, kind))
+}
+
+// preload lists a kind in namespace, or in all namespaces when it is empty, with
+// one call and marks it loaded for each of namespaces, so rolling up a whole
+// cluster doesn't list per namespace
+func (r *ownerResolver) preload(ctx context.Context, namespace, kind string, namespaces map[string]bool) {
+	r.record(kind, r.list(ctx, namespace, kind))
+	for ns := range namespaces {
+		r.loaded[ns+"/"+kind] = true
+	}
+}
+
+// list returns the objects of a kind that can be owned by a controller
+func (r *ownerResolver) list(ctx context.Context, namespace, kind string) []metav1.Object {
+	var objects []metav1.Object
+	if gvr, ok := ownerKindResources[kind]; ok && r.meta != nil {
+		list, err := r.meta.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+		return objects
+	}
+	switch kind {
+	case "Pod":
+		list, err := r.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	case "ReplicaSet":
+		list, err := r.client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	case "Job":
+		list, err := r.client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil
+		}
+		for i := range list.Items {
+			objects = append(objects, &list.Items[i])
+		}
+	}
+	return objects
+}
+
+// record remembers the controllers of objects of a kind
+func (r *ownerResolver) record(kind string, objects []metav1.Object) {
+	for _, obj := range objects {
+		if ref := metav1.GetControllerOf(obj); ref != nil {
+			r.owners[obj.GetNamespace()+"/"+kind+"/"+obj.GetName()] = ref.Kind + "/" + ref.Name
+		}
+	}
+}
+
+// eventSummary converts an event, rolling it up to its owning workload when resolver is set
+func eventSummary(ctx context.Context, event corev1.Event, contextName string, resolver *ownerResolver) Event {
+	e := Event{
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Message:   event.Message,
+		Object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+		Namespace: event.Namespace,
+		Cluster:   contextName,
+		Count:     event.Count,
+		Age:       formatDuration(time.Since(event.LastTimestamp.Time)),
+	}
+	if !event.FirstTimestamp.IsZero() {
+		e.FirstSeen = event.FirstTimestamp.Time.Format(time.RFC3339)
+	}
+	if !event.LastTimestamp.IsZero() {
+		e.LastSeen = event.LastTimestamp.Time.Format(time.RFC3339)
+	}
+	if resolver != nil {
+		e.Owner = resolver.root(ctx, event.InvolvedObject.Namespace, event.InvolvedObject.Kind, event.InvolvedObject.Name)
+	}
+	return e
+}
+
+// GetWorkloadEvents returns all events relevant to a workload: those on the
+// workload itself, its ReplicaSets or Jobs, and its pods. Repeated events for
+// the same object, reason and message are merged, newest first.
+func (m *MultiClusterClient) GetWorkloadEvents(ctx context.Context, contextName, namespace, kind, name string) ([]Event, error) {
+	kind, err := normalizeWorkloadKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	target := kind + "/" + name
+	resolver := m.ownerResolverFor(contextName, client)
+	merged := make(map[string]*Event)
+	var order []string
+	for _, event := range events.Items {
+		obj := event.InvolvedObject
+		related := false
+		// Resolve in the object's own namespace, so a cluster-wide request
+		// (namespace "") still walks owners
+		for _, link := range resolver.chain(ctx, obj.Namespace, obj.Kind, obj.Name) {
+			if link == target {
+				related = true
+				break
+			}
+		}
+		if !related {
+			continue
+		}
+
+		e := eventSummary(ctx, event, contextName, nil)
+		e.Owner = target
+		key := e.Object + "\x00" + e.Reason + "\x00" + e.Message
+		existing, ok := merged[key]
+		if !ok {
+			merged[key] = &e
+			order = append(order, key)
+			continue
+		}
+		existing.Count += e.Count
+		if e.FirstSeen != "" && (existing.FirstSeen == "" || e.FirstSeen < existing.FirstSeen) {
+			existing.FirstSeen = e.FirstSeen
+		}
+		if e.LastSeen > existing.LastSeen {
+			existing.LastSeen, existing.Age, existing.Type = e.LastSeen, e.Age, e.Type
+		}
+	}
+
+	result := make([]Event, 0, len(order))
+	for _, key := range order {
+		result = append(result, *merged[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastSeen > result[j].LastSeen
+	})
+	return result, nil
+}
+
+// normalizeWorkloadKind accepts workload kinds case-insensitively and in plural form
+func normalizeWorkloadKind(kind string) (string, error) {
+	switch strings.TrimSuffix(strings.ToLower(kind), "s") {
+	case "deployment":
+		return "Deployment", nil
+	case "statefulset":
+		return "StatefulSet", nil
+	case "daemonset":
+		return "DaemonSet", nil
+	case "replicaset":
+		return "ReplicaSet", nil
+	case "job":
+		return "Job", nil
+	case "cronjob":
+		return "CronJob", nil
+	case "pod":
+		return "Pod", nil
+	}
+	return "", fmt.Errorf("%w %q", ErrUnsupportedWorkloadKind, kind)
+}
@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWorkloadEvents(t *testing.T) {
+	controller := true
+	ownedBy := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	now := time.Now()
+	event := func(name, kind, object, reason, message string, count int32, age time.Duration) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object, Namespace: "default"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        message,
+			Count:          count,
+			FirstTimestamp: metav1.NewTime(now.Add(-age - time.Minute)),
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "api-7d9", Namespace: "default", OwnerReferences: ownedBy("Deployment", "api")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-7d9-x2", Namespace: "default", OwnerReferences: ownedBy("ReplicaSet", "api-7d9")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", OwnerReferences: ownedBy("StatefulSet", "web")}},
+		event("e1", "Deployment", "api", "ScalingReplicaSet", "Scaled up replica set api-7d9 to 1", 1, 10*time.Minute),
+		event("e2", "ReplicaSet", "api-7d9", "SuccessfulCreate", "Created pod: api-7d9-x2", 1, 9*time.Minute),
+		event("e3", "Pod", "api-7d9-x2", "BackOff", "Back-off restarting failed container", 4, 2*time.Minute),
+		// The same event recorded twice is merged
+		event("e4", "Pod", "api-7d9-x2", "BackOff", "Back-off restarting failed container", 2, time.Minute),
+		event("e5", "Pod", "web-0", "BackOff", "Back-off restarting failed container", 1, time.Minute),
+	))
+	ctx := context.Background()
+
+	events, err := m.GetWorkloadEvents(ctx, "c1", "default", "deployments", "api")
+	if err != nil {
+		t.Fatalf("GetWorkloadEvents failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 deduplicated events for the deployment, got %+v", events)
+	}
+	if events[0].Object != "Pod/api-7d9-x2" || events[0].Count != 6 || events[0].Owner != "Deployment/api" {
+		t.Errorf("Expected the merged pod event first, got %+v", events[0])
+	}
+	if events[2].Object != "Deployment/api" {
+		t.Errorf("Expected the oldest event last, got %+v", events[2])
+	}
+
+	// Across all namespaces, events still roll up through their own namespace
+	cluster, err := m.GetWorkloadEvents(ctx, "c1", "", "deployments", "api")
+	if err != nil {
+		t.Fatalf("GetWorkloadEvents across namespaces failed: %v", err)
+	}
+	if len(cluster) != 3 || cluster[0].Object != "Pod/api-7d9-x2" || cluster[0].Owner != "Deployment/api" {
+		t.Errorf("Expected the same 3 events across all namespaces, got %+v", cluster)
+	}
+
+	if _, err := m.GetWorkloadEvents(ctx, "c1", "default", "service", "api"); !errors.Is(err, ErrUnsupportedWorkloadKind) {
+		t.Errorf("Expected ErrUnsupportedWorkloadKind, got %v", err)
+	}
+
+	all, err := m.GetEvents(ctx, "c1", "default", 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	owners := map[string]string{}
+	for _, e := range all {
+		owners[e.Object] = e.Owner
+	}
+	if owners["Pod/api-7d9-x2"] != "Deployment/api" || owners["Pod/web-0"] != "StatefulSet/web" || owners["Deployment/api"] != "" {
+		t.Errorf("Expected events to roll up to their workloads, got %v", owners)
+	}
+}
+
+func TestOwnerResolverListsMetadata(t *testing.T) {
+	controller := true
+	ownedBy := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	scheme := runtime.NewScheme()
+	metav1.AddMetaToScheme(scheme)
+	partial := func(apiVersion, kind, name string, owners []metav1.OwnerReference) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, OwnerReferences: owners},
+		}
+	}
+	meta := metadatafake.NewSimpleMetadataClient(scheme,
+		partial("apps/v1", "ReplicaSet", "api-7d9", ownedBy("Deployment", "api")),
+		partial("v1", "Pod", "api-7d9-x2", ownedBy("ReplicaSet", "api-7d9")),
+	)
+	// Owner references are all the resolver needs, so full objects are never listed
+	typed := k8sfake.NewSimpleClientset()
+	typed.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		t.Errorf("Unexpected full list of %s", action.GetResource().Resource)
+		return true, nil, errors.New("unexpected full list")
+	})
+
+	r := newOwnerResolver(typed, meta)
+	if root := r.root(context.Background(), "default", "Pod", "api-7d9-x2"); root != "Deployment/api" {
+		t.Errorf("Expected Deployment/api, got %q", root)
+	}
+}
@@ -0,0 +1,229 @@
+package k8s
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// fleetEventsRescan is how often the fleet stream looks for clusters that
+	// came online or whose watch ended
+	fleetEventsRescan    = time.Minute
+	warningFieldSelector = "type=Warning"
+)
+
+// RecentFleetWarnings returns up to limit of the newest warning events of all
+// healthy clusters that pass filter, oldest first, as the backlog of a stream
+func (m *MultiClusterClient) RecentFleetWarnings(ctx context.Context, filter *EventFilter, limit int) ([]Event, error) {
+	clusters, _, err := m.HealthyClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var all []Event
+	for _, cl := range clusters {
+		if !filter.MatchesCluster(cl.Name) {
+			continue
+		}
+		wg.Add(1)
+		go func(cl ClusterInfo) {
+			defer wg.Done()
+			events, err := m.GetWarningEvents(ctx, cl.Context, "", limit)
+			if err != nil {
+				log.Printf("[FleetEvents] %s: %v", cl.Name, err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, e := range events {
+				if e.Cluster = cl.Name; filter.Matches(e) {
+					all = append(all, e)
+				}
+			}
+		}(cl)
+	}
+	wg.Wait()
+
+	// RFC3339 timestamps sort chronologically as strings
+	sort.SliceStable(all, func(i, j int) bool { return all[i].LastSeen < all[j].LastSeen })
+	if limit > 0 && len(all) > limit {
+		all = all[len(all)-limit:]
+	}
+	return all, nil
+}
+
+// StreamFleetWarnings watches warning events on every healthy cluster and calls
+// emit for each new or repeated warning that passes filter, until ctx ends.
+// Calls to emit are serialized. Clusters that come online, or whose watch
+// failed, are picked up on the next rescan.
+func (m *MultiClusterClient) StreamFleetWarnings(ctx context.Context, filter *EventFilter, emit func(Event)) {
+	var emitMu sync.Mutex
+	var watchingMu sync.Mutex
+	watching := make(map[string]bool)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scan := func() {
+		clusters, _, err := m.HealthyClusters(ctx)
+		if err != nil {
+			log.Printf("[FleetEvents] failed to list clusters: %v", err)
+			return
+		}
+		for _, cl := range clusters {
+			watchingMu.Lock()
+			skip := watching[cl.Context] || !filter.MatchesCluster(cl.Name)
+			if !skip {
+				watching[cl.Context] = true
+			}
+			watchingMu.Unlock()
+			if skip {
+				continue
+			}
+			wg.Add(1)
+			go func(cl ClusterInfo) {
+				defer wg.Done()
+				err := m.WatchWarningEvents(ctx, cl.Context, func(e Event) {
+					if e.Cluster = cl.Name; filter.Matches(e) {
+						emitMu.Lock()
+						emit(e)
+						emitMu.Unlock()
+					}
+				})
+				if err != nil && ctx.Err() == nil {
+					log.Printf("[FleetEvents] %s: watch ended: %v", cl.Name, err)
+				}
+				watchingMu.Lock()
+				delete(watching, cl.Context)
+				watchingMu.Unlock()
+			}(cl)
+		}
+	}
+
+	scan()
+	ticker := time.NewTicker(fleetEventsRescan)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			scan()
+		}
+	}
+}
+
+// WatchWarningEvents calls emit for each warning event created or repeated on a
+// cluster from now on, until ctx ends or the watch fails. Under a namespace
+// scope each scoped namespace is watched.
+func (m *MultiClusterClient) WatchWarningEvents(ctx context.Context, contextName string, emit func(Event)) error {
+	namespaces, err := m.scopeNamespaces(ctx, contextName, "")
+	if err != nil {
+		return err
+	}
+	if namespaces == nil {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make(chan error, len(namespaces))
+	for _, ns := range namespaces {
+		go func(ns string) {
+			errs <- watchNamespaceWarnings(ctx, client, m.ownerResolverFor(contextName, client), contextName, ns, emit)
+		}(ns)
+	}
+	// One failed namespace ends the whole watch so the rescan restarts it
+	var first error
+	for range namespaces {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	return first
+}
+
+// watchNamespaceWarnings watches warning events of one namespace from the
+// current resource version, resuming when the API server closes the watch and
+// starting over from now when the watch fails, e.g. on an expired resource version
+func watchNamespaceWarnings(ctx context.Context, client kubernetes.Interface, owners *ownerResolver, contextName, namespace string, emit func(Event)) error {
+	events := client.CoreV1().Events(namespace)
+	current := func() (string, error) {
+		list, err := events.List(ctx, metav1.ListOptions{FieldSelector: warningFieldSelector, Limit: 1})
+		if err != nil {
+			return "", err
+		}
+		return list.ResourceVersion, nil
+	}
+	resourceVersion, err := current()
+	if err != nil {
+		return err
+	}
+
+	for {
+		w, err := events.Watch(ctx, metav1.ListOptions{FieldSelector: warningFieldSelector, ResourceVersion: resourceVersion, AllowWatchBookmarks: true})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		failed, done := followWarnings(ctx, w, &resourceVersion, func(event *corev1.Event) {
+			emit(eventSummary(ctx, *event, contextName, owners))
+		})
+		if done {
+			return nil
+		}
+		if failed {
+			if resourceVersion, err = current(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// followWarnings emits watched warnings until the watch closes. It reports
+// whether the watch failed, e.g. because the resource version expired, so the
+// caller starts over from now, and whether ctx ended.
+func followWarnings(ctx context.Context, w watch.Interface, resourceVersion *string, emit func(*corev1.Event)) (expired, done bool) {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false, true
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return false, ctx.Err() != nil
+			}
+			if ev.Type == watch.Error {
+				if err := apierrors.FromObject(ev.Object); !apierrors.IsResourceExpired(err) && !apierrors.IsGone(err) {
+					log.Printf("[FleetEvents] watch error: %v", err)
+				}
+				return true, false
+			}
+			event, ok := ev.Object.(*corev1.Event)
+			if !ok {
+				continue
+			}
+			*resourceVersion = event.ResourceVersion
+			if (ev.Type == watch.Added || ev.Type == watch.Modified) && event.Type == corev1.EventTypeWarning {
+				emit(event)
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func warningEvent(name, reason string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: name, Namespace: "shop"},
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+}
+
+func TestFleetWarnings(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.rawConfig = &api.Config{
+		Clusters: map[string]*api.Cluster{"a": {Server: "https://a.example.com"}, "b": {Server: "https://b.example.com"}},
+		Contexts: map[string]*api.Context{"c1": {Cluster: "a"}, "c2": {Cluster: "b"}},
+	}
+	now := time.Now()
+	c1 := k8sfake.NewSimpleClientset(warningEvent("old", "BackOff", now.Add(-time.Hour)))
+	c2 := k8sfake.NewSimpleClientset(warningEvent("new", "BackOff", now), warningEvent("skip", "Unhealthy", now))
+	m.clients["c1"] = c1
+	m.clients["c2"] = c2
+
+	filter, _ := ParseEventFilter("reason=BackOff")
+	recent, err := m.RecentFleetWarnings(context.Background(), filter, 10)
+	if err != nil {
+		t.Fatalf("RecentFleetWarnings failed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Cluster != "c1" || recent[1].Cluster != "c2" {
+		t.Fatalf("Expected both clusters' BackOff warnings oldest first, got %+v", recent)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan Event, 10)
+	go m.StreamFleetWarnings(ctx, filter, func(e Event) { got <- e })
+
+	// Wait for both watches before creating events, fake watches don't replay
+	deadline := time.Now().Add(5 * time.Second)
+	for !watched(c1) || !watched(c2) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected every cluster to be watched")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c2.CoreV1().Events("shop").Create(ctx, warningEvent("ignored", "Unhealthy", now), metav1.CreateOptions{})
+	c1.CoreV1().Events("shop").Create(ctx, warningEvent("crash", "BackOff", now), metav1.CreateOptions{})
+
+	select {
+	case e := <-got:
+		if e.Cluster != "c1" || e.Object != "Pod/crash" {
+			t.Errorf("Expected the new c1 BackOff warning, got %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a streamed warning")
+	}
+	select {
+	case e := <-got:
+		t.Errorf("Expected filtered warnings to be dropped, got %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func watched(client *k8sfake.Clientset) bool {
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "watch" {
+			return true
+		}
+	}
+	return false
+}
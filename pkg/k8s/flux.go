@@ -0,0 +1,264 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kubestellar/console/pkg/api/v1alpha1"
+)
+
+// Labels and annotations GitOps tools stamp on the objects they apply
+const (
+	fluxKustomizeNameLabel      = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizeNamespaceLabel = "kustomize.toolkit.fluxcd.io/namespace"
+	fluxHelmNameLabel           = "helm.toolkit.fluxcd.io/name"
+	fluxHelmNamespaceLabel      = "helm.toolkit.fluxcd.io/namespace"
+	argoTrackingAnnotation      = "argocd.argoproj.io/tracking-id"
+	argoInstanceLabel           = "app.kubernetes.io/instance"
+)
+
+// ListFluxResources lists Flux Kustomizations and HelmReleases across all clusters.
+// Clusters without Flux are silently skipped.
+func (m *MultiClusterClient) ListFluxResources(ctx context.Context) (*v1alpha1.FluxResourceList, error) {
+	m.mu.RLock()
+	clusters := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		clusters = append(clusters, name)
+	}
+	m.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	items := make([]v1alpha1.FluxResource, 0)
+
+	for _, clusterName := range clusters {
+		wg.Add(1)
+		go func(cluster string) {
+			defer wg.Done()
+
+			clusterItems, err := m.ListFluxResourcesForCluster(ctx, cluster, "")
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			items = append(items, clusterItems...)
+			mu.Unlock()
+		}(clusterName)
+	}
+
+	wg.Wait()
+
+	return &v1alpha1.FluxResourceList{
+		Items:      items,
+		TotalCount: len(items),
+	}, nil
+}
+
+// ListFluxResourcesForCluster lists Flux Kustomizations and HelmReleases in a
+// specific cluster. Kinds whose CRDs are not installed are skipped.
+func (m *MultiClusterClient) ListFluxResourcesForCluster(ctx context.Context, contextName, namespace string) ([]v1alpha1.FluxResource, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]v1alpha1.FluxResource, error) {
+			return m.ListFluxResourcesForCluster(ctx, contextName, ns)
+		})
+	}
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]v1alpha1.FluxResource, 0)
+	for _, kind := range []struct {
+		gvr  schema.GroupVersionResource
+		kind string
+	}{
+		{v1alpha1.FluxKustomizationGVR, v1alpha1.FluxKindKustomization},
+		{v1alpha1.FluxHelmReleaseGVR, v1alpha1.FluxKindHelmRelease},
+	} {
+		list, err := dynamicClient.Resource(kind.gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue // Flux CRD not installed
+		}
+		for i := range list.Items {
+			items = append(items, parseFluxResource(&list.Items[i], kind.kind, contextName))
+		}
+	}
+	return items, nil
+}
+
+// parseFluxResource parses a Kustomization or HelmRelease
+func parseFluxResource(item *unstructured.Unstructured, kind, contextName string) v1alpha1.FluxResource {
+	res := v1alpha1.FluxResource{
+		Kind:      kind,
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Cluster:   contextName,
+		Ready:     "Unknown",
+	}
+	res.Suspended, _, _ = unstructured.NestedBool(item.Object, "spec", "suspend")
+	res.Revision, _, _ = unstructured.NestedString(item.Object, "status", "lastAppliedRevision")
+
+	if kind == v1alpha1.FluxKindHelmRelease {
+		chart, _, _ := unstructured.NestedString(item.Object, "spec", "chart", "spec", "chart")
+		version, _, _ := unstructured.NestedString(item.Object, "spec", "chart", "spec", "version")
+		res.Source = chart
+		if version != "" {
+			res.Source += "@" + version
+		}
+		if res.Revision == "" {
+			res.Revision, _, _ = unstructured.NestedString(item.Object, "status", "lastAttemptedRevision")
+		}
+	} else {
+		sourceKind, _, _ := unstructured.NestedString(item.Object, "spec", "sourceRef", "kind")
+		sourceName, _, _ := unstructured.NestedString(item.Object, "spec", "sourceRef", "name")
+		if sourceKind != "" {
+			res.Source = sourceKind + "/" + sourceName
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		if status, ok := cond["status"].(string); ok {
+			res.Ready = status
+		}
+		res.Message, _ = cond["message"].(string)
+		if t, ok := cond["lastTransitionTime"].(string); ok {
+			res.LastApplied = parseArgoTimeAgo(t)
+		}
+	}
+
+	entries, _, _ := unstructured.NestedSlice(item.Object, "status", "inventory", "entries")
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := entry["id"].(string)
+		// Inventory IDs are <namespace>_<name>_<group>_<kind>
+		parts := strings.SplitN(id, "_", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		res.Inventory = append(res.Inventory, v1alpha1.GitOpsResource{
+			Namespace: parts[0],
+			Name:      parts[1],
+			Group:     parts[2],
+			Kind:      parts[3],
+		})
+	}
+	return res
+}
+
+// GetGitOpsOwners returns the Argo CD Applications and Flux Kustomizations or
+// HelmReleases that manage a workload, found through the labels and
+// annotations those tools stamp on what they apply
+func (m *MultiClusterClient) GetGitOpsOwners(ctx context.Context, contextName, kind, namespace, name string) ([]v1alpha1.GitOpsOwner, error) {
+	gr, ok := workloadResources[v1alpha1.WorkloadType(kind)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	gvr := schema.GroupVersionResource{Group: gr[0], Version: "v1", Resource: gr[1]}
+	obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	labels := obj.GetLabels()
+	owners := make([]v1alpha1.GitOpsOwner, 0)
+
+	for _, flux := range []struct {
+		gvr                 schema.GroupVersionResource
+		kind, nameLabel, ns string
+	}{
+		{v1alpha1.FluxKustomizationGVR, v1alpha1.FluxKindKustomization, fluxKustomizeNameLabel, fluxKustomizeNamespaceLabel},
+		{v1alpha1.FluxHelmReleaseGVR, v1alpha1.FluxKindHelmRelease, fluxHelmNameLabel, fluxHelmNamespaceLabel},
+	} {
+		ownerName := labels[flux.nameLabel]
+		if ownerName == "" {
+			continue
+		}
+		owner := v1alpha1.GitOpsOwner{
+			Tool:         "flux",
+			Kind:         flux.kind,
+			Name:         ownerName,
+			Namespace:    labels[flux.ns],
+			Cluster:      contextName,
+			SyncStatus:   "Unknown",
+			HealthStatus: "Unknown",
+		}
+		item, err := dynamicClient.Resource(flux.gvr).Namespace(owner.Namespace).Get(ctx, ownerName, metav1.GetOptions{})
+		if err != nil {
+			owner.Message = fmt.Sprintf("%s not readable: %v", flux.kind, err)
+			owners = append(owners, owner)
+			continue
+		}
+		res := parseFluxResource(item, flux.kind, contextName)
+		owner.Message = res.Message
+		switch {
+		case res.Suspended:
+			// A suspended reconciler no longer corrects drift
+			owner.SyncStatus, owner.HealthStatus, owner.Drifted = "Suspended", "Unknown", true
+		case res.Ready == "True":
+			owner.SyncStatus, owner.HealthStatus = "Synced", "Healthy"
+		case res.Ready == "False":
+			owner.SyncStatus, owner.HealthStatus, owner.Drifted = "OutOfSync", "Degraded", true
+		}
+		owners = append(owners, owner)
+	}
+
+	if appName := argoAppName(obj); appName != "" {
+		apps, err := m.ListArgoApplications(ctx)
+		if err == nil {
+			for _, app := range apps.Items {
+				if app.Name != appName {
+					continue
+				}
+				for _, r := range app.Resources {
+					if r.Group != gr[0] || r.Kind != kind || r.Namespace != namespace || r.Name != name {
+						continue
+					}
+					owners = append(owners, v1alpha1.GitOpsOwner{
+						Tool:         "argocd",
+						Kind:         "Application",
+						Name:         app.Name,
+						Namespace:    app.Namespace,
+						Cluster:      app.Cluster,
+						SyncStatus:   r.SyncStatus,
+						HealthStatus: r.HealthStatus,
+						Drifted:      r.SyncStatus == "OutOfSync",
+					})
+				}
+			}
+		}
+	}
+	return owners, nil
+}
+
+// argoAppName returns the Argo CD Application tracking an object, from the
+// tracking-id annotation ("<app>:<group>/<kind>:<namespace>/<name>") or the
+// instance label used by label-based tracking
+func argoAppName(obj *unstructured.Unstructured) string {
+	if id := obj.GetAnnotations()[argoTrackingAnnotation]; id != "" {
+		app, _, _ := strings.Cut(id, ":")
+		// Apps outside the control plane namespace are tracked as <namespace>_<app>
+		if _, name, found := strings.Cut(app, "_"); found {
+			return name
+		}
+		return app
+	}
+	return obj.GetLabels()[argoInstanceLabel]
+}
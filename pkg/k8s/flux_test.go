@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubestellar/console/pkg/api/v1alpha1"
+)
+
+func TestGetGitOpsOwners(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	listKinds := map[schema.GroupVersionResource]string{
+		v1alpha1.FluxKustomizationGVR: "KustomizationList",
+		v1alpha1.FluxHelmReleaseGVR:   "HelmReleaseList",
+		v1alpha1.ArgoApplicationGVR:   "ApplicationList",
+	}
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "podinfo",
+			"namespace": "apps",
+			"labels": map[string]interface{}{
+				fluxKustomizeNameLabel:      "apps",
+				fluxKustomizeNamespaceLabel: "flux-system",
+			},
+			"annotations": map[string]interface{}{
+				argoTrackingAnnotation: "podinfo:apps/Deployment:apps/podinfo",
+			},
+		},
+	}}
+	kustomization := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+		"kind":       "Kustomization",
+		"metadata":   map[string]interface{}{"name": "apps", "namespace": "flux-system"},
+		"spec":       map[string]interface{}{"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system"}},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{
+				"type": "Ready", "status": "False", "message": "kustomize build failed",
+			}},
+			"inventory": map[string]interface{}{"entries": []interface{}{
+				map[string]interface{}{"id": "apps_podinfo_apps_Deployment", "v": "v1"},
+			}},
+		},
+	}}
+	application := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata":   map[string]interface{}{"name": "podinfo", "namespace": "argocd"},
+		"status": map[string]interface{}{
+			"sync":   map[string]interface{}{"status": "OutOfSync"},
+			"health": map[string]interface{}{"status": "Healthy"},
+			"resources": []interface{}{map[string]interface{}{
+				"group": "apps", "kind": "Deployment", "namespace": "apps", "name": "podinfo",
+				"status": "OutOfSync", "health": map[string]interface{}{"status": "Healthy"},
+			}},
+		},
+	}}
+	m.dynamicClients["prod"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		deployment, kustomization, application)
+	// ListArgoApplications walks the clusters with a typed client
+	m.clients["prod"] = k8sfake.NewSimpleClientset()
+
+	flux, err := m.ListFluxResourcesForCluster(context.Background(), "prod", "")
+	if err != nil {
+		t.Fatalf("ListFluxResourcesForCluster failed: %v", err)
+	}
+	if len(flux) != 1 || flux[0].Ready != "False" || flux[0].Source != "GitRepository/flux-system" || len(flux[0].Inventory) != 1 {
+		t.Fatalf("Unexpected Flux resources: %+v", flux)
+	}
+	if inv := flux[0].Inventory[0]; inv.Kind != "Deployment" || inv.Namespace != "apps" || inv.Name != "podinfo" {
+		t.Errorf("Unexpected inventory entry: %+v", inv)
+	}
+
+	owners, err := m.GetGitOpsOwners(context.Background(), "prod", "Deployment", "apps", "podinfo")
+	if err != nil {
+		t.Fatalf("GetGitOpsOwners failed: %v", err)
+	}
+	byTool := make(map[string]v1alpha1.GitOpsOwner)
+	for _, o := range owners {
+		byTool[o.Tool] = o
+	}
+	if o := byTool["flux"]; o.Name != "apps" || !o.Drifted || o.Message != "kustomize build failed" {
+		t.Errorf("Unexpected Flux owner: %+v", o)
+	}
+	if o := byTool["argocd"]; o.Name != "podinfo" || !o.Drifted || o.HealthStatus != "Healthy" || o.Cluster != "prod" {
+		t.Errorf("Unexpected Argo CD owner: %+v", o)
+	}
+}
@@ -49,6 +49,11 @@ func (m *MultiClusterClient) ListGateways(ctx context.Context) (*v1alpha1.Gatewa
 
 // ListGatewaysForCluster lists Gateway resources in a specific cluster
 func (m *MultiClusterClient) ListGatewaysForCluster(ctx context.Context, contextName, namespace string) ([]v1alpha1.Gateway, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]v1alpha1.Gateway, error) {
+			return m.ListGatewaysForCluster(ctx, contextName, ns)
+		})
+	}
 	dynamicClient, err := m.GetDynamicClient(contextName)
 	if err != nil {
 		return nil, err
@@ -173,6 +178,11 @@ func (m *MultiClusterClient) ListHTTPRoutes(ctx context.Context) (*v1alpha1.HTTP
 
 // ListHTTPRoutesForCluster lists HTTPRoute resources in a specific cluster
 func (m *MultiClusterClient) ListHTTPRoutesForCluster(ctx context.Context, contextName, namespace string) ([]v1alpha1.HTTPRoute, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]v1alpha1.HTTPRoute, error) {
+			return m.ListHTTPRoutesForCluster(ctx, contextName, ns)
+		})
+	}
 	dynamicClient, err := m.GetDynamicClient(contextName)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,211 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrInvalidGPUPlacement is returned for placement requests that cannot be evaluated
+var ErrInvalidGPUPlacement = errors.New("invalid GPU placement request")
+
+// acceleratorResourceNames are the extended resources GetGPUNodes counts, in
+// the same order of precedence
+var acceleratorResourceNames = []corev1.ResourceName{
+	"nvidia.com/gpu", "amd.com/gpu", "gpu.intel.com/i915", "google.com/tpu",
+	"habana.ai/gaudi2", "habana.ai/gaudi", "intel.com/gaudi", "intel.com/xpu", "ibm.com/aiu",
+}
+
+// GPUPlacementRequest asks where a namespace can run a job needing GPUs
+type GPUPlacementRequest struct {
+	Namespace string `json:"namespace"`
+	GPUs      int    `json:"gpus"`
+	GPUType   string `json:"gpuType,omitempty"` // case-insensitive substring of the node's GPU type; empty matches any
+}
+
+// GPUPlacement is a candidate node for a GPU job. Fits is set when the node
+// has enough free GPUs and the namespace quota allows the request; Reasons
+// explains any shortfall or contention.
+type GPUPlacement struct {
+	Cluster  string `json:"cluster"`
+	Node     string `json:"node"`
+	GPUType  string `json:"gpuType"`
+	Resource string `json:"resource"` // e.g. nvidia.com/gpu
+	GPUCount int    `json:"gpuCount"`
+	FreeGPUs int    `json:"freeGPUs"`
+	Fits     bool   `json:"fits"`
+	// QuotaRemaining is the namespace's remaining GPU quota in the cluster, nil when unlimited
+	QuotaRemaining *int64 `json:"quotaRemaining,omitempty"`
+	// PendingGPUDemand is GPUs requested by unscheduled pods in the cluster that compete for the same nodes
+	PendingGPUDemand int64    `json:"pendingGPUDemand"`
+	Reasons          []string `json:"reasons,omitempty"`
+}
+
+// Validate checks a placement request
+func (r GPUPlacementRequest) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("%w: namespace is required", ErrInvalidGPUPlacement)
+	}
+	if r.GPUs < 1 {
+		return fmt.Errorf("%w: gpus must be at least 1", ErrInvalidGPUPlacement)
+	}
+	return nil
+}
+
+// SuggestGPUPlacement evaluates every ready, schedulable accelerator node of a
+// cluster matching the requested GPU type against free GPUs, the namespace's
+// ResourceQuota and competing pending pods, best candidates first
+func (m *MultiClusterClient) SuggestGPUPlacement(ctx context.Context, contextName string, req GPUPlacementRequest) ([]GPUPlacement, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	gpuNodes, err := m.GetGPUNodes(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	// GetGPUNodes just refreshed the snapshot, so this is served from cache
+	snap, err := m.getNodePodSnapshot(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	if snap.podsErr != nil {
+		return nil, snap.podsErr
+	}
+
+	var namespaceMissing bool
+	if _, err := client.CoreV1().Namespaces().Get(ctx, req.Namespace, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		namespaceMissing = true
+	} else if err != nil && !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+	// Without permission to read quotas, treat the namespace as unlimited
+	var quotas []corev1.ResourceQuota
+	if list, err := client.CoreV1().ResourceQuotas(req.Namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		quotas = list.Items
+	} else if !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+
+	nodes := make(map[string]*corev1.Node, len(snap.nodes.Items))
+	for i := range snap.nodes.Items {
+		nodes[snap.nodes.Items[i].Name] = &snap.nodes.Items[i]
+	}
+	var pendingDemand int64
+	for i := range snap.pods.Items {
+		pod := &snap.pods.Items[i]
+		if pod.Spec.NodeName == "" && pod.Status.Phase == corev1.PodPending {
+			pendingDemand += podResourceRequests(pod).GPUs
+		}
+	}
+
+	placements := make([]GPUPlacement, 0)
+	for _, gpuNode := range gpuNodes {
+		node := nodes[gpuNode.Name]
+		if node == nil || node.Spec.Unschedulable || !isNodeReady(node) {
+			continue
+		}
+		if req.GPUType != "" && !strings.Contains(strings.ToLower(gpuNode.GPUType), strings.ToLower(req.GPUType)) {
+			continue
+		}
+		resource := nodeAcceleratorResource(node)
+		p := GPUPlacement{
+			Cluster:          contextName,
+			Node:             gpuNode.Name,
+			GPUType:          gpuNode.GPUType,
+			Resource:         string(resource),
+			GPUCount:         gpuNode.GPUCount,
+			FreeGPUs:         max(gpuNode.GPUCount-gpuNode.GPUAllocated, 0),
+			PendingGPUDemand: pendingDemand,
+			Fits:             true,
+			QuotaRemaining:   quotaRemaining(quotas, resource),
+		}
+		if namespaceMissing {
+			p.Fits = false
+			p.Reasons = append(p.Reasons, fmt.Sprintf("namespace %s does not exist in this cluster", req.Namespace))
+		}
+		if p.FreeGPUs < req.GPUs {
+			p.Fits = false
+			p.Reasons = append(p.Reasons, fmt.Sprintf("only %d of %d GPUs free", p.FreeGPUs, p.GPUCount))
+		}
+		if p.QuotaRemaining != nil && *p.QuotaRemaining < int64(req.GPUs) {
+			p.Fits = false
+			p.Reasons = append(p.Reasons, fmt.Sprintf("namespace quota allows %d more %s", *p.QuotaRemaining, resource))
+		}
+		if pendingDemand > 0 {
+			p.Reasons = append(p.Reasons, fmt.Sprintf("%d GPUs requested by pending pods compete for capacity", pendingDemand))
+		}
+		placements = append(placements, p)
+	}
+	RankGPUPlacements(placements, req.GPUs)
+	return placements, nil
+}
+
+// RankGPUPlacements orders candidates: fitting nodes first, then clusters
+// with less pending GPU demand, then the tightest fit so large nodes stay
+// free for large jobs
+func RankGPUPlacements(placements []GPUPlacement, gpus int) {
+	sort.SliceStable(placements, func(i, j int) bool {
+		a, b := placements[i], placements[j]
+		if a.Fits != b.Fits {
+			return a.Fits
+		}
+		if a.PendingGPUDemand != b.PendingGPUDemand {
+			return a.PendingGPUDemand < b.PendingGPUDemand
+		}
+		if a.Fits {
+			if slackA, slackB := a.FreeGPUs-gpus, b.FreeGPUs-gpus; slackA != slackB {
+				return slackA < slackB
+			}
+		} else if a.FreeGPUs != b.FreeGPUs {
+			return a.FreeGPUs > b.FreeGPUs
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.Node < b.Node
+	})
+}
+
+// nodeAcceleratorResource returns the extended resource a node's accelerators
+// are allocated through
+func nodeAcceleratorResource(node *corev1.Node) corev1.ResourceName {
+	for _, name := range acceleratorResourceNames {
+		if qty, ok := node.Status.Allocatable[name]; ok && qty.Value() > 0 {
+			return name
+		}
+	}
+	return ""
+}
+
+// quotaRemaining returns the smallest hard-minus-used across quotas limiting
+// requests of resource, or nil when none does. Extended resources can only
+// be limited through their requests.
+func quotaRemaining(quotas []corev1.ResourceQuota, resource corev1.ResourceName) *int64 {
+	key := corev1.ResourceName("requests." + string(resource))
+	var remaining *int64
+	for _, q := range quotas {
+		hard, ok := q.Status.Hard[key]
+		if !ok {
+			hard, ok = q.Spec.Hard[key]
+		}
+		if !ok {
+			continue
+		}
+		used := q.Status.Used[key]
+		left := max(hard.Value()-used.Value(), 0)
+		if remaining == nil || left < *remaining {
+			remaining = &left
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSuggestGPUPlacement(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	node := func(name, product, gpus string, ready bool) *corev1.Node {
+		status := corev1.ConditionTrue
+		if !ready {
+			status = corev1.ConditionFalse
+		}
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"nvidia.com/gpu.product": product}},
+			Status: corev1.NodeStatus{
+				Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+				Allocatable: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse(gpus)},
+			},
+		}
+	}
+	gpuPod := func(name, nodeName, gpus string) *corev1.Pod {
+		phase := corev1.PodRunning
+		if nodeName == "" {
+			phase = corev1.PodPending
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "other"},
+			Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{
+				Name:      "train",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse(gpus)}},
+			}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	quota := func(namespace, hard, used string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpus", Namespace: namespace},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{"requests.nvidia.com/gpu": resource.MustParse(hard)},
+				Used: corev1.ResourceList{"requests.nvidia.com/gpu": resource.MustParse(used)},
+			},
+		}
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ml"}}
+
+	m.clients["c1"] = k8sfake.NewSimpleClientset(namespace,
+		node("h100-a", "NVIDIA-H100-80GB-HBM3", "8", true),
+		node("h100-b", "NVIDIA-H100-80GB-HBM3", "8", true),
+		node("h100-down", "NVIDIA-H100-80GB-HBM3", "8", false),
+		node("a100-a", "NVIDIA-A100-SXM4-40GB", "8", true),
+		gpuPod("busy", "h100-a", "2"),
+		gpuPod("full", "h100-b", "6"),
+		gpuPod("waiting", "", "1"),
+	)
+	m.clients["c2"] = k8sfake.NewSimpleClientset(namespace, quota("ml", "8", "6"),
+		node("h100-c", "NVIDIA-H100-80GB-HBM3", "8", true),
+	)
+
+	req := GPUPlacementRequest{Namespace: "ml", GPUs: 4, GPUType: "h100"}
+	c1, err := m.SuggestGPUPlacement(context.Background(), "c1", req)
+	if err != nil {
+		t.Fatalf("SuggestGPUPlacement failed: %v", err)
+	}
+	if len(c1) != 2 {
+		t.Fatalf("Expected only the ready H100 nodes, got %+v", c1)
+	}
+	if c1[0].Node != "h100-a" || !c1[0].Fits || c1[0].FreeGPUs != 6 || c1[0].PendingGPUDemand != 1 || c1[0].QuotaRemaining != nil {
+		t.Errorf("Expected h100-a to fit with 6 free GPUs, got %+v", c1[0])
+	}
+	if c1[1].Node != "h100-b" || c1[1].Fits || len(c1[1].Reasons) != 2 {
+		t.Errorf("Expected h100-b to lack free GPUs, got %+v", c1[1])
+	}
+
+	c2, err := m.SuggestGPUPlacement(context.Background(), "c2", req)
+	if err != nil {
+		t.Fatalf("SuggestGPUPlacement failed: %v", err)
+	}
+	if len(c2) != 1 || c2[0].Fits || c2[0].QuotaRemaining == nil || *c2[0].QuotaRemaining != 2 {
+		t.Errorf("Expected c2 to be blocked by quota, got %+v", c2)
+	}
+
+	// Ranking across clusters keeps fitting nodes first
+	all := append(c2, c1...)
+	RankGPUPlacements(all, req.GPUs)
+	if all[0].Node != "h100-a" || all[2].Node != "h100-b" {
+		t.Errorf("Unexpected fleet ranking: %+v", all)
+	}
+
+	if _, err := m.SuggestGPUPlacement(context.Background(), "c1", GPUPlacementRequest{Namespace: "ml"}); !errors.Is(err, ErrInvalidGPUPlacement) {
+		t.Errorf("Expected ErrInvalidGPUPlacement for 0 GPUs, got %v", err)
+	}
+}
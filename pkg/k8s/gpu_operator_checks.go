@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GPU operator stacks used as keys in the operator pod sets
+const (
+	GPUStackNVIDIA = "nvidia"
+	GPUStackAMD    = "amd"
+	GPUStackHabana = "habana" // Intel Gaudi (Habana AI operator)
+	GPUStackIntel  = "intel"  // Intel GPU / XPU device plugins
+)
+
+// GPUOperatorPodCheck describes one operator DaemonSet expected on every node of a stack
+type GPUOperatorPodCheck struct {
+	Name       string   `json:"name"`
+	PodNames   []string `json:"podNames"`             // pod name substrings; any match counts
+	Namespaces []string `json:"namespaces,omitempty"` // extra namespaces to search
+	Exclude    []string `json:"exclude,omitempty"`    // pod name substrings to ignore (other vendors)
+}
+
+// defaultGPUOperatorPodSets are the built-in per-vendor operator checks
+var defaultGPUOperatorPodSets = map[string][]GPUOperatorPodCheck{
+	GPUStackNVIDIA: {
+		{Name: "gpu-feature-discovery", PodNames: []string{"gpu-feature-discovery"}},
+		{Name: "nvidia-device-plugin", PodNames: []string{"nvidia-device-plugin"}},
+		{Name: "dcgm-exporter", PodNames: []string{"dcgm-exporter"}},
+	},
+	// Operator-managed pods are named <deviceconfig>-<component>-<hash>;
+	// standalone ones use the amdgpu- prefix.
+	GPUStackAMD: {
+		{Name: "amd-device-plugin", PodNames: []string{"amdgpu-device-plugin", "-device-plugin-"}, Exclude: []string{"nvidia", "habana", "intel"}},
+		{Name: "amd-node-labeller", PodNames: []string{"amdgpu-labeller", "-node-labeller-"}, Exclude: []string{"nvidia", "habana", "intel"}},
+		{Name: "amd-metrics-exporter", PodNames: []string{"metrics-exporter"}, Exclude: []string{"nvidia", "habana", "intel"}},
+	},
+	GPUStackHabana: {
+		{Name: "habana-device-plugin", PodNames: []string{"habana-ai-device-plugin", "habanalabs-device-plugin"}},
+		{Name: "habana-driver", PodNames: []string{"habana-ai-driver"}},
+		{Name: "habana-metric-exporter", PodNames: []string{"habana-ai-metric-exporter", "habana-metric-exporter"}},
+	},
+	GPUStackIntel: {
+		{Name: "intel-gpu-plugin", PodNames: []string{"intel-gpu-plugin"}},
+		{Name: "nfd-worker", PodNames: []string{"nfd-worker"}},
+	},
+}
+
+// SetGPUOperatorCheckSource sets a function returning per-stack operator pod sets
+// that replace the built-in set for that stack. Stacks not returned keep the defaults.
+func (m *MultiClusterClient) SetGPUOperatorCheckSource(source func() map[string][]GPUOperatorPodCheck) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gpuOperatorChecks = source
+}
+
+// gpuOperatorPodSets returns the effective per-stack operator pod sets
+func (m *MultiClusterClient) gpuOperatorPodSets() map[string][]GPUOperatorPodCheck {
+	m.mu.RLock()
+	source := m.gpuOperatorChecks
+	m.mu.RUnlock()
+
+	sets := make(map[string][]GPUOperatorPodCheck, len(defaultGPUOperatorPodSets))
+	for stack, checks := range defaultGPUOperatorPodSets {
+		sets[stack] = checks
+	}
+	if source != nil {
+		for stack, checks := range source() {
+			sets[strings.ToLower(stack)] = checks
+		}
+	}
+	return sets
+}
+
+// gpuOperatorStack returns the operator stack serving a GPU node, or "" when
+// the accelerator is managed by the platform (e.g. GKE TPUs)
+func gpuOperatorStack(node GPUNode) string {
+	switch node.Manufacturer {
+	case "NVIDIA":
+		return GPUStackNVIDIA
+	case "AMD":
+		return GPUStackAMD
+	case "Intel":
+		if strings.Contains(node.GPUType, "Gaudi") {
+			return GPUStackHabana
+		}
+		return GPUStackIntel
+	}
+	return ""
+}
+
+// gpuOperatorCheckNamespaces returns the namespaces to search for operator pods
+func gpuOperatorCheckNamespaces(sets map[string][]GPUOperatorPodCheck) []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	add := func(ns string) {
+		if ns != "" && !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	for _, ns := range gpuOperatorNamespaces {
+		add(ns)
+	}
+	for _, checks := range sets {
+		for _, c := range checks {
+			for _, ns := range c.Namespaces {
+				add(ns)
+			}
+		}
+	}
+	return namespaces
+}
+
+// runOperatorPodChecks evaluates a stack's operator pod checks for a node
+func runOperatorPodChecks(pods []corev1.Pod, nodeName string, checks []GPUOperatorPodCheck) []GPUNodeHealthCheck {
+	results := make([]GPUNodeHealthCheck, 0, len(checks))
+	for _, c := range checks {
+		check := c
+		results = append(results, checkOperatorPodMatching(pods, nodeName, check.Name, func(name string) bool {
+			for _, ex := range check.Exclude {
+				if strings.Contains(name, ex) {
+					return false
+				}
+			}
+			for _, p := range check.PodNames {
+				if strings.Contains(name, p) {
+					return true
+				}
+			}
+			return false
+		}))
+	}
+	return results
+}
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGPUOperatorStack(t *testing.T) {
+	tests := []struct {
+		node GPUNode
+		want string
+	}{
+		{GPUNode{Manufacturer: "NVIDIA"}, GPUStackNVIDIA},
+		{GPUNode{Manufacturer: "AMD"}, GPUStackAMD},
+		{GPUNode{Manufacturer: "Intel", GPUType: "Intel Gaudi2"}, GPUStackHabana},
+		{GPUNode{Manufacturer: "Intel", GPUType: "Intel XPU"}, GPUStackIntel},
+		{GPUNode{Manufacturer: "Google", GPUType: "tpu-v5"}, ""},
+	}
+	for _, tt := range tests {
+		if got := gpuOperatorStack(tt.node); got != tt.want {
+			t.Errorf("gpuOperatorStack(%+v) = %q, want %q", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestGetGPUNodeHealth_GaudiOperatorChecks(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "gaudi-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{"habana.ai/gaudi2": resource.MustParse("8")},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	plugin := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "habana-ai-device-plugin-ds-abc", Namespace: "habana-ai-operator"},
+		Spec:       corev1.PodSpec{NodeName: "gaudi-1"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				RestartCount: 7,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}},
+		},
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(node, plugin)
+
+	health, err := m.GetGPUNodeHealth(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetGPUNodeHealth failed: %v", err)
+	}
+	if len(health) != 1 {
+		t.Fatalf("Expected 1 node, got %d", len(health))
+	}
+
+	var found bool
+	for _, c := range health[0].Checks {
+		if c.Name == "nvidia-device-plugin" {
+			t.Errorf("Gaudi node should not get NVIDIA checks: %+v", health[0].Checks)
+		}
+		if c.Name == "habana-device-plugin" {
+			found = true
+			if c.Passed {
+				t.Errorf("Expected crashlooping Habana device plugin to fail, got %+v", c)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected habana-device-plugin check, got %+v", health[0].Checks)
+	}
+	if health[0].Status != "degraded" {
+		t.Errorf("Expected degraded status, got %s", health[0].Status)
+	}
+
+	// Overrides replace the built-in set and can add namespaces
+	m.SetGPUOperatorCheckSource(func() map[string][]GPUOperatorPodCheck {
+		return map[string][]GPUOperatorPodCheck{
+			"Habana": {{Name: "custom-exporter", PodNames: []string{"my-exporter"}, Namespaces: []string{"monitoring"}}},
+		}
+	})
+	health, _ = m.GetGPUNodeHealth(context.Background(), "c1")
+	for _, c := range health[0].Checks {
+		if c.Name == "habana-device-plugin" {
+			t.Errorf("Expected override to replace default Habana checks, got %+v", health[0].Checks)
+		}
+	}
+	if health[0].Status != "healthy" {
+		t.Errorf("Expected healthy status with override, got %s", health[0].Status)
+	}
+	if ns := gpuOperatorCheckNamespaces(m.gpuOperatorPodSets()); ns[len(ns)-1] != "monitoring" {
+		t.Errorf("Expected override namespace to be searched, got %v", ns)
+	}
+}
@@ -0,0 +1,199 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// migResourcePrefix prefixes the extended resources the NVIDIA device plugin
+// advertises per MIG profile under the mixed strategy, e.g. nvidia.com/mig-1g.10gb
+const migResourcePrefix = "nvidia.com/mig-"
+
+// MIGSliceCapacity is the capacity of one MIG profile
+type MIGSliceCapacity struct {
+	Profile   string `json:"profile"` // e.g. 1g.10gb
+	Total     int64  `json:"total"`
+	Allocated int64  `json:"allocated"`
+	Available int64  `json:"available"`
+}
+
+// GPUModelCapacity is the capacity of one accelerator model in one cluster
+type GPUModelCapacity struct {
+	Cluster         string             `json:"cluster"`
+	Model           string             `json:"model"`
+	Manufacturer    string             `json:"manufacturer,omitempty"`
+	AcceleratorType AcceleratorType    `json:"acceleratorType,omitempty"`
+	Nodes           int                `json:"nodes"`
+	Total           int                `json:"total"`
+	Allocated       int                `json:"allocated"`
+	Available       int                `json:"available"`
+	MIGSlices       []MIGSliceCapacity `json:"migSlices,omitempty"`
+}
+
+// GPUModelOverview is the fleet-wide capacity of one accelerator model with
+// its per-cluster breakdown
+type GPUModelOverview struct {
+	Model           string             `json:"model"`
+	Manufacturer    string             `json:"manufacturer,omitempty"`
+	AcceleratorType AcceleratorType    `json:"acceleratorType,omitempty"`
+	Nodes           int                `json:"nodes"`
+	Total           int                `json:"total"`
+	Allocated       int                `json:"allocated"`
+	Available       int                `json:"available"`
+	MIGSlices       []MIGSliceCapacity `json:"migSlices,omitempty"`
+	Clusters        []GPUModelCapacity `json:"clusters"`
+}
+
+// GetGPUModelCapacity groups a cluster's accelerator nodes by model with
+// total, allocated and available devices and MIG slice capacity. Nodes that
+// expose only MIG slices are grouped under their nvidia.com/gpu.product label.
+func (m *MultiClusterClient) GetGPUModelCapacity(ctx context.Context, contextName string) ([]GPUModelCapacity, error) {
+	gpuNodes, err := m.GetGPUNodes(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	// GetGPUNodes just refreshed the snapshot, so this is served from cache
+	snap, err := m.getNodePodSnapshot(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	byModel := make(map[string]*GPUModelCapacity)
+	modelOf := make(map[string]*GPUModelCapacity) // by node name
+	model := func(name, manufacturer string, accelType AcceleratorType) *GPUModelCapacity {
+		c, ok := byModel[name]
+		if !ok {
+			c = &GPUModelCapacity{Cluster: contextName, Model: name, Manufacturer: manufacturer, AcceleratorType: accelType}
+			byModel[name] = c
+		}
+		return c
+	}
+	for _, n := range gpuNodes {
+		c := model(n.GPUType, n.Manufacturer, n.AcceleratorType)
+		c.Nodes++
+		c.Total += n.GPUCount
+		c.Allocated += n.GPUAllocated
+		modelOf[n.Name] = c
+	}
+
+	// MIG slices per model and profile, allocated from the requests of pods bound to each node
+	migAllocated := make(map[string]map[corev1.ResourceName]int64)
+	if snap.pods != nil {
+		for i := range snap.pods.Items {
+			pod := &snap.pods.Items[i]
+			if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			for _, container := range pod.Spec.Containers {
+				for name, qty := range container.Resources.Requests {
+					if strings.HasPrefix(string(name), migResourcePrefix) {
+						if migAllocated[pod.Spec.NodeName] == nil {
+							migAllocated[pod.Spec.NodeName] = make(map[corev1.ResourceName]int64)
+						}
+						migAllocated[pod.Spec.NodeName][name] += qty.Value()
+					}
+				}
+			}
+		}
+	}
+	migSlices := make(map[*GPUModelCapacity]map[string]*MIGSliceCapacity)
+	if snap.nodes != nil {
+		for i := range snap.nodes.Items {
+			node := &snap.nodes.Items[i]
+			for name, qty := range node.Status.Allocatable {
+				if !strings.HasPrefix(string(name), migResourcePrefix) || qty.Value() == 0 {
+					continue
+				}
+				c := modelOf[node.Name]
+				if c == nil {
+					product := node.Labels["nvidia.com/gpu.product"]
+					if product == "" {
+						product = "NVIDIA GPU"
+					}
+					c = model(product, "NVIDIA", AcceleratorGPU)
+					c.Nodes++
+					modelOf[node.Name] = c
+				}
+				if migSlices[c] == nil {
+					migSlices[c] = make(map[string]*MIGSliceCapacity)
+				}
+				profile := strings.TrimPrefix(string(name), migResourcePrefix)
+				s, ok := migSlices[c][profile]
+				if !ok {
+					s = &MIGSliceCapacity{Profile: profile}
+					migSlices[c][profile] = s
+				}
+				s.Total += qty.Value()
+				s.Allocated += migAllocated[node.Name][name]
+			}
+		}
+	}
+
+	result := make([]GPUModelCapacity, 0, len(byModel))
+	for _, c := range byModel {
+		c.Available = max(c.Total-c.Allocated, 0)
+		for _, s := range migSlices[c] {
+			s.Available = max(s.Total-s.Allocated, 0)
+			c.MIGSlices = append(c.MIGSlices, *s)
+		}
+		sortMIGSlices(c.MIGSlices)
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Model < result[j].Model })
+	return result, nil
+}
+
+// MergeGPUOverview combines per-cluster model capacity into a fleet-wide
+// overview per model, the largest fleets first
+func MergeGPUOverview(capacities []GPUModelCapacity) []GPUModelOverview {
+	byModel := make(map[string]*GPUModelOverview)
+	migByModel := make(map[string]map[string]*MIGSliceCapacity)
+	for _, c := range capacities {
+		o, ok := byModel[c.Model]
+		if !ok {
+			o = &GPUModelOverview{Model: c.Model, Manufacturer: c.Manufacturer, AcceleratorType: c.AcceleratorType}
+			byModel[c.Model] = o
+			migByModel[c.Model] = make(map[string]*MIGSliceCapacity)
+		}
+		o.Nodes += c.Nodes
+		o.Total += c.Total
+		o.Allocated += c.Allocated
+		o.Available += c.Available
+		o.Clusters = append(o.Clusters, c)
+		for _, s := range c.MIGSlices {
+			merged, ok := migByModel[c.Model][s.Profile]
+			if !ok {
+				merged = &MIGSliceCapacity{Profile: s.Profile}
+				migByModel[c.Model][s.Profile] = merged
+			}
+			merged.Total += s.Total
+			merged.Allocated += s.Allocated
+			merged.Available += s.Available
+		}
+	}
+
+	result := make([]GPUModelOverview, 0, len(byModel))
+	for model, o := range byModel {
+		for _, s := range migByModel[model] {
+			o.MIGSlices = append(o.MIGSlices, *s)
+		}
+		sortMIGSlices(o.MIGSlices)
+		sort.Slice(o.Clusters, func(i, j int) bool { return o.Clusters[i].Cluster < o.Clusters[j].Cluster })
+		result = append(result, *o)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
+
+// sortMIGSlices orders MIG profiles by name
+func sortMIGSlices(s []MIGSliceCapacity) {
+	sort.Slice(s, func(i, j int) bool { return s[i].Profile < s[j].Profile })
+}
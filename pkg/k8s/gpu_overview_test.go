@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGPUModelCapacityAndOverview(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	node := func(name, product string, allocatable corev1.ResourceList) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"nvidia.com/gpu.product": product}},
+			Status:     corev1.NodeStatus{Allocatable: allocatable},
+		}
+	}
+	pod := func(name, nodeName string, requests corev1.ResourceList) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ml"},
+			Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{
+				Name:      "main",
+				Resources: corev1.ResourceRequirements{Requests: requests},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		node("h100-a", "NVIDIA-H100-80GB-HBM3", corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")}),
+		node("a100-mig", "NVIDIA-A100-SXM4-80GB", corev1.ResourceList{
+			"nvidia.com/mig-1g.10gb": resource.MustParse("14"),
+			"nvidia.com/mig-3g.40gb": resource.MustParse("2"),
+		}),
+		pod("train", "h100-a", corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("3")}),
+		pod("notebook", "a100-mig", corev1.ResourceList{"nvidia.com/mig-1g.10gb": resource.MustParse("4")}),
+	)
+	m.clients["c2"] = k8sfake.NewSimpleClientset(
+		node("h100-b", "NVIDIA-H100-80GB-HBM3", corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")}),
+	)
+
+	c1, err := m.GetGPUModelCapacity(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetGPUModelCapacity failed: %v", err)
+	}
+	if len(c1) != 2 {
+		t.Fatalf("Expected 2 models, got %+v", c1)
+	}
+	a100, h100 := c1[0], c1[1]
+	if h100.Model != "NVIDIA-H100-80GB-HBM3" || h100.Total != 8 || h100.Allocated != 3 || h100.Available != 5 || len(h100.MIGSlices) != 0 {
+		t.Errorf("Unexpected H100 capacity: %+v", h100)
+	}
+	if a100.Nodes != 1 || a100.Total != 0 || len(a100.MIGSlices) != 2 {
+		t.Fatalf("Expected MIG-only A100 node with 2 profiles, got %+v", a100)
+	}
+	if s := a100.MIGSlices[0]; s.Profile != "1g.10gb" || s.Total != 14 || s.Allocated != 4 || s.Available != 10 {
+		t.Errorf("Unexpected 1g.10gb slices: %+v", s)
+	}
+
+	c2, err := m.GetGPUModelCapacity(context.Background(), "c2")
+	if err != nil {
+		t.Fatalf("GetGPUModelCapacity failed: %v", err)
+	}
+	overview := MergeGPUOverview(append(c1, c2...))
+	if len(overview) != 2 || overview[0].Model != "NVIDIA-H100-80GB-HBM3" {
+		t.Fatalf("Expected H100 first as the largest fleet, got %+v", overview)
+	}
+	if o := overview[0]; o.Total != 16 || o.Available != 13 || o.Nodes != 2 || len(o.Clusters) != 2 || o.Clusters[0].Cluster != "c1" {
+		t.Errorf("Unexpected merged H100 overview: %+v", o)
+	}
+	if o := overview[1]; len(o.MIGSlices) != 2 || o.MIGSlices[1].Profile != "3g.40gb" || o.MIGSlices[1].Available != 2 {
+		t.Errorf("Unexpected merged MIG slices: %+v", o.MIGSlices)
+	}
+}
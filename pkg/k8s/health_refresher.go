@@ -0,0 +1,216 @@
+package k8s
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	// healthRefreshTick is how often the refresher looks for clusters due a probe
+	healthRefreshTick = 5 * time.Second
+	// healthRefreshTimeout bounds one background probe unless the cluster
+	// configures its own health timeout
+	healthRefreshTimeout = 30 * time.Second
+	// healthRefreshConcurrency bounds the probes running at once
+	healthRefreshConcurrency = 8
+	// healthRefreshJitter spreads refreshes over ±20% of the interval
+	healthRefreshJitter = 0.2
+	// offlineRefreshFactor and slowRefreshFactor stretch the interval of
+	// unreachable and slow clusters so they don't hold up probe slots
+	offlineRefreshFactor = 4
+	slowRefreshFactor    = 2
+	// healthRefreshGrace keeps a cluster's cached health past its scheduled
+	// refresh for the tick that picks it up and the probe itself
+	healthRefreshGrace = healthRefreshTick + healthRefreshTimeout
+)
+
+type refreshHealthKey struct{}
+
+// withHealthRefresh makes GetClusterHealth probe the cluster even when its
+// cached health is still fresh
+func withHealthRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshHealthKey{}, true)
+}
+
+// StartHealthRefresher keeps the health cache warm in the background, so
+// dashboards reading GetCachedHealth or GetClusterHealth don't wait on probes.
+// Each cluster is re-probed at a jittered point before its cached health
+// expires; offline and slow clusters wait longer and focused clusters (see
+// SetFocusedClusters) go first. Calling it again while running is a no-op.
+func (m *MultiClusterClient) StartHealthRefresher() {
+	m.mu.Lock()
+	if m.stopRefresher != nil {
+		m.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopRefresher = cancel
+	m.mu.Unlock()
+
+	go m.runHealthRefresher(ctx)
+}
+
+// StopHealthRefresher stops the background refresher
+func (m *MultiClusterClient) StopHealthRefresher() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopRefresher != nil {
+		m.stopRefresher()
+		m.stopRefresher = nil
+	}
+}
+
+func (m *MultiClusterClient) runHealthRefresher(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[HealthRefresh] recovered from panic: %v", r)
+		}
+	}()
+
+	next := make(map[string]time.Time)
+	ticker := time.NewTicker(healthRefreshTick)
+	defer ticker.Stop()
+	for {
+		m.refreshDueHealth(ctx, next)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshDueHealth probes the clusters whose next refresh time has passed and
+// schedules their next one. next maps context names to that time.
+func (m *MultiClusterClient) refreshDueHealth(ctx context.Context, next map[string]time.Time) {
+	listCtx, cancel := context.WithTimeout(ctx, clusterHealthCheckTimeout)
+	clusters, err := m.DeduplicatedClusters(listCtx)
+	cancel()
+	if err != nil {
+		log.Printf("[HealthRefresh] failed to list clusters: %v", err)
+		return
+	}
+	m.prioritizeFocused(clusters)
+
+	now := time.Now()
+	known := make(map[string]bool, len(clusters))
+	var due []ClusterInfo
+	for _, cl := range clusters {
+		known[cl.Context] = true
+		at, ok := next[cl.Context]
+		if !ok {
+			// Clusters probed by warmup or a request are refreshed relative to that probe
+			at = m.scheduleFromCache(cl.Context, now)
+			next[cl.Context] = at
+			m.holdHealthUntil(cl.Context, at)
+		}
+		if !now.Before(at) {
+			due = append(due, cl)
+		}
+	}
+	for name := range next {
+		if !known[name] {
+			delete(next, name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	slots := make(chan struct{}, healthRefreshConcurrency)
+	for _, cl := range due {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case slots <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(cl ClusterInfo) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			reachable := m.refreshClusterHealth(ctx, cl)
+			at := time.Now().Add(m.refreshInterval(cl.Context, reachable))
+			m.holdHealthUntil(cl.Context, at)
+			mu.Lock()
+			next[cl.Context] = at
+			mu.Unlock()
+		}(cl)
+	}
+	wg.Wait()
+}
+
+// refreshClusterHealth probes one cluster and reports whether it was reachable.
+// Unreachable results are cached too, unlike in GetClusterHealth, so
+// HealthyClusters keeps skipping offline clusters between refreshes.
+func (m *MultiClusterClient) refreshClusterHealth(ctx context.Context, cl ClusterInfo) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, m.OperationTimeout(cl.Context, OpHealth, healthRefreshTimeout))
+	defer cancel()
+
+	health, err := m.GetClusterHealth(WithFreshSnapshot(withHealthRefresh(probeCtx)), cl.Context)
+	if ctx.Err() != nil || err != nil || health == nil {
+		return err == nil
+	}
+	if !health.Reachable {
+		m.mu.Lock()
+		m.healthCache[cl.Context] = health
+		m.cacheTime[cl.Context] = time.Now()
+		m.mu.Unlock()
+	}
+	return health.Reachable
+}
+
+// refreshInterval returns the jittered wait before a cluster's next refresh:
+// three quarters of its health TTL, stretched for offline and slow clusters.
+// holdHealthUntil keeps the cached entry fresh until then.
+func (m *MultiClusterClient) refreshInterval(contextName string, reachable bool) time.Duration {
+	m.mu.RLock()
+	interval := m.healthTTLLocked(contextName) * 3 / 4
+	m.mu.RUnlock()
+	switch {
+	case !reachable:
+		interval *= offlineRefreshFactor
+	case m.IsSlow(contextName):
+		interval *= slowRefreshFactor
+	}
+	return jitter(interval)
+}
+
+// scheduleFromCache returns when a cluster not yet seen by the refresher is due:
+// now without cached health, otherwise a jittered interval after the last probe
+func (m *MultiClusterClient) scheduleFromCache(contextName string, now time.Time) time.Time {
+	m.mu.RLock()
+	health, ok := m.healthCache[contextName]
+	checked := m.cacheTime[contextName]
+	m.mu.RUnlock()
+	if !ok {
+		return now
+	}
+	return checked.Add(m.refreshInterval(contextName, health.Reachable))
+}
+
+// holdHealthUntil keeps a cluster's cached health fresh until its next refresh
+// at, so stretched intervals don't leave requests to probe it themselves
+func (m *MultiClusterClient) holdHealthUntil(contextName string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.healthCache[contextName]; !ok {
+		return
+	}
+	if m.healthExpiry == nil {
+		m.healthExpiry = make(map[string]time.Time)
+	}
+	m.healthExpiry[contextName] = at.Add(healthRefreshGrace)
+}
+
+// healthFreshLocked reports whether a cluster's cached health is within its TTL
+// or held until the refresher's next probe. Must be called with lock held.
+func (m *MultiClusterClient) healthFreshLocked(contextName string, now time.Time) bool {
+	return now.Sub(m.cacheTime[contextName]) < m.healthTTLLocked(contextName) || now.Before(m.healthExpiry[contextName])
+}
+
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration((rand.Float64()*2-1)*healthRefreshJitter*float64(d))
+}
@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestRefreshDueHealth(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.cacheTTL = time.Minute
+	m.rawConfig = &api.Config{
+		Clusters: map[string]*api.Cluster{"up": {Server: "https://up.example.com"}, "down": {Server: "https://127.0.0.1:1"}},
+		Contexts: map[string]*api.Context{"c1": {Cluster: "up"}, "c2": {Cluster: "down"}},
+	}
+	ready := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+	fake := k8sfake.NewSimpleClientset(ready("n1"))
+	m.clients["c1"] = fake
+
+	next := make(map[string]time.Time)
+	start := time.Now()
+	m.refreshDueHealth(context.Background(), next)
+
+	cached := m.GetCachedHealth()
+	if cached["c1"] == nil || !cached["c1"].Reachable || cached["c1"].NodeCount != 1 {
+		t.Fatalf("Expected c1 to be probed and cached, got %+v", cached["c1"])
+	}
+	if cached["c2"] == nil || cached["c2"].Reachable {
+		t.Fatalf("Expected the unreachable c2 to be cached as offline, got %+v", cached["c2"])
+	}
+	interval := 45 * time.Second
+	if d := next["c1"].Sub(start); d < interval*8/10 || d > interval*12/10+time.Second {
+		t.Errorf("Expected c1 to be due within the jittered interval, got %v", d)
+	}
+	if d := next["c2"].Sub(start); d < offlineRefreshFactor*interval*8/10 {
+		t.Errorf("Expected offline c2 to be probed less often, got %v", d)
+	}
+
+	// Nothing is due yet; once c1 is, it is probed although its cache is fresh
+	fake.CoreV1().Nodes().Create(context.Background(), ready("n2"), metav1.CreateOptions{})
+	m.refreshDueHealth(context.Background(), next)
+	if m.GetCachedHealth()["c1"].NodeCount != 1 {
+		t.Error("Expected c1 not to be probed before it is due")
+	}
+	next["c1"] = time.Now().Add(-time.Second)
+	m.refreshDueHealth(context.Background(), next)
+	if m.GetCachedHealth()["c1"].NodeCount != 2 {
+		t.Error("Expected a due cluster to be re-probed past its fresh cache")
+	}
+
+	// Cached health is served past its TTL until the scheduled refresh
+	fake.CoreV1().Nodes().Create(context.Background(), ready("n3"), metav1.CreateOptions{})
+	m.mu.Lock()
+	m.cacheTime["c1"] = time.Now().Add(-2 * m.cacheTTL)
+	m.mu.Unlock()
+	if h, _ := m.GetClusterHealth(WithFreshSnapshot(context.Background()), "c1"); h.NodeCount != 2 {
+		t.Errorf("Expected held health until the next refresh, got %d nodes", h.NodeCount)
+	}
+	m.mu.Lock()
+	m.healthExpiry["c1"] = time.Now().Add(-time.Second)
+	m.mu.Unlock()
+	if h, _ := m.GetClusterHealth(WithFreshSnapshot(context.Background()), "c1"); h.NodeCount != 3 {
+		t.Errorf("Expected health past its refresh to be probed, got %d nodes", h.NodeCount)
+	}
+
+	delete(m.rawConfig.Contexts, "c2")
+	m.refreshDueHealth(context.Background(), next)
+	if _, ok := next["c2"]; ok {
+		t.Error("Expected removed clusters to be forgotten")
+	}
+}
+
+func TestStartHealthRefresher(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.StartHealthRefresher()
+	m.StartHealthRefresher()
+	m.StopHealthRefresher()
+	if m.stopRefresher != nil {
+		t.Error("Expected the refresher to stop")
+	}
+	m.StopHealthRefresher()
+}
@@ -0,0 +1,267 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// hpaFlapWindow is how far back scale direction changes count towards flapping
+	hpaFlapWindow = 30 * time.Minute
+	// hpaFlapDirectionChanges is how many up/down reversals in the window count as flapping
+	hpaFlapDirectionChanges = 3
+)
+
+// hpaNewSizePattern extracts the replica count from SuccessfulRescale events,
+// e.g. "New size: 5; reason: cpu resource utilization (percentage of request) above target"
+var hpaNewSizePattern = regexp.MustCompile(`New size: (\d+)`)
+
+// HPADetail is an HPA with every metric target, its conditions and scaling timeline
+type HPADetail struct {
+	HPA
+	DesiredReplicas int32          `json:"desiredReplicas"`
+	LastScaleTime   string         `json:"lastScaleTime,omitempty"`
+	Metrics         []HPAMetric    `json:"metrics"`
+	Conditions      []HPACondition `json:"conditions"`
+	// Stabilization windows from spec.behavior, when set
+	ScaleUpStabilizationSeconds   *int32 `json:"scaleUpStabilizationSeconds,omitempty"`
+	ScaleDownStabilizationSeconds *int32 `json:"scaleDownStabilizationSeconds,omitempty"`
+	// Events is the scaling timeline, oldest first
+	Events []HPAScalingEvent `json:"events"`
+	// DirectionChanges counts up/down reversals within the flap window
+	DirectionChanges int  `json:"directionChanges"`
+	Flapping         bool `json:"flapping"`
+}
+
+// HPAMetric is one metric the HPA scales on, including custom and external metrics
+type HPAMetric struct {
+	Type     string `json:"type"` // Resource, ContainerResource, Pods, Object, External
+	Name     string `json:"name"`
+	Object   string `json:"object,omitempty"`   // Kind/name, Object metrics only
+	Selector string `json:"selector,omitempty"` // metric label selector
+	Target   string `json:"target"`
+	Current  string `json:"current,omitempty"`
+}
+
+// HPACondition is an HPA status condition (AbleToScale, ScalingActive, ScalingLimited)
+type HPACondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// HPAScalingEvent is an event recorded for the HPA; rescales carry the new size
+type HPAScalingEvent struct {
+	Time      string `json:"time"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Count     int32  `json:"count"`
+	Replicas  int32  `json:"replicas,omitempty"`
+	Direction string `json:"direction,omitempty"` // up, down
+}
+
+// GetHPADetail returns an HPA with all metric targets, status conditions, the
+// scaling event timeline and whether it is flapping between sizes
+func (m *MultiClusterClient) GetHPADetail(ctx context.Context, contextName, namespace, name string) (*HPADetail, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	hpa, err := client.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &HPADetail{
+		HPA:             hpaSummary(hpa, contextName),
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Metrics:         hpaMetrics(hpa),
+		Conditions:      []HPACondition{},
+		Events:          []HPAScalingEvent{},
+	}
+	if hpa.Status.LastScaleTime != nil {
+		detail.LastScaleTime = hpa.Status.LastScaleTime.Format(time.RFC3339)
+	}
+	if b := hpa.Spec.Behavior; b != nil {
+		if b.ScaleUp != nil {
+			detail.ScaleUpStabilizationSeconds = b.ScaleUp.StabilizationWindowSeconds
+		}
+		if b.ScaleDown != nil {
+			detail.ScaleDownStabilizationSeconds = b.ScaleDown.StabilizationWindowSeconds
+		}
+	}
+	for _, c := range hpa.Status.Conditions {
+		cond := HPACondition{Type: string(c.Type), Status: string(c.Status), Reason: c.Reason, Message: c.Message}
+		if !c.LastTransitionTime.IsZero() {
+			cond.LastTransitionTime = c.LastTransitionTime.Format(time.RFC3339)
+		}
+		detail.Conditions = append(detail.Conditions, cond)
+	}
+
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=HorizontalPodAutoscaler,involvedObject.name=" + name,
+	})
+	if err != nil {
+		// The HPA itself is still useful without its timeline
+		return detail, nil
+	}
+	detail.Events, detail.DirectionChanges = hpaTimeline(events.Items, name, time.Now())
+	detail.Flapping = detail.DirectionChanges >= hpaFlapDirectionChanges
+	return detail, nil
+}
+
+// hpaTimeline orders the HPA's events oldest first, infers the direction of each
+// rescale and counts direction reversals within the flap window
+func hpaTimeline(items []corev1.Event, name string, now time.Time) ([]HPAScalingEvent, int) {
+	sort.Slice(items, func(i, j int) bool {
+		return eventTime(items[i]).Before(eventTime(items[j]))
+	})
+
+	timeline := []HPAScalingEvent{}
+	changes := 0
+	lastSize := int32(-1)
+	lastDirection := ""
+	for _, e := range items {
+		if e.InvolvedObject.Kind != "HorizontalPodAutoscaler" || e.InvolvedObject.Name != name {
+			continue
+		}
+		at := eventTime(e)
+		ev := HPAScalingEvent{
+			Time:    at.Format(time.RFC3339),
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Count:   e.Count,
+		}
+		if match := hpaNewSizePattern.FindStringSubmatch(e.Message); match != nil {
+			size, _ := strconv.ParseInt(match[1], 10, 32)
+			ev.Replicas = int32(size)
+			switch {
+			case lastSize >= 0 && ev.Replicas > lastSize, strings.Contains(e.Message, "above target"):
+				ev.Direction = "up"
+			case lastSize >= 0 && ev.Replicas < lastSize, strings.Contains(e.Message, "below target"):
+				ev.Direction = "down"
+			}
+			if now.Sub(at) <= hpaFlapWindow {
+				if ev.Direction != "" && lastDirection != "" && ev.Direction != lastDirection {
+					changes++
+				}
+				// A repeated rescale to the same size means it left that size and came back
+				if e.Count > 1 {
+					changes += 2 * int(e.Count-1)
+				}
+			}
+			lastSize = ev.Replicas
+			if ev.Direction != "" {
+				lastDirection = ev.Direction
+			}
+		}
+		timeline = append(timeline, ev)
+	}
+	return timeline, changes
+}
+
+// eventTime is when an event last occurred
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.FirstTimestamp.Time
+}
+
+// hpaMetrics lists the spec metrics with the matching current values from status
+func hpaMetrics(hpa *autoscalingv2.HorizontalPodAutoscaler) []HPAMetric {
+	current := make(map[string]string, len(hpa.Status.CurrentMetrics))
+	for _, s := range hpa.Status.CurrentMetrics {
+		key, value := metricStatusValue(s)
+		current[key] = value
+	}
+
+	metrics := []HPAMetric{}
+	for _, spec := range hpa.Spec.Metrics {
+		metric := HPAMetric{Type: string(spec.Type)}
+		switch {
+		case spec.Resource != nil:
+			metric.Name = string(spec.Resource.Name)
+			metric.Target = metricTargetString(spec.Resource.Target)
+		case spec.ContainerResource != nil:
+			metric.Name = fmt.Sprintf("%s/%s", spec.ContainerResource.Container, spec.ContainerResource.Name)
+			metric.Target = metricTargetString(spec.ContainerResource.Target)
+		case spec.Pods != nil:
+			metric.Name = spec.Pods.Metric.Name
+			metric.Selector = metav1.FormatLabelSelector(spec.Pods.Metric.Selector)
+			metric.Target = metricTargetString(spec.Pods.Target)
+		case spec.Object != nil:
+			metric.Name = spec.Object.Metric.Name
+			metric.Object = spec.Object.DescribedObject.Kind + "/" + spec.Object.DescribedObject.Name
+			metric.Selector = metav1.FormatLabelSelector(spec.Object.Metric.Selector)
+			metric.Target = metricTargetString(spec.Object.Target)
+		case spec.External != nil:
+			metric.Name = spec.External.Metric.Name
+			metric.Selector = metav1.FormatLabelSelector(spec.External.Metric.Selector)
+			metric.Target = metricTargetString(spec.External.Target)
+		}
+		if metric.Selector == "<none>" {
+			metric.Selector = ""
+		}
+		metric.Current = current[metric.Type+"/"+metric.Name]
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+// metricStatusValue returns the type/name key of a current metric and its value
+func metricStatusValue(s autoscalingv2.MetricStatus) (string, string) {
+	switch {
+	case s.Resource != nil:
+		return string(s.Type) + "/" + string(s.Resource.Name), metricValueString(s.Resource.Current)
+	case s.ContainerResource != nil:
+		return fmt.Sprintf("%s/%s/%s", s.Type, s.ContainerResource.Container, s.ContainerResource.Name), metricValueString(s.ContainerResource.Current)
+	case s.Pods != nil:
+		return string(s.Type) + "/" + s.Pods.Metric.Name, metricValueString(s.Pods.Current)
+	case s.Object != nil:
+		return string(s.Type) + "/" + s.Object.Metric.Name, metricValueString(s.Object.Current)
+	case s.External != nil:
+		return string(s.Type) + "/" + s.External.Metric.Name, metricValueString(s.External.Current)
+	}
+	return string(s.Type), ""
+}
+
+func metricTargetString(t autoscalingv2.MetricTarget) string {
+	switch {
+	case t.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *t.AverageUtilization)
+	case t.AverageValue != nil:
+		return t.AverageValue.String() + " (avg)"
+	case t.Value != nil:
+		return t.Value.String()
+	}
+	return ""
+}
+
+func metricValueString(v autoscalingv2.MetricValueStatus) string {
+	switch {
+	case v.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *v.AverageUtilization)
+	case v.AverageValue != nil:
+		return v.AverageValue.String() + " (avg)"
+	case v.Value != nil:
+		return v.Value.String()
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func hpaEvent(name string, at time.Time, message string, count int32) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "HorizontalPodAutoscaler", Name: "api"},
+		Type:           "Normal",
+		Reason:         "SuccessfulRescale",
+		Message:        message,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestGetHPADetail(t *testing.T) {
+	utilization := int32(70)
+	queueTarget := resource.MustParse("30")
+	queueCurrent := resource.MustParse("45")
+	window := int32(300)
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "api"},
+			MaxReplicas:    10,
+			Metrics: []autoscalingv2.MetricSpec{
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU, Target: autoscalingv2.MetricTarget{AverageUtilization: &utilization},
+				}},
+				{Type: autoscalingv2.ExternalMetricSourceType, External: &autoscalingv2.ExternalMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"},
+					Target: autoscalingv2.MetricTarget{Value: &queueTarget},
+				}},
+			},
+			Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+				ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: &window},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 4,
+			DesiredReplicas: 4,
+			CurrentMetrics: []autoscalingv2.MetricStatus{{
+				Type:     autoscalingv2.ExternalMetricSourceType,
+				External: &autoscalingv2.ExternalMetricStatus{Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"}, Current: autoscalingv2.MetricValueStatus{Value: &queueCurrent}},
+			}},
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{{Type: autoscalingv2.ScalingLimited, Status: corev1.ConditionFalse, Reason: "DesiredWithinRange"}},
+		},
+	}
+
+	now := time.Now()
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(hpa,
+		hpaEvent("e1", now.Add(-20*time.Minute), "New size: 4; reason: cpu resource utilization (percentage of request) above target", 1),
+		hpaEvent("e2", now.Add(-15*time.Minute), "New size: 2; reason: All metrics below target", 1),
+		hpaEvent("e3", now.Add(-10*time.Minute), "New size: 4; reason: external metric queue_depth above target", 1),
+		hpaEvent("e4", now.Add(-5*time.Minute), "New size: 2; reason: All metrics below target", 1),
+	))
+
+	detail, err := m.GetHPADetail(context.Background(), "c1", "default", "api")
+	if err != nil {
+		t.Fatalf("GetHPADetail failed: %v", err)
+	}
+	if len(detail.Metrics) != 2 || detail.Metrics[0].Target != "70%" {
+		t.Fatalf("Expected CPU and external metrics, got %+v", detail.Metrics)
+	}
+	if ext := detail.Metrics[1]; ext.Type != "External" || ext.Target != "30" || ext.Current != "45" {
+		t.Errorf("Expected the external metric target and current value, got %+v", ext)
+	}
+	if detail.ScaleDownStabilizationSeconds == nil || *detail.ScaleDownStabilizationSeconds != 300 {
+		t.Errorf("Expected the scale-down stabilization window, got %v", detail.ScaleDownStabilizationSeconds)
+	}
+	if len(detail.Conditions) != 1 || detail.Conditions[0].Reason != "DesiredWithinRange" {
+		t.Errorf("Expected the status condition, got %+v", detail.Conditions)
+	}
+	if len(detail.Events) != 4 || detail.Events[0].Direction != "up" || detail.Events[1].Direction != "down" {
+		t.Fatalf("Expected an oldest-first timeline with directions, got %+v", detail.Events)
+	}
+	if detail.DirectionChanges != 3 || !detail.Flapping {
+		t.Errorf("Expected up/down cycles to be flagged as flapping, got %d changes", detail.DirectionChanges)
+	}
+}
+
+func TestHPATimelineRepeatedRescale(t *testing.T) {
+	now := time.Now()
+	events := []corev1.Event{
+		*hpaEvent("a", now.Add(-2*time.Hour), "New size: 3; reason: above target", 1),
+		*hpaEvent("b", now.Add(-time.Minute), "New size: 5; reason: above target", 3),
+	}
+	timeline, changes := hpaTimeline(events, "api", now)
+	if len(timeline) != 2 || timeline[1].Replicas != 5 {
+		t.Fatalf("Expected 2 rescales, got %+v", timeline)
+	}
+	// Reaching size 5 three times implies two round trips
+	if changes != 4 {
+		t.Errorf("Expected 4 direction changes, got %d", changes)
+	}
+}
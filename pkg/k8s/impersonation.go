@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// Impersonation is an identity to act as, so admins can see what a user, group
+// or service account actually sees. The kubeconfig identity needs the
+// "impersonate" verb; the API server enforces it.
+type Impersonation struct {
+	User   string
+	Groups []string
+	UID    string
+}
+
+// ServiceAccountImpersonation returns the identity of a service account, with
+// the groups the API server would give it
+func ServiceAccountImpersonation(namespace, name string) Impersonation {
+	return Impersonation{
+		User:   fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name),
+		Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"},
+	}
+}
+
+// IsZero reports whether no identity is set
+func (i Impersonation) IsZero() bool {
+	return i.User == "" && len(i.Groups) == 0 && i.UID == ""
+}
+
+// String identifies the impersonated identity, e.g. for cache keys and logs
+func (i Impersonation) String() string {
+	groups := append([]string(nil), i.Groups...)
+	sort.Strings(groups)
+	return i.User + "|" + strings.Join(groups, ",") + "|" + i.UID
+}
+
+type impersonationKey struct{}
+
+// WithImpersonation makes every API request made with ctx act as imp. Caches
+// shared between identities (health, node snapshots) are bypassed for it.
+func WithImpersonation(ctx context.Context, imp Impersonation) context.Context {
+	if imp.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, impersonationKey{}, imp)
+}
+
+// ImpersonationFrom returns the identity set by WithImpersonation
+func ImpersonationFrom(ctx context.Context) (Impersonation, bool) {
+	imp, ok := ctx.Value(impersonationKey{}).(Impersonation)
+	return imp, ok
+}
+
+// impersonationSuffix distinguishes per-identity cache entries; "" without impersonation
+func impersonationSuffix(ctx context.Context) string {
+	if imp, ok := ImpersonationFrom(ctx); ok {
+		return "\x00" + imp.String()
+	}
+	return ""
+}
+
+// impersonationTransport adds the Impersonate-* headers of the request's identity
+type impersonationTransport struct {
+	next http.RoundTripper
+}
+
+// wrapImpersonation lets requests carry a per-request identity; a config that
+// already impersonates (from the kubeconfig) keeps its own identity
+func wrapImpersonation(config *rest.Config) {
+	if config.Impersonate.UserName != "" {
+		return
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonationTransport{next: rt}
+	})
+}
+
+func (t *impersonationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	imp, ok := ImpersonationFrom(req.Context())
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	return transport.NewImpersonatingRoundTripper(transport.ImpersonationConfig{
+		UserName: imp.User,
+		Groups:   imp.Groups,
+		UID:      imp.UID,
+	}, t.next).RoundTrip(req)
+}
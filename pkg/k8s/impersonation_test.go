@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestImpersonationHeaders(t *testing.T) {
+	var mu sync.Mutex
+	var seen http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = r.Header.Clone()
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[]}`))
+	}))
+	defer srv.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(api.Config{
+		Clusters: map[string]*api.Cluster{"c": {Server: srv.URL}},
+		Contexts: map[string]*api.Context{"c1": {Cluster: "c"}},
+	}, kubeconfig); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+	m, _ := NewMultiClusterClient(kubeconfig)
+	client, err := m.GetClient("c1")
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+
+	list := func(ctx context.Context) http.Header {
+		if _, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{}); err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return seen
+	}
+
+	if h := list(context.Background()); h.Get("Impersonate-User") != "" {
+		t.Errorf("Expected no impersonation by default, got %q", h.Get("Impersonate-User"))
+	}
+
+	ctx := WithImpersonation(context.Background(), ServiceAccountImpersonation("team-a", "deployer"))
+	h := list(ctx)
+	if got := h.Get("Impersonate-User"); got != "system:serviceaccount:team-a:deployer" {
+		t.Errorf("Expected the service account user, got %q", got)
+	}
+	if groups := h.Values("Impersonate-Group"); len(groups) != 3 || groups[1] != "system:serviceaccounts:team-a" {
+		t.Errorf("Expected the service account groups, got %v", groups)
+	}
+
+	// The cached client is shared; the identity only follows the context
+	if h := list(context.Background()); h.Get("Impersonate-User") != "" {
+		t.Errorf("Expected impersonation not to leak into other requests, got %q", h.Get("Impersonate-User"))
+	}
+}
+
+func TestImpersonationBypassesSharedCaches(t *testing.T) {
+	ctx := WithImpersonation(context.Background(), Impersonation{User: "alice", Groups: []string{"b", "a"}})
+	if impersonationSuffix(context.Background()) != "" {
+		t.Error("Expected no cache suffix without impersonation")
+	}
+	same := WithImpersonation(context.Background(), Impersonation{User: "alice", Groups: []string{"a", "b"}})
+	if impersonationSuffix(ctx) == "" || impersonationSuffix(ctx) != impersonationSuffix(same) {
+		t.Error("Expected a stable per-identity cache suffix")
+	}
+	if _, ok := ImpersonationFrom(WithImpersonation(context.Background(), Impersonation{})); ok {
+		t.Error("Expected an empty identity to be ignored")
+	}
+}
@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KServeInferenceServiceGVR is the KServe InferenceService resource
+var KServeInferenceServiceGVR = schema.GroupVersionResource{
+	Group:    "serving.kserve.io",
+	Version:  "v1beta1",
+	Resource: "inferenceservices",
+}
+
+// ListDeploymentObjects returns the full Deployment objects in a namespace (all
+// namespaces when empty), for callers that inspect their pod templates
+func (m *MultiClusterClient) ListDeploymentObjects(ctx context.Context, contextName, namespace string) ([]appsv1.Deployment, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]appsv1.Deployment, error) {
+			return m.ListDeploymentObjects(ctx, contextName, ns)
+		})
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return deployments.Items, nil
+}
+
+// ListInferenceServices returns the KServe InferenceServices in a namespace (all
+// namespaces when empty). KServe is optional, so a missing CRD returns none.
+func (m *MultiClusterClient) ListInferenceServices(ctx context.Context, contextName, namespace string) ([]unstructured.Unstructured, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]unstructured.Unstructured, error) {
+			return m.ListInferenceServices(ctx, contextName, ns)
+		})
+	}
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := dynamicClient.Resource(KServeInferenceServiceGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil // KServe CRD not installed
+	}
+	return list.Items, nil
+}
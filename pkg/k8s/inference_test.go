@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestListDeploymentObjectsScoped(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1"}}})
+	deployment := func(ns, name string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	}
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(deployment("team-a", "vllm"), deployment("team-b", "tgi")))
+	ctx := context.Background()
+
+	if all, err := m.ListDeploymentObjects(ctx, "c1", ""); err != nil || len(all) != 2 {
+		t.Fatalf("Expected both deployments without a scope, got %d (%v)", len(all), err)
+	}
+
+	m.SetNamespaceScope(NamespaceScope{Namespaces: []string{"team-a"}})
+	scoped, err := m.ListDeploymentObjects(ctx, "c1", "")
+	if err != nil || len(scoped) != 1 || scoped[0].Namespace != "team-a" {
+		t.Errorf("Expected only the team-a deployment, got %+v (%v)", scoped, err)
+	}
+	if _, err := m.ListDeploymentObjects(ctx, "c1", "team-b"); !errors.Is(err, ErrOutsideNamespaceScope) {
+		t.Errorf("Expected ErrOutsideNamespaceScope, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// Caches InvalidateCluster can drop
+const (
+	CacheHealth     = "health"     // ClusterHealth from GetClusterHealth
+	CacheSnapshot   = "snapshot"   // node+pod listing shared by node, GPU and health queries
+	CacheNamespaces = "namespaces" // namespaces discovered for NamespaceScope
+	CacheSlow       = "slow"       // reduced timeout for clusters that recently timed out
+)
+
+// ClusterCaches lists every cache InvalidateCluster knows
+var ClusterCaches = []string{CacheHealth, CacheSnapshot, CacheNamespaces, CacheSlow}
+
+// ErrUnknownCache is returned when asked to invalidate a cache that does not exist
+var ErrUnknownCache = errors.New("unknown cache")
+
+// InvalidateCluster drops the named caches (all when none are given) of a
+// cluster, or of every cluster when contextName is empty, so the next read
+// goes to the API server instead of waiting for the TTL. With a shared cache
+// the entries are dropped for other replicas too.
+func (m *MultiClusterClient) InvalidateCluster(contextName string, caches ...string) error {
+	if len(caches) == 0 {
+		caches = ClusterCaches
+	}
+	drop := make(map[string]bool, len(caches))
+	for _, name := range caches {
+		if !slices.Contains(ClusterCaches, name) {
+			return fmt.Errorf("%w %q (expected one of %s)", ErrUnknownCache, name, strings.Join(ClusterCaches, ", "))
+		}
+		drop[name] = true
+	}
+	// matches reports whether a cache key belongs to the cluster; node snapshots
+	// and discovered namespaces are keyed per impersonated identity too
+	matches := func(key string) bool {
+		return contextName == "" || key == contextName || strings.HasPrefix(key, contextName+"\x00")
+	}
+
+	m.mu.Lock()
+	var sharedKeys []string
+	if drop[CacheHealth] {
+		for name := range m.healthCache {
+			if matches(name) {
+				delete(m.healthCache, name)
+				delete(m.cacheTime, name)
+				delete(m.healthExpiry, name)
+				sharedKeys = append(sharedKeys, "health:"+name)
+			}
+		}
+	}
+	if drop[CacheSlow] {
+		for name := range m.slowClusters {
+			if matches(name) {
+				delete(m.slowClusters, name)
+				sharedKeys = append(sharedKeys, "slow:"+name)
+			}
+		}
+	}
+	if contextName != "" {
+		// Another replica may hold entries this one never cached
+		if drop[CacheHealth] && !slices.Contains(sharedKeys, "health:"+contextName) {
+			sharedKeys = append(sharedKeys, "health:"+contextName)
+		}
+		if drop[CacheSlow] && !slices.Contains(sharedKeys, "slow:"+contextName) {
+			sharedKeys = append(sharedKeys, "slow:"+contextName)
+		}
+	}
+	shared := m.sharedCache
+	m.mu.Unlock()
+
+	if drop[CacheSnapshot] {
+		m.nodeSnapshots.mu.Lock()
+		for key := range m.nodeSnapshots.entries {
+			if matches(key) {
+				delete(m.nodeSnapshots.entries, key)
+			}
+		}
+		m.nodeSnapshots.mu.Unlock()
+	}
+	if drop[CacheNamespaces] {
+		m.scopeMu.Lock()
+		for key := range m.scopeCache {
+			if matches(key) {
+				delete(m.scopeCache, key)
+			}
+		}
+		m.scopeMu.Unlock()
+	}
+	for _, key := range sharedKeys {
+		SharedDelete(shared, key)
+	}
+	return nil
+}
+
+// ReprobeCluster drops a cluster's cached health and node+pod snapshot and
+// checks it again right away
+func (m *MultiClusterClient) ReprobeCluster(ctx context.Context, contextName string) (*ClusterHealth, error) {
+	if err := m.InvalidateCluster(contextName, CacheHealth, CacheSnapshot, CacheSlow); err != nil {
+		return nil, err
+	}
+	health, err := m.GetClusterHealth(WithFreshSnapshot(ctx), contextName)
+	if err != nil {
+		log.Printf("[Reprobe] %s: %v", contextName, err)
+		return nil, err
+	}
+	return health, nil
+}
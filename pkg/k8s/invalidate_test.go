@@ -0,0 +1,62 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInvalidateClusterAndReprobe(t *testing.T) {
+	shared := NewMemoryCache()
+	m, _ := NewMultiClusterClient("")
+	m.SetSharedCache(shared)
+	m.clients["c1"] = k8sfake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+	})
+
+	stale := func(name string) {
+		m.mu.Lock()
+		m.healthCache[name] = &ClusterHealth{Cluster: name, Healthy: true, Reachable: true, NodeCount: 5}
+		m.cacheTime[name] = time.Now()
+		m.mu.Unlock()
+	}
+	stale("c1")
+	stale("c2")
+	m.MarkSlow("c1")
+
+	if err := m.InvalidateCluster("c1", "bogus"); !errors.Is(err, ErrUnknownCache) {
+		t.Errorf("Expected ErrUnknownCache, got %v", err)
+	}
+	if err := m.InvalidateCluster("c1", CacheHealth, CacheSlow); err != nil {
+		t.Fatalf("InvalidateCluster failed: %v", err)
+	}
+	cached := m.GetCachedHealth()
+	if cached["c1"] != nil || cached["c2"] == nil {
+		t.Errorf("Expected only c1 health to be dropped, got %+v", cached)
+	}
+	if m.IsSlow("c1") {
+		t.Error("Expected slow mark to be dropped locally and in the shared cache")
+	}
+
+	stale("c1")
+	health, err := m.ReprobeCluster(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("ReprobeCluster failed: %v", err)
+	}
+	if health.NodeCount != 1 {
+		t.Errorf("Expected a fresh probe with 1 node, got %+v", health)
+	}
+
+	if err := m.InvalidateCluster(""); err != nil {
+		t.Fatalf("InvalidateCluster failed: %v", err)
+	}
+	if cached := m.GetCachedHealth(); len(cached) != 0 {
+		t.Errorf("Expected every cluster to be dropped, got %+v", cached)
+	}
+}
@@ -0,0 +1,279 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// defaultJobLogTail is how many lines are fetched per container when no tail is given
+	defaultJobLogTail = 200
+	// maxJobLogTail caps the per-container tail so large Jobs stay responsive
+	maxJobLogTail = 2000
+	// maxJobLogPods caps how many pods of a Job are fetched, failed pods first
+	maxJobLogPods = 50
+	// jobLogConcurrency bounds parallel log requests against the API server
+	jobLogConcurrency = 8
+	// maxFailureLines is how many error lines are kept per pod
+	maxFailureLines = 5
+)
+
+// jobFailurePattern matches log lines that usually explain why a batch pod failed
+var jobFailurePattern = regexp.MustCompile(`(?i)\b(error|exception|panic|fatal|traceback|killed|oom)\b`)
+
+// JobLogs is the interleaved output of every pod of a Job
+type JobLogs struct {
+	Job       string       `json:"job"`
+	Namespace string       `json:"namespace"`
+	Cluster   string       `json:"cluster"`
+	Indexed   bool         `json:"indexed"`
+	Pods      []JobPodLog  `json:"pods"`
+	Lines     []JobLogLine `json:"lines"`
+	Failures  []JobFailure `json:"failures"`
+}
+
+// JobPodLog describes one pod whose logs were collected
+type JobPodLog struct {
+	Pod   string `json:"pod"`
+	Index string `json:"index,omitempty"` // completion index, Indexed Jobs only
+	Phase string `json:"phase"`
+	Error string `json:"error,omitempty"` // set when the logs could not be fetched
+}
+
+// JobLogLine is a single log line labeled with the pod and completion index it came from
+type JobLogLine struct {
+	Time      string `json:"time,omitempty"`
+	Pod       string `json:"pod"`
+	Index     string `json:"index,omitempty"`
+	Container string `json:"container"`
+	Message   string `json:"message"`
+}
+
+// JobFailure explains why a pod of the Job failed
+type JobFailure struct {
+	Pod       string   `json:"pod"`
+	Index     string   `json:"index,omitempty"`
+	Container string   `json:"container,omitempty"`
+	Reason    string   `json:"reason"`
+	ExitCode  int32    `json:"exitCode,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Lines     []string `json:"lines,omitempty"` // error lines from the container's logs
+}
+
+// jobLogLine pairs a parsed line with its timestamp for interleaving
+type jobLogLine struct {
+	at time.Time
+	JobLogLine
+}
+
+// GetJobLogs collects the logs of all pods of a Job and interleaves them by
+// timestamp. Each line is labeled with its pod and, for Indexed Jobs, its
+// completion index. Failed pods are summarized with their exit reason and the
+// error lines found in their output.
+func (m *MultiClusterClient) GetJobLogs(ctx context.Context, contextName, namespace, name, container string, tailLines int64) (*JobLogs, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	job, err := client.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if tailLines <= 0 {
+		tailLines = defaultJobLogTail
+	}
+	if tailLines > maxJobLogTail {
+		tailLines = maxJobLogTail
+	}
+
+	pods, err := jobPods(ctx, client, job)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &JobLogs{
+		Job:       name,
+		Namespace: namespace,
+		Cluster:   contextName,
+		Indexed:   job.Spec.CompletionMode != nil && *job.Spec.CompletionMode == batchv1.IndexedCompletion,
+		Pods:      make([]JobPodLog, len(pods)),
+		Lines:     []JobLogLine{},
+		Failures:  []JobFailure{},
+	}
+
+	perPod := make([][]jobLogLine, len(pods))
+	sem := make(chan struct{}, jobLogConcurrency)
+	var wg sync.WaitGroup
+	for i := range pods {
+		pod := &pods[i]
+		result.Pods[i] = JobPodLog{Pod: pod.Name, Index: completionIndex(pod), Phase: string(pod.Status.Phase)}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var errs []string
+			for _, c := range pod.Spec.Containers {
+				if container != "" && c.Name != container {
+					continue
+				}
+				raw, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+					Container:  c.Name,
+					TailLines:  &tailLines,
+					Timestamps: true,
+				}).DoRaw(ctx)
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("%s: %v", c.Name, err))
+					continue
+				}
+				perPod[i] = append(perPod[i], parseJobLogLines(string(raw), pod.Name, result.Pods[i].Index, c.Name)...)
+			}
+			result.Pods[i].Error = strings.Join(errs, "; ")
+		}(i)
+	}
+	wg.Wait()
+
+	var all []jobLogLine
+	for i := range pods {
+		all = append(all, perPod[i]...)
+		result.Failures = append(result.Failures, podFailures(&pods[i], result.Pods[i].Index, perPod[i])...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].at.Before(all[j].at)
+	})
+	for _, l := range all {
+		result.Lines = append(result.Lines, l.JobLogLine)
+	}
+	return result, nil
+}
+
+// jobPods lists the Job's pods using its selector, failed pods first then by
+// completion index and creation time, capped at maxJobLogPods
+func jobPods(ctx context.Context, client kubernetes.Interface, job *batchv1.Job) ([]corev1.Pod, error) {
+	selector := "job-name=" + job.Name
+	if job.Spec.Selector != nil {
+		s, err := metav1.LabelSelectorAsSelector(job.Spec.Selector)
+		if err != nil {
+			return nil, err
+		}
+		selector = s.String()
+	}
+	list, err := client.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []corev1.Pod
+	for _, pod := range list.Items {
+		for _, ref := range pod.OwnerReferences {
+			if ref.Kind == "Job" && ref.Name == job.Name {
+				owned = append(owned, pod)
+				break
+			}
+		}
+	}
+	sort.SliceStable(owned, func(i, j int) bool {
+		fi, fj := owned[i].Status.Phase == corev1.PodFailed, owned[j].Status.Phase == corev1.PodFailed
+		if fi != fj {
+			return fi
+		}
+		if a, b := completionIndex(&owned[i]), completionIndex(&owned[j]); a != b {
+			return len(a) < len(b) || (len(a) == len(b) && a < b)
+		}
+		return owned[i].CreationTimestamp.Before(&owned[j].CreationTimestamp)
+	})
+	if len(owned) > maxJobLogPods {
+		owned = owned[:maxJobLogPods]
+	}
+	return owned, nil
+}
+
+// completionIndex returns the completion index of a pod of an Indexed Job, or ""
+func completionIndex(pod *corev1.Pod) string {
+	if idx, ok := pod.Annotations[batchv1.JobCompletionIndexAnnotation]; ok {
+		return idx
+	}
+	return pod.Labels[batchv1.JobCompletionIndexAnnotation]
+}
+
+// parseJobLogLines splits logs fetched with timestamps into labeled lines.
+// Lines without a parsable timestamp inherit the previous one so they stay in
+// place when interleaved.
+func parseJobLogLines(raw, pod, index, container string) []jobLogLine {
+	var lines []jobLogLine
+	var last time.Time
+	for _, text := range strings.Split(strings.TrimRight(raw, "\n"), "\n") {
+		if text == "" {
+			continue
+		}
+		line := jobLogLine{JobLogLine: JobLogLine{Pod: pod, Index: index, Container: container, Message: text}}
+		if ts, msg, ok := strings.Cut(text, " "); ok {
+			if at, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				last = at
+				line.Message = msg
+				line.Time = at.Format(time.RFC3339Nano)
+			}
+		}
+		line.at = last
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// podFailures reports failed containers of a pod with the error lines from their logs
+func podFailures(pod *corev1.Pod, index string, lines []jobLogLine) []JobFailure {
+	var failures []JobFailure
+	for _, cs := range pod.Status.ContainerStatuses {
+		term := cs.State.Terminated
+		if term == nil {
+			term = cs.LastTerminationState.Terminated
+		}
+		if term == nil || term.ExitCode == 0 {
+			continue
+		}
+		failures = append(failures, JobFailure{
+			Pod:       pod.Name,
+			Index:     index,
+			Container: cs.Name,
+			Reason:    term.Reason,
+			ExitCode:  term.ExitCode,
+			Message:   term.Message,
+			Lines:     failureLines(lines, cs.Name),
+		})
+	}
+	// Pods killed before a container terminated, e.g. DeadlineExceeded or Evicted
+	if len(failures) == 0 && pod.Status.Phase == corev1.PodFailed {
+		failures = append(failures, JobFailure{
+			Pod:     pod.Name,
+			Index:   index,
+			Reason:  pod.Status.Reason,
+			Message: pod.Status.Message,
+			Lines:   failureLines(lines, ""),
+		})
+	}
+	return failures
+}
+
+// failureLines returns the last error-looking lines of a container
+func failureLines(lines []jobLogLine, container string) []string {
+	var matched []string
+	for _, l := range lines {
+		if (container == "" || l.Container == container) && jobFailurePattern.MatchString(l.Message) {
+			matched = append(matched, l.Message)
+		}
+	}
+	if len(matched) > maxFailureLines {
+		matched = matched[len(matched)-maxFailureLines:]
+	}
+	return matched
+}
@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseJobLogLines(t *testing.T) {
+	raw := "2026-01-30T10:00:02.5Z starting shard\ncontinuation without timestamp\n2026-01-30T10:00:05Z ERROR: shard failed\n"
+	lines := parseJobLogLines(raw, "train-0-abc", "0", "worker")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	if lines[0].Message != "starting shard" || lines[0].Time != "2026-01-30T10:00:02.5Z" || lines[0].Index != "0" {
+		t.Errorf("Expected the timestamp to be split off, got %+v", lines[0])
+	}
+	if lines[1].Time != "" || !lines[1].at.Equal(lines[0].at) {
+		t.Errorf("Expected an untimestamped line to keep the previous position, got %+v", lines[1])
+	}
+	if got := failureLines(lines, "worker"); len(got) != 1 || got[0] != "ERROR: shard failed" {
+		t.Errorf("Expected one failure line, got %v", got)
+	}
+}
+
+func TestGetJobLogs(t *testing.T) {
+	indexed := batchv1.IndexedCompletion
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "ml"},
+		Spec: batchv1.JobSpec{
+			CompletionMode: &indexed,
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "train"}},
+		},
+	}
+	pod := func(name, index string, phase corev1.PodPhase, exitCode int32) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "ml",
+				Labels:          map[string]string{"job-name": "train"},
+				Annotations:     map[string]string{batchv1.JobCompletionIndexAnnotation: index},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "train"}},
+			},
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "worker"}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+		if exitCode != 0 {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "worker",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: "Error"}},
+			}}
+		}
+		return p
+	}
+
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(job,
+		pod("train-1", "1", corev1.PodSucceeded, 0),
+		pod("train-0", "0", corev1.PodSucceeded, 0),
+		pod("train-2", "2", corev1.PodFailed, 137),
+	))
+
+	logs, err := m.GetJobLogs(context.Background(), "c1", "ml", "train", "", 0)
+	if err != nil {
+		t.Fatalf("GetJobLogs failed: %v", err)
+	}
+	if !logs.Indexed || len(logs.Pods) != 3 {
+		t.Fatalf("Expected 3 pods of an indexed job, got %+v", logs)
+	}
+	if logs.Pods[0].Pod != "train-2" || logs.Pods[1].Index != "0" || logs.Pods[2].Index != "1" {
+		t.Errorf("Expected the failed pod first then by index, got %+v", logs.Pods)
+	}
+	if len(logs.Lines) != 3 {
+		t.Errorf("Expected a log line per pod, got %d", len(logs.Lines))
+	}
+	if len(logs.Failures) != 1 || logs.Failures[0].Index != "2" || logs.Failures[0].ExitCode != 137 {
+		t.Errorf("Expected the failed index to be reported, got %+v", logs.Failures)
+	}
+
+	if _, err := m.GetJobLogs(context.Background(), "c1", "ml", "missing", "", 0); err == nil {
+		t.Error("Expected an error for a missing job")
+	}
+}
@@ -0,0 +1,325 @@
+package k8s
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// certExpiryWarning is how far ahead of expiry a client certificate is flagged
+const certExpiryWarning = 14 * 24 * time.Hour
+
+// Doctor finding severities
+const (
+	DoctorError   = "error"
+	DoctorWarning = "warning"
+	DoctorInfo    = "info"
+)
+
+// DoctorFinding is one kubeconfig hygiene problem with a suggested fix
+type DoctorFinding struct {
+	Context  string `json:"context,omitempty"` // empty for findings about the file as a whole
+	Severity string `json:"severity"`          // error, warning, info
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// ContextDiagnosis is the doctor's verdict on one kubeconfig context
+type ContextDiagnosis struct {
+	Context   string          `json:"context"`
+	Cluster   string          `json:"cluster,omitempty"`
+	User      string          `json:"user,omitempty"`
+	Server    string          `json:"server,omitempty"`
+	Reachable *bool           `json:"reachable,omitempty"` // nil when not probed
+	Findings  []DoctorFinding `json:"findings"`
+}
+
+// DoctorReport is the result of validating a kubeconfig
+type DoctorReport struct {
+	Contexts []ContextDiagnosis `json:"contexts"`
+	// Findings covers problems not tied to one context, like dangling users
+	Findings []DoctorFinding `json:"findings"`
+	Errors   int             `json:"errors"`
+	Warnings int             `json:"warnings"`
+}
+
+// RunKubeconfigDoctor validates every context of the loaded kubeconfig and,
+// when probe is set, checks that each context's API server answers
+func (m *MultiClusterClient) RunKubeconfigDoctor(ctx context.Context, probe bool) (*DoctorReport, error) {
+	config := m.GetRawConfig()
+	if config == nil {
+		return nil, fmt.Errorf("no kubeconfig loaded")
+	}
+	report := DiagnoseKubeconfig(config, exec.LookPath, time.Now())
+	if probe {
+		m.probeDoctorContexts(ctx, report)
+	}
+	report.count()
+	return report, nil
+}
+
+// probeDoctorContexts lists one namespace through each context that passed the
+// static checks, recording unreachable servers with a fix for the failure type
+func (m *MultiClusterClient) probeDoctorContexts(ctx context.Context, report *DoctorReport) {
+	var wg sync.WaitGroup
+	for i := range report.Contexts {
+		d := &report.Contexts[i]
+		if hasDoctorErrors(d.Findings) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, m.OperationTimeout(d.Context, OpHealth, clusterProbeTimeout))
+			defer cancel()
+			client, err := m.GetClient(d.Context)
+			if err == nil {
+				_, err = client.CoreV1().Namespaces().List(probeCtx, metav1.ListOptions{Limit: 1})
+			}
+			reachable := err == nil
+			d.Reachable = &reachable
+			if err != nil {
+				d.Findings = append(d.Findings, DoctorFinding{
+					Context:  d.Context,
+					Severity: DoctorError,
+					Check:    "unreachable",
+					Message:  fmt.Sprintf("API server %s did not answer: %v", d.Server, err),
+					Fix:      unreachableFix(classifyError(err.Error()), d.Context),
+				})
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// unreachableFix suggests a remedy for a failed probe by error type
+func unreachableFix(errorType, context string) string {
+	switch errorType {
+	case "auth":
+		return fmt.Sprintf("Refresh credentials for context %q (re-run your cloud CLI login, or replace the token or client certificate)", context)
+	case "certificate":
+		return "Update certificate-authority-data to the CA that signed the API server certificate"
+	case "timeout", "network":
+		return "Check VPN or network access to the API server, or delete the context if the cluster is gone: kubectl config delete-context " + context
+	}
+	return "Run kubectl --context " + context + " get --raw /readyz for details"
+}
+
+// DiagnoseKubeconfig runs the static kubeconfig checks: dangling references,
+// missing servers and CA data, unreadable credential files, exec plugins not
+// on PATH, expiring client certificates, duplicate servers and unused entries.
+// lookPath resolves exec plugin commands.
+func DiagnoseKubeconfig(config *api.Config, lookPath func(string) (string, error), now time.Time) *DoctorReport {
+	report := &DoctorReport{Contexts: []ContextDiagnosis{}, Findings: []DoctorFinding{}}
+
+	if config.CurrentContext != "" {
+		if _, ok := config.Contexts[config.CurrentContext]; !ok {
+			report.Findings = append(report.Findings, DoctorFinding{
+				Severity: DoctorError,
+				Check:    "current-context",
+				Message:  fmt.Sprintf("current-context %q does not exist", config.CurrentContext),
+				Fix:      "kubectl config use-context <context>",
+			})
+		}
+	}
+
+	usedClusters := make(map[string]bool)
+	usedUsers := make(map[string]bool)
+	serverContexts := make(map[string][]string)
+	for _, name := range sortedKeys(config.Contexts) {
+		c := config.Contexts[name]
+		d := ContextDiagnosis{Context: name, Cluster: c.Cluster, User: c.AuthInfo, Findings: []DoctorFinding{}}
+		add := func(severity, check, message, fix string) {
+			d.Findings = append(d.Findings, DoctorFinding{Context: name, Severity: severity, Check: check, Message: message, Fix: fix})
+		}
+		usedClusters[c.Cluster] = true
+		usedUsers[c.AuthInfo] = true
+
+		cluster, ok := config.Clusters[c.Cluster]
+		switch {
+		case c.Cluster == "":
+			add(DoctorError, "missing-cluster", "context does not reference a cluster", fmt.Sprintf("kubectl config set-context %s --cluster=<cluster>", name))
+		case !ok:
+			add(DoctorError, "missing-cluster", fmt.Sprintf("cluster %q is not defined", c.Cluster), fmt.Sprintf("kubectl config set-cluster %s --server=<url>, or delete the context: kubectl config delete-context %s", c.Cluster, name))
+		default:
+			d.Server = cluster.Server
+			diagnoseCluster(cluster, add)
+			if cluster.Server != "" {
+				serverContexts[cluster.Server] = append(serverContexts[cluster.Server], name)
+			}
+		}
+
+		user, ok := config.AuthInfos[c.AuthInfo]
+		switch {
+		case c.AuthInfo == "":
+			add(DoctorWarning, "missing-user", "context does not reference a user; requests will be anonymous", fmt.Sprintf("kubectl config set-context %s --user=<user>", name))
+		case !ok:
+			add(DoctorError, "missing-user", fmt.Sprintf("user %q is not defined", c.AuthInfo), fmt.Sprintf("kubectl config set-credentials %s ..., or point the context at an existing user", c.AuthInfo))
+		default:
+			diagnoseUser(c.AuthInfo, user, lookPath, now, add)
+		}
+		report.Contexts = append(report.Contexts, d)
+	}
+
+	servers := make([]string, 0, len(serverContexts))
+	for server := range serverContexts {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+	for _, server := range servers {
+		if contexts := serverContexts[server]; len(contexts) > 1 {
+			report.Findings = append(report.Findings, DoctorFinding{
+				Severity: DoctorInfo,
+				Check:    "duplicate-server",
+				Message:  fmt.Sprintf("%d contexts point at %s: %s", len(contexts), server, strings.Join(contexts, ", ")),
+				Fix:      "Keep one context per cluster and remove the rest with kubectl config delete-context <context>",
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(config.AuthInfos) {
+		if !usedUsers[name] {
+			report.Findings = append(report.Findings, DoctorFinding{
+				Severity: DoctorInfo,
+				Check:    "dangling-user",
+				Message:  fmt.Sprintf("user %q is not used by any context", name),
+				Fix:      "kubectl config delete-user " + name,
+			})
+		}
+	}
+	for _, name := range sortedKeys(config.Clusters) {
+		if !usedClusters[name] {
+			report.Findings = append(report.Findings, DoctorFinding{
+				Severity: DoctorInfo,
+				Check:    "dangling-cluster",
+				Message:  fmt.Sprintf("cluster %q is not used by any context", name),
+				Fix:      "kubectl config delete-cluster " + name,
+			})
+		}
+	}
+
+	report.count()
+	return report
+}
+
+func diagnoseCluster(cluster *api.Cluster, add func(severity, check, message, fix string)) {
+	if cluster.Server == "" {
+		add(DoctorError, "missing-server", "cluster has no server URL", "kubectl config set-cluster <cluster> --server=https://<host>:6443")
+		return
+	}
+	if !strings.HasPrefix(cluster.Server, "https://") {
+		if strings.HasPrefix(cluster.Server, "http://") {
+			add(DoctorWarning, "insecure-server", fmt.Sprintf("server %s does not use TLS", cluster.Server), "Use the API server's https:// endpoint")
+		}
+		return
+	}
+	switch {
+	case cluster.InsecureSkipTLSVerify:
+		add(DoctorWarning, "insecure-skip-tls-verify", "TLS verification is disabled", "Set certificate-authority-data and remove insecure-skip-tls-verify")
+	case cluster.CertificateAuthority != "":
+		if _, err := os.Stat(resolveConfigPath(cluster.CertificateAuthority, cluster.LocationOfOrigin)); err != nil {
+			add(DoctorError, "missing-ca", fmt.Sprintf("certificate-authority file %s cannot be read", cluster.CertificateAuthority), "Restore the CA file or embed it: kubectl config set-cluster <cluster> --certificate-authority=<ca.crt> --embed-certs")
+		}
+	case len(cluster.CertificateAuthorityData) == 0:
+		add(DoctorWarning, "missing-ca", "no CA data; the server certificate is verified against system roots only", "If the API server uses a private CA: kubectl config set-cluster <cluster> --certificate-authority=<ca.crt> --embed-certs")
+	}
+}
+
+func diagnoseUser(name string, user *api.AuthInfo, lookPath func(string) (string, error), now time.Time, add func(severity, check, message, fix string)) {
+	if user.Exec != nil {
+		if _, err := lookPath(user.Exec.Command); err != nil {
+			fix := fmt.Sprintf("Install %s or update users.%s.exec.command to its full path", user.Exec.Command, name)
+			if user.Exec.InstallHint != "" {
+				fix = strings.TrimSpace(user.Exec.InstallHint)
+			}
+			add(DoctorError, "exec-plugin", fmt.Sprintf("exec plugin %q is not on PATH", user.Exec.Command), fix)
+		}
+	}
+	for _, file := range []struct{ field, path string }{
+		{"client-certificate", user.ClientCertificate},
+		{"client-key", user.ClientKey},
+		{"tokenFile", user.TokenFile},
+	} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(resolveConfigPath(file.path, user.LocationOfOrigin)); err != nil {
+			add(DoctorError, "missing-credentials", fmt.Sprintf("%s file %s cannot be read", file.field, file.path), fmt.Sprintf("Restore the file or re-embed credentials: kubectl config set-credentials %s --%s=<path> --embed-certs", name, file.field))
+		}
+	}
+
+	certPEM := user.ClientCertificateData
+	if len(certPEM) == 0 && user.ClientCertificate != "" {
+		certPEM, _ = os.ReadFile(resolveConfigPath(user.ClientCertificate, user.LocationOfOrigin))
+	}
+	if notAfter, ok := certNotAfter(certPEM); ok {
+		renew := "Request a new client certificate from the cluster administrator, or regenerate the kubeconfig"
+		switch {
+		case !now.Before(notAfter):
+			add(DoctorError, "expired-certificate", fmt.Sprintf("client certificate expired on %s", notAfter.Format("2006-01-02")), renew)
+		case notAfter.Sub(now) < certExpiryWarning:
+			add(DoctorWarning, "expired-certificate", fmt.Sprintf("client certificate expires on %s", notAfter.Format("2006-01-02")), renew)
+		}
+	}
+}
+
+// certNotAfter returns the expiry of the first certificate in PEM data
+func certNotAfter(data []byte) (time.Time, bool) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotAfter, true
+}
+
+// resolveConfigPath resolves a kubeconfig file reference relative to the
+// kubeconfig it was loaded from, as kubectl does
+func resolveConfigPath(path, origin string) string {
+	if path == "" || filepath.IsAbs(path) || origin == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(origin), path)
+}
+
+func hasDoctorErrors(findings []DoctorFinding) bool {
+	for _, f := range findings {
+		if f.Severity == DoctorError {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *DoctorReport) count() {
+	r.Errors, r.Warnings = 0, 0
+	tally := func(findings []DoctorFinding) {
+		for _, f := range findings {
+			switch f.Severity {
+			case DoctorError:
+				r.Errors++
+			case DoctorWarning:
+				r.Warnings++
+			}
+		}
+	}
+	tally(r.Findings)
+	for _, d := range r.Contexts {
+		tally(d.Findings)
+	}
+}
@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testClientCert(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestDiagnoseKubeconfig(t *testing.T) {
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	config := api.NewConfig()
+	config.CurrentContext = "gone"
+	config.Clusters["prod"] = &api.Cluster{Server: "https://prod:6443", CertificateAuthorityData: []byte("ca")}
+	config.Clusters["prod-alias"] = &api.Cluster{Server: "https://prod:6443", CertificateAuthorityData: []byte("ca")}
+	config.Clusters["lab"] = &api.Cluster{Server: "https://lab:6443"}
+	config.Clusters["unused"] = &api.Cluster{Server: "https://old:6443"}
+	config.AuthInfos["eks"] = &api.AuthInfo{Exec: &api.ExecConfig{Command: "aws-iam-authenticator"}}
+	config.AuthInfos["admin"] = &api.AuthInfo{ClientCertificateData: testClientCert(t, now.Add(-time.Hour))}
+	config.AuthInfos["stale"] = &api.AuthInfo{Token: "x"}
+	config.Contexts["prod"] = &api.Context{Cluster: "prod", AuthInfo: "eks"}
+	config.Contexts["prod-2"] = &api.Context{Cluster: "prod-alias", AuthInfo: "admin"}
+	config.Contexts["lab"] = &api.Context{Cluster: "lab", AuthInfo: "missing"}
+	config.Contexts["broken"] = &api.Context{Cluster: "nowhere", AuthInfo: "eks"}
+
+	notFound := func(string) (string, error) { return "", errors.New("not found") }
+	report := DiagnoseKubeconfig(config, notFound, now)
+
+	checks := func(context string) map[string]string {
+		got := make(map[string]string)
+		for _, d := range report.Contexts {
+			if d.Context != context {
+				continue
+			}
+			for _, f := range d.Findings {
+				got[f.Check] = f.Severity
+				if f.Fix == "" {
+					t.Errorf("Expected a fix suggestion for %s/%s", context, f.Check)
+				}
+			}
+		}
+		return got
+	}
+	if got := checks("prod"); got["exec-plugin"] != DoctorError {
+		t.Errorf("Expected a missing exec plugin error for prod, got %v", got)
+	}
+	if got := checks("prod-2"); got["expired-certificate"] != DoctorError {
+		t.Errorf("Expected an expired certificate error for prod-2, got %v", got)
+	}
+	if got := checks("lab"); got["missing-user"] != DoctorError || got["missing-ca"] != DoctorWarning {
+		t.Errorf("Expected missing user and CA findings for lab, got %v", got)
+	}
+	if got := checks("broken"); got["missing-cluster"] != DoctorError {
+		t.Errorf("Expected a missing cluster error for broken, got %v", got)
+	}
+
+	global := make(map[string]int)
+	for _, f := range report.Findings {
+		global[f.Check]++
+	}
+	for _, check := range []string{"current-context", "duplicate-server", "dangling-user", "dangling-cluster"} {
+		if global[check] != 1 {
+			t.Errorf("Expected one %s finding, got %v", check, global)
+		}
+	}
+	if report.Errors != 6 {
+		t.Errorf("Expected 6 errors, got %d", report.Errors)
+	}
+}
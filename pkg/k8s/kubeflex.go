@@ -0,0 +1,283 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ClusterSourceKubeFlex is the ClusterInfo.Source for control planes hosted by KubeFlex
+const ClusterSourceKubeFlex = "kubeflex"
+
+// controlPlaneTypeHost is a KubeFlex control plane that is the hosting cluster itself
+const controlPlaneTypeHost = "host"
+
+// kubeflexControlPlaneGVR is the KubeFlex ControlPlane resource, which KubeStellar
+// uses for its inventory and transport spaces (ITS) and workload description spaces (WDS)
+var kubeflexControlPlaneGVR = schema.GroupVersionResource{
+	Group:    "tenancy.kflex.kubestellar.org",
+	Version:  "v1alpha1",
+	Resource: "controlplanes",
+}
+
+// ControlPlaneSecretRef locates the kubeconfig KubeFlex generates for a control plane
+type ControlPlaneSecretRef struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Key          string `json:"key"`                    // kubeconfig for clients outside the hosting cluster
+	InClusterKey string `json:"inClusterKey,omitempty"` // kubeconfig for clients inside it
+}
+
+// KubeFlexControlPlane is a control plane hosted by KubeFlex
+type KubeFlexControlPlane struct {
+	Name      string                 `json:"name"`
+	Cluster   string                 `json:"cluster"` // hosting cluster context
+	Type      string                 `json:"type"`    // k8s, vcluster, host, ocm, external
+	Namespace string                 `json:"namespace"`
+	Ready     bool                   `json:"ready"`
+	Message   string                 `json:"message,omitempty"`
+	SecretRef *ControlPlaneSecretRef `json:"secretRef,omitempty"`
+	CreatedAt string                 `json:"createdAt,omitempty"`
+	// Context is the console context the control plane is reachable as, once
+	// RefreshControlPlanes has mapped it
+	Context string `json:"context,omitempty"`
+}
+
+// ListControlPlanes lists the KubeFlex control planes hosted by a cluster.
+// Returns an error when KubeFlex is not installed.
+func (m *MultiClusterClient) ListControlPlanes(ctx context.Context, contextName string) ([]KubeFlexControlPlane, error) {
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := dynamicClient.Resource(kubeflexControlPlaneGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	planes := make([]KubeFlexControlPlane, 0, len(list.Items))
+	for i := range list.Items {
+		planes = append(planes, controlPlaneFromUnstructured(&list.Items[i], contextName))
+	}
+	return planes, nil
+}
+
+func controlPlaneFromUnstructured(item *unstructured.Unstructured, contextName string) KubeFlexControlPlane {
+	cp := KubeFlexControlPlane{
+		Name:    item.GetName(),
+		Cluster: contextName,
+		// KubeFlex creates each control plane's resources in <name>-system
+		Namespace: item.GetName() + "-system",
+	}
+	if created := item.GetCreationTimestamp(); !created.IsZero() {
+		cp.CreatedAt = created.UTC().Format(time.RFC3339)
+	}
+	cp.Type, _, _ = unstructured.NestedString(item.Object, "spec", "type")
+
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		cp.Ready = cond["status"] == "True"
+		if msg, ok := cond["message"].(string); ok {
+			cp.Message = msg
+		} else if reason, ok := cond["reason"].(string); ok {
+			cp.Message = reason
+		}
+	}
+
+	if ref, found, _ := unstructured.NestedStringMap(item.Object, "status", "secretRef"); found {
+		cp.SecretRef = &ControlPlaneSecretRef{
+			Name:         ref["name"],
+			Namespace:    ref["namespace"],
+			Key:          ref["key"],
+			InClusterKey: ref["inClusterKey"],
+		}
+	}
+	return cp
+}
+
+// GetControlPlaneKubeconfig returns the external kubeconfig of a KubeFlex control plane
+func (m *MultiClusterClient) GetControlPlaneKubeconfig(ctx context.Context, contextName string, cp KubeFlexControlPlane) ([]byte, error) {
+	if cp.SecretRef == nil || cp.SecretRef.Name == "" || cp.SecretRef.Key == "" {
+		return nil, fmt.Errorf("control plane %s has no kubeconfig secret yet", cp.Name)
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := client.CoreV1().Secrets(cp.SecretRef.Namespace).Get(ctx, cp.SecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig secret of %s: %w", cp.Name, err)
+	}
+	data, ok := secret.Data[cp.SecretRef.Key]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("kubeconfig secret of %s has no %q key", cp.Name, cp.SecretRef.Key)
+	}
+	return data, nil
+}
+
+// ListAllControlPlanes lists the KubeFlex control planes hosted by every
+// cluster, skipping clusters without KubeFlex
+func (m *MultiClusterClient) ListAllControlPlanes(ctx context.Context) ([]KubeFlexControlPlane, error) {
+	clusters, err := m.ListClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	planes := make([]KubeFlexControlPlane, 0)
+	for _, cluster := range clusters {
+		// Hosted control planes are not expected to nest further control planes
+		if cluster.Source == ClusterSourceKubeFlex {
+			continue
+		}
+		wg.Add(1)
+		go func(contextName string) {
+			defer wg.Done()
+			clusterPlanes, err := m.ListControlPlanes(ctx, contextName)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			planes = append(planes, clusterPlanes...)
+			mu.Unlock()
+		}(cluster.Context)
+	}
+	wg.Wait()
+
+	sort.Slice(planes, func(i, j int) bool {
+		if planes[i].Cluster != planes[j].Cluster {
+			return planes[i].Cluster < planes[j].Cluster
+		}
+		return planes[i].Name < planes[j].Name
+	})
+	return planes, nil
+}
+
+// RefreshControlPlanes discovers KubeFlex control planes and maps the ready
+// ones into contexts, so ListClusters reports them as clusters of their own.
+// A control plane whose API server is already a kubeconfig context keeps that
+// context; otherwise it is named after the control plane, or
+// <hosting context>/<control plane> when that name is taken.
+func (m *MultiClusterClient) RefreshControlPlanes(ctx context.Context) ([]KubeFlexControlPlane, error) {
+	planes, err := m.ListAllControlPlanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	existing := existingServers(m.rawConfig, m.mountedConfig)
+	m.mu.RUnlock()
+
+	hosted := api.NewConfig()
+	for i := range planes {
+		cp := &planes[i]
+		if !cp.Ready || cp.Type == controlPlaneTypeHost || cp.SecretRef == nil {
+			continue
+		}
+		data, err := m.GetControlPlaneKubeconfig(ctx, cp.Cluster, *cp)
+		if err != nil {
+			log.Printf("Warning: control plane %s on %s: %v", cp.Name, cp.Cluster, err)
+			continue
+		}
+		cfg, err := clientcmd.Load(data)
+		if err != nil {
+			log.Printf("Warning: invalid kubeconfig for control plane %s on %s: %v", cp.Name, cp.Cluster, err)
+			continue
+		}
+		kctx, cluster, user := primaryContext(cfg)
+		if kctx == nil || cluster == nil {
+			continue
+		}
+		if name, ok := existing[cluster.Server]; ok {
+			cp.Context = name
+			continue
+		}
+		name := cp.Name
+		if m.hasContext(name) || hosted.Contexts[name] != nil {
+			name = cp.Cluster + "/" + cp.Name
+		}
+		hosted.Clusters[name] = cluster
+		if user != nil {
+			hosted.AuthInfos[name] = user
+		}
+		hosted.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name, Namespace: kctx.Namespace}
+		cp.Context = name
+	}
+
+	m.mu.Lock()
+	// Drop cached clients of hosted contexts, their credentials may have rotated
+	if m.controlPlaneConfig != nil {
+		for name := range m.controlPlaneConfig.Contexts {
+			delete(m.clients, name)
+			delete(m.dynamicClients, name)
+			delete(m.metadataClients, name)
+			delete(m.configs, name)
+			delete(m.httpClients, name)
+		}
+	}
+	if len(hosted.Contexts) == 0 {
+		m.controlPlaneConfig = nil
+	} else {
+		m.controlPlaneConfig = hosted
+	}
+	m.mu.Unlock()
+	return planes, nil
+}
+
+// hasContext reports whether a context is defined in the kubeconfig or the mounted kubeconfigs
+func (m *MultiClusterClient) hasContext(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, config := range []*api.Config{m.rawConfig, m.mountedConfig} {
+		if config != nil && config.Contexts[name] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// existingServers maps the API server URLs of the given kubeconfigs to a context using them
+func existingServers(configs ...*api.Config) map[string]string {
+	servers := make(map[string]string)
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+		for name, kctx := range config.Contexts {
+			if cluster, ok := config.Clusters[kctx.Cluster]; ok && cluster.Server != "" {
+				if prev, seen := servers[cluster.Server]; !seen || name < prev {
+					servers[cluster.Server] = name
+				}
+			}
+		}
+	}
+	return servers
+}
+
+// primaryContext returns the current context of a kubeconfig (or its only
+// context) with the cluster and user it references
+func primaryContext(cfg *api.Config) (*api.Context, *api.Cluster, *api.AuthInfo) {
+	kctx := cfg.Contexts[cfg.CurrentContext]
+	if kctx == nil && len(cfg.Contexts) == 1 {
+		for _, c := range cfg.Contexts {
+			kctx = c
+		}
+	}
+	if kctx == nil {
+		return nil, nil, nil
+	}
+	return kctx, cfg.Clusters[kctx.Cluster], cfg.AuthInfos[kctx.AuthInfo]
+}
@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestListControlPlanes(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	ready := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tenancy.kflex.kubestellar.org/v1alpha1",
+		"kind":       "ControlPlane",
+		"metadata":   map[string]interface{}{"name": "wds1"},
+		"spec":       map[string]interface{}{"type": "k8s"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+			"secretRef":  map[string]interface{}{"name": "admin-kubeconfig", "namespace": "wds1-system", "key": "kubeconfig"},
+		},
+	}}
+	pending := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tenancy.kflex.kubestellar.org/v1alpha1",
+		"kind":       "ControlPlane",
+		"metadata":   map[string]interface{}{"name": "its1"},
+		"spec":       map[string]interface{}{"type": "vcluster"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "False", "reason": "Creating"}},
+		},
+	}}
+	m.dynamicClients["host"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kubeflexControlPlaneGVR: "ControlPlaneList"}, ready, pending)
+	m.clients["host"] = k8sfake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin-kubeconfig", Namespace: "wds1-system"},
+		Data:       map[string][]byte{"kubeconfig": []byte("apiVersion: v1")},
+	})
+
+	planes, err := m.ListControlPlanes(context.Background(), "host")
+	if err != nil {
+		t.Fatalf("ListControlPlanes failed: %v", err)
+	}
+	byName := make(map[string]KubeFlexControlPlane)
+	for _, cp := range planes {
+		byName[cp.Name] = cp
+	}
+	wds := byName["wds1"]
+	if !wds.Ready || wds.Type != "k8s" || wds.Namespace != "wds1-system" || wds.Cluster != "host" || wds.SecretRef == nil {
+		t.Errorf("Unexpected wds1: %+v", wds)
+	}
+	its := byName["its1"]
+	if its.Ready || its.Message != "Creating" || its.SecretRef != nil {
+		t.Errorf("Unexpected its1: %+v", its)
+	}
+
+	data, err := m.GetControlPlaneKubeconfig(context.Background(), "host", wds)
+	if err != nil || string(data) != "apiVersion: v1" {
+		t.Errorf("GetControlPlaneKubeconfig = %q, %v", data, err)
+	}
+	if _, err := m.GetControlPlaneKubeconfig(context.Background(), "host", its); err == nil {
+		t.Error("Expected an error for a control plane without a kubeconfig secret")
+	}
+}
+
+func TestRefreshControlPlanes(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.rawConfig = &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"host": {Server: "https://host.example.com:6443"},
+			"its":  {Server: "https://its1.example.com:6443"},
+		},
+		Contexts: map[string]*api.Context{
+			"host": {Cluster: "host"},
+			"its1": {Cluster: "its"}, // already imported, e.g. by the bootstrap workflow
+		},
+	}
+
+	controlPlane := func(name, cpType string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "tenancy.kflex.kubestellar.org/v1alpha1",
+			"kind":       "ControlPlane",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec":       map[string]interface{}{"type": cpType},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+				"secretRef":  map[string]interface{}{"name": "admin-kubeconfig", "namespace": name + "-system", "key": "kubeconfig"},
+			},
+		}}
+	}
+	kubeconfigSecret := func(name, server string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin-kubeconfig", Namespace: name + "-system"},
+			Data:       map[string][]byte{"kubeconfig": []byte(fmt.Sprintf(testMountedKubeconfig, server, name, "token"))},
+		}
+	}
+	m.dynamicClients["host"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kubeflexControlPlaneGVR: "ControlPlaneList"},
+		controlPlane("wds1", "k8s"), controlPlane("its1", "vcluster"), controlPlane("host", "host"))
+	m.clients["host"] = k8sfake.NewSimpleClientset(
+		kubeconfigSecret("wds1", "https://wds1.example.com:6443"),
+		kubeconfigSecret("its1", "https://its1.example.com:6443"),
+	)
+	m.dynamicClients["its1"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kubeflexControlPlaneGVR: "ControlPlaneList"})
+
+	planes, err := m.RefreshControlPlanes(context.Background())
+	if err != nil {
+		t.Fatalf("RefreshControlPlanes failed: %v", err)
+	}
+	contexts := make(map[string]string)
+	for _, cp := range planes {
+		contexts[cp.Name] = cp.Context
+	}
+	if contexts["wds1"] != "wds1" || contexts["its1"] != "its1" || contexts["host"] != "" {
+		t.Errorf("Unexpected control plane contexts: %v", contexts)
+	}
+
+	clusters, err := m.ListClusters(context.Background())
+	if err != nil {
+		t.Fatalf("ListClusters failed: %v", err)
+	}
+	sources := make(map[string]string)
+	for _, c := range clusters {
+		sources[c.Context] = c.Source
+	}
+	if sources["wds1"] != ClusterSourceKubeFlex || sources["its1"] != "kubeconfig" || len(clusters) != 3 {
+		t.Errorf("Unexpected clusters: %v", sources)
+	}
+
+	config, err := m.contextRestConfigLocked("wds1")
+	if err != nil || config.Host != "https://wds1.example.com:6443" {
+		t.Errorf("contextRestConfigLocked(wds1) = %v, %v", config, err)
+	}
+}
@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxTailStreams caps the container log streams a single tail session opens
+const maxTailStreams = 50
+
+// tailColors are the color hints handed out to pods and containers, so the
+// same pod keeps the same color across sessions
+var tailColors = []string{"cyan", "green", "magenta", "yellow", "blue", "red", "bright-cyan", "bright-green", "bright-magenta", "bright-yellow", "bright-blue", "bright-red"}
+
+// LogTailOptions configures a multi-pod log tail
+type LogTailOptions struct {
+	// Container is a regular expression matched against container names; empty tails all
+	Container    string
+	TailLines    int64
+	SinceSeconds int64
+	Timestamps   bool
+}
+
+// LogTailEvent is one message of a multi-pod log tail
+type LogTailEvent struct {
+	Type           string `json:"type"` // log, pod_added, pod_removed, error
+	Pod            string `json:"pod,omitempty"`
+	Container      string `json:"container,omitempty"`
+	Line           string `json:"line,omitempty"`
+	Color          string `json:"color,omitempty"`          // color hint for the pod prefix
+	ContainerColor string `json:"containerColor,omitempty"` // color hint for the container prefix
+	Error          string `json:"error,omitempty"`
+}
+
+// TailPodLogs follows the logs of every container of every pod matching the
+// label selector, including pods that start after the tail began, and
+// multiplexes the lines through emit. emit is never called concurrently. It
+// blocks until ctx is cancelled.
+func (m *MultiClusterClient) TailPodLogs(ctx context.Context, contextName, namespace, selector string, opts LogTailOptions, emit func(LogTailEvent)) error {
+	podSelector, err := labels.Parse(selector)
+	if err != nil {
+		return fmt.Errorf("invalid label selector: %w", err)
+	}
+	var containerFilter *regexp.Regexp
+	if opts.Container != "" {
+		re, err := regexp.Compile(opts.Container)
+		if err != nil {
+			return fmt.Errorf("invalid container pattern: %w", err)
+		}
+		containerFilter = re
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return err
+	}
+
+	t := &logTail{
+		client:    client,
+		namespace: namespace,
+		opts:      opts,
+		filter:    containerFilter,
+		selector:  podSelector,
+		active:    make(map[string]context.CancelFunc),
+		pods:      make(map[string]bool),
+	}
+	t.emit = func(e LogTailEvent) {
+		t.emitMu.Lock()
+		defer t.emitMu.Unlock()
+		emit(e)
+	}
+	defer t.wg.Wait()
+
+	list, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		t.sync(ctx, &list.Items[i])
+	}
+
+	resourceVersion := list.ResourceVersion
+	for {
+		w, err := client.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector, ResourceVersion: resourceVersion})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if done := t.follow(ctx, w, &resourceVersion); done {
+			return nil
+		}
+	}
+}
+
+// follow applies watch events until the watch closes or ctx is cancelled, and
+// reports whether the session is over. The API server closes watches
+// periodically, after which the caller resumes from resourceVersion.
+func (t *logTail) follow(ctx context.Context, w watch.Interface, resourceVersion *string) bool {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return ctx.Err() != nil
+			}
+			pod, ok := ev.Object.(*corev1.Pod)
+			if !ok || !t.selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			*resourceVersion = pod.ResourceVersion
+			if ev.Type == watch.Deleted {
+				t.remove(pod.Name)
+				continue
+			}
+			t.sync(ctx, pod)
+		}
+	}
+}
+
+// logTail tracks the container streams of one TailPodLogs session
+type logTail struct {
+	client    kubernetes.Interface
+	namespace string
+	opts      LogTailOptions
+	filter    *regexp.Regexp
+	selector  labels.Selector
+	emit      func(LogTailEvent)
+	emitMu    sync.Mutex
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	active  map[string]context.CancelFunc // pod/container#restarts -> stream cancel, kept until the pod is deleted
+	pods    map[string]bool
+	limited bool
+}
+
+// sync starts streams for containers of the pod that are running and not yet tailed.
+// Keys include the restart count so a restarted container is tailed again.
+func (t *logTail) sync(ctx context.Context, pod *corev1.Pod) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.pods[pod.Name] && pod.Status.Phase == corev1.PodRunning {
+		t.pods[pod.Name] = true
+		t.emit(LogTailEvent{Type: "pod_added", Pod: pod.Name, Color: tailColor(pod.Name)})
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Running == nil || (t.filter != nil && !t.filter.MatchString(cs.Name)) {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s#%d", pod.Name, cs.Name, cs.RestartCount)
+		if _, ok := t.active[key]; ok {
+			continue
+		}
+		if len(t.active) >= maxTailStreams {
+			if !t.limited {
+				t.limited = true
+				t.emit(LogTailEvent{Type: "error", Error: fmt.Sprintf("stream limit of %d containers reached; narrow the selector to see more pods", maxTailStreams)})
+			}
+			return
+		}
+		streamCtx, cancel := context.WithCancel(ctx)
+		t.active[key] = cancel
+		opts := t.opts
+		if _, followed := t.active[fmt.Sprintf("%s/%s#%d", pod.Name, cs.Name, cs.RestartCount-1)]; followed {
+			// A new instance of a container we were already following: start from its beginning
+			opts.TailLines, opts.SinceSeconds = 0, 0
+		}
+		t.wg.Add(1)
+		go func(pod, container string) {
+			defer t.wg.Done()
+			t.stream(streamCtx, pod, container, opts)
+		}(pod.Name, cs.Name)
+	}
+}
+
+// remove stops all streams of a deleted pod; a pod recreated with the same
+// name, as StatefulSet pods are, is tailed again
+func (t *logTail) remove(pod string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	prefix := pod + "/"
+	for key, cancel := range t.active {
+		if strings.HasPrefix(key, prefix) {
+			cancel()
+			delete(t.active, key)
+		}
+	}
+	if t.pods[pod] {
+		delete(t.pods, pod)
+		t.emit(LogTailEvent{Type: "pod_removed", Pod: pod, Color: tailColor(pod)})
+	}
+}
+
+// stream follows one container's logs until it exits or ctx is cancelled
+func (t *logTail) stream(ctx context.Context, pod, container string, opts LogTailOptions) {
+	logOpts := &corev1.PodLogOptions{Container: container, Follow: true, Timestamps: opts.Timestamps}
+	if opts.TailLines > 0 {
+		logOpts.TailLines = &opts.TailLines
+	}
+	if opts.SinceSeconds > 0 {
+		logOpts.SinceSeconds = &opts.SinceSeconds
+	}
+	stream, err := t.client.CoreV1().Pods(t.namespace).GetLogs(pod, logOpts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.emit(LogTailEvent{Type: "error", Pod: pod, Container: container, Error: err.Error()})
+		}
+		return
+	}
+	defer stream.Close()
+
+	color, containerColor := tailColor(pod), tailColor(container)
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineBytes)
+	for scanner.Scan() {
+		t.emit(LogTailEvent{
+			Type:           "log",
+			Pod:            pod,
+			Container:      container,
+			Line:           scanner.Text(),
+			Color:          color,
+			ContainerColor: containerColor,
+		})
+	}
+}
+
+// tailColor picks a stable color hint for a name
+func tailColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return tailColors[h.Sum32()%uint32(len(tailColors))]
+}
@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func runningPod(name string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "api"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	for _, c := range containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  c,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+func TestTailPodLogs(t *testing.T) {
+	fake := k8sfake.NewSimpleClientset(runningPod("api-1", "app", "sidecar"))
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", fake)
+
+	events := make(chan LogTailEvent, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- m.TailPodLogs(ctx, "c1", "default", "app=api", LogTailOptions{Container: "^app$"}, func(e LogTailEvent) {
+			events <- e
+		})
+	}()
+
+	next := func() LogTailEvent {
+		t.Helper()
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a tail event")
+		}
+		return LogTailEvent{}
+	}
+
+	if e := next(); e.Type != "pod_added" || e.Pod != "api-1" || e.Color == "" {
+		t.Fatalf("Expected api-1 to be added with a color, got %+v", e)
+	}
+	// The fake clientset returns "fake logs" for every stream; the sidecar is filtered out
+	if e := next(); e.Type != "log" || e.Container != "app" || e.Line != "fake logs" || e.Color != tailColor("api-1") {
+		t.Fatalf("Expected a prefixed line from api-1/app, got %+v", e)
+	}
+
+	// Pods started after the tail began are picked up from the watch
+	fake.CoreV1().Pods("default").Create(ctx, runningPod("api-2", "app"), metav1.CreateOptions{})
+	if e := next(); e.Type != "pod_added" || e.Pod != "api-2" {
+		t.Fatalf("Expected api-2 to be added, got %+v", e)
+	}
+	if e := next(); e.Type != "log" || e.Pod != "api-2" {
+		t.Fatalf("Expected a line from api-2, got %+v", e)
+	}
+
+	fake.CoreV1().Pods("default").Delete(ctx, "api-1", metav1.DeleteOptions{})
+	if e := next(); e.Type != "pod_removed" || e.Pod != "api-1" {
+		t.Fatalf("Expected api-1 to be removed, got %+v", e)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("TailPodLogs did not return after cancel")
+	}
+
+	if err := m.TailPodLogs(context.Background(), "c1", "default", "app in (", LogTailOptions{}, func(LogTailEvent) {}); err == nil {
+		t.Error("Expected an invalid selector to be rejected")
+	}
+}
@@ -0,0 +1,423 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// Manifest finding severities
+const (
+	FindingError   = "error"
+	FindingWarning = "warning"
+	FindingInfo    = "info"
+)
+
+// manifestDecodeBufferSize is the lookahead the YAML/JSON decoder uses to sniff the format
+const manifestDecodeBufferSize = 4096
+
+// ManifestFinding is a problem found while validating one object of a manifest
+type ManifestFinding struct {
+	Object   string `json:"object,omitempty"` // Kind/namespace/name
+	Severity string `json:"severity"`         // error, warning, info
+	Source   string `json:"source"`           // parse, discovery, schema, dry-run, security, resources
+	Message  string `json:"message"`
+}
+
+// ManifestValidation is the outcome of validating a manifest against a cluster
+type ManifestValidation struct {
+	Cluster  string            `json:"cluster"`
+	Valid    bool              `json:"valid"` // no error findings
+	Objects  int               `json:"objects"`
+	Findings []ManifestFinding `json:"findings"`
+}
+
+// ValidateManifest checks multi-document YAML or JSON against a cluster
+// without changing it: each object is validated against its CRD's structural
+// schema, submitted as a server-side dry-run, and its pod template is run
+// through the security and resource checks. Objects without a namespace are
+// validated in namespace.
+func (m *MultiClusterClient) ValidateManifest(ctx context.Context, contextName, namespace string, manifest []byte) (*ManifestValidation, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	result := &ManifestValidation{Cluster: contextName, Findings: []ManifestFinding{}}
+	objects, err := decodeManifest(manifest)
+	if err != nil {
+		result.Findings = append(result.Findings, ManifestFinding{Severity: FindingError, Source: "parse", Message: err.Error()})
+		return result, nil
+	}
+	result.Objects = len(objects)
+
+	resources := make(map[string]*metav1.APIResourceList)
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+		ref := fmt.Sprintf("%s/%s/%s", gvk.Kind, obj.GetNamespace(), obj.GetName())
+		add := func(severity, source, message string) {
+			result.Findings = append(result.Findings, ManifestFinding{Object: ref, Severity: severity, Source: source, Message: message})
+		}
+
+		gv := gvk.GroupVersion().String()
+		list, ok := resources[gv]
+		if !ok {
+			list, _ = client.Discovery().ServerResourcesForGroupVersion(gv)
+			resources[gv] = list
+		}
+		apiResource := findAPIResource(list, gvk.Kind)
+		if apiResource == nil {
+			add(FindingError, "discovery", fmt.Sprintf("%s is not served by cluster %s", gvk.GroupKind(), contextName))
+			continue
+		}
+		gvr := gvk.GroupVersion().WithResource(apiResource.Name)
+		if !apiResource.Namespaced {
+			ref = fmt.Sprintf("%s/%s", gvk.Kind, obj.GetName())
+			obj.SetNamespace("")
+		}
+
+		for _, msg := range m.validateAgainstCRD(ctx, contextName, gvr, obj) {
+			add(FindingError, "schema", msg)
+		}
+		if err := dryRunApply(ctx, dynamicClient, gvr, obj); err != nil {
+			add(FindingError, "dry-run", err.Error())
+		}
+		if spec := podSpecOf(obj); spec != nil {
+			for _, issue := range podSecurityIssues(obj.GetName(), obj.GetNamespace(), contextName, spec) {
+				severity := FindingWarning
+				if issue.Severity == "low" {
+					severity = FindingInfo
+				}
+				add(severity, "security", fmt.Sprintf("%s: %s", issue.Issue, issue.Details))
+			}
+			for _, msg := range resourceFindings(spec) {
+				add(FindingWarning, "resources", msg)
+			}
+		}
+	}
+
+	result.Valid = true
+	for _, f := range result.Findings {
+		if f.Severity == FindingError {
+			result.Valid = false
+			break
+		}
+	}
+	return result, nil
+}
+
+// decodeManifest splits a multi-document YAML or JSON manifest into objects,
+// expanding List kinds
+func decodeManifest(manifest []byte) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), manifestDecodeBufferSize)
+	var objects []*unstructured.Unstructured
+	for doc := 1; ; doc++ {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("document %d: %w", doc, err)
+		}
+		if len(raw) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: raw}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %w", doc, err)
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("document %d: apiVersion and kind are required", doc)
+		}
+		objects = append(objects, obj)
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("manifest contains no objects")
+	}
+	return objects, nil
+}
+
+func findAPIResource(list *metav1.APIResourceList, kind string) *metav1.APIResource {
+	if list == nil {
+		return nil
+	}
+	for i := range list.APIResources {
+		r := &list.APIResources[i]
+		// Skip subresources such as deployments/scale, which share the kind
+		if r.Kind == kind && !strings.Contains(r.Name, "/") {
+			return r
+		}
+	}
+	return nil
+}
+
+// dryRunApply submits an object as a server-side dry-run create, or update
+// when it already exists, with strict field validation
+func dryRunApply(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	var resource dynamic.ResourceInterface = client.Resource(gvr)
+	if ns := obj.GetNamespace(); ns != "" {
+		resource = client.Resource(gvr).Namespace(ns)
+	}
+	_, err := resource.Create(ctx, obj, metav1.CreateOptions{
+		DryRun:          []string{metav1.DryRunAll},
+		FieldValidation: metav1.FieldValidationStrict,
+	})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	update := obj.DeepCopy()
+	update.SetResourceVersion(existing.GetResourceVersion())
+	_, err = resource.Update(ctx, update, metav1.UpdateOptions{
+		DryRun:          []string{metav1.DryRunAll},
+		FieldValidation: metav1.FieldValidationStrict,
+	})
+	return err
+}
+
+// podSpecOf returns the pod template of a Pod or workload object, or nil
+func podSpecOf(obj *unstructured.Unstructured) *corev1.PodSpec {
+	switch obj.GroupVersionKind().Group {
+	case "", "apps", "batch":
+	default:
+		return nil
+	}
+	var spec *corev1.PodSpec
+	var target interface{}
+	switch obj.GetKind() {
+	case "Pod":
+		pod := &corev1.Pod{}
+		target, spec = pod, &pod.Spec
+	case "Deployment":
+		d := &appsv1.Deployment{}
+		target, spec = d, &d.Spec.Template.Spec
+	case "StatefulSet":
+		ss := &appsv1.StatefulSet{}
+		target, spec = ss, &ss.Spec.Template.Spec
+	case "DaemonSet":
+		ds := &appsv1.DaemonSet{}
+		target, spec = ds, &ds.Spec.Template.Spec
+	case "ReplicaSet":
+		rs := &appsv1.ReplicaSet{}
+		target, spec = rs, &rs.Spec.Template.Spec
+	case "Job":
+		job := &batchv1.Job{}
+		target, spec = job, &job.Spec.Template.Spec
+	case "CronJob":
+		cj := &batchv1.CronJob{}
+		target, spec = cj, &cj.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, target); err != nil {
+		return nil
+	}
+	return spec
+}
+
+// resourceFindings reports containers without CPU or memory requests and limits
+func resourceFindings(spec *corev1.PodSpec) []string {
+	var findings []string
+	for _, c := range spec.Containers {
+		var missing []string
+		for _, check := range []struct {
+			list corev1.ResourceList
+			name corev1.ResourceName
+			kind string
+		}{
+			{c.Resources.Requests, corev1.ResourceCPU, "cpu request"},
+			{c.Resources.Requests, corev1.ResourceMemory, "memory request"},
+			{c.Resources.Limits, corev1.ResourceMemory, "memory limit"},
+		} {
+			if _, ok := check.list[check.name]; !ok {
+				missing = append(missing, check.kind)
+			}
+		}
+		if len(missing) > 0 {
+			findings = append(findings, fmt.Sprintf("Container '%s' has no %s", c.Name, strings.Join(missing, ", ")))
+		}
+	}
+	return findings
+}
+
+// validateAgainstCRD checks a custom resource against the structural schema of
+// its CRD. Returns nothing for built-in kinds or CRDs without a schema.
+func (m *MultiClusterClient) validateAgainstCRD(ctx context.Context, contextName string, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) []string {
+	if !strings.Contains(gvr.Group, ".") {
+		return nil // built-in groups have no CRD
+	}
+	dynamicClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil
+	}
+	crd, err := dynamicClient.Resource(gvrCRDs).Get(ctx, gvr.Resource+"."+gvr.Group, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok || version["name"] != gvr.Version {
+			continue
+		}
+		openAPISchema, found, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		if !found {
+			return nil
+		}
+		return validateStructural(obj.Object, openAPISchema, "", true)
+	}
+	return nil
+}
+
+// validateStructural checks a value against a structural OpenAPI v3 schema:
+// types, enums, required and unknown fields, recursing into objects and arrays
+func validateStructural(value interface{}, s map[string]interface{}, path string, root bool) []string {
+	if value == nil {
+		return nil
+	}
+	field := path
+	if field == "" {
+		field = "<root>"
+	}
+	if intOrString, _ := s["x-kubernetes-int-or-string"].(bool); intOrString {
+		switch value.(type) {
+		case string, int64, float64:
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: must be an integer or a string", field)}
+	}
+
+	var problems []string
+	typ, _ := s["type"].(string)
+	if typ != "" && !matchesSchemaType(value, typ) {
+		return []string{fmt.Sprintf("%s: expected %s, got %T", field, typ, value)}
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		allowed := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", field, value, enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		required, _ := s["required"].([]interface{})
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, present := v[name]; !present {
+					problems = append(problems, fmt.Sprintf("%s: missing required field %q", field, name))
+				}
+			}
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		additional, _ := s["additionalProperties"].(map[string]interface{})
+		preserve, _ := s["x-kubernetes-preserve-unknown-fields"].(bool)
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := joinFieldPath(path, k)
+			if propSchema, ok := properties[k].(map[string]interface{}); ok {
+				problems = append(problems, validateStructural(v[k], propSchema, child, false)...)
+				continue
+			}
+			switch {
+			case additional != nil:
+				problems = append(problems, validateStructural(v[k], additional, child, false)...)
+			case root && (k == "apiVersion" || k == "kind" || k == "metadata"):
+			case preserve || (properties == nil && typ != "object"):
+			case properties != nil || typ == "object":
+				problems = append(problems, fmt.Sprintf("%s: unknown field", child))
+			}
+		}
+	case []interface{}:
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				problems = append(problems, validateStructural(item, items, fmt.Sprintf("%s[%d]", path, i), false)...)
+			}
+		}
+	}
+	return problems
+}
+
+func matchesSchemaType(value interface{}, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		switch n := value.(type) {
+		case int64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		}
+		return false
+	case "number":
+		switch value.(type) {
+		case int64, float64:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+const testManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels: {app: web}
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        securityContext:
+          privileged: true
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w1
+spec:
+  size: large
+  colour: blue
+---
+apiVersion: unknown.example.com/v1
+kind: Gadget
+metadata:
+  name: g1
+`
+
+func TestValidateManifest(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	client := k8sfake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true},
+			{Name: "deployments/scale", Kind: "Scale", Namespaced: true},
+		}},
+		{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: true},
+		}},
+	}
+	m.clients["dev"] = client
+
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "widgets.example.com"},
+		"spec": map[string]interface{}{"versions": []interface{}{map[string]interface{}{
+			"name": "v1",
+			"schema": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{"spec": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"replicas"},
+					"properties": map[string]interface{}{
+						"size":     map[string]interface{}{"type": "string", "enum": []interface{}{"small", "medium"}},
+						"replicas": map[string]interface{}{"type": "integer"},
+					},
+				}},
+			}},
+		}}},
+	}}
+	m.dynamicClients["dev"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvrCRDs: "CustomResourceDefinitionList"}, crd)
+
+	result, err := m.ValidateManifest(context.Background(), "dev", "team-a", []byte(testManifest))
+	if err != nil {
+		t.Fatalf("ValidateManifest failed: %v", err)
+	}
+	if result.Valid || result.Objects != 3 {
+		t.Fatalf("Expected 3 objects and an invalid result, got %+v", result)
+	}
+
+	found := make(map[string][]string)
+	for _, f := range result.Findings {
+		found[f.Object+" "+f.Source] = append(found[f.Object+" "+f.Source], f.Message)
+	}
+	expect := map[string]string{
+		"Deployment/team-a/web security":  "Privileged container",
+		"Deployment/team-a/web resources": "no cpu request, memory request, memory limit",
+		"Widget/team-a/w1 schema":         `missing required field "replicas"`,
+		"Gadget/team-a/g1 discovery":      "not served",
+	}
+	for key, substr := range expect {
+		if !strings.Contains(strings.Join(found[key], "; "), substr) {
+			t.Errorf("Expected %s finding containing %q, got %v", key, substr, found[key])
+		}
+	}
+	schemaMessages := strings.Join(found["Widget/team-a/w1 schema"], "; ")
+	for _, substr := range []string{"spec.colour: unknown field", `spec.size: large is not one of`} {
+		if !strings.Contains(schemaMessages, substr) {
+			t.Errorf("Expected schema finding %q, got %s", substr, schemaMessages)
+		}
+	}
+	if len(found["Deployment/team-a/web dry-run"]) != 0 {
+		t.Errorf("Unexpected dry-run findings: %v", found["Deployment/team-a/web dry-run"])
+	}
+
+	parseErr, err := m.ValidateManifest(context.Background(), "dev", "", []byte("kind: [unterminated"))
+	if err != nil || parseErr.Valid || len(parseErr.Findings) != 1 || parseErr.Findings[0].Source != "parse" {
+		t.Errorf("Expected a single parse finding, got %+v, %v", parseErr, err)
+	}
+}
@@ -50,6 +50,11 @@ func (m *MultiClusterClient) ListServiceExports(ctx context.Context) (*v1alpha1.
 
 // ListServiceExportsForCluster lists ServiceExport resources in a specific cluster
 func (m *MultiClusterClient) ListServiceExportsForCluster(ctx context.Context, contextName, namespace string) ([]v1alpha1.ServiceExport, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]v1alpha1.ServiceExport, error) {
+			return m.ListServiceExportsForCluster(ctx, contextName, ns)
+		})
+	}
 	dynamicClient, err := m.GetDynamicClient(contextName)
 	if err != nil {
 		return nil, err
@@ -139,6 +144,11 @@ func (m *MultiClusterClient) ListServiceImports(ctx context.Context) (*v1alpha1.
 
 // ListServiceImportsForCluster lists ServiceImport resources in a specific cluster
 func (m *MultiClusterClient) ListServiceImportsForCluster(ctx context.Context, contextName, namespace string) ([]v1alpha1.ServiceImport, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]v1alpha1.ServiceImport, error) {
+			return m.ListServiceImportsForCluster(ctx, contextName, ns)
+		})
+	}
 	dynamicClient, err := m.GetDynamicClient(contextName)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,202 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxMetadataEditTargets caps how many objects one selector edit may change
+const maxMetadataEditTargets = 500
+
+// ErrInvalidMetadataEdit is returned when a label or annotation edit is malformed
+var ErrInvalidMetadataEdit = errors.New("invalid metadata edit")
+
+// MetadataEdit adds and removes labels and annotations on one object, named by
+// Name, or on every object of the kind matching Selector
+type MetadataEdit struct {
+	APIVersion        string            `json:"apiVersion"`
+	Kind              string            `json:"kind"`
+	Namespace         string            `json:"namespace,omitempty"` // empty for all namespaces with a selector
+	Name              string            `json:"name,omitempty"`
+	Selector          string            `json:"selector,omitempty"`
+	AddLabels         map[string]string `json:"addLabels,omitempty"`
+	RemoveLabels      []string          `json:"removeLabels,omitempty"`
+	AddAnnotations    map[string]string `json:"addAnnotations,omitempty"`
+	RemoveAnnotations []string          `json:"removeAnnotations,omitempty"`
+	DryRun            bool              `json:"dryRun,omitempty"`
+}
+
+// MetadataEditResult is the labels and annotations an object has after an
+// edit, or would have for a dry run
+type MetadataEditResult struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Changed     bool              `json:"changed"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// MetadataEditReport is the outcome of a label and annotation edit
+type MetadataEditReport struct {
+	Cluster string               `json:"cluster"`
+	DryRun  bool                 `json:"dryRun"`
+	Results []MetadataEditResult `json:"results"`
+}
+
+// validate checks that the edit targets something and that its keys and
+// label values are well formed
+func (e *MetadataEdit) validate() error {
+	if e.APIVersion == "" || e.Kind == "" {
+		return fmt.Errorf("%w: apiVersion and kind are required", ErrInvalidMetadataEdit)
+	}
+	if (e.Name == "") == (e.Selector == "") {
+		return fmt.Errorf("%w: exactly one of name and selector is required", ErrInvalidMetadataEdit)
+	}
+	if len(e.AddLabels)+len(e.RemoveLabels)+len(e.AddAnnotations)+len(e.RemoveAnnotations) == 0 {
+		return fmt.Errorf("%w: no labels or annotations to change", ErrInvalidMetadataEdit)
+	}
+	var problems []string
+	for k, v := range e.AddLabels {
+		problems = append(problems, validation.IsQualifiedName(k)...)
+		problems = append(problems, validation.IsValidLabelValue(v)...)
+	}
+	for k := range e.AddAnnotations {
+		problems = append(problems, validation.IsQualifiedName(k)...)
+	}
+	for _, k := range append(append([]string(nil), e.RemoveLabels...), e.RemoveAnnotations...) {
+		problems = append(problems, validation.IsQualifiedName(k)...)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidMetadataEdit, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// EditMetadata applies a label and annotation edit with a merge patch per
+// object, reporting each object's resulting metadata. With DryRun nothing is
+// patched and the results preview the change. A selector matching more than
+// maxMetadataEditTargets objects is refused.
+func (m *MultiClusterClient) EditMetadata(ctx context.Context, contextName string, edit MetadataEdit) (*MetadataEditReport, error) {
+	if err := edit.validate(); err != nil {
+		return nil, err
+	}
+	gvr, namespaced, err := m.resolveKind(contextName, edit.APIVersion, edit.Kind)
+	if err != nil {
+		return nil, err
+	}
+	namespace := edit.Namespace
+	if !namespaced {
+		namespace = ""
+	}
+	client, err := m.GetMetadataClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	resource := client.Resource(gvr)
+
+	var targets []metav1.PartialObjectMetadata
+	if edit.Name != "" {
+		obj, err := resource.Namespace(namespace).Get(ctx, edit.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		targets = []metav1.PartialObjectMetadata{*obj}
+	} else {
+		list, err := resource.Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: edit.Selector})
+		if err != nil {
+			return nil, err
+		}
+		if len(list.Items) > maxMetadataEditTargets {
+			return nil, fmt.Errorf("%w: selector matches %d objects, more than %d", ErrInvalidMetadataEdit, len(list.Items), maxMetadataEditTargets)
+		}
+		targets = list.Items
+	}
+
+	report := &MetadataEditReport{Cluster: contextName, DryRun: edit.DryRun, Results: make([]MetadataEditResult, 0, len(targets))}
+	for _, obj := range targets {
+		labels, labelPatch := applyMetadataEdit(obj.Labels, edit.AddLabels, edit.RemoveLabels)
+		annotations, annotationPatch := applyMetadataEdit(obj.Annotations, edit.AddAnnotations, edit.RemoveAnnotations)
+		result := MetadataEditResult{
+			Name:        obj.Name,
+			Namespace:   obj.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			Changed:     labelPatch != nil || annotationPatch != nil,
+		}
+		if result.Changed && !edit.DryRun {
+			meta := map[string]interface{}{}
+			if labelPatch != nil {
+				meta["labels"] = labelPatch
+			}
+			if annotationPatch != nil {
+				meta["annotations"] = annotationPatch
+			}
+			patch, _ := json.Marshal(map[string]interface{}{"metadata": meta})
+			if _, err := resource.Namespace(obj.Namespace).Patch(ctx, obj.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+				result.Labels, result.Annotations = obj.Labels, obj.Annotations
+				result.Changed = false
+				result.Error = err.Error()
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// applyMetadataEdit returns current with add set and remove deleted, and the
+// merge patch doing so, which is nil when nothing changes
+func applyMetadataEdit(current, add map[string]string, remove []string) (map[string]string, map[string]interface{}) {
+	updated := maps.Clone(current)
+	if updated == nil {
+		updated = make(map[string]string)
+	}
+	patch := make(map[string]interface{})
+	for _, k := range remove {
+		if _, ok := updated[k]; ok {
+			delete(updated, k)
+			patch[k] = nil
+		}
+	}
+	for k, v := range add {
+		if cur, ok := updated[k]; !ok || cur != v {
+			updated[k] = v
+			patch[k] = v
+		}
+	}
+	if len(patch) == 0 {
+		return current, nil
+	}
+	return updated, patch
+}
+
+// resolveKind finds the resource serving kind in apiVersion and whether it is namespaced
+func (m *MultiClusterClient) resolveKind(contextName, apiVersion, kind string) (schema.GroupVersionResource, bool, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("%w: %v", ErrInvalidMetadataEdit, err)
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+	list, err := client.Discovery().ServerResourcesForGroupVersion(gv.String())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return schema.GroupVersionResource{}, false, err
+	}
+	r := findAPIResource(list, kind)
+	if r == nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("%w: %s %s is not served by cluster %s", ErrInvalidMetadataEdit, apiVersion, kind, contextName)
+	}
+	return gv.WithResource(r.Name), r.Namespaced, nil
+}
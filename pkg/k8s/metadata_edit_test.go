@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestEditMetadata(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1"}}})
+
+	client := k8sfake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "nodes", Kind: "Node"},
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+		}},
+	}
+	m.clients["c1"] = client
+
+	scheme := runtime.NewScheme()
+	metav1.AddMetaToScheme(scheme)
+	node := func(name string, labels map[string]string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		}
+	}
+	fakeMeta := metadatafake.NewSimpleMetadataClient(scheme,
+		node("gpu-1", map[string]string{"accelerator": "h100", "old": "x"}),
+		node("gpu-2", map[string]string{"accelerator": "h100", "pool": "training"}),
+		node("cpu-1", map[string]string{"accelerator": "none"}),
+	)
+	m.InjectMetadataClient("c1", fakeMeta)
+	nodesGVR := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+	edit := MetadataEdit{
+		APIVersion:     "v1",
+		Kind:           "Node",
+		Selector:       "accelerator=h100",
+		AddLabels:      map[string]string{"pool": "training"},
+		RemoveLabels:   []string{"old"},
+		AddAnnotations: map[string]string{"example.com/owner": "ml-team"},
+		DryRun:         true,
+	}
+	report, err := m.EditMetadata(context.Background(), "c1", edit)
+	if err != nil {
+		t.Fatalf("EditMetadata dry run failed: %v", err)
+	}
+	if !report.DryRun || len(report.Results) != 2 {
+		t.Fatalf("Expected a dry run over 2 nodes, got %+v", report)
+	}
+	for _, r := range report.Results {
+		if !r.Changed || r.Labels["pool"] != "training" || r.Labels["old"] != "" || r.Annotations["example.com/owner"] != "ml-team" {
+			t.Errorf("Unexpected preview: %+v", r)
+		}
+	}
+	if obj, _ := fakeMeta.Resource(nodesGVR).Get(context.Background(), "gpu-1", metav1.GetOptions{}); obj.Labels["old"] != "x" {
+		t.Errorf("Expected the dry run to leave gpu-1 unchanged, got %v", obj.Labels)
+	}
+
+	edit.DryRun = false
+	edit.AddAnnotations = nil
+	if _, err := m.EditMetadata(context.Background(), "c1", edit); err != nil {
+		t.Fatalf("EditMetadata failed: %v", err)
+	}
+	obj, _ := fakeMeta.Resource(nodesGVR).Get(context.Background(), "gpu-1", metav1.GetOptions{})
+	if obj.Labels["pool"] != "training" || obj.Labels["accelerator"] != "h100" {
+		t.Errorf("Expected gpu-1 to join the pool, got %v", obj.Labels)
+	}
+	if _, ok := obj.Labels["old"]; ok {
+		t.Errorf("Expected the old label to be removed, got %v", obj.Labels)
+	}
+
+	// A second run changes nothing
+	report, err = m.EditMetadata(context.Background(), "c1", edit)
+	if err != nil {
+		t.Fatalf("EditMetadata failed: %v", err)
+	}
+	for _, r := range report.Results {
+		if r.Changed {
+			t.Errorf("Expected no change on %s", r.Name)
+		}
+	}
+
+	for name, bad := range map[string]MetadataEdit{
+		"no target":     {APIVersion: "v1", Kind: "Node", AddLabels: map[string]string{"a": "b"}},
+		"bad value":     {APIVersion: "v1", Kind: "Node", Name: "gpu-1", AddLabels: map[string]string{"a": "not valid!"}},
+		"unserved kind": {APIVersion: "v1", Kind: "Gadget", Name: "g", AddLabels: map[string]string{"a": "b"}},
+		"no changes":    {APIVersion: "v1", Kind: "Node", Name: "gpu-1"},
+	} {
+		if _, err := m.EditMetadata(context.Background(), "c1", bad); !errors.Is(err, ErrInvalidMetadataEdit) {
+			t.Errorf("%s: expected ErrInvalidMetadataEdit, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestProtobufConfig(t *testing.T) {
+	base := &rest.Config{Host: "https://example.com"}
+	cfg := protobufConfig(base)
+	if cfg.ContentType != "application/vnd.kubernetes.protobuf" {
+		t.Errorf("Expected protobuf content type, got %q", cfg.ContentType)
+	}
+	if base.ContentType != "" || base.AcceptContentTypes != "" {
+		t.Error("Expected the shared JSON config to be left untouched for dynamic clients")
+	}
+}
+
+func TestListObjectMetadata(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1"}}})
+
+	scheme := runtime.NewScheme()
+	metav1.AddMetaToScheme(scheme)
+	toolGVR := schema.GroupVersionResource{Group: "agent.kagenti.dev", Version: "v1alpha1", Resource: "tools"}
+	partial := func(apiVersion, kind, ns, name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		}
+	}
+	m.InjectMetadataClient("c1", metadatafake.NewSimpleMetadataClient(scheme,
+		partial("v1", "Namespace", "", "default"),
+		partial("v1", "Namespace", "", "ml"),
+		partial("agent.kagenti.dev/v1alpha1", "Tool", "ml", "search"),
+	))
+
+	names, err := m.listAllNamespaces(context.Background(), "c1")
+	if err != nil || len(names) != 2 {
+		t.Fatalf("Expected 2 namespaces from metadata, got %v, %v", names, err)
+	}
+	if n, err := m.CountObjects(context.Background(), "c1", toolGVR, ""); err != nil || n != 1 {
+		t.Errorf("Expected 1 tool, got %d, %v", n, err)
+	}
+}
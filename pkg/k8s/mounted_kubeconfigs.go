@@ -0,0 +1,186 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// kubeconfigDirsEnv lists directories (separated like KUBECONFIG) holding kubeconfigs
+// for remote clusters, typically Secrets or ConfigMaps mounted into the pod. Every
+// file found in them, including in one level of subdirectories, is merged in.
+const kubeconfigDirsEnv = "KUBECONFIG_DIRS"
+
+// kubeconfigSourceMounted is the ClusterInfo.Source for contexts loaded from KUBECONFIG_DIRS
+const kubeconfigSourceMounted = "mounted"
+
+// kubeconfigDirsFromEnv returns the configured mounted kubeconfig directories
+func kubeconfigDirsFromEnv() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv(kubeconfigDirsEnv)) {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// mountedKubeconfigFiles returns the candidate kubeconfig files under dirs, in a stable
+// order. Kubelet's atomic-writer bookkeeping entries ("..data", "..2024_...") are skipped;
+// the visible names are symlinks into them.
+func mountedKubeconfigFiles(dirs []string) []string {
+	var files []string
+	var walk func(dir string, depth int)
+	walk = func(dir string, depth int) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			info, err := os.Stat(path) // follows symlinks
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				if depth == 0 {
+					walk(path, depth+1)
+				}
+				continue
+			}
+			files = append(files, path)
+		}
+	}
+	for _, dir := range dirs {
+		walk(dir, 0)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// mountedKubeconfigWatchDirs returns the directories to watch for Secret/ConfigMap updates
+func mountedKubeconfigWatchDirs(dirs []string) []string {
+	watch := append([]string{}, dirs...)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			if info, err := os.Stat(path); err == nil && info.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				watch = append(watch, path)
+			}
+		}
+	}
+	return watch
+}
+
+// mountedKubeconfigFingerprint changes whenever a mounted kubeconfig is added, removed or updated
+func mountedKubeconfigFingerprint(dirs []string) string {
+	h := sha256.New()
+	for _, path := range mountedKubeconfigFiles(dirs) {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(h, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadMountedKubeconfigs merges the kubeconfigs found under dirs. Cluster and user
+// entries are prefixed with their file name so identically named entries ("admin",
+// "cluster") in different Secrets don't collide; context names are kept as-is and
+// the first file to define a context wins. Files that aren't kubeconfigs are ignored.
+func loadMountedKubeconfigs(dirs []string) *api.Config {
+	merged := api.NewConfig()
+	for _, path := range mountedKubeconfigFiles(dirs) {
+		cfg, err := clientcmd.LoadFromFile(path)
+		if err != nil || len(cfg.Contexts) == 0 {
+			continue
+		}
+		if err := clientcmd.ResolveLocalPaths(cfg); err != nil {
+			log.Printf("Warning: mounted kubeconfig %s: %v", path, err)
+			continue
+		}
+
+		prefix := filepath.Base(filepath.Dir(path)) + "/" + filepath.Base(path) + "/"
+		for name, cluster := range cfg.Clusters {
+			merged.Clusters[prefix+name] = cluster
+		}
+		for name, user := range cfg.AuthInfos {
+			merged.AuthInfos[prefix+name] = user
+		}
+		for name, kctx := range cfg.Contexts {
+			if _, exists := merged.Contexts[name]; exists {
+				log.Printf("Warning: context %q in mounted kubeconfig %s already defined, skipping", name, path)
+				continue
+			}
+			c := kctx.DeepCopy()
+			c.Cluster = prefix + kctx.Cluster
+			c.AuthInfo = prefix + kctx.AuthInfo
+			merged.Contexts[name] = c
+		}
+	}
+	return merged
+}
+
+// loadMountedConfigLocked reloads contexts from the mounted kubeconfig directories.
+// Contexts that also exist in the main kubeconfig are dropped in favor of the main one.
+// Caller must hold m.mu.
+func (m *MultiClusterClient) loadMountedConfigLocked() {
+	if len(m.kubeconfigDirs) == 0 {
+		m.mountedConfig = nil
+		return
+	}
+	mounted := loadMountedKubeconfigs(m.kubeconfigDirs)
+	if m.rawConfig != nil {
+		for name := range mounted.Contexts {
+			if _, exists := m.rawConfig.Contexts[name]; exists {
+				log.Printf("Warning: mounted context %q shadowed by kubeconfig, skipping", name)
+				delete(mounted.Contexts, name)
+			}
+		}
+	}
+	m.mountedConfig = mounted
+	log.Printf("Loaded %d contexts from mounted kubeconfigs", len(mounted.Contexts))
+}
+
+// contextRestConfigLocked builds the REST config for a context from the mounted
+// kubeconfigs, the KubeFlex control planes or the kubeconfig file. Caller must hold m.mu.
+func (m *MultiClusterClient) contextRestConfigLocked(contextName string) (*rest.Config, error) {
+	for _, config := range []*api.Config{m.mountedConfig, m.controlPlaneConfig} {
+		if config == nil {
+			continue
+		}
+		if _, ok := config.Contexts[contextName]; ok {
+			return clientcmd.NewNonInteractiveClientConfig(*config, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+		}
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: m.kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).ClientConfig()
+}
+
+// isMountedKubeconfigEvent reports whether a watcher event path is inside a mounted kubeconfig directory
+func (m *MultiClusterClient) isMountedKubeconfigEvent(path string) bool {
+	for _, dir := range m.kubeconfigDirs {
+		// "..data" is inside the directory; "../x" is not
+		if rel, err := filepath.Rel(dir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return true
+		}
+	}
+	return false
+}
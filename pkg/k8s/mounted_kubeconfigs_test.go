@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testMountedKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: %s
+contexts:
+- name: %s
+  context:
+    cluster: cluster
+    user: admin
+users:
+- name: admin
+  user:
+    token: %s
+`
+
+// writeSecretMount lays out a directory the way kubelet mounts a Secret: the
+// visible key is a symlink through ..data into a timestamped directory
+func writeSecretMount(t *testing.T, dir, name, server, contextName, token string) {
+	t.Helper()
+	mount := filepath.Join(dir, name)
+	data := filepath.Join(mount, "..2026_01_01_00_00_00.000000001")
+	if err := os.MkdirAll(data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := fmt.Sprintf(testMountedKubeconfig, server, contextName, token)
+	if err := os.WriteFile(filepath.Join(data, "kubeconfig"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Base(data), filepath.Join(mount, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "kubeconfig"), filepath.Join(mount, "kubeconfig")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMountedKubeconfigs(t *testing.T) {
+	dir := t.TempDir()
+	writeSecretMount(t, dir, "prod-east", "https://east.example.com:6443", "prod-east", "east-token")
+	writeSecretMount(t, dir, "prod-west", "https://west.example.com:6443", "prod-west", "west-token")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a kubeconfig"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, _ := NewMultiClusterClient(filepath.Join(t.TempDir(), "missing"))
+	m.kubeconfigDirs = []string{dir}
+	if err := m.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	clusters, err := m.ListClusters(context.Background())
+	if err != nil {
+		t.Fatalf("ListClusters failed: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 mounted clusters, got %+v", clusters)
+	}
+	for _, c := range clusters {
+		if c.Source != kubeconfigSourceMounted || c.AuthMethod != "token" {
+			t.Errorf("Unexpected cluster info: %+v", c)
+		}
+	}
+
+	// Same cluster/user names in both Secrets must not collide
+	cfg, err := m.GetRestConfig("prod-west")
+	if err != nil {
+		t.Fatalf("GetRestConfig failed: %v", err)
+	}
+	if cfg.Host != "https://west.example.com:6443" || cfg.BearerToken != "west-token" {
+		t.Errorf("Expected prod-west credentials, got host=%s token=%s", cfg.Host, cfg.BearerToken)
+	}
+
+	// Removing a Secret drops its context on reload
+	before := mountedKubeconfigFingerprint(m.kubeconfigDirs)
+	if err := os.RemoveAll(filepath.Join(dir, "prod-west")); err != nil {
+		t.Fatal(err)
+	}
+	if mountedKubeconfigFingerprint(m.kubeconfigDirs) == before {
+		t.Error("Expected fingerprint to change after removing a mount")
+	}
+	if !m.isMountedKubeconfigEvent(filepath.Join(dir, "prod-east", "..data")) || m.isMountedKubeconfigEvent(filepath.Join(filepath.Dir(dir), "other")) {
+		t.Error("isMountedKubeconfigEvent misclassified a path")
+	}
+	if err := m.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	clusters, _ = m.ListClusters(context.Background())
+	if len(clusters) != 1 || clusters[0].Name != "prod-east" {
+		t.Errorf("Expected only prod-east after reload, got %+v", clusters)
+	}
+}
+
+func TestSetKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contextName string) string {
+		path := filepath.Join(dir, name)
+		content := fmt.Sprintf(testMountedKubeconfig, "https://"+contextName+".example.com:6443", contextName, "token")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	work := write("work.yaml", "acme-prod")
+	home := write("home.yaml", "homelab")
+
+	m, _ := NewMultiClusterClient(work)
+	if err := m.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	reloads := 0
+	m.SetOnReload(func() { reloads++ })
+
+	if err := m.SetKubeconfig(home); err != nil {
+		t.Fatalf("SetKubeconfig failed: %v", err)
+	}
+	clusters, _ := m.ListClusters(context.Background())
+	if len(clusters) != 1 || clusters[0].Name != "homelab" || m.KubeconfigPath() != home || reloads != 1 {
+		t.Errorf("Expected to switch to homelab, got %+v (path %s, reloads %d)", clusters, m.KubeconfigPath(), reloads)
+	}
+
+	if err := m.SetKubeconfig(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Expected a missing kubeconfig to be rejected")
+	}
+	if m.KubeconfigPath() != home || reloads != 1 {
+		t.Errorf("Expected a failed switch to keep %s, got %s", home, m.KubeconfigPath())
+	}
+}
@@ -0,0 +1,154 @@
+package k8s
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// QuotaUsage is the use of one resource against a ResourceQuota's hard limit
+type QuotaUsage struct {
+	Resource string  `json:"resource"`
+	Hard     string  `json:"hard"`
+	Used     string  `json:"used"`
+	Percent  float64 `json:"percent"` // used / hard, 0 when hard is zero
+}
+
+// QuotaUtilization is the utilization of each resource a ResourceQuota limits
+type QuotaUtilization struct {
+	Name      string       `json:"name"`
+	Resources []QuotaUsage `json:"resources"`
+	// MaxPercent is the highest utilization among the resources
+	MaxPercent float64 `json:"maxPercent"`
+}
+
+// NamespaceDetail is everything the namespace drill-down shows, gathered in one call
+type NamespaceDetail struct {
+	Name        string             `json:"name"`
+	Cluster     string             `json:"cluster"`
+	Status      string             `json:"status"`
+	Labels      map[string]string  `json:"labels,omitempty"`
+	CreatedAt   string             `json:"createdAt,omitempty"`
+	Quotas      []QuotaUtilization `json:"quotas"`
+	LimitRanges []LimitRange       `json:"limitRanges"`
+	// Counts holds the number of pods, deployments, statefulsets, daemonsets,
+	// jobs, cronjobs, secrets and configmaps; kinds that could not be listed
+	// are left out
+	Counts      map[string]int `json:"counts"`
+	FailingPods []PodIssue     `json:"failingPods"`
+}
+
+// GetNamespaceDetail returns a namespace's quota utilization, limit ranges,
+// pod and workload counts, failing pods and secret and configmap counts.
+// The lists run in parallel and those the caller may not read are skipped.
+func (m *MultiClusterClient) GetNamespaceDetail(ctx context.Context, contextName, namespace string) (*NamespaceDetail, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	detail := &NamespaceDetail{
+		Name:        ns.Name,
+		Cluster:     contextName,
+		Status:      string(ns.Status.Phase),
+		Labels:      ns.Labels,
+		CreatedAt:   ns.CreationTimestamp.Format(time.RFC3339),
+		Quotas:      make([]QuotaUtilization, 0),
+		LimitRanges: make([]LimitRange, 0),
+		Counts:      make(map[string]int),
+		FailingPods: make([]PodIssue, 0),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for kind, gvr := range namespaceCountedResources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if n, err := m.CountObjects(ctx, contextName, gvr, namespace); err == nil {
+				mu.Lock()
+				detail.Counts[kind] = n
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		if quotas, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{}); err == nil {
+			for _, q := range quotas.Items {
+				u := quotaUtilization(&q)
+				mu.Lock()
+				detail.Quotas = append(detail.Quotas, u)
+				mu.Unlock()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if limitRanges, err := m.GetLimitRanges(ctx, contextName, namespace); err == nil && limitRanges != nil {
+			mu.Lock()
+			detail.LimitRanges = limitRanges
+			mu.Unlock()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return
+		}
+		issues := collectPodIssues(pods.Items, contextName, time.Now())
+		mu.Lock()
+		detail.Counts["pods"] = len(pods.Items)
+		if issues != nil {
+			detail.FailingPods = issues
+		}
+		mu.Unlock()
+	}()
+	wg.Wait()
+
+	sort.Slice(detail.Quotas, func(i, j int) bool { return detail.Quotas[i].Name < detail.Quotas[j].Name })
+	return detail, nil
+}
+
+// namespaceCountedResources are the kinds whose objects GetNamespaceDetail
+// counts from metadata alone; pods are counted from the list the failing-pod
+// pass already needs
+var namespaceCountedResources = map[string]schema.GroupVersionResource{
+	"deployments":  {Group: "apps", Version: "v1", Resource: "deployments"},
+	"statefulsets": {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"daemonsets":   {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"jobs":         {Group: "batch", Version: "v1", Resource: "jobs"},
+	"cronjobs":     {Group: "batch", Version: "v1", Resource: "cronjobs"},
+	"secrets":      {Version: "v1", Resource: "secrets"},
+	"configmaps":   {Version: "v1", Resource: "configmaps"},
+}
+
+// quotaUtilization compares a ResourceQuota's used amounts with its hard limits
+func quotaUtilization(q *corev1.ResourceQuota) QuotaUtilization {
+	u := QuotaUtilization{Name: q.Name, Resources: make([]QuotaUsage, 0, len(q.Status.Hard))}
+	for name, hard := range q.Status.Hard {
+		used := q.Status.Used[name]
+		usage := QuotaUsage{Resource: string(name), Hard: hard.String(), Used: used.String()}
+		if h := hard.AsApproximateFloat64(); h > 0 {
+			usage.Percent = math.Round(used.AsApproximateFloat64()/h*1000) / 10
+		}
+		u.MaxPercent = max(u.MaxPercent, usage.Percent)
+		u.Resources = append(u.Resources, usage)
+	}
+	sort.Slice(u.Resources, func(i, j int) bool { return u.Resources[i].Resource < u.Resources[j].Resource })
+	return u
+}
@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetNamespaceDetail(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	fakeCS := k8sfake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "team-a"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4"), corev1.ResourcePods: resource.MustParse("10")},
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3"), corev1.ResourcePods: resource.MustParse("2")},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "ok", Namespace: "team-a"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "crashing", Namespace: "team-a"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 12,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "team-b"}},
+	)
+	// Only pods are listed in full; every other kind is counted from metadata
+	fakeCS.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch action.GetResource().Resource {
+		case "pods", "resourcequotas", "limitranges":
+			return false, nil, nil
+		}
+		t.Errorf("Unexpected full list of %s", action.GetResource().Resource)
+		return true, nil, errors.New("unexpected full list")
+	})
+	m.clients["c1"] = fakeCS
+
+	scheme := runtime.NewScheme()
+	metav1.AddMetaToScheme(scheme)
+	partial := func(apiVersion, kind, name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: name},
+		}
+	}
+	fakeMeta := metadatafake.NewSimpleMetadataClient(scheme,
+		partial("apps/v1", "Deployment", "web"),
+		partial("v1", "ConfigMap", "cfg"),
+		partial("v1", "Secret", "token"),
+	)
+	fakeMeta.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+	m.InjectMetadataClient("c1", fakeMeta)
+
+	detail, err := m.GetNamespaceDetail(context.Background(), "c1", "team-a")
+	if err != nil {
+		t.Fatalf("GetNamespaceDetail failed: %v", err)
+	}
+	if detail.Status != "Active" {
+		t.Errorf("Expected Active namespace, got %q", detail.Status)
+	}
+	if len(detail.Quotas) != 1 || detail.Quotas[0].MaxPercent != 75 || len(detail.Quotas[0].Resources) != 2 {
+		t.Fatalf("Unexpected quota utilization: %+v", detail.Quotas)
+	}
+	if pods := detail.Quotas[0].Resources[0]; pods.Resource != "pods" || pods.Percent != 20 || pods.Used != "2" || pods.Hard != "10" {
+		t.Errorf("Unexpected pods quota usage: %+v", pods)
+	}
+	if detail.Counts["pods"] != 2 || detail.Counts["deployments"] != 1 || detail.Counts["configmaps"] != 1 || detail.Counts["statefulsets"] != 0 {
+		t.Errorf("Unexpected counts: %v", detail.Counts)
+	}
+	if _, ok := detail.Counts["secrets"]; ok {
+		t.Errorf("Expected unreadable secrets to be left out, got %v", detail.Counts)
+	}
+	if len(detail.FailingPods) != 1 || detail.FailingPods[0].Name != "crashing" {
+		t.Errorf("Unexpected failing pods: %+v", detail.FailingPods)
+	}
+
+	if _, err := m.GetNamespaceDetail(context.Background(), "c1", "missing"); err == nil {
+		t.Error("Expected an error for a missing namespace")
+	}
+}
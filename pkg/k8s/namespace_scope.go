@@ -0,0 +1,265 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// NamespaceScopeEnv restricts both the agent and the backend to namespaces:
+// "discover" finds them through RBAC, anything else is a comma-separated list
+const NamespaceScopeEnv = "KC_NAMESPACE_SCOPE"
+
+const (
+	// namespaceScopeTTL is how long discovered namespaces are reused per cluster
+	namespaceScopeTTL = 5 * time.Minute
+	// scopedListConcurrency bounds parallel per-namespace requests
+	scopedListConcurrency = 8
+)
+
+// ErrOutsideNamespaceScope is returned for queries naming a namespace the
+// client is not scoped to
+var ErrOutsideNamespaceScope = errors.New("namespace is outside the namespace scope")
+
+// NamespaceScope limits namespaced queries for identities without cluster-wide
+// read access. An explicit list applies to every cluster; otherwise Discover
+// finds, per cluster, the namespaces where the current identity can list pods.
+type NamespaceScope struct {
+	Namespaces []string
+	Discover   bool
+}
+
+// Enabled reports whether queries are scoped at all
+func (s NamespaceScope) Enabled() bool {
+	return len(s.Namespaces) > 0 || s.Discover
+}
+
+// ParseNamespaceScope parses the KC_NAMESPACE_SCOPE syntax
+func ParseNamespaceScope(raw string) NamespaceScope {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return NamespaceScope{}
+	}
+	if raw == "discover" {
+		return NamespaceScope{Discover: true}
+	}
+	var scope NamespaceScope
+	for _, ns := range strings.Split(raw, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			scope.Namespaces = append(scope.Namespaces, ns)
+		}
+	}
+	return scope
+}
+
+// scopedNamespaces is the discovered scope of one cluster
+type scopedNamespaces struct {
+	namespaces []string
+	all        bool // the identity can read every namespace, so nothing is scoped
+	fetchedAt  time.Time
+}
+
+// SetNamespaceScope replaces the namespace scope and forgets discovered namespaces
+func (m *MultiClusterClient) SetNamespaceScope(scope NamespaceScope) {
+	m.mu.Lock()
+	m.namespaceScope = scope
+	m.mu.Unlock()
+	m.resetNamespaceScopeCache()
+}
+
+// resetNamespaceScopeCache forgets discovered namespaces, e.g. after a kubeconfig reload
+func (m *MultiClusterClient) resetNamespaceScopeCache() {
+	m.scopeMu.Lock()
+	m.scopeCache = nil
+	m.scopeMu.Unlock()
+}
+
+// NamespaceScope returns the configured namespace scope
+func (m *MultiClusterClient) NamespaceScope() NamespaceScope {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.namespaceScope
+}
+
+// PermittedNamespaces returns the namespaces queries against the cluster are
+// limited to; ok is false when the cluster is not scoped
+func (m *MultiClusterClient) PermittedNamespaces(ctx context.Context, contextName string) ([]string, bool) {
+	scope := m.NamespaceScope()
+	if !scope.Enabled() {
+		return nil, false
+	}
+	if len(scope.Namespaces) > 0 {
+		return scope.Namespaces, true
+	}
+
+	key := contextName + impersonationSuffix(ctx)
+	m.scopeMu.Lock()
+	cached, ok := m.scopeCache[key]
+	m.scopeMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < namespaceScopeTTL {
+		return cached.namespaces, !cached.all
+	}
+
+	discovered, err := m.discoverNamespaces(ctx, contextName)
+	if err != nil {
+		// Don't cache failures; fall back to the kubeconfig's namespace
+		log.Printf("[NamespaceScope] discovery failed for %s: %v", contextName, err)
+		return []string{m.contextNamespace(contextName)}, true
+	}
+	m.scopeMu.Lock()
+	if m.scopeCache == nil {
+		m.scopeCache = make(map[string]*scopedNamespaces)
+	}
+	m.scopeCache[key] = discovered
+	m.scopeMu.Unlock()
+	return discovered.namespaces, !discovered.all
+}
+
+// discoverNamespaces finds the namespaces where the current identity can list pods
+func (m *MultiClusterClient) discoverNamespaces(ctx context.Context, contextName string) (*scopedNamespaces, error) {
+	all, err := m.CheckPermission(ctx, contextName, "list", "pods", "")
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return &scopedNamespaces{all: true, fetchedAt: time.Now()}, nil
+	}
+
+	names, err := m.listAllNamespaces(ctx, contextName)
+	if err != nil {
+		if !apierrors.IsForbidden(err) {
+			return nil, err
+		}
+		// Namespaces can't be listed either: the kubeconfig's namespace is all we know
+		return &scopedNamespaces{namespaces: []string{m.contextNamespace(contextName)}, fetchedAt: time.Now()}, nil
+	}
+
+	allowed := make([]bool, len(names))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, scopedListConcurrency)
+	for i, ns := range names {
+		wg.Add(1)
+		go func(i int, ns string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			allowed[i], _ = m.CheckPermission(ctx, contextName, "list", "pods", ns)
+		}(i, ns)
+	}
+	wg.Wait()
+
+	result := &scopedNamespaces{namespaces: []string{}, fetchedAt: time.Now()}
+	for i, ns := range names {
+		if allowed[i] {
+			result.namespaces = append(result.namespaces, ns)
+		}
+	}
+	sort.Strings(result.namespaces)
+	if len(result.namespaces) == 0 {
+		result.namespaces = []string{m.contextNamespace(contextName)}
+	}
+	return result, nil
+}
+
+// contextNamespace is the namespace set on the kubeconfig context, or "default"
+func (m *MultiClusterClient) contextNamespace(contextName string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, config := range []*api.Config{m.rawConfig, m.mountedConfig, m.controlPlaneConfig} {
+		if config == nil {
+			continue
+		}
+		if kctx, ok := config.Contexts[contextName]; ok && kctx != nil && kctx.Namespace != "" {
+			return kctx.Namespace
+		}
+	}
+	return "default"
+}
+
+// scopeNamespaces decides how a namespaced query runs under the namespace scope:
+// nil means query namespace as given, a list means query each namespace in it
+func (m *MultiClusterClient) scopeNamespaces(ctx context.Context, contextName, namespace string) ([]string, error) {
+	permitted, scoped := m.PermittedNamespaces(ctx, contextName)
+	if !scoped {
+		return nil, nil
+	}
+	if namespace != "" {
+		if slices.Contains(permitted, namespace) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrOutsideNamespaceScope, namespace)
+	}
+	if permitted == nil {
+		permitted = []string{}
+	}
+	return permitted, nil
+}
+
+// listScoped runs list once per namespace and concatenates the results in
+// namespace order. Namespaces that fail are skipped unless all of them fail.
+func listScoped[T any](namespaces []string, err error, list func(namespace string) ([]T, error)) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	results := make([][]T, len(namespaces))
+	errs := make([]error, len(namespaces))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, scopedListConcurrency)
+	for i, ns := range namespaces {
+		wg.Add(1)
+		go func(i int, ns string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = list(ns)
+		}(i, ns)
+	}
+	wg.Wait()
+
+	var out []T
+	var firstErr error
+	failed := 0
+	for i := range namespaces {
+		if errs[i] != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		out = append(out, results[i]...)
+	}
+	if failed > 0 && failed == len(namespaces) {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// mergeScopedEvents orders events merged from several namespaces newest first
+// and applies the caller's limit
+func mergeScopedEvents(events []Event, err error, limit int) ([]Event, error) {
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen > events[j].LastSeen
+	})
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func namespaceScopeFromEnv() NamespaceScope {
+	return ParseNamespaceScope(os.Getenv(NamespaceScopeEnv))
+}
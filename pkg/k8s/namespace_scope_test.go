@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func scopedTestClient(t *testing.T) *MultiClusterClient {
+	t.Helper()
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1", Namespace: "team-a"}}})
+
+	pod := func(ns, name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	}
+	event := func(ns, name string, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: ns},
+			Type:          "Warning",
+			LastTimestamp: metav1.NewTime(at),
+		}
+	}
+	now := time.Now()
+	fakeClient := k8sfake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		pod("team-a", "api"), pod("team-b", "web"), pod("kube-system", "coredns"),
+		event("team-a", "old", now.Add(-time.Hour)), event("team-b", "new", now), event("kube-system", "sys", now),
+	)
+	// The identity may list pods in team-a and team-b only
+	fakeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		ns := review.Spec.ResourceAttributes.Namespace
+		review.Status.Allowed = ns == "team-a" || ns == "team-b"
+		return true, review, nil
+	})
+	m.InjectClient("c1", fakeClient)
+	return m
+}
+
+func podNames(pods []PodInfo) map[string]bool {
+	names := map[string]bool{}
+	for _, p := range pods {
+		names[p.Namespace+"/"+p.Name] = true
+	}
+	return names
+}
+
+func TestNamespaceScopeFixed(t *testing.T) {
+	m := scopedTestClient(t)
+	ctx := context.Background()
+
+	pods, err := m.GetPods(ctx, "c1", "")
+	if err != nil || len(pods) != 3 {
+		t.Fatalf("Expected all 3 pods without a scope, got %d (%v)", len(pods), err)
+	}
+
+	m.SetNamespaceScope(NamespaceScope{Namespaces: []string{"team-a"}})
+	pods, err = m.GetPods(ctx, "c1", "")
+	if err != nil {
+		t.Fatalf("GetPods failed: %v", err)
+	}
+	if names := podNames(pods); len(names) != 1 || !names["team-a/api"] {
+		t.Errorf("Expected only team-a pods, got %v", names)
+	}
+	if _, err := m.GetPods(ctx, "c1", "kube-system"); !errors.Is(err, ErrOutsideNamespaceScope) {
+		t.Errorf("Expected ErrOutsideNamespaceScope, got %v", err)
+	}
+	if pods, err := m.GetPods(ctx, "c1", "team-a"); err != nil || len(pods) != 1 {
+		t.Errorf("Expected the permitted namespace to be queried directly, got %d (%v)", len(pods), err)
+	}
+
+	namespaces, err := m.ListNamespacesWithDetails(ctx, "c1")
+	if err != nil || len(namespaces) != 1 || namespaces[0].Name != "team-a" {
+		t.Errorf("Expected only the permitted namespace, got %+v (%v)", namespaces, err)
+	}
+}
+
+func TestNamespaceScopeDiscover(t *testing.T) {
+	m := scopedTestClient(t)
+	m.SetNamespaceScope(NamespaceScope{Discover: true})
+	ctx := context.Background()
+
+	permitted, scoped := m.PermittedNamespaces(ctx, "c1")
+	if !scoped || len(permitted) != 2 || permitted[0] != "team-a" || permitted[1] != "team-b" {
+		t.Fatalf("Expected team-a and team-b to be discovered, got %v (scoped=%v)", permitted, scoped)
+	}
+
+	pods, err := m.GetPods(ctx, "c1", "")
+	if err != nil {
+		t.Fatalf("GetPods failed: %v", err)
+	}
+	if names := podNames(pods); len(names) != 2 || names["kube-system/coredns"] {
+		t.Errorf("Expected team-a and team-b pods only, got %v", names)
+	}
+
+	// Events merged across namespaces stay newest first and honor the limit
+	events, err := m.GetEvents(ctx, "c1", "", 1)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Namespace != "team-b" {
+		t.Errorf("Expected the newest permitted event, got %+v", events)
+	}
+}
+
+func TestParseNamespaceScope(t *testing.T) {
+	if s := ParseNamespaceScope("discover"); !s.Discover || len(s.Namespaces) != 0 {
+		t.Errorf("Expected discovery, got %+v", s)
+	}
+	if s := ParseNamespaceScope(" team-a, ,team-b "); len(s.Namespaces) != 2 || s.Discover {
+		t.Errorf("Expected two namespaces, got %+v", s)
+	}
+	if ParseNamespaceScope("").Enabled() {
+		t.Error("Expected an empty scope to be disabled")
+	}
+}
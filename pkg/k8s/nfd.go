@@ -0,0 +1,430 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// nfdLabelPrefix is the prefix of all Node Feature Discovery labels
+const nfdLabelPrefix = "feature.node.kubernetes.io/"
+
+// nodeResourceTopologyGVR is the NFD/topology-updater NUMA topology resource
+var nodeResourceTopologyGVR = schema.GroupVersionResource{
+	Group:    "topology.node.k8s.io",
+	Version:  "v1alpha2",
+	Resource: "noderesourcetopologies",
+}
+
+// pciVendorNames maps common PCI vendor IDs to names
+var pciVendorNames = map[string]string{
+	"1002": "AMD",
+	"1014": "IBM",
+	"1077": "QLogic",
+	"10de": "NVIDIA",
+	"1137": "Cisco",
+	"144d": "Samsung",
+	"14e4": "Broadcom",
+	"15b3": "Mellanox",
+	"1924": "Solarflare",
+	"19e5": "Huawei",
+	"1ae0": "Google",
+	"1af4": "Red Hat (virtio)",
+	"1b4b": "Marvell",
+	"1d0f": "Amazon",
+	"1da3": "Habana Labs",
+	"8086": "Intel",
+}
+
+// pciClassNames maps PCI class codes (class + subclass) to names
+var pciClassNames = map[string]string{
+	"0104": "RAID controller",
+	"0106": "SATA controller",
+	"0107": "SAS controller",
+	"0108": "NVMe controller",
+	"0200": "Ethernet controller",
+	"0207": "InfiniBand controller",
+	"0280": "Network controller",
+	"0300": "VGA controller",
+	"0302": "3D controller",
+	"0b40": "Co-processor",
+	"1200": "Processing accelerator",
+}
+
+// pciDeviceNames maps well-known vendor:device IDs to product names
+var pciDeviceNames = map[string]string{
+	"10de:1eb8": "Tesla T4",
+	"10de:20b0": "A100 SXM4 40GB",
+	"10de:20b2": "A100 SXM4 80GB",
+	"10de:20b5": "A100 PCIe 80GB",
+	"10de:20f1": "A100 PCIe 40GB",
+	"10de:2230": "RTX A6000",
+	"10de:2330": "H100 SXM5 80GB",
+	"10de:2331": "H100 PCIe",
+	"10de:26b9": "L40S",
+	"10de:27b8": "L4",
+	"15b3:101b": "ConnectX-6",
+	"15b3:101d": "ConnectX-6 Dx",
+	"15b3:101f": "ConnectX-6 Lx",
+	"15b3:1021": "ConnectX-7",
+	"15b3:a2dc": "BlueField-3",
+	"1002:740c": "Instinct MI250X",
+	"1002:740f": "Instinct MI210",
+	"1002:74a1": "Instinct MI300X",
+	"1da3:1020": "Gaudi2",
+}
+
+// NodeHardwareInventory is the structured hardware inventory of a node derived
+// from Node Feature Discovery labels and NodeResourceTopology objects
+type NodeHardwareInventory struct {
+	Name         string            `json:"name"`
+	Cluster      string            `json:"cluster"`
+	NFDDetected  bool              `json:"nfdDetected"`
+	Architecture string            `json:"architecture,omitempty"`
+	CPU          NFDCPUInfo        `json:"cpu"`
+	Kernel       NFDKernelInfo     `json:"kernel"`
+	OS           NFDOSInfo         `json:"os"`
+	Memory       NFDMemoryInfo     `json:"memory"`
+	PCIDevices   []NFDPCIDevice    `json:"pciDevices"`
+	USBDevices   []NFDPCIDevice    `json:"usbDevices,omitempty"`
+	NUMAZones    []NUMAZone        `json:"numaZones,omitempty"`
+	Storage      NFDStorageInfo    `json:"storage"`
+	Network      NFDNetworkInfo    `json:"network"`
+	Other        map[string]string `json:"other,omitempty"` // feature labels not covered above (e.g. custom-*)
+}
+
+// NFDCPUInfo describes CPU model and features
+type NFDCPUInfo struct {
+	Vendor                 string            `json:"vendor,omitempty"`
+	Family                 string            `json:"family,omitempty"`
+	ModelID                string            `json:"modelId,omitempty"`
+	HardwareMultithreading bool              `json:"hardwareMultithreading"`
+	Features               []string          `json:"features"` // cpuid flags, e.g. AVX512F
+	Attributes             map[string]string `json:"attributes,omitempty"`
+}
+
+// NFDKernelInfo describes the running kernel
+type NFDKernelInfo struct {
+	Version       string   `json:"version,omitempty"`
+	Major         string   `json:"major,omitempty"`
+	Minor         string   `json:"minor,omitempty"`
+	Revision      string   `json:"revision,omitempty"`
+	SELinux       bool     `json:"selinux"`
+	LoadedModules []string `json:"loadedModules,omitempty"`
+	Config        []string `json:"config,omitempty"` // enabled kernel config options reported by NFD
+}
+
+// NFDOSInfo describes the OS release
+type NFDOSInfo struct {
+	ID        string `json:"id,omitempty"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+// NFDMemoryInfo describes memory features
+type NFDMemoryInfo struct {
+	NUMA        bool `json:"numa"`
+	NonVolatile bool `json:"nonVolatile"`
+}
+
+// NFDStorageInfo describes storage features
+type NFDStorageInfo struct {
+	NonRotationalDisk bool `json:"nonRotationalDisk"`
+}
+
+// NFDNetworkInfo describes network features
+type NFDNetworkInfo struct {
+	SRIOVCapable    bool `json:"sriovCapable"`
+	SRIOVConfigured bool `json:"sriovConfigured"`
+}
+
+// NFDPCIDevice is a PCI (or USB) device reported by NFD
+type NFDPCIDevice struct {
+	Class        string `json:"class,omitempty"`
+	ClassName    string `json:"className,omitempty"`
+	Vendor       string `json:"vendor"`
+	VendorName   string `json:"vendorName,omitempty"`
+	Device       string `json:"device,omitempty"`
+	DeviceName   string `json:"deviceName,omitempty"`
+	SRIOVCapable bool   `json:"sriovCapable,omitempty"`
+}
+
+// NUMAZone is a NUMA node from NodeResourceTopology
+type NUMAZone struct {
+	Name      string            `json:"name"`
+	Resources map[string]string `json:"resources,omitempty"` // resource name → available
+}
+
+// ParseNFDLabels builds a hardware inventory from a node's NFD labels.
+// Unknown feature labels are kept in Other so nothing is silently dropped.
+func ParseNFDLabels(nodeLabels map[string]string) NodeHardwareInventory {
+	inv := NodeHardwareInventory{
+		CPU:        NFDCPUInfo{Features: []string{}},
+		PCIDevices: []NFDPCIDevice{},
+	}
+	pci := make(map[string]*NFDPCIDevice)
+	usb := make(map[string]*NFDPCIDevice)
+
+	for key, value := range nodeLabels {
+		if !strings.HasPrefix(key, nfdLabelPrefix) {
+			continue
+		}
+		inv.NFDDetected = true
+		feature := strings.TrimPrefix(key, nfdLabelPrefix)
+		isTrue := value == "true"
+
+		switch {
+		case strings.HasPrefix(feature, "cpu-cpuid."):
+			if isTrue {
+				inv.CPU.Features = append(inv.CPU.Features, strings.TrimPrefix(feature, "cpu-cpuid."))
+			}
+		case feature == "cpu-model.vendor_id":
+			inv.CPU.Vendor = value
+		case feature == "cpu-model.family":
+			inv.CPU.Family = value
+		case feature == "cpu-model.id":
+			inv.CPU.ModelID = value
+		case feature == "cpu-hardware_multithreading":
+			inv.CPU.HardwareMultithreading = isTrue
+		case strings.HasPrefix(feature, "cpu-"):
+			if inv.CPU.Attributes == nil {
+				inv.CPU.Attributes = make(map[string]string)
+			}
+			inv.CPU.Attributes[strings.TrimPrefix(feature, "cpu-")] = value
+
+		case feature == "kernel-version.full":
+			inv.Kernel.Version = value
+		case feature == "kernel-version.major":
+			inv.Kernel.Major = value
+		case feature == "kernel-version.minor":
+			inv.Kernel.Minor = value
+		case feature == "kernel-version.revision":
+			inv.Kernel.Revision = value
+		case feature == "kernel-selinux.enabled":
+			inv.Kernel.SELinux = isTrue
+		case strings.HasPrefix(feature, "kernel-loadedmodule."):
+			if isTrue {
+				inv.Kernel.LoadedModules = append(inv.Kernel.LoadedModules, strings.TrimPrefix(feature, "kernel-loadedmodule."))
+			}
+		case strings.HasPrefix(feature, "kernel-config."):
+			if isTrue {
+				inv.Kernel.Config = append(inv.Kernel.Config, strings.TrimPrefix(feature, "kernel-config."))
+			}
+
+		case feature == "system-os_release.ID":
+			inv.OS.ID = value
+		case feature == "system-os_release.VERSION_ID":
+			inv.OS.VersionID = value
+
+		case feature == "memory-numa":
+			inv.Memory.NUMA = isTrue
+		case strings.HasPrefix(feature, "memory-nv."):
+			inv.Memory.NonVolatile = inv.Memory.NonVolatile || isTrue
+
+		case feature == "storage-nonrotationaldisk":
+			inv.Storage.NonRotationalDisk = isTrue
+
+		case feature == "network-sriov.capable":
+			inv.Network.SRIOVCapable = isTrue
+		case feature == "network-sriov.configured":
+			inv.Network.SRIOVConfigured = isTrue
+
+		case strings.HasPrefix(feature, "pci-"):
+			if !parseNFDDeviceLabel(strings.TrimPrefix(feature, "pci-"), isTrue, pci) {
+				inv.setOther(feature, value)
+			}
+		case strings.HasPrefix(feature, "usb-"):
+			if !parseNFDDeviceLabel(strings.TrimPrefix(feature, "usb-"), isTrue, usb) {
+				inv.setOther(feature, value)
+			}
+
+		default:
+			inv.setOther(feature, value)
+		}
+	}
+
+	inv.PCIDevices = sortedDevices(pci)
+	if len(usb) > 0 {
+		inv.USBDevices = sortedDevices(usb)
+	}
+	sort.Strings(inv.CPU.Features)
+	sort.Strings(inv.Kernel.LoadedModules)
+	sort.Strings(inv.Kernel.Config)
+	return inv
+}
+
+func (inv *NodeHardwareInventory) setOther(feature, value string) {
+	if inv.Other == nil {
+		inv.Other = make(map[string]string)
+	}
+	inv.Other[feature] = value
+}
+
+// parseNFDDeviceLabel parses "<id>.<attr>" where id is class_vendor, vendor,
+// vendor_device or class_vendor_device depending on NFD's deviceLabelFields.
+// Returns false if the label isn't a recognizable device label.
+func parseNFDDeviceLabel(label string, isTrue bool, devices map[string]*NFDPCIDevice) bool {
+	id, attr, ok := strings.Cut(label, ".")
+	if !ok {
+		return false
+	}
+	parts := strings.Split(id, "_")
+	for _, p := range parts {
+		if !isHexID(p) {
+			return false
+		}
+	}
+
+	dev := NFDPCIDevice{}
+	switch len(parts) {
+	case 1:
+		dev.Vendor = parts[0]
+	case 2:
+		if _, known := pciVendorNames[parts[0]]; known {
+			dev.Vendor, dev.Device = parts[0], parts[1]
+		} else {
+			dev.Class, dev.Vendor = parts[0], parts[1]
+		}
+	case 3:
+		dev.Class, dev.Vendor, dev.Device = parts[0], parts[1], parts[2]
+	default:
+		return false
+	}
+
+	existing, ok := devices[id]
+	if !ok {
+		dev.ClassName = pciClassNames[dev.Class]
+		dev.VendorName = pciVendorNames[dev.Vendor]
+		if dev.Device != "" {
+			dev.DeviceName = pciDeviceNames[dev.Vendor+":"+dev.Device]
+		}
+		existing = &dev
+		devices[id] = existing
+	}
+	if (attr == "sriov.capable" || attr == "sriov.configured") && isTrue {
+		existing.SRIOVCapable = true
+	}
+	return true
+}
+
+func isHexID(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedDevices(devices map[string]*NFDPCIDevice) []NFDPCIDevice {
+	keys := make([]string, 0, len(devices))
+	for k := range devices {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]NFDPCIDevice, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, *devices[k])
+	}
+	return out
+}
+
+// parseNUMAZones extracts Node-type zones from a NodeResourceTopology object
+func parseNUMAZones(obj *unstructured.Unstructured) []NUMAZone {
+	zones, _, _ := unstructured.NestedSlice(obj.Object, "zones")
+	var out []NUMAZone
+	for _, z := range zones {
+		zm, ok := z.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _ := zm["type"].(string); t != "" && t != "Node" {
+			continue
+		}
+		zone := NUMAZone{}
+		zone.Name, _ = zm["name"].(string)
+		resources, _ := zm["resources"].([]interface{})
+		for _, r := range resources {
+			rm, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := rm["name"].(string)
+			if name == "" {
+				continue
+			}
+			if zone.Resources == nil {
+				zone.Resources = make(map[string]string)
+			}
+			zone.Resources[name] = stringifyQuantity(rm["available"])
+		}
+		out = append(out, zone)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func stringifyQuantity(v interface{}) string {
+	switch q := v.(type) {
+	case string:
+		return q
+	case int64:
+		return strconv.FormatInt(q, 10)
+	case float64:
+		return strconv.FormatInt(int64(q), 10)
+	}
+	return ""
+}
+
+// buildNodeHardware combines NFD labels with node status fields
+func buildNodeHardware(node *corev1.Node, cluster string) NodeHardwareInventory {
+	inv := ParseNFDLabels(node.Labels)
+	inv.Name = node.Name
+	inv.Cluster = cluster
+	inv.Architecture = node.Status.NodeInfo.Architecture
+	// Fall back to the kubelet-reported kernel when NFD isn't installed
+	if inv.Kernel.Version == "" {
+		inv.Kernel.Version = node.Status.NodeInfo.KernelVersion
+	}
+	return inv
+}
+
+// GetNodeHardware returns the NFD-derived hardware inventory of every node in a
+// cluster. NUMA zones are included when NodeResourceTopology objects exist.
+func (m *MultiClusterClient) GetNodeHardware(ctx context.Context, contextName string) ([]NodeHardwareInventory, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// NodeResourceTopology is optional; ignore errors when the CRD is absent
+	topology := make(map[string][]NUMAZone)
+	if dyn, err := m.GetDynamicClient(contextName); err == nil {
+		if list, err := dyn.Resource(nodeResourceTopologyGVR).List(ctx, metav1.ListOptions{}); err == nil {
+			for i := range list.Items {
+				topology[list.Items[i].GetName()] = parseNUMAZones(&list.Items[i])
+			}
+		}
+	}
+
+	result := make([]NodeHardwareInventory, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		inv := buildNodeHardware(&nodes.Items[i], contextName)
+		inv.NUMAZones = topology[inv.Name]
+		result = append(result, inv)
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestParseNFDLabels(t *testing.T) {
+	inv := ParseNFDLabels(map[string]string{
+		"kubernetes.io/hostname":                                      "gpu-1",
+		"feature.node.kubernetes.io/cpu-cpuid.AVX512F":                "true",
+		"feature.node.kubernetes.io/cpu-cpuid.AVX2":                   "true",
+		"feature.node.kubernetes.io/cpu-model.vendor_id":              "Intel",
+		"feature.node.kubernetes.io/cpu-model.family":                 "6",
+		"feature.node.kubernetes.io/cpu-model.id":                     "143",
+		"feature.node.kubernetes.io/cpu-hardware_multithreading":      "true",
+		"feature.node.kubernetes.io/cpu-pstate.status":                "active",
+		"feature.node.kubernetes.io/kernel-version.full":              "5.15.0-101-generic",
+		"feature.node.kubernetes.io/kernel-version.major":             "5",
+		"feature.node.kubernetes.io/kernel-loadedmodule.nvidia":       "true",
+		"feature.node.kubernetes.io/system-os_release.ID":             "ubuntu",
+		"feature.node.kubernetes.io/system-os_release.VERSION_ID":     "22.04",
+		"feature.node.kubernetes.io/memory-numa":                      "true",
+		"feature.node.kubernetes.io/storage-nonrotationaldisk":        "true",
+		"feature.node.kubernetes.io/pci-0302_10de.present":            "true",
+		"feature.node.kubernetes.io/pci-0207_15b3_1021.present":       "true",
+		"feature.node.kubernetes.io/pci-0207_15b3_1021.sriov.capable": "true",
+		"feature.node.kubernetes.io/pci-bogus.present":                "true",
+		"feature.node.kubernetes.io/custom-rdma.available":            "true",
+	})
+
+	if !inv.NFDDetected {
+		t.Fatal("Expected NFD to be detected")
+	}
+	if !reflect.DeepEqual(inv.CPU.Features, []string{"AVX2", "AVX512F"}) {
+		t.Errorf("Unexpected CPU features: %v", inv.CPU.Features)
+	}
+	if inv.CPU.Vendor != "Intel" || inv.CPU.Family != "6" || inv.CPU.ModelID != "143" || !inv.CPU.HardwareMultithreading {
+		t.Errorf("Unexpected CPU model: %+v", inv.CPU)
+	}
+	if inv.CPU.Attributes["pstate.status"] != "active" {
+		t.Errorf("Expected pstate attribute, got %v", inv.CPU.Attributes)
+	}
+	if inv.Kernel.Version != "5.15.0-101-generic" || inv.Kernel.Major != "5" || !reflect.DeepEqual(inv.Kernel.LoadedModules, []string{"nvidia"}) {
+		t.Errorf("Unexpected kernel info: %+v", inv.Kernel)
+	}
+	if inv.OS.ID != "ubuntu" || inv.OS.VersionID != "22.04" {
+		t.Errorf("Unexpected OS info: %+v", inv.OS)
+	}
+	if !inv.Memory.NUMA || !inv.Storage.NonRotationalDisk {
+		t.Errorf("Expected NUMA and SSD flags, got %+v %+v", inv.Memory, inv.Storage)
+	}
+
+	want := []NFDPCIDevice{
+		{Class: "0207", ClassName: "InfiniBand controller", Vendor: "15b3", VendorName: "Mellanox", Device: "1021", DeviceName: "ConnectX-7", SRIOVCapable: true},
+		{Class: "0302", ClassName: "3D controller", Vendor: "10de", VendorName: "NVIDIA"},
+	}
+	if !reflect.DeepEqual(inv.PCIDevices, want) {
+		t.Errorf("Unexpected PCI devices:\n got %+v\nwant %+v", inv.PCIDevices, want)
+	}
+
+	if inv.Other["pci-bogus.present"] != "true" || inv.Other["custom-rdma.available"] != "true" {
+		t.Errorf("Expected unparsed labels in Other, got %v", inv.Other)
+	}
+}
+
+func TestParseNFDLabels_NoNFD(t *testing.T) {
+	inv := ParseNFDLabels(map[string]string{"kubernetes.io/arch": "amd64"})
+	if inv.NFDDetected {
+		t.Error("Expected NFD not to be detected")
+	}
+	if inv.PCIDevices == nil || inv.CPU.Features == nil {
+		t.Error("Expected empty (non-nil) slices for JSON output")
+	}
+}
+
+func TestGetNodeHardware(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1"}}})
+
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "gpu-1",
+				Labels: map[string]string{"feature.node.kubernetes.io/pci-0302_10de.present": "true"},
+			},
+			Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: "amd64", KernelVersion: "6.1.0"}},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "plain"}},
+	))
+
+	nrt := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "topology.node.k8s.io/v1alpha2",
+		"kind":       "NodeResourceTopology",
+		"metadata":   map[string]interface{}{"name": "gpu-1"},
+		"zones": []interface{}{
+			map[string]interface{}{
+				"name": "node-1",
+				"type": "Node",
+				"resources": []interface{}{
+					map[string]interface{}{"name": "cpu", "available": "32"},
+				},
+			},
+			map[string]interface{}{
+				"name": "node-0",
+				"type": "Node",
+				"resources": []interface{}{
+					map[string]interface{}{"name": "cpu", "available": "30"},
+					map[string]interface{}{"name": "nvidia.com/gpu", "available": "4"},
+				},
+			},
+		},
+	}}
+	m.InjectDynamicClient("c1", dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{nodeResourceTopologyGVR: "NodeResourceTopologyList"}, nrt))
+
+	nodes, err := m.GetNodeHardware(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetNodeHardware failed: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %d", len(nodes))
+	}
+
+	byName := map[string]NodeHardwareInventory{}
+	for _, n := range nodes {
+		byName[n.Name] = n
+	}
+
+	gpu := byName["gpu-1"]
+	if gpu.Cluster != "c1" || gpu.Architecture != "amd64" || !gpu.NFDDetected {
+		t.Errorf("Unexpected gpu-1 inventory: %+v", gpu)
+	}
+	if gpu.Kernel.Version != "6.1.0" {
+		t.Errorf("Expected kernel fallback to node info, got %q", gpu.Kernel.Version)
+	}
+	if len(gpu.NUMAZones) != 2 || gpu.NUMAZones[0].Name != "node-0" || gpu.NUMAZones[0].Resources["nvidia.com/gpu"] != "4" {
+		t.Errorf("Unexpected NUMA zones: %+v", gpu.NUMAZones)
+	}
+
+	if plain := byName["plain"]; plain.NFDDetected || len(plain.NUMAZones) != 0 {
+		t.Errorf("Unexpected plain node inventory: %+v", plain)
+	}
+}
@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// nodePodSnapshotTTL is how long a cluster's node+pod listing is reused by the
+	// node, GPU and health queries before they list again
+	nodePodSnapshotTTL = 15 * time.Second
+	// nodePodSnapshotTimeout bounds a shared fetch independently of the caller
+	// that started it, so one canceled request doesn't fail the others waiting on it
+	nodePodSnapshotTimeout = 45 * time.Second
+)
+
+// nodePodSnapshot is one listing of a cluster's nodes and pods shared by GetNodes,
+// GetGPUNodes, GetGPUNodeHealth and GetClusterHealth. Callers must not modify it.
+type nodePodSnapshot struct {
+	nodes     *corev1.NodeList
+	pods      *corev1.PodList
+	nodesErr  error
+	podsErr   error
+	fetchedAt time.Time
+	done      chan struct{} // closed when the fetch completes
+}
+
+// nodePodSnapshots caches the latest snapshot per context; concurrent callers share
+// a single in-flight fetch
+type nodePodSnapshots struct {
+	mu      sync.Mutex
+	entries map[string]*nodePodSnapshot
+}
+
+type freshSnapshotKey struct{}
+
+// WithFreshSnapshot makes node, GPU and health queries made with ctx list the
+// cluster again instead of reusing a recent snapshot. The new listing is shared
+// with later callers as usual.
+func WithFreshSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshSnapshotKey{}, true)
+}
+
+// getNodePodSnapshot returns a recent snapshot of the cluster's nodes and pods,
+// listing both in parallel when the cached one is missing, stale or failed.
+// Listing errors are reported in the snapshot; err is only set when no client
+// could be created or ctx ended while waiting.
+func (m *MultiClusterClient) getNodePodSnapshot(ctx context.Context, contextName string) (*nodePodSnapshot, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Impersonated identities get snapshots of their own
+	key := contextName + impersonationSuffix(ctx)
+	m.nodeSnapshots.mu.Lock()
+	if snap, ok := m.nodeSnapshots.entries[key]; ok {
+		select {
+		case <-snap.done:
+			fresh, _ := ctx.Value(freshSnapshotKey{}).(bool)
+			if !fresh && snap.nodesErr == nil && snap.podsErr == nil && time.Since(snap.fetchedAt) < nodePodSnapshotTTL {
+				m.nodeSnapshots.mu.Unlock()
+				return snap, nil
+			}
+		default:
+			// Another caller is already listing this cluster
+			m.nodeSnapshots.mu.Unlock()
+			select {
+			case <-snap.done:
+				return snap, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	snap := &nodePodSnapshot{done: make(chan struct{})}
+	if m.nodeSnapshots.entries == nil {
+		m.nodeSnapshots.entries = make(map[string]*nodePodSnapshot)
+	}
+	m.nodeSnapshots.entries[key] = snap
+	m.nodeSnapshots.mu.Unlock()
+
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), nodePodSnapshotTimeout)
+	go func() {
+		defer cancel()
+		defer close(snap.done)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			snap.nodes, snap.nodesErr = client.CoreV1().Nodes().List(fetchCtx, metav1.ListOptions{})
+		}()
+		go func() {
+			defer wg.Done()
+			snap.pods, snap.podsErr = client.CoreV1().Pods("").List(fetchCtx, metav1.ListOptions{})
+		}()
+		wg.Wait()
+		snap.fetchedAt = time.Now()
+	}()
+
+	select {
+	case <-snap.done:
+		return snap, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resetNodePodSnapshots drops every cached snapshot, e.g. after a kubeconfig reload
+func (m *MultiClusterClient) resetNodePodSnapshots() {
+	m.nodeSnapshots.mu.Lock()
+	m.nodeSnapshots.entries = nil
+	m.nodeSnapshots.mu.Unlock()
+}
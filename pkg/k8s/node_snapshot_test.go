@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestNodePodSnapshotShared(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(&api.Config{Contexts: map[string]*api.Context{"c1": {Cluster: "c1"}}})
+
+	fakeClient := k8sfake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-1"},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")},
+				Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "ml"},
+			Spec: corev1.PodSpec{NodeName: "gpu-1", Containers: []corev1.Container{{
+				Name:      "main",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("2")}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	)
+	m.InjectClient("c1", fakeClient)
+
+	ctx := context.Background()
+	if _, err := m.GetNodes(ctx, "c1"); err != nil {
+		t.Fatalf("GetNodes failed: %v", err)
+	}
+	gpuNodes, err := m.GetGPUNodes(ctx, "c1")
+	if err != nil {
+		t.Fatalf("GetGPUNodes failed: %v", err)
+	}
+	if len(gpuNodes) != 1 || gpuNodes[0].GPUAllocated != 2 {
+		t.Errorf("Expected 2 of 8 GPUs allocated from the snapshot, got %+v", gpuNodes)
+	}
+	before := len(fakeClient.Actions())
+	if _, err := m.GetGPUNodeHealth(ctx, "c1"); err != nil {
+		t.Fatalf("GetGPUNodeHealth failed: %v", err)
+	}
+	for _, action := range fakeClient.Actions()[before:] {
+		if action.GetVerb() == "list" && action.GetResource().Resource == "pods" {
+			t.Errorf("Expected GPU node health to find operator pods in the snapshot, got a listing in %q", action.GetNamespace())
+		}
+	}
+	health, err := m.GetClusterHealth(ctx, "c1")
+	if err != nil || health.NodeCount != 1 || health.PodCount != 1 {
+		t.Fatalf("Unexpected health %+v, %v", health, err)
+	}
+
+	// All four queries share a single node and cluster-wide pod listing
+	var nodeLists, podLists int
+	for _, action := range fakeClient.Actions() {
+		if action.GetVerb() != "list" {
+			continue
+		}
+		switch {
+		case action.GetResource().Resource == "nodes":
+			nodeLists++
+		case action.GetResource().Resource == "pods" && action.GetNamespace() == "":
+			podLists++
+		}
+	}
+	if nodeLists != 1 || podLists != 1 {
+		t.Errorf("Expected one node and one pod listing, got %d and %d", nodeLists, podLists)
+	}
+
+	// A kubeconfig reload drops the snapshot
+	before = len(fakeClient.Actions())
+	m.resetNodePodSnapshots()
+	if _, err := m.GetNodes(ctx, "c1"); err != nil {
+		t.Fatalf("GetNodes failed: %v", err)
+	}
+	if len(fakeClient.Actions()) == before {
+		t.Error("Expected a fresh listing after the snapshots were reset")
+	}
+}
@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodePoolLabels are the labels provisioners put on nodes to name their pool,
+// in order of precedence, with the provider each one identifies
+var nodePoolLabels = []struct {
+	label    string
+	provider string
+}{
+	{"karpenter.sh/nodepool", "karpenter"},
+	{"karpenter.sh/provisioner-name", "karpenter"},
+	{"eks.amazonaws.com/nodegroup", "eks"},
+	{"alpha.eksctl.io/nodegroup-name", "eks"},
+	{"cloud.google.com/gke-nodepool", "gke"},
+	{"kubernetes.azure.com/agentpool", "aks"},
+	{"agentpool", "aks"},
+	{"doks.digitalocean.com/node-pool", "doks"},
+	{"kops.k8s.io/instancegroup", "kops"},
+}
+
+// Pools of nodes without a provisioner label
+const (
+	unpooledControlPlane = "control-plane"
+	unpooledWorkers      = "unpooled"
+)
+
+// NodePool aggregates the nodes a provisioner manages as one group
+type NodePool struct {
+	Name            string   `json:"name"`
+	Provider        string   `json:"provider"` // karpenter, eks, gke, aks, doks, kops, none
+	Cluster         string   `json:"cluster"`
+	Nodes           int      `json:"nodes"`
+	ReadyNodes      int      `json:"readyNodes"`
+	Unschedulable   int      `json:"unschedulable"`
+	CPUMillicores   int64    `json:"cpuMillicores"` // allocatable
+	MemoryBytes     int64    `json:"memoryBytes"`   // allocatable
+	GPUs            int64    `json:"gpus"`          // allocatable
+	GPUTypes        []string `json:"gpuTypes,omitempty"`
+	InstanceTypes   []string `json:"instanceTypes,omitempty"`
+	Zones           []string `json:"zones,omitempty"`
+	KubeletVersions []string `json:"kubeletVersions,omitempty"`
+	// Healthy is set when every node is ready; VersionSkew when nodes run different kubelets
+	Healthy     bool     `json:"healthy"`
+	VersionSkew bool     `json:"versionSkew"`
+	NodeNames   []string `json:"nodeNames"`
+}
+
+// nodePoolOf returns the pool a node belongs to and the provider managing it
+func nodePoolOf(node *corev1.Node) (string, string) {
+	for _, l := range nodePoolLabels {
+		if name := node.Labels[l.label]; name != "" {
+			return name, l.provider
+		}
+	}
+	if _, ok := node.Labels["node-role.kubernetes.io/control-plane"]; ok {
+		return unpooledControlPlane, "none"
+	}
+	return unpooledWorkers, "none"
+}
+
+// GetNodePools groups a cluster's nodes by their provisioner pool label with
+// aggregate capacity, GPU counts, instance types, zones, kubelet versions and
+// readiness
+func (m *MultiClusterClient) GetNodePools(ctx context.Context, contextName string) ([]NodePool, error) {
+	snap, err := m.getNodePodSnapshot(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	if snap.nodesErr != nil {
+		return nil, snap.nodesErr
+	}
+
+	byKey := make(map[string]*NodePool)
+	for i := range snap.nodes.Items {
+		node := &snap.nodes.Items[i]
+		name, provider := nodePoolOf(node)
+		key := provider + "/" + name
+		pool, ok := byKey[key]
+		if !ok {
+			pool = &NodePool{Name: name, Provider: provider, Cluster: contextName}
+			byKey[key] = pool
+		}
+
+		pool.Nodes++
+		pool.NodeNames = append(pool.NodeNames, node.Name)
+		if isNodeReady(node) {
+			pool.ReadyNodes++
+		}
+		if node.Spec.Unschedulable {
+			pool.Unschedulable++
+		}
+		pool.CPUMillicores += node.Status.Allocatable.Cpu().MilliValue()
+		pool.MemoryBytes += node.Status.Allocatable.Memory().Value()
+		for _, r := range gpuResourceNames {
+			if qty, ok := node.Status.Allocatable[r]; ok {
+				pool.GPUs += qty.Value()
+			}
+		}
+		pool.GPUTypes = appendUnique(pool.GPUTypes, node.Labels["nvidia.com/gpu.product"], node.Labels["amd.com/gpu.product"])
+		pool.InstanceTypes = appendUnique(pool.InstanceTypes, node.Labels[corev1.LabelInstanceTypeStable])
+		pool.Zones = appendUnique(pool.Zones, node.Labels[corev1.LabelTopologyZone])
+		pool.KubeletVersions = appendUnique(pool.KubeletVersions, node.Status.NodeInfo.KubeletVersion)
+	}
+
+	pools := make([]NodePool, 0, len(byKey))
+	for _, pool := range byKey {
+		pool.Healthy = pool.ReadyNodes == pool.Nodes
+		pool.VersionSkew = len(pool.KubeletVersions) > 1
+		for _, s := range [][]string{pool.GPUTypes, pool.InstanceTypes, pool.Zones, pool.KubeletVersions, pool.NodeNames} {
+			sort.Strings(s)
+		}
+		pools = append(pools, *pool)
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		if pools[i].Provider != pools[j].Provider {
+			return pools[i].Provider < pools[j].Provider
+		}
+		return pools[i].Name < pools[j].Name
+	})
+	return pools, nil
+}
+
+// appendUnique appends the non-empty values not already in list
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if v != "" && !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetNodePools(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	node := func(name string, labels map[string]string, ready corev1.ConditionStatus, kubelet, gpus string) *corev1.Node {
+		allocatable := corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("8"),
+			corev1.ResourceMemory: resource.MustParse("32Gi"),
+		}
+		if gpus != "" {
+			allocatable["nvidia.com/gpu"] = resource.MustParse(gpus)
+		}
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: corev1.NodeStatus{
+				Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+				Allocatable: allocatable,
+				NodeInfo:    corev1.NodeSystemInfo{KubeletVersion: kubelet},
+			},
+		}
+	}
+	gpuLabels := func(zone string) map[string]string {
+		return map[string]string{
+			"eks.amazonaws.com/nodegroup":    "gpu",
+			"nvidia.com/gpu.product":         "NVIDIA-H100-80GB-HBM3",
+			corev1.LabelInstanceTypeStable:   "p5.48xlarge",
+			corev1.LabelTopologyZone:         zone,
+			"alpha.eksctl.io/nodegroup-name": "ignored",
+		}
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		node("gpu-1", gpuLabels("us-east-1a"), corev1.ConditionTrue, "v1.30.2", "8"),
+		node("gpu-2", gpuLabels("us-east-1b"), corev1.ConditionFalse, "v1.29.6", "8"),
+		node("spot-1", map[string]string{"karpenter.sh/nodepool": "spot"}, corev1.ConditionTrue, "v1.30.2", ""),
+		node("cp-1", map[string]string{"node-role.kubernetes.io/control-plane": ""}, corev1.ConditionTrue, "v1.30.2", ""),
+	)
+
+	pools, err := m.GetNodePools(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetNodePools failed: %v", err)
+	}
+	if len(pools) != 3 {
+		t.Fatalf("Expected 3 pools, got %+v", pools)
+	}
+	gpu := pools[0]
+	if gpu.Provider != "eks" || gpu.Name != "gpu" || gpu.Nodes != 2 || gpu.ReadyNodes != 1 || gpu.Healthy {
+		t.Errorf("Unexpected eks pool: %+v", gpu)
+	}
+	if gpu.GPUs != 16 || gpu.CPUMillicores != 16000 || gpu.MemoryBytes != 64<<30 {
+		t.Errorf("Unexpected eks pool capacity: %+v", gpu)
+	}
+	if !gpu.VersionSkew || len(gpu.Zones) != 2 || gpu.Zones[0] != "us-east-1a" || len(gpu.InstanceTypes) != 1 || gpu.GPUTypes[0] != "NVIDIA-H100-80GB-HBM3" {
+		t.Errorf("Unexpected eks pool details: %+v", gpu)
+	}
+	if spot := pools[1]; spot.Provider != "karpenter" || spot.Name != "spot" || !spot.Healthy || spot.GPUs != 0 {
+		t.Errorf("Unexpected karpenter pool: %+v", spot)
+	}
+	if cp := pools[2]; cp.Provider != "none" || cp.Name != unpooledControlPlane || cp.NodeNames[0] != "cp-1" {
+		t.Errorf("Unexpected control plane pool: %+v", cp)
+	}
+}
@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ClusterSourceManaged is the ClusterInfo.Source for kubeconfig contexts
+// registered as workload execution clusters with an OCM hub
+const ClusterSourceManaged = "managed"
+
+// managedClusterGVR is the OCM ManagedCluster resource on a hub (a KubeStellar ITS)
+var managedClusterGVR = schema.GroupVersionResource{
+	Group:    "cluster.open-cluster-management.io",
+	Version:  "v1",
+	Resource: "managedclusters",
+}
+
+// ManagedCluster is a cluster registered with an OCM hub
+type ManagedCluster struct {
+	Name      string            `json:"name"`
+	Hub       string            `json:"hub"` // hub context
+	Accepted  bool              `json:"accepted"`
+	Joined    bool              `json:"joined"`
+	Available bool              `json:"available"`
+	APIServer string            `json:"apiServer,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt string            `json:"createdAt,omitempty"`
+}
+
+// ListManagedClusters lists the ManagedClusters registered with a hub
+func (m *MultiClusterClient) ListManagedClusters(ctx context.Context, hubContext string) ([]ManagedCluster, error) {
+	dynamicClient, err := m.GetDynamicClient(hubContext)
+	if err != nil {
+		return nil, err
+	}
+	list, err := dynamicClient.Resource(managedClusterGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	clusters := make([]ManagedCluster, 0, len(list.Items))
+	for i := range list.Items {
+		clusters = append(clusters, managedClusterFromUnstructured(&list.Items[i], hubContext))
+	}
+	return clusters, nil
+}
+
+func managedClusterFromUnstructured(item *unstructured.Unstructured, hubContext string) ManagedCluster {
+	mc := ManagedCluster{Name: item.GetName(), Hub: hubContext, Labels: item.GetLabels()}
+	if created := item.GetCreationTimestamp(); !created.IsZero() {
+		mc.CreatedAt = created.UTC().Format(time.RFC3339)
+	}
+	mc.Accepted, _, _ = unstructured.NestedBool(item.Object, "spec", "hubAcceptsClient")
+	if configs, _, _ := unstructured.NestedSlice(item.Object, "spec", "managedClusterClientConfigs"); len(configs) > 0 {
+		if c, ok := configs[0].(map[string]interface{}); ok {
+			mc.APIServer, _ = c["url"].(string)
+		}
+	}
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		isTrue := cond["status"] == "True"
+		switch cond["type"] {
+		case "ManagedClusterJoined":
+			mc.Joined = isTrue
+		case "ManagedClusterConditionAvailable":
+			mc.Available = isTrue
+		}
+	}
+	return mc
+}
+
+// LabelManagedCluster merges labels into a ManagedCluster so BindingPolicies can select it
+func (m *MultiClusterClient) LabelManagedCluster(ctx context.Context, hubContext, name string, labels map[string]string) error {
+	dynamicClient, err := m.GetDynamicClient(hubContext)
+	if err != nil {
+		return err
+	}
+	mc, err := dynamicClient.Resource(managedClusterGVR).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	merged := mc.GetLabels()
+	if merged == nil {
+		merged = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	mc.SetLabels(merged)
+	_, err = dynamicClient.Resource(managedClusterGVR).Update(ctx, mc, metav1.UpdateOptions{})
+	return err
+}
+
+// SetManagedContexts records which kubeconfig contexts are registered with a
+// hub, so ListClusters tags them with the managed source
+func (m *MultiClusterClient) SetManagedContexts(contexts map[string]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.managedContexts = contexts
+}
+
+// tagManagedClusters sets the managed source on registered kubeconfig contexts
+func tagManagedClusters(clusters []ClusterInfo, managed map[string]bool) {
+	for i := range clusters {
+		if managed[clusters[i].Context] {
+			clusters[i].Source = ClusterSourceManaged
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultPageSize is how many objects each paged list request asks for
+const defaultPageSize = 500
+
+// EachPodPage lists pods page by page using the API server's continue tokens,
+// calling fn with each page so complete results can be streamed without
+// holding every pod in memory. Returning an error from fn stops the listing.
+func (m *MultiClusterClient) EachPodPage(ctx context.Context, contextName, namespace string, pageSize int64, fn func([]PodInfo) error) error {
+	namespaces, err := m.scopeNamespaces(ctx, contextName, namespace)
+	if err != nil {
+		return err
+	}
+	if namespaces == nil {
+		namespaces = []string{namespace}
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return err
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	for _, ns := range namespaces {
+		opts := metav1.ListOptions{Limit: pageSize}
+		for {
+			pods, err := client.CoreV1().Pods(ns).List(ctx, opts)
+			if err != nil {
+				return err
+			}
+			page := make([]PodInfo, 0, len(pods.Items))
+			for i := range pods.Items {
+				page = append(page, podInfo(&pods.Items[i], contextName))
+			}
+			if err := fn(page); err != nil {
+				return err
+			}
+			if pods.Continue == "" {
+				break
+			}
+			opts.Continue = pods.Continue
+		}
+	}
+	return nil
+}
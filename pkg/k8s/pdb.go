@@ -0,0 +1,279 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Reasons a workload is at risk during voluntary disruptions (drains, upgrades)
+const (
+	// DisruptionBlocked means a PDB allows no disruptions, so evictions hang
+	DisruptionBlocked = "zero-disruptions-allowed"
+	// DisruptionUnprotected means no PDB covers the workload, so a drain may take all replicas down at once
+	DisruptionUnprotected = "no-pdb"
+)
+
+// PDB represents a Kubernetes PodDisruptionBudget
+type PDB struct {
+	Name               string            `json:"name"`
+	Namespace          string            `json:"namespace"`
+	Cluster            string            `json:"cluster,omitempty"`
+	MinAvailable       string            `json:"minAvailable,omitempty"`
+	MaxUnavailable     string            `json:"maxUnavailable,omitempty"`
+	Selector           string            `json:"selector,omitempty"`
+	CurrentHealthy     int32             `json:"currentHealthy"`
+	DesiredHealthy     int32             `json:"desiredHealthy"`
+	ExpectedPods       int32             `json:"expectedPods"`
+	DisruptionsAllowed int32             `json:"disruptionsAllowed"`
+	Age                string            `json:"age,omitempty"`
+	Labels             map[string]string `json:"labels,omitempty"`
+}
+
+// DisruptionRisk is a workload that a node drain would either block on or take down
+type DisruptionRisk struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster,omitempty"`
+	Replicas  int32  `json:"replicas"`
+	Reason    string `json:"reason"` // zero-disruptions-allowed, no-pdb
+	PDB       string `json:"pdb,omitempty"`
+	Message   string `json:"message"`
+}
+
+// DrainBlocker is a PDB that would stop the pods of a node from being evicted
+type DrainBlocker struct {
+	PDB                string   `json:"pdb"`
+	Namespace          string   `json:"namespace"`
+	DisruptionsAllowed int32    `json:"disruptionsAllowed"`
+	Pods               []string `json:"pods"`
+}
+
+// DrainCheck reports whether a node can be drained without PDBs blocking evictions
+type DrainCheck struct {
+	Node          string         `json:"node"`
+	Cluster       string         `json:"cluster,omitempty"`
+	EvictablePods int            `json:"evictablePods"`
+	Blockers      []DrainBlocker `json:"blockers"`
+	Unprotected   []string       `json:"unprotected,omitempty"` // namespace/pod with no PDB
+	Safe          bool           `json:"safe"`
+}
+
+// GetPDBs returns all PodDisruptionBudgets in a namespace or all namespaces if namespace is empty
+func (m *MultiClusterClient) GetPDBs(ctx context.Context, contextName, namespace string) ([]PDB, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]PDB, error) {
+			return m.GetPDBs(ctx, contextName, ns)
+		})
+	}
+	pdbs, err := m.listPDBs(ctx, contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PDB, 0, len(pdbs))
+	for _, pdb := range pdbs {
+		info := PDB{
+			Name:               pdb.Name,
+			Namespace:          pdb.Namespace,
+			Cluster:            contextName,
+			Selector:           metav1.FormatLabelSelector(pdb.Spec.Selector),
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			Age:                formatAge(pdb.CreationTimestamp.Time),
+			Labels:             pdb.Labels,
+		}
+		if pdb.Spec.MinAvailable != nil {
+			info.MinAvailable = pdb.Spec.MinAvailable.String()
+		}
+		if pdb.Spec.MaxUnavailable != nil {
+			info.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// FindDisruptionRisks flags deployments guarded by a PDB that allows no
+// disruptions and deployments that no PDB covers at all
+func (m *MultiClusterClient) FindDisruptionRisks(ctx context.Context, contextName, namespace string) ([]DisruptionRisk, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]DisruptionRisk, error) {
+			return m.FindDisruptionRisks(ctx, contextName, ns)
+		})
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pdbs, err := m.listPDBs(ctx, contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var risks []DisruptionRisk
+	for _, deploy := range deployments.Items {
+		replicas := int32(1)
+		if deploy.Spec.Replicas != nil {
+			replicas = *deploy.Spec.Replicas
+		}
+		if replicas == 0 {
+			continue
+		}
+		risk := DisruptionRisk{
+			Kind:      "Deployment",
+			Name:      deploy.Name,
+			Namespace: deploy.Namespace,
+			Cluster:   contextName,
+			Replicas:  replicas,
+		}
+		matching := matchingPDBs(pdbs, deploy.Namespace, deploy.Spec.Template.Labels)
+		if len(matching) == 0 {
+			risk.Reason = DisruptionUnprotected
+			risk.Message = fmt.Sprintf("No PodDisruptionBudget covers %d replica(s); a drain may evict them all at once", replicas)
+			risks = append(risks, risk)
+			continue
+		}
+		for _, pdb := range matching {
+			if pdb.Status.DisruptionsAllowed == 0 {
+				risk.Reason = DisruptionBlocked
+				risk.PDB = pdb.Name
+				risk.Message = fmt.Sprintf("PodDisruptionBudget %s allows 0 disruptions (%d/%d healthy); node drains will block on these pods",
+					pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy)
+				risks = append(risks, risk)
+				break
+			}
+		}
+	}
+	return risks, nil
+}
+
+// CheckDrain reports which PDBs would block evicting the pods on a node, as
+// "kubectl drain" would do. DaemonSet and mirror pods are not evicted by a drain.
+func (m *MultiClusterClient) CheckDrain(ctx context.Context, contextName, nodeName string) (*DrainCheck, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+	if err != nil {
+		return nil, err
+	}
+	pdbs, err := m.listPDBs(ctx, contextName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	check := &DrainCheck{Node: nodeName, Cluster: contextName, Blockers: []DrainBlocker{}}
+	evictions := make(map[string]*DrainBlocker) // namespace/pdb → pods evicted under it
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName || !drainEvicts(pod) {
+			continue
+		}
+		check.EvictablePods++
+		matching := matchingPDBs(pdbs, pod.Namespace, pod.Labels)
+		if len(matching) == 0 {
+			check.Unprotected = append(check.Unprotected, pod.Namespace+"/"+pod.Name)
+			continue
+		}
+		for _, pdb := range matching {
+			key := pdb.Namespace + "/" + pdb.Name
+			b, ok := evictions[key]
+			if !ok {
+				b = &DrainBlocker{PDB: pdb.Name, Namespace: pdb.Namespace, DisruptionsAllowed: pdb.Status.DisruptionsAllowed}
+				evictions[key] = b
+			}
+			b.Pods = append(b.Pods, pod.Name)
+		}
+	}
+
+	// A PDB blocks the drain once the node holds more of its pods than it lets go
+	for _, b := range evictions {
+		if int32(len(b.Pods)) > b.DisruptionsAllowed {
+			check.Blockers = append(check.Blockers, *b)
+		}
+	}
+	sort.Slice(check.Blockers, func(i, j int) bool {
+		if check.Blockers[i].Namespace != check.Blockers[j].Namespace {
+			return check.Blockers[i].Namespace < check.Blockers[j].Namespace
+		}
+		return check.Blockers[i].PDB < check.Blockers[j].PDB
+	})
+	check.Safe = len(check.Blockers) == 0
+	return check, nil
+}
+
+// listPDBs lists the policy/v1 PodDisruptionBudgets of a namespace, or of all namespaces
+func (m *MultiClusterClient) listPDBs(ctx context.Context, contextName, namespace string) ([]policyv1.PodDisruptionBudget, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// matchingPDBs returns the PDBs in namespace whose selector matches podLabels.
+// A nil selector matches nothing and an empty one matches every pod, as in policy/v1.
+func matchingPDBs(pdbs []policyv1.PodDisruptionBudget, namespace string, podLabels map[string]string) []policyv1.PodDisruptionBudget {
+	var out []policyv1.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		if pdb.Namespace != namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			out = append(out, pdb)
+		}
+	}
+	return out
+}
+
+// drainEvicts reports whether "kubectl drain" would evict the pod
+func drainEvicts(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+		return false
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+// deploymentPDBs returns the PDBs covering a pod template and the fewest
+// disruptions any of them allows, or nil when none covers it
+func deploymentPDBs(pdbs []policyv1.PodDisruptionBudget, namespace string, templateLabels map[string]string) ([]string, *int32) {
+	var names []string
+	var disruptionsAllowed *int32
+	for _, pdb := range matchingPDBs(pdbs, namespace, templateLabels) {
+		names = append(names, pdb.Name)
+		if allowed := pdb.Status.DisruptionsAllowed; disruptionsAllowed == nil || allowed < *disruptionsAllowed {
+			disruptionsAllowed = &allowed
+		}
+	}
+	return names, disruptionsAllowed
+}
@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func pdbTestClient(t *testing.T) *MultiClusterClient {
+	t.Helper()
+	deployment := func(name string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}}},
+			},
+		}
+	}
+	pdb := func(name, app string, allowed int32) *policyv1.PodDisruptionBudget {
+		minAvailable := intstr.FromInt32(2)
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable: &minAvailable,
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: allowed, CurrentHealthy: 2, DesiredHealthy: 2},
+		}
+	}
+	pod := func(name, app, node string, owner string) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if owner != "" {
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: owner, Name: app}}
+		}
+		return p
+	}
+
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(
+		deployment("api", 2), deployment("web", 3), deployment("worker", 3), deployment("idle", 0),
+		pdb("api-pdb", "api", 0), pdb("web-pdb", "web", 1),
+		pod("api-1", "api", "node-1", ""), pod("web-1", "web", "node-1", ""), pod("web-2", "web", "node-2", ""),
+		pod("worker-1", "worker", "node-1", ""), pod("logs-1", "logs", "node-1", "DaemonSet"),
+	))
+	return m
+}
+
+func TestGetPDBs(t *testing.T) {
+	m := pdbTestClient(t)
+	pdbs, err := m.GetPDBs(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("GetPDBs failed: %v", err)
+	}
+	if len(pdbs) != 2 || pdbs[0].MinAvailable != "2" || pdbs[0].Selector != "app=api" {
+		t.Errorf("Expected 2 PDBs with minAvailable and selector, got %+v", pdbs)
+	}
+
+	deployments, err := m.GetDeployments(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("GetDeployments failed: %v", err)
+	}
+	for _, d := range deployments {
+		switch d.Name {
+		case "api":
+			if len(d.PDBs) != 1 || d.DisruptionsAllowed == nil || *d.DisruptionsAllowed != 0 {
+				t.Errorf("Expected api to reference api-pdb with 0 disruptions, got %v %v", d.PDBs, d.DisruptionsAllowed)
+			}
+		case "worker":
+			if len(d.PDBs) != 0 || d.DisruptionsAllowed != nil {
+				t.Errorf("Expected worker to have no PDB, got %v", d.PDBs)
+			}
+		}
+	}
+}
+
+func TestFindDisruptionRisks(t *testing.T) {
+	m := pdbTestClient(t)
+	risks, err := m.FindDisruptionRisks(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("FindDisruptionRisks failed: %v", err)
+	}
+	reasons := map[string]string{}
+	for _, r := range risks {
+		reasons[r.Name] = r.Reason
+	}
+	if len(reasons) != 2 || reasons["api"] != DisruptionBlocked || reasons["worker"] != DisruptionUnprotected {
+		t.Errorf("Expected api blocked and worker unprotected, got %v", reasons)
+	}
+}
+
+func TestCheckDrain(t *testing.T) {
+	m := pdbTestClient(t)
+	check, err := m.CheckDrain(context.Background(), "c1", "node-1")
+	if err != nil {
+		t.Fatalf("CheckDrain failed: %v", err)
+	}
+	// api-pdb allows no disruptions; web-pdb allows one and only web-1 is on the node
+	if check.Safe || len(check.Blockers) != 1 || check.Blockers[0].PDB != "api-pdb" {
+		t.Errorf("Expected api-pdb to block the drain, got %+v", check.Blockers)
+	}
+	if check.EvictablePods != 3 || len(check.Unprotected) != 1 || check.Unprotected[0] != "default/worker-1" {
+		t.Errorf("Expected 3 evictable pods with worker-1 unprotected, got %d %v", check.EvictablePods, check.Unprotected)
+	}
+
+	check, err = m.CheckDrain(context.Background(), "c1", "node-2")
+	if err != nil || !check.Safe {
+		t.Errorf("Expected node-2 to drain safely, got %+v (%v)", check, err)
+	}
+}
@@ -0,0 +1,217 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// platformLabels are the node labels the scheduler matches to place pods by OS and architecture
+var platformLabels = []string{corev1.LabelOSStable, corev1.LabelArchStable, "beta.kubernetes.io/os", "beta.kubernetes.io/arch"}
+
+// platformMismatchMessages appear in container statuses when an image has no build for the node
+var platformMismatchMessages = []string{"no match for platform", "no matching manifest", "exec format error"}
+
+// PlatformCapacity is the share of a cluster's nodes and capacity on one OS/architecture
+type PlatformCapacity struct {
+	Platform      string `json:"platform"` // os/arch, e.g. linux/arm64
+	Nodes         int    `json:"nodes"`
+	ReadyNodes    int    `json:"readyNodes"`
+	CPUMillicores int64  `json:"cpuMillicores"`
+	MemoryBytes   int64  `json:"memoryBytes"`
+	GPUs          int64  `json:"gpus"`
+}
+
+// PlatformIssue is a pod at risk of landing on, or already failing on, a node
+// whose OS or architecture its images may not support
+type PlatformIssue struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Cluster      string `json:"cluster,omitempty"`
+	Node         string `json:"node,omitempty"`
+	NodePlatform string `json:"nodePlatform,omitempty"`
+	Issue        string `json:"issue"`
+	Severity     string `json:"severity"` // high, medium
+	Details      string `json:"details,omitempty"`
+}
+
+// nodePlatform returns a node's os/arch from its well-known labels, falling
+// back to what the kubelet reports
+func nodePlatform(node *corev1.Node) string {
+	os, arch := node.Labels[corev1.LabelOSStable], node.Labels[corev1.LabelArchStable]
+	if os == "" {
+		os = node.Status.NodeInfo.OperatingSystem
+	}
+	if arch == "" {
+		arch = node.Status.NodeInfo.Architecture
+	}
+	if os == "" && arch == "" {
+		return "unknown"
+	}
+	return os + "/" + arch
+}
+
+// platformCapacity splits node counts and allocatable capacity by os/arch,
+// largest platform first
+func platformCapacity(nodes []corev1.Node) []PlatformCapacity {
+	byPlatform := make(map[string]*PlatformCapacity)
+	for i := range nodes {
+		node := &nodes[i]
+		platform := nodePlatform(node)
+		p, ok := byPlatform[platform]
+		if !ok {
+			p = &PlatformCapacity{Platform: platform}
+			byPlatform[platform] = p
+		}
+		p.Nodes++
+		if isNodeReady(node) {
+			p.ReadyNodes++
+		}
+		p.CPUMillicores += node.Status.Allocatable.Cpu().MilliValue()
+		p.MemoryBytes += node.Status.Allocatable.Memory().Value()
+		for _, r := range gpuResourceNames {
+			if qty, ok := node.Status.Allocatable[r]; ok {
+				p.GPUs += qty.Value()
+			}
+		}
+	}
+	result := make([]PlatformCapacity, 0, len(byPlatform))
+	for _, p := range byPlatform {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Nodes != result[j].Nodes {
+			return result[i].Nodes > result[j].Nodes
+		}
+		return result[i].Platform < result[j].Platform
+	})
+	return result
+}
+
+// pinsPlatform reports whether a pod's node selector or required node
+// affinity constrains the OS or architecture it may run on
+func pinsPlatform(pod *corev1.Pod) bool {
+	for _, l := range platformLabels {
+		if _, ok := pod.Spec.NodeSelector[l]; ok {
+			return true
+		}
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return false
+	}
+	// Every term must constrain the platform, since any one of them may match
+	for _, term := range terms {
+		pinned := false
+		for _, expr := range term.MatchExpressions {
+			for _, l := range platformLabels {
+				pinned = pinned || expr.Key == l
+			}
+		}
+		if !pinned {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckPlatformScheduling flags pods whose containers fail because their image
+// has no build for the node's platform and, in clusters that mix operating
+// systems or architectures, pods not pinned to one that may land on a node
+// their images don't support. DaemonSet pods are expected to run everywhere
+// and are only flagged on failure.
+func (m *MultiClusterClient) CheckPlatformScheduling(ctx context.Context, contextName, namespace string) ([]PlatformIssue, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	platforms := make(map[string]string, len(nodes.Items))
+	for i := range nodes.Items {
+		platforms[nodes.Items[i].Name] = nodePlatform(&nodes.Items[i])
+	}
+	capacity := platformCapacity(nodes.Items)
+	names := make([]string, 0, len(capacity))
+	for _, p := range capacity {
+		names = append(names, p.Platform)
+	}
+	mixed := len(capacity) > 1
+
+	issues := make([]PlatformIssue, 0)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		issue := PlatformIssue{
+			Name:         pod.Name,
+			Namespace:    pod.Namespace,
+			Cluster:      contextName,
+			Node:         pod.Spec.NodeName,
+			NodePlatform: platforms[pod.Spec.NodeName],
+		}
+		if msg := platformMismatch(pod); msg != "" {
+			issue.Issue, issue.Severity, issue.Details = "Image not built for node platform", "high", msg
+			issues = append(issues, issue)
+			continue
+		}
+		if !mixed || pinsPlatform(pod) || isDaemonSetPod(pod) {
+			continue
+		}
+		issue.Issue, issue.Severity = "Pod not pinned to an OS or architecture", "medium"
+		issue.Details = fmt.Sprintf("Cluster mixes %s; set a %s or %s node selector", strings.Join(names, ", "), corev1.LabelOSStable, corev1.LabelArchStable)
+		if strings.HasPrefix(issue.NodePlatform, "windows/") {
+			issue.Severity = "high"
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// platformMismatch returns the status message of a container that failed
+// because its image does not support the node's platform, or ""
+func platformMismatch(pod *corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		var msgs []string
+		if w := cs.State.Waiting; w != nil {
+			msgs = append(msgs, w.Message)
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil {
+			msgs = append(msgs, t.Message)
+		}
+		for _, msg := range msgs {
+			lower := strings.ToLower(msg)
+			for _, pattern := range platformMismatchMessages {
+				if strings.Contains(lower, pattern) {
+					return fmt.Sprintf("container %s: %s", cs.Name, msg)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// isDaemonSetPod reports whether a pod is controlled by a DaemonSet
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	if ref := metav1.GetControllerOf(pod); ref != nil {
+		return ref.Kind == "DaemonSet"
+	}
+	return false
+}
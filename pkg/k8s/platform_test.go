@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckPlatformScheduling(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	node := func(name, os, arch string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelOSStable: os, corev1.LabelArchStable: arch}},
+			Status: corev1.NodeStatus{
+				Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			},
+		}
+	}
+	pod := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	controller := true
+	pinned := pod("pinned", "amd-1")
+	pinned.Spec.NodeSelector = map[string]string{corev1.LabelArchStable: "amd64"}
+	affinity := pod("affinity", "amd-1")
+	affinity.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}}},
+		}}},
+	}}
+	daemon := pod("agent", "arm-1")
+	daemon.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &controller}}
+	broken := pod("broken", "arm-1")
+	broken.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:                 "app",
+		State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "exec /app: exec format error"}},
+	}}
+
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		node("amd-1", "linux", "amd64"),
+		node("amd-2", "linux", "amd64"),
+		node("arm-1", "linux", "arm64"),
+		node("win-1", "windows", "amd64"),
+		pod("unpinned", "amd-2"),
+		pod("on-windows", "win-1"),
+		pinned, affinity, daemon, broken,
+	)
+
+	issues, err := m.CheckPlatformScheduling(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("CheckPlatformScheduling failed: %v", err)
+	}
+	byPod := make(map[string]PlatformIssue)
+	for _, issue := range issues {
+		byPod[issue.Name] = issue
+	}
+	if len(byPod) != 3 {
+		t.Errorf("Expected issues for unpinned, on-windows and broken, got %+v", issues)
+	}
+	if i := byPod["unpinned"]; i.Severity != "medium" || i.NodePlatform != "linux/amd64" {
+		t.Errorf("Unexpected unpinned issue: %+v", i)
+	}
+	if i := byPod["on-windows"]; i.Severity != "high" {
+		t.Errorf("Expected an unpinned pod on windows to be high severity, got %+v", i)
+	}
+	if i := byPod["broken"]; i.Issue != "Image not built for node platform" || i.NodePlatform != "linux/arm64" {
+		t.Errorf("Unexpected platform mismatch issue: %+v", i)
+	}
+
+	health, err := m.GetClusterHealth(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetClusterHealth failed: %v", err)
+	}
+	if len(health.Platforms) != 3 || health.Platforms[0].Platform != "linux/amd64" || health.Platforms[0].Nodes != 2 || health.Platforms[0].CPUMillicores != 8000 {
+		t.Errorf("Unexpected platform capacity: %+v", health.Platforms)
+	}
+
+	// A single-platform cluster has nothing to pin against
+	m.clients["c2"] = k8sfake.NewSimpleClientset(node("amd-1", "linux", "amd64"), pod("unpinned", "amd-1"))
+	if issues, err := m.CheckPlatformScheduling(context.Background(), "c2", ""); err != nil || len(issues) != 0 {
+		t.Errorf("Expected no issues in a homogeneous cluster, got %+v, %v", issues, err)
+	}
+}
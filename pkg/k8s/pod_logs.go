@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultLogMatches is how many matching lines a grep returns when no limit is given
+	defaultLogMatches = 500
+	// maxLogMatches caps the matching lines returned by a grep
+	maxLogMatches = 5000
+	// maxLogLineBytes is the longest log line scanned when grepping
+	maxLogLineBytes = 1024 * 1024
+)
+
+// PodLogOptions selects which logs GetPodLogsWithOptions returns
+type PodLogOptions struct {
+	Container string
+	TailLines int64
+	// Previous returns the logs of the last terminated instance of the container
+	Previous     bool
+	SinceSeconds int64
+	SinceTime    *time.Time
+	Timestamps   bool
+	// Grep is a regular expression; only matching lines are returned
+	Grep string
+	// MaxMatches caps the matching lines returned by Grep
+	MaxMatches int
+}
+
+// PodLogResult is the log text plus match accounting when grepping
+type PodLogResult struct {
+	Logs      string `json:"logs"`
+	Matches   int    `json:"matches,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"` // the match limit was reached
+}
+
+// GetPodLogsWithOptions returns pod logs, optionally from the previous container
+// instance, since a point in time, with timestamps, or filtered server-side by a
+// regular expression. Grepping streams the logs so large logs are never held in
+// memory or sent to the client in full.
+func (m *MultiClusterClient) GetPodLogsWithOptions(ctx context.Context, contextName, namespace, podName string, opts PodLogOptions) (*PodLogResult, error) {
+	var grep *regexp.Regexp
+	if opts.Grep != "" {
+		re, err := regexp.Compile(opts.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grep pattern: %w", err)
+		}
+		grep = re
+	}
+	if opts.SinceSeconds > 0 && opts.SinceTime != nil {
+		return nil, fmt.Errorf("sinceSeconds and sinceTime are mutually exclusive")
+	}
+
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	logOpts := &corev1.PodLogOptions{
+		Container:  opts.Container,
+		Previous:   opts.Previous,
+		Timestamps: opts.Timestamps,
+	}
+	if opts.TailLines > 0 {
+		logOpts.TailLines = &opts.TailLines
+	}
+	if opts.SinceSeconds > 0 {
+		logOpts.SinceSeconds = &opts.SinceSeconds
+	}
+	if opts.SinceTime != nil {
+		since := metav1.NewTime(*opts.SinceTime)
+		logOpts.SinceTime = &since
+	}
+	req := client.CoreV1().Pods(namespace).GetLogs(podName, logOpts)
+
+	if grep == nil {
+		logs, err := req.DoRaw(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &PodLogResult{Logs: string(logs)}, nil
+	}
+
+	limit := opts.MaxMatches
+	if limit <= 0 {
+		limit = defaultLogMatches
+	}
+	if limit > maxLogMatches {
+		limit = maxLogMatches
+	}
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	result := &PodLogResult{}
+	var sb strings.Builder
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !grep.MatchString(line) {
+			continue
+		}
+		if result.Matches == limit {
+			result.Truncated = true
+			break
+		}
+		result.Matches++
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	result.Logs = sb.String()
+	return result, nil
+}
@@ -0,0 +1,42 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPodLogsWithOptions(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+	}))
+	ctx := context.Background()
+
+	// The fake clientset always returns "fake logs"
+	result, err := m.GetPodLogsWithOptions(ctx, "c1", "default", "api", PodLogOptions{Previous: true, SinceSeconds: 60})
+	if err != nil || result.Logs != "fake logs" {
+		t.Fatalf("Expected the previous container logs, got %+v, %v", result, err)
+	}
+
+	result, err = m.GetPodLogsWithOptions(ctx, "c1", "default", "api", PodLogOptions{Grep: "^fake"})
+	if err != nil || result.Matches != 1 || result.Logs != "fake logs\n" || result.Truncated {
+		t.Errorf("Expected one matching line, got %+v, %v", result, err)
+	}
+	result, err = m.GetPodLogsWithOptions(ctx, "c1", "default", "api", PodLogOptions{Grep: "(?i)error"})
+	if err != nil || result.Matches != 0 || result.Logs != "" {
+		t.Errorf("Expected no matching lines, got %+v, %v", result, err)
+	}
+
+	if _, err := m.GetPodLogsWithOptions(ctx, "c1", "default", "api", PodLogOptions{Grep: "("}); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+	since := time.Now().Add(-time.Hour)
+	if _, err := m.GetPodLogsWithOptions(ctx, "c1", "default", "api", PodLogOptions{SinceSeconds: 60, SinceTime: &since}); err == nil {
+		t.Error("Expected sinceSeconds and sinceTime together to be rejected")
+	}
+}
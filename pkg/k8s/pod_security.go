@@ -0,0 +1,321 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Pod Security Standards levels, from least to most restrictive
+const (
+	PSSPrivileged = "privileged"
+	PSSBaseline   = "baseline"
+	PSSRestricted = "restricted"
+)
+
+// dangerousCapabilities grant near host-level control when added to a container
+var dangerousCapabilities = map[corev1.Capability]bool{
+	"NET_ADMIN":  true,
+	"SYS_ADMIN":  true,
+	"SYS_MODULE": true,
+	"SYS_PTRACE": true,
+	"NET_RAW":    true,
+	"ALL":        true,
+}
+
+// baselineCapabilities may be added under the baseline standard
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE":      true,
+	"CHOWN":            true,
+	"DAC_OVERRIDE":     true,
+	"FOWNER":           true,
+	"FSETID":           true,
+	"KILL":             true,
+	"MKNOD":            true,
+	"NET_BIND_SERVICE": true,
+	"SETFCAP":          true,
+	"SETGID":           true,
+	"SETPCAP":          true,
+	"SETUID":           true,
+	"SYS_CHROOT":       true,
+}
+
+// PodSecurityLevel is the most restrictive Pod Security Standard a pod
+// satisfies, and why it fails the stricter ones
+type PodSecurityLevel struct {
+	Name       string   `json:"name"`
+	Namespace  string   `json:"namespace"`
+	Cluster    string   `json:"cluster,omitempty"`
+	OwnerKind  string   `json:"ownerKind,omitempty"`
+	OwnerName  string   `json:"ownerName,omitempty"`
+	Level      string   `json:"level"` // privileged, baseline, restricted
+	Violations []string `json:"violations,omitempty"`
+}
+
+// CheckPodSecurityLevels evaluates every pod against the Pod Security Standards
+func (m *MultiClusterClient) CheckPodSecurityLevels(ctx context.Context, contextName, namespace string) ([]PodSecurityLevel, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]PodSecurityLevel, 0, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		level, violations := podSecurityStandard(&pod.Spec)
+		l := PodSecurityLevel{
+			Name:       pod.Name,
+			Namespace:  pod.Namespace,
+			Cluster:    contextName,
+			Level:      level,
+			Violations: violations,
+		}
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			l.OwnerKind, l.OwnerName = owner.Kind, owner.Name
+		}
+		levels = append(levels, l)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if levels[i].Namespace != levels[j].Namespace {
+			return levels[i].Namespace < levels[j].Namespace
+		}
+		return levels[i].Name < levels[j].Name
+	})
+	return levels, nil
+}
+
+// podContainer is a container, init container or ephemeral container of a pod
+type podContainer struct {
+	name  string
+	sc    *corev1.SecurityContext
+	ports []corev1.ContainerPort
+}
+
+func podContainers(spec *corev1.PodSpec) []podContainer {
+	var out []podContainer
+	for _, c := range spec.InitContainers {
+		out = append(out, podContainer{c.Name, c.SecurityContext, c.Ports})
+	}
+	for _, c := range spec.Containers {
+		out = append(out, podContainer{c.Name, c.SecurityContext, c.Ports})
+	}
+	for _, c := range spec.EphemeralContainers {
+		out = append(out, podContainer{c.Name, c.SecurityContext, c.Ports})
+	}
+	return out
+}
+
+// podHardeningIssues checks a pod spec for added capabilities, host paths and
+// ports, missing seccomp profiles, unconfined AppArmor, privilege escalation
+// and writable root filesystems
+func podHardeningIssues(name, namespace, contextName string, spec *corev1.PodSpec) []SecurityIssue {
+	var issues []SecurityIssue
+	add := func(issue, severity, details string) {
+		issues = append(issues, SecurityIssue{
+			Name:      name,
+			Namespace: namespace,
+			Cluster:   contextName,
+			Issue:     issue,
+			Severity:  severity,
+			Details:   details,
+		})
+	}
+
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			add("HostPath volume", "high", fmt.Sprintf("Volume '%s' mounts host path %s", v.Name, v.HostPath.Path))
+		}
+	}
+	if spec.HostIPC {
+		add("Host IPC enabled", "medium", "Pod sharing host IPC namespace")
+	}
+
+	podSC := spec.SecurityContext
+	for _, c := range podContainers(spec) {
+		sc := c.sc
+		for _, p := range c.ports {
+			if p.HostPort != 0 {
+				add("Host port", "medium", fmt.Sprintf("Container '%s' binds host port %d", c.name, p.HostPort))
+			}
+		}
+		if sc != nil && sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if dangerousCapabilities[capability] {
+					add("Dangerous capability", "high", fmt.Sprintf("Container '%s' adds capability %s", c.name, capability))
+				} else if !baselineCapabilities[capability] {
+					add("Added capability", "medium", fmt.Sprintf("Container '%s' adds capability %s", c.name, capability))
+				}
+			}
+		}
+		if seccompType(sc, podSC) == "" {
+			add("Missing seccomp profile", "low", fmt.Sprintf("Container '%s' has no seccomp profile", c.name))
+		} else if seccompType(sc, podSC) == corev1.SeccompProfileTypeUnconfined {
+			add("Unconfined seccomp profile", "medium", fmt.Sprintf("Container '%s' runs without seccomp filtering", c.name))
+		}
+		if appArmorType(sc, podSC) == corev1.AppArmorProfileTypeUnconfined {
+			add("Unconfined AppArmor profile", "medium", fmt.Sprintf("Container '%s' runs without AppArmor confinement", c.name))
+		}
+		if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			add("Privilege escalation allowed", "low", fmt.Sprintf("Container '%s' does not set allowPrivilegeEscalation: false", c.name))
+		}
+		if sc == nil || sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			add("Writable root filesystem", "low", fmt.Sprintf("Container '%s' has a writable root filesystem", c.name))
+		}
+	}
+	return issues
+}
+
+// seccompType returns the effective seccomp profile type of a container
+func seccompType(sc *corev1.SecurityContext, podSC *corev1.PodSecurityContext) corev1.SeccompProfileType {
+	if sc != nil && sc.SeccompProfile != nil {
+		return sc.SeccompProfile.Type
+	}
+	if podSC != nil && podSC.SeccompProfile != nil {
+		return podSC.SeccompProfile.Type
+	}
+	return ""
+}
+
+// appArmorType returns the effective AppArmor profile type of a container
+func appArmorType(sc *corev1.SecurityContext, podSC *corev1.PodSecurityContext) corev1.AppArmorProfileType {
+	if sc != nil && sc.AppArmorProfile != nil {
+		return sc.AppArmorProfile.Type
+	}
+	if podSC != nil && podSC.AppArmorProfile != nil {
+		return podSC.AppArmorProfile.Type
+	}
+	return ""
+}
+
+// podSecurityStandard returns the most restrictive Pod Security Standards
+// level a pod spec satisfies, with the checks it fails at the stricter levels
+func podSecurityStandard(spec *corev1.PodSpec) (string, []string) {
+	baseline := podBaselineViolations(spec)
+	restricted := podRestrictedViolations(spec)
+	switch {
+	case len(baseline) > 0:
+		return PSSPrivileged, append(baseline, restricted...)
+	case len(restricted) > 0:
+		return PSSBaseline, restricted
+	default:
+		return PSSRestricted, nil
+	}
+}
+
+// podBaselineViolations lists the baseline checks a pod spec fails
+func podBaselineViolations(spec *corev1.PodSpec) []string {
+	var v []string
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		v = append(v, "host namespaces are shared")
+	}
+	for _, vol := range spec.Volumes {
+		if vol.HostPath != nil {
+			v = append(v, fmt.Sprintf("volume %s uses hostPath", vol.Name))
+		}
+	}
+	podSC := spec.SecurityContext
+	if podSC != nil && podSC.SeccompProfile != nil && podSC.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		v = append(v, "pod seccomp profile is Unconfined")
+	}
+	if podSC != nil && podSC.AppArmorProfile != nil && podSC.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+		v = append(v, "pod AppArmor profile is Unconfined")
+	}
+	for _, c := range podContainers(spec) {
+		for _, p := range c.ports {
+			if p.HostPort != 0 {
+				v = append(v, fmt.Sprintf("container %s uses hostPort %d", c.name, p.HostPort))
+			}
+		}
+		sc := c.sc
+		if sc == nil {
+			continue
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			v = append(v, fmt.Sprintf("container %s is privileged", c.name))
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					v = append(v, fmt.Sprintf("container %s adds capability %s", c.name, capability))
+				}
+			}
+		}
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			v = append(v, fmt.Sprintf("container %s seccomp profile is Unconfined", c.name))
+		}
+		if sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			v = append(v, fmt.Sprintf("container %s AppArmor profile is Unconfined", c.name))
+		}
+		if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			v = append(v, fmt.Sprintf("container %s uses a non-default procMount", c.name))
+		}
+	}
+	return v
+}
+
+// restrictedVolumeType reports whether a volume is of a type the restricted standard allows
+func restrictedVolumeType(vol corev1.Volume) bool {
+	s := vol.VolumeSource
+	return s.ConfigMap != nil || s.CSI != nil || s.DownwardAPI != nil || s.EmptyDir != nil ||
+		s.Ephemeral != nil || s.PersistentVolumeClaim != nil || s.Projected != nil || s.Secret != nil
+}
+
+// podRestrictedViolations lists the restricted checks a pod spec fails
+// beyond the baseline ones
+func podRestrictedViolations(spec *corev1.PodSpec) []string {
+	var v []string
+	for _, vol := range spec.Volumes {
+		if vol.HostPath == nil && !restrictedVolumeType(vol) {
+			v = append(v, fmt.Sprintf("volume %s uses a volume type restricted pods may not", vol.Name))
+		}
+	}
+	podSC := spec.SecurityContext
+	podNonRoot := podSC != nil && podSC.RunAsNonRoot != nil && *podSC.RunAsNonRoot
+	if podSC != nil && podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
+		v = append(v, "pod runs as UID 0")
+	}
+	for _, c := range podContainers(spec) {
+		sc := c.sc
+		if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			v = append(v, fmt.Sprintf("container %s must set allowPrivilegeEscalation=false", c.name))
+		}
+		nonRoot := podNonRoot
+		if sc != nil && sc.RunAsNonRoot != nil {
+			nonRoot = *sc.RunAsNonRoot
+		}
+		if !nonRoot {
+			v = append(v, fmt.Sprintf("container %s must set runAsNonRoot=true", c.name))
+		}
+		if sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			v = append(v, fmt.Sprintf("container %s runs as UID 0", c.name))
+		}
+		if t := seccompType(sc, podSC); t != corev1.SeccompProfileTypeRuntimeDefault && t != corev1.SeccompProfileTypeLocalhost {
+			v = append(v, fmt.Sprintf("container %s must set seccompProfile to RuntimeDefault or Localhost", c.name))
+		}
+		dropsAll := false
+		if sc != nil && sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Drop {
+				if capability == "ALL" {
+					dropsAll = true
+				}
+			}
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" {
+					v = append(v, fmt.Sprintf("container %s may only add NET_BIND_SERVICE, adds %s", c.name, capability))
+				}
+			}
+		}
+		if !dropsAll {
+			v = append(v, fmt.Sprintf("container %s must drop ALL capabilities", c.name))
+		}
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podStreamPageSize is how many pods StreamPods requests from the API server at a time
+const podStreamPageSize = 500
+
+// errStopStream wraps errors returned by a StreamPods emit callback
+type errStopStream struct{ err error }
+
+func (e errStopStream) Error() string { return e.err.Error() }
+
+// StreamPods lists pods a page at a time and calls emit for each pod as it is
+// converted, so very large clusters can be streamed without holding every pod
+// in memory. An error from emit stops the listing and is returned. As with
+// GetPods, namespaces that fail under a namespace scope are skipped unless all
+// of them fail.
+func (m *MultiClusterClient) StreamPods(ctx context.Context, contextName, namespace string, emit func(PodInfo) error) error {
+	namespaces, err := m.scopeNamespaces(ctx, contextName, namespace)
+	if err != nil {
+		return err
+	}
+	scoped := namespaces != nil
+	if !scoped {
+		namespaces = []string{namespace}
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	failed := 0
+	for _, ns := range namespaces {
+		err := streamNamespacePods(ctx, client.CoreV1().Pods(ns), contextName, emit)
+		var stop errStopStream
+		if errors.As(err, &stop) {
+			return stop.err
+		}
+		if err != nil {
+			if !scoped {
+				return err
+			}
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if failed > 0 && failed == len(namespaces) {
+		return firstErr
+	}
+	return nil
+}
+
+// podLister is the part of the typed pods client StreamPods pages through
+type podLister interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error)
+}
+
+func streamNamespacePods(ctx context.Context, pods podLister, contextName string, emit func(PodInfo) error) error {
+	opts := metav1.ListOptions{Limit: podStreamPageSize}
+	for {
+		page, err := pods.List(ctx, opts)
+		if err != nil {
+			return err
+		}
+		for i := range page.Items {
+			if err := emit(podInfo(&page.Items[i], contextName)); err != nil {
+				return errStopStream{err}
+			}
+		}
+		if page.Continue == "" {
+			return nil
+		}
+		opts.Continue = page.Continue
+	}
+}
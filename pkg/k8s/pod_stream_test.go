@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+// pagedPods serves a fixed pod list in pages of two, like an API server honoring Limit
+type pagedPods struct {
+	pods  []corev1.Pod
+	calls int
+}
+
+func (p *pagedPods) List(_ context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+	p.calls++
+	start := 0
+	if opts.Continue != "" {
+		fmt.Sscanf(opts.Continue, "%d", &start)
+	}
+	end := min(start+2, len(p.pods))
+	list := &corev1.PodList{Items: p.pods[start:end]}
+	if end < len(p.pods) {
+		list.Continue = fmt.Sprint(end)
+	}
+	return list, nil
+}
+
+func TestStreamNamespacePods(t *testing.T) {
+	lister := &pagedPods{}
+	for i := 0; i < 5; i++ {
+		lister.pods = append(lister.pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default"}})
+	}
+
+	var names []string
+	err := streamNamespacePods(context.Background(), lister, "ctx", func(p PodInfo) error {
+		names = append(names, p.Name)
+		return nil
+	})
+	if err != nil || len(names) != 5 || names[4] != "pod-4" || lister.calls != 3 {
+		t.Errorf("Expected 5 pods over 3 pages, got %v (%d calls, err %v)", names, lister.calls, err)
+	}
+
+	stop := errors.New("client went away")
+	seen := 0
+	err = streamNamespacePods(context.Background(), &pagedPods{pods: lister.pods}, "ctx", func(PodInfo) error {
+		seen++
+		return stop
+	})
+	var stopped errStopStream
+	if !errors.As(err, &stopped) || stopped.err != stop || seen != 1 {
+		t.Errorf("Expected the emit error to stop the stream, got %v after %d pods", err, seen)
+	}
+}
+
+func TestStreamPods(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.clients["ctx"] = k8sfake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "kube-system"}},
+	)
+
+	var names []string
+	err := m.StreamPods(context.Background(), "ctx", "", func(p PodInfo) error {
+		names = append(names, p.Namespace+"/"+p.Name)
+		return nil
+	})
+	if err != nil || len(names) != 2 {
+		t.Errorf("Expected both pods, got %v (%v)", names, err)
+	}
+
+	stop := errors.New("stop")
+	if err := m.StreamPods(context.Background(), "ctx", "", func(PodInfo) error { return stop }); err != stop {
+		t.Errorf("Expected the emit error back, got %v", err)
+	}
+}
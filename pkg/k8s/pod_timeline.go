@@ -0,0 +1,212 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Pod lifecycle stages of a timeline entry
+const (
+	PodStageScheduled        = "scheduled"
+	PodStageSchedulingFailed = "scheduling_failed"
+	PodStagePulling          = "pulling"
+	PodStagePulled           = "pulled"
+	PodStageImagePullFailed  = "image_pull_failed"
+	PodStageCreated          = "created"
+	PodStageStarted          = "started"
+	PodStageRestarted        = "restarted"
+	PodStageOOMKilled        = "oomkilled"
+	PodStageBackOff          = "backoff"
+	PodStageUnhealthy        = "unhealthy"
+	PodStageKilling          = "killing"
+	PodStageEvicted          = "evicted"
+	PodStagePreempted        = "preempted"
+	PodStageDeleted          = "deleted"
+	PodStageOther            = "other"
+)
+
+// podEventStages maps kubelet and scheduler event reasons to lifecycle stages
+var podEventStages = map[string]string{
+	"Scheduled":        PodStageScheduled,
+	"FailedScheduling": PodStageSchedulingFailed,
+	"Pulling":          PodStagePulling,
+	"Pulled":           PodStagePulled,
+	"ErrImagePull":     PodStageImagePullFailed,
+	"ImagePullBackOff": PodStageImagePullFailed,
+	"Created":          PodStageCreated,
+	"Started":          PodStageStarted,
+	"BackOff":          PodStageBackOff,
+	"Unhealthy":        PodStageUnhealthy,
+	"Killing":          PodStageKilling,
+	"OOMKilling":       PodStageOOMKilled,
+	"Evicted":          PodStageEvicted,
+	"Preempted":        PodStagePreempted,
+	"Preempting":       PodStagePreempted,
+}
+
+// PodTimelineEntry is one step in a pod's lifecycle, from an event or from
+// the pod's status. ID is stable across collections so entries can be
+// recorded repeatedly without duplicates.
+type PodTimelineEntry struct {
+	ID        string    `json:"id"`
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	PodUID    string    `json:"podUID,omitempty"`
+	Container string    `json:"container,omitempty"`
+	Stage     string    `json:"stage"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message,omitempty"`
+	Count     int32     `json:"count,omitempty"`
+	Source    string    `json:"source"` // event or status
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CollectPodTimeline returns the timeline entries currently derivable for
+// the pods of a namespace (all when empty) from events and container
+// statuses, and the pods that exist now by namespace/name with their UIDs.
+// Events expire after about an hour, so callers record the entries to
+// reconstruct timelines later.
+func (m *MultiClusterClient) CollectPodTimeline(ctx context.Context, contextName, namespace string) ([]PodTimelineEntry, map[string]string, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "involvedObject.kind=Pod"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]PodTimelineEntry, 0, len(events.Items))
+	for _, e := range events.Items {
+		if e.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		stage, ok := podEventStages[e.Reason]
+		if !ok {
+			stage = PodStageOther
+		}
+		count := e.Count
+		if e.Series != nil && e.Series.Count > count {
+			count = e.Series.Count
+		}
+		entries = append(entries, PodTimelineEntry{
+			ID:        "event:" + contextName + "/" + string(e.UID),
+			Cluster:   contextName,
+			Namespace: e.InvolvedObject.Namespace,
+			Pod:       e.InvolvedObject.Name,
+			PodUID:    string(e.InvolvedObject.UID),
+			Container: fieldPathContainer(e.InvolvedObject.FieldPath),
+			Stage:     stage,
+			Reason:    e.Reason,
+			Message:   e.Message,
+			Count:     count,
+			Source:    "event",
+			Timestamp: eventTime(e),
+		})
+	}
+
+	current := make(map[string]string, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		current[pod.Namespace+"/"+pod.Name] = string(pod.UID)
+		entries = append(entries, podStatusTimeline(contextName, pod)...)
+	}
+	return entries, current, nil
+}
+
+// podStatusTimeline derives the entries events don't reliably carry from a
+// pod's status: the latest termination of each container (including
+// OOMKilled) and the deletion request
+func podStatusTimeline(contextName string, pod *corev1.Pod) []PodTimelineEntry {
+	var entries []PodTimelineEntry
+	base := PodTimelineEntry{Cluster: contextName, Namespace: pod.Namespace, Pod: pod.Name, PodUID: string(pod.UID), Source: "status"}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		term := cs.LastTerminationState.Terminated
+		if term == nil || term.FinishedAt.IsZero() {
+			continue
+		}
+		e := base
+		e.ID = fmt.Sprintf("status:%s/%s/%s/%s/%d", contextName, pod.UID, cs.Name, "terminated", term.FinishedAt.Unix())
+		e.Container = cs.Name
+		e.Stage = PodStageRestarted
+		if term.Reason == "OOMKilled" {
+			e.Stage = PodStageOOMKilled
+		}
+		e.Reason = term.Reason
+		e.Message = fmt.Sprintf("exit code %d, restart %d", term.ExitCode, cs.RestartCount)
+		e.Count = cs.RestartCount
+		e.Timestamp = term.FinishedAt.Time
+		entries = append(entries, e)
+	}
+	if pod.DeletionTimestamp != nil {
+		e := base
+		e.ID = fmt.Sprintf("status:%s/%s/deletion", contextName, pod.UID)
+		e.Stage = PodStageKilling
+		e.Reason = "DeletionRequested"
+		e.Timestamp = pod.DeletionTimestamp.Time
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// PodDeletedEntry records that a pod seen earlier no longer exists
+func PodDeletedEntry(contextName, namespace, pod, uid string, at time.Time) PodTimelineEntry {
+	return PodTimelineEntry{
+		ID:        fmt.Sprintf("deleted:%s/%s/%s/%s", contextName, namespace, pod, uid),
+		Cluster:   contextName,
+		Namespace: namespace,
+		Pod:       pod,
+		PodUID:    uid,
+		Stage:     PodStageDeleted,
+		Reason:    "Deleted",
+		Message:   "pod no longer exists",
+		Source:    "status",
+		Timestamp: at,
+	}
+}
+
+// MergePodTimeline combines recorded and live entries, keeping the latest
+// version of each, in chronological order
+func MergePodTimeline(entries []PodTimelineEntry) []PodTimelineEntry {
+	byID := make(map[string]PodTimelineEntry, len(entries))
+	for _, e := range entries {
+		if prev, ok := byID[e.ID]; ok && !e.Timestamp.After(prev.Timestamp) && e.Count <= prev.Count {
+			continue
+		}
+		byID[e.ID] = e
+	}
+	merged := make([]PodTimelineEntry, 0, len(byID))
+	for _, e := range byID {
+		merged = append(merged, e)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if !merged[i].Timestamp.Equal(merged[j].Timestamp) {
+			return merged[i].Timestamp.Before(merged[j].Timestamp)
+		}
+		return merged[i].ID < merged[j].ID
+	})
+	return merged
+}
+
+// fieldPathContainer extracts the container name from an event field path
+// like spec.containers{api}
+func fieldPathContainer(fieldPath string) string {
+	start := strings.IndexByte(fieldPath, '{')
+	end := strings.LastIndexByte(fieldPath, '}')
+	if start < 0 || end <= start {
+		return ""
+	}
+	return fieldPath[start+1 : end]
+}
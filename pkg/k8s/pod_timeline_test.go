@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollectPodTimeline(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	event := func(uid, reason, fieldPath string, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "api-1." + uid, Namespace: "shop", UID: types.UID("ev-" + uid)},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "api-1", UID: "pod-1", FieldPath: fieldPath},
+			Reason:         reason,
+			Count:          1,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop", UID: "pod-1"},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "api",
+				RestartCount: 2,
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					Reason: "OOMKilled", ExitCode: 137, FinishedAt: metav1.NewTime(start.Add(5 * time.Minute)),
+				}},
+			}}},
+		},
+		event("a", "Scheduled", "", start),
+		event("b", "Pulled", "spec.containers{api}", start.Add(time.Minute)),
+		event("c", "Started", "spec.containers{api}", start.Add(2*time.Minute)),
+	)
+
+	entries, current, err := m.CollectPodTimeline(context.Background(), "c1", "shop")
+	if err != nil {
+		t.Fatalf("CollectPodTimeline failed: %v", err)
+	}
+	if current["shop/api-1"] != "pod-1" {
+		t.Errorf("Expected current pods to include shop/api-1, got %v", current)
+	}
+
+	timeline := MergePodTimeline(entries)
+	stages := make([]string, len(timeline))
+	for i, e := range timeline {
+		stages[i] = e.Stage
+	}
+	want := []string{PodStageScheduled, PodStagePulled, PodStageStarted, PodStageOOMKilled}
+	if len(stages) != len(want) {
+		t.Fatalf("Expected stages %v, got %v", want, stages)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Fatalf("Expected stages %v, got %v", want, stages)
+		}
+	}
+	if timeline[1].Container != "api" {
+		t.Errorf("Expected container from field path, got %q", timeline[1].Container)
+	}
+	if oom := timeline[3]; oom.Source != "status" || oom.Count != 2 {
+		t.Errorf("Expected OOMKilled from status with restart count 2, got %+v", oom)
+	}
+}
+
+func TestMergePodTimeline(t *testing.T) {
+	at := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	merged := MergePodTimeline([]PodTimelineEntry{
+		{ID: "back-off", Stage: PodStageBackOff, Count: 3, Timestamp: at.Add(time.Minute)},
+		{ID: "back-off", Stage: PodStageBackOff, Count: 7, Timestamp: at.Add(4 * time.Minute)},
+		{ID: "scheduled", Stage: PodStageScheduled, Timestamp: at},
+		{ID: "back-off", Stage: PodStageBackOff, Count: 5, Timestamp: at.Add(2 * time.Minute)},
+	})
+	if len(merged) != 2 {
+		t.Fatalf("Expected duplicates to collapse into 2 entries, got %+v", merged)
+	}
+	if merged[0].ID != "scheduled" || merged[1].Count != 7 {
+		t.Errorf("Expected scheduled first and the latest back-off kept, got %+v", merged)
+	}
+}
@@ -0,0 +1,164 @@
+package k8s
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// preemptionWindow is how far back GetPreemptions looks
+const preemptionWindow = time.Hour
+
+// preemptedMessage matches the scheduler's Preempted event message across
+// versions: "Preempted by pod <uid> on node <node>", older
+// "Preempted by <namespace>/<name> on node <node>" and "Preempted by a pod on node <node>"
+var preemptedMessage = regexp.MustCompile(`^Preempted by (?:a pod|pod (\S+)|(\S+/\S+)) on node (\S+)`)
+
+// PriorityClass represents a Kubernetes PriorityClass and how many pods use it
+type PriorityClass struct {
+	Name             string `json:"name"`
+	Cluster          string `json:"cluster,omitempty"`
+	Value            int32  `json:"value"`
+	GlobalDefault    bool   `json:"globalDefault"`
+	PreemptionPolicy string `json:"preemptionPolicy"`
+	Description      string `json:"description,omitempty"`
+	Pods             int    `json:"pods"`
+	Age              string `json:"age,omitempty"`
+}
+
+// Preemption is a pod the scheduler evicted to make room for a higher
+// priority one. Preemptor fields are empty when the scheduler did not name
+// it or it no longer exists; victim priority is empty once the victim is gone.
+type Preemption struct {
+	Cluster                string `json:"cluster,omitempty"`
+	Namespace              string `json:"namespace"`
+	Victim                 string `json:"victim"`
+	VictimPriorityClass    string `json:"victimPriorityClass,omitempty"`
+	VictimPriority         *int32 `json:"victimPriority,omitempty"`
+	Node                   string `json:"node,omitempty"`
+	Preemptor              string `json:"preemptor,omitempty"` // namespace/name
+	PreemptorPriorityClass string `json:"preemptorPriorityClass,omitempty"`
+	PreemptorPriority      *int32 `json:"preemptorPriority,omitempty"`
+	Time                   string `json:"time"`
+	Message                string `json:"message"`
+}
+
+// GetPriorityClasses returns a cluster's PriorityClasses, highest value
+// first, with the number of pods running at each
+func (m *MultiClusterClient) GetPriorityClasses(ctx context.Context, contextName string) ([]PriorityClass, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := client.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podsPerClass := make(map[string]int)
+	for _, pod := range pods.Items {
+		if pod.Spec.PriorityClassName != "" && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			podsPerClass[pod.Spec.PriorityClassName]++
+		}
+	}
+
+	result := make([]PriorityClass, 0, len(classes.Items))
+	for _, pc := range classes.Items {
+		// PreemptLowerPriority is the API default
+		policy := string(corev1.PreemptLowerPriority)
+		if pc.PreemptionPolicy != nil {
+			policy = string(*pc.PreemptionPolicy)
+		}
+		result = append(result, PriorityClass{
+			Name:             pc.Name,
+			Cluster:          contextName,
+			Value:            pc.Value,
+			GlobalDefault:    pc.GlobalDefault,
+			PreemptionPolicy: policy,
+			Description:      pc.Description,
+			Pods:             podsPerClass[pc.Name],
+			Age:              formatAge(pc.CreationTimestamp.Time),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return result[i].Value > result[j].Value
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// GetPreemptions returns the pods preempted in the last hour, newest first,
+// with the pod that preempted each where the scheduler's event names it
+func (m *MultiClusterClient) GetPreemptions(ctx context.Context, contextName, namespace string) ([]Preemption, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "reason=Preempted"})
+	if err != nil {
+		return nil, err
+	}
+	// The preemptor may live in any namespace
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	byUID := make(map[types.UID]*corev1.Pod, len(pods.Items))
+	byName := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		byUID[pod.UID] = pod
+		byName[pod.Namespace+"/"+pod.Name] = pod
+	}
+
+	cutoff := time.Now().Add(-preemptionWindow)
+	result := make([]Preemption, 0)
+	for _, event := range events.Items {
+		at := eventTime(event)
+		if event.Reason != "Preempted" || event.InvolvedObject.Kind != "Pod" || at.Before(cutoff) {
+			continue
+		}
+		p := Preemption{
+			Cluster:   contextName,
+			Namespace: event.InvolvedObject.Namespace,
+			Victim:    event.InvolvedObject.Name,
+			Time:      at.UTC().Format(time.RFC3339),
+			Message:   event.Message,
+		}
+		if victim := byName[p.Namespace+"/"+p.Victim]; victim != nil && victim.UID == event.InvolvedObject.UID {
+			p.VictimPriorityClass, p.VictimPriority = victim.Spec.PriorityClassName, victim.Spec.Priority
+		}
+		if match := preemptedMessage.FindStringSubmatch(event.Message); match != nil {
+			p.Node = match[3]
+			var preemptor *corev1.Pod
+			switch {
+			case match[1] != "":
+				preemptor = byUID[types.UID(match[1])]
+			case match[2] != "":
+				p.Preemptor = match[2]
+				preemptor = byName[match[2]]
+			}
+			if preemptor != nil {
+				p.Preemptor = preemptor.Namespace + "/" + preemptor.Name
+				p.PreemptorPriorityClass, p.PreemptorPriority = preemptor.Spec.PriorityClassName, preemptor.Spec.Priority
+			}
+		}
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time > result[j].Time
+	})
+	return result, nil
+}
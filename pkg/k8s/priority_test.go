@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetPriorityClassesAndPreemptions(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	high, low := int32(100000), int32(1000)
+	never := corev1.PreemptNever
+	pod := func(namespace, name, class string, priority *int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID("uid-" + name)},
+			Spec:       corev1.PodSpec{PriorityClassName: class, Priority: priority},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	event := func(name, victim, message string, age time.Duration) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "train"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "train", Name: victim, UID: types.UID("uid-" + victim)},
+			Reason:         "Preempted",
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(time.Now().Add(-age)),
+		}
+	}
+
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "inference"}, Value: high},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "batch"}, Value: low, GlobalDefault: true, PreemptionPolicy: &never},
+		pod("serve", "vllm-0", "inference", &high),
+		pod("serve", "vllm-1", "inference", &high),
+		pod("train", "worker-2", "batch", &low),
+		event("e1", "worker-1", "Preempted by pod uid-vllm-0 on node gpu-1", 10*time.Minute),
+		event("e2", "worker-2", "Preempted by serve/vllm-1 on node gpu-2", 5*time.Minute),
+		event("e3", "worker-3", "Preempted by a pod on node gpu-3", 20*time.Minute),
+		event("e4", "worker-4", "Preempted by pod uid-vllm-0 on node gpu-1", 2*time.Hour),
+	)
+
+	classes, err := m.GetPriorityClasses(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetPriorityClasses failed: %v", err)
+	}
+	if len(classes) != 2 || classes[0].Name != "inference" || classes[0].Pods != 2 || classes[0].PreemptionPolicy != "PreemptLowerPriority" {
+		t.Errorf("Expected inference first with 2 pods and default policy, got %+v", classes)
+	}
+	if classes[1].PreemptionPolicy != "Never" || !classes[1].GlobalDefault || classes[1].Pods != 1 {
+		t.Errorf("Unexpected batch class: %+v", classes[1])
+	}
+
+	preemptions, err := m.GetPreemptions(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("GetPreemptions failed: %v", err)
+	}
+	if len(preemptions) != 3 {
+		t.Fatalf("Expected the 3 preemptions within the last hour, got %+v", preemptions)
+	}
+	byVictim := make(map[string]Preemption)
+	for _, p := range preemptions {
+		byVictim[p.Victim] = p
+	}
+	if preemptions[0].Victim != "worker-2" {
+		t.Errorf("Expected newest preemption first, got %+v", preemptions)
+	}
+	if p := byVictim["worker-1"]; p.Preemptor != "serve/vllm-0" || p.Node != "gpu-1" || p.PreemptorPriorityClass != "inference" || p.VictimPriority != nil {
+		t.Errorf("Expected preemptor resolved by UID, got %+v", p)
+	}
+	if p := byVictim["worker-2"]; p.Preemptor != "serve/vllm-1" || p.VictimPriorityClass != "batch" || *p.PreemptorPriority != high {
+		t.Errorf("Expected preemptor resolved by name and victim priority, got %+v", p)
+	}
+	if p := byVictim["worker-3"]; p.Preemptor != "" || p.Node != "gpu-3" {
+		t.Errorf("Expected anonymous preemptor, got %+v", p)
+	}
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// ProbeFocusTTL is how long a focus hint lasts; the UI re-sends it while open
+	ProbeFocusTTL = 2 * time.Minute
+	// unfocusedHealthTTLFactor stretches the health cache of clusters outside the
+	// focus hint, so refresh loops poll rarely-viewed clusters less often
+	unfocusedHealthTTLFactor = 5
+	// maxFocusedClusters bounds the size of a focus hint
+	maxFocusedClusters = 500
+)
+
+// probeFocus is the set of clusters the UI currently shows
+type probeFocus struct {
+	clusters map[string]bool
+	expires  time.Time
+}
+
+func (f probeFocus) active() bool {
+	return len(f.clusters) > 0 && time.Now().Before(f.expires)
+}
+
+// SetFocusedClusters records which clusters the UI currently shows. Until the
+// hint expires, warmup and health refreshes probe them first and keep their
+// health fresh, while the other clusters are re-probed less often. An empty
+// list clears the hint.
+func (m *MultiClusterClient) SetFocusedClusters(names []string) {
+	if len(names) > maxFocusedClusters {
+		names = names[:maxFocusedClusters]
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(names) == 0 {
+		m.probeFocus = probeFocus{}
+		return
+	}
+	clusters := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name != "" {
+			clusters[name] = true
+		}
+	}
+	m.probeFocus = probeFocus{clusters: clusters, expires: time.Now().Add(ProbeFocusTTL)}
+}
+
+// FocusedClusters returns the clusters of the current focus hint, sorted
+func (m *MultiClusterClient) FocusedClusters() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.probeFocus.active() {
+		return nil
+	}
+	names := make([]string, 0, len(m.probeFocus.clusters))
+	for name := range m.probeFocus.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// healthTTLLocked returns how long a cluster's cached health is served before
+// it is probed again
+func (m *MultiClusterClient) healthTTLLocked(contextName string) time.Duration {
+	if m.probeFocus.active() && !m.probeFocus.clusters[contextName] {
+		return m.cacheTTL * unfocusedHealthTTLFactor
+	}
+	return m.cacheTTL
+}
+
+// prioritizeFocused moves focused clusters to the front, keeping the order otherwise
+func (m *MultiClusterClient) prioritizeFocused(clusters []ClusterInfo) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.prioritizeFocusedLocked(clusters)
+}
+
+func (m *MultiClusterClient) prioritizeFocusedLocked(clusters []ClusterInfo) {
+	focus := m.probeFocus
+	if !focus.active() {
+		return
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return focusedCluster(focus, clusters[i]) && !focusedCluster(focus, clusters[j])
+	})
+}
+
+func focusedCluster(focus probeFocus, cl ClusterInfo) bool {
+	return focus.clusters[cl.Name] || focus.clusters[cl.Context]
+}
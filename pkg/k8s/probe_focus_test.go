@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProbeFocus(t *testing.T) {
+	m := &MultiClusterClient{cacheTTL: time.Minute}
+	clusters := []ClusterInfo{{Name: "a", Context: "a"}, {Name: "b", Context: "b"}, {Name: "c", Context: "ctx-c"}}
+
+	m.prioritizeFocused(clusters)
+	if clusters[0].Name != "a" || m.healthTTLLocked("b") != time.Minute {
+		t.Error("Expected no reordering or TTL change without a focus hint")
+	}
+
+	m.SetFocusedClusters([]string{"ctx-c", "b"})
+	m.prioritizeFocused(clusters)
+	if got := []string{clusters[0].Name, clusters[1].Name, clusters[2].Name}; got[0] != "b" || got[1] != "c" || got[2] != "a" {
+		t.Errorf("Expected focused clusters first in their original order, got %v", got)
+	}
+	if m.healthTTLLocked("b") != time.Minute || m.healthTTLLocked("a") != unfocusedHealthTTLFactor*time.Minute {
+		t.Error("Expected unfocused clusters to be probed less often")
+	}
+	if focused := m.FocusedClusters(); len(focused) != 2 || focused[0] != "b" {
+		t.Errorf("Expected the focus hint to be reported, got %v", focused)
+	}
+
+	m.probeFocus.expires = time.Now().Add(-time.Second)
+	if m.FocusedClusters() != nil || m.healthTTLLocked("a") != time.Minute {
+		t.Error("Expected an expired hint to be ignored")
+	}
+	m.SetFocusedClusters(nil)
+	if m.probeFocus.active() {
+		t.Error("Expected an empty list to clear the hint")
+	}
+}
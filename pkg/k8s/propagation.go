@@ -0,0 +1,230 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kubestellar/console/pkg/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// workStatusGVR is the KubeStellar WorkStatus, which the status add-on
+	// creates on the ITS in each WEC's namespace
+	workStatusGVR = schema.GroupVersionResource{
+		Group:    "control.kubestellar.io",
+		Version:  "v1alpha1",
+		Resource: "workstatuses",
+	}
+	// appliedManifestWorkGVR is the OCM record of what a WEC's work agent applied
+	appliedManifestWorkGVR = schema.GroupVersionResource{
+		Group:    "work.open-cluster-management.io",
+		Version:  "v1",
+		Resource: "appliedmanifestworks",
+	}
+)
+
+// ClusterPropagation is where a workload stands on one execution cluster
+type ClusterPropagation struct {
+	Cluster       string `json:"cluster"`
+	Applied       bool   `json:"applied"`
+	Available     bool   `json:"available"`
+	Degraded      bool   `json:"degraded"`
+	Replicas      int64  `json:"replicas,omitempty"`
+	ReadyReplicas int64  `json:"readyReplicas,omitempty"`
+	Message       string `json:"message,omitempty"`
+	// Sources lists the objects the state was read from: workstatus, appliedmanifestwork
+	Sources []string `json:"sources"`
+}
+
+// WorkloadPropagation is the rollout of one WDS workload across execution clusters
+type WorkloadPropagation struct {
+	Kind      string               `json:"kind"`
+	Name      string               `json:"name"`
+	Namespace string               `json:"namespace"`
+	Clusters  []ClusterPropagation `json:"clusters"`
+	Applied   int                  `json:"applied"`
+	Available int                  `json:"available"`
+	Degraded  int                  `json:"degraded"`
+	Warnings  []string             `json:"warnings,omitempty"`
+}
+
+// GetWorkloadPropagation correlates the WorkStatuses on the ITS with the
+// AppliedManifestWorks on every other cluster to report, per cluster, whether
+// a workload was applied and whether it is available or degraded there.
+// WorkStatuses are keyed by ManagedCluster name, AppliedManifestWorks by
+// context; they merge when the two names agree. wds is skipped as a WEC.
+func (m *MultiClusterClient) GetWorkloadPropagation(ctx context.Context, its, wds, kind, namespace, name string) (*WorkloadPropagation, error) {
+	gr, ok := workloadResources[v1alpha1.WorkloadType(kind)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+	result := &WorkloadPropagation{Kind: kind, Name: name, Namespace: namespace, Clusters: []ClusterPropagation{}}
+	byCluster := make(map[string]*ClusterPropagation)
+	entry := func(cluster string) *ClusterPropagation {
+		if p, ok := byCluster[cluster]; ok {
+			return p
+		}
+		p := &ClusterPropagation{Cluster: cluster}
+		byCluster[cluster] = p
+		return p
+	}
+
+	if its != "" {
+		statuses, err := m.listWorkStatuses(ctx, its, gr[0], gr[1], namespace, name)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("could not list WorkStatuses on %s: %v", its, err))
+		}
+		for _, ws := range statuses {
+			p := entry(ws.GetNamespace())
+			p.Applied = true
+			p.Sources = append(p.Sources, "workstatus")
+			status, _, _ := unstructured.NestedMap(ws.Object, "status")
+			applyWorkloadStatus(p, status)
+		}
+	}
+
+	clusters, err := m.DeduplicatedClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, c := range clusters {
+		if c.Name == its || c.Name == wds {
+			continue
+		}
+		wg.Add(1)
+		go func(cluster string) {
+			defer wg.Done()
+			applied, err := m.hasAppliedResource(ctx, cluster, gr[0], gr[1], namespace, name)
+			if err != nil || !applied {
+				return
+			}
+			// The live object is at least as fresh as a WorkStatus copy
+			dynamicClient, err := m.GetDynamicClient(cluster)
+			if err != nil {
+				return
+			}
+			gvr := schema.GroupVersionResource{Group: gr[0], Version: "v1", Resource: gr[1]}
+			obj, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			p := entry(cluster)
+			p.Applied = true
+			p.Sources = append(p.Sources, "appliedmanifestwork")
+			if err != nil {
+				p.Message = fmt.Sprintf("applied but not readable: %v", err)
+				return
+			}
+			p.Message = ""
+			status, _, _ := unstructured.NestedMap(obj.Object, "status")
+			applyWorkloadStatus(p, status)
+		}(c.Name)
+	}
+	wg.Wait()
+
+	for _, p := range byCluster {
+		result.Clusters = append(result.Clusters, *p)
+		if p.Applied {
+			result.Applied++
+		}
+		if p.Available {
+			result.Available++
+		}
+		if p.Degraded {
+			result.Degraded++
+		}
+	}
+	sort.Slice(result.Clusters, func(i, j int) bool { return result.Clusters[i].Cluster < result.Clusters[j].Cluster })
+	return result, nil
+}
+
+// listWorkStatuses returns the WorkStatuses on the ITS whose source is the given object
+func (m *MultiClusterClient) listWorkStatuses(ctx context.Context, its, group, resource, namespace, name string) ([]unstructured.Unstructured, error) {
+	dynamicClient, err := m.GetDynamicClient(its)
+	if err != nil {
+		return nil, err
+	}
+	list, err := dynamicClient.Resource(workStatusGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var matched []unstructured.Unstructured
+	for _, item := range list.Items {
+		ref, _, _ := unstructured.NestedStringMap(item.Object, "spec", "sourceRef")
+		if ref["group"] == group && ref["resource"] == resource && ref["namespace"] == namespace && ref["name"] == name {
+			matched = append(matched, item)
+		}
+	}
+	return matched, nil
+}
+
+// hasAppliedResource reports whether a cluster's work agent applied the given object
+func (m *MultiClusterClient) hasAppliedResource(ctx context.Context, cluster, group, resource, namespace, name string) (bool, error) {
+	dynamicClient, err := m.GetDynamicClient(cluster)
+	if err != nil {
+		return false, err
+	}
+	list, err := dynamicClient.Resource(appliedManifestWorkGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, item := range list.Items {
+		resources, _, _ := unstructured.NestedSlice(item.Object, "status", "appliedResources")
+		for _, r := range resources {
+			res, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if res["group"] == group && res["resource"] == resource && res["namespace"] == namespace && res["name"] == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// applyWorkloadStatus derives availability from a Deployment, StatefulSet or
+// DaemonSet status reported back through a WorkStatus
+func applyWorkloadStatus(p *ClusterPropagation, status map[string]interface{}) {
+	if status == nil {
+		p.Message = "no status reported yet"
+		return
+	}
+	replicas, _, _ := unstructured.NestedInt64(status, "replicas")
+	ready, _, _ := unstructured.NestedInt64(status, "readyReplicas")
+	if desired, found, _ := unstructured.NestedInt64(status, "desiredNumberScheduled"); found {
+		replicas = desired
+		ready, _, _ = unstructured.NestedInt64(status, "numberReady")
+	}
+	p.Replicas, p.ReadyReplicas = replicas, ready
+	p.Available = ready >= replicas
+	p.Degraded = ready < replicas
+
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		failing := (cond["type"] == "Available" && cond["status"] == "False") ||
+			(cond["type"] == "ReplicaFailure" && cond["status"] == "True") ||
+			(cond["type"] == "Progressing" && cond["status"] == "False")
+		if !failing {
+			continue
+		}
+		p.Degraded = true
+		if cond["type"] == "Available" {
+			p.Available = false
+		}
+		if msg, ok := cond["message"].(string); ok && p.Message == "" {
+			p.Message = msg
+		}
+	}
+}
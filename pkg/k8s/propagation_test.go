@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestGetWorkloadPropagation(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.rawConfig = &api.Config{
+		Clusters: map[string]*api.Cluster{
+			"its1":   {Server: "https://its1.example.com:6443"},
+			"edge-1": {Server: "https://edge-1.example.com:6443"},
+		},
+		Contexts: map[string]*api.Context{
+			"its1":   {Cluster: "its1"},
+			"edge-1": {Cluster: "edge-1"},
+		},
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		workStatusGVR:          "WorkStatusList",
+		appliedManifestWorkGVR: "AppliedManifestWorkList",
+	}
+
+	workStatus := func(cluster string, ready int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "control.kubestellar.io/v1alpha1",
+			"kind":       "WorkStatus",
+			"metadata":   map[string]interface{}{"name": "appsv1-deployment-default-web", "namespace": cluster},
+			"spec": map[string]interface{}{"sourceRef": map[string]interface{}{
+				"group": "apps", "version": "v1", "resource": "deployments", "kind": "Deployment",
+				"namespace": "default", "name": "web",
+			}},
+			"status": map[string]interface{}{"replicas": int64(2), "readyReplicas": ready},
+		}}
+	}
+	m.dynamicClients["its1"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		workStatus("edge-1", 2), workStatus("edge-2", 1))
+
+	applied := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "work.open-cluster-management.io/v1",
+		"kind":       "AppliedManifestWork",
+		"metadata":   map[string]interface{}{"name": "hub-edge-1-work"},
+		"status": map[string]interface{}{"appliedResources": []interface{}{map[string]interface{}{
+			"group": "apps", "version": "v1", "resource": "deployments", "namespace": "default", "name": "web",
+		}}},
+	}}
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"status":     map[string]interface{}{"replicas": int64(2), "readyReplicas": int64(2)},
+	}}
+	m.dynamicClients["edge-1"] = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, applied, deployment)
+
+	result, err := m.GetWorkloadPropagation(context.Background(), "its1", "", "Deployment", "default", "web")
+	if err != nil {
+		t.Fatalf("GetWorkloadPropagation failed: %v", err)
+	}
+	if len(result.Clusters) != 2 || result.Applied != 2 || result.Available != 1 || result.Degraded != 1 {
+		t.Fatalf("Unexpected propagation: %+v", result)
+	}
+	edge1, edge2 := result.Clusters[0], result.Clusters[1]
+	if edge1.Cluster != "edge-1" || !edge1.Available || len(edge1.Sources) != 2 {
+		t.Errorf("Unexpected edge-1: %+v", edge1)
+	}
+	if edge2.Cluster != "edge-2" || !edge2.Degraded || edge2.ReadyReplicas != 1 {
+		t.Errorf("Unexpected edge-2: %+v", edge2)
+	}
+
+	if _, err := m.GetWorkloadPropagation(context.Background(), "its1", "", "CronJob", "default", "web"); err == nil {
+		t.Error("Expected an error for an unsupported kind")
+	}
+}
@@ -18,6 +18,11 @@ import (
 
 // ListServiceAccounts returns all service accounts in a cluster
 func (m *MultiClusterClient) ListServiceAccounts(ctx context.Context, contextName, namespace string) ([]models.K8sServiceAccount, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]models.K8sServiceAccount, error) {
+			return m.ListServiceAccounts(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -96,6 +101,11 @@ func (m *MultiClusterClient) buildServiceAccountRolesMap(ctx context.Context, cl
 
 // ListRoles returns all Roles in a namespace
 func (m *MultiClusterClient) ListRoles(ctx context.Context, contextName, namespace string) ([]models.K8sRole, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]models.K8sRole, error) {
+			return m.ListRoles(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -168,6 +178,11 @@ func isSystemRole(name string) bool {
 
 // ListRoleBindings returns all RoleBindings in a namespace
 func (m *MultiClusterClient) ListRoleBindings(ctx context.Context, contextName, namespace string) ([]models.K8sRoleBinding, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]models.K8sRoleBinding, error) {
+			return m.ListRoleBindings(ctx, contextName, ns)
+		})
+	}
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -622,6 +637,19 @@ func (m *MultiClusterClient) GetPermissionsSummary(ctx context.Context, contextN
 
 // listAllNamespaces returns all namespace names in a cluster
 func (m *MultiClusterClient) listAllNamespaces(ctx context.Context, contextName string) ([]string, error) {
+	// Names are all we need, so list metadata only when possible
+	if _, err := m.GetMetadataClient(contextName); err == nil {
+		items, err := m.ListObjectMetadata(ctx, contextName, namespacesGVR, "")
+		if err != nil {
+			return nil, err
+		}
+		namespaces := make([]string, 0, len(items))
+		for _, ns := range items {
+			namespaces = append(namespaces, ns.Name)
+		}
+		return namespaces, nil
+	}
+
 	client, err := m.GetClient(contextName)
 	if err != nil {
 		return nil, err
@@ -695,6 +723,21 @@ func (m *MultiClusterClient) ListNamespacesWithDetails(ctx context.Context, cont
 		return nil, err
 	}
 
+	// Scoped identities usually can't list namespaces; report the permitted ones
+	if permitted, scoped := m.PermittedNamespaces(ctx, contextName); scoped {
+		namespaces := make([]models.NamespaceDetails, 0, len(permitted))
+		for _, name := range permitted {
+			details := models.NamespaceDetails{Name: name, Cluster: contextName}
+			if ns, err := client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{}); err == nil {
+				details.Status = string(ns.Status.Phase)
+				details.Labels = ns.Labels
+				details.CreatedAt = ns.CreationTimestamp.Format(time.RFC3339)
+			}
+			namespaces = append(namespaces, details)
+		}
+		return namespaces, nil
+	}
+
 	nsList, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,249 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RBAC risk rules
+const (
+	RBACRiskClusterAdminServiceAccount = "cluster-admin-service-account"
+	RBACRiskWildcardVerbs              = "wildcard-verbs"
+	RBACRiskWildcardResources          = "wildcard-resources"
+	RBACRiskSecretsRead                = "secrets-read"
+	RBACRiskEscalation                 = "privilege-escalation"
+)
+
+// severityRank orders severities from least to most severe
+var severityRank = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// escalationVerbs let a subject grant itself, or act with, permissions it does not hold
+var escalationVerbs = []string{"escalate", "bind", "impersonate"}
+
+// rbacDefaultsLabel marks the roles Kubernetes bootstraps, which are unused until bound
+const rbacDefaultsLabel = "kubernetes.io/bootstrapping"
+
+// RBACFinding is one risky permission a subject holds through a binding
+type RBACFinding struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"` // critical, high, medium
+	RoleKind  string `json:"roleKind"` // Role, ClusterRole
+	Role      string `json:"role"`
+	Binding   string `json:"binding"`
+	Namespace string `json:"namespace,omitempty"` // empty when granted cluster-wide
+	Details   string `json:"details"`
+}
+
+// RBACSubjectReport lists the risky permissions of one user, group or service account
+type RBACSubjectReport struct {
+	Kind      string        `json:"kind"` // User, Group, ServiceAccount
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Severity  string        `json:"severity"` // highest severity among the findings
+	Findings  []RBACFinding `json:"findings"`
+}
+
+// RBACRoleRef identifies a Role or ClusterRole
+type RBACRoleRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RBACRiskReport is the result of analyzing the RBAC configuration of a cluster
+type RBACRiskReport struct {
+	Cluster     string              `json:"cluster"`
+	Subjects    []RBACSubjectReport `json:"subjects"`
+	UnusedRoles []RBACRoleRef       `json:"unusedRoles"`
+}
+
+// AnalyzeRBACRisks flags cluster-admin service accounts, wildcard verbs and
+// resources, read access to secrets and escalate/bind/impersonate rights,
+// grouped per subject, and lists the roles no binding refers to. System
+// bindings, subjects and roles are skipped unless includeSystem is set.
+func (m *MultiClusterClient) AnalyzeRBACRisks(ctx context.Context, contextName string, includeSystem bool) (*RBACRiskReport, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	rbac := client.RbacV1()
+
+	clusterRoles, err := rbac.ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	roles, err := rbac.Roles("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	clusterBindings, err := rbac.ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := rbac.RoleBindings("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[RBACRoleRef][]rbacv1.PolicyRule)
+	for _, r := range clusterRoles.Items {
+		rules[RBACRoleRef{Kind: "ClusterRole", Name: r.Name}] = r.Rules
+	}
+	for _, r := range roles.Items {
+		rules[RBACRoleRef{Kind: "Role", Name: r.Name, Namespace: r.Namespace}] = r.Rules
+	}
+
+	used := make(map[RBACRoleRef]bool)
+	subjects := make(map[string]*RBACSubjectReport)
+	analyze := func(binding, namespace string, roleRef rbacv1.RoleRef, bindingSubjects []rbacv1.Subject) {
+		ref := RBACRoleRef{Kind: roleRef.Kind, Name: roleRef.Name}
+		if ref.Kind == "Role" {
+			ref.Namespace = namespace
+		}
+		used[ref] = true
+		if !includeSystem && (isSystemRole(binding) || isSystemRole(roleRef.Name)) {
+			return
+		}
+		risks := policyRuleRisks(rules[ref])
+		for _, s := range bindingSubjects {
+			if !includeSystem && isSystemRole(s.Name) {
+				continue
+			}
+			findings := risks
+			if s.Kind == rbacv1.ServiceAccountKind && ref.Kind == "ClusterRole" && ref.Name == "cluster-admin" && namespace == "" {
+				findings = append([]RBACFinding{{
+					Rule:     RBACRiskClusterAdminServiceAccount,
+					Severity: "critical",
+					Details:  "Service account bound to cluster-admin cluster-wide",
+				}}, findings...)
+			}
+			if len(findings) == 0 {
+				continue
+			}
+			key := s.Kind + "/" + s.Namespace + "/" + s.Name
+			report, ok := subjects[key]
+			if !ok {
+				report = &RBACSubjectReport{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace}
+				subjects[key] = report
+			}
+			for _, f := range findings {
+				f.RoleKind, f.Role, f.Binding, f.Namespace = ref.Kind, ref.Name, binding, namespace
+				report.Findings = append(report.Findings, f)
+				if severityRank[f.Severity] > severityRank[report.Severity] {
+					report.Severity = f.Severity
+				}
+			}
+		}
+	}
+	for _, b := range clusterBindings.Items {
+		analyze(b.Name, "", b.RoleRef, b.Subjects)
+	}
+	for _, b := range bindings.Items {
+		analyze(b.Name, b.Namespace, b.RoleRef, b.Subjects)
+	}
+
+	report := &RBACRiskReport{
+		Cluster:     contextName,
+		Subjects:    make([]RBACSubjectReport, 0, len(subjects)),
+		UnusedRoles: make([]RBACRoleRef, 0),
+	}
+	for _, s := range subjects {
+		report.Subjects = append(report.Subjects, *s)
+	}
+	sort.Slice(report.Subjects, func(i, j int) bool {
+		a, b := report.Subjects[i], report.Subjects[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		if len(a.Findings) != len(b.Findings) {
+			return len(a.Findings) > len(b.Findings)
+		}
+		return a.Kind+a.Namespace+a.Name < b.Kind+b.Namespace+b.Name
+	})
+
+	for _, r := range clusterRoles.Items {
+		ref := RBACRoleRef{Kind: "ClusterRole", Name: r.Name}
+		// Aggregated and bootstrapped roles are in use without a binding of their own
+		if used[ref] || r.AggregationRule != nil || hasAggregationLabel(r.Labels) || r.Labels[rbacDefaultsLabel] != "" {
+			continue
+		}
+		if includeSystem || !isSystemRole(r.Name) {
+			report.UnusedRoles = append(report.UnusedRoles, ref)
+		}
+	}
+	for _, r := range roles.Items {
+		ref := RBACRoleRef{Kind: "Role", Name: r.Name, Namespace: r.Namespace}
+		if used[ref] || r.Labels[rbacDefaultsLabel] != "" {
+			continue
+		}
+		if includeSystem || !isSystemRole(r.Name) {
+			report.UnusedRoles = append(report.UnusedRoles, ref)
+		}
+	}
+	sort.Slice(report.UnusedRoles, func(i, j int) bool {
+		a, b := report.UnusedRoles[i], report.UnusedRoles[j]
+		return a.Kind+a.Namespace+a.Name < b.Kind+b.Namespace+b.Name
+	})
+	return report, nil
+}
+
+// hasAggregationLabel reports whether a ClusterRole is aggregated into another one
+func hasAggregationLabel(labels map[string]string) bool {
+	for k := range labels {
+		if strings.HasPrefix(k, "rbac.authorization.k8s.io/aggregate-to-") {
+			return true
+		}
+	}
+	return false
+}
+
+// policyRuleRisks returns the risky permissions granted by a set of rules
+func policyRuleRisks(rules []rbacv1.PolicyRule) []RBACFinding {
+	var findings []RBACFinding
+	seen := make(map[string]bool)
+	add := func(rule, severity, details string) {
+		if seen[rule] {
+			return
+		}
+		seen[rule] = true
+		findings = append(findings, RBACFinding{Rule: rule, Severity: severity, Details: details})
+	}
+
+	for _, r := range rules {
+		if len(r.NonResourceURLs) > 0 && len(r.Resources) == 0 {
+			continue
+		}
+		wildcardVerbs := slices.Contains(r.Verbs, "*")
+		if wildcardVerbs {
+			add(RBACRiskWildcardVerbs, "high", fmt.Sprintf("All verbs on %s", strings.Join(r.Resources, ", ")))
+		}
+		if slices.Contains(r.Resources, "*") {
+			add(RBACRiskWildcardResources, "high", fmt.Sprintf("%s on all resources in API groups %s", strings.Join(r.Verbs, ", "), strings.Join(r.APIGroups, ", ")))
+		}
+		coreGroup := containsOrWildcard(r.APIGroups, "")
+		if coreGroup && containsOrWildcard(r.Resources, "secrets") {
+			for _, verb := range []string{"get", "list", "watch"} {
+				if containsOrWildcard(r.Verbs, verb) {
+					add(RBACRiskSecretsRead, "high", "Can read secrets")
+					break
+				}
+			}
+		}
+		if wildcardVerbs {
+			add(RBACRiskEscalation, "high", "Holds escalate, bind and impersonate through a wildcard verb")
+		}
+		for _, verb := range escalationVerbs {
+			if slices.Contains(r.Verbs, verb) {
+				add(RBACRiskEscalation, "high", fmt.Sprintf("Holds the %s verb", verb))
+				break
+			}
+		}
+	}
+	return findings
+}
@@ -135,3 +135,83 @@ func TestRBAC_CheckClusterAdminAccess(t *testing.T) {
 		t.Error("Expected cluster admin access")
 	}
 }
+
+func TestRBAC_AnalyzeRBACRisks(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	m.rawConfig = &api.Config{
+		Contexts: map[string]*api.Context{"c1": {Cluster: "cl1"}},
+	}
+
+	m.clients["c1"] = fake.NewSimpleClientset(
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-reader"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}}},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "view", Labels: map[string]string{"kubernetes.io/bootstrapping": "rbac-defaults"}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "impersonator", Namespace: "team-a"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"serviceaccounts"}, Verbs: []string{"impersonate"}}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci-admin"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "ci", Namespace: "ci"}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "read-secrets", Namespace: "team-a"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "secret-reader"},
+			Subjects:   []rbacv1.Subject{{Kind: "User", Name: "alice"}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "impersonate", Namespace: "team-a"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "impersonator"},
+			Subjects:   []rbacv1.Subject{{Kind: "User", Name: "alice"}},
+		},
+	)
+
+	report, err := m.AnalyzeRBACRisks(context.Background(), "c1", false)
+	if err != nil {
+		t.Fatalf("AnalyzeRBACRisks failed: %v", err)
+	}
+	if len(report.Subjects) != 2 {
+		t.Fatalf("Expected 2 risky subjects, got %+v", report.Subjects)
+	}
+
+	ci := report.Subjects[0]
+	if ci.Name != "ci" || ci.Severity != "critical" || ci.Findings[0].Rule != RBACRiskClusterAdminServiceAccount {
+		t.Errorf("Expected the cluster-admin service account first, got %+v", ci)
+	}
+	rules := make(map[string]bool)
+	for _, f := range ci.Findings {
+		rules[f.Rule] = true
+	}
+	for _, rule := range []string{RBACRiskWildcardVerbs, RBACRiskWildcardResources, RBACRiskSecretsRead, RBACRiskEscalation} {
+		if !rules[rule] {
+			t.Errorf("Expected %s finding for cluster-admin, got %+v", rule, ci.Findings)
+		}
+	}
+
+	alice := report.Subjects[1]
+	if alice.Name != "alice" || alice.Severity != "high" || len(alice.Findings) != 2 {
+		t.Errorf("Expected secrets and impersonation findings for alice, got %+v", alice)
+	}
+	for _, f := range alice.Findings {
+		if f.Namespace != "team-a" {
+			t.Errorf("Expected findings scoped to team-a, got %+v", f)
+		}
+	}
+
+	if len(report.UnusedRoles) != 1 || report.UnusedRoles[0].Name != "orphan" {
+		t.Errorf("Expected only the orphan role to be unused, got %+v", report.UnusedRoles)
+	}
+}
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// restartedAtAnnotation is the pod template annotation `kubectl rollout restart` sets
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RestartDeployment triggers a rolling restart of a Deployment the same way
+// `kubectl rollout restart` does, by stamping its pod template
+func (m *MultiClusterClient) RestartDeployment(ctx context.Context, contextName, namespace, name string) error {
+	return m.RestartWorkload(ctx, contextName, RestartTarget{Namespace: namespace, Kind: "Deployment", Name: name})
+}
+
+// RestartWorkload triggers a rolling restart of a Deployment, StatefulSet or
+// DaemonSet the same way `kubectl rollout restart` does
+func (m *MultiClusterClient) RestartWorkload(ctx context.Context, contextName string, target RestartTarget) error {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return err
+	}
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339)))
+	switch target.Kind {
+	case "Deployment":
+		_, err = client.AppsV1().Deployments(target.Namespace).Patch(ctx, target.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "StatefulSet":
+		_, err = client.AppsV1().StatefulSets(target.Namespace).Patch(ctx, target.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case "DaemonSet":
+		_, err = client.AppsV1().DaemonSets(target.Namespace).Patch(ctx, target.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("%w: cannot restart kind %q", ErrInvalidRestart, target.Kind)
+	}
+	return err
+}
+
+// CordonNode marks a node unschedulable; pods already running on it are left alone
+func (m *MultiClusterClient) CordonNode(ctx context.Context, contextName, nodeName string) error {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return err
+	}
+	_, err = client.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, []byte(`{"spec":{"unschedulable":true}}`), metav1.PatchOptions{})
+	return err
+}
+
+// PodDeployment returns the Deployment owning a pod through its ReplicaSet,
+// or "" when the pod is not managed by one
+func (m *MultiClusterClient) PodDeployment(ctx context.Context, contextName, namespace, podName string) (string, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return "", err
+	}
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	rsOwner := metav1.GetControllerOf(pod)
+	if rsOwner == nil || rsOwner.Kind != "ReplicaSet" {
+		return "", nil
+	}
+	rs, err := client.AppsV1().ReplicaSets(namespace).Get(ctx, rsOwner.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if owner := metav1.GetControllerOf(rs); owner != nil && owner.Kind == "Deployment" {
+		return owner.Name, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRemediationActions(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+	controller := true
+	owned := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "api-5d8f", Namespace: "shop", OwnerReferences: owned("Deployment", "api")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-5d8f-x2", Namespace: "shop", OwnerReferences: owned("ReplicaSet", "api-5d8f")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "shop"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-1"}},
+	)
+	ctx := context.Background()
+
+	if name, err := m.PodDeployment(ctx, "c1", "shop", "api-5d8f-x2"); err != nil || name != "api" {
+		t.Errorf("Expected pod to resolve to deployment api, got %q (%v)", name, err)
+	}
+	if name, err := m.PodDeployment(ctx, "c1", "shop", "standalone"); err != nil || name != "" {
+		t.Errorf("Expected unowned pod to have no deployment, got %q (%v)", name, err)
+	}
+
+	if err := m.RestartDeployment(ctx, "c1", "shop", "api"); err != nil {
+		t.Fatalf("RestartDeployment failed: %v", err)
+	}
+	deploy, _ := m.clients["c1"].AppsV1().Deployments("shop").Get(ctx, "api", metav1.GetOptions{})
+	if deploy.Spec.Template.Annotations[restartedAtAnnotation] == "" {
+		t.Errorf("Expected restartedAt annotation on pod template, got %+v", deploy.Spec.Template.Annotations)
+	}
+
+	if err := m.CordonNode(ctx, "c1", "gpu-1"); err != nil {
+		t.Fatalf("CordonNode failed: %v", err)
+	}
+	node, _ := m.clients["c1"].CoreV1().Nodes().Get(ctx, "gpu-1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Error("Expected node to be cordoned")
+	}
+}
@@ -0,0 +1,190 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// RetryAttemptsEnv overrides RetryPolicy.MaxAttempts for both the agent and the backend
+const RetryAttemptsEnv = "KC_K8S_RETRY_ATTEMPTS"
+
+// RetryPolicy controls how API reads (GET requests other than watches and log
+// follows) are retried after throttling, gateway errors or dropped connections
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first; 1 disables retries
+	BaseDelay   time.Duration // first backoff, doubled per attempt with jitter
+	MaxDelay    time.Duration // cap on any single wait, including Retry-After
+}
+
+// DefaultRetryPolicy is used unless SetRetryPolicy or KC_K8S_RETRY_ATTEMPTS says otherwise
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 250 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+func retryPolicyFromEnv() RetryPolicy {
+	policy := DefaultRetryPolicy
+	if raw := os.Getenv(RetryAttemptsEnv); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			log.Printf("Warning: ignoring %s=%q (expected a positive integer)", RetryAttemptsEnv, raw)
+		} else {
+			policy.MaxAttempts = n
+		}
+	}
+	return policy
+}
+
+// SetRetryPolicy replaces the retry policy; it applies to requests already in
+// flight from their next attempt
+func (m *MultiClusterClient) SetRetryPolicy(p RetryPolicy) {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	m.mu.Lock()
+	m.retryPolicy = p
+	m.mu.Unlock()
+}
+
+// RetryPolicy returns the current retry policy
+func (m *MultiClusterClient) RetryPolicy() RetryPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.retryPolicy.MaxAttempts == 0 {
+		return DefaultRetryPolicy
+	}
+	return m.retryPolicy
+}
+
+// RetryStats counts the retries made for API requests carrying its context
+type RetryStats struct {
+	retries atomic.Int64
+}
+
+type retryStatsKey struct{}
+
+// WithRetryStats returns a context whose API requests are counted in the returned stats
+func WithRetryStats(ctx context.Context) (context.Context, *RetryStats) {
+	stats := &RetryStats{}
+	return context.WithValue(ctx, retryStatsKey{}, stats), stats
+}
+
+// Retries returns how many requests were retried so far
+func (s *RetryStats) Retries() int {
+	if s == nil {
+		return 0
+	}
+	return int(s.retries.Load())
+}
+
+// retryTransport retries idempotent reads with exponential backoff and jitter
+type retryTransport struct {
+	next   http.RoundTripper
+	policy func() RetryPolicy
+}
+
+// wrapRetries installs the retry transport on a client config
+func (m *MultiClusterClient) wrapRetries(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &retryTransport{next: rt, policy: m.RetryPolicy}
+	})
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !retryable(req) {
+		return t.next.RoundTrip(req)
+	}
+	policy := t.policy()
+	ctx := req.Context()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !transient(resp, err) {
+			return resp, err
+		}
+
+		delay := backoff(policy, attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = min(after, policy.MaxDelay)
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		if stats, ok := ctx.Value(retryStatsKey{}).(*RetryStats); ok {
+			stats.retries.Add(1)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether req can be sent again: bodiless GETs that don't stream
+func retryable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	q := req.URL.Query()
+	return q.Get("watch") != "true" && q.Get("follow") != "true"
+}
+
+// transient reports whether a response or error is worth retrying
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, syscall.ECONNRESET) || (errors.As(err, &netErr) && netErr.Timeout())
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the wait before the next attempt: BaseDelay doubled per attempt,
+// jittered into its upper half and capped at MaxDelay
+func backoff(policy RetryPolicy, attempt int) time.Duration {
+	d := policy.BaseDelay << (attempt - 1)
+	if d <= 0 || d > policy.MaxDelay {
+		d = policy.MaxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	raw := resp.Header.Get("Retry-After")
+	if raw == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(raw); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(raw); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestRetryTransport(t *testing.T) {
+	var calls atomic.Int32
+	failures := int32(2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	rt := &retryTransport{next: http.DefaultTransport, policy: func() RetryPolicy { return policy }}
+	do := func(method, url string) (*http.Response, *RetryStats) {
+		ctx, stats := WithRetryStats(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, method, url, nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp, stats
+	}
+
+	resp, stats := do("GET", srv.URL+"/api/v1/pods")
+	if resp.StatusCode != http.StatusOK || stats.Retries() != 2 {
+		t.Errorf("Expected success after 2 retries, got %d after %d", resp.StatusCode, stats.Retries())
+	}
+
+	// Attempts are capped
+	calls.Store(0)
+	failures = 5
+	resp, stats = do("GET", srv.URL+"/api/v1/pods")
+	if resp.StatusCode != http.StatusTooManyRequests || stats.Retries() != 2 || calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts ending in 429, got %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	// Writes, watches and log follows are sent once
+	for _, tc := range []struct{ method, path string }{
+		{"POST", "/api/v1/namespaces/default/pods"},
+		{"GET", "/api/v1/pods?watch=true"},
+		{"GET", "/api/v1/namespaces/default/pods/p/log?follow=true"},
+	} {
+		calls.Store(0)
+		do(tc.method, srv.URL+tc.path)
+		if calls.Load() != 1 {
+			t.Errorf("%s %s: expected a single attempt, got %d", tc.method, tc.path, calls.Load())
+		}
+	}
+}
+
+func TestRetryTransportInstalledOnClients(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","items":[{"metadata":{"name":"default"}}]}`))
+	}))
+	defer srv.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := clientcmd.WriteToFile(api.Config{
+		Clusters:  map[string]*api.Cluster{"c": {Server: srv.URL}},
+		AuthInfos: map[string]*api.AuthInfo{"u": {}},
+		Contexts:  map[string]*api.Context{"c1": {Cluster: "c", AuthInfo: "u"}},
+	}, kubeconfig); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+	m, _ := NewMultiClusterClient(kubeconfig)
+	m.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	client, err := m.GetClient("c1")
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+	ctx, stats := WithRetryStats(context.Background())
+	list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Expected the 503 to be retried, got %v", err)
+	}
+	if len(list.Items) != 1 || stats.Retries() != 1 {
+		t.Errorf("Expected 1 namespace after 1 retry, got %d after %d", len(list.Items), stats.Retries())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	if d, ok := retryAfter(resp); !ok || d != 3*time.Second {
+		t.Errorf("Expected 3s, got %v (%v)", d, ok)
+	}
+	resp.Header.Set("Retry-After", "soon")
+	if _, ok := retryAfter(resp); ok {
+		t.Error("Expected an invalid Retry-After to be ignored")
+	}
+
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 1; attempt <= 6; attempt++ {
+		if d := backoff(policy, attempt); d > policy.MaxDelay || d < 50*time.Millisecond {
+			t.Errorf("attempt %d: backoff %v out of range", attempt, d)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// deploymentRevisionAnnotation is set by the Deployment controller on deployments and their ReplicaSets
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+	// changeCauseAnnotation records why a revision was created (kubectl annotate / --record)
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+	// podTemplateHashLabel is added to ReplicaSet templates and must not be copied back on rollback
+	podTemplateHashLabel = "pod-template-hash"
+)
+
+var (
+	// ErrUnsupportedRolloutKind is returned for kinds without rollout history
+	ErrUnsupportedRolloutKind = errors.New("rollout history is only available for Deployments and StatefulSets")
+	// ErrRevisionNotFound is returned when rolling back to a revision that no longer exists
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// RolloutHistory is the revision history of a Deployment or StatefulSet, newest first
+type RolloutHistory struct {
+	Kind            string            `json:"kind"`
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	Cluster         string            `json:"cluster,omitempty"`
+	CurrentRevision int64             `json:"currentRevision"`
+	Revisions       []RolloutRevision `json:"revisions"`
+}
+
+// RolloutRevision is one revision, backed by a ReplicaSet (Deployments) or a
+// ControllerRevision (StatefulSets)
+type RolloutRevision struct {
+	Revision    int64             `json:"revision"`
+	Name        string            `json:"name"`
+	CreatedAt   string            `json:"createdAt,omitempty"`
+	ChangeCause string            `json:"changeCause,omitempty"`
+	Images      map[string]string `json:"images"`             // container → image
+	Replicas    *int32            `json:"replicas,omitempty"` // Deployments only
+	Current     bool              `json:"current"`
+	// Changes against the previous revision
+	Changes []RevisionChange `json:"changes,omitempty"`
+}
+
+// RevisionChange is a single difference between two pod templates
+type RevisionChange struct {
+	Container string `json:"container"`
+	Field     string `json:"field"` // container, image, env
+	Key       string `json:"key,omitempty"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+}
+
+// revisionTemplate is a revision with the pod template it rolls out
+type revisionTemplate struct {
+	info     RolloutRevision
+	template corev1.PodTemplateSpec
+	raw      []byte // ControllerRevision patch data
+}
+
+// GetRolloutHistory returns the revisions of a Deployment or StatefulSet with the
+// image and env changes each one introduced
+func (m *MultiClusterClient) GetRolloutHistory(ctx context.Context, contextName, namespace, kind, name string) (*RolloutHistory, error) {
+	revisions, current, err := m.revisionTemplates(ctx, contextName, namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &RolloutHistory{
+		Kind:            normalizeRolloutKind(kind),
+		Name:            name,
+		Namespace:       namespace,
+		Cluster:         contextName,
+		CurrentRevision: current,
+		Revisions:       make([]RolloutRevision, 0, len(revisions)),
+	}
+	// revisions are oldest first so each can be diffed against its predecessor
+	for i, rev := range revisions {
+		if i > 0 {
+			rev.info.Changes = diffPodTemplates(revisions[i-1].template, rev.template)
+		}
+		history.Revisions = append(history.Revisions, rev.info)
+	}
+	for i, j := 0, len(history.Revisions)-1; i < j; i, j = i+1, j-1 {
+		history.Revisions[i], history.Revisions[j] = history.Revisions[j], history.Revisions[i]
+	}
+	return history, nil
+}
+
+// RollbackWorkload rolls a Deployment or StatefulSet back to revision, or to the
+// revision before the current one when revision is 0, like "kubectl rollout undo".
+// It returns the revision rolled back to.
+func (m *MultiClusterClient) RollbackWorkload(ctx context.Context, contextName, namespace, kind, name string, revision int64) (int64, error) {
+	revisions, current, err := m.revisionTemplates(ctx, contextName, namespace, kind, name)
+	if err != nil {
+		return 0, err
+	}
+
+	var target *revisionTemplate
+	for i := len(revisions) - 1; i >= 0; i-- {
+		rev := revisions[i].info.Revision
+		if (revision == 0 && rev < current) || (revision != 0 && rev == revision) {
+			target = &revisions[i]
+			break
+		}
+	}
+	if target == nil {
+		return 0, ErrRevisionNotFound
+	}
+	if target.info.Revision == current {
+		return current, nil
+	}
+
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return 0, err
+	}
+	switch normalizeRolloutKind(kind) {
+	case "Deployment":
+		deploy, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		template := *target.template.DeepCopy()
+		delete(template.Labels, podTemplateHashLabel)
+		deploy.Spec.Template = template
+		if _, err := client.AppsV1().Deployments(namespace).Update(ctx, deploy, metav1.UpdateOptions{}); err != nil {
+			return 0, err
+		}
+	default:
+		// ControllerRevision data is already a strategic merge patch of the template
+		if _, err := client.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, target.raw, metav1.PatchOptions{}); err != nil {
+			return 0, err
+		}
+	}
+	return target.info.Revision, nil
+}
+
+// revisionTemplates returns the workload's revisions oldest first and its current revision
+func (m *MultiClusterClient) revisionTemplates(ctx context.Context, contextName, namespace, kind, name string) ([]revisionTemplate, int64, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var revisions []revisionTemplate
+	var current int64
+	switch normalizeRolloutKind(kind) {
+	case "Deployment":
+		deploy, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		current, _ = strconv.ParseInt(deploy.Annotations[deploymentRevisionAnnotation], 10, 64)
+		selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+		if err != nil {
+			return nil, 0, err
+		}
+		replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, rs := range replicaSets.Items {
+			if !metav1.IsControlledBy(&rs, deploy) {
+				continue
+			}
+			rev, err := strconv.ParseInt(rs.Annotations[deploymentRevisionAnnotation], 10, 64)
+			if err != nil {
+				continue
+			}
+			replicas := rs.Status.Replicas
+			revisions = append(revisions, revisionTemplate{
+				info:     newRolloutRevision(rev, rs.ObjectMeta, rs.Spec.Template, rev == current, &replicas),
+				template: rs.Spec.Template,
+			})
+		}
+	case "StatefulSet":
+		sts, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, 0, err
+		}
+		currentName := sts.Status.UpdateRevision
+		if currentName == "" {
+			currentName = sts.Status.CurrentRevision
+		}
+		selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+		if err != nil {
+			return nil, 0, err
+		}
+		controllerRevisions, err := client.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, cr := range controllerRevisions.Items {
+			if !metav1.IsControlledBy(&cr, sts) {
+				continue
+			}
+			template, err := statefulSetRevisionTemplate(&cr)
+			if err != nil {
+				continue
+			}
+			if cr.Name == currentName {
+				current = cr.Revision
+			}
+			revisions = append(revisions, revisionTemplate{
+				info:     newRolloutRevision(cr.Revision, cr.ObjectMeta, template, cr.Name == currentName, nil),
+				template: template,
+				raw:      cr.Data.Raw,
+			})
+		}
+	default:
+		return nil, 0, fmt.Errorf("%w: %s", ErrUnsupportedRolloutKind, kind)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].info.Revision < revisions[j].info.Revision
+	})
+	return revisions, current, nil
+}
+
+// statefulSetRevisionTemplate decodes the pod template stored in a ControllerRevision
+func statefulSetRevisionTemplate(cr *appsv1.ControllerRevision) (corev1.PodTemplateSpec, error) {
+	var patch struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(cr.Data.Raw, &patch); err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	return patch.Spec.Template, nil
+}
+
+func newRolloutRevision(rev int64, meta metav1.ObjectMeta, template corev1.PodTemplateSpec, current bool, replicas *int32) RolloutRevision {
+	info := RolloutRevision{
+		Revision:    rev,
+		Name:        meta.Name,
+		ChangeCause: meta.Annotations[changeCauseAnnotation],
+		Images:      map[string]string{},
+		Replicas:    replicas,
+		Current:     current,
+	}
+	if !meta.CreationTimestamp.IsZero() {
+		info.CreatedAt = meta.CreationTimestamp.Format(time.RFC3339)
+	}
+	for _, c := range template.Spec.Containers {
+		info.Images[c.Name] = c.Image
+	}
+	return info
+}
+
+// normalizeRolloutKind accepts the kind in any case and the plural resource names
+func normalizeRolloutKind(kind string) string {
+	switch kind {
+	case "Deployment", "deployment", "deployments", "deploy":
+		return "Deployment"
+	case "StatefulSet", "statefulset", "statefulsets", "sts":
+		return "StatefulSet"
+	}
+	return kind
+}
+
+// diffPodTemplates lists the containers added or removed and the image and env
+// changes between two pod templates
+func diffPodTemplates(from, to corev1.PodTemplateSpec) []RevisionChange {
+	var changes []RevisionChange
+	before := make(map[string]corev1.Container, len(from.Spec.Containers))
+	for _, c := range from.Spec.Containers {
+		before[c.Name] = c
+	}
+
+	for _, c := range to.Spec.Containers {
+		prev, ok := before[c.Name]
+		if !ok {
+			changes = append(changes, RevisionChange{Container: c.Name, Field: "container", To: c.Image})
+			continue
+		}
+		delete(before, c.Name)
+		if prev.Image != c.Image {
+			changes = append(changes, RevisionChange{Container: c.Name, Field: "image", From: prev.Image, To: c.Image})
+		}
+		changes = append(changes, diffEnv(c.Name, prev.Env, c.Env)...)
+	}
+
+	removed := make([]string, 0, len(before))
+	for name := range before {
+		removed = append(removed, name)
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		changes = append(changes, RevisionChange{Container: name, Field: "container", From: before[name].Image})
+	}
+	return changes
+}
+
+// diffEnv compares env vars by name; values from secrets and config maps are
+// reported by reference, never resolved
+func diffEnv(container string, from, to []corev1.EnvVar) []RevisionChange {
+	before := make(map[string]string, len(from))
+	for _, e := range from {
+		before[e.Name] = envValue(e)
+	}
+	var changes []RevisionChange
+	seen := make(map[string]bool, len(to))
+	for _, e := range to {
+		seen[e.Name] = true
+		value := envValue(e)
+		if prev, ok := before[e.Name]; !ok || prev != value {
+			changes = append(changes, RevisionChange{Container: container, Field: "env", Key: e.Name, From: prev, To: value})
+		}
+	}
+	for _, e := range from {
+		if !seen[e.Name] {
+			changes = append(changes, RevisionChange{Container: container, Field: "env", Key: e.Name, From: before[e.Name]})
+		}
+	}
+	return changes
+}
+
+func envValue(e corev1.EnvVar) string {
+	src := e.ValueFrom
+	switch {
+	case src == nil:
+		return e.Value
+	case src.SecretKeyRef != nil:
+		return fmt.Sprintf("secret:%s/%s", src.SecretKeyRef.Name, src.SecretKeyRef.Key)
+	case src.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configmap:%s/%s", src.ConfigMapKeyRef.Name, src.ConfigMapKeyRef.Key)
+	case src.FieldRef != nil:
+		return "field:" + src.FieldRef.FieldPath
+	case src.ResourceFieldRef != nil:
+		return "resource:" + src.ResourceFieldRef.Resource
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func rolloutTemplate(image string, env ...corev1.EnvVar) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: image, Env: env}}},
+	}
+}
+
+func TestDeploymentRolloutHistory(t *testing.T) {
+	isController := true
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "api", Namespace: "default", UID: types.UID("deploy-uid"),
+			Annotations: map[string]string{deploymentRevisionAnnotation: "3"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Template: rolloutTemplate("api:v3", corev1.EnvVar{Name: "LOG", Value: "debug"}),
+		},
+	}
+	replicaSet := func(name, revision string, template corev1.PodTemplateSpec) *appsv1.ReplicaSet {
+		template.Labels = map[string]string{"app": "api", podTemplateHashLabel: name}
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default", Labels: map[string]string{"app": "api"},
+				Annotations:     map[string]string{deploymentRevisionAnnotation: revision},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "api", UID: "deploy-uid", Controller: &isController}},
+			},
+			Spec: appsv1.ReplicaSetSpec{Template: template},
+		}
+	}
+	orphan := replicaSet("other", "9", rolloutTemplate("other:v1"))
+	orphan.OwnerReferences = nil
+
+	m, _ := NewMultiClusterClient("")
+	fakeClient := k8sfake.NewSimpleClientset(deploy,
+		replicaSet("api-1", "1", rolloutTemplate("api:v1", corev1.EnvVar{Name: "LOG", Value: "info"})),
+		replicaSet("api-2", "2", rolloutTemplate("api:v2", corev1.EnvVar{Name: "LOG", Value: "info"})),
+		replicaSet("api-3", "3", rolloutTemplate("api:v3", corev1.EnvVar{Name: "LOG", Value: "debug"})),
+		orphan,
+	)
+	m.InjectClient("c1", fakeClient)
+	ctx := context.Background()
+
+	history, err := m.GetRolloutHistory(ctx, "c1", "default", "deployments", "api")
+	if err != nil {
+		t.Fatalf("GetRolloutHistory failed: %v", err)
+	}
+	if len(history.Revisions) != 3 || history.CurrentRevision != 3 {
+		t.Fatalf("Expected 3 owned revisions at revision 3, got %d at %d", len(history.Revisions), history.CurrentRevision)
+	}
+	newest := history.Revisions[0]
+	if newest.Revision != 3 || !newest.Current || newest.Images["api"] != "api:v3" {
+		t.Errorf("Expected the current revision first, got %+v", newest)
+	}
+	if len(newest.Changes) != 2 || newest.Changes[0].Field != "image" || newest.Changes[1].Key != "LOG" || newest.Changes[1].To != "debug" {
+		t.Errorf("Expected image and env changes, got %+v", newest.Changes)
+	}
+	if len(history.Revisions[2].Changes) != 0 {
+		t.Errorf("Expected no changes for the oldest revision, got %+v", history.Revisions[2].Changes)
+	}
+
+	// Rolling back without a revision goes to the previous one
+	revision, err := m.RollbackWorkload(ctx, "c1", "default", "Deployment", "api", 0)
+	if err != nil || revision != 2 {
+		t.Fatalf("Expected a rollback to revision 2, got %d (%v)", revision, err)
+	}
+	updated, _ := fakeClient.AppsV1().Deployments("default").Get(ctx, "api", metav1.GetOptions{})
+	if updated.Spec.Template.Spec.Containers[0].Image != "api:v2" {
+		t.Errorf("Expected the template of revision 2, got %s", updated.Spec.Template.Spec.Containers[0].Image)
+	}
+	if _, ok := updated.Spec.Template.Labels[podTemplateHashLabel]; ok {
+		t.Error("Expected the pod-template-hash label to be dropped")
+	}
+
+	if _, err := m.RollbackWorkload(ctx, "c1", "default", "Deployment", "api", 7); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("Expected ErrRevisionNotFound, got %v", err)
+	}
+	if _, err := m.GetRolloutHistory(ctx, "c1", "default", "DaemonSet", "api"); !errors.Is(err, ErrUnsupportedRolloutKind) {
+		t.Errorf("Expected ErrUnsupportedRolloutKind, got %v", err)
+	}
+}
+
+func TestStatefulSetRolloutHistory(t *testing.T) {
+	isController := true
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: types.UID("sts-uid")},
+		Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+		Status:     appsv1.StatefulSetStatus{CurrentRevision: "db-1", UpdateRevision: "db-2"},
+	}
+	revision := func(name string, rev int64, template corev1.PodTemplateSpec) *appsv1.ControllerRevision {
+		data, _ := json.Marshal(map[string]interface{}{"spec": map[string]interface{}{"template": template}})
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default", Labels: map[string]string{"app": "api"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", UID: "sts-uid", Controller: &isController}},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: rev,
+		}
+	}
+
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(sts,
+		revision("db-1", 1, rolloutTemplate("postgres:15")),
+		revision("db-2", 2, rolloutTemplate("postgres:16", corev1.EnvVar{
+			Name:      "PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "pw"}},
+		})),
+	))
+
+	history, err := m.GetRolloutHistory(context.Background(), "c1", "default", "StatefulSet", "db")
+	if err != nil {
+		t.Fatalf("GetRolloutHistory failed: %v", err)
+	}
+	if history.CurrentRevision != 2 || len(history.Revisions) != 2 || !history.Revisions[0].Current {
+		t.Fatalf("Expected revision 2 to be current, got %+v", history)
+	}
+	changes := history.Revisions[0].Changes
+	if len(changes) != 2 || changes[1].To != "secret:db/pw" {
+		t.Errorf("Expected secret env vars by reference, got %+v", changes)
+	}
+}
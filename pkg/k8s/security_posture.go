@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxPostureScore is the score of a pod, namespace or cluster with no security issues
+const maxPostureScore = 100.0
+
+// severityPenalty is how many points each issue of a severity takes off a pod's score
+var severityPenalty = map[string]float64{
+	"critical": 40,
+	"high":     25,
+	"medium":   10,
+	"low":      2,
+}
+
+// SecurityPostureScore is the security score of a namespace: the average score
+// of its pods, where each pod starts at 100 and loses points per issue
+type SecurityPostureScore struct {
+	Namespace string  `json:"namespace"`
+	Score     float64 `json:"score"`
+	Pods      int     `json:"pods"`
+	High      int     `json:"high"`
+	Medium    int     `json:"medium"`
+	Low       int     `json:"low"`
+}
+
+// SecurityPosture is the security score of a cluster and of each of its namespaces
+type SecurityPosture struct {
+	Cluster    string                 `json:"cluster"`
+	Score      float64                `json:"score"`
+	Pods       int                    `json:"pods"`
+	High       int                    `json:"high"`
+	Medium     int                    `json:"medium"`
+	Low        int                    `json:"low"`
+	Namespaces []SecurityPostureScore `json:"namespaces"`
+}
+
+// GetSecurityPosture scores a cluster and its namespaces from the security
+// issues of their pods
+func (m *MultiClusterClient) GetSecurityPosture(ctx context.Context, contextName string) (*SecurityPosture, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	type totals struct {
+		SecurityPostureScore
+		sum float64
+	}
+	byNamespace := make(map[string]*totals)
+	cluster := &totals{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		ns, ok := byNamespace[pod.Namespace]
+		if !ok {
+			ns = &totals{SecurityPostureScore: SecurityPostureScore{Namespace: pod.Namespace}}
+			byNamespace[pod.Namespace] = ns
+		}
+		issues := podSecurityIssues(pod.Name, pod.Namespace, contextName, &pod.Spec)
+		score := podPostureScore(issues)
+		for _, t := range []*totals{ns, cluster} {
+			t.Pods++
+			t.sum += score
+			for _, issue := range issues {
+				switch issue.Severity {
+				case "critical", "high":
+					t.High++
+				case "medium":
+					t.Medium++
+				default:
+					t.Low++
+				}
+			}
+		}
+	}
+
+	posture := &SecurityPosture{
+		Cluster:    contextName,
+		Score:      averageScore(cluster.sum, cluster.Pods),
+		Pods:       cluster.Pods,
+		High:       cluster.High,
+		Medium:     cluster.Medium,
+		Low:        cluster.Low,
+		Namespaces: make([]SecurityPostureScore, 0, len(byNamespace)),
+	}
+	for _, ns := range byNamespace {
+		ns.Score = averageScore(ns.sum, ns.Pods)
+		posture.Namespaces = append(posture.Namespaces, ns.SecurityPostureScore)
+	}
+	// Worst namespaces first
+	sort.Slice(posture.Namespaces, func(i, j int) bool {
+		if posture.Namespaces[i].Score != posture.Namespaces[j].Score {
+			return posture.Namespaces[i].Score < posture.Namespaces[j].Score
+		}
+		return posture.Namespaces[i].Namespace < posture.Namespaces[j].Namespace
+	})
+	return posture, nil
+}
+
+// podPostureScore scores a pod from its issues, never below zero
+func podPostureScore(issues []SecurityIssue) float64 {
+	score := maxPostureScore
+	for _, issue := range issues {
+		score -= severityPenalty[issue.Severity]
+	}
+	return math.Max(score, 0)
+}
+
+// averageScore averages pod scores to one decimal; an empty scope scores full marks
+func averageScore(sum float64, pods int) float64 {
+	if pods == 0 {
+		return maxPostureScore
+	}
+	return math.Round(sum/float64(pods)*10) / 10
+}
@@ -0,0 +1,386 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SharedCacheURLEnv selects an external cache shared by all console/agent replicas,
+// e.g. redis://:password@redis:6379/0 or rediss:// for TLS. When unset, cluster health,
+// slow-cluster flags and metrics snapshots stay in-process only.
+const SharedCacheURLEnv = "KC_CACHE_URL"
+
+const (
+	sharedCacheKeyPrefix   = "kc:"
+	sharedCacheOpTimeout   = 500 * time.Millisecond // cache lookups must never stall a request
+	sharedCacheDialTimeout = 2 * time.Second
+	sharedCacheRetryDelay  = 10 * time.Second // skip the cache this long after a failed dial
+)
+
+// SharedCache is a key/value store shared between replicas. Values expire after ttl.
+// Implementations must be safe for concurrent use.
+type SharedCache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewSharedCacheFromURL creates a SharedCache for a redis://, rediss:// or memory:// URL
+func NewSharedCacheFromURL(rawURL string) (SharedCache, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache URL: %w", err)
+	}
+	switch u.Scheme {
+	case "memory":
+		return NewMemoryCache(), nil
+	case "redis", "rediss":
+		return newRedisCache(u)
+	}
+	return nil, fmt.Errorf("unsupported cache backend %q (expected redis, rediss or memory)", u.Scheme)
+}
+
+// SharedCacheFromEnv returns the cache configured by KC_CACHE_URL, or nil
+func SharedCacheFromEnv() SharedCache {
+	rawURL := os.Getenv(SharedCacheURLEnv)
+	if rawURL == "" {
+		return nil
+	}
+	cache, err := NewSharedCacheFromURL(rawURL)
+	if err != nil {
+		log.Printf("Warning: shared cache disabled: %v", err)
+		return nil
+	}
+	log.Printf("Using shared cache backend: %s", cacheURLForLog(rawURL))
+	return cache
+}
+
+// cacheURLForLog strips credentials from a cache URL
+func cacheURLForLog(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid>"
+	}
+	u.User = nil
+	return u.String()
+}
+
+// SetSharedCache shares cluster health and slow-cluster flags with other replicas
+func (m *MultiClusterClient) SetSharedCache(cache SharedCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sharedCache = cache
+}
+
+// SharedCache returns the configured shared cache, or nil
+func (m *MultiClusterClient) SharedCache() SharedCache {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sharedCache
+}
+
+// SharedGetJSON reads a JSON value from the shared cache, reporting whether it was found
+func SharedGetJSON(cache SharedCache, key string, v interface{}) bool {
+	if cache == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedCacheOpTimeout)
+	defer cancel()
+	data, ok, err := cache.Get(ctx, sharedCacheKeyPrefix+key)
+	if err != nil || !ok {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// SharedSetJSON writes a JSON value to the shared cache; failures are logged only
+func SharedSetJSON(cache SharedCache, key string, v interface{}, ttl time.Duration) {
+	if cache == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedCacheOpTimeout)
+	defer cancel()
+	if err := cache.Set(ctx, sharedCacheKeyPrefix+key, data, ttl); err != nil {
+		log.Printf("[SharedCache] write %s failed: %v", key, err)
+	}
+}
+
+// SharedDelete removes a key from the shared cache; failures are logged only
+func SharedDelete(cache SharedCache, key string) {
+	if cache == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedCacheOpTimeout)
+	defer cancel()
+	if err := cache.Delete(ctx, sharedCacheKeyPrefix+key); err != nil {
+		log.Printf("[SharedCache] delete %s failed: %v", key, err)
+	}
+}
+
+// MemoryCache is an in-process SharedCache, useful for a single replica and tests
+type MemoryCache struct {
+	mu    sync.Mutex
+	items map[string]memoryCacheItem
+}
+
+type memoryCacheItem struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an empty in-process cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{items: make(map[string]memoryCacheItem)}
+}
+
+// Get returns a value if present and not expired
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !item.expires.IsZero() && time.Now().After(item.expires) {
+		delete(c.items, key)
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
+// Set stores a value; a zero ttl never expires
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item := memoryCacheItem{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
+	}
+	c.items[key] = item
+	return nil
+}
+
+// Delete removes a value
+func (c *MemoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+	return nil
+}
+
+// redisCache is a minimal RESP2 client supporting the commands the cache needs.
+// A single connection is reused and re-dialed after any error; while the server is
+// unreachable, calls fail fast instead of dialing on every request.
+type redisCache struct {
+	addr     string
+	useTLS   bool
+	username string
+	password string
+	db       int
+
+	mu        sync.Mutex
+	conn      net.Conn
+	rd        *bufio.Reader
+	downUntil time.Time
+}
+
+func newRedisCache(u *url.URL) (*redisCache, error) {
+	c := &redisCache{addr: u.Host, useTLS: u.Scheme == "rediss"}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+		c.db = n
+	}
+	return c, nil
+}
+
+// Get returns the value of key
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	b, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected GET reply %T", reply)
+	}
+	return b, true, nil
+}
+
+// Set stores value under key with a millisecond expiry
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Delete removes key
+func (c *redisCache) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", key)
+	return err
+}
+
+func (c *redisCache) do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if time.Now().Before(c.downUntil) {
+			return nil, fmt.Errorf("redis %s unavailable", c.addr)
+		}
+		if err := c.connectLocked(ctx); err != nil {
+			c.downUntil = time.Now().Add(sharedCacheRetryDelay)
+			return nil, err
+		}
+	}
+	reply, err := c.roundTripLocked(ctx, args)
+	if err != nil {
+		if _, isRedisErr := err.(redisError); !isRedisErr {
+			c.conn.Close()
+			c.conn = nil
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (c *redisCache) connectLocked(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: sharedCacheDialTimeout}
+	var conn net.Conn
+	var err error
+	if c.useTLS {
+		host, _, _ := net.SplitHostPort(c.addr)
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}).DialContext(ctx, "tcp", c.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("redis dial %s: %w", c.addr, err)
+	}
+	c.conn = conn
+	c.rd = bufio.NewReader(conn)
+
+	if c.password != "" {
+		auth := []string{"AUTH", c.password}
+		if c.username != "" {
+			auth = []string{"AUTH", c.username, c.password}
+		}
+		if _, err := c.roundTripLocked(ctx, auth); err != nil {
+			conn.Close()
+			c.conn = nil
+			return fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := c.roundTripLocked(ctx, []string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			conn.Close()
+			c.conn = nil
+			return fmt.Errorf("redis select: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *redisCache) roundTripLocked(ctx context.Context, args []string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(sharedCacheOpTimeout)
+	}
+	c.conn.SetDeadline(deadline)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return readRESP(c.rd)
+}
+
+// redisError is an error reply from the server; the connection stays usable
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// readRESP reads one RESP2 reply. Bulk strings are returned as []byte, nil bulk
+// strings as nil, integers as int64 and simple strings as string.
+func readRESP(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRESP(rd); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves GET/SET/DEL/AUTH/SELECT over RESP for redisCache tests
+func fakeRedis(t *testing.T, password string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	data := map[string]string{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				rd := bufio.NewReader(conn)
+				authed := password == ""
+				for {
+					reply, err := readRESP(rd)
+					if err != nil {
+						return
+					}
+					items, _ := reply.([]interface{})
+					var args []string
+					for _, it := range items {
+						b, _ := it.([]byte)
+						args = append(args, string(b))
+					}
+					if len(args) == 0 {
+						return
+					}
+					cmd := strings.ToUpper(args[0])
+					if !authed && cmd != "AUTH" {
+						conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+						continue
+					}
+					mu.Lock()
+					switch cmd {
+					case "AUTH":
+						if args[len(args)-1] == password {
+							authed = true
+							conn.Write([]byte("+OK\r\n"))
+						} else {
+							conn.Write([]byte("-WRONGPASS invalid password\r\n"))
+						}
+					case "SELECT":
+						conn.Write([]byte("+OK\r\n"))
+					case "SET":
+						data[args[1]] = args[2]
+						conn.Write([]byte("+OK\r\n"))
+					case "GET":
+						if v, ok := data[args[1]]; ok {
+							conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+						} else {
+							conn.Write([]byte("$-1\r\n"))
+						}
+					case "DEL":
+						delete(data, args[1])
+						conn.Write([]byte(":1\r\n"))
+					default:
+						conn.Write([]byte("-ERR unknown command\r\n"))
+					}
+					mu.Unlock()
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRedisCache(t *testing.T) {
+	addr := fakeRedis(t, "s3cret")
+	ctx := context.Background()
+
+	cache, err := NewSharedCacheFromURL("redis://:s3cret@" + addr + "/2")
+	if err != nil {
+		t.Fatalf("NewSharedCacheFromURL failed: %v", err)
+	}
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Expected miss without error, got ok=%v err=%v", ok, err)
+	}
+	if err := cache.Set(ctx, "k", []byte("hello\r\nworld"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if v, ok, err := cache.Get(ctx, "k"); !ok || err != nil || string(v) != "hello\r\nworld" {
+		t.Errorf("Expected stored value, got %q ok=%v err=%v", v, ok, err)
+	}
+	if err := cache.Delete(ctx, "k"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+
+	bad, _ := NewSharedCacheFromURL("redis://:wrong@" + addr)
+	if _, _, err := bad.Get(ctx, "k"); err == nil {
+		t.Error("Expected wrong password to fail")
+	}
+
+	if _, err := NewSharedCacheFromURL("memcached://localhost"); err == nil {
+		t.Error("Expected unsupported backend to be rejected")
+	}
+}
+
+func TestSharedCacheAcrossReplicas(t *testing.T) {
+	shared := NewMemoryCache()
+	a, _ := NewMultiClusterClient("")
+	b, _ := NewMultiClusterClient("")
+	a.SetSharedCache(shared)
+	b.SetSharedCache(shared)
+
+	a.MarkSlow("c1")
+	if !b.IsSlow("c1") {
+		t.Error("Expected slow flag set by one replica to be visible to the other")
+	}
+	if b.IsSlow("c2") {
+		t.Error("Expected unmarked cluster not to be slow")
+	}
+
+	// Health checked by another replica is served without contacting the cluster
+	SharedSetJSON(shared, "health:c1", &ClusterHealth{Cluster: "c1", Healthy: true, Reachable: true, NodeCount: 3, CheckedAt: time.Now().Format(time.RFC3339)}, time.Minute)
+	health, err := b.GetClusterHealth(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetClusterHealth failed: %v", err)
+	}
+	if !health.Healthy || health.NodeCount != 3 {
+		t.Errorf("Expected shared health entry, got %+v", health)
+	}
+	if cached := b.GetCachedHealth(); cached["c1"] == nil {
+		t.Error("Expected shared health to populate the local cache")
+	}
+}
@@ -0,0 +1,233 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Annotations marking a StorageClass as the cluster default
+var defaultStorageClassAnnotations = []string{
+	"storageclass.kubernetes.io/is-default-class",
+	"storageclass.beta.kubernetes.io/is-default-class",
+}
+
+// externalHostPathProvisioners are common out-of-tree provisioners that are
+// not CSI drivers and so never register a CSIDriver
+var externalHostPathProvisioners = []string{
+	"rancher.io/local-path",
+	"k8s.io/minikube-hostpath",
+	"microk8s.io/hostpath",
+	"docker.io/hostpath",
+}
+
+// StorageClass represents a Kubernetes StorageClass
+type StorageClass struct {
+	Name                 string            `json:"name"`
+	Cluster              string            `json:"cluster,omitempty"`
+	Provisioner          string            `json:"provisioner"`
+	ReclaimPolicy        string            `json:"reclaimPolicy"`
+	VolumeBindingMode    string            `json:"volumeBindingMode"`
+	AllowVolumeExpansion bool              `json:"allowVolumeExpansion"`
+	IsDefault            bool              `json:"isDefault"`
+	Parameters           map[string]string `json:"parameters,omitempty"`
+	Age                  string            `json:"age,omitempty"`
+}
+
+// StorageIssue is a storage misconfiguration or a volume running out of space
+type StorageIssue struct {
+	Kind      string `json:"kind"` // StorageClass, PersistentVolumeClaim
+	Name      string `json:"name"` // empty for cluster-wide findings
+	Namespace string `json:"namespace,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+	Issue     string `json:"issue"`
+	Severity  string `json:"severity"` // high, medium
+	Details   string `json:"details,omitempty"`
+}
+
+// GetStorageClasses returns a cluster's StorageClasses, the default class first
+func (m *MultiClusterClient) GetStorageClasses(ctx context.Context, contextName string) ([]StorageClass, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]StorageClass, 0, len(classes.Items))
+	for i := range classes.Items {
+		sc := &classes.Items[i]
+		// The API server defaults these on create; keep the same defaults for objects that predate them
+		reclaimPolicy := "Delete"
+		if sc.ReclaimPolicy != nil {
+			reclaimPolicy = string(*sc.ReclaimPolicy)
+		}
+		bindingMode := string(storagev1.VolumeBindingImmediate)
+		if sc.VolumeBindingMode != nil {
+			bindingMode = string(*sc.VolumeBindingMode)
+		}
+		result = append(result, StorageClass{
+			Name:                 sc.Name,
+			Cluster:              contextName,
+			Provisioner:          sc.Provisioner,
+			ReclaimPolicy:        reclaimPolicy,
+			VolumeBindingMode:    bindingMode,
+			AllowVolumeExpansion: sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion,
+			IsDefault:            isDefaultStorageClass(sc),
+			Parameters:           sc.Parameters,
+			Age:                  formatAge(sc.CreationTimestamp.Time),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].IsDefault != result[j].IsDefault {
+			return result[i].IsDefault
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// isDefaultStorageClass reports whether a StorageClass is annotated as the default
+func isDefaultStorageClass(sc *storagev1.StorageClass) bool {
+	for _, a := range defaultStorageClassAnnotations {
+		if sc.Annotations[a] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// FindStorageIssues detects StorageClass misconfigurations (no default class,
+// several defaults, classes whose CSI driver is not installed) and PVC-backed
+// volumes nearly out of space. Class findings are cluster-wide and reported
+// regardless of namespace.
+func (m *MultiClusterClient) FindStorageIssues(ctx context.Context, contextName, namespace string) ([]StorageIssue, error) {
+	classes, err := m.GetStorageClasses(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	drivers, err := installedCSIDrivers(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := storageClassIssues(classes, drivers)
+	for i := range issues {
+		issues[i].Cluster = contextName
+	}
+
+	volumes, err := m.GetVolumeUsage(ctx, contextName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range volumes {
+		if v.Issue == "" {
+			continue
+		}
+		issues = append(issues, StorageIssue{
+			Kind:      "PersistentVolumeClaim",
+			Name:      v.PVC,
+			Namespace: v.Namespace,
+			Cluster:   contextName,
+			Issue:     v.Issue,
+			Severity:  v.Severity,
+			Details:   fmt.Sprintf("%d of %d bytes used, mounted by %s on %s", v.UsedBytes, v.CapacityBytes, v.Pod, v.Node),
+		})
+	}
+	return issues, nil
+}
+
+// installedCSIDrivers returns the names of CSI drivers registered in the
+// cluster, either as a CSIDriver object or on any node's CSINode
+func installedCSIDrivers(ctx context.Context, client kubernetes.Interface) (map[string]bool, error) {
+	drivers := make(map[string]bool)
+	csiDrivers, err := client.StorageV1().CSIDrivers().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range csiDrivers.Items {
+		drivers[d.Name] = true
+	}
+	csiNodes, err := client.StorageV1().CSINodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range csiNodes.Items {
+		for _, d := range n.Spec.Drivers {
+			drivers[d.Name] = true
+		}
+	}
+	return drivers, nil
+}
+
+// storageClassIssues validates the default class and that each class's
+// provisioner is installed
+func storageClassIssues(classes []StorageClass, drivers map[string]bool) []StorageIssue {
+	issues := make([]StorageIssue, 0)
+	if len(classes) == 0 {
+		return issues
+	}
+
+	var defaults []string
+	for _, sc := range classes {
+		if sc.IsDefault {
+			defaults = append(defaults, sc.Name)
+		}
+	}
+	switch {
+	case len(defaults) == 0:
+		issues = append(issues, StorageIssue{
+			Kind:     "StorageClass",
+			Issue:    "No default StorageClass",
+			Severity: "medium",
+			Details:  "PVCs without a storageClassName stay Pending; annotate one class with storageclass.kubernetes.io/is-default-class=true",
+		})
+	case len(defaults) > 1:
+		issues = append(issues, StorageIssue{
+			Kind:     "StorageClass",
+			Issue:    "Multiple default StorageClasses",
+			Severity: "medium",
+			Details:  fmt.Sprintf("%s are all marked default; new PVCs get the most recently created one", strings.Join(defaults, ", ")),
+		})
+	}
+
+	for _, sc := range classes {
+		if !isCSIProvisioner(sc.Provisioner) || drivers[sc.Provisioner] {
+			continue
+		}
+		severity := "medium"
+		if sc.IsDefault {
+			severity = "high"
+		}
+		issues = append(issues, StorageIssue{
+			Kind:     "StorageClass",
+			Name:     sc.Name,
+			Issue:    "CSI driver not installed",
+			Severity: severity,
+			Details:  fmt.Sprintf("No CSIDriver or node registration for provisioner %s; PVCs using this class cannot be provisioned", sc.Provisioner),
+		})
+	}
+	return issues
+}
+
+// isCSIProvisioner reports whether a provisioner is expected to be a CSI
+// driver, i.e. neither in-tree nor a known non-CSI external provisioner
+func isCSIProvisioner(provisioner string) bool {
+	if strings.HasPrefix(provisioner, "kubernetes.io/") {
+		return false
+	}
+	return !slices.Contains(externalHostPathProvisioners, provisioner)
+}
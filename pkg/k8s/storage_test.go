@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFindStorageIssues(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	class := func(name, provisioner string, isDefault bool) *storagev1.StorageClass {
+		sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}, Provisioner: provisioner}
+		if isDefault {
+			sc.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+		}
+		return sc
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		class("gp3", "ebs.csi.aws.com", true),
+		class("gp2", "kubernetes.io/aws-ebs", false),
+		class("ceph", "rook-ceph.rbd.csi.ceph.com", false),
+		class("local", "rancher.io/local-path", false),
+		&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "ebs.csi.aws.com"}},
+	)
+
+	classes, err := m.GetStorageClasses(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetStorageClasses failed: %v", err)
+	}
+	if len(classes) != 4 || classes[0].Name != "gp3" || !classes[0].IsDefault || classes[0].ReclaimPolicy != "Delete" || classes[0].VolumeBindingMode != "Immediate" {
+		t.Errorf("Expected default class gp3 first with API defaults, got %+v", classes)
+	}
+
+	issues, err := m.FindStorageIssues(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("FindStorageIssues failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Name != "ceph" || issues[0].Issue != "CSI driver not installed" || issues[0].Cluster != "c1" {
+		t.Errorf("Expected only the ceph class to be missing its driver, got %+v", issues)
+	}
+
+	// A driver registered only on nodes counts as installed
+	m.clients["c2"] = k8sfake.NewSimpleClientset(
+		class("ceph", "rook-ceph.rbd.csi.ceph.com", true),
+		class("fast", "rook-ceph.rbd.csi.ceph.com", true),
+		&storagev1.CSINode{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Spec:       storagev1.CSINodeSpec{Drivers: []storagev1.CSINodeDriver{{Name: "rook-ceph.rbd.csi.ceph.com"}}},
+		},
+	)
+	issues, _ = m.FindStorageIssues(context.Background(), "c2", "")
+	if len(issues) != 1 || issues[0].Issue != "Multiple default StorageClasses" {
+		t.Errorf("Expected multiple defaults to be flagged, got %+v", issues)
+	}
+
+	m.clients["c3"] = k8sfake.NewSimpleClientset(class("gp2", "kubernetes.io/aws-ebs", false))
+	issues, _ = m.FindStorageIssues(context.Background(), "c3", "")
+	if len(issues) != 1 || issues[0].Issue != "No default StorageClass" {
+		t.Errorf("Expected missing default to be flagged, got %+v", issues)
+	}
+}
@@ -0,0 +1,236 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+)
+
+// TaintTemplates are the common GPU taints offered by name
+var TaintTemplates = map[string]corev1.Taint{
+	// Keeps pods that don't request NVIDIA GPUs off GPU nodes
+	"nvidia-gpu": {Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule},
+	// Keeps pods that don't request AMD GPUs off GPU nodes
+	"amd-gpu": {Key: "amd.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule},
+	// Reserves nodes for a GPU pool whose pods tolerate dedicated=gpu
+	"gpu-dedicated": {Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+	// Evicts workloads ahead of driver upgrades or hardware maintenance
+	"gpu-maintenance": {Key: "nvidia.com/gpu.maintenance", Value: "true", Effect: corev1.TaintEffectNoExecute},
+}
+
+// ErrInvalidTaintChange is returned when a taint change is empty or malformed
+var ErrInvalidTaintChange = errors.New("invalid taint change")
+
+// Effects of a taint change on a pod running on the node
+const (
+	TaintImpactEvicted = "evicted" // a NoExecute taint the pod does not tolerate
+	TaintImpactBlocked = "blocked" // the pod keeps running but could not be scheduled there again
+)
+
+// TaintChange adds and removes taints on a node. Templates name entries of
+// TaintTemplates to add. A removed taint without an effect removes the key
+// with every effect.
+type TaintChange struct {
+	Add       []corev1.Taint `json:"add,omitempty"`
+	Templates []string       `json:"templates,omitempty"`
+	Remove    []corev1.Taint `json:"remove,omitempty"`
+}
+
+// TaintImpact is a pod on the node that does not tolerate an added taint
+type TaintImpact struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Impact    string `json:"impact"` // evicted, blocked
+	Taint     string `json:"taint"`
+	// AfterSeconds is set when the pod tolerates a NoExecute taint for a limited time
+	AfterSeconds *int64 `json:"afterSeconds,omitempty"`
+}
+
+// TaintPreview is a node's taints before and after a change and the pods it affects
+type TaintPreview struct {
+	Cluster  string         `json:"cluster"`
+	Node     string         `json:"node"`
+	Before   []corev1.Taint `json:"before"`
+	After    []corev1.Taint `json:"after"`
+	Impacts  []TaintImpact  `json:"impacts"`
+	Applied  bool           `json:"applied"`
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+// resolve expands the templates into taints to add and validates the change
+func (tc TaintChange) resolve() ([]corev1.Taint, error) {
+	add := append([]corev1.Taint(nil), tc.Add...)
+	for _, name := range tc.Templates {
+		t, ok := TaintTemplates[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown taint template %q", ErrInvalidTaintChange, name)
+		}
+		add = append(add, t)
+	}
+	if len(add) == 0 && len(tc.Remove) == 0 {
+		return nil, fmt.Errorf("%w: no taints to add or remove", ErrInvalidTaintChange)
+	}
+	for _, t := range add {
+		if errs := validation.IsQualifiedName(t.Key); len(errs) > 0 {
+			return nil, fmt.Errorf("%w: invalid taint key %q: %s", ErrInvalidTaintChange, t.Key, strings.Join(errs, "; "))
+		}
+		if t.Value != "" {
+			if errs := validation.IsValidLabelValue(t.Value); len(errs) > 0 {
+				return nil, fmt.Errorf("%w: invalid taint value %q: %s", ErrInvalidTaintChange, t.Value, strings.Join(errs, "; "))
+			}
+		}
+		switch t.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return nil, fmt.Errorf("%w: invalid taint effect %q for %s", ErrInvalidTaintChange, t.Effect, t.Key)
+		}
+	}
+	return add, nil
+}
+
+// applyTaintChange returns the taints with remove dropped and add set. An
+// added taint with the key and effect of an existing one replaces it in place.
+func applyTaintChange(taints, add, remove []corev1.Taint) []corev1.Taint {
+	result := make([]corev1.Taint, 0, len(taints)+len(add))
+	added := make([]bool, len(add))
+	for _, t := range taints {
+		removed := false
+		for _, r := range remove {
+			if r.Key == t.Key && (r.Effect == "" || r.Effect == t.Effect) {
+				removed = true
+				break
+			}
+		}
+		for i := range add {
+			if !added[i] && add[i].MatchTaint(&t) {
+				t, added[i] = add[i], true
+				removed = false
+				break
+			}
+		}
+		if !removed {
+			result = append(result, t)
+		}
+	}
+	for i, a := range add {
+		if !added[i] {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// UpdateNodeTaints previews a taint change on a node, listing the pods running
+// there that would be evicted by an added NoExecute taint or could not be
+// scheduled back because of an added NoSchedule one, and applies it unless
+// dryRun is set
+func (m *MultiClusterClient) UpdateNodeTaints(ctx context.Context, contextName, nodeName string, change TaintChange, dryRun bool) (*TaintPreview, error) {
+	add, err := change.resolve()
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	preview := &TaintPreview{
+		Cluster: contextName,
+		Node:    nodeName,
+		Before:  append([]corev1.Taint{}, node.Spec.Taints...),
+		After:   applyTaintChange(node.Spec.Taints, add, change.Remove),
+		Impacts: make([]TaintImpact, 0),
+	}
+
+	pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+	if err != nil {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("could not list pods on the node: %v", err))
+	} else {
+		preview.Impacts = taintImpacts(pods.Items, nodeName, preview.Before, add)
+	}
+
+	if dryRun {
+		return preview, nil
+	}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current.Spec.Taints = applyTaintChange(current.Spec.Taints, add, change.Remove)
+		_, err = client.CoreV1().Nodes().Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	preview.Applied = true
+	return preview, nil
+}
+
+// taintImpacts lists the pods on the node that do not tolerate the added
+// taints the node does not already carry
+func taintImpacts(pods []corev1.Pod, nodeName string, existing, add []corev1.Taint) []TaintImpact {
+	impacts := make([]TaintImpact, 0)
+	for _, pod := range pods {
+		if pod.Spec.NodeName != nodeName || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for i := range add {
+			taint := &add[i]
+			if taint.Effect == corev1.TaintEffectPreferNoSchedule || hasTaint(existing, taint) {
+				continue
+			}
+			impact := TaintImpact{Pod: pod.Name, Namespace: pod.Namespace, Taint: taint.ToString(), Impact: TaintImpactBlocked}
+			tolerated := false
+			for _, tol := range pod.Spec.Tolerations {
+				if !tol.ToleratesTaint(taint) {
+					continue
+				}
+				tolerated = true
+				if taint.Effect == corev1.TaintEffectNoExecute && tol.TolerationSeconds != nil {
+					seconds := *tol.TolerationSeconds
+					impact.Impact, impact.AfterSeconds = TaintImpactEvicted, &seconds
+					tolerated = false
+				}
+				break
+			}
+			if tolerated {
+				continue
+			}
+			if taint.Effect == corev1.TaintEffectNoExecute {
+				impact.Impact = TaintImpactEvicted
+			}
+			impacts = append(impacts, impact)
+		}
+	}
+	sort.Slice(impacts, func(i, j int) bool {
+		a, b := impacts[i], impacts[j]
+		if a.Impact != b.Impact {
+			return a.Impact == TaintImpactEvicted
+		}
+		return a.Namespace+"/"+a.Pod < b.Namespace+"/"+b.Pod
+	})
+	return impacts
+}
+
+// hasTaint reports whether taints contain one with the key, value and effect of t
+func hasTaint(taints []corev1.Taint, t *corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(t) && taints[i].Value == t.Value {
+			return true
+		}
+	}
+	return false
+}
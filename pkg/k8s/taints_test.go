@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUpdateNodeTaints(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	seconds := int64(300)
+	pod := func(name, nodeName string, tolerations ...corev1.Toleration) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ml"},
+			Spec:       corev1.PodSpec{NodeName: nodeName, Tolerations: tolerations},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-1"},
+			Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "legacy", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "legacy", Effect: corev1.TaintEffectPreferNoSchedule},
+			}},
+		},
+		pod("web", "gpu-1"),
+		pod("trainer", "gpu-1", corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}),
+		pod("drainable", "gpu-1", corev1.Toleration{Key: "nvidia.com/gpu.maintenance", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &seconds}),
+		pod("elsewhere", "cpu-1"),
+	)
+
+	change := TaintChange{
+		Templates: []string{"nvidia-gpu", "gpu-maintenance", "gpu-dedicated"},
+		Remove:    []corev1.Taint{{Key: "legacy"}},
+	}
+	preview, err := m.UpdateNodeTaints(context.Background(), "c1", "gpu-1", change, true)
+	if err != nil {
+		t.Fatalf("UpdateNodeTaints dry run failed: %v", err)
+	}
+	if preview.Applied || len(preview.Before) != 3 || len(preview.After) != 3 {
+		t.Fatalf("Unexpected preview: %+v", preview)
+	}
+	for _, taint := range preview.After {
+		if taint.Key == "legacy" {
+			t.Errorf("Expected legacy taints to be removed, got %v", preview.After)
+		}
+	}
+
+	impacts := make(map[string][]TaintImpact)
+	for _, i := range preview.Impacts {
+		impacts[i.Pod] = append(impacts[i.Pod], i)
+	}
+	// web tolerates nothing: evicted by maintenance, blocked by the GPU taint;
+	// dedicated=gpu was already present so adds no impact
+	if got := impacts["web"]; len(got) != 2 || got[0].Impact != TaintImpactEvicted || got[1].Impact != TaintImpactBlocked {
+		t.Errorf("Unexpected impacts on web: %+v", got)
+	}
+	if got := impacts["trainer"]; len(got) != 1 || got[0].Impact != TaintImpactEvicted {
+		t.Errorf("Expected trainer to be evicted by maintenance only, got %+v", got)
+	}
+	if got := impacts["drainable"]; len(got) != 2 || got[0].AfterSeconds == nil || *got[0].AfterSeconds != seconds {
+		t.Errorf("Expected drainable to be evicted after %ds, got %+v", seconds, got)
+	}
+	if _, ok := impacts["elsewhere"]; ok {
+		t.Error("Expected pods on other nodes to be unaffected")
+	}
+
+	node, _ := m.clients["c1"].CoreV1().Nodes().Get(context.Background(), "gpu-1", metav1.GetOptions{})
+	if len(node.Spec.Taints) != 3 || node.Spec.Taints[1].Key != "legacy" {
+		t.Fatalf("Expected the dry run to leave the node untouched, got %v", node.Spec.Taints)
+	}
+
+	preview, err = m.UpdateNodeTaints(context.Background(), "c1", "gpu-1", change, false)
+	if err != nil || !preview.Applied {
+		t.Fatalf("UpdateNodeTaints failed: %v", err)
+	}
+	node, _ = m.clients["c1"].CoreV1().Nodes().Get(context.Background(), "gpu-1", metav1.GetOptions{})
+	if len(node.Spec.Taints) != 3 || node.Spec.Taints[0].Key != "dedicated" || node.Spec.Taints[2].Key != "nvidia.com/gpu.maintenance" {
+		t.Errorf("Unexpected taints after update: %v", node.Spec.Taints)
+	}
+
+	for _, bad := range []TaintChange{
+		{},
+		{Templates: []string{"unknown"}},
+		{Add: []corev1.Taint{{Key: "example.com/x", Effect: "Sometimes"}}},
+		{Add: []corev1.Taint{{Key: "bad key!", Effect: corev1.TaintEffectNoSchedule}}},
+	} {
+		if _, err := m.UpdateNodeTaints(context.Background(), "c1", "gpu-1", bad, true); !errors.Is(err, ErrInvalidTaintChange) {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// OperationClass groups API calls that share a timeout: listings, reachability
+// and health probes, and log fetches
+type OperationClass string
+
+const (
+	OpList   OperationClass = "list"
+	OpHealth OperationClass = "health"
+	OpLogs   OperationClass = "logs"
+)
+
+const (
+	// AllClustersTimeoutKey is the ClusterPolicy.Timeouts entry applied to every
+	// cluster without its own entry or kubeconfig extension
+	AllClustersTimeoutKey = "*"
+	// TimeoutsExtension is the kubeconfig context extension carrying per-cluster
+	// timeouts, e.g. {"list": "90s", "health": "20s", "logs": "2m"}
+	TimeoutsExtension = "console.kubestellar.io/timeouts"
+)
+
+// ClusterTimeouts overrides the timeout of each operation class; zero keeps the default
+type ClusterTimeouts struct {
+	List   time.Duration
+	Health time.Duration
+	Logs   time.Duration
+}
+
+func (t ClusterTimeouts) get(op OperationClass) time.Duration {
+	switch op {
+	case OpList:
+		return t.List
+	case OpHealth:
+		return t.Health
+	case OpLogs:
+		return t.Logs
+	}
+	return 0
+}
+
+func (t ClusterTimeouts) max() time.Duration {
+	return max(t.List, t.Health, t.Logs)
+}
+
+// ParseClusterTimeouts parses Go duration strings ("45s", "2m"); empty values stay zero
+func ParseClusterTimeouts(list, health, logs string) (ClusterTimeouts, error) {
+	var t ClusterTimeouts
+	for _, f := range []struct {
+		raw string
+		dst *time.Duration
+	}{{list, &t.List}, {health, &t.Health}, {logs, &t.Logs}} {
+		if f.raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(f.raw)
+		if err != nil {
+			return ClusterTimeouts{}, err
+		}
+		if d < 0 {
+			d = 0
+		}
+		*f.dst = d
+	}
+	return t, nil
+}
+
+// extensionTimeouts reads the TimeoutsExtension of a kubeconfig context
+func extensionTimeouts(config *api.Config, contextName string) ClusterTimeouts {
+	if config == nil {
+		return ClusterTimeouts{}
+	}
+	kctx, ok := config.Contexts[contextName]
+	if !ok || kctx == nil {
+		return ClusterTimeouts{}
+	}
+	ext, ok := kctx.Extensions[TimeoutsExtension]
+	if !ok || ext == nil {
+		return ClusterTimeouts{}
+	}
+
+	var raw []byte
+	if u, ok := ext.(*runtime.Unknown); ok {
+		raw = u.Raw
+	} else if b, err := json.Marshal(ext); err == nil {
+		raw = b
+	}
+	var fields struct {
+		List   string `json:"list"`
+		Health string `json:"health"`
+		Logs   string `json:"logs"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		log.Printf("Warning: ignoring %s extension of context %s: %v", TimeoutsExtension, contextName, err)
+		return ClusterTimeouts{}
+	}
+	t, err := ParseClusterTimeouts(fields.List, fields.Health, fields.Logs)
+	if err != nil {
+		log.Printf("Warning: ignoring %s extension of context %s: %v", TimeoutsExtension, contextName, err)
+	}
+	return t
+}
+
+// resolveTimeout picks the most specific configured timeout for one context:
+// its settings entry, then its kubeconfig extension, then the "*" settings entry
+func resolveTimeout(policy ClusterPolicy, config *api.Config, contextName string, op OperationClass) time.Duration {
+	if d := policy.Timeouts[contextName].get(op); d > 0 {
+		return d
+	}
+	if d := extensionTimeouts(config, contextName).get(op); d > 0 {
+		return d
+	}
+	return policy.Timeouts[AllClustersTimeoutKey].get(op)
+}
+
+// OperationTimeout returns how long an op against the context may take, or
+// fallback when nothing is configured. An empty context name returns the longest
+// timeout of any cluster, which is what fan-out requests should wait for.
+func (m *MultiClusterClient) OperationTimeout(contextName string, op OperationClass, fallback time.Duration) time.Duration {
+	m.mu.RLock()
+	source := m.clusterPolicy
+	config := m.rawConfig
+	m.mu.RUnlock()
+
+	var policy ClusterPolicy
+	if source != nil {
+		policy = source()
+	}
+
+	if contextName != "" {
+		if d := resolveTimeout(policy, config, contextName, op); d > 0 {
+			return d
+		}
+		return fallback
+	}
+
+	longest := fallback
+	if d := policy.Timeouts[AllClustersTimeoutKey].get(op); d > 0 {
+		longest = d
+	}
+	for name := range policy.Timeouts {
+		longest = max(longest, policy.Timeouts[name].get(op))
+	}
+	if config != nil {
+		for name := range config.Contexts {
+			longest = max(longest, resolveTimeout(policy, config, name, op))
+		}
+	}
+	return longest
+}
+
+// ClientTimeout is the HTTP timeout of the context's clients: the 45s default, or
+// its longest configured operation timeout when that is higher. It is fixed when
+// the client is created, so raising a timeout past it takes effect on new clients.
+func (m *MultiClusterClient) ClientTimeout(contextName string) time.Duration {
+	m.mu.RLock()
+	source := m.clusterPolicy
+	config := m.rawConfig
+	m.mu.RUnlock()
+	return clientTimeout(source, config, contextName)
+}
+
+func clientTimeout(source func() ClusterPolicy, config *api.Config, contextName string) time.Duration {
+	var policy ClusterPolicy
+	if source != nil {
+		policy = source()
+	}
+	longest := k8sClientTimeout
+	for _, t := range []ClusterTimeouts{
+		policy.Timeouts[contextName],
+		policy.Timeouts[AllClustersTimeoutKey],
+		extensionTimeouts(config, contextName),
+	} {
+		longest = max(longest, t.max())
+	}
+	return longest
+}
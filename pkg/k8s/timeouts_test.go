@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const timeoutsKubeconfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: wan
+  cluster: {server: "https://wan.example.com"}
+- name: kind
+  cluster: {server: "https://127.0.0.1:6443"}
+contexts:
+- name: wan
+  context:
+    cluster: wan
+    extensions:
+    - name: console.kubestellar.io/timeouts
+      extension:
+        list: 90s
+        logs: 2m
+- name: kind
+  context: {cluster: kind}
+current-context: kind
+`
+
+func TestOperationTimeout(t *testing.T) {
+	config, err := clientcmd.Load([]byte(timeoutsKubeconfig))
+	if err != nil {
+		t.Fatalf("Failed to load kubeconfig: %v", err)
+	}
+	m, _ := NewMultiClusterClient("")
+	m.SetRawConfig(config)
+
+	fallback := 30 * time.Second
+	if got := m.OperationTimeout("wan", OpList, fallback); got != 90*time.Second {
+		t.Errorf("Expected the kubeconfig extension's 90s list timeout, got %v", got)
+	}
+	if got := m.OperationTimeout("wan", OpHealth, fallback); got != fallback {
+		t.Errorf("Expected the fallback for an unset class, got %v", got)
+	}
+	if got := m.OperationTimeout("kind", OpList, fallback); got != fallback {
+		t.Errorf("Expected the fallback without configuration, got %v", got)
+	}
+	if got := m.ClientTimeout("wan"); got != 2*time.Minute {
+		t.Errorf("Expected the client timeout to cover the 2m logs timeout, got %v", got)
+	}
+	if got := m.ClientTimeout("kind"); got != k8sClientTimeout {
+		t.Errorf("Expected the default client timeout, got %v", got)
+	}
+
+	// Settings win over the extension, and "*" applies to everything else
+	m.SetClusterPolicySource(func() ClusterPolicy {
+		return ClusterPolicy{Timeouts: map[string]ClusterTimeouts{
+			"wan":                 {List: time.Minute},
+			AllClustersTimeoutKey: {List: 5 * time.Second, Health: 3 * time.Second},
+		}}
+	})
+	if got := m.OperationTimeout("wan", OpList, fallback); got != time.Minute {
+		t.Errorf("Expected the settings timeout to win, got %v", got)
+	}
+	if got := m.OperationTimeout("wan", OpLogs, fallback); got != 2*time.Minute {
+		t.Errorf("Expected the extension to still apply to logs, got %v", got)
+	}
+	if got := m.OperationTimeout("kind", OpList, fallback); got != 5*time.Second {
+		t.Errorf("Expected the \"*\" timeout for a local cluster, got %v", got)
+	}
+
+	// Fan-outs wait for the slowest cluster
+	if got := m.OperationTimeout("", OpList, fallback); got != time.Minute {
+		t.Errorf("Expected the longest list timeout for all clusters, got %v", got)
+	}
+	if got := m.OperationTimeout("", OpHealth, fallback); got != 3*time.Second {
+		t.Errorf("Expected the \"*\" health timeout for all clusters, got %v", got)
+	}
+}
+
+func TestParseClusterTimeouts(t *testing.T) {
+	got, err := ParseClusterTimeouts("90s", "", "2m")
+	if err != nil {
+		t.Fatalf("ParseClusterTimeouts failed: %v", err)
+	}
+	if got != (ClusterTimeouts{List: 90 * time.Second, Logs: 2 * time.Minute}) {
+		t.Errorf("Unexpected timeouts: %+v", got)
+	}
+	if _, err := ParseClusterTimeouts("soon", "", ""); err == nil {
+		t.Error("Expected an error for an invalid duration")
+	}
+}
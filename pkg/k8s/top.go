@@ -0,0 +1,173 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Top consumer metrics
+const (
+	TopMetricCPU      = "cpu"      // millicores
+	TopMetricMemory   = "memory"   // bytes
+	TopMetricRestarts = "restarts" // container restarts
+	TopMetricGPU      = "gpu"      // requested GPUs
+)
+
+// Top consumer sources
+const (
+	TopSourceMetrics  = "metrics-server"
+	TopSourceRequests = "requests"
+	TopSourceStatus   = "status"
+)
+
+// podMetricsGVR is the metrics-server resource holding live pod usage
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// TopConsumer is a pod, or a namespace when Name is empty, ranked by a metric
+type TopConsumer struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	Value     int64  `json:"value"`
+	Source    string `json:"source"` // metrics-server, requests, status
+}
+
+// IsTopMetric reports whether metric is one GetTopConsumers can rank by
+func IsTopMetric(metric string) bool {
+	switch metric {
+	case TopMetricCPU, TopMetricMemory, TopMetricRestarts, TopMetricGPU:
+		return true
+	}
+	return false
+}
+
+// GetTopConsumers returns the limit pods, or namespaces when byNamespace is
+// set, with the highest value of metric. CPU and memory come from
+// metrics-server when it serves the cluster and from pod requests otherwise.
+func (m *MultiClusterClient) GetTopConsumers(ctx context.Context, contextName, metric string, byNamespace bool, limit int) ([]TopConsumer, error) {
+	if !IsTopMetric(metric) {
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+
+	var consumers []TopConsumer
+	if metric == TopMetricCPU || metric == TopMetricMemory {
+		consumers = m.podUsage(ctx, contextName, metric)
+	}
+	if consumers == nil {
+		snap, err := m.getNodePodSnapshot(ctx, contextName)
+		if err != nil {
+			return nil, err
+		}
+		if snap.podsErr != nil {
+			return nil, snap.podsErr
+		}
+		consumers = podStatusConsumers(snap.pods.Items, contextName, metric)
+	}
+
+	if byNamespace {
+		totals := make(map[string]*TopConsumer)
+		for _, c := range consumers {
+			t, ok := totals[c.Namespace]
+			if !ok {
+				t = &TopConsumer{Namespace: c.Namespace, Cluster: contextName, Source: c.Source}
+				totals[c.Namespace] = t
+			}
+			t.Value += c.Value
+		}
+		consumers = make([]TopConsumer, 0, len(totals))
+		for _, t := range totals {
+			consumers = append(consumers, *t)
+		}
+	}
+	return RankTopConsumers(consumers, limit), nil
+}
+
+// RankTopConsumers sorts consumers by descending value, dropping those with
+// none, and keeps the first limit
+func RankTopConsumers(consumers []TopConsumer, limit int) []TopConsumer {
+	ranked := make([]TopConsumer, 0, len(consumers))
+	for _, c := range consumers {
+		if c.Value > 0 {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Value != b.Value {
+			return a.Value > b.Value
+		}
+		return a.Cluster+"/"+a.Namespace+"/"+a.Name < b.Cluster+"/"+b.Namespace+"/"+b.Name
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// podUsage returns the live CPU or memory usage of every pod from
+// metrics-server, or nil when it is not installed or not reachable
+func (m *MultiClusterClient) podUsage(ctx context.Context, contextName, metric string) []TopConsumer {
+	dynClient, err := m.GetDynamicClient(contextName)
+	if err != nil {
+		return nil
+	}
+	list, err := dynClient.Resource(podMetricsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil || len(list.Items) == 0 {
+		return nil
+	}
+	consumers := make([]TopConsumer, 0, len(list.Items))
+	for _, item := range list.Items {
+		c := TopConsumer{Name: item.GetName(), Namespace: item.GetNamespace(), Cluster: contextName, Source: TopSourceMetrics}
+		containers, _ := item.Object["containers"].([]interface{})
+		for _, raw := range containers {
+			container, _ := raw.(map[string]interface{})
+			usage, _ := container["usage"].(map[string]interface{})
+			value, _ := usage[metric].(string)
+			qty, err := resource.ParseQuantity(value)
+			if err != nil {
+				continue
+			}
+			if metric == TopMetricCPU {
+				c.Value += qty.MilliValue()
+			} else {
+				c.Value += qty.Value()
+			}
+		}
+		consumers = append(consumers, c)
+	}
+	return consumers
+}
+
+// podStatusConsumers values each running or pending pod by its requests or,
+// for restarts, its container restart count
+func podStatusConsumers(pods []corev1.Pod, contextName, metric string) []TopConsumer {
+	consumers := make([]TopConsumer, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		c := TopConsumer{Name: pod.Name, Namespace: pod.Namespace, Cluster: contextName, Source: TopSourceRequests}
+		switch metric {
+		case TopMetricCPU:
+			c.Value = podResourceRequests(pod).CPUMillicores
+		case TopMetricMemory:
+			c.Value = podResourceRequests(pod).MemoryBytes
+		case TopMetricGPU:
+			c.Value = podResourceRequests(pod).GPUs
+		case TopMetricRestarts:
+			c.Source = TopSourceStatus
+			for _, cs := range pod.Status.ContainerStatuses {
+				c.Value += int64(cs.RestartCount)
+			}
+		}
+		consumers = append(consumers, c)
+	}
+	return consumers
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetTopConsumers(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	pod := func(name, namespace, cpu string, restarts int32, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			}}}},
+			Status: corev1.PodStatus{Phase: phase, ContainerStatuses: []corev1.ContainerStatus{{Name: "main", RestartCount: restarts}}},
+		}
+	}
+	m.clients["c1"] = k8sfake.NewSimpleClientset(
+		pod("a", "team-a", "500m", 3, corev1.PodRunning),
+		pod("b", "team-a", "250m", 0, corev1.PodRunning),
+		pod("c", "team-b", "1", 10, corev1.PodRunning),
+		pod("done", "team-b", "4", 0, corev1.PodSucceeded),
+	)
+
+	// Without metrics-server, CPU is ranked by requests
+	top, err := m.GetTopConsumers(context.Background(), "c1", TopMetricCPU, false, 2)
+	if err != nil {
+		t.Fatalf("GetTopConsumers failed: %v", err)
+	}
+	if len(top) != 2 || top[0].Name != "c" || top[0].Value != 1000 || top[1].Name != "a" || top[0].Source != TopSourceRequests {
+		t.Errorf("Unexpected top CPU pods: %+v", top)
+	}
+
+	top, err = m.GetTopConsumers(context.Background(), "c1", TopMetricRestarts, true, 10)
+	if err != nil {
+		t.Fatalf("GetTopConsumers failed: %v", err)
+	}
+	if len(top) != 2 || top[0].Namespace != "team-b" || top[0].Value != 10 || top[1].Value != 3 || top[0].Name != "" {
+		t.Errorf("Unexpected top restart namespaces: %+v", top)
+	}
+
+	if _, err := m.GetTopConsumers(context.Background(), "c1", "disk", false, 10); err == nil {
+		t.Error("Expected an error for an unknown metric")
+	}
+
+	// metrics-server usage takes precedence over requests
+	podMetrics := func(name, namespace, cpu, memory string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "PodMetrics",
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			"containers": []interface{}{
+				map[string]interface{}{"name": "main", "usage": map[string]interface{}{"cpu": cpu, "memory": memory}},
+				map[string]interface{}{"name": "sidecar", "usage": map[string]interface{}{"cpu": "10m", "memory": "1Mi"}},
+			},
+		}}
+	}
+	// PodMetrics does not pluralize to its resource name, so the objects are
+	// created through the resource rather than seeded into the tracker
+	fakeDyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podMetricsGVR: "PodMetricsList"})
+	for _, pm := range []*unstructured.Unstructured{podMetrics("a", "team-a", "90m", "64Mi"), podMetrics("c", "team-b", "5m", "1Gi")} {
+		if _, err := fakeDyn.Resource(podMetricsGVR).Namespace(pm.GetNamespace()).Create(context.Background(), pm, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Create PodMetrics failed: %v", err)
+		}
+	}
+	m.dynamicClients["c1"] = fakeDyn
+
+	top, err = m.GetTopConsumers(context.Background(), "c1", TopMetricCPU, false, 10)
+	if err != nil {
+		t.Fatalf("GetTopConsumers failed: %v", err)
+	}
+	if len(top) != 2 || top[0].Name != "a" || top[0].Value != 100 || top[0].Source != TopSourceMetrics {
+		t.Errorf("Unexpected top CPU pods from metrics-server: %+v", top)
+	}
+	top, err = m.GetTopConsumers(context.Background(), "c1", TopMetricMemory, true, 1)
+	if err != nil {
+		t.Fatalf("GetTopConsumers failed: %v", err)
+	}
+	if len(top) != 1 || top[0].Namespace != "team-b" || top[0].Value != (1<<30)+(1<<20) {
+		t.Errorf("Unexpected top memory namespace: %+v", top)
+	}
+}
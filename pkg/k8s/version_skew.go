@@ -0,0 +1,265 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// eolWarningWindow is how far ahead of end-of-life a Kubernetes version is flagged
+const eolWarningWindow = 90 * 24 * time.Hour
+
+// kubernetesEOL maps a Kubernetes minor version to its upstream end-of-life
+// date. Managed providers may offer extended support beyond these dates.
+var kubernetesEOL = map[uint]string{
+	24: "2023-07-28",
+	25: "2023-10-28",
+	26: "2024-02-28",
+	27: "2024-06-28",
+	28: "2024-10-28",
+	29: "2025-02-28",
+	30: "2025-06-28",
+	31: "2025-10-28",
+	32: "2026-02-28",
+	33: "2026-06-28",
+	34: "2026-10-27",
+	35: "2027-02-28",
+}
+
+// oldestTrackedMinor is the oldest minor in kubernetesEOL; anything older is long past EOL
+const oldestTrackedMinor = 24
+
+// VersionIssue is a version skew or end-of-life finding
+type VersionIssue struct {
+	Cluster  string   `json:"cluster,omitempty"` // empty for fleet-wide findings
+	Severity string   `json:"severity"`          // info, warning, critical
+	Type     string   `json:"type"`              // kubelet-skew, end-of-life, runtime-mixed, gpu-driver-mixed, fleet-skew
+	Message  string   `json:"message"`
+	Nodes    []string `json:"nodes,omitempty"`
+}
+
+// ClusterVersions summarizes the component versions running in a cluster.
+// Version maps count nodes per version.
+type ClusterVersions struct {
+	Cluster      string         `json:"cluster"`
+	ControlPlane string         `json:"controlPlane,omitempty"`
+	EndOfLife    string         `json:"endOfLife,omitempty"` // upstream EOL date of the control plane minor
+	Kubelets     map[string]int `json:"kubelets,omitempty"`
+	Runtimes     map[string]int `json:"runtimes,omitempty"`
+	GPUDrivers   map[string]int `json:"gpuDrivers,omitempty"` // e.g. "NVIDIA 550.54.15"
+	CUDAVersions map[string]int `json:"cudaVersions,omitempty"`
+	Issues       []VersionIssue `json:"issues,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// FleetVersionReport aggregates component versions across clusters
+type FleetVersionReport struct {
+	Clusters []ClusterVersions `json:"clusters"`
+	// ControlPlanes counts clusters per Kubernetes minor, e.g. "1.30"
+	ControlPlanes map[string]int `json:"controlPlanes"`
+	Issues        []VersionIssue `json:"issues"`
+	CheckedAt     string         `json:"checkedAt"`
+}
+
+// GetClusterVersions collects the control-plane, kubelet, container runtime and
+// GPU driver versions of a cluster and flags skew outside the supported ranges
+func (m *MultiClusterClient) GetClusterVersions(ctx context.Context, contextName string) (*ClusterVersions, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	nodes, err := m.GetNodes(ctx, contextName)
+	if err != nil {
+		return nil, err
+	}
+	// GPU details are optional; clusters without GPUs or GFD labels just have none
+	gpuNodes, _ := m.GetGPUNodes(ctx, contextName)
+
+	cv := &ClusterVersions{
+		Cluster:      contextName,
+		ControlPlane: info.GitVersion,
+		Kubelets:     make(map[string]int),
+		Runtimes:     make(map[string]int),
+	}
+	kubeletNodes := make(map[string][]string)
+	for _, n := range nodes {
+		cv.Kubelets[n.KubeletVersion]++
+		kubeletNodes[n.KubeletVersion] = append(kubeletNodes[n.KubeletVersion], n.Name)
+		if n.ContainerRuntime != "" {
+			cv.Runtimes[n.ContainerRuntime]++
+		}
+	}
+	for _, n := range gpuNodes {
+		if driver := gpuDriverVersion(n); driver != "" {
+			if cv.GPUDrivers == nil {
+				cv.GPUDrivers = make(map[string]int)
+			}
+			cv.GPUDrivers[driver]++
+		}
+		if n.CUDARuntimeVersion != "" {
+			if cv.CUDAVersions == nil {
+				cv.CUDAVersions = make(map[string]int)
+			}
+			cv.CUDAVersions[n.CUDARuntimeVersion]++
+		}
+	}
+	cv.Issues = clusterVersionIssues(cv, kubeletNodes, time.Now())
+	if v, err := utilversion.ParseGeneric(cv.ControlPlane); err == nil {
+		cv.EndOfLife = kubernetesEOL[v.Minor()]
+	}
+	return cv, nil
+}
+
+// gpuDriverVersion returns the vendor and driver version of a GPU node
+func gpuDriverVersion(n GPUNode) string {
+	switch {
+	case n.CUDADriverVersion != "":
+		return "NVIDIA " + n.CUDADriverVersion
+	case n.ROCmVersion != "":
+		return "AMD " + n.ROCmVersion
+	}
+	return ""
+}
+
+// maxKubeletSkew returns how many minors a kubelet may trail the API server;
+// the policy widened from two to three minors in Kubernetes 1.28
+func maxKubeletSkew(apiMinor uint) uint {
+	if apiMinor >= 28 {
+		return 3
+	}
+	return 2
+}
+
+// clusterVersionIssues applies the Kubernetes version skew policy and EOL dates
+// to a cluster. kubeletNodes maps kubelet versions to node names.
+func clusterVersionIssues(cv *ClusterVersions, kubeletNodes map[string][]string, now time.Time) []VersionIssue {
+	var issues []VersionIssue
+	api, err := utilversion.ParseGeneric(cv.ControlPlane)
+	if err != nil {
+		return nil
+	}
+
+	for _, kubelet := range sortedKeys(cv.Kubelets) {
+		kv, err := utilversion.ParseGeneric(kubelet)
+		if err != nil || kv.Major() != api.Major() {
+			continue
+		}
+		nodes := kubeletNodes[kubelet]
+		sort.Strings(nodes)
+		switch behind := int(api.Minor()) - int(kv.Minor()); {
+		case behind < 0:
+			issues = append(issues, VersionIssue{
+				Cluster: cv.Cluster, Severity: "critical", Type: "kubelet-skew", Nodes: nodes,
+				Message: fmt.Sprintf("kubelet %s is newer than the control plane %s, which is unsupported", kubelet, cv.ControlPlane),
+			})
+		case uint(behind) > maxKubeletSkew(api.Minor()):
+			issues = append(issues, VersionIssue{
+				Cluster: cv.Cluster, Severity: "critical", Type: "kubelet-skew", Nodes: nodes,
+				Message: fmt.Sprintf("kubelet %s is %d minor versions behind the control plane %s (at most %d supported)", kubelet, behind, cv.ControlPlane, maxKubeletSkew(api.Minor())),
+			})
+		case uint(behind) == maxKubeletSkew(api.Minor()):
+			issues = append(issues, VersionIssue{
+				Cluster: cv.Cluster, Severity: "warning", Type: "kubelet-skew", Nodes: nodes,
+				Message: fmt.Sprintf("kubelet %s is at the maximum supported skew from the control plane %s; upgrade nodes before the next control plane upgrade", kubelet, cv.ControlPlane),
+			})
+		}
+	}
+
+	if issue := eolIssue(api, now); issue != nil {
+		issue.Cluster = cv.Cluster
+		issues = append(issues, *issue)
+	}
+
+	if len(cv.Runtimes) > 1 {
+		issues = append(issues, VersionIssue{
+			Cluster: cv.Cluster, Severity: "info", Type: "runtime-mixed",
+			Message: fmt.Sprintf("nodes run %d container runtime versions: %s", len(cv.Runtimes), strings.Join(sortedKeys(cv.Runtimes), ", ")),
+		})
+	}
+	if len(cv.GPUDrivers) > 1 {
+		issues = append(issues, VersionIssue{
+			Cluster: cv.Cluster, Severity: "warning", Type: "gpu-driver-mixed",
+			Message: fmt.Sprintf("GPU nodes run %d driver versions: %s", len(cv.GPUDrivers), strings.Join(sortedKeys(cv.GPUDrivers), ", ")),
+		})
+	}
+	return issues
+}
+
+// eolIssue flags a Kubernetes version that is past or approaching end-of-life
+func eolIssue(v *utilversion.Version, now time.Time) *VersionIssue {
+	minor := fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+	if v.Major() == 1 && v.Minor() < oldestTrackedMinor {
+		return &VersionIssue{Severity: "critical", Type: "end-of-life", Message: fmt.Sprintf("Kubernetes %s is past end-of-life", minor)}
+	}
+	date, ok := kubernetesEOL[v.Minor()]
+	if !ok || v.Major() != 1 {
+		return nil
+	}
+	eol, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case !now.Before(eol):
+		return &VersionIssue{Severity: "critical", Type: "end-of-life", Message: fmt.Sprintf("Kubernetes %s reached end-of-life on %s", minor, date)}
+	case eol.Sub(now) <= eolWarningWindow:
+		days := int(eol.Sub(now).Hours() / 24)
+		return &VersionIssue{Severity: "warning", Type: "end-of-life", Message: fmt.Sprintf("Kubernetes %s reaches end-of-life on %s (%d days)", minor, date, days)}
+	}
+	return nil
+}
+
+// NewFleetVersionReport combines per-cluster versions and flags control planes
+// that have drifted apart across the fleet
+func NewFleetVersionReport(clusters []ClusterVersions, now time.Time) *FleetVersionReport {
+	report := &FleetVersionReport{
+		Clusters:      clusters,
+		ControlPlanes: make(map[string]int),
+		Issues:        []VersionIssue{},
+		CheckedAt:     now.UTC().Format(time.RFC3339),
+	}
+	sort.Slice(report.Clusters, func(i, j int) bool { return report.Clusters[i].Cluster < report.Clusters[j].Cluster })
+
+	var lowest, highest *utilversion.Version
+	var lowestCluster, highestCluster string
+	for _, cv := range report.Clusters {
+		report.Issues = append(report.Issues, cv.Issues...)
+		v, err := utilversion.ParseGeneric(cv.ControlPlane)
+		if err != nil {
+			continue
+		}
+		report.ControlPlanes[fmt.Sprintf("%d.%d", v.Major(), v.Minor())]++
+		if lowest == nil || v.LessThan(lowest) {
+			lowest, lowestCluster = v, cv.Cluster
+		}
+		if highest == nil || highest.LessThan(v) {
+			highest, highestCluster = v, cv.Cluster
+		}
+	}
+	if lowest != nil && highest.Minor()-lowest.Minor() > 2 {
+		report.Issues = append(report.Issues, VersionIssue{
+			Severity: "warning", Type: "fleet-skew",
+			Message: fmt.Sprintf("control planes span %d minor versions, from %s (%s) to %s (%s)",
+				highest.Minor()-lowest.Minor(), lowest.String(), lowestCluster, highest.String(), highestCluster),
+		})
+	}
+	return report
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
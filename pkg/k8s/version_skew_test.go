@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetClusterVersions(t *testing.T) {
+	node := func(name, kubelet, runtime string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+				KubeletVersion:          kubelet,
+				ContainerRuntimeVersion: runtime,
+			}},
+		}
+	}
+	client := k8sfake.NewSimpleClientset(
+		node("cp-1", "v1.31.2", "containerd://1.7.13"),
+		node("worker-1", "v1.28.9", "containerd://1.7.13"),
+		node("worker-2", "v1.27.3", "containerd://1.6.28"),
+		node("worker-3", "v1.32.0", "containerd://1.7.13"),
+	)
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.31.2-eks-7f9249a"}
+
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", client)
+
+	cv, err := m.GetClusterVersions(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetClusterVersions: %v", err)
+	}
+	if cv.Kubelets["v1.31.2"] != 1 || len(cv.Kubelets) != 4 {
+		t.Errorf("Expected node counts per kubelet version, got %v", cv.Kubelets)
+	}
+	if cv.EndOfLife != "2025-10-28" {
+		t.Errorf("Expected the 1.31 end-of-life date, got %q", cv.EndOfLife)
+	}
+
+	byNode := make(map[string]VersionIssue)
+	for _, issue := range cv.Issues {
+		for _, n := range issue.Nodes {
+			byNode[n] = issue
+		}
+	}
+	if byNode["worker-1"].Severity != "warning" {
+		t.Errorf("Expected a kubelet three minors behind to warn, got %+v", byNode["worker-1"])
+	}
+	if byNode["worker-2"].Severity != "critical" {
+		t.Errorf("Expected a kubelet four minors behind to be critical, got %+v", byNode["worker-2"])
+	}
+	if issue := byNode["worker-3"]; issue.Severity != "critical" || !strings.Contains(issue.Message, "newer") {
+		t.Errorf("Expected a kubelet newer than the API server to be critical, got %+v", issue)
+	}
+	if _, ok := byNode["cp-1"]; ok {
+		t.Error("Expected no issue for a kubelet matching the control plane")
+	}
+}
+
+func TestEOLIssue(t *testing.T) {
+	now := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	cv := &ClusterVersions{Cluster: "c1", ControlPlane: "v1.34.1"}
+	issues := clusterVersionIssues(cv, nil, now)
+	if len(issues) != 1 || issues[0].Type != "end-of-life" || issues[0].Severity != "warning" {
+		t.Fatalf("Expected an approaching end-of-life warning, got %+v", issues)
+	}
+
+	cv.ControlPlane = "v1.21.0"
+	if issues := clusterVersionIssues(cv, nil, now); len(issues) != 1 || issues[0].Severity != "critical" {
+		t.Errorf("Expected versions older than the table to be past end-of-life, got %+v", issues)
+	}
+
+	cv.ControlPlane = "v1.35.0"
+	if issues := clusterVersionIssues(cv, nil, now); len(issues) != 0 {
+		t.Errorf("Expected no issues for a supported version, got %+v", issues)
+	}
+}
+
+func TestNewFleetVersionReport(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := NewFleetVersionReport([]ClusterVersions{
+		{Cluster: "prod", ControlPlane: "v1.33.1"},
+		{Cluster: "legacy", ControlPlane: "v1.29.4"},
+		{Cluster: "offline", Error: "cluster unreachable"},
+	}, now)
+
+	if report.ControlPlanes["1.33"] != 1 || report.ControlPlanes["1.29"] != 1 {
+		t.Errorf("Expected clusters counted per minor, got %v", report.ControlPlanes)
+	}
+	if report.Clusters[0].Cluster != "legacy" {
+		t.Errorf("Expected clusters sorted by name, got %s first", report.Clusters[0].Cluster)
+	}
+	found := false
+	for _, issue := range report.Issues {
+		if issue.Type == "fleet-skew" {
+			found = strings.Contains(issue.Message, "legacy") && strings.Contains(issue.Message, "prod")
+		}
+	}
+	if !found {
+		t.Errorf("Expected a fleet skew finding naming both clusters, got %+v", report.Issues)
+	}
+}
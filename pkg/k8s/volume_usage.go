@@ -0,0 +1,175 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Volumes at or above these percentages full are flagged as storage issues
+	volumeUsageWarnPercent     = 80
+	volumeUsageCriticalPercent = 90
+
+	// kubeletSummaryConcurrency bounds parallel stats/summary requests proxied through the API server
+	kubeletSummaryConcurrency = 8
+)
+
+// VolumeUsage is the filesystem usage of a PVC-backed volume as reported by
+// the kubelet of a node mounting it
+type VolumeUsage struct {
+	PVC               string  `json:"pvc"`
+	Namespace         string  `json:"namespace"`
+	Cluster           string  `json:"cluster,omitempty"`
+	Pod               string  `json:"pod"`
+	Node              string  `json:"node"`
+	CapacityBytes     int64   `json:"capacityBytes"`
+	UsedBytes         int64   `json:"usedBytes"`
+	AvailableBytes    int64   `json:"availableBytes"`
+	UsedPercent       float64 `json:"usedPercent"`
+	InodesUsedPercent float64 `json:"inodesUsedPercent,omitempty"`
+	Issue             string  `json:"issue,omitempty"`
+	Severity          string  `json:"severity,omitempty"` // high, medium
+}
+
+// kubeletSummary is the subset of the kubelet stats/summary response carrying
+// per-pod volume stats, including CSI volumes that report metrics
+type kubeletSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Volume []struct {
+			PVCRef *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef,omitempty"`
+			CapacityBytes  *int64 `json:"capacityBytes,omitempty"`
+			UsedBytes      *int64 `json:"usedBytes,omitempty"`
+			AvailableBytes *int64 `json:"availableBytes,omitempty"`
+			Inodes         *int64 `json:"inodes,omitempty"`
+			InodesUsed     *int64 `json:"inodesUsed,omitempty"`
+		} `json:"volume,omitempty"`
+	} `json:"pods"`
+}
+
+// GetVolumeUsage collects actual filesystem usage of PVC-backed volumes from
+// each node's kubelet stats/summary endpoint, flagging volumes above 80% full
+// as medium and above 90% as high severity storage issues. Only mounted
+// volumes report usage; a claim mounted by several pods is listed once.
+// Results are sorted fullest first.
+func (m *MultiClusterClient) GetVolumeUsage(ctx context.Context, contextName, namespace string) ([]VolumeUsage, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rest := client.Discovery().RESTClient()
+	if rest == nil {
+		return []VolumeUsage{}, nil
+	}
+
+	perNode := make([][]VolumeUsage, len(nodes.Items))
+	sem := make(chan struct{}, kubeletSummaryConcurrency)
+	var wg sync.WaitGroup
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !isNodeReady(node) {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			body, err := rest.Get().AbsPath("/api/v1/nodes", node.Name, "proxy/stats/summary").DoRaw(ctx)
+			if err != nil {
+				// An unreachable kubelet only hides that node's volumes
+				return
+			}
+			perNode[i], _ = volumeUsageFromSummary(body, contextName, node.Name, namespace)
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	result := make([]VolumeUsage, 0)
+	for _, volumes := range perNode {
+		for _, v := range volumes {
+			key := v.Namespace + "/" + v.PVC
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, v)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UsedPercent > result[j].UsedPercent
+	})
+	return result, nil
+}
+
+// volumeUsageFromSummary extracts the PVC-backed volumes of a kubelet
+// stats/summary response, optionally limited to one namespace
+func volumeUsageFromSummary(body []byte, contextName, nodeName, namespace string) ([]VolumeUsage, error) {
+	var summary kubeletSummary
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return nil, err
+	}
+	var result []VolumeUsage
+	for _, pod := range summary.Pods {
+		for _, vol := range pod.Volume {
+			if vol.PVCRef == nil || vol.CapacityBytes == nil || *vol.CapacityBytes == 0 {
+				continue
+			}
+			if namespace != "" && vol.PVCRef.Namespace != namespace {
+				continue
+			}
+			usage := VolumeUsage{
+				PVC:           vol.PVCRef.Name,
+				Namespace:     vol.PVCRef.Namespace,
+				Cluster:       contextName,
+				Pod:           pod.PodRef.Name,
+				Node:          nodeName,
+				CapacityBytes: *vol.CapacityBytes,
+			}
+			if vol.UsedBytes != nil {
+				usage.UsedBytes = *vol.UsedBytes
+			}
+			if vol.AvailableBytes != nil {
+				usage.AvailableBytes = *vol.AvailableBytes
+			}
+			usage.UsedPercent = percentOf(usage.UsedBytes, usage.CapacityBytes)
+			if vol.Inodes != nil && vol.InodesUsed != nil && *vol.Inodes > 0 {
+				usage.InodesUsedPercent = percentOf(*vol.InodesUsed, *vol.Inodes)
+			}
+			// Running out of inodes fills a volume as surely as running out of bytes
+			full := max(usage.UsedPercent, usage.InodesUsedPercent)
+			switch {
+			case full >= volumeUsageCriticalPercent:
+				usage.Severity = "high"
+			case full >= volumeUsageWarnPercent:
+				usage.Severity = "medium"
+			}
+			if usage.Severity != "" {
+				usage.Issue = fmt.Sprintf("Volume %.0f%% full", full)
+			}
+			result = append(result, usage)
+		}
+	}
+	return result, nil
+}
+
+// percentOf returns part as a percentage of total, rounded to one decimal
+func percentOf(part, total int64) float64 {
+	return float64(part*1000/total) / 10
+}
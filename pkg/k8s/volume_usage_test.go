@@ -0,0 +1,43 @@
+package k8s
+
+import "testing"
+
+func TestVolumeUsageFromSummary(t *testing.T) {
+	summary := `{
+		"node": {"nodeName": "node-1"},
+		"pods": [
+			{"podRef": {"name": "db-0", "namespace": "prod"}, "volume": [
+				{"name": "data", "pvcRef": {"name": "data-db-0", "namespace": "prod"}, "capacityBytes": 1000, "usedBytes": 950, "availableBytes": 50, "inodes": 100, "inodesUsed": 10},
+				{"name": "kube-api-access", "capacityBytes": 1000, "usedBytes": 1000}
+			]},
+			{"podRef": {"name": "cache-0", "namespace": "prod"}, "volume": [
+				{"name": "data", "pvcRef": {"name": "data-cache-0", "namespace": "prod"}, "capacityBytes": 1000, "usedBytes": 100, "inodes": 100, "inodesUsed": 85}
+			]},
+			{"podRef": {"name": "web-0", "namespace": "dev"}, "volume": [
+				{"name": "data", "pvcRef": {"name": "data-web-0", "namespace": "dev"}, "capacityBytes": 1000, "usedBytes": 10}
+			]}
+		]
+	}`
+
+	volumes, err := volumeUsageFromSummary([]byte(summary), "c1", "node-1", "")
+	if err != nil {
+		t.Fatalf("volumeUsageFromSummary failed: %v", err)
+	}
+	if len(volumes) != 3 {
+		t.Fatalf("Expected only the 3 PVC-backed volumes, got %+v", volumes)
+	}
+	if v := volumes[0]; v.PVC != "data-db-0" || v.Pod != "db-0" || v.Node != "node-1" || v.UsedPercent != 95 || v.Severity != "high" || v.Issue != "Volume 95% full" {
+		t.Errorf("Unexpected usage for nearly full volume: %+v", v)
+	}
+	if v := volumes[1]; v.InodesUsedPercent != 85 || v.Severity != "medium" {
+		t.Errorf("Expected inode exhaustion to be flagged, got %+v", v)
+	}
+	if v := volumes[2]; v.Severity != "" || v.Issue != "" {
+		t.Errorf("Expected no issue for a mostly empty volume, got %+v", v)
+	}
+
+	volumes, _ = volumeUsageFromSummary([]byte(summary), "c1", "node-1", "dev")
+	if len(volumes) != 1 || volumes[0].PVC != "data-web-0" {
+		t.Errorf("Expected namespace filter to keep only data-web-0, got %+v", volumes)
+	}
+}
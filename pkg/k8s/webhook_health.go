@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// webhookCanaryNamespace is where the dry-run canary pod is submitted
+	webhookCanaryNamespace = "default"
+	// webhookCanaryImage is the image of the dry-run canary pod; it is never pulled
+	webhookCanaryImage = "registry.k8s.io/pause:3.10"
+	// slowWebhookThreshold is the canary latency above which admission is reported as slow
+	slowWebhookThreshold = 2 * time.Second
+)
+
+// failedWebhookPattern extracts the webhook name from an API server admission error
+var failedWebhookPattern = regexp.MustCompile(`failed calling webhook "([^"]+)"`)
+
+// WebhookHealth is the health of one webhook of a validating or mutating configuration
+type WebhookHealth struct {
+	Name          string   `json:"name"`
+	Configuration string   `json:"configuration"`
+	Type          string   `json:"type"` // validating, mutating
+	Cluster       string   `json:"cluster"`
+	FailurePolicy string   `json:"failurePolicy"`
+	Service       string   `json:"service,omitempty"` // namespace/name
+	URL           string   `json:"url,omitempty"`
+	Healthy       bool     `json:"healthy"`
+	Issues        []string `json:"issues,omitempty"`
+}
+
+// WebhookCanary is the outcome of a dry-run pod creation sent through admission
+type WebhookCanary struct {
+	LatencyMs     int64  `json:"latencyMs"`
+	Slow          bool   `json:"slow"`
+	Error         string `json:"error,omitempty"`
+	FailedWebhook string `json:"failedWebhook,omitempty"`
+}
+
+// WebhookHealthReport is the admission webhook health of a cluster
+type WebhookHealthReport struct {
+	Cluster  string          `json:"cluster"`
+	Webhooks []WebhookHealth `json:"webhooks"`
+	Canary   *WebhookCanary  `json:"canary,omitempty"`
+}
+
+// CheckWebhookHealth verifies that every admission webhook's backing service
+// exists and has ready endpoints and, when canary is set, times a dry-run pod
+// creation through admission, attributing a failure to the webhook named in
+// the API server's error
+func (m *MultiClusterClient) CheckWebhookHealth(ctx context.Context, contextName string, canary bool) (*WebhookHealthReport, error) {
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+	webhooks, err := listWebhooks(ctx, client, contextName)
+	if err != nil {
+		return nil, err
+	}
+	report := &WebhookHealthReport{Cluster: contextName, Webhooks: webhooks}
+	if !canary {
+		return report, nil
+	}
+
+	report.Canary = runWebhookCanary(ctx, client)
+	if report.Canary.FailedWebhook != "" {
+		for i := range report.Webhooks {
+			if report.Webhooks[i].Name == report.Canary.FailedWebhook {
+				report.Webhooks[i].Healthy = false
+				report.Webhooks[i].Issues = append(report.Webhooks[i].Issues, "canary request failed: "+report.Canary.Error)
+			}
+		}
+	}
+	return report, nil
+}
+
+// listWebhooks lists the webhooks of all validating and mutating configurations
+// and checks their backing services
+func listWebhooks(ctx context.Context, client kubernetes.Interface, contextName string) ([]WebhookHealth, error) {
+	admission := client.AdmissionregistrationV1()
+	validating, err := admission.ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	mutating, err := admission.MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]WebhookHealth, 0)
+	add := func(configuration, whType, name string, cc admissionv1.WebhookClientConfig, policy *admissionv1.FailurePolicyType) {
+		wh := WebhookHealth{
+			Name:          name,
+			Configuration: configuration,
+			Type:          whType,
+			Cluster:       contextName,
+			FailurePolicy: string(admissionv1.Fail),
+			Healthy:       true,
+		}
+		if policy != nil {
+			wh.FailurePolicy = string(*policy)
+		}
+		if cc.URL != nil {
+			wh.URL = *cc.URL
+		}
+		if svc := cc.Service; svc != nil {
+			wh.Service = svc.Namespace + "/" + svc.Name
+			if issue := checkWebhookService(ctx, client, svc); issue != "" {
+				wh.Healthy = false
+				wh.Issues = append(wh.Issues, issue)
+			}
+		}
+		result = append(result, wh)
+	}
+	for _, cfg := range validating.Items {
+		for _, wh := range cfg.Webhooks {
+			add(cfg.Name, "validating", wh.Name, wh.ClientConfig, wh.FailurePolicy)
+		}
+	}
+	for _, cfg := range mutating.Items {
+		for _, wh := range cfg.Webhooks {
+			add(cfg.Name, "mutating", wh.Name, wh.ClientConfig, wh.FailurePolicy)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Healthy != result[j].Healthy {
+			return !result[i].Healthy
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// checkWebhookService returns why a webhook's service cannot serve requests, or ""
+func checkWebhookService(ctx context.Context, client kubernetes.Interface, ref *admissionv1.ServiceReference) string {
+	svc, err := client.CoreV1().Services(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("service %s/%s not found", ref.Namespace, ref.Name)
+	}
+	if err != nil {
+		return fmt.Sprintf("service %s/%s not readable: %v", ref.Namespace, ref.Name, err)
+	}
+	// ExternalName services resolve outside the cluster and have no endpoints
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return ""
+	}
+	endpointSlices, err := client.DiscoveryV1().EndpointSlices(ref.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + ref.Name,
+	})
+	if err != nil {
+		return fmt.Sprintf("endpoints of %s/%s not readable: %v", ref.Namespace, ref.Name, err)
+	}
+	for _, slice := range endpointSlices.Items {
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				return ""
+			}
+		}
+	}
+	return fmt.Sprintf("service %s/%s has no ready endpoints", ref.Namespace, ref.Name)
+}
+
+// runWebhookCanary submits a dry-run pod, which passes through every webhook
+// that intercepts pod creation, and reports how long admission took
+func runWebhookCanary(ctx context.Context, client kubernetes.Interface) *WebhookCanary {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kc-webhook-canary-",
+			Namespace:    webhookCanaryNamespace,
+			Labels:       map[string]string{"app.kubernetes.io/managed-by": "kubestellar-console"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "canary", Image: webhookCanaryImage}},
+		},
+	}
+	start := time.Now()
+	_, err := client.CoreV1().Pods(webhookCanaryNamespace).Create(ctx, pod, metav1.CreateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+	latency := time.Since(start)
+	canary := &WebhookCanary{LatencyMs: latency.Milliseconds(), Slow: latency > slowWebhookThreshold}
+	if err != nil {
+		canary.Error = err.Error()
+		if match := failedWebhookPattern.FindStringSubmatch(canary.Error); match != nil {
+			canary.FailedWebhook = match[1]
+		}
+	}
+	return canary
+}
+
+// webhookHealthIssues returns a cluster health issue for each webhook whose
+// backing service cannot serve requests while its failure policy is Fail,
+// which rejects every request it intercepts
+func webhookHealthIssues(ctx context.Context, client kubernetes.Interface, contextName string) []string {
+	webhooks, err := listWebhooks(ctx, client, contextName)
+	if err != nil {
+		return nil
+	}
+	var issues []string
+	for _, wh := range webhooks {
+		if wh.Healthy || wh.FailurePolicy != string(admissionv1.Fail) {
+			continue
+		}
+		for _, issue := range wh.Issues {
+			issues = append(issues, fmt.Sprintf("Admission webhook %s blocks requests: %s", wh.Name, issue))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckWebhookHealth(t *testing.T) {
+	m, _ := NewMultiClusterClient("")
+
+	ignore := admissionv1.Ignore
+	ready := true
+	svcRef := func(name string) admissionv1.WebhookClientConfig {
+		return admissionv1.WebhookClientConfig{Service: &admissionv1.ServiceReference{Namespace: "hooks", Name: name}}
+	}
+	fakeCS := k8sfake.NewSimpleClientset(
+		&admissionv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+			Webhooks: []admissionv1.ValidatingWebhook{
+				{Name: "healthy.example.com", ClientConfig: svcRef("healthy")},
+				{Name: "missing.example.com", ClientConfig: svcRef("missing")},
+			},
+		},
+		&admissionv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "injector"},
+			Webhooks: []admissionv1.MutatingWebhook{
+				{Name: "noendpoints.example.com", ClientConfig: svcRef("noendpoints"), FailurePolicy: &ignore},
+			},
+		},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "healthy", Namespace: "hooks"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "noendpoints", Namespace: "hooks"}},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "healthy-abc", Namespace: "hooks", Labels: map[string]string{discoveryv1.LabelServiceName: "healthy"}},
+			Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+		},
+	)
+	fakeCS.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New(`Internal error occurred: failed calling webhook "healthy.example.com": context deadline exceeded`)
+	})
+	m.clients["c1"] = fakeCS
+
+	report, err := m.CheckWebhookHealth(context.Background(), "c1", true)
+	if err != nil {
+		t.Fatalf("CheckWebhookHealth failed: %v", err)
+	}
+	byName := make(map[string]WebhookHealth)
+	for _, wh := range report.Webhooks {
+		byName[wh.Name] = wh
+	}
+	if wh := byName["missing.example.com"]; wh.Healthy || len(wh.Issues) != 1 || !strings.Contains(wh.Issues[0], "not found") {
+		t.Errorf("Expected missing service issue, got %+v", wh)
+	}
+	if wh := byName["noendpoints.example.com"]; wh.Healthy || wh.FailurePolicy != "Ignore" || !strings.Contains(wh.Issues[0], "no ready endpoints") {
+		t.Errorf("Expected no endpoints issue, got %+v", wh)
+	}
+	if report.Canary == nil || report.Canary.FailedWebhook != "healthy.example.com" {
+		t.Fatalf("Expected canary failure attributed to healthy.example.com, got %+v", report.Canary)
+	}
+	// The service checks pass; only the canary failure is reported
+	if wh := byName["healthy.example.com"]; wh.Healthy || len(wh.Issues) != 1 || !strings.HasPrefix(wh.Issues[0], "canary request failed") {
+		t.Errorf("Expected only the canary failure, got %+v", wh)
+	}
+
+	// Only webhooks that fail closed are cluster health issues
+	health, err := m.GetClusterHealth(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("GetClusterHealth failed: %v", err)
+	}
+	var webhookIssues []string
+	for _, issue := range health.Issues {
+		if strings.HasPrefix(issue, "Admission webhook") {
+			webhookIssues = append(webhookIssues, issue)
+		}
+	}
+	if len(webhookIssues) != 1 || !strings.Contains(webhookIssues[0], "missing.example.com") {
+		t.Errorf("Expected one blocking webhook issue, got %v", webhookIssues)
+	}
+}
@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadRollup is one top-level workload (Deployment, StatefulSet, DaemonSet,
+// Job, CronJob or a custom controller) with its pods aggregated. Pods without a
+// controller are reported as their own Pod workload.
+type WorkloadRollup struct {
+	Kind      string         `json:"kind"`
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace"`
+	Cluster   string         `json:"cluster,omitempty"`
+	Pods      int            `json:"pods"`
+	ReadyPods int            `json:"readyPods"`
+	PodStates map[string]int `json:"podStates"` // phase, or the waiting reason of a stuck container
+	Restarts  int            `json:"restarts"`
+	Images    []string       `json:"images"`
+}
+
+// GetWorkloadRollups rolls the pods of a namespace (all namespaces when empty)
+// up to their top-level owners, walking ReplicaSets and Jobs, and returns one
+// row per workload. Workloads without pods are not listed.
+func (m *MultiClusterClient) GetWorkloadRollups(ctx context.Context, contextName, namespace string) ([]WorkloadRollup, error) {
+	if namespaces, err := m.scopeNamespaces(ctx, contextName, namespace); err != nil || namespaces != nil {
+		return listScoped(namespaces, err, func(ns string) ([]WorkloadRollup, error) {
+			return m.GetWorkloadRollups(ctx, contextName, ns)
+		})
+	}
+	client, err := m.GetClient(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// List the intermediate controllers once for the whole scope
+	namespaces := make(map[string]bool)
+	podObjects := make([]metav1.Object, 0, len(pods.Items))
+	for i := range pods.Items {
+		namespaces[pods.Items[i].Namespace] = true
+		podObjects = append(podObjects, &pods.Items[i])
+	}
+	owners := m.ownerResolverFor(contextName, client)
+	owners.record("Pod", podObjects)
+	for ns := range namespaces {
+		owners.loaded[ns+"/Pod"] = true
+	}
+	owners.preload(ctx, namespace, "ReplicaSet", namespaces)
+	owners.preload(ctx, namespace, "Job", namespaces)
+
+	rollups := make(map[string]*WorkloadRollup)
+	images := make(map[string]map[string]bool)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		root := owners.root(ctx, pod.Namespace, "Pod", pod.Name)
+		if root == "" {
+			root = "Pod/" + pod.Name
+		}
+		key := pod.Namespace + "/" + root
+		w, ok := rollups[key]
+		if !ok {
+			kind, name, _ := strings.Cut(root, "/")
+			w = &WorkloadRollup{Kind: kind, Name: name, Namespace: pod.Namespace, Cluster: contextName, PodStates: make(map[string]int)}
+			rollups[key] = w
+			images[key] = make(map[string]bool)
+		}
+
+		w.Pods++
+		w.PodStates[podState(pod)]++
+		ready := len(pod.Status.ContainerStatuses) > 0
+		for _, cs := range pod.Status.ContainerStatuses {
+			w.Restarts += int(cs.RestartCount)
+			ready = ready && cs.Ready
+		}
+		if ready {
+			w.ReadyPods++
+		}
+		for _, c := range pod.Spec.Containers {
+			if !images[key][c.Image] {
+				images[key][c.Image] = true
+				w.Images = append(w.Images, c.Image)
+			}
+		}
+	}
+
+	result := make([]WorkloadRollup, 0, len(rollups))
+	for _, w := range rollups {
+		sort.Strings(w.Images)
+		result = append(result, *w)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return result, nil
+}
+
+// podState is the pod's phase, or the reason a container is stuck waiting
+// (CrashLoopBackOff, ImagePullBackOff, ...) since the phase hides those
+func podState(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "ContainerCreating" {
+			return cs.State.Waiting.Reason
+		}
+	}
+	if pod.Status.Phase == "" {
+		return string(corev1.PodUnknown)
+	}
+	return string(pod.Status.Phase)
+}
@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetWorkloadRollups(t *testing.T) {
+	controller := true
+	ownedBy := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	pod := func(name, namespace, image string, owner []metav1.OwnerReference, status corev1.ContainerStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, OwnerReferences: owner},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: image}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{status}},
+		}
+	}
+	ready := corev1.ContainerStatus{Name: "main", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	crashing := corev1.ContainerStatus{Name: "main", RestartCount: 7, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}
+
+	m, _ := NewMultiClusterClient("")
+	m.InjectClient("c1", k8sfake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "api-old", Namespace: "shop", OwnerReferences: ownedBy("Deployment", "api")}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "api-new", Namespace: "shop", OwnerReferences: ownedBy("Deployment", "api")}},
+		pod("api-old-a", "shop", "api:1", ownedBy("ReplicaSet", "api-old"), ready),
+		pod("api-new-b", "shop", "api:2", ownedBy("ReplicaSet", "api-new"), crashing),
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-123", Namespace: "ops", OwnerReferences: ownedBy("CronJob", "backup")}},
+		pod("backup-123-x", "ops", "backup:1", ownedBy("Job", "backup-123"), ready),
+		pod("debug", "shop", "busybox", nil, ready),
+	))
+
+	rollups, err := m.GetWorkloadRollups(context.Background(), "c1", "")
+	if err != nil {
+		t.Fatalf("GetWorkloadRollups failed: %v", err)
+	}
+	if len(rollups) != 3 {
+		t.Fatalf("Expected 3 workloads, got %+v", rollups)
+	}
+	backup, api, debug := rollups[0], rollups[1], rollups[2]
+	if backup.Kind != "CronJob" || backup.Name != "backup" || backup.Namespace != "ops" {
+		t.Errorf("Expected the job pod to roll up to its CronJob, got %+v", backup)
+	}
+	if api.Kind != "Deployment" || api.Pods != 2 || api.ReadyPods != 1 || api.Restarts != 7 {
+		t.Errorf("Expected both ReplicaSets' pods under the Deployment, got %+v", api)
+	}
+	if api.PodStates["Running"] != 1 || api.PodStates["CrashLoopBackOff"] != 1 {
+		t.Errorf("Expected the crashing pod to be reported by its waiting reason, got %v", api.PodStates)
+	}
+	if len(api.Images) != 2 || api.Images[0] != "api:1" || api.Images[1] != "api:2" {
+		t.Errorf("Expected both image versions, got %v", api.Images)
+	}
+	if debug.Kind != "Pod" || debug.Name != "debug" || debug.Pods != 1 {
+		t.Errorf("Expected a standalone pod to be its own workload, got %+v", debug)
+	}
+
+	if shop, _ := m.GetWorkloadRollups(context.Background(), "c1", "shop"); len(shop) != 2 {
+		t.Errorf("Expected 2 workloads in shop, got %+v", shop)
+	}
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AutomationTriggerType is the condition that fires an automation rule
+type AutomationTriggerType string
+
+const (
+	// TriggerPodCrashLoop fires for pods in CrashLoopBackOff with more restarts than the threshold
+	TriggerPodCrashLoop AutomationTriggerType = "pod_crashloop"
+	// TriggerGPUNodeUnhealthy fires for GPU nodes whose health check reports unhealthy
+	TriggerGPUNodeUnhealthy AutomationTriggerType = "gpu_node_unhealthy"
+)
+
+// AutomationActionType is what a rule does when it fires
+type AutomationActionType string
+
+const (
+	ActionRestartDeployment AutomationActionType = "restart_deployment"
+	ActionCordonNode        AutomationActionType = "cordon_node"
+	ActionSlackAlert        AutomationActionType = "slack_alert"
+	ActionAIAnalysis        AutomationActionType = "ai_analysis"
+)
+
+// AutomationRunStatus is the outcome of one action
+type AutomationRunStatus string
+
+const (
+	AutomationRunSucceeded AutomationRunStatus = "succeeded"
+	AutomationRunFailed    AutomationRunStatus = "failed"
+	AutomationRunDryRun    AutomationRunStatus = "dry_run"
+	AutomationRunSkipped   AutomationRunStatus = "skipped"
+)
+
+// DefaultAutomationCooldownMinutes is how long a rule waits before acting on the same target again
+const DefaultAutomationCooldownMinutes = 30
+
+// DefaultCrashLoopRestarts is the restart threshold of TriggerPodCrashLoop when none is set
+const DefaultCrashLoopRestarts = 10
+
+// ErrInvalidAutomationRule is returned for rules that cannot be evaluated
+var ErrInvalidAutomationRule = errors.New("invalid automation rule")
+
+// AutomationTrigger selects what a rule watches; empty Cluster and Namespace match all
+type AutomationTrigger struct {
+	Type      AutomationTriggerType `json:"type"`
+	Threshold int                   `json:"threshold,omitempty"` // restarts for pod_crashloop
+	Cluster   string                `json:"cluster,omitempty"`
+	Namespace string                `json:"namespace,omitempty"`
+}
+
+// AutomationAction is one step a rule takes. Config holds action settings,
+// e.g. slackWebhookUrl and slackChannel for slack_alert.
+type AutomationAction struct {
+	Type   AutomationActionType `json:"type"`
+	Config map[string]string    `json:"config,omitempty"`
+}
+
+// AutomationRule is a user-defined "if trigger, do actions" rule. Dry-run
+// rules record what they would do without changing anything.
+type AutomationRule struct {
+	ID              uuid.UUID          `json:"id"`
+	UserID          uuid.UUID          `json:"user_id"`
+	Name            string             `json:"name"`
+	Enabled         bool               `json:"enabled"`
+	DryRun          bool               `json:"dry_run"`
+	Trigger         AutomationTrigger  `json:"trigger"`
+	Actions         []AutomationAction `json:"actions"`
+	CooldownMinutes int                `json:"cooldown_minutes"`
+	LastFiredAt     *time.Time         `json:"last_fired_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       *time.Time         `json:"updated_at,omitempty"`
+}
+
+// AutomationRun is the audit record of one action taken (or simulated) by a rule
+type AutomationRun struct {
+	ID          uuid.UUID             `json:"id"`
+	RuleID      uuid.UUID             `json:"rule_id"`
+	RuleName    string                `json:"rule_name"`
+	Trigger     AutomationTriggerType `json:"trigger"`
+	Action      AutomationActionType  `json:"action"`
+	Cluster     string                `json:"cluster"`
+	Namespace   string                `json:"namespace,omitempty"`
+	Target      string                `json:"target"` // pod or node that fired the rule
+	Status      AutomationRunStatus   `json:"status"`
+	DryRun      bool                  `json:"dry_run"`
+	Message     string                `json:"message"`
+	TriggeredBy string                `json:"triggered_by"` // "schedule" or the user who ran the rule
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// Validate checks a rule and fills in defaults
+func (r *AutomationRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAutomationRule)
+	}
+	switch r.Trigger.Type {
+	case TriggerPodCrashLoop:
+		if r.Trigger.Threshold == 0 {
+			r.Trigger.Threshold = DefaultCrashLoopRestarts
+		}
+	case TriggerGPUNodeUnhealthy:
+	default:
+		return fmt.Errorf("%w: unknown trigger %q", ErrInvalidAutomationRule, r.Trigger.Type)
+	}
+	if r.Trigger.Threshold < 0 {
+		return fmt.Errorf("%w: threshold must not be negative", ErrInvalidAutomationRule)
+	}
+	if len(r.Actions) == 0 {
+		return fmt.Errorf("%w: at least one action is required", ErrInvalidAutomationRule)
+	}
+	for _, a := range r.Actions {
+		switch a.Type {
+		case ActionRestartDeployment:
+			if r.Trigger.Type != TriggerPodCrashLoop {
+				return fmt.Errorf("%w: %s needs a pod trigger", ErrInvalidAutomationRule, a.Type)
+			}
+		case ActionCordonNode:
+			if r.Trigger.Type != TriggerGPUNodeUnhealthy {
+				return fmt.Errorf("%w: %s needs a node trigger", ErrInvalidAutomationRule, a.Type)
+			}
+		case ActionSlackAlert:
+			if a.Config["slackWebhookUrl"] == "" {
+				return fmt.Errorf("%w: %s needs slackWebhookUrl", ErrInvalidAutomationRule, a.Type)
+			}
+		case ActionAIAnalysis:
+		default:
+			return fmt.Errorf("%w: unknown action %q", ErrInvalidAutomationRule, a.Type)
+		}
+	}
+	if r.CooldownMinutes < 0 {
+		return fmt.Errorf("%w: cooldown must not be negative", ErrInvalidAutomationRule)
+	}
+	if r.CooldownMinutes == 0 {
+		r.CooldownMinutes = DefaultAutomationCooldownMinutes
+	}
+	return nil
+}
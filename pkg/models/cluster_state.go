@@ -0,0 +1,16 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ClusterStateSnapshot records the state of a cluster at a point in time so
+// later states can be compared against it. State is the JSON encoding of a
+// k8s.ClusterState.
+type ClusterStateSnapshot struct {
+	ID        string          `json:"id"`
+	Cluster   string          `json:"cluster"`
+	Timestamp time.Time       `json:"timestamp"`
+	State     json.RawMessage `json:"state"`
+}
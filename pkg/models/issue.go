@@ -0,0 +1,96 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueKind is the kind of resource an issue was found on
+type IssueKind string
+
+const (
+	IssueKindPod        IssueKind = "pod"
+	IssueKindDeployment IssueKind = "deployment"
+	IssueKindSecurity   IssueKind = "security"
+)
+
+// IssueStatus is whether an issue is currently detected
+type IssueStatus string
+
+const (
+	IssueStatusOpen     IssueStatus = "open"
+	IssueStatusResolved IssueStatus = "resolved"
+)
+
+// IssueAction is an entry type in an issue's history
+type IssueAction string
+
+const (
+	IssueActionOpened       IssueAction = "opened"
+	IssueActionResolved     IssueAction = "resolved"
+	IssueActionReopened     IssueAction = "reopened"
+	IssueActionAcknowledged IssueAction = "acknowledged"
+	IssueActionAssigned     IssueAction = "assigned"
+	IssueActionSnoozed      IssueAction = "snoozed"
+	IssueActionUnsnoozed    IssueAction = "unsnoozed"
+)
+
+// IssueIDLength is the number of hex characters of an issue fingerprint
+const IssueIDLength = 16
+
+// Issue is a problem detected on a pod, deployment or security check. Its ID
+// is derived from what the problem is and where, so the same problem keeps
+// its ID across detections and comes back as the same issue when it recurs.
+type Issue struct {
+	ID             string      `json:"id"`
+	Kind           IssueKind   `json:"kind"`
+	Cluster        string      `json:"cluster"`
+	Namespace      string      `json:"namespace"`
+	Name           string      `json:"name"`
+	Type           string      `json:"type"`
+	Severity       string      `json:"severity,omitempty"`
+	Message        string      `json:"message,omitempty"`
+	Status         IssueStatus `json:"status"`
+	Occurrences    int         `json:"occurrences"`
+	FirstSeen      time.Time   `json:"firstSeen"`
+	LastSeen       time.Time   `json:"lastSeen"`
+	ResolvedAt     *time.Time  `json:"resolvedAt,omitempty"`
+	AcknowledgedBy string      `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *time.Time  `json:"acknowledgedAt,omitempty"`
+	Assignee       string      `json:"assignee,omitempty"`
+	SnoozedUntil   *time.Time  `json:"snoozedUntil,omitempty"`
+}
+
+// IssueFingerprint returns the stable ID of an issue
+func IssueFingerprint(kind IssueKind, cluster, namespace, name, issueType string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{string(kind), cluster, namespace, name, issueType}, "\x00")))
+	return hex.EncodeToString(sum[:])[:IssueIDLength]
+}
+
+// Snoozed reports whether the issue is snoozed at the given time
+func (i *Issue) Snoozed(now time.Time) bool {
+	return i.SnoozedUntil != nil && now.Before(*i.SnoozedUntil)
+}
+
+// IssueFilter narrows an issue listing; empty fields match everything
+type IssueFilter struct {
+	Status  IssueStatus
+	Kind    IssueKind
+	Cluster string
+	Limit   int
+}
+
+// IssueEvent is one entry of an issue's history: detection changes and the
+// acknowledge, assign and snooze actions taken by users
+type IssueEvent struct {
+	ID        uuid.UUID   `json:"id"`
+	IssueID   string      `json:"issueId"`
+	Action    IssueAction `json:"action"`
+	Actor     string      `json:"actor"`
+	Detail    string      `json:"detail,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// PodTimelineEvent is one recorded step of a pod's lifecycle (scheduled,
+// pulled, started, restarted, OOMKilled, deleted...). Events are kept after
+// the pod and its Kubernetes events are gone so incidents can be reviewed.
+type PodTimelineEvent struct {
+	ID        string    `json:"id"`
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	PodUID    string    `json:"podUID,omitempty"`
+	Container string    `json:"container,omitempty"`
+	Stage     string    `json:"stage"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message,omitempty"`
+	Count     int32     `json:"count,omitempty"`
+	Source    string    `json:"source"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidRemoteAgent is returned for agent registrations that cannot be used
+var ErrInvalidRemoteAgent = errors.New("invalid remote agent")
+
+// remoteAgentNamePattern keeps names usable as the prefix of federated cluster names
+var remoteAgentNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// RemoteAgent is a kc-agent registered with the console backend, e.g. on a
+// laptop or jump host next to its kubeconfig. Its clusters are served as
+// "{name}/{context}". The token is never returned by the API.
+type RemoteAgent struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Token     string    `json:"token,omitempty"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate checks the name and normalizes the URL
+func (a *RemoteAgent) Validate() error {
+	if !remoteAgentNamePattern.MatchString(a.Name) {
+		return fmt.Errorf("%w: name must be lowercase letters, digits and dashes", ErrInvalidRemoteAgent)
+	}
+	u, err := url.Parse(strings.TrimSpace(a.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an http(s) URL", ErrInvalidRemoteAgent)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%w: url must not have a query or fragment", ErrInvalidRemoteAgent)
+	}
+	a.URL = strings.TrimRight(u.String(), "/")
+	return nil
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SecurityPostureSnapshot records the security score of a namespace, or of a
+// whole cluster when Namespace is empty, at a point in time
+type SecurityPostureSnapshot struct {
+	ID           string    `json:"id"`
+	Cluster      string    `json:"cluster"`
+	Namespace    string    `json:"namespace"`
+	Timestamp    time.Time `json:"timestamp"`
+	Score        float64   `json:"score"`
+	Pods         int       `json:"pods"`
+	HighIssues   int       `json:"high_issues"`
+	MediumIssues int       `json:"medium_issues"`
+	LowIssues    int       `json:"low_issues"`
+}
@@ -47,10 +47,11 @@ func (e *EmailNotifier) Send(alert Alert) error {
 		return fmt.Errorf("failed to format email body: %w", err)
 	}
 
-	// Build email message
-	msg := e.buildMessage(subject, body)
+	return e.sendMail(e.buildMessage(subject, body))
+}
 
-	// Send email
+// sendMail delivers a built message to all recipients
+func (e *EmailNotifier) sendMail(msg string) error {
 	addr := fmt.Sprintf("%s:%d", e.SMTPHost, e.SMTPPort)
 
 	var auth smtp.Auth
@@ -58,8 +59,7 @@ func (e *EmailNotifier) Send(alert Alert) error {
 		auth = smtp.PlainAuth("", e.Username, e.Password, e.SMTPHost)
 	}
 
-	err = smtp.SendMail(addr, auth, e.From, e.To, []byte(msg))
-	if err != nil {
+	if err := smtp.SendMail(addr, auth, e.From, e.To, []byte(msg)); err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 
@@ -82,13 +82,18 @@ func (e *EmailNotifier) Test() error {
 	return e.Send(testAlert)
 }
 
-// buildMessage constructs the full email message with headers
+// buildMessage constructs the full HTML email message with headers
 func (e *EmailNotifier) buildMessage(subject, body string) string {
+	return e.buildMessageWithType(subject, "text/html", body)
+}
+
+// buildMessageWithType constructs the full email message with the given body content type
+func (e *EmailNotifier) buildMessageWithType(subject, contentType, body string) string {
 	msg := fmt.Sprintf("From: %s\r\n", e.From)
 	msg += fmt.Sprintf("To: %s\r\n", e.To[0])
 	msg += fmt.Sprintf("Subject: %s\r\n", subject)
 	msg += "MIME-Version: 1.0\r\n"
-	msg += "Content-Type: text/html; charset=UTF-8\r\n"
+	msg += fmt.Sprintf("Content-Type: %s; charset=UTF-8\r\n", contentType)
 	msg += "\r\n"
 	msg += body
 	return msg
@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+)
+
+// slackMaxReportText keeps report messages under Slack's 40,000 character text limit
+const slackMaxReportText = 39000
+
+// Report is a rendered periodic summary, such as a daily cluster health report
+type Report struct {
+	Title    string
+	Markdown string
+	// HTML is the rich rendering for email; when empty, email sends Markdown as plain text
+	HTML        string
+	GeneratedAt time.Time
+}
+
+// ReportSender is implemented by notifiers that can deliver reports
+type ReportSender interface {
+	SendReport(report Report) error
+}
+
+// SendReport emails a report, as HTML when it has an HTML rendering
+func (e *EmailNotifier) SendReport(report Report) error {
+	if e.SMTPHost == "" {
+		return fmt.Errorf("SMTP host not configured")
+	}
+	if len(e.To) == 0 {
+		return fmt.Errorf("no email recipients configured")
+	}
+
+	if report.HTML != "" {
+		return e.sendMail(e.buildMessageWithType(report.Title, "text/html", report.HTML))
+	}
+	return e.sendMail(e.buildMessageWithType(report.Title, "text/plain", report.Markdown))
+}
+
+// SendReport posts a report's Markdown rendering to Slack
+func (s *SlackNotifier) SendReport(report Report) error {
+	if s.WebhookURL == "" {
+		return fmt.Errorf("slack webhook URL not configured")
+	}
+
+	text := fmt.Sprintf("*%s*\n%s", report.Title, report.Markdown)
+	if len(text) > slackMaxReportText {
+		text = text[:slackMaxReportText] + "\n…(truncated)"
+	}
+	msg := slackMessage{
+		Username:  "KubeStellar Console",
+		IconEmoji: ":bar_chart:",
+		Text:      text,
+		Channel:   s.Channel,
+	}
+	return s.sendSlackMessage(msg)
+}
@@ -75,36 +75,8 @@ func (s *Service) SendAlertToChannels(alert Alert, channels []NotificationChanne
 			continue
 		}
 
-		var notifier Notifier
 		channelID := fmt.Sprintf("channel-%d", i)
-
-		switch channel.Type {
-		case NotificationTypeSlack:
-			webhookURL, _ := channel.Config["slackWebhookUrl"].(string)
-			slackChannel, _ := channel.Config["slackChannel"].(string)
-			if webhookURL != "" {
-				notifier = NewSlackNotifier(webhookURL, slackChannel)
-			}
-
-		case NotificationTypeEmail:
-			smtpHost, _ := channel.Config["emailSMTPHost"].(string)
-			smtpPortFloat, _ := channel.Config["emailSMTPPort"].(float64)
-			smtpPort := int(smtpPortFloat)
-			username, _ := channel.Config["emailUsername"].(string)
-			password, _ := channel.Config["emailPassword"].(string)
-			from, _ := channel.Config["emailFrom"].(string)
-			to, _ := channel.Config["emailTo"].(string)
-
-			if smtpHost != "" && from != "" && to != "" {
-				recipients := strings.Split(to, ",")
-				for j, r := range recipients {
-					recipients[j] = strings.TrimSpace(r)
-				}
-				notifier = NewEmailNotifier(smtpHost, smtpPort, username, password, from, recipients)
-			}
-		}
-
-		if notifier != nil {
+		if notifier := notifierForChannel(channel); notifier != nil {
 			if err := notifier.Send(alert); err != nil {
 				errMsg := fmt.Sprintf("failed to send notification via %s channel %s: %v", channel.Type, channelID, err)
 				log.Println(errMsg)
@@ -122,6 +94,68 @@ func (s *Service) SendAlertToChannels(alert Alert, channels []NotificationChanne
 	return nil
 }
 
+// SendReportToChannels delivers a report to specific notification channels.
+// Unlike alerts, a report sent to an unconfigured channel is an error.
+func (s *Service) SendReportToChannels(report Report, channels []NotificationChannel) error {
+	var errors []string
+	for i, channel := range channels {
+		if !channel.Enabled {
+			continue
+		}
+
+		channelID := fmt.Sprintf("channel-%d", i)
+		sender, ok := notifierForChannel(channel).(ReportSender)
+		if !ok {
+			errors = append(errors, fmt.Sprintf("%s channel %s is not configured for reports", channel.Type, channelID))
+			continue
+		}
+		if err := sender.SendReport(report); err != nil {
+			errMsg := fmt.Sprintf("failed to send report via %s channel %s: %v", channel.Type, channelID, err)
+			log.Println(errMsg)
+			errors = append(errors, errMsg)
+		} else {
+			log.Printf("Successfully sent report %q via %s channel %s", report.Title, channel.Type, channelID)
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+// notifierForChannel builds the notifier for a channel, or returns nil when
+// the channel's configuration is incomplete
+func notifierForChannel(channel NotificationChannel) Notifier {
+	switch channel.Type {
+	case NotificationTypeSlack:
+		webhookURL, _ := channel.Config["slackWebhookUrl"].(string)
+		slackChannel, _ := channel.Config["slackChannel"].(string)
+		if webhookURL != "" {
+			return NewSlackNotifier(webhookURL, slackChannel)
+		}
+
+	case NotificationTypeEmail:
+		smtpHost, _ := channel.Config["emailSMTPHost"].(string)
+		smtpPortFloat, _ := channel.Config["emailSMTPPort"].(float64)
+		smtpPort := int(smtpPortFloat)
+		username, _ := channel.Config["emailUsername"].(string)
+		password, _ := channel.Config["emailPassword"].(string)
+		from, _ := channel.Config["emailFrom"].(string)
+		to, _ := channel.Config["emailTo"].(string)
+
+		if smtpHost != "" && from != "" && to != "" {
+			recipients := strings.Split(to, ",")
+			for j, r := range recipients {
+				recipients[j] = strings.TrimSpace(r)
+			}
+			return NewEmailNotifier(smtpHost, smtpPort, username, password, from, recipients)
+		}
+	}
+	return nil
+}
+
 // TestNotifier tests a specific notifier configuration
 func (s *Service) TestNotifier(notifierType string, config map[string]interface{}) error {
 	var notifier Notifier
@@ -101,10 +101,24 @@ func (sm *SettingsManager) Load() error {
 		return fmt.Errorf("failed to read settings: %w", err)
 	}
 
+	migrated, version, err := migrateSettingsFile(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse settings: %w", err)
+	}
+	if version > CurrentSchemaVersion {
+		log.Printf("[settings] %s uses schema %d, newer than %d; unknown settings are kept as-is",
+			sm.settingsPath, version, CurrentSchemaVersion)
+	}
+
 	var sf SettingsFile
-	if err := json.Unmarshal(data, &sf); err != nil {
+	if err := json.Unmarshal(migrated, &sf); err != nil {
 		return fmt.Errorf("failed to parse settings: %w", err)
 	}
+	if version < CurrentSchemaVersion {
+		// Keep the pre-migration file for downgrades; the migrated one is
+		// written on the next save
+		backupSettingsFile(sm.settingsPath, data, version)
+	}
 
 	// Merge with defaults for forward compatibility (new fields get defaults)
 	defaults := DefaultSettings()
@@ -134,6 +148,10 @@ func (sm *SettingsManager) saveLocked() error {
 	if sm.settings == nil {
 		sm.settings = DefaultSettings()
 	}
+	// Never lower the schema of a file written by a newer agent
+	if sm.settings.SchemaVersion < CurrentSchemaVersion {
+		sm.settings.SchemaVersion = CurrentSchemaVersion
+	}
 	sm.settings.LastModified = time.Now().UTC().Format(time.RFC3339)
 	sm.settings.KeyFingerprint = keyFingerprint(sm.key)
 
@@ -147,7 +165,13 @@ func (sm *SettingsManager) saveLocked() error {
 		return fmt.Errorf("failed to create settings directory: %w", err)
 	}
 
-	if err := os.WriteFile(sm.settingsPath, data, settingsFileMode); err != nil {
+	// Write via rename so an interrupted save never truncates the file
+	tmp := sm.settingsPath + ".tmp"
+	if err := os.WriteFile(tmp, data, settingsFileMode); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	if err := os.Rename(tmp, sm.settingsPath); err != nil {
+		os.Remove(tmp)
 		return fmt.Errorf("failed to write settings: %w", err)
 	}
 
@@ -164,16 +188,26 @@ func (sm *SettingsManager) GetAll() (*AllSettings, error) {
 	}
 
 	all := &AllSettings{
-		AIMode:        sm.settings.Settings.AIMode,
-		Predictions:   sm.settings.Settings.Predictions,
-		TokenUsage:    sm.settings.Settings.TokenUsage,
-		Theme:         sm.settings.Settings.Theme,
-		CustomThemes:  sm.settings.Settings.CustomThemes,
-		Accessibility: sm.settings.Settings.Accessibility,
-		Profile:       sm.settings.Settings.Profile,
-		Widget:        sm.settings.Settings.Widget,
-		APIKeys:       make(map[string]APIKeyEntry),
-		Notifications: NotificationSecrets{},
+		AIMode:              sm.settings.Settings.AIMode,
+		Predictions:         sm.settings.Settings.Predictions,
+		TokenUsage:          sm.settings.Settings.TokenUsage,
+		Theme:               sm.settings.Settings.Theme,
+		CustomThemes:        sm.settings.Settings.CustomThemes,
+		Accessibility:       sm.settings.Settings.Accessibility,
+		Profile:             sm.settings.Settings.Profile,
+		Widget:              sm.settings.Settings.Widget,
+		TokenBudgets:        sm.settings.Settings.TokenBudgets,
+		PredictionAnalyzers: sm.settings.Settings.PredictionAnalyzers,
+		AlertSilences:       sm.settings.Settings.AlertSilences,
+		DeviceThresholds:    sm.settings.Settings.DeviceThresholds,
+		GPUOperatorChecks:   sm.settings.Settings.GPUOperatorChecks,
+		ClusterFilters:      sm.settings.Settings.ClusterFilters,
+		ClusterGroups:       sm.settings.Settings.ClusterGroups,
+		ClusterPreferences:  sm.settings.Settings.ClusterPreferences,
+		ClusterTimeouts:     sm.settings.Settings.ClusterTimeouts,
+		ReportSchedules:     sm.settings.Settings.ReportSchedules,
+		APIKeys:             make(map[string]APIKeyEntry),
+		Notifications:       NotificationSecrets{},
 	}
 
 	// Cannot decrypt without an encryption key (init may have failed)
@@ -251,6 +285,37 @@ func (sm *SettingsManager) SaveAll(all *AllSettings) error {
 	sm.settings.Settings.Accessibility = all.Accessibility
 	sm.settings.Settings.Profile = all.Profile
 	sm.settings.Settings.Widget = all.Widget
+	// Budgets are managed by the agent; clients that don't send them keep the stored ones
+	if all.TokenBudgets != nil {
+		sm.settings.Settings.TokenBudgets = all.TokenBudgets
+	}
+	if all.PredictionAnalyzers != nil {
+		sm.settings.Settings.PredictionAnalyzers = all.PredictionAnalyzers
+	}
+	if all.AlertSilences != nil {
+		sm.settings.Settings.AlertSilences = all.AlertSilences
+	}
+	if all.DeviceThresholds != nil {
+		sm.settings.Settings.DeviceThresholds = all.DeviceThresholds
+	}
+	if all.GPUOperatorChecks != nil {
+		sm.settings.Settings.GPUOperatorChecks = all.GPUOperatorChecks
+	}
+	if all.ClusterFilters != nil {
+		sm.settings.Settings.ClusterFilters = all.ClusterFilters
+	}
+	if all.ClusterGroups != nil {
+		sm.settings.Settings.ClusterGroups = all.ClusterGroups
+	}
+	if all.ClusterPreferences != nil {
+		sm.settings.Settings.ClusterPreferences = all.ClusterPreferences
+	}
+	if all.ClusterTimeouts != nil {
+		sm.settings.Settings.ClusterTimeouts = all.ClusterTimeouts
+	}
+	if all.ReportSchedules != nil {
+		sm.settings.Settings.ReportSchedules = all.ReportSchedules
+	}
 
 	// Encrypt API keys (only if non-empty)
 	if len(all.APIKeys) > 0 {
@@ -360,8 +425,12 @@ func (sm *SettingsManager) ExportEncrypted() ([]byte, error) {
 // ImportEncrypted validates and imports a settings file.
 // Only plaintext settings are imported; encrypted fields require the original key.
 func (sm *SettingsManager) ImportEncrypted(data []byte) error {
+	migrated, _, err := migrateSettingsFile(data)
+	if err != nil {
+		return fmt.Errorf("invalid settings file: %w", err)
+	}
 	var imported SettingsFile
-	if err := json.Unmarshal(data, &imported); err != nil {
+	if err := json.Unmarshal(migrated, &imported); err != nil {
 		return fmt.Errorf("invalid settings file: %w", err)
 	}
 
@@ -2,6 +2,7 @@ package settings
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -301,3 +302,209 @@ func contains(s, substr string) bool {
 			return false
 		}()
 }
+
+func TestManager_TokenBudgetsPreservedWhenOmitted(t *testing.T) {
+	sm := newTestManager(t)
+
+	all, _ := sm.GetAll()
+	all.TokenBudgets = map[string]TokenBudget{
+		"claude": {DailyTokens: 100000, Action: TokenBudgetActionWarn},
+	}
+	if err := sm.SaveAll(all); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	// A client that doesn't know about budgets saves without the field
+	all2, _ := sm.GetAll()
+	all2.TokenBudgets = nil
+	all2.Theme = "batman"
+	if err := sm.SaveAll(all2); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	got, _ := sm.GetAll()
+	if got.TokenBudgets["claude"].DailyTokens != 100000 {
+		t.Errorf("budgets were dropped: %+v", got.TokenBudgets)
+	}
+
+	// An explicit empty map clears budgets
+	got.TokenBudgets = map[string]TokenBudget{}
+	sm.SaveAll(got)
+	if cleared, _ := sm.GetAll(); len(cleared.TokenBudgets) != 0 {
+		t.Errorf("expected budgets to be cleared, got %+v", cleared.TokenBudgets)
+	}
+}
+
+func TestManager_PredictionAnalyzersPreservedWhenOmitted(t *testing.T) {
+	sm := newTestManager(t)
+
+	disabled := false
+	all, _ := sm.GetAll()
+	all.PredictionAnalyzers = map[string]PredictionAnalyzerConfig{
+		"crashloop": {
+			Enabled:    true,
+			Thresholds: map[string]float64{"restartsWarning": 5},
+			Clusters:   map[string]PredictionAnalyzerOverride{"prod": {Enabled: &disabled}},
+		},
+	}
+	if err := sm.SaveAll(all); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	all2, _ := sm.GetAll()
+	all2.PredictionAnalyzers = nil
+	if err := sm.SaveAll(all2); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	got, _ := sm.GetAll()
+	cfg, ok := got.PredictionAnalyzers["crashloop"]
+	if !ok || cfg.Thresholds["restartsWarning"] != 5 {
+		t.Fatalf("analyzer config was dropped: %+v", got.PredictionAnalyzers)
+	}
+	if override := cfg.Clusters["prod"]; override.Enabled == nil || *override.Enabled {
+		t.Errorf("expected prod override to be disabled, got %+v", override)
+	}
+}
+
+func TestManager_ClusterFiltersPreservedWhenOmitted(t *testing.T) {
+	sm := newTestManager(t)
+
+	all, _ := sm.GetAll()
+	all.ClusterFilters = &ClusterFilters{Include: []string{"prod-.*"}}
+	all.ClusterGroups = []ClusterGroup{{Name: "gpu", Clusters: []string{"gpu-.*"}}}
+	all.ClusterPreferences = map[string]ClusterPreference{"prod-east": {DisplayName: "Prod", Pinned: true}}
+	if err := sm.SaveAll(all); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	all2, _ := sm.GetAll()
+	all2.ClusterFilters = nil
+	all2.ClusterGroups = nil
+	all2.ClusterPreferences = nil
+	if err := sm.SaveAll(all2); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	got, _ := sm.GetAll()
+	if got.ClusterFilters == nil || len(got.ClusterFilters.Include) != 1 {
+		t.Errorf("cluster filters were dropped: %+v", got.ClusterFilters)
+	}
+	if len(got.ClusterGroups) != 1 || got.ClusterGroups[0].Name != "gpu" {
+		t.Errorf("cluster groups were dropped: %+v", got.ClusterGroups)
+	}
+	if pref := got.ClusterPreferences["prod-east"]; pref.DisplayName != "Prod" || !pref.Pinned {
+		t.Errorf("cluster preferences were dropped: %+v", got.ClusterPreferences)
+	}
+}
+
+func TestManager_ReportSchedulesPreservedWhenOmitted(t *testing.T) {
+	sm := newTestManager(t)
+
+	all, _ := sm.GetAll()
+	all.ReportSchedules = []ReportSchedule{{ID: "daily", Name: "Daily GPU", Frequency: "daily", Time: "09:00", TimeZone: "Europe/Berlin", Channel: "email"}}
+	if err := sm.SaveAll(all); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	all2, _ := sm.GetAll()
+	all2.ReportSchedules = nil
+	if err := sm.SaveAll(all2); err != nil {
+		t.Fatalf("SaveAll failed: %v", err)
+	}
+
+	got, _ := sm.GetAll()
+	if len(got.ReportSchedules) != 1 || got.ReportSchedules[0].TimeZone != "Europe/Berlin" {
+		t.Errorf("report schedules were dropped: %+v", got.ReportSchedules)
+	}
+}
+
+func TestManager_SchemaMigrationAndUnknownFields(t *testing.T) {
+	sm := newTestManager(t)
+
+	// A v1 file (no schemaVersion) carrying fields from a newer agent
+	v1 := `{
+  "version": 1,
+  "futureTopLevel": {"a": 1},
+  "settings": {"theme": "nord", "futureSetting": [1, 2]},
+  "encrypted": {"futureSecret": {"ciphertext": "x", "iv": "y"}}
+}`
+	if err := os.WriteFile(sm.settingsPath, []byte(v1), settingsFileMode); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if sm.settings.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("schemaVersion = %d, want %d", sm.settings.SchemaVersion, CurrentSchemaVersion)
+	}
+	if backup, err := os.ReadFile(sm.settingsPath + ".v1.bak"); err != nil || string(backup) != v1 {
+		t.Errorf("expected the v1 file to be backed up, got %q, %v", backup, err)
+	}
+
+	// Saving through the decrypted view must not drop the unknown fields
+	all, err := sm.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	all.Theme = "dracula"
+	if err := sm.SaveAll(all); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(sm.settingsPath)
+	var saved struct {
+		SchemaVersion  int                        `json:"schemaVersion"`
+		FutureTopLevel map[string]int             `json:"futureTopLevel"`
+		Settings       map[string]interface{}     `json:"settings"`
+		Encrypted      map[string]json.RawMessage `json:"encrypted"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.SchemaVersion != CurrentSchemaVersion || saved.FutureTopLevel["a"] != 1 {
+		t.Errorf("unexpected top level: %s", data)
+	}
+	if fmt.Sprint(saved.Settings["futureSetting"]) != "[1 2]" || saved.Settings["theme"] != "dracula" {
+		t.Errorf("unexpected settings: %s", data)
+	}
+	if _, ok := saved.Encrypted["futureSecret"]; !ok {
+		t.Errorf("expected the unknown encrypted field to be kept: %s", data)
+	}
+}
+
+func TestManager_NewerSchemaNotDowngraded(t *testing.T) {
+	sm := newTestManager(t)
+
+	newer := map[string]interface{}{
+		"version":       1,
+		"schemaVersion": CurrentSchemaVersion + 1,
+		"settings":      map[string]interface{}{"theme": "nord", "renamedField": "kept"},
+		"encrypted":     map[string]interface{}{},
+	}
+	data, _ := json.Marshal(newer)
+	if err := os.WriteFile(sm.settingsPath, data, settingsFileMode); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := sm.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ = os.ReadFile(sm.settingsPath)
+	var saved struct {
+		SchemaVersion int                    `json:"schemaVersion"`
+		Settings      map[string]interface{} `json:"settings"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.SchemaVersion != CurrentSchemaVersion+1 || saved.Settings["renamedField"] != "kept" {
+		t.Errorf("expected the newer schema to be kept intact, got %s", data)
+	}
+	if _, err := os.Stat(sm.settingsPath + ".v3.bak"); err == nil {
+		t.Error("newer files should not be backed up as migrated")
+	}
+}
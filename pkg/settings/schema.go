@@ -0,0 +1,220 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// CurrentSchemaVersion is the settings schema this build reads and writes.
+// Bump it together with a new entry in schemaMigrations.
+const CurrentSchemaVersion = 2
+
+// schemaMigration upgrades a settings file from one schema version to the
+// next. It edits the raw JSON so fields this build no longer models survive.
+type schemaMigration struct {
+	from        int
+	description string
+	migrate     func(file map[string]json.RawMessage) error
+}
+
+// schemaMigrations run in order on files older than CurrentSchemaVersion
+var schemaMigrations = []schemaMigration{
+	{
+		from:        1,
+		description: "introduce schemaVersion; unknown fields are preserved from now on",
+		migrate:     func(map[string]json.RawMessage) error { return nil },
+	},
+}
+
+// fileSchemaVersion returns the schemaVersion of a raw settings file; files
+// written before schema versioning are version 1
+func fileSchemaVersion(file map[string]json.RawMessage) (int, error) {
+	raw, ok := file["schemaVersion"]
+	if !ok {
+		return 1, nil
+	}
+	var v int
+	if err := json.Unmarshal(raw, &v); err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid schemaVersion %s", raw)
+	}
+	return v, nil
+}
+
+// migrateSettingsFile upgrades data to CurrentSchemaVersion and returns the
+// schema version it was written with. Files from a newer build are returned
+// unchanged so a downgraded agent can still read them; their unknown fields
+// are kept on save.
+func migrateSettingsFile(data []byte) ([]byte, int, error) {
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, 0, err
+	}
+	version, err := fileSchemaVersion(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version >= CurrentSchemaVersion {
+		return data, version, nil
+	}
+
+	from := version
+	for _, m := range schemaMigrations {
+		if m.from != version {
+			continue
+		}
+		if err := m.migrate(file); err != nil {
+			return nil, 0, fmt.Errorf("migrate settings schema %d: %w", m.from, err)
+		}
+		version = m.from + 1
+		log.Printf("[settings] migrated settings schema %d -> %d: %s", m.from, version, m.description)
+	}
+	if version != CurrentSchemaVersion {
+		return nil, 0, fmt.Errorf("no migration from settings schema %d", version)
+	}
+	file["schemaVersion"] = json.RawMessage(fmt.Sprint(version))
+	migrated, err := json.Marshal(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	return migrated, from, nil
+}
+
+// backupSettingsFile keeps a copy of the file as written before a migration
+func backupSettingsFile(path string, data []byte, version int) {
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := os.WriteFile(backup, data, settingsFileMode); err != nil {
+		log.Printf("[settings] could not back up %s before migrating: %v", path, err)
+	}
+}
+
+// knownFieldsCache maps a struct type to its JSON field names
+var knownFieldsCache sync.Map
+
+func knownJSONFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+// unknownJSONFields returns the members of a JSON object that v's type does
+// not declare, e.g. settings written by a newer agent
+func unknownJSONFields(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	known := knownJSONFields(reflect.TypeOf(v))
+	var unknown map[string]json.RawMessage
+	for name, value := range all {
+		if known[name] {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(map[string]json.RawMessage)
+		}
+		unknown[name] = value
+	}
+	return unknown, nil
+}
+
+// withUnknownJSONFields adds preserved members back to an encoded object
+func withUnknownJSONFields(data []byte, unknown map[string]json.RawMessage) ([]byte, error) {
+	if len(unknown) == 0 {
+		return data, nil
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for name, value := range unknown {
+		if _, ok := all[name]; !ok {
+			all[name] = value
+		}
+	}
+	return json.Marshal(all)
+}
+
+// UnmarshalJSON keeps top-level fields this build doesn't know
+func (f *SettingsFile) UnmarshalJSON(data []byte) error {
+	type plain SettingsFile
+	if err := json.Unmarshal(data, (*plain)(f)); err != nil {
+		return err
+	}
+	unknown, err := unknownJSONFields(data, plain{})
+	f.unknown = unknown
+	return err
+}
+
+// MarshalJSON writes back the preserved top-level fields
+func (f SettingsFile) MarshalJSON() ([]byte, error) {
+	type plain SettingsFile
+	data, err := json.Marshal(plain(f))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownJSONFields(data, f.unknown)
+}
+
+// UnmarshalJSON keeps settings this build doesn't know
+func (p *PlaintextSettings) UnmarshalJSON(data []byte) error {
+	type plain PlaintextSettings
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	unknown, err := unknownJSONFields(data, plain{})
+	p.unknown = unknown
+	return err
+}
+
+// MarshalJSON writes back the preserved settings
+func (p PlaintextSettings) MarshalJSON() ([]byte, error) {
+	type plain PlaintextSettings
+	data, err := json.Marshal(plain(p))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownJSONFields(data, p.unknown)
+}
+
+// UnmarshalJSON keeps encrypted fields this build doesn't know
+func (e *EncryptedSettings) UnmarshalJSON(data []byte) error {
+	type plain EncryptedSettings
+	if err := json.Unmarshal(data, (*plain)(e)); err != nil {
+		return err
+	}
+	unknown, err := unknownJSONFields(data, plain{})
+	e.unknown = unknown
+	return err
+}
+
+// MarshalJSON writes back the preserved encrypted fields
+func (e EncryptedSettings) MarshalJSON() ([]byte, error) {
+	type plain EncryptedSettings
+	data, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+	return withUnknownJSONFields(data, e.unknown)
+}
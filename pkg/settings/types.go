@@ -7,11 +7,17 @@ import (
 
 // SettingsFile is the top-level structure for ~/.kc/settings.json
 type SettingsFile struct {
-	Version        int               `json:"version"`
+	Version int `json:"version"` // file format, always 1
+	// SchemaVersion is the settings schema the file was written with; older
+	// files are migrated on load, see CurrentSchemaVersion
+	SchemaVersion  int               `json:"schemaVersion"`
 	LastModified   string            `json:"lastModified"`
 	Settings       PlaintextSettings `json:"settings"`
 	Encrypted      EncryptedSettings `json:"encrypted"`
 	KeyFingerprint string            `json:"keyFingerprint"`
+
+	// unknown holds fields written by a newer agent, kept on save
+	unknown map[string]json.RawMessage
 }
 
 // PlaintextSettings holds non-sensitive user preferences
@@ -26,6 +32,29 @@ type PlaintextSettings struct {
 	Accessibility AccessibilitySettings `json:"accessibility"`
 	Profile       ProfileSettings       `json:"profile"`
 	Widget        WidgetSettings        `json:"widget"`
+	// TokenBudgets maps provider name (or "*" for all providers) to usage limits
+	TokenBudgets map[string]TokenBudget `json:"tokenBudgets,omitempty"`
+	// PredictionAnalyzers maps analyzer name to its configuration
+	PredictionAnalyzers map[string]PredictionAnalyzerConfig `json:"predictionAnalyzers,omitempty"`
+	// AlertSilences suppresses matching alerts until they expire
+	AlertSilences []AlertSilence `json:"alertSilences,omitempty"`
+	// DeviceThresholds sets expected hardware device counts per node pool
+	DeviceThresholds []DeviceThreshold `json:"deviceThresholds,omitempty"`
+	// GPUOperatorChecks maps a GPU stack (nvidia, amd, habana, intel) to the operator pods checked on its nodes
+	GPUOperatorChecks map[string][]GPUOperatorCheck `json:"gpuOperatorChecks,omitempty"`
+	// ClusterFilters limits which kubeconfig contexts are listed and queried
+	ClusterFilters *ClusterFilters `json:"clusterFilters,omitempty"`
+	// ClusterGroups tags clusters with user-defined groups (prod, staging, gpu-fleet)
+	ClusterGroups []ClusterGroup `json:"clusterGroups,omitempty"`
+	// ClusterPreferences maps a context name to its display name, color and pin state
+	ClusterPreferences map[string]ClusterPreference `json:"clusterPreferences,omitempty"`
+	// ClusterTimeouts maps a context name, or "*" for every cluster, to its request timeouts
+	ClusterTimeouts map[string]ClusterTimeouts `json:"clusterTimeouts,omitempty"`
+	// ReportSchedules delivers periodic cluster summaries by email or Slack
+	ReportSchedules []ReportSchedule `json:"reportSchedules,omitempty"`
+
+	// unknown holds settings written by a newer agent, kept on save
+	unknown map[string]json.RawMessage
 }
 
 // PredictionSettings mirrors the frontend PredictionSettings type
@@ -46,6 +75,113 @@ type PredictionThresholds struct {
 	GPUMemoryPressure int `json:"gpuMemoryPressure"`
 }
 
+// PredictionAnalyzerConfig configures one heuristic prediction analyzer.
+// Analyzers without an entry run with their default thresholds.
+type PredictionAnalyzerConfig struct {
+	Enabled bool `json:"enabled"`
+	// Interval is the minimum number of minutes between runs (0 = every analysis pass)
+	Interval   int                `json:"interval,omitempty"`
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+	// Clusters holds per-cluster overrides keyed by cluster name
+	Clusters map[string]PredictionAnalyzerOverride `json:"clusters,omitempty"`
+}
+
+// PredictionAnalyzerOverride overrides analyzer settings for a single cluster.
+// Unset fields inherit from the analyzer's PredictionAnalyzerConfig.
+type PredictionAnalyzerOverride struct {
+	Enabled    *bool              `json:"enabled,omitempty"`
+	Interval   *int               `json:"interval,omitempty"`
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+}
+
+// AlertSilence mutes alerts that match all of its non-empty fields until ExpiresAt.
+// AlertType is either a family ("device", "prediction") or a
+// specific type within it ("device:gpu", "prediction:pod-crash").
+type AlertSilence struct {
+	ID        string `json:"id"`
+	Cluster   string `json:"cluster,omitempty"`
+	Node      string `json:"node,omitempty"`
+	AlertType string `json:"alertType,omitempty"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"createdAt"` // RFC3339
+	ExpiresAt string `json:"expiresAt"` // RFC3339
+}
+
+// DeviceThreshold raises a device alert when nodes in a pool report fewer
+// devices than expected, e.g. fewer InfiniBand HCAs than the hardware has.
+type DeviceThreshold struct {
+	Cluster    string `json:"cluster,omitempty"`  // empty matches all clusters
+	NodePool   string `json:"nodePool,omitempty"` // node label selector; empty matches all nodes
+	DeviceType string `json:"deviceType"`         // gpu, nic, nvme, infiniband
+	MinCount   int    `json:"minCount"`
+	Severity   string `json:"severity,omitempty"` // warning (default) or critical
+}
+
+// ReportSchedule renders a cluster summary (health, GPU allocation, top issues,
+// cost estimate) daily or weekly and delivers it through a notification channel
+type ReportSchedule struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+	Frequency string `json:"frequency"`          // daily or weekly
+	Weekday   string `json:"weekday,omitempty"`  // weekly reports only, e.g. monday
+	Time      string `json:"time"`               // HH:MM in TimeZone
+	TimeZone  string `json:"timeZone,omitempty"` // IANA name; empty means UTC
+	// Sections lists health, gpu, issues and cost; empty includes all
+	Sections []string `json:"sections,omitempty"`
+	Format   string   `json:"format,omitempty"` // html (default) or markdown
+	Channel  string   `json:"channel"`          // email or slack
+	// Recipients overrides the configured email recipients
+	Recipients []string `json:"recipients,omitempty"`
+	// Clusters limits the report to these contexts; empty covers every cluster
+	Clusters   []string         `json:"clusters,omitempty"`
+	CostRates  *ReportCostRates `json:"costRates,omitempty"`
+	LastSentAt string           `json:"lastSentAt,omitempty"` // RFC3339
+}
+
+// ReportCostRates are the hourly prices used for report cost estimates
+type ReportCostRates struct {
+	CPUCoreHour  float64 `json:"cpuCoreHour"`
+	MemoryGBHour float64 `json:"memoryGBHour"`
+	GPUHour      float64 `json:"gpuHour"`
+}
+
+// ClusterFilters limits which contexts the console shows and fans out to. Patterns
+// are regular expressions matched against the whole context name; exclude wins.
+type ClusterFilters struct {
+	Include []string `json:"include,omitempty"` // empty includes every cluster
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// ClusterGroup is a user-defined tag applied to the matching clusters
+type ClusterGroup struct {
+	Name     string   `json:"name"`
+	Clusters []string `json:"clusters"` // context names or regular expressions
+}
+
+// ClusterPreference customizes how a cluster is shown in every view
+type ClusterPreference struct {
+	DisplayName string `json:"displayName,omitempty"`
+	Color       string `json:"color,omitempty"` // #rgb or #rrggbb
+	Pinned      bool   `json:"pinned,omitempty"`
+}
+
+// ClusterTimeouts overrides request timeouts per operation class for slow WAN or
+// fast local clusters. Values are Go durations ("90s", "2m"); empty keeps the default.
+type ClusterTimeouts struct {
+	List   string `json:"list,omitempty"`   // listing resources
+	Health string `json:"health,omitempty"` // reachability and health probes
+	Logs   string `json:"logs,omitempty"`   // fetching pod logs
+}
+
+// GPUOperatorCheck is an operator DaemonSet expected on every node of a GPU stack
+type GPUOperatorCheck struct {
+	Name       string   `json:"name"`
+	PodNames   []string `json:"podNames"`             // pod name substrings; any match counts
+	Namespaces []string `json:"namespaces,omitempty"` // extra namespaces to search
+	Exclude    []string `json:"exclude,omitempty"`    // pod name substrings to ignore
+}
+
 // TokenUsageSettings holds token limit and threshold configuration
 type TokenUsageSettings struct {
 	Limit             int     `json:"limit"`
@@ -54,6 +190,25 @@ type TokenUsageSettings struct {
 	StopThreshold     float64 `json:"stopThreshold"`
 }
 
+// TokenBudget limits AI usage for a provider. Zero values mean "no limit".
+type TokenBudget struct {
+	DailyTokens   int64   `json:"dailyTokens,omitempty"`
+	MonthlyTokens int64   `json:"monthlyTokens,omitempty"`
+	DailyUSD      float64 `json:"dailyUsd,omitempty"`
+	MonthlyUSD    float64 `json:"monthlyUsd,omitempty"`
+	// Prices per million tokens, used to convert usage into dollars
+	InputCostPerMTok  float64 `json:"inputCostPerMTok,omitempty"`
+	OutputCostPerMTok float64 `json:"outputCostPerMTok,omitempty"`
+	// Action when a budget is exceeded: "reject" (default) or "warn"
+	Action string `json:"action,omitempty"`
+}
+
+// Token budget actions
+const (
+	TokenBudgetActionReject = "reject"
+	TokenBudgetActionWarn   = "warn"
+)
+
 // AccessibilitySettings holds UI accessibility preferences
 type AccessibilitySettings struct {
 	ColorBlindMode bool `json:"colorBlindMode"`
@@ -83,6 +238,9 @@ type EncryptedSettings struct {
 	APIKeys       *EncryptedField `json:"apiKeys,omitempty"`
 	GitHubToken   *EncryptedField `json:"githubToken,omitempty"`
 	Notifications *EncryptedField `json:"notifications,omitempty"`
+
+	// unknown holds encrypted fields written by a newer agent, kept on save
+	unknown map[string]json.RawMessage
 }
 
 // AllSettings is the combined decrypted view sent to/from the frontend
@@ -98,6 +256,46 @@ type AllSettings struct {
 	Profile       ProfileSettings       `json:"profile"`
 	Widget        WidgetSettings        `json:"widget"`
 
+	// TokenBudgets maps provider name (or "*" for all providers) to usage limits.
+	// A nil map on save leaves the stored budgets unchanged.
+	TokenBudgets map[string]TokenBudget `json:"tokenBudgets,omitempty"`
+
+	// PredictionAnalyzers maps analyzer name to its configuration.
+	// A nil map on save leaves the stored configuration unchanged.
+	PredictionAnalyzers map[string]PredictionAnalyzerConfig `json:"predictionAnalyzers,omitempty"`
+
+	// AlertSilences suppresses matching alerts until they expire.
+	// A nil slice on save leaves the stored silences unchanged.
+	AlertSilences []AlertSilence `json:"alertSilences,omitempty"`
+
+	// DeviceThresholds sets expected hardware device counts per node pool.
+	// A nil slice on save leaves the stored thresholds unchanged.
+	DeviceThresholds []DeviceThreshold `json:"deviceThresholds,omitempty"`
+
+	// GPUOperatorChecks overrides the operator pods checked per GPU stack.
+	// A nil map on save leaves the stored overrides unchanged.
+	GPUOperatorChecks map[string][]GPUOperatorCheck `json:"gpuOperatorChecks,omitempty"`
+
+	// ClusterFilters limits which kubeconfig contexts are listed and queried.
+	// Nil on save leaves the stored filters unchanged.
+	ClusterFilters *ClusterFilters `json:"clusterFilters,omitempty"`
+
+	// ClusterGroups tags clusters with user-defined groups.
+	// A nil slice on save leaves the stored groups unchanged.
+	ClusterGroups []ClusterGroup `json:"clusterGroups,omitempty"`
+
+	// ClusterPreferences holds per-cluster display names, colors and pins.
+	// A nil map on save leaves the stored preferences unchanged.
+	ClusterPreferences map[string]ClusterPreference `json:"clusterPreferences,omitempty"`
+
+	// ClusterTimeouts overrides request timeouts per cluster and operation class.
+	// A nil map on save leaves the stored timeouts unchanged.
+	ClusterTimeouts map[string]ClusterTimeouts `json:"clusterTimeouts,omitempty"`
+
+	// ReportSchedules delivers periodic cluster summaries by email or Slack.
+	// A nil slice on save leaves the stored schedules unchanged.
+	ReportSchedules []ReportSchedule `json:"reportSchedules,omitempty"`
+
 	// Auto-update configuration
 	AutoUpdateEnabled bool   `json:"autoUpdateEnabled"`
 	AutoUpdateChannel string `json:"autoUpdateChannel"`
@@ -148,7 +346,8 @@ type NotificationSecrets struct {
 // DefaultSettings returns a SettingsFile with sensible defaults
 func DefaultSettings() *SettingsFile {
 	return &SettingsFile{
-		Version: 1,
+		Version:       1,
+		SchemaVersion: CurrentSchemaVersion,
 		Settings: PlaintextSettings{
 			AIMode: "medium",
 			Predictions: PredictionSettings{
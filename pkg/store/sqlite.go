@@ -195,6 +195,125 @@ func (s *SQLiteStore) migrate() error {
 		FOREIGN KEY (reservation_id) REFERENCES gpu_reservations(id) ON DELETE CASCADE
 	);
 	CREATE INDEX IF NOT EXISTS idx_utilization_reservation ON gpu_utilization_snapshots(reservation_id, timestamp);
+
+	-- Security posture snapshots (per cluster, and per namespace within it)
+	CREATE TABLE IF NOT EXISTS security_posture_snapshots (
+		id TEXT PRIMARY KEY,
+		cluster TEXT NOT NULL,
+		namespace TEXT NOT NULL DEFAULT '',
+		timestamp DATETIME NOT NULL,
+		score REAL NOT NULL,
+		pods INTEGER NOT NULL,
+		high_issues INTEGER NOT NULL,
+		medium_issues INTEGER NOT NULL,
+		low_issues INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_security_posture_cluster ON security_posture_snapshots(cluster, namespace, timestamp);
+
+	-- Cluster state snapshots (nodes, failing deployments, GPUs, versions) for change reports
+	CREATE TABLE IF NOT EXISTS cluster_state_snapshots (
+		id TEXT PRIMARY KEY,
+		cluster TEXT NOT NULL,
+		timestamp DATETIME NOT NULL,
+		state TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_cluster_state_cluster ON cluster_state_snapshots(cluster, timestamp);
+
+	-- Pod lifecycle steps, kept after the pod and its events are gone
+	CREATE TABLE IF NOT EXISTS pod_timeline_events (
+		id TEXT PRIMARY KEY,
+		cluster TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		pod TEXT NOT NULL,
+		pod_uid TEXT DEFAULT '',
+		container TEXT DEFAULT '',
+		stage TEXT NOT NULL,
+		reason TEXT DEFAULT '',
+		message TEXT DEFAULT '',
+		count INTEGER DEFAULT 0,
+		source TEXT NOT NULL,
+		timestamp DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_pod_timeline_pod ON pod_timeline_events(cluster, namespace, pod, timestamp);
+	CREATE INDEX IF NOT EXISTS idx_pod_timeline_timestamp ON pod_timeline_events(timestamp);
+
+	-- Automation rules ("if trigger, do actions") and the audit log of their runs
+	CREATE TABLE IF NOT EXISTS automation_rules (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		enabled INTEGER DEFAULT 1,
+		dry_run INTEGER DEFAULT 0,
+		trigger_config TEXT NOT NULL,
+		actions TEXT NOT NULL,
+		cooldown_minutes INTEGER NOT NULL,
+		last_fired_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME
+	);
+
+	-- Runs outlive their rule so the audit log stays complete
+	CREATE TABLE IF NOT EXISTS automation_runs (
+		id TEXT PRIMARY KEY,
+		rule_id TEXT NOT NULL,
+		rule_name TEXT NOT NULL,
+		trigger_type TEXT NOT NULL,
+		action TEXT NOT NULL,
+		cluster TEXT NOT NULL,
+		namespace TEXT DEFAULT '',
+		target TEXT NOT NULL,
+		status TEXT NOT NULL,
+		dry_run INTEGER DEFAULT 0,
+		message TEXT DEFAULT '',
+		triggered_by TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_automation_runs_rule ON automation_runs(rule_id, created_at);
+	CREATE INDEX IF NOT EXISTS idx_automation_runs_created ON automation_runs(created_at);
+
+	-- Detected issues keyed by a stable fingerprint, with their history
+	CREATE TABLE IF NOT EXISTS issues (
+		id TEXT PRIMARY KEY,
+		kind TEXT NOT NULL,
+		cluster TEXT NOT NULL,
+		namespace TEXT DEFAULT '',
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		severity TEXT DEFAULT '',
+		message TEXT DEFAULT '',
+		status TEXT NOT NULL,
+		occurrences INTEGER DEFAULT 1,
+		first_seen DATETIME NOT NULL,
+		last_seen DATETIME NOT NULL,
+		resolved_at DATETIME,
+		acknowledged_by TEXT DEFAULT '',
+		acknowledged_at DATETIME,
+		assignee TEXT DEFAULT '',
+		snoozed_until DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_issues_status ON issues(status, last_seen);
+	CREATE INDEX IF NOT EXISTS idx_issues_cluster ON issues(cluster, kind);
+
+	CREATE TABLE IF NOT EXISTS issue_events (
+		id TEXT PRIMARY KEY,
+		issue_id TEXT NOT NULL,
+		action TEXT NOT NULL,
+		actor TEXT NOT NULL,
+		detail TEXT DEFAULT '',
+		created_at DATETIME NOT NULL,
+		FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_issue_events_issue ON issue_events(issue_id, created_at);
+
+	-- kc-agents federated behind this console
+	CREATE TABLE IF NOT EXISTS remote_agents (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		url TEXT NOT NULL,
+		token TEXT DEFAULT '',
+		created_by TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	);
 	`
 	_, err := s.db.Exec(schema)
 	if err != nil {
@@ -1459,6 +1578,51 @@ func (s *SQLiteStore) DeleteOldUtilizationSnapshots(before time.Time) (int64, er
 	return res.RowsAffected()
 }
 
+// --- Security Posture Snapshots ---
+
+func (s *SQLiteStore) InsertSecurityPostureSnapshot(snapshot *models.SecurityPostureSnapshot) error {
+	_, err := s.db.Exec(
+		`INSERT INTO security_posture_snapshots (id, cluster, namespace, timestamp, score, pods, high_issues, medium_issues, low_issues) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		snapshot.ID, snapshot.Cluster, snapshot.Namespace, snapshot.Timestamp,
+		snapshot.Score, snapshot.Pods,
+		snapshot.HighIssues, snapshot.MediumIssues, snapshot.LowIssues,
+	)
+	return err
+}
+
+// GetSecurityPostureSnapshots returns snapshots taken since the given time,
+// for one cluster or for all clusters when cluster is empty
+func (s *SQLiteStore) GetSecurityPostureSnapshots(cluster string, since time.Time) ([]models.SecurityPostureSnapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT id, cluster, namespace, timestamp, score, pods, high_issues, medium_issues, low_issues FROM security_posture_snapshots WHERE (? = '' OR cluster = ?) AND timestamp >= ? ORDER BY timestamp ASC`,
+		cluster, cluster, since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []models.SecurityPostureSnapshot
+	for rows.Next() {
+		var snap models.SecurityPostureSnapshot
+		if err := rows.Scan(&snap.ID, &snap.Cluster, &snap.Namespace, &snap.Timestamp,
+			&snap.Score, &snap.Pods,
+			&snap.HighIssues, &snap.MediumIssues, &snap.LowIssues); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteOldSecurityPostureSnapshots(before time.Time) (int64, error) {
+	res, err := s.db.Exec(`DELETE FROM security_posture_snapshots WHERE timestamp < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 func (s *SQLiteStore) ListActiveGPUReservations() ([]models.GPUReservation, error) {
 	rows, err := s.db.Query(
 		`SELECT id, user_id, user_name, title, description, cluster, namespace, gpu_count, gpu_type, start_date, duration_hours, notes, status, quota_name, quota_enforced, created_at, updated_at FROM gpu_reservations WHERE status IN ('active', 'pending') ORDER BY start_date DESC`,
@@ -1479,6 +1643,470 @@ func (s *SQLiteStore) ListActiveGPUReservations() ([]models.GPUReservation, erro
 	return reservations, rows.Err()
 }
 
+// --- Cluster State Snapshots ---
+
+func (s *SQLiteStore) InsertClusterStateSnapshot(snapshot *models.ClusterStateSnapshot) error {
+	_, err := s.db.Exec(
+		`INSERT INTO cluster_state_snapshots (id, cluster, timestamp, state) VALUES (?, ?, ?, ?)`,
+		snapshot.ID, snapshot.Cluster, snapshot.Timestamp, string(snapshot.State),
+	)
+	return err
+}
+
+// GetClusterStateSnapshotAt returns the latest snapshot of a cluster taken at
+// or before the given time, or nil when there is none
+func (s *SQLiteStore) GetClusterStateSnapshotAt(cluster string, at time.Time) (*models.ClusterStateSnapshot, error) {
+	var snap models.ClusterStateSnapshot
+	var state string
+	err := s.db.QueryRow(
+		`SELECT id, cluster, timestamp, state FROM cluster_state_snapshots WHERE cluster = ? AND timestamp <= ? ORDER BY timestamp DESC LIMIT 1`,
+		cluster, at,
+	).Scan(&snap.ID, &snap.Cluster, &snap.Timestamp, &state)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snap.State = json.RawMessage(state)
+	return &snap, nil
+}
+
+func (s *SQLiteStore) DeleteOldClusterStateSnapshots(before time.Time) (int64, error) {
+	res, err := s.db.Exec(`DELETE FROM cluster_state_snapshots WHERE timestamp < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// --- Pod Timeline Events ---
+
+// UpsertPodTimelineEvents records timeline events in one transaction. Events
+// seen again (an aggregated Kubernetes event firing once more) update their
+// count, message and timestamp.
+func (s *SQLiteStore) UpsertPodTimelineEvents(events []models.PodTimelineEvent) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(
+		`INSERT INTO pod_timeline_events (id, cluster, namespace, pod, pod_uid, container, stage, reason, message, count, source, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET message = excluded.message, count = excluded.count, timestamp = excluded.timestamp`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(e.ID, e.Cluster, e.Namespace, e.Pod, e.PodUID, e.Container,
+			e.Stage, e.Reason, e.Message, e.Count, e.Source, e.Timestamp); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPodTimelineEvents returns the recorded events of a pod oldest first,
+// across every pod that had that name
+func (s *SQLiteStore) GetPodTimelineEvents(cluster, namespace, pod string) ([]models.PodTimelineEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT id, cluster, namespace, pod, pod_uid, container, stage, reason, message, count, source, timestamp FROM pod_timeline_events WHERE cluster = ? AND namespace = ? AND pod = ? ORDER BY timestamp ASC`,
+		cluster, namespace, pod,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.PodTimelineEvent
+	for rows.Next() {
+		var e models.PodTimelineEvent
+		if err := rows.Scan(&e.ID, &e.Cluster, &e.Namespace, &e.Pod, &e.PodUID, &e.Container,
+			&e.Stage, &e.Reason, &e.Message, &e.Count, &e.Source, &e.Timestamp); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteOldPodTimelineEvents(before time.Time) (int64, error) {
+	res, err := s.db.Exec(`DELETE FROM pod_timeline_events WHERE timestamp < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// --- Automation Rules ---
+
+const automationRuleColumns = `id, user_id, name, enabled, dry_run, trigger_config, actions, cooldown_minutes, last_fired_at, created_at, updated_at`
+
+func (s *SQLiteStore) CreateAutomationRule(rule *models.AutomationRule) error {
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	rule.CreatedAt = time.Now()
+
+	triggerJSON, actionsJSON, err := marshalAutomationRule(rule)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO automation_rules (id, user_id, name, enabled, dry_run, trigger_config, actions, cooldown_minutes, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rule.ID.String(), rule.UserID.String(), rule.Name, boolToInt(rule.Enabled), boolToInt(rule.DryRun),
+		triggerJSON, actionsJSON, rule.CooldownMinutes, rule.CreatedAt)
+	return err
+}
+
+func (s *SQLiteStore) GetAutomationRule(id uuid.UUID) (*models.AutomationRule, error) {
+	row := s.db.QueryRow(`SELECT `+automationRuleColumns+` FROM automation_rules WHERE id = ?`, id.String())
+	rule, err := scanAutomationRule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rule, err
+}
+
+func (s *SQLiteStore) ListAutomationRules() ([]models.AutomationRule, error) {
+	rows, err := s.db.Query(`SELECT ` + automationRuleColumns + ` FROM automation_rules ORDER BY created_at ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.AutomationRule
+	for rows.Next() {
+		rule, err := scanAutomationRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (s *SQLiteStore) UpdateAutomationRule(rule *models.AutomationRule) error {
+	now := time.Now()
+	rule.UpdatedAt = &now
+
+	triggerJSON, actionsJSON, err := marshalAutomationRule(rule)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE automation_rules SET name = ?, enabled = ?, dry_run = ?, trigger_config = ?, actions = ?, cooldown_minutes = ?, updated_at = ? WHERE id = ?`,
+		rule.Name, boolToInt(rule.Enabled), boolToInt(rule.DryRun), triggerJSON, actionsJSON,
+		rule.CooldownMinutes, rule.UpdatedAt, rule.ID.String())
+	return err
+}
+
+// SetAutomationRuleFired records when a rule last acted
+func (s *SQLiteStore) SetAutomationRuleFired(id uuid.UUID, firedAt time.Time) error {
+	_, err := s.db.Exec(`UPDATE automation_rules SET last_fired_at = ? WHERE id = ?`, firedAt, id.String())
+	return err
+}
+
+func (s *SQLiteStore) DeleteAutomationRule(id uuid.UUID) error {
+	_, err := s.db.Exec(`DELETE FROM automation_rules WHERE id = ?`, id.String())
+	return err
+}
+
+func (s *SQLiteStore) InsertAutomationRun(run *models.AutomationRun) error {
+	if run.ID == uuid.Nil {
+		run.ID = uuid.New()
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO automation_runs (id, rule_id, rule_name, trigger_type, action, cluster, namespace, target, status, dry_run, message, triggered_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID.String(), run.RuleID.String(), run.RuleName, string(run.Trigger), string(run.Action),
+		run.Cluster, run.Namespace, run.Target, string(run.Status), boolToInt(run.DryRun),
+		run.Message, run.TriggeredBy, run.CreatedAt)
+	return err
+}
+
+// ListAutomationRuns returns the newest runs first, of one rule or of all
+// rules when ruleID is nil
+func (s *SQLiteStore) ListAutomationRuns(ruleID *uuid.UUID, limit int) ([]models.AutomationRun, error) {
+	filter := ""
+	if ruleID != nil {
+		filter = ruleID.String()
+	}
+	rows, err := s.db.Query(
+		`SELECT id, rule_id, rule_name, trigger_type, action, cluster, namespace, target, status, dry_run, message, triggered_by, created_at FROM automation_runs WHERE (? = '' OR rule_id = ?) ORDER BY created_at DESC LIMIT ?`,
+		filter, filter, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.AutomationRun
+	for rows.Next() {
+		var run models.AutomationRun
+		var idStr, ruleIDStr, trigger, action, status string
+		var dryRun int
+		if err := rows.Scan(&idStr, &ruleIDStr, &run.RuleName, &trigger, &action,
+			&run.Cluster, &run.Namespace, &run.Target, &status, &dryRun,
+			&run.Message, &run.TriggeredBy, &run.CreatedAt); err != nil {
+			return nil, err
+		}
+		run.ID, _ = uuid.Parse(idStr)
+		run.RuleID, _ = uuid.Parse(ruleIDStr)
+		run.Trigger = models.AutomationTriggerType(trigger)
+		run.Action = models.AutomationActionType(action)
+		run.Status = models.AutomationRunStatus(status)
+		run.DryRun = dryRun == 1
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func marshalAutomationRule(rule *models.AutomationRule) (string, string, error) {
+	triggerJSON, err := json.Marshal(rule.Trigger)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal automation trigger: %w", err)
+	}
+	actionsJSON, err := json.Marshal(rule.Actions)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal automation actions: %w", err)
+	}
+	return string(triggerJSON), string(actionsJSON), nil
+}
+
+// scanAutomationRule reads one automation_rules row from a *sql.Row or *sql.Rows
+func scanAutomationRule(row interface{ Scan(...any) error }) (*models.AutomationRule, error) {
+	var r models.AutomationRule
+	var idStr, userIDStr, triggerJSON, actionsJSON string
+	var enabled, dryRun int
+	var lastFiredAt, updatedAt sql.NullTime
+
+	if err := row.Scan(&idStr, &userIDStr, &r.Name, &enabled, &dryRun, &triggerJSON, &actionsJSON,
+		&r.CooldownMinutes, &lastFiredAt, &r.CreatedAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(triggerJSON), &r.Trigger); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automation trigger: %w", err)
+	}
+	if err := json.Unmarshal([]byte(actionsJSON), &r.Actions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automation actions: %w", err)
+	}
+	r.ID, _ = uuid.Parse(idStr)
+	r.UserID, _ = uuid.Parse(userIDStr)
+	r.Enabled = enabled == 1
+	r.DryRun = dryRun == 1
+	if lastFiredAt.Valid {
+		r.LastFiredAt = &lastFiredAt.Time
+	}
+	if updatedAt.Valid {
+		r.UpdatedAt = &updatedAt.Time
+	}
+	return &r, nil
+}
+
+// --- Issues ---
+
+const issueColumns = `id, kind, cluster, namespace, name, type, severity, message, status, occurrences, first_seen, last_seen, resolved_at, acknowledged_by, acknowledged_at, assignee, snoozed_until`
+
+// UpsertIssue inserts an issue or replaces the stored state of an existing one
+func (s *SQLiteStore) UpsertIssue(issue *models.Issue) error {
+	_, err := s.db.Exec(
+		`INSERT INTO issues (`+issueColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET severity = excluded.severity, message = excluded.message, status = excluded.status,
+			occurrences = excluded.occurrences, last_seen = excluded.last_seen, resolved_at = excluded.resolved_at,
+			acknowledged_by = excluded.acknowledged_by, acknowledged_at = excluded.acknowledged_at,
+			assignee = excluded.assignee, snoozed_until = excluded.snoozed_until`,
+		issue.ID, string(issue.Kind), issue.Cluster, issue.Namespace, issue.Name, issue.Type,
+		issue.Severity, issue.Message, string(issue.Status), issue.Occurrences,
+		issue.FirstSeen, issue.LastSeen, issue.ResolvedAt,
+		issue.AcknowledgedBy, issue.AcknowledgedAt, issue.Assignee, issue.SnoozedUntil,
+	)
+	return err
+}
+
+func (s *SQLiteStore) GetIssue(id string) (*models.Issue, error) {
+	row := s.db.QueryRow(`SELECT `+issueColumns+` FROM issues WHERE id = ?`, id)
+	issue, err := scanIssue(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return issue, err
+}
+
+// ListIssues returns the issues matching the filter, most recently seen first
+func (s *SQLiteStore) ListIssues(filter models.IssueFilter) ([]models.Issue, error) {
+	query := `SELECT ` + issueColumns + ` FROM issues WHERE (? = '' OR status = ?) AND (? = '' OR kind = ?) AND (? = '' OR cluster = ?) ORDER BY last_seen DESC`
+	args := []any{string(filter.Status), string(filter.Status), string(filter.Kind), string(filter.Kind), filter.Cluster, filter.Cluster}
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []models.Issue
+	for rows.Next() {
+		issue, err := scanIssue(rows)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, *issue)
+	}
+	return issues, rows.Err()
+}
+
+// DeleteResolvedIssues removes issues resolved before the given time along
+// with their history
+func (s *SQLiteStore) DeleteResolvedIssues(before time.Time) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM issue_events WHERE issue_id IN (SELECT id FROM issues WHERE status = ? AND resolved_at < ?)`,
+		string(models.IssueStatusResolved), before); err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(`DELETE FROM issues WHERE status = ? AND resolved_at < ?`, string(models.IssueStatusResolved), before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return deleted, tx.Commit()
+}
+
+func (s *SQLiteStore) InsertIssueEvent(event *models.IssueEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO issue_events (id, issue_id, action, actor, detail, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		event.ID.String(), event.IssueID, string(event.Action), event.Actor, event.Detail, event.CreatedAt,
+	)
+	return err
+}
+
+// ListIssueEvents returns the history of an issue, newest first
+func (s *SQLiteStore) ListIssueEvents(issueID string, limit int) ([]models.IssueEvent, error) {
+	rows, err := s.db.Query(
+		`SELECT id, issue_id, action, actor, detail, created_at FROM issue_events WHERE issue_id = ? ORDER BY created_at DESC LIMIT ?`,
+		issueID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.IssueEvent
+	for rows.Next() {
+		var e models.IssueEvent
+		var idStr, action string
+		if err := rows.Scan(&idStr, &e.IssueID, &action, &e.Actor, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.ID, _ = uuid.Parse(idStr)
+		e.Action = models.IssueAction(action)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// scanIssue reads one issues row from a *sql.Row or *sql.Rows
+func scanIssue(row interface{ Scan(...any) error }) (*models.Issue, error) {
+	var i models.Issue
+	var kind, status string
+	var resolvedAt, acknowledgedAt, snoozedUntil sql.NullTime
+
+	if err := row.Scan(&i.ID, &kind, &i.Cluster, &i.Namespace, &i.Name, &i.Type, &i.Severity, &i.Message,
+		&status, &i.Occurrences, &i.FirstSeen, &i.LastSeen, &resolvedAt,
+		&i.AcknowledgedBy, &acknowledgedAt, &i.Assignee, &snoozedUntil); err != nil {
+		return nil, err
+	}
+	i.Kind = models.IssueKind(kind)
+	i.Status = models.IssueStatus(status)
+	if resolvedAt.Valid {
+		i.ResolvedAt = &resolvedAt.Time
+	}
+	if acknowledgedAt.Valid {
+		i.AcknowledgedAt = &acknowledgedAt.Time
+	}
+	if snoozedUntil.Valid {
+		i.SnoozedUntil = &snoozedUntil.Time
+	}
+	return &i, nil
+}
+
+// --- Remote Agents ---
+
+const remoteAgentColumns = `id, name, url, token, created_by, created_at`
+
+func (s *SQLiteStore) CreateRemoteAgent(agent *models.RemoteAgent) error {
+	if agent.ID == uuid.Nil {
+		agent.ID = uuid.New()
+	}
+	agent.CreatedAt = time.Now()
+	_, err := s.db.Exec(`INSERT INTO remote_agents (`+remoteAgentColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		agent.ID.String(), agent.Name, agent.URL, agent.Token, agent.CreatedBy.String(), agent.CreatedAt)
+	return err
+}
+
+func (s *SQLiteStore) GetRemoteAgent(id uuid.UUID) (*models.RemoteAgent, error) {
+	row := s.db.QueryRow(`SELECT `+remoteAgentColumns+` FROM remote_agents WHERE id = ?`, id.String())
+	agent, err := scanRemoteAgent(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return agent, err
+}
+
+func (s *SQLiteStore) ListRemoteAgents() ([]models.RemoteAgent, error) {
+	rows, err := s.db.Query(`SELECT ` + remoteAgentColumns + ` FROM remote_agents ORDER BY name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var agents []models.RemoteAgent
+	for rows.Next() {
+		agent, err := scanRemoteAgent(rows)
+		if err != nil {
+			return nil, err
+		}
+		agents = append(agents, *agent)
+	}
+	return agents, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteRemoteAgent(id uuid.UUID) error {
+	_, err := s.db.Exec(`DELETE FROM remote_agents WHERE id = ?`, id.String())
+	return err
+}
+
+// scanRemoteAgent reads one remote_agents row from a *sql.Row or *sql.Rows
+func scanRemoteAgent(row interface{ Scan(...any) error }) (*models.RemoteAgent, error) {
+	var a models.RemoteAgent
+	var idStr, createdBy string
+	if err := row.Scan(&idStr, &a.Name, &a.URL, &a.Token, &createdBy, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	a.ID, _ = uuid.Parse(idStr)
+	a.CreatedBy, _ = uuid.Parse(createdBy)
+	return &a, nil
+}
+
 // Helper functions
 
 func nullString(s string) sql.NullString {
@@ -98,6 +98,45 @@ type Store interface {
 	DeleteOldUtilizationSnapshots(before time.Time) (int64, error)
 	ListActiveGPUReservations() ([]models.GPUReservation, error)
 
+	// Security Posture Snapshots
+	InsertSecurityPostureSnapshot(snapshot *models.SecurityPostureSnapshot) error
+	GetSecurityPostureSnapshots(cluster string, since time.Time) ([]models.SecurityPostureSnapshot, error)
+	DeleteOldSecurityPostureSnapshots(before time.Time) (int64, error)
+
+	// Cluster State Snapshots
+	InsertClusterStateSnapshot(snapshot *models.ClusterStateSnapshot) error
+	GetClusterStateSnapshotAt(cluster string, at time.Time) (*models.ClusterStateSnapshot, error)
+	DeleteOldClusterStateSnapshots(before time.Time) (int64, error)
+
+	// Pod Timeline Events
+	UpsertPodTimelineEvents(events []models.PodTimelineEvent) error
+	GetPodTimelineEvents(cluster, namespace, pod string) ([]models.PodTimelineEvent, error)
+	DeleteOldPodTimelineEvents(before time.Time) (int64, error)
+
+	// Automation Rules
+	CreateAutomationRule(rule *models.AutomationRule) error
+	GetAutomationRule(id uuid.UUID) (*models.AutomationRule, error)
+	ListAutomationRules() ([]models.AutomationRule, error)
+	UpdateAutomationRule(rule *models.AutomationRule) error
+	SetAutomationRuleFired(id uuid.UUID, firedAt time.Time) error
+	DeleteAutomationRule(id uuid.UUID) error
+	InsertAutomationRun(run *models.AutomationRun) error
+	ListAutomationRuns(ruleID *uuid.UUID, limit int) ([]models.AutomationRun, error)
+
+	// Issues
+	UpsertIssue(issue *models.Issue) error
+	GetIssue(id string) (*models.Issue, error)
+	ListIssues(filter models.IssueFilter) ([]models.Issue, error)
+	DeleteResolvedIssues(before time.Time) (int64, error)
+	InsertIssueEvent(event *models.IssueEvent) error
+	ListIssueEvents(issueID string, limit int) ([]models.IssueEvent, error)
+
+	// Remote Agents
+	CreateRemoteAgent(agent *models.RemoteAgent) error
+	GetRemoteAgent(id uuid.UUID) (*models.RemoteAgent, error)
+	ListRemoteAgents() ([]models.RemoteAgent, error)
+	DeleteRemoteAgent(id uuid.UUID) error
+
 	// Lifecycle
 	Close() error
 }
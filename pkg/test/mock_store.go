@@ -150,4 +150,51 @@ func (m *MockStore) GetBulkUtilizationSnapshots(reservationIDs []string) (map[st
 func (m *MockStore) DeleteOldUtilizationSnapshots(before time.Time) (int64, error) { return 0, nil }
 func (m *MockStore) ListActiveGPUReservations() ([]models.GPUReservation, error)   { return nil, nil }
 
+func (m *MockStore) InsertSecurityPostureSnapshot(snapshot *models.SecurityPostureSnapshot) error {
+	return nil
+}
+func (m *MockStore) GetSecurityPostureSnapshots(cluster string, since time.Time) ([]models.SecurityPostureSnapshot, error) {
+	return nil, nil
+}
+func (m *MockStore) DeleteOldSecurityPostureSnapshots(before time.Time) (int64, error) { return 0, nil }
+
+func (m *MockStore) InsertClusterStateSnapshot(snapshot *models.ClusterStateSnapshot) error {
+	return nil
+}
+func (m *MockStore) GetClusterStateSnapshotAt(cluster string, at time.Time) (*models.ClusterStateSnapshot, error) {
+	return nil, nil
+}
+func (m *MockStore) DeleteOldClusterStateSnapshots(before time.Time) (int64, error) { return 0, nil }
+
+func (m *MockStore) UpsertPodTimelineEvents(events []models.PodTimelineEvent) error { return nil }
+func (m *MockStore) GetPodTimelineEvents(cluster, namespace, pod string) ([]models.PodTimelineEvent, error) {
+	return nil, nil
+}
+func (m *MockStore) DeleteOldPodTimelineEvents(before time.Time) (int64, error) { return 0, nil }
+
+func (m *MockStore) CreateAutomationRule(rule *models.AutomationRule) error         { return nil }
+func (m *MockStore) GetAutomationRule(id uuid.UUID) (*models.AutomationRule, error) { return nil, nil }
+func (m *MockStore) ListAutomationRules() ([]models.AutomationRule, error)          { return nil, nil }
+func (m *MockStore) UpdateAutomationRule(rule *models.AutomationRule) error         { return nil }
+func (m *MockStore) SetAutomationRuleFired(id uuid.UUID, firedAt time.Time) error   { return nil }
+func (m *MockStore) DeleteAutomationRule(id uuid.UUID) error                        { return nil }
+func (m *MockStore) InsertAutomationRun(run *models.AutomationRun) error            { return nil }
+func (m *MockStore) ListAutomationRuns(ruleID *uuid.UUID, limit int) ([]models.AutomationRun, error) {
+	return nil, nil
+}
+
+func (m *MockStore) UpsertIssue(issue *models.Issue) error                        { return nil }
+func (m *MockStore) GetIssue(id string) (*models.Issue, error)                    { return nil, nil }
+func (m *MockStore) ListIssues(filter models.IssueFilter) ([]models.Issue, error) { return nil, nil }
+func (m *MockStore) DeleteResolvedIssues(before time.Time) (int64, error)         { return 0, nil }
+func (m *MockStore) InsertIssueEvent(event *models.IssueEvent) error              { return nil }
+func (m *MockStore) ListIssueEvents(issueID string, limit int) ([]models.IssueEvent, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CreateRemoteAgent(agent *models.RemoteAgent) error        { return nil }
+func (m *MockStore) GetRemoteAgent(id uuid.UUID) (*models.RemoteAgent, error) { return nil, nil }
+func (m *MockStore) ListRemoteAgents() ([]models.RemoteAgent, error)          { return nil, nil }
+func (m *MockStore) DeleteRemoteAgent(id uuid.UUID) error                     { return nil }
+
 func (m *MockStore) Close() error { return nil }